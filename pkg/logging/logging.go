@@ -0,0 +1,100 @@
+// Package logging configures the global zerolog logger the same way in every service.
+// It only depends on zerolog and the standard library.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// Config comes from LOG_LEVEL and LOG_FORMAT; empty values mean info and json
+type Config struct {
+	Level  string
+	Format string
+}
+
+var (
+	mu sync.Mutex
+	// configured is the level from config, which ToggleDebug returns to
+	configured = zerolog.InfoLevel
+)
+
+// Setup replaces the global logger and level. Every line carries the service and version
+// so mixed versions during a rollout can be told apart.
+func Setup(cfg Config, service, version string) error {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	switch strings.ToLower(cfg.Format) {
+	case "", FormatJSON:
+	case FormatConsole:
+		out = zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339}
+	default:
+		return fmt.Errorf("unknown log format %q, use json or console", cfg.Format)
+	}
+
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	log.Logger = zerolog.New(out).With().Timestamp().Str("service", service).Str("version", version).Logger()
+
+	mu.Lock()
+	configured = level
+	mu.Unlock()
+	zerolog.SetGlobalLevel(level)
+	return nil
+}
+
+// ParseLevel accepts the zerolog level names; empty means info
+func ParseLevel(level string) (zerolog.Level, error) {
+	if strings.TrimSpace(level) == "" {
+		return zerolog.InfoLevel, nil
+	}
+	parsed, err := zerolog.ParseLevel(strings.ToLower(strings.TrimSpace(level)))
+	if err != nil || parsed == zerolog.NoLevel {
+		return zerolog.NoLevel, fmt.Errorf("unknown log level %q", level)
+	}
+	return parsed, nil
+}
+
+// Level is the level in effect right now
+func Level() string {
+	return zerolog.GlobalLevel().String()
+}
+
+// SetLevel changes the level at runtime until the next change or restart
+func SetLevel(level string) error {
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	previous := zerolog.GlobalLevel()
+	zerolog.SetGlobalLevel(parsed)
+	log.Warn().Str("from", previous.String()).Str("to", parsed.String()).Msg("[Logging-SetLevel] Log level changed")
+	return nil
+}
+
+// ToggleDebug switches between debug and the configured level
+func ToggleDebug() {
+	mu.Lock()
+	level := configured
+	mu.Unlock()
+
+	if zerolog.GlobalLevel() != zerolog.DebugLevel {
+		level = zerolog.DebugLevel
+	}
+	_ = SetLevel(level.String())
+}
@@ -0,0 +1,28 @@
+//go:build !windows
+
+package logging
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// WatchSignal toggles debug logging on every SIGUSR1 until ctx is done:
+//
+//	kill -USR1 <pid>
+func WatchSignal(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1)
+	defer signal.Stop(signals)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			ToggleDebug()
+		}
+	}
+}
@@ -0,0 +1,6 @@
+package logging
+
+import "context"
+
+// WatchSignal does nothing on Windows, which has no SIGUSR1
+func WatchSignal(ctx context.Context) {}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"notification-service/config"
+	"notification-service/internal/adapter/consumer"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hilmirazib/jualan-sayur/pkg/logging"
+	"github.com/rs/zerolog/log"
+	"github.com/streadway/amqp"
+)
+
+// version is stamped at build time, like cmd/server
+var version = "0.0.0-dev"
+
+// replay moves messages from the email DLQ back to email_queue, e.g. after an outage dropped
+// verification emails. Messages that do not match the filters, or every message in dry-run
+// mode, are returned to the DLQ untouched.
+//
+//	go run ./cmd/replay -type email_verification -since 2024-05-01 -dry-run
+func main() {
+	types := flag.String("type", "", "comma-separated email types to replay, e.g. email_verification,password_reset; empty replays all")
+	since := flag.String("since", "", "only messages dead-lettered at or after this time (2006-01-02 or RFC 3339)")
+	until := flag.String("until", "", "only messages dead-lettered before this time (2006-01-02 or RFC 3339)")
+	limit := flag.Int("limit", 1000, "maximum number of DLQ messages to inspect")
+	dryRun := flag.Bool("dry-run", false, "list matching messages without republishing them")
+	flag.Parse()
+
+	cfg := config.LoadConfig()
+	if err := logging.Setup(logging.Config{Level: cfg.Log.Level, Format: cfg.Log.Format}, "notification-replay", version); err != nil {
+		log.Fatal().Err(err).Msg("Invalid log config")
+	}
+
+	filter, err := newFilter(*types, *since, *until)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid filter")
+	}
+
+	// replay returns instead of exiting so its deferred cleanup, which returns skipped
+	// messages to the DLQ and closes the connection, always runs
+	if err := replay(cfg, filter, *limit, *dryRun); err != nil {
+		log.Error().Err(err).Msg("Replay failed")
+		os.Exit(1)
+	}
+}
+
+func replay(cfg *config.Config, filter *filter, limit int, dryRun bool) error {
+	logger := log.Logger
+
+	connString := "amqp://" + cfg.RabbitMQ.User + ":" + cfg.RabbitMQ.Password + "@" + cfg.RabbitMQ.Host + ":" + cfg.RabbitMQ.Port + cfg.RabbitMQ.VHost
+	conn, err := amqp.Dial(connString)
+	if err != nil {
+		return fmt.Errorf("connect to RabbitMQ: %w", err)
+	}
+	defer conn.Close()
+
+	channel, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("open channel: %w", err)
+	}
+	defer channel.Close()
+
+	if _, err := channel.QueueDeclare(consumer.EmailQueue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("declare email queue: %w", err)
+	}
+	dlq, err := channel.QueueDeclare(consumer.DeadLetterQueue, true, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("declare dead letter queue: %w", err)
+	}
+
+	// Wait for the broker to confirm each republish before removing the message from the DLQ
+	if err := channel.Confirm(false); err != nil {
+		return fmt.Errorf("enable publisher confirms: %w", err)
+	}
+	confirms := channel.NotifyPublish(make(chan amqp.Confirmation, 1))
+
+	// Only look at what is there now; a replayed message that fails again lands back in
+	// the DLQ and must not be picked up by this run
+	total := dlq.Messages
+	if total > limit {
+		total = limit
+	}
+	logger.Info().Int("dlq_messages", dlq.Messages).Int("inspecting", total).Bool("dry_run", dryRun).Msg("Replaying dead-lettered emails")
+
+	var kept []amqp.Delivery
+	replayed, skipped := 0, 0
+	defer func() {
+		// Unacked deliveries are not handed out again on this channel, so returning them
+		// only at the end keeps Get from cycling through the same messages
+		for _, delivery := range kept {
+			delivery.Nack(false, true)
+		}
+		logger.Info().Int("replayed", replayed).Int("skipped", skipped).Bool("dry_run", dryRun).Msg("Replay finished")
+	}()
+
+	for i := 0; i < total; i++ {
+		delivery, ok, err := channel.Get(consumer.DeadLetterQueue, false)
+		if err != nil {
+			return fmt.Errorf("read from the dead letter queue: %w", err)
+		}
+		if !ok {
+			break
+		}
+
+		var emailMsg consumer.EmailMessage
+		if err := json.Unmarshal(delivery.Body, &emailMsg); err != nil {
+			// It would only fail the same way again
+			logger.Warn().Err(err).Msg("Skipping message that is not valid JSON")
+			kept, skipped = append(kept, delivery), skipped+1
+			continue
+		}
+
+		reason, _ := delivery.Headers[consumer.FailureReasonHeader].(string)
+		event := logger.Info().Str("email", emailMsg.Email).Str("type", emailMsg.Type).Time("failed_at", delivery.Timestamp).Str("reason", reason)
+
+		if !filter.match(emailMsg.Type, delivery.Timestamp) {
+			kept, skipped = append(kept, delivery), skipped+1
+			continue
+		}
+		if dryRun {
+			event.Msg("Would replay")
+			kept, replayed = append(kept, delivery), replayed+1
+			continue
+		}
+
+		// The republished message carries no retry count, so it gets a full set of attempts
+		err = channel.Publish("", consumer.EmailQueue, false, false, amqp.Publishing{
+			ContentType:  delivery.ContentType,
+			DeliveryMode: amqp.Persistent,
+			Body:         delivery.Body,
+		})
+		if err == nil {
+			if confirm := <-confirms; !confirm.Ack {
+				err = errors.New("broker rejected the publish")
+			}
+		}
+		if err != nil {
+			kept = append(kept, delivery)
+			return fmt.Errorf("republish to %s for %s: %w", consumer.EmailQueue, emailMsg.Email, err)
+		}
+
+		delivery.Ack(false)
+		replayed++
+		event.Msg("Replayed")
+	}
+	return nil
+}
+
+type filter struct {
+	types        map[string]bool
+	since, until time.Time
+}
+
+func newFilter(types, since, until string) (*filter, error) {
+	f := &filter{types: make(map[string]bool)}
+	for _, emailType := range strings.Split(types, ",") {
+		if emailType = strings.TrimSpace(emailType); emailType != "" {
+			f.types[emailType] = true
+		}
+	}
+
+	var err error
+	if f.since, err = parseTime(since); err != nil {
+		return nil, err
+	}
+	if f.until, err = parseTime(until); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// match treats a message without a timestamp as outside any date range
+func (f *filter) match(emailType string, failedAt time.Time) bool {
+	if len(f.types) > 0 && !f.types[emailType] {
+		return false
+	}
+	if !f.since.IsZero() && (failedAt.IsZero() || failedAt.Before(f.since)) {
+		return false
+	}
+	if !f.until.IsZero() && (failedAt.IsZero() || !failedAt.Before(f.until)) {
+		return false
+	}
+	return true
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
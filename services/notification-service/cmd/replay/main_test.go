@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTime(t *testing.T) {
+	day, err := parseTime("2024-05-01")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.Local), day)
+
+	instant, err := parseTime("2024-05-01T10:30:00+07:00")
+	require.NoError(t, err)
+	assert.True(t, instant.Equal(time.Date(2024, 5, 1, 3, 30, 0, 0, time.UTC)))
+
+	empty, err := parseTime("")
+	require.NoError(t, err)
+	assert.True(t, empty.IsZero(), "no value means no bound")
+
+	_, err = parseTime("01/05/2024")
+	assert.Error(t, err)
+}
+
+func TestNewFilter_SplitsTypes(t *testing.T) {
+	f, err := newFilter(" email_verification, ,password_reset ", "", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]bool{"email_verification": true, "password_reset": true}, f.types)
+	assert.True(t, f.since.IsZero())
+	assert.True(t, f.until.IsZero())
+}
+
+func TestNewFilter_RejectsInvalidTimes(t *testing.T) {
+	_, err := newFilter("", "yesterday", "")
+	assert.Error(t, err)
+
+	_, err = newFilter("", "", "2024-13-01")
+	assert.Error(t, err)
+}
+
+func TestFilterMatch(t *testing.T) {
+	f, err := newFilter("email_verification", "2024-05-01", "2024-05-02")
+	require.NoError(t, err)
+
+	inside := time.Date(2024, 5, 1, 12, 0, 0, 0, time.Local)
+	assert.True(t, f.match("email_verification", inside))
+	assert.True(t, f.match("email_verification", f.since), "since is inclusive")
+	assert.False(t, f.match("email_verification", f.until), "until is exclusive")
+	assert.False(t, f.match("email_verification", inside.Add(-24*time.Hour)))
+	assert.False(t, f.match("password_reset", inside), "other types are skipped")
+	assert.False(t, f.match("email_verification", time.Time{}), "a message without a timestamp is outside any date range")
+}
+
+func TestFilterMatch_WithoutFiltersMatchesEverything(t *testing.T) {
+	f, err := newFilter("", "", "")
+	require.NoError(t, err)
+
+	assert.True(t, f.match("password_reset", time.Time{}))
+	assert.True(t, f.match("", time.Now()))
+}
@@ -3,24 +3,36 @@ package main
 import (
 	"context"
 	"notification-service/config"
+	"notification-service/internal/adapter/assets"
 	"notification-service/internal/adapter/consumer"
+	"notification-service/internal/adapter/metrics"
+	"notification-service/internal/adapter/quota"
+	"notification-service/internal/adapter/templates"
+	"notification-service/internal/core/port"
 	"notification-service/internal/core/service"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
-	"github.com/rs/zerolog"
+	"github.com/go-redis/redis/v8"
+	"github.com/hilmirazib/jualan-sayur/pkg/logging"
+	"github.com/rs/zerolog/log"
 	"github.com/streadway/amqp"
 )
 
-func main() {
-	// Initialize logger
-	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	logger := zerolog.New(os.Stdout).With().Timestamp().Logger()
-	zerolog.SetGlobalLevel(zerolog.InfoLevel)
+// version is stamped at build time: go build -ldflags "-X main.version=1.4.0"
+var version = "0.0.0-dev"
 
+func main() {
 	// Load configuration
 	cfg := config.LoadConfig()
+
+	// Initialize logger; SIGUSR1 toggles debug logging without a restart
+	if err := logging.Setup(logging.Config{Level: cfg.Log.Level, Format: cfg.Log.Format}, "notification-service", version); err != nil {
+		log.Fatal().Err(err).Msg("Invalid log config")
+	}
+	logger := log.Logger
 	logger.Info().Str("env", cfg.App.Env).Msg("Starting notification service")
 
 	// Connect to RabbitMQ
@@ -37,25 +49,69 @@ func main() {
 	}
 	defer channel.Close()
 
+	// Domain events get their own channel, so a failure there does not close the email channel
+	eventChannel, err := conn.Channel()
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to open user events channel")
+	}
+	defer eventChannel.Close()
+
 	logger.Info().Msg("Connected to RabbitMQ")
 
 	// Initialize services
 	emailService := service.NewEmailService(cfg)
+	attachmentService := service.NewAttachmentService(cfg)
+	assetManifest := assets.NewManifest(cfg)
+	emailRenderer, err := templates.NewRenderer(cfg, assetManifest)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to load email templates")
+	}
+	brandingService, err := service.NewBrandingService(cfg)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Failed to load branding")
+	}
+
+	// Email quotas need Redis; it is not dialed here, an unreachable Redis only skips the quota
+	var emailQuota port.EmailQuotaServiceInterface
+	if cfg.Redis.Host != "" {
+		redisClient := redis.NewClient(&redis.Options{
+			Addr:     cfg.Redis.Host + ":" + cfg.Redis.Port,
+			Password: cfg.Redis.Password,
+		})
+		defer redisClient.Close()
+		emailQuota = service.NewEmailQuotaService(cfg, quota.NewRedisStore(redisClient))
+	} else {
+		logger.Warn().Msg("REDIS_HOST is not set, email rate limits are off")
+	}
 
 	// Initialize consumer
-	emailConsumer := consumer.NewEmailConsumer(cfg, emailService, channel)
+	emailConsumer := consumer.NewEmailConsumer(cfg, emailService, attachmentService, brandingService, emailRenderer, emailQuota, channel)
+	userEventConsumer := consumer.NewUserEventConsumer(cfg, eventChannel)
+	productEventConsumer := consumer.NewProductEventConsumer(cfg, eventChannel)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	go logging.WatchSignal(ctx)
+
 	// Handle shutdown signals
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 
+	go metrics.Serve(ctx, cfg.Metrics.Addr)
+	go emailRenderer.Watch(ctx)
+	go assetManifest.Watch(ctx)
+
 	// Start consuming messages
 	if err := emailConsumer.StartConsuming(ctx); err != nil {
 		logger.Fatal().Err(err).Msg("Failed to start consuming")
 	}
+	if err := userEventConsumer.StartConsuming(ctx); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to start consuming user events")
+	}
+	if err := productEventConsumer.StartConsuming(ctx); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to start consuming product events")
+	}
 
 	logger.Info().Msg("Notification service started successfully")
 
@@ -63,7 +119,14 @@ func main() {
 	<-sigChan
 	logger.Info().Msg("Shutting down notification service...")
 
-	// Cancel context to stop consumer
+	// Stop taking messages and let the email in flight finish before the channel closes;
+	// RabbitMQ requeues anything still unacknowledged when it does
+	grace := time.Duration(cfg.Shutdown.GraceSeconds) * time.Second
+	if err := emailConsumer.Drain(grace); err != nil {
+		logger.Warn().Err(err).Msg("Consumer did not drain in time")
+	}
+
+	// Cancel context to stop the remaining workers
 	cancel()
 
 	logger.Info().Msg("Notification service stopped")
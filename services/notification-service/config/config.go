@@ -3,20 +3,38 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/rs/zerolog/log"
 )
 
 type Config struct {
-	App      App
-	RabbitMQ RabbitMQ
-	SMTP     SMTP
+	App            App
+	Log            Log
+	RabbitMQ       RabbitMQ
+	SMTP           SMTP
+	CircuitBreaker CircuitBreaker
+	Email          Email
+	Attachment     Attachment
+	Branding       Branding
+	Assets         Assets
+	Metrics        Metrics
+	Shutdown       Shutdown
+	Welcome        Welcome
+	Redis          Redis
+	RateLimit      RateLimit
 }
 
 type App struct {
 	Env string
 }
 
+// Log sets the zerolog level (trace, debug, info, warn, error) and format (json or console)
+type Log struct {
+	Level  string
+	Format string
+}
+
 type RabbitMQ struct {
 	Host     string
 	Port     string
@@ -32,11 +50,101 @@ type SMTP struct {
 	Password string
 }
 
+// CircuitBreaker stops dialing SMTP for OpenSeconds after FailureThreshold consecutive failures
+type CircuitBreaker struct {
+	FailureThreshold int
+	OpenSeconds      int
+}
+
+// Email picks the template locale; DefaultLanguage is used when the message has no language
+// or no template exists in it. TemplateDir replaces the embedded templates with a directory
+// that is re-read every TemplateReloadSeconds, keeping TemplateHistory versions per template.
+// A send or download that fails is retried RetryDelaySeconds later, and dead-lettered after
+// MaxSendAttempts tries.
+type Email struct {
+	DefaultLanguage       string
+	TemplateDir           string
+	TemplateReloadSeconds int
+	TemplateHistory       int
+	MaxSendAttempts       int
+	RetryDelaySeconds     int
+}
+
+// Branding is the default look and sender of every email. File optionally points at a JSON
+// file of per-tenant overrides, see service.NewBrandingService.
+type Branding struct {
+	Name          string
+	LogoURL       string
+	LogoAsset     string
+	PrimaryColor  string
+	Footer        string
+	SenderName    string
+	SenderAddress string
+	File          string
+}
+
+// Assets resolves {{asset "name"}} in templates from user-service's asset manifest, fetched
+// at start-up and every RefreshSeconds. Without ManifestURL templates cannot use assets.
+type Assets struct {
+	ManifestURL    string
+	RefreshSeconds int
+}
+
+// Metrics is the address of the Prometheus /metrics listener, e.g. ":9100"; "off" turns it off
+type Metrics struct {
+	Addr string
+}
+
+// Shutdown is how long SIGTERM waits for the email being sent before giving up on it; keep it
+// below the orchestrator's kill timeout (30s by default in Docker and Kubernetes)
+type Shutdown struct {
+	GraceSeconds int
+}
+
+// Welcome is the starter voucher in the email sent after a user verifies their account, e.g.
+// VoucherCode "SAYURBARU" and VoucherDiscount "20%". Without a code the email has no voucher.
+type Welcome struct {
+	VoucherCode     string
+	VoucherDiscount string
+}
+
+// Redis holds the email quota counters and the limits admins set in user-service. Without
+// Host no quota is enforced.
+type Redis struct {
+	Host     string
+	Port     string
+	Password string
+}
+
+// RateLimit caps the emails one recipient gets per clock hour: PerUserHour across all emails
+// and PerTemplateHour of each type. ExemptTypes, e.g. password resets, are never held back.
+// Admins override these per type in user-service; LimitsRefreshSeconds is how often that is
+// re-read. Emails over the limit are deferred to the next hour, not dropped.
+type RateLimit struct {
+	PerUserHour          int
+	PerTemplateHour      int
+	ExemptTypes          []string
+	LimitsRefreshSeconds int
+}
+
+// Attachment limits apply to the decoded or downloaded bytes. URL attachments are only
+// fetched over HTTPS from AllowedHosts, e.g. the storage bucket host; none allowed by default.
+type Attachment struct {
+	MaxBytes               int
+	MaxTotalBytes          int
+	AllowedHosts           []string
+	DownloadTimeoutSeconds int
+}
+
 func LoadConfig() *Config {
 	return &Config{
 		App: App{
 			Env: getEnv("APP_ENV", "development"),
 		},
+		Log: Log{
+			Level:  getEnv("LOG_LEVEL", "info"),
+			Format: getEnv("LOG_FORMAT", "json"),
+		},
 		RabbitMQ: RabbitMQ{
 			Host:     getEnv("RABBITMQ_HOST", "localhost"),
 			Port:     getEnv("RABBITMQ_PORT", "5672"),
@@ -50,9 +158,68 @@ func LoadConfig() *Config {
 			User:     getEnv("SMTP_USER", ""),
 			Password: getEnv("SMTP_PASSWORD", ""),
 		},
+		CircuitBreaker: CircuitBreaker{
+			FailureThreshold: getEnvAsInt("SMTP_BREAKER_FAILURE_THRESHOLD", 5),
+			OpenSeconds:      getEnvAsInt("SMTP_BREAKER_OPEN_SECONDS", 30),
+		},
+		Email: Email{
+			DefaultLanguage:       getEnv("EMAIL_DEFAULT_LANGUAGE", "en"),
+			TemplateDir:           getEnv("EMAIL_TEMPLATE_DIR", ""),
+			TemplateReloadSeconds: getEnvAsInt("EMAIL_TEMPLATE_RELOAD_SECONDS", 30),
+			TemplateHistory:       getEnvAsInt("EMAIL_TEMPLATE_HISTORY", 5),
+			MaxSendAttempts:       getEnvAsInt("EMAIL_MAX_SEND_ATTEMPTS", 5),
+			RetryDelaySeconds:     getEnvAsInt("EMAIL_RETRY_DELAY_SECONDS", 60),
+		},
+		Branding: Branding{
+			Name:          getEnv("BRAND_NAME", "Jualan Sayur"),
+			LogoURL:       getEnv("BRAND_LOGO_URL", ""),
+			LogoAsset:     getEnv("BRAND_LOGO_ASSET", ""),
+			PrimaryColor:  getEnv("BRAND_PRIMARY_COLOR", "#16a34a"),
+			Footer:        getEnv("BRAND_FOOTER", ""),
+			SenderName:    getEnv("EMAIL_SENDER_NAME", "Jualan Sayur"),
+			SenderAddress: getEnv("EMAIL_SENDER_ADDRESS", "noreply@mailtrap.io"),
+			File:          getEnv("BRANDING_FILE", ""),
+		},
+		Assets: Assets{
+			ManifestURL:    getEnv("ASSET_MANIFEST_URL", ""),
+			RefreshSeconds: getEnvAsInt("ASSET_MANIFEST_REFRESH_SECONDS", 60),
+		},
+		Metrics: Metrics{
+			Addr: getEnv("METRICS_ADDR", ":9100"),
+		},
+		Shutdown: Shutdown{
+			GraceSeconds: getEnvAsInt("SHUTDOWN_GRACE_SECONDS", 25),
+		},
+		Welcome: Welcome{
+			VoucherCode:     getEnv("WELCOME_VOUCHER_CODE", ""),
+			VoucherDiscount: getEnv("WELCOME_VOUCHER_DISCOUNT", ""),
+		},
+		Attachment: Attachment{
+			MaxBytes:               getEnvAsInt("EMAIL_ATTACHMENT_MAX_BYTES", 5<<20),
+			MaxTotalBytes:          getEnvAsInt("EMAIL_ATTACHMENT_MAX_TOTAL_BYTES", 10<<20),
+			AllowedHosts:           getEnvAsList("EMAIL_ATTACHMENT_ALLOWED_HOSTS"),
+			DownloadTimeoutSeconds: getEnvAsInt("EMAIL_ATTACHMENT_DOWNLOAD_TIMEOUT_SECONDS", 10),
+		},
+		Redis: Redis{
+			Host:     getEnv("REDIS_HOST", ""),
+			Port:     getEnv("REDIS_PORT", "6379"),
+			Password: getEnv("REDIS_PASSWORD", ""),
+		},
+		RateLimit: RateLimit{
+			PerUserHour:          getEnvAsInt("EMAIL_RATE_LIMIT_PER_USER_HOUR", 20),
+			PerTemplateHour:      getEnvAsInt("EMAIL_RATE_LIMIT_PER_TEMPLATE_HOUR", 5),
+			ExemptTypes:          getEnvAsListOr("EMAIL_RATE_LIMIT_EXEMPT_TYPES", defaultExemptEmailTypes),
+			LimitsRefreshSeconds: getEnvAsInt("EMAIL_RATE_LIMIT_REFRESH_SECONDS", 30),
+		},
 	}
 }
 
+// defaultExemptEmailTypes are the security emails a user asked for and must get right away
+var defaultExemptEmailTypes = []string{
+	"email_verification", "password_reset", "signin_otp", "email_change", "email_change_revert",
+	"password_changed", "account_locked_alert", "new_device_signin",
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -72,3 +239,22 @@ func getEnvAsInt(key string, defaultValue int) int {
 	log.Warn().Str("key", key).Int("default", defaultValue).Msg("[Config] Using default value for environment variable")
 	return defaultValue
 }
+
+// getEnvAsList splits a comma-separated variable, dropping blanks; unset means an empty list
+func getEnvAsList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// getEnvAsListOr is getEnvAsList with defaultValue when the variable is unset or blank
+func getEnvAsListOr(key string, defaultValue []string) []string {
+	if values := getEnvAsList(key); len(values) > 0 {
+		return values
+	}
+	return defaultValue
+}
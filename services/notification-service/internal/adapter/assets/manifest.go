@@ -0,0 +1,124 @@
+package assets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"notification-service/config"
+	"notification-service/internal/core/port"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// manifestResponse is user-service's GET /api/v1/assets/manifest
+type manifestResponse struct {
+	Data map[string]struct {
+		URL  string `json:"url"`
+		Hash string `json:"hash"`
+	} `json:"data"`
+}
+
+type Manifest struct {
+	url             string
+	refreshInterval time.Duration
+	client          *http.Client
+
+	mu   sync.RWMutex
+	urls map[string]string
+}
+
+// NewManifest fetches the manifest once. An unreachable manifest is logged, not fatal:
+// templates that use assets fall back to the pre-rendered email until Watch catches up.
+func NewManifest(cfg *config.Config) port.AssetResolverInterface {
+	m := &Manifest{
+		url:             cfg.Assets.ManifestURL,
+		refreshInterval: time.Duration(cfg.Assets.RefreshSeconds) * time.Second,
+		client:          &http.Client{Timeout: 10 * time.Second},
+		urls:            make(map[string]string),
+	}
+	if m.url == "" {
+		return m
+	}
+
+	if err := m.load(context.Background()); err != nil {
+		log.Error().Err(err).Str("url", m.url).Msg("[Manifest] Failed to load asset manifest")
+	}
+	return m
+}
+
+func (m *Manifest) URL(name string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	url, ok := m.urls[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", port.ErrAssetNotFound, name)
+	}
+	return url, nil
+}
+
+// Watch refetches the manifest, so a re-uploaded logo shows up without a restart
+func (m *Manifest) Watch(ctx context.Context) {
+	if m.url == "" || m.refreshInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(m.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.load(ctx); err != nil {
+				log.Error().Err(err).Str("url", m.url).Msg("[Manifest-Watch] Failed to refresh asset manifest, keeping the current one")
+			}
+		}
+	}
+}
+
+func (m *Manifest) load(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, m.url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("asset manifest returned status %d", resp.StatusCode)
+	}
+
+	var manifest manifestResponse
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return err
+	}
+
+	urls := make(map[string]string, len(manifest.Data))
+	for name, asset := range manifest.Data {
+		urls[name] = asset.URL
+	}
+
+	m.mu.Lock()
+	changed := len(urls) != len(m.urls)
+	for name, url := range urls {
+		if m.urls[name] != url {
+			changed = true
+		}
+	}
+	m.urls = urls
+	m.mu.Unlock()
+
+	if changed {
+		log.Info().Int("assets", len(urls)).Msg("[Manifest-load] Asset manifest updated")
+	}
+	return nil
+}
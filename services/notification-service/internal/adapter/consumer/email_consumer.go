@@ -3,13 +3,59 @@ package consumer
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"notification-service/config"
+	"notification-service/internal/adapter/metrics"
 	"notification-service/internal/core/port"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/rs/zerolog/log"
 	"github.com/streadway/amqp"
 )
 
+const (
+	// EmailQueue is shared with the user-service publisher
+	EmailQueue = "email_queue"
+	// DeadLetterQueue keeps messages that can never be sent as they are, for cmd/replay
+	DeadLetterQueue = "email_dlq"
+
+	// DeferredQueue holds emails over their recipient's quota until the next hour, when each
+	// message expires and RabbitMQ dead-letters it back onto email_queue
+	DeferredQueue = "email_deferred"
+	// RetryQueue holds emails whose send or attachment download failed until their retry delay
+	// is over, when they expire back onto email_queue the same way
+	RetryQueue = "email_retry"
+
+	// FailureReasonHeader tells why a message was dead-lettered; the delivery Timestamp says when
+	FailureReasonHeader = "x-failure-reason"
+	// DeferredUntilHeader is when a deferred email goes back to email_queue, in RFC 3339
+	DeferredUntilHeader = "x-deferred-until"
+	// RetryCountHeader counts the failed attempts of an email sent through RetryQueue
+	RetryCountHeader = "x-retry-count"
+)
+
+// Used when the config leaves the retry settings at zero
+const (
+	defaultMaxSendAttempts = 5
+	defaultRetryDelay      = time.Minute
+)
+
+// unavailableRequeueDelay slows consumption while the SMTP circuit breaker is open
+const unavailableRequeueDelay = 5 * time.Second
+
+// consumerTag identifies this consumer to RabbitMQ, so Drain can cancel its subscription
+const consumerTag = "notification-service-email"
+
+// ErrDrainTimeout means the message in flight did not finish within the grace period. Its
+// work was cancelled; whatever is still unacknowledged is requeued once the channel closes.
+var ErrDrainTimeout = errors.New("drain grace period expired with a message in flight")
+
+// preRenderedVersion is logged as the template version when the publisher's subject and body are sent
+const preRenderedVersion = "pre-rendered"
+
 type EmailMessage struct {
 	Email   string `json:"email"`
 	Token   string `json:"token"`
@@ -17,46 +63,115 @@ type EmailMessage struct {
 	Name    string `json:"name"`
 	Subject string `json:"subject"`
 	Body    string `json:"body"`
+	// Language is the recipient's preferred language; Data fills the template for Type.
+	// Older publishers send neither, and Subject and Body are then sent as they are.
+	Language string            `json:"language"`
+	Data     map[string]string `json:"data"`
+	// Attachments are optional, e.g. invoices or data exports
+	Attachments []AttachmentMessage `json:"attachments"`
+	// Tenant picks a white-label brand; empty uses the deployment's default branding
+	Tenant string `json:"tenant"`
+}
+
+// AttachmentMessage carries either base64 Content or a storage URL to download
+type AttachmentMessage struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Content     string `json:"content"`
+	URL         string `json:"url"`
+}
+
+// Channel is the part of *amqp.Channel the consumer uses
+type Channel interface {
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+	Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+	Cancel(consumer string, noWait bool) error
 }
 
 type EmailConsumer struct {
-	config       *config.Config
-	emailService port.EmailServiceInterface
-	channel      *amqp.Channel
+	config            *config.Config
+	emailService      port.EmailServiceInterface
+	attachmentService port.AttachmentServiceInterface
+	brandingService   port.BrandingServiceInterface
+	renderer          port.EmailRendererInterface
+	quota             port.EmailQuotaServiceInterface
+	channel           Channel
+
+	// stop asks the consume loop to quit between messages; done is closed once it has.
+	// abort cancels the work of the message in flight when the drain runs out of time.
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+	abort    context.CancelFunc
 }
 
-func NewEmailConsumer(cfg *config.Config, emailService port.EmailServiceInterface, channel *amqp.Channel) *EmailConsumer {
+// NewEmailConsumer takes an optional quota; without one every email is sent right away
+func NewEmailConsumer(cfg *config.Config, emailService port.EmailServiceInterface, attachmentService port.AttachmentServiceInterface, brandingService port.BrandingServiceInterface, renderer port.EmailRendererInterface, quota port.EmailQuotaServiceInterface, channel Channel) *EmailConsumer {
 	return &EmailConsumer{
-		config:       cfg,
-		emailService: emailService,
-		channel:      channel,
+		config:            cfg,
+		emailService:      emailService,
+		attachmentService: attachmentService,
+		brandingService:   brandingService,
+		renderer:          renderer,
+		quota:             quota,
+		channel:           channel,
+		stop:              make(chan struct{}),
+		done:              make(chan struct{}),
+		abort:             func() {},
 	}
 }
 
 func (c *EmailConsumer) StartConsuming(ctx context.Context) error {
 	// Declare queue (same as publisher)
 	queue, err := c.channel.QueueDeclare(
-		"email_queue", // name
-		true,          // durable
-		false,         // delete when unused
-		false,         // exclusive
-		false,         // no-wait
-		nil,           // arguments
+		EmailQueue, // name
+		true,       // durable
+		false,      // delete when unused
+		false,      // exclusive
+		false,      // no-wait
+		nil,        // arguments
 	)
 	if err != nil {
 		log.Error().Err(err).Msg("[EmailConsumer-StartConsuming] Failed to declare queue")
 		return err
 	}
 
+	// The publisher declares email_queue without arguments, so dead-lettering is done by
+	// republishing here rather than with an x-dead-letter-exchange
+	if _, err := c.channel.QueueDeclare(DeadLetterQueue, true, false, false, false, nil); err != nil {
+		log.Error().Err(err).Msg("[EmailConsumer-StartConsuming] Failed to declare dead letter queue")
+		return err
+	}
+
+	// Retried and deferred emails have no consumer; they expire back onto email_queue through
+	// the default exchange
+	if _, err := c.channel.QueueDeclare(RetryQueue, true, false, false, false, amqp.Table{
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": EmailQueue,
+	}); err != nil {
+		log.Error().Err(err).Msg("[EmailConsumer-StartConsuming] Failed to declare retry queue")
+		return err
+	}
+	if c.quota != nil {
+		if _, err := c.channel.QueueDeclare(DeferredQueue, true, false, false, false, amqp.Table{
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": EmailQueue,
+		}); err != nil {
+			log.Error().Err(err).Msg("[EmailConsumer-StartConsuming] Failed to declare deferred queue")
+			return err
+		}
+	}
+
 	// Start consuming messages
 	msgs, err := c.channel.Consume(
-		queue.Name, // queue
-		"",         // consumer
-		false,      // auto-ack
-		false,      // exclusive
-		false,      // no-local
-		false,      // no-wait
-		nil,        // args
+		queue.Name,  // queue
+		consumerTag, // consumer
+		false,       // auto-ack
+		false,       // exclusive
+		false,       // no-local
+		false,       // no-wait
+		nil,         // args
 	)
 	if err != nil {
 		log.Error().Err(err).Msg("[EmailConsumer-StartConsuming] Failed to register consumer")
@@ -65,15 +180,37 @@ func (c *EmailConsumer) StartConsuming(ctx context.Context) error {
 
 	log.Info().Msg("[EmailConsumer-StartConsuming] Started consuming email messages")
 
+	// Messages are worked on with their own context, so a shutdown lets the one in flight
+	// finish and only cancels it when Drain runs out of time
+	workCtx, abort := context.WithCancel(context.Background())
+	c.abort = abort
+
 	// Process messages
 	go func() {
+		defer close(c.done)
+		defer abort()
 		for {
+			// A stop request wins over messages that are already waiting
+			select {
+			case <-c.stop:
+				c.requeueBuffered(msgs)
+				return
+			default:
+			}
+
 			select {
 			case <-ctx.Done():
 				log.Info().Msg("[EmailConsumer-StartConsuming] Stopping consumer")
 				return
-			case msg := <-msgs:
-				c.processMessage(ctx, msg)
+			case <-c.stop:
+				c.requeueBuffered(msgs)
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					log.Warn().Msg("[EmailConsumer-StartConsuming] Delivery channel closed, stopping consumer")
+					return
+				}
+				c.processMessage(workCtx, msg)
 			}
 		}
 	}()
@@ -81,25 +218,331 @@ func (c *EmailConsumer) StartConsuming(ctx context.Context) error {
 	return nil
 }
 
+// Drain stops taking new messages, lets the one in flight finish and requeues the ones
+// already delivered but not started. After grace the message in flight is cancelled and
+// ErrDrainTimeout returned; an SMTP send that is already dialing cannot be interrupted.
+func (c *EmailConsumer) Drain(grace time.Duration) error {
+	c.stopOnce.Do(func() { close(c.stop) })
+
+	// Without the subscription RabbitMQ stops pushing; what it already pushed is requeued below
+	if err := c.channel.Cancel(consumerTag, false); err != nil {
+		log.Warn().Err(err).Msg("[EmailConsumer-Drain] Failed to cancel consumer, unacked messages are requeued when the channel closes")
+	}
+
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+
+	select {
+	case <-c.done:
+		log.Info().Msg("[EmailConsumer-Drain] Consumer drained")
+		return nil
+	case <-timer.C:
+		log.Warn().Dur("grace", grace).Msg("[EmailConsumer-Drain] Grace period expired, cancelling the message in flight")
+		c.abort()
+		return ErrDrainTimeout
+	}
+}
+
+// requeueBuffered gives back the messages RabbitMQ delivered that were never started
+func (c *EmailConsumer) requeueBuffered(msgs <-chan amqp.Delivery) {
+	requeued := 0
+	for {
+		select {
+		case msg, ok := <-msgs:
+			if !ok {
+				log.Info().Int("requeued", requeued).Msg("[EmailConsumer-requeueBuffered] Requeued undelivered messages")
+				return
+			}
+			metrics.MessageRequeued(messageType(msg.Body), metrics.ReasonShutdown)
+			if err := msg.Nack(false, true); err != nil {
+				log.Error().Err(err).Msg("[EmailConsumer-requeueBuffered] Failed to requeue message")
+			}
+			requeued++
+		default:
+			log.Info().Int("requeued", requeued).Msg("[EmailConsumer-requeueBuffered] Requeued undelivered messages")
+			return
+		}
+	}
+}
+
+// messageType reads only the type of a message, for labelling one that is not processed
+func messageType(body []byte) string {
+	var emailMsg struct {
+		Type string `json:"type"`
+	}
+	_ = json.Unmarshal(body, &emailMsg)
+	return emailMsg.Type
+}
+
 func (c *EmailConsumer) processMessage(ctx context.Context, msg amqp.Delivery) {
 	var emailMsg EmailMessage
 	if err := json.Unmarshal(msg.Body, &emailMsg); err != nil {
 		log.Error().Err(err).Msg("[EmailConsumer-processMessage] Failed to unmarshal message")
-		msg.Nack(false, false) // Don't requeue
+		metrics.MessageConsumed("")
+		metrics.MessageFailed("", metrics.ReasonInvalidMessage)
+		c.deadLetter(msg, "invalid message")
+		return
+	}
+	metrics.MessageConsumed(emailMsg.Type)
+
+	log.Info().Str("email", emailMsg.Email).Str("type", emailMsg.Type).Str("language", emailMsg.Language).Msg("[EmailConsumer-processMessage] Processing email message")
+
+	reservation, deferred := c.reserveQuota(ctx, msg, emailMsg)
+	if deferred {
 		return
 	}
 
-	log.Info().Str("email", emailMsg.Email).Str("type", emailMsg.Type).Msg("[EmailConsumer-processMessage] Processing email message")
+	brand := c.brandingService.ForTenant(emailMsg.Tenant)
+	subject, body, image, templateVersion := c.render(emailMsg, brand)
+
+	// Without the HTML part the email still goes out, just unbranded
+	htmlBody, err := c.renderer.Layout(brand, subject, body, image)
+	if err != nil {
+		log.Error().Err(err).Str("type", emailMsg.Type).Msg("[EmailConsumer-processMessage] Failed to render layout, sending plain text only")
+	}
+
+	attachments, err := c.loadAttachments(ctx, emailMsg.Attachments)
+	if err != nil {
+		log.Error().Err(err).Str("email", emailMsg.Email).Str("type", emailMsg.Type).Msg("[EmailConsumer-processMessage] Failed to load attachments")
+		// A bad attachment fails the same way every time; only failed downloads are retried
+		if errors.Is(err, port.ErrInvalidAttachment) {
+			metrics.MessageFailed(emailMsg.Type, metrics.ReasonInvalidAttachment)
+			c.releaseQuota(reservation)
+			c.deadLetter(msg, err.Error())
+			return
+		}
+		c.releaseQuota(reservation)
+		// A download cut short by the drain is no attempt of its own
+		if ctx.Err() != nil {
+			metrics.MessageRequeued(emailMsg.Type, metrics.ReasonShutdown)
+			msg.Nack(false, true)
+			return
+		}
+		c.retry(msg, emailMsg.Type, metrics.ReasonAttachmentFailed, err)
+		return
+	}
 
 	// Send email using email service
-	if err := c.emailService.SendEmail(ctx, emailMsg.Email, emailMsg.Subject, emailMsg.Body); err != nil {
+	email := port.Email{
+		To:          emailMsg.Email,
+		FromName:    brand.SenderName,
+		FromAddress: brand.SenderAddress,
+		Subject:     subject,
+		TextBody:    body,
+		HTMLBody:    htmlBody,
+		Attachments: attachments,
+	}
+	sendStart := time.Now()
+	err = c.emailService.SendEmail(ctx, email)
+	metrics.EmailSent(emailMsg.Type, sendStart, err)
+	if err != nil {
 		log.Error().Err(err).Str("email", emailMsg.Email).Msg("[EmailConsumer-processMessage] Failed to send email")
-		msg.Nack(false, true) // Requeue for retry
+		c.releaseQuota(reservation)
+		switch {
+		case errors.Is(err, port.ErrEmailUnavailable):
+			// SMTP was not tried, so this is no attempt; pause before requeueing so the
+			// message does not spin through the queue while the breaker is open
+			metrics.MessageRequeued(emailMsg.Type, metrics.ReasonSMTPUnavailable)
+			select {
+			case <-ctx.Done():
+			case <-c.stop:
+			case <-time.After(unavailableRequeueDelay):
+			}
+			msg.Nack(false, true)
+		case errors.Is(err, port.ErrEmailRejected):
+			metrics.MessageFailed(emailMsg.Type, metrics.ReasonSMTPRejected)
+			c.deadLetter(msg, err.Error())
+		default:
+			c.retry(msg, emailMsg.Type, metrics.ReasonSMTPError, err)
+		}
 		return
 	}
 
-	log.Info().Str("email", emailMsg.Email).Msg("[EmailConsumer-processMessage] Email sent successfully")
+	log.Info().Str("email", emailMsg.Email).Str("type", emailMsg.Type).Str("template_version", templateVersion).Msg("[EmailConsumer-processMessage] Email sent successfully")
 
 	// Acknowledge message
 	msg.Ack(false)
+	metrics.MessageProcessed(emailMsg.Type)
+}
+
+// reserveQuota counts the email against its recipient's quotas. An email over quota is moved
+// to the deferred queue and acknowledged, and deferred is true. When the counters or the
+// deferred queue cannot be reached the email is sent anyway: a few extra emails are better
+// than none.
+func (c *EmailConsumer) reserveQuota(ctx context.Context, msg amqp.Delivery, emailMsg EmailMessage) (reservation *port.QuotaReservation, deferred bool) {
+	if c.quota == nil {
+		return nil, false
+	}
+
+	reservation, err := c.quota.Reserve(ctx, emailMsg.Email, emailMsg.Type)
+	if err != nil {
+		log.Warn().Err(err).Str("email", emailMsg.Email).Str("type", emailMsg.Type).Msg("[EmailConsumer-reserveQuota] Failed to check email quota, sending anyway")
+		return nil, false
+	}
+	if reservation.Allowed {
+		return reservation, false
+	}
+
+	// Every email deferred within an hour expires at the same boundary, so the per-message
+	// TTL never leaves an expired message stuck behind one that expires later
+	delay := time.Until(reservation.RetryAt)
+	if delay < time.Second {
+		delay = time.Second
+	}
+	err = c.channel.Publish("", DeferredQueue, false, false, amqp.Publishing{
+		ContentType:  msg.ContentType,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Expiration:   strconv.FormatInt(delay.Milliseconds(), 10),
+		Headers:      amqp.Table{DeferredUntilHeader: reservation.RetryAt.UTC().Format(time.RFC3339)},
+		Body:         msg.Body,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("email", emailMsg.Email).Str("type", emailMsg.Type).Msg("[EmailConsumer-reserveQuota] Failed to defer email over quota, sending anyway")
+		return nil, false
+	}
+
+	log.Info().Str("email", emailMsg.Email).Str("type", emailMsg.Type).Time("retry_at", reservation.RetryAt).Msg("[EmailConsumer-reserveQuota] Email over quota, deferred")
+	metrics.MessageDeferred(emailMsg.Type)
+	msg.Ack(false)
+	return nil, true
+}
+
+// releaseQuota gives back the quota of an email that was not sent. It outlives the message's
+// context, which a drain may have cancelled.
+func (c *EmailConsumer) releaseQuota(reservation *port.QuotaReservation) {
+	if c.quota == nil || reservation == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	c.quota.Release(ctx, reservation)
+}
+
+// retry sends a message back through RetryQueue with its attempt counted, or dead-letters it
+// once it has failed MaxSendAttempts times. Every retry waits the same delay, so messages in
+// RetryQueue expire in the order they were put there.
+func (c *EmailConsumer) retry(msg amqp.Delivery, emailType, reason string, cause error) {
+	maxAttempts := c.config.Email.MaxSendAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxSendAttempts
+	}
+	delay := time.Duration(c.config.Email.RetryDelaySeconds) * time.Second
+	if delay <= 0 {
+		delay = defaultRetryDelay
+	}
+
+	attempts := retryCount(msg) + 1
+	if attempts >= maxAttempts {
+		metrics.MessageFailed(emailType, metrics.ReasonRetriesExhausted)
+		c.deadLetter(msg, fmt.Sprintf("failed after %d attempts: %v", attempts, cause))
+		return
+	}
+
+	err := c.channel.Publish("", RetryQueue, false, false, amqp.Publishing{
+		ContentType:  msg.ContentType,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Expiration:   strconv.FormatInt(delay.Milliseconds(), 10),
+		Headers:      amqp.Table{RetryCountHeader: int32(attempts)},
+		Body:         msg.Body,
+	})
+	if err != nil {
+		// Without the retry queue the attempt cannot be counted; requeue as a last resort
+		log.Error().Err(err).Str("type", emailType).Msg("[EmailConsumer-retry] Failed to schedule retry, requeueing")
+		metrics.MessageRequeued(emailType, reason)
+		msg.Nack(false, true)
+		return
+	}
+
+	log.Info().Str("type", emailType).Int("attempt", attempts).Dur("delay", delay).Msg("[EmailConsumer-retry] Email scheduled for retry")
+	metrics.MessageRequeued(emailType, reason)
+	msg.Ack(false)
+}
+
+// retryCount reads RetryCountHeader, which AMQP may hand back as any integer type
+func retryCount(msg amqp.Delivery) int {
+	switch count := msg.Headers[RetryCountHeader].(type) {
+	case int32:
+		return int(count)
+	case int64:
+		return int(count)
+	case int:
+		return count
+	case int16:
+		return int(count)
+	case int8:
+		return int(count)
+	default:
+		return 0
+	}
+}
+
+// deadLetter moves a message that will never succeed to the DLQ. If that publish fails the
+// message is dropped as before, rather than requeued into the same failure.
+func (c *EmailConsumer) deadLetter(msg amqp.Delivery, reason string) {
+	err := c.channel.Publish("", DeadLetterQueue, false, false, amqp.Publishing{
+		ContentType:  msg.ContentType,
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    time.Now(),
+		Headers:      amqp.Table{FailureReasonHeader: reason},
+		Body:         msg.Body,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("reason", reason).Msg("[EmailConsumer-deadLetter] Failed to dead-letter message, dropping it")
+	} else {
+		log.Warn().Str("reason", reason).Msg("[EmailConsumer-deadLetter] Message moved to the dead letter queue")
+	}
+	msg.Nack(false, false)
+}
+
+// render picks the template for the recipient's language, falling back to the default language
+// and then to the subject and body the publisher rendered itself, which has no image. The
+// returned template version is "pre-rendered" in that last case.
+func (c *EmailConsumer) render(emailMsg EmailMessage, brand port.Brand) (subject, body, image, version string) {
+	if emailMsg.Data == nil {
+		return emailMsg.Subject, emailMsg.Body, "", preRenderedVersion
+	}
+
+	// Templates sign off with the brand name
+	data := make(map[string]string, len(emailMsg.Data)+1)
+	for key, value := range emailMsg.Data {
+		data[key] = value
+	}
+	data["brand_name"] = brand.Name
+
+	lang := emailMsg.Language
+	if lang == "" {
+		lang = c.config.Email.DefaultLanguage
+	}
+
+	rendered, err := c.renderer.Render(lang, emailMsg.Type, data)
+	if err != nil {
+		if !errors.Is(err, port.ErrTemplateNotFound) {
+			log.Error().Err(err).Str("type", emailMsg.Type).Str("language", lang).Msg("[EmailConsumer-render] Failed to render template, using the pre-rendered email")
+		}
+		return emailMsg.Subject, emailMsg.Body, "", preRenderedVersion
+	}
+
+	if rendered.Language != lang {
+		log.Warn().Str("type", emailMsg.Type).Str("language", lang).Str("fallback", rendered.Language).Msg("[EmailConsumer-render] No template in the requested language")
+	}
+	return rendered.Subject, rendered.Body, rendered.Image, rendered.Version
+}
+
+func (c *EmailConsumer) loadAttachments(ctx context.Context, messages []AttachmentMessage) ([]port.Attachment, error) {
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	sources := make([]port.AttachmentSource, 0, len(messages))
+	for _, attachment := range messages {
+		sources = append(sources, port.AttachmentSource{
+			Filename:    attachment.Filename,
+			ContentType: attachment.ContentType,
+			Content:     attachment.Content,
+			URL:         attachment.URL,
+		})
+	}
+	return c.attachmentService.Load(ctx, sources)
 }
@@ -0,0 +1,173 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"notification-service/config"
+
+	"github.com/rs/zerolog/log"
+	"github.com/streadway/amqp"
+)
+
+const (
+	// ProductEventsExchange is the topic exchange product-service publishes to
+	ProductEventsExchange = "product_events"
+	// ProductEventsQueue is this service's own queue on the exchange
+	ProductEventsQueue = "notification_product_events"
+
+	// EventProductBackInStock lists the waitlisted customers of a product that was restocked
+	EventProductBackInStock = "product.back_in_stock"
+
+	// BackInStockEmailType is the template sent to each waitlisted customer
+	BackInStockEmailType = "back_in_stock"
+)
+
+const productEventsConsumerTag = "notification-service-product-events"
+
+// ProductEvent has the same envelope as UserEvent; Data depends on Event
+type ProductEvent struct {
+	ID    string          `json:"event_id"`
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+// BackInStockData is one restock. product-service sends a large waitlist as several events,
+// each with part of the subscribers.
+type BackInStockData struct {
+	ProductID   string                  `json:"product_id"`
+	ProductName string                  `json:"product_name"`
+	Link        string                  `json:"link"`
+	RestockID   string                  `json:"restock_id"`
+	Subscribers []BackInStockSubscriber `json:"subscribers"`
+}
+
+type BackInStockSubscriber struct {
+	UserID   int64  `json:"user_id"`
+	Email    string `json:"email"`
+	Name     string `json:"name"`
+	Language string `json:"language"`
+}
+
+// ProductEventConsumer fans a product event out into one email per customer, queued on
+// email_queue like the user event emails
+type ProductEventConsumer struct {
+	config  *config.Config
+	channel EventChannel
+
+	// Keyed by restock and user, so an event retried after a partial fan-out, or a customer
+	// listed twice, is emailed once
+	sent *recentIDs
+}
+
+func NewProductEventConsumer(cfg *config.Config, channel EventChannel) *ProductEventConsumer {
+	return &ProductEventConsumer{
+		config:  cfg,
+		channel: channel,
+		sent:    newRecentIDs(seenEventsLimit),
+	}
+}
+
+func (c *ProductEventConsumer) StartConsuming(ctx context.Context) error {
+	if err := c.channel.ExchangeDeclare(ProductEventsExchange, "topic", true, false, false, false, nil); err != nil {
+		log.Error().Err(err).Msg("[ProductEventConsumer-StartConsuming] Failed to declare product events exchange")
+		return err
+	}
+
+	queue, err := c.channel.QueueDeclare(ProductEventsQueue, true, false, false, false, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("[ProductEventConsumer-StartConsuming] Failed to declare queue")
+		return err
+	}
+
+	if err := c.channel.QueueBind(queue.Name, EventProductBackInStock, ProductEventsExchange, false, nil); err != nil {
+		log.Error().Err(err).Str("event", EventProductBackInStock).Msg("[ProductEventConsumer-StartConsuming] Failed to bind queue")
+		return err
+	}
+
+	msgs, err := c.channel.Consume(queue.Name, productEventsConsumerTag, false, false, false, false, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("[ProductEventConsumer-StartConsuming] Failed to register consumer")
+		return err
+	}
+
+	log.Info().Msg("[ProductEventConsumer-StartConsuming] Started consuming product events")
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info().Msg("[ProductEventConsumer-StartConsuming] Stopping consumer")
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					log.Warn().Msg("[ProductEventConsumer-StartConsuming] Delivery channel closed, stopping consumer")
+					return
+				}
+				c.processMessage(msg)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (c *ProductEventConsumer) processMessage(msg amqp.Delivery) {
+	var event ProductEvent
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		log.Error().Err(err).Msg("[ProductEventConsumer-processMessage] Failed to unmarshal event, dropping it")
+		msg.Nack(false, false)
+		return
+	}
+
+	if event.Event != EventProductBackInStock {
+		log.Debug().Str("event", event.Event).Msg("[ProductEventConsumer-processMessage] Event has no email")
+		msg.Ack(false)
+		return
+	}
+
+	var data BackInStockData
+	if err := json.Unmarshal(event.Data, &data); err != nil || data.ProductID == "" {
+		log.Error().Err(err).Str("event_id", event.ID).Msg("[ProductEventConsumer-processMessage] Invalid back in stock event, dropping it")
+		msg.Nack(false, false)
+		return
+	}
+
+	queued, skipped := 0, 0
+	for _, subscriber := range data.Subscribers {
+		key := fmt.Sprintf("%s:%s:%s", data.ProductID, data.RestockID, subscriber.Email)
+		if subscriber.Email == "" || c.sent.Has(key) {
+			skipped++
+			continue
+		}
+
+		if err := queueEmail(c.channel, key, backInStockEmail(data, subscriber)); err != nil {
+			// Customers queued so far are remembered, so the redelivery only emails the rest
+			log.Error().Err(err).Str("event_id", event.ID).Int("queued", queued).Msg("[ProductEventConsumer-processMessage] Failed to queue email")
+			msg.Nack(false, true)
+			return
+		}
+		c.sent.Add(key)
+		queued++
+	}
+
+	log.Info().Str("event_id", event.ID).Str("product_id", data.ProductID).Int("queued", queued).Int("skipped", skipped).Msg("[ProductEventConsumer-processMessage] Back in stock emails queued")
+	msg.Ack(false)
+}
+
+// backInStockEmail carries a plain English fallback for when the template cannot be rendered
+func backInStockEmail(data BackInStockData, subscriber BackInStockSubscriber) *EmailMessage {
+	return &EmailMessage{
+		Email:    subscriber.Email,
+		Type:     BackInStockEmailType,
+		Name:     subscriber.Name,
+		Subject:  data.ProductName + " is back in stock",
+		Body:     fmt.Sprintf("Hi %s,\n\n%s is back in stock:\n%s\n", subscriber.Name, data.ProductName, data.Link),
+		Language: subscriber.Language,
+		Data: map[string]string{
+			"name":         subscriber.Name,
+			"product_name": data.ProductName,
+			"link":         data.Link,
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package consumer
+
+import "sync"
+
+// recentIDs remembers the last limit IDs, to drop events that are delivered twice. It lives in
+// memory only, so a restart forgets them.
+type recentIDs struct {
+	limit int
+
+	mu    sync.Mutex
+	ids   map[string]struct{}
+	order []string
+}
+
+func newRecentIDs(limit int) *recentIDs {
+	return &recentIDs{limit: limit, ids: make(map[string]struct{})}
+}
+
+// Has is false for an empty id, which is never remembered
+func (r *recentIDs) Has(id string) bool {
+	if id == "" {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.ids[id]
+	return ok
+}
+
+func (r *recentIDs) Add(id string) {
+	if id == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.ids[id]; ok {
+		return
+	}
+	r.ids[id] = struct{}{}
+	r.order = append(r.order, id)
+	if len(r.order) > r.limit {
+		delete(r.ids, r.order[0])
+		r.order = r.order[1:]
+	}
+}
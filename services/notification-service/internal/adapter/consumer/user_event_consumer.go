@@ -0,0 +1,197 @@
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"notification-service/config"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/streadway/amqp"
+)
+
+const (
+	// UserEventsExchange is the topic exchange user-service publishes domain events to
+	UserEventsExchange = "user_events"
+	// UserEventsQueue is this service's own queue on the exchange; other consumers, such as
+	// analytics, bind their own queues and see every event too
+	UserEventsQueue = "notification_user_events"
+
+	// EventUserVerified is published once a user verifies their email address
+	EventUserVerified = "user.verified"
+
+	// WelcomeEmailType is the template sent for EventUserVerified
+	WelcomeEmailType = "welcome"
+)
+
+// userEventsConsumerTag identifies this consumer to RabbitMQ
+const userEventsConsumerTag = "notification-service-user-events"
+
+// seenEventsLimit bounds the event IDs remembered for deduplication
+const seenEventsLimit = 10000
+
+// UserEvent is the envelope user-service publishes; Data depends on Event
+type UserEvent struct {
+	ID         string                 `json:"event_id"`
+	Event      string                 `json:"event"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+// EventChannel is the part of *amqp.Channel the event consumers use
+type EventChannel interface {
+	ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error
+	QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error)
+	QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error
+	Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error)
+	Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+}
+
+// UserEventConsumer turns user-service events into emails. It does not send them itself: each
+// email is queued on email_queue, so rendering, retries and the dead letter queue stay in one place.
+type UserEventConsumer struct {
+	config  *config.Config
+	channel EventChannel
+
+	// user-service retries publishing until it is confirmed, so an event can arrive twice
+	seen *recentIDs
+}
+
+func NewUserEventConsumer(cfg *config.Config, channel EventChannel) *UserEventConsumer {
+	return &UserEventConsumer{
+		config:  cfg,
+		channel: channel,
+		seen:    newRecentIDs(seenEventsLimit),
+	}
+}
+
+func (c *UserEventConsumer) StartConsuming(ctx context.Context) error {
+	// Same declaration as the publisher, so whichever side starts first creates it
+	if err := c.channel.ExchangeDeclare(UserEventsExchange, "topic", true, false, false, false, nil); err != nil {
+		log.Error().Err(err).Msg("[UserEventConsumer-StartConsuming] Failed to declare user events exchange")
+		return err
+	}
+
+	queue, err := c.channel.QueueDeclare(UserEventsQueue, true, false, false, false, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("[UserEventConsumer-StartConsuming] Failed to declare queue")
+		return err
+	}
+
+	if err := c.channel.QueueBind(queue.Name, EventUserVerified, UserEventsExchange, false, nil); err != nil {
+		log.Error().Err(err).Str("event", EventUserVerified).Msg("[UserEventConsumer-StartConsuming] Failed to bind queue")
+		return err
+	}
+
+	msgs, err := c.channel.Consume(queue.Name, userEventsConsumerTag, false, false, false, false, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("[UserEventConsumer-StartConsuming] Failed to register consumer")
+		return err
+	}
+
+	log.Info().Msg("[UserEventConsumer-StartConsuming] Started consuming user events")
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				log.Info().Msg("[UserEventConsumer-StartConsuming] Stopping consumer")
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					log.Warn().Msg("[UserEventConsumer-StartConsuming] Delivery channel closed, stopping consumer")
+					return
+				}
+				c.processMessage(msg)
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (c *UserEventConsumer) processMessage(msg amqp.Delivery) {
+	var event UserEvent
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		log.Error().Err(err).Msg("[UserEventConsumer-processMessage] Failed to unmarshal event, dropping it")
+		msg.Nack(false, false)
+		return
+	}
+
+	if c.seen.Has(event.ID) {
+		log.Info().Str("event", event.Event).Str("event_id", event.ID).Msg("[UserEventConsumer-processMessage] Duplicate event ignored")
+		msg.Ack(false)
+		return
+	}
+
+	var emailMsg *EmailMessage
+	switch event.Event {
+	case EventUserVerified:
+		emailMsg = c.welcomeEmail(event)
+	default:
+		log.Debug().Str("event", event.Event).Msg("[UserEventConsumer-processMessage] Event has no email")
+	}
+
+	if emailMsg != nil {
+		if emailMsg.Email == "" {
+			log.Error().Str("event", event.Event).Str("event_id", event.ID).Msg("[UserEventConsumer-processMessage] Event has no email address, dropping it")
+			msg.Nack(false, false)
+			return
+		}
+		if err := queueEmail(c.channel, event.ID, emailMsg); err != nil {
+			log.Error().Err(err).Str("event", event.Event).Str("event_id", event.ID).Msg("[UserEventConsumer-processMessage] Failed to queue email")
+			msg.Nack(false, true)
+			return
+		}
+		log.Info().Str("event", event.Event).Str("event_id", event.ID).Str("type", emailMsg.Type).Msg("[UserEventConsumer-processMessage] Email queued")
+	}
+
+	c.seen.Add(event.ID)
+	msg.Ack(false)
+}
+
+// welcomeEmail carries a plain English fallback for when the template cannot be rendered
+func (c *UserEventConsumer) welcomeEmail(event UserEvent) *EmailMessage {
+	name := stringField(event.Data, "name")
+	voucher := c.config.Welcome
+
+	body := fmt.Sprintf("Hi %s,\n\nYour account is verified and ready. Happy shopping!\n", name)
+	if voucher.VoucherCode != "" {
+		body += fmt.Sprintf("\nUse voucher code %s for %s off your first order.\n", voucher.VoucherCode, voucher.VoucherDiscount)
+	}
+
+	return &EmailMessage{
+		Email:    stringField(event.Data, "email"),
+		Type:     WelcomeEmailType,
+		Name:     name,
+		Subject:  "Welcome!",
+		Body:     body,
+		Language: stringField(event.Data, "language"),
+		Data: map[string]string{
+			"name":             name,
+			"voucher_code":     voucher.VoucherCode,
+			"voucher_discount": voucher.VoucherDiscount,
+		},
+	}
+}
+
+// queueEmail hands an email to the email consumer through email_queue
+func queueEmail(channel EventChannel, messageID string, emailMsg *EmailMessage) error {
+	body, err := json.Marshal(emailMsg)
+	if err != nil {
+		return err
+	}
+	return channel.Publish("", EmailQueue, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    messageID,
+		Timestamp:    time.Now(),
+		Body:         body,
+	})
+}
+
+func stringField(data map[string]interface{}, key string) string {
+	value, _ := data[key].(string)
+	return value
+}
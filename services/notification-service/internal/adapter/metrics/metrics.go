@@ -0,0 +1,158 @@
+// Package metrics exports what the email consumer is doing on a Prometheus /metrics listener.
+// Message metrics are labelled with the template, i.e. the message type such as "verification".
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/textproto"
+	"notification-service/internal/core/port"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog/log"
+)
+
+// unknownTemplate labels messages whose type could not be read
+const unknownTemplate = "unknown"
+
+var (
+	messagesConsumed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notification_messages_consumed_total",
+		Help: "Messages taken from email_queue.",
+	}, []string{"template"})
+	messagesProcessed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notification_messages_processed_total",
+		Help: "Messages whose email was sent and acknowledged.",
+	}, []string{"template"})
+	messagesFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notification_messages_failed_total",
+		Help: "Messages given up on and moved to the dead letter queue, by reason.",
+	}, []string{"template", "reason"})
+	messagesRequeued = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notification_messages_requeued_total",
+		Help: "Messages put back on email_queue to be retried, by reason.",
+	}, []string{"template", "reason"})
+	messagesDeferred = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notification_messages_deferred_total",
+		Help: "Messages over their recipient's hourly quota, moved to email_deferred until the next hour.",
+	}, []string{"template"})
+	sendDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "notification_email_send_duration_seconds",
+		Help:    "Time spent handing an email to SMTP, successful or not.",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"template"})
+	smtpErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notification_smtp_errors_total",
+		Help: "Failed SMTP sends by error type: circuit_open, timeout, connection, auth, temporary, rejected or other.",
+	}, []string{"template", "type"})
+)
+
+// Reasons a message is dead-lettered or requeued
+const (
+	ReasonInvalidMessage    = "invalid_message"
+	ReasonInvalidAttachment = "invalid_attachment"
+	ReasonAttachmentFailed  = "attachment_failed"
+	ReasonSMTPUnavailable   = "smtp_unavailable"
+	ReasonSMTPError         = "smtp_error"
+	ReasonSMTPRejected      = "smtp_rejected"
+	ReasonRetriesExhausted  = "retries_exhausted"
+	ReasonShutdown          = "shutdown"
+)
+
+func MessageConsumed(template string) {
+	messagesConsumed.WithLabelValues(templateLabel(template)).Inc()
+}
+
+func MessageProcessed(template string) {
+	messagesProcessed.WithLabelValues(templateLabel(template)).Inc()
+}
+
+func MessageFailed(template, reason string) {
+	messagesFailed.WithLabelValues(templateLabel(template), reason).Inc()
+}
+
+func MessageRequeued(template, reason string) {
+	messagesRequeued.WithLabelValues(templateLabel(template), reason).Inc()
+}
+
+func MessageDeferred(template string) {
+	messagesDeferred.WithLabelValues(templateLabel(template)).Inc()
+}
+
+// EmailSent records one SMTP send that started at start; err is what SendEmail returned
+func EmailSent(template string, start time.Time, err error) {
+	template = templateLabel(template)
+	// A rejection by the open breaker never reached SMTP, so it says nothing about latency
+	if !errors.Is(err, port.ErrEmailUnavailable) {
+		sendDuration.WithLabelValues(template).Observe(time.Since(start).Seconds())
+	}
+	if err != nil {
+		smtpErrors.WithLabelValues(template, smtpErrorType(err)).Inc()
+	}
+}
+
+// smtpErrorType groups SMTP failures by what an operator would do about them: 535 and
+// friends are credentials, 4xx replies are worth retrying, 5xx replies are not.
+func smtpErrorType(err error) string {
+	var (
+		netErr   net.Error
+		protoErr *textproto.Error
+	)
+	switch {
+	case errors.Is(err, port.ErrEmailUnavailable):
+		return "circuit_open"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.As(err, &protoErr):
+		switch {
+		case protoErr.Code == 530 || protoErr.Code == 534 || protoErr.Code == 535:
+			return "auth"
+		case protoErr.Code >= 400 && protoErr.Code < 500:
+			return "temporary"
+		default:
+			return "rejected"
+		}
+	case errors.As(err, &netErr):
+		return "connection"
+	default:
+		return "other"
+	}
+}
+
+func templateLabel(template string) string {
+	if template == "" {
+		return unknownTemplate
+	}
+	return template
+}
+
+// Serve exposes /metrics on addr until ctx is cancelled. Addr "off" disables the listener.
+func Serve(ctx context.Context, addr string) {
+	if addr == "" || addr == "off" {
+		log.Info().Msg("[Metrics-Serve] Metrics listener disabled")
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Error().Err(err).Msg("[Metrics-Serve] Failed to stop metrics listener")
+		}
+	}()
+
+	log.Info().Str("addr", addr).Msg("[Metrics-Serve] Serving metrics")
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error().Err(err).Str("addr", addr).Msg("[Metrics-Serve] Metrics listener stopped")
+	}
+}
@@ -0,0 +1,60 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"notification-service/internal/core/port"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// limitsKey is written by user-service whenever an admin changes a limit
+const limitsKey = "notification:email_rate_limits"
+
+// limitsSnapshot mirrors user-service's EmailRateLimitRepository.PublishLimits
+type limitsSnapshot struct {
+	PerHour map[string]int `json:"per_hour"`
+}
+
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(client *redis.Client) port.EmailQuotaStoreInterface {
+	return &RedisStore{client: client}
+}
+
+func (s *RedisStore) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+func (s *RedisStore) Decrement(ctx context.Context, key string) error {
+	return s.client.Decr(ctx, key).Err()
+}
+
+func (s *RedisStore) Limits(ctx context.Context) (map[string]int, error) {
+	raw, err := s.client.Get(ctx, limitsKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot limitsSnapshot
+	if err := json.Unmarshal(raw, &snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot.PerHour, nil
+}
@@ -0,0 +1,462 @@
+package templates
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"notification-service/config"
+	"notification-service/internal/core/port"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// locales holds one directory per language, e.g. locales/id/password_reset.tmpl.
+// Every file defines a "subject" and a "body" template; the file name is the email type.
+//
+//go:embed locales
+var locales embed.FS
+
+// layout wraps every rendered body in the tenant's branding for the HTML part
+//
+//go:embed layout.html
+var layoutHTML string
+
+// pinsFile in the template directory pins "lang/type" to an earlier version, e.g.
+// {"id/password_reset": "3f2a9c1b2d4e"}, to roll back without editing the template
+const pinsFile = "pins.json"
+
+// versionsDir in the template directory keeps a copy of every version loaded, as
+// versions/<lang>/<type>/<checksum>.tmpl, so a pin still resolves after a restart
+const versionsDir = "versions"
+
+// templateVersion is one loaded revision of a template file, identified by its content checksum
+type templateVersion struct {
+	checksum string
+	tmpl     *template.Template
+}
+
+type Renderer struct {
+	// source is the embedded locales, or cfg.Email.TemplateDir when templates are managed outside the binary
+	source         fs.FS
+	dir            string
+	historySize    int
+	reloadInterval time.Duration
+
+	mu sync.RWMutex
+	// templates is keyed by language, then by email type; the newest version is last
+	templates map[string]map[string][]templateVersion
+	// pins is keyed by "lang/type" and holds a version checksum; pinned holds those versions
+	pins   map[string]string
+	pinned map[string]templateVersion
+	// rejected remembers the checksum of each file that failed to parse, so it is logged once
+	rejected map[string]string
+
+	layout          *htmltemplate.Template
+	assets          port.AssetResolverInterface
+	defaultLanguage string
+}
+
+// NewRenderer parses every template up front, so a broken one stops the service at start-up
+func NewRenderer(cfg *config.Config, assets port.AssetResolverInterface) (port.EmailRendererInterface, error) {
+	source, err := fs.Sub(locales, "locales")
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Email.TemplateDir != "" {
+		source = os.DirFS(cfg.Email.TemplateDir)
+	}
+
+	layout, err := htmltemplate.New("layout").Parse(layoutHTML)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Renderer{
+		source:          source,
+		dir:             cfg.Email.TemplateDir,
+		historySize:     cfg.Email.TemplateHistory,
+		reloadInterval:  time.Duration(cfg.Email.TemplateReloadSeconds) * time.Second,
+		templates:       make(map[string]map[string][]templateVersion),
+		pins:            make(map[string]string),
+		pinned:          make(map[string]templateVersion),
+		rejected:        make(map[string]string),
+		layout:          layout,
+		assets:          assets,
+		defaultLanguage: cfg.Email.DefaultLanguage,
+	}
+	if r.historySize < 1 {
+		r.historySize = 1
+	}
+
+	if err := r.load(true); err != nil {
+		return nil, err
+	}
+
+	if _, ok := r.templates[r.defaultLanguage]; !ok {
+		log.Warn().Str("language", r.defaultLanguage).Msg("[Renderer] No templates for the default language")
+	}
+
+	return r, nil
+}
+
+// Watch reloads the template directory until ctx is done. Embedded templates never change,
+// so there is nothing to watch without cfg.Email.TemplateDir.
+func (r *Renderer) Watch(ctx context.Context) {
+	if r.dir == "" || r.reloadInterval <= 0 {
+		return
+	}
+
+	log.Info().Str("dir", r.dir).Dur("interval", r.reloadInterval).Msg("[Renderer-Watch] Watching email templates")
+	ticker := time.NewTicker(r.reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.load(false); err != nil {
+				log.Error().Err(err).Str("dir", r.dir).Msg("[Renderer-Watch] Failed to reload email templates")
+			}
+		}
+	}
+}
+
+// load reads every template and keeps the ones whose checksum changed as a new version;
+// templates whose file is gone are dropped. At start-up a broken template or a pin whose
+// version cannot be found is an error; on reload it is logged and the current state stays.
+func (r *Renderer) load(startup bool) error {
+	type loaded struct {
+		lang, emailType string
+		version         templateVersion
+	}
+	var changed []loaded
+	present := make(map[string]bool)
+
+	err := fs.WalkDir(r.source, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && filePath == versionsDir && r.dir != "" {
+			return fs.SkipDir
+		}
+		if entry.IsDir() || path.Ext(filePath) != ".tmpl" {
+			return nil
+		}
+
+		lang := path.Base(path.Dir(filePath))
+		emailType := strings.TrimSuffix(path.Base(filePath), ".tmpl")
+		present[lang+"/"+emailType] = true
+
+		raw, err := fs.ReadFile(r.source, filePath)
+		if err != nil {
+			return err
+		}
+		checksum := templateChecksum(raw)
+
+		if current, ok := r.latest(lang, emailType); ok && current.checksum == checksum {
+			return nil
+		}
+		if r.rejected[filePath] == checksum {
+			return nil
+		}
+
+		tmpl, err := r.parse(emailType, raw)
+		if err != nil {
+			if startup {
+				return err
+			}
+			r.rejected[filePath] = checksum
+			log.Error().Err(err).Str("file", filePath).Str("version", checksum).Msg("[Renderer-load] Invalid template, keeping the current version")
+			return nil
+		}
+
+		r.saveVersion(lang, emailType, checksum, raw)
+		changed = append(changed, loaded{lang, emailType, templateVersion{checksum: checksum, tmpl: tmpl}})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Pins are resolved before anything is swapped in, so a bad pins file changes nothing
+	pins, err := r.readPins()
+	var pinned map[string]templateVersion
+	if err == nil {
+		candidates := make(map[string]templateVersion, len(changed))
+		for _, c := range changed {
+			candidates[c.lang+"/"+c.emailType+"@"+c.version.checksum] = c.version
+		}
+		pinned, err = r.resolvePins(pins, candidates)
+	}
+	if err != nil {
+		if startup {
+			return err
+		}
+		log.Error().Err(err).Msg("[Renderer-load] Invalid pins, keeping the current pins")
+		pins = nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, c := range changed {
+		if r.templates[c.lang] == nil {
+			r.templates[c.lang] = make(map[string][]templateVersion)
+		}
+		versions := r.templates[c.lang][c.emailType]
+
+		// Editing a template back to an earlier revision reuses that version instead of duplicating it
+		for i, version := range versions {
+			if version.checksum == c.version.checksum {
+				versions = append(versions[:i], versions[i+1:]...)
+				break
+			}
+		}
+		versions = append(versions, c.version)
+		if len(versions) > r.historySize {
+			versions = versions[len(versions)-r.historySize:]
+		}
+		r.templates[c.lang][c.emailType] = versions
+
+		if !startup {
+			log.Info().Str("language", c.lang).Str("type", c.emailType).Str("version", c.version.checksum).Msg("[Renderer-load] Loaded new template version")
+		}
+	}
+
+	// A deleted file takes its template with it; a pin on it keeps working
+	for lang, types := range r.templates {
+		for emailType := range types {
+			if present[lang+"/"+emailType] {
+				continue
+			}
+			delete(types, emailType)
+			log.Info().Str("language", lang).Str("type", emailType).Msg("[Renderer-load] Template file removed, template dropped")
+		}
+		if len(types) == 0 {
+			delete(r.templates, lang)
+		}
+	}
+
+	if pins != nil {
+		for key, checksum := range pins {
+			if r.pins[key] != checksum {
+				log.Info().Str("template", key).Str("version", checksum).Msg("[Renderer-load] Template pinned")
+			}
+		}
+		for key := range r.pins {
+			if _, ok := pins[key]; !ok {
+				log.Info().Str("template", key).Msg("[Renderer-load] Template unpinned")
+			}
+		}
+		r.pins = pins
+		r.pinned = pinned
+	}
+
+	return nil
+}
+
+// parse compiles one template file. A missing value fails the render, which then falls back to
+// the pre-rendered email. So does an unknown name in {{asset "products/bayam.webp"}}, which
+// resolves an uploaded image's URL.
+func (r *Renderer) parse(emailType string, raw []byte) (*template.Template, error) {
+	return template.New(emailType).Option("missingkey=error").Funcs(template.FuncMap{"asset": r.assets.URL}).Parse(string(raw))
+}
+
+// templateChecksum is the version of a template: the first 12 hex characters of its SHA-256
+func templateChecksum(raw []byte) string {
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+func (r *Renderer) versionPath(lang, emailType, checksum string) string {
+	return filepath.Join(r.dir, versionsDir, lang, emailType, checksum+".tmpl")
+}
+
+// saveVersion keeps a copy of a template version for pins to find after a restart. A
+// read-only template directory only costs that, so a failure is logged and loading goes on.
+func (r *Renderer) saveVersion(lang, emailType, checksum string, raw []byte) {
+	if r.dir == "" {
+		return
+	}
+	file := r.versionPath(lang, emailType, checksum)
+	if _, err := os.Stat(file); err == nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		log.Error().Err(err).Str("file", file).Msg("[Renderer-saveVersion] Failed to keep template version, it cannot be pinned after a restart")
+		return
+	}
+	if err := os.WriteFile(file, raw, 0o644); err != nil {
+		log.Error().Err(err).Str("file", file).Msg("[Renderer-saveVersion] Failed to keep template version, it cannot be pinned after a restart")
+	}
+}
+
+// resolvePins finds the version every pin names: one just loaded (candidates, keyed by
+// "lang/type@checksum"), one in memory, or one kept in the versions directory
+func (r *Renderer) resolvePins(pins map[string]string, candidates map[string]templateVersion) (map[string]templateVersion, error) {
+	pinned := make(map[string]templateVersion, len(pins))
+	for key, checksum := range pins {
+		lang, emailType, ok := strings.Cut(key, "/")
+		if !ok || lang == "" || emailType == "" {
+			return nil, fmt.Errorf("pin %q must be lang/type", key)
+		}
+
+		if version, ok := candidates[key+"@"+checksum]; ok {
+			pinned[key] = version
+			continue
+		}
+		if version, ok := r.loaded(lang, emailType, checksum); ok {
+			pinned[key] = version
+			continue
+		}
+
+		raw, err := os.ReadFile(r.versionPath(lang, emailType, checksum))
+		if err != nil {
+			return nil, fmt.Errorf("pinned version %s of %s not found: %w", checksum, key, err)
+		}
+		// The file is named after its checksum; a copy edited by hand is not that version
+		if templateChecksum(raw) != checksum {
+			return nil, fmt.Errorf("pinned version %s of %s does not match its checksum", checksum, key)
+		}
+		tmpl, err := r.parse(emailType, raw)
+		if err != nil {
+			return nil, fmt.Errorf("pinned version %s of %s: %w", checksum, key, err)
+		}
+		pinned[key] = templateVersion{checksum: checksum, tmpl: tmpl}
+	}
+	return pinned, nil
+}
+
+// readPins returns an empty map when there is no pins file
+func (r *Renderer) readPins() (map[string]string, error) {
+	pins := make(map[string]string)
+	if r.dir == "" {
+		return pins, nil
+	}
+
+	raw, err := fs.ReadFile(r.source, pinsFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return pins, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, &pins); err != nil {
+		return nil, err
+	}
+	return pins, nil
+}
+
+// loaded finds a version still in the in-memory history
+func (r *Renderer) loaded(lang, emailType, checksum string) (templateVersion, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, version := range r.templates[lang][emailType] {
+		if version.checksum == checksum {
+			return version, true
+		}
+	}
+	return templateVersion{}, false
+}
+
+func (r *Renderer) latest(lang, emailType string) (templateVersion, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := r.templates[lang][emailType]
+	if len(versions) == 0 {
+		return templateVersion{}, false
+	}
+	return versions[len(versions)-1], true
+}
+
+// active is the pinned version, otherwise the newest one. Pins are resolved when they are
+// loaded, so a pinned template is never silently replaced by the newest version.
+func (r *Renderer) active(lang, emailType string) (templateVersion, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if version, ok := r.pinned[lang+"/"+emailType]; ok {
+		return version, true
+	}
+
+	versions := r.templates[lang][emailType]
+	if len(versions) == 0 {
+		return templateVersion{}, false
+	}
+	return versions[len(versions)-1], true
+}
+
+// Render uses the template for lang, then the one for the default language
+func (r *Renderer) Render(lang, emailType string, data map[string]string) (*port.RenderedEmail, error) {
+	for _, candidate := range []string{strings.ToLower(lang), r.defaultLanguage} {
+		version, ok := r.active(candidate, emailType)
+		if !ok {
+			continue
+		}
+
+		var subject, body strings.Builder
+		if err := version.tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+			return nil, err
+		}
+		if err := version.tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+			return nil, err
+		}
+		// "image" is optional, e.g. {{define "image"}}{{asset "products/bayam.webp"}}{{end}}
+		var image strings.Builder
+		if version.tmpl.Lookup("image") != nil {
+			if err := version.tmpl.ExecuteTemplate(&image, "image", data); err != nil {
+				return nil, err
+			}
+		}
+
+		return &port.RenderedEmail{
+			Language: candidate,
+			Version:  version.checksum,
+			Subject:  strings.TrimSpace(subject.String()),
+			Body:     body.String(),
+			Image:    strings.TrimSpace(image.String()),
+		}, nil
+	}
+
+	return nil, port.ErrTemplateNotFound
+}
+
+// Layout turns a plain-text body into the branded HTML part; the body is escaped, not trusted as HTML
+func (r *Renderer) Layout(brand port.Brand, subject, body, image string) (string, error) {
+	// An asset missing from the manifest is not worth failing the HTML part; LogoURL still works
+	if brand.LogoAsset != "" {
+		if logoURL, err := r.assets.URL(brand.LogoAsset); err == nil {
+			brand.LogoURL = logoURL
+		} else {
+			log.Warn().Err(err).Str("brand", brand.Name).Msg("[Renderer-Layout] Logo asset not resolved, using logo_url")
+		}
+	}
+
+	var html strings.Builder
+	err := r.layout.Execute(&html, struct {
+		Brand   port.Brand
+		Subject string
+		Body    string
+		Image   string
+	}{brand, subject, body, image})
+	if err != nil {
+		return "", err
+	}
+	return html.String(), nil
+}
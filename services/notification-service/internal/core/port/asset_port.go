@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrAssetNotFound means the manifest has no asset by that name, e.g. it was never uploaded
+var ErrAssetNotFound = errors.New("asset not found")
+
+// AssetResolverInterface maps an asset name such as "logo.png" to its current public URL
+type AssetResolverInterface interface {
+	URL(name string) (string, error)
+	// Watch keeps the names up to date until ctx is done
+	Watch(ctx context.Context)
+}
@@ -0,0 +1,30 @@
+package port
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidAttachment marks attachments that will never succeed, such as bad base64,
+// an oversized file or a URL outside the allowed hosts; the message is dropped, not retried
+var ErrInvalidAttachment = errors.New("invalid attachment")
+
+// AttachmentSource is an attachment as it arrives in the queue payload: inline base64 Content
+// or a storage URL to download, never both
+type AttachmentSource struct {
+	Filename    string
+	ContentType string
+	Content     string
+	URL         string
+}
+
+// Attachment is a file ready to send, decoded or downloaded and within the size limits
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+type AttachmentServiceInterface interface {
+	Load(ctx context.Context, sources []AttachmentSource) ([]Attachment, error)
+}
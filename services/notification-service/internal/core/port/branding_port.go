@@ -0,0 +1,18 @@
+package port
+
+// Brand is how one tenant's emails look and who they come from. LogoAsset names an uploaded
+// asset, e.g. "logo.png", and wins over LogoURL once the asset manifest has it.
+type Brand struct {
+	Name          string `json:"name"`
+	LogoURL       string `json:"logo_url"`
+	LogoAsset     string `json:"logo_asset"`
+	PrimaryColor  string `json:"primary_color"`
+	Footer        string `json:"footer"`
+	SenderName    string `json:"sender_name"`
+	SenderAddress string `json:"sender_address"`
+}
+
+type BrandingServiceInterface interface {
+	// ForTenant returns the tenant's brand, or the default brand for "" and unknown tenants
+	ForTenant(tenant string) Brand
+}
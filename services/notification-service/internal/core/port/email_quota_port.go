@@ -0,0 +1,33 @@
+package port
+
+import (
+	"context"
+	"time"
+)
+
+// QuotaReservation is one email counted against its recipient's hourly quotas. When Allowed is
+// false nothing was counted and the email may be sent from RetryAt, the start of the next hour.
+type QuotaReservation struct {
+	Allowed bool
+	RetryAt time.Time
+	// Keys are the counters that were incremented, for Release
+	Keys []string
+}
+
+type EmailQuotaServiceInterface interface {
+	// Reserve counts an email of emailType to recipient unless a quota is used up. An error
+	// means the counters could not be reached and the email should be sent anyway.
+	Reserve(ctx context.Context, recipient, emailType string) (*QuotaReservation, error)
+	// Release gives back a reservation whose email was not sent after all
+	Release(ctx context.Context, reservation *QuotaReservation)
+}
+
+// EmailQuotaStoreInterface is where the counters live, shared by every consumer instance
+type EmailQuotaStoreInterface interface {
+	// Increment adds one to key and returns the new count; the key expires after ttl
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	Decrement(ctx context.Context, key string) error
+	// Limits returns the per-hour limits admins set in user-service by email type, "*" being
+	// all emails; nil when none were published
+	Limits(ctx context.Context) (map[string]int, error)
+}
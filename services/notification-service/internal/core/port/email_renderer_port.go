@@ -0,0 +1,27 @@
+package port
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrTemplateNotFound means no locale has a template for the email type
+var ErrTemplateNotFound = errors.New("email template not found")
+
+type RenderedEmail struct {
+	// Language is the locale actually used, which differs from the requested one after a fallback
+	Language string
+	// Version is the checksum of the template revision used, for tracing which copy an email got
+	Version string
+	Subject string
+	Body    string
+	// Image is an optional picture URL, e.g. a product photo, shown above the body in the HTML part
+	Image string
+}
+
+type EmailRendererInterface interface {
+	Render(lang, emailType string, data map[string]string) (*RenderedEmail, error)
+	Layout(brand Brand, subject, body, image string) (string, error)
+	// Watch picks up template changes without a redeploy until ctx is done
+	Watch(ctx context.Context)
+}
@@ -1,7 +1,27 @@
 package port
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrEmailUnavailable is returned without contacting SMTP while its circuit breaker is open
+var ErrEmailUnavailable = errors.New("smtp is unavailable, circuit breaker is open")
+
+// ErrEmailRejected wraps a permanent (5xx) SMTP reply; sending the same email again fails the same way
+var ErrEmailRejected = errors.New("smtp rejected the email")
+
+// Email is a message ready for SMTP. HTMLBody is optional; without it only the text part is sent.
+type Email struct {
+	To          string
+	FromName    string
+	FromAddress string
+	Subject     string
+	TextBody    string
+	HTMLBody    string
+	Attachments []Attachment
+}
 
 type EmailServiceInterface interface {
-	SendEmail(ctx context.Context, to, subject, body string) error
+	SendEmail(ctx context.Context, email Email) error
 }
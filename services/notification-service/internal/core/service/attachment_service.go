@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"notification-service/config"
+	"notification-service/internal/core/port"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+type AttachmentService struct {
+	config *config.Config
+	client *http.Client
+}
+
+func NewAttachmentService(cfg *config.Config) port.AttachmentServiceInterface {
+	return &AttachmentService{
+		config: cfg,
+		client: &http.Client{
+			Timeout: time.Duration(cfg.Attachment.DownloadTimeoutSeconds) * time.Second,
+			// A redirect could lead outside the allowed hosts
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// Load decodes or downloads every attachment. Errors wrapping port.ErrInvalidAttachment are
+// permanent; any other error is a failed download worth retrying.
+func (s *AttachmentService) Load(ctx context.Context, sources []port.AttachmentSource) ([]port.Attachment, error) {
+	attachments := make([]port.Attachment, 0, len(sources))
+	total := 0
+
+	for i, source := range sources {
+		filename := sanitizeFilename(source.Filename)
+
+		var (
+			content []byte
+			err     error
+		)
+		switch {
+		case source.Content != "" && source.URL != "":
+			return nil, fmt.Errorf("%w: %s has both content and url", port.ErrInvalidAttachment, filename)
+		case source.Content != "":
+			content, err = s.decode(source.Content)
+		case source.URL != "":
+			content, err = s.download(ctx, source.URL)
+		default:
+			return nil, fmt.Errorf("%w: %s has neither content nor url", port.ErrInvalidAttachment, filename)
+		}
+		if err != nil {
+			log.Error().Err(err).Int("index", i).Str("filename", filename).Msg("[AttachmentService-Load] Failed to load attachment")
+			return nil, err
+		}
+
+		total += len(content)
+		if total > s.config.Attachment.MaxTotalBytes {
+			return nil, fmt.Errorf("%w: attachments exceed %d bytes in total", port.ErrInvalidAttachment, s.config.Attachment.MaxTotalBytes)
+		}
+
+		attachments = append(attachments, port.Attachment{
+			Filename:    filename,
+			ContentType: contentType(source.ContentType, filename, content),
+			Content:     content,
+		})
+	}
+
+	return attachments, nil
+}
+
+func (s *AttachmentService) decode(encoded string) ([]byte, error) {
+	// Reject before decoding; base64 is 4 bytes per 3
+	if base64.StdEncoding.DecodedLen(len(encoded)) > s.config.Attachment.MaxBytes+2 {
+		return nil, fmt.Errorf("%w: larger than %d bytes", port.ErrInvalidAttachment, s.config.Attachment.MaxBytes)
+	}
+
+	content, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: content is not valid base64", port.ErrInvalidAttachment)
+	}
+	if len(content) > s.config.Attachment.MaxBytes {
+		return nil, fmt.Errorf("%w: larger than %d bytes", port.ErrInvalidAttachment, s.config.Attachment.MaxBytes)
+	}
+	return content, nil
+}
+
+func (s *AttachmentService) download(ctx context.Context, rawURL string) ([]byte, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Scheme != "https" || !s.allowedHost(parsed.Hostname()) {
+		return nil, fmt.Errorf("%w: url must be https on an allowed host", port.ErrInvalidAttachment)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", port.ErrInvalidAttachment, err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		// Missing or forbidden objects will not appear by retrying
+		return nil, fmt.Errorf("%w: download returned %d", port.ErrInvalidAttachment, resp.StatusCode)
+	default:
+		return nil, fmt.Errorf("attachment download returned %d", resp.StatusCode)
+	}
+
+	if resp.ContentLength > int64(s.config.Attachment.MaxBytes) {
+		return nil, fmt.Errorf("%w: larger than %d bytes", port.ErrInvalidAttachment, s.config.Attachment.MaxBytes)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, int64(s.config.Attachment.MaxBytes)+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(content) > s.config.Attachment.MaxBytes {
+		return nil, fmt.Errorf("%w: larger than %d bytes", port.ErrInvalidAttachment, s.config.Attachment.MaxBytes)
+	}
+	return content, nil
+}
+
+func (s *AttachmentService) allowedHost(host string) bool {
+	for _, allowed := range s.config.Attachment.AllowedHosts {
+		if strings.EqualFold(host, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeFilename keeps the base name only and drops characters that could break the MIME header
+func sanitizeFilename(filename string) string {
+	filename = path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	filename = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, filename)
+	if filename == "" || filename == "." || filename == "/" {
+		return "attachment"
+	}
+	return filename
+}
+
+// contentType trusts a well-formed declared type, then the extension, then the bytes
+func contentType(declared, filename string, content []byte) string {
+	if declared != "" {
+		if mediaType, _, err := mime.ParseMediaType(declared); err == nil {
+			return mediaType
+		}
+	}
+	if byExtension := mime.TypeByExtension(path.Ext(filename)); byExtension != "" {
+		return byExtension
+	}
+	return http.DetectContentType(content)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"notification-service/config"
+	"notification-service/internal/core/port"
+	"os"
+	"regexp"
+
+	"github.com/rs/zerolog/log"
+)
+
+var brandColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type BrandingService struct {
+	defaultBrand port.Brand
+	tenants      map[string]port.Brand
+}
+
+// NewBrandingService builds the default brand from config and reads the tenants in
+// cfg.Branding.File, a JSON object keyed by tenant:
+//
+//	{"acme": {"name": "Acme Fresh", "logo_url": "https://...", "sender_address": "hello@acme.id"}}
+//
+// Fields a tenant leaves out come from the default brand. Invalid brands stop start-up.
+func NewBrandingService(cfg *config.Config) (port.BrandingServiceInterface, error) {
+	defaultBrand := port.Brand{
+		Name:          cfg.Branding.Name,
+		LogoURL:       cfg.Branding.LogoURL,
+		LogoAsset:     cfg.Branding.LogoAsset,
+		PrimaryColor:  cfg.Branding.PrimaryColor,
+		Footer:        cfg.Branding.Footer,
+		SenderName:    cfg.Branding.SenderName,
+		SenderAddress: cfg.Branding.SenderAddress,
+	}
+	if err := validateBrand(defaultBrand); err != nil {
+		return nil, fmt.Errorf("default brand: %w", err)
+	}
+
+	tenants := make(map[string]port.Brand)
+	if cfg.Branding.File != "" {
+		raw, err := os.ReadFile(cfg.Branding.File)
+		if err != nil {
+			return nil, err
+		}
+
+		var overrides map[string]port.Brand
+		if err := json.Unmarshal(raw, &overrides); err != nil {
+			return nil, fmt.Errorf("%s: %w", cfg.Branding.File, err)
+		}
+
+		for tenant, override := range overrides {
+			brand := mergeBrand(defaultBrand, override)
+			if err := validateBrand(brand); err != nil {
+				return nil, fmt.Errorf("tenant %q: %w", tenant, err)
+			}
+			tenants[tenant] = brand
+		}
+		log.Info().Int("tenants", len(tenants)).Str("file", cfg.Branding.File).Msg("[BrandingService] Loaded tenant branding")
+	}
+
+	return &BrandingService{
+		defaultBrand: defaultBrand,
+		tenants:      tenants,
+	}, nil
+}
+
+func (s *BrandingService) ForTenant(tenant string) port.Brand {
+	if brand, ok := s.tenants[tenant]; ok {
+		return brand
+	}
+	if tenant != "" {
+		log.Warn().Str("tenant", tenant).Msg("[BrandingService-ForTenant] Unknown tenant, using the default brand")
+	}
+	return s.defaultBrand
+}
+
+func mergeBrand(base, override port.Brand) port.Brand {
+	if override.Name != "" {
+		base.Name = override.Name
+	}
+	if override.LogoURL != "" {
+		base.LogoURL = override.LogoURL
+	}
+	if override.LogoAsset != "" {
+		base.LogoAsset = override.LogoAsset
+	}
+	if override.PrimaryColor != "" {
+		base.PrimaryColor = override.PrimaryColor
+	}
+	if override.Footer != "" {
+		base.Footer = override.Footer
+	}
+	if override.SenderName != "" {
+		base.SenderName = override.SenderName
+	}
+	if override.SenderAddress != "" {
+		base.SenderAddress = override.SenderAddress
+	}
+	return base
+}
+
+// validateBrand keeps values that end up in HTML and mail headers well-formed
+func validateBrand(brand port.Brand) error {
+	if brand.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !brandColorPattern.MatchString(brand.PrimaryColor) {
+		return fmt.Errorf("primary_color %q must look like #16a34a", brand.PrimaryColor)
+	}
+	if brand.LogoURL != "" {
+		parsed, err := url.Parse(brand.LogoURL)
+		if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+			return fmt.Errorf("logo_url must be an https URL")
+		}
+	}
+	if _, err := mail.ParseAddress(brand.SenderAddress); err != nil {
+		return fmt.Errorf("sender_address %q is not an email address", brand.SenderAddress)
+	}
+	return nil
+}
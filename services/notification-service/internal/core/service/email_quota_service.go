@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"notification-service/config"
+	"notification-service/internal/core/port"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// quotaWindow is the clock hour the counters are kept for
+const quotaWindow = time.Hour
+
+// allEmailsLimit is the key of the limit across every email type, as user-service publishes it
+const allEmailsLimit = "*"
+
+type EmailQuotaService struct {
+	config *config.Config
+	store  port.EmailQuotaStoreInterface
+
+	// Admin limits are cached for LimitsRefreshSeconds; a failed refresh keeps the last ones
+	mu            sync.Mutex
+	limits        map[string]int
+	limitsFetched time.Time
+}
+
+func NewEmailQuotaService(cfg *config.Config, store port.EmailQuotaStoreInterface) port.EmailQuotaServiceInterface {
+	return &EmailQuotaService{
+		config: cfg,
+		store:  store,
+	}
+}
+
+// Reserve counts the email against the recipient's quota across all emails, then against the
+// quota for its type. A limit of 0 means unlimited. Counters of a denied email are given back,
+// so it only counts once it is actually sent.
+func (s *EmailQuotaService) Reserve(ctx context.Context, recipient, emailType string) (*port.QuotaReservation, error) {
+	now := time.Now()
+	window := now.Truncate(quotaWindow)
+	reservation := &port.QuotaReservation{Allowed: true, RetryAt: window.Add(quotaWindow)}
+
+	perUser, perTemplate := s.limitsFor(ctx, emailType)
+	if perUser == 0 && perTemplate == 0 {
+		return reservation, nil
+	}
+
+	recipient = strings.ToLower(strings.TrimSpace(recipient))
+	// Keys outlive their hour a little, so a slow clock on another instance still sees them
+	ttl := window.Add(quotaWindow).Sub(now) + time.Minute
+
+	checks := []struct {
+		key   string
+		limit int
+	}{
+		{fmt.Sprintf("email_quota:user:%s:%d", recipient, window.Unix()), perUser},
+		{fmt.Sprintf("email_quota:template:%s:%s:%d", recipient, emailType, window.Unix()), perTemplate},
+	}
+	for _, check := range checks {
+		if check.limit == 0 {
+			continue
+		}
+
+		count, err := s.store.Increment(ctx, check.key, ttl)
+		if err != nil {
+			s.Release(ctx, reservation)
+			return nil, err
+		}
+		reservation.Keys = append(reservation.Keys, check.key)
+
+		if count > int64(check.limit) {
+			s.Release(ctx, reservation)
+			log.Info().Str("email", recipient).Str("type", emailType).Int("limit", check.limit).Str("counter", check.key).Msg("[EmailQuotaService-Reserve] Email quota used up")
+			return &port.QuotaReservation{Allowed: false, RetryAt: reservation.RetryAt}, nil
+		}
+	}
+
+	return reservation, nil
+}
+
+func (s *EmailQuotaService) Release(ctx context.Context, reservation *port.QuotaReservation) {
+	if reservation == nil {
+		return
+	}
+	for _, key := range reservation.Keys {
+		if err := s.store.Decrement(ctx, key); err != nil {
+			log.Warn().Err(err).Str("counter", key).Msg("[EmailQuotaService-Release] Failed to release email quota")
+		}
+	}
+	reservation.Keys = nil
+}
+
+// limitsFor returns the hourly limits for all emails and for emailType. An admin limit wins;
+// without one exempt types are unlimited and the rest get the configured defaults.
+func (s *EmailQuotaService) limitsFor(ctx context.Context, emailType string) (perUser, perTemplate int) {
+	limits := s.adminLimits(ctx)
+
+	if limit, ok := limits[emailType]; ok {
+		perTemplate = limit
+	} else if s.isExempt(emailType) {
+		// Security emails are never held back, not even by the quota across all emails
+		return 0, 0
+	} else {
+		perTemplate = s.config.RateLimit.PerTemplateHour
+	}
+
+	// An admin limit of 0 on a type exempts it entirely
+	if perTemplate == 0 {
+		return 0, 0
+	}
+
+	perUser = s.config.RateLimit.PerUserHour
+	if limit, ok := limits[allEmailsLimit]; ok {
+		perUser = limit
+	}
+	return perUser, perTemplate
+}
+
+func (s *EmailQuotaService) isExempt(emailType string) bool {
+	for _, exempt := range s.config.RateLimit.ExemptTypes {
+		if exempt == emailType {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *EmailQuotaService) adminLimits(ctx context.Context) map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	refresh := time.Duration(s.config.RateLimit.LimitsRefreshSeconds) * time.Second
+	if !s.limitsFetched.IsZero() && time.Now().Sub(s.limitsFetched) < refresh {
+		return s.limits
+	}
+
+	limits, err := s.store.Limits(ctx)
+	if err != nil {
+		log.Warn().Err(err).Msg("[EmailQuotaService-adminLimits] Failed to read email rate limits, keeping the previous ones")
+	} else {
+		s.limits = limits
+	}
+	// Retried after the refresh interval either way, so Redis being down does not add a round trip per email
+	s.limitsFetched = time.Now()
+	return s.limits
+}
@@ -2,44 +2,113 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/textproto"
 	"notification-service/config"
 	"notification-service/internal/core/port"
+	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/sony/gobreaker"
 	gomail "gopkg.in/gomail.v2"
 )
 
 type EmailService struct {
-	config *config.Config
+	config  *config.Config
+	breaker *gobreaker.CircuitBreaker
 }
 
 func NewEmailService(cfg *config.Config) port.EmailServiceInterface {
+	threshold := uint32(cfg.CircuitBreaker.FailureThreshold)
+
 	return &EmailService{
 		config: cfg,
+		breaker: gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:        "smtp",
+			MaxRequests: 1,
+			Timeout:     time.Duration(cfg.CircuitBreaker.OpenSeconds) * time.Second,
+			ReadyToTrip: func(counts gobreaker.Counts) bool {
+				return counts.ConsecutiveFailures >= threshold
+			},
+			OnStateChange: func(name string, from, to gobreaker.State) {
+				log.Warn().Str("breaker", name).Str("from", from.String()).Str("to", to.String()).Msg("[EmailService] SMTP circuit breaker state changed")
+			},
+		}),
 	}
 }
 
-func (s *EmailService) SendEmail(ctx context.Context, to, subject, body string) error {
+func (s *EmailService) SendEmail(ctx context.Context, email port.Email) error {
 	m := gomail.NewMessage()
 
-	// Set email headers - use a proper from address for Mailtrap
-	fromAddress := "noreply@mailtrap.io" // Use a standard Mailtrap from address
-	m.SetHeader("From", fromAddress)
-	m.SetHeader("To", to)
-	m.SetHeader("Subject", subject)
+	// The sender comes from the tenant's branding
+	m.SetAddressHeader("From", email.FromAddress, email.FromName)
+	m.SetHeader("To", email.To)
+	m.SetHeader("Subject", email.Subject)
+
+	// Plain text first, so clients that cannot show HTML still read the message
+	m.SetBody("text/plain", email.TextBody)
+	if email.HTMLBody != "" {
+		m.AddAlternative("text/html", email.HTMLBody)
+	}
 
-	// Set email body
-	m.SetBody("text/html", body)
+	// Attachments turn the message into multipart/mixed
+	for _, attachment := range email.Attachments {
+		content := attachment.Content
+		m.Attach(attachment.Filename,
+			gomail.SetCopyFunc(func(w io.Writer) error {
+				_, err := w.Write(content)
+				return err
+			}),
+			gomail.SetHeader(map[string][]string{"Content-Type": {attachment.ContentType}}),
+		)
+	}
 
 	// Create SMTP dialer
 	d := gomail.NewDialer(s.config.SMTP.Host, s.config.SMTP.Port, s.config.SMTP.User, s.config.SMTP.Password)
 
-	// Send email
-	if err := d.DialAndSend(m); err != nil {
-		log.Error().Err(err).Str("to", to).Str("subject", subject).Msg("[EmailService-SendEmail] Failed to send email")
+	// Send email; while SMTP keeps failing the breaker rejects without dialing
+	_, err := s.breaker.Execute(func() (interface{}, error) {
+		return nil, dialAndSend(d, email, m)
+	})
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+		log.Warn().Str("to", email.To).Str("subject", email.Subject).Msg("[EmailService-SendEmail] SMTP circuit breaker is open, email not sent")
+		return port.ErrEmailUnavailable
+	}
+	if err != nil {
+		log.Error().Err(err).Str("to", email.To).Str("subject", email.Subject).Msg("[EmailService-SendEmail] Failed to send email")
+		if isPermanentSMTPError(err) {
+			return fmt.Errorf("%w: %w", port.ErrEmailRejected, err)
+		}
 		return err
 	}
 
-	log.Info().Str("to", to).Str("subject", subject).Msg("[EmailService-SendEmail] Email sent successfully")
+	log.Info().Str("to", email.To).Str("subject", email.Subject).Str("from", email.FromAddress).Int("attachments", len(email.Attachments)).Msg("[EmailService-SendEmail] Email sent successfully")
 	return nil
 }
+
+// dialAndSend is gomail's DialAndSend without gomail.Send, which flattens the SMTP reply into
+// a string; the reply code decides whether the email is worth sending again
+func dialAndSend(d *gomail.Dialer, email port.Email, m *gomail.Message) error {
+	sender, err := d.Dial()
+	if err != nil {
+		return err
+	}
+	defer sender.Close()
+	return sender.Send(email.FromAddress, []string{email.To}, m)
+}
+
+// isPermanentSMTPError is a 5xx reply about the message or its recipient. Authentication
+// failures (530, 534, 535) are a configuration problem and succeed once it is fixed.
+func isPermanentSMTPError(err error) bool {
+	var protoErr *textproto.Error
+	if !errors.As(err, &protoErr) {
+		return false
+	}
+	switch protoErr.Code {
+	case 530, 534, 535:
+		return false
+	}
+	return protoErr.Code >= 500 && protoErr.Code < 600
+}
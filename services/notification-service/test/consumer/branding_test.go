@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"notification-service/config"
+	"notification-service/internal/adapter/consumer"
+	"notification-service/internal/core/port"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tenantBranding knows one tenant, "acme"
+type tenantBranding struct{}
+
+func (tenantBranding) ForTenant(tenant string) port.Brand {
+	if tenant == "acme" {
+		return port.Brand{Name: "Acme Fresh", SenderName: "Acme", SenderAddress: "hello@acme.id"}
+	}
+	return port.Brand{Name: "Jualan Sayur", SenderName: "Jualan Sayur", SenderAddress: "noreply@jualan-sayur.id"}
+}
+
+// recordingRenderer renders every template and keeps the data and brand it was given
+type recordingRenderer struct {
+	mu     sync.Mutex
+	data   map[string]string
+	brands []port.Brand
+}
+
+func (r *recordingRenderer) Render(lang, emailType string, data map[string]string) (*port.RenderedEmail, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.data = data
+	return &port.RenderedEmail{Language: lang, Version: "3f2a9c1b2d4e", Subject: "Selamat datang", Body: "Salam, " + data["brand_name"]}, nil
+}
+
+func (r *recordingRenderer) Layout(brand port.Brand, subject, body, image string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.brands = append(r.brands, brand)
+	return "<p>" + brand.Name + ": " + body + "</p>", nil
+}
+
+func (r *recordingRenderer) Watch(ctx context.Context) {}
+
+func startBrandingConsumer(t *testing.T, emailService port.EmailServiceInterface, renderer port.EmailRendererInterface, channel *quotaChannel) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	cfg := &config.Config{Email: config.Email{DefaultLanguage: "id"}}
+	c := consumer.NewEmailConsumer(cfg, emailService, &blockingAttachments{}, tenantBranding{}, renderer, nil, channel)
+	require.NoError(t, c.StartConsuming(ctx))
+}
+
+func TestBranding_TenantEmailUsesTenantSenderAndLayout(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &quotaChannel{fakeEventChannel{deliveries: make(chan amqp.Delivery, 1)}}
+	emailService := &recordingEmailService{}
+	renderer := &recordingRenderer{}
+	startBrandingConsumer(t, emailService, renderer, channel)
+
+	channel.deliveries <- delivery(ack, 1, `{"email":"budi@example.com","type":"welcome","language":"id","data":{"name":"Budi"},"tenant":"acme"}`)
+
+	assert.Eventually(t, func() bool { return emailService.count() == 1 }, time.Second, 10*time.Millisecond)
+
+	sent := emailService.sent[0]
+	assert.Equal(t, "Acme", sent.FromName)
+	assert.Equal(t, "hello@acme.id", sent.FromAddress)
+	assert.Equal(t, "Salam, Acme Fresh", sent.TextBody, "templates sign off with the tenant's brand name")
+	assert.Equal(t, "<p>Acme Fresh: Salam, Acme Fresh</p>", sent.HTMLBody)
+
+	renderer.mu.Lock()
+	defer renderer.mu.Unlock()
+	assert.Equal(t, "Budi", renderer.data["name"])
+	assert.Equal(t, "Acme Fresh", renderer.data["brand_name"])
+}
+
+func TestBranding_PreRenderedEmailWithoutTenantUsesDefaultBrand(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &quotaChannel{fakeEventChannel{deliveries: make(chan amqp.Delivery, 1)}}
+	emailService := &recordingEmailService{}
+	startBrandingConsumer(t, emailService, &recordingRenderer{}, channel)
+
+	channel.deliveries <- delivery(ack, 1, plainMessage)
+
+	assert.Eventually(t, func() bool { return emailService.count() == 1 }, time.Second, 10*time.Millisecond)
+
+	sent := emailService.sent[0]
+	assert.Equal(t, "noreply@jualan-sayur.id", sent.FromAddress)
+	assert.Equal(t, "Verifikasi", sent.Subject, "without data the publisher's subject is sent")
+	assert.Equal(t, "<p>Jualan Sayur: Halo</p>", sent.HTMLBody)
+}
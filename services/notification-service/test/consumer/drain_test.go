@@ -0,0 +1,181 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"notification-service/config"
+	"notification-service/internal/adapter/consumer"
+	"notification-service/internal/core/port"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// settlement is what the consumer did with one delivery
+type settlement struct {
+	tag     uint64
+	acked   bool
+	requeue bool
+}
+
+type fakeAcknowledger struct {
+	mu      sync.Mutex
+	settled []settlement
+}
+
+func (a *fakeAcknowledger) Ack(tag uint64, multiple bool) error {
+	a.record(settlement{tag: tag, acked: true})
+	return nil
+}
+
+func (a *fakeAcknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	a.record(settlement{tag: tag, requeue: requeue})
+	return nil
+}
+
+func (a *fakeAcknowledger) Reject(tag uint64, requeue bool) error {
+	a.record(settlement{tag: tag, requeue: requeue})
+	return nil
+}
+
+func (a *fakeAcknowledger) record(s settlement) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.settled = append(a.settled, s)
+}
+
+func (a *fakeAcknowledger) settlements() []settlement {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]settlement(nil), a.settled...)
+}
+
+type fakeChannel struct {
+	deliveries chan amqp.Delivery
+	cancelled  chan string
+}
+
+func (ch *fakeChannel) QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+	return amqp.Queue{Name: name}, nil
+}
+
+func (ch *fakeChannel) Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+	return ch.deliveries, nil
+}
+
+func (ch *fakeChannel) Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	return nil
+}
+
+func (ch *fakeChannel) Cancel(consumer string, noWait bool) error {
+	ch.cancelled <- consumer
+	return nil
+}
+
+// blockingEmailService holds every send until release is closed
+type blockingEmailService struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingEmailService) SendEmail(ctx context.Context, email port.Email) error {
+	s.started <- struct{}{}
+	<-s.release
+	return nil
+}
+
+// blockingAttachments holds every download until its context is cancelled
+type blockingAttachments struct {
+	started chan struct{}
+}
+
+func (a *blockingAttachments) Load(ctx context.Context, sources []port.AttachmentSource) ([]port.Attachment, error) {
+	if len(sources) == 0 {
+		return nil, nil
+	}
+	a.started <- struct{}{}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+type plainBranding struct{}
+
+func (plainBranding) ForTenant(tenant string) port.Brand {
+	return port.Brand{Name: "Jualan Sayur", SenderAddress: "noreply@example.com"}
+}
+
+type plainRenderer struct{}
+
+func (plainRenderer) Render(lang, emailType string, data map[string]string) (*port.RenderedEmail, error) {
+	return nil, port.ErrTemplateNotFound
+}
+
+func (plainRenderer) Layout(brand port.Brand, subject, body, image string) (string, error) {
+	return "<p>" + body + "</p>", nil
+}
+
+func (plainRenderer) Watch(ctx context.Context) {}
+
+func delivery(ack amqp.Acknowledger, tag uint64, body string) amqp.Delivery {
+	return amqp.Delivery{Acknowledger: ack, DeliveryTag: tag, Body: []byte(body)}
+}
+
+const plainMessage = `{"email":"budi@example.com","type":"verification","subject":"Verifikasi","body":"Halo"}`
+
+func TestDrain_FinishesInFlightAndRequeuesBuffered(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &fakeChannel{deliveries: make(chan amqp.Delivery, 3), cancelled: make(chan string, 1)}
+	emailService := &blockingEmailService{started: make(chan struct{}, 1), release: make(chan struct{})}
+	c := consumer.NewEmailConsumer(&config.Config{}, emailService, &blockingAttachments{}, plainBranding{}, plainRenderer{}, nil, channel)
+
+	channel.deliveries <- delivery(ack, 1, plainMessage)
+	channel.deliveries <- delivery(ack, 2, plainMessage)
+	channel.deliveries <- delivery(ack, 3, plainMessage)
+	require.NoError(t, c.StartConsuming(context.Background()))
+	<-emailService.started
+
+	drained := make(chan error, 1)
+	go func() { drained <- c.Drain(time.Second) }()
+
+	// The send in flight is not cut short by the drain
+	assert.Equal(t, "notification-service-email", <-channel.cancelled)
+	close(emailService.release)
+	require.NoError(t, <-drained)
+
+	assert.ElementsMatch(t, []settlement{
+		{tag: 1, acked: true},
+		{tag: 2, requeue: true},
+		{tag: 3, requeue: true},
+	}, ack.settlements())
+}
+
+func TestDrain_CancelsInFlightAfterGrace(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &fakeChannel{deliveries: make(chan amqp.Delivery, 1), cancelled: make(chan string, 1)}
+	attachments := &blockingAttachments{started: make(chan struct{}, 1)}
+	c := consumer.NewEmailConsumer(&config.Config{}, &blockingEmailService{}, attachments, plainBranding{}, plainRenderer{}, nil, channel)
+
+	channel.deliveries <- delivery(ack, 1, `{"email":"budi@example.com","type":"invoice","body":"Halo","attachments":[{"filename":"invoice.pdf","url":"https://storage.example.com/invoice.pdf"}]}`)
+	require.NoError(t, c.StartConsuming(context.Background()))
+	<-attachments.started
+
+	err := c.Drain(50 * time.Millisecond)
+
+	assert.ErrorIs(t, err, consumer.ErrDrainTimeout)
+	assert.Eventually(t, func() bool {
+		settled := ack.settlements()
+		return len(settled) == 1 && settled[0] == settlement{tag: 1, requeue: true}
+	}, time.Second, 10*time.Millisecond, "the cancelled message goes back to the queue")
+}
+
+func TestDrain_IdleConsumer(t *testing.T) {
+	channel := &fakeChannel{deliveries: make(chan amqp.Delivery), cancelled: make(chan string, 1)}
+	c := consumer.NewEmailConsumer(&config.Config{}, &blockingEmailService{}, &blockingAttachments{}, plainBranding{}, plainRenderer{}, nil, channel)
+	require.NoError(t, c.StartConsuming(context.Background()))
+
+	assert.NoError(t, c.Drain(time.Second))
+}
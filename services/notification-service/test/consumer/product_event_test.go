@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"notification-service/config"
+	"notification-service/internal/adapter/consumer"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const backInStockEvent = `{"event_id":"product.back_in_stock:bayam:r1:0","event":"product.back_in_stock","data":{"product_id":"bayam","product_name":"Bayam Hijau","link":"https://sayur.example.com/p/bayam","restock_id":"r1","subscribers":[
+	{"user_id":1,"email":"sari@example.com","name":"Sari","language":"id"},
+	{"user_id":2,"email":"budi@example.com","name":"Budi","language":"en"},
+	{"user_id":1,"email":"sari@example.com","name":"Sari","language":"id"}]}}`
+
+func startProductEvents(t *testing.T, channel *fakeEventChannel) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	require.NoError(t, consumer.NewProductEventConsumer(&config.Config{}, channel).StartConsuming(ctx))
+}
+
+func TestProductEvents_BackInStockEmailsEachSubscriberOnce(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &fakeEventChannel{deliveries: make(chan amqp.Delivery, 2)}
+	startProductEvents(t, channel)
+
+	channel.deliveries <- delivery(ack, 1, backInStockEvent)
+	channel.deliveries <- delivery(ack, 2, backInStockEvent)
+
+	assert.Eventually(t, func() bool { return len(ack.settlements()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"product_events/product.back_in_stock"}, channel.bindings)
+	assert.Equal(t, []settlement{{tag: 1, acked: true}, {tag: 2, acked: true}}, ack.settlements())
+
+	emails := channel.emails(t)
+	require.Len(t, emails, 2)
+	assert.Equal(t, "sari@example.com", emails[0].Email)
+	assert.Equal(t, "id", emails[0].Language)
+	assert.Equal(t, "budi@example.com", emails[1].Email)
+	assert.Equal(t, consumer.BackInStockEmailType, emails[1].Type)
+	assert.Equal(t, map[string]string{"name": "Budi", "product_name": "Bayam Hijau", "link": "https://sayur.example.com/p/bayam"}, emails[1].Data)
+}
+
+func TestProductEvents_RetryOnlyEmailsTheRest(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &failingAfterChannel{fakeEventChannel: fakeEventChannel{deliveries: make(chan amqp.Delivery, 2)}, failAfter: 1}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	require.NoError(t, consumer.NewProductEventConsumer(&config.Config{}, channel).StartConsuming(ctx))
+
+	channel.deliveries <- delivery(ack, 1, backInStockEvent)
+	assert.Eventually(t, func() bool { return len(ack.settlements()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, settlement{tag: 1, requeue: true}, ack.settlements()[0])
+
+	// RabbitMQ redelivers once the email queue is back
+	channel.mu.Lock()
+	channel.failAfter = -1
+	channel.mu.Unlock()
+	channel.deliveries <- delivery(ack, 2, backInStockEvent)
+	assert.Eventually(t, func() bool { return len(ack.settlements()) == 2 }, time.Second, 10*time.Millisecond)
+
+	emails := channel.emails(t)
+	require.Len(t, emails, 2)
+	assert.Equal(t, "sari@example.com", emails[0].Email)
+	assert.Equal(t, "budi@example.com", emails[1].Email)
+}
+
+// failingAfterChannel fails every publish after the first failAfter; -1 never fails
+type failingAfterChannel struct {
+	fakeEventChannel
+	failAfter int
+}
+
+func (ch *failingAfterChannel) Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	ch.mu.Lock()
+	failing := ch.failAfter >= 0 && len(ch.published) >= ch.failAfter
+	ch.mu.Unlock()
+	if failing {
+		return errors.New("channel closed")
+	}
+	return ch.fakeEventChannel.Publish(exchange, key, mandatory, immediate, msg)
+}
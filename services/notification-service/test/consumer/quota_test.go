@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"notification-service/config"
+	"notification-service/internal/adapter/consumer"
+	"notification-service/internal/core/port"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// quotaChannel is the email consumer's channel, recording what it publishes
+type quotaChannel struct {
+	fakeEventChannel
+}
+
+func (ch *quotaChannel) Cancel(consumer string, noWait bool) error {
+	return nil
+}
+
+func (ch *quotaChannel) publishedTo(queue string) []amqp.Publishing {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	var msgs []amqp.Publishing
+	for _, p := range ch.published {
+		if p.key == queue {
+			msgs = append(msgs, p.msg)
+		}
+	}
+	return msgs
+}
+
+// fakeQuota answers every Reserve with reservation or err and records releases
+type fakeQuota struct {
+	reservation *port.QuotaReservation
+	err         error
+
+	mu       sync.Mutex
+	released []*port.QuotaReservation
+}
+
+func (q *fakeQuota) Reserve(ctx context.Context, recipient, emailType string) (*port.QuotaReservation, error) {
+	return q.reservation, q.err
+}
+
+func (q *fakeQuota) Release(ctx context.Context, reservation *port.QuotaReservation) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.released = append(q.released, reservation)
+}
+
+type recordingEmailService struct {
+	err error
+
+	mu   sync.Mutex
+	sent []port.Email
+}
+
+func (s *recordingEmailService) SendEmail(ctx context.Context, email port.Email) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sent = append(s.sent, email)
+	return s.err
+}
+
+func (s *recordingEmailService) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sent)
+}
+
+func startQuotaConsumer(t *testing.T, emailService port.EmailServiceInterface, quota port.EmailQuotaServiceInterface, channel *quotaChannel) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	c := consumer.NewEmailConsumer(&config.Config{}, emailService, &blockingAttachments{}, plainBranding{}, plainRenderer{}, quota, channel)
+	require.NoError(t, c.StartConsuming(ctx))
+}
+
+func TestEmailQuota_OverQuotaIsDeferredUntilRetryAt(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &quotaChannel{fakeEventChannel{deliveries: make(chan amqp.Delivery, 1)}}
+	emailService := &recordingEmailService{}
+	retryAt := time.Now().Add(10 * time.Minute)
+	startQuotaConsumer(t, emailService, &fakeQuota{reservation: &port.QuotaReservation{Allowed: false, RetryAt: retryAt}}, channel)
+
+	channel.deliveries <- delivery(ack, 1, plainMessage)
+
+	assert.Eventually(t, func() bool { return len(ack.settlements()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, settlement{tag: 1, acked: true}, ack.settlements()[0])
+	assert.Zero(t, emailService.count(), "an email over quota is not sent now")
+
+	deferred := channel.publishedTo(consumer.DeferredQueue)
+	require.Len(t, deferred, 1)
+	assert.Equal(t, plainMessage, string(deferred[0].Body))
+	assert.Equal(t, retryAt.UTC().Format(time.RFC3339), deferred[0].Headers[consumer.DeferredUntilHeader])
+
+	expiration, err := strconv.ParseInt(deferred[0].Expiration, 10, 64)
+	require.NoError(t, err)
+	assert.InDelta(t, (10 * time.Minute).Milliseconds(), expiration, float64(time.Second.Milliseconds()))
+}
+
+func TestEmailQuota_SendsAnywayWhenQuotaIsUnavailable(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &quotaChannel{fakeEventChannel{deliveries: make(chan amqp.Delivery, 1)}}
+	emailService := &recordingEmailService{}
+	startQuotaConsumer(t, emailService, &fakeQuota{err: errors.New("redis: connection refused")}, channel)
+
+	channel.deliveries <- delivery(ack, 1, plainMessage)
+
+	assert.Eventually(t, func() bool { return len(ack.settlements()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, settlement{tag: 1, acked: true}, ack.settlements()[0])
+	assert.Equal(t, 1, emailService.count())
+}
+
+func TestEmailQuota_SendsAnywayWhenDeferFails(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &quotaChannel{fakeEventChannel{deliveries: make(chan amqp.Delivery, 1), publishErr: errors.New("channel closed")}}
+	emailService := &recordingEmailService{}
+	startQuotaConsumer(t, emailService, &fakeQuota{reservation: &port.QuotaReservation{Allowed: false, RetryAt: time.Now().Add(time.Minute)}}, channel)
+
+	channel.deliveries <- delivery(ack, 1, plainMessage)
+
+	assert.Eventually(t, func() bool { return len(ack.settlements()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, settlement{tag: 1, acked: true}, ack.settlements()[0])
+	assert.Equal(t, 1, emailService.count())
+}
+
+func TestEmailQuota_FailedSendReleasesQuota(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &quotaChannel{fakeEventChannel{deliveries: make(chan amqp.Delivery, 1)}}
+	reservation := &port.QuotaReservation{Allowed: true, Keys: []string{"email_quota:user:budi@example.com:0"}}
+	quota := &fakeQuota{reservation: reservation}
+	startQuotaConsumer(t, &recordingEmailService{err: errors.New("421 try again later")}, quota, channel)
+
+	channel.deliveries <- delivery(ack, 1, plainMessage)
+
+	assert.Eventually(t, func() bool { return len(ack.settlements()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, settlement{tag: 1, acked: true}, ack.settlements()[0])
+	assert.Len(t, channel.publishedTo(consumer.RetryQueue), 1)
+
+	quota.mu.Lock()
+	defer quota.mu.Unlock()
+	assert.Equal(t, []*port.QuotaReservation{reservation}, quota.released, "the retry is counted again")
+}
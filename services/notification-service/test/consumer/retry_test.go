@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"notification-service/config"
+	"notification-service/internal/adapter/consumer"
+	"notification-service/internal/core/port"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startRetryConsumer(t *testing.T, emailService port.EmailServiceInterface, channel *quotaChannel) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	cfg := &config.Config{Email: config.Email{MaxSendAttempts: 3, RetryDelaySeconds: 30}}
+	c := consumer.NewEmailConsumer(cfg, emailService, &blockingAttachments{}, plainBranding{}, plainRenderer{}, nil, channel)
+	require.NoError(t, c.StartConsuming(ctx))
+}
+
+func retriedDelivery(ack amqp.Acknowledger, retries int32) amqp.Delivery {
+	d := delivery(ack, 1, plainMessage)
+	d.Headers = amqp.Table{consumer.RetryCountHeader: retries}
+	return d
+}
+
+func TestEmailRetry_FailedSendWaitsInRetryQueue(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &quotaChannel{fakeEventChannel{deliveries: make(chan amqp.Delivery, 1)}}
+	startRetryConsumer(t, &recordingEmailService{err: &textproto.Error{Code: 421, Msg: "try again later"}}, channel)
+
+	channel.deliveries <- retriedDelivery(ack, 1)
+
+	assert.Eventually(t, func() bool { return len(ack.settlements()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, settlement{tag: 1, acked: true}, ack.settlements()[0])
+
+	retried := channel.publishedTo(consumer.RetryQueue)
+	require.Len(t, retried, 1)
+	assert.Equal(t, plainMessage, string(retried[0].Body))
+	assert.Equal(t, int32(2), retried[0].Headers[consumer.RetryCountHeader])
+	assert.Equal(t, "30000", retried[0].Expiration)
+	assert.Empty(t, channel.publishedTo(consumer.DeadLetterQueue))
+}
+
+func TestEmailRetry_DeadLettersAfterMaxAttempts(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &quotaChannel{fakeEventChannel{deliveries: make(chan amqp.Delivery, 1)}}
+	startRetryConsumer(t, &recordingEmailService{err: errors.New("dial tcp: connection refused")}, channel)
+
+	channel.deliveries <- retriedDelivery(ack, 2)
+
+	assert.Eventually(t, func() bool { return len(ack.settlements()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, settlement{tag: 1, requeue: false}, ack.settlements()[0])
+	assert.Empty(t, channel.publishedTo(consumer.RetryQueue))
+
+	dead := channel.publishedTo(consumer.DeadLetterQueue)
+	require.Len(t, dead, 1)
+	assert.Equal(t, "failed after 3 attempts: dial tcp: connection refused", dead[0].Headers[consumer.FailureReasonHeader])
+}
+
+func TestEmailRetry_PermanentRejectionIsDeadLetteredRightAway(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &quotaChannel{fakeEventChannel{deliveries: make(chan amqp.Delivery, 1)}}
+	rejected := fmt.Errorf("%w: %w", port.ErrEmailRejected, &textproto.Error{Code: 550, Msg: "mailbox unavailable"})
+	startRetryConsumer(t, &recordingEmailService{err: rejected}, channel)
+
+	channel.deliveries <- delivery(ack, 1, plainMessage)
+
+	assert.Eventually(t, func() bool { return len(ack.settlements()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, settlement{tag: 1, requeue: false}, ack.settlements()[0])
+	assert.Empty(t, channel.publishedTo(consumer.RetryQueue))
+	assert.Len(t, channel.publishedTo(consumer.DeadLetterQueue), 1)
+}
+
+func TestEmailRetry_RequeuesWhenRetryQueueIsUnavailable(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &quotaChannel{fakeEventChannel{deliveries: make(chan amqp.Delivery, 1), publishErr: errors.New("channel closed")}}
+	startRetryConsumer(t, &recordingEmailService{err: errors.New("421 try again later")}, channel)
+
+	channel.deliveries <- delivery(ack, 1, plainMessage)
+
+	assert.Eventually(t, func() bool { return len(ack.settlements()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, settlement{tag: 1, requeue: true}, ack.settlements()[0])
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"notification-service/config"
+	"notification-service/internal/adapter/consumer"
+	"notification-service/internal/adapter/templates"
+	"notification-service/internal/core/port"
+
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type published struct {
+	key string
+	msg amqp.Publishing
+}
+
+type fakeEventChannel struct {
+	deliveries chan amqp.Delivery
+	bindings   []string
+	publishErr error
+
+	mu        sync.Mutex
+	published []published
+}
+
+func (ch *fakeEventChannel) ExchangeDeclare(name, kind string, durable, autoDelete, internal, noWait bool, args amqp.Table) error {
+	return nil
+}
+
+func (ch *fakeEventChannel) QueueDeclare(name string, durable, autoDelete, exclusive, noWait bool, args amqp.Table) (amqp.Queue, error) {
+	return amqp.Queue{Name: name}, nil
+}
+
+func (ch *fakeEventChannel) QueueBind(name, key, exchange string, noWait bool, args amqp.Table) error {
+	ch.bindings = append(ch.bindings, exchange+"/"+key)
+	return nil
+}
+
+func (ch *fakeEventChannel) Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args amqp.Table) (<-chan amqp.Delivery, error) {
+	return ch.deliveries, nil
+}
+
+func (ch *fakeEventChannel) Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	if ch.publishErr != nil {
+		return ch.publishErr
+	}
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.published = append(ch.published, published{key: key, msg: msg})
+	return nil
+}
+
+func (ch *fakeEventChannel) emails(t *testing.T) []consumer.EmailMessage {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	emails := make([]consumer.EmailMessage, 0, len(ch.published))
+	for _, p := range ch.published {
+		require.Equal(t, consumer.EmailQueue, p.key)
+		var emailMsg consumer.EmailMessage
+		require.NoError(t, json.Unmarshal(p.msg.Body, &emailMsg))
+		emails = append(emails, emailMsg)
+	}
+	return emails
+}
+
+const verifiedEvent = `{"event_id":"user.verified:7","event":"user.verified","occurred_at":"2026-01-02T03:04:05Z","data":{"user_id":7,"email":"sari@example.com","name":"Sari","language":"id"}}`
+
+func welcomeConfig() *config.Config {
+	return &config.Config{Welcome: config.Welcome{VoucherCode: "SAYURBARU", VoucherDiscount: "20%"}}
+}
+
+func startUserEvents(t *testing.T, cfg *config.Config, channel *fakeEventChannel) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	require.NoError(t, consumer.NewUserEventConsumer(cfg, channel).StartConsuming(ctx))
+}
+
+func TestUserEvents_VerifiedQueuesWelcomeEmail(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &fakeEventChannel{deliveries: make(chan amqp.Delivery, 2)}
+	startUserEvents(t, welcomeConfig(), channel)
+
+	channel.deliveries <- delivery(ack, 1, verifiedEvent)
+	// The publisher retried, so the same event arrives again
+	channel.deliveries <- delivery(ack, 2, verifiedEvent)
+
+	assert.Eventually(t, func() bool { return len(ack.settlements()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{"user_events/user.verified"}, channel.bindings)
+	assert.Equal(t, []settlement{{tag: 1, acked: true}, {tag: 2, acked: true}}, ack.settlements())
+
+	emails := channel.emails(t)
+	require.Len(t, emails, 1)
+	assert.Equal(t, "sari@example.com", emails[0].Email)
+	assert.Equal(t, consumer.WelcomeEmailType, emails[0].Type)
+	assert.Equal(t, "id", emails[0].Language)
+	assert.Equal(t, map[string]string{"name": "Sari", "voucher_code": "SAYURBARU", "voucher_discount": "20%"}, emails[0].Data)
+	assert.Contains(t, emails[0].Body, "SAYURBARU")
+}
+
+func TestUserEvents_RequeuesWhenEmailQueueUnavailable(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &fakeEventChannel{deliveries: make(chan amqp.Delivery, 1), publishErr: errors.New("channel closed")}
+	startUserEvents(t, welcomeConfig(), channel)
+
+	channel.deliveries <- delivery(ack, 1, verifiedEvent)
+
+	assert.Eventually(t, func() bool { return len(ack.settlements()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []settlement{{tag: 1, requeue: true}}, ack.settlements())
+}
+
+func TestUserEvents_DropsInvalidEvents(t *testing.T) {
+	ack := &fakeAcknowledger{}
+	channel := &fakeEventChannel{deliveries: make(chan amqp.Delivery, 2)}
+	startUserEvents(t, welcomeConfig(), channel)
+
+	channel.deliveries <- delivery(ack, 1, `not json`)
+	channel.deliveries <- delivery(ack, 2, `{"event_id":"user.verified:8","event":"user.verified","data":{"name":"Tanpa Email"}}`)
+
+	assert.Eventually(t, func() bool { return len(ack.settlements()) == 2 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []settlement{{tag: 1}, {tag: 2}}, ack.settlements())
+	assert.Empty(t, channel.emails(t))
+}
+
+type noAssets struct{}
+
+func (noAssets) URL(name string) (string, error) { return "", port.ErrAssetNotFound }
+
+func (noAssets) Watch(ctx context.Context) {}
+
+func TestWelcomeTemplate_VoucherIsOptional(t *testing.T) {
+	renderer, err := templates.NewRenderer(&config.Config{Email: config.Email{DefaultLanguage: "en", TemplateHistory: 1}}, noAssets{})
+	require.NoError(t, err)
+
+	for _, lang := range []string{"en", "id"} {
+		withVoucher, err := renderer.Render(lang, consumer.WelcomeEmailType, map[string]string{"name": "Sari", "voucher_code": "SAYURBARU", "voucher_discount": "20%", "brand_name": "Jualan Sayur"})
+		require.NoError(t, err)
+		assert.Equal(t, lang, withVoucher.Language)
+		assert.Contains(t, withVoucher.Body, "SAYURBARU")
+		assert.Contains(t, withVoucher.Body, "20%")
+
+		without, err := renderer.Render(lang, consumer.WelcomeEmailType, map[string]string{"name": "Sari", "voucher_code": "", "voucher_discount": "", "brand_name": "Jualan Sayur"})
+		require.NoError(t, err)
+		assert.NotContains(t, without.Body, "voucher")
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/textproto"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"notification-service/internal/adapter/metrics"
+	"notification-service/internal/core/port"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Every test uses its own template label, since the metrics live in the default registry
+
+func TestMetrics_MessageCounters(t *testing.T) {
+	metrics.MessageConsumed("counters")
+	metrics.MessageConsumed("counters")
+	metrics.MessageProcessed("counters")
+	metrics.MessageRequeued("counters", metrics.ReasonSMTPError)
+	metrics.MessageFailed("counters", metrics.ReasonRetriesExhausted)
+	metrics.MessageDeferred("counters")
+	metrics.MessageFailed("", metrics.ReasonInvalidMessage)
+
+	expected := `
+# HELP notification_messages_consumed_total Messages taken from email_queue.
+# TYPE notification_messages_consumed_total counter
+notification_messages_consumed_total{template="counters"} 2
+# HELP notification_messages_processed_total Messages whose email was sent and acknowledged.
+# TYPE notification_messages_processed_total counter
+notification_messages_processed_total{template="counters"} 1
+# HELP notification_messages_requeued_total Messages put back on email_queue to be retried, by reason.
+# TYPE notification_messages_requeued_total counter
+notification_messages_requeued_total{reason="smtp_error",template="counters"} 1
+# HELP notification_messages_failed_total Messages given up on and moved to the dead letter queue, by reason.
+# TYPE notification_messages_failed_total counter
+notification_messages_failed_total{reason="invalid_message",template="unknown"} 1
+notification_messages_failed_total{reason="retries_exhausted",template="counters"} 1
+# HELP notification_messages_deferred_total Messages over their recipient's hourly quota, moved to email_deferred until the next hour.
+# TYPE notification_messages_deferred_total counter
+notification_messages_deferred_total{template="counters"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(prometheus.DefaultGatherer, strings.NewReader(expected),
+		"notification_messages_consumed_total", "notification_messages_processed_total", "notification_messages_requeued_total",
+		"notification_messages_failed_total", "notification_messages_deferred_total"))
+}
+
+// timeoutError is a net.Error that timed out
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestMetrics_EmailSentClassifiesSMTPErrors(t *testing.T) {
+	start := time.Now()
+	for _, err := range []error{
+		nil,
+		fmt.Errorf("%w: breaker open", port.ErrEmailUnavailable),
+		&net.OpError{Op: "dial", Err: timeoutError{}},
+		&net.OpError{Op: "dial", Err: os.ErrNotExist},
+		&textproto.Error{Code: 535, Msg: "authentication failed"},
+		&textproto.Error{Code: 421, Msg: "try again later"},
+		fmt.Errorf("%w: %w", port.ErrEmailRejected, &textproto.Error{Code: 550, Msg: "mailbox unavailable"}),
+		errors.New("something else"),
+	} {
+		metrics.EmailSent("classified", start, err)
+	}
+
+	expected := `
+# HELP notification_smtp_errors_total Failed SMTP sends by error type: circuit_open, timeout, connection, auth, temporary, rejected or other.
+# TYPE notification_smtp_errors_total counter
+notification_smtp_errors_total{template="classified",type="auth"} 1
+notification_smtp_errors_total{template="classified",type="circuit_open"} 1
+notification_smtp_errors_total{template="classified",type="connection"} 1
+notification_smtp_errors_total{template="classified",type="other"} 1
+notification_smtp_errors_total{template="classified",type="rejected"} 1
+notification_smtp_errors_total{template="classified",type="temporary"} 1
+notification_smtp_errors_total{template="classified",type="timeout"} 1
+`
+	assert.NoError(t, testutil.GatherAndCompare(prometheus.DefaultGatherer, strings.NewReader(expected), "notification_smtp_errors_total"))
+
+	// The open breaker never reached SMTP, so only the other seven sends have a duration
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	var observed uint64
+	for _, family := range families {
+		if family.GetName() != "notification_email_send_duration_seconds" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "template" && label.GetValue() == "classified" {
+					observed = metric.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+	assert.Equal(t, uint64(7), observed)
+}
+
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return addr
+}
+
+func TestMetrics_ServeExposesMetricsUntilCancelled(t *testing.T) {
+	metrics.MessageProcessed("served")
+	addr := freeAddr(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		metrics.Serve(ctx, addr)
+		close(stopped)
+	}()
+
+	var body string
+	assert.Eventually(t, func() bool {
+		resp, err := http.Get("http://" + addr + "/metrics")
+		if err != nil {
+			return false
+		}
+		defer resp.Body.Close()
+		raw, err := io.ReadAll(resp.Body)
+		body = string(raw)
+		return err == nil && resp.StatusCode == http.StatusOK
+	}, 2*time.Second, 20*time.Millisecond)
+	assert.Contains(t, body, `notification_messages_processed_total{template="served"} 1`)
+
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("metrics listener did not stop")
+	}
+}
+
+func TestMetrics_ServeOffDoesNotListen(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		metrics.Serve(context.Background(), "off")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Serve with addr off should return right away")
+	}
+}
+
+func TestMetrics_ServeReturnsWhenAddressIsTaken(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	done := make(chan struct{})
+	go func() {
+		metrics.Serve(context.Background(), listener.Addr().String())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Serve should give up when it cannot listen")
+	}
+}
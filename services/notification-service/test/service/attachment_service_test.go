@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"notification-service/config"
+	"notification-service/internal/core/port"
+	"notification-service/internal/core/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func newAttachmentService(maxBytes, maxTotalBytes int, allowedHosts ...string) port.AttachmentServiceInterface {
+	return service.NewAttachmentService(&config.Config{Attachment: config.Attachment{
+		MaxBytes:               maxBytes,
+		MaxTotalBytes:          maxTotalBytes,
+		AllowedHosts:           allowedHosts,
+		DownloadTimeoutSeconds: 5,
+	}})
+}
+
+// newStorageServer starts an HTTPS server and makes the attachment client trust it. The
+// client uses http.DefaultTransport, which is swapped for the server's for the test.
+func newStorageServer(t *testing.T, handler http.Handler) (*httptest.Server, string) {
+	t.Helper()
+	server := httptest.NewTLSServer(handler)
+	t.Cleanup(server.Close)
+
+	transport := http.DefaultTransport
+	http.DefaultTransport = server.Client().Transport
+	t.Cleanup(func() { http.DefaultTransport = transport })
+
+	parsed, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	return server, parsed.Hostname()
+}
+
+func encoded(content []byte) string {
+	return base64.StdEncoding.EncodeToString(content)
+}
+
+func TestAttachmentLoad_DecodesBase64(t *testing.T) {
+	svc := newAttachmentService(64, 128)
+
+	attachments, err := svc.Load(context.Background(), []port.AttachmentSource{
+		{Filename: "invoice.pdf", Content: encoded([]byte("%PDF-1.4"))},
+	})
+	require.NoError(t, err)
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "invoice.pdf", attachments[0].Filename)
+	assert.Equal(t, []byte("%PDF-1.4"), attachments[0].Content)
+	assert.Equal(t, "application/pdf", attachments[0].ContentType)
+}
+
+func TestAttachmentLoad_RejectsInvalidSources(t *testing.T) {
+	svc := newAttachmentService(64, 128, "storage.test")
+
+	for name, source := range map[string]port.AttachmentSource{
+		"both content and url": {Filename: "a.txt", Content: encoded([]byte("a")), URL: "https://storage.test/a.txt"},
+		"neither":              {Filename: "a.txt"},
+		"bad base64":           {Filename: "a.txt", Content: "not base64!"},
+	} {
+		_, err := svc.Load(context.Background(), []port.AttachmentSource{source})
+		assert.ErrorIs(t, err, port.ErrInvalidAttachment, name)
+	}
+}
+
+func TestAttachmentLoad_MaxBytes(t *testing.T) {
+	svc := newAttachmentService(8, 128)
+
+	_, err := svc.Load(context.Background(), []port.AttachmentSource{{Filename: "a.bin", Content: encoded(make([]byte, 8))}})
+	assert.NoError(t, err, "exactly MaxBytes is allowed")
+
+	_, err = svc.Load(context.Background(), []port.AttachmentSource{{Filename: "a.bin", Content: encoded(make([]byte, 9))}})
+	assert.ErrorIs(t, err, port.ErrInvalidAttachment)
+	assert.ErrorContains(t, err, "larger than 8 bytes")
+}
+
+func TestAttachmentLoad_Base64SizeCheckedBeforeDecoding(t *testing.T) {
+	svc := newAttachmentService(8, 128)
+
+	// Invalid base64 would fail to decode; the size check rejects it first
+	_, err := svc.Load(context.Background(), []port.AttachmentSource{{Filename: "a.bin", Content: strings.Repeat("!", 1024)}})
+	assert.ErrorIs(t, err, port.ErrInvalidAttachment)
+	assert.ErrorContains(t, err, "larger than 8 bytes")
+}
+
+func TestAttachmentLoad_MaxTotalBytes(t *testing.T) {
+	svc := newAttachmentService(8, 12)
+
+	_, err := svc.Load(context.Background(), []port.AttachmentSource{
+		{Filename: "a.bin", Content: encoded(make([]byte, 6))},
+		{Filename: "b.bin", Content: encoded(make([]byte, 6))},
+	})
+	assert.NoError(t, err)
+
+	_, err = svc.Load(context.Background(), []port.AttachmentSource{
+		{Filename: "a.bin", Content: encoded(make([]byte, 8))},
+		{Filename: "b.bin", Content: encoded(make([]byte, 8))},
+	})
+	assert.ErrorIs(t, err, port.ErrInvalidAttachment)
+	assert.ErrorContains(t, err, "exceed 12 bytes in total")
+}
+
+func TestAttachmentLoad_SanitizesFilename(t *testing.T) {
+	svc := newAttachmentService(64, 256)
+
+	for raw, want := range map[string]string{
+		"../../etc/passwd.txt":        "passwd.txt",
+		`C:\Users\budi\nota.txt`:      "nota.txt",
+		"in\"voice\r\nBcc: x@y.z.txt": "invoiceBcc: x@y.z.txt",
+		"":                            "attachment",
+		"/":                           "attachment",
+	} {
+		attachments, err := svc.Load(context.Background(), []port.AttachmentSource{{Filename: raw, Content: encoded([]byte("a"))}})
+		require.NoError(t, err, raw)
+		assert.Equal(t, want, attachments[0].Filename, raw)
+	}
+}
+
+func TestAttachmentLoad_ContentType(t *testing.T) {
+	svc := newAttachmentService(64, 256)
+
+	attachments, err := svc.Load(context.Background(), []port.AttachmentSource{
+		{Filename: "nota.pdf", ContentType: "text/plain; charset=utf-8", Content: encoded([]byte("a"))},
+		{Filename: "nota.pdf", ContentType: "not a type;;", Content: encoded([]byte("a"))},
+		{Filename: "photo", Content: encoded(pngHeader)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "text/plain", attachments[0].ContentType, "a well-formed declared type wins, without parameters")
+	assert.Equal(t, "application/pdf", attachments[1].ContentType, "a malformed declared type falls back to the extension")
+	assert.Equal(t, "image/png", attachments[2].ContentType, "without a type or extension the bytes decide")
+}
+
+func TestAttachmentLoad_DownloadsFromAllowedHost(t *testing.T) {
+	server, host := newStorageServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(pngHeader)
+	}))
+	svc := newAttachmentService(64, 128, host)
+
+	attachments, err := svc.Load(context.Background(), []port.AttachmentSource{{Filename: "bayam.png", URL: server.URL + "/products/bayam.png"}})
+	require.NoError(t, err)
+	assert.Equal(t, pngHeader, attachments[0].Content)
+	assert.Equal(t, "image/png", attachments[0].ContentType)
+}
+
+func TestAttachmentLoad_OnlyHTTPSOnAllowedHosts(t *testing.T) {
+	var hits atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte("a"))
+	})
+	tlsServer, host := newStorageServer(t, handler)
+	plainServer := httptest.NewServer(handler)
+	defer plainServer.Close()
+
+	svc := newAttachmentService(64, 128, "storage.test")
+	_, err := svc.Load(context.Background(), []port.AttachmentSource{{Filename: "a.txt", URL: tlsServer.URL + "/a.txt"}})
+	assert.ErrorIs(t, err, port.ErrInvalidAttachment, "host not in the allowlist")
+
+	svc = newAttachmentService(64, 128, host)
+	_, err = svc.Load(context.Background(), []port.AttachmentSource{{Filename: "a.txt", URL: plainServer.URL + "/a.txt"}})
+	assert.ErrorIs(t, err, port.ErrInvalidAttachment, "plain http is refused")
+
+	assert.Zero(t, hits.Load(), "nothing is requested from a refused url")
+}
+
+func TestAttachmentLoad_RefusesRedirects(t *testing.T) {
+	var followed atomic.Bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a.txt", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/elsewhere", http.StatusFound)
+	})
+	mux.HandleFunc("/elsewhere", func(w http.ResponseWriter, r *http.Request) {
+		followed.Store(true)
+		_, _ = w.Write([]byte("a"))
+	})
+	server, host := newStorageServer(t, mux)
+	svc := newAttachmentService(64, 128, host)
+
+	_, err := svc.Load(context.Background(), []port.AttachmentSource{{Filename: "a.txt", URL: server.URL + "/a.txt"}})
+	assert.Error(t, err)
+	assert.False(t, followed.Load(), "the redirect is not followed")
+}
+
+func TestAttachmentLoad_DownloadStatus(t *testing.T) {
+	server, host := newStorageServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	svc := newAttachmentService(64, 128, host)
+
+	_, err := svc.Load(context.Background(), []port.AttachmentSource{{Filename: "a.txt", URL: server.URL + "/missing.txt"}})
+	assert.ErrorIs(t, err, port.ErrInvalidAttachment, "a 4xx will not change by retrying")
+
+	_, err = svc.Load(context.Background(), []port.AttachmentSource{{Filename: "a.txt", URL: server.URL + "/a.txt"}})
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, port.ErrInvalidAttachment, "a 5xx is worth retrying")
+}
+
+func TestAttachmentLoad_DownloadMaxBytes(t *testing.T) {
+	server, host := newStorageServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/streamed.bin" {
+			// Flushing first sends the body chunked, without a Content-Length
+			w.(http.Flusher).Flush()
+		}
+		_, _ = w.Write(make([]byte, 9))
+	}))
+	svc := newAttachmentService(8, 128, host)
+
+	for _, file := range []string{"/sized.bin", "/streamed.bin"} {
+		_, err := svc.Load(context.Background(), []port.AttachmentSource{{Filename: "a.bin", URL: server.URL + file}})
+		assert.ErrorIs(t, err, port.ErrInvalidAttachment, file)
+		assert.ErrorContains(t, err, "larger than 8 bytes", file)
+	}
+}
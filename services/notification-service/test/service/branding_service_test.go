@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"notification-service/config"
+	"notification-service/internal/adapter/templates"
+	"notification-service/internal/core/port"
+	"notification-service/internal/core/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func defaultBranding() config.Branding {
+	return config.Branding{
+		Name:          "Jualan Sayur",
+		PrimaryColor:  "#16a34a",
+		Footer:        "Jl. Pasar Baru 1, Bandung",
+		SenderName:    "Jualan Sayur",
+		SenderAddress: "noreply@jualan-sayur.id",
+	}
+}
+
+func writeTenants(t *testing.T, tenants string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "tenants.json")
+	require.NoError(t, os.WriteFile(file, []byte(tenants), 0o644))
+	return file
+}
+
+func TestBranding_DefaultBrand(t *testing.T) {
+	branding, err := service.NewBrandingService(&config.Config{Branding: defaultBranding()})
+	require.NoError(t, err)
+
+	brand := branding.ForTenant("")
+	assert.Equal(t, "Jualan Sayur", brand.Name)
+	assert.Equal(t, "noreply@jualan-sayur.id", brand.SenderAddress)
+	assert.Equal(t, brand, branding.ForTenant("unknown"), "an unknown tenant gets the default brand")
+}
+
+func TestBranding_TenantOverridesMergeWithDefault(t *testing.T) {
+	cfg := &config.Config{Branding: defaultBranding()}
+	cfg.Branding.File = writeTenants(t, `{"acme": {"name": "Acme Fresh", "primary_color": "#ea580c", "sender_address": "hello@acme.id"}}`)
+
+	branding, err := service.NewBrandingService(cfg)
+	require.NoError(t, err)
+
+	brand := branding.ForTenant("acme")
+	assert.Equal(t, "Acme Fresh", brand.Name)
+	assert.Equal(t, "#ea580c", brand.PrimaryColor)
+	assert.Equal(t, "hello@acme.id", brand.SenderAddress)
+	assert.Equal(t, "Jl. Pasar Baru 1, Bandung", brand.Footer, "fields the tenant leaves out come from the default")
+	assert.Equal(t, "Jualan Sayur", brand.SenderName)
+}
+
+func TestBranding_InvalidBrandsStopStartup(t *testing.T) {
+	invalidDefault := defaultBranding()
+	invalidDefault.PrimaryColor = "green"
+	_, err := service.NewBrandingService(&config.Config{Branding: invalidDefault})
+	assert.ErrorContains(t, err, "default brand")
+
+	for name, tenants := range map[string]string{
+		"color":     `{"acme": {"primary_color": "orange"}}`,
+		"http logo": `{"acme": {"logo_url": "http://acme.id/logo.png"}}`,
+		"sender":    `{"acme": {"sender_address": "not an address"}}`,
+		"json":      `{"acme": `,
+	} {
+		cfg := &config.Config{Branding: defaultBranding()}
+		cfg.Branding.File = writeTenants(t, tenants)
+		_, err := service.NewBrandingService(cfg)
+		assert.Error(t, err, name)
+	}
+
+	cfg := &config.Config{Branding: defaultBranding()}
+	cfg.Branding.File = filepath.Join(t.TempDir(), "missing.json")
+	_, err = service.NewBrandingService(cfg)
+	assert.Error(t, err, "a configured file that is missing is an error")
+}
+
+// logoAssets resolves only "logo.png"
+type logoAssets struct{}
+
+func (logoAssets) URL(name string) (string, error) {
+	if name == "logo.png" {
+		return "https://cdn.test/logo.3f2a9c1b.png", nil
+	}
+	return "", errors.New("asset not in manifest")
+}
+
+func (logoAssets) Watch(ctx context.Context) {}
+
+func newLayoutRenderer(t *testing.T) port.EmailRendererInterface {
+	t.Helper()
+	renderer, err := templates.NewRenderer(&config.Config{Email: config.Email{DefaultLanguage: "id", TemplateHistory: 1}}, logoAssets{})
+	require.NoError(t, err)
+	return renderer
+}
+
+func TestLayout_AppliesBrandAndEscapes(t *testing.T) {
+	renderer := newLayoutRenderer(t)
+
+	html, err := renderer.Layout(port.Brand{Name: "Acme <Fresh>", PrimaryColor: "#ea580c", Footer: "Bandung"}, "Halo", "Pesanan <b>1</b>", "")
+	require.NoError(t, err)
+	assert.Contains(t, html, "background-color:#ea580c")
+	assert.Contains(t, html, "Acme &lt;Fresh&gt;", "without a logo the name is shown, escaped")
+	assert.Contains(t, html, "Pesanan &lt;b&gt;1&lt;/b&gt;")
+	assert.Contains(t, html, "Bandung")
+}
+
+func TestLayout_LogoAssetWinsOverLogoURL(t *testing.T) {
+	renderer := newLayoutRenderer(t)
+
+	html, err := renderer.Layout(port.Brand{Name: "Acme", PrimaryColor: "#ea580c", LogoURL: "https://acme.id/logo.png", LogoAsset: "logo.png"}, "Halo", "Isi", "")
+	require.NoError(t, err)
+	assert.Contains(t, html, `src="https://cdn.test/logo.3f2a9c1b.png"`)
+
+	html, err = renderer.Layout(port.Brand{Name: "Acme", PrimaryColor: "#ea580c", LogoURL: "https://acme.id/logo.png", LogoAsset: "missing.png"}, "Halo", "Isi", "")
+	require.NoError(t, err)
+	assert.Contains(t, html, `src="https://acme.id/logo.png"`, "an unresolved asset falls back to logo_url")
+}
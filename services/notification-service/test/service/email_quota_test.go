@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"notification-service/config"
+	"notification-service/internal/core/port"
+	"notification-service/internal/core/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryQuotaStore keeps the counters in a map; failOn makes Increment fail for matching keys
+type memoryQuotaStore struct {
+	limits    map[string]int
+	limitsErr error
+	failOn    string
+
+	mu       sync.Mutex
+	counters map[string]int64
+}
+
+func newMemoryQuotaStore(limits map[string]int) *memoryQuotaStore {
+	return &memoryQuotaStore{limits: limits, counters: make(map[string]int64)}
+}
+
+func (s *memoryQuotaStore) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	if s.failOn != "" && strings.Contains(key, s.failOn) {
+		return 0, errors.New("redis: connection refused")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counters[key]++
+	return s.counters[key], nil
+}
+
+func (s *memoryQuotaStore) Decrement(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.counters[key]--
+	return nil
+}
+
+func (s *memoryQuotaStore) Limits(ctx context.Context) (map[string]int, error) {
+	return s.limits, s.limitsErr
+}
+
+// counter sums the counters whose key contains part, e.g. ":template:"
+func (s *memoryQuotaStore) counter(part string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var total int64
+	for key, count := range s.counters {
+		if strings.Contains(key, part) {
+			total += count
+		}
+	}
+	return total
+}
+
+func quotaConfig() *config.Config {
+	return &config.Config{RateLimit: config.RateLimit{
+		PerUserHour:          5,
+		PerTemplateHour:      2,
+		ExemptTypes:          []string{"password_reset"},
+		LimitsRefreshSeconds: 30,
+	}}
+}
+
+func reserveN(t *testing.T, quota port.EmailQuotaServiceInterface, recipient, emailType string, n int) (allowed int, last *port.QuotaReservation) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		reservation, err := quota.Reserve(context.Background(), recipient, emailType)
+		require.NoError(t, err)
+		if reservation.Allowed {
+			allowed++
+		}
+		last = reservation
+	}
+	return allowed, last
+}
+
+func TestEmailQuota_PerTemplateLimit(t *testing.T) {
+	store := newMemoryQuotaStore(nil)
+	quota := service.NewEmailQuotaService(quotaConfig(), store)
+
+	allowed, last := reserveN(t, quota, "Budi@Example.com", "back_in_stock", 3)
+
+	assert.Equal(t, 2, allowed)
+	assert.False(t, last.Allowed)
+	assert.Equal(t, time.Now().Truncate(time.Hour).Add(time.Hour), last.RetryAt)
+	assert.Empty(t, last.Keys)
+	// The denied email is not counted, so it is not held against the next one either
+	assert.EqualValues(t, 2, store.counter(":template:budi@example.com:back_in_stock:"))
+	assert.EqualValues(t, 2, store.counter(":user:budi@example.com:"))
+
+	allowed, _ = reserveN(t, quota, "budi@example.com", "campaign", 2)
+	assert.Equal(t, 2, allowed, "other templates have their own quota")
+}
+
+func TestEmailQuota_PerUserLimitAcrossTemplates(t *testing.T) {
+	store := newMemoryQuotaStore(nil)
+	quota := service.NewEmailQuotaService(quotaConfig(), store)
+
+	allowed := 0
+	for _, emailType := range []string{"campaign", "campaign", "back_in_stock", "back_in_stock", "order_final_charge", "support_ticket_update"} {
+		n, _ := reserveN(t, quota, "sari@example.com", emailType, 1)
+		allowed += n
+	}
+
+	assert.Equal(t, 5, allowed)
+	assert.EqualValues(t, 5, store.counter(":user:sari@example.com:"))
+}
+
+func TestEmailQuota_ExemptTypesAndAdminLimits(t *testing.T) {
+	store := newMemoryQuotaStore(map[string]int{"*": 0, "campaign": 1, "order_final_charge": 0})
+	quota := service.NewEmailQuotaService(quotaConfig(), store)
+
+	allowed, _ := reserveN(t, quota, "budi@example.com", "password_reset", 10)
+	assert.Equal(t, 10, allowed, "exempt types are never held back")
+
+	allowed, _ = reserveN(t, quota, "budi@example.com", "order_final_charge", 10)
+	assert.Equal(t, 10, allowed, "an admin limit of 0 exempts the type")
+
+	allowed, _ = reserveN(t, quota, "budi@example.com", "campaign", 3)
+	assert.Equal(t, 1, allowed, "the admin limit wins over the default")
+
+	allowed, _ = reserveN(t, quota, "budi@example.com", "back_in_stock", 3)
+	assert.Equal(t, 2, allowed)
+	assert.Zero(t, store.counter(":user:"), "\"*\" set to 0 turns off the limit across all emails")
+}
+
+func TestEmailQuota_StoreErrorReleasesPartialReservation(t *testing.T) {
+	store := newMemoryQuotaStore(nil)
+	store.failOn = ":template:"
+	quota := service.NewEmailQuotaService(quotaConfig(), store)
+
+	_, err := quota.Reserve(context.Background(), "budi@example.com", "campaign")
+
+	assert.Error(t, err)
+	assert.Zero(t, store.counter(":user:"))
+}
+
+func TestEmailQuota_Release(t *testing.T) {
+	store := newMemoryQuotaStore(nil)
+	quota := service.NewEmailQuotaService(quotaConfig(), store)
+
+	reservation, err := quota.Reserve(context.Background(), "budi@example.com", "campaign")
+	require.NoError(t, err)
+	require.True(t, reservation.Allowed)
+	assert.EqualValues(t, 2, store.counter(":budi@example.com:"))
+
+	quota.Release(context.Background(), reservation)
+	assert.Zero(t, store.counter(":budi@example.com:"))
+}
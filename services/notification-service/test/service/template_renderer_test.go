@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"notification-service/config"
+	"notification-service/internal/adapter/templates"
+	"notification-service/internal/core/port"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticAssets resolves every asset under a fixed CDN URL
+type staticAssets struct{}
+
+func (staticAssets) URL(name string) (string, error) {
+	return "https://cdn.test/" + name, nil
+}
+
+func (staticAssets) Watch(ctx context.Context) {}
+
+func writeTemplate(t *testing.T, dir, lang, emailType, subject string) string {
+	t.Helper()
+	content := `{{define "subject"}}` + subject + `{{end}}{{define "body"}}Halo {{.name}}{{end}}`
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, lang), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, lang, emailType+".tmpl"), []byte(content), 0o644))
+
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+func writePins(t *testing.T, dir, pins string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pins.json"), []byte(pins), 0o644))
+}
+
+func newTemplateRenderer(t *testing.T, dir string, reloadSeconds int) (port.EmailRendererInterface, error) {
+	t.Helper()
+	return templates.NewRenderer(&config.Config{Email: config.Email{
+		DefaultLanguage:       "id",
+		TemplateDir:           dir,
+		TemplateReloadSeconds: reloadSeconds,
+		TemplateHistory:       5,
+	}}, staticAssets{})
+}
+
+func TestRenderer_EmbeddedTemplates(t *testing.T) {
+	renderer, err := newTemplateRenderer(t, "", 0)
+	require.NoError(t, err)
+
+	rendered, err := renderer.Render("en", "password_reset", map[string]string{"name": "Budi", "brand_name": "Jualan Sayur", "link": "https://jualan-sayur.test/reset", "expires": "30"})
+	require.NoError(t, err)
+	assert.Equal(t, "en", rendered.Language)
+	assert.NotEmpty(t, rendered.Version)
+	assert.NotEmpty(t, rendered.Subject)
+}
+
+func TestRenderer_LoadsDirectoryAndFallsBackToDefaultLanguage(t *testing.T) {
+	dir := t.TempDir()
+	version := writeTemplate(t, dir, "id", "welcome", "Selamat datang")
+
+	renderer, err := newTemplateRenderer(t, dir, 0)
+	require.NoError(t, err)
+
+	rendered, err := renderer.Render("en", "welcome", map[string]string{"name": "Budi"})
+	require.NoError(t, err)
+	assert.Equal(t, "id", rendered.Language, "no en template, so the default language is used")
+	assert.Equal(t, version, rendered.Version)
+	assert.Equal(t, "Selamat datang", rendered.Subject)
+	assert.Equal(t, "Halo Budi", rendered.Body)
+
+	_, err = renderer.Render("en", "password_reset", map[string]string{"name": "Budi"})
+	assert.ErrorIs(t, err, port.ErrTemplateNotFound)
+
+	assert.FileExists(t, filepath.Join(dir, "versions", "id", "welcome", version+".tmpl"), "every loaded version is kept for pins")
+}
+
+func TestRenderer_InvalidTemplateFailsStartup(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "id"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "id", "welcome.tmpl"), []byte(`{{define "subject"}`), 0o644))
+
+	_, err := newTemplateRenderer(t, dir, 0)
+	assert.Error(t, err)
+}
+
+func TestRenderer_PinSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	old := writeTemplate(t, dir, "id", "welcome", "Versi lama")
+	_, err := newTemplateRenderer(t, dir, 0)
+	require.NoError(t, err)
+
+	// The file is edited and the service restarts with the old version pinned
+	writeTemplate(t, dir, "id", "welcome", "Versi baru")
+	writePins(t, dir, `{"id/welcome": "`+old+`"}`)
+
+	renderer, err := newTemplateRenderer(t, dir, 0)
+	require.NoError(t, err)
+
+	rendered, err := renderer.Render("id", "welcome", map[string]string{"name": "Budi"})
+	require.NoError(t, err)
+	assert.Equal(t, old, rendered.Version)
+	assert.Equal(t, "Versi lama", rendered.Subject)
+}
+
+func TestRenderer_MissingPinFailsStartup(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "id", "welcome", "Selamat datang")
+	writePins(t, dir, `{"id/welcome": "000000000000"}`)
+
+	_, err := newTemplateRenderer(t, dir, 0)
+	assert.ErrorContains(t, err, "pinned version 000000000000 of id/welcome not found")
+}
+
+func TestRenderer_TamperedPinnedVersionFailsStartup(t *testing.T) {
+	dir := t.TempDir()
+	old := writeTemplate(t, dir, "id", "welcome", "Versi lama")
+	_, err := newTemplateRenderer(t, dir, 0)
+	require.NoError(t, err)
+
+	writeTemplate(t, dir, "id", "welcome", "Versi baru")
+	kept := filepath.Join(dir, "versions", "id", "welcome", old+".tmpl")
+	require.NoError(t, os.WriteFile(kept, []byte(`{{define "subject"}}Diubah{{end}}{{define "body"}}x{{end}}`), 0o644))
+	writePins(t, dir, `{"id/welcome": "`+old+`"}`)
+
+	_, err = newTemplateRenderer(t, dir, 0)
+	assert.ErrorContains(t, err, "does not match its checksum")
+}
+
+func TestRenderer_ReloadPicksUpNewVersionsAndDropsDeletedTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeTemplate(t, dir, "id", "welcome", "Versi lama")
+	writeTemplate(t, dir, "id", "photo_removed", "Foto dihapus")
+
+	renderer, err := newTemplateRenderer(t, dir, 1)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go renderer.Watch(ctx)
+
+	latest := writeTemplate(t, dir, "id", "welcome", "Versi baru")
+	require.NoError(t, os.Remove(filepath.Join(dir, "id", "photo_removed.tmpl")))
+
+	assert.Eventually(t, func() bool {
+		rendered, err := renderer.Render("id", "welcome", map[string]string{"name": "Budi"})
+		return err == nil && rendered.Version == latest
+	}, 5*time.Second, 100*time.Millisecond)
+
+	_, err = renderer.Render("id", "photo_removed", map[string]string{"name": "Budi"})
+	assert.True(t, errors.Is(err, port.ErrTemplateNotFound), "a deleted template is no longer rendered")
+}
+
+func TestRenderer_MissingPinOnReloadKeepsCurrentPins(t *testing.T) {
+	dir := t.TempDir()
+	old := writeTemplate(t, dir, "id", "welcome", "Versi lama")
+	writePins(t, dir, `{"id/welcome": "`+old+`"}`)
+
+	renderer, err := newTemplateRenderer(t, dir, 1)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go renderer.Watch(ctx)
+
+	latest := writeTemplate(t, dir, "id", "welcome", "Versi baru")
+	writePins(t, dir, `{"id/welcome": "000000000000", "id/other": "`+latest+`"}`)
+
+	// Two reloads are enough for the broken pins to have been read
+	time.Sleep(2500 * time.Millisecond)
+
+	rendered, err := renderer.Render("id", "welcome", map[string]string{"name": "Budi"})
+	require.NoError(t, err)
+	assert.Equal(t, old, rendered.Version, "an unresolvable pin leaves the current pins in place")
+}
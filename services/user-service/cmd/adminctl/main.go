@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+	"user-service/config"
+	"user-service/internal/adapter/message"
+	"user-service/internal/adapter/repository"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/utils"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// operator is recorded in the audit log of every change, since the pod has no logged-in user
+var operator string
+
+var rootCmd = &cobra.Command{
+	Use:   "adminctl",
+	Short: "Operational tasks for user-service",
+	Long: `adminctl menjalankan tugas operasional user-service tanpa menulis SQL, misalnya lewat
+kubectl exec ke pod user-service. Konfigurasi dibaca dari .env dan environment yang sama
+dengan server. Setiap perubahan dicatat di audit log beserta --operator.`,
+	SilenceUsage: true,
+}
+
+var userCmd = &cobra.Command{
+	Use:   "user",
+	Short: "Manage user accounts",
+}
+
+var userPromoteCmd = &cobra.Command{
+	Use:   "promote <email>",
+	Short: "Change a user's role",
+	Long: `Mengganti role user, secara default menjadi Super Admin. Semua sesi user ikut dicabut,
+karena role ada di dalam token dan baru berlaku setelah login ulang.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		role, _ := cmd.Flags().GetString("role")
+		user, err := newOperatorService(false).PromoteUser(context.Background(), args[0], role)
+		if err != nil {
+			log.Fatalf("❌ Promote failed: %v", err)
+		}
+		fmt.Printf("✅ %s is now %s (user %d)\n", user.Email, user.RoleName, user.ID)
+	},
+}
+
+var userVerifyCmd = &cobra.Command{
+	Use:   "verify <email>",
+	Short: "Mark a user's email as verified",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		user, err := newOperatorService(false).VerifyUser(context.Background(), args[0])
+		if err != nil {
+			log.Fatalf("❌ Verify failed: %v", err)
+		}
+		fmt.Printf("✅ %s is verified (user %d)\n", user.Email, user.ID)
+	},
+}
+
+var sessionsCmd = &cobra.Command{
+	Use:   "sessions",
+	Short: "Manage user sessions",
+}
+
+var sessionsRevokeCmd = &cobra.Command{
+	Use:   "revoke <email>",
+	Short: "Sign a user out of every device",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		revoked, err := newOperatorService(false).RevokeSessions(context.Background(), args[0])
+		if err != nil {
+			log.Fatalf("❌ Revoke failed: %v", err)
+		}
+		fmt.Printf("✅ Revoked %d session(s)\n", revoked)
+	},
+}
+
+var verificationCmd = &cobra.Command{
+	Use:   "verification",
+	Short: "Manage email verification",
+}
+
+var verificationResendCmd = &cobra.Command{
+	Use:   "resend <email>",
+	Short: "Send a new verification email",
+	Long: `Mengirim ulang email verifikasi. Berbeda dengan endpoint publik, perintah ini memberi tahu
+jika user tidak ditemukan atau sudah terverifikasi. Butuh RabbitMQ.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := newOperatorService(true).ResendVerification(context.Background(), args[0]); err != nil {
+			log.Fatalf("❌ Resend failed: %v", err)
+		}
+		fmt.Println("✅ Verification email queued")
+	},
+}
+
+var tokensCmd = &cobra.Command{
+	Use:   "tokens",
+	Short: "Manage stored tokens",
+}
+
+var tokensPurgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "Delete expired verification tokens and blacklist entries",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		purged, err := newOperatorService(false).PurgeTokens(context.Background())
+		if err != nil {
+			log.Fatalf("❌ Purge failed: %v", err)
+		}
+		fmt.Printf("✅ Deleted %d verification token(s) and %d blacklist entry(ies)\n", purged.ExpiredVerifications, purged.BlacklistEntries)
+	},
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Print the effective configuration with secrets redacted",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		body, err := json.Marshal(config.NewConfig())
+		if err != nil {
+			log.Fatalf("❌ Failed to encode config: %v", err)
+		}
+		redacted, ok := utils.RedactJSON(body)
+		if !ok {
+			log.Fatalf("❌ Failed to redact config")
+		}
+
+		var out interface{}
+		_ = json.Unmarshal(redacted, &out)
+		pretty, _ := json.MarshalIndent(out, "", "  ")
+		fmt.Println(string(pretty))
+	},
+}
+
+func init() {
+	cobra.OnInitialize(viper.AutomaticEnv)
+
+	defaultOperator := os.Getenv("USER")
+	if defaultOperator == "" {
+		defaultOperator = "adminctl"
+	}
+	rootCmd.PersistentFlags().StringVar(&operator, "operator", defaultOperator, "who is running the command, recorded in the audit log")
+
+	userPromoteCmd.Flags().String("role", "Super Admin", "role to give the user")
+
+	userCmd.AddCommand(userPromoteCmd, userVerifyCmd)
+	sessionsCmd.AddCommand(sessionsRevokeCmd)
+	verificationCmd.AddCommand(verificationResendCmd)
+	tokensCmd.AddCommand(tokensPurgeCmd)
+	rootCmd.AddCommand(userCmd, sessionsCmd, verificationCmd, tokensCmd, configCmd)
+}
+
+// newOperatorService connects to Postgres and Redis, and to RabbitMQ only when the command
+// sends email, so the other commands keep working while the broker is down
+func newOperatorService(sendsEmail bool) port.OperatorServiceInterface {
+	cfg := config.NewConfig()
+	// User details read by the commands include the encrypted phone and address
+	if err := repository.RegisterPIIEncryption(cfg.PIIEncryption); err != nil {
+		log.Fatalf("❌ Failed to load PII keys: %v", err)
+	}
+
+	db, err := cfg.ConnectionPostgres()
+	if err != nil {
+		log.Fatalf("❌ Database connection failed: %v", err)
+	}
+	redisClient := cfg.RedisClient()
+
+	userRepo := repository.NewUserRepository(db.DB)
+	sessionRepo := repository.NewSessionRepository(redisClient, cfg)
+	verificationTokenRepo := repository.NewVerificationTokenRepository(db.DB)
+	blacklistTokenRepo, err := repository.NewBlacklistTokenBackend(cfg, db.DB, redisClient)
+	if err != nil {
+		log.Fatalf("❌ Failed to select blacklist backend: %v", err)
+	}
+
+	var emailPublisher port.EmailInterface
+	if sendsEmail {
+		channel, err := cfg.ConnectionRabbitMQ()
+		if err != nil {
+			log.Fatalf("❌ RabbitMQ connection failed: %v", err)
+		}
+		broker := message.NewBroker(channel, cfg.ConnectionRabbitMQ, time.Duration(cfg.RabbitMQ.ReconnectSeconds)*time.Second)
+		emailPublisher = message.NewEmailPublisher(message.NewEmailOutbox(broker, nil, nil), service.TokenLifetimesFromConfig(cfg), userRepo, cfg.App.LinkBaseURL())
+	}
+
+	auditLogService := service.NewAuditLogService(repository.NewAuditLogRepository(db.DB))
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              userRepo,
+			SessionRepo:           sessionRepo,
+			JWTUtil:               utils.NewJWTUtil(cfg),
+			VerificationTokenRepo: verificationTokenRepo,
+			EmailPublisher:        emailPublisher,
+			BlacklistTokenRepo:    blacklistTokenRepo,
+		},
+		Config: cfg,
+	})
+
+	return service.NewOperatorService(userRepo, sessionRepo, verificationTokenRepo, blacklistTokenRepo, userService, auditLogService, cfg, operator)
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
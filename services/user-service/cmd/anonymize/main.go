@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+
+	"user-service/database/anonymize"
+	"user-service/utils"
+
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// anonymize rewrites personal data in a restored production dump before it is used as staging:
+//
+//	go run ./cmd/anonymize -dsn postgres://.../sayur_staging -yes
+func main() {
+	err := godotenv.Load(".env")
+	if err != nil {
+		log.Printf("Warning: Error loading .env file: %v", err)
+	}
+
+	dsn := flag.String("dsn", "", "database URL (overrides .env)")
+	seed := flag.String("seed", "", "makes the fake data reproducible; random when empty, so fakes cannot be linked back")
+	password := flag.String("password", "password123", "password every account gets; empty keeps the production hashes")
+	batch := flag.Int("batch", 500, "rows read per query")
+	yes := flag.Bool("yes", false, "confirm that the database may be rewritten")
+	flag.Parse()
+
+	if strings.EqualFold(os.Getenv("APP_ENV"), "production") {
+		log.Fatalf("refusing to anonymize with APP_ENV=production")
+	}
+
+	databaseURL := *dsn
+	if databaseURL == "" {
+		host := os.Getenv("DATABASE_HOST")
+		port := os.Getenv("DATABASE_PORT")
+		user := os.Getenv("DATABASE_USER")
+		pass := os.Getenv("DATABASE_PASSWORD")
+		db := os.Getenv("DATABASE_NAME")
+		databaseURL = fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", user, pass, host, port, db)
+	}
+
+	if !*yes {
+		target := "the database"
+		if u, err := url.Parse(databaseURL); err == nil {
+			target = u.Redacted()
+		}
+		log.Fatalf("this rewrites every user in %s; run again with -yes", target)
+	}
+
+	seedBytes := []byte(*seed)
+	if len(seedBytes) == 0 {
+		seedBytes = make([]byte, 32)
+		if _, err := rand.Read(seedBytes); err != nil {
+			log.Fatalf("failed to generate seed: %v", err)
+		}
+	}
+
+	var passwordHash string
+	if *password != "" {
+		passwordHash, err = utils.DefaultPasswordHasher().HashPassword(*password)
+		if err != nil {
+			log.Fatalf("failed to hash password: %v", err)
+		}
+	}
+
+	db, err := gorm.Open(postgres.Open(databaseURL), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		log.Fatalf("failed to connect to database: %v", err)
+	}
+
+	report, err := anonymize.Run(context.Background(), db, anonymize.NewFaker(seedBytes), anonymize.Options{
+		PasswordHash: passwordHash,
+		BatchSize:    *batch,
+	})
+	if err != nil {
+		log.Fatalf("anonymization failed, nothing was changed: %v", err)
+	}
+
+	for _, table := range report {
+		log.Printf("%-24s %d rows", table.Table, table.Rows)
+	}
+	log.Println("Anonymization completed successfully")
+}
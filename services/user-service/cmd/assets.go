@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"user-service/config"
+	"user-service/internal/adapter/httpclient"
+	"user-service/internal/adapter/repository"
+	"user-service/internal/adapter/storage"
+	"user-service/internal/core/service"
+
+	"github.com/spf13/cobra"
+)
+
+// assetsCmd represents the assets command
+var assetsCmd = &cobra.Command{
+	Use:   "assets",
+	Short: "Manage email template assets",
+	Long: `Tools untuk mengelola gambar yang dipakai template email (logo, foto produk).
+
+Subcommands:
+- upload: Upload gambar ke bucket assets dan perbarui URL publiknya`,
+}
+
+// assetsUploadCmd represents the assets upload command
+var assetsUploadCmd = &cobra.Command{
+	Use:   "upload <file>",
+	Short: "Upload an email asset",
+	Long: `Upload gambar ke bucket assets. Nama object diberi hash isi file, jadi URL
+berubah setiap kali gambarnya berubah. Tanpa --name, nama file dipakai sebagai nama asset.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+		uploadAsset(args[0], name)
+	},
+}
+
+func init() {
+	assetsCmd.AddCommand(assetsUploadCmd)
+
+	assetsUploadCmd.Flags().String("name", "", "asset name used by templates, e.g. logo.png or products/bayam.webp")
+}
+
+func uploadAsset(file, name string) {
+	if name == "" {
+		name = strings.ToLower(filepath.Base(file))
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		log.Fatalf("❌ Failed to open %s: %v", file, err)
+	}
+	defer f.Close()
+
+	cfg := config.NewConfig()
+	db, err := cfg.ConnectionPostgres()
+	if err != nil {
+		log.Fatalf("❌ Database connection failed: %v", err)
+	}
+
+	supabaseStorage, err := storage.NewSupabaseStorage(
+		cfg.Supabase.ProjectURL,
+		cfg.Supabase.APIKey,
+		cfg.Supabase.BucketName,
+		cfg.Timeouts.Storage(),
+		cfg.Supabase.MaxUploadSize(),
+		httpclient.New(httpclient.Options{Name: "storage"}),
+	)
+	if err != nil {
+		log.Fatalf("❌ Supabase Storage not available: %v", err)
+	}
+
+	auditLogService := service.NewAuditLogService(repository.NewAuditLogRepository(db.DB))
+	objectStorage := storage.NewContentAddressedStorage(supabaseStorage, repository.NewStoredObjectRepository(db.DB), cfg.Supabase.BucketName)
+	assetService := service.NewAssetService(repository.NewAssetRepository(db.DB), objectStorage, auditLogService, cfg)
+
+	asset, err := assetService.UploadAsset(context.Background(), name, f, 0)
+	if err != nil {
+		log.Fatalf("❌ Upload failed: %v", err)
+	}
+
+	fmt.Printf("✅ %s\n", asset.Name)
+	fmt.Printf("  URL:  %s\n", asset.URL)
+	fmt.Printf("  Hash: %s\n", asset.Hash)
+}
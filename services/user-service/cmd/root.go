@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"user-service/internal/buildinfo"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -25,7 +26,7 @@ Microservices yang tersedia:
 - Notification Service: Mengelola notifikasi
 
 Untuk informasi lebih lanjut, kunjungi: https://github.com/hilmirazib/jualan-sayur`,
-	Version: "1.0.0",
+	Version: buildinfo.Version,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -40,6 +41,9 @@ func Execute() {
 func init() {
 	cobra.OnInitialize(initConfig)
 
+	info := buildinfo.Get()
+	rootCmd.SetVersionTemplate(fmt.Sprintf("{{.Name}} {{.Version}} (commit %s, built %s, %s)\n", info.GitCommit, info.BuildTime, info.GoVersion))
+
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is .env)")
 	rootCmd.PersistentFlags().Bool("verbose", false, "enable verbose output")
@@ -48,6 +52,9 @@ func init() {
 	rootCmd.AddCommand(startCmd)
 	rootCmd.AddCommand(migrateCmd)
 	rootCmd.AddCommand(configCmd)
+	rootCmd.AddCommand(assetsCmd)
+	rootCmd.AddCommand(schemaCmd)
+	rootCmd.AddCommand(piiCmd)
 }
 
 func initConfig() {
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"user-service/config"
+	"user-service/internal/adapter/repository"
+	"user-service/internal/core/domain/model"
+
+	"github.com/spf13/cobra"
+)
+
+// schemaCmd represents the schema command
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Inspect the database schema",
+	Long: `Tools untuk memeriksa skema database.
+
+Subcommands:
+- check: Bandingkan model GORM dengan skema database yang sedang berjalan`,
+}
+
+// schemaCheckCmd represents the schema check command
+var schemaCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Report drift between GORM models and the live schema",
+	Long: `Membandingkan setiap model GORM dengan tabelnya di database: tabel dan kolom yang hilang,
+tipe yang tidak cocok (misalnya field string untuk kolom numeric), nullability, dan index yang
+dideklarasikan model. Keluar dengan kode 1 jika ada perbedaan yang bisa merusak query.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+		checkSchema(all)
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaCheckCmd)
+
+	schemaCheckCmd.Flags().Bool("all", false, "also list database columns no model maps")
+}
+
+func checkSchema(all bool) {
+	cfg := config.NewConfig()
+	db, err := cfg.ConnectionPostgres()
+	if err != nil {
+		log.Fatalf("❌ Database connection failed: %v", err)
+	}
+
+	drifts, err := repository.DetectSchemaDrift(context.Background(), db.DB, model.All())
+	if err != nil {
+		log.Fatalf("❌ Schema inspection failed: %v", err)
+	}
+
+	harmful := 0
+	for _, drift := range drifts {
+		if drift.Harmless() {
+			if all {
+				fmt.Printf("  ℹ️  %-24s %-16s %s\n", drift.Table, drift.Kind, drift.Detail)
+			}
+			continue
+		}
+		harmful++
+		fmt.Printf("  ⚠️  %-24s %-16s %s\n", drift.Table, drift.Kind, drift.Detail)
+	}
+
+	if harmful > 0 {
+		fmt.Printf("\n❌ %d difference(s) between GORM models and the database\n", harmful)
+		os.Exit(1)
+	}
+	fmt.Printf("✅ %d models match the database\n", len(model.All()))
+}
@@ -1,15 +1,56 @@
 package config
 
-import "github.com/spf13/viper"
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 type App struct {
 	AppPort string `json:"app_port"`
 	AppEnv  string `json:"app_env"`
+	// PublicBaseURL is where users reach this service, e.g. https://api.jualan-sayur.id; links in emails start with it
+	PublicBaseURL string `json:"public_base_url"`
 
 	JwtSecretKey string `json:"jwt_secret_key"`
 	JwtIssuer    string `json:"jwt_issuer"`
 }
 
+// LinkBaseURL is PublicBaseURL, or the local port outside production
+func (a App) LinkBaseURL() string {
+	if a.PublicBaseURL != "" {
+		return a.PublicBaseURL
+	}
+	port := a.AppPort
+	if port == "" {
+		port = "8080"
+	}
+	return "http://localhost:" + port
+}
+
+// Validate requires PublicBaseURL in production, where a localhost link in an email is useless
+func (a App) Validate() error {
+	if a.PublicBaseURL == "" {
+		if a.AppEnv == "production" {
+			return fmt.Errorf("APP_PUBLIC_BASE_URL is required in production")
+		}
+		return nil
+	}
+	parsed, err := url.Parse(a.PublicBaseURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("APP_PUBLIC_BASE_URL must be an absolute http(s) URL, got %q", a.PublicBaseURL)
+	}
+	return nil
+}
+
 type PsqlDB struct {
 	Host      string `json:"host"`
 	Port      string `json:"port"`
@@ -24,14 +65,979 @@ type Supabase struct {
 	ProjectURL string `json:"project_url"`
 	APIKey     string `json:"api_key"`
 	BucketName string `json:"bucket_name"`
+	// WebhookSecret verifies storage.objects webhooks sent to /api/v1/webhooks/supabase/storage
+	WebhookSecret string `json:"webhook_secret"`
+	// MaxUploadMB caps a single streamed upload
+	MaxUploadMB int `json:"max_upload_mb"`
+}
+
+// MaxUploadSize defaults to 100 MB
+func (s Supabase) MaxUploadSize() int64 {
+	if s.MaxUploadMB <= 0 {
+		return 100 << 20
+	}
+	return int64(s.MaxUploadMB) << 20
+}
+
+// CDN serves public storage objects: <project>/storage/v1/object/public/<bucket>/<object> is
+// returned to clients as <BaseURL>/<bucket>/<object>, so BaseURL must point at that path.
+type CDN struct {
+	BaseURL string `json:"base_url"`
+	// PurgeURL receives {"files": [...]} when an object is deleted; empty disables purging
+	PurgeURL   string `json:"purge_url"`
+	PurgeToken string `json:"purge_token"`
+}
+
+type Geocoder struct {
+	Provider  string `json:"provider"`
+	BaseURL   string `json:"base_url"`
+	APIKey    string `json:"api_key"`
+	UserAgent string `json:"user_agent"`
+}
+
+type Ledger struct {
+	CommissionRateBps   int64 `json:"commission_rate_bps"`
+	MinWithdrawalAmount int64 `json:"min_withdrawal_amount"`
+}
+
+type Worker struct {
+	Concurrency         int `json:"concurrency"`
+	PollIntervalSeconds int `json:"poll_interval_seconds"`
+}
+
+// Log sets the zerolog level (trace, debug, info, warn, error) and format (json or console)
+type Log struct {
+	Level  string `json:"level"`
+	Format string `json:"format"`
+}
+
+type HTTPLog struct {
+	BodySampleRate float64 `json:"body_sample_rate"`
+	BodyMaxBytes   int     `json:"body_max_bytes"`
+}
+
+type AdminAccess struct {
+	// IPAllowlist is always allowed, so admins cannot lock themselves out via the API
+	IPAllowlist []string `json:"ip_allowlist"`
+	// TrustedProxies are the only peers whose X-Forwarded-For header is believed
+	TrustedProxies []string `json:"trusted_proxies"`
+}
+
+type Security struct {
+	// HSTSMaxAge in seconds; 0 leaves the Strict-Transport-Security header off
+	HSTSMaxAge     int    `json:"hsts_max_age"`
+	FrameOptions   string `json:"frame_options"`
+	ReferrerPolicy string `json:"referrer_policy"`
+	// CSRFEnabled turns on double-submit tokens for requests carrying the session cookie
+	CSRFEnabled       bool   `json:"csrf_enabled"`
+	SessionCookieName string `json:"session_cookie_name"`
+	CookieSecure      bool   `json:"cookie_secure"`
+	// SessionCookieEnabled lets web clients (X-Client-Type: web) receive the JWT as an httpOnly cookie
+	SessionCookieEnabled  bool   `json:"session_cookie_enabled"`
+	SessionCookieSameSite string `json:"session_cookie_same_site"`
+	SessionCookieDomain   string `json:"session_cookie_domain"`
+	// CORSAllowOrigins must list the web frontends explicitly for cookies to be sent cross-origin
+	CORSAllowOrigins []string `json:"cors_allow_origins"`
+}
+
+// Validate rejects cookie sessions without CSRF protection: the browser attaches the cookie to
+// any cross-site form post, so the double-submit token is what tells them apart
+func (s Security) Validate() error {
+	if s.SessionCookieEnabled && !s.CSRFEnabled {
+		return fmt.Errorf("SESSION_COOKIE_ENABLED=true requires CSRF_ENABLED=true")
+	}
+	return nil
+}
+
+type InternalAuth struct {
+	// ServiceKeys maps a service name to its accepted API keys; several keys allow rotation
+	ServiceKeys map[string][]string `json:"-"`
+}
+
+type Risk struct {
+	// StepUpEnabled requires an email OTP when a sign-in scores at or above StepUpThreshold
+	StepUpEnabled         bool `json:"step_up_enabled"`
+	StepUpThreshold       int  `json:"step_up_threshold"`
+	FailedSignInWindowMin int  `json:"failed_signin_window_min"`
+	MaxTravelSpeedKmh     int  `json:"max_travel_speed_kmh"`
+	OTPTTLMinutes         int  `json:"otp_ttl_minutes"`
+	OTPMaxAttempts        int  `json:"otp_max_attempts"`
+	// GeoIPBaseURL points at an ip-api.com compatible service; empty disables geo-velocity checks
+	GeoIPBaseURL string `json:"geoip_base_url"`
+}
+
+type Webhook struct {
+	// TimeoutSeconds bounds one delivery attempt; slow endpoints fail and are retried
+	TimeoutSeconds int `json:"timeout_seconds"`
+}
+
+type Startup struct {
+	// TimeoutSeconds bounds how long startup waits for Postgres, Redis and RabbitMQ before degrading
+	TimeoutSeconds    int `json:"timeout_seconds"`
+	InitialBackoffMs  int `json:"initial_backoff_ms"`
+	MaxBackoffSeconds int `json:"max_backoff_seconds"`
+}
+
+// HTTPServer bounds slow or oversized clients at the connection level, below TimeoutMiddleware
+type HTTPServer struct {
+	ReadTimeoutSeconds       int `json:"read_timeout_seconds"`
+	ReadHeaderTimeoutSeconds int `json:"read_header_timeout_seconds"`
+	WriteTimeoutSeconds      int `json:"write_timeout_seconds"`
+	IdleTimeoutSeconds       int `json:"idle_timeout_seconds"`
+	MaxHeaderKB              int `json:"max_header_kb"`
+	// BodyLimitKB applies to every route except uploads, which get UploadBodyLimitMB
+	BodyLimitKB       int `json:"body_limit_kb"`
+	UploadBodyLimitMB int `json:"upload_body_limit_mb"`
+}
+
+// ReadTimeout defaults to 60s, enough to send an upload body on a slow connection
+func (h HTTPServer) ReadTimeout() time.Duration {
+	return durationOr(h.ReadTimeoutSeconds, time.Second, 60*time.Second)
+}
+
+// ReadHeaderTimeout defaults to 5s; this is what stops slow-loris clients
+func (h HTTPServer) ReadHeaderTimeout() time.Duration {
+	return durationOr(h.ReadHeaderTimeoutSeconds, time.Second, 5*time.Second)
+}
+
+// WriteTimeout defaults to 90s
+func (h HTTPServer) WriteTimeout() time.Duration {
+	return durationOr(h.WriteTimeoutSeconds, time.Second, 90*time.Second)
+}
+
+// IdleTimeout defaults to 120s
+func (h HTTPServer) IdleTimeout() time.Duration {
+	return durationOr(h.IdleTimeoutSeconds, time.Second, 120*time.Second)
+}
+
+// MaxHeaderBytes defaults to 64 KB
+func (h HTTPServer) MaxHeaderBytes() int {
+	if h.MaxHeaderKB <= 0 {
+		return 64 << 10
+	}
+	return h.MaxHeaderKB << 10
+}
+
+// BodyLimit defaults to 1 MB
+func (h HTTPServer) BodyLimit() int64 {
+	if h.BodyLimitKB <= 0 {
+		return 1 << 20
+	}
+	return int64(h.BodyLimitKB) << 10
+}
+
+// UploadBodyLimit defaults to 12 MB: a 10 MB customer import or a 5 MB chunk plus multipart overhead
+func (h HTTPServer) UploadBodyLimit() int64 {
+	if h.UploadBodyLimitMB <= 0 {
+		return 12 << 20
+	}
+	return int64(h.UploadBodyLimitMB) << 20
+}
+
+// Validate keeps the connection limits from cutting off what the request timeouts and
+// upload chunks still allow
+func (h HTTPServer) Validate(timeouts Timeouts, upload Upload) error {
+	if uploadTimeout := timeouts.Request() + timeouts.Storage(); h.WriteTimeout() <= uploadTimeout {
+		return fmt.Errorf("HTTP_WRITE_TIMEOUT_SECONDS: %s must be longer than the upload request timeout %s", h.WriteTimeout(), uploadTimeout)
+	}
+	if h.UploadBodyLimit() <= upload.ChunkSize() {
+		return fmt.Errorf("HTTP_UPLOAD_BODY_LIMIT_MB: %d bytes must be larger than the upload chunk size %d", h.UploadBodyLimit(), upload.ChunkSize())
+	}
+	return nil
+}
+
+type Timeouts struct {
+	// RequestSeconds bounds a whole HTTP request; multipart uploads also get StorageSeconds on top
+	RequestSeconds int `json:"request_seconds"`
+	// DBMs, StorageSeconds and RedisMs bound a single query, storage call or Redis command
+	DBMs           int `json:"db_ms"`
+	StorageSeconds int `json:"storage_seconds"`
+	RedisMs        int `json:"redis_ms"`
+}
+
+// Request defaults to 15s
+func (t Timeouts) Request() time.Duration {
+	return durationOr(t.RequestSeconds, time.Second, 15*time.Second)
+}
+
+// DB defaults to 2s
+func (t Timeouts) DB() time.Duration {
+	return durationOr(t.DBMs, time.Millisecond, 2*time.Second)
+}
+
+// Storage defaults to 30s
+func (t Timeouts) Storage() time.Duration {
+	return durationOr(t.StorageSeconds, time.Second, 30*time.Second)
+}
+
+// Redis defaults to 500ms
+func (t Timeouts) Redis() time.Duration {
+	return durationOr(t.RedisMs, time.Millisecond, 500*time.Millisecond)
+}
+
+// QueryTrace tags SQL statements with the endpoint that ran them and counts them per request.
+// Comments is "full" (route and request ID, the default), "route" or "off". Unique comments
+// defeat the driver's prepared statement cache, so "route" suits a database that is already
+// busy preparing. Requests running more than WarnQueries statements are logged.
+type QueryTrace struct {
+	Comments    string `json:"comments"`
+	WarnQueries int    `json:"warn_queries"`
+}
+
+func (q QueryTrace) CommentsEnabled() bool {
+	return q.Comments != "off"
+}
+
+func (q QueryTrace) CommentRequestID() bool {
+	return q.Comments == "" || q.Comments == "full"
+}
+
+// WarnThreshold defaults to 30 statements per request
+func (q QueryTrace) WarnThreshold() int {
+	if q.WarnQueries <= 0 {
+		return 30
+	}
+	return q.WarnQueries
+}
+
+func (q QueryTrace) Validate() error {
+	switch q.Comments {
+	case "", "full", "route", "off":
+		return nil
+	default:
+		return fmt.Errorf("DB_QUERY_COMMENTS must be full, route or off, got %q", q.Comments)
+	}
+}
+
+func durationOr(value int, unit, fallback time.Duration) time.Duration {
+	if value <= 0 {
+		return fallback
+	}
+	return time.Duration(value) * unit
+}
+
+type CircuitBreaker struct {
+	// FailureThreshold consecutive failures open the breaker for OpenSeconds; then
+	// HalfOpenRequests trial calls decide whether it closes again
+	FailureThreshold int `json:"failure_threshold"`
+	OpenSeconds      int `json:"open_seconds"`
+	HalfOpenRequests int `json:"half_open_requests"`
+}
+
+// HTTPClient is the retry policy of calls to external APIs (storage, geocoding). 5xx responses
+// and dropped connections are retried MaxRetries times (-1 for none) after a random wait of
+// up to RetryBaseMs, doubling per attempt up to RetryMaxMs.
+type HTTPClient struct {
+	MaxRetries  int `json:"max_retries"`
+	RetryBaseMs int `json:"retry_base_ms"`
+	RetryMaxMs  int `json:"retry_max_ms"`
+}
+
+type Email struct {
+	// PlusAddressing is "keep" (default) or "strip" to store budi+promo@x.com as budi@x.com
+	PlusAddressing string `json:"plus_addressing"`
+	// BlockDisposable refuses throwaway domains at signup and email change; DisposableDomains extends the built-in list
+	BlockDisposable   bool     `json:"block_disposable"`
+	DisposableDomains []string `json:"disposable_domains"`
+}
+
+type Upload struct {
+	// Dir holds resumable uploads until they are assembled; must be shared between replicas
+	Dir         string `json:"dir"`
+	ChunkSizeKB int    `json:"chunk_size_kb"`
+	MaxSizeMB   int    `json:"max_size_mb"`
+	// TTLHours after creation an unfinished upload is considered abandoned and removed
+	TTLHours int `json:"ttl_hours"`
+}
+
+// Directory defaults to <os temp dir>/jualan-sayur-uploads
+func (u Upload) Directory() string {
+	if u.Dir == "" {
+		return filepath.Join(os.TempDir(), "jualan-sayur-uploads")
+	}
+	return u.Dir
+}
+
+// ChunkSize defaults to 5 MB
+func (u Upload) ChunkSize() int64 {
+	if u.ChunkSizeKB <= 0 {
+		return 5 << 20
+	}
+	return int64(u.ChunkSizeKB) << 10
+}
+
+// MaxSize defaults to 100 MB
+func (u Upload) MaxSize() int64 {
+	if u.MaxSizeMB <= 0 {
+		return 100 << 20
+	}
+	return int64(u.MaxSizeMB) << 20
+}
+
+// TTL defaults to 24h
+func (u Upload) TTL() time.Duration {
+	return durationOr(u.TTLHours, time.Hour, 24*time.Hour)
+}
+
+// CustomerSuggest serves admin type-ahead from a trigram index; repeated prefixes are answered
+// from a small in-memory cache per instance
+type CustomerSuggest struct {
+	// TimeoutMs is the latency budget of one lookup; slower queries are abandoned
+	TimeoutMs       int `json:"timeout_ms"`
+	CacheSize       int `json:"cache_size"`
+	CacheTTLSeconds int `json:"cache_ttl_seconds"`
+}
+
+// Timeout defaults to 200ms
+func (c CustomerSuggest) Timeout() time.Duration {
+	return durationOr(c.TimeoutMs, time.Millisecond, 200*time.Millisecond)
+}
+
+// Cache defaults to 512 queries for 30s; a negative size disables it
+func (c CustomerSuggest) Cache() (int, time.Duration) {
+	size := c.CacheSize
+	if size == 0 {
+		size = 512
+	}
+	return size, durationOr(c.CacheTTLSeconds, time.Second, 30*time.Second)
+}
+
+// ResponsePolicy hides customer fields from roles that do not need them
+type ResponsePolicy struct {
+	// HiddenFields maps a role to the "payload.field" entries it may not see
+	HiddenFields map[string][]string `json:"-"`
+}
+
+// Hidden defaults to keeping customer emails and usernames from couriers
+func (r ResponsePolicy) Hidden() map[string][]string {
+	if len(r.HiddenFields) == 0 {
+		return map[string][]string{
+			"Courier": {"customer.email", "customer.username"},
+		}
+	}
+	return r.HiddenFields
+}
+
+// SCIM lets an identity provider provision staff accounts with an API key granted scim:provision
+type SCIM struct {
+	// Groups are the roles exposed as SCIM groups; the IdP can only grant these
+	Groups     []string `json:"groups"`
+	MaxResults int      `json:"max_results"`
+}
+
+// ManagedGroups defaults to Super Admin and Courier
+func (s SCIM) ManagedGroups() []string {
+	if len(s.Groups) == 0 {
+		return []string{"Super Admin", "Courier"}
+	}
+	return s.Groups
+}
+
+// PageSize defaults to 100 resources per list response
+func (s SCIM) PageSize() int {
+	if s.MaxResults <= 0 {
+		return 100
+	}
+	return s.MaxResults
+}
+
+// SSO signs staff in to the admin panel through an OpenID Connect provider with the
+// authorization code flow; password sign-in keeps working alongside it
+type SSO struct {
+	Enabled      bool     `json:"enabled"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	RedirectURL  string   `json:"redirect_url"`
+	Scopes       []string `json:"scopes"`
+	// GroupsClaim names the ID token claim that lists the user's groups
+	GroupsClaim string `json:"groups_claim"`
+	// GroupRoles maps provider groups to roles; a user in several groups gets the first match
+	GroupRoles []SSOGroupRole `json:"group_roles"`
+	// JITProvisioning creates the staff account on its first sign-in
+	JITProvisioning bool `json:"jit_provisioning"`
+	StateTTLSeconds int  `json:"state_ttl_seconds"`
+}
+
+type SSOGroupRole struct {
+	Group string `json:"group"`
+	Role  string `json:"role"`
+}
+
+// RequestedScopes defaults to openid, email and profile; openid is always asked for
+func (s SSO) RequestedScopes() []string {
+	if len(s.Scopes) == 0 {
+		return []string{"openid", "email", "profile"}
+	}
+	for _, scope := range s.Scopes {
+		if scope == "openid" {
+			return s.Scopes
+		}
+	}
+	return append([]string{"openid"}, s.Scopes...)
+}
+
+// Groups defaults to the "groups" claim
+func (s SSO) Groups() string {
+	if s.GroupsClaim == "" {
+		return "groups"
+	}
+	return s.GroupsClaim
+}
+
+// StateTTL defaults to 10 minutes for the user to finish signing in at the provider
+func (s SSO) StateTTL() time.Duration {
+	if s.StateTTLSeconds <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(s.StateTTLSeconds) * time.Second
+}
+
+// Validate is only checked when SSO is enabled
+func (s SSO) Validate() error {
+	if !s.Enabled {
+		return nil
+	}
+	switch {
+	case s.Issuer == "":
+		return fmt.Errorf("SSO_ISSUER is required")
+	case s.ClientID == "":
+		return fmt.Errorf("SSO_CLIENT_ID is required")
+	case s.RedirectURL == "":
+		return fmt.Errorf("SSO_REDIRECT_URL is required")
+	case len(s.GroupRoles) == 0:
+		return fmt.Errorf("SSO_GROUP_ROLES must map at least one group to a role")
+	}
+	return nil
+}
+
+type Blacklist struct {
+	// Backend stores revoked token hashes: postgres (default) or redis
+	Backend string `json:"backend"`
+}
+
+type TokenLifetimes struct {
+	// Go durations such as "24h" or "90m"; empty keeps the built-in default
+	EmailVerification string `json:"email_verification"`
+	PasswordReset     string `json:"password_reset"`
+	EmailChange       string `json:"email_change"`
+	EmailChangeRevert string `json:"email_change_revert"`
+}
+
+const (
+	MinTokenLifetime = 5 * time.Minute
+	MaxTokenLifetime = 30 * 24 * time.Hour
+)
+
+// Validate reports the first lifetime that is not a duration or lies outside 5 minutes to 30 days
+func (t TokenLifetimes) Validate() error {
+	for _, item := range []struct{ env, value string }{
+		{"TOKEN_TTL_EMAIL_VERIFICATION", t.EmailVerification},
+		{"TOKEN_TTL_PASSWORD_RESET", t.PasswordReset},
+		{"TOKEN_TTL_EMAIL_CHANGE", t.EmailChange},
+		{"TOKEN_TTL_EMAIL_CHANGE_REVERT", t.EmailChangeRevert},
+	} {
+		if _, err := ParseTokenLifetime(item.value); err != nil {
+			return fmt.Errorf("%s: %w", item.env, err)
+		}
+	}
+	return nil
+}
+
+// ParseTokenLifetime returns 0 for an empty value, meaning the default applies
+func ParseTokenLifetime(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	lifetime, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	if lifetime < MinTokenLifetime || lifetime > MaxTokenLifetime {
+		return 0, fmt.Errorf("%s is outside %s to %s", lifetime, MinTokenLifetime, MaxTokenLifetime)
+	}
+	return lifetime, nil
+}
+
+type Sessions struct {
+	// Go durations for the JWT and its Redis session; empty keeps 12h, or 30 days with remember me
+	TTL           string `json:"ttl"`
+	RememberMeTTL string `json:"remember_me_ttl"`
+}
+
+const (
+	DefaultSessionTTL           = 12 * time.Hour
+	DefaultRememberMeSessionTTL = 30 * 24 * time.Hour
+	// Each kind of session has its own ceiling, so a typo cannot make a plain sign-in last a month
+	MaxSessionTTL           = 24 * time.Hour
+	MaxRememberMeSessionTTL = 90 * 24 * time.Hour
+	minSessionTTL           = 5 * time.Minute
+)
+
+// Validate rejects lifetimes outside their ceilings and a remember-me session shorter than a normal one
+func (s Sessions) Validate() error {
+	ttl, err := parseSessionTTL(s.TTL, DefaultSessionTTL, MaxSessionTTL)
+	if err != nil {
+		return fmt.Errorf("SESSION_TTL: %w", err)
+	}
+	rememberMeTTL, err := parseSessionTTL(s.RememberMeTTL, DefaultRememberMeSessionTTL, MaxRememberMeSessionTTL)
+	if err != nil {
+		return fmt.Errorf("SESSION_REMEMBER_ME_TTL: %w", err)
+	}
+	if rememberMeTTL < ttl {
+		return fmt.Errorf("SESSION_REMEMBER_ME_TTL %s is shorter than SESSION_TTL %s", rememberMeTTL, ttl)
+	}
+	return nil
+}
+
+// Lifetime is how long a session lasts, and its JWT stays valid, from sign-in or refresh.
+// Invalid values fall back to the defaults; RunServer refuses to start with them anyway.
+func (s Sessions) Lifetime(rememberMe bool) time.Duration {
+	if rememberMe {
+		ttl, err := parseSessionTTL(s.RememberMeTTL, DefaultRememberMeSessionTTL, MaxRememberMeSessionTTL)
+		if err != nil {
+			return DefaultRememberMeSessionTTL
+		}
+		return ttl
+	}
+	ttl, err := parseSessionTTL(s.TTL, DefaultSessionTTL, MaxSessionTTL)
+	if err != nil {
+		return DefaultSessionTTL
+	}
+	return ttl
+}
+
+func parseSessionTTL(value string, fallback, ceiling time.Duration) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return fallback, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+	if ttl < minSessionTTL || ttl > ceiling {
+		return 0, fmt.Errorf("%s is outside %s to %s", ttl, minSessionTTL, ceiling)
+	}
+	return ttl, nil
+}
+
+type PasswordHashing struct {
+	// Algorithm for new hashes: bcrypt (default) or argon2id. Hashes of either kind still verify
+	// and are redone with this algorithm at the user's next sign-in.
+	Algorithm  string `json:"algorithm"`
+	BcryptCost int    `json:"bcrypt_cost"`
+	// Argon2Memory in KiB; zero values keep 64 MiB, 3 iterations, parallelism 2
+	Argon2Memory      uint32 `json:"argon2_memory"`
+	Argon2Iterations  uint32 `json:"argon2_iterations"`
+	Argon2Parallelism uint8  `json:"argon2_parallelism"`
+}
+
+// Validate rejects an unknown algorithm and costs that are too weak or would stall sign-in
+func (p PasswordHashing) Validate() error {
+	switch strings.ToLower(strings.TrimSpace(p.Algorithm)) {
+	case "", "bcrypt", "argon2id":
+	default:
+		return fmt.Errorf("PASSWORD_HASH_ALGORITHM: unknown algorithm %q", p.Algorithm)
+	}
+	if p.BcryptCost != 0 && (p.BcryptCost < 10 || p.BcryptCost > 16) {
+		return fmt.Errorf("PASSWORD_BCRYPT_COST: %d is outside 10 to 16", p.BcryptCost)
+	}
+	if p.Argon2Memory != 0 && (p.Argon2Memory < 19*1024 || p.Argon2Memory > 1024*1024) {
+		return fmt.Errorf("PASSWORD_ARGON2_MEMORY: %d KiB is outside 19456 to 1048576", p.Argon2Memory)
+	}
+	if p.Argon2Iterations > 10 {
+		return fmt.Errorf("PASSWORD_ARGON2_ITERATIONS: %d is more than 10", p.Argon2Iterations)
+	}
+	if p.Argon2Parallelism > 16 {
+		return fmt.Errorf("PASSWORD_ARGON2_PARALLELISM: %d is more than 16", p.Argon2Parallelism)
+	}
+	return nil
+}
+
+// PasswordPolicy makes staff rotate their passwords. It only covers Roles; customers and vendors
+// are never made to rotate.
+type PasswordPolicy struct {
+	Roles []string `json:"roles"`
+	// MaxAgeDays is how long a password lasts; 0 turns expiry off
+	MaxAgeDays int `json:"max_age_days"`
+	// HistorySize is how many recent passwords, the current one included, cannot be reused;
+	// 0 turns the check off
+	HistorySize int `json:"history_size"`
+	WarnDays    int `json:"warn_days"`
+}
+
+// PolicyRoles defaults to Super Admin
+func (p PasswordPolicy) PolicyRoles() []string {
+	if len(p.Roles) == 0 {
+		return []string{"Super Admin"}
+	}
+	return p.Roles
+}
+
+// MaxAge is zero while expiry is off
+func (p PasswordPolicy) MaxAge() time.Duration {
+	return durationOr(p.MaxAgeDays, 24*time.Hour, 0)
+}
+
+// WarnBefore defaults to 7 days before the password expires
+func (p PasswordPolicy) WarnBefore() time.Duration {
+	return durationOr(p.WarnDays, 24*time.Hour, 7*24*time.Hour)
+}
+
+func (p PasswordPolicy) Validate() error {
+	switch {
+	case p.MaxAgeDays < 0:
+		return fmt.Errorf("PASSWORD_MAX_AGE_DAYS: %d is negative", p.MaxAgeDays)
+	case p.HistorySize < 0 || p.HistorySize > 24:
+		return fmt.Errorf("PASSWORD_HISTORY_SIZE: %d is outside 0 to 24", p.HistorySize)
+	case p.MaxAgeDays > 0 && p.WarnBefore() >= p.MaxAge():
+		return fmt.Errorf("PASSWORD_EXPIRY_WARN_DAYS must be less than PASSWORD_MAX_AGE_DAYS")
+	}
+	return nil
+}
+
+// PIIEncryption encrypts phone and address columns with AES-256-GCM before they reach the
+// database. Keys are 32 random bytes in base64, normally injected from a KMS or secret manager.
+type PIIEncryption struct {
+	// KeyID names the key new values are written with. Empty writes plaintext while values
+	// sealed with any listed key still decrypt.
+	KeyID string `json:"key_id"`
+	// Keys maps key ids to keys; a retired key stays listed until `pii backfill` has rewritten
+	// the rows that still name it
+	Keys map[string]string `json:"-"`
+}
+
+// DecodedKeys returns the raw keys by id
+func (p PIIEncryption) DecodedKeys() (map[string][]byte, error) {
+	keys := make(map[string][]byte, len(p.Keys))
+	for id, encoded := range p.Keys {
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("PII_ENCRYPTION_KEYS: key %q is not base64", id)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("PII_ENCRYPTION_KEYS: key %q is %d bytes, want 32", id, len(key))
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+func (p PIIEncryption) Validate() error {
+	if p.KeyID != "" {
+		if _, ok := p.Keys[p.KeyID]; !ok {
+			return fmt.Errorf("PII_ENCRYPTION_KEY_ID: no key %q in PII_ENCRYPTION_KEYS", p.KeyID)
+		}
+	}
+	_, err := p.DecodedKeys()
+	return err
+}
+
+type RateLimit struct {
+	// Mode is soft (default: headers and logs only), enforce (429 once the quota is used) or off
+	Mode          string `json:"mode"`
+	Requests      int    `json:"requests"`
+	WindowSeconds int    `json:"window_seconds"`
+	// PartnerQuotas are keyed by API key; their quotas are always enforced
+	PartnerQuotas        map[string]PartnerQuota `json:"-"`
+	PartnerWindowSeconds int                     `json:"partner_window_seconds"`
+	// PartnerDefaultQuota applies to issued API keys created without a quota of their own
+	PartnerDefaultQuota int `json:"partner_default_quota"`
+}
+
+// Diagnostics serves pprof, expvar and dump endpoints on their own listener, never on APP_PORT
+type Diagnostics struct {
+	Enabled bool   `json:"enabled"`
+	Addr    string `json:"addr"`
+	// Token is required as a bearer token when Addr is not a loopback address
+	Token   string `json:"-"`
+	DumpDir string `json:"dump_dir"`
+}
+
+// ListenAddr defaults to localhost only
+func (d Diagnostics) ListenAddr() string {
+	if strings.TrimSpace(d.Addr) == "" {
+		return "127.0.0.1:6060"
+	}
+	return d.Addr
+}
+
+// Validate refuses to expose profiles beyond localhost without a token
+func (d Diagnostics) Validate() error {
+	if !d.Enabled {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(d.ListenAddr())
+	if err != nil {
+		return fmt.Errorf("DIAGNOSTICS_ADDR: %w", err)
+	}
+	if ip := net.ParseIP(host); (ip == nil || !ip.IsLoopback()) && host != "localhost" && d.Token == "" {
+		return fmt.Errorf("DIAGNOSTICS_TOKEN is required when DIAGNOSTICS_ADDR %q is not a loopback address", d.ListenAddr())
+	}
+	return nil
+}
+
+type PartnerQuota struct {
+	Partner  string
+	Requests int
+}
+
+const (
+	RateLimitModeSoft    = "soft"
+	RateLimitModeEnforce = "enforce"
+	RateLimitModeOff     = "off"
+)
+
+// Validate rejects an unknown mode; a typo should not silently turn limits off
+func (r RateLimit) Validate() error {
+	switch r.Mode {
+	case "", RateLimitModeSoft, RateLimitModeEnforce, RateLimitModeOff:
+		return nil
+	}
+	return fmt.Errorf("RATE_LIMIT_MODE: unknown mode %q", r.Mode)
+}
+
+// Limit defaults to 300 requests per client IP per window
+func (r RateLimit) Limit() int {
+	if r.Requests <= 0 {
+		return 300
+	}
+	return r.Requests
+}
+
+// Window defaults to one minute
+func (r RateLimit) Window() time.Duration {
+	return durationOr(r.WindowSeconds, time.Second, time.Minute)
+}
+
+// DefaultPartnerQuota defaults to 10000 requests per partner window
+func (r RateLimit) DefaultPartnerQuota() int {
+	if r.PartnerDefaultQuota <= 0 {
+		return 10000
+	}
+	return r.PartnerDefaultQuota
+}
+
+// PartnerWindow defaults to a day, so partner quotas read as requests per day
+func (r RateLimit) PartnerWindow() time.Duration {
+	return durationOr(r.PartnerWindowSeconds, time.Second, 24*time.Hour)
+}
+
+type FileScan struct {
+	// ClamAVAddress of clamd ("host:3310" or "unix:///path/clamd.sock"); empty disables scanning
+	ClamAVAddress  string `json:"clamav_address"`
+	TimeoutSeconds int    `json:"timeout_seconds"`
+	// LogOnly records infected files and scanner errors but still stores the upload
+	LogOnly bool `json:"log_only"`
+}
+
+// Timeout defaults to 30s
+func (f FileScan) Timeout() time.Duration {
+	return durationOr(f.TimeoutSeconds, time.Second, 30*time.Second)
+}
+
+type Moderation struct {
+	// ProviderURL of the image moderation service; empty disables photo moderation
+	ProviderURL    string `json:"provider_url"`
+	APIKey         string `json:"api_key"`
+	TimeoutSeconds int    `json:"timeout_seconds"`
+	// Photos scoring at least FlagThreshold wait for admin review, at least RemoveThreshold are removed
+	FlagThreshold   float64 `json:"flag_threshold"`
+	RemoveThreshold float64 `json:"remove_threshold"`
+}
+
+// Timeout defaults to 15s
+func (m Moderation) Timeout() time.Duration {
+	return durationOr(m.TimeoutSeconds, time.Second, 15*time.Second)
+}
+
+type Campaign struct {
+	// BatchSize recipients are published per job; batches start BatchIntervalSeconds apart
+	BatchSize            int `json:"batch_size"`
+	BatchIntervalSeconds int `json:"batch_interval_seconds"`
+}
+
+// Batch defaults to 200 recipients per batch, one batch a minute
+func (c Campaign) Batch() (size int, interval time.Duration) {
+	size = c.BatchSize
+	if size <= 0 {
+		size = 200
+	}
+	return size, durationOr(c.BatchIntervalSeconds, time.Second, time.Minute)
+}
+
+type Assets struct {
+	// Bucket holds email images; it must be public so mail clients can load them
+	Bucket string `json:"bucket"`
+	// PublicBaseURL, e.g. a CDN in front of the bucket, replaces the storage URL when set
+	PublicBaseURL string `json:"public_base_url"`
+	MaxSizeKB     int    `json:"max_size_kb"`
+}
+
+// BucketName defaults to "assets"
+func (a Assets) BucketName() string {
+	if a.Bucket == "" {
+		return "assets"
+	}
+	return a.Bucket
+}
+
+// MaxSize defaults to 2 MB
+func (a Assets) MaxSize() int64 {
+	if a.MaxSizeKB <= 0 {
+		return 2 << 20
+	}
+	return int64(a.MaxSizeKB) << 10
+}
+
+type Registration struct {
+	// Mode is open (default) or invite, where signing up needs an invite code from /api/v1/admin/invites
+	Mode string `json:"mode"`
+}
+
+func (r Registration) InviteOnly() bool {
+	return r.Mode == "invite"
+}
+
+// Validate refuses a typo that would silently leave registration open
+func (r Registration) Validate() error {
+	switch r.Mode {
+	case "", "open", "invite":
+		return nil
+	}
+	return fmt.Errorf("REGISTRATION_MODE: unknown mode %q", r.Mode)
+}
+
+type Identity struct {
+	// Bucket holds ID documents; it must be private, admins get short-lived signed links
+	Bucket       string `json:"bucket"`
+	MaxSizeKB    int    `json:"max_size_kb"`
+	ValidityDays int    `json:"validity_days"`
+	// MinimumAge is the age restricted goods require
+	MinimumAge int `json:"minimum_age"`
+}
+
+// BucketName defaults to "identity-documents"
+func (i Identity) BucketName() string {
+	if i.Bucket == "" {
+		return "identity-documents"
+	}
+	return i.Bucket
+}
+
+// MaxSize defaults to 5 MB
+func (i Identity) MaxSize() int64 {
+	if i.MaxSizeKB <= 0 {
+		return 5 << 20
+	}
+	return int64(i.MaxSizeKB) << 10
+}
+
+// Validity defaults to a year, after which the user verifies again
+func (i Identity) Validity() time.Duration {
+	return durationOr(i.ValidityDays, 24*time.Hour, 365*24*time.Hour)
+}
+
+// MinAge defaults to 21
+func (i Identity) MinAge() int {
+	if i.MinimumAge <= 0 {
+		return 21
+	}
+	return i.MinimumAge
+}
+
+type Support struct {
+	// Bucket holds ticket attachments; it must be private, links are signed per view
+	Bucket         string `json:"bucket"`
+	MaxSizeKB      int    `json:"max_size_kb"`
+	MaxAttachments int    `json:"max_attachments"`
+}
+
+// BucketName defaults to "support-attachments"
+func (s Support) BucketName() string {
+	if s.Bucket == "" {
+		return "support-attachments"
+	}
+	return s.Bucket
+}
+
+// MaxSize is per attachment and defaults to 5 MB
+func (s Support) MaxSize() int64 {
+	if s.MaxSizeKB <= 0 {
+		return 5 << 20
+	}
+	return int64(s.MaxSizeKB) << 10
+}
+
+// AttachmentLimit defaults to 3 files per ticket
+func (s Support) AttachmentLimit() int {
+	if s.MaxAttachments <= 0 {
+		return 3
+	}
+	return s.MaxAttachments
+}
+
+// Thresholds defaults to flagging at 0.6 and removing at 0.9
+func (m Moderation) Thresholds() (flag, remove float64) {
+	flag, remove = m.FlagThreshold, m.RemoveThreshold
+	if flag <= 0 || flag > 1 {
+		flag = 0.6
+	}
+	if remove <= 0 || remove > 1 {
+		remove = 0.9
+	}
+	if remove < flag {
+		remove = flag
+	}
+	return flag, remove
 }
 
 type Config struct {
-	App      App      `json:"app"`
-	PsqlDB   PsqlDB   `json:"psql_db"`
-	Redis    RedisConfig `json:"redis"`
-	RabbitMQ RabbitMQ `json:"rabbitmq"`
-	Supabase Supabase `json:"supabase"`
+	App             App             `json:"app"`
+	PsqlDB          PsqlDB          `json:"psql_db"`
+	Redis           RedisConfig     `json:"redis"`
+	RabbitMQ        RabbitMQ        `json:"rabbitmq"`
+	Supabase        Supabase        `json:"supabase"`
+	CDN             CDN             `json:"cdn"`
+	Geocoder        Geocoder        `json:"geocoder"`
+	Ledger          Ledger          `json:"ledger"`
+	Worker          Worker          `json:"worker"`
+	Log             Log             `json:"log"`
+	HTTPLog         HTTPLog         `json:"http_log"`
+	AdminAccess     AdminAccess     `json:"admin_access"`
+	Security        Security        `json:"security"`
+	InternalAuth    InternalAuth    `json:"internal_auth"`
+	Risk            Risk            `json:"risk"`
+	Webhook         Webhook         `json:"webhook"`
+	Startup         Startup         `json:"startup"`
+	Timeouts        Timeouts        `json:"timeouts"`
+	QueryTrace      QueryTrace      `json:"query_trace"`
+	HTTPServer      HTTPServer      `json:"http_server"`
+	CircuitBreaker  CircuitBreaker  `json:"circuit_breaker"`
+	HTTPClient      HTTPClient      `json:"http_client"`
+	Email           Email           `json:"email"`
+	Upload          Upload          `json:"upload"`
+	CustomerSuggest CustomerSuggest `json:"customer_suggest"`
+	ResponsePolicy  ResponsePolicy  `json:"response_policy"`
+	SCIM            SCIM            `json:"scim"`
+	SSO             SSO             `json:"sso"`
+	FileScan        FileScan        `json:"file_scan"`
+	Moderation      Moderation      `json:"moderation"`
+	Blacklist       Blacklist       `json:"blacklist"`
+	TokenLifetimes  TokenLifetimes  `json:"token_lifetimes"`
+	Sessions        Sessions        `json:"sessions"`
+	PasswordHashing PasswordHashing `json:"password_hashing"`
+	PasswordPolicy  PasswordPolicy  `json:"password_policy"`
+	PIIEncryption   PIIEncryption   `json:"pii_encryption"`
+	RateLimit       RateLimit       `json:"rate_limit"`
+	Diagnostics     Diagnostics     `json:"diagnostics"`
+	Campaign        Campaign        `json:"campaign"`
+	Assets          Assets          `json:"assets"`
+	Registration    Registration    `json:"registration"`
+	Identity        Identity        `json:"identity"`
+	Support         Support         `json:"support"`
 }
 
 func NewConfig() *Config {
@@ -44,6 +1050,8 @@ func NewConfig() *Config {
 		App: App{
 			AppPort: viper.GetString("APP_PORT"),
 			AppEnv:  viper.GetString("APP_ENV"),
+			// Links are built as <base>/api/v1/..., so a trailing slash is dropped
+			PublicBaseURL: strings.TrimSuffix(viper.GetString("APP_PUBLIC_BASE_URL"), "/"),
 
 			JwtSecretKey: viper.GetString("JWT_SECRET_KEY"),
 			JwtIssuer:    viper.GetString("JWT_ISSUER"),
@@ -69,11 +1077,300 @@ func NewConfig() *Config {
 			User:     viper.GetString("RABBITMQ_USER"),
 			Password: viper.GetString("RABBITMQ_PASSWORD"),
 			VHost:    viper.GetString("RABBITMQ_VHOST"),
+			// Zero keeps the 5s default
+			ReconnectSeconds: viper.GetInt("RABBITMQ_RECONNECT_SECONDS"),
 		},
 		Supabase: Supabase{
-			ProjectURL: viper.GetString("SUPABASE_PROJECT_URL"),
-			APIKey:     viper.GetString("SUPABASE_API_KEY"),
-			BucketName: viper.GetString("SUPABASE_BUCKET_NAME"),
+			ProjectURL:    viper.GetString("SUPABASE_PROJECT_URL"),
+			APIKey:        viper.GetString("SUPABASE_API_KEY"),
+			BucketName:    viper.GetString("SUPABASE_BUCKET_NAME"),
+			WebhookSecret: viper.GetString("SUPABASE_WEBHOOK_SECRET"),
+			MaxUploadMB:   viper.GetInt("SUPABASE_MAX_UPLOAD_MB"),
+		},
+		CDN: CDN{
+			BaseURL:    strings.TrimSuffix(viper.GetString("CDN_BASE_URL"), "/"),
+			PurgeURL:   viper.GetString("CDN_PURGE_URL"),
+			PurgeToken: viper.GetString("CDN_PURGE_TOKEN"),
+		},
+		Geocoder: Geocoder{
+			Provider:  viper.GetString("GEOCODER_PROVIDER"),
+			BaseURL:   viper.GetString("GEOCODER_BASE_URL"),
+			APIKey:    viper.GetString("GEOCODER_API_KEY"),
+			UserAgent: viper.GetString("GEOCODER_USER_AGENT"),
+		},
+		Ledger: Ledger{
+			CommissionRateBps:   viper.GetInt64("LEDGER_COMMISSION_RATE_BPS"),
+			MinWithdrawalAmount: viper.GetInt64("LEDGER_MIN_WITHDRAWAL_AMOUNT"),
+		},
+		Worker: Worker{
+			Concurrency:         viper.GetInt("WORKER_CONCURRENCY"),
+			PollIntervalSeconds: viper.GetInt("WORKER_POLL_INTERVAL_SECONDS"),
+		},
+		Log: Log{
+			Level:  viper.GetString("LOG_LEVEL"),
+			Format: viper.GetString("LOG_FORMAT"),
+		},
+		HTTPLog: HTTPLog{
+			BodySampleRate: viper.GetFloat64("HTTP_LOG_BODY_SAMPLE_RATE"),
+			BodyMaxBytes:   viper.GetInt("HTTP_LOG_BODY_MAX_BYTES"),
+		},
+		AdminAccess: AdminAccess{
+			IPAllowlist:    splitList(viper.GetString("ADMIN_IP_ALLOWLIST")),
+			TrustedProxies: splitList(viper.GetString("TRUSTED_PROXIES")),
+		},
+		Security: Security{
+			HSTSMaxAge:        viper.GetInt("SECURITY_HSTS_MAX_AGE"),
+			FrameOptions:      viper.GetString("SECURITY_FRAME_OPTIONS"),
+			ReferrerPolicy:    viper.GetString("SECURITY_REFERRER_POLICY"),
+			CSRFEnabled:       viper.GetBool("CSRF_ENABLED"),
+			SessionCookieName: viper.GetString("SESSION_COOKIE_NAME"),
+			CookieSecure:      viper.GetString("APP_ENV") == "production" || viper.GetBool("COOKIE_SECURE"),
+
+			SessionCookieEnabled:  viper.GetBool("SESSION_COOKIE_ENABLED"),
+			SessionCookieSameSite: viper.GetString("SESSION_COOKIE_SAMESITE"),
+			SessionCookieDomain:   viper.GetString("SESSION_COOKIE_DOMAIN"),
+			CORSAllowOrigins:      splitList(viper.GetString("CORS_ALLOW_ORIGINS")),
+		},
+		InternalAuth: InternalAuth{
+			ServiceKeys: parseGroupedList(viper.GetString("SERVICE_API_KEYS")),
+		},
+		Risk: Risk{
+			StepUpEnabled:         viper.GetBool("RISK_STEP_UP_ENABLED"),
+			StepUpThreshold:       viper.GetInt("RISK_STEP_UP_THRESHOLD"),
+			FailedSignInWindowMin: viper.GetInt("RISK_FAILED_SIGNIN_WINDOW_MINUTES"),
+			MaxTravelSpeedKmh:     viper.GetInt("RISK_MAX_TRAVEL_SPEED_KMH"),
+			OTPTTLMinutes:         viper.GetInt("RISK_OTP_TTL_MINUTES"),
+			OTPMaxAttempts:        viper.GetInt("RISK_OTP_MAX_ATTEMPTS"),
+			GeoIPBaseURL:          viper.GetString("GEOIP_BASE_URL"),
+		},
+		Webhook: Webhook{
+			TimeoutSeconds: viper.GetInt("WEBHOOK_TIMEOUT_SECONDS"),
 		},
+		Startup: Startup{
+			TimeoutSeconds:    viper.GetInt("STARTUP_TIMEOUT_SECONDS"),
+			InitialBackoffMs:  viper.GetInt("STARTUP_INITIAL_BACKOFF_MS"),
+			MaxBackoffSeconds: viper.GetInt("STARTUP_MAX_BACKOFF_SECONDS"),
+		},
+		HTTPServer: HTTPServer{
+			ReadTimeoutSeconds:       viper.GetInt("HTTP_READ_TIMEOUT_SECONDS"),
+			ReadHeaderTimeoutSeconds: viper.GetInt("HTTP_READ_HEADER_TIMEOUT_SECONDS"),
+			WriteTimeoutSeconds:      viper.GetInt("HTTP_WRITE_TIMEOUT_SECONDS"),
+			IdleTimeoutSeconds:       viper.GetInt("HTTP_IDLE_TIMEOUT_SECONDS"),
+			MaxHeaderKB:              viper.GetInt("HTTP_MAX_HEADER_KB"),
+			BodyLimitKB:              viper.GetInt("HTTP_BODY_LIMIT_KB"),
+			UploadBodyLimitMB:        viper.GetInt("HTTP_UPLOAD_BODY_LIMIT_MB"),
+		},
+		Timeouts: Timeouts{
+			RequestSeconds: viper.GetInt("REQUEST_TIMEOUT_SECONDS"),
+			DBMs:           viper.GetInt("DB_TIMEOUT_MS"),
+			StorageSeconds: viper.GetInt("STORAGE_TIMEOUT_SECONDS"),
+			RedisMs:        viper.GetInt("REDIS_TIMEOUT_MS"),
+		},
+		QueryTrace: QueryTrace{
+			Comments:    viper.GetString("DB_QUERY_COMMENTS"),
+			WarnQueries: viper.GetInt("DB_QUERY_WARN_PER_REQUEST"),
+		},
+		CircuitBreaker: CircuitBreaker{
+			FailureThreshold: viper.GetInt("CIRCUIT_BREAKER_FAILURE_THRESHOLD"),
+			OpenSeconds:      viper.GetInt("CIRCUIT_BREAKER_OPEN_SECONDS"),
+			HalfOpenRequests: viper.GetInt("CIRCUIT_BREAKER_HALF_OPEN_REQUESTS"),
+		},
+		HTTPClient: HTTPClient{
+			MaxRetries:  viper.GetInt("HTTP_CLIENT_MAX_RETRIES"),
+			RetryBaseMs: viper.GetInt("HTTP_CLIENT_RETRY_BASE_MS"),
+			RetryMaxMs:  viper.GetInt("HTTP_CLIENT_RETRY_MAX_MS"),
+		},
+		Email: Email{
+			PlusAddressing: viper.GetString("EMAIL_PLUS_ADDRESSING"),
+			// Blocking is on unless explicitly disabled
+			BlockDisposable:   !viper.IsSet("EMAIL_BLOCK_DISPOSABLE") || viper.GetBool("EMAIL_BLOCK_DISPOSABLE"),
+			DisposableDomains: splitList(viper.GetString("EMAIL_DISPOSABLE_DOMAINS")),
+		},
+		Upload: Upload{
+			Dir:         viper.GetString("UPLOAD_DIR"),
+			ChunkSizeKB: viper.GetInt("UPLOAD_CHUNK_SIZE_KB"),
+			MaxSizeMB:   viper.GetInt("UPLOAD_MAX_SIZE_MB"),
+			TTLHours:    viper.GetInt("UPLOAD_TTL_HOURS"),
+		},
+		CustomerSuggest: CustomerSuggest{
+			TimeoutMs:       viper.GetInt("CUSTOMER_SUGGEST_TIMEOUT_MS"),
+			CacheSize:       viper.GetInt("CUSTOMER_SUGGEST_CACHE_SIZE"),
+			CacheTTLSeconds: viper.GetInt("CUSTOMER_SUGGEST_CACHE_TTL_SECONDS"),
+		},
+		ResponsePolicy: ResponsePolicy{
+			HiddenFields: parseGroupedList(viper.GetString("RESPONSE_HIDDEN_FIELDS")),
+		},
+		SSO: SSO{
+			Enabled:         viper.GetBool("SSO_ENABLED"),
+			Issuer:          strings.TrimSuffix(viper.GetString("SSO_ISSUER"), "/"),
+			ClientID:        viper.GetString("SSO_CLIENT_ID"),
+			ClientSecret:    viper.GetString("SSO_CLIENT_SECRET"),
+			RedirectURL:     viper.GetString("SSO_REDIRECT_URL"),
+			Scopes:          strings.Fields(viper.GetString("SSO_SCOPES")),
+			GroupsClaim:     viper.GetString("SSO_GROUPS_CLAIM"),
+			GroupRoles:      parseSSOGroupRoles(viper.GetString("SSO_GROUP_ROLES")),
+			JITProvisioning: viper.GetBool("SSO_JIT_PROVISIONING"),
+			StateTTLSeconds: viper.GetInt("SSO_STATE_TTL_SECONDS"),
+		},
+		SCIM: SCIM{
+			Groups:     splitList(viper.GetString("SCIM_GROUPS")),
+			MaxResults: viper.GetInt("SCIM_MAX_RESULTS"),
+		},
+		FileScan: FileScan{
+			ClamAVAddress:  viper.GetString("CLAMAV_ADDRESS"),
+			TimeoutSeconds: viper.GetInt("FILE_SCAN_TIMEOUT_SECONDS"),
+			LogOnly:        viper.GetBool("FILE_SCAN_LOG_ONLY"),
+		},
+		Moderation: Moderation{
+			ProviderURL:     viper.GetString("MODERATION_PROVIDER_URL"),
+			APIKey:          viper.GetString("MODERATION_API_KEY"),
+			TimeoutSeconds:  viper.GetInt("MODERATION_TIMEOUT_SECONDS"),
+			FlagThreshold:   viper.GetFloat64("MODERATION_FLAG_THRESHOLD"),
+			RemoveThreshold: viper.GetFloat64("MODERATION_REMOVE_THRESHOLD"),
+		},
+		Blacklist: Blacklist{
+			Backend: viper.GetString("BLACKLIST_BACKEND"),
+		},
+		TokenLifetimes: TokenLifetimes{
+			EmailVerification: viper.GetString("TOKEN_TTL_EMAIL_VERIFICATION"),
+			PasswordReset:     viper.GetString("TOKEN_TTL_PASSWORD_RESET"),
+			EmailChange:       viper.GetString("TOKEN_TTL_EMAIL_CHANGE"),
+			EmailChangeRevert: viper.GetString("TOKEN_TTL_EMAIL_CHANGE_REVERT"),
+		},
+		Sessions: Sessions{
+			TTL:           viper.GetString("SESSION_TTL"),
+			RememberMeTTL: viper.GetString("SESSION_REMEMBER_ME_TTL"),
+		},
+		PasswordHashing: PasswordHashing{
+			Algorithm:         viper.GetString("PASSWORD_HASH_ALGORITHM"),
+			BcryptCost:        viper.GetInt("PASSWORD_BCRYPT_COST"),
+			Argon2Memory:      viper.GetUint32("PASSWORD_ARGON2_MEMORY"),
+			Argon2Iterations:  viper.GetUint32("PASSWORD_ARGON2_ITERATIONS"),
+			Argon2Parallelism: uint8(viper.GetUint("PASSWORD_ARGON2_PARALLELISM")),
+		},
+		PasswordPolicy: PasswordPolicy{
+			Roles:       splitList(viper.GetString("PASSWORD_POLICY_ROLES")),
+			MaxAgeDays:  viper.GetInt("PASSWORD_MAX_AGE_DAYS"),
+			HistorySize: viper.GetInt("PASSWORD_HISTORY_SIZE"),
+			WarnDays:    viper.GetInt("PASSWORD_EXPIRY_WARN_DAYS"),
+		},
+		PIIEncryption: PIIEncryption{
+			KeyID: strings.TrimSpace(viper.GetString("PII_ENCRYPTION_KEY_ID")),
+			Keys:  parsePIIKeys(viper.GetString("PII_ENCRYPTION_KEYS")),
+		},
+		Diagnostics: Diagnostics{
+			Enabled: viper.GetBool("DIAGNOSTICS_ENABLED"),
+			Addr:    viper.GetString("DIAGNOSTICS_ADDR"),
+			Token:   viper.GetString("DIAGNOSTICS_TOKEN"),
+			DumpDir: viper.GetString("DIAGNOSTICS_DUMP_DIR"),
+		},
+		RateLimit: RateLimit{
+			Mode:                 strings.ToLower(strings.TrimSpace(viper.GetString("RATE_LIMIT_MODE"))),
+			Requests:             viper.GetInt("RATE_LIMIT_REQUESTS"),
+			WindowSeconds:        viper.GetInt("RATE_LIMIT_WINDOW_SECONDS"),
+			PartnerQuotas:        parsePartnerQuotas(viper.GetString("PARTNER_API_KEYS")),
+			PartnerWindowSeconds: viper.GetInt("PARTNER_QUOTA_WINDOW_SECONDS"),
+			PartnerDefaultQuota:  viper.GetInt("PARTNER_DEFAULT_QUOTA"),
+		},
+		Campaign: Campaign{
+			BatchSize:            viper.GetInt("CAMPAIGN_BATCH_SIZE"),
+			BatchIntervalSeconds: viper.GetInt("CAMPAIGN_BATCH_INTERVAL_SECONDS"),
+		},
+		Assets: Assets{
+			Bucket:        viper.GetString("ASSETS_BUCKET_NAME"),
+			PublicBaseURL: strings.TrimSuffix(viper.GetString("ASSETS_PUBLIC_BASE_URL"), "/"),
+			MaxSizeKB:     viper.GetInt("ASSETS_MAX_SIZE_KB"),
+		},
+		Registration: Registration{
+			Mode: strings.ToLower(strings.TrimSpace(viper.GetString("REGISTRATION_MODE"))),
+		},
+		Identity: Identity{
+			Bucket:       viper.GetString("IDENTITY_BUCKET_NAME"),
+			MaxSizeKB:    viper.GetInt("IDENTITY_MAX_SIZE_KB"),
+			ValidityDays: viper.GetInt("IDENTITY_VALIDITY_DAYS"),
+			MinimumAge:   viper.GetInt("IDENTITY_MINIMUM_AGE"),
+		},
+		Support: Support{
+			Bucket:         viper.GetString("SUPPORT_BUCKET_NAME"),
+			MaxSizeKB:      viper.GetInt("SUPPORT_MAX_SIZE_KB"),
+			MaxAttachments: viper.GetInt("SUPPORT_MAX_ATTACHMENTS"),
+		},
+	}
+}
+
+// parseGroupedList reads "a:x,a:y,b:z" into {a: [x y], b: [z]}, e.g. SERVICE_API_KEYS as
+// "order-service:key1,order-service:key2,delivery-service:key3"
+func parseGroupedList(value string) map[string][]string {
+	keys := make(map[string][]string)
+	for _, item := range splitList(value) {
+		name, key, ok := strings.Cut(item, ":")
+		name, key = strings.TrimSpace(name), strings.TrimSpace(key)
+		if !ok || name == "" || key == "" {
+			continue
+		}
+		keys[name] = append(keys[name], key)
+	}
+	return keys
+}
+
+// parseSSOGroupRoles reads "group:role" pairs and keeps their order, which is their precedence.
+// Groups may contain colons (e.g. URNs), so the role is what follows the last one.
+func parseSSOGroupRoles(value string) []SSOGroupRole {
+	var mappings []SSOGroupRole
+	for _, item := range splitList(value) {
+		at := strings.LastIndex(item, ":")
+		if at < 0 {
+			continue
+		}
+		group, role := strings.TrimSpace(item[:at]), strings.TrimSpace(item[at+1:])
+		if group == "" || role == "" {
+			continue
+		}
+		mappings = append(mappings, SSOGroupRole{Group: group, Role: role})
+	}
+	return mappings
+}
+
+// parsePartnerQuotas reads "acme:10000:key1,acme:10000:key2,globex:500:key3"; keys of one
+// partner share its quota
+func parsePartnerQuotas(value string) map[string]PartnerQuota {
+	quotas := make(map[string]PartnerQuota)
+	for _, item := range splitList(value) {
+		parts := strings.SplitN(item, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		partner, key := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[2])
+		requests, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if partner == "" || key == "" || err != nil || requests <= 0 {
+			continue
+		}
+		quotas[key] = PartnerQuota{Partner: partner, Requests: requests}
+	}
+	return quotas
+}
+
+// parsePIIKeys reads "2026-01:<base64 key>,2025-06:<base64 key>" into keys by id
+func parsePIIKeys(value string) map[string]string {
+	keys := make(map[string]string)
+	for _, item := range splitList(value) {
+		id, key, ok := strings.Cut(item, ":")
+		id, key = strings.TrimSpace(id), strings.TrimSpace(key)
+		if !ok || id == "" || key == "" {
+			continue
+		}
+		keys[id] = key
+	}
+	return keys
+}
+
+// splitList parses a comma-separated env value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
+	return items
 }
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 	"user-service/database/seeds"
 
 	"github.com/rs/zerolog/log"
@@ -14,13 +15,17 @@ type Postgres struct {
 }
 
 func (cfg Config) ConnectionPostgres() (*Postgres, error) {
-	dbConnString := fmt.Sprintf("postgres://%s:%s@%s:%s/%s",
+	// Columns are TIMESTAMP without zone, so the session and GORM both work in UTC;
+	// otherwise a server outside UTC would store wall-clock times read back as UTC
+	dbConnString := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?connect_timeout=5&timezone=UTC",
 		cfg.PsqlDB.User,
 		cfg.PsqlDB.Password,
 		cfg.PsqlDB.Host,
 		cfg.PsqlDB.Port,
 		cfg.PsqlDB.DBName)
-	db, err := gorm.Open(postgres.Open(dbConnString), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dbConnString), &gorm.Config{
+		NowFunc: func() time.Time { return time.Now().UTC() },
+	})
 	if err != nil {
 		log.Error().Err(err).Msg("[ConnectionPostgres-1] Failed to connect to database " + cfg.PsqlDB.Host)
 		return nil, err
@@ -13,6 +13,8 @@ type RabbitMQ struct {
 	User     string `json:"user"`
 	Password string `json:"password"`
 	VHost    string `json:"vhost"`
+	// ReconnectSeconds is how often a lost connection is retried; emails are buffered meanwhile
+	ReconnectSeconds int `json:"reconnect_seconds"`
 }
 
 func (cfg Config) ConnectionRabbitMQ() (*amqp.Channel, error) {
@@ -15,13 +15,51 @@ type RedisConfig struct {
 }
 
 func (c *Config) RedisClient() *redis.Client {
-	return redis.NewClient(&redis.Options{
+	client := redis.NewClient(&redis.Options{
 		Addr:        c.Redis.Host + ":" + c.Redis.Port,
 		Password:    c.Redis.Password,
 		DB:          c.Redis.DB,
 		DialTimeout: 5 * time.Second,
 		ReadTimeout: 3 * time.Second,
 	})
+	client.AddHook(redisTimeoutHook{timeout: c.Timeouts.Redis()})
+	return client
+}
+
+type redisCancelKey struct{}
+
+// redisTimeoutHook gives every command (or pipeline) its own deadline, so a stalled
+// Redis fails fast instead of holding the request until the read timeout
+type redisTimeoutHook struct {
+	timeout time.Duration
+}
+
+func (h redisTimeoutHook) withDeadline(ctx context.Context) (context.Context, error) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	return context.WithValue(ctx, redisCancelKey{}, cancel), nil
+}
+
+func (h redisTimeoutHook) release(ctx context.Context) error {
+	if cancel, ok := ctx.Value(redisCancelKey{}).(context.CancelFunc); ok {
+		cancel()
+	}
+	return nil
+}
+
+func (h redisTimeoutHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	return h.withDeadline(ctx)
+}
+
+func (h redisTimeoutHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	return h.release(ctx)
+}
+
+func (h redisTimeoutHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return h.withDeadline(ctx)
+}
+
+func (h redisTimeoutHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	return h.release(ctx)
 }
 
 // TestRedisConnection tests the Redis connection
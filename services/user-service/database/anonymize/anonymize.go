@@ -0,0 +1,236 @@
+package anonymize
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// TableReport is how many rows of a table were rewritten or deleted
+type TableReport struct {
+	Table string
+	Rows  int64
+}
+
+type Options struct {
+	// PasswordHash replaces every password, so testers can sign in to any account
+	PasswordHash string
+	BatchSize    int
+}
+
+type userRow struct {
+	ID       int64
+	Email    string
+	Username *string
+	Phone    *string
+	Address  *string
+	Lat      *float64
+	Lng      *float64
+}
+
+type vendorRow struct {
+	ID      int64
+	Phone   *string
+	Address *string
+	Lat     *float64
+	Lng     *float64
+}
+
+type redemptionRow struct {
+	ID    int64
+	Email string
+}
+
+// Run rewrites personal data in one transaction, so a failure leaves the dump untouched rather
+// than half anonymized. Row IDs and foreign keys are not changed.
+func Run(ctx context.Context, db *gorm.DB, faker *Faker, opts Options) ([]TableReport, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+
+	var report []TableReport
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		emails, rows, err := anonymizeUsers(tx, faker, opts)
+		if err != nil {
+			return fmt.Errorf("users: %w", err)
+		}
+		report = append(report, TableReport{Table: "users", Rows: rows})
+
+		rows, err = anonymizeVendors(tx, faker, opts.BatchSize)
+		if err != nil {
+			return fmt.Errorf("vendors: %w", err)
+		}
+		report = append(report, TableReport{Table: "vendors", Rows: rows})
+
+		rows, err = anonymizeRedemptions(tx, faker, emails)
+		if err != nil {
+			return fmt.Errorf("invite_redemptions: %w", err)
+		}
+		report = append(report, TableReport{Table: "invite_redemptions", Rows: rows})
+
+		for _, statement := range statements {
+			result := tx.Exec(statement.sql)
+			if result.Error != nil {
+				return fmt.Errorf("%s: %w", statement.table, result.Error)
+			}
+			report = append(report, TableReport{Table: statement.table, Rows: result.RowsAffected})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// statements run after users and vendors, so joins to users already see the fake names
+var statements = []struct {
+	table string
+	sql   string
+}{
+	// The account holder is the vendor's owner, so it takes the owner's fake name
+	{"vendor_withdrawals", `
+		UPDATE vendor_withdrawals w
+		SET account_name = u.name, account_number = lpad(((w.id * 7919 + 104729) % 10000000000)::text, 10, '0')
+		FROM vendors v JOIN users u ON u.id = v.user_id
+		WHERE v.id = w.vendor_id`},
+	// The files stay in the production buckets; staging must not point at them
+	{"vendor_documents", `UPDATE vendor_documents SET file_url = '', object_name = 'anonymized/' || id`},
+	{"identity_verifications", `
+		UPDATE identity_verifications
+		SET object_name = 'anonymized/' || id, date_of_birth = date_trunc('year', date_of_birth)::date`},
+	// Pending email changes hold the new address, and the links are useless outside production
+	{"verification_tokens", `DELETE FROM verification_tokens`},
+	{"user_devices", `UPDATE user_devices SET last_ip = NULL, user_agent = NULL`},
+	{"user_consents", `UPDATE user_consents SET ip_address = '', user_agent = ''`},
+	{"audit_logs", `
+		UPDATE audit_logs
+		SET ip_address = NULL, user_agent = NULL,
+			metadata = metadata - ARRAY['email', 'old_email', 'new_email', 'phone', 'ip', 'ip_address', 'name']::text[]`},
+	// Staging must not call partner endpoints, and past payloads carry customer data
+	{"webhook_deliveries", `DELETE FROM webhook_deliveries`},
+	{"webhook_endpoints", `UPDATE webhook_endpoints SET is_active = FALSE`},
+}
+
+// anonymizeUsers returns the original to fake email mapping, so other tables that store an
+// address keep pointing at the same person
+func anonymizeUsers(tx *gorm.DB, faker *Faker, opts Options) (map[string]string, int64, error) {
+	emails := make(map[string]string)
+	var (
+		total  int64
+		lastID int64
+	)
+
+	for {
+		var users []userRow
+		// Raw SQL includes soft-deleted users, whose rows still hold personal data
+		err := tx.Raw(`SELECT id, email, username, phone, address, lat, lng FROM users WHERE id > ? ORDER BY id LIMIT ?`, lastID, opts.BatchSize).Scan(&users).Error
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(users) == 0 {
+			return emails, total, nil
+		}
+
+		for _, user := range users {
+			key := fmt.Sprintf("user:%d", user.ID)
+			email := faker.Email(key)
+			emails[user.Email] = email
+
+			updates := map[string]interface{}{
+				"name":                faker.Name(key),
+				"email":               email,
+				"photo":               nil,
+				"photo_original":      "",
+				"email_bounce_reason": "",
+			}
+			if opts.PasswordHash != "" {
+				updates["password"] = opts.PasswordHash
+			}
+			if user.Username != nil {
+				updates["username"] = faker.Username(key)
+			}
+			if user.Phone != nil && *user.Phone != "" {
+				updates["phone"] = faker.Phone(key)
+			}
+			if user.Address != nil && *user.Address != "" {
+				updates["address"] = faker.Address(key)
+			}
+			if user.Lat != nil && user.Lng != nil {
+				updates["lat"], updates["lng"] = faker.Jitter(key, *user.Lat, *user.Lng)
+			}
+
+			if err := tx.Table("users").Where("id = ?", user.ID).UpdateColumns(updates).Error; err != nil {
+				return nil, 0, err
+			}
+		}
+
+		total += int64(len(users))
+		lastID = users[len(users)-1].ID
+		log.Printf("users: %d anonymized", total)
+	}
+}
+
+func anonymizeVendors(tx *gorm.DB, faker *Faker, batchSize int) (int64, error) {
+	var (
+		total  int64
+		lastID int64
+	)
+
+	for {
+		var vendors []vendorRow
+		err := tx.Raw(`SELECT id, phone, address, lat, lng FROM vendors WHERE id > ? ORDER BY id LIMIT ?`, lastID, batchSize).Scan(&vendors).Error
+		if err != nil {
+			return 0, err
+		}
+		if len(vendors) == 0 {
+			return total, nil
+		}
+
+		for _, vendor := range vendors {
+			key := fmt.Sprintf("vendor:%d", vendor.ID)
+			updates := map[string]interface{}{}
+			if vendor.Phone != nil && *vendor.Phone != "" {
+				updates["phone"] = faker.Phone(key)
+			}
+			if vendor.Address != nil && *vendor.Address != "" {
+				updates["address"] = faker.Address(key)
+			}
+			if vendor.Lat != nil && vendor.Lng != nil {
+				updates["lat"], updates["lng"] = faker.Jitter(key, *vendor.Lat, *vendor.Lng)
+			}
+			if len(updates) == 0 {
+				continue
+			}
+
+			if err := tx.Table("vendors").Where("id = ?", vendor.ID).UpdateColumns(updates).Error; err != nil {
+				return 0, err
+			}
+		}
+
+		total += int64(len(vendors))
+		lastID = vendors[len(vendors)-1].ID
+	}
+}
+
+// anonymizeRedemptions gives a redemption its user's new email, or a fake one when the
+// sign-up never completed
+func anonymizeRedemptions(tx *gorm.DB, faker *Faker, emails map[string]string) (int64, error) {
+	var redemptions []redemptionRow
+	if err := tx.Raw(`SELECT id, email FROM invite_redemptions`).Scan(&redemptions).Error; err != nil {
+		return 0, err
+	}
+
+	for _, redemption := range redemptions {
+		email, ok := emails[redemption.Email]
+		if !ok {
+			email = faker.Email(fmt.Sprintf("redemption:%d", redemption.ID))
+		}
+		if err := tx.Exec(`UPDATE invite_redemptions SET email = ? WHERE id = ?`, email, redemption.ID).Error; err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(redemptions)), nil
+}
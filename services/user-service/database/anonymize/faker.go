@@ -0,0 +1,114 @@
+package anonymize
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+var firstNames = []string{
+	"Adi", "Agus", "Ahmad", "Andi", "Anisa", "Ayu", "Bayu", "Bambang", "Budi", "Citra",
+	"Dewi", "Dian", "Dimas", "Eka", "Fajar", "Fitri", "Gilang", "Hendra", "Indah", "Intan",
+	"Joko", "Kartika", "Lestari", "Maya", "Nur", "Putri", "Rahmat", "Rina", "Rizky", "Sari",
+	"Siti", "Sri", "Teguh", "Tri", "Wahyu", "Wulan", "Yoga", "Yuni",
+}
+
+var lastNames = []string{
+	"Hidayat", "Kurniawan", "Lestari", "Nugroho", "Pratama", "Putra", "Rahayu", "Saputra",
+	"Setiawan", "Simanjuntak", "Siregar", "Susanto", "Wibowo", "Wijaya", "Permana", "Utami",
+	"Hasibuan", "Nasution", "Gunawan", "Halim", "Santoso", "Maharani", "Firmansyah", "Anggraini",
+}
+
+var streets = []string{
+	"Melati", "Mawar", "Kenanga", "Anggrek", "Flamboyan", "Cempaka", "Merdeka", "Sudirman",
+	"Diponegoro", "Gatot Subroto", "Ahmad Yani", "Pahlawan", "Kebon Jeruk", "Pasar Baru",
+	"Raya Bogor", "Cendana", "Dahlia", "Teratai", "Kartini", "Veteran",
+}
+
+// fakeEmailDomain is reserved for documentation, so staging can never mail a real person
+const fakeEmailDomain = "example.com"
+
+// maxJitterDegrees moves coordinates by up to roughly 500 m, enough to hide a house while
+// keeping customers inside the same delivery zone
+const maxJitterDegrees = 0.0045
+
+// Faker derives fake values from a seed and a stable key such as "user:42". The same key always
+// gives the same value, so a person's name, email and withdrawal account name stay consistent
+// across tables. Without the seed the originals cannot be linked back to the fakes.
+type Faker struct {
+	seed []byte
+}
+
+func NewFaker(seed []byte) *Faker {
+	return &Faker{seed: seed}
+}
+
+func (f *Faker) Name(key string) string {
+	first, last := f.person(key)
+	return first + " " + last
+}
+
+// Email is unique per key, e.g. "sari.wijaya.u42@example.com", since users.email is unique
+func (f *Faker) Email(key string) string {
+	first, last := f.person(key)
+	return fmt.Sprintf("%s.%s.%s@%s", slug(first), slug(last), slug(key), fakeEmailDomain)
+}
+
+// Username is unique per key and fits the 30 character column
+func (f *Faker) Username(key string) string {
+	first, _ := f.person(key)
+	return fmt.Sprintf("%s_%s", slug(first), slug(key))
+}
+
+// Phone is an Indonesian mobile number, e.g. "+6281234567890"
+func (f *Faker) Phone(key string) string {
+	return fmt.Sprintf("+628%010d", f.number(key, "phone")%10000000000)
+}
+
+// Address is a street line only; city, district and postal code are kept, since zones,
+// segments and reports group by them
+func (f *Faker) Address(key string) string {
+	street := streets[f.number(key, "street")%uint64(len(streets))]
+	return fmt.Sprintf("Jl. %s No. %d", street, f.number(key, "house")%200+1)
+}
+
+// Jitter moves a coordinate pair by up to maxJitterDegrees on each axis
+func (f *Faker) Jitter(key string, lat, lng float64) (float64, float64) {
+	return lat + f.offset(key, "lat"), lng + f.offset(key, "lng")
+}
+
+func (f *Faker) person(key string) (string, string) {
+	return firstNames[f.number(key, "first")%uint64(len(firstNames))], lastNames[f.number(key, "last")%uint64(len(lastNames))]
+}
+
+func (f *Faker) offset(key, field string) float64 {
+	// Map the hash onto [-1, 1)
+	unit := float64(f.number(key, field)%2000000)/1000000 - 1
+	return unit * maxJitterDegrees
+}
+
+func (f *Faker) number(key, field string) uint64 {
+	h := sha256.New()
+	h.Write(f.seed)
+	h.Write([]byte{0})
+	h.Write([]byte(field))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return binary.BigEndian.Uint64(h.Sum(nil)[:8])
+}
+
+// slug keeps letters and digits, so "user:42" becomes "u42" and "Gatot Subroto" "gatotsubroto"
+func slug(s string) string {
+	if prefix, id, ok := strings.Cut(s, ":"); ok && prefix != "" {
+		s = prefix[:1] + id
+	}
+
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_users_city;
+
+ALTER TABLE users DROP COLUMN postal_code;
+ALTER TABLE users DROP COLUMN district;
+ALTER TABLE users DROP COLUMN city;
+ALTER TABLE users DROP COLUMN province;
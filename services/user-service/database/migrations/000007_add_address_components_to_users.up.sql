@@ -0,0 +1,6 @@
+ALTER TABLE users ADD COLUMN province VARCHAR(100) NULL;
+ALTER TABLE users ADD COLUMN city VARCHAR(100) NULL;
+ALTER TABLE users ADD COLUMN district VARCHAR(100) NULL;
+ALTER TABLE users ADD COLUMN postal_code VARCHAR(10) NULL;
+
+CREATE INDEX idx_users_city ON users (city);
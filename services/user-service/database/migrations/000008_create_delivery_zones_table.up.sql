@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS delivery_zones (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    zone_type VARCHAR(20) NOT NULL CHECK (zone_type IN ('radius', 'polygon')),
+    center_lat DOUBLE PRECISION NULL,
+    center_lng DOUBLE PRECISION NULL,
+    radius_km DOUBLE PRECISION NULL,
+    polygon JSONB NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_delivery_zones_is_active ON delivery_zones (is_active);
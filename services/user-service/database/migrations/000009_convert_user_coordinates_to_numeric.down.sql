@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_users_lat_lng;
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_lat;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS chk_users_lng;
+
+ALTER TABLE users ALTER COLUMN lat TYPE VARCHAR(50) USING lat::TEXT;
+ALTER TABLE users ALTER COLUMN lng TYPE VARCHAR(50) USING lng::TEXT;
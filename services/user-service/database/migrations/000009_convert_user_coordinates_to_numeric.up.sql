@@ -0,0 +1,17 @@
+-- Values that never parsed as numbers used to be read back as 0,0; store them as NULL instead
+UPDATE users SET lat = NULL WHERE lat IS NOT NULL AND TRIM(lat) !~ '^-?[0-9]+(\.[0-9]+)?$';
+UPDATE users SET lng = NULL WHERE lng IS NOT NULL AND TRIM(lng) !~ '^-?[0-9]+(\.[0-9]+)?$';
+
+ALTER TABLE users ALTER COLUMN lat TYPE DOUBLE PRECISION USING TRIM(lat)::DOUBLE PRECISION;
+ALTER TABLE users ALTER COLUMN lng TYPE DOUBLE PRECISION USING TRIM(lng)::DOUBLE PRECISION;
+
+UPDATE users SET lat = NULL, lng = NULL
+WHERE lat IS NULL OR lng IS NULL
+   OR (lat = 0 AND lng = 0)
+   OR lat NOT BETWEEN -90 AND 90
+   OR lng NOT BETWEEN -180 AND 180;
+
+ALTER TABLE users ADD CONSTRAINT chk_users_lat CHECK (lat BETWEEN -90 AND 90);
+ALTER TABLE users ADD CONSTRAINT chk_users_lng CHECK (lng BETWEEN -180 AND 180);
+
+CREATE INDEX IF NOT EXISTS idx_users_lat_lng ON users (lat, lng);
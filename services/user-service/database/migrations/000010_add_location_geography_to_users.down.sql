@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_users_location;
+ALTER TABLE users DROP COLUMN IF EXISTS location;
@@ -0,0 +1,18 @@
+-- PostGIS is optional: when the extension is not installed on the server this migration is a no-op
+-- and distance queries fall back to plain haversine SQL over lat/lng.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'postgis') THEN
+        CREATE EXTENSION IF NOT EXISTS postgis;
+
+        EXECUTE 'ALTER TABLE users ADD COLUMN IF NOT EXISTS location geography(Point, 4326)
+            GENERATED ALWAYS AS (
+                CASE WHEN lat IS NOT NULL AND lng IS NOT NULL
+                     THEN ST_SetSRID(ST_MakePoint(lng, lat), 4326)::geography
+                END
+            ) STORED';
+        EXECUTE 'CREATE INDEX IF NOT EXISTS idx_users_location ON users USING GIST (location)';
+    ELSE
+        RAISE NOTICE 'postgis extension not available, skipping users.location';
+    END IF;
+END $$;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS vendor_documents;
+DROP TABLE IF EXISTS vendors;
+
+DELETE FROM user_role WHERE role_id IN (SELECT id FROM roles WHERE name = 'Vendor');
+DELETE FROM roles WHERE name = 'Vendor';
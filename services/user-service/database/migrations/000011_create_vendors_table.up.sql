@@ -0,0 +1,33 @@
+INSERT INTO roles (name) VALUES ('Vendor') ON CONFLICT (name) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS vendors (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    store_name VARCHAR(100) NOT NULL,
+    description TEXT NULL,
+    phone VARCHAR(20) NULL,
+    address TEXT NULL,
+    lat DOUBLE PRECISION NULL,
+    lng DOUBLE PRECISION NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'suspended')),
+    rejection_reason TEXT NULL,
+    reviewed_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_vendors_status ON vendors (status);
+
+CREATE TABLE IF NOT EXISTS vendor_documents (
+    id SERIAL PRIMARY KEY,
+    vendor_id INT NOT NULL REFERENCES vendors(id) ON DELETE CASCADE,
+    document_type VARCHAR(20) NOT NULL CHECK (document_type IN ('ktp', 'npwp', 'nib', 'other')),
+    file_url TEXT NOT NULL,
+    object_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_vendor_documents_vendor_id ON vendor_documents (vendor_id);
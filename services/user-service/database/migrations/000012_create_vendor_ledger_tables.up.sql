@@ -0,0 +1,44 @@
+-- Every balance movement is a ledger transaction whose entries sum to zero.
+-- Amounts are whole rupiah; positive is a credit, negative is a debit.
+CREATE TABLE IF NOT EXISTS ledger_transactions (
+    id SERIAL PRIMARY KEY,
+    vendor_id INT NOT NULL REFERENCES vendors(id) ON DELETE RESTRICT,
+    type VARCHAR(30) NOT NULL CHECK (type IN ('order_earning', 'withdrawal_hold', 'withdrawal_payout', 'withdrawal_release')),
+    reference_id VARCHAR(100) NOT NULL,
+    description TEXT NULL,
+    created_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_ledger_transactions_type_reference UNIQUE (type, reference_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_transactions_vendor_id ON ledger_transactions (vendor_id);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id SERIAL PRIMARY KEY,
+    transaction_id INT NOT NULL REFERENCES ledger_transactions(id) ON DELETE RESTRICT,
+    vendor_id INT NOT NULL REFERENCES vendors(id) ON DELETE RESTRICT,
+    account VARCHAR(30) NOT NULL CHECK (account IN ('platform_cash', 'platform_commission', 'vendor_payable', 'vendor_payout_pending')),
+    amount BIGINT NOT NULL CHECK (amount <> 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_transaction_id ON ledger_entries (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_entries_vendor_account ON ledger_entries (vendor_id, account);
+
+CREATE TABLE IF NOT EXISTS vendor_withdrawals (
+    id SERIAL PRIMARY KEY,
+    vendor_id INT NOT NULL REFERENCES vendors(id) ON DELETE RESTRICT,
+    amount BIGINT NOT NULL CHECK (amount > 0),
+    bank_name VARCHAR(100) NOT NULL,
+    account_number VARCHAR(50) NOT NULL,
+    account_name VARCHAR(100) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    rejection_reason TEXT NULL,
+    reviewed_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_vendor_withdrawals_vendor_id ON vendor_withdrawals (vendor_id);
+CREATE INDEX IF NOT EXISTS idx_vendor_withdrawals_status ON vendor_withdrawals (status);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS jobs (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 5,
+    run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    unique_key VARCHAR(200) NULL,
+    last_error TEXT NULL,
+    locked_by VARCHAR(100) NULL,
+    locked_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL
+);
+
+-- Workers poll with FOR UPDATE SKIP LOCKED on this index
+CREATE INDEX IF NOT EXISTS idx_jobs_pending_run_at ON jobs (run_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs (status);
+-- Scheduled jobs carry a unique key per run window so several instances enqueue them once
+CREATE UNIQUE INDEX IF NOT EXISTS uq_jobs_unique_key ON jobs (unique_key) WHERE unique_key IS NOT NULL;
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS user_onboarding (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    dismissed_at TIMESTAMP NULL,
+    first_order_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_merged_into_id;
+
+ALTER TABLE users DROP COLUMN IF EXISTS merged_into_id;
@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS merged_into_id INT NULL REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_merged_into_id ON users (merged_into_id) WHERE merged_into_id IS NOT NULL;
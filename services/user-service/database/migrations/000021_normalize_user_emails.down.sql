@@ -0,0 +1,2 @@
+-- Normalizing addresses is not reversible; the original spelling is not kept.
+SELECT 1;
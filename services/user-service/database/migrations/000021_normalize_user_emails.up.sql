@@ -0,0 +1,25 @@
+-- Bring existing addresses in line with utils.EmailPolicy.NormalizeEmail: trimmed and
+-- lowercased, with Gmail dots removed and googlemail.com folded into gmail.com.
+-- Rows whose normalized address already belongs to another account are left alone;
+-- sign-in still finds them by the address as typed.
+WITH normalized AS (
+    SELECT id,
+           CASE
+               WHEN split_part(lower(trim(email)), '@', 2) IN ('gmail.com', 'googlemail.com')
+                   THEN replace(split_part(lower(trim(email)), '@', 1), '.', '') || '@gmail.com'
+               ELSE lower(trim(email))
+           END AS email
+    FROM users
+),
+unique_targets AS (
+    SELECT n.id, n.email
+    FROM normalized n
+    JOIN users u ON u.id = n.id
+    WHERE n.email <> u.email
+      AND NOT EXISTS (SELECT 1 FROM users other WHERE other.email = n.email)
+      AND (SELECT count(*) FROM normalized twin WHERE twin.email = n.email) = 1
+)
+UPDATE users
+SET email = unique_targets.email
+FROM unique_targets
+WHERE users.id = unique_targets.id;
@@ -0,0 +1,4 @@
+-- Optional, changeable handle. Stored lowercased so the unique index is case-insensitive;
+-- soft-deleted users keep theirs so a restored account never collides.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS username VARCHAR(30);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS photo_moderations (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    image_url TEXT NOT NULL,
+    -- pending: waiting for the provider, flagged: waiting for an admin,
+    -- superseded: the user replaced the photo before it was checked
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'flagged', 'removed', 'superseded')),
+    score DOUBLE PRECISION NULL,
+    labels JSONB NOT NULL DEFAULT '[]',
+    review_note TEXT NULL,
+    reviewed_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_photo_moderations_status_created_at ON photo_moderations (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_photo_moderations_user_id ON photo_moderations (user_id);
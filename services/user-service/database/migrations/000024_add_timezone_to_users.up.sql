@@ -0,0 +1,2 @@
+-- IANA zone name used to show times to the user; timestamps themselves are stored in UTC
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
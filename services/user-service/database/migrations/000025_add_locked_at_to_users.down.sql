@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_users_locked_at;
+ALTER TABLE users DROP COLUMN IF EXISTS lock_reason;
+ALTER TABLE users DROP COLUMN IF EXISTS locked_at;
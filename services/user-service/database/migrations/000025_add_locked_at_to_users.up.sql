@@ -0,0 +1,5 @@
+-- A locked account cannot sign in until an admin reviews and unlocks it
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locked_at TIMESTAMP NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS lock_reason VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_users_locked_at ON users (locked_at) WHERE locked_at IS NOT NULL;
@@ -0,0 +1,10 @@
+-- Plaintext tokens cannot be recovered, so links sent before the rollback stop working
+DROP INDEX IF EXISTS idx_verification_tokens_expires_at;
+DROP INDEX IF EXISTS idx_verification_tokens_token_hash;
+
+ALTER TABLE verification_tokens ADD COLUMN IF NOT EXISTS token VARCHAR(255);
+UPDATE verification_tokens SET token = token_hash WHERE token IS NULL;
+ALTER TABLE verification_tokens ALTER COLUMN token SET NOT NULL;
+
+ALTER TABLE verification_tokens DROP COLUMN IF EXISTS used_at;
+ALTER TABLE verification_tokens DROP COLUMN IF EXISTS token_hash;
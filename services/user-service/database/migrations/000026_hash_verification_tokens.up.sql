@@ -0,0 +1,12 @@
+-- Only the SHA-256 of a token is stored; the plaintext exists only in the email that was sent.
+-- used_at makes a token single-use: it is claimed with UPDATE ... WHERE used_at IS NULL.
+ALTER TABLE verification_tokens ADD COLUMN IF NOT EXISTS token_hash VARCHAR(64);
+ALTER TABLE verification_tokens ADD COLUMN IF NOT EXISTS used_at TIMESTAMP NULL;
+
+UPDATE verification_tokens SET token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex') WHERE token_hash IS NULL;
+
+ALTER TABLE verification_tokens ALTER COLUMN token_hash SET NOT NULL;
+ALTER TABLE verification_tokens DROP COLUMN IF EXISTS token;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_verification_tokens_token_hash ON verification_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS idx_verification_tokens_expires_at ON verification_tokens (expires_at);
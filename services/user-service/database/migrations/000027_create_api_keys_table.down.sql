@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_webhook_endpoints_api_key_id;
+ALTER TABLE webhook_endpoints DROP COLUMN IF EXISTS api_key_id;
+DROP TABLE IF EXISTS api_keys;
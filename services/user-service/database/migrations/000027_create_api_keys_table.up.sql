@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS api_keys (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    -- key_prefix is shown in lists so a key can be recognised; only the SHA-256 of the key is stored
+    key_prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) NOT NULL,
+    scopes JSONB NOT NULL DEFAULT '[]',
+    quota INT NOT NULL DEFAULT 0,
+    created_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    last_used_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    revoked_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    CONSTRAINT uq_api_keys_key_hash UNIQUE (key_hash)
+);
+
+-- Webhook endpoints registered by a partner through its API key belong to that key
+ALTER TABLE webhook_endpoints ADD COLUMN IF NOT EXISTS api_key_id BIGINT NULL REFERENCES api_keys(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_webhook_endpoints_api_key_id ON webhook_endpoints(api_key_id);
@@ -0,0 +1,2 @@
+-- Preferred language for emails; empty means the language of the request that triggered the email
+ALTER TABLE users ADD COLUMN IF NOT EXISTS language VARCHAR(8) NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+-- The upload a processed profile photo was cropped from, kept so the user can re-crop it
+ALTER TABLE users ADD COLUMN IF NOT EXISTS photo_original VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE users DROP COLUMN IF EXISTS email_bounce_reason;
+ALTER TABLE users DROP COLUMN IF EXISTS email_bounced_at;
@@ -0,0 +1,3 @@
+-- Set when the mail provider reports a hard bounce for the address; cleared when the email changes
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_bounced_at TIMESTAMP NULL;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_bounce_reason VARCHAR(255) NOT NULL DEFAULT '';
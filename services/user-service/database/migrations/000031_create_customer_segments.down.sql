@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS segment_campaigns;
+DROP TABLE IF EXISTS customer_segment_members;
+DROP TABLE IF EXISTS customer_segments;
+DROP TABLE IF EXISTS customer_orders;
@@ -0,0 +1,46 @@
+-- One row per order reported by order-service; segments filter on the count per user
+CREATE TABLE IF NOT EXISTS customer_orders (
+    order_id VARCHAR(100) PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    ordered_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_orders_user_id ON customer_orders(user_id);
+
+CREATE TABLE IF NOT EXISTS customer_segments (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    city VARCHAR(100) NOT NULL DEFAULT '',
+    signed_up_from TIMESTAMP NULL,
+    signed_up_to TIMESTAMP NULL,
+    min_orders INT NULL,
+    max_orders INT NULL,
+    member_count INT NOT NULL DEFAULT 0,
+    materialized_at TIMESTAMP NULL,
+    created_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL
+);
+
+-- Members as of the last materialization; campaigns are sent to this snapshot
+CREATE TABLE IF NOT EXISTS customer_segment_members (
+    segment_id BIGINT NOT NULL REFERENCES customer_segments(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (segment_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS segment_campaigns (
+    id BIGSERIAL PRIMARY KEY,
+    segment_id BIGINT NOT NULL REFERENCES customer_segments(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    recipients INT NOT NULL DEFAULT 0,
+    batches INT NOT NULL DEFAULT 0,
+    created_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_segment_campaigns_segment_id ON segment_campaigns(segment_id);
@@ -0,0 +1,13 @@
+-- Images referenced by email templates. name is the stable key templates use; the object
+-- name carries a content hash, so a changed image gets a new URL and caches never go stale.
+CREATE TABLE IF NOT EXISTS assets (
+    name VARCHAR(200) PRIMARY KEY,
+    object_name VARCHAR(255) NOT NULL,
+    hash VARCHAR(64) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    size BIGINT NOT NULL,
+    url TEXT NOT NULL,
+    uploaded_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,23 @@
+-- Invite codes for invite-only registration (REGISTRATION_MODE=invite)
+CREATE TABLE IF NOT EXISTS invite_codes (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(32) NOT NULL UNIQUE,
+    note VARCHAR(255) NOT NULL DEFAULT '',
+    max_uses INT NOT NULL,
+    uses INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NULL,
+    revoked_at TIMESTAMP NULL,
+    created_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Who signed up with which code; kept by email so it survives the account being purged
+CREATE TABLE IF NOT EXISTS invite_redemptions (
+    id BIGSERIAL PRIMARY KEY,
+    invite_code_id BIGINT NOT NULL REFERENCES invite_codes(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    redeemed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_invite_redemptions_invite_code_id ON invite_redemptions(invite_code_id);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS user_consents;
+ALTER TABLE users DROP COLUMN IF EXISTS consented_at;
+ALTER TABLE users DROP COLUMN IF EXISTS accepted_privacy_version;
+ALTER TABLE users DROP COLUMN IF EXISTS accepted_terms_version;
+DROP TABLE IF EXISTS legal_documents;
@@ -0,0 +1,28 @@
+-- Published versions of the terms of service and privacy policy; the newest per kind is current
+CREATE TABLE IF NOT EXISTS legal_documents (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL,
+    version VARCHAR(32) NOT NULL,
+    url VARCHAR(500) NOT NULL,
+    published_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    published_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (kind, version)
+);
+
+-- Versions the user last accepted, checked on every request
+ALTER TABLE users ADD COLUMN accepted_terms_version VARCHAR(32) NULL;
+ALTER TABLE users ADD COLUMN accepted_privacy_version VARCHAR(32) NULL;
+ALTER TABLE users ADD COLUMN consented_at TIMESTAMP NULL;
+
+-- Every acceptance, so it can be shown which version a user agreed to and when
+CREATE TABLE IF NOT EXISTS user_consents (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    version VARCHAR(32) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(500) NOT NULL DEFAULT '',
+    accepted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_consents_user_id ON user_consents(user_id);
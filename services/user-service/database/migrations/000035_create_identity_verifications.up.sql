@@ -0,0 +1,22 @@
+-- ID documents submitted for age/identity verification, reviewed by an admin
+CREATE TABLE IF NOT EXISTS identity_verifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    document_type VARCHAR(20) NOT NULL,
+    -- Object in the private IDENTITY_BUCKET_NAME bucket; never exposed as a public URL
+    object_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    date_of_birth DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    rejection_reason VARCHAR(500) NOT NULL DEFAULT '',
+    reviewed_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP NULL,
+    expires_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_identity_verifications_user_id ON identity_verifications(user_id);
+CREATE INDEX IF NOT EXISTS idx_identity_verifications_status ON identity_verifications(status, created_at);
+-- A user has at most one submission waiting for review
+CREATE UNIQUE INDEX IF NOT EXISTS idx_identity_verifications_one_pending ON identity_verifications(user_id) WHERE status = 'pending';
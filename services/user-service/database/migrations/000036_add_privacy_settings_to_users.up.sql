@@ -0,0 +1,3 @@
+-- What the user hides from vendor-facing views; admins and the user themselves always see everything
+ALTER TABLE users ADD COLUMN IF NOT EXISTS hide_phone BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS hide_address BOOLEAN NOT NULL DEFAULT FALSE;
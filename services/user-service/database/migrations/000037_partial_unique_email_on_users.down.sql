@@ -0,0 +1,3 @@
+-- Fails while an active account shares its email with a deleted one; purge the deleted row first
+DROP INDEX IF EXISTS idx_users_email_active;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
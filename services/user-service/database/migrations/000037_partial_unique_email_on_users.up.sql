@@ -0,0 +1,4 @@
+-- Only active accounts own their email, so a deleted account no longer blocks signing up again.
+-- The deleted row stays in the trash with its original email as an archive.
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_active ON users (email) WHERE deleted_at IS NULL;
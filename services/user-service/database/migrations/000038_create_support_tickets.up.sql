@@ -0,0 +1,19 @@
+-- Customer support tickets, assigned to staff and optionally forwarded to a helpdesk by webhook
+CREATE TABLE IF NOT EXISTS support_tickets (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category VARCHAR(20) NOT NULL,
+    subject VARCHAR(150) NOT NULL,
+    message TEXT NOT NULL,
+    -- Objects in the private SUPPORT_BUCKET_NAME bucket: [{"object_name", "file_name", "content_type", "size"}]
+    attachments JSONB NOT NULL DEFAULT '[]',
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    assignee_id INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_support_tickets_user_id ON support_tickets(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_support_tickets_status ON support_tickets(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_support_tickets_assignee_id ON support_tickets(assignee_id) WHERE assignee_id IS NOT NULL;
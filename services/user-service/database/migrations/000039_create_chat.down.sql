@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS chat_reports;
+DROP TABLE IF EXISTS chat_reads;
+DROP TABLE IF EXISTS chat_messages;
+DROP TABLE IF EXISTS chat_rooms;
@@ -0,0 +1,55 @@
+-- One chat room per order, registered by order-service with the order's participants
+CREATE TABLE IF NOT EXISTS chat_rooms (
+    id BIGSERIAL PRIMARY KEY,
+    order_id VARCHAR(64) NOT NULL UNIQUE,
+    customer_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    vendor_user_id INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    courier_id INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    -- A closed room keeps its history but takes no new messages
+    closed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_rooms_customer_id ON chat_rooms(customer_id);
+CREATE INDEX IF NOT EXISTS idx_chat_rooms_vendor_user_id ON chat_rooms(vendor_user_id) WHERE vendor_user_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_chat_rooms_courier_id ON chat_rooms(courier_id) WHERE courier_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS chat_messages (
+    id BIGSERIAL PRIMARY KEY,
+    room_id BIGINT NOT NULL REFERENCES chat_rooms(id) ON DELETE CASCADE,
+    sender_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    body TEXT NOT NULL DEFAULT '',
+    -- Object in the private CHAT_BUCKET_NAME bucket, empty for text messages
+    image_object VARCHAR(255) NOT NULL DEFAULT '',
+    -- Set when an admin removes the message after an abuse report; the body is cleared
+    removed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_room_id ON chat_messages(room_id, id);
+CREATE INDEX IF NOT EXISTS idx_chat_messages_created_at ON chat_messages(created_at);
+
+-- The newest message each member has read, for unread counts
+CREATE TABLE IF NOT EXISTS chat_reads (
+    room_id BIGINT NOT NULL REFERENCES chat_rooms(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    last_read_message_id BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (room_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS chat_reports (
+    id BIGSERIAL PRIMARY KEY,
+    message_id BIGINT NOT NULL REFERENCES chat_messages(id) ON DELETE CASCADE,
+    reporter_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(500) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    reviewed_by INT NULL REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_reports_status ON chat_reports(status, created_at);
+-- A member reports a message once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chat_reports_message_reporter ON chat_reports(message_id, reporter_id);
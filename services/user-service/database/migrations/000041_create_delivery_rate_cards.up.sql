@@ -0,0 +1,25 @@
+-- Delivery pricing per zone; the card without a zone prices every location no zone card covers.
+-- Fees are whole rupiah.
+CREATE TABLE IF NOT EXISTS delivery_rate_cards (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    zone_id INT NULL REFERENCES delivery_zones(id) ON DELETE CASCADE,
+    base_fee BIGINT NOT NULL DEFAULT 0 CHECK (base_fee >= 0),
+    -- Distance from the warehouse covered by the base fee, then per_km_fee for each started km
+    included_km DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (included_km >= 0),
+    per_km_fee BIGINT NOT NULL DEFAULT 0 CHECK (per_km_fee >= 0),
+    -- Weight covered by the base fee, then per_kg_fee for each started kg
+    included_grams INT NOT NULL DEFAULT 0 CHECK (included_grams >= 0),
+    per_kg_fee BIGINT NOT NULL DEFAULT 0 CHECK (per_kg_fee >= 0),
+    -- 0 means no limit
+    max_distance_km DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (max_distance_km >= 0),
+    -- Orders with at least this subtotal ship free; 0 turns it off
+    free_delivery_min_subtotal BIGINT NOT NULL DEFAULT 0 CHECK (free_delivery_min_subtotal >= 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL
+);
+
+-- One card per zone, and one default card
+CREATE UNIQUE INDEX IF NOT EXISTS idx_delivery_rate_cards_zone_id ON delivery_rate_cards (COALESCE(zone_id, 0)) WHERE deleted_at IS NULL;
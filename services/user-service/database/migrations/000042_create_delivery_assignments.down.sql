@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS delivery_assignments;
+
+DELETE FROM user_role WHERE role_id IN (SELECT id FROM roles WHERE name = 'Courier');
+DELETE FROM roles WHERE name = 'Courier';
@@ -0,0 +1,30 @@
+INSERT INTO roles (name) VALUES ('Courier') ON CONFLICT (name) DO NOTHING;
+
+-- A delivery offered to a courier by order-service. A rejected offer stays for history and
+-- order-service offers the order to someone else.
+CREATE TABLE IF NOT EXISTS delivery_assignments (
+    id BIGSERIAL PRIMARY KEY,
+    order_id VARCHAR(64) NOT NULL,
+    courier_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    customer_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    address TEXT NOT NULL,
+    lat DOUBLE PRECISION NOT NULL,
+    lng DOUBLE PRECISION NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'offered'
+        CHECK (status IN ('offered', 'accepted', 'rejected', 'picked_up', 'on_the_way', 'delivered', 'failed')),
+    -- Why the courier rejected the offer or could not deliver
+    reason VARCHAR(500) NOT NULL DEFAULT '',
+    recipient_name VARCHAR(100) NOT NULL DEFAULT '',
+    -- Objects in the private DELIVERY_PROOF_BUCKET_NAME bucket
+    proof_photo_object VARCHAR(255) NOT NULL DEFAULT '',
+    signature_object VARCHAR(255) NOT NULL DEFAULT '',
+    accepted_at TIMESTAMP NULL,
+    picked_up_at TIMESTAMP NULL,
+    delivered_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Only one courier works an order at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_delivery_assignments_order_active ON delivery_assignments(order_id) WHERE status NOT IN ('rejected', 'failed');
+CREATE INDEX IF NOT EXISTS idx_delivery_assignments_courier_id ON delivery_assignments(courier_id, status);
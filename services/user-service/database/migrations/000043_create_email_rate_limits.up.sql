@@ -0,0 +1,9 @@
+-- Hourly email quotas per recipient, enforced by notification-service. email_type '*' caps all
+-- emails to one user together; any other row caps one template. 0 exempts the template from
+-- both, e.g. sign-in codes that must never wait.
+CREATE TABLE IF NOT EXISTS email_rate_limits (
+    email_type VARCHAR(50) PRIMARY KEY,
+    max_per_hour INT NOT NULL CHECK (max_per_hour >= 0),
+    updated_by BIGINT NULL REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,19 @@
+-- Objects are named by the SHA-256 of their content, so identical uploads share one object.
+-- ref_count counts the records pointing at an object; it is only deleted from storage when the
+-- last reference goes. Objects stored before this table existed have no row and are deleted
+-- directly.
+CREATE TABLE IF NOT EXISTS stored_objects (
+    bucket VARCHAR(100) NOT NULL,
+    object_name VARCHAR(500) NOT NULL,
+    sha256 CHAR(64) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    content_type VARCHAR(100) NOT NULL DEFAULT '',
+    url TEXT NOT NULL,
+    ref_count INT NOT NULL DEFAULT 1 CHECK (ref_count >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (bucket, object_name)
+);
+
+-- Reverse lookup from content to every object holding it
+CREATE INDEX IF NOT EXISTS idx_stored_objects_sha256 ON stored_objects (sha256);
@@ -0,0 +1,14 @@
+-- Trigram indexes let the admin customer type-ahead match anywhere in a name or email
+-- (ILIKE '%q%') without scanning users. pg_trgm ships with PostgreSQL's contrib modules; when it
+-- is not available the migration is a no-op and suggestions fall back to a sequential scan.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_available_extensions WHERE name = 'pg_trgm') THEN
+        CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+        EXECUTE 'CREATE INDEX IF NOT EXISTS idx_users_name_trgm ON users USING GIN (name gin_trgm_ops) WHERE deleted_at IS NULL';
+        EXECUTE 'CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING GIN (email gin_trgm_ops) WHERE deleted_at IS NULL';
+    ELSE
+        RAISE NOTICE 'pg_trgm extension not available, skipping trigram indexes on users';
+    END IF;
+END $$;
@@ -0,0 +1,16 @@
+-- Named filter/sort combinations for admin lists. Filters holds the list's query parameters,
+-- e.g. {"search": "budi", "orderBy": "users.name ASC"} for the customers list.
+CREATE TABLE IF NOT EXISTS saved_views (
+    id BIGSERIAL PRIMARY KEY,
+    owner_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    list VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    filters JSONB NOT NULL DEFAULT '{}',
+    -- Shared views are listed for every admin, only the owner can change them
+    is_shared BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    UNIQUE (owner_id, list, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_views_list_shared ON saved_views (list) WHERE is_shared;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_roles_parent_id;
+ALTER TABLE roles DROP COLUMN IF EXISTS parent_id;
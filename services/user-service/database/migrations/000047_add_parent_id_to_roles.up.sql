@@ -0,0 +1,5 @@
+-- A role inherits everything its parent may do, e.g. Super Admin under Admin. The service
+-- refuses parents that would close a cycle.
+ALTER TABLE roles ADD COLUMN IF NOT EXISTS parent_id INT NULL REFERENCES roles(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_roles_parent_id ON roles (parent_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_users_scim_external_id;
+DROP INDEX IF EXISTS idx_users_scim_managed;
+
+ALTER TABLE users DROP COLUMN IF EXISTS scim_external_id;
+ALTER TABLE users DROP COLUMN IF EXISTS scim_managed;
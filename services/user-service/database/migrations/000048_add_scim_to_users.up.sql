@@ -0,0 +1,7 @@
+-- Accounts provisioned (or adopted) by an identity provider over SCIM. Only these are visible to
+-- the IdP; scim_external_id is the IdP's own id for the account.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS scim_managed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS scim_external_id VARCHAR(255) NULL;
+
+CREATE INDEX IF NOT EXISTS idx_users_scim_managed ON users (id) WHERE scim_managed AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_users_scim_external_id ON users (scim_external_id) WHERE scim_managed;
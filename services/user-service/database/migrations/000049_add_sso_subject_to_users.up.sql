@@ -0,0 +1,5 @@
+-- The OpenID Connect subject ("sub") of the provider account a staff member signs in with.
+-- Linked on the first SSO sign-in, so a later email change at the provider keeps the account.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS sso_subject VARCHAR(255) NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_sso_subject ON users (sso_subject) WHERE sso_subject IS NOT NULL AND deleted_at IS NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS password_histories;
+
+ALTER TABLE users DROP COLUMN IF EXISTS password_expiry_warned_at;
+ALTER TABLE users DROP COLUMN IF EXISTS password_changed_at;
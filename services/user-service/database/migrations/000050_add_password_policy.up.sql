@@ -0,0 +1,14 @@
+-- When the password was last set, for the staff rotation policy. Existing accounts start
+-- counting from this migration rather than expiring at once.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+-- Set once the expiry warning for the current password was sent; cleared by a password change
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_expiry_warned_at TIMESTAMP NULL;
+
+CREATE TABLE IF NOT EXISTS password_histories (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_password_histories_user_id ON password_histories (user_id, created_at DESC);
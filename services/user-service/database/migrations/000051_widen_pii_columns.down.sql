@@ -0,0 +1,3 @@
+-- Decrypt first (`sayur-api pii backfill` with PII_ENCRYPTION_KEY_ID empty), or sealed values will not fit
+ALTER TABLE vendors ALTER COLUMN phone TYPE VARCHAR(20);
+ALTER TABLE users ALTER COLUMN phone TYPE VARCHAR(17);
@@ -0,0 +1,4 @@
+-- Encrypted phone numbers ("enc:v1:<key id>:<base64>") no longer fit the old VARCHAR limits;
+-- lengths are validated on the plaintext before it is sealed
+ALTER TABLE users ALTER COLUMN phone TYPE TEXT;
+ALTER TABLE vendors ALTER COLUMN phone TYPE TEXT;
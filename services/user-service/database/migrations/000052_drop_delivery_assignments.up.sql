@@ -0,0 +1,3 @@
+-- Courier assignments moved to delivery-service (not built yet). The Courier role stays: couriers
+-- are still user-service users.
+DROP TABLE IF EXISTS delivery_assignments;
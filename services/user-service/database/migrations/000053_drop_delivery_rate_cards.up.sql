@@ -0,0 +1,2 @@
+-- Delivery rate cards and fee quotes moved to delivery-service (not built yet).
+DROP TABLE IF EXISTS delivery_rate_cards;
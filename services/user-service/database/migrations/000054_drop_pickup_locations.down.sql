@@ -0,0 +1,20 @@
+-- Stores and warehouses where customers can collect their order instead of having it delivered
+CREATE TABLE IF NOT EXISTS pickup_locations (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) UNIQUE NOT NULL,
+    address TEXT NOT NULL,
+    city VARCHAR(100) NOT NULL DEFAULT '',
+    phone VARCHAR(20) NOT NULL DEFAULT '',
+    lat DOUBLE PRECISION NOT NULL,
+    lng DOUBLE PRECISION NOT NULL,
+    -- IANA zone the opening hours are written in
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Asia/Jakarta',
+    -- [{"weekday": 1, "opens": "08:00", "closes": "17:00"}], weekday 0 is Sunday
+    opening_hours JSONB NOT NULL DEFAULT '[]',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pickup_locations_is_active ON pickup_locations (is_active);
@@ -0,0 +1,2 @@
+-- Pickup locations moved to delivery-service (not built yet).
+DROP TABLE IF EXISTS pickup_locations;
@@ -0,0 +1,5 @@
+-- Order chat moved to chat-service (not built yet).
+DROP TABLE IF EXISTS chat_reports;
+DROP TABLE IF EXISTS chat_reads;
+DROP TABLE IF EXISTS chat_messages;
+DROP TABLE IF EXISTS chat_rooms;
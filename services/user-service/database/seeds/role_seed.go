@@ -15,6 +15,12 @@ func SeedRole(db *gorm.DB) {
 		{
 			Name: "Customer",
 		},
+		{
+			Name: "Vendor",
+		},
+		{
+			Name: "Courier",
+		},
 	}
 
 	for _, role := range roles {
@@ -31,8 +31,8 @@ func SeedUsers(db *gorm.DB) {
 			Email:      "superadmin@example.com",
 			Password:   string(hashedPassword),
 			Phone:      "+628123456789",
-			Lat:        "-6.2088",
-			Lng:        "106.8456",
+			Lat:        coordinate(-6.2088),
+			Lng:        coordinate(106.8456),
 			IsVerified: true,
 		},
 		{
@@ -40,8 +40,8 @@ func SeedUsers(db *gorm.DB) {
 			Email:      "john@example.com",
 			Password:   string(hashedPassword),
 			Phone:      "+628987654321",
-			Lat:        "-6.2000",
-			Lng:        "106.8167",
+			Lat:        coordinate(-6.2000),
+			Lng:        coordinate(106.8167),
 			IsVerified: true,
 		},
 		{
@@ -49,8 +49,8 @@ func SeedUsers(db *gorm.DB) {
 			Email:      "jane@example.com",
 			Password:   string(hashedPassword),
 			Phone:      "+628112233445",
-			Lat:        "-6.1751",
-			Lng:        "106.8650",
+			Lat:        coordinate(-6.1751),
+			Lng:        coordinate(106.8650),
 			IsVerified: true,
 		},
 		{
@@ -58,8 +58,8 @@ func SeedUsers(db *gorm.DB) {
 			Email:      "bob@example.com",
 			Password:   string(hashedPassword),
 			Phone:      "+628556667778",
-			Lat:        "-6.2146",
-			Lng:        "106.8451",
+			Lat:        coordinate(-6.2146),
+			Lng:        coordinate(106.8451),
 			IsVerified: true,
 		},
 		{
@@ -67,8 +67,8 @@ func SeedUsers(db *gorm.DB) {
 			Email:      "alice@example.com",
 			Password:   string(hashedPassword),
 			Phone:      "+628998877665",
-			Lat:        "-6.2088",
-			Lng:        "106.8456",
+			Lat:        coordinate(-6.2088),
+			Lng:        coordinate(106.8456),
 			IsVerified: true,
 		},
 	}
@@ -84,3 +84,7 @@ func SeedUsers(db *gorm.DB) {
 
 	log.Printf("Successfully seeded %d users", len(users))
 }
+
+func coordinate(value float64) *float64 {
+	return &value
+}
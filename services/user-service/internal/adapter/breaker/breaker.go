@@ -0,0 +1,105 @@
+// Package breaker wraps calls to external dependencies (storage, RabbitMQ) in circuit
+// breakers, so a dependency that keeps failing is short-circuited instead of making every
+// request wait for it, and is probed again automatically after a cool-down.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+	"github.com/sony/gobreaker"
+)
+
+// ErrOpen is wrapped by the error returned while a breaker rejects calls
+var ErrOpen = errors.New("circuit breaker is open")
+
+var (
+	stateGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "circuit_breaker_state",
+		Help: "Circuit breaker state per dependency: 0 closed, 1 half-open, 2 open.",
+	}, []string{"name"})
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "circuit_breaker_requests_total",
+		Help: "Calls through a circuit breaker by result: success, failure or rejected.",
+	}, []string{"name", "result"})
+)
+
+// Settings for one breaker. It opens after FailureThreshold consecutive failures, stays
+// open for OpenTimeout, then lets HalfOpenRequests trial calls through before closing.
+type Settings struct {
+	FailureThreshold uint32
+	OpenTimeout      time.Duration
+	HalfOpenRequests uint32
+}
+
+type Breaker struct {
+	name string
+	cb   *gobreaker.CircuitBreaker
+}
+
+func New(name string, settings Settings) *Breaker {
+	stateGauge.WithLabelValues(name).Set(stateValue(gobreaker.StateClosed))
+
+	return &Breaker{
+		name: name,
+		cb: gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:        name,
+			MaxRequests: settings.HalfOpenRequests,
+			Timeout:     settings.OpenTimeout,
+			ReadyToTrip: func(counts gobreaker.Counts) bool {
+				return counts.ConsecutiveFailures >= settings.FailureThreshold
+			},
+			// A caller giving up is not the dependency's fault
+			IsSuccessful: func(err error) bool {
+				return err == nil || errors.Is(err, context.Canceled)
+			},
+			OnStateChange: func(name string, from, to gobreaker.State) {
+				stateGauge.WithLabelValues(name).Set(stateValue(to))
+				log.Warn().Str("breaker", name).Str("from", from.String()).Str("to", to.String()).Msg("[Breaker-OnStateChange] Circuit breaker state changed")
+			},
+		}),
+	}
+}
+
+// Execute runs fn through the breaker. A nil Breaker just runs fn, so adapters work without one.
+func (b *Breaker) Execute(fn func() error) error {
+	if b == nil {
+		return fn()
+	}
+
+	_, err := b.cb.Execute(func() (interface{}, error) {
+		return nil, fn()
+	})
+
+	switch {
+	case errors.Is(err, gobreaker.ErrOpenState), errors.Is(err, gobreaker.ErrTooManyRequests):
+		requestsTotal.WithLabelValues(b.name, "rejected").Inc()
+		return fmt.Errorf("%s is unavailable, %w", b.name, ErrOpen)
+	case err != nil:
+		requestsTotal.WithLabelValues(b.name, "failure").Inc()
+	default:
+		requestsTotal.WithLabelValues(b.name, "success").Inc()
+	}
+	return err
+}
+
+// State returns "closed", "half-open" or "open"
+func (b *Breaker) State() string {
+	return b.cb.State().String()
+}
+
+func stateValue(state gobreaker.State) float64 {
+	switch state {
+	case gobreaker.StateHalfOpen:
+		return 1
+	case gobreaker.StateOpen:
+		return 2
+	default:
+		return 0
+	}
+}
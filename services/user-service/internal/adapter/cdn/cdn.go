@@ -0,0 +1,116 @@
+// Package cdn serves public storage objects through a CDN: it rewrites storage URLs to CDN
+// URLs in API responses and purges the CDN's copy when an object is deleted.
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"user-service/internal/adapter/httpclient"
+	"user-service/internal/core/port"
+)
+
+// storagePublicPath is where Supabase serves objects of public buckets
+const storagePublicPath = "/storage/v1/object/public/"
+
+// Rewriter maps <project>/storage/v1/object/public/<bucket>/<object> to <base>/<bucket>/<object>
+// and back. Without a base URL it leaves every URL alone.
+type Rewriter struct {
+	storagePrefix string
+	cdnPrefix     string
+}
+
+func NewRewriter(projectURL, baseURL string) *Rewriter {
+	if projectURL == "" || baseURL == "" {
+		return &Rewriter{}
+	}
+	return &Rewriter{
+		storagePrefix: strings.TrimSuffix(projectURL, "/") + storagePublicPath,
+		cdnPrefix:     strings.TrimSuffix(baseURL, "/") + "/",
+	}
+}
+
+// Enabled reports whether a CDN base URL is configured
+func (r *Rewriter) Enabled() bool {
+	return r.cdnPrefix != ""
+}
+
+// PublicURL returns the CDN URL for a storage URL; other URLs are returned unchanged
+func (r *Rewriter) PublicURL(url string) string {
+	if !r.Enabled() || !strings.HasPrefix(url, r.storagePrefix) {
+		return url
+	}
+	return r.cdnPrefix + strings.TrimPrefix(url, r.storagePrefix)
+}
+
+// StorageURL undoes PublicURL, so a CDN URL sent back by a client is stored as the storage URL
+func (r *Rewriter) StorageURL(url string) string {
+	if !r.Enabled() || !strings.HasPrefix(url, r.cdnPrefix) {
+		return url
+	}
+	return r.storagePrefix + strings.TrimPrefix(url, r.cdnPrefix)
+}
+
+// ObjectURL returns the CDN URL of an object, or "" without a CDN
+func (r *Rewriter) ObjectURL(bucket, objectName string) string {
+	if !r.Enabled() {
+		return ""
+	}
+	return r.cdnPrefix + bucket + "/" + objectName
+}
+
+// maxResponseBytes is all of the CDN's answer that is read; it only feeds the error message
+const maxResponseBytes = 4096
+
+// HTTPPurger asks the CDN to drop cached URLs with Cloudflare's purge_cache request,
+// {"files": [...]}, which other CDNs' purge APIs or a small proxy can accept as well
+type HTTPPurger struct {
+	purgeURL   string
+	token      string
+	httpClient *http.Client
+}
+
+func NewHTTPPurger(purgeURL, token string, httpClient *http.Client) port.CDNPurgerInterface {
+	return &HTTPPurger{
+		purgeURL:   purgeURL,
+		token:      token,
+		httpClient: httpClient,
+	}
+}
+
+func (p *HTTPPurger) Purge(ctx context.Context, urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string][]string{"files": urls})
+	if err != nil {
+		return err
+	}
+
+	// Purging the same URL twice is harmless, so a failed purge may be retried
+	req, err := http.NewRequestWithContext(httpclient.Idempotent(ctx), http.MethodPost, p.purgeURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to purge CDN cache: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+		return fmt.Errorf("CDN purge failed with status %d: %s", resp.StatusCode, string(responseBody))
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package geocoding
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"user-service/config"
+	"user-service/internal/core/port"
+)
+
+var ErrLocationNotFound = errors.New("location not found")
+
+// NewGeocoder builds the geocoder selected by GEOCODER_PROVIDER (defaults to Nominatim)
+func NewGeocoder(cfg *config.Config, httpClient *http.Client) (port.GeocoderInterface, error) {
+	switch strings.ToLower(cfg.Geocoder.Provider) {
+	case "google":
+		return NewGoogleGeocoder(cfg.Geocoder.BaseURL, cfg.Geocoder.APIKey, httpClient)
+	case "", "nominatim":
+		return NewNominatimGeocoder(cfg.Geocoder.BaseURL, cfg.Geocoder.UserAgent, httpClient), nil
+	default:
+		return nil, errors.New("unsupported geocoder provider: " + cfg.Geocoder.Provider)
+	}
+}
@@ -0,0 +1,153 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+const defaultGoogleGeocodeURL = "https://maps.googleapis.com/maps/api/geocode/json"
+
+type GoogleGeocoder struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+type googleAddressComponent struct {
+	LongName string   `json:"long_name"`
+	Types    []string `json:"types"`
+}
+
+type googleGeocodeResponse struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	Results      []struct {
+		FormattedAddress  string                   `json:"formatted_address"`
+		AddressComponents []googleAddressComponent `json:"address_components"`
+		Geometry          struct {
+			Location struct {
+				Lat float64 `json:"lat"`
+				Lng float64 `json:"lng"`
+			} `json:"location"`
+		} `json:"geometry"`
+	} `json:"results"`
+}
+
+func NewGoogleGeocoder(baseURL, apiKey string, httpClient *http.Client) (port.GeocoderInterface, error) {
+	if apiKey == "" {
+		return nil, fmt.Errorf("google geocoder requires GEOCODER_API_KEY")
+	}
+	if baseURL == "" {
+		baseURL = defaultGoogleGeocodeURL
+	}
+
+	return &GoogleGeocoder{
+		baseURL:    baseURL,
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}, nil
+}
+
+func (g *GoogleGeocoder) ReverseGeocode(ctx context.Context, lat, lng float64) (*entity.AddressEntity, error) {
+	params := url.Values{}
+	params.Set("latlng", fmt.Sprintf("%f,%f", lat, lng))
+
+	address, err := g.lookup(ctx, params)
+	if err != nil {
+		log.Error().Err(err).Float64("lat", lat).Float64("lng", lng).Msg("[GoogleGeocoder-ReverseGeocode] Request failed")
+		return nil, err
+	}
+
+	return address, nil
+}
+
+func (g *GoogleGeocoder) Geocode(ctx context.Context, address string) (*entity.AddressEntity, error) {
+	params := url.Values{}
+	params.Set("address", address)
+
+	result, err := g.lookup(ctx, params)
+	if err != nil {
+		log.Error().Err(err).Str("address", address).Msg("[GoogleGeocoder-Geocode] Request failed")
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (g *GoogleGeocoder) lookup(ctx context.Context, params url.Values) (*entity.AddressEntity, error) {
+	params.Set("key", g.apiKey)
+	params.Set("language", "id")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call google geocoding: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("google geocoding returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var payload googleGeocodeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode google geocoding response: %w", err)
+	}
+
+	switch payload.Status {
+	case "OK":
+	case "ZERO_RESULTS":
+		return nil, ErrLocationNotFound
+	default:
+		return nil, fmt.Errorf("google geocoding error %s: %s", payload.Status, payload.ErrorMessage)
+	}
+
+	if len(payload.Results) == 0 {
+		return nil, ErrLocationNotFound
+	}
+
+	result := payload.Results[0]
+	address := &entity.AddressEntity{
+		Address: result.FormattedAddress,
+		Lat:     result.Geometry.Location.Lat,
+		Lng:     result.Geometry.Location.Lng,
+	}
+
+	for _, component := range result.AddressComponents {
+		switch {
+		case hasType(component.Types, "administrative_area_level_1"):
+			address.Province = component.LongName
+		case hasType(component.Types, "administrative_area_level_2"):
+			address.City = component.LongName
+		case hasType(component.Types, "administrative_area_level_3"):
+			address.District = component.LongName
+		case hasType(component.Types, "postal_code"):
+			address.PostalCode = component.LongName
+		}
+	}
+
+	return address, nil
+}
+
+func hasType(types []string, want string) bool {
+	for _, t := range types {
+		if strings.EqualFold(t, want) {
+			return true
+		}
+	}
+	return false
+}
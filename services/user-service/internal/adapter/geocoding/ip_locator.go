@@ -0,0 +1,78 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+)
+
+// IPLocator resolves client IPs with an ip-api.com compatible endpoint
+// (GET {base}/json/{ip}?fields=status,message,lat,lon,city,country)
+type IPLocator struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+type ipAPIResponse struct {
+	Status  string  `json:"status"`
+	Message string  `json:"message"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+	City    string  `json:"city"`
+	Country string  `json:"country"`
+}
+
+func NewIPLocator(baseURL string, httpClient *http.Client) port.IPLocatorInterface {
+	return &IPLocator{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: httpClient,
+	}
+}
+
+func (l *IPLocator) Locate(ctx context.Context, ip string) (*entity.LoginLocationEntity, error) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil || parsed.IsLoopback() || parsed.IsPrivate() || parsed.IsUnspecified() || parsed.IsLinkLocalUnicast() {
+		return nil, ErrLocationNotFound
+	}
+
+	endpoint := fmt.Sprintf("%s/json/%s?fields=%s", l.baseURL, url.PathEscape(parsed.String()), url.QueryEscape("status,message,lat,lon,city,country"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ip locator: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("ip locator returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result ipAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode ip locator response: %w", err)
+	}
+	if result.Status != "success" {
+		return nil, ErrLocationNotFound
+	}
+
+	return &entity.LoginLocationEntity{
+		Lat:     result.Lat,
+		Lng:     result.Lon,
+		At:      time.Now(),
+		City:    result.City,
+		Country: result.Country,
+	}, nil
+}
@@ -0,0 +1,149 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+const defaultNominatimURL = "https://nominatim.openstreetmap.org"
+
+type NominatimGeocoder struct {
+	baseURL    string
+	userAgent  string
+	httpClient *http.Client
+}
+
+type nominatimAddress struct {
+	State        string `json:"state"`
+	City         string `json:"city"`
+	Town         string `json:"town"`
+	County       string `json:"county"`
+	CityDistrict string `json:"city_district"`
+	Suburb       string `json:"suburb"`
+	Village      string `json:"village"`
+	Postcode     string `json:"postcode"`
+}
+
+type nominatimPlace struct {
+	Lat         string           `json:"lat"`
+	Lon         string           `json:"lon"`
+	DisplayName string           `json:"display_name"`
+	Address     nominatimAddress `json:"address"`
+	Error       string           `json:"error"`
+}
+
+func NewNominatimGeocoder(baseURL, userAgent string, httpClient *http.Client) port.GeocoderInterface {
+	if baseURL == "" {
+		baseURL = defaultNominatimURL
+	}
+	if userAgent == "" {
+		// Nominatim usage policy requires an identifying User-Agent
+		userAgent = "jualan-sayur-user-service"
+	}
+
+	return &NominatimGeocoder{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		userAgent:  userAgent,
+		httpClient: httpClient,
+	}
+}
+
+func (n *NominatimGeocoder) ReverseGeocode(ctx context.Context, lat, lng float64) (*entity.AddressEntity, error) {
+	params := url.Values{}
+	params.Set("format", "jsonv2")
+	params.Set("addressdetails", "1")
+	params.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
+	params.Set("lon", strconv.FormatFloat(lng, 'f', -1, 64))
+
+	var place nominatimPlace
+	if err := n.get(ctx, "/reverse?"+params.Encode(), &place); err != nil {
+		log.Error().Err(err).Float64("lat", lat).Float64("lng", lng).Msg("[NominatimGeocoder-ReverseGeocode] Request failed")
+		return nil, err
+	}
+
+	if place.Error != "" {
+		return nil, ErrLocationNotFound
+	}
+
+	return place.toEntity(), nil
+}
+
+func (n *NominatimGeocoder) Geocode(ctx context.Context, address string) (*entity.AddressEntity, error) {
+	params := url.Values{}
+	params.Set("format", "jsonv2")
+	params.Set("addressdetails", "1")
+	params.Set("limit", "1")
+	params.Set("q", address)
+
+	var places []nominatimPlace
+	if err := n.get(ctx, "/search?"+params.Encode(), &places); err != nil {
+		log.Error().Err(err).Str("address", address).Msg("[NominatimGeocoder-Geocode] Request failed")
+		return nil, err
+	}
+
+	if len(places) == 0 {
+		return nil, ErrLocationNotFound
+	}
+
+	return places[0].toEntity(), nil
+}
+
+func (n *NominatimGeocoder) get(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", n.userAgent)
+	req.Header.Set("Accept-Language", "id,en")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call nominatim: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("nominatim returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode nominatim response: %w", err)
+	}
+
+	return nil
+}
+
+func (p nominatimPlace) toEntity() *entity.AddressEntity {
+	lat, _ := strconv.ParseFloat(p.Lat, 64)
+	lng, _ := strconv.ParseFloat(p.Lon, 64)
+
+	return &entity.AddressEntity{
+		Address:    p.DisplayName,
+		Province:   p.Address.State,
+		City:       firstNonEmpty(p.Address.City, p.Address.Town, p.Address.County),
+		District:   firstNonEmpty(p.Address.CityDistrict, p.Address.Suburb, p.Address.Village),
+		PostalCode: p.Address.Postcode,
+		Lat:        lat,
+		Lng:        lng,
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
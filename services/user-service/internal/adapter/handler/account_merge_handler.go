@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"net/http"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/port"
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type AccountMergeHandlerInterface interface {
+	MergeAccounts(c echo.Context) error
+}
+
+type AccountMergeHandler struct {
+	mergeService port.AccountMergeServiceInterface
+	validator    *myvalidator.Validator
+}
+
+// MergeAccounts folds a duplicate account (source) into the one the user keeps (target).
+// With dry_run the response shows what would move without changing anything.
+func (h *AccountMergeHandler) MergeAccounts(c echo.Context) error {
+	var (
+		req  = request.MergeAccountsRequest{}
+		resp = response.DefaultResponse{}
+	)
+	adminID := c.Get("user_id").(int64)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	result, err := h.mergeService.MergeAccounts(c.Request().Context(), req.SourceUserID, req.TargetUserID, adminID, req.Reason, req.DryRun)
+	if err != nil {
+		log.Error().Err(err).Int64("source_user_id", req.SourceUserID).Int64("target_user_id", req.TargetUserID).Msg("[AccountMergeHandler-MergeAccounts] Failed to merge accounts")
+		switch err.Error() {
+		case "source user not found", "target user not found":
+			resp.Message = err.Error()
+			return c.JSON(http.StatusNotFound, resp)
+		case "both accounts have vendor profiles":
+			resp.Message = err.Error()
+			return c.JSON(http.StatusConflict, resp)
+		case "invalid user id", "cannot merge an account into itself", "merge reason is required":
+			resp.Message = err.Error()
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		default:
+			resp.Message = "Failed to merge accounts"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	resp.Message = "Accounts merged successfully"
+	if result.DryRun {
+		resp.Message = "Merge preview generated, no changes were made"
+	}
+	resp.Data = response.AccountMergeResponse{
+		SourceUserID:          result.SourceUserID,
+		TargetUserID:          result.TargetUserID,
+		SourceEmail:           result.SourceEmail,
+		TargetEmail:           result.TargetEmail,
+		ProfileFieldsCopied:   result.ProfileFieldsCopied,
+		DevicesMoved:          result.DevicesMoved,
+		FeatureOverridesMoved: result.FeatureOverridesMoved,
+		VendorMoved:           result.VendorMoved,
+		OnboardingMerged:      result.OnboardingMerged,
+		DryRun:                result.DryRun,
+		MergedAt:              result.MergedAt,
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func NewAccountMergeHandler(mergeService port.AccountMergeServiceInterface) AccountMergeHandlerInterface {
+	return &AccountMergeHandler{
+		mergeService: mergeService,
+		validator:    myvalidator.NewValidator(),
+	}
+}
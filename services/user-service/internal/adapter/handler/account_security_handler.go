@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type AccountSecurityHandlerInterface interface {
+	RevertEmailChange(c echo.Context) error
+	GetLockedAccounts(c echo.Context) error
+	UnlockAccount(c echo.Context) error
+}
+
+type AccountSecurityHandler struct {
+	accountSecurityService port.AccountSecurityServiceInterface
+}
+
+// RevertEmailChange is opened from the link sent to the previous address after an email change
+func (h *AccountSecurityHandler) RevertEmailChange(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	token := c.QueryParam("token")
+	if token == "" {
+		resp.Message = "Revert token is required"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.accountSecurityService.RevertEmailChange(c.Request().Context(), token); err != nil {
+		log.Error().Err(err).Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AccountSecurityHandler-RevertEmailChange] Failed to revert email change")
+		switch err.Error() {
+		case "invalid or expired revert token":
+			resp.Message = "Invalid or expired revert token"
+			return c.JSON(http.StatusBadRequest, resp)
+		case "token already used":
+			resp.Message = "This link has already been used"
+			return c.JSON(http.StatusGone, resp)
+		case "invalid token type":
+			resp.Message = "Invalid token type"
+			return c.JSON(http.StatusBadRequest, resp)
+		case "previous email is already in use":
+			resp.Message = "Previous email is already used by another account. Please contact support."
+			return c.JSON(http.StatusConflict, resp)
+		default:
+			resp.Message = "Failed to revert email change"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	resp.Message = "Your email has been restored and the account is locked until our team reviews it."
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *AccountSecurityHandler) GetLockedAccounts(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	users, err := h.accountSecurityService.GetLockedAccounts(c.Request().Context())
+	if err != nil {
+		resp.Message = "Failed to get locked accounts"
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	accounts := make([]response.LockedAccountResponse, 0, len(users))
+	for _, user := range users {
+		accounts = append(accounts, response.LockedAccountResponse{
+			ID:         user.ID,
+			Name:       user.Name,
+			Email:      user.Email,
+			LockedAt:   user.LockedAt,
+			LockReason: user.LockReason,
+		})
+	}
+
+	resp.Message = "Locked accounts retrieved successfully"
+	resp.Data = accounts
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *AccountSecurityHandler) UnlockAccount(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	adminID := c.Get("user_id").(int64)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid user ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.accountSecurityService.UnlockAccount(c.Request().Context(), userID, adminID); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AccountSecurityHandler-UnlockAccount] Failed to unlock account")
+		switch err.Error() {
+		case "invalid user id":
+			resp.Message = "Invalid user ID format"
+			return c.JSON(http.StatusBadRequest, resp)
+		case "account is not locked":
+			resp.Message = "Account is not locked"
+			return c.JSON(http.StatusNotFound, resp)
+		default:
+			resp.Message = "Failed to unlock account"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	resp.Message = "Account unlocked successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func NewAccountSecurityHandler(accountSecurityService port.AccountSecurityServiceInterface) AccountSecurityHandlerInterface {
+	return &AccountSecurityHandler{
+		accountSecurityService: accountSecurityService,
+	}
+}
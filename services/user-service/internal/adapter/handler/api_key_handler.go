@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type APIKeyHandlerInterface interface {
+	GetAPIKeys(c echo.Context) error
+	GetAPIKey(c echo.Context) error
+	CreateAPIKey(c echo.Context) error
+	RevokeAPIKey(c echo.Context) error
+	RevokeOwnAPIKey(c echo.Context) error
+}
+
+type APIKeyHandler struct {
+	apiKeyService port.APIKeyServiceInterface
+	validator     *myvalidator.Validator
+}
+
+func (h *APIKeyHandler) GetAPIKeys(c echo.Context) error {
+	page, limit := pageQuery(c)
+
+	apiKeys, pagination, err := h.apiKeyService.GetAPIKeys(c.Request().Context(), page, limit)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve API keys")
+	}
+
+	apiKeyData := make([]response.APIKeyResponse, 0, len(apiKeys))
+	for i := range apiKeys {
+		apiKeyData = append(apiKeyData, toAPIKeyResponse(&apiKeys[i], ""))
+	}
+
+	return respondPage(c, "API keys retrieved successfully", apiKeyData, pagination)
+}
+
+func (h *APIKeyHandler) GetAPIKey(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid API key ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	apiKey, err := h.apiKeyService.GetAPIKeyByID(c.Request().Context(), id)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve API key")
+	}
+
+	resp.Message = "API key retrieved successfully"
+	resp.Data = toAPIKeyResponse(apiKey, "")
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateAPIKey issues a key; the response is the only place the full key is ever shown
+func (h *APIKeyHandler) CreateAPIKey(c echo.Context) error {
+	var (
+		req  = request.APIKeyRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	apiKey, plainKey, err := h.apiKeyService.CreateAPIKey(c.Request().Context(), &entity.APIKeyEntity{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		Quota:     req.Quota,
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: c.Get("user_id").(int64),
+	})
+	if err != nil {
+		return h.handleError(c, err, "Failed to create API key")
+	}
+
+	resp.Message = "API key created successfully"
+	resp.Data = toAPIKeyResponse(apiKey, plainKey)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *APIKeyHandler) RevokeAPIKey(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid API key ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.apiKeyService.RevokeAPIKey(c.Request().Context(), id, c.Get("user_id").(int64)); err != nil {
+		return h.handleError(c, err, "Failed to revoke API key")
+	}
+
+	resp.Message = "API key revoked successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RevokeOwnAPIKey lets a partner kill the key it is calling with, e.g. after it leaked
+func (h *APIKeyHandler) RevokeOwnAPIKey(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	principal, _ := middleware.GetPrincipal(c)
+	if err := h.apiKeyService.RevokeAPIKey(c.Request().Context(), principal.APIKeyID, 0); err != nil {
+		return h.handleError(c, err, "Failed to revoke API key")
+	}
+
+	resp.Message = "API key revoked successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *APIKeyHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Str("path", c.Path()).Msg("[APIKeyHandler] Request failed")
+
+	switch err.Error() {
+	case "api key not found":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusNotFound, resp)
+	case "api key name is required", "at least one scope is required", "unknown api key scope",
+		"api key quota must not be negative", "api key expiry must be in the future":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toAPIKeyResponse(apiKey *entity.APIKeyEntity, plainKey string) response.APIKeyResponse {
+	status := "active"
+	switch {
+	case apiKey.RevokedAt != nil:
+		status = "revoked"
+	case apiKey.IsExpired(time.Now()):
+		status = "expired"
+	}
+
+	return response.APIKeyResponse{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Key:        plainKey,
+		Scopes:     apiKey.Scopes,
+		Quota:      apiKey.Quota,
+		Status:     status,
+		LastUsedAt: apiKey.LastUsedAt,
+		ExpiresAt:  apiKey.ExpiresAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
+
+func NewAPIKeyHandler(apiKeyService port.APIKeyServiceInterface) APIKeyHandlerInterface {
+	return &APIKeyHandler{
+		apiKeyService: apiKeyService,
+		validator:     myvalidator.NewValidator(),
+	}
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type AssetHandlerInterface interface {
+	UploadAsset(c echo.Context) error
+	GetAssets(c echo.Context) error
+	GetManifest(c echo.Context) error
+}
+
+type AssetHandler struct {
+	assetService port.AssetServiceInterface
+}
+
+// UploadAsset takes a multipart "file" and the "name" templates will refer to it by
+func (h *AssetHandler) UploadAsset(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	uploadedBy, _ := c.Get("user_id").(int64)
+
+	name := c.FormValue("name")
+	if strings.TrimSpace(name) == "" {
+		resp.Message = "Asset name is required"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		log.Warn().Err(err).Int64("user_id", uploadedBy).Msg("[AssetHandler-UploadAsset] Failed to get file from form")
+		resp.Message = "File is required"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", uploadedBy).Msg("[AssetHandler-UploadAsset] Failed to open uploaded file")
+		resp.Message = "Failed to process uploaded file"
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+	defer src.Close()
+
+	asset, err := h.assetService.UploadAsset(c.Request().Context(), name, src, uploadedBy)
+	if err != nil {
+		return h.handleError(c, err, "Failed to upload asset")
+	}
+
+	resp.Message = "Asset uploaded successfully"
+	resp.Data = toAssetResponse(asset)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *AssetHandler) GetAssets(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	assets, err := h.assetService.GetAssets(c.Request().Context())
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve assets")
+	}
+
+	assetData := make([]response.AssetResponse, 0, len(assets))
+	for i := range assets {
+		assetData = append(assetData, toAssetResponse(&assets[i]))
+	}
+
+	resp.Message = "Assets retrieved successfully"
+	resp.Data = assetData
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetManifest maps asset names to their current URL for the templating engine in
+// notification-service. It is public, since the URLs end up in every email anyway.
+func (h *AssetHandler) GetManifest(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	assets, err := h.assetService.GetAssets(c.Request().Context())
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve asset manifest")
+	}
+
+	manifest := make(map[string]response.AssetManifestEntry, len(assets))
+	for _, asset := range assets {
+		manifest[asset.Name] = response.AssetManifestEntry{URL: asset.URL, Hash: asset.Hash}
+	}
+
+	// Short enough that a new logo reaches emails within a minute
+	c.Response().Header().Set("Cache-Control", "public, max-age=60")
+	resp.Message = "Asset manifest retrieved successfully"
+	resp.Data = manifest
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *AssetHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Msg("[AssetHandler] Request failed")
+
+	switch {
+	case err.Error() == "storage not available":
+		resp.Message = "Storage service unavailable"
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	case err.Error() == "asset exceeds the size limit":
+		resp.Message = "Asset exceeds the size limit"
+		return c.JSON(http.StatusRequestEntityTooLarge, resp)
+	case strings.HasPrefix(err.Error(), "asset "):
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toAssetResponse(asset *entity.AssetEntity) response.AssetResponse {
+	return response.AssetResponse{
+		Name:        asset.Name,
+		URL:         asset.URL,
+		Hash:        asset.Hash,
+		ContentType: asset.ContentType,
+		Size:        asset.Size,
+		UpdatedAt:   asset.UpdatedAt,
+	}
+}
+
+func NewAssetHandler(assetService port.AssetServiceInterface) AssetHandlerInterface {
+	return &AssetHandler{
+		assetService: assetService,
+	}
+}
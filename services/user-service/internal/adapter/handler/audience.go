@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+
+	"github.com/labstack/echo/v4"
+)
+
+// audienceFor tells the response builders whose view of a user they are building. Partners
+// using an API key and signed-in vendors get the vendor view; an unknown caller gets it too.
+func audienceFor(c echo.Context) string {
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		return entity.AudienceVendor
+	}
+
+	switch {
+	case principal.Type == entity.PrincipalTypeAPIKey:
+		return entity.AudienceVendor
+	case principal.Type == entity.PrincipalTypeUser && principal.Role == "Vendor":
+		return entity.AudienceVendor
+	default:
+		return entity.AudienceAdmin
+	}
+}
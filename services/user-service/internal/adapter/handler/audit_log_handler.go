@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type AuditLogHandlerInterface interface {
+	GetAuditLogs(c echo.Context) error
+}
+
+type AuditLogHandler struct {
+	auditLogService port.AuditLogServiceInterface
+}
+
+func (h *AuditLogHandler) GetAuditLogs(c echo.Context) error {
+	filter := entity.AuditLogFilter{Event: c.QueryParam("event")}
+	if userID := c.QueryParam("user_id"); userID != "" {
+		id, err := strconv.ParseInt(userID, 10, 64)
+		if err != nil || id <= 0 {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"message": "Invalid user ID format",
+				"data":    nil,
+			})
+		}
+		filter.UserID = id
+	}
+
+	page, limit := pageQuery(c)
+
+	auditLogs, pagination, err := h.auditLogService.List(c.Request().Context(), filter, page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("[AuditLogHandler-GetAuditLogs] Failed to get audit logs")
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Failed to retrieve audit logs",
+			"data":    nil,
+		})
+	}
+
+	auditLogData := make([]response.AuditLogResponse, 0, len(auditLogs))
+	for _, auditLog := range auditLogs {
+		auditLogData = append(auditLogData, response.AuditLogResponse{
+			ID:        auditLog.ID,
+			UserID:    auditLog.UserID,
+			Event:     auditLog.Event,
+			IPAddress: auditLog.IPAddress,
+			UserAgent: auditLog.UserAgent,
+			Metadata:  auditLog.Metadata,
+			CreatedAt: auditLog.CreatedAt,
+		})
+	}
+
+	return respondPage(c, "Audit logs retrieved successfully", auditLogData, pagination)
+}
+
+func NewAuditLogHandler(auditLogService port.AuditLogServiceInterface) AuditLogHandlerInterface {
+	return &AuditLogHandler{
+		auditLogService: auditLogService,
+	}
+}
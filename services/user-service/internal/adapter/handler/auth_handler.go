@@ -1,14 +1,21 @@
 package handler
 
 import (
-	"io"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
+	"user-service/config"
+	"user-service/internal/adapter/cdn"
 	"user-service/internal/adapter/handler/request"
 	"user-service/internal/adapter/handler/response"
+	"user-service/internal/adapter/middleware"
 	"user-service/internal/adapter/storage"
 	"user-service/internal/core/domain/entity"
 	"user-service/internal/core/port"
+	"user-service/utils"
 
 	"github.com/go-playground/validator/v10"
 	myvalidator "user-service/utils/validator"
@@ -19,28 +26,40 @@ import (
 
 type AuthHandlerInterface interface {
 	SignIn(ctx echo.Context) error
+	VerifySignInOTP(ctx echo.Context) error
 	CreateUserAccount(ctx echo.Context) error
 	VerifyUserAccount(ctx echo.Context) error
 	VerifyEmailChange(ctx echo.Context) error
 	ForgotPassword(ctx echo.Context) error
+	ResendVerificationEmail(ctx echo.Context) error
 	ResetPassword(ctx echo.Context) error
 	Logout(ctx echo.Context) error
+	RefreshSession(ctx echo.Context) error
+	GetSessions(ctx echo.Context) error
 	Profile(ctx echo.Context) error
 	ImageUploadProfile(ctx echo.Context) error
+	RegenerateAvatar(ctx echo.Context) error
 	UpdateProfile(ctx echo.Context) error
+	CheckUsernameAvailability(ctx echo.Context) error
+	ChangeUsername(ctx echo.Context) error
+	ChangeTimezone(ctx echo.Context) error
+	ChangeLanguage(ctx echo.Context) error
+	ChangePrivacySettings(ctx echo.Context) error
 }
 
 type AuthHandler struct {
 	userService port.UserServiceInterface
 	validator   *myvalidator.Validator
+	config      *config.Config
+	// cdn serves profile photos from the CDN when one is configured
+	cdn *cdn.Rewriter
 }
 
 func (a *AuthHandler) SignIn(c echo.Context) error {
 	var (
-		req        = request.SignInRequest{}
-		resp       = response.DefaultResponse{}
-		respSignIn = response.SignInResponse{}
-		ctx        = c.Request().Context()
+		req  = request.SignInRequest{}
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
 	)
 
 	if err := c.Bind(&req); err != nil {
@@ -49,7 +68,7 @@ func (a *AuthHandler) SignIn(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, resp)
 	}
 
-	if err := a.validator.Validate(&req); err != nil {
+	if err := a.validator.ValidateContext(c.Request().Context(), &req); err != nil {
 		log.Error().Err(err).Msg("[AuthHandler-SignIn] Validation failed")
 		resp.Message = err.Error()
 		return c.JSON(http.StatusBadRequest, resp)
@@ -59,10 +78,31 @@ func (a *AuthHandler) SignIn(c echo.Context) error {
 		Email:    req.Email,
 		Password: req.Password,
 	}
+	if req.Email == "" {
+		userEntity.Username = req.Username
+	}
 
-	user, token, err := a.userService.SignIn(ctx, userEntity)
+	client := entity.ClientEntity{
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+		DeviceID:  c.Request().Header.Get(middleware.DeviceIDHeader),
+	}
+
+	user, token, err := a.userService.SignIn(ctx, userEntity, client, req.RememberMe)
 	if err != nil {
-		log.Error().Err(err).Str("email", req.Email).Msg("[AuthHandler-SignIn] Sign in failed")
+		var stepUp *entity.StepUpRequiredError
+		if errors.As(err, &stepUp) {
+			log.Warn().Str("email", req.Email).Str("username", req.Username).Msg("[AuthHandler-SignIn] Step-up verification required")
+			resp.Message = "Additional verification required, a code has been sent to your email"
+			resp.Data = response.StepUpRequiredResponse{
+				StepUpRequired: true,
+				ChallengeID:    stepUp.ChallengeID,
+				ExpiresAt:      stepUp.ExpiresAt,
+			}
+			return c.JSON(http.StatusUnauthorized, resp)
+		}
+
+		log.Error().Err(err).Str("email", req.Email).Str("username", req.Username).Msg("[AuthHandler-SignIn] Sign in failed")
 
 		switch err.Error() {
 		case "user not found":
@@ -71,6 +111,74 @@ func (a *AuthHandler) SignIn(c echo.Context) error {
 		case "incorrect password":
 			resp.Message = "Incorrect password"
 			return c.JSON(http.StatusUnauthorized, resp)
+		case "account is locked":
+			resp.Message = "Account is locked pending review. Please contact support."
+			return c.JSON(http.StatusForbidden, resp)
+		case "password expired":
+			resp.Message = "Your password has expired. Please set a new one with forgot password."
+			return c.JSON(http.StatusForbidden, resp)
+		case "failed to generate token":
+			resp.Message = "Authentication failed"
+			return c.JSON(http.StatusInternalServerError, resp)
+		case "failed to send verification code":
+			resp.Message = "Unable to send verification code, please try again later"
+			return c.JSON(http.StatusServiceUnavailable, resp)
+		default:
+			resp.Message = "Internal server error"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	log.Info().Str("email", user.Email).Int64("user_id", user.ID).Msg("[AuthHandler-SignIn] User signed in successfully")
+	return respondSignedIn(c, a.config, user, token)
+}
+
+// VerifySignInOTP completes a sign-in that the risk engine held back for an email code
+func (a *AuthHandler) VerifySignInOTP(c echo.Context) error {
+	var (
+		req  = request.VerifySignInOTPRequest{}
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("[AuthHandler-VerifySignInOTP] Failed to bind request")
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := a.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		log.Error().Err(err).Msg("[AuthHandler-VerifySignInOTP] Validation failed")
+		resp.Message = err.Error()
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	client := entity.ClientEntity{
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+		DeviceID:  c.Request().Header.Get(middleware.DeviceIDHeader),
+	}
+
+	user, token, err := a.userService.VerifySignInOTP(ctx, req.ChallengeID, req.OTP, client)
+	if err != nil {
+		log.Error().Err(err).Msg("[AuthHandler-VerifySignInOTP] Verification failed")
+
+		switch err.Error() {
+		case "verification challenge not found":
+			resp.Message = "Verification code has expired, please sign in again"
+			return c.JSON(http.StatusUnauthorized, resp)
+		case "invalid verification code":
+			resp.Message = "Invalid verification code"
+			return c.JSON(http.StatusUnauthorized, resp)
+		case "too many verification attempts":
+			resp.Message = "Too many attempts, please sign in again"
+			return c.JSON(http.StatusTooManyRequests, resp)
+		case "account is locked":
+			resp.Message = "Account is locked pending review. Please contact support."
+			return c.JSON(http.StatusForbidden, resp)
+		case "user not found":
+			resp.Message = "User not found"
+			return c.JSON(http.StatusNotFound, resp)
 		case "failed to generate token":
 			resp.Message = "Authentication failed"
 			return c.JSON(http.StatusInternalServerError, resp)
@@ -80,11 +188,34 @@ func (a *AuthHandler) SignIn(c echo.Context) error {
 		}
 	}
 
+	log.Info().Int64("user_id", user.ID).Msg("[AuthHandler-VerifySignInOTP] User signed in successfully")
+	return respondSignedIn(c, a.config, user, token)
+}
+
+// respondSignedIn writes the sign-in payload, moving the token into cookies for web clients
+func respondSignedIn(c echo.Context, cfg *config.Config, user *entity.UserEntity, token string) error {
+	var (
+		resp       = response.DefaultResponse{}
+		respSignIn = response.SignInResponse{}
+	)
+
 	respSignIn.AccessToken = token
+	if middleware.UsesCookieSession(c, cfg.Security) {
+		csrfToken, err := issueSessionCookies(c, cfg, token)
+		if err != nil {
+			log.Error().Err(err).Int64("user_id", user.ID).Msg("[AuthHandler-respondSignedIn] Failed to issue CSRF token")
+			resp.Message = "Authentication failed"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+		// Web clients never see the JWT; it only lives in the httpOnly cookie
+		respSignIn.AccessToken = ""
+		respSignIn.CSRFToken = csrfToken
+	}
 	respSignIn.Role = user.RoleName
 	respSignIn.ID = user.ID
 	respSignIn.Name = user.Name
 	respSignIn.Email = user.Email
+	respSignIn.Username = user.Username
 	respSignIn.Phone = user.Phone
 	respSignIn.Lat = user.Lat
 	respSignIn.Lng = user.Lng
@@ -92,17 +223,15 @@ func (a *AuthHandler) SignIn(c echo.Context) error {
 	resp.Message = "Sign in successful"
 	resp.Data = respSignIn
 
-	log.Info().Str("email", req.Email).Int64("user_id", user.ID).Msg("[AuthHandler-SignIn] User signed in successfully")
-
 	return c.JSON(http.StatusOK, resp)
 }
 
 func (a *AuthHandler) CreateUserAccount(c echo.Context) error {
 	var (
-		req         = request.CreateUserAccountRequest{}
-		resp        = response.DefaultResponse{}
-		respCreate  = response.CreateUserAccountResponse{}
-		ctx         = c.Request().Context()
+		req        = request.CreateUserAccountRequest{}
+		resp       = response.DefaultResponse{}
+		respCreate = response.CreateUserAccountResponse{}
+		ctx        = c.Request().Context()
 	)
 
 	if err := c.Bind(&req); err != nil {
@@ -111,7 +240,7 @@ func (a *AuthHandler) CreateUserAccount(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, resp)
 	}
 
-	if err := a.validator.Validate(&req); err != nil {
+	if err := a.validator.ValidateContext(c.Request().Context(), &req); err != nil {
 		log.Error().Err(err).Msg("[AuthHandler-CreateUserAccount] Validation failed")
 
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
@@ -168,7 +297,15 @@ func (a *AuthHandler) CreateUserAccount(c echo.Context) error {
 		return c.JSON(http.StatusUnprocessableEntity, resp)
 	}
 
-	err := a.userService.CreateUserAccount(ctx, req.Email, req.Name, req.Password, req.PasswordConfirmation)
+	var consent *entity.ConsentEntity
+	if req.AcceptTerms {
+		consent = &entity.ConsentEntity{
+			IPAddress: c.RealIP(),
+			UserAgent: c.Request().UserAgent(),
+		}
+	}
+
+	err := a.userService.CreateUserAccount(ctx, req.Email, req.Name, req.Password, req.PasswordConfirmation, req.InviteCode, req.Lat, req.Lng, consent)
 	if err != nil {
 		log.Error().Err(err).Str("email", req.Email).Msg("[AuthHandler-CreateUserAccount] Account creation failed")
 
@@ -176,12 +313,33 @@ func (a *AuthHandler) CreateUserAccount(c echo.Context) error {
 		case "invalid email format":
 			resp.Message = "Invalid email format"
 			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "disposable email addresses are not allowed":
+			resp.Message = "Disposable email addresses are not allowed"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
 		case "password is required", "password must be at least 8 characters long", "password confirmation does not match":
 			resp.Message = err.Error()
 			return c.JSON(http.StatusUnprocessableEntity, resp)
 		case "email already exists":
 			resp.Message = "Email already exists"
 			return c.JSON(http.StatusConflict, resp)
+		case "invite code is required":
+			resp.Message = "An invite code is required to sign up"
+			return c.JSON(http.StatusForbidden, resp)
+		case "invalid invite code", "invite code has expired", "invite code has been used up":
+			resp.Message = err.Error()
+			return c.JSON(http.StatusForbidden, resp)
+		case "terms must be accepted":
+			resp.Message = "You must accept the terms of service and privacy policy"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "invalid coordinates":
+			resp.Message = "Invalid coordinates"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "location is outside our delivery area":
+			resp.Message = "Sorry, we do not deliver to your location yet"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "unable to verify delivery coverage":
+			resp.Message = "Unable to verify delivery coverage"
+			return c.JSON(http.StatusInternalServerError, resp)
 		case "failed to create account", "failed to generate verification token", "failed to create verification token":
 			resp.Message = "Failed to create account"
 			return c.JSON(http.StatusInternalServerError, resp)
@@ -216,12 +374,15 @@ func (a *AuthHandler) VerifyUserAccount(c echo.Context) error {
 
 	err := a.userService.VerifyUserAccount(ctx, token)
 	if err != nil {
-		log.Error().Err(err).Str("token", token).Msg("[AuthHandler-VerifyUserAccount] Account verification failed")
+		log.Error().Err(err).Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthHandler-VerifyUserAccount] Account verification failed")
 
 		switch err.Error() {
 		case "invalid or expired verification token":
 			resp.Message = "Invalid or expired verification token"
 			return c.JSON(http.StatusBadRequest, resp)
+		case "token already used":
+			resp.Message = "This link has already been used"
+			return c.JSON(http.StatusGone, resp)
 		case "failed to verify token", "failed to verify account":
 			resp.Message = "Failed to verify account"
 			return c.JSON(http.StatusInternalServerError, resp)
@@ -232,7 +393,7 @@ func (a *AuthHandler) VerifyUserAccount(c echo.Context) error {
 	}
 
 	resp.Message = "Account verified successfully. You can now sign in."
-	log.Info().Str("token", token).Msg("[AuthHandler-VerifyUserAccount] User account verified successfully")
+	log.Info().Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthHandler-VerifyUserAccount] User account verified successfully")
 
 	return c.JSON(http.StatusOK, resp)
 }
@@ -250,7 +411,7 @@ func (a *AuthHandler) ForgotPassword(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, resp)
 	}
 
-	if err := a.validator.Validate(&req); err != nil {
+	if err := a.validator.ValidateContext(c.Request().Context(), &req); err != nil {
 		log.Error().Err(err).Msg("[AuthHandler-ForgotPassword] Validation failed")
 		resp.Message = err.Error()
 		return c.JSON(http.StatusBadRequest, resp)
@@ -279,6 +440,47 @@ func (a *AuthHandler) ForgotPassword(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// ResendVerificationEmail answers the same for unknown and verified addresses; only a
+// bounced address gets its own error, since another link to it would bounce again
+func (a *AuthHandler) ResendVerificationEmail(c echo.Context) error {
+	var (
+		req  = request.ResendVerificationRequest{}
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("[AuthHandler-ResendVerificationEmail] Failed to bind request")
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := a.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		log.Error().Err(err).Msg("[AuthHandler-ResendVerificationEmail] Validation failed")
+		resp.Message = err.Error()
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := a.userService.ResendVerificationEmail(ctx, req.Email); err != nil {
+		log.Error().Err(err).Str("email", req.Email).Msg("[AuthHandler-ResendVerificationEmail] Resend failed")
+
+		switch err.Error() {
+		case "invalid email format":
+			resp.Message = "Invalid email format"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "email address is undeliverable":
+			resp.Message = "Emails to this address bounced. Please sign up again with a different email address."
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		default:
+			resp.Message = "Failed to process request"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	resp.Message = "If an unverified account with this email exists, a new verification link has been sent."
+	return c.JSON(http.StatusOK, resp)
+}
+
 func (a *AuthHandler) ResetPassword(c echo.Context) error {
 	var (
 		req  = request.ResetPasswordRequest{}
@@ -292,7 +494,7 @@ func (a *AuthHandler) ResetPassword(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, resp)
 	}
 
-	if err := a.validator.Validate(&req); err != nil {
+	if err := a.validator.ValidateContext(c.Request().Context(), &req); err != nil {
 		log.Error().Err(err).Msg("[AuthHandler-ResetPassword] Validation failed")
 
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
@@ -334,21 +536,30 @@ func (a *AuthHandler) ResetPassword(c echo.Context) error {
 
 	err := a.userService.ResetPassword(ctx, req.Token, req.Password, req.PasswordConfirmation)
 	if err != nil {
-		log.Error().Err(err).Str("token", req.Token).Msg("[AuthHandler-ResetPassword] Password reset failed")
+		log.Error().Err(err).Str("token_fingerprint", utils.SecretFingerprint(req.Token)).Msg("[AuthHandler-ResetPassword] Password reset failed")
 
 		switch err.Error() {
 		case "invalid or expired reset token":
 			resp.Message = "Invalid or expired reset token"
 			return c.JSON(http.StatusBadRequest, resp)
+		case "token already used":
+			resp.Message = "This link has already been used"
+			return c.JSON(http.StatusGone, resp)
 		case "invalid token type":
 			resp.Message = "Invalid token type"
 			return c.JSON(http.StatusBadRequest, resp)
 		case "password is required", "password must be at least 8 characters long", "password confirmation does not match":
 			resp.Message = err.Error()
 			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "password was used recently":
+			resp.Message = "This password was used recently, please choose a different one"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
 		case "failed to validate token", "failed to process password", "failed to update password":
 			resp.Message = "Failed to reset password"
 			return c.JSON(http.StatusInternalServerError, resp)
+		case "failed to revoke sessions":
+			resp.Message = "Password was changed, but signing out other devices failed. Please open the reset link again."
+			return c.JSON(http.StatusInternalServerError, resp)
 		default:
 			resp.Message = "Internal server error"
 			return c.JSON(http.StatusInternalServerError, resp)
@@ -356,7 +567,7 @@ func (a *AuthHandler) ResetPassword(c echo.Context) error {
 	}
 
 	resp.Message = "Password reset successfully. You can now sign in with your new password."
-	log.Info().Str("token", req.Token).Msg("[AuthHandler-ResetPassword] Password reset successfully")
+	log.Info().Str("token_fingerprint", utils.SecretFingerprint(req.Token)).Msg("[AuthHandler-ResetPassword] Password reset successfully")
 
 	return c.JSON(http.StatusOK, resp)
 }
@@ -370,9 +581,9 @@ func (a *AuthHandler) Logout(c echo.Context) error {
 	userID := c.Get("user_id").(int64)
 	sessionID := c.Get("session_id").(string)
 
-	// Get token from Authorization header for blacklist
+	// Get token from Authorization header (or the session cookie) for blacklist
+	tokenString, _ := c.Get("access_token").(string)
 	authHeader := c.Request().Header.Get("Authorization")
-	tokenString := ""
 	if strings.HasPrefix(authHeader, "Bearer ") {
 		tokenString = strings.TrimPrefix(authHeader, "Bearer ")
 	}
@@ -397,12 +608,126 @@ func (a *AuthHandler) Logout(c echo.Context) error {
 		}
 	}
 
+	middleware.ClearSessionCookies(c, a.config.Security)
 	resp.Message = "Logout successful"
 	log.Info().Int64("user_id", userID).Str("session_id", sessionID).Msg("[AuthHandler-Logout] User logged out successfully")
 
 	return c.JSON(http.StatusOK, resp)
 }
 
+// RefreshSession re-issues the token for the current session; cookie clients get a fresh cookie
+func (a *AuthHandler) RefreshSession(c echo.Context) error {
+	var (
+		resp       = response.DefaultResponse{}
+		respSignIn = response.SignInResponse{}
+		ctx        = c.Request().Context()
+	)
+
+	userID := c.Get("user_id").(int64)
+	sessionID, _ := c.Get("session_id").(string)
+	tokenString, _ := c.Get("access_token").(string)
+	tokenExpiresAt, _ := c.Get("exp").(int64)
+
+	user, token, err := a.userService.RefreshSession(ctx, userID, sessionID, tokenString, tokenExpiresAt)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Str("session_id", sessionID).Msg("[AuthHandler-RefreshSession] Refresh failed")
+
+		switch err.Error() {
+		case "session required":
+			resp.Message = "Token is not bound to a session, please sign in again"
+			return c.JSON(http.StatusUnauthorized, resp)
+		case "user not found":
+			resp.Message = "User not found"
+			return c.JSON(http.StatusNotFound, resp)
+		default:
+			resp.Message = "Failed to refresh session"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	respSignIn.AccessToken = token
+	if middleware.UsesCookieSession(c, a.config.Security) {
+		csrfToken, err := issueSessionCookies(c, a.config, token)
+		if err != nil {
+			log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-RefreshSession] Failed to issue CSRF token")
+			resp.Message = "Failed to refresh session"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+		respSignIn.AccessToken = ""
+		respSignIn.CSRFToken = csrfToken
+	}
+	respSignIn.Role = user.RoleName
+	respSignIn.ID = user.ID
+	respSignIn.Name = user.Name
+	respSignIn.Email = user.Email
+	respSignIn.Username = user.Username
+	respSignIn.Phone = user.Phone
+	respSignIn.Lat = user.Lat
+	respSignIn.Lng = user.Lng
+
+	resp.Message = "Session refreshed successfully"
+	resp.Data = respSignIn
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (a *AuthHandler) GetSessions(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	userID := c.Get("user_id").(int64)
+	currentSessionID, _ := c.Get("session_id").(string)
+	page, limit := pageQuery(c)
+
+	sessions, pagination, err := a.userService.GetSessions(c.Request().Context(), userID, page, limit)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-GetSessions] Failed to get sessions")
+		resp.Message = "Failed to retrieve sessions"
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	items := make([]response.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		deviceName := session.DeviceName
+		if deviceName == "" {
+			deviceName = session.UserAgent
+		}
+		location := entity.LoginLocationEntity{City: session.City, Country: session.Country}
+		items = append(items, response.SessionResponse{
+			SessionID:  session.SessionID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			DeviceName: deviceName,
+			Location:   location.Place(),
+			Current:    session.SessionID == currentSessionID,
+			CreatedAt:  session.CreatedAt,
+			ExpiresAt:  session.ExpiresAt,
+		})
+	}
+
+	return respondPage(c, "Sessions retrieved successfully", items, pagination)
+}
+
+// issueSessionCookies sets the httpOnly session cookie and, when CSRF is enabled, a fresh double-submit token
+func issueSessionCookies(c echo.Context, cfg *config.Config, token string) (string, error) {
+	// The cookies expire with the token, whose lifetime depends on remember me
+	claims, err := utils.ValidateJWT(cfg, token)
+	if err != nil {
+		return "", err
+	}
+	lifetime := time.Until(claims.ExpiresAt.Time)
+
+	middleware.SetSessionCookie(c, cfg.Security, token, lifetime)
+	if !cfg.Security.CSRFEnabled {
+		return "", nil
+	}
+
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	csrfToken := hex.EncodeToString(bytes)
+	middleware.SetCSRFCookie(c, cfg.Security, csrfToken, lifetime)
+	return csrfToken, nil
+}
+
 func (a *AuthHandler) Profile(c echo.Context) error {
 	var (
 		resp = response.DefaultResponse{}
@@ -426,17 +751,30 @@ func (a *AuthHandler) Profile(c echo.Context) error {
 	}
 
 	profileResp := response.ProfileResponse{
-		ID:      user.ID,
-		Email:   user.Email,
-		Role:    user.RoleName,
-		Name:    user.Name,
-		Phone:   user.Phone,
-		Address: user.Address,
-		Lat:     user.Lat,
-		Lng:     user.Lng,
-		Photo:   user.Photo,
+		ID:         user.ID,
+		Email:      user.Email,
+		Username:   user.Username,
+		Timezone:   utils.UserLocation(user.Timezone).String(),
+		Language:   user.Language,
+		Role:       user.RoleName,
+		Name:       user.Name,
+		Phone:      user.Phone,
+		Address:    user.Address,
+		Province:   user.Province,
+		City:       user.City,
+		District:   user.District,
+		PostalCode: user.PostalCode,
+		Lat:        user.Lat,
+		Lng:        user.Lng,
+		Photo:      a.cdn.PublicURL(user.Photo),
+		Version:    user.Version,
+		Privacy: response.PrivacySettingsResponse{
+			HidePhone:   user.Privacy.HidePhone,
+			HideAddress: user.Privacy.HideAddress,
+		},
 	}
 
+	setETag(c, user.Version)
 	resp.Message = "Profile retrieved successfully"
 	resp.Data = profileResp
 
@@ -461,22 +799,23 @@ func (a *AuthHandler) ImageUploadProfile(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, resp)
 	}
 
-	// Basic validation before opening file
-	log.Info().Int64("user_id", userID).Int64("file_size", file.Size).Str("filename", file.Filename).Str("content_type", file.Header.Get("Content-Type")).Msg("[AuthHandler-ImageUploadProfile] Starting file validation")
-
-	if file.Size == 0 {
-		log.Error().Int64("user_id", userID).Msg("[AuthHandler-ImageUploadProfile] File size is 0 - rejecting")
-		resp.Message = "File is empty"
+	editReq := request.ProfilePhotoEditRequest{}
+	if err := c.Bind(&editReq); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-ImageUploadProfile] Failed to bind photo edit")
+		resp.Message = "Invalid request format"
 		return c.JSON(http.StatusBadRequest, resp)
 	}
-
-	if file.Size > 5<<20 { // 5MB
-		log.Error().Int64("user_id", userID).Int64("file_size", file.Size).Msg("[AuthHandler-ImageUploadProfile] File size too large")
-		resp.Message = "File size too large, maximum 5MB"
-		return c.JSON(http.StatusBadRequest, resp)
+	if err := a.validator.ValidateContext(ctx, &editReq); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-ImageUploadProfile] Photo edit validation failed")
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+	edit := entity.PhotoEditEntity{Rotation: editReq.Rotation}
+	if editReq.CropX != nil {
+		edit.Crop = &entity.PhotoCropEntity{X: *editReq.CropX, Y: *editReq.CropY, Width: *editReq.CropWidth, Height: *editReq.CropHeight}
 	}
 
-	log.Info().Int64("user_id", userID).Int64("file_size", file.Size).Msg("[AuthHandler-ImageUploadProfile] File size validation passed")
+	log.Info().Int64("user_id", userID).Int64("file_size", file.Size).Str("filename", file.Filename).Str("content_type", file.Header.Get("Content-Type")).Msg("[AuthHandler-ImageUploadProfile] Starting file validation")
 
 	// Open the uploaded file
 	src, err := file.Open()
@@ -487,37 +826,54 @@ func (a *AuthHandler) ImageUploadProfile(c echo.Context) error {
 	}
 	defer src.Close()
 
-	// Validate image file using storage validation function
-	log.Info().Int64("user_id", userID).Msg("[AuthHandler-ImageUploadProfile] Starting ValidateImageFile")
-	if err := storage.ValidateImageFile(src, file); err != nil {
-		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-ImageUploadProfile] File validation failed")
-		resp.Message = err.Error()
-		return c.JSON(http.StatusBadRequest, resp)
-	}
-	log.Info().Int64("user_id", userID).Msg("[AuthHandler-ImageUploadProfile] ValidateImageFile passed")
-
-	// Reset file pointer after validation (important!)
-	log.Info().Int64("user_id", userID).Msg("[AuthHandler-ImageUploadProfile] Resetting file pointer")
-	if seeker, ok := src.(io.Seeker); ok {
-		if _, err := seeker.Seek(0, io.SeekStart); err != nil {
-			log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-ImageUploadProfile] Failed to reset file pointer")
-			resp.Message = "Failed to process file"
+	// Size, extension and sniffed content; the sniffed type is what gets stored
+	contentType, err := storage.ValidateImageFile(src, file)
+	if err != nil {
+		log.Warn().Err(err).Int64("user_id", userID).Int64("file_size", file.Size).Str("filename", file.Filename).Msg("[AuthHandler-ImageUploadProfile] File validation failed")
+		switch {
+		case errors.Is(err, storage.ErrImageEmpty):
+			resp.Message = "File is empty"
+			return c.JSON(http.StatusBadRequest, resp)
+		case errors.Is(err, storage.ErrImageTooLarge):
+			resp.Message = "File size too large, maximum 5MB"
+			return c.JSON(http.StatusRequestEntityTooLarge, resp)
+		case errors.Is(err, storage.ErrImageType):
+			resp.Message = "Invalid file type, only JPEG, PNG, GIF and WebP images are allowed"
+			return c.JSON(http.StatusUnsupportedMediaType, resp)
+		default:
+			resp.Message = "Failed to process uploaded file"
 			return c.JSON(http.StatusInternalServerError, resp)
 		}
-		log.Info().Int64("user_id", userID).Msg("[AuthHandler-ImageUploadProfile] File pointer reset successfully")
-	} else {
-		log.Warn().Int64("user_id", userID).Msg("[AuthHandler-ImageUploadProfile] File does not support seeking")
 	}
+	log.Info().Int64("user_id", userID).Str("content_type", contentType).Msg("[AuthHandler-ImageUploadProfile] File validation passed")
 
 	// Upload image
-	imageURL, err := a.userService.UploadProfileImage(ctx, userID, src, file.Header.Get("Content-Type"), file.Filename)
+	photo, err := a.userService.UploadProfilePhoto(ctx, userID, src, contentType, file.Filename, edit)
 	if err != nil {
 		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-ImageUploadProfile] Failed to upload profile image")
 
 		switch err.Error() {
+		case utils.ErrPhotoUnreadable.Error():
+			resp.Message = "Image cannot be read"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case utils.ErrPhotoTooLarge.Error():
+			resp.Message = "Image dimensions too large"
+			return c.JSON(http.StatusRequestEntityTooLarge, resp)
+		case utils.ErrPhotoRotation.Error():
+			resp.Message = "Rotation must be 0, 90, 180 or 270"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case utils.ErrPhotoCrop.Error():
+			resp.Message = "Crop is outside the image"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
 		case "failed to upload image":
 			resp.Message = "Failed to upload image to storage"
 			return c.JSON(http.StatusInternalServerError, resp)
+		case "file is infected":
+			resp.Message = "File was rejected by the virus scanner"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "file scanner is unavailable":
+			resp.Message = "File scanning is temporarily unavailable, please try again later"
+			return c.JSON(http.StatusServiceUnavailable, resp)
 		case "failed to update profile":
 			resp.Message = "Failed to update profile"
 			return c.JSON(http.StatusInternalServerError, resp)
@@ -528,14 +884,261 @@ func (a *AuthHandler) ImageUploadProfile(c echo.Context) error {
 	}
 
 	imageResp := response.ImageUploadResponse{
-		ImageURL: imageURL,
+		ImageURL:    a.cdn.PublicURL(photo.URL),
+		OriginalURL: a.cdn.PublicURL(photo.OriginalURL),
 	}
 
 	resp.Message = "Profile image uploaded successfully"
 	resp.Data = imageResp
 
-	log.Info().Int64("user_id", userID).Str("image_url", imageURL).Msg("[AuthHandler-ImageUploadProfile] Profile image uploaded successfully")
+	log.Info().Int64("user_id", userID).Str("image_url", photo.URL).Msg("[AuthHandler-ImageUploadProfile] Profile image uploaded successfully")
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RegenerateAvatar swaps the current photo for a freshly generated avatar
+func (a *AuthHandler) RegenerateAvatar(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	userID := c.Get("user_id").(int64)
+
+	imageURL, err := a.userService.RegenerateAvatar(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-RegenerateAvatar] Failed to regenerate avatar")
+
+		switch err.Error() {
+		case "failed to upload image":
+			resp.Message = "Failed to upload image to storage"
+			return c.JSON(http.StatusInternalServerError, resp)
+		case "failed to update profile":
+			resp.Message = "Failed to update profile"
+			return c.JSON(http.StatusInternalServerError, resp)
+		default:
+			resp.Message = "Internal server error"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	resp.Message = "Avatar regenerated successfully"
+	resp.Data = response.ImageUploadResponse{ImageURL: a.cdn.PublicURL(imageURL)}
+
+	log.Info().Int64("user_id", userID).Str("image_url", imageURL).Msg("[AuthHandler-RegenerateAvatar] Avatar regenerated successfully")
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CheckUsernameAvailability is public so the signup and profile forms can check while typing
+func (a *AuthHandler) CheckUsernameAvailability(c echo.Context) error {
+	var (
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	username := c.QueryParam("username")
+	if username == "" {
+		resp.Message = "Username is required"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	availability, err := a.userService.CheckUsernameAvailability(ctx, username)
+	if err != nil {
+		log.Error().Err(err).Str("username", username).Msg("[AuthHandler-CheckUsernameAvailability] Failed to check username")
+		resp.Message = "Internal server error"
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "Username availability retrieved successfully"
+	resp.Data = response.UsernameAvailabilityResponse{
+		Username:  availability.Username,
+		Available: availability.Available,
+		Reason:    availability.Reason,
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (a *AuthHandler) ChangeUsername(c echo.Context) error {
+	var (
+		req  = request.ChangeUsernameRequest{}
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	userID := c.Get("user_id").(int64)
+
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-ChangeUsername] Failed to bind request")
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := a.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-ChangeUsername] Validation failed")
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	username, err := a.userService.ChangeUsername(ctx, userID, req.Username)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Str("username", req.Username).Msg("[AuthHandler-ChangeUsername] Failed to change username")
+
+		switch err.Error() {
+		case utils.ErrInvalidUsername.Error():
+			resp.Message = "Username must be 3-30 characters, start with a letter and contain only letters, numbers, '.' or '_'"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case utils.ErrReservedUsername.Error():
+			resp.Message = "Username is reserved"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "username already taken":
+			resp.Message = "Username already taken"
+			return c.JSON(http.StatusConflict, resp)
+		case "user not found":
+			resp.Message = "User not found"
+			return c.JSON(http.StatusNotFound, resp)
+		default:
+			resp.Message = "Internal server error"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	resp.Message = "Username updated successfully"
+	resp.Data = response.UsernameResponse{Username: username}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ChangeTimezone sets the IANA timezone used for times shown to the user, such as in emails
+func (a *AuthHandler) ChangeTimezone(c echo.Context) error {
+	var (
+		req  = request.ChangeTimezoneRequest{}
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	userID := c.Get("user_id").(int64)
+
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-ChangeTimezone] Failed to bind request")
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := a.validator.ValidateContext(ctx, &req); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-ChangeTimezone] Validation failed")
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	timezone, err := a.userService.ChangeTimezone(ctx, userID, req.Timezone)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Str("timezone", req.Timezone).Msg("[AuthHandler-ChangeTimezone] Failed to change timezone")
+
+		switch err.Error() {
+		case utils.ErrInvalidTimezone.Error():
+			resp.Message = "Timezone must be an IANA name such as Asia/Jakarta"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "user not found":
+			resp.Message = "User not found"
+			return c.JSON(http.StatusNotFound, resp)
+		default:
+			resp.Message = "Internal server error"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	resp.Message = "Timezone updated successfully"
+	resp.Data = response.TimezoneResponse{Timezone: timezone}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ChangeLanguage sets the language of emails sent to the user, independent of Accept-Language
+func (a *AuthHandler) ChangeLanguage(c echo.Context) error {
+	var (
+		req  = request.ChangeLanguageRequest{}
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	userID := c.Get("user_id").(int64)
+
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-ChangeLanguage] Failed to bind request")
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := a.validator.ValidateContext(ctx, &req); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-ChangeLanguage] Validation failed")
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	language, err := a.userService.ChangeLanguage(ctx, userID, req.Language)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Str("language", req.Language).Msg("[AuthHandler-ChangeLanguage] Failed to change language")
+
+		switch err.Error() {
+		case "unsupported language":
+			resp.Message = "Language must be en or id"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "user not found":
+			resp.Message = "User not found"
+			return c.JSON(http.StatusNotFound, resp)
+		default:
+			resp.Message = "Internal server error"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	resp.Message = "Language updated successfully"
+	resp.Data = response.LanguageResponse{Language: language}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ChangePrivacySettings sets what vendors may see of the user's contact details
+func (a *AuthHandler) ChangePrivacySettings(c echo.Context) error {
+	var (
+		req  = request.ChangePrivacySettingsRequest{}
+		resp = response.DefaultResponse{}
+		ctx  = c.Request().Context()
+	)
+
+	userID := c.Get("user_id").(int64)
+
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-ChangePrivacySettings] Failed to bind request")
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := a.validator.ValidateContext(ctx, &req); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-ChangePrivacySettings] Validation failed")
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	settings, err := a.userService.ChangePrivacySettings(ctx, userID, entity.PrivacySettingsEntity{
+		HidePhone:   *req.HidePhone,
+		HideAddress: *req.HideAddress,
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-ChangePrivacySettings] Failed to change privacy settings")
+
+		switch err.Error() {
+		case "user not found":
+			resp.Message = "User not found"
+			return c.JSON(http.StatusNotFound, resp)
+		default:
+			resp.Message = "Internal server error"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
 
+	resp.Message = "Privacy settings updated successfully"
+	resp.Data = response.PrivacySettingsResponse{
+		HidePhone:   settings.HidePhone,
+		HideAddress: settings.HideAddress,
+	}
 	return c.JSON(http.StatusOK, resp)
 }
 
@@ -554,7 +1157,7 @@ func (a *AuthHandler) UpdateProfile(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, resp)
 	}
 
-	if err := a.validator.Validate(&req); err != nil {
+	if err := a.validator.ValidateContext(c.Request().Context(), &req); err != nil {
 		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthHandler-UpdateProfile] Validation failed")
 
 		if validationErrors, ok := err.(validator.ValidationErrors); ok {
@@ -600,11 +1203,19 @@ func (a *AuthHandler) UpdateProfile(c echo.Context) error {
 						resp.Message = "Latitude is required"
 						return c.JSON(http.StatusUnprocessableEntity, resp)
 					}
+					if tag == "latitude" {
+						resp.Message = "Latitude must be between -90 and 90"
+						return c.JSON(http.StatusUnprocessableEntity, resp)
+					}
 				case "Lng":
 					if tag == "required" {
 						resp.Message = "Longitude is required"
 						return c.JSON(http.StatusUnprocessableEntity, resp)
 					}
+					if tag == "longitude" {
+						resp.Message = "Longitude must be between -180 and 180"
+						return c.JSON(http.StatusUnprocessableEntity, resp)
+					}
 				case "Photo":
 					if tag == "required" {
 						resp.Message = "Photo is required"
@@ -618,14 +1229,43 @@ func (a *AuthHandler) UpdateProfile(c echo.Context) error {
 		return c.JSON(http.StatusUnprocessableEntity, resp)
 	}
 
-	err := a.userService.UpdateProfile(ctx, userID, req.Name, req.Email, req.Phone, req.Address, req.Lat, req.Lng, req.Photo)
+	version, err := expectedVersion(c, req.Version)
+	if err != nil {
+		_, result := respondVersionError(c, err)
+		return result
+	}
+	if version == 0 {
+		return versionRequired(c)
+	}
+
+	// Clients send back the photo URL they were given, which may be the CDN's
+	err = a.userService.UpdateProfile(ctx, userID, req.Name, req.Email, req.Phone, req.Address, req.Lat, req.Lng, a.cdn.StorageURL(req.Photo), version)
 	if err != nil {
 		log.Error().Err(err).Int64("user_id", userID).Str("email", req.Email).Msg("[AuthHandler-UpdateProfile] Profile update failed")
 
+		if handled, result := respondVersionError(c, err); handled {
+			return result
+		}
+
 		switch err.Error() {
+		case "invalid email format":
+			resp.Message = "Invalid email format"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "disposable email addresses are not allowed":
+			resp.Message = "Disposable email addresses are not allowed"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
 		case "email already exists":
 			resp.Message = "Email already exists"
 			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "invalid coordinates":
+			resp.Message = "Invalid coordinates"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "location is outside our delivery area":
+			resp.Message = "Sorry, we do not deliver to your location yet"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "unable to verify delivery coverage":
+			resp.Message = "Unable to verify delivery coverage"
+			return c.JSON(http.StatusInternalServerError, resp)
 		case "user not found":
 			resp.Message = "User not found"
 			return c.JSON(http.StatusNotFound, resp)
@@ -671,15 +1311,21 @@ func (a *AuthHandler) VerifyEmailChange(c echo.Context) error {
 
 	err := a.userService.VerifyEmailChange(ctx, token)
 	if err != nil {
-		log.Error().Err(err).Str("token", token).Msg("[AuthHandler-VerifyEmailChange] Email change verification failed")
+		log.Error().Err(err).Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthHandler-VerifyEmailChange] Email change verification failed")
 
 		switch err.Error() {
 		case "invalid or expired verification token":
 			resp.Message = "Invalid or expired verification token"
 			return c.JSON(http.StatusBadRequest, resp)
+		case "token already used":
+			resp.Message = "This link has already been used"
+			return c.JSON(http.StatusGone, resp)
 		case "invalid token type":
 			resp.Message = "Invalid token type"
 			return c.JSON(http.StatusBadRequest, resp)
+		case "email already exists":
+			resp.Message = "Email already exists"
+			return c.JSON(http.StatusConflict, resp)
 		case "failed to verify email change":
 			resp.Message = "Failed to verify email change"
 			return c.JSON(http.StatusInternalServerError, resp)
@@ -690,14 +1336,16 @@ func (a *AuthHandler) VerifyEmailChange(c echo.Context) error {
 	}
 
 	resp.Message = "Email change verified successfully. You can now sign in with your new email."
-	log.Info().Str("token", token).Msg("[AuthHandler-VerifyEmailChange] Email change verified successfully")
+	log.Info().Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthHandler-VerifyEmailChange] Email change verified successfully")
 
 	return c.JSON(http.StatusOK, resp)
 }
 
-func NewAuthHandler(userService port.UserServiceInterface) AuthHandlerInterface {
+func NewAuthHandler(userService port.UserServiceInterface, cfg *config.Config) AuthHandlerInterface {
 	return &AuthHandler{
 		userService: userService,
 		validator:   myvalidator.NewValidator(),
+		config:      cfg,
+		cdn:         cdn.NewRewriter(cfg.Supabase.ProjectURL, cfg.CDN.BaseURL),
 	}
 }
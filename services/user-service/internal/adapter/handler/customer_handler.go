@@ -3,6 +3,8 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"user-service/config"
+	"user-service/internal/adapter/cdn"
 	"user-service/internal/core/port"
 
 	"github.com/labstack/echo/v4"
@@ -12,34 +14,22 @@ import (
 type CustomerHandlerInterface interface {
 	GetCustomers(c echo.Context) error
 	GetCustomerByID(c echo.Context) error
+	GetNearbyCustomers(c echo.Context) error
+	SuggestCustomers(c echo.Context) error
 }
 
 type CustomerHandler struct {
-	userService port.UserServiceInterface
+	userService    port.UserServiceInterface
+	suggestService port.CustomerSuggestServiceInterface
+	cdn            *cdn.Rewriter
+	policy         responsePolicy
 }
 
 func (h *CustomerHandler) GetCustomers(c echo.Context) error {
 	// Get query parameters
 	search := c.QueryParam("search")
-	pageStr := c.QueryParam("page")
-	limitStr := c.QueryParam("limit")
 	orderBy := c.QueryParam("orderBy")
-
-	// Parse page
-	page := 1
-	if pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
-			page = p
-		}
-	}
-
-	// Parse limit
-	limit := 10
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
-	}
+	page, limit := pageQuery(c)
 
 	// Get customers from service
 	customers, pagination, err := h.userService.GetCustomers(c.Request().Context(), search, page, limit, orderBy)
@@ -54,26 +44,19 @@ func (h *CustomerHandler) GetCustomers(c echo.Context) error {
 	// Transform customers to response format
 	var customerData []map[string]interface{}
 	for _, customer := range customers {
-		customerData = append(customerData, map[string]interface{}{
-			"id":     customer.ID,
-			"name":   customer.Name,
-			"photo":  customer.Photo,
-			"email":  customer.Email,
-			"phone":  customer.Phone,
-		})
+		customer, hidden := h.policy.customer(c, customer)
+		customerData = append(customerData, withHiddenFields(map[string]interface{}{
+			"id":       customer.ID,
+			"name":     customer.Name,
+			"photo":    h.cdn.PublicURL(customer.Photo),
+			"email":    customer.Email,
+			"username": customer.Username,
+			"phone":    customer.Phone,
+		}, hidden))
 	}
 
 	log.Info().Int("count", len(customers)).Int64("total_count", pagination.TotalCount).Str("search", search).Int("page", page).Int("limit", limit).Msg("[CustomerHandler-GetCustomers] Customers retrieved successfully")
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"message": "Customers retrieved successfully",
-		"data":    customerData,
-		"pagination": map[string]interface{}{
-			"page":        pagination.Page,
-			"total_count": pagination.TotalCount,
-			"per_page":    pagination.PerPage,
-			"total_page":  pagination.TotalPage,
-		},
-	})
+	return respondPage(c, "Customers retrieved successfully", customerData, pagination)
 }
 
 func (h *CustomerHandler) GetCustomerByID(c echo.Context) error {
@@ -102,17 +85,24 @@ func (h *CustomerHandler) GetCustomerByID(c echo.Context) error {
 		})
 	}
 
-	customerData := map[string]interface{}{
-		"id":      customer.ID,
-		"name":    customer.Name,
-		"email":   customer.Email,
-		"phone":   customer.Phone,
-		"photo":   customer.Photo,
-		"address": customer.Address,
-		"lat":     customer.Lat,
-		"lng":     customer.Lng,
-		"role_id": customer.RoleID,
-	}
+	shaped, hidden := h.policy.customer(c, *customer)
+	customer = &shaped
+	customerData := withHiddenFields(map[string]interface{}{
+		"id":          customer.ID,
+		"name":        customer.Name,
+		"email":       customer.Email,
+		"username":    customer.Username,
+		"phone":       customer.Phone,
+		"photo":       h.cdn.PublicURL(customer.Photo),
+		"address":     customer.Address,
+		"province":    customer.Province,
+		"city":        customer.City,
+		"district":    customer.District,
+		"postal_code": customer.PostalCode,
+		"lat":         customer.Lat,
+		"lng":         customer.Lng,
+		"role_id":     customer.RoleID,
+	}, hidden)
 
 	log.Info().Int64("customer_id", customerID).Msg("[CustomerHandler-GetCustomerByID] Customer retrieved successfully")
 	return c.JSON(http.StatusOK, map[string]interface{}{
@@ -121,8 +111,124 @@ func (h *CustomerHandler) GetCustomerByID(c echo.Context) error {
 	})
 }
 
-func NewCustomerHandler(userService port.UserServiceInterface) CustomerHandlerInterface {
+func (h *CustomerHandler) GetNearbyCustomers(c echo.Context) error {
+	lat, errLat := strconv.ParseFloat(c.QueryParam("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.QueryParam("lng"), 64)
+	if errLat != nil || errLng != nil {
+		log.Warn().Str("lat", c.QueryParam("lat")).Str("lng", c.QueryParam("lng")).Msg("[CustomerHandler-GetNearbyCustomers] Invalid lat/lng query")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "lat and lng query parameters must be valid numbers",
+			"data":    nil,
+		})
+	}
+
+	// Parse radius, default 5km
+	radiusKm := 5.0
+	if radiusStr := c.QueryParam("radius_km"); radiusStr != "" {
+		r, err := strconv.ParseFloat(radiusStr, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]interface{}{
+				"message": "radius_km must be a valid number",
+				"data":    nil,
+			})
+		}
+		radiusKm = r
+	}
+
+	// Parse limit
+	limit := 20
+	if l, err := strconv.Atoi(c.QueryParam("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	customers, err := h.userService.GetCustomersNearby(c.Request().Context(), lat, lng, radiusKm, limit)
+	if err != nil {
+		log.Error().Err(err).Float64("lat", lat).Float64("lng", lng).Float64("radius_km", radiusKm).Msg("[CustomerHandler-GetNearbyCustomers] Failed to get nearby customers")
+		if err.Error() == "invalid coordinates" || err.Error() == "radius must be between 0 and 100 km" {
+			return c.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+				"message": err.Error(),
+				"data":    nil,
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Failed to retrieve nearby customers",
+			"data":    nil,
+		})
+	}
+
+	customerData := make([]map[string]interface{}, 0, len(customers))
+	for _, customer := range customers {
+		customer, hidden := h.policy.customer(c, customer)
+		customerData = append(customerData, withHiddenFields(map[string]interface{}{
+			"id":          customer.ID,
+			"name":        customer.Name,
+			"email":       customer.Email,
+			"username":    customer.Username,
+			"phone":       customer.Phone,
+			"city":        customer.City,
+			"lat":         customer.Lat,
+			"lng":         customer.Lng,
+			"distance_km": customer.DistanceKm,
+		}, hidden))
+	}
+
+	log.Info().Int("count", len(customers)).Float64("radius_km", radiusKm).Msg("[CustomerHandler-GetNearbyCustomers] Nearby customers retrieved successfully")
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Nearby customers retrieved successfully",
+		"data":    customerData,
+	})
+}
+
+// SuggestCustomers backs the admin type-ahead; queries shorter than three characters match nothing
+func (h *CustomerHandler) SuggestCustomers(c echo.Context) error {
+	query := c.QueryParam("q")
+
+	customers, err := h.suggestService.Suggest(c.Request().Context(), query)
+	if err != nil {
+		if err.Error() == "customer suggestions timed out" {
+			return c.JSON(http.StatusGatewayTimeout, map[string]interface{}{
+				"message": "Customer suggestions timed out",
+				"data":    nil,
+			})
+		}
+		log.Error().Err(err).Str("query", query).Msg("[CustomerHandler-SuggestCustomers] Failed to suggest customers")
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Failed to suggest customers",
+			"data":    nil,
+		})
+	}
+
+	customerData := make([]map[string]interface{}, 0, len(customers))
+	for _, customer := range customers {
+		customer, hidden := h.policy.customer(c, customer)
+		customerData = append(customerData, withHiddenFields(map[string]interface{}{
+			"id":    customer.ID,
+			"name":  customer.Name,
+			"email": customer.Email,
+			"photo": h.cdn.PublicURL(customer.Photo),
+		}, hidden))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Customer suggestions retrieved successfully",
+		"data":    customerData,
+	})
+}
+
+// withHiddenFields tells the caller which blank fields were hidden by the customer's privacy
+// settings or the caller's response policy rather than never filled in
+func withHiddenFields(customer map[string]interface{}, hidden []string) map[string]interface{} {
+	if len(hidden) > 0 {
+		customer["hidden_fields"] = hidden
+	}
+	return customer
+}
+
+func NewCustomerHandler(userService port.UserServiceInterface, suggestService port.CustomerSuggestServiceInterface, cfg *config.Config) CustomerHandlerInterface {
 	return &CustomerHandler{
-		userService: userService,
+		userService:    userService,
+		suggestService: suggestService,
+		cdn:            cdn.NewRewriter(cfg.Supabase.ProjectURL, cfg.CDN.BaseURL),
+		policy:         newResponsePolicy(cfg),
 	}
 }
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// maxCustomerImportSize bounds the upload; the service additionally caps the number of rows
+const maxCustomerImportSize = 10 << 20
+
+type CustomerImportHandlerInterface interface {
+	ImportCustomers(c echo.Context) error
+	GetErrorReport(c echo.Context) error
+}
+
+type CustomerImportHandler struct {
+	importService port.CustomerImportServiceInterface
+}
+
+// ImportCustomers reads the "file" part of a multipart upload straight from the request body,
+// so large files are parsed while they arrive instead of being buffered first
+func (h *CustomerImportHandler) ImportCustomers(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	adminID := c.Get("user_id").(int64)
+
+	dryRun := false
+	if value := c.QueryParam("dry_run"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			resp.Message = "Invalid dry_run value"
+			return c.JSON(http.StatusBadRequest, resp)
+		}
+		dryRun = parsed
+	}
+
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, maxCustomerImportSize)
+	reader, err := c.Request().MultipartReader()
+	if err != nil {
+		resp.Message = "Request must be multipart/form-data with a file field"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	var file io.Reader
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Warn().Err(err).Msg("[CustomerImportHandler-ImportCustomers] Failed to read multipart body")
+			resp.Message = "Invalid multipart body"
+			return c.JSON(http.StatusBadRequest, resp)
+		}
+		if part.FormName() == "file" {
+			file = part
+			break
+		}
+	}
+	if file == nil {
+		resp.Message = "File is required"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	result, err := h.importService.ImportCustomers(c.Request().Context(), file, adminID, dryRun)
+	if err != nil {
+		log.Error().Err(err).Int64("admin_id", adminID).Msg("[CustomerImportHandler-ImportCustomers] Customer import failed")
+		switch err.Error() {
+		case "csv file is empty", "invalid csv file", "csv header must contain name and email columns":
+			resp.Message = err.Error()
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "failed to read csv file":
+			// Rows read before the failure were already processed, so report them too
+			resp.Message = fmt.Sprintf("Upload interrupted or larger than %d MB, rows before the failure were processed", maxCustomerImportSize>>20)
+			if result != nil {
+				resp.Data = h.toImportResponse(c, result)
+			}
+			return c.JSON(http.StatusBadRequest, resp)
+		default:
+			resp.Message = "Failed to import customers"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	resp.Message = "Customers imported successfully"
+	if result.DryRun {
+		resp.Message = "Import preview generated, no accounts were created"
+	}
+	resp.Data = h.toImportResponse(c, result)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *CustomerImportHandler) GetErrorReport(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	reportID := c.Param("id")
+
+	report, err := h.importService.GetErrorReport(c.Request().Context(), reportID)
+	if err != nil {
+		log.Error().Err(err).Str("report_id", reportID).Msg("[CustomerImportHandler-GetErrorReport] Failed to get report")
+		if err.Error() == "import report not found" {
+			resp.Message = "Import report not found or expired"
+			return c.JSON(http.StatusNotFound, resp)
+		}
+		resp.Message = "Failed to retrieve import report"
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="customer-import-errors-%s.csv"`, reportID))
+	return c.Blob(http.StatusOK, "text/csv; charset=utf-8", report)
+}
+
+func (h *CustomerImportHandler) toImportResponse(c echo.Context, result *entity.CustomerImportResultEntity) response.CustomerImportResponse {
+	rowErrors := make([]response.CustomerImportRowErrorResponse, 0, len(result.Errors))
+	for _, rowError := range result.Errors {
+		rowErrors = append(rowErrors, response.CustomerImportRowErrorResponse{
+			Row:     rowError.Row,
+			Email:   rowError.Email,
+			Message: rowError.Message,
+		})
+	}
+
+	importResponse := response.CustomerImportResponse{
+		TotalRows:   result.TotalRows,
+		Valid:       result.Valid,
+		Imported:    result.Imported,
+		InvitesSent: result.InvitesSent,
+		Failed:      result.Failed,
+		DryRun:      result.DryRun,
+		Errors:      rowErrors,
+	}
+	if result.ReportID != "" {
+		importResponse.ErrorReportURL = "/api/v1/admin/customers/import/reports/" + result.ReportID
+	}
+	return importResponse
+}
+
+func NewCustomerImportHandler(importService port.CustomerImportServiceInterface) CustomerImportHandlerInterface {
+	return &CustomerImportHandler{
+		importService: importService,
+	}
+}
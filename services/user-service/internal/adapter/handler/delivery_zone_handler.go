@@ -0,0 +1,258 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils/i18n"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type DeliveryZoneHandlerInterface interface {
+	GetAllZones(c echo.Context) error
+	GetZoneByID(c echo.Context) error
+	CreateZone(c echo.Context) error
+	UpdateZone(c echo.Context) error
+	DeleteZone(c echo.Context) error
+	CheckZone(c echo.Context) error
+}
+
+type DeliveryZoneHandler struct {
+	zoneService port.DeliveryZoneServiceInterface
+	validator   *myvalidator.Validator
+}
+
+func (h *DeliveryZoneHandler) GetAllZones(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	zones, err := h.zoneService.GetAllZones(c.Request().Context())
+	if err != nil {
+		log.Error().Err(err).Msg("[DeliveryZoneHandler-GetAllZones] Failed to get zones")
+		resp.Message = "Failed to retrieve delivery zones"
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	zoneData := make([]response.DeliveryZoneResponse, 0, len(zones))
+	for _, zone := range zones {
+		zoneData = append(zoneData, toDeliveryZoneResponse(&zone))
+	}
+
+	resp.Message = "Delivery zones retrieved successfully"
+	resp.Data = zoneData
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *DeliveryZoneHandler) GetZoneByID(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := h.parseID(c)
+	if err != nil {
+		resp.Message = "Invalid zone ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	zone, err := h.zoneService.GetZoneByID(c.Request().Context(), id)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve delivery zone")
+	}
+
+	resp.Message = "Delivery zone retrieved successfully"
+	resp.Data = toDeliveryZoneResponse(zone)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *DeliveryZoneHandler) CreateZone(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	zone, status, message := h.bindZone(c)
+	if zone == nil {
+		resp.Message = message
+		return c.JSON(status, resp)
+	}
+
+	createdZone, err := h.zoneService.CreateZone(c.Request().Context(), zone)
+	if err != nil {
+		return h.handleError(c, err, "Failed to create delivery zone")
+	}
+
+	log.Info().Int64("zone_id", createdZone.ID).Msg("[DeliveryZoneHandler-CreateZone] Delivery zone created successfully")
+	resp.Message = "Delivery zone created successfully"
+	resp.Data = toDeliveryZoneResponse(createdZone)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *DeliveryZoneHandler) UpdateZone(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := h.parseID(c)
+	if err != nil {
+		resp.Message = "Invalid zone ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	zone, status, message := h.bindZone(c)
+	if zone == nil {
+		resp.Message = message
+		return c.JSON(status, resp)
+	}
+
+	updatedZone, err := h.zoneService.UpdateZone(c.Request().Context(), id, zone)
+	if err != nil {
+		return h.handleError(c, err, "Failed to update delivery zone")
+	}
+
+	log.Info().Int64("zone_id", id).Msg("[DeliveryZoneHandler-UpdateZone] Delivery zone updated successfully")
+	resp.Message = "Delivery zone updated successfully"
+	resp.Data = toDeliveryZoneResponse(updatedZone)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *DeliveryZoneHandler) DeleteZone(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := h.parseID(c)
+	if err != nil {
+		resp.Message = "Invalid zone ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.zoneService.DeleteZone(c.Request().Context(), id); err != nil {
+		return h.handleError(c, err, "Failed to delete delivery zone")
+	}
+
+	log.Info().Int64("zone_id", id).Msg("[DeliveryZoneHandler-DeleteZone] Delivery zone deleted successfully")
+	resp.Message = "Delivery zone deleted successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *DeliveryZoneHandler) CheckZone(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	lat, errLat := strconv.ParseFloat(c.QueryParam("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.QueryParam("lng"), 64)
+	if errLat != nil || errLng != nil {
+		log.Warn().Str("lat", c.QueryParam("lat")).Str("lng", c.QueryParam("lng")).Msg("[DeliveryZoneHandler-CheckZone] Invalid lat/lng query")
+		resp.Message = "lat and lng query parameters must be valid numbers"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	zone, covered, err := h.zoneService.CheckPoint(c.Request().Context(), lat, lng)
+	if err != nil {
+		return h.handleError(c, err, "Failed to check delivery coverage")
+	}
+
+	lang := i18n.FromContext(c.Request().Context())
+	checkResp := response.ZoneCheckResponse{Covered: covered}
+	if covered {
+		checkResp.Message = i18n.T(lang, "We deliver to this location")
+	} else {
+		checkResp.Message = i18n.T(lang, "Sorry, this location is outside our delivery area")
+	}
+	if zone != nil {
+		checkResp.ZoneID = zone.ID
+		checkResp.ZoneName = zone.Name
+	}
+
+	resp.Message = "Delivery coverage checked successfully"
+	resp.Data = checkResp
+	return c.JSON(http.StatusOK, resp)
+}
+
+// bindZone returns the zone, or nil with the status and message to respond with
+func (h *DeliveryZoneHandler) bindZone(c echo.Context) (*entity.DeliveryZoneEntity, int, string) {
+	req := request.DeliveryZoneRequest{}
+
+	if err := c.Bind(&req); err != nil {
+		log.Warn().Err(err).Msg("[DeliveryZoneHandler-bindZone] Failed to bind request")
+		return nil, http.StatusBadRequest, "Invalid request format"
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		log.Warn().Err(err).Msg("[DeliveryZoneHandler-bindZone] Validation failed")
+		return nil, http.StatusUnprocessableEntity, err.Error()
+	}
+
+	zone := &entity.DeliveryZoneEntity{
+		Name:      req.Name,
+		ZoneType:  req.Type,
+		CenterLat: req.CenterLat,
+		CenterLng: req.CenterLng,
+		RadiusKm:  req.RadiusKm,
+		IsActive:  true,
+	}
+	if req.IsActive != nil {
+		zone.IsActive = *req.IsActive
+	}
+	for _, point := range req.Polygon {
+		zone.Polygon = append(zone.Polygon, entity.GeoPointEntity{Lat: point.Lat, Lng: point.Lng})
+	}
+
+	return zone, http.StatusOK, ""
+}
+
+func (h *DeliveryZoneHandler) parseID(c echo.Context) (int64, error) {
+	var id int64
+	if _, err := fmt.Sscanf(c.Param("id"), "%d", &id); err != nil {
+		log.Warn().Str("id_param", c.Param("id")).Msg("[DeliveryZoneHandler-parseID] Invalid ID format")
+		return 0, err
+	}
+	return id, nil
+}
+
+func (h *DeliveryZoneHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Msg("[DeliveryZoneHandler] Request failed")
+
+	switch {
+	case err.Error() == "zone not found":
+		resp.Message = "Delivery zone not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case err.Error() == "invalid coordinates":
+		resp.Message = "Invalid coordinates"
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	case strings.Contains(err.Error(), "already exists"):
+		resp.Message = err.Error()
+		return c.JSON(http.StatusConflict, resp)
+	case strings.HasPrefix(err.Error(), "zone name"),
+		strings.HasPrefix(err.Error(), "zone type"),
+		strings.HasPrefix(err.Error(), "radius zone"),
+		strings.HasPrefix(err.Error(), "polygon zone"):
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toDeliveryZoneResponse(zone *entity.DeliveryZoneEntity) response.DeliveryZoneResponse {
+	zoneResp := response.DeliveryZoneResponse{
+		ID:        zone.ID,
+		Name:      zone.Name,
+		Type:      zone.ZoneType,
+		CenterLat: zone.CenterLat,
+		CenterLng: zone.CenterLng,
+		RadiusKm:  zone.RadiusKm,
+		IsActive:  zone.IsActive,
+	}
+	for _, point := range zone.Polygon {
+		zoneResp.Polygon = append(zoneResp.Polygon, response.GeoPointResponse{Lat: point.Lat, Lng: point.Lng})
+	}
+	return zoneResp
+}
+
+func NewDeliveryZoneHandler(zoneService port.DeliveryZoneServiceInterface) DeliveryZoneHandlerInterface {
+	return &DeliveryZoneHandler{
+		zoneService: zoneService,
+		validator:   myvalidator.NewValidator(),
+	}
+}
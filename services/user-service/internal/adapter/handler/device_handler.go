@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type DeviceHandlerInterface interface {
+	GetDevices(c echo.Context) error
+	TrustDevice(c echo.Context) error
+}
+
+type DeviceHandler struct {
+	deviceService port.DeviceServiceInterface
+}
+
+func (h *DeviceHandler) GetDevices(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	userID := c.Get("user_id").(int64)
+
+	devices, err := h.deviceService.GetDevices(c.Request().Context(), userID)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve devices")
+	}
+
+	items := make([]response.DeviceResponse, 0, len(devices))
+	for _, device := range devices {
+		items = append(items, response.DeviceResponse{
+			ID:          device.ID,
+			UserAgent:   device.UserAgent,
+			LastIP:      device.LastIP,
+			Trusted:     device.Trusted,
+			FirstSeenAt: device.FirstSeenAt,
+			LastSeenAt:  device.LastSeenAt,
+		})
+	}
+
+	resp.Message = "Devices retrieved successfully"
+	resp.Data = items
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *DeviceHandler) TrustDevice(c echo.Context) error {
+	var (
+		req  = request.TrustDeviceRequest{}
+		resp = response.DefaultResponse{}
+	)
+	userID := c.Get("user_id").(int64)
+
+	deviceID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid device ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil || req.Trusted == nil {
+		resp.Message = "trusted is required"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.deviceService.SetTrusted(c.Request().Context(), userID, deviceID, *req.Trusted); err != nil {
+		return h.handleError(c, err, "Failed to update device")
+	}
+
+	resp.Message = "Device updated successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *DeviceHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Msg("[DeviceHandler] Request failed")
+
+	switch err.Error() {
+	case "device not found":
+		resp.Message = "Device not found"
+		return c.JSON(http.StatusNotFound, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func NewDeviceHandler(deviceService port.DeviceServiceInterface) DeviceHandlerInterface {
+	return &DeviceHandler{
+		deviceService: deviceService,
+	}
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type EmailRateLimitHandlerInterface interface {
+	GetLimits(c echo.Context) error
+	SetLimit(c echo.Context) error
+	DeleteLimit(c echo.Context) error
+}
+
+type EmailRateLimitHandler struct {
+	limitService port.EmailRateLimitServiceInterface
+	validator    *myvalidator.Validator
+}
+
+func (h *EmailRateLimitHandler) GetLimits(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	limits, err := h.limitService.GetLimits(c.Request().Context())
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve email rate limits")
+	}
+
+	limitData := make([]response.EmailRateLimitResponse, 0, len(limits))
+	for i := range limits {
+		limitData = append(limitData, toEmailRateLimitResponse(&limits[i]))
+	}
+
+	resp.Message = "Email rate limits retrieved successfully"
+	resp.Data = limitData
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SetLimit creates or replaces the quota for one email type
+func (h *EmailRateLimitHandler) SetLimit(c echo.Context) error {
+	var (
+		req  = request.EmailRateLimitRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	limit, err := h.limitService.SetLimit(c.Request().Context(), c.Get("user_id").(int64), req.EmailType, *req.MaxPerHour)
+	if err != nil {
+		return h.handleError(c, err, "Failed to save email rate limit")
+	}
+
+	resp.Message = "Email rate limit saved successfully"
+	resp.Data = toEmailRateLimitResponse(limit)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *EmailRateLimitHandler) DeleteLimit(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	if err := h.limitService.DeleteLimit(c.Request().Context(), c.Get("user_id").(int64), c.Param("email_type")); err != nil {
+		return h.handleError(c, err, "Failed to delete email rate limit")
+	}
+
+	resp.Message = "Email rate limit deleted successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *EmailRateLimitHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Str("path", c.Path()).Msg("[EmailRateLimitHandler] Request failed")
+
+	switch {
+	case err.Error() == "email rate limit not found":
+		resp.Message = "Email rate limit not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case strings.HasPrefix(err.Error(), "email type "),
+		strings.HasPrefix(err.Error(), "max per hour "):
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	case err.Error() == "failed to apply email rate limits":
+		// The change is stored; repeating the request publishes it again
+		resp.Message = "Email rate limits were saved but could not be applied, please try again"
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toEmailRateLimitResponse(limit *entity.EmailRateLimitEntity) response.EmailRateLimitResponse {
+	return response.EmailRateLimitResponse{
+		EmailType:  limit.EmailType,
+		MaxPerHour: limit.MaxPerHour,
+		UpdatedBy:  limit.UpdatedBy,
+		UpdatedAt:  limit.UpdatedAt,
+	}
+}
+
+func NewEmailRateLimitHandler(limitService port.EmailRateLimitServiceInterface) EmailRateLimitHandlerInterface {
+	return &EmailRateLimitHandler{
+		limitService: limitService,
+		validator:    myvalidator.NewValidator(),
+	}
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type FeatureFlagHandlerInterface interface {
+	GetMyFeatures(c echo.Context) error
+	GetAllFlags(c echo.Context) error
+	GetFlag(c echo.Context) error
+	CreateFlag(c echo.Context) error
+	UpdateFlag(c echo.Context) error
+	DeleteFlag(c echo.Context) error
+	SetOverride(c echo.Context) error
+	DeleteOverride(c echo.Context) error
+}
+
+type FeatureFlagHandler struct {
+	flagService port.FeatureFlagServiceInterface
+	validator   *myvalidator.Validator
+}
+
+// GetMyFeatures lists the flag keys enabled for the signed-in user so clients can toggle UI
+func (h *FeatureFlagHandler) GetMyFeatures(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	userID := c.Get("user_id").(int64)
+
+	keys, err := h.flagService.GetEnabledFlags(c.Request().Context(), userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[FeatureFlagHandler-GetMyFeatures] Failed to get enabled flags")
+		resp.Message = "Failed to retrieve features"
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "Features retrieved successfully"
+	resp.Data = keys
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *FeatureFlagHandler) GetAllFlags(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	flags, err := h.flagService.GetAllFlags(c.Request().Context())
+	if err != nil {
+		log.Error().Err(err).Msg("[FeatureFlagHandler-GetAllFlags] Failed to get flags")
+		resp.Message = "Failed to retrieve feature flags"
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	flagData := make([]response.FeatureFlagResponse, 0, len(flags))
+	for i := range flags {
+		flagData = append(flagData, toFeatureFlagResponse(&flags[i]))
+	}
+
+	resp.Message = "Feature flags retrieved successfully"
+	resp.Data = flagData
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *FeatureFlagHandler) GetFlag(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	flag, err := h.flagService.GetFlagByKey(c.Request().Context(), c.Param("key"))
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve feature flag")
+	}
+
+	resp.Message = "Feature flag retrieved successfully"
+	resp.Data = toFeatureFlagResponse(flag)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *FeatureFlagHandler) CreateFlag(c echo.Context) error {
+	var (
+		req  = request.FeatureFlagRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	flag, err := h.flagService.CreateFlag(c.Request().Context(), &entity.FeatureFlagEntity{
+		Key:               req.Key,
+		Description:       req.Description,
+		Enabled:           req.Enabled,
+		RolloutPercentage: req.RolloutPercentage,
+	})
+	if err != nil {
+		return h.handleError(c, err, "Failed to create feature flag")
+	}
+
+	resp.Message = "Feature flag created successfully"
+	resp.Data = toFeatureFlagResponse(flag)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *FeatureFlagHandler) UpdateFlag(c echo.Context) error {
+	var (
+		req  = request.FeatureFlagRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	flag, err := h.flagService.UpdateFlag(c.Request().Context(), c.Param("key"), &entity.FeatureFlagEntity{
+		Description:       req.Description,
+		Enabled:           req.Enabled,
+		RolloutPercentage: req.RolloutPercentage,
+	})
+	if err != nil {
+		return h.handleError(c, err, "Failed to update feature flag")
+	}
+
+	resp.Message = "Feature flag updated successfully"
+	resp.Data = toFeatureFlagResponse(flag)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *FeatureFlagHandler) DeleteFlag(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	if err := h.flagService.DeleteFlag(c.Request().Context(), c.Param("key")); err != nil {
+		return h.handleError(c, err, "Failed to delete feature flag")
+	}
+
+	resp.Message = "Feature flag deleted successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *FeatureFlagHandler) SetOverride(c echo.Context) error {
+	var (
+		req  = request.FeatureFlagOverrideRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid user ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.flagService.SetOverride(c.Request().Context(), c.Param("key"), userID, req.Enabled); err != nil {
+		return h.handleError(c, err, "Failed to set override")
+	}
+
+	resp.Message = "Override set successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *FeatureFlagHandler) DeleteOverride(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	userID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid user ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.flagService.DeleteOverride(c.Request().Context(), c.Param("key"), userID); err != nil {
+		return h.handleError(c, err, "Failed to delete override")
+	}
+
+	resp.Message = "Override deleted successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *FeatureFlagHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Str("key", c.Param("key")).Msg("[FeatureFlagHandler] Request failed")
+
+	switch err.Error() {
+	case "feature flag not found", "override not found":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusNotFound, resp)
+	case "feature flag already exists":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusConflict, resp)
+	case "flag key must be 2-100 lowercase letters, digits, dots, dashes or underscores",
+		"rollout percentage must be between 0 and 100", "invalid user id":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toFeatureFlagResponse(flag *entity.FeatureFlagEntity) response.FeatureFlagResponse {
+	return response.FeatureFlagResponse{
+		Key:               flag.Key,
+		Description:       flag.Description,
+		Enabled:           flag.Enabled,
+		RolloutPercentage: flag.RolloutPercentage,
+		Overrides:         flag.Overrides,
+		CreatedAt:         flag.CreatedAt,
+		UpdatedAt:         flag.UpdatedAt,
+	}
+}
+
+func NewFeatureFlagHandler(flagService port.FeatureFlagServiceInterface) FeatureFlagHandlerInterface {
+	return &FeatureFlagHandler{
+		flagService: flagService,
+		validator:   myvalidator.NewValidator(),
+	}
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type GeocodeHandlerInterface interface {
+	ReverseGeocode(c echo.Context) error
+	Geocode(c echo.Context) error
+}
+
+type GeocodeHandler struct {
+	geocodingService port.GeocodingServiceInterface
+}
+
+func (h *GeocodeHandler) ReverseGeocode(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	lat, errLat := strconv.ParseFloat(c.QueryParam("lat"), 64)
+	lng, errLng := strconv.ParseFloat(c.QueryParam("lng"), 64)
+	if errLat != nil || errLng != nil {
+		log.Warn().Str("lat", c.QueryParam("lat")).Str("lng", c.QueryParam("lng")).Msg("[GeocodeHandler-ReverseGeocode] Invalid lat/lng query")
+		resp.Message = "lat and lng query parameters must be valid numbers"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	address, err := h.geocodingService.ReverseGeocode(c.Request().Context(), lat, lng)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	resp.Message = "Location resolved successfully"
+	resp.Data = toGeocodeResponse(address)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *GeocodeHandler) Geocode(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	address, err := h.geocodingService.Geocode(c.Request().Context(), c.QueryParam("address"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	resp.Message = "Address resolved successfully"
+	resp.Data = toGeocodeResponse(address)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *GeocodeHandler) handleError(c echo.Context, err error) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Msg("[GeocodeHandler] Geocoding failed")
+
+	switch err.Error() {
+	case "invalid coordinates":
+		resp.Message = "Invalid coordinates"
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	case "address is required":
+		resp.Message = "Address is required"
+		return c.JSON(http.StatusBadRequest, resp)
+	case "location not found":
+		resp.Message = "Location not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case "geocoding service unavailable":
+		resp.Message = "Geocoding service unavailable"
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	default:
+		resp.Message = "Internal server error"
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toGeocodeResponse(address *entity.AddressEntity) response.GeocodeResponse {
+	return response.GeocodeResponse{
+		Address:    address.Address,
+		Province:   address.Province,
+		City:       address.City,
+		District:   address.District,
+		PostalCode: address.PostalCode,
+		Lat:        address.Lat,
+		Lng:        address.Lng,
+	}
+}
+
+func NewGeocodeHandler(geocodingService port.GeocodingServiceInterface) GeocodeHandlerInterface {
+	return &GeocodeHandler{
+		geocodingService: geocodingService,
+	}
+}
@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type IdentityHandlerInterface interface {
+	SubmitVerification(c echo.Context) error
+	GetMyVerification(c echo.Context) error
+	GetVerifications(c echo.Context) error
+	GetVerification(c echo.Context) error
+	Approve(c echo.Context) error
+	Reject(c echo.Context) error
+	GetUserIdentity(c echo.Context) error
+}
+
+type IdentityHandler struct {
+	identityService port.IdentityServiceInterface
+	validator       *myvalidator.Validator
+}
+
+// SubmitVerification takes a multipart "file" with "document_type" and "date_of_birth" (YYYY-MM-DD)
+func (h *IdentityHandler) SubmitVerification(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	userID := c.Get("user_id").(int64)
+
+	dateOfBirth, err := time.Parse("2006-01-02", c.FormValue("date_of_birth"))
+	if err != nil {
+		resp.Message = "Date of birth must be in YYYY-MM-DD format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		log.Warn().Err(err).Int64("user_id", userID).Msg("[IdentityHandler-SubmitVerification] Failed to get file from form")
+		resp.Message = "File is required"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[IdentityHandler-SubmitVerification] Failed to open uploaded file")
+		resp.Message = "Failed to process uploaded file"
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+	defer src.Close()
+
+	verification, err := h.identityService.Submit(c.Request().Context(), userID, c.FormValue("document_type"), dateOfBirth, src)
+	if err != nil {
+		return h.handleError(c, err, "Failed to upload document")
+	}
+
+	resp.Message = "Identity document submitted for review"
+	resp.Data = toIdentityVerificationResponse(verification)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *IdentityHandler) GetMyVerification(c echo.Context) error {
+	return h.respondStatus(c, c.Get("user_id").(int64))
+}
+
+// GetUserIdentity lets services selling restricted goods check a customer
+func (h *IdentityHandler) GetUserIdentity(c echo.Context) error {
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, response.DefaultResponse{Message: "Invalid user ID format"})
+	}
+	return h.respondStatus(c, userID)
+}
+
+func (h *IdentityHandler) respondStatus(c echo.Context, userID int64) error {
+	resp := response.DefaultResponse{}
+
+	status, err := h.identityService.Status(c.Request().Context(), userID)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve identity verification")
+	}
+
+	resp.Message = "Identity verification retrieved successfully"
+	resp.Data = response.IdentityStatusResponse{
+		Status:          status.Status,
+		Verified:        status.Verified,
+		AgeVerified:     status.AgeVerified,
+		ExpiresAt:       status.ExpiresAt,
+		RejectionReason: status.RejectionReason,
+		SubmittedAt:     status.SubmittedAt,
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *IdentityHandler) GetVerifications(c echo.Context) error {
+	status := c.QueryParam("status")
+	switch status {
+	case "", entity.IdentityVerificationPending, entity.IdentityVerificationApproved, entity.IdentityVerificationRejected:
+	default:
+		return c.JSON(http.StatusBadRequest, response.DefaultResponse{Message: "Invalid status filter"})
+	}
+
+	page, limit := pageQuery(c)
+
+	verifications, pagination, err := h.identityService.GetVerifications(c.Request().Context(), status, page, limit)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve identity verifications")
+	}
+
+	verificationData := make([]response.IdentityVerificationResponse, 0, len(verifications))
+	for i := range verifications {
+		verificationData = append(verificationData, toIdentityVerificationResponse(&verifications[i]))
+	}
+
+	return respondPage(c, "Identity verifications retrieved successfully", verificationData, pagination)
+}
+
+func (h *IdentityHandler) GetVerification(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid identity verification ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	verification, err := h.identityService.GetVerification(c.Request().Context(), id)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve identity verification")
+	}
+
+	resp.Message = "Identity verification retrieved successfully"
+	resp.Data = toIdentityVerificationResponse(verification)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *IdentityHandler) Approve(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	adminID := c.Get("user_id").(int64)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid identity verification ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	verification, err := h.identityService.Approve(c.Request().Context(), id, adminID)
+	if err != nil {
+		return h.handleError(c, err, "Failed to approve identity verification")
+	}
+
+	resp.Message = "Identity verification approved successfully"
+	resp.Data = toIdentityVerificationResponse(verification)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *IdentityHandler) Reject(c echo.Context) error {
+	var (
+		req  = request.RejectIdentityRequest{}
+		resp = response.DefaultResponse{}
+	)
+	adminID := c.Get("user_id").(int64)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid identity verification ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	verification, err := h.identityService.Reject(c.Request().Context(), id, adminID, req.Reason)
+	if err != nil {
+		return h.handleError(c, err, "Failed to reject identity verification")
+	}
+
+	resp.Message = "Identity verification rejected successfully"
+	resp.Data = toIdentityVerificationResponse(verification)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *IdentityHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Str("path", c.Path()).Msg("[IdentityHandler] Request failed")
+
+	switch err.Error() {
+	case "identity verification not found":
+		resp.Message = "Identity verification not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case "identity verification already pending", "identity verification already reviewed":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusConflict, resp)
+	case "document type must be one of ktp, passport, sim", "date of birth is invalid", "document is empty",
+		"document must be a JPEG, PNG or PDF file", "rejection reason is required":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	case "document exceeds the size limit":
+		resp.Message = "Document exceeds the size limit"
+		return c.JSON(http.StatusRequestEntityTooLarge, resp)
+	case "storage service unavailable":
+		resp.Message = "Storage service unavailable"
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	case "file is infected":
+		resp.Message = "File was rejected by the virus scanner"
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	case "file scanner is unavailable":
+		resp.Message = "File scanning is temporarily unavailable, please try again later"
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toIdentityVerificationResponse(verification *entity.IdentityVerificationEntity) response.IdentityVerificationResponse {
+	return response.IdentityVerificationResponse{
+		ID:              verification.ID,
+		UserID:          verification.UserID,
+		DocumentType:    verification.DocumentType,
+		ContentType:     verification.ContentType,
+		DateOfBirth:     verification.DateOfBirth.Format("2006-01-02"),
+		Status:          verification.Status,
+		RejectionReason: verification.RejectionReason,
+		ReviewedBy:      verification.ReviewedBy,
+		ReviewedAt:      verification.ReviewedAt,
+		ExpiresAt:       verification.ExpiresAt,
+		CreatedAt:       verification.CreatedAt,
+		DocumentURL:     verification.DocumentURL,
+	}
+}
+
+func NewIdentityHandler(identityService port.IdentityServiceInterface) IdentityHandlerInterface {
+	return &IdentityHandler{
+		identityService: identityService,
+		validator:       myvalidator.NewValidator(),
+	}
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type InternalHandlerInterface interface {
+	BatchGetUsers(c echo.Context) error
+	IntrospectToken(c echo.Context) error
+	RecordEmailBounce(c echo.Context) error
+}
+
+type InternalHandler struct {
+	userService          port.UserServiceInterface
+	introspectionService port.TokenIntrospectionServiceInterface
+	validator            *myvalidator.Validator
+}
+
+// BatchGetUsers returns delivery details for up to 500 users; ids that are unknown come back in missing_ids.
+// Unless the audience is the courier, fields the users hide from vendors are blanked.
+func (h *InternalHandler) BatchGetUsers(c echo.Context) error {
+	var (
+		req  = request.BatchUsersRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	principal, _ := middleware.GetPrincipal(c)
+	users, err := h.userService.GetUsersByIDs(c.Request().Context(), req.IDs)
+	if err != nil {
+		log.Error().Err(err).Str("service", principal.ServiceName).Int("count", len(req.IDs)).Msg("[InternalHandler-BatchGetUsers] Failed to get users")
+
+		switch err.Error() {
+		case "user ids are required", "invalid user id":
+			resp.Message = err.Error()
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		case "too many user ids":
+			resp.Message = "Too many user ids, maximum is 500"
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		default:
+			resp.Message = "Failed to retrieve users"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	audience := req.Audience
+	if audience == "" {
+		audience = entity.AudienceVendor
+	}
+
+	found := make(map[int64]struct{}, len(users))
+	batch := response.BatchUsersResponse{
+		Users:      make([]response.InternalUserResponse, 0, len(users)),
+		MissingIDs: []int64{},
+	}
+	for _, user := range users {
+		found[user.ID] = struct{}{}
+		user, hidden := user.MaskedFor(audience)
+		batch.Users = append(batch.Users, response.InternalUserResponse{
+			ID:           user.ID,
+			Name:         user.Name,
+			Phone:        user.Phone,
+			Address:      user.Address,
+			Province:     user.Province,
+			City:         user.City,
+			District:     user.District,
+			PostalCode:   user.PostalCode,
+			Lat:          user.Lat,
+			Lng:          user.Lng,
+			HiddenFields: hidden,
+		})
+	}
+	for _, id := range req.IDs {
+		if _, ok := found[id]; !ok {
+			found[id] = struct{}{}
+			batch.MissingIDs = append(batch.MissingIDs, id)
+		}
+	}
+
+	log.Info().Str("service", principal.ServiceName).Int("requested", len(req.IDs)).Int("found", len(batch.Users)).Str("audience", audience).Msg("[InternalHandler-BatchGetUsers] Batch lookup served")
+	resp.Message = "Users retrieved successfully"
+	resp.Data = batch
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RecordEmailBounce is called by notification-service when the mail provider reports a bounce
+func (h *InternalHandler) RecordEmailBounce(c echo.Context) error {
+	var (
+		req  = request.EmailBounceRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	principal, _ := middleware.GetPrincipal(c)
+	if err := h.userService.RecordEmailBounce(c.Request().Context(), req.Email, req.Type, req.Reason); err != nil {
+		log.Error().Err(err).Str("service", principal.ServiceName).Str("email", req.Email).Msg("[InternalHandler-RecordEmailBounce] Failed to record bounce")
+
+		switch err.Error() {
+		case "user not found":
+			resp.Message = "User not found"
+			return c.JSON(http.StatusNotFound, resp)
+		default:
+			resp.Message = "Failed to record bounce"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	resp.Message = "Bounce recorded successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+// IntrospectToken tells another service whether a user's JWT is still accepted here. Inactive
+// tokens are a normal answer (200 with active=false), not an error.
+func (h *InternalHandler) IntrospectToken(c echo.Context) error {
+	var (
+		req  = request.IntrospectTokenRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	principal, _ := middleware.GetPrincipal(c)
+	result, err := h.introspectionService.Introspect(c.Request().Context(), req.Token)
+	if err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	resp.Message = "Token introspected successfully"
+	if !result.Active {
+		log.Info().Str("service", principal.ServiceName).Str("reason", result.Reason).Int64("user_id", result.UserID).Msg("[InternalHandler-IntrospectToken] Token is not active")
+		resp.Data = response.TokenIntrospectionResponse{Active: false}
+		return c.JSON(http.StatusOK, resp)
+	}
+
+	resp.Data = response.TokenIntrospectionResponse{
+		Active:    true,
+		Sub:       strconv.FormatInt(result.UserID, 10),
+		UserID:    result.UserID,
+		Email:     result.Email,
+		Role:      result.RoleName,
+		SessionID: result.SessionID,
+		Iss:       result.Issuer,
+		Iat:       unixOrZero(result.IssuedAt),
+		Exp:       unixOrZero(result.ExpiresAt),
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
+
+func NewInternalHandler(userService port.UserServiceInterface, introspectionService port.TokenIntrospectionServiceInterface) InternalHandlerInterface {
+	return &InternalHandler{
+		userService:          userService,
+		introspectionService: introspectionService,
+		validator:            myvalidator.NewValidator(),
+	}
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type InviteHandlerInterface interface {
+	GetInvites(c echo.Context) error
+	CreateInvite(c echo.Context) error
+	RevokeInvite(c echo.Context) error
+}
+
+type InviteHandler struct {
+	inviteService port.InviteServiceInterface
+	validator     *myvalidator.Validator
+}
+
+func (h *InviteHandler) GetInvites(c echo.Context) error {
+	page, limit := pageQuery(c)
+
+	invites, pagination, err := h.inviteService.GetInvites(c.Request().Context(), page, limit)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve invites")
+	}
+
+	inviteData := make([]response.InviteResponse, 0, len(invites))
+	for i := range invites {
+		inviteData = append(inviteData, toInviteResponse(&invites[i]))
+	}
+
+	return respondPage(c, "Invites retrieved successfully", inviteData, pagination)
+}
+
+func (h *InviteHandler) CreateInvite(c echo.Context) error {
+	var (
+		req  = request.InviteRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	createdBy, _ := c.Get("user_id").(int64)
+	invite, err := h.inviteService.CreateInvite(c.Request().Context(), &entity.InviteEntity{
+		Code:      req.Code,
+		Note:      req.Note,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: req.ExpiresAt,
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		return h.handleError(c, err, "Failed to create invite")
+	}
+
+	resp.Message = "Invite created successfully"
+	resp.Data = toInviteResponse(invite)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *InviteHandler) RevokeInvite(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid invite ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.inviteService.RevokeInvite(c.Request().Context(), id); err != nil {
+		return h.handleError(c, err, "Failed to revoke invite")
+	}
+
+	resp.Message = "Invite revoked successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *InviteHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Str("path", c.Path()).Msg("[InviteHandler] Request failed")
+
+	switch {
+	case err.Error() == "invite not found":
+		resp.Message = "Invite not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case err.Error() == "invite code already exists":
+		resp.Message = "Invite code already exists"
+		return c.JSON(http.StatusConflict, resp)
+	case strings.HasPrefix(err.Error(), "invite "):
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toInviteResponse(invite *entity.InviteEntity) response.InviteResponse {
+	status := "active"
+	switch {
+	case invite.RevokedAt != nil:
+		status = "revoked"
+	case invite.IsExpired(time.Now()):
+		status = "expired"
+	case invite.Uses >= invite.MaxUses:
+		status = "used_up"
+	}
+
+	return response.InviteResponse{
+		ID:        invite.ID,
+		Code:      invite.Code,
+		Note:      invite.Note,
+		MaxUses:   invite.MaxUses,
+		Uses:      invite.Uses,
+		Status:    status,
+		ExpiresAt: invite.ExpiresAt,
+		RevokedAt: invite.RevokedAt,
+		CreatedAt: invite.CreatedAt,
+	}
+}
+
+func NewInviteHandler(inviteService port.InviteServiceInterface) InviteHandlerInterface {
+	return &InviteHandler{
+		inviteService: inviteService,
+		validator:     myvalidator.NewValidator(),
+	}
+}
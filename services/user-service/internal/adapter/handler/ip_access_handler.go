@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"net/http"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type IPAccessHandlerInterface interface {
+	GetRules(c echo.Context) error
+	AddRule(c echo.Context) error
+	RemoveRule(c echo.Context) error
+}
+
+type IPAccessHandler struct {
+	ipAccessService port.IPAccessServiceInterface
+	validator       *myvalidator.Validator
+}
+
+func (h *IPAccessHandler) GetRules(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	rules, err := h.ipAccessService.GetRules(c.Request().Context())
+	if err != nil {
+		log.Error().Err(err).Msg("[IPAccessHandler-GetRules] Failed to get ip rules")
+		resp.Message = "Failed to retrieve IP rules"
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	ruleData := make([]response.IPRuleResponse, 0, len(rules))
+	for i := range rules {
+		ruleData = append(ruleData, toIPRuleResponse(&rules[i]))
+	}
+
+	resp.Message = "IP rules retrieved successfully"
+	resp.Data = ruleData
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *IPAccessHandler) AddRule(c echo.Context) error {
+	var (
+		req  = request.IPRuleRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	adminID := c.Get("user_id").(int64)
+	rule, err := h.ipAccessService.AddRule(c.Request().Context(), req.ListType, req.CIDR, req.Note, adminID, c.RealIP())
+	if err != nil {
+		return h.handleError(c, err, "Failed to add IP rule")
+	}
+
+	resp.Message = "IP rule added successfully"
+	resp.Data = toIPRuleResponse(rule)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// RemoveRule takes the rule in the body because CIDR ranges contain a slash
+func (h *IPAccessHandler) RemoveRule(c echo.Context) error {
+	var (
+		req  = request.IPRuleDeleteRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	if err := h.ipAccessService.RemoveRule(c.Request().Context(), req.ListType, req.CIDR, c.RealIP()); err != nil {
+		return h.handleError(c, err, "Failed to remove IP rule")
+	}
+
+	resp.Message = "IP rule removed successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *IPAccessHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Str("ip", c.RealIP()).Msg("[IPAccessHandler] Request failed")
+
+	switch err.Error() {
+	case "ip rule not found":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusNotFound, resp)
+	case "this change would block your current IP":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusConflict, resp)
+	case "list type must be allow or deny", "invalid IP address or CIDR range":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toIPRuleResponse(rule *entity.IPRuleEntity) response.IPRuleResponse {
+	return response.IPRuleResponse{
+		CIDR:      rule.CIDR,
+		ListType:  rule.ListType,
+		Note:      rule.Note,
+		CreatedBy: rule.CreatedBy,
+		CreatedAt: rule.CreatedAt,
+		Static:    rule.Static,
+	}
+}
+
+func NewIPAccessHandler(ipAccessService port.IPAccessServiceInterface) IPAccessHandlerInterface {
+	return &IPAccessHandler{
+		ipAccessService: ipAccessService,
+		validator:       myvalidator.NewValidator(),
+	}
+}
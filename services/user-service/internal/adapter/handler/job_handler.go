@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type JobHandlerInterface interface {
+	GetJobs(c echo.Context) error
+	RetryJob(c echo.Context) error
+}
+
+type JobHandler struct {
+	jobService port.JobServiceInterface
+}
+
+// GetJobs defaults to failed jobs, the ones an admin usually needs to act on
+func (h *JobHandler) GetJobs(c echo.Context) error {
+	status := c.QueryParam("status")
+	if status == "" {
+		status = entity.JobStatusFailed
+	}
+	if status != entity.JobStatusPending && status != entity.JobStatusRunning &&
+		status != entity.JobStatusCompleted && status != entity.JobStatusFailed {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Invalid status filter",
+			"data":    nil,
+		})
+	}
+
+	page, limit := pageQuery(c)
+
+	jobs, pagination, err := h.jobService.GetJobs(c.Request().Context(), status, c.QueryParam("type"), page, limit)
+	if err != nil {
+		log.Error().Err(err).Str("status", status).Msg("[JobHandler-GetJobs] Failed to get jobs")
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Failed to retrieve jobs",
+			"data":    nil,
+		})
+	}
+
+	jobData := make([]response.JobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		jobData = append(jobData, response.JobResponse{
+			ID:          job.ID,
+			Type:        job.Type,
+			Payload:     job.Payload,
+			Status:      job.Status,
+			Attempts:    job.Attempts,
+			MaxAttempts: job.MaxAttempts,
+			RunAt:       job.RunAt,
+			LastError:   job.LastError,
+			CompletedAt: job.CompletedAt,
+			CreatedAt:   job.CreatedAt,
+		})
+	}
+
+	return respondPage(c, "Jobs retrieved successfully", jobData, pagination)
+}
+
+func (h *JobHandler) RetryJob(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid job ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.jobService.RetryJob(c.Request().Context(), id); err != nil {
+		log.Error().Err(err).Int64("job_id", id).Msg("[JobHandler-RetryJob] Failed to retry job")
+		switch err.Error() {
+		case "job not found":
+			resp.Message = "Job not found"
+			return c.JSON(http.StatusNotFound, resp)
+		case "job is not failed":
+			resp.Message = "Only failed jobs can be retried"
+			return c.JSON(http.StatusConflict, resp)
+		default:
+			resp.Message = "Failed to retry job"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	resp.Message = "Job queued for retry"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func NewJobHandler(jobService port.JobServiceInterface) JobHandlerInterface {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
@@ -0,0 +1,333 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type LedgerHandlerInterface interface {
+	GetBalance(c echo.Context) error
+	GetLedgerEntries(c echo.Context) error
+	RequestWithdrawal(c echo.Context) error
+	GetMyWithdrawals(c echo.Context) error
+	RecordOrderEarning(c echo.Context) error
+	GetWithdrawals(c echo.Context) error
+	ApproveWithdrawal(c echo.Context) error
+	RejectWithdrawal(c echo.Context) error
+	AuditLedger(c echo.Context) error
+}
+
+type LedgerHandler struct {
+	ledgerService port.LedgerServiceInterface
+	validator     *myvalidator.Validator
+}
+
+func (h *LedgerHandler) GetBalance(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	vendorID := c.Get("vendor_id").(int64)
+
+	balance, err := h.ledgerService.GetBalance(c.Request().Context(), vendorID)
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Msg("[LedgerHandler-GetBalance] Failed to get balance")
+		resp.Message = "Failed to retrieve balance"
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "Balance retrieved successfully"
+	resp.Data = response.VendorBalanceResponse{
+		VendorID:      balance.VendorID,
+		Available:     balance.Available,
+		PendingPayout: balance.PendingPayout,
+		TotalEarned:   balance.TotalEarned,
+		TotalPaidOut:  balance.TotalPaidOut,
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *LedgerHandler) GetLedgerEntries(c echo.Context) error {
+	vendorID := c.Get("vendor_id").(int64)
+	page, limit := pageQuery(c)
+
+	entries, pagination, err := h.ledgerService.GetLedgerEntries(c.Request().Context(), vendorID, page, limit)
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Msg("[LedgerHandler-GetLedgerEntries] Failed to get ledger entries")
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Failed to retrieve ledger entries",
+			"data":    nil,
+		})
+	}
+
+	entryData := make([]response.LedgerEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		entryData = append(entryData, toLedgerEntryResponse(entry))
+	}
+
+	return respondPage(c, "Ledger entries retrieved successfully", entryData, pagination)
+}
+
+func (h *LedgerHandler) RequestWithdrawal(c echo.Context) error {
+	var (
+		req  = request.WithdrawalRequest{}
+		resp = response.DefaultResponse{}
+	)
+	vendorID := c.Get("vendor_id").(int64)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	withdrawal, err := h.ledgerService.RequestWithdrawal(c.Request().Context(), vendorID, &entity.WithdrawalEntity{
+		Amount:        req.Amount,
+		BankName:      req.BankName,
+		AccountNumber: req.AccountNumber,
+		AccountName:   req.AccountName,
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Int64("amount", req.Amount).Msg("[LedgerHandler-RequestWithdrawal] Failed to request withdrawal")
+		switch err.Error() {
+		case "withdrawal amount is below the minimum", "insufficient balance":
+			resp.Message = err.Error()
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		default:
+			resp.Message = "Failed to request withdrawal"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	resp.Message = "Withdrawal requested successfully"
+	resp.Data = toWithdrawalResponse(*withdrawal)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *LedgerHandler) GetMyWithdrawals(c echo.Context) error {
+	return h.listWithdrawals(c, c.Get("vendor_id").(int64))
+}
+
+func (h *LedgerHandler) RecordOrderEarning(c echo.Context) error {
+	var (
+		req  = request.RecordEarningRequest{}
+		resp = response.DefaultResponse{}
+	)
+	adminID := c.Get("user_id").(int64)
+
+	vendorID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid vendor ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	transaction, err := h.ledgerService.RecordOrderEarning(c.Request().Context(), vendorID, req.OrderID, req.Amount, adminID)
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Str("order_id", req.OrderID).Msg("[LedgerHandler-RecordOrderEarning] Failed to record earning")
+		switch err.Error() {
+		case "vendor not found":
+			resp.Message = "Vendor not found"
+			return c.JSON(http.StatusNotFound, resp)
+		case "order earning already recorded":
+			resp.Message = err.Error()
+			return c.JSON(http.StatusConflict, resp)
+		case "vendor is not approved", "order id is required", "amount must be greater than 0":
+			resp.Message = err.Error()
+			return c.JSON(http.StatusUnprocessableEntity, resp)
+		default:
+			resp.Message = "Failed to record earning"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	transactionResp := response.LedgerTransactionResponse{
+		ID:          transaction.ID,
+		VendorID:    transaction.VendorID,
+		Type:        transaction.Type,
+		ReferenceID: transaction.ReferenceID,
+		CreatedAt:   transaction.CreatedAt,
+	}
+	for _, entry := range transaction.Entries {
+		transactionResp.Entries = append(transactionResp.Entries, toLedgerEntryResponse(entry))
+	}
+
+	resp.Message = "Order earning recorded successfully"
+	resp.Data = transactionResp
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *LedgerHandler) GetWithdrawals(c echo.Context) error {
+	return h.listWithdrawals(c, 0)
+}
+
+func (h *LedgerHandler) ApproveWithdrawal(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	adminID := c.Get("user_id").(int64)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid withdrawal ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.ledgerService.ApproveWithdrawal(c.Request().Context(), id, adminID); err != nil {
+		return h.handleReviewError(c, err, "Failed to approve withdrawal")
+	}
+
+	resp.Message = "Withdrawal approved successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *LedgerHandler) RejectWithdrawal(c echo.Context) error {
+	var (
+		req  = request.RejectWithdrawalRequest{}
+		resp = response.DefaultResponse{}
+	)
+	adminID := c.Get("user_id").(int64)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid withdrawal ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	if err := h.ledgerService.RejectWithdrawal(c.Request().Context(), id, adminID, req.Reason); err != nil {
+		return h.handleReviewError(c, err, "Failed to reject withdrawal")
+	}
+
+	resp.Message = "Withdrawal rejected successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *LedgerHandler) AuditLedger(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	audit, err := h.ledgerService.AuditLedger(c.Request().Context())
+	if err != nil {
+		log.Error().Err(err).Msg("[LedgerHandler-AuditLedger] Failed to audit ledger")
+		resp.Message = "Failed to audit ledger"
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	resp.Message = "Ledger audited successfully"
+	resp.Data = response.LedgerAuditResponse{
+		Balanced:               audit.NetTotal == 0 && len(audit.UnbalancedTransactions) == 0,
+		NetTotal:               audit.NetTotal,
+		TransactionCount:       audit.TransactionCount,
+		AccountTotals:          audit.AccountTotals,
+		UnbalancedTransactions: audit.UnbalancedTransactions,
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *LedgerHandler) listWithdrawals(c echo.Context, vendorID int64) error {
+	status := c.QueryParam("status")
+	if status != "" && status != entity.WithdrawalStatusPending && status != entity.WithdrawalStatusApproved && status != entity.WithdrawalStatusRejected {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Invalid status filter",
+			"data":    nil,
+		})
+	}
+
+	page, limit := pageQuery(c)
+
+	withdrawals, pagination, err := h.ledgerService.GetWithdrawals(c.Request().Context(), vendorID, status, page, limit)
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Str("status", status).Msg("[LedgerHandler-listWithdrawals] Failed to get withdrawals")
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Failed to retrieve withdrawals",
+			"data":    nil,
+		})
+	}
+
+	withdrawalData := make([]response.WithdrawalResponse, 0, len(withdrawals))
+	for _, withdrawal := range withdrawals {
+		withdrawalData = append(withdrawalData, toWithdrawalResponse(withdrawal))
+	}
+
+	return respondPage(c, "Withdrawals retrieved successfully", withdrawalData, pagination)
+}
+
+func (h *LedgerHandler) handleReviewError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Msg("[LedgerHandler] Failed to review withdrawal")
+
+	switch err.Error() {
+	case "withdrawal not found":
+		resp.Message = "Withdrawal not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case "withdrawal is not pending":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusConflict, resp)
+	case "rejection reason is required":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toLedgerEntryResponse(entry entity.LedgerEntryEntity) response.LedgerEntryResponse {
+	return response.LedgerEntryResponse{
+		ID:              entry.ID,
+		TransactionID:   entry.TransactionID,
+		TransactionType: entry.TransactionType,
+		ReferenceID:     entry.ReferenceID,
+		Account:         entry.Account,
+		Amount:          entry.Amount,
+		CreatedAt:       entry.CreatedAt,
+	}
+}
+
+func toWithdrawalResponse(withdrawal entity.WithdrawalEntity) response.WithdrawalResponse {
+	return response.WithdrawalResponse{
+		ID:              withdrawal.ID,
+		VendorID:        withdrawal.VendorID,
+		Amount:          withdrawal.Amount,
+		BankName:        withdrawal.BankName,
+		AccountNumber:   withdrawal.AccountNumber,
+		AccountName:     withdrawal.AccountName,
+		Status:          withdrawal.Status,
+		RejectionReason: withdrawal.RejectionReason,
+		ReviewedAt:      withdrawal.ReviewedAt,
+		CreatedAt:       withdrawal.CreatedAt,
+	}
+}
+
+func NewLedgerHandler(ledgerService port.LedgerServiceInterface) LedgerHandlerInterface {
+	return &LedgerHandler{
+		ledgerService: ledgerService,
+		validator:     myvalidator.NewValidator(),
+	}
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type LegalHandlerInterface interface {
+	GetCurrentDocuments(c echo.Context) error
+	GetConsent(c echo.Context) error
+	Accept(c echo.Context) error
+	GetDocuments(c echo.Context) error
+	PublishDocument(c echo.Context) error
+	GetUserConsents(c echo.Context) error
+}
+
+type LegalHandler struct {
+	legalService port.LegalServiceInterface
+	validator    *myvalidator.Validator
+}
+
+func (h *LegalHandler) GetCurrentDocuments(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	docs, err := h.legalService.GetCurrentDocuments(c.Request().Context())
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve legal documents")
+	}
+
+	resp.Message = "Legal documents retrieved successfully"
+	resp.Data = toLegalDocumentResponses(docs)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *LegalHandler) GetConsent(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	userID, _ := c.Get("user_id").(int64)
+
+	status, err := h.legalService.ConsentStatus(c.Request().Context(), userID)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve consent")
+	}
+
+	resp.Message = "Consent retrieved successfully"
+	resp.Data = toConsentStatusResponse(status)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *LegalHandler) Accept(c echo.Context) error {
+	var (
+		req  = request.ConsentRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	userID, _ := c.Get("user_id").(int64)
+	err := h.legalService.Accept(c.Request().Context(), &entity.ConsentEntity{
+		UserID:         userID,
+		TermsVersion:   req.TermsVersion,
+		PrivacyVersion: req.PrivacyVersion,
+		IPAddress:      c.RealIP(),
+		UserAgent:      c.Request().UserAgent(),
+	})
+	if err != nil {
+		return h.handleError(c, err, "Failed to record consent")
+	}
+
+	resp.Message = "Consent recorded successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *LegalHandler) GetDocuments(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	docs, err := h.legalService.GetDocuments(c.Request().Context(), c.QueryParam("kind"))
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve legal documents")
+	}
+
+	resp.Message = "Legal documents retrieved successfully"
+	resp.Data = toLegalDocumentResponses(docs)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *LegalHandler) PublishDocument(c echo.Context) error {
+	var (
+		req  = request.LegalDocumentRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	publishedBy, _ := c.Get("user_id").(int64)
+	doc, err := h.legalService.PublishDocument(c.Request().Context(), &entity.LegalDocumentEntity{
+		Kind:        req.Kind,
+		Version:     req.Version,
+		URL:         req.URL,
+		PublishedBy: publishedBy,
+	})
+	if err != nil {
+		return h.handleError(c, err, "Failed to publish legal document")
+	}
+
+	resp.Message = "Legal document published successfully"
+	resp.Data = toLegalDocumentResponse(doc)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *LegalHandler) GetUserConsents(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid user ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	history, err := h.legalService.GetConsentHistory(c.Request().Context(), userID)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve consent")
+	}
+
+	consentData := make([]response.UserConsentResponse, 0, len(history))
+	for _, consent := range history {
+		consentData = append(consentData, response.UserConsentResponse{
+			Kind:       consent.Kind,
+			Version:    consent.Version,
+			IPAddress:  consent.IPAddress,
+			UserAgent:  consent.UserAgent,
+			AcceptedAt: consent.AcceptedAt,
+		})
+	}
+
+	resp.Message = "Consent retrieved successfully"
+	resp.Data = consentData
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *LegalHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Str("path", c.Path()).Msg("[LegalHandler] Request failed")
+
+	switch {
+	case err.Error() == "user not found":
+		resp.Message = "User not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case err.Error() == "legal document version already exists", err.Error() == "consent version is outdated":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusConflict, resp)
+	case strings.HasPrefix(err.Error(), "legal document "):
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toConsentStatusResponse(status *entity.ConsentStatusEntity) response.ConsentStatusResponse {
+	resp := response.ConsentStatusResponse{
+		Required:        status.Required(),
+		AcceptedTerms:   status.AcceptedTerms,
+		AcceptedPrivacy: status.AcceptedPrivacy,
+		ConsentedAt:     status.ConsentedAt,
+	}
+	if status.CurrentTerms != nil {
+		doc := toLegalDocumentResponse(status.CurrentTerms)
+		resp.CurrentTerms = &doc
+	}
+	if status.CurrentPrivacy != nil {
+		doc := toLegalDocumentResponse(status.CurrentPrivacy)
+		resp.CurrentPrivacy = &doc
+	}
+	return resp
+}
+
+func toLegalDocumentResponses(docs []entity.LegalDocumentEntity) []response.LegalDocumentResponse {
+	docData := make([]response.LegalDocumentResponse, 0, len(docs))
+	for i := range docs {
+		docData = append(docData, toLegalDocumentResponse(&docs[i]))
+	}
+	return docData
+}
+
+func toLegalDocumentResponse(doc *entity.LegalDocumentEntity) response.LegalDocumentResponse {
+	return response.LegalDocumentResponse{
+		ID:          doc.ID,
+		Kind:        doc.Kind,
+		Version:     doc.Version,
+		URL:         doc.URL,
+		PublishedAt: doc.PublishedAt,
+	}
+}
+
+func NewLegalHandler(legalService port.LegalServiceInterface) LegalHandlerInterface {
+	return &LegalHandler{
+		legalService: legalService,
+		validator:    myvalidator.NewValidator(),
+	}
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/hilmirazib/jualan-sayur/pkg/logging"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type LogLevelHandlerInterface interface {
+	GetLevel(c echo.Context) error
+	SetLevel(c echo.Context) error
+}
+
+// LogLevelHandler changes the level of this instance only; other replicas keep theirs
+type LogLevelHandler struct {
+	validator *myvalidator.Validator
+}
+
+func (h *LogLevelHandler) GetLevel(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	resp.Message = "Log level retrieved successfully"
+	resp.Data = response.LogLevelResponse{Level: logging.Level()}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *LogLevelHandler) SetLevel(c echo.Context) error {
+	var (
+		req  = request.LogLevelRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	if err := logging.SetLevel(req.Level); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	log.Warn().Interface("admin_id", c.Get("user_id")).Str("level", req.Level).Str("ip", c.RealIP()).Msg("[LogLevelHandler-SetLevel] Log level changed by admin")
+
+	resp.Message = "Log level updated successfully"
+	resp.Data = response.LogLevelResponse{Level: logging.Level()}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func NewLogLevelHandler() LogLevelHandlerInterface {
+	return &LogLevelHandler{
+		validator: myvalidator.NewValidator(),
+	}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type OnboardingHandlerInterface interface {
+	GetOnboarding(c echo.Context) error
+	Dismiss(c echo.Context) error
+	RecordFirstOrder(c echo.Context) error
+}
+
+type OnboardingHandler struct {
+	onboardingService port.OnboardingServiceInterface
+}
+
+func (h *OnboardingHandler) GetOnboarding(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	userID := c.Get("user_id").(int64)
+
+	onboarding, err := h.onboardingService.GetOnboarding(c.Request().Context(), userID)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve onboarding")
+	}
+
+	resp.Message = "Onboarding retrieved successfully"
+	resp.Data = toOnboardingResponse(onboarding)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *OnboardingHandler) Dismiss(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	userID := c.Get("user_id").(int64)
+
+	if err := h.onboardingService.Dismiss(c.Request().Context(), userID); err != nil {
+		return h.handleError(c, err, "Failed to dismiss onboarding")
+	}
+
+	resp.Message = "Onboarding dismissed successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RecordFirstOrder is called by the order service when a user's first order is placed
+func (h *OnboardingHandler) RecordFirstOrder(c echo.Context) error {
+	var (
+		req  = request.FirstOrderRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid user ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	orderedAt := time.Time{}
+	if req.OrderedAt != nil {
+		orderedAt = *req.OrderedAt
+	}
+
+	if err := h.onboardingService.RecordFirstOrder(c.Request().Context(), userID, orderedAt); err != nil {
+		return h.handleError(c, err, "Failed to record first order")
+	}
+
+	resp.Message = "First order recorded successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *OnboardingHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Msg("[OnboardingHandler] Request failed")
+
+	switch err.Error() {
+	case "user not found":
+		resp.Message = "User not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case "invalid user id":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toOnboardingResponse(onboarding *entity.OnboardingEntity) response.OnboardingResponse {
+	items := make([]response.OnboardingItemResponse, 0, len(onboarding.Items))
+	for _, item := range onboarding.Items {
+		items = append(items, response.OnboardingItemResponse{
+			Key:       item.Key,
+			Completed: item.Completed,
+		})
+	}
+
+	return response.OnboardingResponse{
+		Items:       items,
+		Percentage:  onboarding.Percentage,
+		Completed:   onboarding.Completed,
+		Dismissed:   onboarding.Dismissed,
+		CompletedAt: onboarding.CompletedAt,
+	}
+}
+
+func NewOnboardingHandler(onboardingService port.OnboardingServiceInterface) OnboardingHandlerInterface {
+	return &OnboardingHandler{
+		onboardingService: onboardingService,
+	}
+}
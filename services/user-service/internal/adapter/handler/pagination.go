@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+
+	"github.com/labstack/echo/v4"
+)
+
+// pageQuery reads ?page= and ?limit=. A ?cursor= from an earlier meta wins over both; an
+// unreadable one is ignored like any other bad page value and the service falls back to page 1.
+func pageQuery(c echo.Context) (page, limit int) {
+	page, _ = strconv.Atoi(c.QueryParam("page"))
+	limit, _ = strconv.Atoi(c.QueryParam("limit"))
+
+	if cursor := c.QueryParam("cursor"); cursor != "" {
+		if cursorPage, cursorLimit, ok := decodePageCursor(cursor); ok {
+			return cursorPage, cursorLimit
+		}
+	}
+	return page, limit
+}
+
+// respondPage writes a list response with its pagination meta and an RFC 5988 Link header
+func respondPage(c echo.Context, message string, data interface{}, pagination *entity.PaginationEntity) error {
+	meta := &response.PaginationMeta{
+		Page:       pagination.Page,
+		PerPage:    pagination.PerPage,
+		Total:      pagination.TotalCount,
+		TotalPages: pagination.TotalPage,
+	}
+	if pagination.Page < pagination.TotalPage {
+		meta.NextCursor = encodePageCursor(pagination.Page+1, pagination.PerPage)
+	}
+	if pagination.Page > 1 {
+		meta.PrevCursor = encodePageCursor(min(pagination.Page-1, max(pagination.TotalPage, 1)), pagination.PerPage)
+	}
+
+	if links := pageLinks(c, pagination); links != "" {
+		c.Response().Header().Set("Link", links)
+	}
+
+	return c.JSON(http.StatusOK, response.DefaultResponse{
+		Message: message,
+		Data:    data,
+		Meta:    meta,
+	})
+}
+
+// pageLinks keeps the request's filters and only swaps the page, so the links stay relative
+// to whatever host and prefix the client called through
+func pageLinks(c echo.Context, pagination *entity.PaginationEntity) string {
+	lastPage := max(pagination.TotalPage, 1)
+
+	var links []string
+	add := func(page int, rel string) {
+		query := c.Request().URL.Query()
+		query.Del("cursor")
+		query.Set("page", strconv.Itoa(page))
+		query.Set("limit", strconv.Itoa(pagination.PerPage))
+		links = append(links, fmt.Sprintf(`<%s?%s>; rel="%s"`, c.Request().URL.Path, query.Encode(), rel))
+	}
+
+	add(1, "first")
+	if pagination.Page > 1 {
+		add(min(pagination.Page-1, lastPage), "prev")
+	}
+	if pagination.Page < pagination.TotalPage {
+		add(pagination.Page+1, "next")
+	}
+	add(lastPage, "last")
+
+	return strings.Join(links, ", ")
+}
+
+func encodePageCursor(page, limit int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", page, limit)))
+}
+
+func decodePageCursor(cursor string) (page, limit int, ok bool) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	pageStr, limitStr, found := strings.Cut(string(raw), ":")
+	if !found {
+		return 0, 0, false
+	}
+	page, pageErr := strconv.Atoi(pageStr)
+	limit, limitErr := strconv.Atoi(limitStr)
+	if pageErr != nil || limitErr != nil || page < 1 || limit < 1 {
+		return 0, 0, false
+	}
+	return page, limit, true
+}
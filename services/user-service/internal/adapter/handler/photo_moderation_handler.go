@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type PhotoModerationHandlerInterface interface {
+	GetModerations(c echo.Context) error
+	GetModeration(c echo.Context) error
+	Approve(c echo.Context) error
+	Remove(c echo.Context) error
+}
+
+type PhotoModerationHandler struct {
+	photoModerationService port.PhotoModerationServiceInterface
+	validator              *myvalidator.Validator
+}
+
+// GetModerations is the review queue, oldest first; ?status=flagged shows what needs a decision
+func (h *PhotoModerationHandler) GetModerations(c echo.Context) error {
+	status := c.QueryParam("status")
+	switch status {
+	case "", entity.PhotoModerationPending, entity.PhotoModerationApproved, entity.PhotoModerationFlagged,
+		entity.PhotoModerationRemoved, entity.PhotoModerationSuperseded:
+	default:
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Invalid status filter",
+			"data":    nil,
+		})
+	}
+
+	page, limit := pageQuery(c)
+
+	moderations, pagination, err := h.photoModerationService.GetModerations(c.Request().Context(), status, page, limit)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve photo moderations")
+	}
+
+	moderationData := make([]response.PhotoModerationResponse, 0, len(moderations))
+	for i := range moderations {
+		moderationData = append(moderationData, toPhotoModerationResponse(&moderations[i]))
+	}
+
+	return respondPage(c, "Photo moderations retrieved successfully", moderationData, pagination)
+}
+
+func (h *PhotoModerationHandler) GetModeration(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid photo moderation ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	moderation, err := h.photoModerationService.GetModerationByID(c.Request().Context(), id)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve photo moderation")
+	}
+
+	resp.Message = "Photo moderation retrieved successfully"
+	resp.Data = toPhotoModerationResponse(moderation)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *PhotoModerationHandler) Approve(c echo.Context) error {
+	var (
+		req  = request.ApprovePhotoRequest{}
+		resp = response.DefaultResponse{}
+	)
+	adminID := c.Get("user_id").(int64)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid photo moderation ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	moderation, err := h.photoModerationService.Approve(c.Request().Context(), id, adminID, req.Note)
+	if err != nil {
+		return h.handleError(c, err, "Failed to approve photo")
+	}
+
+	resp.Message = "Photo approved successfully"
+	resp.Data = toPhotoModerationResponse(moderation)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *PhotoModerationHandler) Remove(c echo.Context) error {
+	var (
+		req  = request.RemovePhotoRequest{}
+		resp = response.DefaultResponse{}
+	)
+	adminID := c.Get("user_id").(int64)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid photo moderation ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	moderation, err := h.photoModerationService.Remove(c.Request().Context(), id, adminID, req.Reason)
+	if err != nil {
+		return h.handleError(c, err, "Failed to remove photo")
+	}
+
+	resp.Message = "Photo removed successfully. The user has been notified."
+	resp.Data = toPhotoModerationResponse(moderation)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *PhotoModerationHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Str("path", c.Path()).Msg("[PhotoModerationHandler] Request failed")
+
+	switch err.Error() {
+	case "photo moderation not found":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusNotFound, resp)
+	case "photo moderation already decided":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusConflict, resp)
+	case "removal reason is required":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toPhotoModerationResponse(moderation *entity.PhotoModerationEntity) response.PhotoModerationResponse {
+	return response.PhotoModerationResponse{
+		ID:         moderation.ID,
+		UserID:     moderation.UserID,
+		ImageURL:   moderation.ImageURL,
+		Status:     moderation.Status,
+		Score:      moderation.Score,
+		Labels:     moderation.Labels,
+		ReviewNote: moderation.ReviewNote,
+		ReviewedBy: moderation.ReviewedBy,
+		ReviewedAt: moderation.ReviewedAt,
+		CreatedAt:  moderation.CreatedAt,
+		UpdatedAt:  moderation.UpdatedAt,
+	}
+}
+
+func NewPhotoModerationHandler(photoModerationService port.PhotoModerationServiceInterface) PhotoModerationHandlerInterface {
+	return &PhotoModerationHandler{
+		photoModerationService: photoModerationService,
+		validator:              myvalidator.NewValidator(),
+	}
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+
+	"github.com/labstack/echo/v4"
+)
+
+var errInvalidIfMatch = errors.New("invalid If-Match header")
+
+// expectedVersion returns the version an update is based on. The If-Match header wins over
+// the body field; 0 means the client sent neither.
+func expectedVersion(c echo.Context, bodyVersion int64) (int64, error) {
+	ifMatch := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if ifMatch == "" {
+		if bodyVersion < 0 {
+			return 0, errInvalidIfMatch
+		}
+		return bodyVersion, nil
+	}
+
+	ifMatch = strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`)
+	version, err := strconv.ParseInt(ifMatch, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errInvalidIfMatch
+	}
+	return version, nil
+}
+
+func setETag(c echo.Context, version int64) {
+	c.Response().Header().Set("ETag", fmt.Sprintf(`"%d"`, version))
+}
+
+// respondVersionError answers an invalid version or a stale update; handled is false for
+// any other error so the caller can fall through to its own mapping.
+func respondVersionError(c echo.Context, err error) (handled bool, result error) {
+	resp := response.DefaultResponse{}
+
+	if errors.Is(err, errInvalidIfMatch) {
+		resp.Message = "Invalid version, send the version from the latest read"
+		return true, c.JSON(http.StatusBadRequest, resp)
+	}
+
+	var conflict *entity.VersionConflictError
+	if errors.As(err, &conflict) {
+		setETag(c, conflict.CurrentVersion)
+		resp.Message = "The record was changed by someone else, reload and try again"
+		resp.Data = response.VersionConflictResponse{CurrentVersion: conflict.CurrentVersion}
+		return true, c.JSON(http.StatusConflict, resp)
+	}
+
+	return false, nil
+}
+
+func versionRequired(c echo.Context) error {
+	return c.JSON(http.StatusPreconditionRequired, response.DefaultResponse{
+		Message: "Version is required, send it as If-Match header or version field",
+	})
+}
@@ -0,0 +1,8 @@
+package request
+
+type MergeAccountsRequest struct {
+	SourceUserID int64  `json:"source_user_id" validate:"required"`
+	TargetUserID int64  `json:"target_user_id" validate:"required"`
+	Reason       string `json:"reason" validate:"max=500"`
+	DryRun       bool   `json:"dry_run"`
+}
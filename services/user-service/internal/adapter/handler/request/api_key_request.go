@@ -0,0 +1,12 @@
+package request
+
+import "time"
+
+type APIKeyRequest struct {
+	// Name identifies the partner, e.g. "acme-logistics"
+	Name   string   `json:"name" validate:"required,max=100"`
+	Scopes []string `json:"scopes" validate:"required"`
+	// Quota in requests per partner quota window; 0 uses PARTNER_DEFAULT_QUOTA
+	Quota     int        `json:"quota" validate:"min=0"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
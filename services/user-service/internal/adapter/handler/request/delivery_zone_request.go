@@ -0,0 +1,18 @@
+package request
+
+type GeoPointRequest struct {
+	Lat float64 `json:"lat" validate:"required,latitude"`
+	Lng float64 `json:"lng" validate:"required,longitude"`
+}
+
+// DeliveryZoneRequest carries either a center/radius or a polygon depending on Type;
+// the service enforces which geometry is required.
+type DeliveryZoneRequest struct {
+	Name      string            `json:"name" validate:"required,min=2,max=100"`
+	Type      string            `json:"type" validate:"required,oneof=radius polygon"`
+	CenterLat float64           `json:"center_lat" validate:"omitempty,latitude"`
+	CenterLng float64           `json:"center_lng" validate:"omitempty,longitude"`
+	RadiusKm  float64           `json:"radius_km" validate:"omitempty,gt=0,max=500"`
+	Polygon   []GeoPointRequest `json:"polygon" validate:"omitempty,dive"`
+	IsActive  *bool             `json:"is_active"`
+}
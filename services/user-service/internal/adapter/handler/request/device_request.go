@@ -0,0 +1,5 @@
+package request
+
+type TrustDeviceRequest struct {
+	Trusted *bool `json:"trusted"`
+}
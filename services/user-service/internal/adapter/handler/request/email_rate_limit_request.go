@@ -0,0 +1,7 @@
+package request
+
+type EmailRateLimitRequest struct {
+	// EmailType is "*" for the quota shared by all emails, or a template such as back_in_stock
+	EmailType  string `json:"email_type" validate:"required,max=50"`
+	MaxPerHour *int   `json:"max_per_hour" validate:"required,min=0,max=1000"`
+}
@@ -0,0 +1,12 @@
+package request
+
+type FeatureFlagRequest struct {
+	Key               string `json:"key"`
+	Description       string `json:"description" validate:"max=500"`
+	Enabled           bool   `json:"enabled"`
+	RolloutPercentage int    `json:"rollout_percentage" validate:"gte=0,lte=100"`
+}
+
+type FeatureFlagOverrideRequest struct {
+	Enabled bool `json:"enabled"`
+}
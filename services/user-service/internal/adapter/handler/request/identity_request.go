@@ -0,0 +1,6 @@
+package request
+
+type RejectIdentityRequest struct {
+	// Reason is shown to the user, e.g. "the photo of the document is blurry"
+	Reason string `json:"reason" validate:"required,max=500"`
+}
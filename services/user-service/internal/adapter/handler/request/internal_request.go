@@ -0,0 +1,19 @@
+package request
+
+// BatchUsersRequest names who the details are for: "vendor" (the default) gets them masked by
+// each user's privacy settings, "courier" gets everything needed to deliver
+type BatchUsersRequest struct {
+	IDs      []int64 `json:"ids" validate:"required"`
+	Audience string  `json:"audience" validate:"omitempty,oneof=vendor courier"`
+}
+
+type IntrospectTokenRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// EmailBounceRequest is a bounce reported by the mail provider; soft bounces are accepted and ignored
+type EmailBounceRequest struct {
+	Email  string `json:"email" validate:"required,email"`
+	Type   string `json:"type" validate:"required,oneof=hard soft"`
+	Reason string `json:"reason" validate:"max=255"`
+}
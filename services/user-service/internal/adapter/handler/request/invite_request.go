@@ -0,0 +1,11 @@
+package request
+
+import "time"
+
+type InviteRequest struct {
+	// Code is generated when empty, e.g. "K7QMX-4TPZA"
+	Code      string     `json:"code" validate:"max=32"`
+	Note      string     `json:"note" validate:"max=255"`
+	MaxUses   int        `json:"max_uses" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -0,0 +1,12 @@
+package request
+
+type IPRuleRequest struct {
+	ListType string `json:"list_type" validate:"required,oneof=allow deny"`
+	CIDR     string `json:"cidr" validate:"required"`
+	Note     string `json:"note" validate:"max=255"`
+}
+
+type IPRuleDeleteRequest struct {
+	ListType string `json:"list_type" validate:"required,oneof=allow deny"`
+	CIDR     string `json:"cidr" validate:"required"`
+}
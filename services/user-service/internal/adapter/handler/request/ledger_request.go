@@ -0,0 +1,17 @@
+package request
+
+type RecordEarningRequest struct {
+	OrderID string `json:"order_id" validate:"required,max=100"`
+	Amount  int64  `json:"amount" validate:"required,gt=0"`
+}
+
+type WithdrawalRequest struct {
+	Amount        int64  `json:"amount" validate:"required,gt=0"`
+	BankName      string `json:"bank_name" validate:"required,max=100"`
+	AccountNumber string `json:"account_number" validate:"required,numeric,max=50"`
+	AccountName   string `json:"account_name" validate:"required,max=100"`
+}
+
+type RejectWithdrawalRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
@@ -0,0 +1,14 @@
+package request
+
+type LegalDocumentRequest struct {
+	// Kind is "terms" or "privacy"
+	Kind    string `json:"kind" validate:"required,oneof=terms privacy"`
+	Version string `json:"version" validate:"required,max=32"`
+	URL     string `json:"url" validate:"required,url,max=500"`
+}
+
+// ConsentRequest names the versions the user was shown; omit a kind that is not published
+type ConsentRequest struct {
+	TermsVersion   string `json:"terms_version" validate:"max=32"`
+	PrivacyVersion string `json:"privacy_version" validate:"max=32"`
+}
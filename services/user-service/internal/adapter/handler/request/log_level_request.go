@@ -0,0 +1,5 @@
+package request
+
+type LogLevelRequest struct {
+	Level string `json:"level" validate:"required,oneof=trace debug info warn error"`
+}
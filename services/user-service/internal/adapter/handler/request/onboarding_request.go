@@ -0,0 +1,8 @@
+package request
+
+import "time"
+
+type FirstOrderRequest struct {
+	OrderID   string     `json:"order_id"`
+	OrderedAt *time.Time `json:"ordered_at"`
+}
@@ -0,0 +1,10 @@
+package request
+
+type ApprovePhotoRequest struct {
+	Note string `json:"note" validate:"max=500"`
+}
+
+type RemovePhotoRequest struct {
+	// Reason is included in the email to the user
+	Reason string `json:"reason" validate:"required,max=500"`
+}
@@ -3,3 +3,16 @@ package request
 type CreateRoleRequest struct {
 	Name string `json:"name" validate:"required,min=2,max=50"`
 }
+
+type UpdateRoleRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=50"`
+	// Version of the role this edit is based on; the If-Match header may be used instead
+	Version int64 `json:"version"`
+}
+
+type SetRoleParentRequest struct {
+	// ParentID is the role to inherit from; null makes the role a root role
+	ParentID *int64 `json:"parent_id" validate:"omitempty,min=1"`
+	// Version of the role this edit is based on; the If-Match header may be used instead
+	Version int64 `json:"version"`
+}
@@ -0,0 +1,10 @@
+package request
+
+type SavedViewRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	// List is the admin list the view applies to, e.g. "customers"; ignored on update
+	List string `json:"list" validate:"omitempty,max=50"`
+	// Filters are the list's query parameters, e.g. {"search": "budi", "orderBy": "users.name ASC"}
+	Filters map[string]string `json:"filters" validate:"max=20"`
+	Shared  bool              `json:"shared"`
+}
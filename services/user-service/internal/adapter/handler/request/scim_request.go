@@ -0,0 +1,48 @@
+package request
+
+// SCIMUserRequest is a SCIM User resource as identity providers send it. userName must be the
+// account's email; the emails list is ignored.
+type SCIMUserRequest struct {
+	Schemas      []string              `json:"schemas"`
+	ExternalID   string                `json:"externalId"`
+	UserName     string                `json:"userName"`
+	Name         SCIMNameRequest       `json:"name"`
+	DisplayName  string                `json:"displayName"`
+	PhoneNumbers []SCIMMultiValuedAttr `json:"phoneNumbers"`
+	// Active defaults to true when left out
+	Active *bool `json:"active"`
+}
+
+type SCIMNameRequest struct {
+	Formatted  string `json:"formatted"`
+	GivenName  string `json:"givenName"`
+	FamilyName string `json:"familyName"`
+}
+
+type SCIMMultiValuedAttr struct {
+	Value   string `json:"value"`
+	Type    string `json:"type"`
+	Primary bool   `json:"primary"`
+}
+
+// SCIMGroupRequest replaces a group's members; displayName must stay the role's name
+type SCIMGroupRequest struct {
+	Schemas     []string            `json:"schemas"`
+	DisplayName string              `json:"displayName"`
+	Members     []SCIMMemberRequest `json:"members"`
+}
+
+type SCIMMemberRequest struct {
+	Value string `json:"value"`
+}
+
+type SCIMPatchRequest struct {
+	Schemas    []string                `json:"schemas"`
+	Operations []SCIMPatchOperationReq `json:"Operations"`
+}
+
+type SCIMPatchOperationReq struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
@@ -0,0 +1,26 @@
+package request
+
+import "time"
+
+type SegmentRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	City string `json:"city" validate:"max=100"`
+	// Signup window: from is inclusive, to is exclusive
+	SignedUpFrom *time.Time `json:"signed_up_from"`
+	SignedUpTo   *time.Time `json:"signed_up_to"`
+	MinOrders    *int       `json:"min_orders" validate:"omitempty,min=0"`
+	MaxOrders    *int       `json:"max_orders" validate:"omitempty,min=0"`
+}
+
+type CampaignRequest struct {
+	Name    string `json:"name" validate:"required,max=100"`
+	Subject string `json:"subject" validate:"required,max=255"`
+	Body    string `json:"body" validate:"required"`
+	// Data is passed through to notification-service templates, e.g. a voucher code
+	Data map[string]string `json:"data"`
+}
+
+type OrderRequest struct {
+	OrderID   string     `json:"order_id" validate:"required,max=100"`
+	OrderedAt *time.Time `json:"ordered_at"`
+}
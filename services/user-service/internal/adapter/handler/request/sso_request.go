@@ -0,0 +1,7 @@
+package request
+
+// SSOCallbackRequest carries what the provider sent back to SSO_REDIRECT_URL
+type SSOCallbackRequest struct {
+	Code  string `json:"code" validate:"required,max=2048"`
+	State string `json:"state" validate:"required,max=128"`
+}
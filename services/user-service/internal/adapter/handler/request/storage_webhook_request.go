@@ -0,0 +1,16 @@
+package request
+
+// SupabaseStorageWebhookRequest is a Supabase database webhook on the storage.objects table
+type SupabaseStorageWebhookRequest struct {
+	Type      string                 `json:"type"`
+	Table     string                 `json:"table"`
+	Schema    string                 `json:"schema"`
+	Record    *SupabaseStorageObject `json:"record"`
+	OldRecord *SupabaseStorageObject `json:"old_record"`
+}
+
+type SupabaseStorageObject struct {
+	ID       string `json:"id"`
+	BucketID string `json:"bucket_id"`
+	Name     string `json:"name"`
+}
@@ -0,0 +1,11 @@
+package request
+
+type AssignSupportTicketRequest struct {
+	AssigneeID int64 `json:"assignee_id" validate:"required"`
+}
+
+type UpdateSupportTicketStatusRequest struct {
+	Status string `json:"status" validate:"required"`
+	// Note is emailed to the customer with the new status, e.g. "your refund was sent today"
+	Note string `json:"note" validate:"max=1000"`
+}
@@ -0,0 +1,20 @@
+package request
+
+type CreateUploadRequest struct {
+	Filename    string `json:"filename" validate:"required,max=255"`
+	ContentType string `json:"content_type" validate:"required"`
+	Size        int64  `json:"size" validate:"required,gt=0"`
+	// Checksum is the hex SHA-256 of the whole file
+	Checksum string `json:"checksum" validate:"required,len=64,hexadecimal"`
+}
+
+// ProfilePhotoEditRequest comes in the multipart form next to the photo. Rotation is clockwise
+// degrees; the crop is in pixels of the upright, rotated image and, when left out, the centred
+// square is used.
+type ProfilePhotoEditRequest struct {
+	Rotation   int  `form:"rotation" validate:"oneof=0 90 180 270"`
+	CropX      *int `form:"crop_x" validate:"required_with=CropY CropWidth CropHeight,omitempty,min=0"`
+	CropY      *int `form:"crop_y" validate:"required_with=CropX CropWidth CropHeight,omitempty,min=0"`
+	CropWidth  *int `form:"crop_width" validate:"required_with=CropX CropY CropHeight,omitempty,min=1"`
+	CropHeight *int `form:"crop_height" validate:"required_with=CropX CropY CropWidth,omitempty,min=1"`
+}
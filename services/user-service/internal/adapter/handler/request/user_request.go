@@ -1,21 +1,58 @@
 package request
 
+// SignInRequest takes either an email or a username; email wins when both are sent
 type SignInRequest struct {
-	Email    string `json:"email" validate:"email,required"`
+	Email    string `json:"email" validate:"required_without=Username,omitempty,email"`
+	Username string `json:"username" validate:"required_without=Email,omitempty,username"`
 	Password string `json:"password" validate:"required,min=8"`
+	// RememberMe picks the long session lifetime (SESSION_REMEMBER_ME_TTL) over SESSION_TTL
+	RememberMe bool `json:"remember_me"`
+}
+
+type VerifySignInOTPRequest struct {
+	ChallengeID string `json:"challenge_id" validate:"required"`
+	OTP         string `json:"otp" validate:"required,len=6,numeric"`
 }
 
 type CreateUserAccountRequest struct {
-	Email                string `json:"email" validate:"email,required"`
-	Name                 string `json:"name" validate:"required,min=2,max=100"`
-	Password             string `json:"password" validate:"required,min=8"`
-	PasswordConfirmation string `json:"password_confirmation" validate:"required,eqfield=Password"`
+	Email                string  `json:"email" validate:"email,required"`
+	Name                 string  `json:"name" validate:"required,min=2,max=100"`
+	Password             string  `json:"password" validate:"required,min=8"`
+	PasswordConfirmation string  `json:"password_confirmation" validate:"required,eqfield=Password"`
+	Lat                  float64 `json:"lat" validate:"omitempty,latitude"`
+	Lng                  float64 `json:"lng" validate:"omitempty,longitude"`
+	// InviteCode is only checked when REGISTRATION_MODE is invite
+	InviteCode string `json:"invite_code" validate:"max=32"`
+	// AcceptTerms accepts the current terms of service and privacy policy
+	AcceptTerms bool `json:"accept_terms"`
+}
+
+type ChangeUsernameRequest struct {
+	Username string `json:"username" validate:"required,username"`
+}
+
+type ChangeTimezoneRequest struct {
+	Timezone string `json:"timezone" validate:"required"`
+}
+
+type ChangeLanguageRequest struct {
+	Language string `json:"language" validate:"required"`
+}
+
+// ChangePrivacySettingsRequest replaces both settings, so both must be sent
+type ChangePrivacySettingsRequest struct {
+	HidePhone   *bool `json:"hide_phone" validate:"required"`
+	HideAddress *bool `json:"hide_address" validate:"required"`
 }
 
 type ForgotPasswordRequest struct {
 	Email string `json:"email" validate:"email,required"`
 }
 
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"email,required"`
+}
+
 type ResetPasswordRequest struct {
 	Token                string `json:"token" validate:"required"`
 	Password             string `json:"password" validate:"required,min=8"`
@@ -27,7 +64,9 @@ type UpdateProfileRequest struct {
 	Name    string  `json:"name" validate:"required,min=2,max=100"`
 	Phone   string  `json:"phone" validate:"required"`
 	Address string  `json:"address" validate:"required"`
-	Lat     float64 `json:"lat" validate:"required"`
-	Lng     float64 `json:"lng" validate:"required"`
+	Lat     float64 `json:"lat" validate:"required,latitude"`
+	Lng     float64 `json:"lng" validate:"required,longitude"`
 	Photo   string  `json:"photo" validate:"required"`
+	// Version of the profile this edit is based on; the If-Match header may be used instead
+	Version int64 `json:"version"`
 }
@@ -0,0 +1,14 @@
+package request
+
+type RegisterVendorRequest struct {
+	StoreName   string  `json:"store_name" validate:"required,min=3,max=100"`
+	Description string  `json:"description" validate:"max=1000"`
+	Phone       string  `json:"phone" validate:"max=20"`
+	Address     string  `json:"address" validate:"max=500"`
+	Lat         float64 `json:"lat" validate:"omitempty,latitude"`
+	Lng         float64 `json:"lng" validate:"omitempty,longitude"`
+}
+
+type RejectVendorRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
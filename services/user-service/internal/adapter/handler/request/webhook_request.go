@@ -0,0 +1,18 @@
+package request
+
+import "encoding/json"
+
+type WebhookEndpointRequest struct {
+	URL        string   `json:"url" validate:"required,url,max=2048"`
+	Secret     string   `json:"secret" validate:"max=255"`
+	EventTypes []string `json:"event_types" validate:"required"`
+	// IsActive defaults to true when omitted
+	IsActive    *bool  `json:"is_active"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+type WebhookEventRequest struct {
+	EventID string          `json:"event_id" validate:"required,max=200"`
+	Event   string          `json:"event" validate:"required"`
+	Data    json.RawMessage `json:"data"`
+}
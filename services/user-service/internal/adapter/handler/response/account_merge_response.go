@@ -0,0 +1,17 @@
+package response
+
+import "time"
+
+type AccountMergeResponse struct {
+	SourceUserID          int64     `json:"source_user_id"`
+	TargetUserID          int64     `json:"target_user_id"`
+	SourceEmail           string    `json:"source_email"`
+	TargetEmail           string    `json:"target_email"`
+	ProfileFieldsCopied   []string  `json:"profile_fields_copied"`
+	DevicesMoved          int64     `json:"devices_moved"`
+	FeatureOverridesMoved int64     `json:"feature_overrides_moved"`
+	VendorMoved           bool      `json:"vendor_moved"`
+	OnboardingMerged      bool      `json:"onboarding_merged"`
+	DryRun                bool      `json:"dry_run"`
+	MergedAt              time.Time `json:"merged_at"`
+}
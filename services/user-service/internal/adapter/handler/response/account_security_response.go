@@ -0,0 +1,11 @@
+package response
+
+import "time"
+
+type LockedAccountResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Email      string     `json:"email"`
+	LockedAt   *time.Time `json:"locked_at"`
+	LockReason string     `json:"lock_reason"`
+}
@@ -0,0 +1,18 @@
+package response
+
+import "time"
+
+type APIKeyResponse struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Prefix string `json:"prefix"`
+	// Key is only returned when the key is created
+	Key        string     `json:"key,omitempty"`
+	Scopes     []string   `json:"scopes"`
+	Quota      int        `json:"quota"`
+	Status     string     `json:"status"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package response
+
+import "time"
+
+type AssetResponse struct {
+	Name        string    `json:"name"`
+	URL         string    `json:"url"`
+	Hash        string    `json:"hash"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// AssetManifestEntry is what the templating engine needs to link an asset
+type AssetManifestEntry struct {
+	URL  string `json:"url"`
+	Hash string `json:"hash"`
+}
@@ -0,0 +1,13 @@
+package response
+
+import "time"
+
+type AuditLogResponse struct {
+	ID        int64                  `json:"id"`
+	UserID    int64                  `json:"user_id,omitempty"`
+	Event     string                 `json:"event"`
+	IPAddress string                 `json:"ip_address"`
+	UserAgent string                 `json:"user_agent"`
+	Metadata  map[string]interface{} `json:"metadata"`
+	CreatedAt time.Time              `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package response
+
+type CustomerImportRowErrorResponse struct {
+	Row     int    `json:"row,omitempty"`
+	Email   string `json:"email,omitempty"`
+	Message string `json:"message"`
+}
+
+type CustomerImportResponse struct {
+	TotalRows      int                              `json:"total_rows"`
+	Valid          int                              `json:"valid"`
+	Imported       int                              `json:"imported"`
+	InvitesSent    int                              `json:"invites_sent"`
+	Failed         int                              `json:"failed"`
+	DryRun         bool                             `json:"dry_run"`
+	Errors         []CustomerImportRowErrorResponse `json:"errors"`
+	ErrorReportURL string                           `json:"error_report_url,omitempty"`
+}
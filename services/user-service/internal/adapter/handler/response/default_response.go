@@ -1,6 +1,17 @@
 package response
 
 type DefaultResponse struct {
-	Message string      `json:"message"`
-	Data    interface{} `json:"data"`
+	Message string          `json:"message"`
+	Data    interface{}     `json:"data"`
+	Meta    *PaginationMeta `json:"meta,omitempty"`
+}
+
+// PaginationMeta is set on every list response; a cursor is empty when there is no such page
+type PaginationMeta struct {
+	Page       int    `json:"page"`
+	PerPage    int    `json:"per_page"`
+	Total      int64  `json:"total"`
+	TotalPages int    `json:"total_pages"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
 }
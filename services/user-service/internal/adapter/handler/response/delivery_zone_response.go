@@ -0,0 +1,24 @@
+package response
+
+type GeoPointResponse struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+type DeliveryZoneResponse struct {
+	ID        int64              `json:"id"`
+	Name      string             `json:"name"`
+	Type      string             `json:"type"`
+	CenterLat float64            `json:"center_lat,omitempty"`
+	CenterLng float64            `json:"center_lng,omitempty"`
+	RadiusKm  float64            `json:"radius_km,omitempty"`
+	Polygon   []GeoPointResponse `json:"polygon,omitempty"`
+	IsActive  bool               `json:"is_active"`
+}
+
+type ZoneCheckResponse struct {
+	Covered  bool   `json:"covered"`
+	ZoneID   int64  `json:"zone_id,omitempty"`
+	ZoneName string `json:"zone_name,omitempty"`
+	Message  string `json:"message"`
+}
@@ -0,0 +1,12 @@
+package response
+
+import "time"
+
+type DeviceResponse struct {
+	ID          int64     `json:"id"`
+	UserAgent   string    `json:"user_agent"`
+	LastIP      string    `json:"last_ip"`
+	Trusted     bool      `json:"trusted"`
+	FirstSeenAt time.Time `json:"first_seen_at"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
@@ -0,0 +1,10 @@
+package response
+
+import "time"
+
+type EmailRateLimitResponse struct {
+	EmailType  string    `json:"email_type"`
+	MaxPerHour int       `json:"max_per_hour"`
+	UpdatedBy  int64     `json:"updated_by,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
@@ -0,0 +1,13 @@
+package response
+
+import "time"
+
+type FeatureFlagResponse struct {
+	Key               string         `json:"key"`
+	Description       string         `json:"description"`
+	Enabled           bool           `json:"enabled"`
+	RolloutPercentage int            `json:"rollout_percentage"`
+	Overrides         map[int64]bool `json:"overrides"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
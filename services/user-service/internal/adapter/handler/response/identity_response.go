@@ -0,0 +1,29 @@
+package response
+
+import "time"
+
+type IdentityStatusResponse struct {
+	// Status is none, pending, approved, rejected or expired
+	Status          string     `json:"status"`
+	Verified        bool       `json:"verified"`
+	AgeVerified     bool       `json:"age_verified"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+	RejectionReason string     `json:"rejection_reason,omitempty"`
+	SubmittedAt     *time.Time `json:"submitted_at"`
+}
+
+type IdentityVerificationResponse struct {
+	ID              int64      `json:"id"`
+	UserID          int64      `json:"user_id"`
+	DocumentType    string     `json:"document_type"`
+	ContentType     string     `json:"content_type"`
+	DateOfBirth     string     `json:"date_of_birth"`
+	Status          string     `json:"status"`
+	RejectionReason string     `json:"rejection_reason,omitempty"`
+	ReviewedBy      int64      `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	// DocumentURL is only set on the single-item admin view and expires after a few minutes
+	DocumentURL string `json:"document_url,omitempty"`
+}
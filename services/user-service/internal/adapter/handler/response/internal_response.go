@@ -0,0 +1,34 @@
+package response
+
+type InternalUserResponse struct {
+	ID         int64   `json:"id"`
+	Name       string  `json:"name"`
+	Phone      string  `json:"phone"`
+	Address    string  `json:"address"`
+	Province   string  `json:"province"`
+	City       string  `json:"city"`
+	District   string  `json:"district"`
+	PostalCode string  `json:"postal_code"`
+	Lat        float64 `json:"lat"`
+	Lng        float64 `json:"lng"`
+	// HiddenFields lists what the user's privacy settings blanked, e.g. ["phone"]
+	HiddenFields []string `json:"hidden_fields,omitempty"`
+}
+
+type BatchUsersResponse struct {
+	Users      []InternalUserResponse `json:"users"`
+	MissingIDs []int64                `json:"missing_ids"`
+}
+
+// TokenIntrospectionResponse follows RFC 7662: an inactive token carries only active=false
+type TokenIntrospectionResponse struct {
+	Active    bool   `json:"active"`
+	Sub       string `json:"sub,omitempty"`
+	UserID    int64  `json:"user_id,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Role      string `json:"role,omitempty"`
+	SessionID string `json:"session_id,omitempty"`
+	Iss       string `json:"iss,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+}
@@ -0,0 +1,15 @@
+package response
+
+import "time"
+
+type InviteResponse struct {
+	ID        int64      `json:"id"`
+	Code      string     `json:"code"`
+	Note      string     `json:"note,omitempty"`
+	MaxUses   int        `json:"max_uses"`
+	Uses      int        `json:"uses"`
+	Status    string     `json:"status"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	RevokedAt *time.Time `json:"revoked_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,12 @@
+package response
+
+import "time"
+
+type IPRuleResponse struct {
+	CIDR      string    `json:"cidr"`
+	ListType  string    `json:"list_type"`
+	Note      string    `json:"note"`
+	CreatedBy int64     `json:"created_by,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Static    bool      `json:"static"`
+}
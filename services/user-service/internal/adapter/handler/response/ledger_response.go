@@ -0,0 +1,51 @@
+package response
+
+import "time"
+
+type VendorBalanceResponse struct {
+	VendorID      int64 `json:"vendor_id"`
+	Available     int64 `json:"available"`
+	PendingPayout int64 `json:"pending_payout"`
+	TotalEarned   int64 `json:"total_earned"`
+	TotalPaidOut  int64 `json:"total_paid_out"`
+}
+
+type LedgerEntryResponse struct {
+	ID              int64     `json:"id"`
+	TransactionID   int64     `json:"transaction_id"`
+	TransactionType string    `json:"transaction_type"`
+	ReferenceID     string    `json:"reference_id"`
+	Account         string    `json:"account"`
+	Amount          int64     `json:"amount"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type LedgerTransactionResponse struct {
+	ID          int64                 `json:"id"`
+	VendorID    int64                 `json:"vendor_id"`
+	Type        string                `json:"type"`
+	ReferenceID string                `json:"reference_id"`
+	Entries     []LedgerEntryResponse `json:"entries"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+type WithdrawalResponse struct {
+	ID              int64      `json:"id"`
+	VendorID        int64      `json:"vendor_id"`
+	Amount          int64      `json:"amount"`
+	BankName        string     `json:"bank_name"`
+	AccountNumber   string     `json:"account_number"`
+	AccountName     string     `json:"account_name"`
+	Status          string     `json:"status"`
+	RejectionReason string     `json:"rejection_reason,omitempty"`
+	ReviewedAt      *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type LedgerAuditResponse struct {
+	Balanced               bool             `json:"balanced"`
+	NetTotal               int64            `json:"net_total"`
+	TransactionCount       int64            `json:"transaction_count"`
+	AccountTotals          map[string]int64 `json:"account_totals"`
+	UnbalancedTransactions []int64          `json:"unbalanced_transactions"`
+}
@@ -0,0 +1,28 @@
+package response
+
+import "time"
+
+type LegalDocumentResponse struct {
+	ID          int64     `json:"id"`
+	Kind        string    `json:"kind"`
+	Version     string    `json:"version"`
+	URL         string    `json:"url"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+type ConsentStatusResponse struct {
+	Required        bool                   `json:"consent_required"`
+	AcceptedTerms   string                 `json:"accepted_terms_version"`
+	AcceptedPrivacy string                 `json:"accepted_privacy_version"`
+	ConsentedAt     *time.Time             `json:"consented_at"`
+	CurrentTerms    *LegalDocumentResponse `json:"current_terms"`
+	CurrentPrivacy  *LegalDocumentResponse `json:"current_privacy"`
+}
+
+type UserConsentResponse struct {
+	Kind       string    `json:"kind"`
+	Version    string    `json:"version"`
+	IPAddress  string    `json:"ip_address"`
+	UserAgent  string    `json:"user_agent"`
+	AcceptedAt time.Time `json:"accepted_at"`
+}
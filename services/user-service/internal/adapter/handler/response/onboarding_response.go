@@ -0,0 +1,16 @@
+package response
+
+import "time"
+
+type OnboardingItemResponse struct {
+	Key       string `json:"key"`
+	Completed bool   `json:"completed"`
+}
+
+type OnboardingResponse struct {
+	Items       []OnboardingItemResponse `json:"items"`
+	Percentage  int                      `json:"percentage"`
+	Completed   bool                     `json:"completed"`
+	Dismissed   bool                     `json:"dismissed"`
+	CompletedAt *time.Time               `json:"completed_at"`
+}
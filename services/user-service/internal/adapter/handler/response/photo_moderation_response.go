@@ -0,0 +1,17 @@
+package response
+
+import "time"
+
+type PhotoModerationResponse struct {
+	ID         int64      `json:"id"`
+	UserID     int64      `json:"user_id"`
+	ImageURL   string     `json:"image_url"`
+	Status     string     `json:"status"`
+	Score      *float64   `json:"score"`
+	Labels     []string   `json:"labels"`
+	ReviewNote string     `json:"review_note,omitempty"`
+	ReviewedBy int64      `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
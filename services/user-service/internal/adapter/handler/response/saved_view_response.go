@@ -0,0 +1,17 @@
+package response
+
+import "time"
+
+type SavedViewResponse struct {
+	ID      int64             `json:"id"`
+	List    string            `json:"list"`
+	Name    string            `json:"name"`
+	Filters map[string]string `json:"filters"`
+	// Query is Filters encoded for the list endpoint, e.g. "orderBy=users.name+ASC&search=budi"
+	Query     string    `json:"query"`
+	Shared    bool      `json:"shared"`
+	OwnerID   int64     `json:"owner_id"`
+	Owned     bool      `json:"owned"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,68 @@
+package response
+
+import "time"
+
+// SCIM schema URNs (RFC 7643, RFC 7644)
+const (
+	SCIMSchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMSchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCIMSchemaListResponse          = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIMSchemaPatchOp               = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIMSchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SCIMSchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+)
+
+type SCIMListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int64       `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+type SCIMUserResponse struct {
+	Schemas      []string              `json:"schemas"`
+	ID           string                `json:"id"`
+	ExternalID   string                `json:"externalId,omitempty"`
+	UserName     string                `json:"userName"`
+	Name         SCIMNameResponse      `json:"name"`
+	DisplayName  string                `json:"displayName"`
+	Emails       []SCIMMultiValuedAttr `json:"emails"`
+	PhoneNumbers []SCIMMultiValuedAttr `json:"phoneNumbers,omitempty"`
+	Active       bool                  `json:"active"`
+	Groups       []SCIMReference       `json:"groups,omitempty"`
+	Meta         SCIMMeta              `json:"meta"`
+}
+
+type SCIMNameResponse struct {
+	Formatted string `json:"formatted"`
+}
+
+type SCIMMultiValuedAttr struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type SCIMGroupResponse struct {
+	Schemas     []string        `json:"schemas"`
+	ID          string          `json:"id"`
+	DisplayName string          `json:"displayName"`
+	Members     []SCIMReference `json:"members,omitempty"`
+	Meta        SCIMMeta        `json:"meta"`
+}
+
+// SCIMReference points at another resource: a user's group or a group's member
+type SCIMReference struct {
+	Value   string `json:"value"`
+	Ref     string `json:"$ref"`
+	Display string `json:"display"`
+}
+
+type SCIMMeta struct {
+	ResourceType string    `json:"resourceType"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+	Version      string    `json:"version"`
+}
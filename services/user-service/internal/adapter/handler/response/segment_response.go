@@ -0,0 +1,25 @@
+package response
+
+import "time"
+
+type SegmentResponse struct {
+	ID             int64      `json:"id"`
+	Name           string     `json:"name"`
+	City           string     `json:"city,omitempty"`
+	SignedUpFrom   *time.Time `json:"signed_up_from,omitempty"`
+	SignedUpTo     *time.Time `json:"signed_up_to,omitempty"`
+	MinOrders      *int       `json:"min_orders,omitempty"`
+	MaxOrders      *int       `json:"max_orders,omitempty"`
+	MemberCount    int64      `json:"member_count"`
+	MaterializedAt *time.Time `json:"materialized_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type CampaignResponse struct {
+	ID         int64     `json:"id"`
+	SegmentID  int64     `json:"segment_id"`
+	Name       string    `json:"name"`
+	Recipients int64     `json:"recipients"`
+	Batches    int       `json:"batches"`
+	CreatedAt  time.Time `json:"created_at"`
+}
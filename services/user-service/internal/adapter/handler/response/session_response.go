@@ -0,0 +1,15 @@
+package response
+
+import "time"
+
+type SessionResponse struct {
+	SessionID string `json:"session_id"`
+	UserAgent string `json:"user_agent"`
+	IPAddress string `json:"ip_address"`
+	// DeviceName falls back to the raw user agent for clients that are not recognised
+	DeviceName string    `json:"device_name"`
+	Location   string    `json:"location"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
@@ -0,0 +1,10 @@
+package response
+
+import "time"
+
+// SSOLoginResponse sends the admin panel to the identity provider; the sign-in has to be
+// finished before ExpiresAt
+type SSOLoginResponse struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
@@ -0,0 +1,25 @@
+package response
+
+import "time"
+
+type SupportAttachmentResponse struct {
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// URL is only set on single-ticket views and expires after a few minutes
+	URL string `json:"url,omitempty"`
+}
+
+type SupportTicketResponse struct {
+	ID          int64                       `json:"id"`
+	UserID      int64                       `json:"user_id"`
+	Category    string                      `json:"category"`
+	Subject     string                      `json:"subject"`
+	Message     string                      `json:"message"`
+	Attachments []SupportAttachmentResponse `json:"attachments"`
+	Status      string                      `json:"status"`
+	AssigneeID  int64                       `json:"assignee_id,omitempty"`
+	ResolvedAt  *time.Time                  `json:"resolved_at"`
+	CreatedAt   time.Time                   `json:"created_at"`
+	UpdatedAt   time.Time                   `json:"updated_at"`
+}
@@ -0,0 +1,18 @@
+package response
+
+import "time"
+
+type TrashedUserResponse struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	RoleName     string    `json:"role_name"`
+	MergedIntoID *int64    `json:"merged_into_id,omitempty"`
+	DeletedAt    time.Time `json:"deleted_at"`
+}
+
+type TrashedRoleResponse struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
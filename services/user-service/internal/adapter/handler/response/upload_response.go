@@ -0,0 +1,23 @@
+package response
+
+import "time"
+
+type UploadSessionResponse struct {
+	ID             string    `json:"id"`
+	Filename       string    `json:"filename"`
+	ContentType    string    `json:"content_type"`
+	Size           int64     `json:"size"`
+	ChunkSize      int64     `json:"chunk_size"`
+	TotalChunks    int       `json:"total_chunks"`
+	ReceivedChunks []int     `json:"received_chunks"`
+	MissingChunks  []int     `json:"missing_chunks"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+type UploadResultResponse struct {
+	URL         string `json:"url"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	Checksum    string `json:"checksum"`
+}
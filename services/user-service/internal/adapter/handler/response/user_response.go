@@ -1,16 +1,26 @@
 package response
 
+import "time"
+
 type SignInResponse struct {
-	AccessToken string  `json:"access_token"`
+	AccessToken string  `json:"access_token,omitempty"`
+	CSRFToken   string  `json:"csrf_token,omitempty"`
 	Role        string  `json:"role"`
 	ID          int64   `json:"id"`
 	Name        string  `json:"name"`
 	Email       string  `json:"email"`
+	Username    string  `json:"username"`
 	Phone       string  `json:"phone"`
 	Lat         float64 `json:"lat"`
 	Lng         float64 `json:"lng"`
 }
 
+type StepUpRequiredResponse struct {
+	StepUpRequired bool      `json:"step_up_required"`
+	ChallengeID    string    `json:"challenge_id"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
 type CreateUserAccountResponse struct {
 	ID    int64  `json:"id"`
 	Name  string `json:"name"`
@@ -18,17 +28,61 @@ type CreateUserAccountResponse struct {
 }
 
 type ProfileResponse struct {
-	ID      int64   `json:"id"`
-	Email   string  `json:"email"`
-	Role    string  `json:"role"`
-	Name    string  `json:"name"`
-	Phone   string  `json:"phone"`
-	Address string  `json:"address"`
-	Lat     float64 `json:"lat"`
-	Lng     float64 `json:"lng"`
-	Photo   string  `json:"photo"`
+	ID         int64                   `json:"id"`
+	Email      string                  `json:"email"`
+	Username   string                  `json:"username"`
+	Timezone   string                  `json:"timezone"`
+	Language   string                  `json:"language"`
+	Role       string                  `json:"role"`
+	Name       string                  `json:"name"`
+	Phone      string                  `json:"phone"`
+	Address    string                  `json:"address"`
+	Province   string                  `json:"province"`
+	City       string                  `json:"city"`
+	District   string                  `json:"district"`
+	PostalCode string                  `json:"postal_code"`
+	Lat        float64                 `json:"lat"`
+	Lng        float64                 `json:"lng"`
+	Photo      string                  `json:"photo"`
+	Version    int64                   `json:"version"`
+	Privacy    PrivacySettingsResponse `json:"privacy"`
+}
+
+type UsernameAvailabilityResponse struct {
+	Username  string `json:"username"`
+	Available bool   `json:"available"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+type UsernameResponse struct {
+	Username string `json:"username"`
+}
+
+type TimezoneResponse struct {
+	Timezone string `json:"timezone"`
+}
+
+type LanguageResponse struct {
+	Language string `json:"language"`
+}
+
+type PrivacySettingsResponse struct {
+	HidePhone   bool `json:"hide_phone"`
+	HideAddress bool `json:"hide_address"`
 }
 
 type ImageUploadResponse struct {
 	ImageURL string `json:"image_url"`
+	// OriginalURL is the upload as received, before rotation and cropping
+	OriginalURL string `json:"original_url,omitempty"`
+}
+
+type GeocodeResponse struct {
+	Address    string  `json:"address"`
+	Province   string  `json:"province"`
+	City       string  `json:"city"`
+	District   string  `json:"district"`
+	PostalCode string  `json:"postal_code"`
+	Lat        float64 `json:"lat"`
+	Lng        float64 `json:"lng"`
 }
@@ -0,0 +1,26 @@
+package response
+
+import "time"
+
+type VendorDocumentResponse struct {
+	ID           int64     `json:"id"`
+	DocumentType string    `json:"document_type"`
+	FileURL      string    `json:"file_url"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type VendorResponse struct {
+	ID              int64                    `json:"id"`
+	UserID          int64                    `json:"user_id"`
+	StoreName       string                   `json:"store_name"`
+	Description     string                   `json:"description"`
+	Phone           string                   `json:"phone"`
+	Address         string                   `json:"address"`
+	Lat             float64                  `json:"lat"`
+	Lng             float64                  `json:"lng"`
+	Status          string                   `json:"status"`
+	RejectionReason string                   `json:"rejection_reason,omitempty"`
+	ReviewedAt      *time.Time               `json:"reviewed_at,omitempty"`
+	Documents       []VendorDocumentResponse `json:"documents,omitempty"`
+	CreatedAt       time.Time                `json:"created_at"`
+}
@@ -0,0 +1,5 @@
+package response
+
+type VersionConflictResponse struct {
+	CurrentVersion int64 `json:"current_version"`
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"user-service/config"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+
+	"github.com/labstack/echo/v4"
+)
+
+// responsePolicy shapes customer payloads for the caller's role, on top of the
+// customer's own privacy settings. Hidden fields are blanked and listed in hidden_fields.
+type responsePolicy struct {
+	hidden entity.ResponsePolicyEntity
+}
+
+func newResponsePolicy(cfg *config.Config) responsePolicy {
+	return responsePolicy{hidden: entity.ResponsePolicyEntity(cfg.ResponsePolicy.Hidden())}
+}
+
+// customer applies the customer's privacy settings and the caller's policy
+func (p responsePolicy) customer(c echo.Context, customer entity.UserEntity) (entity.UserEntity, []string) {
+	customer, private := customer.MaskedFor(audienceFor(c))
+	customer, hidden := customer.WithoutFields(p.hidden.HiddenFields(callerRoles(c), entity.ResponsePayloadCustomer))
+	return customer, mergeHidden(private, hidden)
+}
+
+// callerRoles returns the signed-in user's role and the roles it inherits from. Partners are
+// looked up as entity.ResponsePolicyPartner; other services have no policy.
+func callerRoles(c echo.Context) []string {
+	if roles, ok := c.Get(middleware.UserRolesContextKey).([]string); ok {
+		return roles
+	}
+
+	principal, ok := middleware.GetPrincipal(c)
+	if !ok {
+		if role, ok := c.Get("user_role").(string); ok {
+			return []string{role}
+		}
+		return nil
+	}
+
+	switch principal.Type {
+	case entity.PrincipalTypeAPIKey:
+		return []string{entity.ResponsePolicyPartner}
+	case entity.PrincipalTypeUser:
+		return []string{principal.Role}
+	default:
+		return nil
+	}
+}
+
+// mergeHidden adds the fields of extra not already in hidden
+func mergeHidden(hidden, extra []string) []string {
+	for _, field := range extra {
+		seen := false
+		for _, h := range hidden {
+			if h == field {
+				seen = true
+				break
+			}
+		}
+		if !seen {
+			hidden = append(hidden, field)
+		}
+	}
+	return hidden
+}
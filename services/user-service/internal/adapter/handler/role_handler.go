@@ -18,6 +18,7 @@ type RoleHandlerInterface interface {
 	GetRoleByID(c echo.Context) error
 	CreateRole(c echo.Context) error
 	UpdateRole(c echo.Context) error
+	SetRoleParent(c echo.Context) error
 	DeleteRole(c echo.Context) error
 }
 
@@ -28,8 +29,9 @@ type RoleHandler struct {
 
 func (h *RoleHandler) GetAllRoles(c echo.Context) error {
 	search := c.QueryParam("search")
+	page, limit := pageQuery(c)
 
-	roles, err := h.roleService.GetAllRoles(c.Request().Context(), search)
+	roles, pagination, err := h.roleService.GetAllRoles(c.Request().Context(), search, page, limit)
 	if err != nil {
 		log.Error().Err(err).Str("search", search).Msg("[RoleHandler-GetAllRoles] Failed to get roles")
 		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
@@ -42,16 +44,15 @@ func (h *RoleHandler) GetAllRoles(c echo.Context) error {
 	var roleData []map[string]interface{}
 	for _, role := range roles {
 		roleData = append(roleData, map[string]interface{}{
-			"id":   role.ID,
-			"name": role.Name,
+			"id":        role.ID,
+			"name":      role.Name,
+			"parent_id": role.ParentID,
+			"version":   role.Version,
 		})
 	}
 
 	log.Info().Int("count", len(roles)).Str("search", search).Msg("[RoleHandler-GetAllRoles] Roles retrieved successfully")
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"message": "Roles retrieved successfully",
-		"data":    roleData,
-	})
+	return respondPage(c, "Roles retrieved successfully", roleData, pagination)
 }
 
 func (h *RoleHandler) GetRoleByID(c echo.Context) error {
@@ -95,11 +96,15 @@ func (h *RoleHandler) GetRoleByID(c echo.Context) error {
 
 	// Response data
 	roleData := map[string]interface{}{
-		"id":    role.ID,
-		"name":  role.Name,
-		"users": userData,
+		"id":        role.ID,
+		"name":      role.Name,
+		"parent_id": role.ParentID,
+		"version":   role.Version,
+		"users":     userData,
 	}
 
+	setETag(c, role.Version)
+
 	log.Info().Int64("role_id", id).Int("users_count", len(userData)).Msg("[RoleHandler-GetRoleByID] Role retrieved successfully")
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"message": "Role retrieved successfully",
@@ -119,7 +124,7 @@ func (h *RoleHandler) CreateRole(c echo.Context) error {
 	}
 
 	// Validate request
-	if err := h.validator.Validate(&req); err != nil {
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
 		log.Error().Err(err).Msg("[RoleHandler-CreateRole] Validation failed")
 		return c.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
 			"message": err.Error(),
@@ -170,7 +175,7 @@ func (h *RoleHandler) UpdateRole(c echo.Context) error {
 	}
 
 	// Bind request
-	var req request.CreateRoleRequest
+	var req request.UpdateRoleRequest
 	if err := c.Bind(&req); err != nil {
 		log.Warn().Err(err).Int64("role_id", id).Msg("[RoleHandler-UpdateRole] Failed to bind request")
 		return c.JSON(http.StatusBadRequest, map[string]interface{}{
@@ -180,7 +185,7 @@ func (h *RoleHandler) UpdateRole(c echo.Context) error {
 	}
 
 	// Validate request
-	if err := h.validator.Validate(&req); err != nil {
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
 		log.Error().Err(err).Int64("role_id", id).Msg("[RoleHandler-UpdateRole] Validation failed")
 		return c.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
 			"message": err.Error(),
@@ -188,11 +193,24 @@ func (h *RoleHandler) UpdateRole(c echo.Context) error {
 		})
 	}
 
+	version, err := expectedVersion(c, req.Version)
+	if err != nil {
+		_, result := respondVersionError(c, err)
+		return result
+	}
+	if version == 0 {
+		return versionRequired(c)
+	}
+
 	// Update role
-	role, err := h.roleService.UpdateRole(c.Request().Context(), id, req.Name)
+	role, err := h.roleService.UpdateRole(c.Request().Context(), id, req.Name, version)
 	if err != nil {
 		log.Error().Err(err).Int64("role_id", id).Str("role_name", req.Name).Msg("[RoleHandler-UpdateRole] Failed to update role")
 
+		if handled, result := respondVersionError(c, err); handled {
+			return result
+		}
+
 		// Check for specific errors
 		if err.Error() == "role not found" {
 			return c.JSON(http.StatusNotFound, map[string]interface{}{
@@ -215,12 +233,93 @@ func (h *RoleHandler) UpdateRole(c echo.Context) error {
 	}
 
 	log.Info().Int64("role_id", id).Str("role_name", role.Name).Msg("[RoleHandler-UpdateRole] Role updated successfully")
+	setETag(c, role.Version)
 	return c.JSON(http.StatusOK, map[string]interface{}{
 		"message": "Role updated successfully",
 		"data":    nil,
 	})
 }
 
+func (h *RoleHandler) SetRoleParent(c echo.Context) error {
+	idParam := c.Param("id")
+
+	var id int64
+	if _, err := fmt.Sscanf(idParam, "%d", &id); err != nil {
+		log.Warn().Str("id_param", idParam).Msg("[RoleHandler-SetRoleParent] Invalid ID format")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Invalid role ID format",
+			"data":    nil,
+		})
+	}
+
+	var req request.SetRoleParentRequest
+	if err := c.Bind(&req); err != nil {
+		log.Warn().Err(err).Int64("role_id", id).Msg("[RoleHandler-SetRoleParent] Failed to bind request")
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Invalid request format",
+			"data":    nil,
+		})
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		log.Warn().Err(err).Int64("role_id", id).Msg("[RoleHandler-SetRoleParent] Validation failed")
+		return c.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+			"message": err.Error(),
+			"data":    nil,
+		})
+	}
+
+	version, err := expectedVersion(c, req.Version)
+	if err != nil {
+		_, result := respondVersionError(c, err)
+		return result
+	}
+	if version == 0 {
+		return versionRequired(c)
+	}
+
+	role, err := h.roleService.SetRoleParent(c.Request().Context(), id, req.ParentID, version)
+	if err != nil {
+		log.Error().Err(err).Int64("role_id", id).Msg("[RoleHandler-SetRoleParent] Failed to set role parent")
+
+		if handled, result := respondVersionError(c, err); handled {
+			return result
+		}
+
+		switch err.Error() {
+		case "role not found":
+			return c.JSON(http.StatusNotFound, map[string]interface{}{
+				"message": "Role not found",
+				"data":    nil,
+			})
+		case "parent role not found", "role cannot inherit from itself", "role hierarchy cannot contain a cycle":
+			return c.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+				"message": err.Error(),
+				"data":    nil,
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Failed to update role parent",
+			"data":    nil,
+		})
+	}
+
+	setETag(c, role.Version)
+	log.Info().Int64("role_id", id).Msg("[RoleHandler-SetRoleParent] Role parent updated successfully")
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Role parent updated successfully",
+		"data": map[string]interface{}{
+			"id":        role.ID,
+			"name":      role.Name,
+			"parent_id": role.ParentID,
+			"version":   role.Version,
+			// inherits lists every ancestor, nearest first
+			"inherits": h.roleService.EffectiveRoles(c.Request().Context(), role.Name)[1:],
+		},
+	})
+}
+
 func (h *RoleHandler) DeleteRole(c echo.Context) error {
 	// Get role ID from URL parameter
 	idParam := c.Param("id")
@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type SavedViewHandlerInterface interface {
+	GetViews(c echo.Context) error
+	CreateView(c echo.Context) error
+	UpdateView(c echo.Context) error
+	DeleteView(c echo.Context) error
+}
+
+type SavedViewHandler struct {
+	viewService port.SavedViewServiceInterface
+	validator   *myvalidator.Validator
+}
+
+// GetViews lists the caller's views and the ones shared by other admins; ?list= narrows it to one list
+func (h *SavedViewHandler) GetViews(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	adminID, _ := c.Get("user_id").(int64)
+
+	views, err := h.viewService.GetViews(c.Request().Context(), adminID, c.QueryParam("list"))
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve saved views")
+	}
+
+	viewData := make([]response.SavedViewResponse, 0, len(views))
+	for i := range views {
+		viewData = append(viewData, toSavedViewResponse(&views[i], adminID))
+	}
+
+	resp.Message = "Saved views retrieved successfully"
+	resp.Data = viewData
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *SavedViewHandler) CreateView(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	adminID, _ := c.Get("user_id").(int64)
+
+	req, status, message := h.bindView(c)
+	if req == nil {
+		resp.Message = message
+		return c.JSON(status, resp)
+	}
+
+	view, err := h.viewService.CreateView(c.Request().Context(), &entity.SavedViewEntity{
+		OwnerID: adminID,
+		List:    req.List,
+		Name:    req.Name,
+		Filters: req.Filters,
+		Shared:  req.Shared,
+	})
+	if err != nil {
+		return h.handleError(c, err, "Failed to create saved view")
+	}
+
+	resp.Message = "Saved view created successfully"
+	resp.Data = toSavedViewResponse(view, adminID)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *SavedViewHandler) UpdateView(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	adminID, _ := c.Get("user_id").(int64)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid saved view ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	req, status, message := h.bindView(c)
+	if req == nil {
+		resp.Message = message
+		return c.JSON(status, resp)
+	}
+
+	view, err := h.viewService.UpdateView(c.Request().Context(), adminID, id, &entity.SavedViewEntity{
+		Name:    req.Name,
+		Filters: req.Filters,
+		Shared:  req.Shared,
+	})
+	if err != nil {
+		return h.handleError(c, err, "Failed to update saved view")
+	}
+
+	resp.Message = "Saved view updated successfully"
+	resp.Data = toSavedViewResponse(view, adminID)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *SavedViewHandler) DeleteView(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	adminID, _ := c.Get("user_id").(int64)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid saved view ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.viewService.DeleteView(c.Request().Context(), adminID, id); err != nil {
+		return h.handleError(c, err, "Failed to delete saved view")
+	}
+
+	resp.Message = "Saved view deleted successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+// bindView returns the request, or nil with the status and message to respond with
+func (h *SavedViewHandler) bindView(c echo.Context) (*request.SavedViewRequest, int, string) {
+	req := request.SavedViewRequest{}
+
+	if err := c.Bind(&req); err != nil {
+		log.Warn().Err(err).Msg("[SavedViewHandler-bindView] Failed to bind request")
+		return nil, http.StatusBadRequest, "Invalid request format"
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		log.Warn().Err(err).Msg("[SavedViewHandler-bindView] Validation failed")
+		return nil, http.StatusUnprocessableEntity, err.Error()
+	}
+
+	return &req, http.StatusOK, ""
+}
+
+func (h *SavedViewHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Msg("[SavedViewHandler] Request failed")
+
+	switch {
+	case err.Error() == "saved view not found":
+		resp.Message = "Saved view not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case err.Error() == "saved view already exists":
+		resp.Message = "You already have a saved view with this name for this list"
+		return c.JSON(http.StatusConflict, resp)
+	case strings.HasPrefix(err.Error(), "saved view "):
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toSavedViewResponse(view *entity.SavedViewEntity, adminID int64) response.SavedViewResponse {
+	filters := view.Filters
+	if filters == nil {
+		filters = map[string]string{}
+	}
+
+	query := url.Values{}
+	for key, value := range filters {
+		query.Set(key, value)
+	}
+
+	return response.SavedViewResponse{
+		ID:        view.ID,
+		List:      view.List,
+		Name:      view.Name,
+		Filters:   filters,
+		Query:     query.Encode(),
+		Shared:    view.Shared,
+		OwnerID:   view.OwnerID,
+		Owned:     view.OwnerID == adminID,
+		CreatedAt: view.CreatedAt,
+		UpdatedAt: view.UpdatedAt,
+	}
+}
+
+func NewSavedViewHandler(viewService port.SavedViewServiceInterface) SavedViewHandlerInterface {
+	return &SavedViewHandler{
+		viewService: viewService,
+		validator:   myvalidator.NewValidator(),
+	}
+}
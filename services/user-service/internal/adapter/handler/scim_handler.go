@@ -0,0 +1,422 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"user-service/config"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// SCIMHandlerInterface serves SCIM 2.0 (RFC 7644) under /scim/v2 for identity providers.
+// Users are SCIM-managed accounts; Groups are the roles listed in SCIM_GROUPS.
+type SCIMHandlerInterface interface {
+	GetServiceProviderConfig(c echo.Context) error
+	GetResourceTypes(c echo.Context) error
+	GetUsers(c echo.Context) error
+	GetUser(c echo.Context) error
+	CreateUser(c echo.Context) error
+	ReplaceUser(c echo.Context) error
+	PatchUser(c echo.Context) error
+	DeleteUser(c echo.Context) error
+	GetGroups(c echo.Context) error
+	GetGroup(c echo.Context) error
+	ReplaceGroup(c echo.Context) error
+	PatchGroup(c echo.Context) error
+}
+
+type SCIMHandler struct {
+	scimService port.SCIMServiceInterface
+	pageSize    int
+}
+
+func (h *SCIMHandler) GetServiceProviderConfig(c echo.Context) error {
+	return scimJSON(c, http.StatusOK, map[string]interface{}{
+		"schemas":        []string{response.SCIMSchemaServiceProviderConfig},
+		"patch":          map[string]bool{"supported": true},
+		"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         map[string]interface{}{"supported": true, "maxResults": h.pageSize},
+		"changePassword": map[string]bool{"supported": false},
+		"sort":           map[string]bool{"supported": false},
+		"etag":           map[string]bool{"supported": false},
+		"authenticationSchemes": []map[string]interface{}{{
+			"type":        "oauthbearertoken",
+			"name":        "API key",
+			"description": "An API key granted the scim:provision scope, sent as a bearer token",
+			"primary":     true,
+		}},
+	})
+}
+
+func (h *SCIMHandler) GetResourceTypes(c echo.Context) error {
+	resourceTypes := []map[string]interface{}{
+		{"schemas": []string{response.SCIMSchemaResourceType}, "id": "User", "name": "User", "endpoint": "/Users", "schema": response.SCIMSchemaUser},
+		{"schemas": []string{response.SCIMSchemaResourceType}, "id": "Group", "name": "Group", "endpoint": "/Groups", "schema": response.SCIMSchemaGroup},
+	}
+	return scimJSON(c, http.StatusOK, response.SCIMListResponse{
+		Schemas:      []string{response.SCIMSchemaListResponse},
+		TotalResults: int64(len(resourceTypes)),
+		StartIndex:   1,
+		ItemsPerPage: len(resourceTypes),
+		Resources:    resourceTypes,
+	})
+}
+
+func (h *SCIMHandler) GetUsers(c echo.Context) error {
+	startIndex, count := scimPage(c)
+
+	users, total, err := h.scimService.ListUsers(c.Request().Context(), c.QueryParam("filter"), startIndex, count)
+	if err != nil {
+		return h.handleError(c, err, "Failed to list users")
+	}
+
+	resources := make([]response.SCIMUserResponse, 0, len(users))
+	for i := range users {
+		resources = append(resources, toSCIMUserResponse(c, &users[i]))
+	}
+	return scimJSON(c, http.StatusOK, response.SCIMListResponse{
+		Schemas:      []string{response.SCIMSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   max(startIndex, 1),
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+func (h *SCIMHandler) GetUser(c echo.Context) error {
+	id, ok := scimID(c)
+	if !ok {
+		return middleware.SCIMError(c, http.StatusNotFound, "", "User not found")
+	}
+
+	user, err := h.scimService.GetUser(c.Request().Context(), id)
+	if err != nil {
+		return h.handleError(c, err, "Failed to get user")
+	}
+	return scimJSON(c, http.StatusOK, toSCIMUserResponse(c, user))
+}
+
+func (h *SCIMHandler) CreateUser(c echo.Context) error {
+	var req request.SCIMUserRequest
+	if err := decodeSCIM(c, &req); err != nil {
+		return middleware.SCIMError(c, http.StatusBadRequest, "invalidSyntax", "Request body is not valid JSON")
+	}
+
+	user, err := h.scimService.CreateUser(c.Request().Context(), fromSCIMUserRequest(req))
+	if err != nil {
+		return h.handleError(c, err, "Failed to create user")
+	}
+	return scimJSON(c, http.StatusCreated, toSCIMUserResponse(c, user))
+}
+
+func (h *SCIMHandler) ReplaceUser(c echo.Context) error {
+	id, ok := scimID(c)
+	if !ok {
+		return middleware.SCIMError(c, http.StatusNotFound, "", "User not found")
+	}
+
+	var req request.SCIMUserRequest
+	if err := decodeSCIM(c, &req); err != nil {
+		return middleware.SCIMError(c, http.StatusBadRequest, "invalidSyntax", "Request body is not valid JSON")
+	}
+
+	user, err := h.scimService.ReplaceUser(c.Request().Context(), id, fromSCIMUserRequest(req))
+	if err != nil {
+		return h.handleError(c, err, "Failed to replace user")
+	}
+	return scimJSON(c, http.StatusOK, toSCIMUserResponse(c, user))
+}
+
+func (h *SCIMHandler) PatchUser(c echo.Context) error {
+	id, ok := scimID(c)
+	if !ok {
+		return middleware.SCIMError(c, http.StatusNotFound, "", "User not found")
+	}
+
+	ops, err := decodeSCIMPatch(c)
+	if err != nil {
+		return middleware.SCIMError(c, http.StatusBadRequest, "invalidSyntax", "Request body is not a valid PatchOp")
+	}
+
+	user, err := h.scimService.PatchUser(c.Request().Context(), id, ops)
+	if err != nil {
+		return h.handleError(c, err, "Failed to patch user")
+	}
+	return scimJSON(c, http.StatusOK, toSCIMUserResponse(c, user))
+}
+
+func (h *SCIMHandler) DeleteUser(c echo.Context) error {
+	id, ok := scimID(c)
+	if !ok {
+		return middleware.SCIMError(c, http.StatusNotFound, "", "User not found")
+	}
+
+	if err := h.scimService.DeleteUser(c.Request().Context(), id); err != nil {
+		return h.handleError(c, err, "Failed to delete user")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *SCIMHandler) GetGroups(c echo.Context) error {
+	startIndex, count := scimPage(c)
+	withMembers := !strings.Contains(strings.ToLower(c.QueryParam("excludedAttributes")), "members")
+
+	groups, total, err := h.scimService.ListGroups(c.Request().Context(), c.QueryParam("filter"), startIndex, count, withMembers)
+	if err != nil {
+		return h.handleError(c, err, "Failed to list groups")
+	}
+
+	resources := make([]response.SCIMGroupResponse, 0, len(groups))
+	for i := range groups {
+		resources = append(resources, toSCIMGroupResponse(c, &groups[i]))
+	}
+	return scimJSON(c, http.StatusOK, response.SCIMListResponse{
+		Schemas:      []string{response.SCIMSchemaListResponse},
+		TotalResults: total,
+		StartIndex:   max(startIndex, 1),
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	})
+}
+
+func (h *SCIMHandler) GetGroup(c echo.Context) error {
+	id, ok := scimID(c)
+	if !ok {
+		return middleware.SCIMError(c, http.StatusNotFound, "", "Group not found")
+	}
+
+	group, err := h.scimService.GetGroup(c.Request().Context(), id)
+	if err != nil {
+		return h.handleError(c, err, "Failed to get group")
+	}
+	return scimJSON(c, http.StatusOK, toSCIMGroupResponse(c, group))
+}
+
+func (h *SCIMHandler) ReplaceGroup(c echo.Context) error {
+	id, ok := scimID(c)
+	if !ok {
+		return middleware.SCIMError(c, http.StatusNotFound, "", "Group not found")
+	}
+
+	var req request.SCIMGroupRequest
+	if err := decodeSCIM(c, &req); err != nil {
+		return middleware.SCIMError(c, http.StatusBadRequest, "invalidSyntax", "Request body is not valid JSON")
+	}
+
+	memberIDs := make([]int64, 0, len(req.Members))
+	for _, member := range req.Members {
+		memberID, err := strconv.ParseInt(member.Value, 10, 64)
+		if err != nil {
+			return middleware.SCIMError(c, http.StatusBadRequest, "invalidValue", fmt.Sprintf("Member %s is not a valid id", member.Value))
+		}
+		memberIDs = append(memberIDs, memberID)
+	}
+
+	ctx := c.Request().Context()
+	if req.DisplayName != "" {
+		group, err := h.scimService.GetGroup(ctx, id)
+		if err != nil {
+			return h.handleError(c, err, "Failed to replace group")
+		}
+		if req.DisplayName != group.Name {
+			return middleware.SCIMError(c, http.StatusBadRequest, "mutability", "Group displayName is the role's name and cannot be changed")
+		}
+	}
+
+	group, err := h.scimService.ReplaceGroupMembers(ctx, id, memberIDs)
+	if err != nil {
+		return h.handleError(c, err, "Failed to replace group")
+	}
+	return scimJSON(c, http.StatusOK, toSCIMGroupResponse(c, group))
+}
+
+func (h *SCIMHandler) PatchGroup(c echo.Context) error {
+	id, ok := scimID(c)
+	if !ok {
+		return middleware.SCIMError(c, http.StatusNotFound, "", "Group not found")
+	}
+
+	ops, err := decodeSCIMPatch(c)
+	if err != nil {
+		return middleware.SCIMError(c, http.StatusBadRequest, "invalidSyntax", "Request body is not a valid PatchOp")
+	}
+
+	group, err := h.scimService.PatchGroup(c.Request().Context(), id, ops)
+	if err != nil {
+		return h.handleError(c, err, "Failed to patch group")
+	}
+	return scimJSON(c, http.StatusOK, toSCIMGroupResponse(c, group))
+}
+
+func (h *SCIMHandler) handleError(c echo.Context, err error, fallback string) error {
+	switch {
+	case err.Error() == "scim user not found":
+		return middleware.SCIMError(c, http.StatusNotFound, "", "User not found")
+	case err.Error() == "scim group not found":
+		return middleware.SCIMError(c, http.StatusNotFound, "", "Group not found")
+	case err.Error() == "email already exists", err.Error() == "scim user already exists":
+		return middleware.SCIMError(c, http.StatusConflict, "uniqueness", "An account with this userName already exists")
+	case err.Error() == "scim filter is not supported":
+		return middleware.SCIMError(c, http.StatusBadRequest, "invalidFilter", `Only filters of the form attribute eq "value" on userName, externalId or displayName are supported`)
+	case err.Error() == "scim group displayName cannot be changed":
+		return middleware.SCIMError(c, http.StatusBadRequest, "mutability", "Group displayName is the role's name and cannot be changed")
+	case strings.HasPrefix(err.Error(), "scim path "):
+		return middleware.SCIMError(c, http.StatusBadRequest, "invalidPath", err.Error())
+	case strings.HasPrefix(err.Error(), "scim "):
+		return middleware.SCIMError(c, http.StatusBadRequest, "invalidValue", err.Error())
+	default:
+		log.Error().Err(err).Str("path", c.Path()).Msg("[SCIMHandler] Request failed")
+		return middleware.SCIMError(c, http.StatusInternalServerError, "", fallback)
+	}
+}
+
+func scimJSON(c echo.Context, status int, body interface{}) error {
+	c.Response().Header().Set(echo.HeaderContentType, middleware.SCIMContentType)
+	return c.JSON(status, body)
+}
+
+// decodeSCIM reads the body whatever its content type; SCIM clients send application/scim+json,
+// which echo's binder does not take
+func decodeSCIM(c echo.Context, v interface{}) error {
+	return json.NewDecoder(c.Request().Body).Decode(v)
+}
+
+func decodeSCIMPatch(c echo.Context) ([]entity.SCIMPatchOperation, error) {
+	var req request.SCIMPatchRequest
+	if err := decodeSCIM(c, &req); err != nil {
+		return nil, err
+	}
+
+	ops := make([]entity.SCIMPatchOperation, 0, len(req.Operations))
+	for _, op := range req.Operations {
+		ops = append(ops, entity.SCIMPatchOperation{
+			Op:    strings.ToLower(op.Op),
+			Path:  op.Path,
+			Value: op.Value,
+		})
+	}
+	return ops, nil
+}
+
+// scimID reads the resource id; ids are opaque to clients, so a malformed one is just unknown
+func scimID(c echo.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	return id, err == nil && id > 0
+}
+
+// scimPage reads startIndex and count; a missing count is passed on as -1 for the default size
+func scimPage(c echo.Context) (int, int) {
+	startIndex, err := strconv.Atoi(c.QueryParam("startIndex"))
+	if err != nil {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(c.QueryParam("count"))
+	if err != nil {
+		count = -1
+	}
+	return startIndex, count
+}
+
+func scimBaseURL(c echo.Context) string {
+	return c.Scheme() + "://" + c.Request().Host + "/scim/v2"
+}
+
+func fromSCIMUserRequest(req request.SCIMUserRequest) entity.SCIMUserEntity {
+	name := strings.TrimSpace(req.Name.Formatted)
+	if name == "" {
+		name = strings.TrimSpace(req.Name.GivenName + " " + req.Name.FamilyName)
+	}
+	if name == "" {
+		name = req.DisplayName
+	}
+	if name == "" {
+		name, _, _ = strings.Cut(req.UserName, "@")
+	}
+
+	phone := ""
+	for i, number := range req.PhoneNumbers {
+		if i == 0 || number.Primary {
+			phone = number.Value
+		}
+	}
+
+	return entity.SCIMUserEntity{
+		ExternalID: req.ExternalID,
+		UserName:   req.UserName,
+		Name:       name,
+		Phone:      phone,
+		Active:     req.Active == nil || *req.Active,
+	}
+}
+
+func toSCIMUserResponse(c echo.Context, user *entity.SCIMUserEntity) response.SCIMUserResponse {
+	base := scimBaseURL(c)
+	resp := response.SCIMUserResponse{
+		Schemas:     []string{response.SCIMSchemaUser},
+		ID:          strconv.FormatInt(user.ID, 10),
+		ExternalID:  user.ExternalID,
+		UserName:    user.UserName,
+		Name:        response.SCIMNameResponse{Formatted: user.Name},
+		DisplayName: user.Name,
+		Emails:      []response.SCIMMultiValuedAttr{{Value: user.UserName, Type: "work", Primary: true}},
+		Active:      user.Active,
+		Meta: response.SCIMMeta{
+			ResourceType: "User",
+			Created:      user.CreatedAt,
+			LastModified: user.UpdatedAt,
+			Location:     fmt.Sprintf("%s/Users/%d", base, user.ID),
+			Version:      fmt.Sprintf(`W/"%d"`, user.Version),
+		},
+	}
+	if user.Phone != "" {
+		resp.PhoneNumbers = []response.SCIMMultiValuedAttr{{Value: user.Phone, Type: "work", Primary: true}}
+	}
+	if user.RoleID != 0 {
+		resp.Groups = []response.SCIMReference{{
+			Value:   strconv.FormatInt(user.RoleID, 10),
+			Ref:     fmt.Sprintf("%s/Groups/%d", base, user.RoleID),
+			Display: user.RoleName,
+		}}
+	}
+	return resp
+}
+
+func toSCIMGroupResponse(c echo.Context, group *entity.SCIMGroupEntity) response.SCIMGroupResponse {
+	base := scimBaseURL(c)
+	resp := response.SCIMGroupResponse{
+		Schemas:     []string{response.SCIMSchemaGroup},
+		ID:          strconv.FormatInt(group.ID, 10),
+		DisplayName: group.Name,
+		Meta: response.SCIMMeta{
+			ResourceType: "Group",
+			Created:      group.CreatedAt,
+			LastModified: group.UpdatedAt,
+			Location:     fmt.Sprintf("%s/Groups/%d", base, group.ID),
+			Version:      fmt.Sprintf(`W/"%d"`, group.Version),
+		},
+	}
+	for _, member := range group.Members {
+		resp.Members = append(resp.Members, response.SCIMReference{
+			Value:   strconv.FormatInt(member.UserID, 10),
+			Ref:     fmt.Sprintf("%s/Users/%d", base, member.UserID),
+			Display: member.UserName,
+		})
+	}
+	return resp
+}
+
+func NewSCIMHandler(scimService port.SCIMServiceInterface, cfg *config.Config) SCIMHandlerInterface {
+	return &SCIMHandler{
+		scimService: scimService,
+		pageSize:    cfg.SCIM.PageSize(),
+	}
+}
@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type SegmentHandlerInterface interface {
+	GetSegments(c echo.Context) error
+	GetSegment(c echo.Context) error
+	CreateSegment(c echo.Context) error
+	RefreshSegment(c echo.Context) error
+	DeleteSegment(c echo.Context) error
+	LaunchCampaign(c echo.Context) error
+	RecordOrder(c echo.Context) error
+}
+
+type SegmentHandler struct {
+	segmentService port.SegmentServiceInterface
+	validator      *myvalidator.Validator
+}
+
+func (h *SegmentHandler) GetSegments(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	segments, err := h.segmentService.GetSegments(c.Request().Context())
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve segments")
+	}
+
+	segmentData := make([]response.SegmentResponse, 0, len(segments))
+	for i := range segments {
+		segmentData = append(segmentData, toSegmentResponse(&segments[i]))
+	}
+
+	resp.Message = "Segments retrieved successfully"
+	resp.Data = segmentData
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *SegmentHandler) GetSegment(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid segment ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	segment, err := h.segmentService.GetSegmentByID(c.Request().Context(), id)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve segment")
+	}
+
+	resp.Message = "Segment retrieved successfully"
+	resp.Data = toSegmentResponse(segment)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateSegment materializes the members in the same request, so the response has the count
+func (h *SegmentHandler) CreateSegment(c echo.Context) error {
+	var (
+		req  = request.SegmentRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	createdBy, _ := c.Get("user_id").(int64)
+	segment, err := h.segmentService.CreateSegment(c.Request().Context(), &entity.SegmentEntity{
+		Name: req.Name,
+		Filter: entity.SegmentFilterEntity{
+			City:         req.City,
+			SignedUpFrom: req.SignedUpFrom,
+			SignedUpTo:   req.SignedUpTo,
+			MinOrders:    req.MinOrders,
+			MaxOrders:    req.MaxOrders,
+		},
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		return h.handleError(c, err, "Failed to create segment")
+	}
+
+	resp.Message = "Segment created successfully"
+	resp.Data = toSegmentResponse(segment)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *SegmentHandler) RefreshSegment(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid segment ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	segment, err := h.segmentService.RefreshSegment(c.Request().Context(), id)
+	if err != nil {
+		return h.handleError(c, err, "Failed to refresh segment")
+	}
+
+	resp.Message = "Segment refreshed successfully"
+	resp.Data = toSegmentResponse(segment)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *SegmentHandler) DeleteSegment(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid segment ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.segmentService.DeleteSegment(c.Request().Context(), id); err != nil {
+		return h.handleError(c, err, "Failed to delete segment")
+	}
+
+	resp.Message = "Segment deleted successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+// LaunchCampaign answers 202 once the batches are queued; sending happens in the job worker
+func (h *SegmentHandler) LaunchCampaign(c echo.Context) error {
+	var (
+		req  = request.CampaignRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid segment ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	createdBy, _ := c.Get("user_id").(int64)
+	campaign, err := h.segmentService.LaunchCampaign(c.Request().Context(), id, &entity.CampaignEntity{
+		Name:      req.Name,
+		Subject:   req.Subject,
+		Body:      req.Body,
+		Data:      req.Data,
+		CreatedBy: createdBy,
+	})
+	if err != nil {
+		return h.handleError(c, err, "Failed to launch campaign")
+	}
+
+	resp.Message = "Campaign launched successfully"
+	resp.Data = response.CampaignResponse{
+		ID:         campaign.ID,
+		SegmentID:  campaign.SegmentID,
+		Name:       campaign.Name,
+		Recipients: campaign.Recipients,
+		Batches:    campaign.Batches,
+		CreatedAt:  campaign.CreatedAt,
+	}
+	return c.JSON(http.StatusAccepted, resp)
+}
+
+// RecordOrder is called by the order service for every order, so segments can filter on order count
+func (h *SegmentHandler) RecordOrder(c echo.Context) error {
+	var (
+		req  = request.OrderRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid user ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	orderedAt := time.Time{}
+	if req.OrderedAt != nil {
+		orderedAt = *req.OrderedAt
+	}
+
+	if err := h.segmentService.RecordOrder(c.Request().Context(), userID, req.OrderID, orderedAt); err != nil {
+		return h.handleError(c, err, "Failed to record order")
+	}
+
+	resp.Message = "Order recorded successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *SegmentHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Msg("[SegmentHandler] Request failed")
+
+	switch {
+	case err.Error() == "segment not found":
+		resp.Message = "Segment not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case err.Error() == "user not found":
+		resp.Message = "User not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case err.Error() == "segment already exists":
+		resp.Message = "Segment with this name already exists"
+		return c.JSON(http.StatusConflict, resp)
+	case err.Error() == "segment has no members":
+		resp.Message = "Segment has no members, refresh it or change the filter"
+		return c.JSON(http.StatusConflict, resp)
+	case strings.HasPrefix(err.Error(), "segment "),
+		strings.HasPrefix(err.Error(), "campaign "),
+		err.Error() == "invalid user id",
+		err.Error() == "order id is required":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toSegmentResponse(segment *entity.SegmentEntity) response.SegmentResponse {
+	return response.SegmentResponse{
+		ID:             segment.ID,
+		Name:           segment.Name,
+		City:           segment.Filter.City,
+		SignedUpFrom:   segment.Filter.SignedUpFrom,
+		SignedUpTo:     segment.Filter.SignedUpTo,
+		MinOrders:      segment.Filter.MinOrders,
+		MaxOrders:      segment.Filter.MaxOrders,
+		MemberCount:    segment.MemberCount,
+		MaterializedAt: segment.MaterializedAt,
+		CreatedAt:      segment.CreatedAt,
+	}
+}
+
+func NewSegmentHandler(segmentService port.SegmentServiceInterface) SegmentHandlerInterface {
+	return &SegmentHandler{
+		segmentService: segmentService,
+		validator:      myvalidator.NewValidator(),
+	}
+}
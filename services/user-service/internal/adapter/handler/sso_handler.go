@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"time"
+	"user-service/config"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// SSOHandlerInterface signs staff in to the admin panel through OpenID Connect
+type SSOHandlerInterface interface {
+	Login(c echo.Context) error
+	Callback(c echo.Context) error
+}
+
+type SSOHandler struct {
+	ssoService port.SSOServiceInterface
+	validator  *myvalidator.Validator
+	config     *config.Config
+}
+
+// Login starts a sign-in and returns the provider URL to send the browser to
+func (h *SSOHandler) Login(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	rememberMe, _ := strconv.ParseBool(c.QueryParam("remember_me"))
+
+	authURL, state, err := h.ssoService.StartLogin(c.Request().Context(), rememberMe)
+	if err != nil {
+		log.Error().Err(err).Msg("[SSOHandler-Login] Failed to start sign-in")
+		if err.Error() == "sso provider unavailable" {
+			resp.Message = "Identity provider is unavailable, please try again later"
+			return c.JSON(http.StatusServiceUnavailable, resp)
+		}
+		resp.Message = "Internal server error"
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+
+	middleware.SetSSOStateCookie(c, h.config.Security, state.ID, time.Until(state.ExpiresAt))
+
+	resp.Message = "Continue at the identity provider"
+	resp.Data = response.SSOLoginResponse{
+		AuthorizationURL: authURL,
+		ExpiresAt:        state.ExpiresAt,
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// Callback finishes the sign-in with the code and state the provider sent to the admin panel
+func (h *SSOHandler) Callback(c echo.Context) error {
+	var (
+		req  = request.SSOCallbackRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		log.Error().Err(err).Msg("[SSOHandler-Callback] Failed to bind request")
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	// The state must be the one this browser started, or a victim could be signed in to the
+	// attacker's account
+	cookie, err := c.Cookie(middleware.SSOStateCookieName)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(req.State)) != 1 {
+		log.Warn().Str("ip", c.RealIP()).Msg("[SSOHandler-Callback] State does not match this browser")
+		resp.Message = "Sign-in session not found, please start again"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+	middleware.ClearSSOStateCookie(c, h.config.Security)
+
+	client := entity.ClientEntity{
+		UserAgent: c.Request().UserAgent(),
+		IPAddress: c.RealIP(),
+		DeviceID:  c.Request().Header.Get(middleware.DeviceIDHeader),
+	}
+
+	user, token, err := h.ssoService.CompleteLogin(c.Request().Context(), req.State, req.Code, client)
+	if err != nil {
+		log.Warn().Err(err).Msg("[SSOHandler-Callback] Sign-in failed")
+
+		switch err.Error() {
+		case "sso state not found":
+			resp.Message = "Sign-in session expired, please start again"
+			return c.JSON(http.StatusBadRequest, resp)
+		case "sso code exchange failed":
+			resp.Message = "Identity provider sign-in could not be verified"
+			return c.JSON(http.StatusUnauthorized, resp)
+		case "sso email is not verified":
+			resp.Message = "Identity provider did not confirm your email"
+			return c.JSON(http.StatusForbidden, resp)
+		case "sso user has no staff role":
+			resp.Message = "Your account is not in a group with access to the admin panel"
+			return c.JSON(http.StatusForbidden, resp)
+		case "sso account not provisioned":
+			resp.Message = "No staff account exists for you yet, please contact an administrator"
+			return c.JSON(http.StatusForbidden, resp)
+		case "sso email belongs to a non-staff account", "sso subject already linked", "email already exists":
+			resp.Message = "Your email is already used by another account, please contact an administrator"
+			return c.JSON(http.StatusConflict, resp)
+		case "account is locked":
+			resp.Message = "Account is locked pending review. Please contact support."
+			return c.JSON(http.StatusForbidden, resp)
+		case "failed to generate token":
+			resp.Message = "Authentication failed"
+			return c.JSON(http.StatusInternalServerError, resp)
+		default:
+			resp.Message = "Internal server error"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	return respondSignedIn(c, h.config, user, token)
+}
+
+func NewSSOHandler(ssoService port.SSOServiceInterface, cfg *config.Config) SSOHandlerInterface {
+	return &SSOHandler{
+		ssoService: ssoService,
+		validator:  myvalidator.NewValidator(),
+		config:     cfg,
+	}
+}
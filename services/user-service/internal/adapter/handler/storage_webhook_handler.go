@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// maxStorageWebhookBytes bounds the body read before the signature is checked
+const maxStorageWebhookBytes = 1 << 20
+
+type StorageWebhookHandlerInterface interface {
+	ReceiveStorageEvent(c echo.Context) error
+}
+
+type StorageWebhookHandler struct {
+	storageEventService port.StorageEventServiceInterface
+	secret              string
+}
+
+// ReceiveStorageEvent accepts Supabase database webhooks for storage.objects. The sender signs
+// the raw body the same way this service signs its own webhooks (utils.SignWebhookPayload).
+func (h *StorageWebhookHandler) ReceiveStorageEvent(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	if h.secret == "" {
+		log.Warn().Msg("[StorageWebhookHandler-ReceiveStorageEvent] Storage webhook called but SUPABASE_WEBHOOK_SECRET is not set")
+		resp.Message = "Storage webhook is not configured"
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, maxStorageWebhookBytes))
+	if err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := utils.VerifyWebhookSignature(h.secret, c.Request().Header.Get(utils.WebhookSignatureHeader), body, time.Now()); err != nil {
+		log.Warn().Str("ip", c.RealIP()).Msg("[StorageWebhookHandler-ReceiveStorageEvent] Invalid webhook signature")
+		resp.Message = "Invalid webhook signature"
+		return c.JSON(http.StatusUnauthorized, resp)
+	}
+
+	var req request.SupabaseStorageWebhookRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if req.Schema != "storage" || req.Table != "objects" {
+		resp.Message = "Event ignored"
+		return c.JSON(http.StatusOK, resp)
+	}
+
+	for _, event := range toStorageEvents(&req) {
+		if err := h.storageEventService.HandleEvent(c.Request().Context(), event); err != nil {
+			log.Error().Err(err).Str("type", event.Type).Str("object", event.ObjectName).Msg("[StorageWebhookHandler-ReceiveStorageEvent] Failed to handle storage event")
+			resp.Message = "Failed to process storage event"
+			return c.JSON(http.StatusInternalServerError, resp)
+		}
+	}
+
+	resp.Message = "Event accepted"
+	return c.JSON(http.StatusOK, resp)
+}
+
+// toStorageEvents maps row changes to object events; a move (renamed row) is a delete plus a create
+func toStorageEvents(req *request.SupabaseStorageWebhookRequest) []entity.StorageEventEntity {
+	var events []entity.StorageEventEntity
+	switch req.Type {
+	case "INSERT":
+		if req.Record != nil {
+			events = append(events, entity.StorageEventEntity{Type: entity.StorageEventCreated, Bucket: req.Record.BucketID, ObjectName: req.Record.Name})
+		}
+	case "DELETE":
+		if req.OldRecord != nil {
+			events = append(events, entity.StorageEventEntity{Type: entity.StorageEventDeleted, Bucket: req.OldRecord.BucketID, ObjectName: req.OldRecord.Name})
+		}
+	case "UPDATE":
+		if req.Record != nil && req.OldRecord != nil && (req.Record.Name != req.OldRecord.Name || req.Record.BucketID != req.OldRecord.BucketID) {
+			events = append(events,
+				entity.StorageEventEntity{Type: entity.StorageEventDeleted, Bucket: req.OldRecord.BucketID, ObjectName: req.OldRecord.Name},
+				entity.StorageEventEntity{Type: entity.StorageEventCreated, Bucket: req.Record.BucketID, ObjectName: req.Record.Name},
+			)
+		}
+	}
+	return events
+}
+
+func NewStorageWebhookHandler(storageEventService port.StorageEventServiceInterface, secret string) StorageWebhookHandlerInterface {
+	return &StorageWebhookHandler{
+		storageEventService: storageEventService,
+		secret:              secret,
+	}
+}
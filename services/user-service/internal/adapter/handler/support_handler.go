@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type SupportHandlerInterface interface {
+	CreateTicket(c echo.Context) error
+	GetMyTickets(c echo.Context) error
+	GetMyTicket(c echo.Context) error
+	GetTickets(c echo.Context) error
+	GetTicket(c echo.Context) error
+	Assign(c echo.Context) error
+	UpdateStatus(c echo.Context) error
+}
+
+type SupportHandler struct {
+	supportService port.SupportServiceInterface
+	validator      *myvalidator.Validator
+}
+
+// CreateTicket takes a multipart form with "category", "subject", "message" and optional "attachments" files
+func (h *SupportHandler) CreateTicket(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	userID := c.Get("user_id").(int64)
+
+	var uploads []entity.SupportUploadEntity
+	if form, err := c.MultipartForm(); err == nil {
+		for _, file := range form.File["attachments"] {
+			src, err := file.Open()
+			if err != nil {
+				log.Error().Err(err).Int64("user_id", userID).Msg("[SupportHandler-CreateTicket] Failed to open uploaded file")
+				resp.Message = "Failed to process uploaded file"
+				return c.JSON(http.StatusInternalServerError, resp)
+			}
+			data, err := io.ReadAll(src)
+			src.Close()
+			if err != nil {
+				log.Error().Err(err).Int64("user_id", userID).Msg("[SupportHandler-CreateTicket] Failed to read uploaded file")
+				resp.Message = "Failed to process uploaded file"
+				return c.JSON(http.StatusInternalServerError, resp)
+			}
+			uploads = append(uploads, entity.SupportUploadEntity{FileName: file.Filename, Data: data})
+		}
+	}
+
+	ticket, err := h.supportService.CreateTicket(c.Request().Context(), userID, c.FormValue("category"), c.FormValue("subject"), c.FormValue("message"), uploads)
+	if err != nil {
+		return h.handleError(c, err, "Failed to create support ticket")
+	}
+
+	resp.Message = "Support ticket created successfully"
+	resp.Data = toSupportTicketResponse(ticket)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *SupportHandler) GetMyTickets(c echo.Context) error {
+	page, limit := pageQuery(c)
+
+	tickets, pagination, err := h.supportService.GetMyTickets(c.Request().Context(), c.Get("user_id").(int64), page, limit)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve support tickets")
+	}
+
+	return respondPage(c, "Support tickets retrieved successfully", toSupportTicketResponses(tickets), pagination)
+}
+
+func (h *SupportHandler) GetMyTicket(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid support ticket ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	ticket, err := h.supportService.GetMyTicket(c.Request().Context(), c.Get("user_id").(int64), id)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve support ticket")
+	}
+
+	resp.Message = "Support ticket retrieved successfully"
+	resp.Data = toSupportTicketResponse(ticket)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetTickets filters by ?status=, ?category= and ?assignee_id=, where assignee_id=none lists unassigned tickets
+func (h *SupportHandler) GetTickets(c echo.Context) error {
+	filter := entity.SupportTicketFilter{
+		Status:   c.QueryParam("status"),
+		Category: c.QueryParam("category"),
+	}
+	if filter.Status != "" && !entity.IsSupportTicketStatus(filter.Status) {
+		return c.JSON(http.StatusBadRequest, response.DefaultResponse{Message: "Invalid status filter"})
+	}
+	if filter.Category != "" && !entity.IsSupportTicketCategory(filter.Category) {
+		return c.JSON(http.StatusBadRequest, response.DefaultResponse{Message: "Invalid category filter"})
+	}
+	switch assignee := c.QueryParam("assignee_id"); assignee {
+	case "":
+	case "none":
+		filter.Unassigned = true
+	default:
+		assigneeID, err := strconv.ParseInt(assignee, 10, 64)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, response.DefaultResponse{Message: "Invalid assignee filter"})
+		}
+		filter.AssigneeID = assigneeID
+	}
+
+	page, limit := pageQuery(c)
+
+	tickets, pagination, err := h.supportService.GetTickets(c.Request().Context(), filter, page, limit)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve support tickets")
+	}
+
+	return respondPage(c, "Support tickets retrieved successfully", toSupportTicketResponses(tickets), pagination)
+}
+
+func (h *SupportHandler) GetTicket(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid support ticket ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	ticket, err := h.supportService.GetTicket(c.Request().Context(), id)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve support ticket")
+	}
+
+	resp.Message = "Support ticket retrieved successfully"
+	resp.Data = toSupportTicketResponse(ticket)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *SupportHandler) Assign(c echo.Context) error {
+	var (
+		req  = request.AssignSupportTicketRequest{}
+		resp = response.DefaultResponse{}
+	)
+	adminID := c.Get("user_id").(int64)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid support ticket ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	ticket, err := h.supportService.Assign(c.Request().Context(), id, req.AssigneeID, adminID)
+	if err != nil {
+		return h.handleError(c, err, "Failed to assign support ticket")
+	}
+
+	resp.Message = "Support ticket assigned successfully"
+	resp.Data = toSupportTicketResponse(ticket)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *SupportHandler) UpdateStatus(c echo.Context) error {
+	var (
+		req  = request.UpdateSupportTicketStatusRequest{}
+		resp = response.DefaultResponse{}
+	)
+	adminID := c.Get("user_id").(int64)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid support ticket ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	ticket, err := h.supportService.UpdateStatus(c.Request().Context(), id, req.Status, req.Note, adminID)
+	if err != nil {
+		return h.handleError(c, err, "Failed to update support ticket")
+	}
+
+	resp.Message = "Support ticket updated successfully"
+	resp.Data = toSupportTicketResponse(ticket)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *SupportHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Str("path", c.Path()).Msg("[SupportHandler] Request failed")
+
+	switch {
+	case err.Error() == "support ticket not found":
+		resp.Message = "Support ticket not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case err.Error() == "assignee not found":
+		resp.Message = "Assignee not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case err.Error() == "support ticket already has this status":
+		resp.Message = "Support ticket already has this status"
+		return c.JSON(http.StatusConflict, resp)
+	case strings.HasPrefix(err.Error(), "category "),
+		strings.HasPrefix(err.Error(), "status "),
+		strings.HasPrefix(err.Error(), "subject "),
+		strings.HasPrefix(err.Error(), "message "),
+		strings.HasPrefix(err.Error(), "note "),
+		strings.HasPrefix(err.Error(), "assignee must"),
+		err.Error() == "too many attachments",
+		err.Error() == "attachment is empty",
+		err.Error() == "attachment must be a JPEG, PNG, WebP or PDF file":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	case err.Error() == "attachment exceeds the size limit":
+		resp.Message = "Attachment exceeds the size limit"
+		return c.JSON(http.StatusRequestEntityTooLarge, resp)
+	case err.Error() == "storage service unavailable":
+		resp.Message = "Storage service unavailable"
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	case err.Error() == "file is infected":
+		resp.Message = "File was rejected by the virus scanner"
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	case err.Error() == "file scanner is unavailable":
+		resp.Message = "File scanning is temporarily unavailable, please try again later"
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toSupportTicketResponses(tickets []entity.SupportTicketEntity) []response.SupportTicketResponse {
+	ticketData := make([]response.SupportTicketResponse, 0, len(tickets))
+	for i := range tickets {
+		ticketData = append(ticketData, toSupportTicketResponse(&tickets[i]))
+	}
+	return ticketData
+}
+
+func toSupportTicketResponse(ticket *entity.SupportTicketEntity) response.SupportTicketResponse {
+	attachments := make([]response.SupportAttachmentResponse, 0, len(ticket.Attachments))
+	for _, attachment := range ticket.Attachments {
+		attachments = append(attachments, response.SupportAttachmentResponse{
+			FileName:    attachment.FileName,
+			ContentType: attachment.ContentType,
+			Size:        attachment.Size,
+			URL:         attachment.URL,
+		})
+	}
+
+	return response.SupportTicketResponse{
+		ID:          ticket.ID,
+		UserID:      ticket.UserID,
+		Category:    ticket.Category,
+		Subject:     ticket.Subject,
+		Message:     ticket.Message,
+		Attachments: attachments,
+		Status:      ticket.Status,
+		AssigneeID:  ticket.AssigneeID,
+		ResolvedAt:  ticket.ResolvedAt,
+		CreatedAt:   ticket.CreatedAt,
+		UpdatedAt:   ticket.UpdatedAt,
+	}
+}
+
+func NewSupportHandler(supportService port.SupportServiceInterface) SupportHandlerInterface {
+	return &SupportHandler{
+		supportService: supportService,
+		validator:      myvalidator.NewValidator(),
+	}
+}
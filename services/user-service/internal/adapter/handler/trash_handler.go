@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type TrashHandlerInterface interface {
+	GetDeletedUsers(c echo.Context) error
+	RestoreUser(c echo.Context) error
+	PurgeUser(c echo.Context) error
+	GetDeletedRoles(c echo.Context) error
+	RestoreRole(c echo.Context) error
+	PurgeRole(c echo.Context) error
+}
+
+type TrashHandler struct {
+	trashService port.TrashServiceInterface
+}
+
+func (h *TrashHandler) GetDeletedUsers(c echo.Context) error {
+	page, limit := pageQuery(c)
+
+	users, pagination, err := h.trashService.GetDeletedUsers(c.Request().Context(), c.QueryParam("search"), page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("[TrashHandler-GetDeletedUsers] Failed to get deleted users")
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Failed to retrieve deleted users",
+			"data":    nil,
+		})
+	}
+
+	userData := make([]response.TrashedUserResponse, 0, len(users))
+	for _, user := range users {
+		userData = append(userData, toTrashedUserResponse(user))
+	}
+
+	return respondPage(c, "Deleted users retrieved successfully", userData, pagination)
+}
+
+func (h *TrashHandler) RestoreUser(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid user ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	user, err := h.trashService.RestoreUser(c.Request().Context(), userID, c.Get("user_id").(int64))
+	if err != nil {
+		return h.handleError(c, err, "Failed to restore user")
+	}
+
+	resp.Message = "User restored successfully"
+	resp.Data = toTrashedUserResponse(*user)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// PurgeUser permanently deletes a user that is already in the trash
+func (h *TrashHandler) PurgeUser(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	userID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid user ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.trashService.PurgeUser(c.Request().Context(), userID, c.Get("user_id").(int64)); err != nil {
+		return h.handleError(c, err, "Failed to purge user")
+	}
+
+	resp.Message = "User permanently deleted"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *TrashHandler) GetDeletedRoles(c echo.Context) error {
+	page, limit := pageQuery(c)
+
+	roles, pagination, err := h.trashService.GetDeletedRoles(c.Request().Context(), c.QueryParam("search"), page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("[TrashHandler-GetDeletedRoles] Failed to get deleted roles")
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Failed to retrieve deleted roles",
+			"data":    nil,
+		})
+	}
+
+	roleData := make([]response.TrashedRoleResponse, 0, len(roles))
+	for _, role := range roles {
+		roleData = append(roleData, toTrashedRoleResponse(role))
+	}
+
+	return respondPage(c, "Deleted roles retrieved successfully", roleData, pagination)
+}
+
+func (h *TrashHandler) RestoreRole(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	roleID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid role ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	role, err := h.trashService.RestoreRole(c.Request().Context(), roleID, c.Get("user_id").(int64))
+	if err != nil {
+		return h.handleError(c, err, "Failed to restore role")
+	}
+
+	resp.Message = "Role restored successfully"
+	resp.Data = toTrashedRoleResponse(*role)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// PurgeRole permanently deletes a role that is already in the trash
+func (h *TrashHandler) PurgeRole(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	roleID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid role ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.trashService.PurgeRole(c.Request().Context(), roleID, c.Get("user_id").(int64)); err != nil {
+		return h.handleError(c, err, "Failed to purge role")
+	}
+
+	resp.Message = "Role permanently deleted"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *TrashHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Msg("[TrashHandler] Request failed")
+
+	switch err.Error() {
+	case "user not found":
+		resp.Message = "User not found in trash"
+		return c.JSON(http.StatusNotFound, resp)
+	case "role not found":
+		resp.Message = "Role not found in trash"
+		return c.JSON(http.StatusNotFound, resp)
+	case "user has ledger history":
+		resp.Message = "User has ledger history and cannot be purged"
+		return c.JSON(http.StatusConflict, resp)
+	case "email is used by another account":
+		resp.Message = "Email is already used by an active account"
+		return c.JSON(http.StatusConflict, resp)
+	case "invalid user id", "invalid role id":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toTrashedUserResponse(user entity.TrashedUserEntity) response.TrashedUserResponse {
+	return response.TrashedUserResponse{
+		ID:           user.ID,
+		Name:         user.Name,
+		Email:        user.Email,
+		RoleName:     user.RoleName,
+		MergedIntoID: user.MergedIntoID,
+		DeletedAt:    user.DeletedAt,
+	}
+}
+
+func toTrashedRoleResponse(role entity.TrashedRoleEntity) response.TrashedRoleResponse {
+	return response.TrashedRoleResponse{
+		ID:        role.ID,
+		Name:      role.Name,
+		DeletedAt: role.DeletedAt,
+	}
+}
+
+func NewTrashHandler(trashService port.TrashServiceInterface) TrashHandlerInterface {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// ChunkChecksumHeader optionally carries the hex SHA-256 of a single chunk
+const ChunkChecksumHeader = "X-Chunk-Checksum"
+
+type UploadHandlerInterface interface {
+	CreateUpload(c echo.Context) error
+	GetUpload(c echo.Context) error
+	UploadChunk(c echo.Context) error
+	CompleteUpload(c echo.Context) error
+	AbortUpload(c echo.Context) error
+}
+
+type UploadHandler struct {
+	uploadService port.UploadServiceInterface
+	validator     *myvalidator.Validator
+}
+
+func (h *UploadHandler) CreateUpload(c echo.Context) error {
+	var (
+		req  = request.CreateUploadRequest{}
+		resp = response.DefaultResponse{}
+	)
+	userID := c.Get("user_id").(int64)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	session, err := h.uploadService.CreateUpload(c.Request().Context(), userID, req.Filename, req.ContentType, req.Size, req.Checksum)
+	if err != nil {
+		return h.handleError(c, err, "Failed to create upload")
+	}
+
+	resp.Message = "Upload created successfully"
+	resp.Data = toUploadSessionResponse(session)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *UploadHandler) GetUpload(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	userID := c.Get("user_id").(int64)
+
+	session, err := h.uploadService.GetUpload(c.Request().Context(), userID, c.Param("id"))
+	if err != nil {
+		return h.handleError(c, err, "Failed to get upload")
+	}
+
+	resp.Message = "Upload retrieved successfully"
+	resp.Data = toUploadSessionResponse(session)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// UploadChunk takes the raw chunk bytes as the request body. Sending a chunk again replaces
+// it, so a client that lost a response can simply retry.
+func (h *UploadHandler) UploadChunk(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	userID := c.Get("user_id").(int64)
+
+	index, err := strconv.Atoi(c.Param("index"))
+	if err != nil {
+		resp.Message = "Invalid chunk index"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	session, err := h.uploadService.UploadChunk(c.Request().Context(), userID, c.Param("id"), index, c.Request().Body, c.Request().Header.Get(ChunkChecksumHeader))
+	if err != nil {
+		return h.handleError(c, err, "Failed to store chunk")
+	}
+
+	resp.Message = "Chunk uploaded successfully"
+	resp.Data = toUploadSessionResponse(session)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *UploadHandler) CompleteUpload(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	userID := c.Get("user_id").(int64)
+
+	result, err := h.uploadService.CompleteUpload(c.Request().Context(), userID, c.Param("id"))
+	if err != nil {
+		return h.handleError(c, err, "Failed to complete upload")
+	}
+
+	resp.Message = "Upload completed successfully"
+	resp.Data = response.UploadResultResponse{
+		URL:         result.URL,
+		Filename:    result.Filename,
+		ContentType: result.ContentType,
+		Size:        result.Size,
+		Checksum:    result.Checksum,
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *UploadHandler) AbortUpload(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	userID := c.Get("user_id").(int64)
+
+	if err := h.uploadService.AbortUpload(c.Request().Context(), userID, c.Param("id")); err != nil {
+		return h.handleError(c, err, "Failed to abort upload")
+	}
+
+	resp.Message = "Upload aborted successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *UploadHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Str("upload_id", c.Param("id")).Msg("[UploadHandler] Request failed")
+
+	switch {
+	case err.Error() == "upload not found":
+		resp.Message = "Upload not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case err.Error() == "storage is unavailable":
+		resp.Message = "File storage is unavailable, please try again later"
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	case err.Error() == "file scanner is unavailable":
+		resp.Message = "File scanning is temporarily unavailable, please try again later"
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	case err.Error() == "file is infected":
+		resp.Message = "File was rejected by the virus scanner, the upload was discarded"
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	case err.Error() == "file exceeds the storage size limit":
+		resp.Message = "File is larger than the storage allows, the upload was discarded"
+		return c.JSON(http.StatusRequestEntityTooLarge, resp)
+	case err.Error() == "checksum mismatch":
+		resp.Message = "Checksum mismatch, the upload was discarded and must be started again"
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	case strings.HasPrefix(err.Error(), "upload is incomplete"):
+		resp.Message = err.Error()
+		return c.JSON(http.StatusConflict, resp)
+	case err.Error() == "filename is required",
+		err.Error() == "unsupported content type",
+		strings.HasPrefix(err.Error(), "file size"),
+		strings.HasPrefix(err.Error(), "checksum must"),
+		strings.HasPrefix(err.Error(), "chunk "):
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toUploadSessionResponse(session *entity.UploadSessionEntity) response.UploadSessionResponse {
+	return response.UploadSessionResponse{
+		ID:             session.ID,
+		Filename:       session.Filename,
+		ContentType:    session.ContentType,
+		Size:           session.Size,
+		ChunkSize:      session.ChunkSize,
+		TotalChunks:    session.TotalChunks,
+		ReceivedChunks: session.ReceivedChunks,
+		MissingChunks:  session.MissingChunks(),
+		ExpiresAt:      session.ExpiresAt,
+	}
+}
+
+func NewUploadHandler(uploadService port.UploadServiceInterface) UploadHandlerInterface {
+	return &UploadHandler{
+		uploadService: uploadService,
+		validator:     myvalidator.NewValidator(),
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"user-service/config"
 	"user-service/internal/core/port"
 )
 
@@ -14,9 +15,9 @@ type UserHandler struct {
 	AdminHandlerInterface
 }
 
-func NewUserHandler(userService port.UserServiceInterface) UserHandlerInterface {
+func NewUserHandler(userService port.UserServiceInterface, cfg *config.Config) UserHandlerInterface {
 	return &UserHandler{
-		AuthHandlerInterface:  NewAuthHandler(userService),
+		AuthHandlerInterface:  NewAuthHandler(userService, cfg),
 		AdminHandlerInterface: NewAdminHandler(userService),
 	}
 }
@@ -0,0 +1,321 @@
+package handler
+
+import (
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+const maxVendorDocumentSize = 5 << 20 // 5MB
+
+var vendorDocumentContentTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".pdf":  "application/pdf",
+}
+
+type VendorHandlerInterface interface {
+	RegisterVendor(c echo.Context) error
+	GetMyVendor(c echo.Context) error
+	UploadDocument(c echo.Context) error
+	GetVendorProfile(c echo.Context) error
+	GetVendors(c echo.Context) error
+	GetVendorByID(c echo.Context) error
+	ApproveVendor(c echo.Context) error
+	RejectVendor(c echo.Context) error
+}
+
+type VendorHandler struct {
+	vendorService port.VendorServiceInterface
+	validator     *myvalidator.Validator
+}
+
+func (h *VendorHandler) RegisterVendor(c echo.Context) error {
+	var (
+		req  = request.RegisterVendorRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	userID := c.Get("user_id").(int64)
+
+	if err := c.Bind(&req); err != nil {
+		log.Warn().Err(err).Int64("user_id", userID).Msg("[VendorHandler-RegisterVendor] Failed to bind request")
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		log.Warn().Err(err).Int64("user_id", userID).Msg("[VendorHandler-RegisterVendor] Validation failed")
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	vendor, err := h.vendorService.RegisterVendor(c.Request().Context(), userID, &entity.VendorEntity{
+		StoreName:   req.StoreName,
+		Description: req.Description,
+		Phone:       req.Phone,
+		Address:     req.Address,
+		Lat:         req.Lat,
+		Lng:         req.Lng,
+	})
+	if err != nil {
+		return h.handleError(c, err, "Failed to register vendor")
+	}
+
+	resp.Message = "Vendor application submitted. Please upload your KYC documents for review."
+	resp.Data = toVendorResponse(vendor)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *VendorHandler) GetMyVendor(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	userID := c.Get("user_id").(int64)
+
+	vendor, err := h.vendorService.GetMyVendor(c.Request().Context(), userID)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve vendor")
+	}
+
+	resp.Message = "Vendor retrieved successfully"
+	resp.Data = toVendorResponse(vendor)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *VendorHandler) UploadDocument(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	userID := c.Get("user_id").(int64)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		log.Warn().Err(err).Int64("user_id", userID).Msg("[VendorHandler-UploadDocument] Failed to get file from form")
+		resp.Message = "File is required"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if file.Size == 0 || file.Size > maxVendorDocumentSize {
+		log.Warn().Int64("user_id", userID).Int64("file_size", file.Size).Msg("[VendorHandler-UploadDocument] Invalid file size")
+		resp.Message = "File must be between 1 byte and 5MB"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	// Extension and declared type must agree; KYC documents are images or PDF only
+	contentType, ok := vendorDocumentContentTypes[strings.ToLower(filepath.Ext(file.Filename))]
+	if !ok || !strings.HasPrefix(file.Header.Get("Content-Type"), contentType) {
+		log.Warn().Int64("user_id", userID).Str("filename", file.Filename).Str("content_type", file.Header.Get("Content-Type")).Msg("[VendorHandler-UploadDocument] Invalid file type")
+		resp.Message = "Invalid file type, only JPEG, PNG and PDF are allowed"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[VendorHandler-UploadDocument] Failed to open uploaded file")
+		resp.Message = "Failed to process uploaded file"
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+	defer src.Close()
+
+	document, err := h.vendorService.UploadDocument(c.Request().Context(), userID, c.FormValue("document_type"), src, contentType, file.Filename)
+	if err != nil {
+		return h.handleError(c, err, "Failed to upload document")
+	}
+
+	resp.Message = "Document uploaded successfully"
+	resp.Data = response.VendorDocumentResponse{
+		ID:           document.ID,
+		DocumentType: document.DocumentType,
+		FileURL:      document.FileURL,
+		CreatedAt:    document.CreatedAt,
+	}
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// GetVendorProfile is served on the vendor-scoped group, so vendor_id is already resolved
+func (h *VendorHandler) GetVendorProfile(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	vendorID := c.Get("vendor_id").(int64)
+
+	vendor, err := h.vendorService.GetVendorByID(c.Request().Context(), vendorID)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve vendor")
+	}
+
+	resp.Message = "Vendor retrieved successfully"
+	resp.Data = toVendorResponse(vendor)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *VendorHandler) GetVendors(c echo.Context) error {
+	status := c.QueryParam("status")
+	if status != "" && status != entity.VendorStatusPending && status != entity.VendorStatusApproved &&
+		status != entity.VendorStatusRejected && status != entity.VendorStatusSuspended {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Invalid status filter",
+			"data":    nil,
+		})
+	}
+
+	page, limit := pageQuery(c)
+
+	vendors, pagination, err := h.vendorService.GetVendors(c.Request().Context(), status, page, limit)
+	if err != nil {
+		log.Error().Err(err).Str("status", status).Msg("[VendorHandler-GetVendors] Failed to get vendors")
+		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
+			"message": "Failed to retrieve vendors",
+			"data":    nil,
+		})
+	}
+
+	vendorData := make([]response.VendorResponse, 0, len(vendors))
+	for i := range vendors {
+		vendorData = append(vendorData, toVendorResponse(&vendors[i]))
+	}
+
+	return respondPage(c, "Vendors retrieved successfully", vendorData, pagination)
+}
+
+func (h *VendorHandler) GetVendorByID(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid vendor ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	vendor, err := h.vendorService.GetVendorByID(c.Request().Context(), id)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve vendor")
+	}
+
+	resp.Message = "Vendor retrieved successfully"
+	resp.Data = toVendorResponse(vendor)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *VendorHandler) ApproveVendor(c echo.Context) error {
+	resp := response.DefaultResponse{}
+	adminID := c.Get("user_id").(int64)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid vendor ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.vendorService.ApproveVendor(c.Request().Context(), id, adminID); err != nil {
+		return h.handleError(c, err, "Failed to approve vendor")
+	}
+
+	log.Info().Int64("vendor_id", id).Int64("admin_id", adminID).Msg("[VendorHandler-ApproveVendor] Vendor approved")
+	resp.Message = "Vendor approved successfully. The vendor must sign in again to use vendor features."
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *VendorHandler) RejectVendor(c echo.Context) error {
+	var (
+		req  = request.RejectVendorRequest{}
+		resp = response.DefaultResponse{}
+	)
+	adminID := c.Get("user_id").(int64)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid vendor ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	if err := h.vendorService.RejectVendor(c.Request().Context(), id, adminID, req.Reason); err != nil {
+		return h.handleError(c, err, "Failed to reject vendor")
+	}
+
+	log.Info().Int64("vendor_id", id).Int64("admin_id", adminID).Msg("[VendorHandler-RejectVendor] Vendor rejected")
+	resp.Message = "Vendor rejected successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *VendorHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Msg("[VendorHandler] Request failed")
+
+	switch err.Error() {
+	case "vendor not found":
+		resp.Message = "Vendor not found"
+		return c.JSON(http.StatusNotFound, resp)
+	case "vendor application already exists", "vendor is not pending review":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusConflict, resp)
+	case "store name must be between 3 and 100 characters", "invalid coordinates",
+		"document type must be one of ktp, npwp, nib, other", "vendor has no KYC documents",
+		"rejection reason is required":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	case "storage service unavailable":
+		resp.Message = "Storage service unavailable"
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	case "file is infected":
+		resp.Message = "File was rejected by the virus scanner"
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	case "file scanner is unavailable":
+		resp.Message = "File scanning is temporarily unavailable, please try again later"
+		return c.JSON(http.StatusServiceUnavailable, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func toVendorResponse(vendor *entity.VendorEntity) response.VendorResponse {
+	vendorResp := response.VendorResponse{
+		ID:              vendor.ID,
+		UserID:          vendor.UserID,
+		StoreName:       vendor.StoreName,
+		Description:     vendor.Description,
+		Phone:           vendor.Phone,
+		Address:         vendor.Address,
+		Lat:             vendor.Lat,
+		Lng:             vendor.Lng,
+		Status:          vendor.Status,
+		RejectionReason: vendor.RejectionReason,
+		ReviewedAt:      vendor.ReviewedAt,
+		CreatedAt:       vendor.CreatedAt,
+	}
+
+	for _, document := range vendor.Documents {
+		vendorResp.Documents = append(vendorResp.Documents, response.VendorDocumentResponse{
+			ID:           document.ID,
+			DocumentType: document.DocumentType,
+			FileURL:      document.FileURL,
+			CreatedAt:    document.CreatedAt,
+		})
+	}
+
+	return vendorResp
+}
+
+func NewVendorHandler(vendorService port.VendorServiceInterface) VendorHandlerInterface {
+	return &VendorHandler{
+		vendorService: vendorService,
+		validator:     myvalidator.NewValidator(),
+	}
+}
@@ -0,0 +1,363 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"user-service/internal/adapter/handler/request"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	myvalidator "user-service/utils/validator"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+type WebhookHandlerInterface interface {
+	GetEndpoints(c echo.Context) error
+	GetEndpoint(c echo.Context) error
+	CreateEndpoint(c echo.Context) error
+	UpdateEndpoint(c echo.Context) error
+	DeleteEndpoint(c echo.Context) error
+	GetDeliveries(c echo.Context) error
+	Redeliver(c echo.Context) error
+	DispatchEvent(c echo.Context) error
+}
+
+type WebhookHandler struct {
+	webhookService port.WebhookServiceInterface
+	validator      *myvalidator.Validator
+}
+
+func (h *WebhookHandler) GetEndpoints(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	endpoints, err := h.webhookService.GetEndpoints(c.Request().Context())
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve webhook endpoints")
+	}
+
+	principal, _ := middleware.GetPrincipal(c)
+	endpointData := make([]response.WebhookEndpointResponse, 0, len(endpoints))
+	for i := range endpoints {
+		if principal.IsAPIKey() && endpoints[i].APIKeyID != principal.APIKeyID {
+			continue
+		}
+		endpointData = append(endpointData, toWebhookEndpointResponse(&endpoints[i], false))
+	}
+
+	resp.Message = "Webhook endpoints retrieved successfully"
+	resp.Data = endpointData
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *WebhookHandler) GetEndpoint(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid webhook ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	endpoint, err := h.webhookService.GetEndpointByID(c.Request().Context(), id)
+	if err == nil {
+		err = checkEndpointOwner(c, endpoint)
+	}
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve webhook endpoint")
+	}
+
+	resp.Message = "Webhook endpoint retrieved successfully"
+	resp.Data = toWebhookEndpointResponse(endpoint, false)
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateEndpoint generates a secret when none is given; the response is the only place it is shown in full
+func (h *WebhookHandler) CreateEndpoint(c echo.Context) error {
+	var (
+		req  = request.WebhookEndpointRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	// Admins create endpoints with their session, partners with their API key
+	createdBy, _ := c.Get("user_id").(int64)
+	principal, _ := middleware.GetPrincipal(c)
+
+	endpoint, err := h.webhookService.CreateEndpoint(c.Request().Context(), &entity.WebhookEndpointEntity{
+		URL:         req.URL,
+		Secret:      req.Secret,
+		EventTypes:  req.EventTypes,
+		Description: req.Description,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+		CreatedBy:   createdBy,
+		APIKeyID:    principal.APIKeyID,
+	})
+	if err != nil {
+		return h.handleError(c, err, "Failed to create webhook endpoint")
+	}
+
+	resp.Message = "Webhook endpoint created successfully"
+	resp.Data = toWebhookEndpointResponse(endpoint, true)
+	return c.JSON(http.StatusCreated, resp)
+}
+
+func (h *WebhookHandler) UpdateEndpoint(c echo.Context) error {
+	var (
+		req  = request.WebhookEndpointRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid webhook ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	if err := h.checkEndpointOwnerByID(c, id); err != nil {
+		return h.handleError(c, err, "Failed to update webhook endpoint")
+	}
+
+	endpoint, err := h.webhookService.UpdateEndpoint(c.Request().Context(), id, &entity.WebhookEndpointEntity{
+		URL:         req.URL,
+		Secret:      req.Secret,
+		EventTypes:  req.EventTypes,
+		Description: req.Description,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+	})
+	if err != nil {
+		return h.handleError(c, err, "Failed to update webhook endpoint")
+	}
+
+	resp.Message = "Webhook endpoint updated successfully"
+	resp.Data = toWebhookEndpointResponse(endpoint, false)
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *WebhookHandler) DeleteEndpoint(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid webhook ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.checkEndpointOwnerByID(c, id); err != nil {
+		return h.handleError(c, err, "Failed to delete webhook endpoint")
+	}
+
+	if err := h.webhookService.DeleteEndpoint(c.Request().Context(), id); err != nil {
+		return h.handleError(c, err, "Failed to delete webhook endpoint")
+	}
+
+	resp.Message = "Webhook endpoint deleted successfully"
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetDeliveries is the delivery log of one endpoint, newest first, optionally filtered by status
+func (h *WebhookHandler) GetDeliveries(c echo.Context) error {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Invalid webhook ID format",
+			"data":    nil,
+		})
+	}
+
+	status := c.QueryParam("status")
+	if status != "" && status != entity.WebhookDeliveryPending &&
+		status != entity.WebhookDeliverySucceeded && status != entity.WebhookDeliveryFailed {
+		return c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"message": "Invalid status filter",
+			"data":    nil,
+		})
+	}
+
+	if err := h.checkEndpointOwnerByID(c, id); err != nil {
+		return h.handleError(c, err, "Failed to retrieve webhook deliveries")
+	}
+
+	page, limit := pageQuery(c)
+
+	deliveries, pagination, err := h.webhookService.GetDeliveries(c.Request().Context(), id, status, page, limit)
+	if err != nil {
+		return h.handleError(c, err, "Failed to retrieve webhook deliveries")
+	}
+
+	deliveryData := make([]response.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryData = append(deliveryData, response.WebhookDeliveryResponse{
+			ID:             delivery.ID,
+			EventID:        delivery.EventID,
+			EventType:      delivery.EventType,
+			Payload:        delivery.Payload,
+			Status:         delivery.Status,
+			Attempts:       delivery.Attempts,
+			ResponseStatus: delivery.ResponseStatus,
+			ResponseBody:   delivery.ResponseBody,
+			LastError:      delivery.LastError,
+			DurationMs:     delivery.DurationMs,
+			DeliveredAt:    delivery.DeliveredAt,
+			CreatedAt:      delivery.CreatedAt,
+			UpdatedAt:      delivery.UpdatedAt,
+		})
+	}
+
+	return respondPage(c, "Webhook deliveries retrieved successfully", deliveryData, pagination)
+}
+
+func (h *WebhookHandler) Redeliver(c echo.Context) error {
+	resp := response.DefaultResponse{}
+
+	id, err := strconv.ParseInt(c.Param("delivery_id"), 10, 64)
+	if err != nil {
+		resp.Message = "Invalid delivery ID format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.webhookService.Redeliver(c.Request().Context(), id); err != nil {
+		return h.handleError(c, err, "Failed to redeliver webhook")
+	}
+
+	resp.Message = "Webhook delivery queued"
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DispatchEvent lets other services raise the events they own, such as order.paid
+func (h *WebhookHandler) DispatchEvent(c echo.Context) error {
+	var (
+		req  = request.WebhookEventRequest{}
+		resp = response.DefaultResponse{}
+	)
+
+	if err := c.Bind(&req); err != nil {
+		resp.Message = "Invalid request format"
+		return c.JSON(http.StatusBadRequest, resp)
+	}
+
+	if err := h.validator.ValidateContext(c.Request().Context(), &req); err != nil {
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	// Events about users are raised by this service itself
+	if req.Event != entity.WebhookEventOrderPaid {
+		resp.Message = "Event cannot be dispatched by other services"
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	}
+
+	data := req.Data
+	if len(data) == 0 {
+		data = json.RawMessage("{}")
+	}
+
+	principal, _ := middleware.GetPrincipal(c)
+	if err := h.webhookService.Dispatch(c.Request().Context(), req.Event, req.EventID, data); err != nil {
+		log.Error().Err(err).Str("service", principal.ServiceName).Str("event_id", req.EventID).Msg("[WebhookHandler-DispatchEvent] Failed to dispatch event")
+		return h.handleError(c, err, "Failed to dispatch event")
+	}
+
+	resp.Message = "Event accepted"
+	return c.JSON(http.StatusAccepted, resp)
+}
+
+func (h *WebhookHandler) handleError(c echo.Context, err error, fallback string) error {
+	resp := response.DefaultResponse{}
+	log.Error().Err(err).Str("path", c.Path()).Msg("[WebhookHandler] Request failed")
+
+	switch err.Error() {
+	case "webhook endpoint not found", "webhook delivery not found":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusNotFound, resp)
+	case "webhook delivery is still pending":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusConflict, resp)
+	case "webhook url must be an absolute http or https url", "webhook secret must be at least 16 characters",
+		"unknown webhook event type", "at least one event type is required", "event id is required":
+		resp.Message = err.Error()
+		return c.JSON(http.StatusUnprocessableEntity, resp)
+	default:
+		resp.Message = fallback
+		return c.JSON(http.StatusInternalServerError, resp)
+	}
+}
+
+func (h *WebhookHandler) checkEndpointOwnerByID(c echo.Context, id int64) error {
+	if principal, _ := middleware.GetPrincipal(c); !principal.IsAPIKey() {
+		return nil
+	}
+
+	endpoint, err := h.webhookService.GetEndpointByID(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return checkEndpointOwner(c, endpoint)
+}
+
+// checkEndpointOwner hides endpoints of other partners, and those made by admins, from API key callers
+func checkEndpointOwner(c echo.Context, endpoint *entity.WebhookEndpointEntity) error {
+	principal, _ := middleware.GetPrincipal(c)
+	if principal.IsAPIKey() && endpoint.APIKeyID != principal.APIKeyID {
+		return errors.New("webhook endpoint not found")
+	}
+	return nil
+}
+
+// toWebhookEndpointResponse masks the secret unless it is being handed out on creation
+func toWebhookEndpointResponse(endpoint *entity.WebhookEndpointEntity, revealSecret bool) response.WebhookEndpointResponse {
+	secret := endpoint.Secret
+	if !revealSecret {
+		secret = maskSecret(secret)
+	}
+
+	return response.WebhookEndpointResponse{
+		ID:          endpoint.ID,
+		URL:         endpoint.URL,
+		Secret:      secret,
+		EventTypes:  endpoint.EventTypes,
+		Description: endpoint.Description,
+		IsActive:    endpoint.IsActive,
+		APIKeyID:    endpoint.APIKeyID,
+		CreatedAt:   endpoint.CreatedAt,
+		UpdatedAt:   endpoint.UpdatedAt,
+	}
+}
+
+func maskSecret(secret string) string {
+	if len(secret) <= 4 {
+		return "****"
+	}
+	return "****" + secret[len(secret)-4:]
+}
+
+func NewWebhookHandler(webhookService port.WebhookServiceInterface) WebhookHandlerInterface {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		validator:      myvalidator.NewValidator(),
+	}
+}
@@ -0,0 +1,237 @@
+// Package httpclient builds the *http.Client used for calls to external APIs (storage,
+// geocoding). Each client retries transient failures with jittered backoff, exports per-host
+// metrics and forwards the request ID, so adapters only build requests and read responses.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+	"user-service/utils/querytrace"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+)
+
+// drainLimit is how much of a failed response is read so its connection can be reused
+const drainLimit = 4 << 10
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_client_requests_total",
+		Help: "Attempts made to external APIs by status code, or \"error\" when no response came back.",
+	}, []string{"client", "host", "method", "status"})
+	requestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_client_request_duration_seconds",
+		Help:    "Time to the response headers of one attempt to an external API.",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"client", "host"})
+	retriesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_client_retries_total",
+		Help: "Attempts to external APIs repeated after a 5xx or a connection failure.",
+	}, []string{"client", "host"})
+)
+
+// Options for one client. Zero values get the defaults: 2 retries, 100ms base and 2s
+// maximum backoff. A negative MaxRetries turns retries off.
+type Options struct {
+	// Name labels the metrics, e.g. "storage" or "geocoder"
+	Name string
+	// Timeout bounds a whole call including its retries; 0 leaves it to the request context
+	Timeout     time.Duration
+	MaxRetries  int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func New(opts Options) *http.Client {
+	return &http.Client{
+		Timeout:   opts.Timeout,
+		Transport: NewTransport(opts, defaultTransport()),
+	}
+}
+
+// defaultTransport is http.DefaultTransport with bounds on every phase before the response
+func defaultTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{Timeout: 5 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	transport.TLSHandshakeTimeout = 5 * time.Second
+	transport.ResponseHeaderTimeout = 30 * time.Second
+	return transport
+}
+
+type Transport struct {
+	name        string
+	base        http.RoundTripper
+	maxRetries  int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+}
+
+// NewTransport wraps base, e.g. for tests that need their own transport
+func NewTransport(opts Options, base http.RoundTripper) *Transport {
+	t := &Transport{
+		name:        opts.Name,
+		base:        base,
+		maxRetries:  opts.MaxRetries,
+		baseBackoff: opts.BaseBackoff,
+		maxBackoff:  opts.MaxBackoff,
+	}
+	if t.maxRetries == 0 {
+		t.maxRetries = 2
+	}
+	if t.maxRetries < 0 {
+		t.maxRetries = 0
+	}
+	if t.baseBackoff <= 0 {
+		t.baseBackoff = 100 * time.Millisecond
+	}
+	if t.maxBackoff <= 0 {
+		t.maxBackoff = 2 * time.Second
+	}
+	return t
+}
+
+type idempotentContextKey struct{}
+
+// Idempotent marks a POST or PATCH as safe to repeat, e.g. one that only reads or signs.
+// GET, HEAD, OPTIONS, PUT and DELETE, and requests with an Idempotency-Key header, already are.
+func Idempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, idempotentContextKey{}, true)
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if trace := querytrace.FromContext(ctx); trace != nil && trace.RequestID != "" && req.Header.Get("X-Request-Id") == "" {
+		req = req.Clone(ctx)
+		req.Header.Set("X-Request-Id", trace.RequestID)
+	}
+
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		resp, err := t.base.RoundTrip(req)
+		t.observe(req, resp, start)
+
+		retry, wait := t.shouldRetry(req, resp, err, attempt)
+		if !retry {
+			return resp, err
+		}
+
+		// The body of a retried request is sent again from the start
+		next := req
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			next = req.Clone(ctx)
+			next.Body = body
+		}
+		if resp != nil {
+			_, _ = io.CopyN(io.Discard, resp.Body, drainLimit)
+			resp.Body.Close()
+		}
+
+		retriesTotal.WithLabelValues(t.name, req.URL.Host).Inc()
+		log.Warn().Err(err).Str("client", t.name).Str("host", req.URL.Host).Int("attempt", attempt+1).Dur("backoff", wait).Msg("[HTTPClient-RoundTrip] Retrying request")
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		req = next
+	}
+}
+
+// shouldRetry repeats 5xx responses and connection failures of requests that are safe to
+// repeat. A request that never reached the server, because the dial failed, is always safe.
+func (t *Transport) shouldRetry(req *http.Request, resp *http.Response, err error, attempt int) (bool, time.Duration) {
+	if attempt >= t.maxRetries || req.Context().Err() != nil {
+		return false, 0
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false, 0
+	}
+
+	wait := t.backoff(attempt)
+	if err != nil {
+		if isDialError(err) {
+			return true, wait
+		}
+		return isIdempotent(req) && isConnectionError(err), wait
+	}
+
+	switch resp.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+	default:
+		return false, 0
+	}
+	if !isIdempotent(req) {
+		return false, 0
+	}
+	// A server asking for more than the longest backoff is not worth waiting for here
+	if after := resp.Header.Get("Retry-After"); after != "" {
+		seconds, parseErr := strconv.Atoi(after)
+		if parseErr == nil && time.Duration(seconds)*time.Second > t.maxBackoff {
+			return false, 0
+		}
+		if parseErr == nil && time.Duration(seconds)*time.Second > wait {
+			wait = time.Duration(seconds) * time.Second
+		}
+	}
+	return true, wait
+}
+
+// backoff is "full jitter": a random wait up to an exponentially growing cap, so clients
+// failing together do not retry together
+func (t *Transport) backoff(attempt int) time.Duration {
+	limit := t.baseBackoff << attempt
+	if limit > t.maxBackoff || limit <= 0 {
+		limit = t.maxBackoff
+	}
+	return rand.N(limit) + 1
+}
+
+func (t *Transport) observe(req *http.Request, resp *http.Response, start time.Time) {
+	status := "error"
+	if resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+		requestDuration.WithLabelValues(t.name, req.URL.Host).Observe(time.Since(start).Seconds())
+	}
+	requestsTotal.WithLabelValues(t.name, req.URL.Host, req.Method, status).Inc()
+}
+
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	if req.Header.Get("Idempotency-Key") != "" {
+		return true
+	}
+	marked, _ := req.Context().Value(idempotentContextKey{}).(bool)
+	return marked
+}
+
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// isConnectionError is a connection dropped or reset while the request was in flight
+func isConnectionError(err error) bool {
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.EPIPE)
+}
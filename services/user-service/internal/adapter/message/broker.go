@@ -0,0 +1,100 @@
+package message
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/streadway/amqp"
+)
+
+const defaultReconnectInterval = 5 * time.Second
+
+// Broker holds the current RabbitMQ channel. It notices when the channel closes, reconnects in
+// the background and runs the OnReconnect hooks, so publishers can buffer while it is down.
+type Broker struct {
+	mu       sync.RWMutex
+	channel  *amqp.Channel
+	connect  func() (*amqp.Channel, error)
+	interval time.Duration
+	hooks    []func(ctx context.Context)
+}
+
+// NewBroker starts from channel, which may be nil when RabbitMQ was down at startup.
+// connect opens a new channel; without it the broker never reconnects.
+func NewBroker(channel *amqp.Channel, connect func() (*amqp.Channel, error), interval time.Duration) *Broker {
+	if interval <= 0 {
+		interval = defaultReconnectInterval
+	}
+	return &Broker{
+		channel:  channel,
+		connect:  connect,
+		interval: interval,
+	}
+}
+
+// Channel is the open channel, or nil while RabbitMQ is unavailable
+func (b *Broker) Channel() *amqp.Channel {
+	if b == nil {
+		return nil
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.channel
+}
+
+func (b *Broker) Healthy() bool {
+	return b.Channel() != nil
+}
+
+// OnReconnect runs hook after every successful reconnect; call before Start
+func (b *Broker) OnReconnect(hook func(ctx context.Context)) {
+	b.hooks = append(b.hooks, hook)
+}
+
+// Start watches the channel until ctx is cancelled
+func (b *Broker) Start(ctx context.Context) {
+	if b.connect == nil {
+		return
+	}
+	go b.watch(ctx)
+}
+
+func (b *Broker) watch(ctx context.Context) {
+	for {
+		if channel := b.Channel(); channel != nil {
+			closed := channel.NotifyClose(make(chan *amqp.Error, 1))
+			select {
+			case <-ctx.Done():
+				return
+			case err := <-closed:
+				log.Warn().Err(err).Msg("[Broker-watch] RabbitMQ channel closed, buffering until it is back")
+				b.setChannel(nil)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(b.interval):
+		}
+
+		channel, err := b.connect()
+		if err != nil {
+			continue
+		}
+		b.setChannel(channel)
+		log.Info().Msg("[Broker-watch] RabbitMQ reconnected")
+
+		for _, hook := range b.hooks {
+			hook(ctx)
+		}
+	}
+}
+
+func (b *Broker) setChannel(channel *amqp.Channel) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.channel = channel
+}
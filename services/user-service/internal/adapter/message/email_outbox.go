@@ -0,0 +1,65 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-service/internal/adapter/breaker"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"github.com/streadway/amqp"
+)
+
+// EmailOutbox publishes email messages straight to RabbitMQ while it is healthy and keeps them
+// as entity.JobTypePublishEmail jobs otherwise; the job worker flushes them once it is back.
+type EmailOutbox struct {
+	broker  *Broker
+	breaker *breaker.Breaker
+	jobs    port.JobServiceInterface
+}
+
+// NewEmailOutbox buffers nothing when jobs is nil, so publish errors reach the caller as before
+func NewEmailOutbox(broker *Broker, b *breaker.Breaker, jobs port.JobServiceInterface) port.EmailOutboxInterface {
+	return &EmailOutbox{
+		broker:  broker,
+		breaker: b,
+		jobs:    jobs,
+	}
+}
+
+func (o *EmailOutbox) Publish(ctx context.Context, email *entity.BufferedEmailEntity) error {
+	err := o.Flush(ctx, email)
+	if err == nil || o.jobs == nil {
+		return err
+	}
+
+	if _, bufferErr := o.jobs.Enqueue(ctx, entity.JobTypePublishEmail, email, time.Time{}); bufferErr != nil {
+		log.Error().Err(bufferErr).Str("routing_key", email.RoutingKey).Msg("[EmailOutbox-Publish] Failed to buffer email")
+		return err
+	}
+
+	log.Warn().Err(err).Str("routing_key", email.RoutingKey).Msg("[EmailOutbox-Publish] RabbitMQ unavailable, email buffered")
+	return nil
+}
+
+// Flush goes through the RabbitMQ circuit breaker, so a broken broker fails fast
+func (o *EmailOutbox) Flush(ctx context.Context, email *entity.BufferedEmailEntity) error {
+	channel := o.broker.Channel()
+	if channel == nil {
+		return errors.New("rabbitmq not available")
+	}
+	return o.breaker.Execute(func() error {
+		return channel.Publish(
+			email.Exchange,   // exchange
+			email.RoutingKey, // routing key
+			false,            // mandatory
+			false,            // immediate
+			amqp.Publishing{
+				ContentType: email.ContentType,
+				Body:        email.Body,
+			},
+		)
+	})
+}
@@ -3,16 +3,25 @@ package message
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
+	"user-service/internal/core/domain/entity"
 	"user-service/internal/core/port"
+	"user-service/utils/i18n"
 
 	"github.com/rs/zerolog/log"
 	"github.com/streadway/amqp"
 )
 
 type EmailPublisher struct {
-	channel *amqp.Channel
+	outbox    port.EmailOutboxInterface
+	lifetimes entity.TokenLifetimes
+	languages port.RecipientLanguageInterface
+	// baseURL starts every link, e.g. https://api.jualan-sayur.id
+	baseURL string
 }
 
 type EmailVerificationMessage struct {
@@ -22,17 +31,68 @@ type EmailVerificationMessage struct {
 	Name    string `json:"name"`
 	Subject string `json:"subject"`
 	Body    string `json:"body"`
+	// Language and Data let notification-service render its own template for Type;
+	// Subject and Body are the same email rendered here, used when it has none
+	Language string            `json:"language"`
+	Data     map[string]string `json:"data,omitempty"`
 }
 
-func NewEmailPublisher(channel *amqp.Channel) port.EmailInterface {
+// NewEmailPublisher takes the token lifetimes so links state when they expire; nil means the defaults.
+// languages looks up each recipient's saved language; nil uses the request language only.
+// baseURL is the public URL of the service that links point at, see config.App.LinkBaseURL.
+func NewEmailPublisher(outbox port.EmailOutboxInterface, lifetimes *entity.TokenLifetimes, languages port.RecipientLanguageInterface, baseURL string) port.EmailInterface {
+	if lifetimes == nil {
+		lifetimes = &entity.TokenLifetimes{}
+	}
 	return &EmailPublisher{
-		channel: channel,
+		outbox:    outbox,
+		lifetimes: *lifetimes,
+		languages: languages,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// recipientLanguage prefers the language saved on the recipient's account, then the request language
+func (p *EmailPublisher) recipientLanguage(ctx context.Context, email string) string {
+	if p.languages != nil {
+		lang, err := p.languages.GetLanguageByEmail(ctx, email)
+		if err == nil && i18n.Supported(lang) {
+			return lang
+		}
+		if err != nil {
+			log.Warn().Err(err).Str("email", email).Msg("[EmailPublisher-recipientLanguage] Falling back to the request language")
+		}
+	}
+	return i18n.FromContext(ctx)
+}
+
+// emailData carries the template values, already formatted for the recipient language
+func emailData(params i18n.Params) map[string]string {
+	data := make(map[string]string, len(params))
+	for key, value := range params {
+		data[key] = fmt.Sprint(value)
+	}
+	return data
+}
+
+// publish hands the message to the outbox, which buffers it while RabbitMQ is unavailable
+func (p *EmailPublisher) publish(ctx context.Context, exchange, key string, msg amqp.Publishing) error {
+	if p.outbox == nil {
+		return errors.New("rabbitmq not available")
 	}
+	return p.outbox.Publish(ctx, &entity.BufferedEmailEntity{
+		Exchange:    exchange,
+		RoutingKey:  key,
+		ContentType: msg.ContentType,
+		Body:        msg.Body,
+	})
 }
 
 func (p *EmailPublisher) SendVerificationEmail(ctx context.Context, email, token string) error {
+	lang := p.recipientLanguage(ctx, email)
+
 	// Extract name from email (before @) or use default
-	name := "User"
+	name := i18n.T(lang, "email.default_name")
 	if atIndex := strings.Index(email, "@"); atIndex > 0 {
 		name = email[:atIndex]
 		// Capitalize first letter
@@ -41,25 +101,18 @@ func (p *EmailPublisher) SendVerificationEmail(ctx context.Context, email, token
 		}
 	}
 
-	verificationLink := "http://localhost:8080/api/v1/auth/verify?token=" + token
+	verificationLink := p.baseURL + "/api/v1/auth/verify?token=" + token
 
+	params := i18n.Params{"name": name, "link": verificationLink, "expires": i18n.Duration(lang, p.lifetimes.For(entity.TokenTypeEmailVerification)), "signature": i18n.T(lang, "email.signature")}
 	message := EmailVerificationMessage{
-		Email:   email,
-		Token:   token,
-		Type:    "email_verification",
-		Name:    name,
-		Subject: "Verify Your Account",
-		Body: fmt.Sprintf(`Hi %s,
-
-Please click this link to verify your account:
-%s
-
-Link expires in 24 hours.
-
-If you didn't create an account, please ignore this email.
-
-Best regards,
-Your App Team`, name, verificationLink),
+		Email:    email,
+		Token:    token,
+		Type:     "email_verification",
+		Name:     name,
+		Subject:  i18n.T(lang, "email.verification.subject"),
+		Body:     i18n.T(lang, "email.verification.body", params),
+		Language: lang,
+		Data:     emailData(params),
 	}
 
 	body, err := json.Marshal(message)
@@ -68,11 +121,10 @@ Your App Team`, name, verificationLink),
 		return err
 	}
 
-	err = p.channel.Publish(
+	err = p.publish(
+		ctx,
 		"",            // exchange
 		"email_queue", // routing key
-		false,         // mandatory
-		false,         // immediate
 		amqp.Publishing{
 			ContentType: "application/json",
 			Body:        body,
@@ -89,8 +141,10 @@ Your App Team`, name, verificationLink),
 }
 
 func (p *EmailPublisher) SendEmailChangeVerificationEmail(ctx context.Context, email, token string) error {
+	lang := p.recipientLanguage(ctx, email)
+
 	// Extract name from email (before @) or use default
-	name := "User"
+	name := i18n.T(lang, "email.default_name")
 	if atIndex := strings.Index(email, "@"); atIndex > 0 {
 		name = email[:atIndex]
 		// Capitalize first letter
@@ -99,25 +153,18 @@ func (p *EmailPublisher) SendEmailChangeVerificationEmail(ctx context.Context, e
 		}
 	}
 
-	verificationLink := "http://localhost:8080/api/v1/auth/verify-email-change?token=" + token
+	verificationLink := p.baseURL + "/api/v1/auth/verify-email-change?token=" + token
 
+	params := i18n.Params{"name": name, "link": verificationLink, "expires": i18n.Duration(lang, p.lifetimes.For(entity.TokenTypeEmailChange)), "signature": i18n.T(lang, "email.signature")}
 	message := EmailVerificationMessage{
-		Email:   email,
-		Token:   token,
-		Type:    "email_change",
-		Name:    name,
-		Subject: "Verify Your Email Change",
-		Body: fmt.Sprintf(`Hi %s,
-
-You requested to change your email address. Please click this link to verify your new email:
-%s
-
-Link expires in 24 hours.
-
-If you didn't request this change, please ignore this email.
-
-Best regards,
-Your App Team`, name, verificationLink),
+		Email:    email,
+		Token:    token,
+		Type:     "email_change",
+		Name:     name,
+		Subject:  i18n.T(lang, "email.email_change.subject"),
+		Body:     i18n.T(lang, "email.email_change.body", params),
+		Language: lang,
+		Data:     emailData(params),
 	}
 
 	body, err := json.Marshal(message)
@@ -126,11 +173,10 @@ Your App Team`, name, verificationLink),
 		return err
 	}
 
-	err = p.channel.Publish(
+	err = p.publish(
+		ctx,
 		"",            // exchange
 		"email_queue", // routing key
-		false,         // mandatory
-		false,         // immediate
 		amqp.Publishing{
 			ContentType: "application/json",
 			Body:        body,
@@ -147,8 +193,62 @@ Your App Team`, name, verificationLink),
 }
 
 func (p *EmailPublisher) SendPasswordResetEmail(ctx context.Context, email, token string) error {
+	lang := p.recipientLanguage(ctx, email)
+
+	// Extract name from email (before @) or use default
+	name := i18n.T(lang, "email.default_name")
+	if atIndex := strings.Index(email, "@"); atIndex > 0 {
+		name = email[:atIndex]
+		// Capitalize first letter
+		if len(name) > 0 {
+			name = strings.ToUpper(name[:1]) + strings.ToLower(name[1:])
+		}
+	}
+
+	resetLink := p.baseURL + "/api/v1/auth/reset-password?token=" + token
+
+	params := i18n.Params{"name": name, "link": resetLink, "expires": i18n.Duration(lang, p.lifetimes.For(entity.TokenTypePasswordReset)), "signature": i18n.T(lang, "email.signature")}
+	message := EmailVerificationMessage{
+		Email:    email,
+		Token:    token,
+		Type:     "password_reset",
+		Name:     name,
+		Subject:  i18n.T(lang, "email.password_reset.subject"),
+		Body:     i18n.T(lang, "email.password_reset.body", params),
+		Language: lang,
+		Data:     emailData(params),
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		log.Error().Err(err).Msg("[EmailPublisher-SendPasswordResetEmail] Failed to marshal message")
+		return err
+	}
+
+	err = p.publish(
+		ctx,
+		"",            // exchange
+		"email_queue", // routing key
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		log.Error().Err(err).Str("email", email).Msg("[EmailPublisher-SendPasswordResetEmail] Failed to publish message")
+		return err
+	}
+
+	log.Info().Str("email", email).Msg("[EmailPublisher-SendPasswordResetEmail] Password reset email sent to queue")
+	return nil
+}
+
+func (p *EmailPublisher) SendEmailChangeRevertEmail(ctx context.Context, email, newEmail, token string) error {
+	lang := p.recipientLanguage(ctx, email)
+
 	// Extract name from email (before @) or use default
-	name := "User"
+	name := i18n.T(lang, "email.default_name")
 	if atIndex := strings.Index(email, "@"); atIndex > 0 {
 		name = email[:atIndex]
 		// Capitalize first letter
@@ -157,38 +257,70 @@ func (p *EmailPublisher) SendPasswordResetEmail(ctx context.Context, email, toke
 		}
 	}
 
-	resetLink := "http://localhost:8080/api/v1/auth/reset-password?token=" + token
+	revertLink := p.baseURL + "/api/v1/auth/revert-email-change?token=" + token
 
+	params := i18n.Params{"name": name, "new_email": newEmail, "link": revertLink, "expires": i18n.Duration(lang, p.lifetimes.For(entity.TokenTypeEmailChangeRevert)), "signature": i18n.T(lang, "email.signature")}
 	message := EmailVerificationMessage{
-		Email:   email,
-		Token:   token,
-		Type:    "password_reset",
-		Name:    name,
-		Subject: "Reset Your Password",
-		Body: fmt.Sprintf(`Hi %s,
+		Email:    email,
+		Token:    token,
+		Type:     "email_change_revert",
+		Name:     name,
+		Subject:  i18n.T(lang, "email.email_change_revert.subject"),
+		Body:     i18n.T(lang, "email.email_change_revert.body", params),
+		Language: lang,
+		Data:     emailData(params),
+	}
 
-You requested to reset your password. Please click this link to reset your password:
-%s
+	body, err := json.Marshal(message)
+	if err != nil {
+		log.Error().Err(err).Msg("[EmailPublisher-SendEmailChangeRevertEmail] Failed to marshal message")
+		return err
+	}
 
-Link expires in 1 hour.
+	err = p.publish(
+		ctx,
+		"",            // exchange
+		"email_queue", // routing key
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		log.Error().Err(err).Str("email", email).Msg("[EmailPublisher-SendEmailChangeRevertEmail] Failed to publish message")
+		return err
+	}
+
+	log.Info().Str("email", email).Msg("[EmailPublisher-SendEmailChangeRevertEmail] Email change revert email sent to queue")
+	return nil
+}
 
-If you didn't request this, please ignore this email.
+// SendAccountLockedAlertEmail is for staff, so it falls back to the default language rather than the requester's
+func (p *EmailPublisher) SendAccountLockedAlertEmail(ctx context.Context, adminEmail string, userID int64, restoredEmail, replacedEmail string) error {
+	lang := p.recipientLanguage(i18n.WithLanguage(ctx, i18n.Default), adminEmail)
 
-Best regards,
-Your App Team`, name, resetLink),
+	params := i18n.Params{"user_id": strconv.FormatInt(userID, 10), "restored_email": restoredEmail, "replaced_email": replacedEmail, "signature": i18n.T(lang, "email.signature")}
+	message := EmailVerificationMessage{
+		Email:    adminEmail,
+		Type:     "account_locked_alert",
+		Name:     i18n.T(lang, "email.default_name"),
+		Subject:  i18n.T(lang, "email.account_locked_alert.subject", params),
+		Body:     i18n.T(lang, "email.account_locked_alert.body", params),
+		Language: lang,
+		Data:     emailData(params),
 	}
 
 	body, err := json.Marshal(message)
 	if err != nil {
-		log.Error().Err(err).Msg("[EmailPublisher-SendPasswordResetEmail] Failed to marshal message")
+		log.Error().Err(err).Msg("[EmailPublisher-SendAccountLockedAlertEmail] Failed to marshal message")
 		return err
 	}
 
-	err = p.channel.Publish(
+	err = p.publish(
+		ctx,
 		"",            // exchange
 		"email_queue", // routing key
-		false,         // mandatory
-		false,         // immediate
 		amqp.Publishing{
 			ContentType: "application/json",
 			Body:        body,
@@ -196,10 +328,501 @@ Your App Team`, name, resetLink),
 	)
 
 	if err != nil {
-		log.Error().Err(err).Str("email", email).Msg("[EmailPublisher-SendPasswordResetEmail] Failed to publish message")
+		log.Error().Err(err).Str("email", adminEmail).Msg("[EmailPublisher-SendAccountLockedAlertEmail] Failed to publish message")
 		return err
 	}
 
-	log.Info().Str("email", email).Msg("[EmailPublisher-SendPasswordResetEmail] Password reset email sent to queue")
+	log.Info().Str("email", adminEmail).Int64("user_id", userID).Msg("[EmailPublisher-SendAccountLockedAlertEmail] Account locked alert sent to queue")
+	return nil
+}
+
+func (p *EmailPublisher) SendPasswordChangedEmail(ctx context.Context, email string, changedAt time.Time) error {
+	lang := p.recipientLanguage(ctx, email)
+
+	// Extract name from email (before @) or use default
+	name := i18n.T(lang, "email.default_name")
+	if atIndex := strings.Index(email, "@"); atIndex > 0 {
+		name = email[:atIndex]
+		// Capitalize first letter
+		if len(name) > 0 {
+			name = strings.ToUpper(name[:1]) + strings.ToLower(name[1:])
+		}
+	}
+
+	params := i18n.Params{"name": name, "time": changedAt.Format(time.RFC1123), "signature": i18n.T(lang, "email.signature")}
+	message := EmailVerificationMessage{
+		Email:    email,
+		Type:     "password_changed",
+		Name:     name,
+		Subject:  i18n.T(lang, "email.password_changed.subject"),
+		Body:     i18n.T(lang, "email.password_changed.body", params),
+		Language: lang,
+		Data:     emailData(params),
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		log.Error().Err(err).Msg("[EmailPublisher-SendPasswordChangedEmail] Failed to marshal message")
+		return err
+	}
+
+	err = p.publish(
+		ctx,
+		"",            // exchange
+		"email_queue", // routing key
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		log.Error().Err(err).Str("email", email).Msg("[EmailPublisher-SendPasswordChangedEmail] Failed to publish message")
+		return err
+	}
+
+	log.Info().Str("email", email).Msg("[EmailPublisher-SendPasswordChangedEmail] Password changed email sent to queue")
+	return nil
+}
+
+func (p *EmailPublisher) SendPasswordExpiryWarningEmail(ctx context.Context, email, name string, expiresAt time.Time) error {
+	lang := p.recipientLanguage(ctx, email)
+
+	if name == "" {
+		name = i18n.T(lang, "email.default_name")
+		if atIndex := strings.Index(email, "@"); atIndex > 0 {
+			name = email[:atIndex]
+			// Capitalize first letter
+			if len(name) > 0 {
+				name = strings.ToUpper(name[:1]) + strings.ToLower(name[1:])
+			}
+		}
+	}
+
+	params := i18n.Params{"name": name, "time": expiresAt.Format(time.RFC1123), "signature": i18n.T(lang, "email.signature")}
+	message := EmailVerificationMessage{
+		Email:    email,
+		Type:     "password_expiry_warning",
+		Name:     name,
+		Subject:  i18n.T(lang, "email.password_expiry_warning.subject"),
+		Body:     i18n.T(lang, "email.password_expiry_warning.body", params),
+		Language: lang,
+		Data:     emailData(params),
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		log.Error().Err(err).Msg("[EmailPublisher-SendPasswordExpiryWarningEmail] Failed to marshal message")
+		return err
+	}
+
+	err = p.publish(
+		ctx,
+		"",            // exchange
+		"email_queue", // routing key
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		log.Error().Err(err).Str("email", email).Msg("[EmailPublisher-SendPasswordExpiryWarningEmail] Failed to publish message")
+		return err
+	}
+
+	log.Info().Str("email", email).Msg("[EmailPublisher-SendPasswordExpiryWarningEmail] Password expiry warning sent to queue")
+	return nil
+}
+
+func (p *EmailPublisher) SendNewDeviceSignInEmail(ctx context.Context, email, device, ipAddress, location string, signedInAt time.Time) error {
+	lang := p.recipientLanguage(ctx, email)
+
+	// Extract name from email (before @) or use default
+	name := i18n.T(lang, "email.default_name")
+	if atIndex := strings.Index(email, "@"); atIndex > 0 {
+		name = email[:atIndex]
+		// Capitalize first letter
+		if len(name) > 0 {
+			name = strings.ToUpper(name[:1]) + strings.ToLower(name[1:])
+		}
+	}
+
+	if device == "" {
+		device = i18n.T(lang, "email.new_device.unknown_device")
+	}
+	if ipAddress == "" {
+		ipAddress = i18n.T(lang, "email.new_device.unknown_ip")
+	}
+	if location == "" {
+		location = i18n.T(lang, "email.new_device.unknown_location")
+	}
+
+	params := i18n.Params{"name": name, "device": device, "ip": ipAddress, "location": location, "time": signedInAt.Format(time.RFC1123), "signature": i18n.T(lang, "email.signature")}
+	message := EmailVerificationMessage{
+		Email:    email,
+		Type:     "new_device_signin",
+		Name:     name,
+		Subject:  i18n.T(lang, "email.new_device.subject"),
+		Body:     i18n.T(lang, "email.new_device.body", params),
+		Language: lang,
+		Data:     emailData(params),
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		log.Error().Err(err).Msg("[EmailPublisher-SendNewDeviceSignInEmail] Failed to marshal message")
+		return err
+	}
+
+	err = p.publish(
+		ctx,
+		"",            // exchange
+		"email_queue", // routing key
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		log.Error().Err(err).Str("email", email).Msg("[EmailPublisher-SendNewDeviceSignInEmail] Failed to publish message")
+		return err
+	}
+
+	log.Info().Str("email", email).Msg("[EmailPublisher-SendNewDeviceSignInEmail] New device sign-in email sent to queue")
+	return nil
+}
+
+func (p *EmailPublisher) SendSignInOTPEmail(ctx context.Context, email, code string, expiresAt time.Time) error {
+	lang := p.recipientLanguage(ctx, email)
+
+	// Extract name from email (before @) or use default
+	name := i18n.T(lang, "email.default_name")
+	if atIndex := strings.Index(email, "@"); atIndex > 0 {
+		name = email[:atIndex]
+		// Capitalize first letter
+		if len(name) > 0 {
+			name = strings.ToUpper(name[:1]) + strings.ToLower(name[1:])
+		}
+	}
+
+	params := i18n.Params{"name": name, "code": code, "minutes": int(time.Until(expiresAt).Round(time.Minute).Minutes()), "signature": i18n.T(lang, "email.signature")}
+	message := EmailVerificationMessage{
+		Email:    email,
+		Type:     "signin_otp",
+		Name:     name,
+		Subject:  i18n.T(lang, "email.signin_otp.subject"),
+		Body:     i18n.T(lang, "email.signin_otp.body", params),
+		Language: lang,
+		Data:     emailData(params),
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		log.Error().Err(err).Msg("[EmailPublisher-SendSignInOTPEmail] Failed to marshal message")
+		return err
+	}
+
+	err = p.publish(
+		ctx,
+		"",            // exchange
+		"email_queue", // routing key
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		log.Error().Err(err).Str("email", email).Msg("[EmailPublisher-SendSignInOTPEmail] Failed to publish message")
+		return err
+	}
+
+	log.Info().Str("email", email).Msg("[EmailPublisher-SendSignInOTPEmail] Sign-in OTP email sent to queue")
+	return nil
+}
+
+func (p *EmailPublisher) SendAccountMergedEmail(ctx context.Context, email, mergedEmail, survivingEmail string) error {
+	lang := p.recipientLanguage(ctx, email)
+
+	// Extract name from email (before @) or use default
+	name := i18n.T(lang, "email.default_name")
+	if atIndex := strings.Index(email, "@"); atIndex > 0 {
+		name = email[:atIndex]
+		// Capitalize first letter
+		if len(name) > 0 {
+			name = strings.ToUpper(name[:1]) + strings.ToLower(name[1:])
+		}
+	}
+
+	params := i18n.Params{"name": name, "merged_email": mergedEmail, "surviving_email": survivingEmail, "signature": i18n.T(lang, "email.signature")}
+	message := EmailVerificationMessage{
+		Email:    email,
+		Type:     "account_merged",
+		Name:     name,
+		Subject:  i18n.T(lang, "email.account_merged.subject"),
+		Body:     i18n.T(lang, "email.account_merged.body", params),
+		Language: lang,
+		Data:     emailData(params),
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		log.Error().Err(err).Msg("[EmailPublisher-SendAccountMergedEmail] Failed to marshal message")
+		return err
+	}
+
+	err = p.publish(
+		ctx,
+		"",            // exchange
+		"email_queue", // routing key
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		log.Error().Err(err).Str("email", email).Msg("[EmailPublisher-SendAccountMergedEmail] Failed to publish message")
+		return err
+	}
+
+	log.Info().Str("email", email).Msg("[EmailPublisher-SendAccountMergedEmail] Account merged email sent to queue")
+	return nil
+}
+
+func (p *EmailPublisher) SendCustomerInviteEmail(ctx context.Context, email, name, token string) error {
+	lang := p.recipientLanguage(ctx, email)
+
+	// Imported customers have a name on file; fall back to the email prefix like the other emails
+	if name == "" {
+		name = i18n.T(lang, "email.default_name")
+		if atIndex := strings.Index(email, "@"); atIndex > 0 {
+			name = email[:atIndex]
+			// Capitalize first letter
+			if len(name) > 0 {
+				name = strings.ToUpper(name[:1]) + strings.ToLower(name[1:])
+			}
+		}
+	}
+
+	setPasswordLink := p.baseURL + "/api/v1/auth/reset-password?token=" + token
+
+	params := i18n.Params{"name": name, "link": setPasswordLink, "signature": i18n.T(lang, "email.signature")}
+	message := EmailVerificationMessage{
+		Email:    email,
+		Token:    token,
+		Type:     "customer_invite",
+		Name:     name,
+		Subject:  i18n.T(lang, "email.customer_invite.subject"),
+		Body:     i18n.T(lang, "email.customer_invite.body", params),
+		Language: lang,
+		Data:     emailData(params),
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		log.Error().Err(err).Msg("[EmailPublisher-SendCustomerInviteEmail] Failed to marshal message")
+		return err
+	}
+
+	err = p.publish(
+		ctx,
+		"",            // exchange
+		"email_queue", // routing key
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		log.Error().Err(err).Str("email", email).Msg("[EmailPublisher-SendCustomerInviteEmail] Failed to publish message")
+		return err
+	}
+
+	log.Info().Str("email", email).Msg("[EmailPublisher-SendCustomerInviteEmail] Customer invite email sent to queue")
+	return nil
+}
+
+// SendPhotoRemovedEmail tells the user why; an empty reason means the provider removed it
+func (p *EmailPublisher) SendPhotoRemovedEmail(ctx context.Context, email, name, reason string) error {
+	lang := p.recipientLanguage(ctx, email)
+
+	if reason == "" {
+		reason = i18n.T(lang, "email.photo_removed.default_reason")
+	}
+
+	if name == "" {
+		name = i18n.T(lang, "email.default_name")
+		if atIndex := strings.Index(email, "@"); atIndex > 0 {
+			name = email[:atIndex]
+			// Capitalize first letter
+			if len(name) > 0 {
+				name = strings.ToUpper(name[:1]) + strings.ToLower(name[1:])
+			}
+		}
+	}
+
+	params := i18n.Params{"name": name, "reason": reason, "signature": i18n.T(lang, "email.signature")}
+	message := EmailVerificationMessage{
+		Email:    email,
+		Type:     "photo_removed",
+		Name:     name,
+		Subject:  i18n.T(lang, "email.photo_removed.subject"),
+		Body:     i18n.T(lang, "email.photo_removed.body", params),
+		Language: lang,
+		Data:     emailData(params),
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		log.Error().Err(err).Msg("[EmailPublisher-SendPhotoRemovedEmail] Failed to marshal message")
+		return err
+	}
+
+	err = p.publish(
+		ctx,
+		"",            // exchange
+		"email_queue", // routing key
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		log.Error().Err(err).Str("email", email).Msg("[EmailPublisher-SendPhotoRemovedEmail] Failed to publish message")
+		return err
+	}
+
+	log.Info().Str("email", email).Msg("[EmailPublisher-SendPhotoRemovedEmail] Photo removed email sent to queue")
+	return nil
+}
+
+// SendSupportTicketUpdateEmail sends the status in the customer's language; Data carries ticket_id and
+// status so notification-service can render its own template
+func (p *EmailPublisher) SendSupportTicketUpdateEmail(ctx context.Context, email, name string, ticket *entity.SupportTicketEntity, note string) error {
+	lang := p.recipientLanguage(ctx, email)
+
+	if name == "" {
+		name = i18n.T(lang, "email.default_name")
+		if atIndex := strings.Index(email, "@"); atIndex > 0 {
+			name = email[:atIndex]
+			// Capitalize first letter
+			if len(name) > 0 {
+				name = strings.ToUpper(name[:1]) + strings.ToLower(name[1:])
+			}
+		}
+	}
+
+	noteBlock := ""
+	if note != "" {
+		noteBlock = i18n.T(lang, "email.support_ticket_update.note", i18n.Params{"note": note})
+	}
+
+	params := i18n.Params{
+		"name":         name,
+		"ticket_id":    ticket.ID,
+		"subject":      ticket.Subject,
+		"status":       ticket.Status,
+		"status_label": i18n.T(lang, "support.status."+ticket.Status),
+		"note":         note,
+		"note_block":   noteBlock,
+		"signature":    i18n.T(lang, "email.signature"),
+	}
+	message := EmailVerificationMessage{
+		Email:    email,
+		Type:     "support_ticket_update",
+		Name:     name,
+		Subject:  i18n.T(lang, "email.support_ticket_update.subject", params),
+		Body:     i18n.T(lang, "email.support_ticket_update.body", params),
+		Language: lang,
+		Data:     emailData(params),
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		log.Error().Err(err).Msg("[EmailPublisher-SendSupportTicketUpdateEmail] Failed to marshal message")
+		return err
+	}
+
+	err = p.publish(
+		ctx,
+		"",            // exchange
+		"email_queue", // routing key
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		log.Error().Err(err).Str("email", email).Int64("ticket_id", ticket.ID).Msg("[EmailPublisher-SendSupportTicketUpdateEmail] Failed to publish message")
+		return err
+	}
+
+	log.Info().Str("email", email).Int64("ticket_id", ticket.ID).Msg("[EmailPublisher-SendSupportTicketUpdateEmail] Support ticket update email sent to queue")
+	return nil
+}
+
+// SendCampaignEmail sends one segment member the campaign text as written by the admin.
+// Data carries campaign_id so notification-service can dedupe a batch that was retried.
+func (p *EmailPublisher) SendCampaignEmail(ctx context.Context, email, name string, campaign *entity.CampaignEntity) error {
+	lang := p.recipientLanguage(ctx, email)
+
+	if name == "" {
+		name = i18n.T(lang, "email.default_name")
+		if atIndex := strings.Index(email, "@"); atIndex > 0 {
+			name = email[:atIndex]
+			// Capitalize first letter
+			if len(name) > 0 {
+				name = strings.ToUpper(name[:1]) + strings.ToLower(name[1:])
+			}
+		}
+	}
+
+	data := make(map[string]string, len(campaign.Data)+3)
+	for key, value := range campaign.Data {
+		data[key] = value
+	}
+	data["name"] = name
+	data["campaign"] = campaign.Name
+	data["campaign_id"] = strconv.FormatInt(campaign.ID, 10)
+
+	personalize := strings.NewReplacer("{name}", name)
+	message := EmailVerificationMessage{
+		Email:    email,
+		Type:     "campaign",
+		Name:     name,
+		Subject:  personalize.Replace(campaign.Subject),
+		Body:     personalize.Replace(campaign.Body),
+		Language: lang,
+		Data:     data,
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		log.Error().Err(err).Msg("[EmailPublisher-SendCampaignEmail] Failed to marshal message")
+		return err
+	}
+
+	err = p.publish(
+		ctx,
+		"",            // exchange
+		"email_queue", // routing key
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        body,
+		},
+	)
+
+	if err != nil {
+		log.Error().Err(err).Str("email", email).Int64("campaign_id", campaign.ID).Msg("[EmailPublisher-SendCampaignEmail] Failed to publish message")
+		return err
+	}
+
+	log.Debug().Str("email", email).Int64("campaign_id", campaign.ID).Msg("[EmailPublisher-SendCampaignEmail] Campaign email sent to queue")
 	return nil
 }
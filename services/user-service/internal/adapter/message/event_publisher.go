@@ -0,0 +1,80 @@
+package message
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"user-service/internal/adapter/breaker"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"github.com/streadway/amqp"
+)
+
+// UserEventsExchange is a topic exchange; consumers bind queues by event type, e.g. "user.#"
+const UserEventsExchange = "user_events"
+
+type EventPublisher struct {
+	broker  *Broker
+	breaker *breaker.Breaker
+}
+
+// NewEventPublisher declares the exchange now and again after every reconnect
+func NewEventPublisher(broker *Broker, b *breaker.Breaker) port.EventPublisherInterface {
+	declareUserEventsExchange(broker.Channel())
+	broker.OnReconnect(func(ctx context.Context) {
+		declareUserEventsExchange(broker.Channel())
+	})
+
+	return &EventPublisher{
+		broker:  broker,
+		breaker: b,
+	}
+}
+
+func declareUserEventsExchange(channel *amqp.Channel) {
+	if channel == nil {
+		return
+	}
+	if err := channel.ExchangeDeclare(UserEventsExchange, "topic", true, false, false, false, nil); err != nil {
+		log.Error().Err(err).Msg("[EventPublisher] Failed to declare user events exchange")
+	}
+}
+
+func (p *EventPublisher) Publish(ctx context.Context, event *entity.EventEntity) error {
+	channel := p.broker.Channel()
+	if channel == nil {
+		return errors.New("rabbitmq not available")
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		log.Error().Err(err).Str("event", event.Type).Msg("[EventPublisher-Publish] Failed to marshal event")
+		return err
+	}
+
+	err = p.breaker.Execute(func() error {
+		return channel.Publish(
+			UserEventsExchange, // exchange
+			event.Type,         // routing key
+			false,              // mandatory
+			false,              // immediate
+			amqp.Publishing{
+				ContentType:  "application/json",
+				DeliveryMode: amqp.Persistent,
+				MessageId:    event.ID,
+				Timestamp:    event.OccurredAt,
+				Type:         event.Type,
+				Body:         body,
+			},
+		)
+	})
+	if err != nil {
+		log.Error().Err(err).Str("event", event.Type).Str("event_id", event.ID).Msg("[EventPublisher-Publish] Failed to publish event")
+		return err
+	}
+
+	log.Info().Str("event", event.Type).Str("event_id", event.ID).Msg("[EventPublisher-Publish] Event published")
+	return nil
+}
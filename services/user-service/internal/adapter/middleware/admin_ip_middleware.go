@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// AdminIPMiddleware rejects admin requests from networks outside the configured rules.
+// It runs before JWTMiddleware so stolen credentials are useless from unknown networks.
+// The client IP comes from Echo's IPExtractor, see TRUSTED_PROXIES.
+func AdminIPMiddleware(ipAccessService port.IPAccessServiceInterface) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ip := c.RealIP()
+
+			allowed, reason := ipAccessService.IsAllowed(c.Request().Context(), ip)
+			if !allowed {
+				log.Warn().Str("ip", ip).Str("reason", reason).Str("path", c.Request().URL.Path).Msg("[AdminIPMiddleware] Admin access blocked")
+				return c.JSON(http.StatusForbidden, map[string]interface{}{
+					"message": "Access denied",
+					"data":    nil,
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
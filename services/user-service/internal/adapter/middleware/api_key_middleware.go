@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"net/http"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// APIKeyContextKey holds the *entity.APIKeyEntity once a request's X-API-Key was authenticated,
+// so the rate limiter and APIKeyMiddleware look it up only once
+const APIKeyContextKey = "api_key"
+
+// APIKeyMiddleware authenticates partners by the X-API-Key header and sets an API key principal.
+// User JWTs and service tokens are not accepted on these routes.
+func APIKeyMiddleware(apiKeyService port.APIKeyServiceInterface) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			apiKey, ok := c.Get(APIKeyContextKey).(*entity.APIKeyEntity)
+			if !ok {
+				plainKey := c.Request().Header.Get(APIKeyHeader)
+				if plainKey == "" {
+					return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+						"message": "API key is required",
+						"data":    nil,
+					})
+				}
+
+				var err error
+				apiKey, err = apiKeyService.Authenticate(c.Request().Context(), plainKey)
+				if err != nil {
+					return apiKeyError(c, err)
+				}
+				c.Set(APIKeyContextKey, apiKey)
+			}
+
+			principal := entity.PrincipalEntity{
+				Type:     entity.PrincipalTypeAPIKey,
+				APIKeyID: apiKey.ID,
+				Partner:  apiKey.Name,
+				Scopes:   apiKey.Scopes,
+			}
+			c.Set(PrincipalContextKey, principal)
+			c.SetRequest(c.Request().WithContext(entity.ContextWithPrincipal(c.Request().Context(), principal)))
+			return next(c)
+		}
+	}
+}
+
+// RequireAPIKeyScope narrows a route inside an APIKeyMiddleware group to keys granted scope
+func RequireAPIKeyScope(scope string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			apiKey, ok := c.Get(APIKeyContextKey).(*entity.APIKeyEntity)
+			if !ok || !apiKey.HasScope(scope) {
+				log.Warn().Str("scope", scope).Str("path", c.Request().URL.Path).Msg("[RequireAPIKeyScope] API key lacks scope")
+				return c.JSON(http.StatusForbidden, map[string]interface{}{
+					"message": "API key lacks the required scope",
+					"data":    nil,
+				})
+			}
+			return next(c)
+		}
+	}
+}
+
+func apiKeyError(c echo.Context, err error) error {
+	switch err.Error() {
+	case "invalid api key", "api key revoked", "api key expired":
+		log.Warn().Err(err).Str("ip", c.RealIP()).Str("path", c.Request().URL.Path).Msg("[APIKeyMiddleware] API key rejected")
+		return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+			"message": "Invalid API key",
+			"data":    nil,
+		})
+	default:
+		log.Error().Err(err).Str("path", c.Request().URL.Path).Msg("[APIKeyMiddleware] Failed to authenticate API key")
+		return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{
+			"message": "Failed to authenticate API key",
+			"data":    nil,
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// BodyLimitMiddleware answers 413 when the declared body is over limit and caps what the
+// handler can read for bodies sent without a length. uploadRoutes are route patterns, e.g.
+// "/api/v1/uploads/:id/chunks/:index", that get uploadLimit instead.
+func BodyLimitMiddleware(limit, uploadLimit int64, uploadRoutes ...string) echo.MiddlewareFunc {
+	uploads := make(map[string]bool, len(uploadRoutes))
+	for _, route := range uploadRoutes {
+		uploads[route] = true
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			max := limit
+			if uploads[c.Path()] {
+				max = uploadLimit
+			}
+
+			req := c.Request()
+			if req.ContentLength > max {
+				log.Warn().Str("method", req.Method).Str("path", c.Path()).Int64("content_length", req.ContentLength).Int64("limit", max).Msg("[BodyLimitMiddleware] Request body too large")
+				return echo.ErrStatusRequestEntityTooLarge
+			}
+			req.Body = http.MaxBytesReader(c.Response(), req.Body, max)
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math/rand"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"user-service/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultMaxLoggedBodyBytes = 4096
+	// Response bytes kept for redaction; JSON must be complete to be parsed and redacted
+	maxCapturedBodyBytes = 64 << 10
+)
+
+// BodyLoggerConfig controls request/response body logging for the routes it is attached to
+type BodyLoggerConfig struct {
+	// SampleRate is the fraction of requests logged, 0 disables logging and 1 logs all
+	SampleRate float64
+	// MaxBodyBytes truncates each logged body after redaction
+	MaxBodyBytes int
+}
+
+// BodyLoggerMiddleware logs redacted request and response bodies. It is opt-in per route
+// because bodies are large and often personal; only JSON and form bodies are logged, and
+// password, token and Authorization-like fields are always redacted.
+func BodyLoggerMiddleware(config BodyLoggerConfig) echo.MiddlewareFunc {
+	if config.MaxBodyBytes <= 0 {
+		config.MaxBodyBytes = defaultMaxLoggedBodyBytes
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.SampleRate <= 0 || (config.SampleRate < 1 && rand.Float64() >= config.SampleRate) {
+				return next(c)
+			}
+
+			req := c.Request()
+			reqContentType := req.Header.Get(echo.HeaderContentType)
+
+			reqBody := ""
+			if req.Body != nil && req.ContentLength != 0 {
+				if isLoggableContentType(reqContentType) {
+					body, err := io.ReadAll(req.Body)
+					if err != nil {
+						log.Warn().Err(err).Msg("[BodyLoggerMiddleware] Failed to read request body")
+					}
+					req.Body = io.NopCloser(bytes.NewReader(body))
+					reqBody = formatBody(body, reqContentType, config.MaxBodyBytes)
+				} else {
+					reqBody = "[body omitted: " + reqContentType + "]"
+				}
+			}
+
+			resBuffer := &limitedBuffer{limit: maxCapturedBodyBytes}
+			writer := &bodyDumpResponseWriter{Writer: io.MultiWriter(c.Response().Writer, resBuffer), ResponseWriter: c.Response().Writer}
+			c.Response().Writer = writer
+
+			err := next(c)
+
+			headers := make(map[string]string, len(req.Header))
+			for name := range req.Header {
+				if utils.IsSensitiveKey(name) {
+					headers[name] = utils.RedactedValue
+					continue
+				}
+				headers[name] = req.Header.Get(name)
+			}
+
+			log.Info().
+				Str("method", req.Method).
+				Str("uri", utils.RedactURI(req.RequestURI)).
+				Int("status", c.Response().Status).
+				Interface("headers", headers).
+				Str("request_body", reqBody).
+				Str("response_body", formatBody(resBuffer.Bytes(), c.Response().Header().Get(echo.HeaderContentType), config.MaxBodyBytes)).
+				Msg("[BodyLoggerMiddleware] HTTP exchange")
+
+			return err
+		}
+	}
+}
+
+func isLoggableContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == echo.MIMEApplicationJSON || mediaType == echo.MIMEApplicationForm
+}
+
+// formatBody redacts then truncates; bodies that cannot be parsed are omitted rather than
+// logged raw, since an unparsed body may still contain secrets
+func formatBody(body []byte, contentType string, maxBytes int) string {
+	if len(body) == 0 {
+		return ""
+	}
+	if !isLoggableContentType(contentType) {
+		return "[body omitted: " + contentType + "]"
+	}
+
+	var redacted string
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == echo.MIMEApplicationForm {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return "[body omitted: unparseable]"
+		}
+		redacted = utils.RedactValues(values).Encode()
+	} else {
+		redactedJSON, ok := utils.RedactJSON(body)
+		if !ok {
+			return "[body omitted: unparseable]"
+		}
+		redacted = string(redactedJSON)
+	}
+
+	if len(redacted) > maxBytes {
+		return redacted[:maxBytes] + "...(truncated)"
+	}
+	return redacted
+}
+
+// limitedBuffer keeps at most limit bytes so huge responses don't balloon memory;
+// a cut-off JSON body fails to parse and is omitted from the log
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.Len(); remaining > 0 {
+		if len(p) > remaining {
+			b.Buffer.Write(p[:remaining])
+		} else {
+			b.Buffer.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+type bodyDumpResponseWriter struct {
+	io.Writer
+	http.ResponseWriter
+}
+
+func (w *bodyDumpResponseWriter) WriteHeader(code int) {
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *bodyDumpResponseWriter) Write(b []byte) (int, error) {
+	return w.Writer.Write(b)
+}
+
+func (w *bodyDumpResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *bodyDumpResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.ResponseWriter).Hijack()
+}
+
+func (w *bodyDumpResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -3,21 +3,38 @@ package middleware
 import (
 	"net/http"
 	"time"
+	"user-service/utils"
 
+	"github.com/hilmirazib/jualan-sayur/pkg/security"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 	"github.com/rs/zerolog/log"
 )
 
-func CORSMiddleware() echo.MiddlewareFunc {
+// CORSMiddleware allows any origin unless allowOrigins is set, in which case
+// credentials (the session cookie) are allowed for exactly those origins
+func CORSMiddleware(allowOrigins []string) echo.MiddlewareFunc {
+	origins := []string{"*"}
+	if len(allowOrigins) > 0 {
+		origins = allowOrigins
+	}
+
 	return middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins: []string{"*"}, 
-		AllowMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
-		MaxAge:       86400, // 24 hours
+		AllowOrigins:     origins,
+		AllowMethods:     []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete, http.MethodOptions},
+		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, echo.HeaderXRequestID, security.CSRFHeaderName, ClientTypeHeader, DeviceIDHeader, APIKeyHeader},
+		ExposeHeaders:    []string{echo.HeaderXRequestID, RateLimitLimitHeader, RateLimitRemainingHeader, RateLimitResetHeader, echo.HeaderRetryAfter},
+		AllowCredentials: len(allowOrigins) > 0,
+		MaxAge:           86400, // 24 hours
 	})
 }
 
+// RequestIDMiddleware keeps the client's X-Request-Id or generates one, and returns it in
+// the response so a report can be matched to the logs and the database's slow query log
+func RequestIDMiddleware() echo.MiddlewareFunc {
+	return middleware.RequestID()
+}
+
 // LoggerMiddleware creates custom logger middleware
 func LoggerMiddleware() echo.MiddlewareFunc {
 	return middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
@@ -27,21 +44,24 @@ func LoggerMiddleware() echo.MiddlewareFunc {
 		LogRemoteIP:  true,
 		LogUserAgent: true,
 		LogError:     true,
+		LogRequestID: true,
 		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
 			if v.Error != nil {
 				log.Error().
 					Err(v.Error).
 					Str("method", v.Method).
-					Str("uri", v.URI).
+					Str("uri", utils.RedactURI(v.URI)).
 					Int("status", v.Status).
+					Str("request_id", v.RequestID).
 					Str("remote_ip", v.RemoteIP).
 					Str("user_agent", v.UserAgent).
 					Msg("Request failed")
 			} else {
 				log.Info().
 					Str("method", v.Method).
-					Str("uri", v.URI).
+					Str("uri", utils.RedactURI(v.URI)).
 					Int("status", v.Status).
+					Str("request_id", v.RequestID).
 					Str("remote_ip", v.RemoteIP).
 					Dur("latency", time.Since(v.StartTime)).
 					Msg("Request completed")
@@ -68,34 +88,23 @@ func RecoveryMiddleware() echo.MiddlewareFunc {
 	})
 }
 
-// RateLimitMiddleware creates rate limiting middleware
-func RateLimitMiddleware() echo.MiddlewareFunc {
-	config := middleware.RateLimiterConfig{
-		Store: middleware.NewRateLimiterMemoryStoreWithConfig(
-			middleware.RateLimiterMemoryStoreConfig{
-				Rate:  10, // 10 requests per second
-				Burst: 30, // burst of 30 requests
-			},
-		),
-		IdentifierExtractor: func(ctx echo.Context) (string, error) {
-			id := ctx.RealIP()
-			return id, nil
-		},
-		ErrorHandler: func(context echo.Context, err error) error {
-			return context.JSON(http.StatusTooManyRequests, map[string]string{
-				"message": "Rate limit exceeded",
-			})
-		},
-		DenyHandler: func(context echo.Context, identifier string, err error) error {
-			return context.JSON(http.StatusTooManyRequests, map[string]string{
-				"message": "Rate limit exceeded",
-			})
-		},
+// UserRolesContextKey holds the user's role followed by the roles it inherits from
+const UserRolesContextKey = "user_roles"
+
+// hasRole reports whether the user's role is role or inherits from it
+func hasRole(c echo.Context, userRole, role string) bool {
+	if roles, ok := c.Get(UserRolesContextKey).([]string); ok {
+		for _, r := range roles {
+			if r == role {
+				return true
+			}
+		}
+		return false
 	}
-	return middleware.RateLimiterWithConfig(config)
+	return userRole == role
 }
 
-// SuperAdminMiddleware checks if the user has Super Admin role
+// SuperAdminMiddleware checks if the user has Super Admin role, directly or by inheritance
 func SuperAdminMiddleware() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
@@ -108,7 +117,7 @@ func SuperAdminMiddleware() echo.MiddlewareFunc {
 				})
 			}
 
-			if userRole != "Super Admin" {
+			if !hasRole(c, userRole, "Super Admin") {
 				log.Warn().Str("user_role", userRole).Msg("[SuperAdminMiddleware] User is not Super Admin")
 				return c.JSON(http.StatusForbidden, map[string]interface{}{
 					"message": "Access denied",
@@ -121,3 +130,31 @@ func SuperAdminMiddleware() echo.MiddlewareFunc {
 		}
 	}
 }
+
+// RoleMiddleware allows the request only when the user's role is, or inherits from, one of roles
+func RoleMiddleware(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userRole, exists := c.Get("user_role").(string)
+			if !exists {
+				log.Warn().Msg("[RoleMiddleware] User role not found in context")
+				return c.JSON(http.StatusForbidden, map[string]interface{}{
+					"message": "Access denied",
+					"data":    nil,
+				})
+			}
+
+			for _, role := range roles {
+				if hasRole(c, userRole, role) {
+					return next(c)
+				}
+			}
+
+			log.Warn().Str("user_role", userRole).Strs("allowed_roles", roles).Msg("[RoleMiddleware] Role not allowed")
+			return c.JSON(http.StatusForbidden, map[string]interface{}{
+				"message": "Access denied",
+				"data":    nil,
+			})
+		}
+	}
+}
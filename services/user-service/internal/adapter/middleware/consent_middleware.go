@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// ConsentMiddleware answers 428 until the user has accepted the current terms of service
+// and privacy policy. Place it after JWTMiddleware. Clients show the documents named in the
+// response and send POST /api/v1/auth/consent, which is not behind this middleware.
+func ConsentMiddleware(legalService port.LegalServiceInterface) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, ok := c.Get("user_id").(int64)
+			if !ok {
+				return next(c)
+			}
+
+			status, err := legalService.ConsentStatus(c.Request().Context(), userID)
+			if err != nil {
+				// Fail open: a database hiccup should not lock every user out
+				log.Warn().Err(err).Int64("user_id", userID).Msg("[ConsentMiddleware] Failed to check consent")
+				return next(c)
+			}
+			if !status.Required() {
+				return next(c)
+			}
+
+			data := map[string]interface{}{"consent_required": true}
+			if status.CurrentTerms != nil {
+				data["terms_version"] = status.CurrentTerms.Version
+				data["terms_url"] = status.CurrentTerms.URL
+			}
+			if status.CurrentPrivacy != nil {
+				data["privacy_version"] = status.CurrentPrivacy.Version
+				data["privacy_url"] = status.CurrentPrivacy.URL
+			}
+
+			log.Info().Int64("user_id", userID).Str("path", c.Path()).Msg("[ConsentMiddleware] Consent to current terms required")
+			return c.JSON(http.StatusPreconditionRequired, map[string]interface{}{
+				"message": "Please accept the updated terms of service and privacy policy to continue",
+				"data":    data,
+			})
+		}
+	}
+}
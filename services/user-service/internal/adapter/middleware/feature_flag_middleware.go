@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// FeatureFlagMiddleware hides a route behind a flag. Disabled routes answer 404 so
+// unreleased endpoints are indistinguishable from missing ones. Place it after
+// JWTMiddleware to evaluate per-user overrides and rollouts.
+func FeatureFlagMiddleware(flagService port.FeatureFlagServiceInterface, key string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !FeatureEnabled(c, flagService, key) {
+				log.Info().Str("flag", key).Str("path", c.Path()).Msg("[FeatureFlagMiddleware] Feature disabled for request")
+				return c.JSON(http.StatusNotFound, map[string]interface{}{
+					"message": "Not found",
+					"data":    nil,
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// FeatureEnabled evaluates a flag for the current request's user (anonymous if none),
+// for handlers that branch on a flag instead of hiding the whole route
+func FeatureEnabled(c echo.Context, flagService port.FeatureFlagServiceInterface, key string) bool {
+	userID, _ := c.Get("user_id").(int64)
+	return flagService.IsEnabled(c.Request().Context(), key, userID)
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"user-service/internal/adapter/handler/response"
+	"user-service/utils/i18n"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	headerAcceptLanguage  = "Accept-Language"
+	headerContentLanguage = "Content-Language"
+)
+
+// LanguageMiddleware negotiates the response language from Accept-Language and stores it
+// on the request context, where the JSON serializer, the validator and the email
+// publisher pick it up. Register it before anything that can write a response.
+func LanguageMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			lang := i18n.Negotiate(c.Request().Header.Get(headerAcceptLanguage))
+
+			c.Set("lang", lang)
+			c.SetRequest(c.Request().WithContext(i18n.WithLanguage(c.Request().Context(), lang)))
+
+			c.Response().Header().Set(headerContentLanguage, lang)
+			c.Response().Header().Add(echo.HeaderVary, headerAcceptLanguage)
+
+			return next(c)
+		}
+	}
+}
+
+// LocalizedJSONSerializer translates the top-level "message" of every JSON response into
+// the request language. Handlers keep writing English messages, which are the catalog keys.
+type LocalizedJSONSerializer struct {
+	echo.DefaultJSONSerializer
+}
+
+func (s LocalizedJSONSerializer) Serialize(c echo.Context, i interface{}, indent string) error {
+	return s.DefaultJSONSerializer.Serialize(c, localizeMessage(i18n.FromContext(c.Request().Context()), i), indent)
+}
+
+// localizeMessage returns a translated copy, so a response value shared between requests
+// is never changed in place
+func localizeMessage(lang string, i interface{}) interface{} {
+	switch v := i.(type) {
+	case response.DefaultResponse:
+		v.Message = i18n.T(lang, v.Message)
+		return v
+	case *response.DefaultResponse:
+		if v == nil {
+			return v
+		}
+		localized := *v
+		localized.Message = i18n.T(lang, v.Message)
+		return localized
+	case map[string]interface{}:
+		return localizeMap(lang, v)
+	case echo.Map:
+		// echo's own HTTP errors, such as 404 and 405, are written as echo.Map
+		return localizeMap(lang, v)
+	default:
+		return i
+	}
+}
+
+func localizeMap(lang string, m map[string]interface{}) map[string]interface{} {
+	message, ok := m["message"].(string)
+	if !ok {
+		return m
+	}
+
+	localized := make(map[string]interface{}, len(m))
+	for key, value := range m {
+		localized[key] = value
+	}
+	localized["message"] = i18n.T(lang, message)
+	return localized
+}
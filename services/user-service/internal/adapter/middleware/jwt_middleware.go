@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strings"
 	"user-service/config"
+	"user-service/internal/core/domain/entity"
 	"user-service/internal/core/port"
 	"user-service/utils"
 
@@ -13,32 +14,40 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-// JWTMiddleware creates JWT authentication middleware with Redis session validation and blacklist check
-func JWTMiddleware(cfg *config.Config, sessionRepo port.SessionInterface, blacklistRepo port.BlacklistTokenInterface) echo.MiddlewareFunc {
+// JWTMiddleware creates JWT authentication middleware with Redis session validation and blacklist check.
+// With roles set, the roles the user's role inherits from are resolved for RoleMiddleware and
+// SuperAdminMiddleware. With passwords set, staff sessions end once the password policy expires
+// the password they were signed in with.
+func JWTMiddleware(cfg *config.Config, sessionRepo port.SessionInterface, blacklistRepo port.BlacklistTokenInterface, roles port.RoleResolverInterface, passwords port.PasswordExpiryCheckerInterface) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			// Get token from Authorization header
+			// Get token from Authorization header, falling back to the web session cookie
+			var tokenString string
 			authHeader := c.Request().Header.Get("Authorization")
 			if authHeader == "" {
-				log.Warn().Msg("[JWTMiddleware] Missing authorization header")
-				return c.JSON(http.StatusUnauthorized, map[string]interface{}{
-					"message": "Authorization header required",
-					"data":    nil,
-				})
-			}
+				cookie, err := c.Cookie(cfg.Security.SessionCookieName)
+				if !cfg.Security.SessionCookieEnabled || err != nil || cookie.Value == "" {
+					log.Warn().Msg("[JWTMiddleware] Missing authorization header")
+					return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+						"message": "Authorization header required",
+						"data":    nil,
+					})
+				}
+				tokenString = cookie.Value
+			} else {
+				// Check Bearer token format
+				tokenParts := strings.Split(authHeader, " ")
+				if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+					log.Warn().Str("auth_header_fingerprint", utils.SecretFingerprint(authHeader)).Msg("[JWTMiddleware] Invalid authorization header format")
+					return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+						"message": "Invalid authorization header format. Use: Bearer <token>",
+						"data":    nil,
+					})
+				}
 
-			// Check Bearer token format
-			tokenParts := strings.Split(authHeader, " ")
-			if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-				log.Warn().Str("auth_header", authHeader).Msg("[JWTMiddleware] Invalid authorization header format")
-				return c.JSON(http.StatusUnauthorized, map[string]interface{}{
-					"message": "Invalid authorization header format. Use: Bearer <token>",
-					"data":    nil,
-				})
+				tokenString = tokenParts[1]
 			}
 
-			tokenString := tokenParts[1]
-
 			// Validate JWT signature first
 			claims, err := utils.ValidateJWT(cfg, tokenString)
 			if err != nil {
@@ -86,12 +95,34 @@ func JWTMiddleware(cfg *config.Config, sessionRepo port.SessionInterface, blackl
 					Msg("[JWTMiddleware] Token without session_id (backward compatibility)")
 			}
 
+			if passwords != nil && passwords.PasswordExpired(c.Request().Context(), claims.UserID, claims.SessionID, claims.RoleName) {
+				log.Warn().
+					Int64("user_id", claims.UserID).
+					Str("session_id", claims.SessionID).
+					Msg("[JWTMiddleware] Password expired since sign-in")
+				return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+					"message": "Your password has expired. Please set a new one with forgot password.",
+					"data":    nil,
+				})
+			}
+
 			// Set user information in context
 			c.Set("user_id", claims.UserID)
 			c.Set("user_email", claims.Email)
 			c.Set("user_role", claims.RoleName)
+			if roles != nil {
+				c.Set(UserRolesContextKey, roles.EffectiveRoles(c.Request().Context(), claims.RoleName))
+			}
 			c.Set("session_id", claims.SessionID)
 			c.Set("exp", claims.ExpiresAt.Unix()) // Set expiration time for logout
+			c.Set("access_token", tokenString)
+			principal := entity.PrincipalEntity{
+				Type:   entity.PrincipalTypeUser,
+				UserID: claims.UserID,
+				Role:   claims.RoleName,
+			}
+			c.Set(PrincipalContextKey, principal)
+			c.SetRequest(c.Request().WithContext(entity.ContextWithPrincipal(c.Request().Context(), principal)))
 
 			log.Info().
 				Int64("user_id", claims.UserID).
@@ -135,6 +166,13 @@ func OptionalJWTMiddleware(cfg *config.Config) echo.MiddlewareFunc {
 			c.Set("user_id", claims.UserID)
 			c.Set("user_email", claims.Email)
 			c.Set("user_role", claims.RoleName)
+			principal := entity.PrincipalEntity{
+				Type:   entity.PrincipalTypeUser,
+				UserID: claims.UserID,
+				Role:   claims.RoleName,
+			}
+			c.Set(PrincipalContextKey, principal)
+			c.SetRequest(c.Request().WithContext(entity.ContextWithPrincipal(c.Request().Context(), principal)))
 
 			return next(c)
 		}
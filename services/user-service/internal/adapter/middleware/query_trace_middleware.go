@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"user-service/utils/querytrace"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+)
+
+// maxTracedRequestIDLength keeps a client-chosen X-Request-Id from bloating every statement
+const maxTracedRequestIDLength = 64
+
+// unmatchedRoute labels requests no route matched, so 404 scans share one series
+const unmatchedRoute = "unmatched"
+
+var (
+	requestQueries = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_db_queries",
+		Help:    "SQL statements run per HTTP request, by route.",
+		Buckets: []float64{0, 1, 2, 5, 10, 20, 50, 100},
+	}, []string{"method", "route"})
+	requestQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_db_duration_seconds",
+		Help:    "Time spent in the database per HTTP request, summed over its statements, by route.",
+		Buckets: []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5},
+	}, []string{"method", "route"})
+)
+
+// QueryTraceMiddleware puts a querytrace.Trace on the request context for the GORM query
+// tracing plugin, then records how many statements the request ran and how long they took.
+// Requests running more than warnQueries statements are logged. Register it after
+// RequestIDMiddleware and before TimeoutMiddleware.
+func QueryTraceMiddleware(warnQueries int) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			route := c.Path()
+			if route == "" {
+				route = unmatchedRoute
+			}
+			requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+			if len(requestID) > maxTracedRequestIDLength {
+				requestID = requestID[:maxTracedRequestIDLength]
+			}
+
+			trace := querytrace.New(c.Request().Method, route, requestID)
+			c.SetRequest(c.Request().WithContext(querytrace.WithTrace(c.Request().Context(), trace)))
+
+			err := next(c)
+
+			queries := trace.Queries()
+			requestQueries.WithLabelValues(trace.Method, route).Observe(float64(queries))
+			requestQueryDuration.WithLabelValues(trace.Method, route).Observe(trace.Duration().Seconds())
+
+			if warnQueries > 0 && queries > int64(warnQueries) {
+				log.Warn().
+					Str("method", trace.Method).
+					Str("route", route).
+					Str("request_id", requestID).
+					Int64("queries", queries).
+					Dur("db_time", trace.Duration()).
+					Msg("[QueryTraceMiddleware] Request ran many SQL statements")
+			}
+			return err
+		}
+	}
+}
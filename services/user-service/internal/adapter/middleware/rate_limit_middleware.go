@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	// RateLimitResetHeader is the Unix time in seconds at which the current window ends
+	RateLimitResetHeader = "X-RateLimit-Reset"
+
+	// APIKeyHeader identifies a partner integration, which is counted against its own quota
+	// instead of the per-IP limit
+	APIKeyHeader = "X-API-Key"
+	// PartnerContextKey holds the partner name for requests made with a partner API key
+	PartnerContextKey = "partner"
+)
+
+// RateLimitMiddleware counts requests in Redis and reports the caller's quota in X-RateLimit-*
+// headers on every response. Anonymous and user traffic is limited per client IP and only
+// rejected in enforce mode; partner API keys, from PARTNER_API_KEYS or issued through apiKeys,
+// always get a 429 once their quota is used. When Redis is unavailable requests pass without
+// quota headers.
+func RateLimitMiddleware(limiter port.RateLimitRepositoryInterface, apiKeys port.APIKeyServiceInterface, cfg config.RateLimit) echo.MiddlewareFunc {
+	if cfg.Mode == config.RateLimitModeOff {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return next
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipRateLimit(c.Request().URL.Path) {
+				return next(c)
+			}
+
+			bucket, limit, window := "ip:"+c.RealIP(), cfg.Limit(), cfg.Window()
+			enforce := cfg.Mode == config.RateLimitModeEnforce
+
+			if plainKey := c.Request().Header.Get(APIKeyHeader); plainKey != "" {
+				if quota, ok := matchPartnerKey(cfg.PartnerQuotas, plainKey); ok {
+					bucket, limit, window, enforce = "partner:"+quota.Partner, quota.Requests, cfg.PartnerWindow(), true
+					c.Set(PartnerContextKey, quota.Partner)
+				} else if apiKeys != nil {
+					apiKey, err := apiKeys.Authenticate(c.Request().Context(), plainKey)
+					if err != nil {
+						return apiKeyError(c, err)
+					}
+					c.Set(APIKeyContextKey, apiKey)
+					c.Set(PartnerContextKey, apiKey.Name)
+
+					limit = apiKey.Quota
+					if limit == 0 {
+						limit = cfg.DefaultPartnerQuota()
+					}
+					bucket, window, enforce = "api_key:"+strconv.FormatInt(apiKey.ID, 10), cfg.PartnerWindow(), true
+				} else {
+					log.Warn().Str("ip", c.RealIP()).Str("path", c.Request().URL.Path).Msg("[RateLimitMiddleware] Invalid partner API key")
+					return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+						"message": "Invalid API key",
+						"data":    nil,
+					})
+				}
+			}
+
+			state, err := limiter.Hit(c.Request().Context(), bucket, limit, window)
+			if err != nil {
+				log.Warn().Err(err).Str("bucket", bucket).Msg("[RateLimitMiddleware] Rate limiter unavailable, request not counted")
+				return next(c)
+			}
+
+			header := c.Response().Header()
+			header.Set(RateLimitLimitHeader, strconv.Itoa(state.Limit))
+			header.Set(RateLimitRemainingHeader, strconv.Itoa(state.Remaining()))
+			header.Set(RateLimitResetHeader, strconv.FormatInt(state.ResetAt.Unix(), 10))
+
+			if state.Exceeded() {
+				if enforce {
+					retryAfter := int(math.Ceil(time.Until(state.ResetAt).Seconds()))
+					if retryAfter < 1 {
+						retryAfter = 1
+					}
+					header.Set(echo.HeaderRetryAfter, strconv.Itoa(retryAfter))
+					return c.JSON(http.StatusTooManyRequests, map[string]interface{}{
+						"message": "Rate limit exceeded",
+						"data":    nil,
+					})
+				}
+				log.Warn().Str("bucket", bucket).Int64("count", state.Count).Int("limit", state.Limit).Msg("[RateLimitMiddleware] Soft rate limit exceeded")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// skipRateLimit leaves probes, metrics scrapes and service-to-service calls uncounted
+func skipRateLimit(path string) bool {
+	switch path {
+	case "/health", "/metrics", "/version":
+		return true
+	}
+	return strings.HasPrefix(path, "/internal/")
+}
+
+// matchPartnerKey compares against every key so timing does not reveal which partner matched
+func matchPartnerKey(quotas map[string]config.PartnerQuota, apiKey string) (config.PartnerQuota, bool) {
+	var matched config.PartnerQuota
+	found := false
+	for key, quota := range quotas {
+		if subtle.ConstantTimeCompare([]byte(apiKey), []byte(key)) == 1 {
+			matched, found = quota, true
+		}
+	}
+	return matched, found
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// SCIMContentType is the media type of SCIM requests and responses (RFC 7644)
+const SCIMContentType = "application/scim+json"
+
+// SCIMAuthMiddleware authenticates identity providers by an API key granted scim:provision,
+// sent as "Authorization: Bearer <key>" because that is all SCIM clients support. Errors use
+// the SCIM error schema.
+func SCIMAuthMiddleware(apiKeyService port.APIKeyServiceInterface) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			plainKey, ok := strings.CutPrefix(c.Request().Header.Get(echo.HeaderAuthorization), "Bearer ")
+			if !ok || plainKey == "" {
+				return SCIMError(c, http.StatusUnauthorized, "", "Bearer token is required")
+			}
+
+			apiKey, err := apiKeyService.Authenticate(c.Request().Context(), plainKey)
+			if err != nil {
+				switch err.Error() {
+				case "invalid api key", "api key revoked", "api key expired":
+					log.Warn().Err(err).Str("ip", c.RealIP()).Msg("[SCIMAuthMiddleware] API key rejected")
+					return SCIMError(c, http.StatusUnauthorized, "", "Invalid bearer token")
+				default:
+					log.Error().Err(err).Msg("[SCIMAuthMiddleware] Failed to authenticate API key")
+					return SCIMError(c, http.StatusServiceUnavailable, "", "Failed to authenticate bearer token")
+				}
+			}
+			if !apiKey.HasScope(entity.APIKeyScopeSCIMProvision) {
+				log.Warn().Int64("api_key_id", apiKey.ID).Msg("[SCIMAuthMiddleware] API key lacks the scim:provision scope")
+				return SCIMError(c, http.StatusForbidden, "", "API key lacks the scim:provision scope")
+			}
+
+			principal := entity.PrincipalEntity{
+				Type:     entity.PrincipalTypeAPIKey,
+				APIKeyID: apiKey.ID,
+				Partner:  apiKey.Name,
+				Scopes:   apiKey.Scopes,
+			}
+			c.Set(APIKeyContextKey, apiKey)
+			c.Set(PrincipalContextKey, principal)
+			c.SetRequest(c.Request().WithContext(entity.ContextWithPrincipal(c.Request().Context(), principal)))
+			return next(c)
+		}
+	}
+}
+
+// SCIMError writes an error in the SCIM error schema; scimType is empty for errors without one
+func SCIMError(c echo.Context, status int, scimType, detail string) error {
+	body := map[string]interface{}{
+		"schemas": []string{"urn:ietf:params:scim:api:messages:2.0:Error"},
+		"status":  strconv.Itoa(status),
+		"detail":  detail,
+	}
+	if scimType != "" {
+		body["scimType"] = scimType
+	}
+	c.Response().Header().Set(echo.HeaderContentType, SCIMContentType)
+	return c.JSON(status, body)
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"user-service/config"
+
+	"github.com/hilmirazib/jualan-sayur/pkg/security"
+)
+
+// CSRFTokenPath issues a token even before a session cookie exists
+const CSRFTokenPath = "/api/v1/auth/csrf-token"
+
+// csrfExemptPaths never rely on the session cookie: service keys, SCIM tokens, partner API keys,
+// signed storage webhooks, and the SSO callback, which the identity provider may post cross-site
+// and which checks its own state cookie
+var csrfExemptPaths = []string{
+	"/internal/",
+	"/scim/v2/",
+	"/api/v1/partner/",
+	"/api/v1/webhooks/",
+	"/api/v1/auth/sso/callback",
+}
+
+// SecurityConfig maps the service config onto the shared security headers and CSRF middleware
+func SecurityConfig(cfg config.Security) security.Config {
+	return security.Config{
+		HSTSMaxAge:        cfg.HSTSMaxAge,
+		FrameOptions:      cfg.FrameOptions,
+		ReferrerPolicy:    cfg.ReferrerPolicy,
+		CSRFEnabled:       cfg.CSRFEnabled,
+		SessionCookieName: cfg.SessionCookieName,
+		CookieSecure:      cfg.CookieSecure,
+		CSRFTokenPath:     CSRFTokenPath,
+		CSRFExemptPaths:   csrfExemptPaths,
+	}
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	ServiceTokenHeader = "X-Service-Token"
+	// PrincipalContextKey holds the entity.PrincipalEntity for the authenticated caller
+	PrincipalContextKey = "principal"
+
+	minServiceKeyLength = 32
+)
+
+// ServiceAuthMiddleware authenticates other services by API key and sets a service principal.
+// With allowedServices only those callers pass; user JWTs are never accepted here.
+func ServiceAuthMiddleware(cfg *config.Config, allowedServices ...string) echo.MiddlewareFunc {
+	for name, keys := range cfg.InternalAuth.ServiceKeys {
+		for _, key := range keys {
+			if len(key) < minServiceKeyLength {
+				log.Warn().Str("service", name).Msg("[ServiceAuthMiddleware] Service API key is shorter than 32 characters")
+			}
+		}
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if len(cfg.InternalAuth.ServiceKeys) == 0 {
+				log.Warn().Str("path", c.Request().URL.Path).Msg("[ServiceAuthMiddleware] Internal API called but SERVICE_API_KEYS is not set")
+				return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{
+					"message": "Internal API is not configured",
+					"data":    nil,
+				})
+			}
+
+			serviceName, ok := matchServiceKey(cfg.InternalAuth.ServiceKeys, c.Request().Header.Get(ServiceTokenHeader))
+			if !ok {
+				log.Warn().Str("ip", c.RealIP()).Str("path", c.Request().URL.Path).Msg("[ServiceAuthMiddleware] Invalid service token")
+				return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+					"message": "Invalid service token",
+					"data":    nil,
+				})
+			}
+
+			if len(allowedServices) > 0 && !containsString(allowedServices, serviceName) {
+				log.Warn().Str("service", serviceName).Str("path", c.Request().URL.Path).Msg("[ServiceAuthMiddleware] Service not allowed on this endpoint")
+				return c.JSON(http.StatusForbidden, map[string]interface{}{
+					"message": "Access denied",
+					"data":    nil,
+				})
+			}
+
+			principal := entity.PrincipalEntity{
+				Type:        entity.PrincipalTypeService,
+				ServiceName: serviceName,
+			}
+			c.Set(PrincipalContextKey, principal)
+			c.SetRequest(c.Request().WithContext(entity.ContextWithPrincipal(c.Request().Context(), principal)))
+			return next(c)
+		}
+	}
+}
+
+// RequireServices narrows a route inside a ServiceAuthMiddleware group to the named services
+func RequireServices(services ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal, ok := GetPrincipal(c)
+			if !ok || !principal.IsService() || !containsString(services, principal.ServiceName) {
+				log.Warn().Str("service", principal.ServiceName).Str("path", c.Request().URL.Path).Msg("[RequireServices] Service not allowed on this endpoint")
+				return c.JSON(http.StatusForbidden, map[string]interface{}{
+					"message": "Access denied",
+					"data":    nil,
+				})
+			}
+			return next(c)
+		}
+	}
+}
+
+// GetPrincipal returns the caller set by JWTMiddleware or ServiceAuthMiddleware
+func GetPrincipal(c echo.Context) (entity.PrincipalEntity, bool) {
+	principal, ok := c.Get(PrincipalContextKey).(entity.PrincipalEntity)
+	return principal, ok
+}
+
+// matchServiceKey compares against every key so timing does not reveal which service matched
+func matchServiceKey(serviceKeys map[string][]string, token string) (string, bool) {
+	if token == "" {
+		return "", false
+	}
+
+	matched := ""
+	for name, keys := range serviceKeys {
+		for _, key := range keys {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(key)) == 1 {
+				matched = name
+			}
+		}
+	}
+	return matched, matched != ""
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
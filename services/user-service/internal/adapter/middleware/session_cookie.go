@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"user-service/config"
+
+	"github.com/hilmirazib/jualan-sayur/pkg/security"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// ClientTypeHeader selects the session mode; web clients get an httpOnly cookie instead of a token
+	ClientTypeHeader = "X-Client-Type"
+	ClientTypeWeb    = "web"
+
+	// DeviceIDHeader carries an optional stable device identifier sent by mobile apps
+	DeviceIDHeader = "X-Device-ID"
+
+	sessionCookiePath = "/api/v1"
+
+	// SSOStateCookieName binds a pending SSO sign-in to the browser that started it
+	SSOStateCookieName = "sso_state"
+	ssoStateCookiePath = "/api/v1/auth/sso"
+)
+
+// UsesCookieSession reports whether the response should carry the session cookie
+func UsesCookieSession(c echo.Context, cfg config.Security) bool {
+	return cfg.SessionCookieEnabled && strings.EqualFold(c.Request().Header.Get(ClientTypeHeader), ClientTypeWeb)
+}
+
+// SetSessionCookie stores the JWT in an httpOnly cookie scoped to the API. lifetime is what is
+// left of the token, so a remember-me session survives a browser restart and a short one does not
+// outlive its token.
+func SetSessionCookie(c echo.Context, cfg config.Security, token string, lifetime time.Duration) {
+	c.SetCookie(&http.Cookie{
+		Name:     cfg.SessionCookieName,
+		Value:    token,
+		Path:     sessionCookiePath,
+		Domain:   cfg.SessionCookieDomain,
+		MaxAge:   int(lifetime.Seconds()),
+		Secure:   cfg.CookieSecure,
+		HttpOnly: true,
+		SameSite: sameSiteMode(cfg.SessionCookieSameSite),
+	})
+}
+
+// SetCSRFCookie issues a double-submit token alongside a new session; security.CSRFMiddleware validates it
+func SetCSRFCookie(c echo.Context, cfg config.Security, token string, lifetime time.Duration) {
+	c.SetCookie(&http.Cookie{
+		Name:     security.CSRFCookieName,
+		Value:    token,
+		Path:     "/",
+		Domain:   cfg.SessionCookieDomain,
+		MaxAge:   int(lifetime.Seconds()),
+		Secure:   cfg.CookieSecure,
+		HttpOnly: false,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// ClearSessionCookies expires the session and CSRF cookies on logout
+func ClearSessionCookies(c echo.Context, cfg config.Security) {
+	for _, cookie := range []*http.Cookie{
+		{Name: cfg.SessionCookieName, Path: sessionCookiePath, HttpOnly: true},
+		{Name: security.CSRFCookieName, Path: "/"},
+	} {
+		cookie.Domain = cfg.SessionCookieDomain
+		cookie.MaxAge = -1
+		cookie.Secure = cfg.CookieSecure
+		c.SetCookie(cookie)
+	}
+}
+
+// SetSSOStateCookie remembers the state of the SSO sign-in this browser started, so a callback
+// carrying someone else's state (login CSRF) is refused
+func SetSSOStateCookie(c echo.Context, cfg config.Security, state string, lifetime time.Duration) {
+	c.SetCookie(&http.Cookie{
+		Name:     SSOStateCookieName,
+		Value:    state,
+		Path:     ssoStateCookiePath,
+		Domain:   cfg.SessionCookieDomain,
+		MaxAge:   int(lifetime.Seconds()),
+		Secure:   cfg.CookieSecure,
+		HttpOnly: true,
+		SameSite: sameSiteMode(cfg.SessionCookieSameSite),
+	})
+}
+
+// ClearSSOStateCookie expires the state cookie once the callback used it
+func ClearSSOStateCookie(c echo.Context, cfg config.Security) {
+	c.SetCookie(&http.Cookie{
+		Name:     SSOStateCookieName,
+		Path:     ssoStateCookiePath,
+		Domain:   cfg.SessionCookieDomain,
+		MaxAge:   -1,
+		Secure:   cfg.CookieSecure,
+		HttpOnly: true,
+	})
+}
+
+func sameSiteMode(value string) http.SameSite {
+	switch strings.ToLower(value) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
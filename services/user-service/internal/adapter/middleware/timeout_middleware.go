@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// TimeoutMiddleware puts a deadline on the request context, so every repository, storage
+// and Redis call made with it is cancelled once the request has run too long. Multipart
+// requests and raw upload chunks (application/octet-stream) get uploadTimeout instead.
+// When the deadline is hit before the handler wrote a response, the client gets a 503
+// rather than whatever error bubbled up.
+func TimeoutMiddleware(timeout, uploadTimeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			limit := timeout
+			contentType := c.Request().Header.Get(echo.HeaderContentType)
+			if strings.HasPrefix(contentType, echo.MIMEMultipartForm) || strings.HasPrefix(contentType, echo.MIMEOctetStream) {
+				limit = uploadTimeout
+			}
+
+			ctx, cancel := context.WithTimeout(c.Request().Context(), limit)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			err := next(c)
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return err
+			}
+
+			log.Warn().Str("method", c.Request().Method).Str("path", c.Path()).Dur("timeout", limit).Msg("[TimeoutMiddleware] Request deadline exceeded")
+			if c.Response().Committed {
+				return err
+			}
+			return c.JSON(http.StatusServiceUnavailable, map[string]interface{}{
+				"message": "Request timed out, please try again",
+				"data":    nil,
+			})
+		}
+	}
+}
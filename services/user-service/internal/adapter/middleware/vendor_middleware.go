@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"user-service/internal/core/port"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog/log"
+)
+
+// VendorScopeMiddleware resolves the approved vendor of the authenticated user and sets
+// vendor_id in context, so vendor-owned resources (products, orders) can be scoped to it.
+// Must run after JWTMiddleware and RoleMiddleware("Vendor").
+func VendorScopeMiddleware(vendorService port.VendorServiceInterface) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, ok := c.Get("user_id").(int64)
+			if !ok {
+				log.Warn().Msg("[VendorScopeMiddleware] User ID not found in context")
+				return c.JSON(http.StatusUnauthorized, map[string]interface{}{
+					"message": "Unauthorized",
+					"data":    nil,
+				})
+			}
+
+			vendor, err := vendorService.GetApprovedVendorByUserID(c.Request().Context(), userID)
+			if err != nil {
+				log.Warn().Err(err).Int64("user_id", userID).Msg("[VendorScopeMiddleware] No approved vendor for user")
+				return c.JSON(http.StatusForbidden, map[string]interface{}{
+					"message": "Vendor account is not approved",
+					"data":    nil,
+				})
+			}
+
+			c.Set("vendor_id", vendor.ID)
+			return next(c)
+		}
+	}
+}
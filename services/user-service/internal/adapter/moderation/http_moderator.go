@@ -0,0 +1,79 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+)
+
+// HTTPModerator asks an image moderation service whether a photo is acceptable. The service
+// receives {"image_url": "..."} and answers {"score": 0.97, "labels": ["nudity"]}, where score
+// is the confidence that the image violates the content policy; a self-hosted NSFW model or a
+// small proxy in front of a commercial provider can both speak this.
+type HTTPModerator struct {
+	url        string
+	apiKey     string
+	httpClient *http.Client
+}
+
+type moderationRequest struct {
+	ImageURL string `json:"image_url"`
+}
+
+type moderationResponse struct {
+	Score  *float64 `json:"score"`
+	Labels []string `json:"labels"`
+}
+
+func NewHTTPModerator(url, apiKey string, httpClient *http.Client) port.ImageModeratorInterface {
+	return &HTTPModerator{
+		url:        url,
+		apiKey:     apiKey,
+		httpClient: httpClient,
+	}
+}
+
+func (m *HTTPModerator) Moderate(ctx context.Context, imageURL string) (*entity.ModerationResultEntity, error) {
+	body, err := json.Marshal(moderationRequest{ImageURL: imageURL})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call moderation service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("moderation service returned status %d: %s", resp.StatusCode, string(responseBody))
+	}
+
+	var payload moderationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode moderation response: %w", err)
+	}
+	if payload.Score == nil || *payload.Score < 0 || *payload.Score > 1 {
+		return nil, fmt.Errorf("moderation response has no valid score")
+	}
+
+	return &entity.ModerationResultEntity{
+		Score:  *payload.Score,
+		Labels: payload.Labels,
+	}, nil
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+	"user-service/utils/pii"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errMergeDryRun rolls back a dry-run merge after the result has been computed
+var errMergeDryRun = errors.New("merge dry run")
+
+type AccountMergeRepository struct {
+	db *gorm.DB
+}
+
+func (r *AccountMergeRepository) MergeAccounts(ctx context.Context, sourceUserID, targetUserID int64, dryRun bool) (*entity.AccountMergeEntity, error) {
+	result := &entity.AccountMergeEntity{
+		SourceUserID: sourceUserID,
+		TargetUserID: targetUserID,
+		DryRun:       dryRun,
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		source, err := r.lockUser(tx, sourceUserID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.New("source user not found")
+			}
+			return err
+		}
+		target, err := r.lockUser(tx, targetUserID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.New("target user not found")
+			}
+			return err
+		}
+		result.SourceEmail = source.Email
+		result.TargetEmail = target.Email
+
+		if err := r.mergeVendor(tx, result); err != nil {
+			return err
+		}
+
+		result.ProfileFieldsCopied = r.fillProfile(source, target)
+		if len(result.ProfileFieldsCopied) > 0 {
+			if err := tx.Model(&model.User{}).Where("id = ?", targetUserID).Updates(map[string]interface{}{
+				"phone":       pii.Value(target.Phone),
+				"photo":       target.Photo,
+				"address":     pii.Value(target.Address),
+				"province":    target.Province,
+				"city":        target.City,
+				"district":    target.District,
+				"postal_code": target.PostalCode,
+				"lat":         target.Lat,
+				"lng":         target.Lng,
+				"updated_at":  time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		// Devices the target already knows keep the target's trust decision
+		moved := tx.Exec(`
+			INSERT INTO user_devices (user_id, fingerprint, user_agent, last_ip, trusted, first_seen_at, last_seen_at)
+			SELECT ?, fingerprint, user_agent, last_ip, trusted, first_seen_at, last_seen_at
+			FROM user_devices WHERE user_id = ?
+			ON CONFLICT (user_id, fingerprint) DO NOTHING`, targetUserID, sourceUserID)
+		if moved.Error != nil {
+			return moved.Error
+		}
+		result.DevicesMoved = moved.RowsAffected
+		if err := tx.Exec("DELETE FROM user_devices WHERE user_id = ?", sourceUserID).Error; err != nil {
+			return err
+		}
+
+		// Only overrides for flags the target has no opinion on are carried over
+		overrides := tx.Exec(`
+			UPDATE feature_flag_overrides SET user_id = ?, updated_at = NOW()
+			WHERE user_id = ? AND feature_flag_id NOT IN (
+				SELECT feature_flag_id FROM feature_flag_overrides WHERE user_id = ?
+			)`, targetUserID, sourceUserID, targetUserID)
+		if overrides.Error != nil {
+			return overrides.Error
+		}
+		result.FeatureOverridesMoved = overrides.RowsAffected
+
+		// LEAST ignores NULLs, so the earliest known first order wins
+		onboarding := tx.Exec(`
+			INSERT INTO user_onboarding (user_id, first_order_at, created_at, updated_at)
+			SELECT ?, first_order_at, NOW(), NOW()
+			FROM user_onboarding WHERE user_id = ? AND first_order_at IS NOT NULL
+			ON CONFLICT (user_id) DO UPDATE
+			SET first_order_at = LEAST(user_onboarding.first_order_at, EXCLUDED.first_order_at), updated_at = NOW()`,
+			targetUserID, sourceUserID)
+		if onboarding.Error != nil {
+			return onboarding.Error
+		}
+		result.OnboardingMerged = onboarding.RowsAffected > 0
+
+		// Orders count towards the target's segments from now on
+		if err := tx.Exec("UPDATE customer_orders SET user_id = ? WHERE user_id = ?", targetUserID, sourceUserID).Error; err != nil {
+			return err
+		}
+
+		// Pending verification or reset links must not reactivate the archived account
+		if err := tx.Exec("DELETE FROM verification_tokens WHERE user_id = ?", sourceUserID).Error; err != nil {
+			return err
+		}
+
+		result.MergedAt = time.Now()
+		if err := tx.Model(&model.User{}).Where("id = ?", sourceUserID).Updates(map[string]interface{}{
+			"deleted_at":     result.MergedAt,
+			"merged_into_id": targetUserID,
+			"updated_at":     result.MergedAt,
+		}).Error; err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errMergeDryRun
+		}
+		return nil
+	})
+	if err != nil && err != errMergeDryRun {
+		log.Error().Err(err).Int64("source_user_id", sourceUserID).Int64("target_user_id", targetUserID).Msg("[AccountMergeRepository-MergeAccounts] Merge failed")
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (r *AccountMergeRepository) lockUser(tx *gorm.DB, userID int64) (*model.User, error) {
+	user := model.User{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NULL", userID).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *AccountMergeRepository) mergeVendor(tx *gorm.DB, result *entity.AccountMergeEntity) error {
+	var sourceVendors, targetVendors int64
+	if err := tx.Model(&model.Vendor{}).Where("user_id = ? AND deleted_at IS NULL", result.SourceUserID).Count(&sourceVendors).Error; err != nil {
+		return err
+	}
+	if sourceVendors == 0 {
+		return nil
+	}
+
+	if err := tx.Model(&model.Vendor{}).Where("user_id = ? AND deleted_at IS NULL", result.TargetUserID).Count(&targetVendors).Error; err != nil {
+		return err
+	}
+	if targetVendors > 0 {
+		return errors.New("both accounts have vendor profiles")
+	}
+
+	if err := tx.Model(&model.Vendor{}).Where("user_id = ?", result.SourceUserID).Update("user_id", result.TargetUserID).Error; err != nil {
+		return err
+	}
+	result.VendorMoved = true
+	return nil
+}
+
+// fillProfile copies profile fields the target is missing and reports which ones changed
+func (r *AccountMergeRepository) fillProfile(source, target *model.User) []string {
+	var copied []string
+	if target.Phone == "" && source.Phone != "" {
+		target.Phone = source.Phone
+		copied = append(copied, "phone")
+	}
+	if target.Photo == "" && source.Photo != "" {
+		target.Photo = source.Photo
+		copied = append(copied, "photo")
+	}
+	// The address is copied as a whole so components and coordinates stay consistent
+	if target.Address == "" && source.Address != "" {
+		target.Address = source.Address
+		target.Province = source.Province
+		target.City = source.City
+		target.District = source.District
+		target.PostalCode = source.PostalCode
+		target.Lat = source.Lat
+		target.Lng = source.Lng
+		copied = append(copied, "address")
+	}
+	return copied
+}
+
+func NewAccountMergeRepository(db *gorm.DB) port.AccountMergeRepositoryInterface {
+	return &AccountMergeRepository{db: db}
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository struct {
+	db *gorm.DB
+}
+
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, apiKey *entity.APIKeyEntity) (*entity.APIKeyEntity, error) {
+	scopes, err := json.Marshal(apiKey.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKeyModel := &model.APIKey{
+		Name:      apiKey.Name,
+		KeyPrefix: apiKey.Prefix,
+		KeyHash:   apiKey.KeyHash,
+		Scopes:    string(scopes),
+		Quota:     apiKey.Quota,
+		ExpiresAt: apiKey.ExpiresAt,
+	}
+	if apiKey.CreatedBy > 0 {
+		apiKeyModel.CreatedBy = &apiKey.CreatedBy
+	}
+
+	if err := r.db.WithContext(ctx).Create(apiKeyModel).Error; err != nil {
+		log.Error().Err(err).Str("name", apiKey.Name).Msg("[APIKeyRepository-CreateAPIKey] Failed to create API key")
+		return nil, err
+	}
+
+	return r.toEntity(apiKeyModel), nil
+}
+
+func (r *APIKeyRepository) GetAPIKeys(ctx context.Context, page, limit int) ([]entity.APIKeyEntity, int64, error) {
+	var (
+		apiKeys    []model.APIKey
+		totalCount int64
+	)
+
+	if err := r.db.WithContext(ctx).Model(&model.APIKey{}).Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Msg("[APIKeyRepository-GetAPIKeys] Failed to count API keys")
+		return nil, 0, err
+	}
+
+	if err := r.db.WithContext(ctx).Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&apiKeys).Error; err != nil {
+		log.Error().Err(err).Msg("[APIKeyRepository-GetAPIKeys] Failed to get API keys")
+		return nil, 0, err
+	}
+
+	entities := make([]entity.APIKeyEntity, 0, len(apiKeys))
+	for i := range apiKeys {
+		entities = append(entities, *r.toEntity(&apiKeys[i]))
+	}
+	return entities, totalCount, nil
+}
+
+func (r *APIKeyRepository) GetAPIKeyByID(ctx context.Context, id int64) (*entity.APIKeyEntity, error) {
+	var apiKeyModel model.APIKey
+	if err := r.db.WithContext(ctx).First(&apiKeyModel, id).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Int64("api_key_id", id).Msg("[APIKeyRepository-GetAPIKeyByID] Failed to get API key")
+		}
+		return nil, err
+	}
+
+	return r.toEntity(&apiKeyModel), nil
+}
+
+func (r *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*entity.APIKeyEntity, error) {
+	var apiKeyModel model.APIKey
+	if err := r.db.WithContext(ctx).Where("key_hash = ?", keyHash).First(&apiKeyModel).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Msg("[APIKeyRepository-GetAPIKeyByHash] Failed to get API key")
+		}
+		return nil, err
+	}
+
+	return r.toEntity(&apiKeyModel), nil
+}
+
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, id, revokedBy int64, at time.Time) error {
+	updates := map[string]interface{}{
+		"revoked_at": at,
+		"updated_at": at,
+	}
+	if revokedBy > 0 {
+		updates["revoked_by"] = revokedBy
+	}
+
+	result := r.db.WithContext(ctx).Model(&model.APIKey{}).Where("id = ? AND revoked_at IS NULL", id).Updates(updates)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("api_key_id", id).Msg("[APIKeyRepository-RevokeAPIKey] Failed to revoke API key")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&model.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", at).Error; err != nil {
+		log.Error().Err(err).Int64("api_key_id", id).Msg("[APIKeyRepository-TouchLastUsed] Failed to record API key use")
+		return err
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) toEntity(apiKey *model.APIKey) *entity.APIKeyEntity {
+	var scopes []string
+	if err := json.Unmarshal([]byte(apiKey.Scopes), &scopes); err != nil {
+		log.Error().Err(err).Int64("api_key_id", apiKey.ID).Msg("[APIKeyRepository-toEntity] Invalid scopes")
+	}
+
+	result := &entity.APIKeyEntity{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.KeyPrefix,
+		KeyHash:    apiKey.KeyHash,
+		Scopes:     scopes,
+		Quota:      apiKey.Quota,
+		LastUsedAt: apiKey.LastUsedAt,
+		ExpiresAt:  apiKey.ExpiresAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+		UpdatedAt:  apiKey.UpdatedAt,
+	}
+	if apiKey.CreatedBy != nil {
+		result.CreatedBy = *apiKey.CreatedBy
+	}
+	if apiKey.RevokedBy != nil {
+		result.RevokedBy = *apiKey.RevokedBy
+	}
+	return result
+}
+
+func NewAPIKeyRepository(db *gorm.DB) port.APIKeyRepositoryInterface {
+	return &APIKeyRepository{db: db}
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AssetRepository struct {
+	db *gorm.DB
+}
+
+func (r *AssetRepository) GetAssets(ctx context.Context) ([]entity.AssetEntity, error) {
+	var assets []model.Asset
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&assets).Error; err != nil {
+		log.Error().Err(err).Msg("[AssetRepository-GetAssets] Failed to get assets")
+		return nil, err
+	}
+
+	assetEntities := make([]entity.AssetEntity, 0, len(assets))
+	for i := range assets {
+		assetEntities = append(assetEntities, *toAssetEntity(&assets[i]))
+	}
+	return assetEntities, nil
+}
+
+func (r *AssetRepository) GetAsset(ctx context.Context, name string) (*entity.AssetEntity, error) {
+	var assetModel model.Asset
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&assetModel).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Str("name", name).Msg("[AssetRepository-GetAsset] Failed to get asset")
+		}
+		return nil, err
+	}
+
+	return toAssetEntity(&assetModel), nil
+}
+
+func (r *AssetRepository) SaveAsset(ctx context.Context, asset *entity.AssetEntity) error {
+	now := time.Now()
+	assetModel := &model.Asset{
+		Name:        asset.Name,
+		ObjectName:  asset.ObjectName,
+		Hash:        asset.Hash,
+		ContentType: asset.ContentType,
+		Size:        asset.Size,
+		URL:         asset.URL,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if asset.UploadedBy > 0 {
+		assetModel.UploadedBy = &asset.UploadedBy
+	}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"object_name", "hash", "content_type", "size", "url", "uploaded_by", "updated_at"}),
+	}).Create(assetModel).Error
+	if err != nil {
+		log.Error().Err(err).Str("name", asset.Name).Msg("[AssetRepository-SaveAsset] Failed to save asset")
+		return err
+	}
+
+	asset.UpdatedAt = now
+	return nil
+}
+
+func toAssetEntity(assetModel *model.Asset) *entity.AssetEntity {
+	asset := &entity.AssetEntity{
+		Name:        assetModel.Name,
+		ObjectName:  assetModel.ObjectName,
+		Hash:        assetModel.Hash,
+		ContentType: assetModel.ContentType,
+		Size:        assetModel.Size,
+		URL:         assetModel.URL,
+		UpdatedAt:   assetModel.UpdatedAt,
+	}
+	if assetModel.UploadedBy != nil {
+		asset.UploadedBy = *assetModel.UploadedBy
+	}
+	return asset
+}
+
+func NewAssetRepository(db *gorm.DB) port.AssetRepositoryInterface {
+	return &AssetRepository{db: db}
+}
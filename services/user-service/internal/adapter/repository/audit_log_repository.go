@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type AuditLogRepository struct {
+	db *gorm.DB
+}
+
+func (r *AuditLogRepository) Create(ctx context.Context, auditLog *entity.AuditLogEntity) error {
+	metadata := "{}"
+	if len(auditLog.Metadata) > 0 {
+		data, err := json.Marshal(auditLog.Metadata)
+		if err != nil {
+			return err
+		}
+		metadata = string(data)
+	}
+
+	modelAuditLog := model.AuditLog{
+		Event:     auditLog.Event,
+		IPAddress: auditLog.IPAddress,
+		UserAgent: auditLog.UserAgent,
+		Metadata:  metadata,
+	}
+	if auditLog.UserID > 0 {
+		modelAuditLog.UserID = &auditLog.UserID
+	}
+
+	if err := r.db.WithContext(ctx).Create(&modelAuditLog).Error; err != nil {
+		log.Error().Err(err).Str("event", auditLog.Event).Msg("[AuditLogRepository-Create] Failed to create audit log")
+		return err
+	}
+
+	auditLog.ID = modelAuditLog.ID
+	auditLog.CreatedAt = modelAuditLog.CreatedAt
+	return nil
+}
+
+func (r *AuditLogRepository) List(ctx context.Context, filter entity.AuditLogFilter, page, limit int) ([]entity.AuditLogEntity, int64, error) {
+	var modelAuditLogs []model.AuditLog
+	var totalCount int64
+
+	query := r.db.WithContext(ctx).Model(&model.AuditLog{})
+	if filter.UserID > 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.Event != "" {
+		query = query.Where("event = ?", filter.Event)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Msg("[AuditLogRepository-List] Failed to count audit logs")
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC").Order("id DESC").Offset(offset).Limit(limit).Find(&modelAuditLogs).Error; err != nil {
+		log.Error().Err(err).Msg("[AuditLogRepository-List] Failed to get audit logs")
+		return nil, 0, err
+	}
+
+	auditLogs := make([]entity.AuditLogEntity, 0, len(modelAuditLogs))
+	for _, modelAuditLog := range modelAuditLogs {
+		auditLog := entity.AuditLogEntity{
+			ID:        modelAuditLog.ID,
+			Event:     modelAuditLog.Event,
+			IPAddress: modelAuditLog.IPAddress,
+			UserAgent: modelAuditLog.UserAgent,
+			CreatedAt: modelAuditLog.CreatedAt,
+		}
+		if modelAuditLog.UserID != nil {
+			auditLog.UserID = *modelAuditLog.UserID
+		}
+		if err := json.Unmarshal([]byte(modelAuditLog.Metadata), &auditLog.Metadata); err != nil {
+			log.Warn().Err(err).Int64("audit_log_id", modelAuditLog.ID).Msg("[AuditLogRepository-List] Malformed metadata")
+		}
+		auditLogs = append(auditLogs, auditLog)
+	}
+
+	return auditLogs, totalCount, nil
+}
+
+func NewAuditLogRepository(db *gorm.DB) port.AuditLogRepositoryInterface {
+	return &AuditLogRepository{db: db}
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+	"user-service/internal/core/port"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog/log"
+)
+
+// blacklistIndexKey scores every blacklisted hash by its expiry so the size can be read
+// without scanning keys; the per-token keys expire on their own
+const blacklistIndexKey = "blacklist:tokens"
+
+type RedisBlacklistTokenRepository struct {
+	redisClient *redis.Client
+}
+
+func NewRedisBlacklistTokenRepository(redisClient *redis.Client) port.BlacklistTokenInterface {
+	return &RedisBlacklistTokenRepository{
+		redisClient: redisClient,
+	}
+}
+
+func (r *RedisBlacklistTokenRepository) AddToBlacklist(ctx context.Context, tokenHash string, expiresAt int64) error {
+	ttl := time.Until(time.Unix(expiresAt, 0))
+	// The token is already unusable, nothing to remember
+	if ttl <= 0 {
+		return nil
+	}
+
+	_, err := r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, r.getTokenKey(tokenHash), 1, ttl)
+		pipe.ZAdd(ctx, blacklistIndexKey, &redis.Z{Score: float64(expiresAt), Member: tokenHash})
+		return nil
+	})
+	if err != nil {
+		log.Error().Err(err).Msg("[RedisBlacklistTokenRepository-AddToBlacklist] Failed to blacklist token")
+	}
+	return err
+}
+
+func (r *RedisBlacklistTokenRepository) IsTokenBlacklisted(ctx context.Context, tokenHash string) bool {
+	exists, err := r.redisClient.Exists(ctx, r.getTokenKey(tokenHash)).Result()
+	return err == nil && exists > 0
+}
+
+func (r *RedisBlacklistTokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	return r.redisClient.ZRemRangeByScore(ctx, blacklistIndexKey, "-inf", strconv.FormatInt(time.Now().Unix(), 10)).Result()
+}
+
+func (r *RedisBlacklistTokenRepository) Count(ctx context.Context) (int64, error) {
+	return r.redisClient.ZCount(ctx, blacklistIndexKey, "("+strconv.FormatInt(time.Now().Unix(), 10), "+inf").Result()
+}
+
+func (r *RedisBlacklistTokenRepository) getTokenKey(tokenHash string) string {
+	return fmt.Sprintf("blacklist:token:%s", tokenHash)
+}
@@ -2,10 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
+	"user-service/config"
 	"user-service/internal/core/domain/model"
 	"user-service/internal/core/port"
 
+	"github.com/go-redis/redis/v8"
 	"gorm.io/gorm"
 )
 
@@ -19,6 +23,18 @@ func NewBlacklistTokenRepository(db *gorm.DB) port.BlacklistTokenInterface {
 	}
 }
 
+// NewBlacklistTokenBackend picks the blacklist store from BLACKLIST_BACKEND
+func NewBlacklistTokenBackend(cfg *config.Config, db *gorm.DB, redisClient *redis.Client) (port.BlacklistTokenInterface, error) {
+	switch strings.ToLower(cfg.Blacklist.Backend) {
+	case "", "postgres":
+		return NewBlacklistTokenRepository(db), nil
+	case "redis":
+		return NewRedisBlacklistTokenRepository(redisClient), nil
+	default:
+		return nil, errors.New("unsupported blacklist backend: " + cfg.Blacklist.Backend)
+	}
+}
+
 func (r *BlacklistTokenRepository) AddToBlacklist(ctx context.Context, tokenHash string, expiresAt int64) error {
 	model := &model.BlacklistToken{
 		TokenHash: tokenHash,
@@ -36,3 +52,16 @@ func (r *BlacklistTokenRepository) IsTokenBlacklisted(ctx context.Context, token
 
 	return err == nil && count > 0
 }
+
+func (r *BlacklistTokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", time.Now()).Delete(&model.BlacklistToken{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *BlacklistTokenRepository) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.BlacklistToken{}).
+		Where("expires_at > ?", time.Now()).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"user-service/internal/core/port"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog/log"
+)
+
+// CustomerImportReportRepository stores import error reports in Redis until they expire
+type CustomerImportReportRepository struct {
+	redisClient *redis.Client
+}
+
+func (r *CustomerImportReportRepository) SaveReport(ctx context.Context, reportID string, report []byte, ttl time.Duration) error {
+	if err := r.redisClient.Set(ctx, r.getReportKey(reportID), report, ttl).Err(); err != nil {
+		log.Error().Err(err).Str("report_id", reportID).Msg("[CustomerImportReportRepository-SaveReport] Failed to save report")
+		return err
+	}
+	return nil
+}
+
+func (r *CustomerImportReportRepository) GetReport(ctx context.Context, reportID string) ([]byte, error) {
+	report, err := r.redisClient.Get(ctx, r.getReportKey(reportID)).Bytes()
+	if err == redis.Nil {
+		return nil, errors.New("import report not found")
+	}
+	if err != nil {
+		log.Error().Err(err).Str("report_id", reportID).Msg("[CustomerImportReportRepository-GetReport] Failed to get report")
+		return nil, err
+	}
+	return report, nil
+}
+
+func (r *CustomerImportReportRepository) getReportKey(reportID string) string {
+	return fmt.Sprintf("customer_import_report:%s", reportID)
+}
+
+func NewCustomerImportReportRepository(redisClient *redis.Client) port.CustomerImportReportRepositoryInterface {
+	return &CustomerImportReportRepository{redisClient: redisClient}
+}
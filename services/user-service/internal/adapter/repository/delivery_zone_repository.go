@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type DeliveryZoneRepository struct {
+	db *gorm.DB
+}
+
+func (r *DeliveryZoneRepository) GetAllZones(ctx context.Context, activeOnly bool) ([]entity.DeliveryZoneEntity, error) {
+	var zones []model.DeliveryZone
+	query := r.db.WithContext(ctx).Where("deleted_at IS NULL")
+
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	if err := query.Order("id ASC").Find(&zones).Error; err != nil {
+		log.Error().Err(err).Bool("active_only", activeOnly).Msg("[DeliveryZoneRepository-GetAllZones] Failed to get zones")
+		return nil, err
+	}
+
+	zoneEntities := make([]entity.DeliveryZoneEntity, 0, len(zones))
+	for _, zone := range zones {
+		zoneEntity, err := r.toEntity(&zone)
+		if err != nil {
+			log.Error().Err(err).Int64("zone_id", zone.ID).Msg("[DeliveryZoneRepository-GetAllZones] Failed to decode zone polygon")
+			return nil, err
+		}
+		zoneEntities = append(zoneEntities, *zoneEntity)
+	}
+
+	log.Info().Int("count", len(zoneEntities)).Bool("active_only", activeOnly).Msg("[DeliveryZoneRepository-GetAllZones] Zones retrieved successfully")
+	return zoneEntities, nil
+}
+
+func (r *DeliveryZoneRepository) GetZoneByID(ctx context.Context, id int64) (*entity.DeliveryZoneEntity, error) {
+	var zone model.DeliveryZone
+	if err := r.db.WithContext(ctx).Where("deleted_at IS NULL").First(&zone, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			log.Info().Int64("zone_id", id).Msg("[DeliveryZoneRepository-GetZoneByID] Zone not found")
+			return nil, gorm.ErrRecordNotFound
+		}
+		log.Error().Err(err).Int64("zone_id", id).Msg("[DeliveryZoneRepository-GetZoneByID] Failed to get zone by ID")
+		return nil, err
+	}
+
+	return r.toEntity(&zone)
+}
+
+func (r *DeliveryZoneRepository) CreateZone(ctx context.Context, zone *entity.DeliveryZoneEntity) (*entity.DeliveryZoneEntity, error) {
+	zoneModel := &model.DeliveryZone{}
+	if err := r.applyEntity(zoneModel, zone); err != nil {
+		log.Error().Err(err).Str("zone_name", zone.Name).Msg("[DeliveryZoneRepository-CreateZone] Failed to encode zone polygon")
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).Create(zoneModel).Error; err != nil {
+		log.Error().Err(err).Str("zone_name", zone.Name).Msg("[DeliveryZoneRepository-CreateZone] Failed to create zone")
+		return nil, err
+	}
+
+	log.Info().Int64("zone_id", zoneModel.ID).Str("zone_name", zoneModel.Name).Msg("[DeliveryZoneRepository-CreateZone] Zone created successfully")
+	return r.toEntity(zoneModel)
+}
+
+func (r *DeliveryZoneRepository) UpdateZone(ctx context.Context, id int64, zone *entity.DeliveryZoneEntity) (*entity.DeliveryZoneEntity, error) {
+	var existingZone model.DeliveryZone
+	if err := r.db.WithContext(ctx).Where("deleted_at IS NULL").First(&existingZone, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			log.Info().Int64("zone_id", id).Msg("[DeliveryZoneRepository-UpdateZone] Zone not found")
+			return nil, gorm.ErrRecordNotFound
+		}
+		log.Error().Err(err).Int64("zone_id", id).Msg("[DeliveryZoneRepository-UpdateZone] Failed to find zone")
+		return nil, err
+	}
+
+	if err := r.applyEntity(&existingZone, zone); err != nil {
+		log.Error().Err(err).Int64("zone_id", id).Msg("[DeliveryZoneRepository-UpdateZone] Failed to encode zone polygon")
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).Save(&existingZone).Error; err != nil {
+		log.Error().Err(err).Int64("zone_id", id).Msg("[DeliveryZoneRepository-UpdateZone] Failed to update zone")
+		return nil, err
+	}
+
+	log.Info().Int64("zone_id", id).Str("zone_name", existingZone.Name).Msg("[DeliveryZoneRepository-UpdateZone] Zone updated successfully")
+	return r.toEntity(&existingZone)
+}
+
+func (r *DeliveryZoneRepository) DeleteZone(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Model(&model.DeliveryZone{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP"))
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("zone_id", id).Msg("[DeliveryZoneRepository-DeleteZone] Failed to delete zone")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		log.Info().Int64("zone_id", id).Msg("[DeliveryZoneRepository-DeleteZone] Zone not found")
+		return gorm.ErrRecordNotFound
+	}
+
+	log.Info().Int64("zone_id", id).Msg("[DeliveryZoneRepository-DeleteZone] Zone deleted successfully")
+	return nil
+}
+
+// applyEntity copies the editable fields; only the geometry matching the zone type is stored
+func (r *DeliveryZoneRepository) applyEntity(zoneModel *model.DeliveryZone, zone *entity.DeliveryZoneEntity) error {
+	zoneModel.Name = zone.Name
+	zoneModel.ZoneType = zone.ZoneType
+	zoneModel.IsActive = zone.IsActive
+	zoneModel.CenterLat = nil
+	zoneModel.CenterLng = nil
+	zoneModel.RadiusKm = nil
+	zoneModel.Polygon = nil
+
+	switch zone.ZoneType {
+	case entity.ZoneTypeRadius:
+		centerLat, centerLng, radiusKm := zone.CenterLat, zone.CenterLng, zone.RadiusKm
+		zoneModel.CenterLat = &centerLat
+		zoneModel.CenterLng = &centerLng
+		zoneModel.RadiusKm = &radiusKm
+	case entity.ZoneTypePolygon:
+		encoded, err := json.Marshal(zone.Polygon)
+		if err != nil {
+			return err
+		}
+		polygon := string(encoded)
+		zoneModel.Polygon = &polygon
+	}
+
+	return nil
+}
+
+func (r *DeliveryZoneRepository) toEntity(zone *model.DeliveryZone) (*entity.DeliveryZoneEntity, error) {
+	zoneEntity := &entity.DeliveryZoneEntity{
+		ID:        zone.ID,
+		Name:      zone.Name,
+		ZoneType:  zone.ZoneType,
+		IsActive:  zone.IsActive,
+		CreatedAt: zone.CreatedAt,
+		UpdatedAt: zone.UpdatedAt,
+	}
+
+	if zone.CenterLat != nil {
+		zoneEntity.CenterLat = *zone.CenterLat
+	}
+	if zone.CenterLng != nil {
+		zoneEntity.CenterLng = *zone.CenterLng
+	}
+	if zone.RadiusKm != nil {
+		zoneEntity.RadiusKm = *zone.RadiusKm
+	}
+	if zone.Polygon != nil && *zone.Polygon != "" {
+		if err := json.Unmarshal([]byte(*zone.Polygon), &zoneEntity.Polygon); err != nil {
+			return nil, err
+		}
+	}
+
+	return zoneEntity, nil
+}
+
+func NewDeliveryZoneRepository(db *gorm.DB) port.DeliveryZoneRepositoryInterface {
+	return &DeliveryZoneRepository{db: db}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type DeviceRepository struct {
+	db *gorm.DB
+}
+
+func (r *DeviceRepository) TouchDevice(ctx context.Context, userID int64, client entity.ClientEntity) (*entity.DeviceEntity, bool, error) {
+	now := time.Now()
+	modelDevice := model.UserDevice{}
+
+	// xmax = 0 only for rows created by this statement, which tells new devices apart from updates
+	var inserted bool
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO user_devices (user_id, fingerprint, user_agent, last_ip, first_seen_at, last_seen_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, fingerprint) DO UPDATE
+		SET user_agent = EXCLUDED.user_agent, last_ip = EXCLUDED.last_ip, last_seen_at = EXCLUDED.last_seen_at
+		RETURNING id, user_id, fingerprint, user_agent, last_ip, trusted, first_seen_at, last_seen_at, (xmax = 0) AS inserted`,
+		userID, client.Fingerprint(), client.UserAgent, client.IPAddress, now, now,
+	).Row().Scan(&modelDevice.ID, &modelDevice.UserID, &modelDevice.Fingerprint, &modelDevice.UserAgent, &modelDevice.LastIP,
+		&modelDevice.Trusted, &modelDevice.FirstSeenAt, &modelDevice.LastSeenAt, &inserted)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[DeviceRepository-TouchDevice] Failed to record device")
+		return nil, false, err
+	}
+
+	return toDeviceEntity(modelDevice), inserted, nil
+}
+
+func (r *DeviceRepository) FindDevice(ctx context.Context, userID int64, fingerprint string) (*entity.DeviceEntity, error) {
+	modelDevice := model.UserDevice{}
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND fingerprint = ?", userID, fingerprint).First(&modelDevice).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Int64("user_id", userID).Msg("[DeviceRepository-FindDevice] Failed to find device")
+		}
+		return nil, err
+	}
+	return toDeviceEntity(modelDevice), nil
+}
+
+func (r *DeviceRepository) CountDevices(ctx context.Context, userID int64) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&model.UserDevice{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[DeviceRepository-CountDevices] Failed to count devices")
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *DeviceRepository) GetDevices(ctx context.Context, userID int64) ([]entity.DeviceEntity, error) {
+	var modelDevices []model.UserDevice
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("last_seen_at DESC").Find(&modelDevices).Error; err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[DeviceRepository-GetDevices] Failed to get devices")
+		return nil, err
+	}
+
+	devices := make([]entity.DeviceEntity, 0, len(modelDevices))
+	for _, modelDevice := range modelDevices {
+		devices = append(devices, *toDeviceEntity(modelDevice))
+	}
+	return devices, nil
+}
+
+func (r *DeviceRepository) SetTrusted(ctx context.Context, userID, deviceID int64, trusted bool) error {
+	result := r.db.WithContext(ctx).Model(&model.UserDevice{}).
+		Where("id = ? AND user_id = ?", deviceID, userID).
+		Update("trusted", trusted)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("user_id", userID).Int64("device_id", deviceID).Msg("[DeviceRepository-SetTrusted] Failed to update device")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func toDeviceEntity(modelDevice model.UserDevice) *entity.DeviceEntity {
+	return &entity.DeviceEntity{
+		ID:          modelDevice.ID,
+		UserID:      modelDevice.UserID,
+		Fingerprint: modelDevice.Fingerprint,
+		UserAgent:   modelDevice.UserAgent,
+		LastIP:      modelDevice.LastIP,
+		Trusted:     modelDevice.Trusted,
+		FirstSeenAt: modelDevice.FirstSeenAt,
+		LastSeenAt:  modelDevice.LastSeenAt,
+	}
+}
+
+func NewDeviceRepository(db *gorm.DB) port.DeviceRepositoryInterface {
+	return &DeviceRepository{db: db}
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// emailRateLimitsKey is read by notification-service; keep the value's shape in step with it
+const emailRateLimitsKey = "notification:email_rate_limits"
+
+type emailRateLimitSnapshot struct {
+	PerHour   map[string]int `json:"per_hour"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+type EmailRateLimitRepository struct {
+	db          *gorm.DB
+	redisClient *redis.Client
+}
+
+func (r *EmailRateLimitRepository) GetAllLimits(ctx context.Context) ([]entity.EmailRateLimitEntity, error) {
+	var limits []model.EmailRateLimit
+	if err := r.db.WithContext(ctx).Order("email_type ASC").Find(&limits).Error; err != nil {
+		log.Error().Err(err).Msg("[EmailRateLimitRepository-GetAllLimits] Failed to get email rate limits")
+		return nil, err
+	}
+
+	entities := make([]entity.EmailRateLimitEntity, 0, len(limits))
+	for i := range limits {
+		entities = append(entities, *toEmailRateLimitEntity(&limits[i]))
+	}
+	return entities, nil
+}
+
+func (r *EmailRateLimitRepository) UpsertLimit(ctx context.Context, limit *entity.EmailRateLimitEntity) (*entity.EmailRateLimitEntity, error) {
+	limitModel := &model.EmailRateLimit{
+		EmailType:  limit.EmailType,
+		MaxPerHour: limit.MaxPerHour,
+		UpdatedBy:  optionalID(limit.UpdatedBy),
+		UpdatedAt:  time.Now(),
+	}
+
+	err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "email_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_per_hour", "updated_by", "updated_at"}),
+	}).Create(limitModel).Error
+	if err != nil {
+		log.Error().Err(err).Str("email_type", limit.EmailType).Msg("[EmailRateLimitRepository-UpsertLimit] Failed to save email rate limit")
+		return nil, err
+	}
+
+	return toEmailRateLimitEntity(limitModel), nil
+}
+
+func (r *EmailRateLimitRepository) DeleteLimit(ctx context.Context, emailType string) error {
+	result := r.db.WithContext(ctx).Where("email_type = ?", emailType).Delete(&model.EmailRateLimit{})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("email_type", emailType).Msg("[EmailRateLimitRepository-DeleteLimit] Failed to delete email rate limit")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *EmailRateLimitRepository) PublishLimits(ctx context.Context, limits []entity.EmailRateLimitEntity) error {
+	snapshot := emailRateLimitSnapshot{PerHour: make(map[string]int, len(limits)), UpdatedAt: time.Now()}
+	for _, limit := range limits {
+		snapshot.PerHour[limit.EmailType] = limit.MaxPerHour
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	// No expiry: notification-service keeps using the last snapshot until the next change
+	if err := r.redisClient.Set(ctx, emailRateLimitsKey, data, 0).Err(); err != nil {
+		log.Error().Err(err).Msg("[EmailRateLimitRepository-PublishLimits] Failed to publish email rate limits")
+		return err
+	}
+	return nil
+}
+
+// optionalID stores 0 as NULL for nullable foreign keys
+func optionalID(id int64) *int64 {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
+
+func toEmailRateLimitEntity(limitModel *model.EmailRateLimit) *entity.EmailRateLimitEntity {
+	limit := &entity.EmailRateLimitEntity{
+		EmailType:  limitModel.EmailType,
+		MaxPerHour: limitModel.MaxPerHour,
+		UpdatedAt:  limitModel.UpdatedAt,
+	}
+	if limitModel.UpdatedBy != nil {
+		limit.UpdatedBy = *limitModel.UpdatedBy
+	}
+	return limit
+}
+
+func NewEmailRateLimitRepository(db *gorm.DB, redisClient *redis.Client) port.EmailRateLimitRepositoryInterface {
+	return &EmailRateLimitRepository{db: db, redisClient: redisClient}
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	featureFlagCacheTTL        = 5 * time.Minute
+	featureFlagMissingCacheTTL = 30 * time.Second
+	featureFlagMissingMarker   = "missing"
+)
+
+type FeatureFlagRepository struct {
+	db          *gorm.DB
+	redisClient *redis.Client
+}
+
+func (r *FeatureFlagRepository) GetAllFlags(ctx context.Context) ([]entity.FeatureFlagEntity, error) {
+	var flags []model.FeatureFlag
+	if err := r.db.WithContext(ctx).Preload("Overrides").Order("key ASC").Find(&flags).Error; err != nil {
+		log.Error().Err(err).Msg("[FeatureFlagRepository-GetAllFlags] Failed to get feature flags")
+		return nil, err
+	}
+
+	flagEntities := make([]entity.FeatureFlagEntity, 0, len(flags))
+	for i := range flags {
+		flagEntities = append(flagEntities, *r.toEntity(&flags[i]))
+	}
+
+	return flagEntities, nil
+}
+
+func (r *FeatureFlagRepository) GetFlagByKey(ctx context.Context, key string) (*entity.FeatureFlagEntity, error) {
+	cacheKey := r.getCacheKey(key)
+
+	// Redis errors fall through to the database; the cache is only an optimization
+	if cached, err := r.redisClient.Get(ctx, cacheKey).Result(); err == nil {
+		if cached == featureFlagMissingMarker {
+			return nil, gorm.ErrRecordNotFound
+		}
+		var flag entity.FeatureFlagEntity
+		if err := json.Unmarshal([]byte(cached), &flag); err == nil {
+			return &flag, nil
+		}
+	} else if err != redis.Nil {
+		log.Warn().Err(err).Str("key", key).Msg("[FeatureFlagRepository-GetFlagByKey] Failed to read flag cache")
+	}
+
+	var flagModel model.FeatureFlag
+	if err := r.db.WithContext(ctx).Preload("Overrides").Where("key = ?", key).First(&flagModel).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			r.redisClient.Set(ctx, cacheKey, featureFlagMissingMarker, featureFlagMissingCacheTTL)
+			return nil, gorm.ErrRecordNotFound
+		}
+		log.Error().Err(err).Str("key", key).Msg("[FeatureFlagRepository-GetFlagByKey] Failed to get feature flag")
+		return nil, err
+	}
+
+	flag := r.toEntity(&flagModel)
+	if data, err := json.Marshal(flag); err == nil {
+		if err := r.redisClient.Set(ctx, cacheKey, data, featureFlagCacheTTL).Err(); err != nil {
+			log.Warn().Err(err).Str("key", key).Msg("[FeatureFlagRepository-GetFlagByKey] Failed to cache flag")
+		}
+	}
+
+	return flag, nil
+}
+
+func (r *FeatureFlagRepository) CreateFlag(ctx context.Context, flag *entity.FeatureFlagEntity) (*entity.FeatureFlagEntity, error) {
+	flagModel := &model.FeatureFlag{
+		Key:               flag.Key,
+		Description:       flag.Description,
+		Enabled:           flag.Enabled,
+		RolloutPercentage: flag.RolloutPercentage,
+	}
+
+	if err := r.db.WithContext(ctx).Create(flagModel).Error; err != nil {
+		log.Error().Err(err).Str("key", flag.Key).Msg("[FeatureFlagRepository-CreateFlag] Failed to create feature flag")
+		return nil, err
+	}
+
+	r.invalidate(ctx, flag.Key)
+	return r.toEntity(flagModel), nil
+}
+
+func (r *FeatureFlagRepository) UpdateFlag(ctx context.Context, key string, flag *entity.FeatureFlagEntity) (*entity.FeatureFlagEntity, error) {
+	var flagModel model.FeatureFlag
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&flagModel).Error; err != nil {
+		log.Error().Err(err).Str("key", key).Msg("[FeatureFlagRepository-UpdateFlag] Failed to get feature flag")
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).Model(&flagModel).Updates(map[string]interface{}{
+		"description":        flag.Description,
+		"enabled":            flag.Enabled,
+		"rollout_percentage": flag.RolloutPercentage,
+	}).Error; err != nil {
+		log.Error().Err(err).Str("key", key).Msg("[FeatureFlagRepository-UpdateFlag] Failed to update feature flag")
+		return nil, err
+	}
+
+	r.invalidate(ctx, key)
+
+	if err := r.db.WithContext(ctx).Preload("Overrides").First(&flagModel, flagModel.ID).Error; err != nil {
+		return nil, err
+	}
+
+	log.Info().Str("key", key).Bool("enabled", flag.Enabled).Int("rollout_percentage", flag.RolloutPercentage).Msg("[FeatureFlagRepository-UpdateFlag] Feature flag updated")
+	return r.toEntity(&flagModel), nil
+}
+
+func (r *FeatureFlagRepository) DeleteFlag(ctx context.Context, key string) error {
+	result := r.db.WithContext(ctx).Where("key = ?", key).Delete(&model.FeatureFlag{})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("key", key).Msg("[FeatureFlagRepository-DeleteFlag] Failed to delete feature flag")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	r.invalidate(ctx, key)
+	return nil
+}
+
+func (r *FeatureFlagRepository) SetOverride(ctx context.Context, key string, userID int64, enabled bool) error {
+	var flagModel model.FeatureFlag
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&flagModel).Error; err != nil {
+		return err
+	}
+
+	override := &model.FeatureFlagOverride{
+		FeatureFlagID: flagModel.ID,
+		UserID:        userID,
+		Enabled:       enabled,
+	}
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "feature_flag_id"}, {Name: "user_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"enabled": enabled, "updated_at": time.Now()}),
+	}).Create(override).Error; err != nil {
+		log.Error().Err(err).Str("key", key).Int64("user_id", userID).Msg("[FeatureFlagRepository-SetOverride] Failed to set override")
+		return err
+	}
+
+	r.invalidate(ctx, key)
+	return nil
+}
+
+func (r *FeatureFlagRepository) DeleteOverride(ctx context.Context, key string, userID int64) error {
+	var flagModel model.FeatureFlag
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&flagModel).Error; err != nil {
+		return err
+	}
+
+	result := r.db.WithContext(ctx).
+		Where("feature_flag_id = ? AND user_id = ?", flagModel.ID, userID).
+		Delete(&model.FeatureFlagOverride{})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("key", key).Int64("user_id", userID).Msg("[FeatureFlagRepository-DeleteOverride] Failed to delete override")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("override not found")
+	}
+
+	r.invalidate(ctx, key)
+	return nil
+}
+
+func (r *FeatureFlagRepository) invalidate(ctx context.Context, key string) {
+	if err := r.redisClient.Del(ctx, r.getCacheKey(key)).Err(); err != nil {
+		log.Warn().Err(err).Str("key", key).Msg("[FeatureFlagRepository-invalidate] Failed to invalidate flag cache")
+	}
+}
+
+func (r *FeatureFlagRepository) getCacheKey(key string) string {
+	return "feature_flag:" + key
+}
+
+func (r *FeatureFlagRepository) toEntity(flagModel *model.FeatureFlag) *entity.FeatureFlagEntity {
+	flagEntity := &entity.FeatureFlagEntity{
+		ID:                flagModel.ID,
+		Key:               flagModel.Key,
+		Description:       flagModel.Description,
+		Enabled:           flagModel.Enabled,
+		RolloutPercentage: flagModel.RolloutPercentage,
+		Overrides:         make(map[int64]bool, len(flagModel.Overrides)),
+		CreatedAt:         flagModel.CreatedAt,
+		UpdatedAt:         flagModel.UpdatedAt,
+	}
+
+	for _, override := range flagModel.Overrides {
+		flagEntity.Overrides[override.UserID] = override.Enabled
+	}
+
+	return flagEntity
+}
+
+func NewFeatureFlagRepository(db *gorm.DB, redisClient *redis.Client) port.FeatureFlagRepositoryInterface {
+	return &FeatureFlagRepository{
+		db:          db,
+		redisClient: redisClient,
+	}
+}
@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type IdentityRepository struct {
+	db *gorm.DB
+}
+
+func (r *IdentityRepository) Create(ctx context.Context, verification *entity.IdentityVerificationEntity) (*entity.IdentityVerificationEntity, error) {
+	verificationModel := &model.IdentityVerification{
+		UserID:       verification.UserID,
+		DocumentType: verification.DocumentType,
+		ObjectName:   verification.ObjectName,
+		ContentType:  verification.ContentType,
+		DateOfBirth:  verification.DateOfBirth,
+		Status:       entity.IdentityVerificationPending,
+	}
+
+	if err := r.db.WithContext(ctx).Create(verificationModel).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, errors.New("identity verification already pending")
+		}
+		log.Error().Err(err).Int64("user_id", verification.UserID).Msg("[IdentityRepository-Create] Failed to create identity verification")
+		return nil, err
+	}
+
+	return toIdentityVerificationEntity(verificationModel), nil
+}
+
+func (r *IdentityRepository) GetByID(ctx context.Context, id int64) (*entity.IdentityVerificationEntity, error) {
+	var verificationModel model.IdentityVerification
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&verificationModel).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Int64("verification_id", id).Msg("[IdentityRepository-GetByID] Failed to get identity verification")
+		}
+		return nil, err
+	}
+
+	return toIdentityVerificationEntity(&verificationModel), nil
+}
+
+func (r *IdentityRepository) GetLatest(ctx context.Context, userID int64) (*entity.IdentityVerificationEntity, error) {
+	return r.getLatest(ctx, r.db.WithContext(ctx).Where("user_id = ?", userID), userID)
+}
+
+func (r *IdentityRepository) GetLatestApproved(ctx context.Context, userID int64) (*entity.IdentityVerificationEntity, error) {
+	return r.getLatest(ctx, r.db.WithContext(ctx).Where("user_id = ? AND status = ?", userID, entity.IdentityVerificationApproved), userID)
+}
+
+func (r *IdentityRepository) getLatest(ctx context.Context, query *gorm.DB, userID int64) (*entity.IdentityVerificationEntity, error) {
+	var verificationModel model.IdentityVerification
+	if err := query.Order("created_at DESC, id DESC").First(&verificationModel).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Int64("user_id", userID).Msg("[IdentityRepository-getLatest] Failed to get identity verification")
+		}
+		return nil, err
+	}
+
+	return toIdentityVerificationEntity(&verificationModel), nil
+}
+
+func (r *IdentityRepository) List(ctx context.Context, status string, page, limit int) ([]entity.IdentityVerificationEntity, int64, error) {
+	var (
+		verifications []model.IdentityVerification
+		totalCount    int64
+	)
+
+	query := r.db.WithContext(ctx).Model(&model.IdentityVerification{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Msg("[IdentityRepository-List] Failed to count identity verifications")
+		return nil, 0, err
+	}
+
+	order := "created_at DESC, id DESC"
+	if status == entity.IdentityVerificationPending {
+		order = "created_at ASC, id ASC"
+	}
+	if err := query.Order(order).Offset((page - 1) * limit).Limit(limit).Find(&verifications).Error; err != nil {
+		log.Error().Err(err).Msg("[IdentityRepository-List] Failed to list identity verifications")
+		return nil, 0, err
+	}
+
+	entities := make([]entity.IdentityVerificationEntity, 0, len(verifications))
+	for i := range verifications {
+		entities = append(entities, *toIdentityVerificationEntity(&verifications[i]))
+	}
+	return entities, totalCount, nil
+}
+
+func (r *IdentityRepository) Review(ctx context.Context, id int64, update *entity.IdentityVerificationEntity) error {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":           update.Status,
+		"rejection_reason": update.RejectionReason,
+		"reviewed_by":      update.ReviewedBy,
+		"reviewed_at":      now,
+		"expires_at":       update.ExpiresAt,
+		"updated_at":       now,
+	}
+
+	// The status condition keeps two admins from deciding the same submission twice
+	result := r.db.WithContext(ctx).Model(&model.IdentityVerification{}).
+		Where("id = ? AND status = ?", id, entity.IdentityVerificationPending).
+		Updates(updates)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("verification_id", id).Msg("[IdentityRepository-Review] Failed to review identity verification")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("identity verification already reviewed")
+	}
+
+	return nil
+}
+
+func toIdentityVerificationEntity(verificationModel *model.IdentityVerification) *entity.IdentityVerificationEntity {
+	verification := &entity.IdentityVerificationEntity{
+		ID:              verificationModel.ID,
+		UserID:          verificationModel.UserID,
+		DocumentType:    verificationModel.DocumentType,
+		ObjectName:      verificationModel.ObjectName,
+		ContentType:     verificationModel.ContentType,
+		DateOfBirth:     verificationModel.DateOfBirth,
+		Status:          verificationModel.Status,
+		RejectionReason: verificationModel.RejectionReason,
+		ReviewedAt:      verificationModel.ReviewedAt,
+		ExpiresAt:       verificationModel.ExpiresAt,
+		CreatedAt:       verificationModel.CreatedAt,
+		UpdatedAt:       verificationModel.UpdatedAt,
+	}
+	if verificationModel.ReviewedBy != nil {
+		verification.ReviewedBy = *verificationModel.ReviewedBy
+	}
+	return verification
+}
+
+func NewIdentityRepository(db *gorm.DB) port.IdentityRepositoryInterface {
+	return &IdentityRepository{db: db}
+}
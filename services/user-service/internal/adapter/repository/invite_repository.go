@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type InviteRepository struct {
+	db *gorm.DB
+}
+
+func (r *InviteRepository) CreateInvite(ctx context.Context, invite *entity.InviteEntity) (*entity.InviteEntity, error) {
+	inviteModel := &model.InviteCode{
+		Code:      invite.Code,
+		Note:      invite.Note,
+		MaxUses:   invite.MaxUses,
+		ExpiresAt: invite.ExpiresAt,
+	}
+	if invite.CreatedBy > 0 {
+		inviteModel.CreatedBy = &invite.CreatedBy
+	}
+
+	if err := r.db.WithContext(ctx).Create(inviteModel).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, errors.New("invite code already exists")
+		}
+		log.Error().Err(err).Msg("[InviteRepository-CreateInvite] Failed to create invite")
+		return nil, err
+	}
+
+	return toInviteEntity(inviteModel), nil
+}
+
+func (r *InviteRepository) GetInvites(ctx context.Context, page, limit int) ([]entity.InviteEntity, int64, error) {
+	var (
+		invites    []model.InviteCode
+		totalCount int64
+	)
+
+	if err := r.db.WithContext(ctx).Model(&model.InviteCode{}).Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Msg("[InviteRepository-GetInvites] Failed to count invites")
+		return nil, 0, err
+	}
+
+	if err := r.db.WithContext(ctx).Order("id DESC").Offset((page - 1) * limit).Limit(limit).Find(&invites).Error; err != nil {
+		log.Error().Err(err).Msg("[InviteRepository-GetInvites] Failed to get invites")
+		return nil, 0, err
+	}
+
+	entities := make([]entity.InviteEntity, 0, len(invites))
+	for i := range invites {
+		entities = append(entities, *toInviteEntity(&invites[i]))
+	}
+	return entities, totalCount, nil
+}
+
+func (r *InviteRepository) GetInviteByCode(ctx context.Context, code string) (*entity.InviteEntity, error) {
+	var inviteModel model.InviteCode
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&inviteModel).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Msg("[InviteRepository-GetInviteByCode] Failed to get invite")
+		}
+		return nil, err
+	}
+
+	return toInviteEntity(&inviteModel), nil
+}
+
+func (r *InviteRepository) RevokeInvite(ctx context.Context, id int64) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&model.InviteCode{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"revoked_at": now, "updated_at": now})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("invite_id", id).Msg("[InviteRepository-RevokeInvite] Failed to revoke invite")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *InviteRepository) ReserveUse(ctx context.Context, code string) (int64, bool, error) {
+	var ids []int64
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE invite_codes SET uses = uses + 1, updated_at = NOW()
+		WHERE code = ? AND revoked_at IS NULL AND uses < max_uses AND (expires_at IS NULL OR expires_at > NOW())
+		RETURNING id`, code).Scan(&ids).Error
+	if err != nil {
+		log.Error().Err(err).Msg("[InviteRepository-ReserveUse] Failed to reserve invite use")
+		return 0, false, err
+	}
+
+	if len(ids) == 0 {
+		return 0, false, nil
+	}
+	return ids[0], true, nil
+}
+
+func (r *InviteRepository) ReleaseUse(ctx context.Context, id int64) error {
+	err := r.db.WithContext(ctx).Exec("UPDATE invite_codes SET uses = uses - 1, updated_at = NOW() WHERE id = ? AND uses > 0", id).Error
+	if err != nil {
+		log.Error().Err(err).Int64("invite_id", id).Msg("[InviteRepository-ReleaseUse] Failed to release invite use")
+		return err
+	}
+	return nil
+}
+
+func (r *InviteRepository) CreateRedemption(ctx context.Context, id int64, email string) error {
+	redemption := &model.InviteRedemption{
+		InviteCodeID: id,
+		Email:        email,
+		RedeemedAt:   time.Now(),
+	}
+	if err := r.db.WithContext(ctx).Create(redemption).Error; err != nil {
+		log.Error().Err(err).Int64("invite_id", id).Msg("[InviteRepository-CreateRedemption] Failed to record invite redemption")
+		return err
+	}
+	return nil
+}
+
+func toInviteEntity(inviteModel *model.InviteCode) *entity.InviteEntity {
+	invite := &entity.InviteEntity{
+		ID:        inviteModel.ID,
+		Code:      inviteModel.Code,
+		Note:      inviteModel.Note,
+		MaxUses:   inviteModel.MaxUses,
+		Uses:      inviteModel.Uses,
+		ExpiresAt: inviteModel.ExpiresAt,
+		RevokedAt: inviteModel.RevokedAt,
+		CreatedAt: inviteModel.CreatedAt,
+	}
+	if inviteModel.CreatedBy != nil {
+		invite.CreatedBy = *inviteModel.CreatedBy
+	}
+	return invite
+}
+
+func NewInviteRepository(db *gorm.DB) port.InviteRepositoryInterface {
+	return &InviteRepository{db: db}
+}
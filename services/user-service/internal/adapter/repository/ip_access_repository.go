@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog/log"
+)
+
+// IPAccessRepository keeps admin IP rules in Redis hashes (cidr -> rule JSON) so every
+// instance sees changes immediately without a deploy
+type IPAccessRepository struct {
+	redisClient *redis.Client
+}
+
+func (r *IPAccessRepository) GetRules(ctx context.Context, listType string) ([]entity.IPRuleEntity, error) {
+	values, err := r.redisClient.HGetAll(ctx, r.getRulesKey(listType)).Result()
+	if err != nil {
+		log.Error().Err(err).Str("list_type", listType).Msg("[IPAccessRepository-GetRules] Failed to get rules")
+		return nil, err
+	}
+
+	rules := make([]entity.IPRuleEntity, 0, len(values))
+	for cidr, data := range values {
+		var rule entity.IPRuleEntity
+		if err := json.Unmarshal([]byte(data), &rule); err != nil {
+			log.Warn().Err(err).Str("cidr", cidr).Msg("[IPAccessRepository-GetRules] Skipping malformed rule")
+			rule = entity.IPRuleEntity{CIDR: cidr}
+		}
+		rule.ListType = listType
+		rules = append(rules, rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool { return rules[i].CIDR < rules[j].CIDR })
+	return rules, nil
+}
+
+func (r *IPAccessRepository) AddRule(ctx context.Context, rule *entity.IPRuleEntity) error {
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return err
+	}
+
+	if err := r.redisClient.HSet(ctx, r.getRulesKey(rule.ListType), rule.CIDR, data).Err(); err != nil {
+		log.Error().Err(err).Str("cidr", rule.CIDR).Str("list_type", rule.ListType).Msg("[IPAccessRepository-AddRule] Failed to add rule")
+		return err
+	}
+
+	log.Info().Str("cidr", rule.CIDR).Str("list_type", rule.ListType).Int64("created_by", rule.CreatedBy).Msg("[IPAccessRepository-AddRule] Rule added")
+	return nil
+}
+
+func (r *IPAccessRepository) RemoveRule(ctx context.Context, listType, cidr string) error {
+	removed, err := r.redisClient.HDel(ctx, r.getRulesKey(listType), cidr).Result()
+	if err != nil {
+		log.Error().Err(err).Str("cidr", cidr).Str("list_type", listType).Msg("[IPAccessRepository-RemoveRule] Failed to remove rule")
+		return err
+	}
+
+	if removed == 0 {
+		return errors.New("rule not found")
+	}
+
+	log.Info().Str("cidr", cidr).Str("list_type", listType).Msg("[IPAccessRepository-RemoveRule] Rule removed")
+	return nil
+}
+
+func (r *IPAccessRepository) getRulesKey(listType string) string {
+	return "admin_ip_rules:" + listType
+}
+
+func NewIPAccessRepository(redisClient *redis.Client) port.IPAccessRepositoryInterface {
+	return &IPAccessRepository{redisClient: redisClient}
+}
@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRepository struct {
+	db *gorm.DB
+}
+
+func (r *JobRepository) Enqueue(ctx context.Context, job *entity.JobEntity) (*entity.JobEntity, error) {
+	payload := "{}"
+	if len(job.Payload) > 0 {
+		payload = string(job.Payload)
+	}
+
+	jobModel := &model.Job{
+		Type:        job.Type,
+		Payload:     payload,
+		Status:      entity.JobStatusPending,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+	}
+	if job.UniqueKey != "" {
+		jobModel.UniqueKey = &job.UniqueKey
+	}
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(jobModel)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("type", job.Type).Msg("[JobRepository-Enqueue] Failed to enqueue job")
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		log.Info().Str("type", job.Type).Str("unique_key", job.UniqueKey).Msg("[JobRepository-Enqueue] Job with unique key already enqueued")
+		return nil, errors.New("job already enqueued")
+	}
+
+	return r.toEntity(jobModel), nil
+}
+
+func (r *JobRepository) ClaimNext(ctx context.Context, types []string, workerID string) (*entity.JobEntity, error) {
+	var jobModel model.Job
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_at <= ? AND type IN ?", entity.JobStatusPending, time.Now(), types).
+			Order("run_at ASC, id ASC").
+			First(&jobModel).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		jobModel.Status = entity.JobStatusRunning
+		jobModel.Attempts++
+		jobModel.LockedBy = workerID
+		jobModel.LockedAt = &now
+
+		return tx.Model(&model.Job{}).Where("id = ?", jobModel.ID).Updates(map[string]interface{}{
+			"status":     jobModel.Status,
+			"attempts":   jobModel.Attempts,
+			"locked_by":  workerID,
+			"locked_at":  now,
+			"updated_at": now,
+		}).Error
+	})
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Str("worker_id", workerID).Msg("[JobRepository-ClaimNext] Failed to claim job")
+		}
+		return nil, err
+	}
+
+	return r.toEntity(&jobModel), nil
+}
+
+func (r *JobRepository) MarkCompleted(ctx context.Context, id int64) error {
+	now := time.Now()
+	if err := r.db.WithContext(ctx).Model(&model.Job{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":       entity.JobStatusCompleted,
+		"last_error":   "",
+		"locked_by":    nil,
+		"locked_at":    nil,
+		"completed_at": now,
+		"updated_at":   now,
+	}).Error; err != nil {
+		log.Error().Err(err).Int64("job_id", id).Msg("[JobRepository-MarkCompleted] Failed to mark job completed")
+		return err
+	}
+
+	return nil
+}
+
+func (r *JobRepository) MarkFailed(ctx context.Context, id int64, lastError string, retryAt *time.Time) error {
+	updates := map[string]interface{}{
+		"status":     entity.JobStatusFailed,
+		"last_error": lastError,
+		"locked_by":  nil,
+		"locked_at":  nil,
+		"updated_at": time.Now(),
+	}
+	if retryAt != nil {
+		updates["status"] = entity.JobStatusPending
+		updates["run_at"] = *retryAt
+	}
+
+	if err := r.db.WithContext(ctx).Model(&model.Job{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		log.Error().Err(err).Int64("job_id", id).Msg("[JobRepository-MarkFailed] Failed to mark job failed")
+		return err
+	}
+
+	return nil
+}
+
+func (r *JobRepository) RequeueStale(ctx context.Context, lockedBefore time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.Job{}).
+		Where("status = ? AND locked_at < ?", entity.JobStatusRunning, lockedBefore).
+		Updates(map[string]interface{}{
+			"status":     entity.JobStatusPending,
+			"last_error": "worker lock expired",
+			"locked_by":  nil,
+			"locked_at":  nil,
+			"run_at":     time.Now(),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msg("[JobRepository-RequeueStale] Failed to requeue stale jobs")
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *JobRepository) DeleteCompletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND completed_at < ?", entity.JobStatusCompleted, before).
+		Delete(&model.Job{})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msg("[JobRepository-DeleteCompletedBefore] Failed to delete completed jobs")
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *JobRepository) GetJobs(ctx context.Context, status, jobType string, page, limit int) ([]entity.JobEntity, int64, error) {
+	var jobs []model.Job
+	var totalCount int64
+
+	query := r.db.WithContext(ctx).Model(&model.Job{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if jobType != "" {
+		query = query.Where("type = ?", jobType)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Str("status", status).Msg("[JobRepository-GetJobs] Failed to count jobs")
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&jobs).Error; err != nil {
+		log.Error().Err(err).Str("status", status).Msg("[JobRepository-GetJobs] Failed to get jobs")
+		return nil, 0, err
+	}
+
+	jobEntities := make([]entity.JobEntity, 0, len(jobs))
+	for i := range jobs {
+		jobEntities = append(jobEntities, *r.toEntity(&jobs[i]))
+	}
+
+	return jobEntities, totalCount, nil
+}
+
+func (r *JobRepository) RetryJob(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Model(&model.Job{}).
+		Where("id = ? AND status = ?", id, entity.JobStatusFailed).
+		Updates(map[string]interface{}{
+			"status":     entity.JobStatusPending,
+			"attempts":   0,
+			"run_at":     time.Now(),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("job_id", id).Msg("[JobRepository-RetryJob] Failed to retry job")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		var count int64
+		if err := r.db.WithContext(ctx).Model(&model.Job{}).Where("id = ?", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return errors.New("job is not failed")
+	}
+
+	log.Info().Int64("job_id", id).Msg("[JobRepository-RetryJob] Job requeued")
+	return nil
+}
+
+func (r *JobRepository) Release(ctx context.Context, jobType string) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.Job{}).
+		Where("type = ? AND status IN ?", jobType, []string{entity.JobStatusPending, entity.JobStatusFailed}).
+		Updates(map[string]interface{}{
+			"status":     entity.JobStatusPending,
+			"attempts":   0,
+			"run_at":     time.Now(),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("type", jobType).Msg("[JobRepository-Release] Failed to release jobs")
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *JobRepository) toEntity(jobModel *model.Job) *entity.JobEntity {
+	jobEntity := &entity.JobEntity{
+		ID:          jobModel.ID,
+		Type:        jobModel.Type,
+		Payload:     json.RawMessage(jobModel.Payload),
+		Status:      jobModel.Status,
+		Attempts:    jobModel.Attempts,
+		MaxAttempts: jobModel.MaxAttempts,
+		RunAt:       jobModel.RunAt,
+		LastError:   jobModel.LastError,
+		LockedBy:    jobModel.LockedBy,
+		LockedAt:    jobModel.LockedAt,
+		CompletedAt: jobModel.CompletedAt,
+		CreatedAt:   jobModel.CreatedAt,
+		UpdatedAt:   jobModel.UpdatedAt,
+	}
+
+	if jobModel.UniqueKey != nil {
+		jobEntity.UniqueKey = *jobModel.UniqueKey
+	}
+
+	return jobEntity
+}
+
+func NewJobRepository(db *gorm.DB) port.JobRepositoryInterface {
+	return &JobRepository{db: db}
+}
@@ -0,0 +1,435 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LedgerRepository struct {
+	db *gorm.DB
+}
+
+func (r *LedgerRepository) RecordTransaction(ctx context.Context, transaction *entity.LedgerTransactionEntity) (*entity.LedgerTransactionEntity, error) {
+	var recorded *entity.LedgerTransactionEntity
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		recorded, err = r.recordTransaction(tx, transaction)
+		return err
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", transaction.VendorID).Str("type", transaction.Type).Str("reference_id", transaction.ReferenceID).Msg("[LedgerRepository-RecordTransaction] Failed to record transaction")
+		return nil, err
+	}
+
+	log.Info().Int64("transaction_id", recorded.ID).Int64("vendor_id", recorded.VendorID).Str("type", recorded.Type).Msg("[LedgerRepository-RecordTransaction] Transaction recorded successfully")
+	return recorded, nil
+}
+
+func (r *LedgerRepository) GetVendorBalance(ctx context.Context, vendorID int64) (*entity.VendorBalanceEntity, error) {
+	totals, err := r.accountTotals(r.db.WithContext(ctx), vendorID)
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Msg("[LedgerRepository-GetVendorBalance] Failed to sum ledger entries")
+		return nil, err
+	}
+
+	var totalEarned, totalPaidOut int64
+	if err := r.db.WithContext(ctx).Model(&model.LedgerEntry{}).
+		Joins("JOIN ledger_transactions ON ledger_transactions.id = ledger_entries.transaction_id").
+		Where("ledger_entries.vendor_id = ? AND ledger_entries.account = ? AND ledger_transactions.type = ?", vendorID, entity.LedgerAccountVendorPayable, entity.LedgerTransactionOrderEarning).
+		Select("COALESCE(SUM(ledger_entries.amount), 0)").Scan(&totalEarned).Error; err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Msg("[LedgerRepository-GetVendorBalance] Failed to sum earnings")
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).Model(&model.VendorWithdrawal{}).
+		Where("vendor_id = ? AND status = ?", vendorID, entity.WithdrawalStatusApproved).
+		Select("COALESCE(SUM(amount), 0)").Scan(&totalPaidOut).Error; err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Msg("[LedgerRepository-GetVendorBalance] Failed to sum payouts")
+		return nil, err
+	}
+
+	return &entity.VendorBalanceEntity{
+		VendorID:      vendorID,
+		Available:     totals[entity.LedgerAccountVendorPayable],
+		PendingPayout: totals[entity.LedgerAccountVendorPayoutPending],
+		TotalEarned:   totalEarned,
+		TotalPaidOut:  totalPaidOut,
+	}, nil
+}
+
+func (r *LedgerRepository) GetVendorEntries(ctx context.Context, vendorID int64, page, limit int) ([]entity.LedgerEntryEntity, int64, error) {
+	type entryRow struct {
+		model.LedgerEntry
+		TransactionType string
+		ReferenceID     string
+	}
+
+	var rows []entryRow
+	var totalCount int64
+
+	// Vendors only see their own side of each transaction
+	query := r.db.WithContext(ctx).Model(&model.LedgerEntry{}).
+		Joins("JOIN ledger_transactions ON ledger_transactions.id = ledger_entries.transaction_id").
+		Where("ledger_entries.vendor_id = ? AND ledger_entries.account IN ?", vendorID,
+			[]string{entity.LedgerAccountVendorPayable, entity.LedgerAccountVendorPayoutPending})
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Msg("[LedgerRepository-GetVendorEntries] Failed to count entries")
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Select("ledger_entries.*, ledger_transactions.type AS transaction_type, ledger_transactions.reference_id").
+		Order("ledger_entries.id DESC").Offset(offset).Limit(limit).Scan(&rows).Error; err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Msg("[LedgerRepository-GetVendorEntries] Failed to get entries")
+		return nil, 0, err
+	}
+
+	entries := make([]entity.LedgerEntryEntity, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, entity.LedgerEntryEntity{
+			ID:              row.ID,
+			TransactionID:   row.TransactionID,
+			TransactionType: row.TransactionType,
+			ReferenceID:     row.ReferenceID,
+			VendorID:        row.VendorID,
+			Account:         row.Account,
+			Amount:          row.Amount,
+			CreatedAt:       row.CreatedAt,
+		})
+	}
+
+	return entries, totalCount, nil
+}
+
+func (r *LedgerRepository) CreateWithdrawal(ctx context.Context, withdrawal *entity.WithdrawalEntity) (*entity.WithdrawalEntity, error) {
+	withdrawalModel := &model.VendorWithdrawal{
+		VendorID:      withdrawal.VendorID,
+		Amount:        withdrawal.Amount,
+		BankName:      withdrawal.BankName,
+		AccountNumber: withdrawal.AccountNumber,
+		AccountName:   withdrawal.AccountName,
+		Status:        entity.WithdrawalStatusPending,
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serialize balance checks per vendor so concurrent requests cannot overdraw
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&model.Vendor{}, withdrawal.VendorID).Error; err != nil {
+			return err
+		}
+
+		totals, err := r.accountTotals(tx, withdrawal.VendorID)
+		if err != nil {
+			return err
+		}
+
+		if totals[entity.LedgerAccountVendorPayable] < withdrawal.Amount {
+			return errors.New("insufficient balance")
+		}
+
+		if err := tx.Create(withdrawalModel).Error; err != nil {
+			return err
+		}
+
+		_, err = r.recordTransaction(tx, &entity.LedgerTransactionEntity{
+			VendorID:    withdrawal.VendorID,
+			Type:        entity.LedgerTransactionWithdrawalHold,
+			ReferenceID: strconv.FormatInt(withdrawalModel.ID, 10),
+			Description: "Funds held for withdrawal request",
+			Entries: []entity.LedgerEntryEntity{
+				{VendorID: withdrawal.VendorID, Account: entity.LedgerAccountVendorPayable, Amount: -withdrawal.Amount},
+				{VendorID: withdrawal.VendorID, Account: entity.LedgerAccountVendorPayoutPending, Amount: withdrawal.Amount},
+			},
+		})
+		return err
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", withdrawal.VendorID).Int64("amount", withdrawal.Amount).Msg("[LedgerRepository-CreateWithdrawal] Failed to create withdrawal")
+		return nil, err
+	}
+
+	log.Info().Int64("withdrawal_id", withdrawalModel.ID).Int64("vendor_id", withdrawal.VendorID).Msg("[LedgerRepository-CreateWithdrawal] Withdrawal created successfully")
+	return r.toWithdrawalEntity(withdrawalModel), nil
+}
+
+func (r *LedgerRepository) GetWithdrawalByID(ctx context.Context, id int64) (*entity.WithdrawalEntity, error) {
+	var withdrawalModel model.VendorWithdrawal
+	if err := r.db.WithContext(ctx).First(&withdrawalModel, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			log.Info().Int64("withdrawal_id", id).Msg("[LedgerRepository-GetWithdrawalByID] Withdrawal not found")
+			return nil, gorm.ErrRecordNotFound
+		}
+		log.Error().Err(err).Int64("withdrawal_id", id).Msg("[LedgerRepository-GetWithdrawalByID] Failed to get withdrawal")
+		return nil, err
+	}
+
+	return r.toWithdrawalEntity(&withdrawalModel), nil
+}
+
+func (r *LedgerRepository) GetWithdrawals(ctx context.Context, vendorID int64, status string, page, limit int) ([]entity.WithdrawalEntity, int64, error) {
+	var withdrawals []model.VendorWithdrawal
+	var totalCount int64
+
+	query := r.db.WithContext(ctx).Model(&model.VendorWithdrawal{})
+	if vendorID > 0 {
+		query = query.Where("vendor_id = ?", vendorID)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Msg("[LedgerRepository-GetWithdrawals] Failed to count withdrawals")
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&withdrawals).Error; err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Msg("[LedgerRepository-GetWithdrawals] Failed to get withdrawals")
+		return nil, 0, err
+	}
+
+	withdrawalEntities := make([]entity.WithdrawalEntity, 0, len(withdrawals))
+	for i := range withdrawals {
+		withdrawalEntities = append(withdrawalEntities, *r.toWithdrawalEntity(&withdrawals[i]))
+	}
+
+	return withdrawalEntities, totalCount, nil
+}
+
+func (r *LedgerRepository) ApproveWithdrawal(ctx context.Context, id, reviewerID int64) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		withdrawal, err := r.review(tx, id, reviewerID, entity.WithdrawalStatusApproved, "")
+		if err != nil {
+			return err
+		}
+
+		_, err = r.recordTransaction(tx, &entity.LedgerTransactionEntity{
+			VendorID:    withdrawal.VendorID,
+			Type:        entity.LedgerTransactionWithdrawalPayout,
+			ReferenceID: strconv.FormatInt(id, 10),
+			Description: "Withdrawal paid out",
+			CreatedBy:   reviewerID,
+			Entries: []entity.LedgerEntryEntity{
+				{VendorID: withdrawal.VendorID, Account: entity.LedgerAccountVendorPayoutPending, Amount: -withdrawal.Amount},
+				{VendorID: withdrawal.VendorID, Account: entity.LedgerAccountPlatformCash, Amount: withdrawal.Amount},
+			},
+		})
+		return err
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("withdrawal_id", id).Msg("[LedgerRepository-ApproveWithdrawal] Failed to approve withdrawal")
+		return err
+	}
+
+	log.Info().Int64("withdrawal_id", id).Int64("reviewer_id", reviewerID).Msg("[LedgerRepository-ApproveWithdrawal] Withdrawal approved successfully")
+	return nil
+}
+
+func (r *LedgerRepository) RejectWithdrawal(ctx context.Context, id, reviewerID int64, reason string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		withdrawal, err := r.review(tx, id, reviewerID, entity.WithdrawalStatusRejected, reason)
+		if err != nil {
+			return err
+		}
+
+		// Release the held funds back to the vendor's available balance
+		_, err = r.recordTransaction(tx, &entity.LedgerTransactionEntity{
+			VendorID:    withdrawal.VendorID,
+			Type:        entity.LedgerTransactionWithdrawalRelease,
+			ReferenceID: strconv.FormatInt(id, 10),
+			Description: "Withdrawal rejected: " + reason,
+			CreatedBy:   reviewerID,
+			Entries: []entity.LedgerEntryEntity{
+				{VendorID: withdrawal.VendorID, Account: entity.LedgerAccountVendorPayoutPending, Amount: -withdrawal.Amount},
+				{VendorID: withdrawal.VendorID, Account: entity.LedgerAccountVendorPayable, Amount: withdrawal.Amount},
+			},
+		})
+		return err
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("withdrawal_id", id).Msg("[LedgerRepository-RejectWithdrawal] Failed to reject withdrawal")
+		return err
+	}
+
+	log.Info().Int64("withdrawal_id", id).Int64("reviewer_id", reviewerID).Msg("[LedgerRepository-RejectWithdrawal] Withdrawal rejected successfully")
+	return nil
+}
+
+func (r *LedgerRepository) GetLedgerAudit(ctx context.Context) (*entity.LedgerAuditEntity, error) {
+	totals, err := r.accountTotals(r.db.WithContext(ctx), 0)
+	if err != nil {
+		log.Error().Err(err).Msg("[LedgerRepository-GetLedgerAudit] Failed to sum ledger entries")
+		return nil, err
+	}
+
+	audit := &entity.LedgerAuditEntity{AccountTotals: totals}
+	for _, total := range totals {
+		audit.NetTotal += total
+	}
+
+	if err := r.db.WithContext(ctx).Model(&model.LedgerTransaction{}).Count(&audit.TransactionCount).Error; err != nil {
+		log.Error().Err(err).Msg("[LedgerRepository-GetLedgerAudit] Failed to count transactions")
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).Model(&model.LedgerEntry{}).
+		Select("transaction_id").Group("transaction_id").Having("SUM(amount) <> 0").
+		Order("transaction_id").Scan(&audit.UnbalancedTransactions).Error; err != nil {
+		log.Error().Err(err).Msg("[LedgerRepository-GetLedgerAudit] Failed to find unbalanced transactions")
+		return nil, err
+	}
+
+	return audit, nil
+}
+
+func (r *LedgerRepository) recordTransaction(tx *gorm.DB, transaction *entity.LedgerTransactionEntity) (*entity.LedgerTransactionEntity, error) {
+	if !transaction.IsBalanced() {
+		return nil, errors.New("ledger transaction is not balanced")
+	}
+
+	var existing int64
+	if err := tx.Model(&model.LedgerTransaction{}).
+		Where("type = ? AND reference_id = ?", transaction.Type, transaction.ReferenceID).
+		Count(&existing).Error; err != nil {
+		return nil, err
+	}
+	if existing > 0 {
+		return nil, errors.New("ledger transaction already recorded")
+	}
+
+	transactionModel := &model.LedgerTransaction{
+		VendorID:    transaction.VendorID,
+		Type:        transaction.Type,
+		ReferenceID: transaction.ReferenceID,
+		Description: transaction.Description,
+	}
+	if transaction.CreatedBy > 0 {
+		transactionModel.CreatedBy = &transaction.CreatedBy
+	}
+
+	for _, entry := range transaction.Entries {
+		transactionModel.Entries = append(transactionModel.Entries, model.LedgerEntry{
+			VendorID: entry.VendorID,
+			Account:  entry.Account,
+			Amount:   entry.Amount,
+		})
+	}
+
+	if err := tx.Create(transactionModel).Error; err != nil {
+		return nil, err
+	}
+
+	return r.toTransactionEntity(transactionModel), nil
+}
+
+// review moves a pending withdrawal to its final status and returns it for the ledger entries
+func (r *LedgerRepository) review(tx *gorm.DB, id, reviewerID int64, status, reason string) (*model.VendorWithdrawal, error) {
+	var withdrawalModel model.VendorWithdrawal
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&withdrawalModel, id).Error; err != nil {
+		return nil, err
+	}
+
+	if withdrawalModel.Status != entity.WithdrawalStatusPending {
+		return nil, errors.New("withdrawal is not pending")
+	}
+
+	if err := tx.Model(&withdrawalModel).Updates(map[string]interface{}{
+		"status":           status,
+		"rejection_reason": reason,
+		"reviewed_by":      reviewerID,
+		"reviewed_at":      time.Now(),
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return &withdrawalModel, nil
+}
+
+// accountTotals sums entries per account, for one vendor or for the whole ledger when vendorID is 0
+func (r *LedgerRepository) accountTotals(db *gorm.DB, vendorID int64) (map[string]int64, error) {
+	var rows []struct {
+		Account string
+		Total   int64
+	}
+
+	query := db.Model(&model.LedgerEntry{}).Select("account, COALESCE(SUM(amount), 0) AS total").Group("account")
+	if vendorID > 0 {
+		query = query.Where("vendor_id = ?", vendorID)
+	}
+
+	if err := query.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		totals[row.Account] = row.Total
+	}
+
+	return totals, nil
+}
+
+func (r *LedgerRepository) toTransactionEntity(transactionModel *model.LedgerTransaction) *entity.LedgerTransactionEntity {
+	transactionEntity := &entity.LedgerTransactionEntity{
+		ID:          transactionModel.ID,
+		VendorID:    transactionModel.VendorID,
+		Type:        transactionModel.Type,
+		ReferenceID: transactionModel.ReferenceID,
+		Description: transactionModel.Description,
+		CreatedAt:   transactionModel.CreatedAt,
+	}
+
+	if transactionModel.CreatedBy != nil {
+		transactionEntity.CreatedBy = *transactionModel.CreatedBy
+	}
+
+	for _, entry := range transactionModel.Entries {
+		transactionEntity.Entries = append(transactionEntity.Entries, entity.LedgerEntryEntity{
+			ID:              entry.ID,
+			TransactionID:   entry.TransactionID,
+			TransactionType: transactionModel.Type,
+			ReferenceID:     transactionModel.ReferenceID,
+			VendorID:        entry.VendorID,
+			Account:         entry.Account,
+			Amount:          entry.Amount,
+			CreatedAt:       entry.CreatedAt,
+		})
+	}
+
+	return transactionEntity
+}
+
+func (r *LedgerRepository) toWithdrawalEntity(withdrawalModel *model.VendorWithdrawal) *entity.WithdrawalEntity {
+	withdrawalEntity := &entity.WithdrawalEntity{
+		ID:              withdrawalModel.ID,
+		VendorID:        withdrawalModel.VendorID,
+		Amount:          withdrawalModel.Amount,
+		BankName:        withdrawalModel.BankName,
+		AccountNumber:   withdrawalModel.AccountNumber,
+		AccountName:     withdrawalModel.AccountName,
+		Status:          withdrawalModel.Status,
+		RejectionReason: withdrawalModel.RejectionReason,
+		ReviewedAt:      withdrawalModel.ReviewedAt,
+		CreatedAt:       withdrawalModel.CreatedAt,
+	}
+
+	if withdrawalModel.ReviewedBy != nil {
+		withdrawalEntity.ReviewedBy = *withdrawalModel.ReviewedBy
+	}
+
+	return withdrawalEntity
+}
+
+func NewLedgerRepository(db *gorm.DB) port.LedgerRepositoryInterface {
+	return &LedgerRepository{db: db}
+}
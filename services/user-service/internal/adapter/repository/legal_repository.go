@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type LegalRepository struct {
+	db *gorm.DB
+}
+
+func (r *LegalRepository) CreateDocument(ctx context.Context, doc *entity.LegalDocumentEntity) (*entity.LegalDocumentEntity, error) {
+	docModel := &model.LegalDocument{
+		Kind:        doc.Kind,
+		Version:     doc.Version,
+		URL:         doc.URL,
+		PublishedAt: time.Now(),
+	}
+	if doc.PublishedBy > 0 {
+		docModel.PublishedBy = &doc.PublishedBy
+	}
+
+	if err := r.db.WithContext(ctx).Create(docModel).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, errors.New("legal document version already exists")
+		}
+		log.Error().Err(err).Str("kind", doc.Kind).Msg("[LegalRepository-CreateDocument] Failed to create legal document")
+		return nil, err
+	}
+
+	return toLegalDocumentEntity(docModel), nil
+}
+
+func (r *LegalRepository) GetDocuments(ctx context.Context, kind string) ([]entity.LegalDocumentEntity, error) {
+	var docs []model.LegalDocument
+	query := r.db.WithContext(ctx).Order("published_at DESC, id DESC")
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if err := query.Find(&docs).Error; err != nil {
+		log.Error().Err(err).Msg("[LegalRepository-GetDocuments] Failed to get legal documents")
+		return nil, err
+	}
+
+	return toLegalDocumentEntities(docs), nil
+}
+
+func (r *LegalRepository) GetCurrentDocuments(ctx context.Context) ([]entity.LegalDocumentEntity, error) {
+	var docs []model.LegalDocument
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT ON (kind) * FROM legal_documents
+		ORDER BY kind, published_at DESC, id DESC`).Scan(&docs).Error
+	if err != nil {
+		log.Error().Err(err).Msg("[LegalRepository-GetCurrentDocuments] Failed to get current legal documents")
+		return nil, err
+	}
+
+	return toLegalDocumentEntities(docs), nil
+}
+
+func (r *LegalRepository) GetUserConsent(ctx context.Context, userID int64) (*entity.ConsentStatusEntity, error) {
+	var row struct {
+		AcceptedTermsVersion   *string
+		AcceptedPrivacyVersion *string
+		ConsentedAt            *time.Time
+	}
+	result := r.db.WithContext(ctx).Table("users").
+		Select("accepted_terms_version, accepted_privacy_version, consented_at").
+		Where("id = ? AND deleted_at IS NULL", userID).
+		Limit(1).Scan(&row)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("user_id", userID).Msg("[LegalRepository-GetUserConsent] Failed to get user consent")
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	status := &entity.ConsentStatusEntity{ConsentedAt: row.ConsentedAt}
+	if row.AcceptedTermsVersion != nil {
+		status.AcceptedTerms = *row.AcceptedTermsVersion
+	}
+	if row.AcceptedPrivacyVersion != nil {
+		status.AcceptedPrivacy = *row.AcceptedPrivacyVersion
+	}
+	return status, nil
+}
+
+func (r *LegalRepository) GetUserIDByEmail(ctx context.Context, email string) (int64, error) {
+	var ids []int64
+	if err := r.db.WithContext(ctx).Table("users").Where("email = ? AND deleted_at IS NULL", email).Limit(1).Pluck("id", &ids).Error; err != nil {
+		log.Error().Err(err).Msg("[LegalRepository-GetUserIDByEmail] Failed to get user")
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return ids[0], nil
+}
+
+func (r *LegalRepository) SaveConsent(ctx context.Context, consent *entity.ConsentEntity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		updates := map[string]interface{}{"consented_at": consent.AcceptedAt}
+		if consent.TermsVersion != "" {
+			updates["accepted_terms_version"] = consent.TermsVersion
+		}
+		if consent.PrivacyVersion != "" {
+			updates["accepted_privacy_version"] = consent.PrivacyVersion
+		}
+		if err := tx.Table("users").Where("id = ?", consent.UserID).Updates(updates).Error; err != nil {
+			log.Error().Err(err).Int64("user_id", consent.UserID).Msg("[LegalRepository-SaveConsent] Failed to update user consent")
+			return err
+		}
+
+		var history []model.UserConsent
+		for _, accepted := range []struct{ kind, version string }{
+			{entity.LegalDocumentTerms, consent.TermsVersion},
+			{entity.LegalDocumentPrivacy, consent.PrivacyVersion},
+		} {
+			if accepted.version == "" {
+				continue
+			}
+			history = append(history, model.UserConsent{
+				UserID:     consent.UserID,
+				Kind:       accepted.kind,
+				Version:    accepted.version,
+				IPAddress:  consent.IPAddress,
+				UserAgent:  consent.UserAgent,
+				AcceptedAt: consent.AcceptedAt,
+			})
+		}
+		if len(history) == 0 {
+			return nil
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			log.Error().Err(err).Int64("user_id", consent.UserID).Msg("[LegalRepository-SaveConsent] Failed to record consent history")
+			return err
+		}
+		return nil
+	})
+}
+
+func (r *LegalRepository) GetConsentHistory(ctx context.Context, userID int64) ([]entity.UserConsentEntity, error) {
+	var consents []model.UserConsent
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("accepted_at DESC, id DESC").Find(&consents).Error; err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[LegalRepository-GetConsentHistory] Failed to get consent history")
+		return nil, err
+	}
+
+	entities := make([]entity.UserConsentEntity, 0, len(consents))
+	for _, c := range consents {
+		entities = append(entities, entity.UserConsentEntity{
+			ID:         c.ID,
+			UserID:     c.UserID,
+			Kind:       c.Kind,
+			Version:    c.Version,
+			IPAddress:  c.IPAddress,
+			UserAgent:  c.UserAgent,
+			AcceptedAt: c.AcceptedAt,
+		})
+	}
+	return entities, nil
+}
+
+func toLegalDocumentEntities(docs []model.LegalDocument) []entity.LegalDocumentEntity {
+	entities := make([]entity.LegalDocumentEntity, 0, len(docs))
+	for i := range docs {
+		entities = append(entities, *toLegalDocumentEntity(&docs[i]))
+	}
+	return entities
+}
+
+func toLegalDocumentEntity(docModel *model.LegalDocument) *entity.LegalDocumentEntity {
+	doc := &entity.LegalDocumentEntity{
+		ID:          docModel.ID,
+		Kind:        docModel.Kind,
+		Version:     docModel.Version,
+		URL:         docModel.URL,
+		PublishedAt: docModel.PublishedAt,
+	}
+	if docModel.PublishedBy != nil {
+		doc.PublishedBy = *docModel.PublishedBy
+	}
+	return doc
+}
+
+func NewLegalRepository(db *gorm.DB) port.LegalRepositoryInterface {
+	return &LegalRepository{db: db}
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type OnboardingRepository struct {
+	db *gorm.DB
+}
+
+func (r *OnboardingRepository) GetState(ctx context.Context, userID int64) (*entity.OnboardingStateEntity, error) {
+	modelOnboarding := model.UserOnboarding{}
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&modelOnboarding).Error
+	if err == gorm.ErrRecordNotFound {
+		return &entity.OnboardingStateEntity{UserID: userID}, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[OnboardingRepository-GetState] Failed to get onboarding state")
+		return nil, err
+	}
+
+	return &entity.OnboardingStateEntity{
+		UserID:       modelOnboarding.UserID,
+		DismissedAt:  modelOnboarding.DismissedAt,
+		FirstOrderAt: modelOnboarding.FirstOrderAt,
+		CompletedAt:  modelOnboarding.CompletedAt,
+	}, nil
+}
+
+func (r *OnboardingRepository) Dismiss(ctx context.Context, userID int64) error {
+	err := r.db.WithContext(ctx).Exec(`
+		INSERT INTO user_onboarding (user_id, dismissed_at, created_at) VALUES (?, NOW(), NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET dismissed_at = COALESCE(user_onboarding.dismissed_at, EXCLUDED.dismissed_at), updated_at = NOW()`,
+		userID,
+	).Error
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[OnboardingRepository-Dismiss] Failed to dismiss onboarding")
+		return err
+	}
+	return nil
+}
+
+func (r *OnboardingRepository) MarkFirstOrder(ctx context.Context, userID int64, orderedAt time.Time) error {
+	err := r.db.WithContext(ctx).Exec(`
+		INSERT INTO user_onboarding (user_id, first_order_at, created_at) VALUES (?, ?, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET first_order_at = LEAST(COALESCE(user_onboarding.first_order_at, EXCLUDED.first_order_at), EXCLUDED.first_order_at), updated_at = NOW()`,
+		userID, orderedAt,
+	).Error
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[OnboardingRepository-MarkFirstOrder] Failed to record first order")
+		return err
+	}
+	return nil
+}
+
+func (r *OnboardingRepository) MarkCompleted(ctx context.Context, userID int64) (bool, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO user_onboarding (user_id, completed_at, created_at) VALUES (?, NOW(), NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET completed_at = EXCLUDED.completed_at, updated_at = NOW()
+		WHERE user_onboarding.completed_at IS NULL`,
+		userID,
+	)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("user_id", userID).Msg("[OnboardingRepository-MarkCompleted] Failed to mark onboarding completed")
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func NewOnboardingRepository(db *gorm.DB) port.OnboardingRepositoryInterface {
+	return &OnboardingRepository{db: db}
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"user-service/internal/core/domain/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const versionField = "Version"
+
+// RegisterOptimisticLocking installs a GORM update hook for models with a Version field.
+// When the model passed to Model() carries a non-zero Version, the update only matches
+// that version and bumps it by one; a stale version therefore affects zero rows.
+// Updates without a version (status flags, geocoded address, merges) are left alone.
+func RegisterOptimisticLocking(db *gorm.DB) error {
+	return db.Callback().Update().Before("gorm:update").Register("app:optimistic_lock", optimisticLock)
+}
+
+func optimisticLock(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return
+	}
+
+	field := db.Statement.Schema.LookUpField(versionField)
+	if field == nil {
+		return
+	}
+
+	value, isZero := field.ValueOf(db.Statement.Context, db.Statement.ReflectValue)
+	if isZero {
+		return
+	}
+	version, ok := value.(int64)
+	if !ok {
+		return
+	}
+
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: version},
+	}})
+	db.Statement.SetColumn(field.DBName, version+1)
+}
+
+// versionConflict tells a stale update apart from a missing row after zero rows were affected
+func versionConflict(db *gorm.DB, table string, id int64) error {
+	var current struct{ Version int64 }
+	err := db.Table(table).Select("version").Where("id = ? AND deleted_at IS NULL", id).Take(&current).Error
+	if err != nil {
+		return err
+	}
+	return &entity.VersionConflictError{CurrentVersion: current.Version}
+}
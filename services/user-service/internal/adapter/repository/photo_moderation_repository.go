@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type PhotoModerationRepository struct {
+	db *gorm.DB
+}
+
+func (r *PhotoModerationRepository) Create(ctx context.Context, moderation *entity.PhotoModerationEntity) (*entity.PhotoModerationEntity, error) {
+	moderationModel := &model.PhotoModeration{
+		UserID:   moderation.UserID,
+		ImageURL: moderation.ImageURL,
+		Status:   entity.PhotoModerationPending,
+		Labels:   "[]",
+	}
+
+	if err := r.db.WithContext(ctx).Create(moderationModel).Error; err != nil {
+		log.Error().Err(err).Int64("user_id", moderation.UserID).Msg("[PhotoModerationRepository-Create] Failed to create photo moderation")
+		return nil, err
+	}
+
+	return r.toEntity(moderationModel), nil
+}
+
+func (r *PhotoModerationRepository) GetByID(ctx context.Context, id int64) (*entity.PhotoModerationEntity, error) {
+	var moderationModel model.PhotoModeration
+	if err := r.db.WithContext(ctx).First(&moderationModel, id).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Int64("moderation_id", id).Msg("[PhotoModerationRepository-GetByID] Failed to get photo moderation")
+		}
+		return nil, err
+	}
+
+	return r.toEntity(&moderationModel), nil
+}
+
+func (r *PhotoModerationRepository) List(ctx context.Context, status string, page, limit int) ([]entity.PhotoModerationEntity, int64, error) {
+	var moderations []model.PhotoModeration
+	var totalCount int64
+
+	query := r.db.WithContext(ctx).Model(&model.PhotoModeration{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Str("status", status).Msg("[PhotoModerationRepository-List] Failed to count photo moderations")
+		return nil, 0, err
+	}
+
+	// Oldest first, so the review queue is worked in upload order
+	offset := (page - 1) * limit
+	if err := query.Order("created_at ASC").Offset(offset).Limit(limit).Find(&moderations).Error; err != nil {
+		log.Error().Err(err).Str("status", status).Msg("[PhotoModerationRepository-List] Failed to get photo moderations")
+		return nil, 0, err
+	}
+
+	moderationEntities := make([]entity.PhotoModerationEntity, 0, len(moderations))
+	for i := range moderations {
+		moderationEntities = append(moderationEntities, *r.toEntity(&moderations[i]))
+	}
+
+	return moderationEntities, totalCount, nil
+}
+
+func (r *PhotoModerationRepository) UpdateStatus(ctx context.Context, id int64, fromStatuses []string, update *entity.PhotoModerationEntity) error {
+	updates := map[string]interface{}{
+		"status":     update.Status,
+		"updated_at": time.Now(),
+	}
+	if update.Score != nil {
+		updates["score"] = *update.Score
+	}
+	if update.Labels != nil {
+		labels, err := json.Marshal(update.Labels)
+		if err != nil {
+			return err
+		}
+		updates["labels"] = string(labels)
+	}
+	if update.ReviewedBy > 0 {
+		updates["reviewed_by"] = update.ReviewedBy
+		updates["reviewed_at"] = time.Now()
+		updates["review_note"] = update.ReviewNote
+	}
+
+	// The status condition keeps the worker and two admins from deciding the same photo twice
+	result := r.db.WithContext(ctx).Model(&model.PhotoModeration{}).
+		Where("id = ? AND status IN ?", id, fromStatuses).
+		Updates(updates)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("moderation_id", id).Msg("[PhotoModerationRepository-UpdateStatus] Failed to update photo moderation")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("photo moderation already decided")
+	}
+
+	return nil
+}
+
+func (r *PhotoModerationRepository) toEntity(moderationModel *model.PhotoModeration) *entity.PhotoModerationEntity {
+	moderation := &entity.PhotoModerationEntity{
+		ID:         moderationModel.ID,
+		UserID:     moderationModel.UserID,
+		ImageURL:   moderationModel.ImageURL,
+		Status:     moderationModel.Status,
+		Score:      moderationModel.Score,
+		Labels:     []string{},
+		ReviewNote: moderationModel.ReviewNote,
+		ReviewedAt: moderationModel.ReviewedAt,
+		CreatedAt:  moderationModel.CreatedAt,
+		UpdatedAt:  moderationModel.UpdatedAt,
+	}
+	if moderationModel.ReviewedBy != nil {
+		moderation.ReviewedBy = *moderationModel.ReviewedBy
+	}
+
+	if err := json.Unmarshal([]byte(moderationModel.Labels), &moderation.Labels); err != nil {
+		log.Warn().Err(err).Int64("moderation_id", moderationModel.ID).Msg("[PhotoModerationRepository-toEntity] Invalid labels")
+	}
+
+	return moderation
+}
+
+func NewPhotoModerationRepository(db *gorm.DB) port.PhotoModerationRepositoryInterface {
+	return &PhotoModerationRepository{db: db}
+}
@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"user-service/config"
+	"user-service/utils/pii"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// RegisterPIIEncryption hands the configured keys to the "pii" serializer (see utils/pii).
+// Without keys values are read and written as plaintext.
+func RegisterPIIEncryption(settings config.PIIEncryption) error {
+	keys, err := settings.DecodedKeys()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		pii.Use(nil)
+		return nil
+	}
+
+	cipher, err := pii.NewCipher(settings.KeyID, keys)
+	if err != nil {
+		return err
+	}
+	pii.Use(cipher)
+	return nil
+}
+
+// PIITable lists the encrypted columns of one table
+type PIITable struct {
+	Table   string
+	Columns []string
+}
+
+// PIITables finds the columns whose model fields use the "pii" serializer
+func PIITables(models []interface{}) ([]PIITable, error) {
+	var tables []PIITable
+	for _, model := range models {
+		s, err := schema.Parse(model, schemaCache, schema.NamingStrategy{})
+		if err != nil {
+			return nil, err
+		}
+
+		var columns []string
+		for _, field := range s.Fields {
+			if _, ok := field.Serializer.(pii.Serializer); ok && field.DBName != "" {
+				columns = append(columns, field.DBName)
+			}
+		}
+		if len(columns) > 0 {
+			tables = append(tables, PIITable{Table: s.Table, Columns: columns})
+		}
+	}
+	return tables, nil
+}
+
+// PIIBackfillReport counts, for one table, the rows read and the rows that were not yet in the
+// form the current key writes
+type PIIBackfillReport struct {
+	Table     string
+	Columns   []string
+	Scanned   int64
+	Rewritten int64
+}
+
+// BackfillPIIEncryption rewrites every PII value that is plaintext or sealed with a retired key
+// with the current key; without a current key it decrypts them back to plaintext. Soft-deleted
+// rows are included. A row edited by the application meanwhile is skipped, since that write
+// already used the current key. dryRun only counts.
+func BackfillPIIEncryption(ctx context.Context, db *gorm.DB, models []interface{}, batchSize int, dryRun bool) ([]PIIBackfillReport, error) {
+	tables, err := PIITables(models)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]PIIBackfillReport, 0, len(tables))
+	for _, table := range tables {
+		report, err := backfillPIITable(ctx, db, table, batchSize, dryRun)
+		reports = append(reports, report)
+		if err != nil {
+			return reports, fmt.Errorf("%s: %w", table.Table, err)
+		}
+		log.Info().Str("table", table.Table).Int64("scanned", report.Scanned).Int64("rewritten", report.Rewritten).Bool("dry_run", dryRun).Msg("[BackfillPIIEncryption] Table done")
+	}
+	return reports, nil
+}
+
+type piiRow struct {
+	id     int64
+	values []sql.NullString
+}
+
+func backfillPIITable(ctx context.Context, db *gorm.DB, table PIITable, batchSize int, dryRun bool) (PIIBackfillReport, error) {
+	report := PIIBackfillReport{Table: table.Table, Columns: table.Columns}
+	cipher := pii.Current()
+	var lastID int64
+
+	for {
+		batch, err := readPIIBatch(ctx, db, table, lastID, batchSize)
+		if err != nil {
+			return report, err
+		}
+		if len(batch) == 0 {
+			return report, nil
+		}
+
+		for _, row := range batch {
+			updates := map[string]interface{}{}
+			var unchanged []clause.Expression
+			for i, column := range table.Columns {
+				value := row.values[i]
+				if !value.Valid || cipher.Current(value.String) {
+					continue
+				}
+
+				plaintext, err := cipher.Decrypt(value.String)
+				if err != nil {
+					return report, fmt.Errorf("id %d, %s: %w", row.id, column, err)
+				}
+				sealed, err := cipher.Encrypt(plaintext)
+				if err != nil {
+					return report, err
+				}
+				updates[column] = sealed
+				unchanged = append(unchanged, clause.Eq{Column: clause.Column{Name: column}, Value: value.String})
+			}
+			if len(updates) == 0 {
+				continue
+			}
+
+			report.Rewritten++
+			if dryRun {
+				continue
+			}
+			result := db.WithContext(ctx).Table(table.Table).Where("id = ?", row.id).Clauses(clause.Where{Exprs: unchanged}).UpdateColumns(updates)
+			if result.Error != nil {
+				return report, fmt.Errorf("id %d: %w", row.id, result.Error)
+			}
+			if result.RowsAffected == 0 {
+				report.Rewritten--
+				log.Info().Str("table", table.Table).Int64("id", row.id).Msg("[BackfillPIIEncryption] Row changed meanwhile, skipped")
+			}
+		}
+
+		report.Scanned += int64(len(batch))
+		lastID = batch[len(batch)-1].id
+	}
+}
+
+func readPIIBatch(ctx context.Context, db *gorm.DB, table PIITable, afterID int64, limit int) ([]piiRow, error) {
+	rows, err := db.WithContext(ctx).Table(table.Table).
+		Select(append([]string{"id"}, table.Columns...)).
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var batch []piiRow
+	for rows.Next() {
+		row := piiRow{values: make([]sql.NullString, len(table.Columns))}
+		dest := []interface{}{&row.id}
+		for i := range row.values {
+			dest = append(dest, &row.values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		batch = append(batch, row)
+	}
+	return batch, rows.Err()
+}
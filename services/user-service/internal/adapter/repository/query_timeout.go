@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const queryCancelKey = "app:query_cancel"
+
+// RegisterQueryTimeout bounds every statement by timeout, on top of whatever deadline
+// the caller's context already carries. Row/Raw statements hand open rows back to the
+// caller, so their deadline is left to expire on its own instead of being cancelled.
+// The deadline is released only after preloads and the implicit transaction commit,
+// which both run on the statement context.
+func RegisterQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	callbacks := db.Callback()
+	before := func(db *gorm.DB) { withQueryDeadline(db, timeout) }
+
+	if err := callbacks.Create().Before("gorm:create").Register("app:timeout", before); err != nil {
+		return err
+	}
+	if err := callbacks.Create().After("gorm:commit_or_rollback_transaction").Register("app:timeout_release", releaseQueryDeadline); err != nil {
+		return err
+	}
+	if err := callbacks.Query().Before("gorm:query").Register("app:timeout", before); err != nil {
+		return err
+	}
+	if err := callbacks.Query().After("gorm:after_query").Register("app:timeout_release", releaseQueryDeadline); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("app:timeout", before); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:commit_or_rollback_transaction").Register("app:timeout_release", releaseQueryDeadline); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("app:timeout", before); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().After("gorm:commit_or_rollback_transaction").Register("app:timeout_release", releaseQueryDeadline); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().Before("gorm:raw").Register("app:timeout", before); err != nil {
+		return err
+	}
+	if err := callbacks.Raw().After("gorm:raw").Register("app:timeout_release", releaseQueryDeadline); err != nil {
+		return err
+	}
+	return callbacks.Row().Before("gorm:row").Register("app:timeout", before)
+}
+
+func withQueryDeadline(db *gorm.DB, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(db.Statement.Context, timeout)
+	db.Statement.Context = ctx
+	db.InstanceSet(queryCancelKey, cancel)
+}
+
+func releaseQueryDeadline(db *gorm.DB) {
+	if cancel, ok := db.InstanceGet(queryCancelKey); ok {
+		cancel.(context.CancelFunc)()
+	}
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"user-service/utils/querytrace"
+
+	"gorm.io/gorm"
+)
+
+// RegisterQueryTracing installs queryTracingPlugin. With comments on, every statement run
+// during a request ends in a SQL comment naming the route (and the request ID when
+// withRequestID is set), so pg_stat_activity and the slow query log point at the endpoint.
+func RegisterQueryTracing(db *gorm.DB, comments, withRequestID bool) error {
+	return db.Use(&queryTracingPlugin{comments: comments, withRequestID: withRequestID})
+}
+
+// queryTracingPlugin wraps the connection pool rather than registering callbacks, so it
+// sees every statement: preloads, associations, Raw/Exec and transaction bodies alike
+type queryTracingPlugin struct {
+	comments      bool
+	withRequestID bool
+}
+
+func (p *queryTracingPlugin) Name() string {
+	return "app:query_tracing"
+}
+
+func (p *queryTracingPlugin) Initialize(db *gorm.DB) error {
+	pool := &tracingConnPool{tracedConn{ConnPool: db.ConnPool, plugin: p}}
+	db.ConnPool = pool
+	db.Statement.ConnPool = pool
+	return nil
+}
+
+// annotate appends the request's comment; a trailing comment keeps the statement's own text,
+// and the prefix pg_stat_statements groups by, unchanged
+func (p *queryTracingPlugin) annotate(trace *querytrace.Trace, query string) string {
+	if !p.comments || trace == nil {
+		return query
+	}
+	return query + " " + trace.Comment(p.withRequestID)
+}
+
+// record counts the statement towards its request. Rows are still being read after
+// QueryContext returns, so only the time to the first row is counted for them.
+func record(trace *querytrace.Trace, start time.Time) {
+	if trace != nil {
+		trace.Record(time.Since(start))
+	}
+}
+
+// tracedConn runs statements on a pool or a transaction. Only the ConnPool methods are
+// promoted, so whether it can begin or commit is up to the type embedding it.
+type tracedConn struct {
+	gorm.ConnPool
+	plugin *queryTracingPlugin
+}
+
+func (p *tracedConn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.ConnPool.PrepareContext(ctx, p.plugin.annotate(querytrace.FromContext(ctx), query))
+}
+
+func (p *tracedConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	trace := querytrace.FromContext(ctx)
+	defer record(trace, time.Now())
+	return p.ConnPool.ExecContext(ctx, p.plugin.annotate(trace, query), args...)
+}
+
+func (p *tracedConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	trace := querytrace.FromContext(ctx)
+	defer record(trace, time.Now())
+	return p.ConnPool.QueryContext(ctx, p.plugin.annotate(trace, query), args...)
+}
+
+func (p *tracedConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	trace := querytrace.FromContext(ctx)
+	defer record(trace, time.Now())
+	return p.ConnPool.QueryRowContext(ctx, p.plugin.annotate(trace, query), args...)
+}
+
+// tracingConnPool is the pool outside transactions
+type tracingConnPool struct {
+	tracedConn
+}
+
+// BeginTx keeps tracing inside transactions; GORM looks for this to start one
+func (p *tracingConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	var (
+		tx  gorm.ConnPool
+		err error
+	)
+	switch beginner := p.ConnPool.(type) {
+	case gorm.TxBeginner:
+		tx, err = beginner.BeginTx(ctx, opts)
+	case gorm.ConnPoolBeginner:
+		tx, err = beginner.BeginTx(ctx, opts)
+	default:
+		return nil, gorm.ErrInvalidTransaction
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &tracingTx{tracedConn{ConnPool: tx, plugin: p.plugin}}, nil
+}
+
+// GetDBConn keeps db.DB() working for the pool settings and health checks
+func (p *tracingConnPool) GetDBConn() (*sql.DB, error) {
+	if sqlDB, ok := p.ConnPool.(*sql.DB); ok {
+		return sqlDB, nil
+	}
+	if connector, ok := p.ConnPool.(gorm.GetDBConnector); ok {
+		return connector.GetDBConn()
+	}
+	return nil, gorm.ErrInvalidDB
+}
+
+// tracingTx is an open transaction. Like a plain *sql.Tx it cannot begin another one, so
+// nested transactions use savepoints.
+type tracingTx struct {
+	tracedConn
+}
+
+func (t *tracingTx) Commit() error {
+	return t.ConnPool.(gorm.TxCommitter).Commit()
+}
+
+func (t *tracingTx) Rollback() error {
+	return t.ConnPool.(gorm.TxCommitter).Rollback()
+}
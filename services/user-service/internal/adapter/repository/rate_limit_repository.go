@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog/log"
+)
+
+// RateLimitRepository keeps fixed-window request counters in Redis, so every instance
+// behind the load balancer shares the same quota
+type RateLimitRepository struct {
+	redisClient *redis.Client
+}
+
+func (r *RateLimitRepository) Hit(ctx context.Context, key string, limit int, window time.Duration) (entity.RateLimitEntity, error) {
+	redisKey := "rate_limit:" + key
+
+	pipe := r.redisClient.TxPipeline()
+	count := pipe.Incr(ctx, redisKey)
+	ttl := pipe.PTTL(ctx, redisKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Error().Err(err).Msg("[RateLimitRepository-Hit] Failed to count request")
+		return entity.RateLimitEntity{}, err
+	}
+
+	// The window starts at the first request and is not extended by later ones
+	remaining := ttl.Val()
+	if remaining < 0 {
+		if err := r.redisClient.PExpire(ctx, redisKey, window).Err(); err != nil {
+			log.Error().Err(err).Msg("[RateLimitRepository-Hit] Failed to set window")
+		}
+		remaining = window
+	}
+
+	return entity.RateLimitEntity{
+		Limit:   limit,
+		Count:   count.Val(),
+		ResetAt: time.Now().Add(remaining),
+	}, nil
+}
+
+func NewRateLimitRepository(redisClient *redis.Client) port.RateLimitRepositoryInterface {
+	return &RateLimitRepository{redisClient: redisClient}
+}
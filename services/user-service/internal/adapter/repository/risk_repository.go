@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog/log"
+)
+
+// lastLocationTTL bounds how long a previous sign-in location is compared against
+const lastLocationTTL = 30 * 24 * time.Hour
+
+// RiskRepository keeps short-lived sign-in risk state in Redis
+type RiskRepository struct {
+	redisClient *redis.Client
+}
+
+func (r *RiskRepository) IncrementFailedSignIns(ctx context.Context, email string, window time.Duration) (int64, error) {
+	key := r.getFailuresKey(email)
+
+	count, err := r.redisClient.Incr(ctx, key).Result()
+	if err != nil {
+		log.Error().Err(err).Msg("[RiskRepository-IncrementFailedSignIns] Failed to increment failures")
+		return 0, err
+	}
+
+	// The window starts at the first failure and is not extended by later ones
+	if count == 1 {
+		if err := r.redisClient.Expire(ctx, key, window).Err(); err != nil {
+			log.Error().Err(err).Msg("[RiskRepository-IncrementFailedSignIns] Failed to set failure window")
+		}
+	}
+
+	return count, nil
+}
+
+func (r *RiskRepository) GetFailedSignIns(ctx context.Context, email string) (int64, error) {
+	count, err := r.redisClient.Get(ctx, r.getFailuresKey(email)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("[RiskRepository-GetFailedSignIns] Failed to get failures")
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *RiskRepository) ResetFailedSignIns(ctx context.Context, email string) error {
+	return r.redisClient.Del(ctx, r.getFailuresKey(email)).Err()
+}
+
+func (r *RiskRepository) GetLastLocation(ctx context.Context, userID int64) (*entity.LoginLocationEntity, error) {
+	data, err := r.redisClient.Get(ctx, r.getLocationKey(userID)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[RiskRepository-GetLastLocation] Failed to get location")
+		return nil, err
+	}
+
+	var location entity.LoginLocationEntity
+	if err := json.Unmarshal(data, &location); err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+func (r *RiskRepository) SetLastLocation(ctx context.Context, userID int64, location entity.LoginLocationEntity) error {
+	data, err := json.Marshal(location)
+	if err != nil {
+		return err
+	}
+	return r.redisClient.Set(ctx, r.getLocationKey(userID), data, lastLocationTTL).Err()
+}
+
+func (r *RiskRepository) SaveChallenge(ctx context.Context, challenge *entity.StepUpChallengeEntity) error {
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(challenge.ExpiresAt)
+	if ttl <= 0 {
+		return errors.New("challenge already expired")
+	}
+
+	if err := r.redisClient.Set(ctx, r.getChallengeKey(challenge.ID), data, ttl).Err(); err != nil {
+		log.Error().Err(err).Int64("user_id", challenge.UserID).Msg("[RiskRepository-SaveChallenge] Failed to save challenge")
+		return err
+	}
+	return nil
+}
+
+func (r *RiskRepository) GetChallenge(ctx context.Context, challengeID string) (*entity.StepUpChallengeEntity, error) {
+	data, err := r.redisClient.Get(ctx, r.getChallengeKey(challengeID)).Bytes()
+	if err == redis.Nil {
+		return nil, errors.New("challenge not found")
+	}
+	if err != nil {
+		log.Error().Err(err).Msg("[RiskRepository-GetChallenge] Failed to get challenge")
+		return nil, err
+	}
+
+	var challenge entity.StepUpChallengeEntity
+	if err := json.Unmarshal(data, &challenge); err != nil {
+		return nil, err
+	}
+
+	// Attempts are counted separately so concurrent guesses cannot overwrite each other
+	attempts, err := r.redisClient.Get(ctx, r.getChallengeAttemptsKey(challengeID)).Int()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	challenge.Attempts = attempts
+	return &challenge, nil
+}
+
+func (r *RiskRepository) IncrementChallengeAttempts(ctx context.Context, challengeID string) (int, error) {
+	key := r.getChallengeAttemptsKey(challengeID)
+
+	pipe := r.redisClient.TxPipeline()
+	count := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, time.Hour)
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Error().Err(err).Msg("[RiskRepository-IncrementChallengeAttempts] Failed to increment attempts")
+		return 0, err
+	}
+	return int(count.Val()), nil
+}
+
+func (r *RiskRepository) DeleteChallenge(ctx context.Context, challengeID string) error {
+	return r.redisClient.Del(ctx, r.getChallengeKey(challengeID), r.getChallengeAttemptsKey(challengeID)).Err()
+}
+
+func (r *RiskRepository) getFailuresKey(email string) string {
+	return "risk:signin_failures:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func (r *RiskRepository) getLocationKey(userID int64) string {
+	return fmt.Sprintf("risk:last_location:%d", userID)
+}
+
+func (r *RiskRepository) getChallengeKey(challengeID string) string {
+	return "risk:step_up:" + challengeID
+}
+
+func (r *RiskRepository) getChallengeAttemptsKey(challengeID string) string {
+	return "risk:step_up_attempts:" + challengeID
+}
+
+func NewRiskRepository(redisClient *redis.Client) port.RiskRepositoryInterface {
+	return &RiskRepository{redisClient: redisClient}
+}
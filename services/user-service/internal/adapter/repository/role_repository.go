@@ -17,7 +17,7 @@ type RoleRepository struct {
 
 func (r *RoleRepository) GetAllRoles(ctx context.Context, search string) ([]entity.RoleEntity, error) {
 	var roles []model.Role
-	query := r.db.WithContext(ctx)
+	query := r.db.WithContext(ctx).Where("deleted_at IS NULL")
 
 	if search != "" {
 		query = query.Where("name ILIKE ?", "%"+search+"%")
@@ -33,9 +33,11 @@ func (r *RoleRepository) GetAllRoles(ctx context.Context, search string) ([]enti
 		roleEntities = append(roleEntities, entity.RoleEntity{
 			ID:        role.ID,
 			Name:      role.Name,
+			ParentID:  role.ParentID,
 			CreatedAt: role.CreatedAt,
 			UpdatedAt: role.UpdatedAt,
 			DeletedAt: role.DeletedAt,
+			Version:   role.Version,
 		})
 	}
 
@@ -43,9 +45,45 @@ func (r *RoleRepository) GetAllRoles(ctx context.Context, search string) ([]enti
 	return roleEntities, nil
 }
 
+func (r *RoleRepository) GetRoles(ctx context.Context, search string, page, limit int) ([]entity.RoleEntity, int64, error) {
+	var roles []model.Role
+	var totalCount int64
+
+	query := r.db.WithContext(ctx).Model(&model.Role{}).Where("deleted_at IS NULL")
+	if search != "" {
+		query = query.Where("name ILIKE ?", "%"+search+"%")
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Str("search", search).Msg("[RoleRepository-GetRoles] Failed to count roles")
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("id ASC").Offset(offset).Limit(limit).Find(&roles).Error; err != nil {
+		log.Error().Err(err).Str("search", search).Msg("[RoleRepository-GetRoles] Failed to get roles")
+		return nil, 0, err
+	}
+
+	var roleEntities []entity.RoleEntity
+	for _, role := range roles {
+		roleEntities = append(roleEntities, entity.RoleEntity{
+			ID:        role.ID,
+			Name:      role.Name,
+			ParentID:  role.ParentID,
+			CreatedAt: role.CreatedAt,
+			UpdatedAt: role.UpdatedAt,
+			DeletedAt: role.DeletedAt,
+			Version:   role.Version,
+		})
+	}
+
+	return roleEntities, totalCount, nil
+}
+
 func (r *RoleRepository) GetRoleByID(ctx context.Context, id int64) (*entity.RoleEntity, error) {
 	var role model.Role
-	if err := r.db.WithContext(ctx).Preload("Users").First(&role, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("deleted_at IS NULL").Preload("Users").First(&role, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			log.Info().Int64("role_id", id).Msg("[RoleRepository-GetRoleByID] Role not found")
 			return nil, gorm.ErrRecordNotFound
@@ -67,9 +105,11 @@ func (r *RoleRepository) GetRoleByID(ctx context.Context, id int64) (*entity.Rol
 	roleEntity := &entity.RoleEntity{
 		ID:        role.ID,
 		Name:      role.Name,
+		ParentID:  role.ParentID,
 		CreatedAt: role.CreatedAt,
 		UpdatedAt: role.UpdatedAt,
 		DeletedAt: role.DeletedAt,
+		Version:   role.Version,
 		Users:     userEntities,
 	}
 
@@ -79,7 +119,8 @@ func (r *RoleRepository) GetRoleByID(ctx context.Context, id int64) (*entity.Rol
 
 func (r *RoleRepository) CreateRole(ctx context.Context, role *entity.RoleEntity) (*entity.RoleEntity, error) {
 	roleModel := &model.Role{
-		Name: role.Name,
+		Name:     role.Name,
+		ParentID: role.ParentID,
 	}
 
 	if err := r.db.WithContext(ctx).Create(roleModel).Error; err != nil {
@@ -91,17 +132,20 @@ func (r *RoleRepository) CreateRole(ctx context.Context, role *entity.RoleEntity
 	createdRole := &entity.RoleEntity{
 		ID:        roleModel.ID,
 		Name:      roleModel.Name,
+		ParentID:  roleModel.ParentID,
 		CreatedAt: roleModel.CreatedAt,
 		UpdatedAt: roleModel.UpdatedAt,
+		Version:   roleModel.Version,
 	}
 
 	log.Info().Int64("role_id", createdRole.ID).Str("role_name", createdRole.Name).Msg("[RoleRepository-CreateRole] Role created successfully")
 	return createdRole, nil
 }
 
+// UpdateRole only applies when role.Version still matches the stored one (0 skips the check)
 func (r *RoleRepository) UpdateRole(ctx context.Context, id int64, role *entity.RoleEntity) (*entity.RoleEntity, error) {
 	var existingRole model.Role
-	if err := r.db.WithContext(ctx).First(&existingRole, id).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("deleted_at IS NULL").First(&existingRole, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			log.Info().Int64("role_id", id).Msg("[RoleRepository-UpdateRole] Role not found")
 			return nil, gorm.ErrRecordNotFound
@@ -110,11 +154,23 @@ func (r *RoleRepository) UpdateRole(ctx context.Context, id int64, role *entity.
 		return nil, err
 	}
 
-	// Update fields
-	existingRole.Name = role.Name
+	result := r.db.WithContext(ctx).Model(&model.Role{ID: id, Version: role.Version}).Updates(map[string]interface{}{
+		"name": role.Name,
+	})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("role_id", id).Str("role_name", role.Name).Msg("[RoleRepository-UpdateRole] Failed to update role")
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 && role.Version > 0 {
+		err := versionConflict(r.db.WithContext(ctx), "roles", id)
+		log.Warn().Err(err).Int64("role_id", id).Int64("version", role.Version).Msg("[RoleRepository-UpdateRole] Role update rejected")
+		return nil, err
+	}
 
-	if err := r.db.WithContext(ctx).Save(&existingRole).Error; err != nil {
-		log.Error().Err(err).Int64("role_id", id).Str("role_name", role.Name).Msg("[RoleRepository-UpdateRole] Failed to update role")
+	// Reload so the caller gets the bumped version and timestamps
+	if err := r.db.WithContext(ctx).First(&existingRole, id).Error; err != nil {
+		log.Error().Err(err).Int64("role_id", id).Msg("[RoleRepository-UpdateRole] Failed to reload role")
 		return nil, err
 	}
 
@@ -122,17 +178,57 @@ func (r *RoleRepository) UpdateRole(ctx context.Context, id int64, role *entity.
 	updatedRole := &entity.RoleEntity{
 		ID:        existingRole.ID,
 		Name:      existingRole.Name,
+		ParentID:  existingRole.ParentID,
 		CreatedAt: existingRole.CreatedAt,
 		UpdatedAt: existingRole.UpdatedAt,
+		Version:   existingRole.Version,
 	}
 
 	log.Info().Int64("role_id", id).Str("role_name", role.Name).Msg("[RoleRepository-UpdateRole] Role updated successfully")
 	return updatedRole, nil
 }
 
-func (r *RoleRepository) DeleteRole(ctx context.Context, id int64) error {
+// UpdateRoleParent sets or, with a nil parentID, clears the role's parent. Like UpdateRole it
+// only applies when version still matches (0 skips the check).
+func (r *RoleRepository) UpdateRoleParent(ctx context.Context, id int64, parentID *int64, version int64) (*entity.RoleEntity, error) {
+	result := r.db.WithContext(ctx).Model(&model.Role{ID: id, Version: version}).
+		Where("deleted_at IS NULL").
+		Updates(map[string]interface{}{"parent_id": parentID})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("role_id", id).Msg("[RoleRepository-UpdateRoleParent] Failed to update role parent")
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		if version > 0 {
+			err := versionConflict(r.db.WithContext(ctx), "roles", id)
+			log.Warn().Err(err).Int64("role_id", id).Int64("version", version).Msg("[RoleRepository-UpdateRoleParent] Role update rejected")
+			return nil, err
+		}
+		log.Info().Int64("role_id", id).Msg("[RoleRepository-UpdateRoleParent] Role not found")
+		return nil, gorm.ErrRecordNotFound
+	}
+
 	var role model.Role
 	if err := r.db.WithContext(ctx).First(&role, id).Error; err != nil {
+		log.Error().Err(err).Int64("role_id", id).Msg("[RoleRepository-UpdateRoleParent] Failed to reload role")
+		return nil, err
+	}
+
+	log.Info().Int64("role_id", id).Msg("[RoleRepository-UpdateRoleParent] Role parent updated successfully")
+	return &entity.RoleEntity{
+		ID:        role.ID,
+		Name:      role.Name,
+		ParentID:  role.ParentID,
+		CreatedAt: role.CreatedAt,
+		UpdatedAt: role.UpdatedAt,
+		Version:   role.Version,
+	}, nil
+}
+
+func (r *RoleRepository) DeleteRole(ctx context.Context, id int64) error {
+	var role model.Role
+	if err := r.db.WithContext(ctx).Where("deleted_at IS NULL").First(&role, id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			log.Info().Int64("role_id", id).Msg("[RoleRepository-DeleteRole] Role not found")
 			return gorm.ErrRecordNotFound
@@ -155,8 +251,10 @@ func (r *RoleRepository) DeleteRole(ctx context.Context, id int64) error {
 		return fmt.Errorf("cannot delete role that is currently assigned to users")
 	}
 
-	// Soft delete the role
-	if err := r.db.WithContext(ctx).Delete(&role).Error; err != nil {
+	// Soft delete the role; it stays restorable from the admin trash until purged
+	if err := r.db.WithContext(ctx).Model(&model.Role{}).
+		Where("id = ? AND deleted_at IS NULL", id).
+		Update("deleted_at", gorm.Expr("CURRENT_TIMESTAMP")).Error; err != nil {
 		log.Error().Err(err).Int64("role_id", id).Msg("[RoleRepository-DeleteRole] Failed to delete role")
 		return err
 	}
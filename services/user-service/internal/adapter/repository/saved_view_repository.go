@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type SavedViewRepository struct {
+	db *gorm.DB
+}
+
+func (r *SavedViewRepository) GetViews(ctx context.Context, ownerID int64, list string) ([]entity.SavedViewEntity, error) {
+	var views []model.SavedView
+	query := r.db.WithContext(ctx).Where("owner_id = ? OR is_shared = ?", ownerID, true)
+	if list != "" {
+		query = query.Where("list = ?", list)
+	}
+
+	if err := query.Order("list ASC, name ASC, id ASC").Find(&views).Error; err != nil {
+		log.Error().Err(err).Int64("owner_id", ownerID).Str("list", list).Msg("[SavedViewRepository-GetViews] Failed to get saved views")
+		return nil, err
+	}
+
+	viewEntities := make([]entity.SavedViewEntity, 0, len(views))
+	for i := range views {
+		viewEntity, err := toSavedViewEntity(&views[i])
+		if err != nil {
+			log.Error().Err(err).Int64("view_id", views[i].ID).Msg("[SavedViewRepository-GetViews] Failed to decode filters")
+			return nil, err
+		}
+		viewEntities = append(viewEntities, *viewEntity)
+	}
+	return viewEntities, nil
+}
+
+func (r *SavedViewRepository) GetViewByID(ctx context.Context, id int64) (*entity.SavedViewEntity, error) {
+	var viewModel model.SavedView
+	if err := r.db.WithContext(ctx).First(&viewModel, id).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Int64("view_id", id).Msg("[SavedViewRepository-GetViewByID] Failed to get saved view")
+		}
+		return nil, err
+	}
+
+	return toSavedViewEntity(&viewModel)
+}
+
+func (r *SavedViewRepository) CreateView(ctx context.Context, view *entity.SavedViewEntity) (*entity.SavedViewEntity, error) {
+	viewModel := &model.SavedView{OwnerID: view.OwnerID}
+	if err := applySavedView(viewModel, view); err != nil {
+		log.Error().Err(err).Str("name", view.Name).Msg("[SavedViewRepository-CreateView] Failed to encode filters")
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).Create(viewModel).Error; err != nil {
+		if isUniqueViolation(err, "saved_views_owner_id_list_name_key") {
+			return nil, errors.New("saved view already exists")
+		}
+		log.Error().Err(err).Str("name", view.Name).Msg("[SavedViewRepository-CreateView] Failed to create saved view")
+		return nil, err
+	}
+
+	return toSavedViewEntity(viewModel)
+}
+
+func (r *SavedViewRepository) UpdateView(ctx context.Context, view *entity.SavedViewEntity) (*entity.SavedViewEntity, error) {
+	var viewModel model.SavedView
+	if err := r.db.WithContext(ctx).First(&viewModel, view.ID).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Int64("view_id", view.ID).Msg("[SavedViewRepository-UpdateView] Failed to find saved view")
+		}
+		return nil, err
+	}
+
+	if err := applySavedView(&viewModel, view); err != nil {
+		log.Error().Err(err).Int64("view_id", view.ID).Msg("[SavedViewRepository-UpdateView] Failed to encode filters")
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).Save(&viewModel).Error; err != nil {
+		if isUniqueViolation(err, "saved_views_owner_id_list_name_key") {
+			return nil, errors.New("saved view already exists")
+		}
+		log.Error().Err(err).Int64("view_id", view.ID).Msg("[SavedViewRepository-UpdateView] Failed to update saved view")
+		return nil, err
+	}
+
+	return toSavedViewEntity(&viewModel)
+}
+
+func (r *SavedViewRepository) DeleteView(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Delete(&model.SavedView{}, id)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("view_id", id).Msg("[SavedViewRepository-DeleteView] Failed to delete saved view")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func applySavedView(viewModel *model.SavedView, view *entity.SavedViewEntity) error {
+	filters := view.Filters
+	if filters == nil {
+		filters = map[string]string{}
+	}
+	encoded, err := json.Marshal(filters)
+	if err != nil {
+		return err
+	}
+
+	viewModel.List = view.List
+	viewModel.Name = view.Name
+	viewModel.Filters = string(encoded)
+	viewModel.IsShared = view.Shared
+	return nil
+}
+
+func toSavedViewEntity(viewModel *model.SavedView) (*entity.SavedViewEntity, error) {
+	viewEntity := &entity.SavedViewEntity{
+		ID:        viewModel.ID,
+		OwnerID:   viewModel.OwnerID,
+		List:      viewModel.List,
+		Name:      viewModel.Name,
+		Shared:    viewModel.IsShared,
+		CreatedAt: viewModel.CreatedAt,
+		UpdatedAt: viewModel.UpdatedAt,
+	}
+
+	if viewModel.Filters != "" {
+		if err := json.Unmarshal([]byte(viewModel.Filters), &viewEntity.Filters); err != nil {
+			return nil, err
+		}
+	}
+
+	return viewEntity, nil
+}
+
+func NewSavedViewRepository(db *gorm.DB) port.SavedViewRepositoryInterface {
+	return &SavedViewRepository{db: db}
+}
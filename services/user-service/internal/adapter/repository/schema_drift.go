@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"user-service/internal/core/domain/entity"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Type families a column and a field must agree on. Lengths and precisions are ignored; only a
+// mismatch that breaks scanning or writing counts, like a string field on a numeric column.
+const (
+	familyBool    = "bool"
+	familyInt     = "int"
+	familyFloat   = "float"
+	familyString  = "string"
+	familyJSON    = "json"
+	familyTime    = "time"
+	familyBytes   = "bytes"
+	familySpatial = "spatial"
+)
+
+// columnFamilies maps Postgres type names to their family
+var columnFamilies = map[string]string{
+	"bool":    familyBool,
+	"boolean": familyBool,
+
+	"int2":      familyInt,
+	"int4":      familyInt,
+	"int8":      familyInt,
+	"smallint":  familyInt,
+	"integer":   familyInt,
+	"bigint":    familyInt,
+	"serial":    familyInt,
+	"bigserial": familyInt,
+
+	"numeric":          familyFloat,
+	"decimal":          familyFloat,
+	"float4":           familyFloat,
+	"float8":           familyFloat,
+	"real":             familyFloat,
+	"double precision": familyFloat,
+
+	"varchar":           familyString,
+	"character varying": familyString,
+	"text":              familyString,
+	"bpchar":            familyString,
+	"char":              familyString,
+	"character":         familyString,
+	"uuid":              familyString,
+	"citext":            familyString,
+	"inet":              familyString,
+
+	"json":  familyJSON,
+	"jsonb": familyJSON,
+
+	"timestamp":                   familyTime,
+	"timestamptz":                 familyTime,
+	"timestamp without time zone": familyTime,
+	"timestamp with time zone":    familyTime,
+	"date":                        familyTime,
+	"time":                        familyTime,
+	"timetz":                      familyTime,
+
+	"bytea": familyBytes,
+
+	"geography": familySpatial,
+	"geometry":  familySpatial,
+}
+
+var fieldFamilies = map[schema.DataType]string{
+	schema.Bool:   familyBool,
+	schema.Int:    familyInt,
+	schema.Uint:   familyInt,
+	schema.Float:  familyFloat,
+	schema.String: familyString,
+	schema.Time:   familyTime,
+	schema.Bytes:  familyBytes,
+}
+
+var schemaCache = &sync.Map{}
+
+// DetectSchemaDrift compares each model with its live table: missing tables and columns, type
+// families, nullability and the indexes the model declares
+func DetectSchemaDrift(ctx context.Context, db *gorm.DB, models []interface{}) ([]entity.SchemaDriftEntity, error) {
+	var drifts []entity.SchemaDriftEntity
+	for _, m := range models {
+		live, err := inspectTable(ctx, db, m)
+		if err != nil {
+			return nil, err
+		}
+
+		modelDrifts, err := CompareSchema(m, live)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, modelDrifts...)
+	}
+	return drifts, nil
+}
+
+// LogSchemaDrift runs the check and logs each difference as a warning. It never fails startup;
+// the server may still work, and a column renamed by a pending migration is expected for a while.
+func LogSchemaDrift(ctx context.Context, db *gorm.DB, models []interface{}) {
+	drifts, err := DetectSchemaDrift(ctx, db, models)
+	if err != nil {
+		log.Warn().Err(err).Msg("[SchemaDrift] Failed to inspect the database schema")
+		return
+	}
+
+	harmful := 0
+	for _, drift := range drifts {
+		event := log.Warn()
+		if drift.Harmless() {
+			event = log.Debug()
+		} else {
+			harmful++
+		}
+		event.Str("table", drift.Table).Str("column", drift.Column).Str("kind", drift.Kind).Msg("[SchemaDrift] " + drift.Detail)
+	}
+
+	if harmful > 0 {
+		log.Warn().Int("differences", harmful).Msg("[SchemaDrift] GORM models do not match the database; run `sayur-api schema check` for the full report")
+		return
+	}
+	log.Info().Int("tables", len(models)).Msg("[SchemaDrift] GORM models match the database")
+}
+
+// CompareSchema lists how live differs from what model expects
+func CompareSchema(model interface{}, live entity.TableSchemaEntity) ([]entity.SchemaDriftEntity, error) {
+	s, err := schema.Parse(model, schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+
+	if !live.Exists {
+		return []entity.SchemaDriftEntity{{
+			Table:  s.Table,
+			Kind:   entity.SchemaDriftMissingTable,
+			Detail: fmt.Sprintf("%s has no table %s", s.Name, s.Table),
+		}}, nil
+	}
+
+	var drifts []entity.SchemaDriftEntity
+	add := func(column, kind, detail string) {
+		drifts = append(drifts, entity.SchemaDriftEntity{Table: s.Table, Column: column, Kind: kind, Detail: detail})
+	}
+
+	mapped := make(map[string]bool)
+	for _, field := range s.Fields {
+		if field.DBName == "" || field.IgnoreMigration {
+			continue
+		}
+		mapped[field.DBName] = true
+
+		column, ok := live.Columns[field.DBName]
+		if !ok {
+			add(field.DBName, entity.SchemaDriftMissingColumn, fmt.Sprintf("%s.%s has no column %s", s.Name, field.Name, field.DBName))
+			continue
+		}
+
+		want, got := fieldFamily(field), columnFamily(column.Type)
+		if !compatible(want, got) {
+			add(field.DBName, entity.SchemaDriftTypeMismatch, fmt.Sprintf("%s.%s is %s but the column is %s", s.Name, field.Name, field.FieldType, column.Type))
+		}
+
+		switch {
+		case writesNull(field) && !column.Nullable:
+			add(field.DBName, entity.SchemaDriftNullability, fmt.Sprintf("%s.%s can be nil but the column is NOT NULL", s.Name, field.Name))
+		case field.NotNull && column.Nullable:
+			add(field.DBName, entity.SchemaDriftNullability, fmt.Sprintf("%s.%s is not null but the column allows NULL", s.Name, field.Name))
+		}
+
+		if field.Unique && !column.Unique && !hasIndex(live.Indexes, []string{field.DBName}, true) {
+			add(field.DBName, entity.SchemaDriftMissingIndex, fmt.Sprintf("%s.%s is unique but the column has no unique index", s.Name, field.Name))
+		}
+	}
+
+	for _, index := range s.ParseIndexes() {
+		columns := make([]string, 0, len(index.Fields))
+		for _, option := range index.Fields {
+			if option.Field != nil {
+				columns = append(columns, option.DBName)
+			}
+		}
+		unique := index.Class == "UNIQUE"
+		if len(columns) == 0 || hasIndex(live.Indexes, columns, unique) {
+			continue
+		}
+		add(strings.Join(columns, ","), entity.SchemaDriftMissingIndex, fmt.Sprintf("%s declares index %s on (%s), the table has none", s.Name, index.Name, strings.Join(columns, ", ")))
+	}
+
+	unmapped := make([]string, 0)
+	for name := range live.Columns {
+		if !mapped[name] {
+			unmapped = append(unmapped, name)
+		}
+	}
+	sort.Strings(unmapped)
+	for _, name := range unmapped {
+		add(name, entity.SchemaDriftUnmappedColumn, fmt.Sprintf("%s has no field for column %s", s.Name, name))
+	}
+	return drifts, nil
+}
+
+func inspectTable(ctx context.Context, db *gorm.DB, model interface{}) (entity.TableSchemaEntity, error) {
+	migrator := db.WithContext(ctx).Migrator()
+	live := entity.TableSchemaEntity{Columns: make(map[string]entity.ColumnSchemaEntity)}
+
+	if !migrator.HasTable(model) {
+		return live, nil
+	}
+	live.Exists = true
+
+	columns, err := migrator.ColumnTypes(model)
+	if err != nil {
+		return live, err
+	}
+	for _, column := range columns {
+		nullable, _ := column.Nullable()
+		unique, _ := column.Unique()
+		live.Columns[column.Name()] = entity.ColumnSchemaEntity{Type: column.DatabaseTypeName(), Nullable: nullable, Unique: unique}
+	}
+
+	indexes, err := migrator.GetIndexes(model)
+	if err != nil {
+		return live, err
+	}
+	for _, index := range indexes {
+		unique, _ := index.Unique()
+		live.Indexes = append(live.Indexes, entity.IndexSchemaEntity{Name: index.Name(), Columns: index.Columns(), Unique: unique})
+	}
+	return live, nil
+}
+
+func fieldFamily(field *schema.Field) string {
+	if family, ok := fieldFamilies[field.DataType]; ok {
+		return family
+	}
+	// An explicit type tag, e.g. type:jsonb or type:varchar(256)
+	return columnFamily(string(field.DataType))
+}
+
+func columnFamily(columnType string) string {
+	columnType = strings.ToLower(strings.TrimSpace(columnType))
+	if base, _, ok := strings.Cut(columnType, "("); ok {
+		columnType = strings.TrimSpace(base)
+	}
+	return columnFamilies[columnType]
+}
+
+// compatible gives unknown types the benefit of the doubt; a string field may hold JSON
+func compatible(field, column string) bool {
+	return field == "" || column == "" || field == column || (field == familyString && column == familyJSON)
+}
+
+// writesNull is true for fields GORM inserts as NULL when unset
+func writesNull(field *schema.Field) bool {
+	if field.PrimaryKey || field.HasDefaultValue {
+		return false
+	}
+	return field.FieldType.Kind() == reflect.Ptr
+}
+
+func hasIndex(indexes []entity.IndexSchemaEntity, columns []string, unique bool) bool {
+	for _, index := range indexes {
+		if unique && !index.Unique {
+			continue
+		}
+		if strings.Join(index.Columns, ",") == strings.Join(columns, ",") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,296 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+	"user-service/utils/pii"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// scimUserColumns maps the SCIM attributes users can be filtered on to their columns
+var scimUserColumns = map[string]string{
+	"username":   "email",
+	"externalid": "scim_external_id",
+}
+
+type SCIMRepository struct {
+	db *gorm.DB
+}
+
+func (r *SCIMRepository) ListUsers(ctx context.Context, filter *entity.SCIMFilter, offset, limit int) ([]entity.SCIMUserEntity, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.User{}).Where("scim_managed AND deleted_at IS NULL")
+	if filter != nil {
+		column, ok := scimUserColumns[filter.Attribute]
+		if !ok {
+			return nil, 0, errors.New("scim filter is not supported")
+		}
+		query = query.Where(column+" = ?", filter.Value)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.Error().Err(err).Msg("[SCIMRepository-ListUsers] Failed to count users")
+		return nil, 0, err
+	}
+	if limit == 0 {
+		return []entity.SCIMUserEntity{}, total, nil
+	}
+
+	var modelUsers []model.User
+	if err := query.Preload("Roles").Order("id").Offset(offset).Limit(limit).Find(&modelUsers).Error; err != nil {
+		log.Error().Err(err).Msg("[SCIMRepository-ListUsers] Failed to list users")
+		return nil, 0, err
+	}
+
+	users := make([]entity.SCIMUserEntity, 0, len(modelUsers))
+	for _, modelUser := range modelUsers {
+		users = append(users, toSCIMUserEntity(modelUser))
+	}
+	return users, total, nil
+}
+
+func (r *SCIMRepository) GetUser(ctx context.Context, id int64) (*entity.SCIMUserEntity, error) {
+	modelUser := model.User{}
+	err := r.db.WithContext(ctx).Where("id = ? AND scim_managed AND deleted_at IS NULL", id).Preload("Roles").First(&modelUser).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Int64("user_id", id).Msg("[SCIMRepository-GetUser] Failed to get user")
+		}
+		return nil, err
+	}
+
+	user := toSCIMUserEntity(modelUser)
+	return &user, nil
+}
+
+func (r *SCIMRepository) CreateUser(ctx context.Context, user *entity.SCIMUserEntity, hashedPassword string) (*entity.SCIMUserEntity, error) {
+	modelUser := &model.User{
+		Name:           user.Name,
+		Email:          user.UserName,
+		Password:       hashedPassword,
+		Phone:          user.Phone,
+		IsVerified:     true,
+		SCIMManaged:    true,
+		SCIMExternalID: nullableString(user.ExternalID),
+	}
+	if !user.Active {
+		now := time.Now()
+		modelUser.LockedAt = &now
+		modelUser.LockReason = entity.SCIMLockReason
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(modelUser).Error; err != nil {
+			if isUniqueViolation(err, usersEmailIndex) {
+				return errors.New("email already exists")
+			}
+			log.Error().Err(err).Str("email", user.UserName).Msg("[SCIMRepository-CreateUser] Failed to create user")
+			return err
+		}
+
+		customerRole := model.Role{}
+		if err := tx.Where("name = ?", "Customer").First(&customerRole).Error; err != nil {
+			log.Error().Err(err).Msg("[SCIMRepository-CreateUser] Failed to find Customer role")
+			return err
+		}
+		if err := tx.Model(modelUser).Association("Roles").Append(&customerRole); err != nil {
+			log.Error().Err(err).Int64("user_id", modelUser.ID).Msg("[SCIMRepository-CreateUser] Failed to assign role")
+			return err
+		}
+		modelUser.Roles = []model.Role{customerRole}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	created := toSCIMUserEntity(*modelUser)
+	return &created, nil
+}
+
+func (r *SCIMRepository) AdoptUser(ctx context.Context, id int64, externalID string) error {
+	err := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ? AND deleted_at IS NULL", id).Updates(map[string]interface{}{
+		"scim_managed":     true,
+		"scim_external_id": nullableString(externalID),
+	}).Error
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", id).Msg("[SCIMRepository-AdoptUser] Failed to adopt user")
+		return err
+	}
+	return nil
+}
+
+func (r *SCIMRepository) UpdateUser(ctx context.Context, user *entity.SCIMUserEntity) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.User{}).Where("id = ? AND scim_managed AND deleted_at IS NULL", user.ID).Updates(map[string]interface{}{
+			"name":             user.Name,
+			"email":            user.UserName,
+			"phone":            pii.Value(user.Phone),
+			"scim_external_id": nullableString(user.ExternalID),
+			"version":          gorm.Expr("version + 1"),
+		})
+		if result.Error != nil {
+			if isUniqueViolation(result.Error, usersEmailIndex) {
+				return errors.New("email already exists")
+			}
+			log.Error().Err(result.Error).Int64("user_id", user.ID).Msg("[SCIMRepository-UpdateUser] Failed to update user")
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		// An admin's lock for review stays in place whatever the identity provider says
+		lock := tx.Model(&model.User{}).Where("id = ?", user.ID)
+		if user.Active {
+			lock = lock.Where("lock_reason = ?", entity.SCIMLockReason).Updates(map[string]interface{}{"locked_at": nil, "lock_reason": ""})
+		} else {
+			lock = lock.Where("locked_at IS NULL").Updates(map[string]interface{}{"locked_at": time.Now(), "lock_reason": entity.SCIMLockReason})
+		}
+		if lock.Error != nil {
+			log.Error().Err(lock.Error).Int64("user_id", user.ID).Bool("active", user.Active).Msg("[SCIMRepository-UpdateUser] Failed to update lock")
+			return lock.Error
+		}
+		return nil
+	})
+}
+
+func (r *SCIMRepository) DeleteUser(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ? AND scim_managed AND deleted_at IS NULL", id).Update("deleted_at", time.Now())
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("user_id", id).Msg("[SCIMRepository-DeleteUser] Failed to delete user")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *SCIMRepository) ListGroups(ctx context.Context, names []string, filter *entity.SCIMFilter, offset, limit int, withMembers bool) ([]entity.SCIMGroupEntity, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.Role{}).Where("name IN ? AND deleted_at IS NULL", names)
+	if filter != nil {
+		if filter.Attribute != "displayname" {
+			return nil, 0, errors.New("scim filter is not supported")
+		}
+		query = query.Where("name = ?", filter.Value)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.Error().Err(err).Msg("[SCIMRepository-ListGroups] Failed to count groups")
+		return nil, 0, err
+	}
+	if limit == 0 {
+		return []entity.SCIMGroupEntity{}, total, nil
+	}
+
+	var roles []model.Role
+	if err := query.Order("id").Offset(offset).Limit(limit).Find(&roles).Error; err != nil {
+		log.Error().Err(err).Msg("[SCIMRepository-ListGroups] Failed to list groups")
+		return nil, 0, err
+	}
+
+	groups := make([]entity.SCIMGroupEntity, 0, len(roles))
+	for _, role := range roles {
+		groups = append(groups, entity.SCIMGroupEntity{
+			ID:        role.ID,
+			Name:      role.Name,
+			Version:   role.Version,
+			CreatedAt: role.CreatedAt,
+			UpdatedAt: role.UpdatedAt,
+		})
+	}
+	if !withMembers || len(groups) == 0 {
+		return groups, total, nil
+	}
+
+	if err := r.fillMembers(ctx, groups); err != nil {
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
+
+func (r *SCIMRepository) GetGroup(ctx context.Context, id int64, names []string) (*entity.SCIMGroupEntity, error) {
+	role := model.Role{}
+	if err := r.db.WithContext(ctx).Where("id = ? AND name IN ? AND deleted_at IS NULL", id, names).First(&role).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Int64("role_id", id).Msg("[SCIMRepository-GetGroup] Failed to get group")
+		}
+		return nil, err
+	}
+
+	groups := []entity.SCIMGroupEntity{{
+		ID:        role.ID,
+		Name:      role.Name,
+		Version:   role.Version,
+		CreatedAt: role.CreatedAt,
+		UpdatedAt: role.UpdatedAt,
+	}}
+	if err := r.fillMembers(ctx, groups); err != nil {
+		return nil, err
+	}
+	return &groups[0], nil
+}
+
+// fillMembers loads the SCIM-managed holders of each group's role in one query
+func (r *SCIMRepository) fillMembers(ctx context.Context, groups []entity.SCIMGroupEntity) error {
+	roleIDs := make([]int64, 0, len(groups))
+	index := make(map[int64]int, len(groups))
+	for i, group := range groups {
+		roleIDs = append(roleIDs, group.ID)
+		index[group.ID] = i
+		groups[i].Members = []entity.SCIMMemberEntity{}
+	}
+
+	var rows []struct {
+		RoleID int64
+		UserID int64
+		Email  string
+	}
+	err := r.db.WithContext(ctx).Table("users").
+		Select("ur.role_id, users.id AS user_id, users.email").
+		Joins("JOIN user_role ur ON users.id = ur.user_id").
+		Where("ur.role_id IN ? AND users.scim_managed AND users.deleted_at IS NULL", roleIDs).
+		Order("users.id").
+		Scan(&rows).Error
+	if err != nil {
+		log.Error().Err(err).Msg("[SCIMRepository-fillMembers] Failed to load group members")
+		return err
+	}
+
+	for _, row := range rows {
+		i := index[row.RoleID]
+		groups[i].Members = append(groups[i].Members, entity.SCIMMemberEntity{UserID: row.UserID, UserName: row.Email})
+	}
+	return nil
+}
+
+func toSCIMUserEntity(modelUser model.User) entity.SCIMUserEntity {
+	user := entity.SCIMUserEntity{
+		ID:         modelUser.ID,
+		ExternalID: stringValue(modelUser.SCIMExternalID),
+		UserName:   modelUser.Email,
+		Name:       modelUser.Name,
+		Phone:      modelUser.Phone,
+		Active:     modelUser.LockedAt == nil,
+		Version:    modelUser.Version,
+		CreatedAt:  modelUser.CreatedAt,
+		UpdatedAt:  modelUser.UpdatedAt,
+	}
+	if len(modelUser.Roles) > 0 {
+		user.RoleID = modelUser.Roles[0].ID
+		user.RoleName = modelUser.Roles[0].Name
+	}
+	return user
+}
+
+func NewSCIMRepository(db *gorm.DB) port.SCIMRepositoryInterface {
+	return &SCIMRepository{db: db}
+}
@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SegmentRepository struct {
+	db *gorm.DB
+}
+
+func (r *SegmentRepository) CreateSegment(ctx context.Context, segment *entity.SegmentEntity) (*entity.SegmentEntity, error) {
+	segmentModel := &model.CustomerSegment{
+		Name:         segment.Name,
+		City:         segment.Filter.City,
+		SignedUpFrom: segment.Filter.SignedUpFrom,
+		SignedUpTo:   segment.Filter.SignedUpTo,
+		MinOrders:    segment.Filter.MinOrders,
+		MaxOrders:    segment.Filter.MaxOrders,
+	}
+	if segment.CreatedBy > 0 {
+		segmentModel.CreatedBy = &segment.CreatedBy
+	}
+
+	if err := r.db.WithContext(ctx).Create(segmentModel).Error; err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, errors.New("segment already exists")
+		}
+		log.Error().Err(err).Str("name", segment.Name).Msg("[SegmentRepository-CreateSegment] Failed to create segment")
+		return nil, err
+	}
+
+	return toSegmentEntity(segmentModel), nil
+}
+
+func (r *SegmentRepository) GetSegments(ctx context.Context) ([]entity.SegmentEntity, error) {
+	var segments []model.CustomerSegment
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&segments).Error; err != nil {
+		log.Error().Err(err).Msg("[SegmentRepository-GetSegments] Failed to get segments")
+		return nil, err
+	}
+
+	segmentEntities := make([]entity.SegmentEntity, 0, len(segments))
+	for i := range segments {
+		segmentEntities = append(segmentEntities, *toSegmentEntity(&segments[i]))
+	}
+	return segmentEntities, nil
+}
+
+func (r *SegmentRepository) GetSegmentByID(ctx context.Context, id int64) (*entity.SegmentEntity, error) {
+	var segmentModel model.CustomerSegment
+	if err := r.db.WithContext(ctx).First(&segmentModel, id).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Int64("segment_id", id).Msg("[SegmentRepository-GetSegmentByID] Failed to get segment")
+		}
+		return nil, err
+	}
+
+	return toSegmentEntity(&segmentModel), nil
+}
+
+func (r *SegmentRepository) DeleteSegment(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Delete(&model.CustomerSegment{}, id)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("segment_id", id).Msg("[SegmentRepository-DeleteSegment] Failed to delete segment")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Materialize rebuilds the member list in one transaction, so a campaign never sees it half done
+func (r *SegmentRepository) Materialize(ctx context.Context, id int64) (int64, error) {
+	var members int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var segmentModel model.CustomerSegment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&segmentModel, id).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec("DELETE FROM customer_segment_members WHERE segment_id = ?", id).Error; err != nil {
+			return err
+		}
+
+		// Only customers that can receive email are members; bounced addresses are left out
+		conditions := []string{
+			"r.name = 'Customer'",
+			"u.is_verified = TRUE",
+			"u.deleted_at IS NULL",
+			"u.email_bounced_at IS NULL",
+		}
+		args := []interface{}{id}
+		if segmentModel.City != "" {
+			conditions = append(conditions, "LOWER(u.city) = LOWER(?)")
+			args = append(args, segmentModel.City)
+		}
+		if segmentModel.SignedUpFrom != nil {
+			conditions = append(conditions, "u.created_at >= ?")
+			args = append(args, *segmentModel.SignedUpFrom)
+		}
+		if segmentModel.SignedUpTo != nil {
+			conditions = append(conditions, "u.created_at < ?")
+			args = append(args, *segmentModel.SignedUpTo)
+		}
+		orderCount := "(SELECT COUNT(*) FROM customer_orders o WHERE o.user_id = u.id)"
+		if segmentModel.MinOrders != nil {
+			conditions = append(conditions, orderCount+" >= ?")
+			args = append(args, *segmentModel.MinOrders)
+		}
+		if segmentModel.MaxOrders != nil {
+			conditions = append(conditions, orderCount+" <= ?")
+			args = append(args, *segmentModel.MaxOrders)
+		}
+
+		inserted := tx.Exec(`
+			INSERT INTO customer_segment_members (segment_id, user_id)
+			SELECT DISTINCT ?, u.id FROM users u
+			JOIN user_role ur ON u.id = ur.user_id
+			JOIN roles r ON ur.role_id = r.id
+			WHERE `+strings.Join(conditions, " AND "), args...)
+		if inserted.Error != nil {
+			return inserted.Error
+		}
+		members = inserted.RowsAffected
+
+		now := time.Now()
+		return tx.Model(&model.CustomerSegment{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"member_count":    members,
+			"materialized_at": now,
+			"updated_at":      now,
+		}).Error
+	})
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Int64("segment_id", id).Msg("[SegmentRepository-Materialize] Failed to materialize segment")
+		}
+		return 0, err
+	}
+
+	return members, nil
+}
+
+func (r *SegmentRepository) GetMemberIDs(ctx context.Context, segmentID, afterUserID int64, limit int) ([]int64, error) {
+	var userIDs []int64
+	err := r.db.WithContext(ctx).Table("customer_segment_members").
+		Where("segment_id = ? AND user_id > ?", segmentID, afterUserID).
+		Order("user_id ASC").
+		Limit(limit).
+		Pluck("user_id", &userIDs).Error
+	if err != nil {
+		log.Error().Err(err).Int64("segment_id", segmentID).Msg("[SegmentRepository-GetMemberIDs] Failed to get segment members")
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+func (r *SegmentRepository) CreateCampaign(ctx context.Context, campaign *entity.CampaignEntity) (*entity.CampaignEntity, error) {
+	data, err := json.Marshal(campaign.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	campaignModel := &model.SegmentCampaign{
+		SegmentID: campaign.SegmentID,
+		Name:      campaign.Name,
+		Subject:   campaign.Subject,
+		Body:      campaign.Body,
+		Data:      string(data),
+	}
+	if campaign.CreatedBy > 0 {
+		campaignModel.CreatedBy = &campaign.CreatedBy
+	}
+
+	if err := r.db.WithContext(ctx).Create(campaignModel).Error; err != nil {
+		log.Error().Err(err).Int64("segment_id", campaign.SegmentID).Msg("[SegmentRepository-CreateCampaign] Failed to create campaign")
+		return nil, err
+	}
+
+	return toCampaignEntity(campaignModel), nil
+}
+
+func (r *SegmentRepository) UpdateCampaignFanOut(ctx context.Context, id, recipients int64, batches int) error {
+	err := r.db.WithContext(ctx).Model(&model.SegmentCampaign{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"recipients": recipients,
+		"batches":    batches,
+	}).Error
+	if err != nil {
+		log.Error().Err(err).Int64("campaign_id", id).Msg("[SegmentRepository-UpdateCampaignFanOut] Failed to update campaign")
+		return err
+	}
+	return nil
+}
+
+func (r *SegmentRepository) GetCampaignByID(ctx context.Context, id int64) (*entity.CampaignEntity, error) {
+	var campaignModel model.SegmentCampaign
+	if err := r.db.WithContext(ctx).First(&campaignModel, id).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Int64("campaign_id", id).Msg("[SegmentRepository-GetCampaignByID] Failed to get campaign")
+		}
+		return nil, err
+	}
+
+	return toCampaignEntity(&campaignModel), nil
+}
+
+// GetRecipients checks again at send time; users deleted or bounced since launch are skipped
+func (r *SegmentRepository) GetRecipients(ctx context.Context, userIDs []int64) ([]entity.UserEntity, error) {
+	var modelUsers []model.User
+	err := r.db.WithContext(ctx).
+		Select("id", "name", "email").
+		Where("id IN ? AND is_verified = ? AND deleted_at IS NULL AND email_bounced_at IS NULL", userIDs, true).
+		Order("id ASC").
+		Find(&modelUsers).Error
+	if err != nil {
+		log.Error().Err(err).Int("count", len(userIDs)).Msg("[SegmentRepository-GetRecipients] Failed to get campaign recipients")
+		return nil, err
+	}
+
+	recipients := make([]entity.UserEntity, 0, len(modelUsers))
+	for _, modelUser := range modelUsers {
+		recipients = append(recipients, entity.UserEntity{
+			ID:    modelUser.ID,
+			Name:  modelUser.Name,
+			Email: modelUser.Email,
+		})
+	}
+	return recipients, nil
+}
+
+func (r *SegmentRepository) RecordOrder(ctx context.Context, userID int64, orderID string, orderedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO customer_orders (order_id, user_id, ordered_at, created_at) VALUES (?, ?, ?, NOW())
+		ON CONFLICT (order_id) DO NOTHING`,
+		orderID, userID, orderedAt,
+	)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("user_id", userID).Str("order_id", orderID).Msg("[SegmentRepository-RecordOrder] Failed to record order")
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func toSegmentEntity(segmentModel *model.CustomerSegment) *entity.SegmentEntity {
+	segment := &entity.SegmentEntity{
+		ID:   segmentModel.ID,
+		Name: segmentModel.Name,
+		Filter: entity.SegmentFilterEntity{
+			City:         segmentModel.City,
+			SignedUpFrom: segmentModel.SignedUpFrom,
+			SignedUpTo:   segmentModel.SignedUpTo,
+			MinOrders:    segmentModel.MinOrders,
+			MaxOrders:    segmentModel.MaxOrders,
+		},
+		MemberCount:    segmentModel.MemberCount,
+		MaterializedAt: segmentModel.MaterializedAt,
+		CreatedAt:      segmentModel.CreatedAt,
+		UpdatedAt:      segmentModel.UpdatedAt,
+	}
+	if segmentModel.CreatedBy != nil {
+		segment.CreatedBy = *segmentModel.CreatedBy
+	}
+	return segment
+}
+
+func toCampaignEntity(campaignModel *model.SegmentCampaign) *entity.CampaignEntity {
+	campaign := &entity.CampaignEntity{
+		ID:         campaignModel.ID,
+		SegmentID:  campaignModel.SegmentID,
+		Name:       campaignModel.Name,
+		Subject:    campaignModel.Subject,
+		Body:       campaignModel.Body,
+		Data:       map[string]string{},
+		Recipients: campaignModel.Recipients,
+		Batches:    campaignModel.Batches,
+		CreatedAt:  campaignModel.CreatedAt,
+	}
+	if campaignModel.Data != "" {
+		if err := json.Unmarshal([]byte(campaignModel.Data), &campaign.Data); err != nil {
+			log.Warn().Err(err).Int64("campaign_id", campaignModel.ID).Msg("[SegmentRepository-toCampaignEntity] Failed to decode campaign data")
+		}
+	}
+	if campaignModel.CreatedBy != nil {
+		campaign.CreatedBy = *campaignModel.CreatedBy
+	}
+	return campaign
+}
+
+func NewSegmentRepository(db *gorm.DB) port.SegmentRepositoryInterface {
+	return &SegmentRepository{db: db}
+}
@@ -8,6 +8,7 @@ import (
 	"user-service/config"
 	"user-service/internal/core/domain/entity"
 	"user-service/internal/core/port"
+	"user-service/utils"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
@@ -26,10 +27,11 @@ func NewSessionRepository(redisClient *redis.Client, cfg *config.Config) port.Se
 	}
 }
 
-func (s *SessionRepository) StoreToken(ctx context.Context, userID int64, sessionID string, token string) error {
+func (s *SessionRepository) StoreToken(ctx context.Context, userID int64, sessionID string, token string, rememberMe bool) error {
 	key := s.getSessionKey(userID, sessionID)
+	ttl := s.config.Sessions.Lifetime(rememberMe)
 
-	err := s.redisClient.Set(ctx, key, token, 24*time.Hour).Err()
+	err := s.redisClient.Set(ctx, key, token, ttl).Err()
 	if err != nil {
 		log.Error().Err(err).Int64("user_id", userID).Str("session_id", sessionID).Msg("[SessionRepository-StoreToken] Failed to store token")
 		return err
@@ -38,10 +40,21 @@ func (s *SessionRepository) StoreToken(ctx context.Context, userID int64, sessio
 	// Add session to user's active sessions list
 	userSessionsKey := s.getUserSessionsKey(userID)
 	sessionInfo := entity.SessionInfo{
-		SessionID: sessionID,
-		UserID:    userID,
-		CreatedAt: time.Now(),
-		ExpiresAt: time.Now().Add(24 * time.Hour),
+		SessionID:  sessionID,
+		UserID:     userID,
+		CreatedAt:  time.Now(),
+		ExpiresAt:  time.Now().Add(ttl),
+		RememberMe: rememberMe,
+	}
+
+	// Keep the client details recorded at sign-in when the session token is reissued
+	if existing, err := s.getSessionInfo(ctx, userID, sessionID); err == nil {
+		sessionInfo.UserAgent = existing.UserAgent
+		sessionInfo.IPAddress = existing.IPAddress
+		sessionInfo.DeviceName = existing.DeviceName
+		sessionInfo.City = existing.City
+		sessionInfo.Country = existing.Country
+		sessionInfo.SSO = existing.SSO
 	}
 
 	sessionData, err := json.Marshal(sessionInfo)
@@ -56,8 +69,10 @@ func (s *SessionRepository) StoreToken(ctx context.Context, userID int64, sessio
 		return err
 	}
 
-	// Set expiration for user sessions key as well
-	s.redisClient.Expire(ctx, userSessionsKey, 24*time.Hour)
+	// The hash outlives its longest session; a short session must not cut a remember-me one short
+	if current, err := s.redisClient.TTL(ctx, userSessionsKey).Result(); err == nil && current < ttl {
+		s.redisClient.Expire(ctx, userSessionsKey, ttl)
+	}
 
 	log.Info().Int64("user_id", userID).Str("session_id", sessionID).Msg("[SessionRepository-StoreToken] Token stored successfully")
 	return nil
@@ -143,6 +158,61 @@ func (s *SessionRepository) ValidateToken(ctx context.Context, userID int64, ses
 	return storedToken == token
 }
 
+// SetSessionClient records the user agent, IP address and approximate location of the client
+// that owns the session
+func (s *SessionRepository) SetSessionClient(ctx context.Context, userID int64, sessionID string, client entity.ClientEntity, location *entity.LoginLocationEntity) error {
+	sessionInfo, err := s.getSessionInfo(ctx, userID, sessionID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Str("session_id", sessionID).Msg("[SessionRepository-SetSessionClient] Failed to get session info")
+		return err
+	}
+
+	sessionInfo.UserAgent = client.UserAgent
+	sessionInfo.IPAddress = client.IPAddress
+	sessionInfo.DeviceName = utils.DeviceDisplayName(client.UserAgent)
+	if location != nil {
+		sessionInfo.City = location.City
+		sessionInfo.Country = location.Country
+	}
+
+	sessionData, err := json.Marshal(sessionInfo)
+	if err != nil {
+		log.Error().Err(err).Msg("[SessionRepository-SetSessionClient] Failed to marshal session info")
+		return err
+	}
+
+	if err := s.redisClient.HSet(ctx, s.getUserSessionsKey(userID), sessionID, sessionData).Err(); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Str("session_id", sessionID).Msg("[SessionRepository-SetSessionClient] Failed to store session info")
+		return err
+	}
+
+	return nil
+}
+
+// MarkSSOSession flags the session as signed in through the identity provider; StoreToken keeps
+// the flag when the session is refreshed
+func (s *SessionRepository) MarkSSOSession(ctx context.Context, userID int64, sessionID string) error {
+	sessionInfo, err := s.getSessionInfo(ctx, userID, sessionID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Str("session_id", sessionID).Msg("[SessionRepository-MarkSSOSession] Failed to get session info")
+		return err
+	}
+
+	sessionInfo.SSO = true
+	sessionData, err := json.Marshal(sessionInfo)
+	if err != nil {
+		log.Error().Err(err).Msg("[SessionRepository-MarkSSOSession] Failed to marshal session info")
+		return err
+	}
+
+	if err := s.redisClient.HSet(ctx, s.getUserSessionsKey(userID), sessionID, sessionData).Err(); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Str("session_id", sessionID).Msg("[SessionRepository-MarkSSOSession] Failed to store session info")
+		return err
+	}
+
+	return nil
+}
+
 // GetUserSessions returns all active sessions for a user
 func (s *SessionRepository) GetUserSessions(ctx context.Context, userID int64) ([]entity.SessionInfo, error) {
 	userSessionsKey := s.getUserSessionsKey(userID)
@@ -166,7 +236,25 @@ func (s *SessionRepository) GetUserSessions(ctx context.Context, userID int64) (
 	return sessions, nil
 }
 
+// GetSession returns one session of the user, or redis.Nil when it does not exist
+func (s *SessionRepository) GetSession(ctx context.Context, userID int64, sessionID string) (*entity.SessionInfo, error) {
+	return s.getSessionInfo(ctx, userID, sessionID)
+}
+
 // Helper methods
+func (s *SessionRepository) getSessionInfo(ctx context.Context, userID int64, sessionID string) (*entity.SessionInfo, error) {
+	data, err := s.redisClient.HGet(ctx, s.getUserSessionsKey(userID), sessionID).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var sessionInfo entity.SessionInfo
+	if err := json.Unmarshal([]byte(data), &sessionInfo); err != nil {
+		return nil, err
+	}
+	return &sessionInfo, nil
+}
+
 func (s *SessionRepository) getSessionKey(userID int64, sessionID string) string {
 	return fmt.Sprintf("session:%d:%s", userID, sessionID)
 }
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type SSORepository struct {
+	db *gorm.DB
+}
+
+func (r *SSORepository) GetUserIDBySubject(ctx context.Context, subject string) (int64, error) {
+	modelUser := model.User{}
+	err := r.db.WithContext(ctx).Select("id").Where("sso_subject = ? AND deleted_at IS NULL", subject).First(&modelUser).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Msg("[SSORepository-GetUserIDBySubject] Failed to look up subject")
+		}
+		return 0, err
+	}
+	return modelUser.ID, nil
+}
+
+func (r *SSORepository) LinkSubject(ctx context.Context, userID int64, subject string) error {
+	err := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"sso_subject": subject,
+		"is_verified": true,
+	}).Error
+	if err != nil {
+		if isUniqueViolation(err, usersSSOSubjectIndex) {
+			return errors.New("sso subject already linked")
+		}
+		log.Error().Err(err).Int64("user_id", userID).Msg("[SSORepository-LinkSubject] Failed to link subject")
+		return err
+	}
+	return nil
+}
+
+func (r *SSORepository) CreateUser(ctx context.Context, user *entity.UserEntity, hashedPassword, roleName, subject string) (int64, error) {
+	modelUser := &model.User{
+		Name:       user.Name,
+		Email:      user.Email,
+		Password:   hashedPassword,
+		IsVerified: true,
+		Language:   user.Language,
+		SSOSubject: &subject,
+	}
+
+	// The account and its role go in together, so a failed role never leaves a roleless account
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(modelUser).Error; err != nil {
+			if isUniqueViolation(err, usersEmailIndex) {
+				return errors.New("email already exists")
+			}
+			log.Error().Err(err).Str("email", user.Email).Msg("[SSORepository-CreateUser] Failed to create user")
+			return err
+		}
+
+		role := model.Role{}
+		if err := tx.Where("name = ? AND deleted_at IS NULL", roleName).First(&role).Error; err != nil {
+			log.Error().Err(err).Str("role", roleName).Msg("[SSORepository-CreateUser] Failed to find role")
+			return err
+		}
+		if err := tx.Model(modelUser).Association("Roles").Append(&role); err != nil {
+			log.Error().Err(err).Int64("user_id", modelUser.ID).Msg("[SSORepository-CreateUser] Failed to assign role")
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return modelUser.ID, nil
+}
+
+func NewSSORepository(db *gorm.DB) port.SSORepositoryInterface {
+	return &SSORepository{db: db}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog/log"
+)
+
+type SSOStateRepository struct {
+	redisClient *redis.Client
+}
+
+func (r *SSOStateRepository) SaveState(ctx context.Context, state *entity.SSOStateEntity) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(state.ExpiresAt)
+	if ttl <= 0 {
+		return errors.New("sso state already expired")
+	}
+
+	if err := r.redisClient.Set(ctx, r.getStateKey(state.ID), data, ttl).Err(); err != nil {
+		log.Error().Err(err).Msg("[SSOStateRepository-SaveState] Failed to save state")
+		return err
+	}
+	return nil
+}
+
+func (r *SSOStateRepository) TakeState(ctx context.Context, id string) (*entity.SSOStateEntity, error) {
+	// GET and DEL in one transaction, so two callbacks racing with the same state cannot both win
+	key := r.getStateKey(id)
+	var get *redis.StringCmd
+	_, err := r.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		log.Error().Err(err).Msg("[SSOStateRepository-TakeState] Failed to take state")
+		return nil, err
+	}
+	data, err := get.Bytes()
+	if err == redis.Nil {
+		return nil, errors.New("sso state not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var state entity.SSOStateEntity
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *SSOStateRepository) getStateKey(id string) string {
+	return "sso:state:" + id
+}
+
+func NewSSOStateRepository(redisClient *redis.Client) port.SSOStateRepositoryInterface {
+	return &SSOStateRepository{redisClient: redisClient}
+}
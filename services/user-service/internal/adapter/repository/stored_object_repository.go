@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type StoredObjectRepository struct {
+	db *gorm.DB
+}
+
+func (r *StoredObjectRepository) GetObject(ctx context.Context, bucket, objectName string) (*entity.StoredObjectEntity, error) {
+	modelObject := model.StoredObject{}
+	err := r.db.WithContext(ctx).Where("bucket = ? AND object_name = ? AND ref_count > 0", bucket, objectName).First(&modelObject).Error
+	if err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Str("object_name", objectName).Msg("[StoredObjectRepository-GetObject] Failed to get stored object")
+		}
+		return nil, err
+	}
+	return toStoredObjectEntity(modelObject), nil
+}
+
+func (r *StoredObjectRepository) AddReference(ctx context.Context, object *entity.StoredObjectEntity) (*entity.StoredObjectEntity, error) {
+	now := time.Now()
+	modelObject := model.StoredObject{}
+
+	// Concurrent identical uploads land on the same row, so the count is kept in the statement
+	err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO stored_objects (bucket, object_name, sha256, size, content_type, url, ref_count, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, 1, ?, ?)
+		ON CONFLICT (bucket, object_name) DO UPDATE
+		SET ref_count = stored_objects.ref_count + 1, updated_at = EXCLUDED.updated_at
+		RETURNING bucket, object_name, sha256, size, content_type, url, ref_count, created_at, updated_at`,
+		object.Bucket, object.ObjectName, object.SHA256, object.Size, object.ContentType, object.URL, now, now,
+	).Scan(&modelObject).Error
+	if err != nil {
+		log.Error().Err(err).Str("object_name", object.ObjectName).Msg("[StoredObjectRepository-AddReference] Failed to add object reference")
+		return nil, err
+	}
+
+	return toStoredObjectEntity(modelObject), nil
+}
+
+func (r *StoredObjectRepository) RemoveReference(ctx context.Context, bucket, objectName string) (int, error) {
+	var remaining []int
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE stored_objects SET ref_count = ref_count - 1, updated_at = ?
+		WHERE bucket = ? AND object_name = ? AND ref_count > 0
+		RETURNING ref_count`,
+		time.Now(), bucket, objectName,
+	).Scan(&remaining).Error
+	if err != nil {
+		log.Error().Err(err).Str("object_name", objectName).Msg("[StoredObjectRepository-RemoveReference] Failed to remove object reference")
+		return 0, err
+	}
+	if len(remaining) == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+
+	return remaining[0], nil
+}
+
+func (r *StoredObjectRepository) DeleteUnreferenced(ctx context.Context, bucket, objectName string) (bool, error) {
+	result := r.db.WithContext(ctx).Where("bucket = ? AND object_name = ? AND ref_count = 0", bucket, objectName).Delete(&model.StoredObject{})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Str("object_name", objectName).Msg("[StoredObjectRepository-DeleteUnreferenced] Failed to delete stored object")
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *StoredObjectRepository) ForgetObject(ctx context.Context, bucket, objectName string) error {
+	if err := r.db.WithContext(ctx).Where("bucket = ? AND object_name = ?", bucket, objectName).Delete(&model.StoredObject{}).Error; err != nil {
+		log.Error().Err(err).Str("object_name", objectName).Msg("[StoredObjectRepository-ForgetObject] Failed to delete stored object")
+		return err
+	}
+	return nil
+}
+
+func toStoredObjectEntity(modelObject model.StoredObject) *entity.StoredObjectEntity {
+	return &entity.StoredObjectEntity{
+		Bucket:      modelObject.Bucket,
+		ObjectName:  modelObject.ObjectName,
+		SHA256:      modelObject.SHA256,
+		Size:        modelObject.Size,
+		ContentType: modelObject.ContentType,
+		URL:         modelObject.URL,
+		RefCount:    modelObject.RefCount,
+		CreatedAt:   modelObject.CreatedAt,
+		UpdatedAt:   modelObject.UpdatedAt,
+	}
+}
+
+func NewStoredObjectRepository(db *gorm.DB) port.StoredObjectRepositoryInterface {
+	return &StoredObjectRepository{db: db}
+}
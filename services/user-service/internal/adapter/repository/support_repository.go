@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type SupportRepository struct {
+	db *gorm.DB
+}
+
+func (r *SupportRepository) Create(ctx context.Context, ticket *entity.SupportTicketEntity) (*entity.SupportTicketEntity, error) {
+	attachments := ticket.Attachments
+	if attachments == nil {
+		attachments = []entity.SupportAttachmentEntity{}
+	}
+	attachmentsJSON, err := json.Marshal(attachments)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketModel := &model.SupportTicket{
+		UserID:      ticket.UserID,
+		Category:    ticket.Category,
+		Subject:     ticket.Subject,
+		Message:     ticket.Message,
+		Attachments: string(attachmentsJSON),
+		Status:      entity.SupportTicketOpen,
+	}
+
+	if err := r.db.WithContext(ctx).Create(ticketModel).Error; err != nil {
+		log.Error().Err(err).Int64("user_id", ticket.UserID).Msg("[SupportRepository-Create] Failed to create support ticket")
+		return nil, err
+	}
+
+	return toSupportTicketEntity(ticketModel), nil
+}
+
+func (r *SupportRepository) GetByID(ctx context.Context, id int64) (*entity.SupportTicketEntity, error) {
+	var ticketModel model.SupportTicket
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&ticketModel).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Int64("ticket_id", id).Msg("[SupportRepository-GetByID] Failed to get support ticket")
+		}
+		return nil, err
+	}
+
+	return toSupportTicketEntity(&ticketModel), nil
+}
+
+func (r *SupportRepository) ListByUser(ctx context.Context, userID int64, page, limit int) ([]entity.SupportTicketEntity, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.SupportTicket{}).Where("user_id = ?", userID)
+	return r.list(query, "created_at DESC, id DESC", page, limit)
+}
+
+func (r *SupportRepository) List(ctx context.Context, filter entity.SupportTicketFilter, page, limit int) ([]entity.SupportTicketEntity, int64, error) {
+	query := r.db.WithContext(ctx).Model(&model.SupportTicket{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	switch {
+	case filter.Unassigned:
+		query = query.Where("assignee_id IS NULL")
+	case filter.AssigneeID > 0:
+		query = query.Where("assignee_id = ?", filter.AssigneeID)
+	}
+	return r.list(query, "created_at ASC, id ASC", page, limit)
+}
+
+func (r *SupportRepository) list(query *gorm.DB, order string, page, limit int) ([]entity.SupportTicketEntity, int64, error) {
+	var (
+		tickets    []model.SupportTicket
+		totalCount int64
+	)
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Msg("[SupportRepository-list] Failed to count support tickets")
+		return nil, 0, err
+	}
+
+	if err := query.Order(order).Offset((page - 1) * limit).Limit(limit).Find(&tickets).Error; err != nil {
+		log.Error().Err(err).Msg("[SupportRepository-list] Failed to list support tickets")
+		return nil, 0, err
+	}
+
+	entities := make([]entity.SupportTicketEntity, 0, len(tickets))
+	for i := range tickets {
+		entities = append(entities, *toSupportTicketEntity(&tickets[i]))
+	}
+	return entities, totalCount, nil
+}
+
+func (r *SupportRepository) Assign(ctx context.Context, id, assigneeID int64) error {
+	return r.update(ctx, id, map[string]interface{}{
+		"assignee_id": assigneeID,
+		"updated_at":  time.Now(),
+	}, "[SupportRepository-Assign] Failed to assign support ticket")
+}
+
+func (r *SupportRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":      status,
+		"resolved_at": nil,
+		"updated_at":  now,
+	}
+	if status == entity.SupportTicketResolved || status == entity.SupportTicketClosed {
+		// Closing a resolved ticket keeps the time it was first resolved
+		updates["resolved_at"] = gorm.Expr("COALESCE(resolved_at, ?)", now)
+	}
+	return r.update(ctx, id, updates, "[SupportRepository-UpdateStatus] Failed to update support ticket status")
+}
+
+func (r *SupportRepository) update(ctx context.Context, id int64, updates map[string]interface{}, failure string) error {
+	result := r.db.WithContext(ctx).Model(&model.SupportTicket{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("ticket_id", id).Msg(failure)
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func toSupportTicketEntity(ticketModel *model.SupportTicket) *entity.SupportTicketEntity {
+	ticket := &entity.SupportTicketEntity{
+		ID:          ticketModel.ID,
+		UserID:      ticketModel.UserID,
+		Category:    ticketModel.Category,
+		Subject:     ticketModel.Subject,
+		Message:     ticketModel.Message,
+		Attachments: []entity.SupportAttachmentEntity{},
+		Status:      ticketModel.Status,
+		ResolvedAt:  ticketModel.ResolvedAt,
+		CreatedAt:   ticketModel.CreatedAt,
+		UpdatedAt:   ticketModel.UpdatedAt,
+	}
+	if ticketModel.AssigneeID != nil {
+		ticket.AssigneeID = *ticketModel.AssigneeID
+	}
+	if ticketModel.Attachments != "" {
+		if err := json.Unmarshal([]byte(ticketModel.Attachments), &ticket.Attachments); err != nil {
+			log.Error().Err(err).Int64("ticket_id", ticketModel.ID).Msg("[SupportRepository] Failed to decode attachments")
+		}
+	}
+	return ticket
+}
+
+func NewSupportRepository(db *gorm.DB) port.SupportRepositoryInterface {
+	return &SupportRepository{db: db}
+}
@@ -0,0 +1,262 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TrashRepository struct {
+	db *gorm.DB
+}
+
+func (r *TrashRepository) GetDeletedUsers(ctx context.Context, search string, page, limit int) ([]entity.TrashedUserEntity, int64, error) {
+	var users []model.User
+	var totalCount int64
+
+	query := r.db.WithContext(ctx).Model(&model.User{}).Where("deleted_at IS NOT NULL")
+	if search != "" {
+		query = query.Where("name ILIKE ? OR email ILIKE ?", "%"+search+"%", "%"+search+"%")
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Str("search", search).Msg("[TrashRepository-GetDeletedUsers] Failed to count deleted users")
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Preload("Roles").Order("deleted_at DESC").Offset(offset).Limit(limit).Find(&users).Error; err != nil {
+		log.Error().Err(err).Str("search", search).Msg("[TrashRepository-GetDeletedUsers] Failed to get deleted users")
+		return nil, 0, err
+	}
+
+	trashedUsers := make([]entity.TrashedUserEntity, 0, len(users))
+	for _, user := range users {
+		trashedUsers = append(trashedUsers, toTrashedUser(user))
+	}
+
+	return trashedUsers, totalCount, nil
+}
+
+func (r *TrashRepository) RestoreUser(ctx context.Context, userID int64) (*entity.TrashedUserEntity, error) {
+	var restored entity.TrashedUserEntity
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user, err := r.lockDeletedUser(tx, userID)
+		if err != nil {
+			return err
+		}
+		restored = toTrashedUser(*user)
+
+		// Someone may have signed up with the email since the account was deleted
+		var taken int64
+		if err := tx.Model(&model.User{}).Where("email = ? AND deleted_at IS NULL", user.Email).Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken > 0 {
+			return errors.New("email is used by another account")
+		}
+
+		// A restored account stands on its own again, so the merge pointer goes too
+		return tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"deleted_at":     nil,
+			"merged_into_id": nil,
+		}).Error
+	})
+	if err != nil {
+		switch err.Error() {
+		case "user not found", "email is used by another account":
+		default:
+			log.Error().Err(err).Int64("user_id", userID).Msg("[TrashRepository-RestoreUser] Failed to restore user")
+		}
+		return nil, err
+	}
+
+	log.Info().Int64("user_id", userID).Msg("[TrashRepository-RestoreUser] User restored")
+	return &restored, nil
+}
+
+func (r *TrashRepository) PurgeUser(ctx context.Context, userID int64) (*entity.TrashedUserEntity, error) {
+	var purged entity.TrashedUserEntity
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		user, err := r.lockDeletedUser(tx, userID)
+		if err != nil {
+			return err
+		}
+		purged = toTrashedUser(*user)
+
+		// Ledger rows reference the vendor with ON DELETE RESTRICT; financial history is never purged
+		var ledgerCount int64
+		if err := tx.Table("ledger_transactions lt").
+			Joins("JOIN vendors v ON v.id = lt.vendor_id").
+			Where("v.user_id = ?", userID).
+			Count(&ledgerCount).Error; err != nil {
+			return err
+		}
+		var withdrawalCount int64
+		if err := tx.Table("vendor_withdrawals vw").
+			Joins("JOIN vendors v ON v.id = vw.vendor_id").
+			Where("v.user_id = ?", userID).
+			Count(&withdrawalCount).Error; err != nil {
+			return err
+		}
+		if ledgerCount > 0 || withdrawalCount > 0 {
+			return errors.New("user has ledger history")
+		}
+
+		// Roles, tokens, devices, vendor profile and onboarding go with the user via ON DELETE CASCADE
+		return tx.Exec("DELETE FROM users WHERE id = ?", userID).Error
+	})
+	if err != nil {
+		switch err.Error() {
+		case "user not found", "user has ledger history":
+		default:
+			log.Error().Err(err).Int64("user_id", userID).Msg("[TrashRepository-PurgeUser] Failed to purge user")
+		}
+		return nil, err
+	}
+
+	log.Info().Int64("user_id", userID).Msg("[TrashRepository-PurgeUser] User purged")
+	return &purged, nil
+}
+
+func (r *TrashRepository) GetDeletedRoles(ctx context.Context, search string, page, limit int) ([]entity.TrashedRoleEntity, int64, error) {
+	var roles []model.Role
+	var totalCount int64
+
+	query := r.db.WithContext(ctx).Model(&model.Role{}).Where("deleted_at IS NOT NULL")
+	if search != "" {
+		query = query.Where("name ILIKE ?", "%"+search+"%")
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Str("search", search).Msg("[TrashRepository-GetDeletedRoles] Failed to count deleted roles")
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("deleted_at DESC").Offset(offset).Limit(limit).Find(&roles).Error; err != nil {
+		log.Error().Err(err).Str("search", search).Msg("[TrashRepository-GetDeletedRoles] Failed to get deleted roles")
+		return nil, 0, err
+	}
+
+	trashedRoles := make([]entity.TrashedRoleEntity, 0, len(roles))
+	for _, role := range roles {
+		trashedRoles = append(trashedRoles, toTrashedRole(role))
+	}
+
+	return trashedRoles, totalCount, nil
+}
+
+func (r *TrashRepository) RestoreRole(ctx context.Context, roleID int64) (*entity.TrashedRoleEntity, error) {
+	var restored entity.TrashedRoleEntity
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		role, err := r.lockDeletedRole(tx, roleID)
+		if err != nil {
+			return err
+		}
+		restored = toTrashedRole(*role)
+
+		return tx.Model(&model.Role{}).Where("id = ?", roleID).Update("deleted_at", nil).Error
+	})
+	if err != nil {
+		if err.Error() != "role not found" {
+			log.Error().Err(err).Int64("role_id", roleID).Msg("[TrashRepository-RestoreRole] Failed to restore role")
+		}
+		return nil, err
+	}
+
+	log.Info().Int64("role_id", roleID).Msg("[TrashRepository-RestoreRole] Role restored")
+	return &restored, nil
+}
+
+func (r *TrashRepository) PurgeRole(ctx context.Context, roleID int64) (*entity.TrashedRoleEntity, error) {
+	var purged entity.TrashedRoleEntity
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		role, err := r.lockDeletedRole(tx, roleID)
+		if err != nil {
+			return err
+		}
+		purged = toTrashedRole(*role)
+
+		return tx.Exec("DELETE FROM roles WHERE id = ?", roleID).Error
+	})
+	if err != nil {
+		if err.Error() != "role not found" {
+			log.Error().Err(err).Int64("role_id", roleID).Msg("[TrashRepository-PurgeRole] Failed to purge role")
+		}
+		return nil, err
+	}
+
+	log.Info().Int64("role_id", roleID).Msg("[TrashRepository-PurgeRole] Role purged")
+	return &purged, nil
+}
+
+func (r *TrashRepository) lockDeletedUser(tx *gorm.DB, userID int64) (*model.User, error) {
+	user := model.User{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NOT NULL", userID).
+		Preload("Roles").
+		First(&user).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *TrashRepository) lockDeletedRole(tx *gorm.DB, roleID int64) (*model.Role, error) {
+	role := model.Role{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND deleted_at IS NOT NULL", roleID).
+		First(&role).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.New("role not found")
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func toTrashedUser(user model.User) entity.TrashedUserEntity {
+	trashed := entity.TrashedUserEntity{
+		ID:           user.ID,
+		Name:         user.Name,
+		Email:        user.Email,
+		MergedIntoID: user.MergedIntoID,
+	}
+	if len(user.Roles) > 0 {
+		trashed.RoleName = user.Roles[0].Name
+	}
+	if user.DeletedAt != nil {
+		trashed.DeletedAt = *user.DeletedAt
+	}
+	return trashed
+}
+
+func toTrashedRole(role model.Role) entity.TrashedRoleEntity {
+	trashed := entity.TrashedRoleEntity{
+		ID:   role.ID,
+		Name: role.Name,
+	}
+	if role.DeletedAt != nil {
+		trashed.DeletedAt = *role.DeletedAt
+	}
+	return trashed
+}
+
+func NewTrashRepository(db *gorm.DB) port.TrashRepositoryInterface {
+	return &TrashRepository{db: db}
+}
@@ -2,23 +2,51 @@ package repository
 
 import (
 	"context"
-	"strconv"
+	"errors"
+	"math"
+	"strings"
+	"sync"
+	"time"
 	"user-service/internal/core/domain/entity"
 	"user-service/internal/core/domain/model"
 	"user-service/internal/core/port"
+	"user-service/utils/pii"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository struct {
 	db *gorm.DB
+
+	postgisOnce    sync.Once
+	postgisEnabled bool
+}
+
+// customerListRow is the scan target for GetCustomers; the list only renders these columns
+type customerListRow struct {
+	ID       int64
+	Name     string
+	Email    string
+	Username *string
+	Photo    string
+	Phone    string `gorm:"serializer:pii"`
+	// HidePhone is all GetCustomers reads of the privacy settings, since the list has no address
+	HidePhone bool
+}
+
+// userWithDistance is the scan target for distance queries
+type userWithDistance struct {
+	model.User `gorm:"embedded"`
+	DistanceKm float64
 }
 
 // GetUserByEmail implements UserRepositoryInterface.
 func (u *UserRepository) GetUserByEmail(ctx context.Context, email string) (*entity.UserEntity, error) {
 	modelUser := model.User{}
-	if err := u.db.Where("email = ? AND is_verified = ?", email, true).Preload("Roles").First(&modelUser).Error; err != nil {
+	if err := u.db.WithContext(ctx).Where("email = ? AND is_verified = ? AND deleted_at IS NULL", email, true).Preload("Roles").First(&modelUser).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			log.Info().Str("email", email).Msg("[UserRepository-GetUserByEmail] User not found")
 			return nil, gorm.ErrRecordNotFound
@@ -35,64 +63,76 @@ func (u *UserRepository) GetUserByEmail(ctx context.Context, email string) (*ent
 		roleName = "user" // Default role
 	}
 
-	lat, lng, err := u.parseLatLng(modelUser.Lat, modelUser.Lng)
-	if err != nil {
-		log.Warn().Err(err).Str("lat", modelUser.Lat).Str("lng", modelUser.Lng).Int64("user_id", modelUser.ID).Msg("[UserRepository-GetUserByEmail] Failed to parse lat/lng, using default values")
-		lat, lng = 0.0, 0.0
-	}
-
 	return &entity.UserEntity{
-		ID:         modelUser.ID,
-		Name:       modelUser.Name,
-		Email:      email,
-		Password:   modelUser.Password,
-		RoleName:   roleName,
-		Address:    modelUser.Address,
-		Lat:        lat,
-		Lng:        lng,
-		Phone:      modelUser.Phone,
-		Photo:      modelUser.Photo,
-		IsVerified: modelUser.IsVerified,
+		ID:                modelUser.ID,
+		Name:              modelUser.Name,
+		Email:             email,
+		Username:          stringValue(modelUser.Username),
+		Timezone:          modelUser.Timezone,
+		Language:          modelUser.Language,
+		Password:          modelUser.Password,
+		RoleName:          roleName,
+		Address:           modelUser.Address,
+		Province:          modelUser.Province,
+		City:              modelUser.City,
+		District:          modelUser.District,
+		PostalCode:        modelUser.PostalCode,
+		Lat:               floatValue(modelUser.Lat),
+		Lng:               floatValue(modelUser.Lng),
+		Phone:             modelUser.Phone,
+		Photo:             modelUser.Photo,
+		PhotoOriginal:     modelUser.PhotoOriginal,
+		IsVerified:        modelUser.IsVerified,
+		LockedAt:          modelUser.LockedAt,
+		LockReason:        modelUser.LockReason,
+		BouncedAt:         modelUser.EmailBouncedAt,
+		Version:           modelUser.Version,
+		PasswordChangedAt: modelUser.PasswordChangedAt,
 	}, nil
 }
 
 func (u *UserRepository) CreateUser(ctx context.Context, user *entity.UserEntity) (*entity.UserEntity, error) {
-	// Format lat/lng from float64 to string for database
-	latStr, lngStr := u.formatLatLng(user.Lat, user.Lng)
+	lat, lng := nullableCoordinates(user.Lat, user.Lng)
 
 	modelUser := &model.User{
 		Name:       user.Name,
 		Email:      user.Email,
 		Password:   user.Password,
 		Address:    user.Address,
-		Lat:        latStr,
-		Lng:        lngStr,
+		Lat:        lat,
+		Lng:        lng,
 		Phone:      user.Phone,
 		Photo:      user.Photo,
 		IsVerified: user.IsVerified,
+		Language:   user.Language,
 	}
 
-	if err := u.db.WithContext(ctx).Create(modelUser).Error; err != nil {
-		log.Error().Err(err).Str("email", user.Email).Msg("[UserRepository-CreateUser] Failed to create user")
-		return nil, err
-	}
-
-	// Assign default role "Customer"
+	// The user and its default role go in together, so a failed role never leaves a roleless account
 	customerRole := &model.Role{}
-	if err := u.db.Where("name = ?", "Customer").First(customerRole).Error; err != nil {
-		log.Error().Err(err).Msg("[UserRepository-CreateUser] Failed to find Customer role")
-		return nil, err
-	}
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(modelUser).Error; err != nil {
+			// Two signups can both pass the service's existence check; the unique index picks one
+			if isUniqueViolation(err, usersEmailIndex) {
+				log.Warn().Str("email", user.Email).Msg("[UserRepository-CreateUser] Email already exists")
+				return errors.New("email already exists")
+			}
+			log.Error().Err(err).Str("email", user.Email).Msg("[UserRepository-CreateUser] Failed to create user")
+			return err
+		}
 
-	if err := u.db.Model(modelUser).Association("Roles").Append(customerRole); err != nil {
-		log.Error().Err(err).Int64("user_id", modelUser.ID).Msg("[UserRepository-CreateUser] Failed to assign role")
-		return nil, err
-	}
+		// Assign default role "Customer"
+		if err := tx.Where("name = ?", "Customer").First(customerRole).Error; err != nil {
+			log.Error().Err(err).Msg("[UserRepository-CreateUser] Failed to find Customer role")
+			return err
+		}
 
-	// Parse lat/lng back to float64 for entity
-	lat, lng, err := u.parseLatLng(modelUser.Lat, modelUser.Lng)
+		if err := tx.Model(modelUser).Association("Roles").Append(customerRole); err != nil {
+			log.Error().Err(err).Int64("user_id", modelUser.ID).Msg("[UserRepository-CreateUser] Failed to assign role")
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		log.Error().Err(err).Str("lat", modelUser.Lat).Str("lng", modelUser.Lng).Msg("[UserRepository-CreateUser] Failed to parse lat/lng")
 		return nil, err
 	}
 
@@ -100,20 +140,30 @@ func (u *UserRepository) CreateUser(ctx context.Context, user *entity.UserEntity
 		ID:         modelUser.ID,
 		Name:       modelUser.Name,
 		Email:      modelUser.Email,
+		Username:   stringValue(modelUser.Username),
+		Timezone:   modelUser.Timezone,
+		Language:   modelUser.Language,
 		Password:   modelUser.Password,
 		RoleName:   customerRole.Name,
 		Address:    modelUser.Address,
-		Lat:        lat,
-		Lng:        lng,
+		Province:   modelUser.Province,
+		City:       modelUser.City,
+		District:   modelUser.District,
+		PostalCode: modelUser.PostalCode,
+		Lat:        floatValue(modelUser.Lat),
+		Lng:        floatValue(modelUser.Lng),
 		Phone:      modelUser.Phone,
 		Photo:      modelUser.Photo,
 		IsVerified: modelUser.IsVerified,
+		LockedAt:   modelUser.LockedAt,
+		LockReason: modelUser.LockReason,
+		Version:    modelUser.Version,
 	}, nil
 }
 
 func (u *UserRepository) GetRoleByName(ctx context.Context, name string) (*entity.RoleEntity, error) {
 	modelRole := &model.Role{}
-	if err := u.db.Where("name = ?", name).First(modelRole).Error; err != nil {
+	if err := u.db.WithContext(ctx).Where("name = ? AND deleted_at IS NULL", name).First(modelRole).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			log.Info().Str("role_name", name).Msg("[UserRepository-GetRoleByName] Role not found")
 			return nil, gorm.ErrRecordNotFound
@@ -141,10 +191,29 @@ func (u *UserRepository) UpdateUserVerificationStatus(ctx context.Context, userI
 	return nil
 }
 
+func (u *UserRepository) UpdateUserRole(ctx context.Context, userID, roleID int64) error {
+	return u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		role := &model.Role{}
+		if err := tx.First(role, roleID).Error; err != nil {
+			log.Error().Err(err).Int64("role_id", roleID).Msg("[UserRepository-UpdateUserRole] Failed to find role")
+			return err
+		}
+
+		user := &model.User{ID: userID}
+		if err := tx.Model(user).Association("Roles").Replace(role); err != nil {
+			log.Error().Err(err).Int64("user_id", userID).Int64("role_id", roleID).Msg("[UserRepository-UpdateUserRole] Failed to update user role")
+			return err
+		}
+
+		log.Info().Int64("user_id", userID).Str("role_name", role.Name).Msg("[UserRepository-UpdateUserRole] User role updated successfully")
+		return nil
+	})
+}
+
 // GetUserByEmailIncludingUnverified implements UserRepositoryInterface.
 func (u *UserRepository) GetUserByID(ctx context.Context, userID int64) (*entity.UserEntity, error) {
 	modelUser := model.User{}
-	if err := u.db.Where("id = ? AND is_verified = ?", userID, true).Preload("Roles").First(&modelUser).Error; err != nil {
+	if err := u.db.WithContext(ctx).Where("id = ? AND is_verified = ? AND deleted_at IS NULL", userID, true).Preload("Roles").First(&modelUser).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			log.Info().Int64("user_id", userID).Msg("[UserRepository-GetUserByID] User not found")
 			return nil, gorm.ErrRecordNotFound
@@ -160,32 +229,39 @@ func (u *UserRepository) GetUserByID(ctx context.Context, userID int64) (*entity
 		roleName = "user" // Default role
 	}
 
-	// Parse lat/lng from string to float64 with error handling
-	lat, lng, err := u.parseLatLng(modelUser.Lat, modelUser.Lng)
-	if err != nil {
-		log.Warn().Err(err).Str("lat", modelUser.Lat).Str("lng", modelUser.Lng).Int64("user_id", modelUser.ID).Msg("[UserRepository-GetUserByID] Failed to parse lat/lng, using default values")
-		// Use default values instead of failing
-		lat, lng = 0.0, 0.0
-	}
-
 	return &entity.UserEntity{
-		ID:         modelUser.ID,
-		Name:       modelUser.Name,
-		Email:      modelUser.Email,
-		Password:   modelUser.Password,
-		RoleName:   roleName,
-		Address:    modelUser.Address,
-		Lat:        lat,
-		Lng:        lng,
-		Phone:      modelUser.Phone,
-		Photo:      modelUser.Photo,
-		IsVerified: modelUser.IsVerified,
+		ID:            modelUser.ID,
+		Name:          modelUser.Name,
+		Email:         modelUser.Email,
+		Username:      stringValue(modelUser.Username),
+		Timezone:      modelUser.Timezone,
+		Language:      modelUser.Language,
+		Password:      modelUser.Password,
+		RoleName:      roleName,
+		Address:       modelUser.Address,
+		Province:      modelUser.Province,
+		City:          modelUser.City,
+		District:      modelUser.District,
+		PostalCode:    modelUser.PostalCode,
+		Lat:           floatValue(modelUser.Lat),
+		Lng:           floatValue(modelUser.Lng),
+		Phone:         modelUser.Phone,
+		Photo:         modelUser.Photo,
+		PhotoOriginal: modelUser.PhotoOriginal,
+		IsVerified:    modelUser.IsVerified,
+		LockedAt:      modelUser.LockedAt,
+		LockReason:    modelUser.LockReason,
+		BouncedAt:     modelUser.EmailBouncedAt,
+		Version:       modelUser.Version,
+		Privacy:       entity.PrivacySettingsEntity{HidePhone: modelUser.HidePhone, HideAddress: modelUser.HideAddress},
 	}, nil
 }
 
+// GetUserByEmailIncludingUnverified finds the active account using email. Deleted accounts keep
+// their email in the trash but no longer own it.
 func (u *UserRepository) GetUserByEmailIncludingUnverified(ctx context.Context, email string) (*entity.UserEntity, error) {
 	modelUser := model.User{}
-	if err := u.db.Where("email = ?", email).Preload("Roles").First(&modelUser).Error; err != nil {
+	if err := u.db.WithContext(ctx).Where("email = ? AND deleted_at IS NULL", email).Preload("Roles").First(&modelUser).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			log.Info().Str("email", email).Msg("[UserRepository-GetUserByEmailIncludingUnverified] User not found")
 			return nil, gorm.ErrRecordNotFound
@@ -202,26 +278,30 @@ func (u *UserRepository) GetUserByEmailIncludingUnverified(ctx context.Context,
 		roleName = "user" // Default role
 	}
 
-	// Parse lat/lng from string to float64 with error handling
-	lat, lng, err := u.parseLatLng(modelUser.Lat, modelUser.Lng)
-	if err != nil {
-		log.Warn().Err(err).Str("lat", modelUser.Lat).Str("lng", modelUser.Lng).Int64("user_id", modelUser.ID).Msg("[UserRepository-GetUserByEmailIncludingUnverified] Failed to parse lat/lng, using default values")
-		// Use default values instead of failing - this prevents email uniqueness checking from breaking
-		lat, lng = 0.0, 0.0
-	}
-
 	return &entity.UserEntity{
-		ID:         modelUser.ID,
-		Name:       modelUser.Name,
-		Email:      email,
-		Password:   modelUser.Password,
-		RoleName:   roleName,
-		Address:    modelUser.Address,
-		Lat:        lat,
-		Lng:        lng,
-		Phone:      modelUser.Phone,
-		Photo:      modelUser.Photo,
-		IsVerified: modelUser.IsVerified,
+		ID:            modelUser.ID,
+		Name:          modelUser.Name,
+		Email:         email,
+		Username:      stringValue(modelUser.Username),
+		Timezone:      modelUser.Timezone,
+		Language:      modelUser.Language,
+		Password:      modelUser.Password,
+		RoleName:      roleName,
+		Address:       modelUser.Address,
+		Province:      modelUser.Province,
+		City:          modelUser.City,
+		District:      modelUser.District,
+		PostalCode:    modelUser.PostalCode,
+		Lat:           floatValue(modelUser.Lat),
+		Lng:           floatValue(modelUser.Lng),
+		Phone:         modelUser.Phone,
+		Photo:         modelUser.Photo,
+		PhotoOriginal: modelUser.PhotoOriginal,
+		IsVerified:    modelUser.IsVerified,
+		LockedAt:      modelUser.LockedAt,
+		LockReason:    modelUser.LockReason,
+		BouncedAt:     modelUser.EmailBouncedAt,
+		Version:       modelUser.Version,
 	}, nil
 }
 
@@ -235,18 +315,149 @@ func (u *UserRepository) UpdateUserPassword(ctx context.Context, userID int64, h
 	return nil
 }
 
+func (u *UserRepository) ChangeUserPassword(ctx context.Context, userID int64, hashedPassword string, keepHistory int) error {
+	err := u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		current := model.User{}
+		if err := tx.Select("id", "password").Where("id = ?", userID).First(&current).Error; err != nil {
+			return err
+		}
+		if keepHistory > 0 && current.Password != "" {
+			if err := tx.Create(&model.PasswordHistory{UserID: userID, PasswordHash: current.Password}).Error; err != nil {
+				return err
+			}
+		}
+
+		// Entries past keepHistory can no longer block a reuse
+		kept := tx.Model(&model.PasswordHistory{}).Select("id").Where("user_id = ?", userID).Order("created_at DESC, id DESC").Limit(keepHistory)
+		if err := tx.Where("user_id = ? AND id NOT IN (?)", userID, kept).Delete(&model.PasswordHistory{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"password":                  hashedPassword,
+			"password_changed_at":       time.Now(),
+			"password_expiry_warned_at": nil,
+		}).Error
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[UserRepository-ChangeUserPassword] Failed to change password")
+		return err
+	}
+
+	log.Info().Int64("user_id", userID).Msg("[UserRepository-ChangeUserPassword] User password changed successfully")
+	return nil
+}
+
+func (u *UserRepository) GetPasswordHistory(ctx context.Context, userID int64, limit int) ([]string, error) {
+	var hashes []string
+	if limit <= 0 {
+		return hashes, nil
+	}
+	err := u.db.WithContext(ctx).Model(&model.PasswordHistory{}).
+		Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Pluck("password_hash", &hashes).Error
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[UserRepository-GetPasswordHistory] Failed to get password history")
+		return nil, err
+	}
+	return hashes, nil
+}
+
+func (u *UserRepository) GetPasswordsDueForWarning(ctx context.Context, roles []string, changedAfter, changedBefore time.Time, afterID int64, limit int) ([]entity.PasswordExpiryEntity, error) {
+	var rows []struct {
+		ID                int64
+		Email             string
+		Name              string
+		Timezone          string
+		PasswordChangedAt time.Time
+	}
+	err := u.db.WithContext(ctx).Model(&model.User{}).
+		Select("users.id, users.email, users.name, users.timezone, users.password_changed_at").
+		Joins("JOIN user_role ur ON users.id = ur.user_id").
+		Joins("JOIN roles r ON ur.role_id = r.id").
+		Where("r.name IN ? AND users.is_verified = ? AND users.deleted_at IS NULL", roles, true).
+		Where("users.password_changed_at > ? AND users.password_changed_at <= ?", changedAfter, changedBefore).
+		Where("users.password_expiry_warned_at IS NULL AND users.id > ?", afterID).
+		Order("users.id").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		log.Error().Err(err).Msg("[UserRepository-GetPasswordsDueForWarning] Failed to list expiring passwords")
+		return nil, err
+	}
+
+	users := make([]entity.PasswordExpiryEntity, 0, len(rows))
+	for _, row := range rows {
+		users = append(users, entity.PasswordExpiryEntity{
+			UserID:            row.ID,
+			Email:             row.Email,
+			Name:              row.Name,
+			Timezone:          row.Timezone,
+			PasswordChangedAt: row.PasswordChangedAt,
+		})
+	}
+	return users, nil
+}
+
+func (u *UserRepository) MarkPasswordExpiryWarned(ctx context.Context, userID int64) error {
+	if err := u.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Update("password_expiry_warned_at", time.Now()).Error; err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[UserRepository-MarkPasswordExpiryWarned] Failed to mark warning sent")
+		return err
+	}
+	return nil
+}
+
+// UpdateUserPhoto sets a photo that has no separate original, such as a generated avatar
 func (u *UserRepository) UpdateUserPhoto(ctx context.Context, userID int64, photoURL string) error {
-	if err := u.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Update("photo", photoURL).Error; err != nil {
-		log.Error().Err(err).Int64("user_id", userID).Str("photo_url", photoURL).Msg("[UserRepository-UpdateUserPhoto] Failed to update user photo")
+	return u.UpdateUserPhotos(ctx, userID, photoURL, "")
+}
+
+// UpdateUserPhotos sets the processed photo together with the upload it was cropped from
+func (u *UserRepository) UpdateUserPhotos(ctx context.Context, userID int64, photoURL, originalURL string) error {
+	err := u.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"photo":          photoURL,
+		"photo_original": originalURL,
+	}).Error
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Str("photo_url", photoURL).Msg("[UserRepository-UpdateUserPhotos] Failed to update user photo")
 		return err
 	}
 
-	log.Info().Int64("user_id", userID).Str("photo_url", photoURL).Msg("[UserRepository-UpdateUserPhoto] User photo updated successfully")
+	log.Info().Int64("user_id", userID).Str("photo_url", photoURL).Msg("[UserRepository-UpdateUserPhotos] User photo updated successfully")
 	return nil
 }
 
+func (u *UserRepository) ClearPhotoByURL(ctx context.Context, photoURL string) ([]int64, error) {
+	var userIDs []int64
+	err := u.db.WithContext(ctx).Raw(`UPDATE users SET photo = '', updated_at = NOW() WHERE photo = ? RETURNING id`, photoURL).Scan(&userIDs).Error
+	if err != nil {
+		log.Error().Err(err).Str("photo_url", photoURL).Msg("[UserRepository-ClearPhotoByURL] Failed to clear user photo")
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+func (u *UserRepository) IsPhotoReferenced(ctx context.Context, photoURL string) (bool, error) {
+	var count int64
+	if err := u.db.WithContext(ctx).Model(&model.User{}).Where("photo = ? OR photo_original = ?", photoURL, photoURL).Count(&count).Error; err != nil {
+		log.Error().Err(err).Str("photo_url", photoURL).Msg("[UserRepository-IsPhotoReferenced] Failed to check photo")
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// UpdateUserEmail also clears a bounce recorded for the previous address
 func (u *UserRepository) UpdateUserEmail(ctx context.Context, userID int64, email string) error {
-	if err := u.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Update("email", email).Error; err != nil {
+	if err := u.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+		"email":               email,
+		"email_bounced_at":    nil,
+		"email_bounce_reason": "",
+	}).Error; err != nil {
+		if isUniqueViolation(err, usersEmailIndex) {
+			return errors.New("email already exists")
+		}
 		log.Error().Err(err).Int64("user_id", userID).Str("email", email).Msg("[UserRepository-UpdateUserEmail] Failed to update user email")
 		return err
 	}
@@ -255,22 +466,241 @@ func (u *UserRepository) UpdateUserEmail(ctx context.Context, userID int64, emai
 	return nil
 }
 
-func (u *UserRepository) UpdateUserProfile(ctx context.Context, userID int64, name, email, phone, address string, lat, lng float64, photo string) error {
-	// Format lat/lng from float64 to string for database
-	latStr, lngStr := u.formatLatLng(lat, lng)
+// RestoreEmailAndLock puts back a previous email and holds the account for review in one update
+func (u *UserRepository) RestoreEmailAndLock(ctx context.Context, userID int64, email, reason string) error {
+	result := u.db.WithContext(ctx).Model(&model.User{}).Where("id = ? AND deleted_at IS NULL", userID).Updates(map[string]interface{}{
+		"email":       email,
+		"locked_at":   time.Now(),
+		"lock_reason": reason,
+	})
+	if result.Error != nil {
+		if isUniqueViolation(result.Error, usersEmailIndex) {
+			return errors.New("email already taken")
+		}
+		log.Error().Err(result.Error).Int64("user_id", userID).Msg("[UserRepository-RestoreEmailAndLock] Failed to restore email")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	log.Warn().Int64("user_id", userID).Str("email", email).Str("reason", reason).Msg("[UserRepository-RestoreEmailAndLock] Email restored and account locked")
+	return nil
+}
+
+// MarkEmailBounced returns gorm.ErrRecordNotFound when the user no longer has that email
+func (u *UserRepository) MarkEmailBounced(ctx context.Context, userID int64, email, reason string) error {
+	result := u.db.WithContext(ctx).Model(&model.User{}).Where("id = ? AND email = ?", userID, email).Updates(map[string]interface{}{
+		"email_bounced_at":    time.Now(),
+		"email_bounce_reason": reason,
+	})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("user_id", userID).Msg("[UserRepository-MarkEmailBounced] Failed to mark email bounced")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	log.Warn().Int64("user_id", userID).Str("email", email).Str("reason", reason).Msg("[UserRepository-MarkEmailBounced] Email marked undeliverable")
+	return nil
+}
+
+func (u *UserRepository) UnlockUser(ctx context.Context, userID int64) error {
+	result := u.db.WithContext(ctx).Model(&model.User{}).Where("id = ? AND locked_at IS NOT NULL", userID).Updates(map[string]interface{}{
+		"locked_at":   nil,
+		"lock_reason": "",
+	})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("user_id", userID).Msg("[UserRepository-UnlockUser] Failed to unlock user")
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetLockedUsers lists accounts awaiting review, oldest lock first
+func (u *UserRepository) GetLockedUsers(ctx context.Context) ([]entity.UserEntity, error) {
+	var users []model.User
+	if err := u.db.WithContext(ctx).Where("locked_at IS NOT NULL AND deleted_at IS NULL").Order("locked_at ASC").Find(&users).Error; err != nil {
+		log.Error().Err(err).Msg("[UserRepository-GetLockedUsers] Failed to get locked users")
+		return nil, err
+	}
+
+	result := make([]entity.UserEntity, 0, len(users))
+	for _, user := range users {
+		result = append(result, entity.UserEntity{
+			ID:         user.ID,
+			Name:       user.Name,
+			Email:      user.Email,
+			Username:   stringValue(user.Username),
+			IsVerified: user.IsVerified,
+			LockedAt:   user.LockedAt,
+			LockReason: user.LockReason,
+		})
+	}
+	return result, nil
+}
+
+// GetEmailsByRole returns the addresses of active, verified users holding roleName
+func (u *UserRepository) GetEmailsByRole(ctx context.Context, roleName string) ([]string, error) {
+	var emails []string
+	err := u.db.WithContext(ctx).Model(&model.User{}).
+		Joins("JOIN user_role ur ON users.id = ur.user_id").
+		Joins("JOIN roles r ON ur.role_id = r.id").
+		Where("r.name = ? AND users.is_verified = ? AND users.deleted_at IS NULL", roleName, true).
+		Pluck("users.email", &emails).Error
+	if err != nil {
+		log.Error().Err(err).Str("role", roleName).Msg("[UserRepository-GetEmailsByRole] Failed to get emails")
+		return nil, err
+	}
+	return emails, nil
+}
+
+// GetUserByUsername expects a normalized (lowercased) username
+func (u *UserRepository) GetUserByUsername(ctx context.Context, username string) (*entity.UserEntity, error) {
+	modelUser := model.User{}
+	if err := u.db.WithContext(ctx).Where("username = ? AND is_verified = ? AND deleted_at IS NULL", username, true).Preload("Roles").First(&modelUser).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			log.Info().Str("username", username).Msg("[UserRepository-GetUserByUsername] User not found")
+			return nil, gorm.ErrRecordNotFound
+		}
+		log.Error().Err(err).Str("username", username).Msg("[UserRepository-GetUserByUsername] Failed to get user by username")
+		return nil, err
+	}
+
+	roleName := "user"
+	if len(modelUser.Roles) > 0 {
+		roleName = modelUser.Roles[0].Name
+	}
+
+	return &entity.UserEntity{
+		ID:                modelUser.ID,
+		Name:              modelUser.Name,
+		Email:             modelUser.Email,
+		Username:          username,
+		Timezone:          modelUser.Timezone,
+		Language:          modelUser.Language,
+		Password:          modelUser.Password,
+		RoleName:          roleName,
+		Address:           modelUser.Address,
+		Province:          modelUser.Province,
+		City:              modelUser.City,
+		District:          modelUser.District,
+		PostalCode:        modelUser.PostalCode,
+		Lat:               floatValue(modelUser.Lat),
+		Lng:               floatValue(modelUser.Lng),
+		Phone:             modelUser.Phone,
+		Photo:             modelUser.Photo,
+		PhotoOriginal:     modelUser.PhotoOriginal,
+		IsVerified:        modelUser.IsVerified,
+		LockedAt:          modelUser.LockedAt,
+		LockReason:        modelUser.LockReason,
+		BouncedAt:         modelUser.EmailBouncedAt,
+		Version:           modelUser.Version,
+		PasswordChangedAt: modelUser.PasswordChangedAt,
+	}, nil
+}
+
+// IsUsernameTaken also counts soft-deleted and unverified accounts, which keep their username
+func (u *UserRepository) IsUsernameTaken(ctx context.Context, username string) (bool, error) {
+	var count int64
+	if err := u.db.WithContext(ctx).Model(&model.User{}).Where("username = ?", username).Count(&count).Error; err != nil {
+		log.Error().Err(err).Str("username", username).Msg("[UserRepository-IsUsernameTaken] Failed to check username")
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (u *UserRepository) UpdateTimezone(ctx context.Context, userID int64, timezone string) error {
+	if err := u.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Update("timezone", timezone).Error; err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Str("timezone", timezone).Msg("[UserRepository-UpdateTimezone] Failed to update timezone")
+		return err
+	}
+
+	log.Info().Int64("user_id", userID).Str("timezone", timezone).Msg("[UserRepository-UpdateTimezone] Timezone updated successfully")
+	return nil
+}
+
+func (u *UserRepository) UpdateLanguage(ctx context.Context, userID int64, language string) error {
+	if err := u.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Update("language", language).Error; err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Str("language", language).Msg("[UserRepository-UpdateLanguage] Failed to update language")
+		return err
+	}
+
+	log.Info().Int64("user_id", userID).Str("language", language).Msg("[UserRepository-UpdateLanguage] Language updated successfully")
+	return nil
+}
+
+func (u *UserRepository) UpdatePrivacySettings(ctx context.Context, userID int64, settings entity.PrivacySettingsEntity) error {
+	updates := map[string]interface{}{
+		"hide_phone":   settings.HidePhone,
+		"hide_address": settings.HideAddress,
+	}
+	if err := u.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Updates(updates).Error; err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[UserRepository-UpdatePrivacySettings] Failed to update privacy settings")
+		return err
+	}
+
+	log.Info().Int64("user_id", userID).Bool("hide_phone", settings.HidePhone).Bool("hide_address", settings.HideAddress).Msg("[UserRepository-UpdatePrivacySettings] Privacy settings updated successfully")
+	return nil
+}
+
+// GetLanguageByEmail returns "" for addresses without an account, such as a pending email change
+func (u *UserRepository) GetLanguageByEmail(ctx context.Context, email string) (string, error) {
+	var languages []string
+	if err := u.db.WithContext(ctx).Model(&model.User{}).Where("email = ? AND deleted_at IS NULL", email).Limit(1).Pluck("language", &languages).Error; err != nil {
+		log.Error().Err(err).Str("email", email).Msg("[UserRepository-GetLanguageByEmail] Failed to get language")
+		return "", err
+	}
+	if len(languages) == 0 {
+		return "", nil
+	}
+	return languages[0], nil
+}
+
+// UpdateUsername relies on the unique index so two concurrent claims cannot both win
+func (u *UserRepository) UpdateUsername(ctx context.Context, userID int64, username string) error {
+	if err := u.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Update("username", username).Error; err != nil {
+		if isUniqueViolation(err, usersUsernameIndex) {
+			return errors.New("username already taken")
+		}
+		log.Error().Err(err).Int64("user_id", userID).Str("username", username).Msg("[UserRepository-UpdateUsername] Failed to update username")
+		return err
+	}
+
+	log.Info().Int64("user_id", userID).Str("username", username).Msg("[UserRepository-UpdateUsername] Username updated successfully")
+	return nil
+}
+
+// UpdateUserProfile only applies when version still matches the stored one (0 skips the check)
+func (u *UserRepository) UpdateUserProfile(ctx context.Context, userID int64, name, email, phone, address string, lat, lng float64, photo string, version int64) error {
+	latValue, lngValue := nullableCoordinates(lat, lng)
 
 	updates := map[string]interface{}{
 		"name":    name,
 		"email":   email,
-		"phone":   phone,
-		"address": address,
-		"lat":     latStr,
-		"lng":     lngStr,
+		"phone":   pii.Value(phone),
+		"address": pii.Value(address),
+		"lat":     latValue,
+		"lng":     lngValue,
 		"photo":   photo,
 	}
 
-	if err := u.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Updates(updates).Error; err != nil {
-		log.Error().Err(err).Int64("user_id", userID).Str("email", email).Msg("[UserRepository-UpdateUserProfile] Failed to update user profile")
+	result := u.db.WithContext(ctx).Model(&model.User{ID: userID, Version: version}).Where("id = ?", userID).Updates(updates)
+	if result.Error != nil {
+		if isUniqueViolation(result.Error, usersEmailIndex) {
+			return errors.New("email already exists")
+		}
+		log.Error().Err(result.Error).Int64("user_id", userID).Str("email", email).Msg("[UserRepository-UpdateUserProfile] Failed to update user profile")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 && version > 0 {
+		err := versionConflict(u.db.WithContext(ctx), "users", userID)
+		log.Warn().Err(err).Int64("user_id", userID).Int64("version", version).Msg("[UserRepository-UpdateUserProfile] Profile update rejected")
 		return err
 	}
 
@@ -278,28 +708,31 @@ func (u *UserRepository) UpdateUserProfile(ctx context.Context, userID int64, na
 	return nil
 }
 
-// Helper functions for lat/lng conversion
-func (u *UserRepository) parseLatLng(latStr, lngStr string) (float64, float64, error) {
-	lat, err := strconv.ParseFloat(latStr, 64)
-	if err != nil {
-		return 0, 0, err
+func (u *UserRepository) UpdateUserAddressComponents(ctx context.Context, userID int64, address entity.AddressEntity) error {
+	updates := map[string]interface{}{
+		"province":    address.Province,
+		"city":        address.City,
+		"district":    address.District,
+		"postal_code": address.PostalCode,
 	}
-	lng, err := strconv.ParseFloat(lngStr, 64)
-	if err != nil {
-		return 0, 0, err
+
+	if err := u.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", userID).Updates(updates).Error; err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[UserRepository-UpdateUserAddressComponents] Failed to update address components")
+		return err
 	}
-	return lat, lng, nil
-}
 
-func (u *UserRepository) formatLatLng(lat, lng float64) (string, string) {
-	return strconv.FormatFloat(lat, 'f', -1, 64), strconv.FormatFloat(lng, 'f', -1, 64)
+	log.Info().Int64("user_id", userID).Str("city", address.City).Msg("[UserRepository-UpdateUserAddressComponents] Address components updated successfully")
+	return nil
 }
 
+// GetCustomers returns one page of the customer list. The role filter is a join and only the
+// listed columns are read, so neither roles nor full user rows are loaded.
 func (u *UserRepository) GetCustomers(ctx context.Context, search string, page, limit int, orderBy string) ([]entity.UserEntity, int64, error) {
-	var users []model.User
+	var rows []customerListRow
 	var totalCount int64
 
-	query := u.db.WithContext(ctx).Joins("JOIN user_role ur ON users.id = ur.user_id").
+	query := u.db.WithContext(ctx).Table("users").
+		Joins("JOIN user_role ur ON users.id = ur.user_id").
 		Joins("JOIN roles r ON ur.role_id = r.id").
 		Where("r.name = ? AND users.is_verified = ?", "Customer", true).
 		Where("users.deleted_at IS NULL")
@@ -310,7 +743,7 @@ func (u *UserRepository) GetCustomers(ctx context.Context, search string, page,
 	}
 
 	// Get total count for pagination
-	if err := query.Model(&model.User{}).Count(&totalCount).Error; err != nil {
+	if err := query.Count(&totalCount).Error; err != nil {
 		log.Error().Err(err).Str("search", search).Msg("[UserRepository-GetCustomers] Failed to count customers")
 		return nil, 0, err
 	}
@@ -326,32 +759,24 @@ func (u *UserRepository) GetCustomers(ctx context.Context, search string, page,
 	offset := (page - 1) * limit
 	query = query.Offset(offset).Limit(limit)
 
-	// Execute query with preloading roles
-	if err := query.Preload("Roles").Find(&users).Error; err != nil {
+	err := query.Select("users.id, users.name, users.email, users.username, users.photo, users.phone, users.hide_phone").Scan(&rows).Error
+	if err != nil {
 		log.Error().Err(err).Str("search", search).Int("page", page).Int("limit", limit).Msg("[UserRepository-GetCustomers] Failed to get customers")
 		return nil, 0, err
 	}
 
-	var customerEntities []entity.UserEntity
-	for _, user := range users {
-		// Parse lat/lng
-		lat, lng, err := u.parseLatLng(user.Lat, user.Lng)
-		if err != nil {
-			log.Warn().Err(err).Str("lat", user.Lat).Str("lng", user.Lng).Int64("user_id", user.ID).Msg("[UserRepository-GetCustomers] Failed to parse lat/lng, using default values")
-			lat, lng = 0.0, 0.0
-		}
-
+	customerEntities := make([]entity.UserEntity, 0, len(rows))
+	for _, row := range rows {
 		customerEntities = append(customerEntities, entity.UserEntity{
-			ID:         user.ID,
-			Name:       user.Name,
-			Email:      user.Email,
-			Photo:      user.Photo,
-			Phone:      user.Phone,
+			ID:         row.ID,
+			Name:       row.Name,
+			Email:      row.Email,
+			Username:   stringValue(row.Username),
+			Photo:      row.Photo,
+			Phone:      row.Phone,
 			RoleName:   "Customer", // Since we filtered by role
-			Address:    user.Address,
-			Lat:        lat,
-			Lng:        lng,
-			IsVerified: user.IsVerified,
+			IsVerified: true,
+			Privacy:    entity.PrivacySettingsEntity{HidePhone: row.HidePhone},
 		})
 	}
 
@@ -359,6 +784,47 @@ func (u *UserRepository) GetCustomers(ctx context.Context, search string, page,
 	return customerEntities, totalCount, nil
 }
 
+// likeEscaper makes user input match literally inside a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (u *UserRepository) SuggestCustomers(ctx context.Context, query string, limit int) ([]entity.UserEntity, error) {
+	escaped := likeEscaper.Replace(query)
+	contains, prefix := "%"+escaped+"%", escaped+"%"
+
+	// ILIKE '%q%' is served by the trigram indexes; prefix matches and short names rank first
+	var rows []customerListRow
+	err := u.db.WithContext(ctx).Table("users").
+		Joins("JOIN user_role ur ON users.id = ur.user_id").
+		Joins("JOIN roles r ON ur.role_id = r.id").
+		Where("r.name = ? AND users.is_verified = ?", "Customer", true).
+		Where("users.deleted_at IS NULL").
+		Where("users.name ILIKE ? OR users.email ILIKE ?", contains, contains).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "(users.name ILIKE ? OR users.email ILIKE ?) DESC, LENGTH(users.name), users.id",
+			Vars:               []interface{}{prefix, prefix},
+			WithoutParentheses: true,
+		}}).
+		Limit(limit).
+		Select("users.id, users.name, users.email, users.photo").
+		Scan(&rows).Error
+	if err != nil {
+		log.Error().Err(err).Str("query", query).Msg("[UserRepository-SuggestCustomers] Failed to suggest customers")
+		return nil, err
+	}
+
+	customers := make([]entity.UserEntity, 0, len(rows))
+	for _, row := range rows {
+		customers = append(customers, entity.UserEntity{
+			ID:       row.ID,
+			Name:     row.Name,
+			Email:    row.Email,
+			Photo:    row.Photo,
+			RoleName: "Customer",
+		})
+	}
+	return customers, nil
+}
+
 func (u *UserRepository) GetCustomerByID(ctx context.Context, customerID int64) (*entity.UserEntity, error) {
 	modelUser := model.User{}
 	if err := u.db.WithContext(ctx).Where("id = ? AND is_verified = ?", customerID, true).Preload("Roles").First(&modelUser).Error; err != nil {
@@ -385,28 +851,188 @@ func (u *UserRepository) GetCustomerByID(ctx context.Context, customerID int64)
 		return nil, gorm.ErrRecordNotFound
 	}
 
-	lat, lng, err := u.parseLatLng(modelUser.Lat, modelUser.Lng)
+	return &entity.UserEntity{
+		ID:            modelUser.ID,
+		Name:          modelUser.Name,
+		Email:         modelUser.Email,
+		Username:      stringValue(modelUser.Username),
+		Timezone:      modelUser.Timezone,
+		Language:      modelUser.Language,
+		Password:      modelUser.Password,
+		RoleName:      roleName,
+		RoleID:        roleID,
+		Address:       modelUser.Address,
+		Province:      modelUser.Province,
+		City:          modelUser.City,
+		District:      modelUser.District,
+		PostalCode:    modelUser.PostalCode,
+		Lat:           floatValue(modelUser.Lat),
+		Lng:           floatValue(modelUser.Lng),
+		Phone:         modelUser.Phone,
+		Photo:         modelUser.Photo,
+		PhotoOriginal: modelUser.PhotoOriginal,
+		IsVerified:    modelUser.IsVerified,
+		LockedAt:      modelUser.LockedAt,
+		LockReason:    modelUser.LockReason,
+		BouncedAt:     modelUser.EmailBouncedAt,
+		Version:       modelUser.Version,
+		Privacy:       entity.PrivacySettingsEntity{HidePhone: modelUser.HidePhone, HideAddress: modelUser.HideAddress},
+	}, nil
+}
+
+// GetCustomersNearby returns verified customers within radiusKm of lat/lng, nearest first.
+// It uses the PostGIS location column when migration 000010 created it, otherwise haversine over lat/lng.
+func (u *UserRepository) GetCustomersNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]entity.UserEntity, error) {
+	query := u.db.WithContext(ctx).Table("users").
+		Joins("JOIN user_role ur ON users.id = ur.user_id").
+		Joins("JOIN roles r ON ur.role_id = r.id").
+		Where("r.name = ? AND users.is_verified = ?", "Customer", true).
+		Where("users.deleted_at IS NULL")
+
+	if u.hasPostGIS(ctx) {
+		point := "ST_SetSRID(ST_MakePoint(?, ?), 4326)::geography"
+		query = query.
+			Select("users.*, ST_Distance(users.location, "+point+") / 1000 AS distance_km", lng, lat).
+			Where("ST_DWithin(users.location, "+point+", ?)", lng, lat, radiusKm*1000)
+	} else {
+		// Bounding box first so the (lat, lng) index can be used, then the exact distance
+		latDelta := radiusKm / 111.0
+		lngDelta := radiusKm / (111.0 * math.Max(math.Cos(lat*math.Pi/180), 0.01))
+		distance := "6371 * ACOS(LEAST(1, COS(RADIANS(?)) * COS(RADIANS(users.lat)) * COS(RADIANS(users.lng) - RADIANS(?)) + SIN(RADIANS(?)) * SIN(RADIANS(users.lat))))"
+		query = query.
+			Select("users.*, "+distance+" AS distance_km", lat, lng, lat).
+			Where("users.lat BETWEEN ? AND ? AND users.lng BETWEEN ? AND ?", lat-latDelta, lat+latDelta, lng-lngDelta, lng+lngDelta).
+			Where(distance+" <= ?", lat, lng, lat, radiusKm)
+	}
+
+	var rows []userWithDistance
+	if err := query.Order("distance_km ASC").Limit(limit).Scan(&rows).Error; err != nil {
+		log.Error().Err(err).Float64("lat", lat).Float64("lng", lng).Float64("radius_km", radiusKm).Msg("[UserRepository-GetCustomersNearby] Failed to get nearby customers")
+		return nil, err
+	}
+
+	customerEntities := make([]entity.UserEntity, 0, len(rows))
+	for _, row := range rows {
+		customerEntities = append(customerEntities, entity.UserEntity{
+			ID:         row.ID,
+			Name:       row.Name,
+			Email:      row.Email,
+			Username:   stringValue(row.Username),
+			Timezone:   row.Timezone,
+			Photo:      row.Photo,
+			Phone:      row.Phone,
+			RoleName:   "Customer", // Since we filtered by role
+			Address:    row.Address,
+			Province:   row.Province,
+			City:       row.City,
+			District:   row.District,
+			PostalCode: row.PostalCode,
+			Lat:        floatValue(row.Lat),
+			Lng:        floatValue(row.Lng),
+			DistanceKm: row.DistanceKm,
+			IsVerified: row.IsVerified,
+			Privacy:    entity.PrivacySettingsEntity{HidePhone: row.HidePhone, HideAddress: row.HideAddress},
+		})
+	}
+
+	log.Info().Int("count", len(customerEntities)).Float64("radius_km", radiusKm).Bool("postgis", u.postgisEnabled).Msg("[UserRepository-GetCustomersNearby] Nearby customers retrieved successfully")
+	return customerEntities, nil
+}
+
+// GetUsersByIDs loads the contact and location fields for many users in a single query.
+// Unknown, unverified and deleted users are simply absent from the result.
+func (u *UserRepository) GetUsersByIDs(ctx context.Context, userIDs []int64) ([]entity.UserEntity, error) {
+	var modelUsers []model.User
+	err := u.db.WithContext(ctx).
+		Select("id", "name", "phone", "address", "province", "city", "district", "postal_code", "lat", "lng", "hide_phone", "hide_address").
+		Where("id IN ? AND is_verified = ? AND deleted_at IS NULL", userIDs, true).
+		Order("id ASC").
+		Find(&modelUsers).Error
 	if err != nil {
-		log.Warn().Err(err).Str("lat", modelUser.Lat).Str("lng", modelUser.Lng).Int64("customer_id", customerID).Msg("[UserRepository-GetCustomerByID] Failed to parse lat/lng, using default values")
-		lat, lng = 0.0, 0.0
+		log.Error().Err(err).Int("count", len(userIDs)).Msg("[UserRepository-GetUsersByIDs] Failed to get users by IDs")
+		return nil, err
 	}
 
-	return &entity.UserEntity{
-		ID:         modelUser.ID,
-		Name:       modelUser.Name,
-		Email:      modelUser.Email,
-		Password:   modelUser.Password,
-		RoleName:   roleName,
-		RoleID:     roleID,
-		Address:    modelUser.Address,
-		Lat:        lat,
-		Lng:        lng,
-		Phone:      modelUser.Phone,
-		Photo:      modelUser.Photo,
-		IsVerified: modelUser.IsVerified,
-	}, nil
+	userEntities := make([]entity.UserEntity, 0, len(modelUsers))
+	for _, modelUser := range modelUsers {
+		userEntities = append(userEntities, entity.UserEntity{
+			ID:         modelUser.ID,
+			Name:       modelUser.Name,
+			Phone:      modelUser.Phone,
+			Address:    modelUser.Address,
+			Province:   modelUser.Province,
+			City:       modelUser.City,
+			District:   modelUser.District,
+			PostalCode: modelUser.PostalCode,
+			Lat:        floatValue(modelUser.Lat),
+			Lng:        floatValue(modelUser.Lng),
+			Privacy:    entity.PrivacySettingsEntity{HidePhone: modelUser.HidePhone, HideAddress: modelUser.HideAddress},
+		})
+	}
+
+	return userEntities, nil
+}
+
+// hasPostGIS checks once whether users.location exists
+func (u *UserRepository) hasPostGIS(ctx context.Context) bool {
+	u.postgisOnce.Do(func() {
+		var exists bool
+		err := u.db.WithContext(ctx).Raw(
+			"SELECT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'users' AND column_name = 'location')",
+		).Scan(&exists).Error
+		if err != nil {
+			log.Warn().Err(err).Msg("[UserRepository-hasPostGIS] Failed to detect PostGIS location column, using haversine")
+			return
+		}
+		u.postgisEnabled = exists
+	})
+	return u.postgisEnabled
+}
+
+// nullableCoordinates stores an unset location (0,0) as NULL instead of a point in the Gulf of Guinea
+func nullableCoordinates(lat, lng float64) (*float64, *float64) {
+	if lat == 0 && lng == 0 {
+		return nil, nil
+	}
+	return &lat, &lng
+}
+
+// nullableString stores an empty string as NULL
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+func floatValue(value *float64) float64 {
+	if value == nil {
+		return 0
+	}
+	return *value
 }
 
 func NewUserRepository(db *gorm.DB) port.UserRepositoryInterface {
 	return &UserRepository{db: db}
 }
+
+// Unique indexes on users, as the migrations name them
+const (
+	usersEmailIndex      = "idx_users_email_active"
+	usersUsernameIndex   = "idx_users_username"
+	usersSSOSubjectIndex = "idx_users_sso_subject"
+)
+
+// isUniqueViolation reports a Postgres unique_violation (23505) on the given constraint or index,
+// so a clash on another unique column of the same row is not reported as this one
+func isUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constraint
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type VendorRepository struct {
+	db *gorm.DB
+}
+
+func (r *VendorRepository) CreateVendor(ctx context.Context, vendor *entity.VendorEntity) (*entity.VendorEntity, error) {
+	lat, lng := nullableCoordinates(vendor.Lat, vendor.Lng)
+
+	vendorModel := &model.Vendor{
+		UserID:      vendor.UserID,
+		StoreName:   vendor.StoreName,
+		Description: vendor.Description,
+		Phone:       vendor.Phone,
+		Address:     vendor.Address,
+		Lat:         lat,
+		Lng:         lng,
+		Status:      entity.VendorStatusPending,
+	}
+
+	if err := r.db.WithContext(ctx).Create(vendorModel).Error; err != nil {
+		log.Error().Err(err).Int64("user_id", vendor.UserID).Msg("[VendorRepository-CreateVendor] Failed to create vendor")
+		return nil, err
+	}
+
+	log.Info().Int64("vendor_id", vendorModel.ID).Int64("user_id", vendor.UserID).Msg("[VendorRepository-CreateVendor] Vendor created successfully")
+	return r.toEntity(vendorModel), nil
+}
+
+func (r *VendorRepository) GetVendorByID(ctx context.Context, id int64) (*entity.VendorEntity, error) {
+	var vendorModel model.Vendor
+	if err := r.db.WithContext(ctx).Where("deleted_at IS NULL").Preload("Documents").First(&vendorModel, id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			log.Info().Int64("vendor_id", id).Msg("[VendorRepository-GetVendorByID] Vendor not found")
+			return nil, gorm.ErrRecordNotFound
+		}
+		log.Error().Err(err).Int64("vendor_id", id).Msg("[VendorRepository-GetVendorByID] Failed to get vendor")
+		return nil, err
+	}
+
+	return r.toEntity(&vendorModel), nil
+}
+
+func (r *VendorRepository) GetVendorByUserID(ctx context.Context, userID int64) (*entity.VendorEntity, error) {
+	var vendorModel model.Vendor
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND deleted_at IS NULL", userID).Preload("Documents").First(&vendorModel).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			log.Info().Int64("user_id", userID).Msg("[VendorRepository-GetVendorByUserID] Vendor not found")
+			return nil, gorm.ErrRecordNotFound
+		}
+		log.Error().Err(err).Int64("user_id", userID).Msg("[VendorRepository-GetVendorByUserID] Failed to get vendor")
+		return nil, err
+	}
+
+	return r.toEntity(&vendorModel), nil
+}
+
+func (r *VendorRepository) GetVendors(ctx context.Context, status string, page, limit int) ([]entity.VendorEntity, int64, error) {
+	var vendors []model.Vendor
+	var totalCount int64
+
+	query := r.db.WithContext(ctx).Model(&model.Vendor{}).Where("deleted_at IS NULL")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Str("status", status).Msg("[VendorRepository-GetVendors] Failed to count vendors")
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("created_at ASC").Offset(offset).Limit(limit).Find(&vendors).Error; err != nil {
+		log.Error().Err(err).Str("status", status).Msg("[VendorRepository-GetVendors] Failed to get vendors")
+		return nil, 0, err
+	}
+
+	vendorEntities := make([]entity.VendorEntity, 0, len(vendors))
+	for i := range vendors {
+		vendorEntities = append(vendorEntities, *r.toEntity(&vendors[i]))
+	}
+
+	log.Info().Int("count", len(vendorEntities)).Int64("total_count", totalCount).Str("status", status).Msg("[VendorRepository-GetVendors] Vendors retrieved successfully")
+	return vendorEntities, totalCount, nil
+}
+
+func (r *VendorRepository) AddVendorDocument(ctx context.Context, document *entity.VendorDocumentEntity) (*entity.VendorDocumentEntity, error) {
+	documentModel := &model.VendorDocument{
+		VendorID:     document.VendorID,
+		DocumentType: document.DocumentType,
+		FileURL:      document.FileURL,
+		ObjectName:   document.ObjectName,
+		ContentType:  document.ContentType,
+	}
+
+	if err := r.db.WithContext(ctx).Create(documentModel).Error; err != nil {
+		log.Error().Err(err).Int64("vendor_id", document.VendorID).Msg("[VendorRepository-AddVendorDocument] Failed to save vendor document")
+		return nil, err
+	}
+
+	document.ID = documentModel.ID
+	document.CreatedAt = documentModel.CreatedAt
+	return document, nil
+}
+
+func (r *VendorRepository) ApproveVendor(ctx context.Context, id, reviewerID int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var vendorModel model.Vendor
+		if err := tx.Where("deleted_at IS NULL").First(&vendorModel, id).Error; err != nil {
+			log.Error().Err(err).Int64("vendor_id", id).Msg("[VendorRepository-ApproveVendor] Failed to get vendor")
+			return err
+		}
+
+		if err := r.review(tx, id, reviewerID, entity.VendorStatusApproved, ""); err != nil {
+			log.Error().Err(err).Int64("vendor_id", id).Msg("[VendorRepository-ApproveVendor] Failed to update vendor status")
+			return err
+		}
+
+		vendorRole := &model.Role{}
+		if err := tx.Where("name = ?", "Vendor").First(vendorRole).Error; err != nil {
+			log.Error().Err(err).Msg("[VendorRepository-ApproveVendor] Failed to find Vendor role")
+			return err
+		}
+
+		user := &model.User{ID: vendorModel.UserID}
+		if err := tx.Model(user).Association("Roles").Replace(vendorRole); err != nil {
+			log.Error().Err(err).Int64("user_id", vendorModel.UserID).Msg("[VendorRepository-ApproveVendor] Failed to assign Vendor role")
+			return err
+		}
+
+		log.Info().Int64("vendor_id", id).Int64("user_id", vendorModel.UserID).Int64("reviewer_id", reviewerID).Msg("[VendorRepository-ApproveVendor] Vendor approved successfully")
+		return nil
+	})
+}
+
+func (r *VendorRepository) RejectVendor(ctx context.Context, id, reviewerID int64, reason string) error {
+	if err := r.review(r.db.WithContext(ctx), id, reviewerID, entity.VendorStatusRejected, reason); err != nil {
+		log.Error().Err(err).Int64("vendor_id", id).Msg("[VendorRepository-RejectVendor] Failed to reject vendor")
+		return err
+	}
+
+	log.Info().Int64("vendor_id", id).Int64("reviewer_id", reviewerID).Msg("[VendorRepository-RejectVendor] Vendor rejected successfully")
+	return nil
+}
+
+// review only moves pending applications, so two admins cannot decide the same vendor twice
+func (r *VendorRepository) review(db *gorm.DB, id, reviewerID int64, status, reason string) error {
+	result := db.Model(&model.Vendor{}).
+		Where("id = ? AND status = ? AND deleted_at IS NULL", id, entity.VendorStatusPending).
+		Updates(map[string]interface{}{
+			"status":           status,
+			"rejection_reason": reason,
+			"reviewed_by":      reviewerID,
+			"reviewed_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("vendor is not pending review")
+	}
+
+	return nil
+}
+
+func (r *VendorRepository) toEntity(vendorModel *model.Vendor) *entity.VendorEntity {
+	vendorEntity := &entity.VendorEntity{
+		ID:              vendorModel.ID,
+		UserID:          vendorModel.UserID,
+		StoreName:       vendorModel.StoreName,
+		Description:     vendorModel.Description,
+		Phone:           vendorModel.Phone,
+		Address:         vendorModel.Address,
+		Lat:             floatValue(vendorModel.Lat),
+		Lng:             floatValue(vendorModel.Lng),
+		Status:          vendorModel.Status,
+		RejectionReason: vendorModel.RejectionReason,
+		ReviewedAt:      vendorModel.ReviewedAt,
+		CreatedAt:       vendorModel.CreatedAt,
+		UpdatedAt:       vendorModel.UpdatedAt,
+	}
+
+	if vendorModel.ReviewedBy != nil {
+		vendorEntity.ReviewedBy = *vendorModel.ReviewedBy
+	}
+
+	for _, document := range vendorModel.Documents {
+		vendorEntity.Documents = append(vendorEntity.Documents, entity.VendorDocumentEntity{
+			ID:           document.ID,
+			VendorID:     document.VendorID,
+			DocumentType: document.DocumentType,
+			FileURL:      document.FileURL,
+			ObjectName:   document.ObjectName,
+			ContentType:  document.ContentType,
+			CreatedAt:    document.CreatedAt,
+		})
+	}
+
+	return vendorEntity
+}
+
+func NewVendorRepository(db *gorm.DB) port.VendorRepositoryInterface {
+	return &VendorRepository{db: db}
+}
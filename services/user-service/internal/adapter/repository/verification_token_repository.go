@@ -2,11 +2,14 @@ package repository
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 	"user-service/internal/core/domain/entity"
 	"user-service/internal/core/domain/model"
 	"user-service/internal/core/port"
 
+	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 )
 
@@ -20,10 +23,16 @@ func NewVerificationTokenRepository(db *gorm.DB) port.VerificationTokenInterface
 	}
 }
 
+// hashVerificationToken is what is stored and looked up, so a leaked table holds no usable links
+func hashVerificationToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
 func (r *VerificationTokenRepository) CreateVerificationToken(ctx context.Context, token *entity.VerificationTokenEntity) error {
 	model := &model.VerificationToken{
 		UserID:    token.UserID,
-		Token:     token.Token,
+		TokenHash: hashVerificationToken(token.Token),
 		TokenType: token.TokenType,
 		NewEmail:  token.NewEmail,
 		ExpiresAt: token.ExpiresAt,
@@ -39,23 +48,75 @@ func (r *VerificationTokenRepository) CreateVerificationToken(ctx context.Contex
 
 func (r *VerificationTokenRepository) GetVerificationToken(ctx context.Context, token string) (*entity.VerificationTokenEntity, error) {
 	modelToken := &model.VerificationToken{}
-	if err := r.db.WithContext(ctx).Where("token = ? AND expires_at > ?", token, time.Now()).First(modelToken).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("token_hash = ? AND expires_at > ?", hashVerificationToken(token), time.Now()).First(modelToken).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, gorm.ErrRecordNotFound
 		}
 		return nil, err
 	}
+	if modelToken.UsedAt != nil {
+		return nil, port.ErrTokenAlreadyUsed
+	}
 
 	return &entity.VerificationTokenEntity{
 		ID:        modelToken.ID,
 		UserID:    modelToken.UserID,
-		Token:     modelToken.Token,
+		Token:     token,
 		TokenType: modelToken.TokenType,
 		NewEmail:  modelToken.NewEmail,
 		ExpiresAt: modelToken.ExpiresAt,
 	}, nil
 }
 
-func (r *VerificationTokenRepository) DeleteVerificationToken(ctx context.Context, token string) error {
-	return r.db.WithContext(ctx).Where("token = ?", token).Delete(&model.VerificationToken{}).Error
+func (r *VerificationTokenRepository) ClaimVerificationToken(ctx context.Context, token string) error {
+	hash := hashVerificationToken(token)
+	now := time.Now()
+
+	result := r.db.WithContext(ctx).Model(&model.VerificationToken{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", hash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msg("[VerificationTokenRepository-ClaimVerificationToken] Failed to claim token")
+		return result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil
+	}
+
+	// Nothing was claimed: tell a reused link apart from an unknown or expired one
+	var used int64
+	if err := r.db.WithContext(ctx).Model(&model.VerificationToken{}).
+		Where("token_hash = ? AND used_at IS NOT NULL AND expires_at > ?", hash, now).
+		Count(&used).Error; err != nil {
+		return err
+	}
+	if used > 0 {
+		return port.ErrTokenAlreadyUsed
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *VerificationTokenRepository) ReleaseVerificationToken(ctx context.Context, token string) error {
+	return r.db.WithContext(ctx).Model(&model.VerificationToken{}).
+		Where("token_hash = ?", hashVerificationToken(token)).
+		Update("used_at", nil).Error
+}
+
+// DeleteExpiredVerificationTokens removes tokens that can no longer be used or reported as used
+func (r *VerificationTokenRepository) DeleteExpiredVerificationTokens(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at <= ?", time.Now()).Delete(&model.VerificationToken{})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msg("[VerificationTokenRepository-DeleteExpiredVerificationTokens] Failed to delete expired tokens")
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *VerificationTokenRepository) DeleteUserTokens(ctx context.Context, userID int64, tokenType string) (int64, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND token_type = ? AND used_at IS NULL", userID, tokenType).Delete(&model.VerificationToken{})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("user_id", userID).Str("token_type", tokenType).Msg("[VerificationTokenRepository-DeleteUserTokens] Failed to delete tokens")
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
 }
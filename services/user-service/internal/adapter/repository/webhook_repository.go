@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxWebhookResponseBody keeps the delivery log readable when an endpoint answers with a whole page
+const maxWebhookResponseBody = 2048
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func (r *WebhookRepository) CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpointEntity) (*entity.WebhookEndpointEntity, error) {
+	eventTypes, err := json.Marshal(endpoint.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	endpointModel := &model.WebhookEndpoint{
+		URL:         endpoint.URL,
+		Secret:      endpoint.Secret,
+		EventTypes:  string(eventTypes),
+		Description: endpoint.Description,
+		IsActive:    endpoint.IsActive,
+	}
+	if endpoint.CreatedBy > 0 {
+		endpointModel.CreatedBy = &endpoint.CreatedBy
+	}
+	if endpoint.APIKeyID > 0 {
+		endpointModel.APIKeyID = &endpoint.APIKeyID
+	}
+
+	if err := r.db.WithContext(ctx).Create(endpointModel).Error; err != nil {
+		log.Error().Err(err).Str("url", endpoint.URL).Msg("[WebhookRepository-CreateEndpoint] Failed to create webhook endpoint")
+		return nil, err
+	}
+
+	return r.toEndpointEntity(endpointModel), nil
+}
+
+func (r *WebhookRepository) GetEndpoints(ctx context.Context) ([]entity.WebhookEndpointEntity, error) {
+	var endpoints []model.WebhookEndpoint
+	if err := r.db.WithContext(ctx).Order("id ASC").Find(&endpoints).Error; err != nil {
+		log.Error().Err(err).Msg("[WebhookRepository-GetEndpoints] Failed to get webhook endpoints")
+		return nil, err
+	}
+
+	return r.toEndpointEntities(endpoints), nil
+}
+
+func (r *WebhookRepository) GetEndpointByID(ctx context.Context, id int64) (*entity.WebhookEndpointEntity, error) {
+	var endpointModel model.WebhookEndpoint
+	if err := r.db.WithContext(ctx).First(&endpointModel, id).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Int64("endpoint_id", id).Msg("[WebhookRepository-GetEndpointByID] Failed to get webhook endpoint")
+		}
+		return nil, err
+	}
+
+	return r.toEndpointEntity(&endpointModel), nil
+}
+
+func (r *WebhookRepository) UpdateEndpoint(ctx context.Context, id int64, endpoint *entity.WebhookEndpointEntity) (*entity.WebhookEndpointEntity, error) {
+	eventTypes, err := json.Marshal(endpoint.EventTypes)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"url":         endpoint.URL,
+		"event_types": string(eventTypes),
+		"description": endpoint.Description,
+		"is_active":   endpoint.IsActive,
+		"updated_at":  time.Now(),
+	}
+	// An empty secret keeps the current one so admins can edit without re-sharing it
+	if endpoint.Secret != "" {
+		updates["secret"] = endpoint.Secret
+	}
+
+	result := r.db.WithContext(ctx).Model(&model.WebhookEndpoint{}).Where("id = ?", id).Updates(updates)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("endpoint_id", id).Msg("[WebhookRepository-UpdateEndpoint] Failed to update webhook endpoint")
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return r.GetEndpointByID(ctx, id)
+}
+
+func (r *WebhookRepository) DeleteEndpoint(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Delete(&model.WebhookEndpoint{}, id)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("endpoint_id", id).Msg("[WebhookRepository-DeleteEndpoint] Failed to delete webhook endpoint")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) GetActiveEndpointsForEvent(ctx context.Context, eventType string) ([]entity.WebhookEndpointEntity, error) {
+	subscription, err := json.Marshal([]string{eventType})
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []model.WebhookEndpoint
+	if err := r.db.WithContext(ctx).
+		Where("is_active = ? AND event_types @> ?::jsonb", true, string(subscription)).
+		Order("id ASC").
+		Find(&endpoints).Error; err != nil {
+		log.Error().Err(err).Str("event", eventType).Msg("[WebhookRepository-GetActiveEndpointsForEvent] Failed to get webhook endpoints")
+		return nil, err
+	}
+
+	return r.toEndpointEntities(endpoints), nil
+}
+
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *entity.WebhookDeliveryEntity) (*entity.WebhookDeliveryEntity, error) {
+	deliveryModel := &model.WebhookDelivery{
+		EndpointID: delivery.EndpointID,
+		EventID:    delivery.EventID,
+		EventType:  delivery.EventType,
+		Payload:    string(delivery.Payload),
+		Status:     entity.WebhookDeliveryPending,
+	}
+
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(deliveryModel)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("endpoint_id", delivery.EndpointID).Str("event_id", delivery.EventID).Msg("[WebhookRepository-CreateDelivery] Failed to create webhook delivery")
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, errors.New("webhook delivery already exists")
+	}
+
+	return r.toDeliveryEntity(deliveryModel), nil
+}
+
+func (r *WebhookRepository) GetDeliveryByID(ctx context.Context, id int64) (*entity.WebhookDeliveryEntity, error) {
+	var deliveryModel model.WebhookDelivery
+	if err := r.db.WithContext(ctx).First(&deliveryModel, id).Error; err != nil {
+		if err != gorm.ErrRecordNotFound {
+			log.Error().Err(err).Int64("delivery_id", id).Msg("[WebhookRepository-GetDeliveryByID] Failed to get webhook delivery")
+		}
+		return nil, err
+	}
+
+	return r.toDeliveryEntity(&deliveryModel), nil
+}
+
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, id int64, attempt *entity.WebhookAttemptEntity) error {
+	responseBody := attempt.ResponseBody
+	if len(responseBody) > maxWebhookResponseBody {
+		responseBody = responseBody[:maxWebhookResponseBody]
+	}
+
+	updates := map[string]interface{}{
+		"status":        attempt.Status,
+		"attempts":      gorm.Expr("attempts + 1"),
+		"response_body": responseBody,
+		"last_error":    attempt.Error,
+		"duration_ms":   attempt.DurationMs,
+		"updated_at":    time.Now(),
+	}
+	if attempt.ResponseStatus > 0 {
+		updates["response_status"] = attempt.ResponseStatus
+	} else {
+		updates["response_status"] = nil
+	}
+	if attempt.Status == entity.WebhookDeliverySucceeded {
+		updates["delivered_at"] = time.Now()
+	}
+
+	if err := r.db.WithContext(ctx).Model(&model.WebhookDelivery{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		log.Error().Err(err).Int64("delivery_id", id).Msg("[WebhookRepository-RecordAttempt] Failed to record webhook attempt")
+		return err
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) ResetDelivery(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Model(&model.WebhookDelivery{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"status":     entity.WebhookDeliveryPending,
+		"updated_at": time.Now(),
+	})
+	if result.Error != nil {
+		log.Error().Err(result.Error).Int64("delivery_id", id).Msg("[WebhookRepository-ResetDelivery] Failed to reset webhook delivery")
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, endpointID int64, status string, page, limit int) ([]entity.WebhookDeliveryEntity, int64, error) {
+	var deliveries []model.WebhookDelivery
+	var totalCount int64
+
+	query := r.db.WithContext(ctx).Model(&model.WebhookDelivery{}).Where("endpoint_id = ?", endpointID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&totalCount).Error; err != nil {
+		log.Error().Err(err).Int64("endpoint_id", endpointID).Msg("[WebhookRepository-GetDeliveries] Failed to count webhook deliveries")
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.Order("id DESC").Offset(offset).Limit(limit).Find(&deliveries).Error; err != nil {
+		log.Error().Err(err).Int64("endpoint_id", endpointID).Msg("[WebhookRepository-GetDeliveries] Failed to get webhook deliveries")
+		return nil, 0, err
+	}
+
+	deliveryEntities := make([]entity.WebhookDeliveryEntity, 0, len(deliveries))
+	for i := range deliveries {
+		deliveryEntities = append(deliveryEntities, *r.toDeliveryEntity(&deliveries[i]))
+	}
+
+	return deliveryEntities, totalCount, nil
+}
+
+func (r *WebhookRepository) toEndpointEntities(endpoints []model.WebhookEndpoint) []entity.WebhookEndpointEntity {
+	endpointEntities := make([]entity.WebhookEndpointEntity, 0, len(endpoints))
+	for i := range endpoints {
+		endpointEntities = append(endpointEntities, *r.toEndpointEntity(&endpoints[i]))
+	}
+	return endpointEntities
+}
+
+func (r *WebhookRepository) toEndpointEntity(endpointModel *model.WebhookEndpoint) *entity.WebhookEndpointEntity {
+	endpoint := &entity.WebhookEndpointEntity{
+		ID:          endpointModel.ID,
+		URL:         endpointModel.URL,
+		Secret:      endpointModel.Secret,
+		EventTypes:  []string{},
+		Description: endpointModel.Description,
+		IsActive:    endpointModel.IsActive,
+		CreatedAt:   endpointModel.CreatedAt,
+		UpdatedAt:   endpointModel.UpdatedAt,
+	}
+	if endpointModel.CreatedBy != nil {
+		endpoint.CreatedBy = *endpointModel.CreatedBy
+	}
+	if endpointModel.APIKeyID != nil {
+		endpoint.APIKeyID = *endpointModel.APIKeyID
+	}
+	if err := json.Unmarshal([]byte(endpointModel.EventTypes), &endpoint.EventTypes); err != nil {
+		log.Warn().Err(err).Int64("endpoint_id", endpointModel.ID).Msg("[WebhookRepository-toEndpointEntity] Invalid event types")
+	}
+
+	return endpoint
+}
+
+func (r *WebhookRepository) toDeliveryEntity(deliveryModel *model.WebhookDelivery) *entity.WebhookDeliveryEntity {
+	delivery := &entity.WebhookDeliveryEntity{
+		ID:           deliveryModel.ID,
+		EndpointID:   deliveryModel.EndpointID,
+		EventID:      deliveryModel.EventID,
+		EventType:    deliveryModel.EventType,
+		Payload:      json.RawMessage(deliveryModel.Payload),
+		Status:       deliveryModel.Status,
+		Attempts:     deliveryModel.Attempts,
+		ResponseBody: deliveryModel.ResponseBody,
+		LastError:    deliveryModel.LastError,
+		DeliveredAt:  deliveryModel.DeliveredAt,
+		CreatedAt:    deliveryModel.CreatedAt,
+		UpdatedAt:    deliveryModel.UpdatedAt,
+	}
+	if deliveryModel.ResponseStatus != nil {
+		delivery.ResponseStatus = *deliveryModel.ResponseStatus
+	}
+	if deliveryModel.DurationMs != nil {
+		delivery.DurationMs = *deliveryModel.DurationMs
+	}
+
+	return delivery
+}
+
+func NewWebhookRepository(db *gorm.DB) port.WebhookRepositoryInterface {
+	return &WebhookRepository{db: db}
+}
@@ -0,0 +1,107 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+)
+
+// clamdChunkSize is how much of the file goes into one INSTREAM frame
+const clamdChunkSize = 64 << 10
+
+// ClamAVScanner talks to a clamd daemon with the INSTREAM command, so files are streamed
+// over the socket and never have to be visible on the daemon's filesystem
+type ClamAVScanner struct {
+	network string
+	address string
+	timeout time.Duration
+	dialer  net.Dialer
+}
+
+func (s *ClamAVScanner) Scan(ctx context.Context, file io.Reader) (*entity.ScanResultEntity, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	conn, err := s.dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return nil, fmt.Errorf("connect to clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+
+	// The "z" prefix selects null-terminated commands and replies
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return nil, fmt.Errorf("send INSTREAM: %w", err)
+	}
+
+	frame := make([]byte, 4+clamdChunkSize)
+	for {
+		n, readErr := file.Read(frame[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(frame[:4], uint32(n))
+			if _, err := conn.Write(frame[:4+n]); err != nil {
+				// clamd closes the stream early when StreamMaxLength is exceeded; its reply says why
+				break
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("read file: %w", readErr)
+		}
+	}
+
+	// A zero-length frame ends the stream
+	_, _ = conn.Write([]byte{0, 0, 0, 0})
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !(errors.Is(err, io.EOF) && reply != "") {
+		return nil, fmt.Errorf("read clamd reply: %w", err)
+	}
+
+	return parseClamdReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamdReply reads "stream: OK", "stream: <signature> FOUND" or "<reason> ERROR"
+func parseClamdReply(reply string) (*entity.ScanResultEntity, error) {
+	result := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+
+	switch {
+	case result == "OK":
+		return &entity.ScanResultEntity{}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return &entity.ScanResultEntity{Infected: true, Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	default:
+		return nil, fmt.Errorf("clamd: %s", result)
+	}
+}
+
+// NewClamAVScanner accepts "host:port", "tcp://host:port" or "unix:///path/to/clamd.sock"
+func NewClamAVScanner(address string, timeout time.Duration) port.FileScannerInterface {
+	network := "tcp"
+	switch {
+	case strings.HasPrefix(address, "unix://"):
+		network, address = "unix", strings.TrimPrefix(address, "unix://")
+	case strings.HasPrefix(address, "tcp://"):
+		address = strings.TrimPrefix(address, "tcp://")
+	}
+
+	return &ClamAVScanner{
+		network: network,
+		address: address,
+		timeout: timeout,
+	}
+}
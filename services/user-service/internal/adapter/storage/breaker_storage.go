@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+	"user-service/internal/adapter/breaker"
+	"user-service/internal/core/port"
+)
+
+// BreakerStorage short-circuits storage calls while the backing service keeps failing
+type BreakerStorage struct {
+	storage port.StorageInterface
+	breaker *breaker.Breaker
+}
+
+func NewBreakerStorage(storage port.StorageInterface, b *breaker.Breaker) port.StorageInterface {
+	return &BreakerStorage{storage: storage, breaker: b}
+}
+
+func (s *BreakerStorage) UploadFile(ctx context.Context, bucketName, objectName string, file io.Reader, contentType string) (string, error) {
+	var url string
+	var tooLarge error
+	err := s.breaker.Execute(func() error {
+		var err error
+		url, err = s.storage.UploadFile(ctx, bucketName, objectName, file, contentType)
+		// An oversized file is the caller's fault, not the storage's
+		if errors.Is(err, port.ErrFileTooLarge) {
+			tooLarge = err
+			return nil
+		}
+		return err
+	})
+	if tooLarge != nil {
+		return "", tooLarge
+	}
+	return url, err
+}
+
+func (s *BreakerStorage) DeleteFile(ctx context.Context, bucketName, objectName string) error {
+	return s.breaker.Execute(func() error {
+		return s.storage.DeleteFile(ctx, bucketName, objectName)
+	})
+}
+
+func (s *BreakerStorage) SignedURL(ctx context.Context, bucketName, objectName string, expiresIn time.Duration) (string, error) {
+	signer, ok := s.storage.(port.SignedURLInterface)
+	if !ok {
+		return "", port.ErrSignedURLUnsupported
+	}
+
+	var url string
+	err := s.breaker.Execute(func() error {
+		var err error
+		url, err = signer.SignedURL(ctx, bucketName, objectName, expiresIn)
+		return err
+	})
+	return url, err
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+	"user-service/internal/adapter/cdn"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+// CDNPurgingStorage purges an object's CDN URL once it is deleted, e.g. a replaced or rejected
+// profile photo. Objects are named by content, so a changed file gets a new URL and only
+// deletions leave stale copies behind.
+type CDNPurgingStorage struct {
+	storage       port.StorageInterface
+	rewriter      *cdn.Rewriter
+	purger        port.CDNPurgerInterface
+	defaultBucket string
+}
+
+func NewCDNPurgingStorage(storage port.StorageInterface, rewriter *cdn.Rewriter, purger port.CDNPurgerInterface, defaultBucket string) port.StorageInterface {
+	return &CDNPurgingStorage{
+		storage:       storage,
+		rewriter:      rewriter,
+		purger:        purger,
+		defaultBucket: defaultBucket,
+	}
+}
+
+func (s *CDNPurgingStorage) UploadFile(ctx context.Context, bucketName, objectName string, file io.Reader, contentType string) (string, error) {
+	return s.storage.UploadFile(ctx, bucketName, objectName, file, contentType)
+}
+
+func (s *CDNPurgingStorage) DeleteFile(ctx context.Context, bucketName, objectName string) error {
+	if err := s.storage.DeleteFile(ctx, bucketName, objectName); err != nil {
+		return err
+	}
+
+	bucket := bucketName
+	if bucket == "" {
+		bucket = s.defaultBucket
+	}
+	url := s.rewriter.ObjectURL(bucket, objectName)
+	if url == "" {
+		return nil
+	}
+
+	// The object is gone either way; without a purge the CDN serves it until its cache expires
+	if err := s.purger.Purge(ctx, []string{url}); err != nil {
+		log.Error().Err(err).Str("url", url).Msg("[CDNPurgingStorage-DeleteFile] Failed to purge CDN cache")
+	}
+	return nil
+}
+
+func (s *CDNPurgingStorage) SignedURL(ctx context.Context, bucketName, objectName string, expiresIn time.Duration) (string, error) {
+	signer, ok := s.storage.(port.SignedURLInterface)
+	if !ok {
+		return "", port.ErrSignedURLUnsupported
+	}
+	return signer.SignedURL(ctx, bucketName, objectName, expiresIn)
+}
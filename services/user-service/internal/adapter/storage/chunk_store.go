@@ -0,0 +1,277 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	sessionFile = "session.json"
+	chunkSuffix = ".part"
+	// orphanGrace keeps a directory without a readable session around long enough
+	// that a session being created right now is never mistaken for an orphan
+	orphanGrace = time.Hour
+)
+
+var uploadIDPattern = regexp.MustCompile(`^[a-f0-9]{32}$`)
+
+// LocalChunkStore keeps resumable uploads on local disk, one directory per upload.
+// With several replicas the directory has to be a shared volume.
+type LocalChunkStore struct {
+	dir string
+}
+
+func (s *LocalChunkStore) CreateSession(ctx context.Context, session *entity.UploadSessionEntity) error {
+	uploadDir, err := s.uploadDir(session.ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(uploadDir, 0o700); err != nil {
+		log.Error().Err(err).Str("upload_id", session.ID).Msg("[LocalChunkStore-CreateSession] Failed to create upload directory")
+		return err
+	}
+
+	if err := writeFileAtomic(uploadDir, sessionFile, bytes.NewReader(data), -1); err != nil {
+		log.Error().Err(err).Str("upload_id", session.ID).Msg("[LocalChunkStore-CreateSession] Failed to write session")
+		return err
+	}
+	return nil
+}
+
+func (s *LocalChunkStore) GetSession(ctx context.Context, uploadID string) (*entity.UploadSessionEntity, error) {
+	uploadDir, err := s.uploadDir(uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(uploadDir, sessionFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errors.New("upload not found")
+	}
+	if err != nil {
+		log.Error().Err(err).Str("upload_id", uploadID).Msg("[LocalChunkStore-GetSession] Failed to read session")
+		return nil, err
+	}
+
+	session := &entity.UploadSessionEntity{}
+	if err := json.Unmarshal(data, session); err != nil {
+		log.Error().Err(err).Str("upload_id", uploadID).Msg("[LocalChunkStore-GetSession] Failed to decode session")
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(uploadDir)
+	if err != nil {
+		return nil, err
+	}
+	session.ReceivedChunks = []int{}
+	for _, entry := range entries {
+		index, err := strconv.Atoi(strings.TrimSuffix(entry.Name(), chunkSuffix))
+		if err != nil || !strings.HasSuffix(entry.Name(), chunkSuffix) {
+			continue
+		}
+		session.ReceivedChunks = append(session.ReceivedChunks, index)
+	}
+	sort.Ints(session.ReceivedChunks)
+
+	return session, nil
+}
+
+func (s *LocalChunkStore) WriteChunk(ctx context.Context, uploadID string, index int, data io.Reader, maxBytes int64) (int64, error) {
+	uploadDir, err := s.uploadDir(uploadID)
+	if err != nil {
+		return 0, err
+	}
+
+	counter := &countingReader{reader: io.LimitReader(data, maxBytes+1)}
+	if err := writeFileAtomic(uploadDir, chunkName(index), counter, maxBytes); err != nil {
+		if errors.Is(err, errChunkTooLarge) {
+			return counter.n, nil
+		}
+		log.Error().Err(err).Str("upload_id", uploadID).Int("index", index).Msg("[LocalChunkStore-WriteChunk] Failed to write chunk")
+		return counter.n, err
+	}
+	return counter.n, nil
+}
+
+func (s *LocalChunkStore) DeleteChunk(ctx context.Context, uploadID string, index int) error {
+	uploadDir, err := s.uploadDir(uploadID)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(uploadDir, chunkName(index))); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalChunkStore) OpenAssembled(ctx context.Context, uploadID string, totalChunks int) (io.ReadCloser, error) {
+	uploadDir, err := s.uploadDir(uploadID)
+	if err != nil {
+		return nil, err
+	}
+	return &assembledReader{dir: uploadDir, total: totalChunks}, nil
+}
+
+func (s *LocalChunkStore) DeleteSession(ctx context.Context, uploadID string) error {
+	uploadDir, err := s.uploadDir(uploadID)
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(uploadDir); err != nil {
+		log.Error().Err(err).Str("upload_id", uploadID).Msg("[LocalChunkStore-DeleteSession] Failed to delete upload")
+		return err
+	}
+	return nil
+}
+
+func (s *LocalChunkStore) ListExpired(ctx context.Context, before time.Time) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var expired []string
+	for _, entry := range entries {
+		if !entry.IsDir() || !uploadIDPattern.MatchString(entry.Name()) {
+			continue
+		}
+
+		session := entity.UploadSessionEntity{}
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name(), sessionFile))
+		if err == nil {
+			err = json.Unmarshal(data, &session)
+		}
+		if err == nil {
+			if session.ExpiresAt.Before(before) {
+				expired = append(expired, entry.Name())
+			}
+			continue
+		}
+
+		// No readable session: an interrupted create, removed once it is clearly stale
+		if info, infoErr := entry.Info(); infoErr == nil && info.ModTime().Before(before.Add(-orphanGrace)) {
+			expired = append(expired, entry.Name())
+		}
+	}
+	return expired, nil
+}
+
+// uploadDir also keeps client-supplied IDs from escaping the upload directory
+func (s *LocalChunkStore) uploadDir(uploadID string) (string, error) {
+	if !uploadIDPattern.MatchString(uploadID) {
+		return "", errors.New("upload not found")
+	}
+	return filepath.Join(s.dir, uploadID), nil
+}
+
+func chunkName(index int) string {
+	return fmt.Sprintf("%06d%s", index, chunkSuffix)
+}
+
+var errChunkTooLarge = errors.New("chunk too large")
+
+// writeFileAtomic writes to a temp file and renames it into place, so readers never see a
+// partial file. maxBytes >= 0 discards the file when more than maxBytes were read.
+func writeFileAtomic(dir, name string, data io.Reader, maxBytes int64) error {
+	tmp, err := os.CreateTemp(dir, name+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if maxBytes >= 0 && written > maxBytes {
+		return errChunkTooLarge
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
+
+type countingReader struct {
+	reader io.Reader
+	n      int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// assembledReader opens one chunk at a time, so assembling never holds more than one file open
+type assembledReader struct {
+	dir     string
+	total   int
+	next    int
+	current *os.File
+}
+
+func (r *assembledReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if r.next >= r.total {
+				return 0, io.EOF
+			}
+			file, err := os.Open(filepath.Join(r.dir, chunkName(r.next)))
+			if err != nil {
+				return 0, err
+			}
+			r.current = file
+			r.next++
+		}
+
+		n, err := r.current.Read(p)
+		if err == io.EOF {
+			r.current.Close()
+			r.current = nil
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *assembledReader) Close() error {
+	if r.current == nil {
+		return nil
+	}
+	err := r.current.Close()
+	r.current = nil
+	return err
+}
+
+func NewLocalChunkStore(dir string) port.ChunkStoreInterface {
+	return &LocalChunkStore{dir: dir}
+}
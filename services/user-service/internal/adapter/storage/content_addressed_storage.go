@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// ContentAddressedStorage stores each object once per name and counts its references. Callers
+// name objects by the SHA-256 of their content (see utils.ContentObjectName), so uploading the
+// same file again, e.g. a retried request, reuses the stored object instead of failing on the
+// duplicate name, and deleting it only removes the object when the last reference goes.
+// Uploads without a name are named here as profile-<sha256><ext>.
+type ContentAddressedStorage struct {
+	storage port.StorageInterface
+	objects port.StoredObjectRepositoryInterface
+	// defaultBucket is recorded for objects uploaded to bucket "", as storage webhooks name it
+	defaultBucket string
+}
+
+func NewContentAddressedStorage(storage port.StorageInterface, objects port.StoredObjectRepositoryInterface, defaultBucket string) port.StorageInterface {
+	return &ContentAddressedStorage{storage: storage, objects: objects, defaultBucket: defaultBucket}
+}
+
+func (s *ContentAddressedStorage) UploadFile(ctx context.Context, bucketName, objectName string, file io.Reader, contentType string) (string, error) {
+	if objectName == "" {
+		// The name depends on the whole content, so spool it to disk rather than memory first
+		spool, sum, err := spoolWithHash(file)
+		if err != nil {
+			log.Error().Err(err).Msg("[ContentAddressedStorage-UploadFile] Failed to spool file")
+			return "", fmt.Errorf("failed to read file content: %w", err)
+		}
+		defer os.Remove(spool.Name())
+		defer spool.Close()
+
+		file = spool
+		objectName = profileObjectName(sum, contentType)
+	}
+
+	bucket := s.bucket(bucketName)
+	if url, ok := s.reuse(ctx, bucket, objectName); ok {
+		return url, nil
+	}
+
+	content := &hashingReader{reader: file, hash: sha256.New()}
+	url, err := s.storage.UploadFile(ctx, bucketName, objectName, content, contentType)
+	if err != nil {
+		// A concurrent upload of the same content may have stored it first
+		if url, ok := s.reuse(ctx, bucket, objectName); ok {
+			return url, nil
+		}
+		return "", err
+	}
+
+	_, err = s.objects.AddReference(ctx, &entity.StoredObjectEntity{
+		Bucket:      bucket,
+		ObjectName:  objectName,
+		SHA256:      hex.EncodeToString(content.hash.Sum(nil)),
+		Size:        content.size,
+		ContentType: contentType,
+		URL:         url,
+	})
+	if err != nil {
+		// The upload itself succeeded; without a record the object is deleted on first release
+		log.Error().Err(err).Str("object_name", objectName).Msg("[ContentAddressedStorage-UploadFile] Failed to record stored object")
+	}
+
+	return url, nil
+}
+
+func (s *ContentAddressedStorage) DeleteFile(ctx context.Context, bucketName, objectName string) error {
+	bucket := s.bucket(bucketName)
+	remaining, err := s.objects.RemoveReference(ctx, bucket, objectName)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// Stored before objects were recorded, nothing else can reference it
+		return s.storage.DeleteFile(ctx, bucketName, objectName)
+	case err != nil:
+		return err
+	case remaining > 0:
+		log.Info().Str("object_name", objectName).Int("references", remaining).Msg("[ContentAddressedStorage-DeleteFile] Object still referenced, kept")
+		return nil
+	}
+
+	// Drop the record first, so an upload reusing the object in the meantime keeps it alive
+	deleted, err := s.objects.DeleteUnreferenced(ctx, bucket, objectName)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return nil
+	}
+
+	return s.storage.DeleteFile(ctx, bucketName, objectName)
+}
+
+// SignedURL only reads, so references are not counted
+func (s *ContentAddressedStorage) SignedURL(ctx context.Context, bucketName, objectName string, expiresIn time.Duration) (string, error) {
+	signer, ok := s.storage.(port.SignedURLInterface)
+	if !ok {
+		return "", port.ErrSignedURLUnsupported
+	}
+	return signer.SignedURL(ctx, bucketName, objectName, expiresIn)
+}
+
+func (s *ContentAddressedStorage) bucket(bucketName string) string {
+	if bucketName == "" {
+		return s.defaultBucket
+	}
+	return bucketName
+}
+
+// reuse counts one more reference to an object that is already stored and returns its URL
+func (s *ContentAddressedStorage) reuse(ctx context.Context, bucket, objectName string) (string, bool) {
+	existing, err := s.objects.GetObject(ctx, bucket, objectName)
+	if err != nil {
+		return "", false
+	}
+
+	if _, err := s.objects.AddReference(ctx, existing); err != nil {
+		return "", false
+	}
+
+	log.Info().Str("object_name", objectName).Msg("[ContentAddressedStorage-UploadFile] Identical object already stored, reused")
+	return existing.URL, true
+}
+
+// spoolWithHash copies file to a temporary file, rewound for reading, and returns the hex
+// SHA-256 of its content
+func spoolWithHash(file io.Reader) (*os.File, string, error) {
+	spool, err := os.CreateTemp("", "jualan-sayur-object-*")
+	if err != nil {
+		return nil, "", err
+	}
+
+	sum := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(spool, sum), file); err != nil {
+		spool.Close()
+		os.Remove(spool.Name())
+		return nil, "", err
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		spool.Close()
+		os.Remove(spool.Name())
+		return nil, "", err
+	}
+
+	return spool, hex.EncodeToString(sum.Sum(nil)), nil
+}
+
+// hashingReader hashes and counts what storage reads, so uploads stay streamed
+type hashingReader struct {
+	reader io.Reader
+	hash   hash.Hash
+	size   int64
+}
+
+func (r *hashingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.hash.Write(p[:n])
+	r.size += int64(n)
+	return n, err
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// MaxImageSize bounds profile photos
+const MaxImageSize = 5 << 20
+
+var (
+	ErrImageEmpty    = errors.New("file is empty")
+	ErrImageTooLarge = errors.New("file size too large, maximum 5MB")
+	ErrImageType     = errors.New("invalid file type, only JPEG, PNG, GIF, and WebP are allowed")
+)
+
+// imageTypes maps each allowed extension to the type its content must sniff as
+var imageTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+}
+
+// ValidateImageFile checks the size, the extension and the first 512 bytes of the content,
+// and returns the sniffed content type to store the file with. The Content-Type the client
+// declared is not trusted. The file is rewound so it can be uploaded afterwards.
+func ValidateImageFile(file multipart.File, header *multipart.FileHeader) (string, error) {
+	if header.Size == 0 {
+		return "", ErrImageEmpty
+	}
+	if header.Size > MaxImageSize {
+		return "", ErrImageTooLarge
+	}
+
+	expected, ok := imageTypes[strings.ToLower(filepath.Ext(header.Filename))]
+	if !ok {
+		return "", ErrImageType
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		if errors.Is(err, io.EOF) {
+			return "", ErrImageEmpty
+		}
+		return "", err
+	}
+	// An HTML or script file renamed to .png sniffs as text and is rejected here
+	contentType := http.DetectContentType(head[:n])
+	if contentType != expected {
+		return "", ErrImageType
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return contentType, nil
+}
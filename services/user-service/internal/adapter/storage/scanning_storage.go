@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ScanningStorage scans every upload before handing it to the wrapped storage. In log-only
+// mode infected files and scanner failures are recorded but the upload still goes through,
+// which allows trying a scanner in production before it starts rejecting files.
+type ScanningStorage struct {
+	storage         port.StorageInterface
+	scanner         port.FileScannerInterface
+	auditLogService port.AuditLogServiceInterface
+	logOnly         bool
+}
+
+func NewScanningStorage(storage port.StorageInterface, scanner port.FileScannerInterface, auditLogService port.AuditLogServiceInterface, logOnly bool) port.StorageInterface {
+	return &ScanningStorage{
+		storage:         storage,
+		scanner:         scanner,
+		auditLogService: auditLogService,
+		logOnly:         logOnly,
+	}
+}
+
+func (s *ScanningStorage) UploadFile(ctx context.Context, bucketName, objectName string, file io.Reader, contentType string) (string, error) {
+	// The file is read once for the scan and once for the upload; storage buffers it anyway
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := s.scanner.Scan(ctx, bytes.NewReader(content))
+	if err != nil {
+		log.Error().Err(err).Str("object_name", objectName).Bool("log_only", s.logOnly).Msg("[ScanningStorage-UploadFile] Failed to scan file")
+		if !s.logOnly {
+			return "", port.ErrScannerUnavailable
+		}
+	}
+
+	if result != nil && result.Infected {
+		s.recordInfected(ctx, objectName, contentType, len(content), result.Signature)
+		if !s.logOnly {
+			return "", port.ErrFileInfected
+		}
+	}
+
+	return s.storage.UploadFile(ctx, bucketName, objectName, bytes.NewReader(content), contentType)
+}
+
+func (s *ScanningStorage) DeleteFile(ctx context.Context, bucketName, objectName string) error {
+	return s.storage.DeleteFile(ctx, bucketName, objectName)
+}
+
+// SignedURL only reads, so there is nothing to scan
+func (s *ScanningStorage) SignedURL(ctx context.Context, bucketName, objectName string, expiresIn time.Duration) (string, error) {
+	signer, ok := s.storage.(port.SignedURLInterface)
+	if !ok {
+		return "", port.ErrSignedURLUnsupported
+	}
+	return signer.SignedURL(ctx, bucketName, objectName, expiresIn)
+}
+
+func (s *ScanningStorage) recordInfected(ctx context.Context, objectName, contentType string, size int, signature string) {
+	action := "rejected"
+	if s.logOnly {
+		action = "stored"
+	}
+
+	var userID int64
+	if principal, ok := entity.PrincipalFromContext(ctx); ok {
+		userID = principal.UserID
+	}
+
+	log.Warn().Int64("user_id", userID).Str("object_name", objectName).Str("signature", signature).Str("action", action).Msg("[ScanningStorage-UploadFile] Infected file detected")
+
+	if s.auditLogService == nil {
+		return
+	}
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{
+		UserID: userID,
+		Event:  entity.AuditEventUploadInfected,
+		Metadata: map[string]interface{}{
+			"object_name":  objectName,
+			"content_type": contentType,
+			"size":         size,
+			"signature":    signature,
+			"action":       action,
+		},
+	})
+}
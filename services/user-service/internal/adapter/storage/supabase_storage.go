@@ -1,14 +1,18 @@
 package storage
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
-	"path/filepath"
 	"strings"
+	"time"
+	"user-service/internal/adapter/httpclient"
 	"user-service/internal/core/port"
 
 	"github.com/google/uuid"
@@ -16,26 +20,34 @@ import (
 )
 
 type SupabaseStorage struct {
-	projectURL    string
-	apiKey        string
-	bucketName    string
-	httpClient    *http.Client
+	projectURL string
+	apiKey     string
+	bucketName string
+	httpClient *http.Client
+	// timeout bounds each upload/delete, on top of the caller's deadline
+	timeout time.Duration
+	// maxSize caps the bytes streamed per upload
+	maxSize int64
 }
 
 type SupabaseUploadResponse struct {
 	Key string `json:"Key"`
 }
 
-func NewSupabaseStorage(projectURL, apiKey, bucketName string) (port.StorageInterface, error) {
+// NewSupabaseStorage calls the storage API with httpClient, normally an httpclient.New client.
+// Uploads are streamed and fail with port.ErrFileTooLarge past maxSize bytes.
+func NewSupabaseStorage(projectURL, apiKey, bucketName string, timeout time.Duration, maxSize int64, httpClient *http.Client) (port.StorageInterface, error) {
 	if projectURL == "" || apiKey == "" || bucketName == "" {
 		return nil, fmt.Errorf("supabase project URL, API key, and bucket name are required")
 	}
 
 	return &SupabaseStorage{
-		projectURL:    strings.TrimSuffix(projectURL, "/"),
-		apiKey:        apiKey,
-		bucketName:    bucketName,
-		httpClient:    &http.Client{},
+		projectURL: strings.TrimSuffix(projectURL, "/"),
+		apiKey:     apiKey,
+		bucketName: bucketName,
+		httpClient: httpClient,
+		timeout:    timeout,
+		maxSize:    maxSize,
 	}, nil
 }
 
@@ -47,60 +59,42 @@ func (s *SupabaseStorage) UploadFile(ctx context.Context, bucketName, objectName
 
 	// Generate unique filename if not provided
 	if objectName == "" {
-		ext := ".jpg" // default extension
-		if contentType != "" {
-			switch contentType {
-			case "image/jpeg":
-				ext = ".jpg"
-			case "image/png":
-				ext = ".png"
-			case "image/gif":
-				ext = ".gif"
-			case "image/webp":
-				ext = ".webp"
-			}
-		}
-		objectName = fmt.Sprintf("profile-%s%s", uuid.New().String(), ext)
+		objectName = profileObjectName(uuid.New().String(), contentType)
 	}
 
-	// Read file content
-	log.Info().Str("content_type", contentType).Msg("[SupabaseStorage-UploadFile] Starting to read file content")
-	fileContent, err := io.ReadAll(file)
-	if err != nil {
+	// Peek one byte so an empty file is still refused before the request is sent
+	log.Info().Str("content_type", contentType).Msg("[SupabaseStorage-UploadFile] Starting to stream file content")
+	content := bufio.NewReader(file)
+	if _, err := content.Peek(1); err != nil {
+		if err == io.EOF {
+			log.Error().Msg("[SupabaseStorage-UploadFile] File content is empty")
+			return "", fmt.Errorf("file content is empty")
+		}
 		log.Error().Err(err).Msg("[SupabaseStorage-UploadFile] Failed to read file content")
 		return "", fmt.Errorf("failed to read file content: %w", err)
 	}
 
-	log.Info().Int("content_length", len(fileContent)).Msg("[SupabaseStorage-UploadFile] File content read successfully")
-
-	// Check if file content is empty
-	if len(fileContent) == 0 {
-		log.Error().Msg("[SupabaseStorage-UploadFile] File content is empty after reading")
-		return "", fmt.Errorf("file content is empty")
-	}
-
-	// Create multipart form data
-	var b bytes.Buffer
-	w := multipart.NewWriter(&b)
-
-	// Add file field
-	fw, err := w.CreateFormFile("file", objectName)
-	if err != nil {
-		return "", fmt.Errorf("failed to create form file: %w", err)
-	}
-	if _, err = fw.Write(fileContent); err != nil {
-		return "", fmt.Errorf("failed to write file content: %w", err)
-	}
-
-	// Close the writer
-	w.Close()
+	// Stream the multipart body through a pipe instead of buffering the whole file
+	pr, pw := io.Pipe()
+	w := multipart.NewWriter(pw)
+	writeErr := make(chan error, 1)
+	go func() {
+		err := writeMultipart(w, objectName, content, s.maxSize)
+		pw.CloseWithError(err)
+		writeErr <- err
+	}()
 
 	// Create upload URL
 	uploadURL := fmt.Sprintf("%s/storage/v1/object/%s/%s", s.projectURL, bucketName, objectName)
 
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
 	// Create HTTP request
-	req, err := http.NewRequestWithContext(ctx, "POST", uploadURL, &b)
+	req, err := http.NewRequestWithContext(ctx, "POST", uploadURL, pr)
 	if err != nil {
+		pr.Close()
+		<-writeErr
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
 
@@ -111,6 +105,16 @@ func (s *SupabaseStorage) UploadFile(ctx context.Context, bucketName, objectName
 
 	// Execute request
 	resp, err := s.httpClient.Do(req)
+	// Unblock the writer if the request ended early; its error explains a broken stream
+	// better than the transport's
+	pr.Close()
+	if werr := <-writeErr; werr != nil && !errors.Is(werr, io.ErrClosedPipe) {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		log.Error().Err(werr).Msg("[SupabaseStorage-UploadFile] Failed to stream file content")
+		return "", werr
+	}
 	if err != nil {
 		return "", fmt.Errorf("failed to upload file: %w", err)
 	}
@@ -128,6 +132,39 @@ func (s *SupabaseStorage) UploadFile(ctx context.Context, bucketName, objectName
 	return publicURL, nil
 }
 
+// profileObjectName names a profile photo uploaded without a name
+func profileObjectName(id, contentType string) string {
+	ext := ".jpg" // default extension
+	switch contentType {
+	case "image/png":
+		ext = ".png"
+	case "image/gif":
+		ext = ".gif"
+	case "image/webp":
+		ext = ".webp"
+	}
+	return fmt.Sprintf("profile-%s%s", id, ext)
+}
+
+// writeMultipart writes the "file" form field from content, reading at most maxSize+1
+// bytes so an oversized file is detected without reading the rest of it
+func writeMultipart(w *multipart.Writer, objectName string, content io.Reader, maxSize int64) error {
+	fw, err := w.CreateFormFile("file", objectName)
+	if err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
+	}
+
+	n, err := io.Copy(fw, io.LimitReader(content, maxSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read file content: %w", err)
+	}
+	if n > maxSize {
+		return port.ErrFileTooLarge
+	}
+
+	return w.Close()
+}
+
 func (s *SupabaseStorage) DeleteFile(ctx context.Context, bucketName, objectName string) error {
 	// If bucketName is empty, use the default bucket
 	if bucketName == "" {
@@ -137,6 +174,9 @@ func (s *SupabaseStorage) DeleteFile(ctx context.Context, bucketName, objectName
 	// Create delete URL
 	deleteURL := fmt.Sprintf("%s/storage/v1/object/%s/%s", s.projectURL, bucketName, objectName)
 
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
 	// Create HTTP request
 	req, err := http.NewRequestWithContext(ctx, "DELETE", deleteURL, nil)
 	if err != nil {
@@ -162,45 +202,47 @@ func (s *SupabaseStorage) DeleteFile(ctx context.Context, bucketName, objectName
 	return nil
 }
 
-// Helper function to validate image file
-func ValidateImageFile(file multipart.File, header *multipart.FileHeader) error {
-	// Check file size (max 5MB)
-	const maxSize = 5 << 20 // 5MB
-	if header.Size > maxSize {
-		return fmt.Errorf("file size too large, maximum 5MB")
+// SignedURL links to an object in a private bucket for expiresIn
+func (s *SupabaseStorage) SignedURL(ctx context.Context, bucketName, objectName string, expiresIn time.Duration) (string, error) {
+	if bucketName == "" {
+		bucketName = s.bucketName
 	}
 
-	// Check content type
-	contentType := header.Header.Get("Content-Type")
-	allowedTypes := []string{"image/jpeg", "image/jpg", "image/png", "image/gif", "image/webp"}
-
-	validType := false
-	for _, allowedType := range allowedTypes {
-		if contentType == allowedType {
-			validType = true
-			break
-		}
+	body, err := json.Marshal(map[string]int{"expiresIn": int(expiresIn.Seconds())})
+	if err != nil {
+		return "", err
 	}
 
-	if !validType {
-		return fmt.Errorf("invalid file type, only JPEG, PNG, GIF, and WebP are allowed")
+	// Signing has no side effect, so a failed attempt is retried like a GET
+	ctx, cancel := context.WithTimeout(httpclient.Idempotent(ctx), s.timeout)
+	defer cancel()
+
+	signURL := fmt.Sprintf("%s/storage/v1/object/sign/%s/%s", s.projectURL, bucketName, objectName)
+	req, err := http.NewRequestWithContext(ctx, "POST", signURL, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create sign request: %w", err)
 	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.apiKey))
+	req.Header.Set("Content-Type", "application/json")
 
-	// Additional validation: check file extension
-	ext := strings.ToLower(filepath.Ext(header.Filename))
-	allowedExts := []string{".jpg", ".jpeg", ".png", ".gif", ".webp"}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign url: %w", err)
+	}
+	defer resp.Body.Close()
 
-	validExt := false
-	for _, allowedExt := range allowedExts {
-		if ext == allowedExt {
-			validExt = true
-			break
-		}
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("sign failed with status %d: %s", resp.StatusCode, string(respBody))
 	}
 
-	if !validExt {
-		return fmt.Errorf("invalid file extension")
+	// signedURL is relative to the storage API, e.g. "/object/sign/bucket/path?token=..."
+	var signed struct {
+		SignedURL string `json:"signedURL"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&signed); err != nil {
+		return "", fmt.Errorf("failed to decode sign response: %w", err)
 	}
 
-	return nil
+	return s.projectURL + "/storage/v1" + signed.SignedURL, nil
 }
@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+)
+
+// maxResponseBytes is all of the receiver's answer that is read; it only feeds the delivery log
+const maxResponseBytes = 4096
+
+// HTTPSender posts webhook payloads as JSON
+type HTTPSender struct {
+	httpClient *http.Client
+}
+
+func NewHTTPSender(httpClient *http.Client) port.WebhookSenderInterface {
+	return &HTTPSender{
+		httpClient: httpClient,
+	}
+}
+
+// Send returns the receiver's response for any status code; err is only set when no response arrived
+func (s *HTTPSender) Send(ctx context.Context, url string, headers map[string]string, body []byte) (*entity.WebhookResponseEntity, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "jualan-sayur-webhooks/1.0")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call webhook endpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	return &entity.WebhookResponseEntity{
+		StatusCode: resp.StatusCode,
+		Body:       string(responseBody),
+	}, nil
+}
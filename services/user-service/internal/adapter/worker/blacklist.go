@@ -0,0 +1,40 @@
+package worker
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rs/zerolog/log"
+)
+
+const JobTypeCleanupBlacklist = "blacklist.cleanup"
+
+var blacklistSize = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "token_blacklist_size",
+	Help: "Revoked tokens that have not expired yet, as of the last blacklist cleanup.",
+})
+
+// RegisterBlacklistCleanup drops revoked tokens once they would have expired anyway and
+// refreshes the blacklist size metric
+func (w *Worker) RegisterBlacklistCleanup(blacklistRepo port.BlacklistTokenInterface) {
+	w.Register(JobTypeCleanupBlacklist, func(ctx context.Context, job *entity.JobEntity) error {
+		removed, err := blacklistRepo.DeleteExpired(ctx)
+		if err != nil {
+			return err
+		}
+
+		size, err := blacklistRepo.Count(ctx)
+		if err != nil {
+			return err
+		}
+		blacklistSize.Set(float64(size))
+
+		log.Info().Int64("removed", removed).Int64("size", size).Msg("[Worker-CleanupBlacklist] Expired blacklist entries removed")
+		return nil
+	})
+	w.Schedule(JobTypeCleanupBlacklist, time.Hour, nil)
+}
@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+)
+
+// RegisterCampaignBatches sends the batches queued by SegmentService.LaunchCampaign. Each batch
+// has its own run_at, so the pacing comes from the queue rather than from sleeping here.
+func (w *Worker) RegisterCampaignBatches(segmentService port.SegmentServiceInterface) {
+	w.Register(entity.JobTypeSendCampaignBatch, func(ctx context.Context, job *entity.JobEntity) error {
+		var payload entity.CampaignBatchJobPayload
+		if err := job.Decode(&payload); err != nil {
+			return err
+		}
+		return segmentService.SendCampaignBatch(ctx, payload.CampaignID, payload.UserIDs)
+	})
+}
@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RegisterEmailPublishing delivers emails buffered as entity.JobTypePublishEmail jobs while
+// RabbitMQ was unavailable. Failures are retried with the usual backoff until Release.
+func (w *Worker) RegisterEmailPublishing(outbox port.EmailOutboxInterface) {
+	w.Register(entity.JobTypePublishEmail, func(ctx context.Context, job *entity.JobEntity) error {
+		var email entity.BufferedEmailEntity
+		if err := job.Decode(&email); err != nil {
+			return err
+		}
+		return outbox.Flush(ctx, &email)
+	})
+}
+
+// Release makes the waiting jobs of each type due now with fresh attempts, e.g. once RabbitMQ
+// is back, so a long outage does not leave them failed or waiting out their backoff
+func (w *Worker) Release(ctx context.Context, jobTypes ...string) {
+	for _, jobType := range jobTypes {
+		released, err := w.jobRepo.Release(ctx, jobType)
+		if err != nil {
+			log.Error().Err(err).Str("type", jobType).Msg("[Worker-Release] Failed to release jobs")
+			continue
+		}
+		if released > 0 {
+			log.Info().Int64("count", released).Str("type", jobType).Msg("[Worker-Release] Jobs released")
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+)
+
+// RegisterEventPublishing delivers events queued as entity.JobTypePublishEvent jobs.
+// Failed publishes are retried with the usual backoff, so consumers must dedupe on event_id.
+func (w *Worker) RegisterEventPublishing(publisher port.EventPublisherInterface) {
+	w.Register(entity.JobTypePublishEvent, func(ctx context.Context, job *entity.JobEntity) error {
+		var event entity.EventEntity
+		if err := job.Decode(&event); err != nil {
+			return err
+		}
+		return publisher.Publish(ctx, &event)
+	})
+}
@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+)
+
+// RegisterPhotoModeration checks uploaded profile photos queued as entity.JobTypeModeratePhoto
+// jobs; provider failures are retried with the job backoff
+func (w *Worker) RegisterPhotoModeration(photoModerationService port.PhotoModerationServiceInterface) {
+	w.Register(entity.JobTypeModeratePhoto, func(ctx context.Context, job *entity.JobEntity) error {
+		var payload entity.PhotoModerationJobPayload
+		if err := job.Decode(&payload); err != nil {
+			return err
+		}
+		return photoModerationService.Moderate(ctx, payload.ModerationID)
+	})
+}
@@ -0,0 +1,23 @@
+package worker
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RegisterPasswordExpiryWarnings emails staff before their password expires under PASSWORD_MAX_AGE_DAYS
+func (w *Worker) RegisterPasswordExpiryWarnings(passwordPolicyService port.PasswordPolicyServiceInterface) {
+	w.Register(entity.JobTypePasswordExpiryWarning, func(ctx context.Context, job *entity.JobEntity) error {
+		sent, err := passwordPolicyService.SendExpiryWarnings(ctx)
+		if err != nil {
+			return err
+		}
+		log.Info().Int("sent", sent).Msg("[Worker-PasswordExpiryWarnings] Password expiry warnings processed")
+		return nil
+	})
+	w.Schedule(entity.JobTypePasswordExpiryWarning, 24*time.Hour, nil)
+}
@@ -0,0 +1,18 @@
+package worker
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+)
+
+// RegisterStorageReconcile runs the delayed orphan check queued for new profile photo objects
+func (w *Worker) RegisterStorageReconcile(storageEventService port.StorageEventServiceInterface) {
+	w.Register(entity.JobTypeReconcileProfilePhoto, func(ctx context.Context, job *entity.JobEntity) error {
+		var payload entity.ReconcileProfilePhotoJobPayload
+		if err := job.Decode(&payload); err != nil {
+			return err
+		}
+		return storageEventService.ReconcileProfilePhoto(ctx, payload.Bucket, payload.ObjectName)
+	})
+}
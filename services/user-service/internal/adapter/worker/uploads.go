@@ -0,0 +1,25 @@
+package worker
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+const JobTypeCleanupUploads = "uploads.cleanup"
+
+// RegisterUploadCleanup removes resumable uploads that were never completed or aborted
+func (w *Worker) RegisterUploadCleanup(uploadService port.UploadServiceInterface) {
+	w.Register(JobTypeCleanupUploads, func(ctx context.Context, job *entity.JobEntity) error {
+		removed, err := uploadService.CleanupExpired(ctx)
+		if err != nil {
+			return err
+		}
+		log.Info().Int("removed", removed).Msg("[Worker-CleanupUploads] Abandoned uploads removed")
+		return nil
+	})
+	w.Schedule(JobTypeCleanupUploads, time.Hour, nil)
+}
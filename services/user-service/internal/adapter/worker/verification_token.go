@@ -0,0 +1,26 @@
+package worker
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+const JobTypePruneVerificationTokens = "verification_tokens.prune"
+
+// RegisterVerificationTokenCleanup deletes expired tokens; used ones are kept until then so a
+// second click on a link is reported as already used rather than invalid
+func (w *Worker) RegisterVerificationTokenCleanup(tokenRepo port.VerificationTokenInterface) {
+	w.Register(JobTypePruneVerificationTokens, func(ctx context.Context, job *entity.JobEntity) error {
+		deleted, err := tokenRepo.DeleteExpiredVerificationTokens(ctx)
+		if err != nil {
+			return err
+		}
+		log.Info().Int64("deleted", deleted).Msg("[Worker-PruneVerificationTokens] Expired verification tokens pruned")
+		return nil
+	})
+	w.Schedule(JobTypePruneVerificationTokens, 24*time.Hour, nil)
+}
@@ -0,0 +1,19 @@
+package worker
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+)
+
+// RegisterWebhookDelivery sends deliveries queued as entity.JobTypeDeliverWebhook jobs. The job
+// backoff paces the retries; the final attempt marks the delivery failed in the delivery log.
+func (w *Worker) RegisterWebhookDelivery(webhookService port.WebhookServiceInterface) {
+	w.Register(entity.JobTypeDeliverWebhook, func(ctx context.Context, job *entity.JobEntity) error {
+		var payload entity.WebhookDeliveryJobPayload
+		if err := job.Decode(&payload); err != nil {
+			return err
+		}
+		return webhookService.Deliver(ctx, payload.DeliveryID, job.Attempts >= job.MaxAttempts)
+	})
+}
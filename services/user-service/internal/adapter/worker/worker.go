@@ -0,0 +1,232 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultConcurrency  = 2
+	defaultPollInterval = 2 * time.Second
+	// Jobs locked longer than this are assumed abandoned by a crashed worker
+	lockTimeout = 10 * time.Minute
+	maxBackoff  = time.Hour
+)
+
+// HandlerFunc processes one job; returning an error schedules a retry until attempts run out
+type HandlerFunc func(ctx context.Context, job *entity.JobEntity) error
+
+type schedule struct {
+	jobType  string
+	interval time.Duration
+	payload  interface{}
+}
+
+type Worker struct {
+	jobRepo      port.JobRepositoryInterface
+	jobService   port.JobServiceInterface
+	id           string
+	concurrency  int
+	pollInterval time.Duration
+	handlers     map[string]HandlerFunc
+	schedules    []schedule
+	wg           sync.WaitGroup
+	cancel       context.CancelFunc
+}
+
+// Register binds a handler to a job type; call before Start
+func (w *Worker) Register(jobType string, handler HandlerFunc) {
+	w.handlers[jobType] = handler
+}
+
+// Schedule enqueues jobType every interval. Each window gets a unique key, so running
+// several instances still enqueues the job once per window.
+func (w *Worker) Schedule(jobType string, interval time.Duration, payload interface{}) {
+	w.schedules = append(w.schedules, schedule{jobType: jobType, interval: interval, payload: payload})
+}
+
+func (w *Worker) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	types := make([]string, 0, len(w.handlers))
+	for jobType := range w.handlers {
+		types = append(types, jobType)
+	}
+	if len(types) == 0 {
+		log.Warn().Msg("[Worker-Start] No job handlers registered, worker not started")
+		return
+	}
+
+	for i := 0; i < w.concurrency; i++ {
+		w.wg.Add(1)
+		go w.run(ctx, types, fmt.Sprintf("%s-%d", w.id, i))
+	}
+
+	w.wg.Add(1)
+	go w.maintain(ctx)
+
+	log.Info().Str("worker_id", w.id).Int("concurrency", w.concurrency).Strs("types", types).Msg("[Worker-Start] Job worker started")
+}
+
+// Stop waits for in-flight jobs to finish or ctx to expire
+func (w *Worker) Stop(ctx context.Context) {
+	if w.cancel == nil {
+		return
+	}
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Info().Str("worker_id", w.id).Msg("[Worker-Stop] Job worker stopped")
+	case <-ctx.Done():
+		log.Warn().Str("worker_id", w.id).Msg("[Worker-Stop] Timed out waiting for running jobs")
+	}
+}
+
+func (w *Worker) run(ctx context.Context, types []string, workerID string) {
+	defer w.wg.Done()
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		job, err := w.jobRepo.ClaimNext(ctx, types, workerID)
+		if err != nil {
+			// Nothing due (or the database hiccuped); wait for the next poll
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(w.pollInterval):
+			}
+			continue
+		}
+
+		// Let the running job finish even when shutdown starts
+		w.process(context.WithoutCancel(ctx), job)
+	}
+}
+
+func (w *Worker) process(ctx context.Context, job *entity.JobEntity) {
+	handler := w.handlers[job.Type]
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return handler(ctx, job)
+	}()
+
+	if err == nil {
+		if markErr := w.jobRepo.MarkCompleted(ctx, job.ID); markErr != nil {
+			log.Error().Err(markErr).Int64("job_id", job.ID).Msg("[Worker-process] Failed to mark job completed")
+		}
+		log.Info().Int64("job_id", job.ID).Str("type", job.Type).Int("attempt", job.Attempts).Msg("[Worker-process] Job completed")
+		return
+	}
+
+	var retryAt *time.Time
+	if job.Attempts < job.MaxAttempts {
+		next := time.Now().Add(Backoff(job.Attempts))
+		retryAt = &next
+	}
+
+	if markErr := w.jobRepo.MarkFailed(ctx, job.ID, err.Error(), retryAt); markErr != nil {
+		log.Error().Err(markErr).Int64("job_id", job.ID).Msg("[Worker-process] Failed to mark job failed")
+	}
+
+	if retryAt != nil {
+		log.Warn().Err(err).Int64("job_id", job.ID).Str("type", job.Type).Int("attempt", job.Attempts).Time("retry_at", *retryAt).Msg("[Worker-process] Job failed, retry scheduled")
+		return
+	}
+	log.Error().Err(err).Int64("job_id", job.ID).Str("type", job.Type).Int("attempt", job.Attempts).Msg("[Worker-process] Job failed permanently")
+}
+
+// maintain enqueues scheduled jobs and releases jobs held by dead workers
+func (w *Worker) maintain(ctx context.Context) {
+	defer w.wg.Done()
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		for _, s := range w.schedules {
+			window := now.Truncate(s.interval)
+			uniqueKey := fmt.Sprintf("%s:%d", s.jobType, window.Unix())
+			if _, err := w.jobService.EnqueueUnique(ctx, s.jobType, uniqueKey, s.payload, window); err != nil && err.Error() != "job already enqueued" {
+				log.Error().Err(err).Str("type", s.jobType).Msg("[Worker-maintain] Failed to enqueue scheduled job")
+			}
+		}
+
+		if requeued, err := w.jobRepo.RequeueStale(ctx, now.Add(-lockTimeout)); err == nil && requeued > 0 {
+			log.Warn().Int64("count", requeued).Msg("[Worker-maintain] Requeued jobs with expired locks")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Backoff grows quadratically from 30s and is capped at one hour
+func Backoff(attempt int) time.Duration {
+	delay := time.Duration(attempt*attempt) * 30 * time.Second
+	if delay > maxBackoff {
+		return maxBackoff
+	}
+	return delay
+}
+
+func NewWorker(jobRepo port.JobRepositoryInterface, jobService port.JobServiceInterface, concurrency int, pollInterval time.Duration) *Worker {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+
+	hostname, _ := os.Hostname()
+
+	return &Worker{
+		jobRepo:      jobRepo,
+		jobService:   jobService,
+		id:           fmt.Sprintf("%s-%s", hostname, uuid.New().String()[:8]),
+		concurrency:  concurrency,
+		pollInterval: pollInterval,
+		handlers:     make(map[string]HandlerFunc),
+	}
+}
+
+const JobTypePruneJobs = "jobs.prune"
+
+// RegisterMaintenanceJobs adds the worker's own housekeeping: completed jobs are kept for a week
+func (w *Worker) RegisterMaintenanceJobs() {
+	w.Register(JobTypePruneJobs, func(ctx context.Context, job *entity.JobEntity) error {
+		deleted, err := w.jobRepo.DeleteCompletedBefore(ctx, time.Now().Add(-7*24*time.Hour))
+		if err != nil {
+			return err
+		}
+		log.Info().Int64("deleted", deleted).Msg("[Worker-PruneJobs] Completed jobs pruned")
+		return nil
+	})
+	w.Schedule(JobTypePruneJobs, 24*time.Hour, nil)
+}
@@ -5,36 +5,54 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 	"user-service/config"
+	"user-service/internal/adapter/breaker"
+	"user-service/internal/adapter/cdn"
+	"user-service/internal/adapter/geocoding"
 	"user-service/internal/adapter/handler"
+	"user-service/internal/adapter/httpclient"
 	"user-service/internal/adapter/message"
 	"user-service/internal/adapter/middleware"
+	"user-service/internal/adapter/moderation"
+	"user-service/internal/adapter/oidc"
 	"user-service/internal/adapter/repository"
+	"user-service/internal/adapter/scanner"
 	"user-service/internal/adapter/storage"
+	"user-service/internal/adapter/webhook"
+	"user-service/internal/adapter/worker"
+	"user-service/internal/buildinfo"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
 	"user-service/internal/core/port"
 	"user-service/internal/core/service"
+	"user-service/internal/diagnostics"
+	"user-service/internal/startup"
 	"user-service/utils"
 	validatorUtils "user-service/utils/validator"
 
+	"github.com/hilmirazib/jualan-sayur/pkg/logging"
+	"github.com/hilmirazib/jualan-sayur/pkg/security"
 	"github.com/labstack/echo/v4"
-	"github.com/rs/zerolog"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/streadway/amqp"
 	"gorm.io/gorm"
 )
 
 // App holds all dependencies
 type App struct {
-	UserService      port.UserServiceInterface
-	UserRepo         port.UserRepositoryInterface
-	RoleService      port.RoleServiceInterface
-	RoleRepo         port.RoleRepositoryInterface
-	JWTUtil          port.JWTInterface
-	DB               *gorm.DB
-	RabbitMQChannel  *amqp.Channel
+	UserService   port.UserServiceInterface
+	UserRepo      port.UserRepositoryInterface
+	RoleService   port.RoleServiceInterface
+	RoleRepo      port.RoleRepositoryInterface
+	JWTUtil       port.JWTInterface
+	BlacklistRepo port.BlacklistTokenInterface
+	DB            *gorm.DB
+	RabbitMQ      *message.Broker
 	// Add other services here as they are created
 }
 
@@ -48,109 +66,694 @@ func checkPortAvailability(port string) error {
 	return nil
 }
 
+// trustedProxyOptions trusts only the configured proxy ranges instead of Echo's private-network defaults
+func trustedProxyOptions(proxies []string) []echo.TrustOption {
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, proxy := range proxies {
+		normalized, err := utils.NormalizeCIDR(proxy)
+		if err != nil {
+			log.Printf("⚠️  Ignoring invalid TRUSTED_PROXIES entry %q", proxy)
+			continue
+		}
+		_, network, _ := net.ParseCIDR(normalized)
+		options = append(options, echo.TrustIPRange(network))
+	}
+	return options
+}
+
 // RunServer starts the HTTP server with graceful shutdown
 func RunServer() {
-	// Initialize zerolog
-	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	// Timestamps are stored and returned in UTC whatever the host's zone; per-user zones
+	// only apply where times are shown to a person (emails)
+	time.Local = time.UTC
 
 	// Load configuration
 	cfg := config.NewConfig()
 
+	// Initialize zerolog; SIGUSR1 toggles debug logging without a restart
+	if err := logging.Setup(logging.Config{Level: cfg.Log.Level, Format: cfg.Log.Format}, "user-service", buildinfo.Version); err != nil {
+		log.Fatalf("Invalid log config: %v", err)
+	}
+	go logging.WatchSignal(context.Background())
+
+	if err := cfg.App.Validate(); err != nil {
+		log.Fatalf("Invalid app config: %v", err)
+	}
+	// Token lifetimes change what users are told in emails, so refuse to guess on bad values
+	if err := cfg.TokenLifetimes.Validate(); err != nil {
+		log.Fatalf("Invalid token lifetime: %v", err)
+	}
+	if err := cfg.Sessions.Validate(); err != nil {
+		log.Fatalf("Invalid session lifetime: %v", err)
+	}
+	if err := cfg.PasswordHashing.Validate(); err != nil {
+		log.Fatalf("Invalid password hashing config: %v", err)
+	}
+	if err := cfg.PasswordPolicy.Validate(); err != nil {
+		log.Fatalf("Invalid password policy config: %v", err)
+	}
+	if err := cfg.RateLimit.Validate(); err != nil {
+		log.Fatalf("Invalid rate limit config: %v", err)
+	}
+	if err := cfg.HTTPServer.Validate(cfg.Timeouts, cfg.Upload); err != nil {
+		log.Fatalf("Invalid HTTP server config: %v", err)
+	}
+	if err := cfg.Diagnostics.Validate(); err != nil {
+		log.Fatalf("Invalid diagnostics config: %v", err)
+	}
+	if err := cfg.QueryTrace.Validate(); err != nil {
+		log.Fatalf("Invalid query trace config: %v", err)
+	}
+	if err := cfg.Registration.Validate(); err != nil {
+		log.Fatalf("Invalid registration config: %v", err)
+	}
+	if err := cfg.SSO.Validate(); err != nil {
+		log.Fatalf("Invalid SSO config: %v", err)
+	}
+	if err := cfg.PIIEncryption.Validate(); err != nil {
+		log.Fatalf("Invalid PII encryption config: %v", err)
+	}
+	if err := cfg.Security.Validate(); err != nil {
+		log.Fatalf("Invalid security config: %v", err)
+	}
+
 	// Check if APP_PORT is available
 	if err := checkPortAvailability(cfg.App.AppPort); err != nil {
 		log.Fatalf("Port %s is not available: %v", cfg.App.AppPort, err)
 	}
 
+	// pprof and expvar on their own listener, also while degraded
+	stopDiagnostics := diagnostics.Start(cfg.Diagnostics)
+
 	// Initialize application
 	app, err := NewApp(cfg)
 	if err != nil {
-		log.Printf("⚠️  Database not available, starting server in offline mode: %v", err)
-		log.Printf("🚀 Server will start but API endpoints will return database errors")
+		log.Printf("⚠️  Database not available, starting server in degraded mode: %v", err)
+		log.Printf("🚀 Server will start but API endpoints will return 503 until it is restarted")
 		log.Printf("💡 To fix: Start PostgreSQL and run migrations")
 	}
 
+	// Catch models that no longer match the migrations, like a string field on a numeric
+	// column, before a request trips over them. Production runs migrations before deploying.
+	if app != nil && cfg.App.AppEnv != "production" {
+		go repository.LogSchemaDrift(context.Background(), app.DB, model.All())
+	}
+
 	// Initialize Echo server
 	e := echo.New()
 	e.HideBanner = true
 
+	// Connection-level limits; a slow client cannot hold a connection open indefinitely
+	e.Server.ReadTimeout = cfg.HTTPServer.ReadTimeout()
+	e.Server.ReadHeaderTimeout = cfg.HTTPServer.ReadHeaderTimeout()
+	e.Server.WriteTimeout = cfg.HTTPServer.WriteTimeout()
+	e.Server.IdleTimeout = cfg.HTTPServer.IdleTimeout()
+	e.Server.MaxHeaderBytes = cfg.HTTPServer.MaxHeaderBytes()
+
+	// Only trust X-Forwarded-For from configured proxies so RealIP cannot be spoofed
+	if len(cfg.AdminAccess.TrustedProxies) > 0 {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader(trustedProxyOptions(cfg.AdminAccess.TrustedProxies)...)
+	} else {
+		e.IPExtractor = echo.ExtractIPDirect()
+	}
+
 	// Initialize validator
 	e.Validator = validatorUtils.NewValidator()
+	// Handlers write English messages; the serializer translates them per request
+	e.JSONSerializer = middleware.LocalizedJSONSerializer{}
 
 	// Middleware
-	e.Use(middleware.CORSMiddleware())
+	e.Use(middleware.CORSMiddleware(cfg.Security.CORSAllowOrigins))
+	e.Use(middleware.RequestIDMiddleware())
 	e.Use(middleware.LoggerMiddleware())
+	e.Use(middleware.LanguageMiddleware())
+	e.Use(middleware.QueryTraceMiddleware(cfg.QueryTrace.WarnThreshold()))
+	// Uploads stream the body and then call storage, so they get the storage deadline on top
+	e.Use(middleware.TimeoutMiddleware(cfg.Timeouts.Request(), cfg.Timeouts.Request()+cfg.Timeouts.Storage()))
+	// Only the routes that take files get the larger body limit
+	e.Use(middleware.BodyLimitMiddleware(cfg.HTTPServer.BodyLimit(), cfg.HTTPServer.UploadBodyLimit(),
+		"/api/v1/auth/profile/image-upload",
+		"/api/v1/vendors/me/documents",
+		"/api/v1/uploads/:id/chunks/:index",
+		"/api/v1/admin/customers/import",
+		"/api/v1/admin/assets",
+		"/api/v1/users/me/identity",
+		"/api/v1/support/tickets",
+	))
+	securityConfig := middleware.SecurityConfig(cfg.Security)
+	e.Use(security.HeadersMiddleware(securityConfig))
+	e.Use(security.CSRFMiddleware(securityConfig))
+
+	// Degraded mode: without a database nothing below can be wired, so only report why
+	if app == nil {
+		registerDegradedRoutes(e, err)
+		serve(e, cfg.App.AppPort, stopDiagnostics)
+		return
+	}
 
 	// Initialize repositories
 	redisClient := cfg.RedisClient()
 	sessionRepo := repository.NewSessionRepository(redisClient, cfg)
 	verificationTokenRepo := repository.NewVerificationTokenRepository(app.DB)
-	blacklistTokenRepo := repository.NewBlacklistTokenRepository(app.DB)
-
-	// Initialize message publishers
-	emailPublisher := message.NewEmailPublisher(app.RabbitMQChannel)
+	blacklistTokenRepo := app.BlacklistRepo
+	deliveryZoneRepo := repository.NewDeliveryZoneRepository(app.DB)
+	vendorRepo := repository.NewVendorRepository(app.DB)
+	ledgerRepo := repository.NewLedgerRepository(app.DB)
+	jobRepo := repository.NewJobRepository(app.DB)
+	featureFlagRepo := repository.NewFeatureFlagRepository(app.DB, redisClient)
+	ipAccessRepo := repository.NewIPAccessRepository(redisClient)
+	onboardingRepo := repository.NewOnboardingRepository(app.DB)
+	deviceRepo := repository.NewDeviceRepository(app.DB)
+	auditLogRepo := repository.NewAuditLogRepository(app.DB)
+	riskRepo := repository.NewRiskRepository(redisClient)
+	accountMergeRepo := repository.NewAccountMergeRepository(app.DB)
+	trashRepo := repository.NewTrashRepository(app.DB)
+	customerImportReportRepo := repository.NewCustomerImportReportRepository(redisClient)
+	webhookRepo := repository.NewWebhookRepository(app.DB)
+	photoModerationRepo := repository.NewPhotoModerationRepository(app.DB)
+	rateLimitRepo := repository.NewRateLimitRepository(redisClient)
+	apiKeyRepo := repository.NewAPIKeyRepository(app.DB)
+	segmentRepo := repository.NewSegmentRepository(app.DB)
+	savedViewRepo := repository.NewSavedViewRepository(app.DB)
+	assetRepo := repository.NewAssetRepository(app.DB)
+	inviteRepo := repository.NewInviteRepository(app.DB)
+	legalRepo := repository.NewLegalRepository(app.DB)
+	identityRepo := repository.NewIdentityRepository(app.DB)
+	supportRepo := repository.NewSupportRepository(app.DB)
+	scimRepo := repository.NewSCIMRepository(app.DB)
+	emailRateLimitRepo := repository.NewEmailRateLimitRepository(app.DB, redisClient)
+	storedObjectRepo := repository.NewStoredObjectRepository(app.DB)
+
+	// Circuit breakers for external dependencies; state is exported on /metrics
+	breakerSettings := circuitBreakerSettings(cfg)
+	rabbitMQBreaker := breaker.New("rabbitmq", breakerSettings)
+	storageBreaker := breaker.New("storage", breakerSettings)
+
+	jobService := service.NewJobService(jobRepo)
+
+	// Initialize message publishers; emails wait in the job queue while RabbitMQ is down
+	emailOutbox := message.NewEmailOutbox(app.RabbitMQ, rabbitMQBreaker, jobService)
+	emailPublisher := message.NewEmailPublisher(emailOutbox, service.TokenLifetimesFromConfig(cfg), app.UserRepo, cfg.App.LinkBaseURL())
+	eventPublisher := message.NewEventPublisher(app.RabbitMQ, rabbitMQBreaker)
 
 	// Initialize storage (Supabase Storage)
 	supabaseStorage, err := storage.NewSupabaseStorage(
 		cfg.Supabase.ProjectURL,
 		cfg.Supabase.APIKey,
 		cfg.Supabase.BucketName,
+		cfg.Timeouts.Storage(),
+		cfg.Supabase.MaxUploadSize(),
+		httpclient.New(httpClientOptions(cfg, "storage", 0)),
 	)
 	if err != nil {
 		log.Printf("⚠️  Supabase Storage not available: %v", err)
 		log.Printf("💡 Image upload will not work until Supabase is configured")
 		supabaseStorage = nil
+	} else {
+		supabaseStorage = storage.NewBreakerStorage(supabaseStorage, storageBreaker)
+	}
+
+	// Initialize geocoder (Nominatim or Google)
+	geocoder, err := geocoding.NewGeocoder(cfg, httpclient.New(httpClientOptions(cfg, "geocoder", 10*time.Second)))
+	if err != nil {
+		log.Printf("⚠️  Geocoder not available: %v", err)
+		log.Printf("💡 Address components will not be resolved until GEOCODER_* is configured")
+		geocoder = nil
+	}
+
+	// IP geolocation for geo-velocity checks is optional
+	var ipLocator port.IPLocatorInterface
+	if cfg.Risk.GeoIPBaseURL != "" {
+		ipLocator = geocoding.NewIPLocator(cfg.Risk.GeoIPBaseURL, httpclient.New(httpClientOptions(cfg, "geoip", 3*time.Second)))
+	} else {
+		log.Printf("💡 Geo-velocity sign-in checks are disabled until GEOIP_BASE_URL is configured")
+	}
+
+	// Webhook endpoints are admin-supplied URLs, so redirects are reported instead of followed
+	webhookTimeout := 10 * time.Second
+	if cfg.Webhook.TimeoutSeconds > 0 {
+		webhookTimeout = time.Duration(cfg.Webhook.TimeoutSeconds) * time.Second
+	}
+	webhookSender := webhook.NewHTTPSender(&http.Client{
+		Timeout: webhookTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	})
+
+	auditLogService := service.NewAuditLogService(auditLogRepo)
+	apiKeyService := service.NewAPIKeyService(apiKeyRepo, auditLogService)
+
+	// Counts every route below, including ones registered after this call
+	e.Use(middleware.RateLimitMiddleware(rateLimitRepo, apiKeyService, cfg.RateLimit))
+
+	// Uploads are scanned before they reach storage when a ClamAV daemon is configured
+	if supabaseStorage != nil && cfg.FileScan.ClamAVAddress != "" {
+		fileScanner := scanner.NewClamAVScanner(cfg.FileScan.ClamAVAddress, cfg.FileScan.Timeout())
+		supabaseStorage = storage.NewScanningStorage(supabaseStorage, fileScanner, auditLogService, cfg.FileScan.LogOnly)
+		log.Printf("🛡️  Upload scanning enabled (clamd %s, log-only: %t)", cfg.FileScan.ClamAVAddress, cfg.FileScan.LogOnly)
+	}
+
+	// Deleted objects are purged from the CDN serving them
+	cdnRewriter := cdn.NewRewriter(cfg.Supabase.ProjectURL, cfg.CDN.BaseURL)
+	if supabaseStorage != nil && cdnRewriter.Enabled() && cfg.CDN.PurgeURL != "" {
+		purger := cdn.NewHTTPPurger(cfg.CDN.PurgeURL, cfg.CDN.PurgeToken, httpclient.New(httpClientOptions(cfg, "cdn", 10*time.Second)))
+		supabaseStorage = storage.NewCDNPurgingStorage(supabaseStorage, cdnRewriter, purger, cfg.Supabase.BucketName)
+	}
+
+	// Outermost, so a file that is already stored is neither scanned nor uploaded again
+	if supabaseStorage != nil {
+		supabaseStorage = storage.NewContentAddressedStorage(supabaseStorage, storedObjectRepo, cfg.Supabase.BucketName)
 	}
 
-	app.UserService = service.NewUserService(app.UserRepo, sessionRepo, app.JWTUtil, verificationTokenRepo, emailPublisher, blacklistTokenRepo, supabaseStorage, cfg)
+	webhookService := service.NewWebhookService(webhookRepo, jobService, webhookSender)
+	riskService := service.NewRiskService(riskRepo, deviceRepo, auditLogService, ipLocator, emailPublisher, cfg)
+
+	// Profile photos are checked in the background when a moderation provider is configured;
+	// the admin review queue works either way
+	var imageModerator port.ImageModeratorInterface
+	if cfg.Moderation.ProviderURL != "" {
+		imageModerator = moderation.NewHTTPModerator(cfg.Moderation.ProviderURL, cfg.Moderation.APIKey, &http.Client{Timeout: cfg.Moderation.Timeout()})
+	}
+	photoModerationService := service.NewPhotoModerationService(photoModerationRepo, jobService, imageModerator, app.UserRepo, supabaseStorage, emailPublisher, cfg)
+	var photoModeration port.PhotoModerationSubmitterInterface
+	if imageModerator != nil {
+		photoModeration = photoModerationService
+	}
+
+	inviteService := service.NewInviteService(inviteRepo)
+	legalService := service.NewLegalService(legalRepo, auditLogService)
+	app.UserService = service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              app.UserRepo,
+			SessionRepo:           sessionRepo,
+			JWTUtil:               app.JWTUtil,
+			VerificationTokenRepo: verificationTokenRepo,
+			EmailPublisher:        emailPublisher,
+			BlacklistTokenRepo:    blacklistTokenRepo,
+			Storage:               supabaseStorage,
+			Geocoder:              geocoder,
+			ZoneRepo:              deliveryZoneRepo,
+			DeviceRepo:            deviceRepo,
+			RiskService:           riskService,
+			Webhooks:              webhookService,
+			PhotoModeration:       photoModeration,
+			Jobs:                  jobService,
+			Roles:                 app.RoleService,
+		},
+		Invites: inviteService,
+		Legal:   legalService,
+		Config:  cfg,
+	})
+	passwordPolicyService := service.NewPasswordPolicyService(app.UserRepo, app.RoleRepo, sessionRepo, emailPublisher, app.RoleService, cfg)
+	geocodingService := service.NewGeocodingService(geocoder)
+	deliveryZoneService := service.NewDeliveryZoneService(deliveryZoneRepo)
+	vendorService := service.NewVendorService(vendorRepo, supabaseStorage)
+	ledgerService := service.NewLedgerService(ledgerRepo, vendorRepo, cfg)
+	featureFlagService := service.NewFeatureFlagService(featureFlagRepo)
+	ipAccessService := service.NewIPAccessService(ipAccessRepo, cfg)
+	onboardingService := service.NewOnboardingService(onboardingRepo, app.UserRepo, jobService)
+	deviceService := service.NewDeviceService(deviceRepo)
+	accountMergeService := service.NewAccountMergeService(accountMergeRepo, sessionRepo, emailPublisher, jobService, auditLogService)
+	accountSecurityService := service.NewAccountSecurityService(app.UserRepo, verificationTokenRepo, sessionRepo, emailPublisher, auditLogService)
+	trashService := service.NewTrashService(trashRepo, auditLogService)
+	customerImportService := service.NewCustomerImportService(app.UserRepo, verificationTokenRepo, emailPublisher, customerImportReportRepo, webhookService, service.EmailPolicyFromConfig(cfg), service.PasswordHasherFromConfig(cfg))
+	storageEventService := service.NewStorageEventService(app.UserRepo, storedObjectRepo, jobService, supabaseStorage, cfg)
+	uploadService := service.NewUploadService(storage.NewLocalChunkStore(cfg.Upload.Directory()), supabaseStorage, cfg)
+	segmentService := service.NewSegmentService(segmentRepo, app.UserRepo, jobService, emailPublisher, auditLogService, cfg)
+	savedViewService := service.NewSavedViewService(savedViewRepo)
+	assetService := service.NewAssetService(assetRepo, supabaseStorage, auditLogService, cfg)
+	identityService := service.NewIdentityService(identityRepo, supabaseStorage, auditLogService, cfg)
+	supportService := service.NewSupportService(supportRepo, app.UserRepo, supabaseStorage, emailPublisher, webhookService, auditLogService, cfg)
+	emailRateLimitService := service.NewEmailRateLimitService(emailRateLimitRepo, auditLogService)
+	scimService := service.NewSCIMService(scimRepo, app.UserRepo, sessionRepo, auditLogService, cfg)
+
+	// OpenID Connect sign-in for staff is optional; password sign-in works either way
+	var ssoHandler handler.SSOHandlerInterface
+	if cfg.SSO.Enabled {
+		oidcProvider := oidc.NewProvider(cfg.SSO, httpclient.New(httpClientOptions(cfg, "oidc", 10*time.Second)))
+		ssoService := service.NewSSOService(oidcProvider, repository.NewSSOStateRepository(redisClient), repository.NewSSORepository(app.DB), app.UserRepo, sessionRepo, app.UserService, auditLogService, cfg)
+		ssoHandler = handler.NewSSOHandler(ssoService, cfg)
+	} else {
+		log.Printf("💡 Staff SSO is disabled until SSO_ENABLED and SSO_* are configured")
+	}
+
+	// notification-service reads the quotas from Redis; republish them in case Redis lost them
+	if err := emailRateLimitService.SyncLimits(context.Background()); err != nil {
+		log.Printf("⚠️  Failed to publish email rate limits, notification-service keeps its defaults: %v", err)
+	}
+
+	// Background jobs; features register their handlers here before Start
+	jobWorker := worker.NewWorker(jobRepo, jobService, cfg.Worker.Concurrency, time.Duration(cfg.Worker.PollIntervalSeconds)*time.Second)
+	jobWorker.RegisterMaintenanceJobs()
+	jobWorker.RegisterEventPublishing(eventPublisher)
+	jobWorker.RegisterEmailPublishing(emailOutbox)
+	jobWorker.RegisterWebhookDelivery(webhookService)
+	jobWorker.RegisterUploadCleanup(uploadService)
+	jobWorker.RegisterBlacklistCleanup(blacklistTokenRepo)
+	jobWorker.RegisterVerificationTokenCleanup(verificationTokenRepo)
+	jobWorker.RegisterPhotoModeration(photoModerationService)
+	jobWorker.RegisterStorageReconcile(storageEventService)
+	jobWorker.RegisterCampaignBatches(segmentService)
+	if cfg.PasswordPolicy.MaxAgeDays > 0 {
+		jobWorker.RegisterPasswordExpiryWarnings(passwordPolicyService)
+	}
+	jobWorker.Start(context.Background())
+
+	// Buffered emails and events go out as soon as RabbitMQ is back instead of after their backoff
+	app.RabbitMQ.OnReconnect(func(ctx context.Context) {
+		jobWorker.Release(ctx, entity.JobTypePublishEmail, entity.JobTypePublishEvent)
+	})
+	brokerCtx, stopBroker := context.WithCancel(context.Background())
+	app.RabbitMQ.Start(brokerCtx)
 
 	// Initialize handlers
-	userHandler := handler.NewUserHandler(app.UserService)
+	userHandler := handler.NewUserHandler(app.UserService, cfg)
 	roleHandler := handler.NewRoleHandler(app.RoleService)
-	customerHandler := handler.NewCustomerHandler(app.UserService)
+	customerSuggestService := service.NewCustomerSuggestService(app.UserRepo, cfg)
+	customerHandler := handler.NewCustomerHandler(app.UserService, customerSuggestService, cfg)
+	geocodeHandler := handler.NewGeocodeHandler(geocodingService)
+	deliveryZoneHandler := handler.NewDeliveryZoneHandler(deliveryZoneService)
+	vendorHandler := handler.NewVendorHandler(vendorService)
+	ledgerHandler := handler.NewLedgerHandler(ledgerService)
+	jobHandler := handler.NewJobHandler(jobService)
+	featureFlagHandler := handler.NewFeatureFlagHandler(featureFlagService)
+	ipAccessHandler := handler.NewIPAccessHandler(ipAccessService)
+	internalHandler := handler.NewInternalHandler(app.UserService, service.NewTokenIntrospectionService(app.JWTUtil, sessionRepo, blacklistTokenRepo, passwordPolicyService))
+	onboardingHandler := handler.NewOnboardingHandler(onboardingService)
+	deviceHandler := handler.NewDeviceHandler(deviceService)
+	auditLogHandler := handler.NewAuditLogHandler(auditLogService)
+	logLevelHandler := handler.NewLogLevelHandler()
+	accountMergeHandler := handler.NewAccountMergeHandler(accountMergeService)
+	accountSecurityHandler := handler.NewAccountSecurityHandler(accountSecurityService)
+	trashHandler := handler.NewTrashHandler(trashService)
+	customerImportHandler := handler.NewCustomerImportHandler(customerImportService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
+	photoModerationHandler := handler.NewPhotoModerationHandler(photoModerationService)
+	uploadHandler := handler.NewUploadHandler(uploadService)
+	storageWebhookHandler := handler.NewStorageWebhookHandler(storageEventService, cfg.Supabase.WebhookSecret)
+	segmentHandler := handler.NewSegmentHandler(segmentService)
+	savedViewHandler := handler.NewSavedViewHandler(savedViewService)
+	assetHandler := handler.NewAssetHandler(assetService)
+	inviteHandler := handler.NewInviteHandler(inviteService)
+	legalHandler := handler.NewLegalHandler(legalService)
+	identityHandler := handler.NewIdentityHandler(identityService)
+	supportHandler := handler.NewSupportHandler(supportService)
+	emailRateLimitHandler := handler.NewEmailRateLimitHandler(emailRateLimitService)
+	scimHandler := handler.NewSCIMHandler(scimService, cfg)
+
+	// Opt-in body logging for auth flows, where redaction matters most
+	bodyLogger := middleware.BodyLoggerMiddleware(middleware.BodyLoggerConfig{
+		SampleRate:   cfg.HTTPLog.BodySampleRate,
+		MaxBodyBytes: cfg.HTTPLog.BodyMaxBytes,
+	})
+
+	// Signed-in routes answer 428 until the current terms are accepted; sign-out, sessions
+	// and the consent endpoints themselves stay reachable
+	requireConsent := middleware.ConsentMiddleware(legalService)
 
 	public := e.Group("/api/v1")
-	public.POST("/auth/signin", userHandler.SignIn)
-	public.POST("/auth/signup", userHandler.CreateUserAccount)
-	public.POST("/auth/logout", userHandler.Logout, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo))
+	if cfg.Security.CSRFEnabled {
+		public.GET("/auth/csrf-token", security.CSRFTokenHandler)
+	}
+	public.POST("/auth/signin", userHandler.SignIn, bodyLogger)
+	public.POST("/auth/signin/verify-otp", userHandler.VerifySignInOTP, bodyLogger)
+	if ssoHandler != nil {
+		public.GET("/auth/sso/login", ssoHandler.Login)
+		public.POST("/auth/sso/callback", ssoHandler.Callback, bodyLogger)
+	}
+	public.POST("/auth/signup", userHandler.CreateUserAccount, bodyLogger)
+	public.POST("/auth/logout", userHandler.Logout, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService))
+	public.POST("/auth/refresh", userHandler.RefreshSession, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService))
+	public.GET("/auth/sessions", userHandler.GetSessions, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService))
 	public.GET("/auth/verify", userHandler.VerifyUserAccount)
 	public.GET("/auth/verify-email-change", userHandler.VerifyEmailChange)
-	public.POST("/auth/forgot-password", userHandler.ForgotPassword)
-	public.POST("/auth/reset-password", userHandler.ResetPassword)
-	public.GET("/auth/profile", userHandler.Profile, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo))
-	public.PUT("/auth/profile", userHandler.UpdateProfile, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo))
-	public.POST("/auth/profile/image-upload", userHandler.ImageUploadProfile, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo))
-
-	admin := e.Group("/api/v1/admin", middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo))
+	public.GET("/auth/revert-email-change", accountSecurityHandler.RevertEmailChange)
+	public.POST("/auth/forgot-password", userHandler.ForgotPassword, bodyLogger)
+	public.POST("/auth/verify/resend", userHandler.ResendVerificationEmail, bodyLogger)
+	// Called by Supabase, authenticated by the body signature instead of a JWT
+	public.POST("/webhooks/supabase/storage", storageWebhookHandler.ReceiveStorageEvent)
+	public.POST("/auth/reset-password", userHandler.ResetPassword, bodyLogger)
+	public.GET("/auth/profile", userHandler.Profile, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.PUT("/auth/profile", userHandler.UpdateProfile, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.POST("/auth/profile/image-upload", userHandler.ImageUploadProfile, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.POST("/auth/profile/avatar/regenerate", userHandler.RegenerateAvatar, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.PUT("/auth/profile/username", userHandler.ChangeUsername, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.PUT("/auth/profile/timezone", userHandler.ChangeTimezone, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.PUT("/auth/profile/language", userHandler.ChangeLanguage, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.PUT("/auth/profile/privacy", userHandler.ChangePrivacySettings, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.GET("/auth/username/availability", userHandler.CheckUsernameAvailability)
+	public.GET("/auth/devices", deviceHandler.GetDevices, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.PUT("/auth/devices/:id/trust", deviceHandler.TrustDevice, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.GET("/geocode", geocodeHandler.Geocode, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.GET("/geocode/reverse", geocodeHandler.ReverseGeocode, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.GET("/zones/check", deliveryZoneHandler.CheckZone)
+	public.GET("/assets/manifest", assetHandler.GetManifest)
+	public.GET("/legal/documents", legalHandler.GetCurrentDocuments)
+	public.GET("/auth/consent", legalHandler.GetConsent, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService))
+	public.POST("/auth/consent", legalHandler.Accept, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService))
+	public.POST("/vendors/register", vendorHandler.RegisterVendor, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.GET("/vendors/me", vendorHandler.GetMyVendor, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.POST("/vendors/me/documents", vendorHandler.UploadDocument, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.GET("/features", featureFlagHandler.GetMyFeatures, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.GET("/users/me/onboarding", onboardingHandler.GetOnboarding, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.POST("/users/me/onboarding/dismiss", onboardingHandler.Dismiss, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.GET("/users/me/identity", identityHandler.GetMyVerification, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent, middleware.FeatureFlagMiddleware(featureFlagService, entity.FeatureIdentityVerification))
+	public.POST("/users/me/identity", identityHandler.SubmitVerification, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent, middleware.FeatureFlagMiddleware(featureFlagService, entity.FeatureIdentityVerification))
+	public.POST("/support/tickets", supportHandler.CreateTicket, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.GET("/support/tickets", supportHandler.GetMyTickets, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	public.GET("/support/tickets/:id", supportHandler.GetMyTicket, middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+
+	// Resumable uploads: create, send chunks in any order, then complete
+	uploads := e.Group("/api/v1/uploads", middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent)
+	uploads.POST("", uploadHandler.CreateUpload)
+	uploads.GET("/:id", uploadHandler.GetUpload)
+	uploads.PUT("/:id/chunks/:index", uploadHandler.UploadChunk)
+	uploads.POST("/:id/complete", uploadHandler.CompleteUpload)
+	uploads.DELETE("/:id", uploadHandler.AbortUpload)
+
+	// Vendor-scoped routes: vendor_id is resolved from the signed-in vendor
+	vendor := e.Group("/api/v1/vendor", middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService), requireConsent, middleware.RoleMiddleware("Vendor"), middleware.VendorScopeMiddleware(vendorService))
+	vendor.GET("/profile", vendorHandler.GetVendorProfile)
+	vendor.GET("/balance", ledgerHandler.GetBalance)
+	vendor.GET("/ledger", ledgerHandler.GetLedgerEntries)
+	vendor.GET("/withdrawals", ledgerHandler.GetMyWithdrawals)
+	vendor.POST("/withdrawals", ledgerHandler.RequestWithdrawal)
+
+	// IP rules run before JWT so leaked admin credentials fail from unknown networks
+	admin := e.Group("/api/v1/admin", middleware.AdminIPMiddleware(ipAccessService), middleware.JWTMiddleware(cfg, sessionRepo, blacklistTokenRepo, app.RoleService, passwordPolicyService))
 	admin.GET("/check", userHandler.AdminCheck)
 	admin.GET("/roles", roleHandler.GetAllRoles, middleware.SuperAdminMiddleware())
 	admin.POST("/roles", roleHandler.CreateRole, middleware.SuperAdminMiddleware())
 	admin.PUT("/roles/:id", roleHandler.UpdateRole, middleware.SuperAdminMiddleware())
+	admin.PUT("/roles/:id/parent", roleHandler.SetRoleParent, middleware.SuperAdminMiddleware())
 	admin.DELETE("/roles/:id", roleHandler.DeleteRole, middleware.SuperAdminMiddleware())
 	admin.GET("/roles/:id", roleHandler.GetRoleByID, middleware.SuperAdminMiddleware())
 	admin.GET("/customers", customerHandler.GetCustomers, middleware.SuperAdminMiddleware())
+	admin.GET("/customers/nearby", customerHandler.GetNearbyCustomers, middleware.SuperAdminMiddleware())
+	admin.GET("/customers/suggest", customerHandler.SuggestCustomers, middleware.SuperAdminMiddleware())
+	admin.POST("/customers/import", customerImportHandler.ImportCustomers, middleware.SuperAdminMiddleware())
+	admin.GET("/customers/import/reports/:id", customerImportHandler.GetErrorReport, middleware.SuperAdminMiddleware())
 	admin.GET("/customers/:id", customerHandler.GetCustomerByID, middleware.SuperAdminMiddleware())
+	// Saved filters for admin lists; each admin sees their own views plus the shared ones
+	admin.GET("/views", savedViewHandler.GetViews, middleware.SuperAdminMiddleware())
+	admin.POST("/views", savedViewHandler.CreateView, middleware.SuperAdminMiddleware())
+	admin.PUT("/views/:id", savedViewHandler.UpdateView, middleware.SuperAdminMiddleware())
+	admin.DELETE("/views/:id", savedViewHandler.DeleteView, middleware.SuperAdminMiddleware())
+	admin.POST("/users/merge", accountMergeHandler.MergeAccounts, middleware.SuperAdminMiddleware())
+	admin.GET("/users/locked", accountSecurityHandler.GetLockedAccounts, middleware.SuperAdminMiddleware())
+	admin.POST("/users/:id/unlock", accountSecurityHandler.UnlockAccount, middleware.SuperAdminMiddleware())
+	admin.GET("/zones", deliveryZoneHandler.GetAllZones, middleware.SuperAdminMiddleware())
+	admin.POST("/zones", deliveryZoneHandler.CreateZone, middleware.SuperAdminMiddleware())
+	admin.GET("/zones/:id", deliveryZoneHandler.GetZoneByID, middleware.SuperAdminMiddleware())
+	admin.PUT("/zones/:id", deliveryZoneHandler.UpdateZone, middleware.SuperAdminMiddleware())
+	admin.DELETE("/zones/:id", deliveryZoneHandler.DeleteZone, middleware.SuperAdminMiddleware())
+	admin.GET("/vendors", vendorHandler.GetVendors, middleware.SuperAdminMiddleware())
+	admin.GET("/vendors/:id", vendorHandler.GetVendorByID, middleware.SuperAdminMiddleware())
+	admin.PUT("/vendors/:id/approve", vendorHandler.ApproveVendor, middleware.SuperAdminMiddleware())
+	admin.PUT("/vendors/:id/reject", vendorHandler.RejectVendor, middleware.SuperAdminMiddleware())
+	admin.POST("/vendors/:id/earnings", ledgerHandler.RecordOrderEarning, middleware.SuperAdminMiddleware())
+	admin.GET("/withdrawals", ledgerHandler.GetWithdrawals, middleware.SuperAdminMiddleware())
+	admin.PUT("/withdrawals/:id/approve", ledgerHandler.ApproveWithdrawal, middleware.SuperAdminMiddleware())
+	admin.PUT("/withdrawals/:id/reject", ledgerHandler.RejectWithdrawal, middleware.SuperAdminMiddleware())
+	admin.GET("/ledger/audit", ledgerHandler.AuditLedger, middleware.SuperAdminMiddleware())
+	admin.GET("/jobs", jobHandler.GetJobs, middleware.SuperAdminMiddleware())
+	admin.POST("/jobs/:id/retry", jobHandler.RetryJob, middleware.SuperAdminMiddleware())
+	admin.GET("/feature-flags", featureFlagHandler.GetAllFlags, middleware.SuperAdminMiddleware())
+	admin.POST("/feature-flags", featureFlagHandler.CreateFlag, middleware.SuperAdminMiddleware())
+	admin.GET("/feature-flags/:key", featureFlagHandler.GetFlag, middleware.SuperAdminMiddleware())
+	admin.PUT("/feature-flags/:key", featureFlagHandler.UpdateFlag, middleware.SuperAdminMiddleware())
+	admin.DELETE("/feature-flags/:key", featureFlagHandler.DeleteFlag, middleware.SuperAdminMiddleware())
+	admin.PUT("/feature-flags/:key/overrides/:user_id", featureFlagHandler.SetOverride, middleware.SuperAdminMiddleware())
+	admin.DELETE("/feature-flags/:key/overrides/:user_id", featureFlagHandler.DeleteOverride, middleware.SuperAdminMiddleware())
+	admin.GET("/ip-rules", ipAccessHandler.GetRules, middleware.SuperAdminMiddleware())
+	admin.POST("/ip-rules", ipAccessHandler.AddRule, middleware.SuperAdminMiddleware())
+	admin.DELETE("/ip-rules", ipAccessHandler.RemoveRule, middleware.SuperAdminMiddleware())
+	admin.GET("/audit-logs", auditLogHandler.GetAuditLogs, middleware.SuperAdminMiddleware())
+	admin.GET("/log-level", logLevelHandler.GetLevel, middleware.SuperAdminMiddleware())
+	admin.PUT("/log-level", logLevelHandler.SetLevel, middleware.SuperAdminMiddleware())
+	admin.GET("/trash/users", trashHandler.GetDeletedUsers, middleware.SuperAdminMiddleware())
+	admin.POST("/trash/users/:id/restore", trashHandler.RestoreUser, middleware.SuperAdminMiddleware())
+	admin.DELETE("/trash/users/:id", trashHandler.PurgeUser, middleware.SuperAdminMiddleware())
+	admin.GET("/trash/roles", trashHandler.GetDeletedRoles, middleware.SuperAdminMiddleware())
+	admin.POST("/trash/roles/:id/restore", trashHandler.RestoreRole, middleware.SuperAdminMiddleware())
+	admin.DELETE("/trash/roles/:id", trashHandler.PurgeRole, middleware.SuperAdminMiddleware())
+	admin.GET("/webhooks", webhookHandler.GetEndpoints, middleware.SuperAdminMiddleware())
+	admin.POST("/webhooks", webhookHandler.CreateEndpoint, middleware.SuperAdminMiddleware())
+	admin.GET("/webhooks/:id", webhookHandler.GetEndpoint, middleware.SuperAdminMiddleware())
+	admin.PUT("/webhooks/:id", webhookHandler.UpdateEndpoint, middleware.SuperAdminMiddleware())
+	admin.DELETE("/webhooks/:id", webhookHandler.DeleteEndpoint, middleware.SuperAdminMiddleware())
+	admin.GET("/webhooks/:id/deliveries", webhookHandler.GetDeliveries, middleware.SuperAdminMiddleware())
+	admin.POST("/webhooks/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver, middleware.SuperAdminMiddleware())
+	admin.GET("/photo-moderations", photoModerationHandler.GetModerations, middleware.SuperAdminMiddleware())
+	admin.GET("/photo-moderations/:id", photoModerationHandler.GetModeration, middleware.SuperAdminMiddleware())
+	admin.PUT("/photo-moderations/:id/approve", photoModerationHandler.Approve, middleware.SuperAdminMiddleware())
+	admin.PUT("/photo-moderations/:id/remove", photoModerationHandler.Remove, middleware.SuperAdminMiddleware())
+	admin.GET("/api-keys", apiKeyHandler.GetAPIKeys, middleware.SuperAdminMiddleware())
+	admin.POST("/api-keys", apiKeyHandler.CreateAPIKey, middleware.SuperAdminMiddleware())
+	admin.GET("/api-keys/:id", apiKeyHandler.GetAPIKey, middleware.SuperAdminMiddleware())
+	admin.POST("/api-keys/:id/revoke", apiKeyHandler.RevokeAPIKey, middleware.SuperAdminMiddleware())
+	admin.GET("/invites", inviteHandler.GetInvites, middleware.SuperAdminMiddleware())
+	admin.POST("/invites", inviteHandler.CreateInvite, middleware.SuperAdminMiddleware())
+	admin.DELETE("/invites/:id", inviteHandler.RevokeInvite, middleware.SuperAdminMiddleware())
+	admin.GET("/legal/documents", legalHandler.GetDocuments, middleware.SuperAdminMiddleware())
+	admin.POST("/legal/documents", legalHandler.PublishDocument, middleware.SuperAdminMiddleware())
+	admin.GET("/users/:id/consents", legalHandler.GetUserConsents, middleware.SuperAdminMiddleware())
+	admin.GET("/identity-verifications", identityHandler.GetVerifications, middleware.SuperAdminMiddleware())
+	admin.GET("/identity-verifications/:id", identityHandler.GetVerification, middleware.SuperAdminMiddleware())
+	admin.PUT("/identity-verifications/:id/approve", identityHandler.Approve, middleware.SuperAdminMiddleware())
+	admin.PUT("/identity-verifications/:id/reject", identityHandler.Reject, middleware.SuperAdminMiddleware())
+	admin.GET("/support/tickets", supportHandler.GetTickets, middleware.SuperAdminMiddleware())
+	admin.GET("/support/tickets/:id", supportHandler.GetTicket, middleware.SuperAdminMiddleware())
+	admin.PUT("/support/tickets/:id/assign", supportHandler.Assign, middleware.SuperAdminMiddleware())
+	admin.PUT("/support/tickets/:id/status", supportHandler.UpdateStatus, middleware.SuperAdminMiddleware())
+	admin.GET("/email-rate-limits", emailRateLimitHandler.GetLimits, middleware.SuperAdminMiddleware())
+	admin.PUT("/email-rate-limits", emailRateLimitHandler.SetLimit, middleware.SuperAdminMiddleware())
+	admin.DELETE("/email-rate-limits/:email_type", emailRateLimitHandler.DeleteLimit, middleware.SuperAdminMiddleware())
+	admin.GET("/segments", segmentHandler.GetSegments, middleware.SuperAdminMiddleware())
+	admin.POST("/segments", segmentHandler.CreateSegment, middleware.SuperAdminMiddleware())
+	admin.GET("/segments/:id", segmentHandler.GetSegment, middleware.SuperAdminMiddleware())
+	admin.DELETE("/segments/:id", segmentHandler.DeleteSegment, middleware.SuperAdminMiddleware())
+	admin.POST("/segments/:id/refresh", segmentHandler.RefreshSegment, middleware.SuperAdminMiddleware())
+	admin.POST("/segments/:id/campaigns", segmentHandler.LaunchCampaign, middleware.SuperAdminMiddleware())
+	admin.GET("/assets", assetHandler.GetAssets, middleware.SuperAdminMiddleware())
+	admin.POST("/assets", assetHandler.UploadAsset, middleware.SuperAdminMiddleware())
+
+	// Partner (B2B) endpoints, authenticated by X-API-Key and limited to the key's scopes
+	partner := e.Group("/api/v1/partner", middleware.APIKeyMiddleware(apiKeyService))
+	partner.GET("/customers", customerHandler.GetCustomers, middleware.RequireAPIKeyScope(entity.APIKeyScopeCustomersRead))
+	partner.GET("/customers/:id", customerHandler.GetCustomerByID, middleware.RequireAPIKeyScope(entity.APIKeyScopeCustomersRead))
+	partner.GET("/webhooks", webhookHandler.GetEndpoints, middleware.RequireAPIKeyScope(entity.APIKeyScopeWebhooksManage))
+	partner.POST("/webhooks", webhookHandler.CreateEndpoint, middleware.RequireAPIKeyScope(entity.APIKeyScopeWebhooksManage))
+	partner.GET("/webhooks/:id", webhookHandler.GetEndpoint, middleware.RequireAPIKeyScope(entity.APIKeyScopeWebhooksManage))
+	partner.PUT("/webhooks/:id", webhookHandler.UpdateEndpoint, middleware.RequireAPIKeyScope(entity.APIKeyScopeWebhooksManage))
+	partner.DELETE("/webhooks/:id", webhookHandler.DeleteEndpoint, middleware.RequireAPIKeyScope(entity.APIKeyScopeWebhooksManage))
+	partner.GET("/webhooks/:id/deliveries", webhookHandler.GetDeliveries, middleware.RequireAPIKeyScope(entity.APIKeyScopeWebhooksManage))
+	partner.DELETE("/api-key", apiKeyHandler.RevokeOwnAPIKey)
+
+	// SCIM 2.0 provisioning for identity providers, authenticated by an API key with the scim:provision scope
+	scim := e.Group("/scim/v2", middleware.SCIMAuthMiddleware(apiKeyService))
+	scim.GET("/ServiceProviderConfig", scimHandler.GetServiceProviderConfig)
+	scim.GET("/ResourceTypes", scimHandler.GetResourceTypes)
+	scim.GET("/Users", scimHandler.GetUsers)
+	scim.POST("/Users", scimHandler.CreateUser)
+	scim.GET("/Users/:id", scimHandler.GetUser)
+	scim.PUT("/Users/:id", scimHandler.ReplaceUser)
+	scim.PATCH("/Users/:id", scimHandler.PatchUser)
+	scim.DELETE("/Users/:id", scimHandler.DeleteUser)
+	scim.GET("/Groups", scimHandler.GetGroups)
+	scim.GET("/Groups/:id", scimHandler.GetGroup)
+	scim.PUT("/Groups/:id", scimHandler.ReplaceGroup)
+	scim.PATCH("/Groups/:id", scimHandler.PatchGroup)
+
+	// Service-to-service endpoints; never exposed through the public gateway
+	internalAPI := e.Group("/internal", middleware.ServiceAuthMiddleware(cfg))
+	internalAPI.POST("/users/batch", internalHandler.BatchGetUsers)
+	internalAPI.POST("/auth/introspect", internalHandler.IntrospectToken)
+	internalAPI.POST("/emails/bounces", internalHandler.RecordEmailBounce, middleware.RequireServices("notification-service"))
+	internalAPI.POST("/users/:id/first-order", onboardingHandler.RecordFirstOrder, middleware.RequireServices("order-service"))
+	internalAPI.POST("/users/:id/orders", segmentHandler.RecordOrder, middleware.RequireServices("order-service"))
+	internalAPI.POST("/webhooks/events", webhookHandler.DispatchEvent, middleware.RequireServices("order-service"))
+	internalAPI.GET("/users/:id/identity", identityHandler.GetUserIdentity, middleware.RequireServices("order-service"))
 
 	// Root endpoint - redirect to health
 	e.GET("/", func(c echo.Context) error {
 		return c.JSON(200, map[string]string{
 			"message": "User Service API",
-			"version": "1.0.0",
-			"health": "/health",
-			"docs": "/api/v1",
+			"version": buildinfo.Version,
+			"health":  "/health",
+			"docs":    "/api/v1",
 		})
 	})
 
+	// Build info, stamped through -ldflags (see internal/buildinfo)
+	e.GET("/version", versionHandler)
+
+	// Prometheus metrics (circuit breaker state and call results)
+	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
+
 	// Health check
 	e.GET("/health", func(c echo.Context) error {
+		// RabbitMQ being down does not make the instance unhealthy; emails are buffered meanwhile
+		rabbitMQStatus := "connected"
+		if !app.RabbitMQ.Healthy() {
+			rabbitMQStatus = "buffering"
+		}
 		return c.JSON(200, map[string]string{
-			"status": "healthy",
+			"status":   "healthy",
+			"service":  "user-service",
+			"rabbitmq": rabbitMQStatus,
+		})
+	})
+
+	serve(e, cfg.App.AppPort, func(ctx context.Context) {
+		stopBroker()
+		jobWorker.Stop(ctx)
+		stopDiagnostics(ctx)
+	})
+}
+
+func versionHandler(c echo.Context) error {
+	info := buildinfo.Get()
+	return c.JSON(200, map[string]string{
+		"service":    "user-service",
+		"version":    info.Version,
+		"git_commit": info.GitCommit,
+		"build_time": info.BuildTime,
+		"go_version": info.GoVersion,
+		"platform":   info.Platform,
+	})
+}
+
+// registerDegradedRoutes answers every request with 503 so load balancers and
+// orchestrators see the instance as unhealthy and restart it
+func registerDegradedRoutes(e *echo.Echo, cause error) {
+	e.GET("/version", versionHandler)
+	e.GET("/health", func(c echo.Context) error {
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"status":  "degraded",
 			"service": "user-service",
+			"error":   "database unavailable",
+		})
+	})
+	e.Any("/*", func(c echo.Context) error {
+		log.Printf("⚠️  Rejecting %s %s in degraded mode: %v", c.Request().Method, c.Request().URL.Path, cause)
+		return c.JSON(http.StatusServiceUnavailable, map[string]string{
+			"message": "Service unavailable, please try again later",
 		})
 	})
+}
 
+// serve starts the server and blocks until SIGINT/SIGTERM, then shuts down gracefully
+func serve(e *echo.Echo, port string, stop func(ctx context.Context)) {
 	// Start server in a goroutine
 	go func() {
-		serverAddr := fmt.Sprintf(":%s", cfg.App.AppPort)
+		serverAddr := fmt.Sprintf(":%s", port)
 		log.Printf("🚀 User Service starting on %s", serverAddr)
 		log.Printf("📚 API Documentation: http://localhost%s/api/v1", serverAddr)
 
@@ -172,40 +775,140 @@ func RunServer() {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
 
+	stop(ctx)
+
 	log.Println("Server exiting")
 }
 
+// circuitBreakerSettings defaults to opening after 5 consecutive failures for 30s, with 1 trial call
+// httpClientOptions applies the HTTP_CLIENT_* retry policy to the client called name
+func httpClientOptions(cfg *config.Config, name string, timeout time.Duration) httpclient.Options {
+	return httpclient.Options{
+		Name:        name,
+		Timeout:     timeout,
+		MaxRetries:  cfg.HTTPClient.MaxRetries,
+		BaseBackoff: time.Duration(cfg.HTTPClient.RetryBaseMs) * time.Millisecond,
+		MaxBackoff:  time.Duration(cfg.HTTPClient.RetryMaxMs) * time.Millisecond,
+	}
+}
+
+func circuitBreakerSettings(cfg *config.Config) breaker.Settings {
+	settings := breaker.Settings{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+		HalfOpenRequests: 1,
+	}
+	if cfg.CircuitBreaker.FailureThreshold > 0 {
+		settings.FailureThreshold = uint32(cfg.CircuitBreaker.FailureThreshold)
+	}
+	if cfg.CircuitBreaker.OpenSeconds > 0 {
+		settings.OpenTimeout = time.Duration(cfg.CircuitBreaker.OpenSeconds) * time.Second
+	}
+	if cfg.CircuitBreaker.HalfOpenRequests > 0 {
+		settings.HalfOpenRequests = uint32(cfg.CircuitBreaker.HalfOpenRequests)
+	}
+	return settings
+}
+
+// startupPolicy reads the dependency retry schedule, defaulting to 60s total with 0.5s..10s backoff
+func startupPolicy(cfg *config.Config) startup.Policy {
+	policy := startup.Policy{
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Timeout:        60 * time.Second,
+	}
+	if cfg.Startup.InitialBackoffMs > 0 {
+		policy.InitialBackoff = time.Duration(cfg.Startup.InitialBackoffMs) * time.Millisecond
+	}
+	if cfg.Startup.MaxBackoffSeconds > 0 {
+		policy.MaxBackoff = time.Duration(cfg.Startup.MaxBackoffSeconds) * time.Second
+	}
+	if cfg.Startup.TimeoutSeconds > 0 {
+		policy.Timeout = time.Duration(cfg.Startup.TimeoutSeconds) * time.Second
+	}
+	return policy
+}
+
 func NewApp(cfg *config.Config) (*App, error) {
-	// Initialize database connection
-	db, err := cfg.ConnectionPostgres()
-	if err != nil {
-		log.Fatalf("[RunServer-1] %v", err)
+	// Wait for Postgres, Redis and RabbitMQ together so docker-compose start order does not matter
+	var db *config.Postgres
+	var rabbitMQChannel *amqp.Channel
+	redisClient := cfg.RedisClient()
+
+	report := startup.WaitFor(context.Background(), startupPolicy(cfg),
+		startup.Dependency{Name: "postgres", Connect: func(ctx context.Context) error {
+			var err error
+			db, err = cfg.ConnectionPostgres()
+			return err
+		}},
+		startup.Dependency{Name: "redis", Connect: func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		}},
+		startup.Dependency{Name: "rabbitmq", Connect: func(ctx context.Context) error {
+			var err error
+			rabbitMQChannel, err = cfg.ConnectionRabbitMQ()
+			return err
+		}},
+	)
+
+	if !report.Ready("postgres") {
+		return nil, report.Failed["postgres"]
+	}
+
+	if err := repository.RegisterOptimisticLocking(db.DB); err != nil {
+		log.Fatalf("[RunServer-1] Failed to register optimistic locking: %v", err)
 		return nil, err
 	}
 
-	// Initialize Redis client
-	redisClient := cfg.RedisClient()
+	if err := repository.RegisterQueryTimeout(db.DB, cfg.Timeouts.DB()); err != nil {
+		log.Fatalf("[RunServer-1] Failed to register query timeout: %v", err)
+		return nil, err
+	}
 
-	// Initialize RabbitMQ connection
-	rabbitMQChannel, err := cfg.ConnectionRabbitMQ()
-	if err != nil {
-		log.Printf("⚠️  RabbitMQ not available: %v", err)
-		log.Printf("💡 Email verification will not work until RabbitMQ is started")
+	if err := repository.RegisterQueryTracing(db.DB, cfg.QueryTrace.CommentsEnabled(), cfg.QueryTrace.CommentRequestID()); err != nil {
+		log.Fatalf("[RunServer-1] Failed to register query tracing: %v", err)
+		return nil, err
+	}
+
+	if err := repository.RegisterPIIEncryption(cfg.PIIEncryption); err != nil {
+		log.Fatalf("[RunServer-1] Failed to register PII encryption: %v", err)
+		return nil, err
+	}
+
+	// go-redis reconnects on its own, so a late Redis only fails the requests made before it is up
+	if !report.Ready("redis") {
+		log.Printf("⚠️  Redis not available: %v", report.Failed["redis"])
+		log.Printf("💡 Sessions and rate limits will fail until Redis is reachable")
+	}
+
+	if !report.Ready("rabbitmq") {
+		log.Printf("⚠️  RabbitMQ not available: %v", report.Failed["rabbitmq"])
+		log.Printf("💡 Emails are buffered in the job queue and sent once RabbitMQ is reachable")
 	}
 
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db.DB)
 	roleRepo := repository.NewRoleRepository(db.DB)
 	sessionRepo := repository.NewSessionRepository(redisClient, cfg)
-	blacklistTokenRepo := repository.NewBlacklistTokenRepository(db.DB)
+	blacklistTokenRepo, err := repository.NewBlacklistTokenBackend(cfg, db.DB, redisClient)
+	if err != nil {
+		log.Fatalf("[RunServer-1] Failed to select blacklist backend: %v", err)
+		return nil, err
+	}
+	deliveryZoneRepo := repository.NewDeliveryZoneRepository(db.DB)
+	deviceRepo := repository.NewDeviceRepository(db.DB)
+	inviteRepo := repository.NewInviteRepository(db.DB)
+	legalRepo := repository.NewLegalRepository(db.DB)
+	auditLogRepo := repository.NewAuditLogRepository(db.DB)
 
 	// Initialize utilities
 	jwtUtil := utils.NewJWTUtil(cfg)
 
-	// Initialize message publishers
+	// Initialize message publishers; the broker reconnects once RunServer starts it
+	rabbitMQ := message.NewBroker(rabbitMQChannel, cfg.ConnectionRabbitMQ, time.Duration(cfg.RabbitMQ.ReconnectSeconds)*time.Second)
 	var emailPublisher port.EmailInterface
 	if rabbitMQChannel != nil {
-		emailPublisher = message.NewEmailPublisher(rabbitMQChannel)
+		emailPublisher = message.NewEmailPublisher(message.NewEmailOutbox(rabbitMQ, nil, nil), service.TokenLifetimesFromConfig(cfg), userRepo, cfg.App.LinkBaseURL())
 	}
 
 	// Initialize storage (Supabase Storage)
@@ -213,25 +916,46 @@ func NewApp(cfg *config.Config) (*App, error) {
 		cfg.Supabase.ProjectURL,
 		cfg.Supabase.APIKey,
 		cfg.Supabase.BucketName,
+		cfg.Timeouts.Storage(),
+		cfg.Supabase.MaxUploadSize(),
+		httpclient.New(httpClientOptions(cfg, "storage", 0)),
 	)
 	if err != nil {
 		log.Printf("⚠️  Supabase Storage not available: %v", err)
 		log.Printf("💡 Image upload will not work until Supabase is configured")
 		supabaseStorage = nil
+	} else {
+		supabaseStorage = storage.NewContentAddressedStorage(supabaseStorage, repository.NewStoredObjectRepository(db.DB), cfg.Supabase.BucketName)
 	}
 
 	// Initialize services
-	userService := service.NewUserService(userRepo, sessionRepo, jwtUtil, nil, emailPublisher, blacklistTokenRepo, supabaseStorage, cfg)
 	roleService := service.NewRoleService(roleRepo)
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:           userRepo,
+			SessionRepo:        sessionRepo,
+			JWTUtil:            jwtUtil,
+			EmailPublisher:     emailPublisher,
+			BlacklistTokenRepo: blacklistTokenRepo,
+			Storage:            supabaseStorage,
+			ZoneRepo:           deliveryZoneRepo,
+			DeviceRepo:         deviceRepo,
+			Roles:              roleService,
+		},
+		Invites: service.NewInviteService(inviteRepo),
+		Legal:   service.NewLegalService(legalRepo, service.NewAuditLogService(auditLogRepo)),
+		Config:  cfg,
+	})
 
 	return &App{
-		UserService:     userService,
-		UserRepo:        userRepo,
-		RoleService:     roleService,
-		RoleRepo:        roleRepo,
-		JWTUtil:         jwtUtil,
-		DB:              db.DB,
-		RabbitMQChannel: rabbitMQChannel,
+		UserService:   userService,
+		UserRepo:      userRepo,
+		RoleService:   roleService,
+		RoleRepo:      roleRepo,
+		JWTUtil:       jwtUtil,
+		BlacklistRepo: blacklistTokenRepo,
+		DB:            db.DB,
+		RabbitMQ:      rabbitMQ,
 	}, nil
 }
 
@@ -0,0 +1,59 @@
+// Package buildinfo holds the version stamped into the binary at build time:
+//
+//	go build -ldflags "-X user-service/internal/buildinfo.Version=1.4.0 \
+//	  -X user-service/internal/buildinfo.GitCommit=$(git rev-parse --short HEAD) \
+//	  -X user-service/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Set through -ldflags; the defaults are what a plain `go run` reports
+var (
+	Version   = "0.0.0-dev"
+	GitCommit = ""
+	BuildTime = ""
+)
+
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// Get returns the build info. Without ldflags the commit and time fall back to the VCS
+// stamp the Go toolchain embeds when building inside a git checkout.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+
+	if info.GitCommit == "" || info.BuildTime == "" {
+		if build, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range build.Settings {
+				switch {
+				case setting.Key == "vcs.revision" && info.GitCommit == "":
+					info.GitCommit = setting.Value
+				case setting.Key == "vcs.time" && info.BuildTime == "":
+					info.BuildTime = setting.Value
+				}
+			}
+		}
+	}
+
+	if info.GitCommit == "" {
+		info.GitCommit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
@@ -0,0 +1,19 @@
+package entity
+
+import "time"
+
+// AccountMergeEntity summarises what a merge moved from the duplicate (source)
+// account to the surviving (target) account
+type AccountMergeEntity struct {
+	SourceUserID          int64
+	TargetUserID          int64
+	SourceEmail           string
+	TargetEmail           string
+	ProfileFieldsCopied   []string
+	DevicesMoved          int64
+	FeatureOverridesMoved int64
+	VendorMoved           bool
+	OnboardingMerged      bool
+	DryRun                bool
+	MergedAt              time.Time
+}
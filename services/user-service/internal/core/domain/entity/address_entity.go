@@ -0,0 +1,12 @@
+package entity
+
+// AddressEntity holds a geocoded location with its structured address components
+type AddressEntity struct {
+	Address    string  `json:"address"`
+	Province   string  `json:"province"`
+	City       string  `json:"city"`
+	District   string  `json:"district"`
+	PostalCode string  `json:"postal_code"`
+	Lat        float64 `json:"lat"`
+	Lng        float64 `json:"lng"`
+}
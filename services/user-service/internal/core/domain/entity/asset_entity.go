@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// AssetEntity is an image email templates refer to by Name. URL changes whenever the
+// content does, so it can be cached forever.
+type AssetEntity struct {
+	Name        string
+	ObjectName  string
+	Hash        string
+	ContentType string
+	Size        int64
+	URL         string
+	UploadedBy  int64
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,68 @@
+package entity
+
+import "time"
+
+// Security events recorded in the audit log
+const (
+	AuditEventSignInFailed    = "signin.failed"
+	AuditEventSignInRisk      = "signin.risk_assessed"
+	AuditEventStepUpRequired  = "signin.step_up_required"
+	AuditEventStepUpVerified  = "signin.step_up_verified"
+	AuditEventStepUpFailed    = "signin.step_up_failed"
+	AuditEventStepUpLockedOut = "signin.step_up_locked_out"
+	AuditEventAccountMerged   = "account.merged"
+	AuditEventRecordRestored  = "record.restored"
+	AuditEventRecordPurged    = "record.purged"
+	AuditEventUploadInfected  = "upload.infected"
+
+	AuditEventEmailChangeReverted = "account.email_change_reverted"
+	AuditEventAccountUnlocked     = "account.unlocked"
+
+	AuditEventAPIKeyCreated = "api_key.created"
+	AuditEventAPIKeyRevoked = "api_key.revoked"
+
+	AuditEventCampaignLaunched = "campaign.launched"
+	AuditEventAssetUploaded    = "asset.uploaded"
+	AuditEventLegalPublished   = "legal.published"
+
+	AuditEventIdentityApproved = "identity.approved"
+	AuditEventIdentityRejected = "identity.rejected"
+
+	AuditEventSupportTicketAssigned = "support.ticket_assigned"
+	AuditEventSupportTicketStatus   = "support.ticket_status_changed"
+
+	AuditEventEmailRateLimitChanged = "email_rate_limit.changed"
+
+	// Written by SCIM provisioning; metadata.api_key_id is the identity provider's key
+	AuditEventSCIMUserProvisioned = "scim.user_provisioned"
+	AuditEventSCIMUserUpdated     = "scim.user_updated"
+	AuditEventSCIMUserDeactivated = "scim.user_deactivated"
+	AuditEventSCIMUserDeleted     = "scim.user_deleted"
+	AuditEventSCIMGroupChanged    = "scim.group_membership_changed"
+
+	// Written by OpenID Connect sign-in; metadata.subject is the provider's subject
+	AuditEventSSOSignIn          = "sso.signed_in"
+	AuditEventSSOUserProvisioned = "sso.user_provisioned"
+	AuditEventSSORoleChanged     = "sso.role_changed"
+	AuditEventSSOSignInRefused   = "sso.signin_refused"
+
+	// Written by cmd/adminctl; metadata.operator is who ran it
+	AuditEventRoleChanged      = "account.role_changed"
+	AuditEventVerifiedManually = "account.verified_manually"
+	AuditEventSessionsRevoked  = "account.sessions_revoked"
+)
+
+type AuditLogEntity struct {
+	ID        int64
+	UserID    int64
+	Event     string
+	IPAddress string
+	UserAgent string
+	Metadata  map[string]interface{}
+	CreatedAt time.Time
+}
+
+type AuditLogFilter struct {
+	UserID int64
+	Event  string
+}
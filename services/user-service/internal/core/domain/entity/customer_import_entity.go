@@ -0,0 +1,28 @@
+package entity
+
+// CustomerImportRowEntity is one customer read from the import CSV
+type CustomerImportRowEntity struct {
+	Row     int
+	Name    string
+	Email   string
+	Phone   string
+	Address string
+}
+
+type CustomerImportRowError struct {
+	Row     int
+	Email   string
+	Message string
+}
+
+type CustomerImportResultEntity struct {
+	TotalRows   int
+	Valid       int
+	Imported    int
+	InvitesSent int
+	Failed      int
+	DryRun      bool
+	// Errors holds the first row errors; the full list is in the downloadable report
+	Errors   []CustomerImportRowError
+	ReportID string
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+	"user-service/utils"
+)
+
+const (
+	ZoneTypeRadius  = "radius"
+	ZoneTypePolygon = "polygon"
+)
+
+type GeoPointEntity struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+type DeliveryZoneEntity struct {
+	ID        int64
+	Name      string
+	ZoneType  string
+	CenterLat float64
+	CenterLng float64
+	RadiusKm  float64
+	Polygon   []GeoPointEntity
+	IsActive  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Contains reports whether the point lies inside the zone
+func (z DeliveryZoneEntity) Contains(lat, lng float64) bool {
+	switch z.ZoneType {
+	case ZoneTypeRadius:
+		return utils.HaversineKm(z.CenterLat, z.CenterLng, lat, lng) <= z.RadiusKm
+	case ZoneTypePolygon:
+		vertices := make([][2]float64, len(z.Polygon))
+		for i, p := range z.Polygon {
+			vertices[i] = [2]float64{p.Lat, p.Lng}
+		}
+		return utils.PointInPolygon(lat, lng, vertices)
+	default:
+		return false
+	}
+}
@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// ClientEntity describes where a request came from
+type ClientEntity struct {
+	UserAgent string
+	IPAddress string
+	// DeviceID is an optional stable identifier sent by mobile apps (X-Device-ID)
+	DeviceID string
+}
+
+// Fingerprint identifies the device independently of its IP, which changes between networks
+func (c ClientEntity) Fingerprint() string {
+	source := "ua:" + strings.ToLower(strings.TrimSpace(c.UserAgent))
+	if deviceID := strings.TrimSpace(c.DeviceID); deviceID != "" {
+		source = "id:" + deviceID
+	}
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:])
+}
+
+type DeviceEntity struct {
+	ID          int64
+	UserID      int64
+	Fingerprint string
+	UserAgent   string
+	LastIP      string
+	Trusted     bool
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
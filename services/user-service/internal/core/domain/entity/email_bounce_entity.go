@@ -0,0 +1,7 @@
+package entity
+
+// Bounce types reported for a sent email; only a hard bounce makes the address undeliverable
+const (
+	EmailBounceHard = "hard"
+	EmailBounceSoft = "soft"
+)
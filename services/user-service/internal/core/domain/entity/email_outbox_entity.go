@@ -0,0 +1,14 @@
+package entity
+
+import "encoding/json"
+
+// JobTypePublishEmail holds an email in the job queue until RabbitMQ accepts it
+const JobTypePublishEmail = "email.publish"
+
+// BufferedEmailEntity is an email_queue message kept while RabbitMQ was unavailable
+type BufferedEmailEntity struct {
+	Exchange    string          `json:"exchange"`
+	RoutingKey  string          `json:"routing_key"`
+	ContentType string          `json:"content_type"`
+	Body        json.RawMessage `json:"body"`
+}
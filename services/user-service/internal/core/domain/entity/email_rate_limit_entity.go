@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// EmailRateLimitAllEmails is the email type of the quota shared by every email to one user
+const EmailRateLimitAllEmails = "*"
+
+// EmailRateLimitEntity is an hourly quota per recipient; MaxPerHour 0 exempts the email type
+type EmailRateLimitEntity struct {
+	EmailType  string
+	MaxPerHour int
+	UpdatedBy  int64
+	UpdatedAt  time.Time
+}
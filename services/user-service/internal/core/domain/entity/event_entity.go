@@ -0,0 +1,22 @@
+package entity
+
+import "time"
+
+// JobTypePublishEvent delivers a domain event through the job queue so publishing is retried
+const JobTypePublishEvent = "events.publish"
+
+const (
+	EventUserOnboardingCompleted = "user.onboarding_completed"
+	// EventUserVerified follows a successful email verification; notification-service sends the welcome email
+	EventUserVerified = "user.verified"
+	// EventUserMerged tells services that own user data (orders, carts) to reassign it to the surviving account
+	EventUserMerged = "user.merged"
+)
+
+// EventEntity is the envelope published to the user_events exchange
+type EventEntity struct {
+	ID         string      `json:"event_id"`
+	Type       string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+type FeatureFlagEntity struct {
+	ID                int64
+	Key               string
+	Description       string
+	Enabled           bool
+	RolloutPercentage int
+	// Overrides maps user ID to a forced on/off value that wins over the rollout
+	Overrides map[int64]bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// IsEnabledFor evaluates the flag for a user. userID 0 means anonymous, which only
+// sees flags rolled out to everyone.
+func (f *FeatureFlagEntity) IsEnabledFor(userID int64) bool {
+	if enabled, ok := f.Overrides[userID]; ok && userID > 0 {
+		return enabled
+	}
+
+	if !f.Enabled || f.RolloutPercentage <= 0 {
+		return false
+	}
+	if f.RolloutPercentage >= 100 {
+		return true
+	}
+	if userID <= 0 {
+		return false
+	}
+
+	return RolloutBucket(f.Key, userID) < f.RolloutPercentage
+}
+
+// RolloutBucket places a user in 0-99 for a flag. Hashing the key with the user keeps a
+// user's bucket stable as the percentage grows, without every flag picking the same users.
+func RolloutBucket(key string, userID int64) int {
+	h := fnv.New32a()
+	fmt.Fprintf(h, "%s:%d", key, userID)
+	return int(h.Sum32() % 100)
+}
@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+// FeatureIdentityVerification is the feature flag that opens identity verification to users
+const FeatureIdentityVerification = "identity_verification"
+
+const (
+	IdentityVerificationPending  = "pending"
+	IdentityVerificationApproved = "approved"
+	IdentityVerificationRejected = "rejected"
+	// IdentityVerificationExpired is never stored; it describes an approval past ExpiresAt
+	IdentityVerificationExpired = "expired"
+	// IdentityVerificationNone is reported for users who never submitted a document
+	IdentityVerificationNone = "none"
+)
+
+type IdentityVerificationEntity struct {
+	ID              int64
+	UserID          int64
+	DocumentType    string
+	ObjectName      string
+	ContentType     string
+	DateOfBirth     time.Time
+	Status          string
+	RejectionReason string
+	ReviewedBy      int64
+	ReviewedAt      *time.Time
+	ExpiresAt       *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	// DocumentURL is a short-lived signed link, only filled for admin review
+	DocumentURL string
+}
+
+// IdentityStatusEntity is what a user, or a service selling restricted goods, needs to know
+type IdentityStatusEntity struct {
+	Status          string
+	Verified        bool
+	AgeVerified     bool
+	ExpiresAt       *time.Time
+	RejectionReason string
+	SubmittedAt     *time.Time
+}
+
+// AgeOn returns the age in whole years on the given day
+func AgeOn(dateOfBirth, day time.Time) int {
+	age := day.Year() - dateOfBirth.Year()
+	if day.Month() < dateOfBirth.Month() || (day.Month() == dateOfBirth.Month() && day.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// InviteEntity is a sign-up code for invite-only registration; it works until MaxUses
+// accounts were created with it, it expires or it is revoked
+type InviteEntity struct {
+	ID        int64
+	Code      string
+	Note      string
+	MaxUses   int
+	Uses      int
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+	CreatedBy int64
+	CreatedAt time.Time
+}
+
+func (i *InviteEntity) IsExpired(now time.Time) bool {
+	return i.ExpiresAt != nil && !now.Before(*i.ExpiresAt)
+}
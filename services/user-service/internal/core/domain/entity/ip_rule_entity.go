@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+const (
+	IPRuleListAllow = "allow"
+	IPRuleListDeny  = "deny"
+)
+
+type IPRuleEntity struct {
+	CIDR      string    `json:"cidr"`
+	ListType  string    `json:"list_type"`
+	Note      string    `json:"note"`
+	CreatedBy int64     `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+	// Static rules come from ADMIN_IP_ALLOWLIST and cannot be removed through the API
+	Static bool `json:"static"`
+}
@@ -0,0 +1,92 @@
+package entity
+
+import "time"
+
+// Ledger accounts. Entries are signed: positive credits, negative debits.
+const (
+	LedgerAccountPlatformCash        = "platform_cash"
+	LedgerAccountPlatformCommission  = "platform_commission"
+	LedgerAccountVendorPayable       = "vendor_payable"
+	LedgerAccountVendorPayoutPending = "vendor_payout_pending"
+)
+
+const (
+	LedgerTransactionOrderEarning      = "order_earning"
+	LedgerTransactionWithdrawalHold    = "withdrawal_hold"
+	LedgerTransactionWithdrawalPayout  = "withdrawal_payout"
+	LedgerTransactionWithdrawalRelease = "withdrawal_release"
+)
+
+const (
+	WithdrawalStatusPending  = "pending"
+	WithdrawalStatusApproved = "approved"
+	WithdrawalStatusRejected = "rejected"
+)
+
+type LedgerTransactionEntity struct {
+	ID          int64
+	VendorID    int64
+	Type        string
+	ReferenceID string
+	Description string
+	CreatedBy   int64
+	Entries     []LedgerEntryEntity
+	CreatedAt   time.Time
+}
+
+// IsBalanced reports whether the entries net to zero, which every transaction must
+func (t *LedgerTransactionEntity) IsBalanced() bool {
+	if len(t.Entries) < 2 {
+		return false
+	}
+
+	var total int64
+	for _, entry := range t.Entries {
+		if entry.Amount == 0 {
+			return false
+		}
+		total += entry.Amount
+	}
+
+	return total == 0
+}
+
+type LedgerEntryEntity struct {
+	ID              int64
+	TransactionID   int64
+	TransactionType string
+	ReferenceID     string
+	VendorID        int64
+	Account         string
+	Amount          int64
+	CreatedAt       time.Time
+}
+
+type VendorBalanceEntity struct {
+	VendorID      int64
+	Available     int64
+	PendingPayout int64
+	TotalEarned   int64
+	TotalPaidOut  int64
+}
+
+type WithdrawalEntity struct {
+	ID              int64
+	VendorID        int64
+	Amount          int64
+	BankName        string
+	AccountNumber   string
+	AccountName     string
+	Status          string
+	RejectionReason string
+	ReviewedBy      int64
+	ReviewedAt      *time.Time
+	CreatedAt       time.Time
+}
+
+type LedgerAuditEntity struct {
+	AccountTotals          map[string]int64
+	NetTotal               int64
+	TransactionCount       int64
+	UnbalancedTransactions []int64
+}
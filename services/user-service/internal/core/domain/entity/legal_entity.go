@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// Kinds of legal documents users have to accept
+const (
+	LegalDocumentTerms   = "terms"
+	LegalDocumentPrivacy = "privacy"
+)
+
+type LegalDocumentEntity struct {
+	ID          int64
+	Kind        string
+	Version     string
+	URL         string
+	PublishedBy int64
+	PublishedAt time.Time
+}
+
+// ConsentEntity is one acceptance of the current documents, with the client it came from
+type ConsentEntity struct {
+	UserID         int64
+	TermsVersion   string
+	PrivacyVersion string
+	IPAddress      string
+	UserAgent      string
+	AcceptedAt     time.Time
+}
+
+// ConsentStatusEntity compares what a user accepted with what is published now;
+// a nil Current* means no document of that kind was published yet
+type ConsentStatusEntity struct {
+	AcceptedTerms   string
+	AcceptedPrivacy string
+	ConsentedAt     *time.Time
+	CurrentTerms    *LegalDocumentEntity
+	CurrentPrivacy  *LegalDocumentEntity
+}
+
+func (s *ConsentStatusEntity) Required() bool {
+	return (s.CurrentTerms != nil && s.CurrentTerms.Version != s.AcceptedTerms) ||
+		(s.CurrentPrivacy != nil && s.CurrentPrivacy.Version != s.AcceptedPrivacy)
+}
+
+// UserConsentEntity is a row of a user's consent history
+type UserConsentEntity struct {
+	ID         int64
+	UserID     int64
+	Kind       string
+	Version    string
+	IPAddress  string
+	UserAgent  string
+	AcceptedAt time.Time
+}
@@ -0,0 +1,57 @@
+package entity
+
+import "time"
+
+const (
+	OnboardingItemPhoto      = "photo"
+	OnboardingItemPhone      = "phone"
+	OnboardingItemAddress    = "address"
+	OnboardingItemFirstOrder = "first_order"
+)
+
+// OnboardingStateEntity is what is persisted per user; the checklist itself is derived from the profile
+type OnboardingStateEntity struct {
+	UserID       int64
+	DismissedAt  *time.Time
+	FirstOrderAt *time.Time
+	CompletedAt  *time.Time
+}
+
+type OnboardingItemEntity struct {
+	Key       string
+	Completed bool
+}
+
+type OnboardingEntity struct {
+	Items       []OnboardingItemEntity
+	Percentage  int
+	Completed   bool
+	Dismissed   bool
+	CompletedAt *time.Time
+}
+
+// BuildOnboarding derives the checklist from the user's profile and persisted state
+func BuildOnboarding(user *UserEntity, state *OnboardingStateEntity) *OnboardingEntity {
+	items := []OnboardingItemEntity{
+		{Key: OnboardingItemPhoto, Completed: user.Photo != ""},
+		// Counts as done once a phone number is saved; there is no phone OTP flow yet
+		{Key: OnboardingItemPhone, Completed: user.Phone != ""},
+		{Key: OnboardingItemAddress, Completed: user.Address != "" && (user.Lat != 0 || user.Lng != 0)},
+		{Key: OnboardingItemFirstOrder, Completed: state.FirstOrderAt != nil},
+	}
+
+	done := 0
+	for _, item := range items {
+		if item.Completed {
+			done++
+		}
+	}
+
+	return &OnboardingEntity{
+		Items:       items,
+		Percentage:  done * 100 / len(items),
+		Completed:   done == len(items),
+		Dismissed:   state.DismissedAt != nil,
+		CompletedAt: state.CompletedAt,
+	}
+}
@@ -0,0 +1,52 @@
+package entity
+
+import "time"
+
+// JobTypePasswordExpiryWarning emails staff whose password expires soon
+const JobTypePasswordExpiryWarning = "password_policy.expiry_warning"
+
+// PasswordPolicy is the rotation policy for staff passwords; the zero value enforces nothing
+type PasswordPolicy struct {
+	Roles []string
+	// MaxAge is how long a password lasts; zero turns expiry off
+	MaxAge time.Duration
+	// HistorySize recent passwords, the current one included, cannot be reused
+	HistorySize int
+	WarnBefore  time.Duration
+}
+
+// AppliesTo reports whether an account has to follow the policy, given its effective roles:
+// its own role and every role that one inherits from
+func (p PasswordPolicy) AppliesTo(effectiveRoles []string) bool {
+	for _, role := range p.Roles {
+		for _, effective := range effectiveRoles {
+			if role == effective {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ExpiresAt is when a password set at changedAt expires, or zero while expiry is off
+func (p PasswordPolicy) ExpiresAt(changedAt time.Time) time.Time {
+	if p.MaxAge <= 0 || changedAt.IsZero() {
+		return time.Time{}
+	}
+	return changedAt.Add(p.MaxAge)
+}
+
+// Expired reports whether a password set at changedAt has to be changed before signing in
+func (p PasswordPolicy) Expired(changedAt, now time.Time) bool {
+	expiresAt := p.ExpiresAt(changedAt)
+	return !expiresAt.IsZero() && !now.Before(expiresAt)
+}
+
+// PasswordExpiryEntity is a staff account whose password expires soon and was not warned yet
+type PasswordExpiryEntity struct {
+	UserID            int64
+	Email             string
+	Name              string
+	Timezone          string
+	PasswordChangedAt time.Time
+}
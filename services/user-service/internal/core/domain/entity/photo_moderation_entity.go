@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// JobTypeModeratePhoto sends one uploaded profile photo to the moderation provider
+const JobTypeModeratePhoto = "moderation.photo"
+
+// PhotoModerationJobPayload is the payload of a JobTypeModeratePhoto job
+type PhotoModerationJobPayload struct {
+	ModerationID int64 `json:"moderation_id"`
+}
+
+const (
+	PhotoModerationPending    = "pending"
+	PhotoModerationApproved   = "approved"
+	PhotoModerationFlagged    = "flagged"
+	PhotoModerationRemoved    = "removed"
+	PhotoModerationSuperseded = "superseded"
+)
+
+type PhotoModerationEntity struct {
+	ID       int64
+	UserID   int64
+	ImageURL string
+	Status   string
+	// Score is the provider's confidence (0-1) that the photo violates the content policy
+	Score      *float64
+	Labels     []string
+	ReviewNote string
+	ReviewedBy int64
+	ReviewedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// ModerationResultEntity is what the moderation provider answered for one image
+type ModerationResultEntity struct {
+	Score  float64
+	Labels []string
+}
@@ -0,0 +1,48 @@
+package entity
+
+import "context"
+
+const (
+	PrincipalTypeUser    = "user"
+	PrincipalTypeService = "service"
+	PrincipalTypeAPIKey  = "api_key"
+)
+
+// PrincipalEntity identifies who is calling: a signed-in user, another internal service or a
+// partner using an API key
+type PrincipalEntity struct {
+	Type string
+	// UserID and Role are set for user principals
+	UserID int64
+	Role   string
+	// ServiceName is set for service principals, e.g. "order-service"
+	ServiceName string
+	// APIKeyID, Partner and Scopes are set for API key principals; Partner is the key's name
+	APIKeyID int64
+	Partner  string
+	Scopes   []string
+}
+
+func (p PrincipalEntity) IsService() bool {
+	return p.Type == PrincipalTypeService
+}
+
+func (p PrincipalEntity) IsUser() bool {
+	return p.Type == PrincipalTypeUser
+}
+
+func (p PrincipalEntity) IsAPIKey() bool {
+	return p.Type == PrincipalTypeAPIKey
+}
+
+type principalContextKey struct{}
+
+// ContextWithPrincipal lets code below the handlers, such as storage decorators, see the caller
+func ContextWithPrincipal(ctx context.Context, principal PrincipalEntity) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (PrincipalEntity, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(PrincipalEntity)
+	return principal, ok
+}
@@ -0,0 +1,46 @@
+package entity
+
+// Audiences a user-exposing response can be built for. Only vendors are subject to the
+// user's privacy settings; the user, admins and the courier delivering an order see everything.
+const (
+	AudienceSelf    = "self"
+	AudienceAdmin   = "admin"
+	AudienceVendor  = "vendor"
+	AudienceCourier = "courier"
+)
+
+// Names of the fields a privacy setting can hide, as reported in hidden_fields
+const (
+	PrivacyFieldPhone   = "phone"
+	PrivacyFieldAddress = "address"
+)
+
+// PrivacySettingsEntity is what a user hides from vendor-facing views. HideAddress hides the
+// street address, postal code and coordinates; province, city and district stay visible so a
+// vendor can still tell whether it delivers there.
+type PrivacySettingsEntity struct {
+	HidePhone   bool
+	HideAddress bool
+}
+
+// MaskedFor returns a copy of user with the fields hidden from audience blanked, and the names
+// of the fields that were hidden
+func (u UserEntity) MaskedFor(audience string) (UserEntity, []string) {
+	if audience != AudienceVendor {
+		return u, nil
+	}
+
+	var hidden []string
+	if u.Privacy.HidePhone {
+		u.Phone = ""
+		hidden = append(hidden, PrivacyFieldPhone)
+	}
+	if u.Privacy.HideAddress {
+		u.Address = ""
+		u.PostalCode = ""
+		u.Lat = 0
+		u.Lng = 0
+		hidden = append(hidden, PrivacyFieldAddress)
+	}
+	return u, hidden
+}
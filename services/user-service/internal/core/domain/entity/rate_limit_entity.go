@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// RateLimitEntity is the state of one fixed rate-limit window after counting a request
+type RateLimitEntity struct {
+	Limit   int
+	Count   int64
+	ResetAt time.Time
+}
+
+func (r RateLimitEntity) Remaining() int {
+	if remaining := int64(r.Limit) - r.Count; remaining > 0 {
+		return int(remaining)
+	}
+	return 0
+}
+
+func (r RateLimitEntity) Exceeded() bool {
+	return r.Count > int64(r.Limit)
+}
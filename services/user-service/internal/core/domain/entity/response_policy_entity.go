@@ -0,0 +1,72 @@
+package entity
+
+import "strings"
+
+// ResponsePayloadCustomer is the payload a response policy hides fields of. Order payloads are
+// shaped by delivery-service, which owns courier assignments.
+const ResponsePayloadCustomer = "customer"
+
+// ResponsePolicyPartner is the policy key for partners calling with an API key, which have no role
+const ResponsePolicyPartner = "Partner"
+
+// Names of the customer fields a response policy can hide, as reported in hidden_fields.
+// Address also hides the postal code and coordinates.
+const (
+	ResponseFieldEmail    = "email"
+	ResponseFieldUsername = "username"
+	ResponseFieldPhone    = "phone"
+	ResponseFieldAddress  = "address"
+	ResponseFieldPhoto    = "photo"
+)
+
+// ResponsePolicyEntity maps a role to the "payload.field" entries it may not see, e.g.
+// "customer.email". A role without entries inherits the policy of the nearest role it inherits
+// from that has some; a caller none of whose roles has entries sees everything.
+type ResponsePolicyEntity map[string][]string
+
+// HiddenFields returns the fields of payload hidden from a caller holding roles, the caller's
+// own role first and then the roles it inherits from
+func (p ResponsePolicyEntity) HiddenFields(roles []string, payload string) []string {
+	for _, role := range roles {
+		entries, ok := p[role]
+		if !ok || len(entries) == 0 {
+			continue
+		}
+
+		var hidden []string
+		for _, entry := range entries {
+			if field, ok := strings.CutPrefix(entry, payload+"."); ok && field != "" {
+				hidden = append(hidden, field)
+			}
+		}
+		return hidden
+	}
+	return nil
+}
+
+// WithoutFields returns a copy of user with fields blanked, and the names of the fields that
+// were hidden. Unknown names are ignored.
+func (u UserEntity) WithoutFields(fields []string) (UserEntity, []string) {
+	var hidden []string
+	for _, field := range fields {
+		switch field {
+		case ResponseFieldEmail:
+			u.Email = ""
+		case ResponseFieldUsername:
+			u.Username = ""
+		case ResponseFieldPhone:
+			u.Phone = ""
+		case ResponseFieldAddress:
+			u.Address = ""
+			u.PostalCode = ""
+			u.Lat = 0
+			u.Lng = 0
+		case ResponseFieldPhoto:
+			u.Photo = ""
+		default:
+			continue
+		}
+		hidden = append(hidden, field)
+	}
+	return u, hidden
+}
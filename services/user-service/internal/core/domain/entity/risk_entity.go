@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+// Signals raised by the sign-in risk engine
+const (
+	RiskSignalNewDevice        = "new_device"
+	RiskSignalImpossibleTravel = "impossible_travel"
+	RiskSignalFailedAttempts   = "failed_attempts"
+)
+
+type LoginLocationEntity struct {
+	Lat float64   `json:"lat"`
+	Lng float64   `json:"lng"`
+	At  time.Time `json:"at"`
+	// City and Country are approximate, from the IP locator, and may be empty
+	City    string `json:"city,omitempty"`
+	Country string `json:"country,omitempty"`
+}
+
+// Place is the location as shown to users, e.g. "Jakarta, Indonesia"
+func (l *LoginLocationEntity) Place() string {
+	if l == nil {
+		return ""
+	}
+	parts := make([]string, 0, 2)
+	for _, part := range []string{l.City, l.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+type RiskAssessmentEntity struct {
+	Score          int
+	Signals        []string
+	RequiresStepUp bool
+	// Location is where the client IP resolved to, if the IP locator is configured
+	Location *LoginLocationEntity
+}
+
+type StepUpChallengeEntity struct {
+	ID        string               `json:"id"`
+	UserID    int64                `json:"user_id"`
+	CodeHash  string               `json:"code_hash"`
+	Attempts  int                  `json:"attempts"`
+	Score     int                  `json:"score"`
+	Signals   []string             `json:"signals"`
+	Location  *LoginLocationEntity `json:"location,omitempty"`
+	ExpiresAt time.Time            `json:"expires_at"`
+	// RememberMe is what the user asked for at sign-in, applied once the code is verified
+	RememberMe bool `json:"remember_me,omitempty"`
+}
+
+// StepUpRequiredError is returned by SignIn when the user must confirm an email OTP
+// before a session is issued
+type StepUpRequiredError struct {
+	ChallengeID string
+	ExpiresAt   time.Time
+}
+
+func (e *StepUpRequiredError) Error() string {
+	return "step up required"
+}
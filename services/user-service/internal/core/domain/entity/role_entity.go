@@ -2,11 +2,15 @@ package entity
 
 import "time"
 
+// RoleEntity is a role a user can hold. A role with a ParentID inherits everything its parent
+// (and the parent's ancestors) may do.
 type RoleEntity struct {
 	ID        int64
 	Name      string
+	ParentID  *int64
 	Users     []UserEntity
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
+	Version   int64
 }
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// SavedViewListCustomers is the admin customers list, GET /admin/customers
+const SavedViewListCustomers = "customers"
+
+// SavedViewEntity is a named filter and sort for an admin list. Filters holds the list's query
+// parameters as the list endpoint takes them, e.g. search and orderBy for customers.
+type SavedViewEntity struct {
+	ID      int64
+	OwnerID int64
+	List    string
+	Name    string
+	Filters map[string]string
+	// Shared views are listed for every admin; only the owner can change or delete them
+	Shared    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
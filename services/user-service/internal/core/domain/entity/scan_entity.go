@@ -0,0 +1,7 @@
+package entity
+
+type ScanResultEntity struct {
+	Infected bool
+	// Signature names what was found, e.g. "Eicar-Test-Signature"
+	Signature string
+}
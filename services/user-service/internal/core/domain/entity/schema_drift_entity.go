@@ -0,0 +1,44 @@
+package entity
+
+const (
+	SchemaDriftMissingTable   = "missing_table"
+	SchemaDriftMissingColumn  = "missing_column"
+	SchemaDriftTypeMismatch   = "type_mismatch"
+	SchemaDriftNullability    = "nullability"
+	SchemaDriftMissingIndex   = "missing_index"
+	SchemaDriftUnmappedColumn = "unmapped_column"
+)
+
+// SchemaDriftEntity is one difference between a GORM model and the live table
+type SchemaDriftEntity struct {
+	Table  string
+	Column string
+	Kind   string
+	Detail string
+}
+
+// Harmless reports a difference that cannot break queries, like a column no model reads
+func (d SchemaDriftEntity) Harmless() bool {
+	return d.Kind == SchemaDriftUnmappedColumn
+}
+
+// TableSchemaEntity is what the database says a table looks like
+type TableSchemaEntity struct {
+	Name    string
+	Exists  bool
+	Columns map[string]ColumnSchemaEntity
+	Indexes []IndexSchemaEntity
+}
+
+type ColumnSchemaEntity struct {
+	// Type is the Postgres type name, e.g. "int8", "varchar" or "numeric"
+	Type     string
+	Nullable bool
+	Unique   bool
+}
+
+type IndexSchemaEntity struct {
+	Name    string
+	Columns []string
+	Unique  bool
+}
@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// SCIMLockReason marks accounts the identity provider deactivated. Reactivating only lifts these
+// locks, never one an admin placed for review.
+const SCIMLockReason = "deactivated by identity provider"
+
+// SCIMUserEntity is an account as the identity provider sees it. UserName is the email.
+type SCIMUserEntity struct {
+	ID         int64
+	ExternalID string
+	UserName   string
+	Name       string
+	Phone      string
+	Active     bool
+	// RoleID and RoleName are only set when the role is one of the SCIM groups
+	RoleID    int64
+	RoleName  string
+	Version   int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// SCIMGroupEntity is a role exposed as a SCIM group; its members are the SCIM-managed accounts
+// holding it
+type SCIMGroupEntity struct {
+	ID        int64
+	Name      string
+	Members   []SCIMMemberEntity
+	Version   int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type SCIMMemberEntity struct {
+	UserID   int64
+	UserName string
+}
+
+// SCIMFilter is the single `attribute eq "value"` filter identity providers send to look up a
+// resource before provisioning it
+type SCIMFilter struct {
+	Attribute string
+	Value     string
+}
+
+// SCIMPatchOperation is one entry of a PatchOp request. Op is lowercased; Value is the decoded
+// JSON value.
+type SCIMPatchOperation struct {
+	Op    string
+	Path  string
+	Value interface{}
+}
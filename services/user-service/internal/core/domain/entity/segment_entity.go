@@ -0,0 +1,47 @@
+package entity
+
+import "time"
+
+// JobTypeSendCampaignBatch publishes a campaign to one batch of segment members
+const JobTypeSendCampaignBatch = "campaigns.send_batch"
+
+// CampaignBatchJobPayload is the payload of a JobTypeSendCampaignBatch job. The recipients are
+// fixed at launch, so refreshing the segment does not change a campaign already under way.
+type CampaignBatchJobPayload struct {
+	CampaignID int64   `json:"campaign_id"`
+	UserIDs    []int64 `json:"user_ids"`
+}
+
+// SegmentFilterEntity selects verified customers; empty fields do not filter
+type SegmentFilterEntity struct {
+	City         string
+	SignedUpFrom *time.Time
+	SignedUpTo   *time.Time
+	MinOrders    *int
+	MaxOrders    *int
+}
+
+type SegmentEntity struct {
+	ID             int64
+	Name           string
+	Filter         SegmentFilterEntity
+	MemberCount    int64
+	MaterializedAt *time.Time
+	CreatedBy      int64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// CampaignEntity is one send to a segment. Subject and Body may use "{name}" for the recipient's name.
+type CampaignEntity struct {
+	ID         int64
+	SegmentID  int64
+	Name       string
+	Subject    string
+	Body       string
+	Data       map[string]string
+	Recipients int64
+	Batches    int
+	CreatedBy  int64
+	CreatedAt  time.Time
+}
@@ -9,4 +9,13 @@ type SessionInfo struct {
 	ExpiresAt time.Time `json:"expires_at"`
 	UserAgent string    `json:"user_agent,omitempty"`
 	IPAddress string    `json:"ip_address,omitempty"`
+	// DeviceName is a readable label derived from UserAgent, e.g. "Chrome on Windows"
+	DeviceName string `json:"device_name,omitempty"`
+	// City and Country are where IPAddress resolved to at sign-in
+	City    string `json:"city,omitempty"`
+	Country string `json:"country,omitempty"`
+	// RememberMe sessions get the longer lifetime, also when refreshed
+	RememberMe bool `json:"remember_me,omitempty"`
+	// SSO sessions were signed in through the identity provider, so password expiry does not end them
+	SSO bool `json:"sso,omitempty"`
 }
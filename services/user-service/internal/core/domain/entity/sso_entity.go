@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// SSOStateEntity is a pending OpenID Connect sign-in, keyed by the state sent to the provider.
+// The nonce and PKCE verifier never leave the server.
+type SSOStateEntity struct {
+	ID           string    `json:"id"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier"`
+	RememberMe   bool      `json:"remember_me"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// OIDCIdentityEntity is what a verified ID token says about the user
+type OIDCIdentityEntity struct {
+	Subject string
+	Email   string
+	// EmailVerified is nil when the provider does not send email_verified
+	EmailVerified *bool
+	Name          string
+	Groups        []string
+	Nonce         string
+}
@@ -0,0 +1,21 @@
+package entity
+
+const (
+	StorageEventCreated = "created"
+	StorageEventDeleted = "deleted"
+
+	// JobTypeReconcileProfilePhoto deletes a new profile photo object when no user ended up using it
+	JobTypeReconcileProfilePhoto = "storage.reconcile_profile_photo"
+)
+
+// StorageEventEntity is an object created in or deleted from a storage bucket
+type StorageEventEntity struct {
+	Type       string
+	Bucket     string
+	ObjectName string
+}
+
+type ReconcileProfilePhotoJobPayload struct {
+	Bucket     string `json:"bucket"`
+	ObjectName string `json:"object_name"`
+}
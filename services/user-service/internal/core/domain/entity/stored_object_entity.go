@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// StoredObjectEntity is one object in storage and the number of records referencing it. Bucket
+// is the name callers pass to storage, "" for the default bucket.
+type StoredObjectEntity struct {
+	Bucket      string
+	ObjectName  string
+	SHA256      string
+	Size        int64
+	ContentType string
+	URL         string
+	RefCount    int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,74 @@
+package entity
+
+import "time"
+
+const (
+	SupportTicketOpen       = "open"
+	SupportTicketInProgress = "in_progress"
+	// SupportTicketWaiting means staff asked the customer for more information
+	SupportTicketWaiting  = "waiting_on_customer"
+	SupportTicketResolved = "resolved"
+	SupportTicketClosed   = "closed"
+)
+
+// SupportTicketStatuses are the statuses staff can set
+var SupportTicketStatuses = []string{SupportTicketOpen, SupportTicketInProgress, SupportTicketWaiting, SupportTicketResolved, SupportTicketClosed}
+
+// SupportTicketCategories are the categories a customer picks when opening a ticket
+var SupportTicketCategories = []string{"order", "payment", "delivery", "account", "other"}
+
+func IsSupportTicketStatus(status string) bool {
+	for _, known := range SupportTicketStatuses {
+		if status == known {
+			return true
+		}
+	}
+	return false
+}
+
+func IsSupportTicketCategory(category string) bool {
+	for _, known := range SupportTicketCategories {
+		if category == known {
+			return true
+		}
+	}
+	return false
+}
+
+type SupportAttachmentEntity struct {
+	ObjectName  string `json:"object_name"`
+	FileName    string `json:"file_name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// URL is a short-lived signed link, filled when a single ticket is viewed
+	URL string `json:"-"`
+}
+
+type SupportTicketEntity struct {
+	ID          int64
+	UserID      int64
+	Category    string
+	Subject     string
+	Message     string
+	Attachments []SupportAttachmentEntity
+	Status      string
+	AssigneeID  int64
+	ResolvedAt  *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// SupportTicketFilter narrows the staff list; zero values match everything
+type SupportTicketFilter struct {
+	Status     string
+	Category   string
+	AssigneeID int64
+	// Unassigned lists tickets nobody has picked up yet and ignores AssigneeID
+	Unassigned bool
+}
+
+// SupportUploadEntity is one attachment as received from the customer
+type SupportUploadEntity struct {
+	FileName string
+	Data     []byte
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Reasons a token is reported inactive; they are logged, not returned to the caller
+const (
+	TokenInactiveInvalid   = "invalid"
+	TokenInactiveRevoked   = "revoked"
+	TokenInactiveNoSession = "session_not_found"
+	// TokenInactivePasswordExpired is a staff session signed in with a password that has expired since
+	TokenInactivePasswordExpired = "password_expired"
+)
+
+type TokenIntrospectionEntity struct {
+	Active    bool
+	Reason    string
+	UserID    int64
+	Email     string
+	RoleName  string
+	SessionID string
+	Issuer    string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Record types handled by the admin trash
+const (
+	TrashRecordUser = "user"
+	TrashRecordRole = "role"
+)
+
+type TrashedUserEntity struct {
+	ID           int64
+	Name         string
+	Email        string
+	RoleName     string
+	MergedIntoID *int64
+	DeletedAt    time.Time
+}
+
+type TrashedRoleEntity struct {
+	ID        int64
+	Name      string
+	DeletedAt time.Time
+}
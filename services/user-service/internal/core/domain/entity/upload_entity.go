@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// UploadSessionEntity is a resumable upload in progress. The file is sent as TotalChunks
+// chunks of ChunkSize bytes (the last one may be shorter) and assembled on completion.
+type UploadSessionEntity struct {
+	ID          string `json:"id"`
+	UserID      int64  `json:"user_id"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// Checksum is the hex SHA-256 of the whole file, checked after assembly
+	Checksum    string    `json:"checksum"`
+	ChunkSize   int64     `json:"chunk_size"`
+	TotalChunks int       `json:"total_chunks"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+
+	// ReceivedChunks is read from the chunk store, not persisted with the session
+	ReceivedChunks []int `json:"-"`
+}
+
+// ChunkLength is the exact byte length chunk index must have
+func (u *UploadSessionEntity) ChunkLength(index int) int64 {
+	if index == u.TotalChunks-1 {
+		return u.Size - int64(u.TotalChunks-1)*u.ChunkSize
+	}
+	return u.ChunkSize
+}
+
+// MissingChunks lists the chunk indexes the client still has to send
+func (u *UploadSessionEntity) MissingChunks() []int {
+	received := make(map[int]bool, len(u.ReceivedChunks))
+	for _, index := range u.ReceivedChunks {
+		received[index] = true
+	}
+
+	missing := []int{}
+	for index := 0; index < u.TotalChunks; index++ {
+		if !received[index] {
+			missing = append(missing, index)
+		}
+	}
+	return missing
+}
+
+type UploadResultEntity struct {
+	URL         string
+	Filename    string
+	ContentType string
+	Size        int64
+	Checksum    string
+}
@@ -1,16 +1,65 @@
 package entity
 
+import "time"
+
 type UserEntity struct {
 	ID         int64
 	Name       string
 	Email      string
+	Username   string
+	Timezone   string
+	Language   string
 	Password   string
 	RoleName   string
 	RoleID     int64
 	Address    string
+	Province   string
+	City       string
+	District   string
+	PostalCode string
 	Lat        float64
 	Lng        float64
+	DistanceKm float64
 	Phone      string
 	Photo      string
-	IsVerified bool
+	// PhotoOriginal is the upload Photo was cropped from; empty for generated avatars
+	PhotoOriginal string
+	IsVerified    bool
+	LockedAt      *time.Time
+	LockReason    string
+	Version       int64
+	// BouncedAt is when a hard bounce made Email undeliverable; nil while it is deliverable
+	BouncedAt    *time.Time
+	BounceReason string
+	Privacy      PrivacySettingsEntity
+	// PasswordChangedAt starts the clock of the staff password rotation policy
+	PasswordChangedAt time.Time
+}
+
+// PhotoEditEntity is how the client wants an uploaded profile photo turned and cropped.
+// Rotation is clockwise degrees; Crop is in pixels of the upright, rotated image and nil
+// takes the centred square.
+type PhotoEditEntity struct {
+	Rotation int
+	Crop     *PhotoCropEntity
+}
+
+type PhotoCropEntity struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+// ProfilePhotoEntity holds both stored copies of an edited profile photo
+type ProfilePhotoEntity struct {
+	URL         string
+	OriginalURL string
+}
+
+// UsernameAvailabilityEntity answers whether a username can be claimed, and why not
+type UsernameAvailabilityEntity struct {
+	Username  string
+	Available bool
+	Reason    string
 }
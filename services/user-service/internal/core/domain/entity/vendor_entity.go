@@ -0,0 +1,38 @@
+package entity
+
+import "time"
+
+const (
+	VendorStatusPending   = "pending"
+	VendorStatusApproved  = "approved"
+	VendorStatusRejected  = "rejected"
+	VendorStatusSuspended = "suspended"
+)
+
+type VendorEntity struct {
+	ID              int64
+	UserID          int64
+	StoreName       string
+	Description     string
+	Phone           string
+	Address         string
+	Lat             float64
+	Lng             float64
+	Status          string
+	RejectionReason string
+	ReviewedBy      int64
+	ReviewedAt      *time.Time
+	Documents       []VendorDocumentEntity
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+type VendorDocumentEntity struct {
+	ID           int64
+	VendorID     int64
+	DocumentType string
+	FileURL      string
+	ObjectName   string
+	ContentType  string
+	CreatedAt    time.Time
+}
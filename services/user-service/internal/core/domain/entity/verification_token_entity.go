@@ -2,6 +2,14 @@ package entity
 
 import "time"
 
+const (
+	TokenTypeEmailVerification = "email_verification"
+	TokenTypePasswordReset     = "password_reset"
+	TokenTypeEmailChange       = "email_change"
+	// TokenTypeEmailChangeRevert lets the previous address undo a completed email change
+	TokenTypeEmailChangeRevert = "email_change_revert"
+)
+
 type VerificationTokenEntity struct {
 	ID        int64
 	UserID    int64
@@ -11,3 +19,50 @@ type VerificationTokenEntity struct {
 	ExpiresAt time.Time
 	User      UserEntity
 }
+
+// TokenLifetimes is how long each type of verification token stays valid; zero means the default
+type TokenLifetimes struct {
+	EmailVerification time.Duration
+	PasswordReset     time.Duration
+	EmailChange       time.Duration
+	EmailChangeRevert time.Duration
+}
+
+// DefaultTokenLifetimes are used for every lifetime that is not configured
+func DefaultTokenLifetimes() TokenLifetimes {
+	return TokenLifetimes{
+		EmailVerification: 24 * time.Hour,
+		PasswordReset:     time.Hour,
+		EmailChange:       24 * time.Hour,
+		EmailChangeRevert: 7 * 24 * time.Hour,
+	}
+}
+
+// For returns the lifetime of tokenType, or 0 for an unknown type
+func (l TokenLifetimes) For(tokenType string) time.Duration {
+	if lifetime := l.lifetime(tokenType); lifetime > 0 {
+		return lifetime
+	}
+	return DefaultTokenLifetimes().lifetime(tokenType)
+}
+
+func (l TokenLifetimes) lifetime(tokenType string) time.Duration {
+	switch tokenType {
+	case TokenTypeEmailVerification:
+		return l.EmailVerification
+	case TokenTypePasswordReset:
+		return l.PasswordReset
+	case TokenTypeEmailChange:
+		return l.EmailChange
+	case TokenTypeEmailChangeRevert:
+		return l.EmailChangeRevert
+	default:
+		return 0
+	}
+}
+
+// TokenPurgeEntity counts what a token purge removed: expired verification links and blacklist entries
+type TokenPurgeEntity struct {
+	ExpiredVerifications int64
+	BlacklistEntries     int64
+}
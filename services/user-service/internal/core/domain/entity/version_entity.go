@@ -0,0 +1,11 @@
+package entity
+
+// VersionConflictError is returned when an update was based on a stale version of the record.
+// CurrentVersion lets the client refetch or retry against the latest state.
+type VersionConflictError struct {
+	CurrentVersion int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return "version conflict"
+}
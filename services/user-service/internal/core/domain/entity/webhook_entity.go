@@ -0,0 +1,104 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JobTypeDeliverWebhook sends one webhook delivery; failed sends are retried with the job backoff
+const JobTypeDeliverWebhook = "webhooks.deliver"
+
+// WebhookDeliveryJobPayload is the payload of a JobTypeDeliverWebhook job
+type WebhookDeliveryJobPayload struct {
+	DeliveryID int64 `json:"delivery_id"`
+}
+
+const (
+	WebhookEventUserCreated  = "user.created"
+	WebhookEventUserVerified = "user.verified"
+	// WebhookEventOrderPaid is raised by the order service through the internal API
+	WebhookEventOrderPaid = "order.paid"
+	// Support ticket events let an external helpdesk mirror the tickets
+	WebhookEventSupportTicketCreated = "support.ticket_created"
+	WebhookEventSupportTicketUpdated = "support.ticket_updated"
+)
+
+// WebhookEventTypes are the events endpoints can subscribe to
+var WebhookEventTypes = []string{
+	WebhookEventUserCreated,
+	WebhookEventUserVerified,
+	WebhookEventOrderPaid,
+	WebhookEventSupportTicketCreated,
+	WebhookEventSupportTicketUpdated,
+}
+
+func IsWebhookEventType(eventType string) bool {
+	for _, known := range WebhookEventTypes {
+		if eventType == known {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+type WebhookEndpointEntity struct {
+	ID          int64
+	URL         string
+	Secret      string
+	EventTypes  []string
+	Description string
+	IsActive    bool
+	CreatedBy   int64
+	// APIKeyID is set when a partner registered the endpoint; only that key can manage it
+	APIKeyID  int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (e *WebhookEndpointEntity) Subscribes(eventType string) bool {
+	for _, subscribed := range e.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryEntity struct {
+	ID         int64
+	EndpointID int64
+	EventID    string
+	EventType  string
+	// Payload is the exact body sent on every attempt
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int
+	ResponseStatus int
+	ResponseBody   string
+	LastError      string
+	DurationMs     int64
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// WebhookAttemptEntity is the outcome of one HTTP call to an endpoint
+type WebhookAttemptEntity struct {
+	Status         string
+	ResponseStatus int
+	ResponseBody   string
+	Error          string
+	DurationMs     int64
+}
+
+// WebhookResponseEntity is what the receiving endpoint answered
+type WebhookResponseEntity struct {
+	StatusCode int
+	Body       string
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type APIKey struct {
+	ID         int64 `gorm:"PrimaryKey"`
+	Name       string
+	KeyPrefix  string
+	KeyHash    string
+	Scopes     string `gorm:"type:jsonb"`
+	Quota      int
+	CreatedBy  *int64
+	LastUsedAt *time.Time
+	ExpiresAt  *time.Time
+	RevokedAt  *time.Time
+	RevokedBy  *int64
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
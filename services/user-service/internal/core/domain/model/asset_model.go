@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+type Asset struct {
+	Name        string `gorm:"PrimaryKey"`
+	ObjectName  string
+	Hash        string
+	ContentType string
+	Size        int64
+	URL         string `gorm:"column:url"`
+	UploadedBy  *int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+type AuditLog struct {
+	ID        int64 `gorm:"PrimaryKey"`
+	UserID    *int64
+	Event     string
+	IPAddress string
+	UserAgent string
+	Metadata  string `gorm:"type:jsonb"`
+	CreatedAt time.Time
+}
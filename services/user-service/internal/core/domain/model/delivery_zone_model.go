@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+type DeliveryZone struct {
+	ID        int64  `gorm:"PrimaryKey"`
+	Name      string `gorm:"unique"`
+	ZoneType  string
+	CenterLat *float64
+	CenterLng *float64
+	RadiusKm  *float64
+	Polygon   *string `gorm:"type:jsonb"`
+	IsActive  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+}
+
+func (DeliveryZone) TableName() string {
+	return "delivery_zones"
+}
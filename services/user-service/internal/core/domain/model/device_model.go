@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type UserDevice struct {
+	ID          int64 `gorm:"PrimaryKey"`
+	UserID      int64
+	Fingerprint string
+	UserAgent   string
+	LastIP      string `gorm:"column:last_ip"`
+	Trusted     bool
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+}
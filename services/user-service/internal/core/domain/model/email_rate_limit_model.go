@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type EmailRateLimit struct {
+	EmailType  string `gorm:"PrimaryKey"`
+	MaxPerHour int
+	UpdatedBy  *int64
+	UpdatedAt  time.Time
+}
+
+func (EmailRateLimit) TableName() string {
+	return "email_rate_limits"
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+type FeatureFlag struct {
+	ID                int64 `gorm:"PrimaryKey"`
+	Key               string
+	Description       string
+	Enabled           bool
+	RolloutPercentage int
+	Overrides         []FeatureFlagOverride `gorm:"foreignKey:FeatureFlagID"`
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+type FeatureFlagOverride struct {
+	ID            int64 `gorm:"PrimaryKey"`
+	FeatureFlagID int64
+	UserID        int64
+	Enabled       bool
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type IdentityVerification struct {
+	ID              int64 `gorm:"PrimaryKey"`
+	UserID          int64
+	DocumentType    string
+	ObjectName      string
+	ContentType     string
+	DateOfBirth     time.Time
+	Status          string
+	RejectionReason string
+	ReviewedBy      *int64
+	ReviewedAt      *time.Time
+	ExpiresAt       *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
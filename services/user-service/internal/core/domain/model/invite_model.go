@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+type InviteCode struct {
+	ID        int64 `gorm:"PrimaryKey"`
+	Code      string
+	Note      string
+	MaxUses   int
+	Uses      int
+	ExpiresAt *time.Time
+	RevokedAt *time.Time
+	CreatedBy *int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type InviteRedemption struct {
+	ID           int64 `gorm:"PrimaryKey"`
+	InviteCodeID int64
+	Email        string
+	RedeemedAt   time.Time
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+type Job struct {
+	ID          int64 `gorm:"PrimaryKey"`
+	Type        string
+	Payload     string `gorm:"type:jsonb"`
+	Status      string
+	Attempts    int
+	MaxAttempts int
+	RunAt       time.Time
+	UniqueKey   *string
+	LastError   string
+	LockedBy    string
+	LockedAt    *time.Time
+	CompletedAt *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+type LedgerTransaction struct {
+	ID          int64 `gorm:"PrimaryKey"`
+	VendorID    int64
+	Type        string
+	ReferenceID string
+	Description string
+	CreatedBy   *int64
+	Entries     []LedgerEntry `gorm:"foreignKey:TransactionID"`
+	CreatedAt   time.Time
+}
+
+type LedgerEntry struct {
+	ID            int64 `gorm:"PrimaryKey"`
+	TransactionID int64
+	VendorID      int64
+	Account       string
+	Amount        int64
+	CreatedAt     time.Time
+}
+
+type VendorWithdrawal struct {
+	ID              int64 `gorm:"PrimaryKey"`
+	VendorID        int64
+	Amount          int64
+	BankName        string
+	AccountNumber   string
+	AccountName     string
+	Status          string
+	RejectionReason string
+	ReviewedBy      *int64
+	ReviewedAt      *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+type LegalDocument struct {
+	ID          int64 `gorm:"PrimaryKey"`
+	Kind        string
+	Version     string
+	URL         string
+	PublishedBy *int64
+	PublishedAt time.Time
+}
+
+type UserConsent struct {
+	ID         int64 `gorm:"PrimaryKey"`
+	UserID     int64
+	Kind       string
+	Version    string
+	IPAddress  string
+	UserAgent  string
+	AcceptedAt time.Time
+}
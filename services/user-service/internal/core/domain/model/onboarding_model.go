@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type UserOnboarding struct {
+	UserID       int64 `gorm:"PrimaryKey"`
+	DismissedAt  *time.Time
+	FirstOrderAt *time.Time
+	CompletedAt  *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (UserOnboarding) TableName() string {
+	return "user_onboarding"
+}
@@ -0,0 +1,15 @@
+package model
+
+import "time"
+
+// PasswordHistory keeps a user's earlier password hashes so the staff policy can refuse reuse
+type PasswordHistory struct {
+	ID           int64 `gorm:"PrimaryKey"`
+	UserID       int64
+	PasswordHash string
+	CreatedAt    time.Time
+}
+
+func (PasswordHistory) TableName() string {
+	return "password_histories"
+}
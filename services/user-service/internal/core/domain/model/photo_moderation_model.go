@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type PhotoModeration struct {
+	ID         int64 `gorm:"PrimaryKey"`
+	UserID     int64
+	ImageURL   string `gorm:"column:image_url"`
+	Status     string
+	Score      *float64
+	Labels     string `gorm:"type:jsonb"`
+	ReviewNote string
+	ReviewedBy *int64
+	ReviewedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
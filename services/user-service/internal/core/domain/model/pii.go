@@ -0,0 +1,5 @@
+package model
+
+// Fields tagged `gorm:"serializer:pii"` only parse once the serializer is registered, so every
+// binary that uses the models gets it, not just those that import the repositories
+import _ "user-service/utils/pii"
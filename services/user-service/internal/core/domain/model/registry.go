@@ -0,0 +1,42 @@
+package model
+
+// All lists every model backed by a table, for the schema drift check. Add new models here,
+// or their tables go unchecked.
+func All() []interface{} {
+	return []interface{}{
+		&APIKey{},
+		&Asset{},
+		&AuditLog{},
+		&BlacklistToken{},
+		&CustomerSegment{},
+		&DeliveryZone{},
+		&EmailRateLimit{},
+		&FeatureFlag{},
+		&FeatureFlagOverride{},
+		&IdentityVerification{},
+		&InviteCode{},
+		&InviteRedemption{},
+		&Job{},
+		&LedgerEntry{},
+		&LedgerTransaction{},
+		&LegalDocument{},
+		&PasswordHistory{},
+		&PhotoModeration{},
+		&Role{},
+		&SavedView{},
+		&SegmentCampaign{},
+		&StoredObject{},
+		&SupportTicket{},
+		&User{},
+		&UserConsent{},
+		&UserDevice{},
+		&UserOnboarding{},
+		&UserRole{},
+		&Vendor{},
+		&VendorDocument{},
+		&VendorWithdrawal{},
+		&VerificationToken{},
+		&WebhookDelivery{},
+		&WebhookEndpoint{},
+	}
+}
@@ -5,8 +5,10 @@ import "time"
 type Role struct {
 	ID        int64  `gorm:"PrimaryKey"`
 	Name      string `gorm:"unique"`
+	ParentID  *int64
 	Users     []User `gorm:"many2many:user_role;"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
+	Version   int64 `gorm:"default:1"`
 }
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+type SavedView struct {
+	ID        int64 `gorm:"PrimaryKey"`
+	OwnerID   int64
+	List      string
+	Name      string
+	Filters   string `gorm:"type:jsonb"`
+	IsShared  bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (SavedView) TableName() string {
+	return "saved_views"
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+type CustomerSegment struct {
+	ID             int64 `gorm:"PrimaryKey"`
+	Name           string
+	City           string
+	SignedUpFrom   *time.Time
+	SignedUpTo     *time.Time
+	MinOrders      *int
+	MaxOrders      *int
+	MemberCount    int64
+	MaterializedAt *time.Time
+	CreatedBy      *int64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type SegmentCampaign struct {
+	ID         int64 `gorm:"PrimaryKey"`
+	SegmentID  int64
+	Name       string
+	Subject    string
+	Body       string
+	Data       string `gorm:"type:jsonb"`
+	Recipients int64
+	Batches    int
+	CreatedBy  *int64
+	CreatedAt  time.Time
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+type StoredObject struct {
+	Bucket      string `gorm:"PrimaryKey"`
+	ObjectName  string `gorm:"PrimaryKey"`
+	SHA256      string `gorm:"column:sha256"`
+	Size        int64
+	ContentType string
+	URL         string
+	RefCount    int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (StoredObject) TableName() string {
+	return "stored_objects"
+}
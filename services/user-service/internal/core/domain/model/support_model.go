@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+type SupportTicket struct {
+	ID          int64 `gorm:"PrimaryKey"`
+	UserID      int64
+	Category    string
+	Subject     string
+	Message     string
+	Attachments string `gorm:"type:jsonb"`
+	Status      string
+	AssigneeID  *int64
+	ResolvedAt  *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
@@ -5,18 +5,54 @@ import (
 )
 
 type User struct {
-	ID         int64 `gorm:"PrimaryKey"`
-	Name       string
-	Email      string `gorm:"unique"`
-	Password   string
-	Address    string
-	Phone      string
+	ID   int64 `gorm:"PrimaryKey"`
+	Name string
+	// Email is only unique among active accounts (migration 000037); deleted rows keep theirs
+	Email string `gorm:"uniqueIndex:idx_users_email_active,where:deleted_at IS NULL"`
+	// Username is stored lowercased; NULL until the user picks one
+	Username *string `gorm:"unique"`
+	// Timezone is an IANA name such as "Asia/Jakarta"
+	Timezone string `gorm:"default:UTC"`
+	// Language is the preferred email language, e.g. "id"; empty until known
+	Language string
+	Password string
+	// PasswordChangedAt starts the staff rotation clock; PasswordExpiryWarnedAt is set once the
+	// expiry warning for the current password went out
+	PasswordChangedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP"`
+	PasswordExpiryWarnedAt *time.Time
+	// Address and Phone are sealed at rest; see repository.RegisterPIIEncryption
+	Address    string `gorm:"serializer:pii"`
+	Province   string
+	City       string
+	District   string
+	PostalCode string
+	Phone      string `gorm:"serializer:pii"`
 	Photo      string
-	Lat        string
-	Lng        string
-	IsVerified bool
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	DeletedAt  *time.Time
-	Roles      []Role `gorm:"many2many:user_role;"`
+	// PhotoOriginal is the uncropped upload Photo was processed from
+	PhotoOriginal string
+	Lat           *float64
+	Lng           *float64
+	IsVerified    bool
+	// LockedAt is set while the account is held for admin review
+	LockedAt   *time.Time
+	LockReason string
+	// EmailBouncedAt is set by a hard bounce report; the address gets no more verification emails
+	EmailBouncedAt    *time.Time
+	EmailBounceReason string
+	// HidePhone and HideAddress keep those fields out of vendor-facing views
+	HidePhone   bool
+	HideAddress bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	DeletedAt   *time.Time
+	// MergedIntoID points at the surviving account when this one was archived by a merge
+	MergedIntoID *int64
+	// SCIMManaged is set for accounts an identity provider provisions; SCIMExternalID is its id
+	SCIMManaged    bool    `gorm:"column:scim_managed"`
+	SCIMExternalID *string `gorm:"column:scim_external_id"`
+	// SSOSubject is the OpenID Connect subject the account signs in with, once linked
+	SSOSubject *string `gorm:"column:sso_subject"`
+	// Version is bumped by every profile edit; see repository.RegisterOptimisticLocking
+	Version int64  `gorm:"default:1"`
+	Roles   []Role `gorm:"many2many:user_role;"`
 }
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+type Vendor struct {
+	ID              int64 `gorm:"PrimaryKey"`
+	UserID          int64 `gorm:"unique"`
+	StoreName       string
+	Description     string
+	Phone           string `gorm:"serializer:pii"`
+	Address         string `gorm:"serializer:pii"`
+	Lat             *float64
+	Lng             *float64
+	Status          string
+	RejectionReason string
+	ReviewedBy      *int64
+	ReviewedAt      *time.Time
+	Documents       []VendorDocument `gorm:"foreignKey:VendorID"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	DeletedAt       *time.Time
+}
+
+type VendorDocument struct {
+	ID           int64 `gorm:"PrimaryKey"`
+	VendorID     int64
+	DocumentType string
+	FileURL      string
+	ObjectName   string
+	ContentType  string
+	CreatedAt    time.Time
+}
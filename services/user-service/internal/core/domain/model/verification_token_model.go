@@ -5,9 +5,10 @@ import "time"
 type VerificationToken struct {
 	ID        int64 `gorm:"primaryKey"`
 	UserID    int64 `gorm:"index"`
-	Token     string
+	TokenHash string
 	TokenType string
 	NewEmail  string
 	ExpiresAt time.Time
+	UsedAt    *time.Time
 	User      User `gorm:"foreignKey:UserID"`
 }
@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+type WebhookEndpoint struct {
+	ID          int64  `gorm:"PrimaryKey"`
+	URL         string `gorm:"column:url"`
+	Secret      string
+	EventTypes  string `gorm:"type:jsonb"`
+	Description string
+	IsActive    bool
+	CreatedBy   *int64
+	APIKeyID    *int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type WebhookDelivery struct {
+	ID             int64 `gorm:"PrimaryKey"`
+	EndpointID     int64
+	EventID        string
+	EventType      string
+	Payload        string `gorm:"type:jsonb"`
+	Status         string
+	Attempts       int
+	ResponseStatus *int
+	ResponseBody   string
+	LastError      string
+	DurationMs     *int64
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type AccountMergeRepositoryInterface interface {
+	// MergeAccounts moves the source account's data to the target and archives the source in
+	// one transaction. With dryRun the transaction is rolled back after computing the result.
+	MergeAccounts(ctx context.Context, sourceUserID, targetUserID int64, dryRun bool) (*entity.AccountMergeEntity, error)
+}
+
+type AccountMergeServiceInterface interface {
+	MergeAccounts(ctx context.Context, sourceUserID, targetUserID, adminID int64, reason string, dryRun bool) (*entity.AccountMergeEntity, error)
+}
@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type AccountSecurityServiceInterface interface {
+	// RevertEmailChange restores the address named in a revert token and locks the account for review
+	RevertEmailChange(ctx context.Context, token string) error
+	GetLockedAccounts(ctx context.Context) ([]entity.UserEntity, error)
+	UnlockAccount(ctx context.Context, userID, adminID int64) error
+}
@@ -0,0 +1,28 @@
+package port
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
+)
+
+type APIKeyRepositoryInterface interface {
+	CreateAPIKey(ctx context.Context, apiKey *entity.APIKeyEntity) (*entity.APIKeyEntity, error)
+	GetAPIKeys(ctx context.Context, page, limit int) ([]entity.APIKeyEntity, int64, error)
+	GetAPIKeyByID(ctx context.Context, id int64) (*entity.APIKeyEntity, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*entity.APIKeyEntity, error)
+	// RevokeAPIKey returns gorm.ErrRecordNotFound when the key does not exist or is already revoked
+	RevokeAPIKey(ctx context.Context, id, revokedBy int64, at time.Time) error
+	TouchLastUsed(ctx context.Context, id int64, at time.Time) error
+}
+
+type APIKeyServiceInterface interface {
+	// CreateAPIKey returns the stored key and the plain key, which is never retrievable again
+	CreateAPIKey(ctx context.Context, apiKey *entity.APIKeyEntity) (*entity.APIKeyEntity, string, error)
+	GetAPIKeys(ctx context.Context, page, limit int) ([]entity.APIKeyEntity, *entity.PaginationEntity, error)
+	GetAPIKeyByID(ctx context.Context, id int64) (*entity.APIKeyEntity, error)
+	// RevokeAPIKey takes effect on the next request; revokedBy is 0 when a partner revokes its own key
+	RevokeAPIKey(ctx context.Context, id, revokedBy int64) error
+	// Authenticate resolves a plain key from the X-API-Key header to an active key
+	Authenticate(ctx context.Context, plainKey string) (*entity.APIKeyEntity, error)
+}
@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+	"io"
+	"user-service/internal/core/domain/entity"
+)
+
+type AssetRepositoryInterface interface {
+	GetAssets(ctx context.Context) ([]entity.AssetEntity, error)
+	GetAsset(ctx context.Context, name string) (*entity.AssetEntity, error)
+	// SaveAsset inserts the asset or points an existing name at the new object
+	SaveAsset(ctx context.Context, asset *entity.AssetEntity) error
+}
+
+type AssetServiceInterface interface {
+	// UploadAsset stores the image under a hashed object name and points name at it
+	UploadAsset(ctx context.Context, name string, file io.Reader, uploadedBy int64) (*entity.AssetEntity, error)
+	GetAssets(ctx context.Context) ([]entity.AssetEntity, error)
+}
@@ -0,0 +1,17 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type AuditLogRepositoryInterface interface {
+	Create(ctx context.Context, auditLog *entity.AuditLogEntity) error
+	List(ctx context.Context, filter entity.AuditLogFilter, page, limit int) ([]entity.AuditLogEntity, int64, error)
+}
+
+type AuditLogServiceInterface interface {
+	// Record stores the event best-effort; audit failures never break the calling flow
+	Record(ctx context.Context, auditLog entity.AuditLogEntity)
+	List(ctx context.Context, filter entity.AuditLogFilter, page, limit int) ([]entity.AuditLogEntity, *entity.PaginationEntity, error)
+}
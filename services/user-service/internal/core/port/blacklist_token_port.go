@@ -7,4 +7,8 @@ import (
 type BlacklistTokenInterface interface {
 	AddToBlacklist(ctx context.Context, tokenHash string, expiresAt int64) error
 	IsTokenBlacklisted(ctx context.Context, tokenHash string) bool
+	// DeleteExpired drops entries whose token has expired anyway and returns how many were removed
+	DeleteExpired(ctx context.Context) (int64, error)
+	// Count returns the number of entries that are still in effect
+	Count(ctx context.Context) (int64, error)
 }
@@ -0,0 +1,8 @@
+package port
+
+import "context"
+
+// CDNPurgerInterface drops URLs from the CDN's cache, so deleted or replaced files stop being served
+type CDNPurgerInterface interface {
+	Purge(ctx context.Context, urls []string) error
+}
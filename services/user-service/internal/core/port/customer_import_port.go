@@ -0,0 +1,21 @@
+package port
+
+import (
+	"context"
+	"io"
+	"time"
+	"user-service/internal/core/domain/entity"
+)
+
+// CustomerImportReportRepositoryInterface keeps the CSV error reports of past imports
+type CustomerImportReportRepositoryInterface interface {
+	SaveReport(ctx context.Context, reportID string, report []byte, ttl time.Duration) error
+	GetReport(ctx context.Context, reportID string) ([]byte, error)
+}
+
+type CustomerImportServiceInterface interface {
+	// ImportCustomers reads the CSV row by row. Rows are committed as they are read, so on a
+	// read error the partial result is returned together with the error.
+	ImportCustomers(ctx context.Context, file io.Reader, adminID int64, dryRun bool) (*entity.CustomerImportResultEntity, error)
+	GetErrorReport(ctx context.Context, reportID string) ([]byte, error)
+}
@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+// CustomerSuggestServiceInterface completes a partial name or email for admin type-ahead
+type CustomerSuggestServiceInterface interface {
+	Suggest(ctx context.Context, query string) ([]entity.UserEntity, error)
+}
@@ -0,0 +1,24 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type DeliveryZoneRepositoryInterface interface {
+	GetAllZones(ctx context.Context, activeOnly bool) ([]entity.DeliveryZoneEntity, error)
+	GetZoneByID(ctx context.Context, id int64) (*entity.DeliveryZoneEntity, error)
+	CreateZone(ctx context.Context, zone *entity.DeliveryZoneEntity) (*entity.DeliveryZoneEntity, error)
+	UpdateZone(ctx context.Context, id int64, zone *entity.DeliveryZoneEntity) (*entity.DeliveryZoneEntity, error)
+	DeleteZone(ctx context.Context, id int64) error
+}
+
+type DeliveryZoneServiceInterface interface {
+	GetAllZones(ctx context.Context) ([]entity.DeliveryZoneEntity, error)
+	GetZoneByID(ctx context.Context, id int64) (*entity.DeliveryZoneEntity, error)
+	CreateZone(ctx context.Context, zone *entity.DeliveryZoneEntity) (*entity.DeliveryZoneEntity, error)
+	UpdateZone(ctx context.Context, id int64, zone *entity.DeliveryZoneEntity) (*entity.DeliveryZoneEntity, error)
+	DeleteZone(ctx context.Context, id int64) error
+	// CheckPoint reports whether lat/lng is covered and by which active zone
+	CheckPoint(ctx context.Context, lat, lng float64) (*entity.DeliveryZoneEntity, bool, error)
+}
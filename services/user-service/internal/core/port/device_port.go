@@ -0,0 +1,21 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type DeviceRepositoryInterface interface {
+	// TouchDevice upserts the device and reports whether it was seen for the first time
+	TouchDevice(ctx context.Context, userID int64, client entity.ClientEntity) (*entity.DeviceEntity, bool, error)
+	// FindDevice returns gorm.ErrRecordNotFound for a device the user has never signed in from
+	FindDevice(ctx context.Context, userID int64, fingerprint string) (*entity.DeviceEntity, error)
+	CountDevices(ctx context.Context, userID int64) (int64, error)
+	GetDevices(ctx context.Context, userID int64) ([]entity.DeviceEntity, error)
+	SetTrusted(ctx context.Context, userID, deviceID int64, trusted bool) error
+}
+
+type DeviceServiceInterface interface {
+	GetDevices(ctx context.Context, userID int64) ([]entity.DeviceEntity, error)
+	SetTrusted(ctx context.Context, userID, deviceID int64, trusted bool) error
+}
@@ -2,10 +2,38 @@ package port
 
 import (
 	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
 )
 
 type EmailInterface interface {
 	SendVerificationEmail(ctx context.Context, email, token string) error
 	SendEmailChangeVerificationEmail(ctx context.Context, email, token string) error
+	SendEmailChangeRevertEmail(ctx context.Context, email, newEmail, token string) error
+	SendAccountLockedAlertEmail(ctx context.Context, adminEmail string, userID int64, restoredEmail, replacedEmail string) error
 	SendPasswordResetEmail(ctx context.Context, email, token string) error
+	SendPasswordChangedEmail(ctx context.Context, email string, changedAt time.Time) error
+	// SendPasswordExpiryWarningEmail tells staff their password expires at expiresAt
+	SendPasswordExpiryWarningEmail(ctx context.Context, email, name string, expiresAt time.Time) error
+	SendNewDeviceSignInEmail(ctx context.Context, email, device, ipAddress, location string, signedInAt time.Time) error
+	SendSignInOTPEmail(ctx context.Context, email, code string, expiresAt time.Time) error
+	SendAccountMergedEmail(ctx context.Context, email, mergedEmail, survivingEmail string) error
+	SendCustomerInviteEmail(ctx context.Context, email, name, token string) error
+	SendPhotoRemovedEmail(ctx context.Context, email, name, reason string) error
+	SendCampaignEmail(ctx context.Context, email, name string, campaign *entity.CampaignEntity) error
+	// SendSupportTicketUpdateEmail tells the customer their ticket's new status; note may be empty
+	SendSupportTicketUpdateEmail(ctx context.Context, email, name string, ticket *entity.SupportTicketEntity, note string) error
+}
+
+// RecipientLanguageInterface looks up the saved email language of the account that owns an address
+type RecipientLanguageInterface interface {
+	GetLanguageByEmail(ctx context.Context, email string) (string, error)
+}
+
+// EmailOutboxInterface publishes email messages, buffering them in the job queue while RabbitMQ is down
+type EmailOutboxInterface interface {
+	// Publish returns nil once the message is either with RabbitMQ or buffered
+	Publish(ctx context.Context, email *entity.BufferedEmailEntity) error
+	// Flush publishes a buffered message and fails, without buffering again, while RabbitMQ is down
+	Flush(ctx context.Context, email *entity.BufferedEmailEntity) error
 }
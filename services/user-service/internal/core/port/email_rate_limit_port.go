@@ -0,0 +1,24 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type EmailRateLimitRepositoryInterface interface {
+	GetAllLimits(ctx context.Context) ([]entity.EmailRateLimitEntity, error)
+	UpsertLimit(ctx context.Context, limit *entity.EmailRateLimitEntity) (*entity.EmailRateLimitEntity, error)
+	// DeleteLimit returns gorm's "record not found" for an email type without a row
+	DeleteLimit(ctx context.Context, emailType string) error
+	// PublishLimits replaces the snapshot notification-service reads from Redis
+	PublishLimits(ctx context.Context, limits []entity.EmailRateLimitEntity) error
+}
+
+type EmailRateLimitServiceInterface interface {
+	GetLimits(ctx context.Context) ([]entity.EmailRateLimitEntity, error)
+	SetLimit(ctx context.Context, adminID int64, emailType string, maxPerHour int) (*entity.EmailRateLimitEntity, error)
+	// DeleteLimit puts the email type back on notification-service's default quota
+	DeleteLimit(ctx context.Context, adminID int64, emailType string) error
+	// SyncLimits republishes the stored limits, e.g. at start-up after Redis lost them
+	SyncLimits(ctx context.Context) error
+}
@@ -0,0 +1,10 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type EventPublisherInterface interface {
+	Publish(ctx context.Context, event *entity.EventEntity) error
+}
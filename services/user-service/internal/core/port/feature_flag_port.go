@@ -0,0 +1,30 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type FeatureFlagRepositoryInterface interface {
+	GetAllFlags(ctx context.Context) ([]entity.FeatureFlagEntity, error)
+	// GetFlagByKey returns the flag with its overrides, served from Redis when cached
+	GetFlagByKey(ctx context.Context, key string) (*entity.FeatureFlagEntity, error)
+	CreateFlag(ctx context.Context, flag *entity.FeatureFlagEntity) (*entity.FeatureFlagEntity, error)
+	UpdateFlag(ctx context.Context, key string, flag *entity.FeatureFlagEntity) (*entity.FeatureFlagEntity, error)
+	DeleteFlag(ctx context.Context, key string) error
+	SetOverride(ctx context.Context, key string, userID int64, enabled bool) error
+	DeleteOverride(ctx context.Context, key string, userID int64) error
+}
+
+type FeatureFlagServiceInterface interface {
+	// IsEnabled fails closed: unknown flags and lookup errors count as disabled
+	IsEnabled(ctx context.Context, key string, userID int64) bool
+	GetEnabledFlags(ctx context.Context, userID int64) ([]string, error)
+	GetAllFlags(ctx context.Context) ([]entity.FeatureFlagEntity, error)
+	GetFlagByKey(ctx context.Context, key string) (*entity.FeatureFlagEntity, error)
+	CreateFlag(ctx context.Context, flag *entity.FeatureFlagEntity) (*entity.FeatureFlagEntity, error)
+	UpdateFlag(ctx context.Context, key string, flag *entity.FeatureFlagEntity) (*entity.FeatureFlagEntity, error)
+	DeleteFlag(ctx context.Context, key string) error
+	SetOverride(ctx context.Context, key string, userID int64, enabled bool) error
+	DeleteOverride(ctx context.Context, key string, userID int64) error
+}
@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+	"errors"
+	"io"
+	"user-service/internal/core/domain/entity"
+)
+
+var (
+	// ErrFileInfected is returned by storage when a scanned upload is rejected
+	ErrFileInfected = errors.New("file is infected")
+	// ErrScannerUnavailable is returned when an upload could not be scanned and is not stored
+	ErrScannerUnavailable = errors.New("file scanner is unavailable")
+)
+
+// FileScannerInterface checks uploaded content for malware before it is stored
+type FileScannerInterface interface {
+	Scan(ctx context.Context, file io.Reader) (*entity.ScanResultEntity, error)
+}
@@ -0,0 +1,16 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type GeocoderInterface interface {
+	ReverseGeocode(ctx context.Context, lat, lng float64) (*entity.AddressEntity, error)
+	Geocode(ctx context.Context, address string) (*entity.AddressEntity, error)
+}
+
+type GeocodingServiceInterface interface {
+	ReverseGeocode(ctx context.Context, lat, lng float64) (*entity.AddressEntity, error)
+	Geocode(ctx context.Context, address string) (*entity.AddressEntity, error)
+}
@@ -0,0 +1,32 @@
+package port
+
+import (
+	"context"
+	"io"
+	"time"
+	"user-service/internal/core/domain/entity"
+)
+
+type IdentityRepositoryInterface interface {
+	// Create returns "identity verification already pending" when the user has one waiting
+	Create(ctx context.Context, verification *entity.IdentityVerificationEntity) (*entity.IdentityVerificationEntity, error)
+	GetByID(ctx context.Context, id int64) (*entity.IdentityVerificationEntity, error)
+	// GetLatest returns the user's newest submission, approved or not
+	GetLatest(ctx context.Context, userID int64) (*entity.IdentityVerificationEntity, error)
+	GetLatestApproved(ctx context.Context, userID int64) (*entity.IdentityVerificationEntity, error)
+	// List returns pending submissions oldest first, like a queue, and others newest first
+	List(ctx context.Context, status string, page, limit int) ([]entity.IdentityVerificationEntity, int64, error)
+	// Review decides a pending submission and returns "identity verification already reviewed"
+	// when it is not pending anymore
+	Review(ctx context.Context, id int64, update *entity.IdentityVerificationEntity) error
+}
+
+type IdentityServiceInterface interface {
+	Submit(ctx context.Context, userID int64, documentType string, dateOfBirth time.Time, file io.Reader) (*entity.IdentityVerificationEntity, error)
+	Status(ctx context.Context, userID int64) (*entity.IdentityStatusEntity, error)
+	GetVerifications(ctx context.Context, status string, page, limit int) ([]entity.IdentityVerificationEntity, *entity.PaginationEntity, error)
+	// GetVerification fills DocumentURL with a link that works for a few minutes
+	GetVerification(ctx context.Context, id int64) (*entity.IdentityVerificationEntity, error)
+	Approve(ctx context.Context, id, adminID int64) (*entity.IdentityVerificationEntity, error)
+	Reject(ctx context.Context, id, adminID int64, reason string) (*entity.IdentityVerificationEntity, error)
+}
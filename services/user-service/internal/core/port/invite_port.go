@@ -0,0 +1,31 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type InviteRepositoryInterface interface {
+	CreateInvite(ctx context.Context, invite *entity.InviteEntity) (*entity.InviteEntity, error)
+	GetInvites(ctx context.Context, page, limit int) ([]entity.InviteEntity, int64, error)
+	GetInviteByCode(ctx context.Context, code string) (*entity.InviteEntity, error)
+	// RevokeInvite returns gorm.ErrRecordNotFound when the invite does not exist or is already revoked
+	RevokeInvite(ctx context.Context, id int64) error
+	// ReserveUse takes one use of a usable code in a single statement, so concurrent sign-ups
+	// cannot go over MaxUses; ok is false when the code is unknown or no longer usable
+	ReserveUse(ctx context.Context, code string) (id int64, ok bool, err error)
+	ReleaseUse(ctx context.Context, id int64) error
+	CreateRedemption(ctx context.Context, id int64, email string) error
+}
+
+type InviteServiceInterface interface {
+	// CreateInvite generates a code unless the admin picked one
+	CreateInvite(ctx context.Context, invite *entity.InviteEntity) (*entity.InviteEntity, error)
+	GetInvites(ctx context.Context, page, limit int) ([]entity.InviteEntity, *entity.PaginationEntity, error)
+	RevokeInvite(ctx context.Context, id int64) error
+	// Reserve takes a use of the code before the account is created; Release gives it back
+	// when creating the account fails
+	Reserve(ctx context.Context, code string) (*entity.InviteEntity, error)
+	Release(ctx context.Context, invite *entity.InviteEntity)
+	RecordRedemption(ctx context.Context, invite *entity.InviteEntity, email string)
+}
@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type IPAccessRepositoryInterface interface {
+	GetRules(ctx context.Context, listType string) ([]entity.IPRuleEntity, error)
+	AddRule(ctx context.Context, rule *entity.IPRuleEntity) error
+	RemoveRule(ctx context.Context, listType, cidr string) error
+}
+
+type IPAccessServiceInterface interface {
+	// IsAllowed applies the denylist first, then the allowlist; an empty allowlist allows everyone
+	IsAllowed(ctx context.Context, ip string) (bool, string)
+	GetRules(ctx context.Context) ([]entity.IPRuleEntity, error)
+	AddRule(ctx context.Context, listType, cidr, note string, adminID int64, callerIP string) (*entity.IPRuleEntity, error)
+	RemoveRule(ctx context.Context, listType, cidr, callerIP string) error
+}
@@ -0,0 +1,29 @@
+package port
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
+)
+
+type JobRepositoryInterface interface {
+	Enqueue(ctx context.Context, job *entity.JobEntity) (*entity.JobEntity, error)
+	// ClaimNext locks the oldest due job of the given types for workerID, skipping rows other workers hold
+	ClaimNext(ctx context.Context, types []string, workerID string) (*entity.JobEntity, error)
+	MarkCompleted(ctx context.Context, id int64) error
+	// MarkFailed reschedules the job at retryAt, or fails it permanently when retryAt is nil
+	MarkFailed(ctx context.Context, id int64, lastError string, retryAt *time.Time) error
+	RequeueStale(ctx context.Context, lockedBefore time.Time) (int64, error)
+	DeleteCompletedBefore(ctx context.Context, before time.Time) (int64, error)
+	GetJobs(ctx context.Context, status, jobType string, page, limit int) ([]entity.JobEntity, int64, error)
+	RetryJob(ctx context.Context, id int64) error
+	// Release makes pending and failed jobs of jobType due now, with their attempts reset
+	Release(ctx context.Context, jobType string) (int64, error)
+}
+
+type JobServiceInterface interface {
+	Enqueue(ctx context.Context, jobType string, payload interface{}, runAt time.Time) (*entity.JobEntity, error)
+	EnqueueUnique(ctx context.Context, jobType, uniqueKey string, payload interface{}, runAt time.Time) (*entity.JobEntity, error)
+	GetJobs(ctx context.Context, status, jobType string, page, limit int) ([]entity.JobEntity, *entity.PaginationEntity, error)
+	RetryJob(ctx context.Context, id int64) error
+}
@@ -0,0 +1,31 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type LedgerRepositoryInterface interface {
+	// RecordTransaction stores a transaction and its entries atomically
+	RecordTransaction(ctx context.Context, transaction *entity.LedgerTransactionEntity) (*entity.LedgerTransactionEntity, error)
+	GetVendorBalance(ctx context.Context, vendorID int64) (*entity.VendorBalanceEntity, error)
+	GetVendorEntries(ctx context.Context, vendorID int64, page, limit int) ([]entity.LedgerEntryEntity, int64, error)
+	// CreateWithdrawal locks the vendor's balance, checks it covers the amount and holds the funds
+	CreateWithdrawal(ctx context.Context, withdrawal *entity.WithdrawalEntity) (*entity.WithdrawalEntity, error)
+	GetWithdrawalByID(ctx context.Context, id int64) (*entity.WithdrawalEntity, error)
+	GetWithdrawals(ctx context.Context, vendorID int64, status string, page, limit int) ([]entity.WithdrawalEntity, int64, error)
+	ApproveWithdrawal(ctx context.Context, id, reviewerID int64) error
+	RejectWithdrawal(ctx context.Context, id, reviewerID int64, reason string) error
+	GetLedgerAudit(ctx context.Context) (*entity.LedgerAuditEntity, error)
+}
+
+type LedgerServiceInterface interface {
+	RecordOrderEarning(ctx context.Context, vendorID int64, orderID string, grossAmount, adminID int64) (*entity.LedgerTransactionEntity, error)
+	GetBalance(ctx context.Context, vendorID int64) (*entity.VendorBalanceEntity, error)
+	GetLedgerEntries(ctx context.Context, vendorID int64, page, limit int) ([]entity.LedgerEntryEntity, *entity.PaginationEntity, error)
+	RequestWithdrawal(ctx context.Context, vendorID int64, withdrawal *entity.WithdrawalEntity) (*entity.WithdrawalEntity, error)
+	GetWithdrawals(ctx context.Context, vendorID int64, status string, page, limit int) ([]entity.WithdrawalEntity, *entity.PaginationEntity, error)
+	ApproveWithdrawal(ctx context.Context, id, adminID int64) error
+	RejectWithdrawal(ctx context.Context, id, adminID int64, reason string) error
+	AuditLedger(ctx context.Context) (*entity.LedgerAuditEntity, error)
+}
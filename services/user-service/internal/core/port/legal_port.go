@@ -0,0 +1,37 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type LegalRepositoryInterface interface {
+	CreateDocument(ctx context.Context, doc *entity.LegalDocumentEntity) (*entity.LegalDocumentEntity, error)
+	// GetDocuments lists every published version, newest first; an empty kind lists both kinds
+	GetDocuments(ctx context.Context, kind string) ([]entity.LegalDocumentEntity, error)
+	// GetCurrentDocuments returns the newest version of each kind
+	GetCurrentDocuments(ctx context.Context) ([]entity.LegalDocumentEntity, error)
+	// GetUserConsent fills only the Accepted* fields and ConsentedAt
+	GetUserConsent(ctx context.Context, userID int64) (*entity.ConsentStatusEntity, error)
+	GetUserIDByEmail(ctx context.Context, email string) (int64, error)
+	// SaveConsent updates the user's accepted versions and appends them to the history
+	SaveConsent(ctx context.Context, consent *entity.ConsentEntity) error
+	GetConsentHistory(ctx context.Context, userID int64) ([]entity.UserConsentEntity, error)
+}
+
+type LegalServiceInterface interface {
+	// PublishDocument makes a new version current; every user has to accept it before
+	// using the API again
+	PublishDocument(ctx context.Context, doc *entity.LegalDocumentEntity) (*entity.LegalDocumentEntity, error)
+	GetDocuments(ctx context.Context, kind string) ([]entity.LegalDocumentEntity, error)
+	GetCurrentDocuments(ctx context.Context) ([]entity.LegalDocumentEntity, error)
+	ConsentStatus(ctx context.Context, userID int64) (*entity.ConsentStatusEntity, error)
+	// Accept records consent to the current versions; the versions sent must be the current
+	// ones, so a client showing an old document cannot accept the new one
+	Accept(ctx context.Context, consent *entity.ConsentEntity) error
+	GetConsentHistory(ctx context.Context, userID int64) ([]entity.UserConsentEntity, error)
+	// CheckSignupConsent fails when documents are published and the new user did not accept them
+	CheckSignupConsent(ctx context.Context, consent *entity.ConsentEntity) error
+	// RecordSignupConsent stores the acceptance for the account just created with email
+	RecordSignupConsent(ctx context.Context, email string, consent *entity.ConsentEntity)
+}
@@ -0,0 +1,23 @@
+package port
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
+)
+
+type OnboardingRepositoryInterface interface {
+	// GetState returns an empty state for users that have no onboarding row yet
+	GetState(ctx context.Context, userID int64) (*entity.OnboardingStateEntity, error)
+	Dismiss(ctx context.Context, userID int64) error
+	// MarkFirstOrder keeps the earliest timestamp when called more than once
+	MarkFirstOrder(ctx context.Context, userID int64, orderedAt time.Time) error
+	// MarkCompleted reports whether this call set completed_at, so the event is emitted once
+	MarkCompleted(ctx context.Context, userID int64) (bool, error)
+}
+
+type OnboardingServiceInterface interface {
+	GetOnboarding(ctx context.Context, userID int64) (*entity.OnboardingEntity, error)
+	Dismiss(ctx context.Context, userID int64) error
+	RecordFirstOrder(ctx context.Context, userID int64, orderedAt time.Time) error
+}
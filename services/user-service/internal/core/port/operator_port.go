@@ -0,0 +1,19 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+// OperatorServiceInterface backs cmd/adminctl: account fixes an operator makes from a shell
+// instead of through the admin API or hand-written SQL. Users are looked up by email,
+// unverified accounts included.
+type OperatorServiceInterface interface {
+	// PromoteUser replaces the user's role and signs out their sessions, whose tokens carry the old one
+	PromoteUser(ctx context.Context, email, roleName string) (*entity.UserEntity, error)
+	VerifyUser(ctx context.Context, email string) (*entity.UserEntity, error)
+	// RevokeSessions signs the user out everywhere and returns how many sessions were open
+	RevokeSessions(ctx context.Context, email string) (int, error)
+	ResendVerification(ctx context.Context, email string) error
+	PurgeTokens(ctx context.Context) (*entity.TokenPurgeEntity, error)
+}
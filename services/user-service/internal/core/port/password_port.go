@@ -1,6 +1,24 @@
 package port
 
+import "context"
+
 type PasswordInterface interface {
 	HashPassword(password string) (string, error)
 	CheckPasswordHash(password, hash string) bool
+	// NeedsRehash reports a hash made with another algorithm or weaker settings than configured
+	NeedsRehash(hash string) bool
+}
+
+type PasswordPolicyServiceInterface interface {
+	// SendExpiryWarnings emails staff whose password expires within the warning period, once
+	// per password, and returns how many were sent
+	SendExpiryWarnings(ctx context.Context) (int, error)
+	PasswordExpiryCheckerInterface
+}
+
+// PasswordExpiryCheckerInterface ends staff sessions whose password expired after signing in
+type PasswordExpiryCheckerInterface interface {
+	// PasswordExpired reports whether the session was signed in with a password the policy has
+	// expired since; SSO sessions and roles outside the policy never are
+	PasswordExpired(ctx context.Context, userID int64, sessionID, roleName string) bool
 }
@@ -0,0 +1,35 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+// ImageModeratorInterface classifies an image reachable at imageURL
+type ImageModeratorInterface interface {
+	Moderate(ctx context.Context, imageURL string) (*entity.ModerationResultEntity, error)
+}
+
+type PhotoModerationRepositoryInterface interface {
+	Create(ctx context.Context, moderation *entity.PhotoModerationEntity) (*entity.PhotoModerationEntity, error)
+	GetByID(ctx context.Context, id int64) (*entity.PhotoModerationEntity, error)
+	List(ctx context.Context, status string, page, limit int) ([]entity.PhotoModerationEntity, int64, error)
+	// UpdateStatus moves a moderation out of one of fromStatuses and returns
+	// "photo moderation already decided" when it is in none of them anymore
+	UpdateStatus(ctx context.Context, id int64, fromStatuses []string, update *entity.PhotoModerationEntity) error
+}
+
+// PhotoModerationSubmitterInterface is what the profile photo upload calls; a nil submitter disables moderation
+type PhotoModerationSubmitterInterface interface {
+	Submit(ctx context.Context, userID int64, imageURL string) error
+}
+
+type PhotoModerationServiceInterface interface {
+	PhotoModerationSubmitterInterface
+	// Moderate runs the provider check for a queued moderation
+	Moderate(ctx context.Context, moderationID int64) error
+	GetModerations(ctx context.Context, status string, page, limit int) ([]entity.PhotoModerationEntity, *entity.PaginationEntity, error)
+	GetModerationByID(ctx context.Context, id int64) (*entity.PhotoModerationEntity, error)
+	Approve(ctx context.Context, id, adminID int64, note string) (*entity.PhotoModerationEntity, error)
+	Remove(ctx context.Context, id, adminID int64, reason string) (*entity.PhotoModerationEntity, error)
+}
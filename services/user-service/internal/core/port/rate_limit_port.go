@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
+)
+
+type RateLimitRepositoryInterface interface {
+	// Hit counts one request in key's current window, starting a new window of the given
+	// length when none is open, and returns the window as it stands after the hit
+	Hit(ctx context.Context, key string, limit int, window time.Duration) (entity.RateLimitEntity, error)
+}
@@ -0,0 +1,34 @@
+package port
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
+)
+
+type RiskRepositoryInterface interface {
+	IncrementFailedSignIns(ctx context.Context, email string, window time.Duration) (int64, error)
+	GetFailedSignIns(ctx context.Context, email string) (int64, error)
+	ResetFailedSignIns(ctx context.Context, email string) error
+	// GetLastLocation returns nil without error when the user has no recorded location
+	GetLastLocation(ctx context.Context, userID int64) (*entity.LoginLocationEntity, error)
+	SetLastLocation(ctx context.Context, userID int64, location entity.LoginLocationEntity) error
+	SaveChallenge(ctx context.Context, challenge *entity.StepUpChallengeEntity) error
+	GetChallenge(ctx context.Context, challengeID string) (*entity.StepUpChallengeEntity, error)
+	IncrementChallengeAttempts(ctx context.Context, challengeID string) (int, error)
+	DeleteChallenge(ctx context.Context, challengeID string) error
+}
+
+type IPLocatorInterface interface {
+	// Locate returns the approximate position of ip; City and Country are filled when known
+	Locate(ctx context.Context, ip string) (*entity.LoginLocationEntity, error)
+}
+
+type RiskServiceInterface interface {
+	Assess(ctx context.Context, user *entity.UserEntity, client entity.ClientEntity) *entity.RiskAssessmentEntity
+	RecordFailedSignIn(ctx context.Context, email string, userID int64, client entity.ClientEntity)
+	RecordSuccessfulSignIn(ctx context.Context, user *entity.UserEntity, location *entity.LoginLocationEntity)
+	// StartStepUp keeps rememberMe on the challenge so the session issued after the OTP honours it
+	StartStepUp(ctx context.Context, user *entity.UserEntity, client entity.ClientEntity, assessment *entity.RiskAssessmentEntity, rememberMe bool) (*entity.StepUpChallengeEntity, error)
+	VerifyStepUp(ctx context.Context, challengeID, code string, client entity.ClientEntity) (*entity.StepUpChallengeEntity, error)
+}
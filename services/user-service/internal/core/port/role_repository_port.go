@@ -7,8 +7,10 @@ import (
 
 type RoleRepositoryInterface interface {
 	GetAllRoles(ctx context.Context, search string) ([]entity.RoleEntity, error)
+	GetRoles(ctx context.Context, search string, page, limit int) ([]entity.RoleEntity, int64, error)
 	GetRoleByID(ctx context.Context, id int64) (*entity.RoleEntity, error)
 	CreateRole(ctx context.Context, role *entity.RoleEntity) (*entity.RoleEntity, error)
 	UpdateRole(ctx context.Context, id int64, role *entity.RoleEntity) (*entity.RoleEntity, error)
+	UpdateRoleParent(ctx context.Context, id int64, parentID *int64, version int64) (*entity.RoleEntity, error)
 	DeleteRole(ctx context.Context, id int64) error
 }
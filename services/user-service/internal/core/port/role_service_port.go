@@ -6,9 +6,18 @@ import (
 )
 
 type RoleServiceInterface interface {
-	GetAllRoles(ctx context.Context, search string) ([]entity.RoleEntity, error)
+	GetAllRoles(ctx context.Context, search string, page, limit int) ([]entity.RoleEntity, *entity.PaginationEntity, error)
 	GetRoleByID(ctx context.Context, id int64) (*entity.RoleEntity, error)
 	CreateRole(ctx context.Context, name string) (*entity.RoleEntity, error)
-	UpdateRole(ctx context.Context, id int64, name string) (*entity.RoleEntity, error)
+	UpdateRole(ctx context.Context, id int64, name string, version int64) (*entity.RoleEntity, error)
+	// SetRoleParent makes the role inherit from parentID, or from nothing when it is nil
+	SetRoleParent(ctx context.Context, id int64, parentID *int64, version int64) (*entity.RoleEntity, error)
 	DeleteRole(ctx context.Context, id int64) error
+	RoleResolverInterface
+}
+
+// RoleResolverInterface resolves role inheritance for access checks
+type RoleResolverInterface interface {
+	// EffectiveRoles returns roleName followed by every role it inherits from, nearest first
+	EffectiveRoles(ctx context.Context, roleName string) []string
 }
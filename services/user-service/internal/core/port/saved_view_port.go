@@ -0,0 +1,23 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type SavedViewRepositoryInterface interface {
+	// GetViews lists ownerID's views and the ones shared by other admins; an empty list matches every list
+	GetViews(ctx context.Context, ownerID int64, list string) ([]entity.SavedViewEntity, error)
+	GetViewByID(ctx context.Context, id int64) (*entity.SavedViewEntity, error)
+	CreateView(ctx context.Context, view *entity.SavedViewEntity) (*entity.SavedViewEntity, error)
+	UpdateView(ctx context.Context, view *entity.SavedViewEntity) (*entity.SavedViewEntity, error)
+	DeleteView(ctx context.Context, id int64) error
+}
+
+type SavedViewServiceInterface interface {
+	GetViews(ctx context.Context, adminID int64, list string) ([]entity.SavedViewEntity, error)
+	CreateView(ctx context.Context, view *entity.SavedViewEntity) (*entity.SavedViewEntity, error)
+	// UpdateView and DeleteView only touch views adminID owns; others' views look missing
+	UpdateView(ctx context.Context, adminID, id int64, view *entity.SavedViewEntity) (*entity.SavedViewEntity, error)
+	DeleteView(ctx context.Context, adminID, id int64) error
+}
@@ -0,0 +1,40 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type SCIMRepositoryInterface interface {
+	// ListUsers pages through SCIM-managed accounts; filter is nil or on userName or externalId
+	ListUsers(ctx context.Context, filter *entity.SCIMFilter, offset, limit int) ([]entity.SCIMUserEntity, int64, error)
+	// GetUser returns gorm.ErrRecordNotFound for accounts SCIM does not manage
+	GetUser(ctx context.Context, id int64) (*entity.SCIMUserEntity, error)
+	// CreateUser creates a verified, SCIM-managed account with the default Customer role
+	CreateUser(ctx context.Context, user *entity.SCIMUserEntity, hashedPassword string) (*entity.SCIMUserEntity, error)
+	// AdoptUser brings an existing account under SCIM management
+	AdoptUser(ctx context.Context, id int64, externalID string) error
+	// UpdateUser saves the identity fields and locks or unlocks the account to match Active
+	UpdateUser(ctx context.Context, user *entity.SCIMUserEntity) error
+	DeleteUser(ctx context.Context, id int64) error
+	// ListGroups pages through the roles named in names; filter is nil or on displayName
+	ListGroups(ctx context.Context, names []string, filter *entity.SCIMFilter, offset, limit int, withMembers bool) ([]entity.SCIMGroupEntity, int64, error)
+	// GetGroup returns gorm.ErrRecordNotFound unless the role is named in names
+	GetGroup(ctx context.Context, id int64, names []string) (*entity.SCIMGroupEntity, error)
+}
+
+type SCIMServiceInterface interface {
+	// ListUsers takes the SCIM filter, the 1-based startIndex and count of the request
+	ListUsers(ctx context.Context, filter string, startIndex, count int) ([]entity.SCIMUserEntity, int64, error)
+	GetUser(ctx context.Context, id int64) (*entity.SCIMUserEntity, error)
+	// CreateUser adopts an existing account that already holds a SCIM group's role
+	CreateUser(ctx context.Context, user entity.SCIMUserEntity) (*entity.SCIMUserEntity, error)
+	ReplaceUser(ctx context.Context, id int64, user entity.SCIMUserEntity) (*entity.SCIMUserEntity, error)
+	PatchUser(ctx context.Context, id int64, ops []entity.SCIMPatchOperation) (*entity.SCIMUserEntity, error)
+	DeleteUser(ctx context.Context, id int64) error
+	ListGroups(ctx context.Context, filter string, startIndex, count int, withMembers bool) ([]entity.SCIMGroupEntity, int64, error)
+	GetGroup(ctx context.Context, id int64) (*entity.SCIMGroupEntity, error)
+	// ReplaceGroupMembers makes memberIDs the only SCIM-managed holders of the group's role
+	ReplaceGroupMembers(ctx context.Context, id int64, memberIDs []int64) (*entity.SCIMGroupEntity, error)
+	PatchGroup(ctx context.Context, id int64, ops []entity.SCIMPatchOperation) (*entity.SCIMGroupEntity, error)
+}
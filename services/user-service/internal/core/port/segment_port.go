@@ -0,0 +1,37 @@
+package port
+
+import (
+	"context"
+	"time"
+	"user-service/internal/core/domain/entity"
+)
+
+type SegmentRepositoryInterface interface {
+	CreateSegment(ctx context.Context, segment *entity.SegmentEntity) (*entity.SegmentEntity, error)
+	GetSegments(ctx context.Context) ([]entity.SegmentEntity, error)
+	GetSegmentByID(ctx context.Context, id int64) (*entity.SegmentEntity, error)
+	DeleteSegment(ctx context.Context, id int64) error
+	// Materialize replaces the members with the customers matching the segment filter now
+	Materialize(ctx context.Context, id int64) (int64, error)
+	// GetMemberIDs pages through the members in user ID order, starting after afterUserID
+	GetMemberIDs(ctx context.Context, segmentID, afterUserID int64, limit int) ([]int64, error)
+	CreateCampaign(ctx context.Context, campaign *entity.CampaignEntity) (*entity.CampaignEntity, error)
+	UpdateCampaignFanOut(ctx context.Context, id, recipients int64, batches int) error
+	GetCampaignByID(ctx context.Context, id int64) (*entity.CampaignEntity, error)
+	// GetRecipients fills ID, Name and Email of the users that can still receive email
+	GetRecipients(ctx context.Context, userIDs []int64) ([]entity.UserEntity, error)
+	// RecordOrder reports whether the order was new; order-service may report an order more than once
+	RecordOrder(ctx context.Context, userID int64, orderID string, orderedAt time.Time) (bool, error)
+}
+
+type SegmentServiceInterface interface {
+	CreateSegment(ctx context.Context, segment *entity.SegmentEntity) (*entity.SegmentEntity, error)
+	GetSegments(ctx context.Context) ([]entity.SegmentEntity, error)
+	GetSegmentByID(ctx context.Context, id int64) (*entity.SegmentEntity, error)
+	RefreshSegment(ctx context.Context, id int64) (*entity.SegmentEntity, error)
+	DeleteSegment(ctx context.Context, id int64) error
+	// LaunchCampaign queues the members in batches spaced out over time and returns at once
+	LaunchCampaign(ctx context.Context, segmentID int64, campaign *entity.CampaignEntity) (*entity.CampaignEntity, error)
+	SendCampaignBatch(ctx context.Context, campaignID int64, userIDs []int64) error
+	RecordOrder(ctx context.Context, userID int64, orderID string, orderedAt time.Time) error
+}
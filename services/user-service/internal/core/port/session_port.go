@@ -6,10 +6,16 @@ import (
 )
 
 type SessionInterface interface {
-	StoreToken(ctx context.Context, userID int64, sessionID string, token string) error
+	// StoreToken saves the token for the session's lifetime, which rememberMe lengthens
+	StoreToken(ctx context.Context, userID int64, sessionID string, token string, rememberMe bool) error
 	GetToken(ctx context.Context, userID int64, sessionID string) (string, error)
 	DeleteToken(ctx context.Context, userID int64, sessionID string) error
 	DeleteAllUserTokens(ctx context.Context, userID int64) error
 	ValidateToken(ctx context.Context, userID int64, sessionID string, token string) bool
+	// SetSessionClient records who signed in; location is nil when the IP could not be resolved
+	SetSessionClient(ctx context.Context, userID int64, sessionID string, client entity.ClientEntity, location *entity.LoginLocationEntity) error
+	// MarkSSOSession records that the session was signed in through the identity provider
+	MarkSSOSession(ctx context.Context, userID int64, sessionID string) error
 	GetUserSessions(ctx context.Context, userID int64) ([]entity.SessionInfo, error)
+	GetSession(ctx context.Context, userID int64, sessionID string) (*entity.SessionInfo, error)
 }
@@ -0,0 +1,44 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type OIDCProviderInterface interface {
+	// AuthCodeURL is where the browser signs in; the provider sends it back to the redirect URL
+	// with a code. codeChallenge is the S256 PKCE challenge.
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange redeems the code and returns the claims of the verified ID token
+	Exchange(ctx context.Context, code, codeVerifier string) (*entity.OIDCIdentityEntity, error)
+}
+
+type SSOStateRepositoryInterface interface {
+	SaveState(ctx context.Context, state *entity.SSOStateEntity) error
+	// TakeState returns and deletes the state so it can only be used once; "sso state not found"
+	// when it expired or was used
+	TakeState(ctx context.Context, id string) (*entity.SSOStateEntity, error)
+}
+
+type SSORepositoryInterface interface {
+	// GetUserIDBySubject returns gorm.ErrRecordNotFound when no account is linked to subject
+	GetUserIDBySubject(ctx context.Context, subject string) (int64, error)
+	// LinkSubject also marks the account verified, since the provider vouched for its email
+	LinkSubject(ctx context.Context, userID int64, subject string) error
+	// CreateUser creates a verified staff account with roleName, linked to subject
+	CreateUser(ctx context.Context, user *entity.UserEntity, hashedPassword, roleName, subject string) (int64, error)
+}
+
+// ExternalSignInInterface is the part of the user service that opens a session for a user an
+// identity provider authenticated
+type ExternalSignInInterface interface {
+	SignInExternal(ctx context.Context, userID int64, client entity.ClientEntity, rememberMe bool) (*entity.UserEntity, string, error)
+}
+
+type SSOServiceInterface interface {
+	// StartLogin returns the provider's sign-in URL and the pending state, whose ID the
+	// browser must present again on the callback
+	StartLogin(ctx context.Context, rememberMe bool) (string, *entity.SSOStateEntity, error)
+	// CompleteLogin redeems the code, maps the user's groups to a role and opens a session
+	CompleteLogin(ctx context.Context, stateID, code string, client entity.ClientEntity) (*entity.UserEntity, string, error)
+}
@@ -0,0 +1,13 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type StorageEventServiceInterface interface {
+	// HandleEvent reconciles users.photo with an object created in or deleted from the bucket
+	HandleEvent(ctx context.Context, event entity.StorageEventEntity) error
+	// ReconcileProfilePhoto deletes the object when no user references it
+	ReconcileProfilePhoto(ctx context.Context, bucket, objectName string) error
+}
@@ -2,10 +2,52 @@ package port
 
 import (
 	"context"
+	"errors"
 	"io"
+	"time"
+	"user-service/internal/core/domain/entity"
 )
 
 type StorageInterface interface {
 	UploadFile(ctx context.Context, bucketName, objectName string, file io.Reader, contentType string) (string, error)
 	DeleteFile(ctx context.Context, bucketName, objectName string) error
 }
+
+// ErrFileTooLarge is returned by storage backends that refuse a file above their size limit
+var ErrFileTooLarge = errors.New("file exceeds the storage size limit")
+
+// ErrSignedURLUnsupported is returned by storage backends that cannot sign links
+var ErrSignedURLUnsupported = errors.New("storage does not support signed URLs")
+
+// SignedURLInterface is implemented by storage that can hand out temporary links to objects
+// in private buckets. Callers type-assert a StorageInterface for it.
+type SignedURLInterface interface {
+	SignedURL(ctx context.Context, bucketName, objectName string, expiresIn time.Duration) (string, error)
+}
+
+// ChunkStoreInterface holds resumable uploads until they are assembled. Chunks are written
+// whole or not at all, so a chunk that is listed is complete.
+type ChunkStoreInterface interface {
+	CreateSession(ctx context.Context, session *entity.UploadSessionEntity) error
+	// GetSession fills ReceivedChunks from the chunks stored so far
+	GetSession(ctx context.Context, uploadID string) (*entity.UploadSessionEntity, error)
+	// WriteChunk stores at most maxBytes+1 bytes of data, so an oversized chunk can be detected
+	WriteChunk(ctx context.Context, uploadID string, index int, data io.Reader, maxBytes int64) (int64, error)
+	DeleteChunk(ctx context.Context, uploadID string, index int) error
+	// OpenAssembled reads all chunks back to back, in index order
+	OpenAssembled(ctx context.Context, uploadID string, totalChunks int) (io.ReadCloser, error)
+	DeleteSession(ctx context.Context, uploadID string) error
+	// ListExpired returns the sessions whose ExpiresAt is before the given time
+	ListExpired(ctx context.Context, before time.Time) ([]string, error)
+}
+
+type UploadServiceInterface interface {
+	CreateUpload(ctx context.Context, userID int64, filename, contentType string, size int64, checksum string) (*entity.UploadSessionEntity, error)
+	GetUpload(ctx context.Context, userID int64, uploadID string) (*entity.UploadSessionEntity, error)
+	// UploadChunk verifies the chunk length and, when given, its hex SHA-256
+	UploadChunk(ctx context.Context, userID int64, uploadID string, index int, data io.Reader, checksum string) (*entity.UploadSessionEntity, error)
+	CompleteUpload(ctx context.Context, userID int64, uploadID string) (*entity.UploadResultEntity, error)
+	AbortUpload(ctx context.Context, userID int64, uploadID string) error
+	// CleanupExpired removes abandoned uploads and returns how many were removed
+	CleanupExpired(ctx context.Context) (int, error)
+}
@@ -0,0 +1,22 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+// StoredObjectRepositoryInterface counts the references to each content-addressed object, so
+// an object shared by identical uploads is only deleted once nothing uses it
+type StoredObjectRepositoryInterface interface {
+	// GetObject returns gorm's "record not found" unless the object has references left
+	GetObject(ctx context.Context, bucket, objectName string) (*entity.StoredObjectEntity, error)
+	// AddReference records the object with one reference, or adds one if it is already recorded
+	AddReference(ctx context.Context, object *entity.StoredObjectEntity) (*entity.StoredObjectEntity, error)
+	// RemoveReference returns the references left, or gorm's "record not found" for an
+	// object that is not recorded
+	RemoveReference(ctx context.Context, bucket, objectName string) (int, error)
+	// DeleteUnreferenced removes the record if it still has no references and reports whether it did
+	DeleteUnreferenced(ctx context.Context, bucket, objectName string) (bool, error)
+	// ForgetObject removes the record whatever its references, for objects deleted outside the service
+	ForgetObject(ctx context.Context, bucket, objectName string) error
+}
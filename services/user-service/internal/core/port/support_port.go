@@ -0,0 +1,31 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type SupportRepositoryInterface interface {
+	Create(ctx context.Context, ticket *entity.SupportTicketEntity) (*entity.SupportTicketEntity, error)
+	GetByID(ctx context.Context, id int64) (*entity.SupportTicketEntity, error)
+	// ListByUser returns the customer's own tickets, newest first
+	ListByUser(ctx context.Context, userID int64, page, limit int) ([]entity.SupportTicketEntity, int64, error)
+	// List returns tickets for staff, oldest first so the queue is worked in order
+	List(ctx context.Context, filter entity.SupportTicketFilter, page, limit int) ([]entity.SupportTicketEntity, int64, error)
+	Assign(ctx context.Context, id, assigneeID int64) error
+	// UpdateStatus sets resolved_at when the ticket is resolved or closed and clears it on reopen
+	UpdateStatus(ctx context.Context, id int64, status string) error
+}
+
+type SupportServiceInterface interface {
+	CreateTicket(ctx context.Context, userID int64, category, subject, message string, uploads []entity.SupportUploadEntity) (*entity.SupportTicketEntity, error)
+	GetMyTickets(ctx context.Context, userID int64, page, limit int) ([]entity.SupportTicketEntity, *entity.PaginationEntity, error)
+	// GetMyTicket returns "support ticket not found" for tickets of other users
+	GetMyTicket(ctx context.Context, userID, id int64) (*entity.SupportTicketEntity, error)
+	GetTickets(ctx context.Context, filter entity.SupportTicketFilter, page, limit int) ([]entity.SupportTicketEntity, *entity.PaginationEntity, error)
+	GetTicket(ctx context.Context, id int64) (*entity.SupportTicketEntity, error)
+	// Assign hands the ticket to a staff member; customers cannot be assignees
+	Assign(ctx context.Context, id, assigneeID, adminID int64) (*entity.SupportTicketEntity, error)
+	// UpdateStatus emails the customer the new status, with the optional note from staff
+	UpdateStatus(ctx context.Context, id int64, status, note string, adminID int64) (*entity.SupportTicketEntity, error)
+}
@@ -0,0 +1,11 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type TokenIntrospectionServiceInterface interface {
+	// Introspect applies the same checks as JWTMiddleware: signature and expiry, blacklist, then the Redis session
+	Introspect(ctx context.Context, token string) (*entity.TokenIntrospectionEntity, error)
+}
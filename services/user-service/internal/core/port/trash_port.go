@@ -0,0 +1,26 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+// TrashRepositoryInterface only ever touches rows whose deleted_at is set,
+// so live records cannot be restored or purged through it by accident.
+type TrashRepositoryInterface interface {
+	GetDeletedUsers(ctx context.Context, search string, page, limit int) ([]entity.TrashedUserEntity, int64, error)
+	RestoreUser(ctx context.Context, userID int64) (*entity.TrashedUserEntity, error)
+	PurgeUser(ctx context.Context, userID int64) (*entity.TrashedUserEntity, error)
+	GetDeletedRoles(ctx context.Context, search string, page, limit int) ([]entity.TrashedRoleEntity, int64, error)
+	RestoreRole(ctx context.Context, roleID int64) (*entity.TrashedRoleEntity, error)
+	PurgeRole(ctx context.Context, roleID int64) (*entity.TrashedRoleEntity, error)
+}
+
+type TrashServiceInterface interface {
+	GetDeletedUsers(ctx context.Context, search string, page, limit int) ([]entity.TrashedUserEntity, *entity.PaginationEntity, error)
+	RestoreUser(ctx context.Context, userID, adminID int64) (*entity.TrashedUserEntity, error)
+	PurgeUser(ctx context.Context, userID, adminID int64) error
+	GetDeletedRoles(ctx context.Context, search string, page, limit int) ([]entity.TrashedRoleEntity, *entity.PaginationEntity, error)
+	RestoreRole(ctx context.Context, roleID, adminID int64) (*entity.TrashedRoleEntity, error)
+	PurgeRole(ctx context.Context, roleID, adminID int64) error
+}
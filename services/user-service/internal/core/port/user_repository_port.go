@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"time"
 	"user-service/internal/core/domain/entity"
 )
 
@@ -10,12 +11,49 @@ type UserRepositoryInterface interface {
 	CreateUser(ctx context.Context, user *entity.UserEntity) (*entity.UserEntity, error)
 	GetRoleByName(ctx context.Context, name string) (*entity.RoleEntity, error)
 	UpdateUserVerificationStatus(ctx context.Context, userID int64, isVerified bool) error
+	// UpdateUserRole replaces whatever roles the user has with roleID
+	UpdateUserRole(ctx context.Context, userID, roleID int64) error
 	GetUserByEmailIncludingUnverified(ctx context.Context, email string) (*entity.UserEntity, error)
+	// UpdateUserPassword only swaps the hash, e.g. to upgrade it; it is not a password change
 	UpdateUserPassword(ctx context.Context, userID int64, hashedPassword string) error
+	// ChangeUserPassword stores a password the user chose: the replaced hash joins the history,
+	// which is trimmed to keepHistory entries, and the rotation clock starts again
+	ChangeUserPassword(ctx context.Context, userID int64, hashedPassword string, keepHistory int) error
+	// GetPasswordHistory returns up to limit earlier password hashes, newest first
+	GetPasswordHistory(ctx context.Context, userID int64, limit int) ([]string, error)
+	// GetPasswordsDueForWarning lists verified accounts with one of roles whose password was set
+	// between changedAfter and changedBefore and not warned about yet, by ID after afterID
+	GetPasswordsDueForWarning(ctx context.Context, roles []string, changedAfter, changedBefore time.Time, afterID int64, limit int) ([]entity.PasswordExpiryEntity, error)
+	MarkPasswordExpiryWarned(ctx context.Context, userID int64) error
 	GetUserByID(ctx context.Context, userID int64) (*entity.UserEntity, error)
 	UpdateUserPhoto(ctx context.Context, userID int64, photoURL string) error
+	UpdateUserPhotos(ctx context.Context, userID int64, photoURL, originalURL string) error
 	UpdateUserEmail(ctx context.Context, userID int64, email string) error
-	UpdateUserProfile(ctx context.Context, userID int64, name, email, phone, address string, lat, lng float64, photo string) error
+	UpdateUserProfile(ctx context.Context, userID int64, name, email, phone, address string, lat, lng float64, photo string, version int64) error
+	UpdateUserAddressComponents(ctx context.Context, userID int64, address entity.AddressEntity) error
+	// GetCustomers fills only the list fields: ID, Name, Email, Username, Photo, Phone and Privacy.HidePhone
 	GetCustomers(ctx context.Context, search string, page, limit int, orderBy string) ([]entity.UserEntity, int64, error)
+	// SuggestCustomers matches query anywhere in the name or email and fills ID, Name, Email and Photo
+	SuggestCustomers(ctx context.Context, query string, limit int) ([]entity.UserEntity, error)
 	GetCustomerByID(ctx context.Context, customerID int64) (*entity.UserEntity, error)
+	GetCustomersNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]entity.UserEntity, error)
+	GetUsersByIDs(ctx context.Context, userIDs []int64) ([]entity.UserEntity, error)
+	GetUserByUsername(ctx context.Context, username string) (*entity.UserEntity, error)
+	IsUsernameTaken(ctx context.Context, username string) (bool, error)
+	UpdateUsername(ctx context.Context, userID int64, username string) error
+	UpdateTimezone(ctx context.Context, userID int64, timezone string) error
+	UpdateLanguage(ctx context.Context, userID int64, language string) error
+	UpdatePrivacySettings(ctx context.Context, userID int64, settings entity.PrivacySettingsEntity) error
+	GetLanguageByEmail(ctx context.Context, email string) (string, error)
+	// ClearPhotoByURL empties the photo of every user, deleted ones included, that uses photoURL
+	ClearPhotoByURL(ctx context.Context, photoURL string) ([]int64, error)
+	// IsPhotoReferenced also counts photos kept as the original of a cropped one
+	IsPhotoReferenced(ctx context.Context, photoURL string) (bool, error)
+	// RestoreEmailAndLock and UnlockUser return gorm.ErrRecordNotFound when no row matched
+	RestoreEmailAndLock(ctx context.Context, userID int64, email, reason string) error
+	UnlockUser(ctx context.Context, userID int64) error
+	// MarkEmailBounced flags email as undeliverable, but only while it is still the user's email
+	MarkEmailBounced(ctx context.Context, userID int64, email, reason string) error
+	GetLockedUsers(ctx context.Context) ([]entity.UserEntity, error)
+	GetEmailsByRole(ctx context.Context, roleName string) ([]string, error)
 }
@@ -7,16 +7,35 @@ import (
 )
 
 type UserServiceInterface interface {
-	SignIn(ctx context.Context, req entity.UserEntity) (*entity.UserEntity, string, error)
-	CreateUserAccount(ctx context.Context, email, name, password, passwordConfirmation string) error
+	SignIn(ctx context.Context, req entity.UserEntity, client entity.ClientEntity, rememberMe bool) (*entity.UserEntity, string, error)
+	VerifySignInOTP(ctx context.Context, challengeID, code string, client entity.ClientEntity) (*entity.UserEntity, string, error)
+	// SignInExternal opens a session for a user an identity provider authenticated
+	SignInExternal(ctx context.Context, userID int64, client entity.ClientEntity, rememberMe bool) (*entity.UserEntity, string, error)
+	// CreateUserAccount requires inviteCode when registration is invite only, and consent
+	// (nil when the terms were not accepted) once legal documents are published
+	CreateUserAccount(ctx context.Context, email, name, password, passwordConfirmation, inviteCode string, lat, lng float64, consent *entity.ConsentEntity) error
 	VerifyUserAccount(ctx context.Context, token string) error
 	VerifyEmailChange(ctx context.Context, token string) error
 	ForgotPassword(ctx context.Context, email string) error
+	// ResendVerificationEmail fails with "email address is undeliverable" after a hard bounce
+	ResendVerificationEmail(ctx context.Context, email string) error
+	RecordEmailBounce(ctx context.Context, email, bounceType, reason string) error
 	ResetPassword(ctx context.Context, token, newPassword, passwordConfirmation string) error
 	Logout(ctx context.Context, userID int64, sessionID, tokenString string, tokenExpiresAt int64) error
+	RefreshSession(ctx context.Context, userID int64, sessionID, tokenString string, tokenExpiresAt int64) (*entity.UserEntity, string, error)
+	GetSessions(ctx context.Context, userID int64, page, limit int) ([]entity.SessionInfo, *entity.PaginationEntity, error)
 	GetProfile(ctx context.Context, userID int64) (*entity.UserEntity, error)
 	UploadProfileImage(ctx context.Context, userID int64, file io.Reader, contentType, filename string) (string, error)
-	UpdateProfile(ctx context.Context, userID int64, name, email, phone, address string, lat, lng float64, photo string) error
+	UploadProfilePhoto(ctx context.Context, userID int64, file io.Reader, contentType, filename string, edit entity.PhotoEditEntity) (*entity.ProfilePhotoEntity, error)
+	RegenerateAvatar(ctx context.Context, userID int64) (string, error)
+	UpdateProfile(ctx context.Context, userID int64, name, email, phone, address string, lat, lng float64, photo string, version int64) error
+	CheckUsernameAvailability(ctx context.Context, username string) (*entity.UsernameAvailabilityEntity, error)
+	ChangeUsername(ctx context.Context, userID int64, username string) (string, error)
+	ChangeTimezone(ctx context.Context, userID int64, timezone string) (string, error)
+	ChangeLanguage(ctx context.Context, userID int64, language string) (string, error)
+	ChangePrivacySettings(ctx context.Context, userID int64, settings entity.PrivacySettingsEntity) (*entity.PrivacySettingsEntity, error)
 	GetCustomers(ctx context.Context, search string, page, limit int, orderBy string) ([]entity.UserEntity, *entity.PaginationEntity, error)
 	GetCustomerByID(ctx context.Context, customerID int64) (*entity.UserEntity, error)
+	GetCustomersNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]entity.UserEntity, error)
+	GetUsersByIDs(ctx context.Context, userIDs []int64) ([]entity.UserEntity, error)
 }
@@ -0,0 +1,29 @@
+package port
+
+import (
+	"context"
+	"io"
+	"user-service/internal/core/domain/entity"
+)
+
+type VendorRepositoryInterface interface {
+	CreateVendor(ctx context.Context, vendor *entity.VendorEntity) (*entity.VendorEntity, error)
+	GetVendorByID(ctx context.Context, id int64) (*entity.VendorEntity, error)
+	GetVendorByUserID(ctx context.Context, userID int64) (*entity.VendorEntity, error)
+	GetVendors(ctx context.Context, status string, page, limit int) ([]entity.VendorEntity, int64, error)
+	AddVendorDocument(ctx context.Context, document *entity.VendorDocumentEntity) (*entity.VendorDocumentEntity, error)
+	// ApproveVendor marks the vendor approved and gives its user the Vendor role in one transaction
+	ApproveVendor(ctx context.Context, id, reviewerID int64) error
+	RejectVendor(ctx context.Context, id, reviewerID int64, reason string) error
+}
+
+type VendorServiceInterface interface {
+	RegisterVendor(ctx context.Context, userID int64, vendor *entity.VendorEntity) (*entity.VendorEntity, error)
+	GetMyVendor(ctx context.Context, userID int64) (*entity.VendorEntity, error)
+	GetApprovedVendorByUserID(ctx context.Context, userID int64) (*entity.VendorEntity, error)
+	UploadDocument(ctx context.Context, userID int64, documentType string, file io.Reader, contentType, filename string) (*entity.VendorDocumentEntity, error)
+	GetVendors(ctx context.Context, status string, page, limit int) ([]entity.VendorEntity, *entity.PaginationEntity, error)
+	GetVendorByID(ctx context.Context, id int64) (*entity.VendorEntity, error)
+	ApproveVendor(ctx context.Context, id, adminID int64) error
+	RejectVendor(ctx context.Context, id, adminID int64, reason string) error
+}
@@ -2,11 +2,24 @@ package port
 
 import (
 	"context"
+	"errors"
 	"user-service/internal/core/domain/entity"
 )
 
+// ErrTokenAlreadyUsed is returned for a token that has not expired but was claimed before
+var ErrTokenAlreadyUsed = errors.New("token already used")
+
+// VerificationTokenInterface takes plaintext tokens; only their SHA-256 hash is stored
 type VerificationTokenInterface interface {
 	CreateVerificationToken(ctx context.Context, token *entity.VerificationTokenEntity) error
+	// GetVerificationToken returns gorm.ErrRecordNotFound for unknown or expired tokens and
+	// ErrTokenAlreadyUsed for claimed ones
 	GetVerificationToken(ctx context.Context, token string) (*entity.VerificationTokenEntity, error)
-	DeleteVerificationToken(ctx context.Context, token string) error
+	// ClaimVerificationToken marks the token used; of concurrent claims only one succeeds
+	ClaimVerificationToken(ctx context.Context, token string) error
+	// ReleaseVerificationToken undoes a claim when the action it guarded failed
+	ReleaseVerificationToken(ctx context.Context, token string) error
+	DeleteExpiredVerificationTokens(ctx context.Context) (int64, error)
+	// DeleteUserTokens removes the user's outstanding tokens of tokenType, so their links stop working
+	DeleteUserTokens(ctx context.Context, userID int64, tokenType string) (int64, error)
 }
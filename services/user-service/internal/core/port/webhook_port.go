@@ -0,0 +1,46 @@
+package port
+
+import (
+	"context"
+	"user-service/internal/core/domain/entity"
+)
+
+type WebhookRepositoryInterface interface {
+	CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpointEntity) (*entity.WebhookEndpointEntity, error)
+	GetEndpoints(ctx context.Context) ([]entity.WebhookEndpointEntity, error)
+	GetEndpointByID(ctx context.Context, id int64) (*entity.WebhookEndpointEntity, error)
+	UpdateEndpoint(ctx context.Context, id int64, endpoint *entity.WebhookEndpointEntity) (*entity.WebhookEndpointEntity, error)
+	DeleteEndpoint(ctx context.Context, id int64) error
+	GetActiveEndpointsForEvent(ctx context.Context, eventType string) ([]entity.WebhookEndpointEntity, error)
+	// CreateDelivery returns "webhook delivery already exists" when the endpoint already has the event
+	CreateDelivery(ctx context.Context, delivery *entity.WebhookDeliveryEntity) (*entity.WebhookDeliveryEntity, error)
+	GetDeliveryByID(ctx context.Context, id int64) (*entity.WebhookDeliveryEntity, error)
+	RecordAttempt(ctx context.Context, id int64, attempt *entity.WebhookAttemptEntity) error
+	ResetDelivery(ctx context.Context, id int64) error
+	GetDeliveries(ctx context.Context, endpointID int64, status string, page, limit int) ([]entity.WebhookDeliveryEntity, int64, error)
+}
+
+// WebhookSenderInterface posts a signed payload to an endpoint
+type WebhookSenderInterface interface {
+	Send(ctx context.Context, url string, headers map[string]string, body []byte) (*entity.WebhookResponseEntity, error)
+}
+
+// WebhookDispatcherInterface is what features call to raise an event; a nil dispatcher disables webhooks
+type WebhookDispatcherInterface interface {
+	// Dispatch queues a delivery to every active endpoint subscribed to eventType.
+	// eventID must be stable for the event so repeated dispatches are delivered once.
+	Dispatch(ctx context.Context, eventType, eventID string, data interface{}) error
+}
+
+type WebhookServiceInterface interface {
+	WebhookDispatcherInterface
+	CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpointEntity) (*entity.WebhookEndpointEntity, error)
+	GetEndpoints(ctx context.Context) ([]entity.WebhookEndpointEntity, error)
+	GetEndpointByID(ctx context.Context, id int64) (*entity.WebhookEndpointEntity, error)
+	UpdateEndpoint(ctx context.Context, id int64, endpoint *entity.WebhookEndpointEntity) (*entity.WebhookEndpointEntity, error)
+	DeleteEndpoint(ctx context.Context, id int64) error
+	GetDeliveries(ctx context.Context, endpointID int64, status string, page, limit int) ([]entity.WebhookDeliveryEntity, *entity.PaginationEntity, error)
+	Redeliver(ctx context.Context, deliveryID int64) error
+	// Deliver makes one attempt; lastAttempt marks the delivery failed instead of pending on error
+	Deliver(ctx context.Context, deliveryID int64, lastAttempt bool) error
+}
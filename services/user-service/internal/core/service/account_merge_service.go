@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+type AccountMergeService struct {
+	mergeRepo       port.AccountMergeRepositoryInterface
+	sessionRepo     port.SessionInterface
+	emailPublisher  port.EmailInterface
+	jobService      port.JobServiceInterface
+	auditLogService port.AuditLogServiceInterface
+}
+
+func (s *AccountMergeService) MergeAccounts(ctx context.Context, sourceUserID, targetUserID, adminID int64, reason string, dryRun bool) (*entity.AccountMergeEntity, error) {
+	if sourceUserID <= 0 || targetUserID <= 0 {
+		return nil, errors.New("invalid user id")
+	}
+	if sourceUserID == targetUserID {
+		return nil, errors.New("cannot merge an account into itself")
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" && !dryRun {
+		return nil, errors.New("merge reason is required")
+	}
+
+	result, err := s.mergeRepo.MergeAccounts(ctx, sourceUserID, targetUserID, dryRun)
+	if err != nil {
+		switch err.Error() {
+		case "source user not found", "target user not found", "both accounts have vendor profiles":
+			return nil, err
+		default:
+			log.Error().Err(err).Int64("source_user_id", sourceUserID).Int64("target_user_id", targetUserID).Msg("[AccountMergeService-MergeAccounts] Failed to merge accounts")
+			return nil, errors.New("failed to merge accounts")
+		}
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	// Everything below runs after the commit and is best-effort; the merge itself already succeeded.
+	// Tokens carry the source user id, so they are revoked rather than moved.
+	if err := s.sessionRepo.DeleteAllUserTokens(ctx, sourceUserID); err != nil {
+		log.Error().Err(err).Int64("user_id", sourceUserID).Msg("[AccountMergeService-MergeAccounts] Failed to revoke sessions of merged account")
+	}
+
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{
+		UserID: targetUserID,
+		Event:  entity.AuditEventAccountMerged,
+		Metadata: map[string]interface{}{
+			"source_user_id":          sourceUserID,
+			"source_email":            result.SourceEmail,
+			"admin_id":                adminID,
+			"reason":                  reason,
+			"profile_fields_copied":   result.ProfileFieldsCopied,
+			"devices_moved":           result.DevicesMoved,
+			"feature_overrides_moved": result.FeatureOverridesMoved,
+			"vendor_moved":            result.VendorMoved,
+		},
+	})
+
+	s.emitMerged(ctx, result)
+	s.notify(ctx, result)
+
+	log.Info().Int64("source_user_id", sourceUserID).Int64("target_user_id", targetUserID).Int64("admin_id", adminID).Msg("[AccountMergeService-MergeAccounts] Accounts merged")
+	return result, nil
+}
+
+// emitMerged lets the services that own orders and carts reassign them to the surviving account
+func (s *AccountMergeService) emitMerged(ctx context.Context, result *entity.AccountMergeEntity) {
+	eventID := fmt.Sprintf("%s:%d", entity.EventUserMerged, result.SourceUserID)
+	event := entity.EventEntity{
+		ID:         eventID,
+		Type:       entity.EventUserMerged,
+		OccurredAt: result.MergedAt,
+		Data: map[string]interface{}{
+			"source_user_id": result.SourceUserID,
+			"target_user_id": result.TargetUserID,
+		},
+	}
+
+	if _, err := s.jobService.EnqueueUnique(ctx, entity.JobTypePublishEvent, eventID, event, time.Time{}); err != nil && err.Error() != "job already enqueued" {
+		log.Error().Err(err).Int64("source_user_id", result.SourceUserID).Msg("[AccountMergeService-emitMerged] Failed to queue user merged event")
+	}
+}
+
+// notify tells both addresses, so the owner learns about the merge whichever inbox they still read
+func (s *AccountMergeService) notify(ctx context.Context, result *entity.AccountMergeEntity) {
+	if s.emailPublisher == nil {
+		return
+	}
+
+	for _, email := range []string{result.TargetEmail, result.SourceEmail} {
+		if err := s.emailPublisher.SendAccountMergedEmail(ctx, email, result.SourceEmail, result.TargetEmail); err != nil {
+			log.Error().Err(err).Str("email", email).Msg("[AccountMergeService-notify] Failed to send account merged email")
+		}
+	}
+}
+
+func NewAccountMergeService(mergeRepo port.AccountMergeRepositoryInterface, sessionRepo port.SessionInterface, emailPublisher port.EmailInterface, jobService port.JobServiceInterface, auditLogService port.AuditLogServiceInterface) port.AccountMergeServiceInterface {
+	return &AccountMergeService{
+		mergeRepo:       mergeRepo,
+		sessionRepo:     sessionRepo,
+		emailPublisher:  emailPublisher,
+		jobService:      jobService,
+		auditLogService: auditLogService,
+	}
+}
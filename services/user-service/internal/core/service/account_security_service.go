@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const emailChangeRevertedReason = "email change reverted by previous owner"
+
+type AccountSecurityService struct {
+	userRepo              port.UserRepositoryInterface
+	verificationTokenRepo port.VerificationTokenInterface
+	sessionRepo           port.SessionInterface
+	emailPublisher        port.EmailInterface
+	auditLogService       port.AuditLogServiceInterface
+}
+
+func (s *AccountSecurityService) RevertEmailChange(ctx context.Context, token string) error {
+	revertToken, err := s.verificationTokenRepo.GetVerificationToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, port.ErrTokenAlreadyUsed) {
+			log.Warn().Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AccountSecurityService-RevertEmailChange] Revert token was already used")
+			return err
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warn().Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AccountSecurityService-RevertEmailChange] Revert token not found or expired")
+			return errors.New("invalid or expired revert token")
+		}
+		log.Error().Err(err).Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AccountSecurityService-RevertEmailChange] Failed to get revert token")
+		return errors.New("failed to revert email change")
+	}
+
+	if revertToken.TokenType != entity.TokenTypeEmailChangeRevert || revertToken.NewEmail == "" {
+		log.Warn().Str("token_fingerprint", utils.SecretFingerprint(token)).Str("token_type", revertToken.TokenType).Msg("[AccountSecurityService-RevertEmailChange] Token is not for an email change revert")
+		return errors.New("invalid token type")
+	}
+
+	if err := s.verificationTokenRepo.ClaimVerificationToken(ctx, token); err != nil {
+		log.Warn().Err(err).Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AccountSecurityService-RevertEmailChange] Failed to claim revert token")
+		return claimError(err, "invalid or expired revert token", "failed to revert email change")
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, revertToken.UserID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", revertToken.UserID).Msg("[AccountSecurityService-RevertEmailChange] Failed to get user")
+		s.release(ctx, token)
+		return errors.New("failed to revert email change")
+	}
+	replacedEmail := user.Email
+
+	if err := s.userRepo.RestoreEmailAndLock(ctx, revertToken.UserID, revertToken.NewEmail, emailChangeRevertedReason); err != nil {
+		s.release(ctx, token)
+		if err.Error() == "email already taken" {
+			return errors.New("previous email is already in use")
+		}
+		log.Error().Err(err).Int64("user_id", revertToken.UserID).Msg("[AccountSecurityService-RevertEmailChange] Failed to restore email")
+		return errors.New("failed to revert email change")
+	}
+
+	// Whoever changed the address may still be signed in
+	if err := s.sessionRepo.DeleteAllUserTokens(ctx, revertToken.UserID); err != nil {
+		log.Error().Err(err).Int64("user_id", revertToken.UserID).Msg("[AccountSecurityService-RevertEmailChange] Failed to revoke sessions")
+	}
+
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{
+		UserID: revertToken.UserID,
+		Event:  entity.AuditEventEmailChangeReverted,
+		Metadata: map[string]interface{}{
+			"restored_email": revertToken.NewEmail,
+			"replaced_email": replacedEmail,
+		},
+	})
+
+	s.alertAdmins(ctx, revertToken.UserID, revertToken.NewEmail, replacedEmail)
+
+	log.Info().Int64("user_id", revertToken.UserID).Msg("[AccountSecurityService-RevertEmailChange] Email change reverted, account locked for review")
+	return nil
+}
+
+// release lets the revert link be used again after the revert failed
+func (s *AccountSecurityService) release(ctx context.Context, token string) {
+	if err := s.verificationTokenRepo.ReleaseVerificationToken(ctx, token); err != nil {
+		log.Error().Err(err).Msg("[AccountSecurityService-release] Failed to release revert token")
+	}
+}
+
+// alertAdmins is best-effort; the account is already locked and listed for review
+func (s *AccountSecurityService) alertAdmins(ctx context.Context, userID int64, restoredEmail, replacedEmail string) {
+	admins, err := s.userRepo.GetEmailsByRole(ctx, "Super Admin")
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AccountSecurityService-alertAdmins] Failed to get admin emails")
+		return
+	}
+
+	for _, adminEmail := range admins {
+		if err := s.emailPublisher.SendAccountLockedAlertEmail(ctx, adminEmail, userID, restoredEmail, replacedEmail); err != nil {
+			log.Error().Err(err).Str("email", adminEmail).Int64("user_id", userID).Msg("[AccountSecurityService-alertAdmins] Failed to send alert")
+		}
+	}
+}
+
+func (s *AccountSecurityService) GetLockedAccounts(ctx context.Context) ([]entity.UserEntity, error) {
+	users, err := s.userRepo.GetLockedUsers(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("[AccountSecurityService-GetLockedAccounts] Failed to get locked accounts")
+		return nil, errors.New("failed to get locked accounts")
+	}
+	return users, nil
+}
+
+func (s *AccountSecurityService) UnlockAccount(ctx context.Context, userID, adminID int64) error {
+	if userID <= 0 {
+		return errors.New("invalid user id")
+	}
+
+	if err := s.userRepo.UnlockUser(ctx, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("account is not locked")
+		}
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AccountSecurityService-UnlockAccount] Failed to unlock account")
+		return errors.New("failed to unlock account")
+	}
+
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{
+		UserID:   userID,
+		Event:    entity.AuditEventAccountUnlocked,
+		Metadata: map[string]interface{}{"admin_id": adminID},
+	})
+
+	log.Info().Int64("user_id", userID).Int64("admin_id", adminID).Msg("[AccountSecurityService-UnlockAccount] Account unlocked")
+	return nil
+}
+
+func NewAccountSecurityService(userRepo port.UserRepositoryInterface, verificationTokenRepo port.VerificationTokenInterface, sessionRepo port.SessionInterface, emailPublisher port.EmailInterface, auditLogService port.AuditLogServiceInterface) port.AccountSecurityServiceInterface {
+	return &AccountSecurityService{
+		userRepo:              userRepo,
+		verificationTokenRepo: verificationTokenRepo,
+		sessionRepo:           sessionRepo,
+		emailPublisher:        emailPublisher,
+		auditLogService:       auditLogService,
+	}
+}
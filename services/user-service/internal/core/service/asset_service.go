@@ -0,0 +1,151 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+// assetNamePattern allows lowercase paths like "logo.png" or "products/bayam.webp"
+var assetNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*(/[a-z0-9][a-z0-9_-]*)*\.[a-z]+$`)
+
+// assetExtensions lists the extensions each sniffed content type may be stored under
+var assetExtensions = map[string][]string{
+	"image/png":  {".png"},
+	"image/jpeg": {".jpg", ".jpeg"},
+	"image/gif":  {".gif"},
+	"image/webp": {".webp"},
+}
+
+type AssetService struct {
+	assetRepo       port.AssetRepositoryInterface
+	storage         port.StorageInterface
+	auditLogService port.AuditLogServiceInterface
+	bucket          string
+	publicBaseURL   string
+	maxSize         int64
+}
+
+// UploadAsset keeps earlier objects in storage, because emails already sent still link to them
+func (s *AssetService) UploadAsset(ctx context.Context, name string, file io.Reader, uploadedBy int64) (*entity.AssetEntity, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) > 200 || !assetNamePattern.MatchString(name) {
+		return nil, errors.New("asset name is invalid")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, s.maxSize+1))
+	if err != nil {
+		log.Error().Err(err).Str("name", name).Msg("[AssetService-UploadAsset] Failed to read asset")
+		return nil, errors.New("failed to upload asset")
+	}
+	if len(data) == 0 {
+		return nil, errors.New("asset is empty")
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, errors.New("asset exceeds the size limit")
+	}
+
+	// The content decides the type; the extension only has to agree with it
+	contentType := http.DetectContentType(data)
+	extensions, ok := assetExtensions[contentType]
+	if !ok {
+		return nil, errors.New("asset must be a PNG, JPEG, GIF or WebP image")
+	}
+	ext := path.Ext(name)
+	if !containsString(extensions, ext) {
+		return nil, errors.New("asset content does not match its extension")
+	}
+
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	existing, err := s.assetRepo.GetAsset(ctx, name)
+	if err != nil && err.Error() != "record not found" {
+		return nil, errors.New("failed to upload asset")
+	}
+	if existing != nil && existing.Hash == hash {
+		log.Info().Str("name", name).Msg("[AssetService-UploadAsset] Asset unchanged, upload skipped")
+		return existing, nil
+	}
+
+	if s.storage == nil {
+		return nil, errors.New("storage not available")
+	}
+
+	// logo.png becomes logo.<hash>.png, so the URL changes exactly when the image does
+	objectName := strings.TrimSuffix(name, ext) + "." + hash[:12] + ext
+	url, err := s.storage.UploadFile(ctx, s.bucket, objectName, bytes.NewReader(data), contentType)
+	if err != nil {
+		log.Error().Err(err).Str("name", name).Str("object_name", objectName).Msg("[AssetService-UploadAsset] Failed to upload asset")
+		return nil, errors.New("failed to upload asset")
+	}
+	if s.publicBaseURL != "" {
+		url = s.publicBaseURL + "/" + objectName
+	}
+
+	asset := &entity.AssetEntity{
+		Name:        name,
+		ObjectName:  objectName,
+		Hash:        hash,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		URL:         url,
+		UploadedBy:  uploadedBy,
+	}
+	if err := s.assetRepo.SaveAsset(ctx, asset); err != nil {
+		return nil, errors.New("failed to save asset")
+	}
+
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{
+		UserID: uploadedBy,
+		Event:  entity.AuditEventAssetUploaded,
+		Metadata: map[string]interface{}{
+			"name":        name,
+			"object_name": objectName,
+			"size":        asset.Size,
+		},
+	})
+
+	log.Info().Str("name", name).Str("object_name", objectName).Int64("size", asset.Size).Msg("[AssetService-UploadAsset] Asset uploaded")
+	return asset, nil
+}
+
+func (s *AssetService) GetAssets(ctx context.Context) ([]entity.AssetEntity, error) {
+	assets, err := s.assetRepo.GetAssets(ctx)
+	if err != nil {
+		return nil, errors.New("failed to retrieve assets")
+	}
+	return assets, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func NewAssetService(assetRepo port.AssetRepositoryInterface, storage port.StorageInterface, auditLogService port.AuditLogServiceInterface, cfg *config.Config) port.AssetServiceInterface {
+	return &AssetService{
+		assetRepo:       assetRepo,
+		storage:         storage,
+		auditLogService: auditLogService,
+		bucket:          cfg.Assets.BucketName(),
+		publicBaseURL:   cfg.Assets.PublicBaseURL,
+		maxSize:         cfg.Assets.MaxSize(),
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+type AuditLogService struct {
+	auditLogRepo port.AuditLogRepositoryInterface
+}
+
+func (s *AuditLogService) Record(ctx context.Context, auditLog entity.AuditLogEntity) {
+	if err := s.auditLogRepo.Create(ctx, &auditLog); err != nil {
+		log.Warn().Err(err).Str("event", auditLog.Event).Int64("user_id", auditLog.UserID).Msg("[AuditLogService-Record] Failed to record audit event")
+	}
+}
+
+func (s *AuditLogService) List(ctx context.Context, filter entity.AuditLogFilter, page, limit int) ([]entity.AuditLogEntity, *entity.PaginationEntity, error) {
+	page, limit = normalizePage(page, limit)
+
+	auditLogs, totalCount, err := s.auditLogRepo.List(ctx, filter, page, limit)
+	if err != nil {
+		log.Error().Err(err).Msg("[AuditLogService-List] Failed to get audit logs")
+		return nil, nil, errors.New("failed to retrieve audit logs")
+	}
+
+	return auditLogs, newPagination(page, limit, totalCount), nil
+}
+
+func NewAuditLogService(auditLogRepo port.AuditLogRepositoryInterface) port.AuditLogServiceInterface {
+	return &AuditLogService{
+		auditLogRepo: auditLogRepo,
+	}
+}
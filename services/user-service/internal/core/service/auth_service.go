@@ -1,35 +1,55 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"image"
 	"io"
+	"sort"
 	"strings"
 	"time"
 	"user-service/internal/core/domain/entity"
 	"user-service/internal/core/port"
 	"user-service/utils"
+	"user-service/utils/i18n"
 
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
 type AuthServiceInterface interface {
-	SignIn(ctx context.Context, req entity.UserEntity) (*entity.UserEntity, string, error)
-	CreateUserAccount(ctx context.Context, email, name, password, passwordConfirmation string) error
+	SignIn(ctx context.Context, req entity.UserEntity, client entity.ClientEntity, rememberMe bool) (*entity.UserEntity, string, error)
+	VerifySignInOTP(ctx context.Context, challengeID, code string, client entity.ClientEntity) (*entity.UserEntity, string, error)
+	SignInExternal(ctx context.Context, userID int64, client entity.ClientEntity, rememberMe bool) (*entity.UserEntity, string, error)
+	CreateUserAccount(ctx context.Context, email, name, password, passwordConfirmation string, lat, lng float64) error
 	VerifyUserAccount(ctx context.Context, token string) error
 	VerifyEmailChange(ctx context.Context, token string) error
 	ForgotPassword(ctx context.Context, email string) error
+	ResendVerificationEmail(ctx context.Context, email string) error
+	RecordEmailBounce(ctx context.Context, email, bounceType, reason string) error
 	ResetPassword(ctx context.Context, token, newPassword, passwordConfirmation string) error
 	Logout(ctx context.Context, userID int64, sessionID, tokenString string, tokenExpiresAt int64) error
+	RefreshSession(ctx context.Context, userID int64, sessionID, tokenString string, tokenExpiresAt int64) (*entity.UserEntity, string, error)
+	GetSessions(ctx context.Context, userID int64, page, limit int) ([]entity.SessionInfo, *entity.PaginationEntity, error)
 	GetProfile(ctx context.Context, userID int64) (*entity.UserEntity, error)
 	UploadProfileImage(ctx context.Context, userID int64, file io.Reader, contentType, filename string) (string, error)
-	UpdateProfile(ctx context.Context, userID int64, name, email, phone, address string, lat, lng float64, photo string) error
+	UploadProfilePhoto(ctx context.Context, userID int64, file io.Reader, contentType, filename string, edit entity.PhotoEditEntity) (*entity.ProfilePhotoEntity, error)
+	RegenerateAvatar(ctx context.Context, userID int64) (string, error)
+	UpdateProfile(ctx context.Context, userID int64, name, email, phone, address string, lat, lng float64, photo string, version int64) error
+	CheckUsernameAvailability(ctx context.Context, username string) (*entity.UsernameAvailabilityEntity, error)
+	ChangeUsername(ctx context.Context, userID int64, username string) (string, error)
+	ChangeTimezone(ctx context.Context, userID int64, timezone string) (string, error)
+	ChangeLanguage(ctx context.Context, userID int64, language string) (string, error)
+	ChangePrivacySettings(ctx context.Context, userID int64, settings entity.PrivacySettingsEntity) (*entity.PrivacySettingsEntity, error)
 	GetCustomers(ctx context.Context, search string, page, limit int, orderBy string) ([]entity.UserEntity, *entity.PaginationEntity, error)
 	GetCustomerByID(ctx context.Context, customerID int64) (*entity.UserEntity, error)
+	GetCustomersNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]entity.UserEntity, error)
+	GetUsersByIDs(ctx context.Context, userIDs []int64) ([]entity.UserEntity, error)
 }
 
 type AuthService struct {
@@ -40,63 +60,303 @@ type AuthService struct {
 	emailPublisher        port.EmailInterface
 	blacklistTokenRepo    port.BlacklistTokenInterface
 	storage               port.StorageInterface
+	geocoder              port.GeocoderInterface
+	zoneRepo              port.DeliveryZoneRepositoryInterface
+	deviceRepo            port.DeviceRepositoryInterface
+	riskService           port.RiskServiceInterface
+	webhooks              port.WebhookDispatcherInterface
+	photoModeration       port.PhotoModerationSubmitterInterface
+	jobs                  port.JobServiceInterface
+	roles                 port.RoleResolverInterface
+	emailPolicy           *utils.EmailPolicy
+	tokenLifetimes        entity.TokenLifetimes
+	passwordHasher        port.PasswordInterface
+	passwordPolicy        entity.PasswordPolicy
 }
 
-func NewAuthService(userRepo port.UserRepositoryInterface, sessionRepo port.SessionInterface, jwtUtil port.JWTInterface, verificationTokenRepo port.VerificationTokenInterface, emailPublisher port.EmailInterface, blacklistTokenRepo port.BlacklistTokenInterface, storage port.StorageInterface) AuthServiceInterface {
+// AuthServiceDeps lists what AuthService is built from. A nil optional dependency (geocoder,
+// zones, devices, risk, webhooks, photo moderation, jobs) switches its feature off; without Roles
+// the password policy only sees the user's own role. Nil settings keep the defaults. New
+// dependencies are added here as fields.
+type AuthServiceDeps struct {
+	UserRepo              port.UserRepositoryInterface
+	SessionRepo           port.SessionInterface
+	JWTUtil               port.JWTInterface
+	VerificationTokenRepo port.VerificationTokenInterface
+	EmailPublisher        port.EmailInterface
+	BlacklistTokenRepo    port.BlacklistTokenInterface
+	Storage               port.StorageInterface
+	Geocoder              port.GeocoderInterface
+	ZoneRepo              port.DeliveryZoneRepositoryInterface
+	DeviceRepo            port.DeviceRepositoryInterface
+	RiskService           port.RiskServiceInterface
+	Webhooks              port.WebhookDispatcherInterface
+	PhotoModeration       port.PhotoModerationSubmitterInterface
+	Jobs                  port.JobServiceInterface
+	Roles                 port.RoleResolverInterface
+
+	EmailPolicy    *utils.EmailPolicy
+	TokenLifetimes *entity.TokenLifetimes
+	PasswordHasher port.PasswordInterface
+	PasswordPolicy *entity.PasswordPolicy
+}
+
+func NewAuthService(deps AuthServiceDeps) AuthServiceInterface {
+	emailPolicy := deps.EmailPolicy
+	if emailPolicy == nil {
+		emailPolicy = utils.DefaultEmailPolicy()
+	}
+	tokenLifetimes := deps.TokenLifetimes
+	if tokenLifetimes == nil {
+		tokenLifetimes = &entity.TokenLifetimes{}
+	}
+	passwordHasher := deps.PasswordHasher
+	if passwordHasher == nil {
+		passwordHasher = utils.DefaultPasswordHasher()
+	}
+	passwordPolicy := deps.PasswordPolicy
+	if passwordPolicy == nil {
+		passwordPolicy = &entity.PasswordPolicy{}
+	}
+
 	return &AuthService{
-		userRepo:              userRepo,
-		sessionRepo:           sessionRepo,
-		jwtUtil:               jwtUtil,
-		verificationTokenRepo: verificationTokenRepo,
-		emailPublisher:        emailPublisher,
-		blacklistTokenRepo:    blacklistTokenRepo,
-		storage:               storage,
+		userRepo:              deps.UserRepo,
+		sessionRepo:           deps.SessionRepo,
+		jwtUtil:               deps.JWTUtil,
+		verificationTokenRepo: deps.VerificationTokenRepo,
+		emailPublisher:        deps.EmailPublisher,
+		blacklistTokenRepo:    deps.BlacklistTokenRepo,
+		storage:               deps.Storage,
+		geocoder:              deps.Geocoder,
+		zoneRepo:              deps.ZoneRepo,
+		deviceRepo:            deps.DeviceRepo,
+		riskService:           deps.RiskService,
+		webhooks:              deps.Webhooks,
+		photoModeration:       deps.PhotoModeration,
+		jobs:                  deps.Jobs,
+		roles:                 deps.Roles,
+		emailPolicy:           emailPolicy,
+		tokenLifetimes:        *tokenLifetimes,
+		passwordHasher:        passwordHasher,
+		passwordPolicy:        *passwordPolicy,
 	}
 }
 
-func (s *AuthService) SignIn(ctx context.Context, req entity.UserEntity) (*entity.UserEntity, string, error) {
-	if err := s.validateEmail(req.Email); err != nil {
-		log.Error().Err(err).Str("email", req.Email).Msg("[AuthService-SignIn] Invalid email format")
+// SignIn checks the credentials and opens a session; rememberMe picks the long session lifetime
+func (s *AuthService) SignIn(ctx context.Context, req entity.UserEntity, client entity.ClientEntity, rememberMe bool) (*entity.UserEntity, string, error) {
+	user, err := s.findSignInUser(ctx, req)
+	if err != nil {
 		return nil, "", err
 	}
+	// Risk tracking and logs are keyed by email whichever identifier was typed
+	req.Email = user.Email
+
+	if checkPass := s.passwordHasher.CheckPasswordHash(req.Password, user.Password); !checkPass {
+		log.Warn().Str("email", req.Email).Msg("[AuthService-SignIn] Incorrect password")
+		if s.riskService != nil {
+			s.riskService.RecordFailedSignIn(ctx, req.Email, user.ID, client)
+		}
+		return nil, "", errors.New("incorrect password")
+	}
+	s.upgradePasswordHash(ctx, user.ID, req.Password, user.Password)
 
-	req.Email = strings.ToLower(strings.TrimSpace(req.Email))
+	if user.LockedAt != nil {
+		log.Warn().Int64("user_id", user.ID).Str("reason", user.LockReason).Msg("[AuthService-SignIn] Account is locked")
+		return nil, "", errors.New("account is locked")
+	}
 
-	user, err := s.userRepo.GetUserByEmail(ctx, req.Email)
+	// Staff with an expired password get no session until they pick a new one through the reset flow
+	if s.passwordPolicy.AppliesTo(effectiveRoles(ctx, s.roles, user.RoleName)) && s.passwordPolicy.Expired(user.PasswordChangedAt, time.Now()) {
+		log.Warn().Int64("user_id", user.ID).Time("changed_at", user.PasswordChangedAt).Msg("[AuthService-SignIn] Password expired")
+		return nil, "", errors.New("password expired")
+	}
+
+	var location *entity.LoginLocationEntity
+	if s.riskService != nil {
+		assessment := s.riskService.Assess(ctx, user, client)
+		if assessment.RequiresStepUp {
+			challenge, err := s.riskService.StartStepUp(ctx, user, client, assessment, rememberMe)
+			if err != nil {
+				log.Error().Err(err).Int64("user_id", user.ID).Msg("[AuthService-SignIn] Failed to start step-up verification")
+				return nil, "", err
+			}
+			return nil, "", &entity.StepUpRequiredError{ChallengeID: challenge.ID, ExpiresAt: challenge.ExpiresAt}
+		}
+		location = assessment.Location
+	}
+
+	token, err := s.issueSession(ctx, user, client, location, rememberMe, false)
 	if err != nil {
-		log.Error().Err(err).Str("email", req.Email).Msg("[AuthService-SignIn] Failed to get user from repository")
+		return nil, "", err
+	}
+
+	log.Info().Int64("user_id", user.ID).Str("email", req.Email).Msg("[AuthService-SignIn] User signed in successfully")
+	return user, token, nil
+}
+
+// VerifySignInOTP completes a sign-in that was held back for step-up verification
+// upgradePasswordHash redoes a hash from an older algorithm or weaker settings while the plain
+// password is at hand. Best-effort: the old hash keeps working if the update fails.
+func (s *AuthService) upgradePasswordHash(ctx context.Context, userID int64, password, hash string) {
+	if !s.passwordHasher.NeedsRehash(hash) {
+		return
+	}
+
+	upgraded, err := s.passwordHasher.HashPassword(password)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-upgradePasswordHash] Failed to hash password")
+		return
+	}
+	if err := s.userRepo.UpdateUserPassword(ctx, userID, upgraded); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-upgradePasswordHash] Failed to store upgraded hash")
+		return
+	}
+	log.Info().Int64("user_id", userID).Msg("[AuthService-upgradePasswordHash] Password hash upgraded")
+}
+
+func (s *AuthService) VerifySignInOTP(ctx context.Context, challengeID, code string, client entity.ClientEntity) (*entity.UserEntity, string, error) {
+	if s.riskService == nil {
+		return nil, "", errors.New("verification challenge not found")
+	}
+
+	challenge, err := s.riskService.VerifyStepUp(ctx, strings.TrimSpace(challengeID), strings.TrimSpace(code), client)
+	if err != nil {
+		log.Warn().Err(err).Msg("[AuthService-VerifySignInOTP] Verification failed")
+		return nil, "", err
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, challenge.UserID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", challenge.UserID).Msg("[AuthService-VerifySignInOTP] Failed to get user")
 		if err.Error() == "record not found" {
 			return nil, "", errors.New("user not found")
 		}
 		return nil, "", err
 	}
+	if user.LockedAt != nil {
+		log.Warn().Int64("user_id", user.ID).Msg("[AuthService-VerifySignInOTP] Account is locked")
+		return nil, "", errors.New("account is locked")
+	}
 
-	if checkPass := utils.CheckPasswordHash(req.Password, user.Password); !checkPass {
-		log.Warn().Str("email", req.Email).Msg("[AuthService-SignIn] Incorrect password")
-		return nil, "", errors.New("incorrect password")
+	token, err := s.issueSession(ctx, user, client, challenge.Location, challenge.RememberMe, false)
+	if err != nil {
+		return nil, "", err
+	}
+
+	log.Info().Int64("user_id", user.ID).Msg("[AuthService-VerifySignInOTP] User signed in after step-up verification")
+	return user, token, nil
+}
+
+// SignInExternal opens a session for a user an identity provider has already authenticated, so
+// there is no password check or step-up; the provider enforces its own MFA
+func (s *AuthService) SignInExternal(ctx context.Context, userID int64, client entity.ClientEntity, rememberMe bool) (*entity.UserEntity, string, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-SignInExternal] Failed to get user")
+		if err.Error() == "record not found" {
+			return nil, "", errors.New("user not found")
+		}
+		return nil, "", err
+	}
+	if user.LockedAt != nil {
+		log.Warn().Int64("user_id", user.ID).Str("reason", user.LockReason).Msg("[AuthService-SignInExternal] Account is locked")
+		return nil, "", errors.New("account is locked")
+	}
+
+	token, err := s.issueSession(ctx, user, client, nil, rememberMe, true)
+	if err != nil {
+		return nil, "", err
 	}
 
+	log.Info().Int64("user_id", user.ID).Msg("[AuthService-SignInExternal] User signed in through identity provider")
+	return user, token, nil
+}
+
+// issueSession creates the session token once the user has been fully authenticated; sso marks a
+// sign-in through the identity provider, which password expiry does not end
+func (s *AuthService) issueSession(ctx context.Context, user *entity.UserEntity, client entity.ClientEntity, location *entity.LoginLocationEntity, rememberMe, sso bool) (string, error) {
 	sessionID := "sess_" + fmt.Sprintf("%d", time.Now().UnixNano())
 
-	token, err := s.jwtUtil.GenerateJWTWithSession(user.ID, user.Email, user.RoleName, sessionID)
+	token, err := s.jwtUtil.GenerateJWTWithSession(user.ID, user.Email, user.RoleName, sessionID, rememberMe)
 	if err != nil {
 		log.Error().Err(err).Int64("user_id", user.ID).Msg("[AuthService-SignIn] Failed to generate JWT token")
-		return nil, "", errors.New("failed to generate token")
+		return "", errors.New("failed to generate token")
 	}
 
-	err = s.sessionRepo.StoreToken(ctx, user.ID, sessionID, token)
+	err = s.sessionRepo.StoreToken(ctx, user.ID, sessionID, token, rememberMe)
 	if err != nil {
 		log.Error().Err(err).Int64("user_id", user.ID).Msg("[AuthService-SignIn] Failed to store token in session")
-		return nil, "", errors.New("failed to create session")
+		return "", errors.New("failed to create session")
+	}
+	if sso {
+		// Unmarked, the session ends like a password one should the password expire
+		if err := s.sessionRepo.MarkSSOSession(ctx, user.ID, sessionID); err != nil {
+			log.Warn().Err(err).Int64("user_id", user.ID).Str("session_id", sessionID).Msg("[AuthService-SignIn] Failed to mark SSO session")
+		}
 	}
 
-	log.Info().Int64("user_id", user.ID).Str("email", req.Email).Str("session_id", sessionID).Msg("[AuthService-SignIn] User signed in successfully")
-	return user, token, nil
+	s.recordSignInDevice(ctx, user, sessionID, client, location)
+	if s.riskService != nil {
+		s.riskService.RecordSuccessfulSignIn(ctx, user, location)
+	}
+
+	log.Info().Int64("user_id", user.ID).Str("session_id", sessionID).Bool("remember_me", rememberMe).Msg("[AuthService-SignIn] Session issued")
+	return token, nil
+}
+
+// recordSignInDevice stores the client on the session and alerts the user about unseen devices.
+// Failures are logged only; they must never block a valid sign-in.
+func (s *AuthService) recordSignInDevice(ctx context.Context, user *entity.UserEntity, sessionID string, client entity.ClientEntity, location *entity.LoginLocationEntity) {
+	if err := s.sessionRepo.SetSessionClient(ctx, user.ID, sessionID, client, location); err != nil {
+		log.Warn().Err(err).Int64("user_id", user.ID).Str("session_id", sessionID).Msg("[AuthService-SignIn] Failed to record session client")
+	}
+
+	if s.deviceRepo == nil {
+		return
+	}
+
+	device, isNew, err := s.deviceRepo.TouchDevice(ctx, user.ID, client)
+	if err != nil {
+		log.Warn().Err(err).Int64("user_id", user.ID).Msg("[AuthService-SignIn] Failed to record device")
+		return
+	}
+	if !isNew {
+		return
+	}
+
+	// The very first device of an account is expected, so there is nothing to alert about
+	count, err := s.deviceRepo.CountDevices(ctx, user.ID)
+	if err != nil {
+		log.Warn().Err(err).Int64("user_id", user.ID).Msg("[AuthService-SignIn] Failed to count devices")
+		return
+	}
+	if count <= 1 || s.emailPublisher == nil {
+		return
+	}
+
+	// The alert shows the sign-in time on the user's own clock
+	signedInAt := device.FirstSeenAt.In(utils.UserLocation(user.Timezone))
+	deviceName := utils.DeviceDisplayName(client.UserAgent)
+	if deviceName == "" {
+		deviceName = client.UserAgent
+	}
+	if err := s.emailPublisher.SendNewDeviceSignInEmail(ctx, user.Email, deviceName, client.IPAddress, location.Place(), signedInAt); err != nil {
+		log.Warn().Err(err).Int64("user_id", user.ID).Int64("device_id", device.ID).Msg("[AuthService-SignIn] Failed to publish new device sign-in email")
+		return
+	}
+
+	log.Info().Int64("user_id", user.ID).Int64("device_id", device.ID).Msg("[AuthService-SignIn] New device sign-in alert sent")
 }
 
-func (s *AuthService) CreateUserAccount(ctx context.Context, email, name, password, passwordConfirmation string) error {
-	if err := s.validateEmail(email); err != nil {
+func (s *AuthService) CreateUserAccount(ctx context.Context, email, name, password, passwordConfirmation string, lat, lng float64) error {
+	normalized, err := s.emailPolicy.NormalizeEmail(email)
+	if err != nil {
 		log.Error().Err(err).Str("email", email).Msg("[AuthService-CreateUserAccount] Invalid email format")
+		return ErrInvalidEmail
+	}
+
+	if err := s.emailPolicy.CheckDomain(normalized); err != nil {
+		log.Warn().Str("email", normalized).Msg("[AuthService-CreateUserAccount] Disposable email domain rejected")
 		return err
 	}
 
@@ -105,7 +365,20 @@ func (s *AuthService) CreateUserAccount(ctx context.Context, email, name, passwo
 		return err
 	}
 
-	email = strings.ToLower(strings.TrimSpace(email))
+	// Location is optional at signup, but when given it must be inside a delivery zone
+	if lat != 0 || lng != 0 {
+		if err := utils.ValidateCoordinates(lat, lng); err != nil {
+			log.Warn().Float64("lat", lat).Float64("lng", lng).Str("email", email).Msg("[AuthService-CreateUserAccount] Invalid coordinates")
+			return err
+		}
+
+		if err := s.ensureDeliveryCoverage(ctx, lat, lng); err != nil {
+			log.Warn().Err(err).Float64("lat", lat).Float64("lng", lng).Str("email", email).Msg("[AuthService-CreateUserAccount] Location not covered")
+			return err
+		}
+	}
+
+	email = normalized
 	name = strings.TrimSpace(name)
 
 	existingUser, err := s.userRepo.GetUserByEmailIncludingUnverified(ctx, email)
@@ -114,7 +387,7 @@ func (s *AuthService) CreateUserAccount(ctx context.Context, email, name, passwo
 		return errors.New("email already exists")
 	}
 
-	hashedPassword, err := utils.HashPassword(password)
+	hashedPassword, err := s.passwordHasher.HashPassword(password)
 	if err != nil {
 		log.Error().Err(err).Str("email", email).Msg("[AuthService-CreateUserAccount] Failed to hash password")
 		return errors.New("failed to process password")
@@ -124,12 +397,29 @@ func (s *AuthService) CreateUserAccount(ctx context.Context, email, name, passwo
 		Name:       name,
 		Email:      email,
 		Password:   hashedPassword,
+		Lat:        lat,
+		Lng:        lng,
 		IsVerified: false,
+		// Emails keep the sign-up language until the user picks another one
+		Language: i18n.FromContext(ctx),
+	}
+
+	// A missing avatar never blocks signup; the user can regenerate it later
+	avatarURL, err := s.uploadGeneratedAvatar(ctx, email)
+	if err != nil {
+		log.Warn().Err(err).Str("email", email).Msg("[AuthService-CreateUserAccount] Failed to generate avatar")
 	}
+	userEntity.Photo = avatarURL
 
 	createdUser, err := s.userRepo.CreateUser(ctx, userEntity)
 	if err != nil {
+		if err.Error() == "email already exists" {
+			// Lost the race to a concurrent signup with the same email
+			s.deleteStoredPhoto(ctx, avatarURL)
+			return err
+		}
 		log.Error().Err(err).Str("email", email).Msg("[AuthService-CreateUserAccount] Failed to create user")
+		s.deleteStoredPhoto(ctx, avatarURL)
 		return errors.New("failed to create account")
 	}
 
@@ -142,8 +432,8 @@ func (s *AuthService) CreateUserAccount(ctx context.Context, email, name, passwo
 	verificationToken := &entity.VerificationTokenEntity{
 		UserID:    createdUser.ID,
 		Token:     token,
-		TokenType: "email_verification",
-		ExpiresAt: time.Now().Add(24 * time.Hour),
+		TokenType: entity.TokenTypeEmailVerification,
+		ExpiresAt: time.Now().Add(s.tokenLifetimes.For(entity.TokenTypeEmailVerification)),
 	}
 
 	err = s.verificationTokenRepo.CreateVerificationToken(ctx, verificationToken)
@@ -158,6 +448,8 @@ func (s *AuthService) CreateUserAccount(ctx context.Context, email, name, passwo
 		log.Warn().Int64("user_id", createdUser.ID).Msg("[AuthService-CreateUserAccount] Account created but email sending failed")
 	}
 
+	s.dispatchUserWebhook(ctx, entity.WebhookEventUserCreated, createdUser)
+
 	log.Info().Int64("user_id", createdUser.ID).Str("email", email).Msg("[AuthService-CreateUserAccount] User account created successfully")
 	return nil
 }
@@ -165,54 +457,234 @@ func (s *AuthService) CreateUserAccount(ctx context.Context, email, name, passwo
 func (s *AuthService) VerifyUserAccount(ctx context.Context, token string) error {
 	verificationToken, err := s.verificationTokenRepo.GetVerificationToken(ctx, token)
 	if err != nil {
+		if errors.Is(err, port.ErrTokenAlreadyUsed) {
+			log.Warn().Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthService-VerifyUserAccount] Verification token was already used")
+			return err
+		}
 		if err.Error() == "record not found" {
-			log.Warn().Str("token", token).Msg("[AuthService-VerifyUserAccount] Verification token not found or expired")
+			log.Warn().Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthService-VerifyUserAccount] Verification token not found or expired")
 			return errors.New("invalid or expired verification token")
 		}
-		log.Error().Err(err).Str("token", token).Msg("[AuthService-VerifyUserAccount] Failed to get verification token")
+		log.Error().Err(err).Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthService-VerifyUserAccount] Failed to get verification token")
 		return errors.New("failed to verify token")
 	}
 
+	if err := s.claimVerificationToken(ctx, token); err != nil {
+		return claimError(err, "invalid or expired verification token", "failed to verify token")
+	}
+
 	err = s.userRepo.UpdateUserVerificationStatus(ctx, verificationToken.UserID, true)
 	if err != nil {
 		log.Error().Err(err).Int64("user_id", verificationToken.UserID).Msg("[AuthService-VerifyUserAccount] Failed to update user verification status")
+		s.releaseVerificationToken(ctx, token)
 		return errors.New("failed to verify account")
 	}
 
-	err = s.verificationTokenRepo.DeleteVerificationToken(ctx, token)
+	if s.webhooks != nil || s.jobs != nil {
+		if user, err := s.userRepo.GetUserByID(ctx, verificationToken.UserID); err == nil {
+			if s.webhooks != nil {
+				s.dispatchUserWebhook(ctx, entity.WebhookEventUserVerified, user)
+			}
+			s.emitUserVerified(ctx, user)
+		} else {
+			log.Warn().Err(err).Int64("user_id", verificationToken.UserID).Msg("[AuthService-VerifyUserAccount] Failed to load verified user, no event sent")
+		}
+	}
+
+	log.Info().Int64("user_id", verificationToken.UserID).Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthService-VerifyUserAccount] User account verified successfully")
+	return nil
+}
+
+// emitUserVerified queues the user.verified event, which notification-service turns into the
+// welcome email. A failure is logged only; the account is verified either way.
+func (s *AuthService) emitUserVerified(ctx context.Context, user *entity.UserEntity) {
+	if s.jobs == nil {
+		return
+	}
+
+	// One event per account, however often the link is clicked
+	eventID := fmt.Sprintf("%s:%d", entity.EventUserVerified, user.ID)
+	event := entity.EventEntity{
+		ID:         eventID,
+		Type:       entity.EventUserVerified,
+		OccurredAt: time.Now(),
+		Data: map[string]interface{}{
+			"user_id":  user.ID,
+			"email":    user.Email,
+			"name":     user.Name,
+			"language": user.Language,
+		},
+	}
+
+	if _, err := s.jobs.EnqueueUnique(ctx, entity.JobTypePublishEvent, eventID, event, time.Time{}); err != nil && err.Error() != "job already enqueued" {
+		log.Error().Err(err).Int64("user_id", user.ID).Msg("[AuthService-emitUserVerified] Failed to queue user verified event")
+	}
+}
+
+// ResendVerificationEmail sends a new link to an unverified account and invalidates the old ones.
+// Unknown and already verified addresses succeed silently, so the endpoint does not reveal accounts.
+func (s *AuthService) ResendVerificationEmail(ctx context.Context, email string) error {
+	normalized, err := s.emailPolicy.NormalizeEmail(email)
+	if err != nil {
+		log.Error().Err(err).Str("email", email).Msg("[AuthService-ResendVerificationEmail] Invalid email format")
+		return ErrInvalidEmail
+	}
+	email = normalized
+
+	user, err := s.userRepo.GetUserByEmailIncludingUnverified(ctx, email)
 	if err != nil {
-		log.Error().Err(err).Str("token", token).Msg("[AuthService-VerifyUserAccount] Failed to delete verification token")
+		if err.Error() == "record not found" {
+			log.Warn().Str("email", email).Msg("[AuthService-ResendVerificationEmail] User not found")
+			return nil
+		}
+		log.Error().Err(err).Str("email", email).Msg("[AuthService-ResendVerificationEmail] Failed to get user from repository")
+		return errors.New("failed to process request")
+	}
+
+	if user.IsVerified {
+		log.Info().Int64("user_id", user.ID).Msg("[AuthService-ResendVerificationEmail] User already verified")
+		return nil
+	}
+
+	if user.BouncedAt != nil {
+		log.Warn().Int64("user_id", user.ID).Str("email", email).Time("bounced_at", *user.BouncedAt).Msg("[AuthService-ResendVerificationEmail] Email address bounced")
+		return ErrEmailUndeliverable
+	}
+
+	if _, err := s.verificationTokenRepo.DeleteUserTokens(ctx, user.ID, entity.TokenTypeEmailVerification); err != nil {
+		log.Warn().Err(err).Int64("user_id", user.ID).Msg("[AuthService-ResendVerificationEmail] Failed to invalidate previous tokens")
+	}
+
+	token, err := s.generateVerificationToken()
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", user.ID).Msg("[AuthService-ResendVerificationEmail] Failed to generate verification token")
+		return errors.New("failed to generate verification token")
+	}
+
+	err = s.verificationTokenRepo.CreateVerificationToken(ctx, &entity.VerificationTokenEntity{
+		UserID:    user.ID,
+		Token:     token,
+		TokenType: entity.TokenTypeEmailVerification,
+		ExpiresAt: time.Now().Add(s.tokenLifetimes.For(entity.TokenTypeEmailVerification)),
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", user.ID).Msg("[AuthService-ResendVerificationEmail] Failed to save verification token")
+		return errors.New("failed to create verification token")
+	}
+
+	if err := s.emailPublisher.SendVerificationEmail(ctx, email, token); err != nil {
+		log.Error().Err(err).Int64("user_id", user.ID).Str("email", email).Msg("[AuthService-ResendVerificationEmail] Failed to send verification email")
+		return errors.New("failed to send verification email")
+	}
+
+	log.Info().Int64("user_id", user.ID).Msg("[AuthService-ResendVerificationEmail] Verification email resent")
+	return nil
+}
+
+// RecordEmailBounce is called when the mail provider reports a bounce. A hard bounce marks the
+// address undeliverable and, for an unverified account, voids the verification links sent to it.
+func (s *AuthService) RecordEmailBounce(ctx context.Context, email, bounceType, reason string) error {
+	if bounceType != entity.EmailBounceHard {
+		log.Info().Str("email", email).Str("bounce_type", bounceType).Msg("[AuthService-RecordEmailBounce] Ignoring soft bounce")
+		return nil
+	}
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	user, err := s.userRepo.GetUserByEmailIncludingUnverified(ctx, email)
+	if err != nil {
+		if err.Error() == "record not found" {
+			log.Warn().Str("email", email).Msg("[AuthService-RecordEmailBounce] No account uses the bounced address")
+			return ErrUserNotFound
+		}
+		log.Error().Err(err).Str("email", email).Msg("[AuthService-RecordEmailBounce] Failed to get user from repository")
+		return errors.New("failed to record bounce")
+	}
+
+	if err := s.userRepo.MarkEmailBounced(ctx, user.ID, user.Email, reason); err != nil {
+		if err.Error() == "record not found" {
+			return ErrUserNotFound
+		}
+		return errors.New("failed to record bounce")
+	}
+
+	if !user.IsVerified {
+		deleted, err := s.verificationTokenRepo.DeleteUserTokens(ctx, user.ID, entity.TokenTypeEmailVerification)
+		if err != nil {
+			log.Warn().Err(err).Int64("user_id", user.ID).Msg("[AuthService-RecordEmailBounce] Failed to invalidate verification tokens")
+		} else {
+			log.Info().Int64("user_id", user.ID).Int64("tokens", deleted).Msg("[AuthService-RecordEmailBounce] Verification tokens invalidated")
+		}
 	}
 
-	log.Info().Int64("user_id", verificationToken.UserID).Str("token", token).Msg("[AuthService-VerifyUserAccount] User account verified successfully")
 	return nil
 }
 
+// dispatchUserWebhook notifies integrations about a user; a failed dispatch never fails the user's request
+func (s *AuthService) dispatchUserWebhook(ctx context.Context, eventType string, user *entity.UserEntity) {
+	if s.webhooks == nil {
+		return
+	}
+
+	eventID := fmt.Sprintf("%s:%d", eventType, user.ID)
+	data := map[string]interface{}{
+		"user_id":     user.ID,
+		"email":       user.Email,
+		"name":        user.Name,
+		"is_verified": user.IsVerified,
+	}
+	if err := s.webhooks.Dispatch(ctx, eventType, eventID, data); err != nil {
+		log.Error().Err(err).Int64("user_id", user.ID).Str("event", eventType).Msg("[AuthService-dispatchUserWebhook] Failed to dispatch webhook")
+	}
+}
+
 func (s *AuthService) VerifyEmailChange(ctx context.Context, token string) error {
 	verificationToken, err := s.verificationTokenRepo.GetVerificationToken(ctx, token)
 	if err != nil {
+		if errors.Is(err, port.ErrTokenAlreadyUsed) {
+			log.Warn().Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthService-VerifyEmailChange] Verification token was already used")
+			return err
+		}
 		if err.Error() == "record not found" {
-			log.Warn().Str("token", token).Msg("[AuthService-VerifyEmailChange] Verification token not found or expired")
+			log.Warn().Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthService-VerifyEmailChange] Verification token not found or expired")
 			return errors.New("invalid or expired verification token")
 		}
-		log.Error().Err(err).Str("token", token).Msg("[AuthService-VerifyEmailChange] Failed to get verification token")
+		log.Error().Err(err).Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthService-VerifyEmailChange] Failed to get verification token")
 		return errors.New("failed to verify token")
 	}
 
-	if verificationToken.TokenType != "email_change" {
-		log.Warn().Str("token", token).Str("token_type", verificationToken.TokenType).Msg("[AuthService-VerifyEmailChange] Token is not for email change")
+	if verificationToken.TokenType != entity.TokenTypeEmailChange {
+		log.Warn().Str("token_fingerprint", utils.SecretFingerprint(token)).Str("token_type", verificationToken.TokenType).Msg("[AuthService-VerifyEmailChange] Token is not for email change")
 		return errors.New("invalid token type")
 	}
 
 	if verificationToken.NewEmail == "" {
-		log.Error().Str("token", token).Msg("[AuthService-VerifyEmailChange] No new email found in token")
+		log.Error().Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthService-VerifyEmailChange] No new email found in token")
 		return errors.New("invalid token data")
 	}
 
+	if err := s.claimVerificationToken(ctx, token); err != nil {
+		return claimError(err, "invalid or expired verification token", "failed to verify token")
+	}
+
+	// The current address is where the revert link goes once the change is done
+	user, err := s.userRepo.GetUserByID(ctx, verificationToken.UserID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", verificationToken.UserID).Msg("[AuthService-VerifyEmailChange] Failed to get user")
+		s.releaseVerificationToken(ctx, token)
+		return errors.New("failed to verify email change")
+	}
+	previousEmail := user.Email
+
 	// Update user email to the new email
 	err = s.userRepo.UpdateUserEmail(ctx, verificationToken.UserID, verificationToken.NewEmail)
 	if err != nil {
+		if err.Error() == "email already exists" {
+			// Someone signed up with the address after the change was requested
+			log.Warn().Int64("user_id", verificationToken.UserID).Str("new_email", verificationToken.NewEmail).Msg("[AuthService-VerifyEmailChange] Email already exists")
+			return err
+		}
 		log.Error().Err(err).Int64("user_id", verificationToken.UserID).Str("new_email", verificationToken.NewEmail).Msg("[AuthService-VerifyEmailChange] Failed to update user email")
+		s.releaseVerificationToken(ctx, token)
 		return errors.New("failed to update email")
 	}
 
@@ -220,27 +692,89 @@ func (s *AuthService) VerifyEmailChange(ctx context.Context, token string) error
 	err = s.userRepo.UpdateUserVerificationStatus(ctx, verificationToken.UserID, true)
 	if err != nil {
 		log.Error().Err(err).Int64("user_id", verificationToken.UserID).Msg("[AuthService-VerifyEmailChange] Failed to update user verification status")
+		s.releaseVerificationToken(ctx, token)
 		return errors.New("failed to verify email change")
 	}
 
-	err = s.verificationTokenRepo.DeleteVerificationToken(ctx, token)
-	if err != nil {
-		log.Error().Err(err).Str("token", token).Msg("[AuthService-VerifyEmailChange] Failed to delete verification token")
+	if previousEmail != "" && previousEmail != verificationToken.NewEmail {
+		s.sendEmailChangeRevert(ctx, verificationToken.UserID, previousEmail, verificationToken.NewEmail)
 	}
 
-	log.Info().Int64("user_id", verificationToken.UserID).Str("new_email", verificationToken.NewEmail).Str("token", token).Msg("[AuthService-VerifyEmailChange] Email change verified successfully")
+	log.Info().Int64("user_id", verificationToken.UserID).Str("new_email", verificationToken.NewEmail).Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthService-VerifyEmailChange] Email change verified successfully")
 	return nil
 }
 
+// sendEmailChangeRevert gives the previous address a way back if the change was not its owner's.
+// It is best-effort: the change itself has already been made.
+func (s *AuthService) sendEmailChangeRevert(ctx context.Context, userID int64, previousEmail, newEmail string) {
+	if s.emailPublisher == nil {
+		return
+	}
+
+	token, err := s.generateVerificationToken()
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-sendEmailChangeRevert] Failed to generate revert token")
+		return
+	}
+
+	revertToken := &entity.VerificationTokenEntity{
+		UserID:    userID,
+		Token:     token,
+		TokenType: entity.TokenTypeEmailChangeRevert,
+		NewEmail:  previousEmail, // the address a revert restores
+		ExpiresAt: time.Now().Add(s.tokenLifetimes.For(entity.TokenTypeEmailChangeRevert)),
+	}
+	if err := s.verificationTokenRepo.CreateVerificationToken(ctx, revertToken); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-sendEmailChangeRevert] Failed to save revert token")
+		return
+	}
+
+	if err := s.emailPublisher.SendEmailChangeRevertEmail(ctx, previousEmail, newEmail, token); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-sendEmailChangeRevert] Failed to send revert email")
+	}
+}
+
+// claimVerificationToken marks a token used before the change it authorizes is made, so two
+// requests with the same link cannot both succeed
+func (s *AuthService) claimVerificationToken(ctx context.Context, token string) error {
+	err := s.verificationTokenRepo.ClaimVerificationToken(ctx, token)
+	if err != nil {
+		log.Warn().Err(err).Msg("[AuthService-claimVerificationToken] Failed to claim token")
+	}
+	return err
+}
+
+// releaseVerificationToken lets the link be used again after the change it guarded failed
+func (s *AuthService) releaseVerificationToken(ctx context.Context, token string) {
+	if err := s.verificationTokenRepo.ReleaseVerificationToken(ctx, token); err != nil {
+		log.Error().Err(err).Msg("[AuthService-releaseVerificationToken] Failed to release token")
+	}
+}
+
+// claimError maps a failed claim to the flow's errors; a token can expire or be used by a
+// concurrent request between the lookup and the claim
+func claimError(err error, expired, failed string) error {
+	switch {
+	case errors.Is(err, port.ErrTokenAlreadyUsed):
+		return port.ErrTokenAlreadyUsed
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return errors.New(expired)
+	default:
+		return errors.New(failed)
+	}
+}
+
 func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
-	if err := s.validateEmail(email); err != nil {
+	normalized, err := s.emailPolicy.NormalizeEmail(email)
+	if err != nil {
 		log.Error().Err(err).Str("email", email).Msg("[AuthService-ForgotPassword] Invalid email format")
-		return err
+		return ErrInvalidEmail
 	}
 
-	email = strings.ToLower(strings.TrimSpace(email))
+	typed := strings.ToLower(strings.TrimSpace(email))
+	email = normalized
 
-	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	user, err := s.findUserByEmail(ctx, email, typed)
 	if err != nil {
 		if err.Error() == "record not found" {
 			log.Warn().Str("email", email).Msg("[AuthService-ForgotPassword] User not found")
@@ -264,8 +798,8 @@ func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
 	resetToken := &entity.VerificationTokenEntity{
 		UserID:    user.ID,
 		Token:     token,
-		TokenType: "password_reset",
-		ExpiresAt: time.Now().Add(1 * time.Hour),
+		TokenType: entity.TokenTypePasswordReset,
+		ExpiresAt: time.Now().Add(s.tokenLifetimes.For(entity.TokenTypePasswordReset)),
 	}
 
 	err = s.verificationTokenRepo.CreateVerificationToken(ctx, resetToken)
@@ -292,55 +826,201 @@ func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword, pas
 
 	resetToken, err := s.verificationTokenRepo.GetVerificationToken(ctx, token)
 	if err != nil {
+		if errors.Is(err, port.ErrTokenAlreadyUsed) {
+			log.Warn().Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthService-ResetPassword] Reset token was already used")
+			return err
+		}
 		if err.Error() == "record not found" {
-			log.Warn().Str("token", token).Msg("[AuthService-ResetPassword] Reset token not found or expired")
+			log.Warn().Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthService-ResetPassword] Reset token not found or expired")
 			return errors.New("invalid or expired reset token")
 		}
-		log.Error().Err(err).Str("token", token).Msg("[AuthService-ResetPassword] Failed to get reset token")
+		log.Error().Err(err).Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthService-ResetPassword] Failed to get reset token")
 		return errors.New("failed to validate token")
 	}
 
-	if resetToken.TokenType != "password_reset" {
-		log.Warn().Str("token", token).Str("token_type", resetToken.TokenType).Msg("[AuthService-ResetPassword] Token is not for password reset")
+	if resetToken.TokenType != entity.TokenTypePasswordReset {
+		log.Warn().Str("token_fingerprint", utils.SecretFingerprint(token)).Str("token_type", resetToken.TokenType).Msg("[AuthService-ResetPassword] Token is not for password reset")
 		return errors.New("invalid token type")
 	}
 
-	hashedPassword, err := utils.HashPassword(newPassword)
+	keepHistory, err := s.checkPasswordReuse(ctx, resetToken.UserID, newPassword)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := s.passwordHasher.HashPassword(newPassword)
 	if err != nil {
 		log.Error().Err(err).Int64("user_id", resetToken.UserID).Msg("[AuthService-ResetPassword] Failed to hash new password")
 		return errors.New("failed to process password")
 	}
 
-	err = s.userRepo.UpdateUserPassword(ctx, resetToken.UserID, hashedPassword)
+	if err := s.claimVerificationToken(ctx, token); err != nil {
+		return claimError(err, "invalid or expired reset token", "failed to validate token")
+	}
+
+	err = s.userRepo.ChangeUserPassword(ctx, resetToken.UserID, hashedPassword, keepHistory)
 	if err != nil {
 		log.Error().Err(err).Int64("user_id", resetToken.UserID).Msg("[AuthService-ResetPassword] Failed to update user password")
+		s.releaseVerificationToken(ctx, token)
 		return errors.New("failed to update password")
 	}
 
-	err = s.verificationTokenRepo.DeleteVerificationToken(ctx, token)
+	// Whoever knew the old password may still be signed in, so no existing session survives a reset
+	revokeErr := s.revokeAllSessions(ctx, resetToken.UserID)
+	s.sendPasswordChangedEmail(ctx, resetToken.UserID)
+	if revokeErr != nil {
+		// The reset token is released so the link can be used again to finish signing out
+		log.Error().Err(revokeErr).Int64("user_id", resetToken.UserID).Msg("[AuthService-ResetPassword] Password updated but sessions were not revoked")
+		s.releaseVerificationToken(ctx, token)
+		return errors.New("failed to revoke sessions")
+	}
+
+	log.Info().Int64("user_id", resetToken.UserID).Str("token_fingerprint", utils.SecretFingerprint(token)).Msg("[AuthService-ResetPassword] Password reset successfully")
+	return nil
+}
+
+// checkPasswordReuse refuses, for staff under the password policy, the current password and the
+// ones in its history. It returns how many replaced hashes the account keeps, which is none for
+// accounts the policy does not cover.
+func (s *AuthService) checkPasswordReuse(ctx context.Context, userID int64, password string) (int, error) {
+	if s.passwordPolicy.HistorySize <= 0 {
+		return 0, nil
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		// Unverified accounts are never staff
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-checkPasswordReuse] Failed to get user")
+		return 0, errors.New("failed to update password")
+	}
+	if !s.passwordPolicy.AppliesTo(effectiveRoles(ctx, s.roles, user.RoleName)) {
+		return 0, nil
+	}
+
+	keepHistory := s.passwordPolicy.HistorySize - 1
+	history, err := s.userRepo.GetPasswordHistory(ctx, userID, keepHistory)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-checkPasswordReuse] Failed to get password history")
+		return 0, errors.New("failed to update password")
+	}
+
+	for _, hash := range append([]string{user.Password}, history...) {
+		if s.passwordHasher.CheckPasswordHash(password, hash) {
+			log.Warn().Int64("user_id", userID).Int("history_size", s.passwordPolicy.HistorySize).Msg("[AuthService-checkPasswordReuse] Recent password reused")
+			return 0, errors.New("password was used recently")
+		}
+	}
+	return keepHistory, nil
+}
+
+// revokeAllSessions signs the user out on every device. Outstanding tokens are blacklisted and
+// then the Redis sessions are deleted; JWTMiddleware rejects a token on either, so this only
+// fails when some token may be left with neither.
+func (s *AuthService) revokeAllSessions(ctx context.Context, userID int64) error {
+	sessions, err := s.sessionRepo.GetUserSessions(ctx, userID)
+	allBlacklisted := err == nil
 	if err != nil {
-		log.Error().Err(err).Str("token", token).Msg("[AuthService-ResetPassword] Failed to delete reset token")
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-revokeAllSessions] Failed to list sessions")
 	}
 
-	log.Info().Int64("user_id", resetToken.UserID).Str("token", token).Msg("[AuthService-ResetPassword] Password reset successfully")
+	for _, session := range sessions {
+		tokenString, err := s.sessionRepo.GetToken(ctx, userID, session.SessionID)
+		if err != nil {
+			if err.Error() != "token not found" {
+				allBlacklisted = false
+			}
+			continue
+		}
+
+		// Expired or unreadable tokens are rejected anyway
+		claims, err := s.jwtUtil.ValidateJWT(tokenString)
+		if err != nil || claims.ExpiresAt == nil {
+			continue
+		}
+
+		if s.blacklistTokenRepo == nil {
+			allBlacklisted = false
+			continue
+		}
+		hash := sha256.Sum256([]byte(tokenString))
+		if err := s.blacklistTokenRepo.AddToBlacklist(ctx, hex.EncodeToString(hash[:]), claims.ExpiresAt.Unix()); err != nil {
+			log.Error().Err(err).Int64("user_id", userID).Str("session_id", session.SessionID).Msg("[AuthService-revokeAllSessions] Failed to blacklist token")
+			allBlacklisted = false
+		}
+	}
+
+	if err := s.sessionRepo.DeleteAllUserTokens(ctx, userID); err != nil {
+		if !allBlacklisted {
+			return err
+		}
+		log.Warn().Err(err).Int64("user_id", userID).Msg("[AuthService-revokeAllSessions] Failed to delete sessions, tokens are blacklisted")
+	}
+
+	log.Info().Int64("user_id", userID).Int("sessions", len(sessions)).Msg("[AuthService-revokeAllSessions] All sessions revoked")
 	return nil
 }
 
-func (s *AuthService) validateEmail(email string) error {
-	if email == "" {
-		return ErrInvalidEmail
+// sendPasswordChangedEmail warns the account owner; a failure never undoes the reset
+func (s *AuthService) sendPasswordChangedEmail(ctx context.Context, userID int64) {
+	if s.emailPublisher == nil {
+		return
 	}
 
-	email = strings.TrimSpace(email)
-	if len(email) == 0 {
-		return ErrInvalidEmail
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-sendPasswordChangedEmail] Failed to get user")
+		return
+	}
+
+	changedAt := time.Now().In(utils.UserLocation(user.Timezone))
+	if err := s.emailPublisher.SendPasswordChangedEmail(ctx, user.Email, changedAt); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-sendPasswordChangedEmail] Failed to send password changed email")
+	}
+}
+
+// findSignInUser resolves the account by username when one was given, otherwise by email
+func (s *AuthService) findSignInUser(ctx context.Context, req entity.UserEntity) (*entity.UserEntity, error) {
+	var user *entity.UserEntity
+	var err error
+
+	if req.Username != "" {
+		username, normErr := utils.NormalizeUsername(req.Username)
+		if normErr != nil {
+			// No account can have a malformed username
+			log.Warn().Str("username", req.Username).Msg("[AuthService-SignIn] Invalid username format")
+			return nil, errors.New("user not found")
+		}
+		user, err = s.userRepo.GetUserByUsername(ctx, username)
+	} else {
+		email, normErr := s.emailPolicy.NormalizeEmail(req.Email)
+		if normErr != nil {
+			log.Error().Err(normErr).Str("email", req.Email).Msg("[AuthService-SignIn] Invalid email format")
+			return nil, ErrInvalidEmail
+		}
+		user, err = s.findUserByEmail(ctx, email, strings.ToLower(strings.TrimSpace(req.Email)))
+	}
+
+	if err != nil {
+		log.Error().Err(err).Str("email", req.Email).Str("username", req.Username).Msg("[AuthService-SignIn] Failed to get user from repository")
+		if err.Error() == "record not found" {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
 	}
+	return user, nil
+}
 
-	if !strings.Contains(email, "@") || !strings.Contains(email, ".") {
-		return ErrInvalidEmail
+// findUserByEmail looks up the normalized address first and falls back to the address
+// as typed, for accounts stored before normalization that could not be migrated
+func (s *AuthService) findUserByEmail(ctx context.Context, email, typed string) (*entity.UserEntity, error) {
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil && err.Error() == "record not found" && typed != email {
+		return s.userRepo.GetUserByEmail(ctx, typed)
 	}
-
-	return nil
+	return user, err
 }
 
 func (s *AuthService) validatePassword(password, confirmation string) error {
@@ -385,6 +1065,84 @@ func (s *AuthService) Logout(ctx context.Context, userID int64, sessionID, token
 	return nil
 }
 
+// RefreshSession re-issues the JWT for an existing session with the user's current email and role.
+// The session keeps its ID, so the old token stops validating once the new one is stored.
+func (s *AuthService) RefreshSession(ctx context.Context, userID int64, sessionID, tokenString string, tokenExpiresAt int64) (*entity.UserEntity, string, error) {
+	if sessionID == "" {
+		log.Warn().Int64("user_id", userID).Msg("[AuthService-RefreshSession] Token has no session to refresh")
+		return nil, "", errors.New("session required")
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-RefreshSession] Failed to get user")
+		if err.Error() == "record not found" {
+			return nil, "", errors.New("user not found")
+		}
+		return nil, "", err
+	}
+
+	// A refreshed session keeps the lifetime it was signed in with
+	rememberMe := false
+	if session, err := s.sessionRepo.GetSession(ctx, userID, sessionID); err == nil {
+		rememberMe = session.RememberMe
+	} else {
+		log.Warn().Err(err).Int64("user_id", userID).Str("session_id", sessionID).Msg("[AuthService-RefreshSession] Failed to read session, using the short lifetime")
+	}
+
+	token, err := s.jwtUtil.GenerateJWTWithSession(user.ID, user.Email, user.RoleName, sessionID, rememberMe)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-RefreshSession] Failed to generate JWT token")
+		return nil, "", errors.New("failed to generate token")
+	}
+
+	if err := s.sessionRepo.StoreToken(ctx, user.ID, sessionID, token, rememberMe); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Str("session_id", sessionID).Msg("[AuthService-RefreshSession] Failed to store token in session")
+		return nil, "", errors.New("failed to create session")
+	}
+
+	if tokenString != "" && tokenExpiresAt > 0 {
+		hash := sha256.Sum256([]byte(tokenString))
+		if err := s.blacklistTokenRepo.AddToBlacklist(ctx, hex.EncodeToString(hash[:]), tokenExpiresAt); err != nil {
+			// The session already points at the new token, so the old one is rejected anyway
+			log.Error().Err(err).Int64("user_id", userID).Str("session_id", sessionID).Msg("[AuthService-RefreshSession] Failed to blacklist previous token")
+		}
+	}
+
+	log.Info().Int64("user_id", userID).Str("session_id", sessionID).Msg("[AuthService-RefreshSession] Session refreshed successfully")
+	return user, token, nil
+}
+
+// GetSessions lists the user's signed-in sessions, newest first. Sessions live in a single Redis
+// hash per user, so the page is cut in memory.
+func (s *AuthService) GetSessions(ctx context.Context, userID int64, page, limit int) ([]entity.SessionInfo, *entity.PaginationEntity, error) {
+	page, limit = normalizePage(page, limit)
+
+	sessions, err := s.sessionRepo.GetUserSessions(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-GetSessions] Failed to get sessions")
+		return nil, nil, errors.New("failed to retrieve sessions")
+	}
+
+	now := time.Now()
+	active := make([]entity.SessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		if !session.ExpiresAt.IsZero() && session.ExpiresAt.Before(now) {
+			continue
+		}
+		active = append(active, session)
+	}
+	sort.Slice(active, func(i, j int) bool {
+		return active[i].CreatedAt.After(active[j].CreatedAt)
+	})
+
+	totalCount := int64(len(active))
+	start := min((page-1)*limit, len(active))
+	end := min(start+limit, len(active))
+
+	return active[start:end], newPagination(page, limit, totalCount), nil
+}
+
 func (s *AuthService) GetProfile(ctx context.Context, userID int64) (*entity.UserEntity, error) {
 	user, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
@@ -413,6 +1171,9 @@ func (s *AuthService) UploadProfileImage(ctx context.Context, userID int64, file
 	imageURL, err := s.storage.UploadFile(ctx, "", "", file, contentType)
 	if err != nil {
 		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-UploadProfileImage] Failed to upload image to storage")
+		if errors.Is(err, port.ErrFileInfected) || errors.Is(err, port.ErrScannerUnavailable) {
+			return "", err
+		}
 		return "", errors.New("failed to upload image")
 	}
 
@@ -421,7 +1182,7 @@ func (s *AuthService) UploadProfileImage(ctx context.Context, userID int64, file
 	if err != nil {
 		log.Error().Err(err).Int64("user_id", userID).Str("image_url", imageURL).Msg("[AuthService-UploadProfileImage] Failed to update user photo in database")
 		// Try to delete uploaded file if database update fails
-		newObjectName := s.extractObjectNameFromURL(imageURL)
+		newObjectName := objectNameFromURL(imageURL)
 		if newObjectName != "" {
 			if deleteErr := s.storage.DeleteFile(ctx, "", newObjectName); deleteErr != nil {
 				log.Error().Err(deleteErr).Str("image_url", imageURL).Msg("[AuthService-UploadProfileImage] Failed to delete uploaded file after database error")
@@ -430,16 +1191,12 @@ func (s *AuthService) UploadProfileImage(ctx context.Context, userID int64, file
 		return "", errors.New("failed to update profile")
 	}
 
-	// Delete old photo from storage if it exists
-	if currentUser.Photo != "" && currentUser.Photo != imageURL {
-		oldObjectName := s.extractObjectNameFromURL(currentUser.Photo)
-		if oldObjectName != "" {
-			if deleteErr := s.storage.DeleteFile(ctx, "", oldObjectName); deleteErr != nil {
-				log.Warn().Err(deleteErr).Str("old_photo_url", currentUser.Photo).Msg("[AuthService-UploadProfileImage] Failed to delete old photo from storage")
-				// Don't fail the upload if old photo deletion fails
-			} else {
-				log.Info().Int64("user_id", userID).Str("old_photo_url", currentUser.Photo).Msg("[AuthService-UploadProfileImage] Old photo deleted successfully")
-			}
+	s.deleteReplacedPhotos(ctx, currentUser, imageURL)
+
+	// Moderation runs in the background; the photo stays visible until it is flagged or removed
+	if s.photoModeration != nil {
+		if err := s.photoModeration.Submit(ctx, userID, imageURL); err != nil {
+			log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-UploadProfileImage] Failed to submit photo for moderation")
 		}
 	}
 
@@ -447,14 +1204,154 @@ func (s *AuthService) UploadProfileImage(ctx context.Context, userID int64, file
 	return imageURL, nil
 }
 
-func (s *AuthService) UpdateProfile(ctx context.Context, userID int64, name, email, phone, address string, lat, lng float64, photo string) error {
+// UploadProfilePhoto orients, rotates and crops an uploaded photo into a square JPEG, stores it
+// next to the untouched original and points the profile at the processed copy
+func (s *AuthService) UploadProfilePhoto(ctx context.Context, userID int64, file io.Reader, contentType, filename string, edit entity.PhotoEditEntity) (*entity.ProfilePhotoEntity, error) {
+	log.Info().Int64("user_id", userID).Str("content_type", contentType).Str("filename", filename).Int("rotation", edit.Rotation).Bool("crop", edit.Crop != nil).Msg("[AuthService-UploadProfilePhoto] Starting photo upload")
+
+	original, err := io.ReadAll(file)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-UploadProfilePhoto] Failed to read photo")
+		return nil, errors.New("failed to upload image")
+	}
+
+	var crop *image.Rectangle
+	if edit.Crop != nil {
+		rect := image.Rect(edit.Crop.X, edit.Crop.Y, edit.Crop.X+edit.Crop.Width, edit.Crop.Y+edit.Crop.Height)
+		crop = &rect
+	}
+	processed, err := utils.ProcessProfilePhoto(original, edit.Rotation, crop)
+	if err != nil {
+		log.Warn().Err(err).Int64("user_id", userID).Msg("[AuthService-UploadProfilePhoto] Failed to process photo")
+		return nil, err
+	}
+
+	currentUser, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-UploadProfilePhoto] Failed to get current user data")
+		return nil, errors.New("failed to get user data")
+	}
+
+	originalURL, err := s.storage.UploadFile(ctx, "", "", bytes.NewReader(original), contentType)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-UploadProfilePhoto] Failed to upload original photo to storage")
+		if errors.Is(err, port.ErrFileInfected) || errors.Is(err, port.ErrScannerUnavailable) {
+			return nil, err
+		}
+		return nil, errors.New("failed to upload image")
+	}
+
+	imageURL, err := s.storage.UploadFile(ctx, "", "", bytes.NewReader(processed), "image/jpeg")
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-UploadProfilePhoto] Failed to upload processed photo to storage")
+		s.deleteStoredPhoto(ctx, originalURL)
+		if errors.Is(err, port.ErrFileInfected) || errors.Is(err, port.ErrScannerUnavailable) {
+			return nil, err
+		}
+		return nil, errors.New("failed to upload image")
+	}
+
+	if err := s.userRepo.UpdateUserPhotos(ctx, userID, imageURL, originalURL); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Str("image_url", imageURL).Msg("[AuthService-UploadProfilePhoto] Failed to update user photo in database")
+		s.deleteStoredPhoto(ctx, imageURL)
+		s.deleteStoredPhoto(ctx, originalURL)
+		return nil, errors.New("failed to update profile")
+	}
+
+	s.deleteReplacedPhotos(ctx, currentUser, imageURL)
+
+	// Only the processed copy is ever shown, so it is the one moderated
+	if s.photoModeration != nil {
+		if err := s.photoModeration.Submit(ctx, userID, imageURL); err != nil {
+			log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-UploadProfilePhoto] Failed to submit photo for moderation")
+		}
+	}
+
+	log.Info().Int64("user_id", userID).Str("image_url", imageURL).Str("original_url", originalURL).Msg("[AuthService-UploadProfilePhoto] Profile photo uploaded successfully")
+	return &entity.ProfilePhotoEntity{URL: imageURL, OriginalURL: originalURL}, nil
+}
+
+// deleteReplacedPhotos removes the user's previous photo and its original once a new one is
+// saved. Failures are only logged; the upload has already succeeded.
+func (s *AuthService) deleteReplacedPhotos(ctx context.Context, previous *entity.UserEntity, newURL string) {
+	for _, oldURL := range []string{previous.Photo, previous.PhotoOriginal} {
+		if oldURL != newURL {
+			s.deleteStoredPhoto(ctx, oldURL)
+		}
+	}
+}
+
+// RegenerateAvatar replaces the user's photo with a new generated avatar
+func (s *AuthService) RegenerateAvatar(ctx context.Context, userID int64) (string, error) {
+	if s.storage == nil {
+		return "", errors.New("failed to upload image")
+	}
+
+	// A random seed gives a different avatar each time the user asks for one
+	seed, err := randomHex(16)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-RegenerateAvatar] Failed to generate seed")
+		return "", errors.New("failed to generate avatar")
+	}
+
+	avatar, err := utils.GenerateAvatar(seed, utils.AvatarSize)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-RegenerateAvatar] Failed to render avatar")
+		return "", errors.New("failed to generate avatar")
+	}
+
+	return s.UploadProfileImage(ctx, userID, bytes.NewReader(avatar), "image/png", "avatar.png")
+}
+
+// uploadGeneratedAvatar stores the identicon for seed and returns its URL, or "" without storage
+func (s *AuthService) uploadGeneratedAvatar(ctx context.Context, seed string) (string, error) {
+	if s.storage == nil {
+		return "", nil
+	}
+
+	avatar, err := utils.GenerateAvatar(seed, utils.AvatarSize)
+	if err != nil {
+		return "", err
+	}
+
+	return s.storage.UploadFile(ctx, "", "", bytes.NewReader(avatar), "image/png")
+}
+
+// deleteStoredPhoto removes an uploaded photo that ended up unused or was replaced; failures are only logged
+func (s *AuthService) deleteStoredPhoto(ctx context.Context, photoURL string) {
+	if photoURL == "" || s.storage == nil {
+		return
+	}
+
+	objectName := objectNameFromURL(photoURL)
+	if objectName == "" {
+		return
+	}
+
+	if err := s.storage.DeleteFile(ctx, "", objectName); err != nil {
+		log.Warn().Err(err).Str("photo_url", photoURL).Msg("[AuthService-deleteStoredPhoto] Failed to delete unused photo")
+	}
+}
+
+func (s *AuthService) UpdateProfile(ctx context.Context, userID int64, name, email, phone, address string, lat, lng float64, photo string, version int64) error {
 	// Validate email format
-	if err := s.validateEmail(email); err != nil {
+	normalized, err := s.emailPolicy.NormalizeEmail(email)
+	if err != nil {
 		log.Error().Err(err).Str("email", email).Msg("[AuthService-UpdateProfile] Invalid email format")
+		return ErrInvalidEmail
+	}
+
+	if err := utils.ValidateCoordinates(lat, lng); err != nil {
+		log.Warn().Float64("lat", lat).Float64("lng", lng).Int64("user_id", userID).Msg("[AuthService-UpdateProfile] Invalid coordinates")
 		return err
 	}
 
-	email = strings.ToLower(strings.TrimSpace(email))
+	if err := s.ensureDeliveryCoverage(ctx, lat, lng); err != nil {
+		log.Warn().Err(err).Float64("lat", lat).Float64("lng", lng).Int64("user_id", userID).Msg("[AuthService-UpdateProfile] Location not covered")
+		return err
+	}
+
+	email = normalized
 	name = strings.TrimSpace(name)
 	phone = strings.TrimSpace(phone)
 	address = strings.TrimSpace(address)
@@ -469,10 +1366,25 @@ func (s *AuthService) UpdateProfile(ctx context.Context, userID int64, name, ema
 		return errors.New("failed to get user data")
 	}
 
+	// Reject stale edits before any side effect (verification email, photo cleanup)
+	if version > 0 && currentUser.Version != version {
+		log.Warn().Int64("user_id", userID).Int64("version", version).Int64("current_version", currentUser.Version).Msg("[AuthService-UpdateProfile] Stale profile version")
+		return &entity.VersionConflictError{CurrentVersion: currentUser.Version}
+	}
+
+	// An address stored before normalization still counts as unchanged when it normalizes the same
+	if stored, err := s.emailPolicy.NormalizeEmail(currentUser.Email); err == nil && stored == email {
+		email = currentUser.Email
+	}
 	emailChanged := currentUser.Email != email
 
 	// Check if email is already used by another user
 	if emailChanged {
+		if err := s.emailPolicy.CheckDomain(email); err != nil {
+			log.Warn().Str("email", email).Int64("user_id", userID).Msg("[AuthService-UpdateProfile] Disposable email domain rejected")
+			return err
+		}
+
 		existingUser, err := s.userRepo.GetUserByEmailIncludingUnverified(ctx, email)
 		if err != nil && err.Error() != "record not found" {
 			log.Error().Err(err).Str("email", email).Msg("[AuthService-UpdateProfile] Failed to check email uniqueness")
@@ -488,7 +1400,7 @@ func (s *AuthService) UpdateProfile(ctx context.Context, userID int64, name, ema
 
 	// Handle photo cleanup if photo URL changed
 	if currentUser.Photo != "" && currentUser.Photo != photo {
-		oldObjectName := s.extractObjectNameFromURL(currentUser.Photo)
+		oldObjectName := objectNameFromURL(currentUser.Photo)
 		if oldObjectName != "" {
 			if deleteErr := s.storage.DeleteFile(ctx, "", oldObjectName); deleteErr != nil {
 				log.Warn().Err(deleteErr).Str("old_photo_url", currentUser.Photo).Msg("[AuthService-UpdateProfile] Failed to delete old photo from storage")
@@ -512,9 +1424,9 @@ func (s *AuthService) UpdateProfile(ctx context.Context, userID int64, name, ema
 		verificationToken := &entity.VerificationTokenEntity{
 			UserID:    userID,
 			Token:     token,
-			TokenType: "email_change",
+			TokenType: entity.TokenTypeEmailChange,
 			NewEmail:  email,
-			ExpiresAt: time.Now().Add(24 * time.Hour),
+			ExpiresAt: time.Now().Add(s.tokenLifetimes.For(entity.TokenTypeEmailChange)),
 		}
 
 		err = s.verificationTokenRepo.CreateVerificationToken(ctx, verificationToken)
@@ -548,12 +1460,21 @@ func (s *AuthService) UpdateProfile(ctx context.Context, userID int64, name, ema
 		updateEmail = currentUser.Email
 	}
 
-	err = s.userRepo.UpdateUserProfile(ctx, userID, name, updateEmail, phone, address, lat, lng, photo)
+	err = s.userRepo.UpdateUserProfile(ctx, userID, name, updateEmail, phone, address, lat, lng, photo, version)
 	if err != nil {
+		var conflict *entity.VersionConflictError
+		if errors.As(err, &conflict) {
+			return conflict
+		}
+		if err.Error() == "email already exists" {
+			return err
+		}
 		log.Error().Err(err).Int64("user_id", userID).Str("email", email).Msg("[AuthService-UpdateProfile] Failed to update user profile")
 		return errors.New("failed to update profile")
 	}
 
+	s.refreshAddressComponents(ctx, userID, lat, lng)
+
 	if emailChanged {
 		log.Info().Int64("user_id", userID).Str("email", email).Msg("[AuthService-UpdateProfile] Profile updated successfully, email verification pending")
 	} else {
@@ -563,6 +1484,191 @@ func (s *AuthService) UpdateProfile(ctx context.Context, userID int64, name, ema
 	return nil
 }
 
+// refreshAddressComponents reverse geocodes lat/lng into province/city/district/postal code.
+// Geocoding is best effort: a provider outage must not block profile updates.
+func (s *AuthService) refreshAddressComponents(ctx context.Context, userID int64, lat, lng float64) {
+	if s.geocoder == nil {
+		return
+	}
+
+	address, err := s.geocoder.ReverseGeocode(ctx, lat, lng)
+	if err != nil {
+		log.Warn().Err(err).Int64("user_id", userID).Float64("lat", lat).Float64("lng", lng).Msg("[AuthService-UpdateProfile] Failed to reverse geocode location")
+		return
+	}
+
+	if err := s.userRepo.UpdateUserAddressComponents(ctx, userID, *address); err != nil {
+		log.Warn().Err(err).Int64("user_id", userID).Msg("[AuthService-UpdateProfile] Failed to store address components")
+	}
+}
+
+// ensureDeliveryCoverage rejects locations outside every active delivery zone
+func (s *AuthService) ensureDeliveryCoverage(ctx context.Context, lat, lng float64) error {
+	if s.zoneRepo == nil {
+		return nil
+	}
+
+	_, covered, err := findCoveringZone(ctx, s.zoneRepo, lat, lng)
+	if err != nil {
+		return err
+	}
+
+	if !covered {
+		return ErrOutsideDeliveryArea
+	}
+
+	return nil
+}
+
+// CheckUsernameAvailability reports invalid or reserved names as unavailable with a reason
+// instead of an error, so clients can show it inline while the user types
+func (s *AuthService) CheckUsernameAvailability(ctx context.Context, username string) (*entity.UsernameAvailabilityEntity, error) {
+	normalized, err := utils.NormalizeUsername(username)
+	if err != nil {
+		return &entity.UsernameAvailabilityEntity{Username: username, Reason: err.Error()}, nil
+	}
+
+	if utils.IsReservedUsername(normalized) {
+		return &entity.UsernameAvailabilityEntity{Username: normalized, Reason: utils.ErrReservedUsername.Error()}, nil
+	}
+
+	taken, err := s.userRepo.IsUsernameTaken(ctx, normalized)
+	if err != nil {
+		log.Error().Err(err).Str("username", normalized).Msg("[AuthService-CheckUsernameAvailability] Failed to check username")
+		return nil, errors.New("unable to verify username availability")
+	}
+
+	if taken {
+		return &entity.UsernameAvailabilityEntity{Username: normalized, Reason: "username already taken"}, nil
+	}
+	return &entity.UsernameAvailabilityEntity{Username: normalized, Available: true}, nil
+}
+
+// ChangeUsername claims a new username for the user and returns it normalized
+func (s *AuthService) ChangeUsername(ctx context.Context, userID int64, username string) (string, error) {
+	normalized, err := utils.NormalizeUsername(username)
+	if err != nil {
+		return "", err
+	}
+
+	if utils.IsReservedUsername(normalized) {
+		log.Warn().Int64("user_id", userID).Str("username", normalized).Msg("[AuthService-ChangeUsername] Reserved username rejected")
+		return "", utils.ErrReservedUsername
+	}
+
+	currentUser, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-ChangeUsername] Failed to get current user")
+		if err.Error() == "record not found" {
+			return "", errors.New("user not found")
+		}
+		return "", errors.New("failed to get user data")
+	}
+
+	if currentUser.Username == normalized {
+		return normalized, nil
+	}
+
+	// The unique index has the final say; this check only avoids a failed write in the common case
+	taken, err := s.userRepo.IsUsernameTaken(ctx, normalized)
+	if err != nil {
+		log.Error().Err(err).Str("username", normalized).Msg("[AuthService-ChangeUsername] Failed to check username")
+		return "", errors.New("unable to verify username availability")
+	}
+	if taken {
+		return "", errors.New("username already taken")
+	}
+
+	if err := s.userRepo.UpdateUsername(ctx, userID, normalized); err != nil {
+		if err.Error() == "username already taken" {
+			return "", err
+		}
+		return "", errors.New("failed to update username")
+	}
+
+	log.Info().Int64("user_id", userID).Str("old_username", currentUser.Username).Str("username", normalized).Msg("[AuthService-ChangeUsername] Username changed successfully")
+	return normalized, nil
+}
+
+// ChangeTimezone stores the user's IANA timezone and returns its canonical name
+func (s *AuthService) ChangeTimezone(ctx context.Context, userID int64, timezone string) (string, error) {
+	normalized, err := utils.NormalizeTimezone(timezone)
+	if err != nil {
+		return "", err
+	}
+
+	currentUser, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-ChangeTimezone] Failed to get current user")
+		if err.Error() == "record not found" {
+			return "", errors.New("user not found")
+		}
+		return "", errors.New("failed to get user data")
+	}
+
+	if currentUser.Timezone == normalized {
+		return normalized, nil
+	}
+
+	if err := s.userRepo.UpdateTimezone(ctx, userID, normalized); err != nil {
+		return "", errors.New("failed to update timezone")
+	}
+
+	log.Info().Int64("user_id", userID).Str("old_timezone", currentUser.Timezone).Str("timezone", normalized).Msg("[AuthService-ChangeTimezone] Timezone changed successfully")
+	return normalized, nil
+}
+
+// ChangeLanguage sets the language emails are written in, whatever language later requests use
+func (s *AuthService) ChangeLanguage(ctx context.Context, userID int64, language string) (string, error) {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if !i18n.Supported(language) {
+		return "", errors.New("unsupported language")
+	}
+
+	currentUser, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-ChangeLanguage] Failed to get current user")
+		if err.Error() == "record not found" {
+			return "", errors.New("user not found")
+		}
+		return "", errors.New("failed to get user data")
+	}
+
+	if currentUser.Language == language {
+		return language, nil
+	}
+
+	if err := s.userRepo.UpdateLanguage(ctx, userID, language); err != nil {
+		return "", errors.New("failed to update language")
+	}
+
+	log.Info().Int64("user_id", userID).Str("old_language", currentUser.Language).Str("language", language).Msg("[AuthService-ChangeLanguage] Language changed successfully")
+	return language, nil
+}
+
+// ChangePrivacySettings replaces what the user hides from vendor-facing views
+func (s *AuthService) ChangePrivacySettings(ctx context.Context, userID int64, settings entity.PrivacySettingsEntity) (*entity.PrivacySettingsEntity, error) {
+	currentUser, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[AuthService-ChangePrivacySettings] Failed to get current user")
+		if err.Error() == "record not found" {
+			return nil, errors.New("user not found")
+		}
+		return nil, errors.New("failed to get user data")
+	}
+
+	if currentUser.Privacy == settings {
+		return &settings, nil
+	}
+
+	if err := s.userRepo.UpdatePrivacySettings(ctx, userID, settings); err != nil {
+		return nil, errors.New("failed to update privacy settings")
+	}
+
+	log.Info().Int64("user_id", userID).Bool("hide_phone", settings.HidePhone).Bool("hide_address", settings.HideAddress).Msg("[AuthService-ChangePrivacySettings] Privacy settings changed successfully")
+	return &settings, nil
+}
+
 func (s *AuthService) GetCustomers(ctx context.Context, search string, page, limit int, orderBy string) ([]entity.UserEntity, *entity.PaginationEntity, error) {
 	// Validate pagination parameters
 	if page < 1 {
@@ -607,6 +1713,65 @@ func (s *AuthService) GetCustomerByID(ctx context.Context, customerID int64) (*e
 	return customer, nil
 }
 
+func (s *AuthService) GetCustomersNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]entity.UserEntity, error) {
+	if err := utils.ValidateCoordinates(lat, lng); err != nil {
+		log.Warn().Float64("lat", lat).Float64("lng", lng).Msg("[AuthService-GetCustomersNearby] Invalid coordinates")
+		return nil, err
+	}
+
+	if radiusKm <= 0 || radiusKm > 100 {
+		log.Warn().Float64("radius_km", radiusKm).Msg("[AuthService-GetCustomersNearby] Invalid radius")
+		return nil, errors.New("radius must be between 0 and 100 km")
+	}
+
+	if limit < 1 || limit > 100 {
+		limit = 20 // Default limit
+	}
+
+	customers, err := s.userRepo.GetCustomersNearby(ctx, lat, lng, radiusKm, limit)
+	if err != nil {
+		log.Error().Err(err).Float64("lat", lat).Float64("lng", lng).Float64("radius_km", radiusKm).Msg("[AuthService-GetCustomersNearby] Failed to get nearby customers")
+		return nil, err
+	}
+
+	log.Info().Int("count", len(customers)).Float64("radius_km", radiusKm).Msg("[AuthService-GetCustomersNearby] Nearby customers retrieved successfully")
+	return customers, nil
+}
+
+// GetUsersByIDs serves internal callers (order, delivery) that need many users at once
+func (s *AuthService) GetUsersByIDs(ctx context.Context, userIDs []int64) ([]entity.UserEntity, error) {
+	if len(userIDs) == 0 {
+		return nil, errors.New("user ids are required")
+	}
+
+	seen := make(map[int64]struct{}, len(userIDs))
+	uniqueIDs := make([]int64, 0, len(userIDs))
+	for _, id := range userIDs {
+		if id <= 0 {
+			return nil, errors.New("invalid user id")
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		uniqueIDs = append(uniqueIDs, id)
+	}
+
+	if len(uniqueIDs) > MaxBatchUserIDs {
+		log.Warn().Int("count", len(uniqueIDs)).Msg("[AuthService-GetUsersByIDs] Too many user ids")
+		return nil, errors.New("too many user ids")
+	}
+
+	users, err := s.userRepo.GetUsersByIDs(ctx, uniqueIDs)
+	if err != nil {
+		log.Error().Err(err).Int("count", len(uniqueIDs)).Msg("[AuthService-GetUsersByIDs] Failed to get users")
+		return nil, err
+	}
+
+	log.Info().Int("requested", len(uniqueIDs)).Int("found", len(users)).Msg("[AuthService-GetUsersByIDs] Users retrieved successfully")
+	return users, nil
+}
+
 func (s *AuthService) generateVerificationToken() (string, error) {
 	bytes := make([]byte, 32)
 	if _, err := rand.Read(bytes); err != nil {
@@ -616,7 +1781,7 @@ func (s *AuthService) generateVerificationToken() (string, error) {
 }
 
 // URL format: https://project.supabase.co/storage/v1/object/public/bucket-name/object-name
-func (s *AuthService) extractObjectNameFromURL(url string) string {
+func objectNameFromURL(url string) string {
 	// Find the position after "/storage/v1/object/public/"
 	parts := strings.Split(url, "/storage/v1/object/public/")
 	if len(parts) != 2 {
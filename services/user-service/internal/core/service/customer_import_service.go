@@ -0,0 +1,349 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	maxImportRows = 5000
+	// maxImportResponseErrors caps the row errors returned inline; the report has all of them
+	maxImportResponseErrors = 100
+	importInviteTTL         = 7 * 24 * time.Hour
+	importReportTTL         = 24 * time.Hour
+)
+
+type CustomerImportService struct {
+	userRepo              port.UserRepositoryInterface
+	verificationTokenRepo port.VerificationTokenInterface
+	emailPublisher        port.EmailInterface
+	reportRepo            port.CustomerImportReportRepositoryInterface
+	webhooks              port.WebhookDispatcherInterface
+	emailPolicy           *utils.EmailPolicy
+	passwordHasher        port.PasswordInterface
+}
+
+func (s *CustomerImportService) ImportCustomers(ctx context.Context, file io.Reader, adminID int64, dryRun bool) (*entity.CustomerImportResultEntity, error) {
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("csv file is empty")
+	}
+	if err != nil {
+		log.Warn().Err(err).Msg("[CustomerImportService-ImportCustomers] Failed to read header")
+		return nil, errors.New("invalid csv file")
+	}
+
+	columns, err := parseImportHeader(header)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &entity.CustomerImportResultEntity{DryRun: dryRun}
+	var rowErrors []entity.CustomerImportRowError
+	seen := make(map[string]int)
+
+	var readErr error
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if err != nil && !errors.As(err, &parseErr) {
+			// The upload itself broke off (size limit, client gone); rows so far are kept
+			log.Error().Err(err).Int("rows", result.TotalRows).Msg("[CustomerImportService-ImportCustomers] Failed to read csv")
+			readErr = errors.New("failed to read csv file")
+			break
+		}
+
+		if result.TotalRows == maxImportRows {
+			rowErrors = append(rowErrors, entity.CustomerImportRowError{
+				Message: fmt.Sprintf("row limit of %d exceeded, remaining rows were not processed", maxImportRows),
+			})
+			break
+		}
+		result.TotalRows++
+
+		if parseErr != nil {
+			rowErrors = append(rowErrors, entity.CustomerImportRowError{Row: parseErr.StartLine, Message: "malformed csv row"})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		customer := columns.toRow(line, record)
+
+		if message := s.checkRow(ctx, &customer, seen); message != "" {
+			rowErrors = append(rowErrors, entity.CustomerImportRowError{Row: line, Email: customer.Email, Message: message})
+			continue
+		}
+		result.Valid++
+
+		if dryRun {
+			continue
+		}
+
+		invited, err := s.createCustomer(ctx, customer)
+		if err != nil {
+			message := "failed to create account"
+			if err.Error() == "email already exists" {
+				message = err.Error()
+			}
+			rowErrors = append(rowErrors, entity.CustomerImportRowError{Row: line, Email: customer.Email, Message: message})
+			continue
+		}
+		result.Imported++
+		if invited {
+			result.InvitesSent++
+		}
+	}
+
+	result.Failed = len(rowErrors)
+	result.Errors = rowErrors
+	if len(rowErrors) > maxImportResponseErrors {
+		result.Errors = rowErrors[:maxImportResponseErrors]
+	}
+
+	if len(rowErrors) > 0 {
+		result.ReportID = s.saveReport(ctx, rowErrors)
+	}
+
+	log.Info().Int64("admin_id", adminID).Bool("dry_run", dryRun).Int("total_rows", result.TotalRows).Int("imported", result.Imported).Int("failed", result.Failed).Msg("[CustomerImportService-ImportCustomers] Customer import finished")
+	return result, readErr
+}
+
+func (s *CustomerImportService) GetErrorReport(ctx context.Context, reportID string) ([]byte, error) {
+	report, err := s.reportRepo.GetReport(ctx, reportID)
+	if err != nil {
+		if err.Error() == "import report not found" {
+			return nil, err
+		}
+		log.Error().Err(err).Str("report_id", reportID).Msg("[CustomerImportService-GetErrorReport] Failed to get report")
+		return nil, errors.New("failed to get import report")
+	}
+	return report, nil
+}
+
+// checkRow normalizes the row's email and returns the reason the row cannot be imported, or "" when it is valid
+func (s *CustomerImportService) checkRow(ctx context.Context, customer *entity.CustomerImportRowEntity, seen map[string]int) string {
+	nameLength := utf8.RuneCountInString(customer.Name)
+	switch {
+	case customer.Name == "":
+		return "name is required"
+	case nameLength < 2 || nameLength > 100:
+		return "name must be between 2 and 100 characters"
+	case customer.Email == "":
+		return "email is required"
+	case len(customer.Phone) > 20:
+		return "phone must not exceed 20 characters"
+	}
+
+	email, err := s.emailPolicy.NormalizeEmail(customer.Email)
+	if err != nil {
+		return ErrInvalidEmail.Error()
+	}
+	customer.Email = email
+	if err := s.emailPolicy.CheckDomain(email); err != nil {
+		return err.Error()
+	}
+
+	if firstRow, ok := seen[customer.Email]; ok {
+		return fmt.Sprintf("duplicate email, already on row %d", firstRow)
+	}
+	seen[customer.Email] = customer.Row
+
+	existingUser, err := s.userRepo.GetUserByEmailIncludingUnverified(ctx, customer.Email)
+	if err != nil && err.Error() != "record not found" {
+		log.Error().Err(err).Str("email", customer.Email).Msg("[CustomerImportService-checkRow] Failed to check email availability")
+		return "unable to verify email availability"
+	}
+	if existingUser != nil {
+		return "email already exists"
+	}
+
+	return ""
+}
+
+// createCustomer creates a verified account with an unguessable password and invites the
+// customer to choose their own through the password reset flow
+func (s *CustomerImportService) createCustomer(ctx context.Context, customer entity.CustomerImportRowEntity) (bool, error) {
+	password, err := randomHex(24)
+	if err != nil {
+		return false, err
+	}
+	hashedPassword, err := s.passwordHasher.HashPassword(password)
+	if err != nil {
+		log.Error().Err(err).Str("email", customer.Email).Msg("[CustomerImportService-createCustomer] Failed to hash password")
+		return false, err
+	}
+
+	user, err := s.userRepo.CreateUser(ctx, &entity.UserEntity{
+		Name:       customer.Name,
+		Email:      customer.Email,
+		Password:   hashedPassword,
+		Phone:      customer.Phone,
+		Address:    customer.Address,
+		IsVerified: true,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("email", customer.Email).Msg("[CustomerImportService-createCustomer] Failed to create user")
+		return false, err
+	}
+
+	if s.webhooks != nil {
+		data := map[string]interface{}{
+			"user_id":     user.ID,
+			"email":       customer.Email,
+			"name":        customer.Name,
+			"is_verified": true,
+		}
+		if err := s.webhooks.Dispatch(ctx, entity.WebhookEventUserCreated, fmt.Sprintf("%s:%d", entity.WebhookEventUserCreated, user.ID), data); err != nil {
+			log.Error().Err(err).Int64("user_id", user.ID).Msg("[CustomerImportService-createCustomer] Failed to dispatch webhook")
+		}
+	}
+
+	// From here on the account exists; a missing invite can be replaced by "forgot password"
+	token, err := randomHex(32)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", user.ID).Msg("[CustomerImportService-createCustomer] Failed to generate invite token")
+		return false, nil
+	}
+
+	if err := s.verificationTokenRepo.CreateVerificationToken(ctx, &entity.VerificationTokenEntity{
+		UserID:    user.ID,
+		Token:     token,
+		TokenType: "password_reset",
+		ExpiresAt: time.Now().Add(importInviteTTL),
+	}); err != nil {
+		log.Error().Err(err).Int64("user_id", user.ID).Msg("[CustomerImportService-createCustomer] Failed to save invite token")
+		return false, nil
+	}
+
+	if s.emailPublisher == nil {
+		log.Warn().Int64("user_id", user.ID).Msg("[CustomerImportService-createCustomer] Email publisher unavailable, invite not sent")
+		return false, nil
+	}
+	if err := s.emailPublisher.SendCustomerInviteEmail(ctx, customer.Email, customer.Name, token); err != nil {
+		log.Error().Err(err).Int64("user_id", user.ID).Msg("[CustomerImportService-createCustomer] Failed to send invite email")
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// saveReport stores every row error as CSV; a failure only loses the download, not the import
+func (s *CustomerImportService) saveReport(ctx context.Context, rowErrors []entity.CustomerImportRowError) string {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	_ = writer.Write([]string{"row", "email", "error"})
+	for _, rowError := range rowErrors {
+		row := ""
+		if rowError.Row > 0 {
+			row = strconv.Itoa(rowError.Row)
+		}
+		_ = writer.Write([]string{row, rowError.Email, rowError.Message})
+	}
+	writer.Flush()
+
+	reportID, err := randomHex(16)
+	if err != nil {
+		log.Error().Err(err).Msg("[CustomerImportService-saveReport] Failed to generate report id")
+		return ""
+	}
+
+	if err := s.reportRepo.SaveReport(ctx, reportID, buf.Bytes(), importReportTTL); err != nil {
+		log.Error().Err(err).Msg("[CustomerImportService-saveReport] Failed to save error report")
+		return ""
+	}
+	return reportID
+}
+
+// importColumns maps the CSV header to field positions; -1 means the column is absent
+type importColumns struct {
+	name, email, phone, address int
+}
+
+func parseImportHeader(header []string) (importColumns, error) {
+	columns := importColumns{name: -1, email: -1, phone: -1, address: -1}
+	for i, column := range header {
+		// Spreadsheet exports often start the first column name with a UTF-8 BOM
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff"))) {
+		case "name":
+			columns.name = i
+		case "email":
+			columns.email = i
+		case "phone":
+			columns.phone = i
+		case "address":
+			columns.address = i
+		}
+	}
+
+	if columns.name < 0 || columns.email < 0 {
+		return columns, errors.New("csv header must contain name and email columns")
+	}
+	return columns, nil
+}
+
+func (c importColumns) toRow(line int, record []string) entity.CustomerImportRowEntity {
+	field := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	return entity.CustomerImportRowEntity{
+		Row:     line,
+		Name:    field(c.name),
+		Email:   field(c.email),
+		Phone:   field(c.phone),
+		Address: field(c.address),
+	}
+}
+
+func randomHex(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func NewCustomerImportService(userRepo port.UserRepositoryInterface, verificationTokenRepo port.VerificationTokenInterface, emailPublisher port.EmailInterface, reportRepo port.CustomerImportReportRepositoryInterface, webhooks port.WebhookDispatcherInterface, emailPolicy *utils.EmailPolicy, passwordHasher port.PasswordInterface) port.CustomerImportServiceInterface {
+	if emailPolicy == nil {
+		emailPolicy = utils.DefaultEmailPolicy()
+	}
+	if passwordHasher == nil {
+		passwordHasher = utils.DefaultPasswordHasher()
+	}
+
+	return &CustomerImportService{
+		userRepo:              userRepo,
+		verificationTokenRepo: verificationTokenRepo,
+		emailPublisher:        emailPublisher,
+		reportRepo:            reportRepo,
+		webhooks:              webhooks,
+		emailPolicy:           emailPolicy,
+		passwordHasher:        passwordHasher,
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// customerSuggestMinLength keeps one- and two-letter prefixes, which trigrams cannot narrow, off the database
+	customerSuggestMinLength = 3
+	customerSuggestLimit     = 10
+)
+
+type CustomerSuggestService struct {
+	userRepo port.UserRepositoryInterface
+	cache    *utils.LRUCache[[]entity.UserEntity]
+	timeout  time.Duration
+}
+
+func (s *CustomerSuggestService) Suggest(ctx context.Context, query string) ([]entity.UserEntity, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if utf8.RuneCountInString(query) < customerSuggestMinLength {
+		return []entity.UserEntity{}, nil
+	}
+
+	if customers, ok := s.cache.Get(query); ok {
+		return customers, nil
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	customers, err := s.userRepo.SuggestCustomers(lookupCtx, query, customerSuggestLimit)
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(lookupCtx.Err(), context.DeadlineExceeded) {
+			log.Warn().Str("query", query).Dur("timeout", s.timeout).Msg("[CustomerSuggestService-Suggest] Lookup exceeded its latency budget")
+			return nil, errors.New("customer suggestions timed out")
+		}
+		log.Error().Err(err).Str("query", query).Msg("[CustomerSuggestService-Suggest] Failed to suggest customers")
+		return nil, err
+	}
+
+	s.cache.Add(query, customers)
+	return customers, nil
+}
+
+func NewCustomerSuggestService(userRepo port.UserRepositoryInterface, cfg *config.Config) port.CustomerSuggestServiceInterface {
+	size, ttl := cfg.CustomerSuggest.Cache()
+	return &CustomerSuggestService{
+		userRepo: userRepo,
+		cache:    utils.NewLRUCache[[]entity.UserEntity](size, ttl),
+		timeout:  cfg.CustomerSuggest.Timeout(),
+	}
+}
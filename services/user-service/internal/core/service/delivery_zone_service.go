@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+var ErrOutsideDeliveryArea = errors.New("location is outside our delivery area")
+
+type DeliveryZoneService struct {
+	zoneRepo port.DeliveryZoneRepositoryInterface
+}
+
+func (s *DeliveryZoneService) GetAllZones(ctx context.Context) ([]entity.DeliveryZoneEntity, error) {
+	zones, err := s.zoneRepo.GetAllZones(ctx, false)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeliveryZoneService-GetAllZones] Failed to get zones")
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+func (s *DeliveryZoneService) GetZoneByID(ctx context.Context, id int64) (*entity.DeliveryZoneEntity, error) {
+	zone, err := s.zoneRepo.GetZoneByID(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Int64("zone_id", id).Msg("[DeliveryZoneService-GetZoneByID] Failed to get zone")
+		if err.Error() == "record not found" {
+			return nil, errors.New("zone not found")
+		}
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+func (s *DeliveryZoneService) CreateZone(ctx context.Context, zone *entity.DeliveryZoneEntity) (*entity.DeliveryZoneEntity, error) {
+	if err := s.validateZone(zone); err != nil {
+		log.Warn().Err(err).Str("zone_name", zone.Name).Msg("[DeliveryZoneService-CreateZone] Invalid zone")
+		return nil, err
+	}
+
+	if err := s.ensureUniqueName(ctx, 0, zone.Name); err != nil {
+		return nil, err
+	}
+
+	createdZone, err := s.zoneRepo.CreateZone(ctx, zone)
+	if err != nil {
+		log.Error().Err(err).Str("zone_name", zone.Name).Msg("[DeliveryZoneService-CreateZone] Failed to create zone")
+		return nil, err
+	}
+
+	log.Info().Int64("zone_id", createdZone.ID).Str("zone_type", createdZone.ZoneType).Msg("[DeliveryZoneService-CreateZone] Zone created successfully")
+	return createdZone, nil
+}
+
+func (s *DeliveryZoneService) UpdateZone(ctx context.Context, id int64, zone *entity.DeliveryZoneEntity) (*entity.DeliveryZoneEntity, error) {
+	if err := s.validateZone(zone); err != nil {
+		log.Warn().Err(err).Int64("zone_id", id).Msg("[DeliveryZoneService-UpdateZone] Invalid zone")
+		return nil, err
+	}
+
+	if err := s.ensureUniqueName(ctx, id, zone.Name); err != nil {
+		return nil, err
+	}
+
+	updatedZone, err := s.zoneRepo.UpdateZone(ctx, id, zone)
+	if err != nil {
+		log.Error().Err(err).Int64("zone_id", id).Msg("[DeliveryZoneService-UpdateZone] Failed to update zone")
+		if err.Error() == "record not found" {
+			return nil, errors.New("zone not found")
+		}
+		return nil, err
+	}
+
+	log.Info().Int64("zone_id", id).Msg("[DeliveryZoneService-UpdateZone] Zone updated successfully")
+	return updatedZone, nil
+}
+
+func (s *DeliveryZoneService) DeleteZone(ctx context.Context, id int64) error {
+	if err := s.zoneRepo.DeleteZone(ctx, id); err != nil {
+		log.Error().Err(err).Int64("zone_id", id).Msg("[DeliveryZoneService-DeleteZone] Failed to delete zone")
+		if err.Error() == "record not found" {
+			return errors.New("zone not found")
+		}
+		return err
+	}
+
+	log.Info().Int64("zone_id", id).Msg("[DeliveryZoneService-DeleteZone] Zone deleted successfully")
+	return nil
+}
+
+func (s *DeliveryZoneService) CheckPoint(ctx context.Context, lat, lng float64) (*entity.DeliveryZoneEntity, bool, error) {
+	if err := utils.ValidateCoordinates(lat, lng); err != nil {
+		log.Warn().Float64("lat", lat).Float64("lng", lng).Msg("[DeliveryZoneService-CheckPoint] Invalid coordinates")
+		return nil, false, err
+	}
+
+	zone, covered, err := findCoveringZone(ctx, s.zoneRepo, lat, lng)
+	if err != nil {
+		log.Error().Err(err).Float64("lat", lat).Float64("lng", lng).Msg("[DeliveryZoneService-CheckPoint] Failed to check coverage")
+		return nil, false, err
+	}
+
+	log.Info().Float64("lat", lat).Float64("lng", lng).Bool("covered", covered).Msg("[DeliveryZoneService-CheckPoint] Coverage checked")
+	return zone, covered, nil
+}
+
+func (s *DeliveryZoneService) validateZone(zone *entity.DeliveryZoneEntity) error {
+	zone.Name = strings.TrimSpace(zone.Name)
+	if len(zone.Name) < 2 || len(zone.Name) > 100 {
+		return errors.New("zone name must be between 2 and 100 characters")
+	}
+
+	switch zone.ZoneType {
+	case entity.ZoneTypeRadius:
+		if utils.ValidateCoordinates(zone.CenterLat, zone.CenterLng) != nil || zone.RadiusKm <= 0 {
+			return errors.New("radius zone requires a valid center and a positive radius")
+		}
+		zone.Polygon = nil
+	case entity.ZoneTypePolygon:
+		if len(zone.Polygon) < 3 {
+			return errors.New("polygon zone requires at least 3 points")
+		}
+		for _, point := range zone.Polygon {
+			if utils.ValidateCoordinates(point.Lat, point.Lng) != nil {
+				return errors.New("polygon zone contains invalid coordinates")
+			}
+		}
+		zone.CenterLat, zone.CenterLng, zone.RadiusKm = 0, 0, 0
+	default:
+		return errors.New("zone type must be radius or polygon")
+	}
+
+	return nil
+}
+
+func (s *DeliveryZoneService) ensureUniqueName(ctx context.Context, id int64, name string) error {
+	zones, err := s.zoneRepo.GetAllZones(ctx, false)
+	if err != nil {
+		log.Error().Err(err).Msg("[DeliveryZoneService-ensureUniqueName] Failed to check existing zones")
+		return err
+	}
+
+	for _, zone := range zones {
+		if zone.ID != id && strings.EqualFold(zone.Name, name) {
+			log.Warn().Str("zone_name", name).Msg("[DeliveryZoneService-ensureUniqueName] Zone name already exists")
+			return fmt.Errorf("zone with name '%s' already exists", name)
+		}
+	}
+
+	return nil
+}
+
+// findCoveringZone returns the first active zone containing lat/lng.
+// Coverage is only restricted once at least one active zone exists, so an empty
+// zone table reports covered with a nil zone.
+func findCoveringZone(ctx context.Context, zoneRepo port.DeliveryZoneRepositoryInterface, lat, lng float64) (*entity.DeliveryZoneEntity, bool, error) {
+	zones, err := zoneRepo.GetAllZones(ctx, true)
+	if err != nil {
+		return nil, false, errors.New("unable to verify delivery coverage")
+	}
+
+	if len(zones) == 0 {
+		return nil, true, nil
+	}
+
+	for i := range zones {
+		if zones[i].Contains(lat, lng) {
+			return &zones[i], true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+func NewDeliveryZoneService(zoneRepo port.DeliveryZoneRepositoryInterface) port.DeliveryZoneServiceInterface {
+	return &DeliveryZoneService{
+		zoneRepo: zoneRepo,
+	}
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+type DeviceService struct {
+	deviceRepo port.DeviceRepositoryInterface
+}
+
+func (s *DeviceService) GetDevices(ctx context.Context, userID int64) ([]entity.DeviceEntity, error) {
+	devices, err := s.deviceRepo.GetDevices(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to get devices")
+	}
+	return devices, nil
+}
+
+func (s *DeviceService) SetTrusted(ctx context.Context, userID, deviceID int64, trusted bool) error {
+	if err := s.deviceRepo.SetTrusted(ctx, userID, deviceID, trusted); err != nil {
+		if err.Error() == "record not found" {
+			return errors.New("device not found")
+		}
+		return errors.New("failed to update device")
+	}
+
+	log.Info().Int64("user_id", userID).Int64("device_id", deviceID).Bool("trusted", trusted).Msg("[DeviceService-SetTrusted] Device trust updated")
+	return nil
+}
+
+func NewDeviceService(deviceRepo port.DeviceRepositoryInterface) port.DeviceServiceInterface {
+	return &DeviceService{
+		deviceRepo: deviceRepo,
+	}
+}
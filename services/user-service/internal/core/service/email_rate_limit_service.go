@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxEmailsPerHour keeps a typo from effectively turning the quota off
+const maxEmailsPerHour = 1000
+
+// emailTypePattern matches the message types user-service publishes, e.g. back_in_stock
+var emailTypePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+
+type EmailRateLimitService struct {
+	limitRepo       port.EmailRateLimitRepositoryInterface
+	auditLogService port.AuditLogServiceInterface
+}
+
+func (s *EmailRateLimitService) GetLimits(ctx context.Context) ([]entity.EmailRateLimitEntity, error) {
+	limits, err := s.limitRepo.GetAllLimits(ctx)
+	if err != nil {
+		return nil, errors.New("failed to retrieve email rate limits")
+	}
+	return limits, nil
+}
+
+func (s *EmailRateLimitService) SetLimit(ctx context.Context, adminID int64, emailType string, maxPerHour int) (*entity.EmailRateLimitEntity, error) {
+	emailType = strings.ToLower(strings.TrimSpace(emailType))
+	if emailType != entity.EmailRateLimitAllEmails && !emailTypePattern.MatchString(emailType) {
+		return nil, errors.New("email type must be * or a template name such as back_in_stock")
+	}
+	if maxPerHour < 0 || maxPerHour > maxEmailsPerHour {
+		return nil, errors.New("max per hour must be between 0 and 1000")
+	}
+
+	limit, err := s.limitRepo.UpsertLimit(ctx, &entity.EmailRateLimitEntity{EmailType: emailType, MaxPerHour: maxPerHour, UpdatedBy: adminID})
+	if err != nil {
+		return nil, errors.New("failed to save email rate limit")
+	}
+
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{
+		UserID: adminID,
+		Event:  entity.AuditEventEmailRateLimitChanged,
+		Metadata: map[string]interface{}{
+			"email_type":   emailType,
+			"max_per_hour": maxPerHour,
+		},
+	})
+	log.Info().Int64("admin_id", adminID).Str("email_type", emailType).Int("max_per_hour", maxPerHour).Msg("[EmailRateLimitService-SetLimit] Email rate limit saved")
+
+	if err := s.SyncLimits(ctx); err != nil {
+		return nil, err
+	}
+	return limit, nil
+}
+
+func (s *EmailRateLimitService) DeleteLimit(ctx context.Context, adminID int64, emailType string) error {
+	emailType = strings.ToLower(strings.TrimSpace(emailType))
+	if err := s.limitRepo.DeleteLimit(ctx, emailType); err != nil {
+		if err.Error() == "record not found" {
+			return errors.New("email rate limit not found")
+		}
+		return errors.New("failed to delete email rate limit")
+	}
+
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{
+		UserID: adminID,
+		Event:  entity.AuditEventEmailRateLimitChanged,
+		Metadata: map[string]interface{}{
+			"email_type": emailType,
+			"deleted":    true,
+		},
+	})
+	log.Info().Int64("admin_id", adminID).Str("email_type", emailType).Msg("[EmailRateLimitService-DeleteLimit] Email rate limit deleted")
+
+	return s.SyncLimits(ctx)
+}
+
+// SyncLimits publishes every stored limit at once, so notification-service never sees half a change
+func (s *EmailRateLimitService) SyncLimits(ctx context.Context) error {
+	limits, err := s.limitRepo.GetAllLimits(ctx)
+	if err != nil {
+		return errors.New("failed to apply email rate limits")
+	}
+	if err := s.limitRepo.PublishLimits(ctx, limits); err != nil {
+		return errors.New("failed to apply email rate limits")
+	}
+	return nil
+}
+
+func NewEmailRateLimitService(limitRepo port.EmailRateLimitRepositoryInterface, auditLogService port.AuditLogServiceInterface) port.EmailRateLimitServiceInterface {
+	return &EmailRateLimitService{
+		limitRepo:       limitRepo,
+		auditLogService: auditLogService,
+	}
+}
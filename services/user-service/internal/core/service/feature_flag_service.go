@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+var featureFlagKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{1,99}$`)
+
+type FeatureFlagService struct {
+	flagRepo port.FeatureFlagRepositoryInterface
+}
+
+func (s *FeatureFlagService) IsEnabled(ctx context.Context, key string, userID int64) bool {
+	flag, err := s.flagRepo.GetFlagByKey(ctx, key)
+	if err != nil {
+		if err.Error() != "record not found" {
+			log.Error().Err(err).Str("key", key).Msg("[FeatureFlagService-IsEnabled] Failed to get flag, treating as disabled")
+		}
+		return false
+	}
+
+	return flag.IsEnabledFor(userID)
+}
+
+func (s *FeatureFlagService) GetEnabledFlags(ctx context.Context, userID int64) ([]string, error) {
+	flags, err := s.flagRepo.GetAllFlags(ctx)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[FeatureFlagService-GetEnabledFlags] Failed to get flags")
+		return nil, errors.New("failed to retrieve feature flags")
+	}
+
+	enabled := make([]string, 0, len(flags))
+	for i := range flags {
+		if flags[i].IsEnabledFor(userID) {
+			enabled = append(enabled, flags[i].Key)
+		}
+	}
+
+	return enabled, nil
+}
+
+func (s *FeatureFlagService) GetAllFlags(ctx context.Context) ([]entity.FeatureFlagEntity, error) {
+	flags, err := s.flagRepo.GetAllFlags(ctx)
+	if err != nil {
+		return nil, errors.New("failed to retrieve feature flags")
+	}
+
+	return flags, nil
+}
+
+func (s *FeatureFlagService) GetFlagByKey(ctx context.Context, key string) (*entity.FeatureFlagEntity, error) {
+	flag, err := s.flagRepo.GetFlagByKey(ctx, key)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("feature flag not found")
+		}
+		return nil, errors.New("failed to retrieve feature flag")
+	}
+
+	return flag, nil
+}
+
+func (s *FeatureFlagService) CreateFlag(ctx context.Context, flag *entity.FeatureFlagEntity) (*entity.FeatureFlagEntity, error) {
+	flag.Key = strings.ToLower(strings.TrimSpace(flag.Key))
+	if err := s.validate(flag); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.flagRepo.GetFlagByKey(ctx, flag.Key); err == nil {
+		log.Warn().Str("key", flag.Key).Msg("[FeatureFlagService-CreateFlag] Feature flag already exists")
+		return nil, errors.New("feature flag already exists")
+	} else if err.Error() != "record not found" {
+		log.Error().Err(err).Str("key", flag.Key).Msg("[FeatureFlagService-CreateFlag] Failed to check existing flag")
+		return nil, errors.New("failed to create feature flag")
+	}
+
+	created, err := s.flagRepo.CreateFlag(ctx, flag)
+	if err != nil {
+		return nil, errors.New("failed to create feature flag")
+	}
+
+	log.Info().Str("key", created.Key).Bool("enabled", created.Enabled).Int("rollout_percentage", created.RolloutPercentage).Msg("[FeatureFlagService-CreateFlag] Feature flag created")
+	return created, nil
+}
+
+func (s *FeatureFlagService) UpdateFlag(ctx context.Context, key string, flag *entity.FeatureFlagEntity) (*entity.FeatureFlagEntity, error) {
+	flag.Key = key
+	if err := s.validate(flag); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.flagRepo.UpdateFlag(ctx, key, flag)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("feature flag not found")
+		}
+		return nil, errors.New("failed to update feature flag")
+	}
+
+	return updated, nil
+}
+
+func (s *FeatureFlagService) DeleteFlag(ctx context.Context, key string) error {
+	if err := s.flagRepo.DeleteFlag(ctx, key); err != nil {
+		if err.Error() == "record not found" {
+			return errors.New("feature flag not found")
+		}
+		return errors.New("failed to delete feature flag")
+	}
+
+	log.Info().Str("key", key).Msg("[FeatureFlagService-DeleteFlag] Feature flag deleted")
+	return nil
+}
+
+func (s *FeatureFlagService) SetOverride(ctx context.Context, key string, userID int64, enabled bool) error {
+	if userID <= 0 {
+		return errors.New("invalid user id")
+	}
+
+	if err := s.flagRepo.SetOverride(ctx, key, userID, enabled); err != nil {
+		if err.Error() == "record not found" {
+			return errors.New("feature flag not found")
+		}
+		return errors.New("failed to set override")
+	}
+
+	log.Info().Str("key", key).Int64("user_id", userID).Bool("enabled", enabled).Msg("[FeatureFlagService-SetOverride] Override set")
+	return nil
+}
+
+func (s *FeatureFlagService) DeleteOverride(ctx context.Context, key string, userID int64) error {
+	if err := s.flagRepo.DeleteOverride(ctx, key, userID); err != nil {
+		switch err.Error() {
+		case "record not found":
+			return errors.New("feature flag not found")
+		case "override not found":
+			return err
+		default:
+			return errors.New("failed to delete override")
+		}
+	}
+
+	return nil
+}
+
+func (s *FeatureFlagService) validate(flag *entity.FeatureFlagEntity) error {
+	if !featureFlagKeyPattern.MatchString(flag.Key) {
+		return errors.New("flag key must be 2-100 lowercase letters, digits, dots, dashes or underscores")
+	}
+	if flag.RolloutPercentage < 0 || flag.RolloutPercentage > 100 {
+		return errors.New("rollout percentage must be between 0 and 100")
+	}
+	flag.Description = strings.TrimSpace(flag.Description)
+	return nil
+}
+
+func NewFeatureFlagService(flagRepo port.FeatureFlagRepositoryInterface) port.FeatureFlagServiceInterface {
+	return &FeatureFlagService{
+		flagRepo: flagRepo,
+	}
+}
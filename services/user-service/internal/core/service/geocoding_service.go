@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+type GeocodingService struct {
+	geocoder port.GeocoderInterface
+}
+
+func (s *GeocodingService) ReverseGeocode(ctx context.Context, lat, lng float64) (*entity.AddressEntity, error) {
+	if err := utils.ValidateCoordinates(lat, lng); err != nil {
+		log.Warn().Float64("lat", lat).Float64("lng", lng).Msg("[GeocodingService-ReverseGeocode] Invalid coordinates")
+		return nil, err
+	}
+
+	if s.geocoder == nil {
+		log.Warn().Msg("[GeocodingService-ReverseGeocode] Geocoder is not configured")
+		return nil, errors.New("geocoding service unavailable")
+	}
+
+	address, err := s.geocoder.ReverseGeocode(ctx, lat, lng)
+	if err != nil {
+		if err.Error() == "location not found" {
+			return nil, errors.New("location not found")
+		}
+		log.Error().Err(err).Float64("lat", lat).Float64("lng", lng).Msg("[GeocodingService-ReverseGeocode] Failed to reverse geocode")
+		return nil, errors.New("geocoding service unavailable")
+	}
+
+	// Keep the coordinates the caller asked about rather than the snapped ones
+	address.Lat = lat
+	address.Lng = lng
+
+	log.Info().Float64("lat", lat).Float64("lng", lng).Str("city", address.City).Msg("[GeocodingService-ReverseGeocode] Location resolved successfully")
+	return address, nil
+}
+
+func (s *GeocodingService) Geocode(ctx context.Context, address string) (*entity.AddressEntity, error) {
+	address = strings.TrimSpace(address)
+	if len(address) < 3 {
+		log.Warn().Str("address", address).Msg("[GeocodingService-Geocode] Address too short")
+		return nil, errors.New("address is required")
+	}
+
+	if s.geocoder == nil {
+		log.Warn().Msg("[GeocodingService-Geocode] Geocoder is not configured")
+		return nil, errors.New("geocoding service unavailable")
+	}
+
+	result, err := s.geocoder.Geocode(ctx, address)
+	if err != nil {
+		if err.Error() == "location not found" {
+			return nil, errors.New("location not found")
+		}
+		log.Error().Err(err).Str("address", address).Msg("[GeocodingService-Geocode] Failed to geocode address")
+		return nil, errors.New("geocoding service unavailable")
+	}
+
+	log.Info().Str("address", address).Float64("lat", result.Lat).Float64("lng", result.Lng).Msg("[GeocodingService-Geocode] Address resolved successfully")
+	return result, nil
+}
+
+func NewGeocodingService(geocoder port.GeocoderInterface) port.GeocodingServiceInterface {
+	return &GeocodingService{
+		geocoder: geocoder,
+	}
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+// identityDocumentLinkTTL is how long an admin's link to an ID document works
+const identityDocumentLinkTTL = 10 * time.Minute
+
+var identityDocumentTypes = map[string]bool{
+	"ktp":      true,
+	"passport": true,
+	"sim":      true,
+}
+
+// identityContentTypes maps the sniffed content type to the stored extension
+var identityContentTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"application/pdf": ".pdf",
+}
+
+type IdentityService struct {
+	identityRepo    port.IdentityRepositoryInterface
+	storage         port.StorageInterface
+	auditLogService port.AuditLogServiceInterface
+	config          config.Identity
+}
+
+func (s *IdentityService) Submit(ctx context.Context, userID int64, documentType string, dateOfBirth time.Time, file io.Reader) (*entity.IdentityVerificationEntity, error) {
+	documentType = strings.ToLower(strings.TrimSpace(documentType))
+	if !identityDocumentTypes[documentType] {
+		return nil, errors.New("document type must be one of ktp, passport, sim")
+	}
+	if age := entity.AgeOn(dateOfBirth, time.Now()); dateOfBirth.IsZero() || age < 0 || age > 120 {
+		return nil, errors.New("date of birth is invalid")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, s.config.MaxSize()+1))
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[IdentityService-Submit] Failed to read document")
+		return nil, errors.New("failed to upload document")
+	}
+	if len(data) == 0 {
+		return nil, errors.New("document is empty")
+	}
+	if int64(len(data)) > s.config.MaxSize() {
+		return nil, errors.New("document exceeds the size limit")
+	}
+	contentType := http.DetectContentType(data)
+	ext, ok := identityContentTypes[contentType]
+	if !ok {
+		return nil, errors.New("document must be a JPEG, PNG or PDF file")
+	}
+
+	// Checked before uploading so a second submission does not leave an orphaned file
+	if latest, err := s.identityRepo.GetLatest(ctx, userID); err == nil && latest.Status == entity.IdentityVerificationPending {
+		return nil, errors.New("identity verification already pending")
+	}
+
+	if s.storage == nil {
+		log.Error().Int64("user_id", userID).Msg("[IdentityService-Submit] Storage is not configured")
+		return nil, errors.New("storage service unavailable")
+	}
+
+	bucket := s.config.BucketName()
+	objectName := utils.ContentObjectName(fmt.Sprintf("%d/%s-", userID, documentType), data, ext)
+	if _, err := s.storage.UploadFile(ctx, bucket, objectName, bytes.NewReader(data), contentType); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[IdentityService-Submit] Failed to upload document")
+		if errors.Is(err, port.ErrFileInfected) || errors.Is(err, port.ErrScannerUnavailable) {
+			return nil, err
+		}
+		return nil, errors.New("failed to upload document")
+	}
+
+	verification, err := s.identityRepo.Create(ctx, &entity.IdentityVerificationEntity{
+		UserID:       userID,
+		DocumentType: documentType,
+		ObjectName:   objectName,
+		ContentType:  contentType,
+		DateOfBirth:  dateOfBirth,
+	})
+	if err != nil {
+		if deleteErr := s.storage.DeleteFile(ctx, bucket, objectName); deleteErr != nil {
+			log.Error().Err(deleteErr).Str("object_name", objectName).Msg("[IdentityService-Submit] Failed to delete document after database error")
+		}
+		if err.Error() == "identity verification already pending" {
+			return nil, err
+		}
+		return nil, errors.New("failed to save document")
+	}
+
+	log.Info().Int64("user_id", userID).Int64("verification_id", verification.ID).Str("document_type", documentType).Msg("[IdentityService-Submit] Identity document submitted for review")
+	return verification, nil
+}
+
+// Status keeps an earlier approval valid while a newer submission waits for review
+func (s *IdentityService) Status(ctx context.Context, userID int64) (*entity.IdentityStatusEntity, error) {
+	status := &entity.IdentityStatusEntity{Status: entity.IdentityVerificationNone}
+
+	latest, err := s.identityRepo.GetLatest(ctx, userID)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return status, nil
+		}
+		return nil, errors.New("failed to retrieve identity verification")
+	}
+	status.Status = latest.Status
+	status.RejectionReason = latest.RejectionReason
+	status.SubmittedAt = &latest.CreatedAt
+
+	approved := latest
+	if latest.Status != entity.IdentityVerificationApproved {
+		approved, err = s.identityRepo.GetLatestApproved(ctx, userID)
+		if err != nil && err.Error() != "record not found" {
+			return nil, errors.New("failed to retrieve identity verification")
+		}
+	}
+	if approved == nil {
+		return status, nil
+	}
+
+	now := time.Now()
+	status.ExpiresAt = approved.ExpiresAt
+	status.Verified = approved.ExpiresAt == nil || now.Before(*approved.ExpiresAt)
+	status.AgeVerified = status.Verified && entity.AgeOn(approved.DateOfBirth, now) >= s.config.MinAge()
+	if latest == approved && !status.Verified {
+		status.Status = entity.IdentityVerificationExpired
+	}
+	return status, nil
+}
+
+func (s *IdentityService) GetVerifications(ctx context.Context, status string, page, limit int) ([]entity.IdentityVerificationEntity, *entity.PaginationEntity, error) {
+	page, limit = normalizePage(page, limit)
+	verifications, totalCount, err := s.identityRepo.List(ctx, status, page, limit)
+	if err != nil {
+		return nil, nil, errors.New("failed to retrieve identity verifications")
+	}
+
+	return verifications, newPagination(page, limit, totalCount), nil
+}
+
+func (s *IdentityService) GetVerification(ctx context.Context, id int64) (*entity.IdentityVerificationEntity, error) {
+	verification, err := s.getVerification(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a link the admin can still see the metadata; the document stays private
+	signer, ok := s.storage.(port.SignedURLInterface)
+	if !ok {
+		log.Warn().Int64("verification_id", id).Msg("[IdentityService-GetVerification] Storage cannot sign document links")
+		return verification, nil
+	}
+	url, err := signer.SignedURL(ctx, s.config.BucketName(), verification.ObjectName, identityDocumentLinkTTL)
+	if err != nil {
+		log.Error().Err(err).Int64("verification_id", id).Msg("[IdentityService-GetVerification] Failed to sign document link")
+		return verification, nil
+	}
+	verification.DocumentURL = url
+	return verification, nil
+}
+
+func (s *IdentityService) Approve(ctx context.Context, id, adminID int64) (*entity.IdentityVerificationEntity, error) {
+	expiresAt := time.Now().Add(s.config.Validity())
+	return s.review(ctx, id, &entity.IdentityVerificationEntity{
+		Status:     entity.IdentityVerificationApproved,
+		ReviewedBy: adminID,
+		ExpiresAt:  &expiresAt,
+	}, entity.AuditEventIdentityApproved)
+}
+
+func (s *IdentityService) Reject(ctx context.Context, id, adminID int64, reason string) (*entity.IdentityVerificationEntity, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("rejection reason is required")
+	}
+
+	return s.review(ctx, id, &entity.IdentityVerificationEntity{
+		Status:          entity.IdentityVerificationRejected,
+		RejectionReason: reason,
+		ReviewedBy:      adminID,
+	}, entity.AuditEventIdentityRejected)
+}
+
+func (s *IdentityService) review(ctx context.Context, id int64, update *entity.IdentityVerificationEntity, event string) (*entity.IdentityVerificationEntity, error) {
+	verification, err := s.getVerification(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.identityRepo.Review(ctx, id, update); err != nil {
+		if err.Error() == "identity verification already reviewed" {
+			return nil, err
+		}
+		return nil, errors.New("failed to review identity verification")
+	}
+
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{
+		UserID: update.ReviewedBy,
+		Event:  event,
+		Metadata: map[string]interface{}{
+			"verification_id": id,
+			"user_id":         verification.UserID,
+		},
+	})
+
+	log.Info().Int64("verification_id", id).Int64("admin_id", update.ReviewedBy).Str("status", update.Status).Msg("[IdentityService-review] Identity verification reviewed")
+	return s.getVerification(ctx, id)
+}
+
+func (s *IdentityService) getVerification(ctx context.Context, id int64) (*entity.IdentityVerificationEntity, error) {
+	verification, err := s.identityRepo.GetByID(ctx, id)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("identity verification not found")
+		}
+		return nil, errors.New("failed to retrieve identity verification")
+	}
+	return verification, nil
+}
+
+func NewIdentityService(identityRepo port.IdentityRepositoryInterface, storage port.StorageInterface, auditLogService port.AuditLogServiceInterface, cfg *config.Config) port.IdentityServiceInterface {
+	return &IdentityService{
+		identityRepo:    identityRepo,
+		storage:         storage,
+		auditLogService: auditLogService,
+		config:          cfg.Identity,
+	}
+}
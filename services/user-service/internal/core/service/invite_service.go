@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// inviteCodeAlphabet leaves out 0/O and 1/I, since codes are read out and typed by hand
+	inviteCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	inviteCodeLength   = 10
+)
+
+// inviteCodePattern is what admins may pick themselves, e.g. "BETA-BANDUNG"
+var inviteCodePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9-]{3,31}$`)
+
+type InviteService struct {
+	inviteRepo port.InviteRepositoryInterface
+}
+
+func (s *InviteService) CreateInvite(ctx context.Context, invite *entity.InviteEntity) (*entity.InviteEntity, error) {
+	invite.Code = normalizeInviteCode(invite.Code)
+	invite.Note = strings.TrimSpace(invite.Note)
+	if invite.Code == "" {
+		code, err := generateInviteCode()
+		if err != nil {
+			log.Error().Err(err).Msg("[InviteService-CreateInvite] Failed to generate invite code")
+			return nil, errors.New("failed to create invite")
+		}
+		invite.Code = code
+	} else if !inviteCodePattern.MatchString(invite.Code) {
+		return nil, errors.New("invite code must be 4-32 letters, digits or dashes")
+	}
+	if invite.MaxUses < 1 {
+		return nil, errors.New("invite max_uses must be at least 1")
+	}
+	if invite.ExpiresAt != nil && !invite.ExpiresAt.After(time.Now()) {
+		return nil, errors.New("invite expiry must be in the future")
+	}
+
+	created, err := s.inviteRepo.CreateInvite(ctx, invite)
+	if err != nil {
+		if err.Error() == "invite code already exists" {
+			return nil, err
+		}
+		return nil, errors.New("failed to create invite")
+	}
+
+	log.Info().Int64("invite_id", created.ID).Int("max_uses", created.MaxUses).Int64("created_by", invite.CreatedBy).Msg("[InviteService-CreateInvite] Invite created")
+	return created, nil
+}
+
+func (s *InviteService) GetInvites(ctx context.Context, page, limit int) ([]entity.InviteEntity, *entity.PaginationEntity, error) {
+	page, limit = normalizePage(page, limit)
+	invites, totalCount, err := s.inviteRepo.GetInvites(ctx, page, limit)
+	if err != nil {
+		return nil, nil, errors.New("failed to retrieve invites")
+	}
+
+	return invites, newPagination(page, limit, totalCount), nil
+}
+
+// RevokeInvite stops new sign-ups with the code; accounts already created keep working
+func (s *InviteService) RevokeInvite(ctx context.Context, id int64) error {
+	if err := s.inviteRepo.RevokeInvite(ctx, id); err != nil {
+		if err.Error() == "record not found" {
+			return errors.New("invite not found")
+		}
+		return errors.New("failed to revoke invite")
+	}
+
+	log.Info().Int64("invite_id", id).Msg("[InviteService-RevokeInvite] Invite revoked")
+	return nil
+}
+
+func (s *InviteService) Reserve(ctx context.Context, code string) (*entity.InviteEntity, error) {
+	code = normalizeInviteCode(code)
+	if code == "" {
+		return nil, errors.New("invite code is required")
+	}
+
+	id, ok, err := s.inviteRepo.ReserveUse(ctx, code)
+	if err != nil {
+		return nil, errors.New("failed to check invite code")
+	}
+	if ok {
+		return &entity.InviteEntity{ID: id, Code: code}, nil
+	}
+
+	// The reservation only says no; look the code up again to tell the user why
+	invite, err := s.inviteRepo.GetInviteByCode(ctx, code)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("invalid invite code")
+		}
+		return nil, errors.New("failed to check invite code")
+	}
+	switch {
+	case invite.RevokedAt != nil:
+		return nil, errors.New("invalid invite code")
+	case invite.IsExpired(time.Now()):
+		return nil, errors.New("invite code has expired")
+	default:
+		return nil, errors.New("invite code has been used up")
+	}
+}
+
+func (s *InviteService) Release(ctx context.Context, invite *entity.InviteEntity) {
+	if err := s.inviteRepo.ReleaseUse(ctx, invite.ID); err != nil {
+		log.Warn().Err(err).Int64("invite_id", invite.ID).Msg("[InviteService-Release] Failed to release invite use")
+	}
+}
+
+// RecordRedemption is best effort; the use is already counted, only the history is missing
+func (s *InviteService) RecordRedemption(ctx context.Context, invite *entity.InviteEntity, email string) {
+	if err := s.inviteRepo.CreateRedemption(ctx, invite.ID, email); err != nil {
+		log.Warn().Err(err).Int64("invite_id", invite.ID).Msg("[InviteService-RecordRedemption] Failed to record invite redemption")
+	}
+}
+
+func normalizeInviteCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// generateInviteCode returns e.g. "K7QMX-4TPZA"
+func generateInviteCode() (string, error) {
+	var code strings.Builder
+	for i := 0; i < inviteCodeLength; i++ {
+		if i == inviteCodeLength/2 {
+			code.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(inviteCodeAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		code.WriteByte(inviteCodeAlphabet[n.Int64()])
+	}
+	return code.String(), nil
+}
+
+func NewInviteService(inviteRepo port.InviteRepositoryInterface) port.InviteServiceInterface {
+	return &InviteService{
+		inviteRepo: inviteRepo,
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+type IPAccessService struct {
+	ipAccessRepo port.IPAccessRepositoryInterface
+	config       *config.Config
+}
+
+func (s *IPAccessService) IsAllowed(ctx context.Context, ip string) (bool, string) {
+	staticAllow := s.config.AdminAccess.IPAllowlist
+	if utils.IPInCIDRs(ip, staticAllow) {
+		return true, ""
+	}
+
+	allow, deny, err := s.loadRules(ctx)
+	if err != nil {
+		// Without the dynamic lists we cannot tell whether this IP was denied
+		log.Error().Err(err).Str("ip", ip).Msg("[IPAccessService-IsAllowed] IP rules unavailable, denying")
+		return false, "ip rules unavailable"
+	}
+
+	return evaluateIPRules(ip, staticAllow, allow, deny)
+}
+
+func (s *IPAccessService) GetRules(ctx context.Context) ([]entity.IPRuleEntity, error) {
+	allow, deny, err := s.loadRules(ctx)
+	if err != nil {
+		return nil, errors.New("failed to retrieve ip rules")
+	}
+
+	rules := make([]entity.IPRuleEntity, 0, len(s.config.AdminAccess.IPAllowlist)+len(allow)+len(deny))
+	for _, cidr := range s.config.AdminAccess.IPAllowlist {
+		normalized, err := utils.NormalizeCIDR(cidr)
+		if err != nil {
+			log.Warn().Str("cidr", cidr).Msg("[IPAccessService-GetRules] Ignoring invalid ADMIN_IP_ALLOWLIST entry")
+			continue
+		}
+		rules = append(rules, entity.IPRuleEntity{CIDR: normalized, ListType: entity.IPRuleListAllow, Note: "ADMIN_IP_ALLOWLIST", Static: true})
+	}
+	rules = append(rules, allow...)
+	rules = append(rules, deny...)
+
+	return rules, nil
+}
+
+func (s *IPAccessService) AddRule(ctx context.Context, listType, cidr, note string, adminID int64, callerIP string) (*entity.IPRuleEntity, error) {
+	if listType != entity.IPRuleListAllow && listType != entity.IPRuleListDeny {
+		return nil, errors.New("list type must be allow or deny")
+	}
+
+	normalized, err := utils.NormalizeCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	allow, deny, err := s.loadRules(ctx)
+	if err != nil {
+		return nil, errors.New("failed to add ip rule")
+	}
+
+	rule := &entity.IPRuleEntity{
+		CIDR:      normalized,
+		ListType:  listType,
+		Note:      strings.TrimSpace(note),
+		CreatedBy: adminID,
+		CreatedAt: time.Now(),
+	}
+
+	if listType == entity.IPRuleListAllow {
+		allow = append(allow, *rule)
+	} else {
+		deny = append(deny, *rule)
+	}
+	if err := s.ensureCallerKeepsAccess(callerIP, allow, deny); err != nil {
+		return nil, err
+	}
+
+	if err := s.ipAccessRepo.AddRule(ctx, rule); err != nil {
+		return nil, errors.New("failed to add ip rule")
+	}
+
+	log.Info().Str("cidr", normalized).Str("list_type", listType).Int64("admin_id", adminID).Msg("[IPAccessService-AddRule] Admin IP rule added")
+	return rule, nil
+}
+
+func (s *IPAccessService) RemoveRule(ctx context.Context, listType, cidr, callerIP string) error {
+	if listType != entity.IPRuleListAllow && listType != entity.IPRuleListDeny {
+		return errors.New("list type must be allow or deny")
+	}
+
+	normalized, err := utils.NormalizeCIDR(cidr)
+	if err != nil {
+		return err
+	}
+
+	allow, deny, err := s.loadRules(ctx)
+	if err != nil {
+		return errors.New("failed to remove ip rule")
+	}
+
+	if listType == entity.IPRuleListAllow {
+		allow = withoutRule(allow, normalized)
+	} else {
+		deny = withoutRule(deny, normalized)
+	}
+	if err := s.ensureCallerKeepsAccess(callerIP, allow, deny); err != nil {
+		return err
+	}
+
+	if err := s.ipAccessRepo.RemoveRule(ctx, listType, normalized); err != nil {
+		if err.Error() == "rule not found" {
+			return errors.New("ip rule not found")
+		}
+		return errors.New("failed to remove ip rule")
+	}
+
+	return nil
+}
+
+// ensureCallerKeepsAccess rejects changes that would block the admin making them
+func (s *IPAccessService) ensureCallerKeepsAccess(callerIP string, allow, deny []entity.IPRuleEntity) error {
+	staticAllow := s.config.AdminAccess.IPAllowlist
+	if utils.IPInCIDRs(callerIP, staticAllow) {
+		return nil
+	}
+
+	if allowed, _ := evaluateIPRules(callerIP, staticAllow, allow, deny); !allowed {
+		log.Warn().Str("caller_ip", callerIP).Msg("[IPAccessService] Rejected rule change that would lock out the caller")
+		return errors.New("this change would block your current IP")
+	}
+	return nil
+}
+
+func (s *IPAccessService) loadRules(ctx context.Context) ([]entity.IPRuleEntity, []entity.IPRuleEntity, error) {
+	allow, err := s.ipAccessRepo.GetRules(ctx, entity.IPRuleListAllow)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	deny, err := s.ipAccessRepo.GetRules(ctx, entity.IPRuleListDeny)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return allow, deny, nil
+}
+
+func evaluateIPRules(ip string, staticAllow []string, allow, deny []entity.IPRuleEntity) (bool, string) {
+	if utils.IPInCIDRs(ip, staticAllow) {
+		return true, ""
+	}
+
+	if utils.IPInCIDRs(ip, ruleCIDRs(deny)) {
+		return false, "ip denied"
+	}
+
+	allowCIDRs := ruleCIDRs(allow)
+	if len(allowCIDRs) == 0 && len(staticAllow) == 0 {
+		return true, ""
+	}
+
+	if utils.IPInCIDRs(ip, allowCIDRs) {
+		return true, ""
+	}
+	return false, "ip not in allowlist"
+}
+
+func ruleCIDRs(rules []entity.IPRuleEntity) []string {
+	cidrs := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		cidrs = append(cidrs, rule.CIDR)
+	}
+	return cidrs
+}
+
+func withoutRule(rules []entity.IPRuleEntity, cidr string) []entity.IPRuleEntity {
+	filtered := make([]entity.IPRuleEntity, 0, len(rules))
+	for _, rule := range rules {
+		if rule.CIDR != cidr {
+			filtered = append(filtered, rule)
+		}
+	}
+	return filtered
+}
+
+func NewIPAccessService(ipAccessRepo port.IPAccessRepositoryInterface, cfg *config.Config) port.IPAccessServiceInterface {
+	return &IPAccessService{
+		ipAccessRepo: ipAccessRepo,
+		config:       cfg,
+	}
+}
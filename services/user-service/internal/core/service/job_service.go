@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+type JobService struct {
+	jobRepo port.JobRepositoryInterface
+}
+
+func (s *JobService) Enqueue(ctx context.Context, jobType string, payload interface{}, runAt time.Time) (*entity.JobEntity, error) {
+	return s.EnqueueUnique(ctx, jobType, "", payload, runAt)
+}
+
+// EnqueueUnique enqueues a job at most once per uniqueKey; an empty key behaves like Enqueue
+func (s *JobService) EnqueueUnique(ctx context.Context, jobType, uniqueKey string, payload interface{}, runAt time.Time) (*entity.JobEntity, error) {
+	jobType = strings.TrimSpace(jobType)
+	if jobType == "" {
+		return nil, errors.New("job type is required")
+	}
+
+	var rawPayload json.RawMessage
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			log.Error().Err(err).Str("type", jobType).Msg("[JobService-EnqueueUnique] Failed to encode payload")
+			return nil, errors.New("invalid job payload")
+		}
+		rawPayload = encoded
+	}
+
+	if runAt.IsZero() {
+		runAt = time.Now()
+	}
+
+	job, err := s.jobRepo.Enqueue(ctx, &entity.JobEntity{
+		Type:        jobType,
+		Payload:     rawPayload,
+		MaxAttempts: entity.DefaultJobMaxAttempts,
+		RunAt:       runAt,
+		UniqueKey:   uniqueKey,
+	})
+	if err != nil {
+		if err.Error() == "job already enqueued" {
+			return nil, err
+		}
+		log.Error().Err(err).Str("type", jobType).Msg("[JobService-EnqueueUnique] Failed to enqueue job")
+		return nil, errors.New("failed to enqueue job")
+	}
+
+	log.Info().Int64("job_id", job.ID).Str("type", jobType).Time("run_at", runAt).Msg("[JobService-EnqueueUnique] Job enqueued")
+	return job, nil
+}
+
+func (s *JobService) GetJobs(ctx context.Context, status, jobType string, page, limit int) ([]entity.JobEntity, *entity.PaginationEntity, error) {
+	page, limit = normalizePage(page, limit)
+
+	jobs, totalCount, err := s.jobRepo.GetJobs(ctx, status, jobType, page, limit)
+	if err != nil {
+		log.Error().Err(err).Str("status", status).Msg("[JobService-GetJobs] Failed to get jobs")
+		return nil, nil, errors.New("failed to retrieve jobs")
+	}
+
+	return jobs, newPagination(page, limit, totalCount), nil
+}
+
+func (s *JobService) RetryJob(ctx context.Context, id int64) error {
+	if err := s.jobRepo.RetryJob(ctx, id); err != nil {
+		switch err.Error() {
+		case "record not found":
+			return errors.New("job not found")
+		case "job is not failed":
+			return err
+		default:
+			log.Error().Err(err).Int64("job_id", id).Msg("[JobService-RetryJob] Failed to retry job")
+			return errors.New("failed to retry job")
+		}
+	}
+
+	return nil
+}
+
+func NewJobService(jobRepo port.JobRepositoryInterface) port.JobServiceInterface {
+	return &JobService{
+		jobRepo: jobRepo,
+	}
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultCommissionRateBps   = 1000 // 10%
+	defaultMinWithdrawalAmount = 50000
+)
+
+type LedgerService struct {
+	ledgerRepo port.LedgerRepositoryInterface
+	vendorRepo port.VendorRepositoryInterface
+	config     *config.Config
+}
+
+func (s *LedgerService) RecordOrderEarning(ctx context.Context, vendorID int64, orderID string, grossAmount, adminID int64) (*entity.LedgerTransactionEntity, error) {
+	orderID = strings.TrimSpace(orderID)
+	if orderID == "" {
+		return nil, errors.New("order id is required")
+	}
+	if grossAmount <= 0 {
+		return nil, errors.New("amount must be greater than 0")
+	}
+
+	vendor, err := s.vendorRepo.GetVendorByID(ctx, vendorID)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("vendor not found")
+		}
+		log.Error().Err(err).Int64("vendor_id", vendorID).Msg("[LedgerService-RecordOrderEarning] Failed to get vendor")
+		return nil, errors.New("failed to record earning")
+	}
+	if vendor.Status != entity.VendorStatusApproved {
+		log.Warn().Int64("vendor_id", vendorID).Str("status", vendor.Status).Msg("[LedgerService-RecordOrderEarning] Vendor is not approved")
+		return nil, errors.New("vendor is not approved")
+	}
+
+	commission := s.commissionFor(grossAmount)
+	transaction := &entity.LedgerTransactionEntity{
+		VendorID:    vendorID,
+		Type:        entity.LedgerTransactionOrderEarning,
+		ReferenceID: orderID,
+		Description: "Earning for order " + orderID,
+		CreatedBy:   adminID,
+		Entries: []entity.LedgerEntryEntity{
+			{VendorID: vendorID, Account: entity.LedgerAccountPlatformCash, Amount: -grossAmount},
+			{VendorID: vendorID, Account: entity.LedgerAccountVendorPayable, Amount: grossAmount - commission},
+		},
+	}
+	if commission > 0 {
+		transaction.Entries = append(transaction.Entries, entity.LedgerEntryEntity{
+			VendorID: vendorID, Account: entity.LedgerAccountPlatformCommission, Amount: commission,
+		})
+	}
+
+	recorded, err := s.ledgerRepo.RecordTransaction(ctx, transaction)
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Str("order_id", orderID).Msg("[LedgerService-RecordOrderEarning] Failed to record earning")
+		if err.Error() == "ledger transaction already recorded" {
+			return nil, errors.New("order earning already recorded")
+		}
+		return nil, errors.New("failed to record earning")
+	}
+
+	log.Info().Int64("vendor_id", vendorID).Str("order_id", orderID).Int64("gross", grossAmount).Int64("commission", commission).Msg("[LedgerService-RecordOrderEarning] Order earning recorded")
+	return recorded, nil
+}
+
+func (s *LedgerService) GetBalance(ctx context.Context, vendorID int64) (*entity.VendorBalanceEntity, error) {
+	balance, err := s.ledgerRepo.GetVendorBalance(ctx, vendorID)
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Msg("[LedgerService-GetBalance] Failed to get balance")
+		return nil, errors.New("failed to retrieve balance")
+	}
+
+	return balance, nil
+}
+
+func (s *LedgerService) GetLedgerEntries(ctx context.Context, vendorID int64, page, limit int) ([]entity.LedgerEntryEntity, *entity.PaginationEntity, error) {
+	page, limit = normalizePage(page, limit)
+
+	entries, totalCount, err := s.ledgerRepo.GetVendorEntries(ctx, vendorID, page, limit)
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Msg("[LedgerService-GetLedgerEntries] Failed to get entries")
+		return nil, nil, errors.New("failed to retrieve ledger entries")
+	}
+
+	return entries, newPagination(page, limit, totalCount), nil
+}
+
+func (s *LedgerService) RequestWithdrawal(ctx context.Context, vendorID int64, withdrawal *entity.WithdrawalEntity) (*entity.WithdrawalEntity, error) {
+	minAmount := s.config.Ledger.MinWithdrawalAmount
+	if minAmount <= 0 {
+		minAmount = defaultMinWithdrawalAmount
+	}
+	if withdrawal.Amount < minAmount {
+		log.Warn().Int64("vendor_id", vendorID).Int64("amount", withdrawal.Amount).Msg("[LedgerService-RequestWithdrawal] Amount below minimum")
+		return nil, errors.New("withdrawal amount is below the minimum")
+	}
+
+	withdrawal.VendorID = vendorID
+	withdrawal.BankName = strings.TrimSpace(withdrawal.BankName)
+	withdrawal.AccountNumber = strings.TrimSpace(withdrawal.AccountNumber)
+	withdrawal.AccountName = strings.TrimSpace(withdrawal.AccountName)
+
+	created, err := s.ledgerRepo.CreateWithdrawal(ctx, withdrawal)
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Int64("amount", withdrawal.Amount).Msg("[LedgerService-RequestWithdrawal] Failed to create withdrawal")
+		if err.Error() == "insufficient balance" {
+			return nil, err
+		}
+		return nil, errors.New("failed to request withdrawal")
+	}
+
+	return created, nil
+}
+
+func (s *LedgerService) GetWithdrawals(ctx context.Context, vendorID int64, status string, page, limit int) ([]entity.WithdrawalEntity, *entity.PaginationEntity, error) {
+	page, limit = normalizePage(page, limit)
+
+	withdrawals, totalCount, err := s.ledgerRepo.GetWithdrawals(ctx, vendorID, status, page, limit)
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendorID).Str("status", status).Msg("[LedgerService-GetWithdrawals] Failed to get withdrawals")
+		return nil, nil, errors.New("failed to retrieve withdrawals")
+	}
+
+	return withdrawals, newPagination(page, limit, totalCount), nil
+}
+
+func (s *LedgerService) ApproveWithdrawal(ctx context.Context, id, adminID int64) error {
+	if err := s.ledgerRepo.ApproveWithdrawal(ctx, id, adminID); err != nil {
+		return s.reviewError(err, "failed to approve withdrawal")
+	}
+
+	log.Info().Int64("withdrawal_id", id).Int64("admin_id", adminID).Msg("[LedgerService-ApproveWithdrawal] Withdrawal approved")
+	return nil
+}
+
+func (s *LedgerService) RejectWithdrawal(ctx context.Context, id, adminID int64, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("rejection reason is required")
+	}
+
+	if err := s.ledgerRepo.RejectWithdrawal(ctx, id, adminID, reason); err != nil {
+		return s.reviewError(err, "failed to reject withdrawal")
+	}
+
+	log.Info().Int64("withdrawal_id", id).Int64("admin_id", adminID).Msg("[LedgerService-RejectWithdrawal] Withdrawal rejected")
+	return nil
+}
+
+func (s *LedgerService) AuditLedger(ctx context.Context) (*entity.LedgerAuditEntity, error) {
+	audit, err := s.ledgerRepo.GetLedgerAudit(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("[LedgerService-AuditLedger] Failed to audit ledger")
+		return nil, errors.New("failed to audit ledger")
+	}
+
+	if audit.NetTotal != 0 || len(audit.UnbalancedTransactions) > 0 {
+		log.Error().Int64("net_total", audit.NetTotal).Ints64("unbalanced_transactions", audit.UnbalancedTransactions).Msg("[LedgerService-AuditLedger] Ledger is out of balance")
+	}
+
+	return audit, nil
+}
+
+// commissionFor rounds the platform cut half-up to the nearest rupiah
+func (s *LedgerService) commissionFor(grossAmount int64) int64 {
+	rateBps := s.config.Ledger.CommissionRateBps
+	if rateBps <= 0 || rateBps > 10000 {
+		rateBps = defaultCommissionRateBps
+	}
+
+	return (grossAmount*rateBps + 5000) / 10000
+}
+
+func (s *LedgerService) reviewError(err error, fallback string) error {
+	switch err.Error() {
+	case "record not found":
+		return errors.New("withdrawal not found")
+	case "withdrawal is not pending":
+		return err
+	default:
+		log.Error().Err(err).Msg("[LedgerService] Failed to review withdrawal")
+		return errors.New(fallback)
+	}
+}
+
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10 // Default limit
+	}
+	return page, limit
+}
+
+func newPagination(page, limit int, totalCount int64) *entity.PaginationEntity {
+	return &entity.PaginationEntity{
+		Page:       page,
+		TotalCount: totalCount,
+		PerPage:    limit,
+		TotalPage:  int((totalCount + int64(limit) - 1) / int64(limit)),
+	}
+}
+
+func NewLedgerService(ledgerRepo port.LedgerRepositoryInterface, vendorRepo port.VendorRepositoryInterface, cfg *config.Config) port.LedgerServiceInterface {
+	return &LedgerService{
+		ledgerRepo: ledgerRepo,
+		vendorRepo: vendorRepo,
+		config:     cfg,
+	}
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+// legalCacheTTL bounds how long other instances keep asking for the previous version
+// after a new one is published
+const legalCacheTTL = 30 * time.Second
+
+var legalVersionPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,31}$`)
+
+type LegalService struct {
+	legalRepo       port.LegalRepositoryInterface
+	auditLogService port.AuditLogServiceInterface
+
+	// The current documents are read on every authenticated request, so they are cached
+	mu        sync.RWMutex
+	current   []entity.LegalDocumentEntity
+	fetchedAt time.Time
+}
+
+func (s *LegalService) PublishDocument(ctx context.Context, doc *entity.LegalDocumentEntity) (*entity.LegalDocumentEntity, error) {
+	doc.Kind = strings.ToLower(strings.TrimSpace(doc.Kind))
+	doc.Version = strings.TrimSpace(doc.Version)
+	doc.URL = strings.TrimSpace(doc.URL)
+
+	if doc.Kind != entity.LegalDocumentTerms && doc.Kind != entity.LegalDocumentPrivacy {
+		return nil, errors.New("legal document kind must be terms or privacy")
+	}
+	if !legalVersionPattern.MatchString(doc.Version) {
+		return nil, errors.New("legal document version must be 1-32 letters, digits, dots, dashes or underscores")
+	}
+	if u, err := url.Parse(doc.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New("legal document url must be an http(s) URL")
+	}
+
+	created, err := s.legalRepo.CreateDocument(ctx, doc)
+	if err != nil {
+		if err.Error() == "legal document version already exists" {
+			return nil, err
+		}
+		return nil, errors.New("failed to publish legal document")
+	}
+
+	s.mu.Lock()
+	s.current, s.fetchedAt = nil, time.Time{}
+	s.mu.Unlock()
+
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{
+		UserID: doc.PublishedBy,
+		Event:  entity.AuditEventLegalPublished,
+		Metadata: map[string]interface{}{
+			"kind":    created.Kind,
+			"version": created.Version,
+		},
+	})
+
+	log.Info().Str("kind", created.Kind).Str("version", created.Version).Int64("published_by", doc.PublishedBy).Msg("[LegalService-PublishDocument] Legal document published")
+	return created, nil
+}
+
+func (s *LegalService) GetDocuments(ctx context.Context, kind string) ([]entity.LegalDocumentEntity, error) {
+	docs, err := s.legalRepo.GetDocuments(ctx, strings.ToLower(strings.TrimSpace(kind)))
+	if err != nil {
+		return nil, errors.New("failed to retrieve legal documents")
+	}
+	return docs, nil
+}
+
+func (s *LegalService) GetCurrentDocuments(ctx context.Context) ([]entity.LegalDocumentEntity, error) {
+	s.mu.RLock()
+	current, fetchedAt := s.current, s.fetchedAt
+	s.mu.RUnlock()
+	if !fetchedAt.IsZero() && time.Since(fetchedAt) < legalCacheTTL {
+		return current, nil
+	}
+
+	docs, err := s.legalRepo.GetCurrentDocuments(ctx)
+	if err != nil {
+		return nil, errors.New("failed to retrieve legal documents")
+	}
+
+	s.mu.Lock()
+	s.current, s.fetchedAt = docs, time.Now()
+	s.mu.Unlock()
+	return docs, nil
+}
+
+func (s *LegalService) ConsentStatus(ctx context.Context, userID int64) (*entity.ConsentStatusEntity, error) {
+	status, err := s.legalRepo.GetUserConsent(ctx, userID)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, ErrUserNotFound
+		}
+		return nil, errors.New("failed to retrieve consent")
+	}
+
+	if err := s.fillCurrent(ctx, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func (s *LegalService) Accept(ctx context.Context, consent *entity.ConsentEntity) error {
+	status := &entity.ConsentStatusEntity{}
+	if err := s.fillCurrent(ctx, status); err != nil {
+		return err
+	}
+	if status.CurrentTerms == nil && status.CurrentPrivacy == nil {
+		return nil
+	}
+	if (status.CurrentTerms != nil && strings.TrimSpace(consent.TermsVersion) != status.CurrentTerms.Version) ||
+		(status.CurrentPrivacy != nil && strings.TrimSpace(consent.PrivacyVersion) != status.CurrentPrivacy.Version) {
+		return errors.New("consent version is outdated")
+	}
+
+	if err := s.save(ctx, consent, status); err != nil {
+		return errors.New("failed to record consent")
+	}
+
+	log.Info().Int64("user_id", consent.UserID).Str("terms_version", consent.TermsVersion).Str("privacy_version", consent.PrivacyVersion).Msg("[LegalService-Accept] Consent recorded")
+	return nil
+}
+
+func (s *LegalService) GetConsentHistory(ctx context.Context, userID int64) ([]entity.UserConsentEntity, error) {
+	history, err := s.legalRepo.GetConsentHistory(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to retrieve consent")
+	}
+	return history, nil
+}
+
+func (s *LegalService) CheckSignupConsent(ctx context.Context, consent *entity.ConsentEntity) error {
+	if consent != nil {
+		return nil
+	}
+
+	docs, err := s.GetCurrentDocuments(ctx)
+	if err != nil {
+		return err
+	}
+	if len(docs) > 0 {
+		return errors.New("terms must be accepted")
+	}
+	return nil
+}
+
+// RecordSignupConsent is best effort: the account exists already, and a user without a
+// recorded consent is simply asked to accept again on the first request
+func (s *LegalService) RecordSignupConsent(ctx context.Context, email string, consent *entity.ConsentEntity) {
+	if consent == nil {
+		return
+	}
+
+	userID, err := s.legalRepo.GetUserIDByEmail(ctx, email)
+	if err != nil {
+		log.Warn().Err(err).Str("email", email).Msg("[LegalService-RecordSignupConsent] Failed to find new user")
+		return
+	}
+	consent.UserID = userID
+
+	status := &entity.ConsentStatusEntity{}
+	if err := s.fillCurrent(ctx, status); err != nil || (status.CurrentTerms == nil && status.CurrentPrivacy == nil) {
+		return
+	}
+	if err := s.save(ctx, consent, status); err != nil {
+		log.Warn().Err(err).Int64("user_id", userID).Msg("[LegalService-RecordSignupConsent] Failed to record consent")
+	}
+}
+
+// save stores the current versions, not whatever the client sent
+func (s *LegalService) save(ctx context.Context, consent *entity.ConsentEntity, status *entity.ConsentStatusEntity) error {
+	consent.TermsVersion, consent.PrivacyVersion = "", ""
+	if status.CurrentTerms != nil {
+		consent.TermsVersion = status.CurrentTerms.Version
+	}
+	if status.CurrentPrivacy != nil {
+		consent.PrivacyVersion = status.CurrentPrivacy.Version
+	}
+	consent.AcceptedAt = time.Now()
+	return s.legalRepo.SaveConsent(ctx, consent)
+}
+
+func (s *LegalService) fillCurrent(ctx context.Context, status *entity.ConsentStatusEntity) error {
+	docs, err := s.GetCurrentDocuments(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range docs {
+		switch docs[i].Kind {
+		case entity.LegalDocumentTerms:
+			status.CurrentTerms = &docs[i]
+		case entity.LegalDocumentPrivacy:
+			status.CurrentPrivacy = &docs[i]
+		}
+	}
+	return nil
+}
+
+func NewLegalService(legalRepo port.LegalRepositoryInterface, auditLogService port.AuditLogServiceInterface) port.LegalServiceInterface {
+	return &LegalService{
+		legalRepo:       legalRepo,
+		auditLogService: auditLogService,
+	}
+}
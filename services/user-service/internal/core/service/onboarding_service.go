@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+type OnboardingService struct {
+	onboardingRepo port.OnboardingRepositoryInterface
+	userRepo       port.UserRepositoryInterface
+	jobService     port.JobServiceInterface
+}
+
+func (s *OnboardingService) GetOnboarding(ctx context.Context, userID int64) (*entity.OnboardingEntity, error) {
+	return s.evaluate(ctx, userID)
+}
+
+func (s *OnboardingService) Dismiss(ctx context.Context, userID int64) error {
+	if err := s.onboardingRepo.Dismiss(ctx, userID); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[OnboardingService-Dismiss] Failed to dismiss onboarding")
+		return errors.New("failed to dismiss onboarding")
+	}
+	return nil
+}
+
+// RecordFirstOrder is called by the order service; it may complete onboarding
+func (s *OnboardingService) RecordFirstOrder(ctx context.Context, userID int64, orderedAt time.Time) error {
+	if userID <= 0 {
+		return errors.New("invalid user id")
+	}
+	if orderedAt.IsZero() {
+		orderedAt = time.Now()
+	}
+
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		if err.Error() == "record not found" {
+			return errors.New("user not found")
+		}
+		return err
+	}
+
+	if err := s.onboardingRepo.MarkFirstOrder(ctx, userID, orderedAt); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[OnboardingService-RecordFirstOrder] Failed to record first order")
+		return errors.New("failed to record first order")
+	}
+
+	_, err := s.evaluate(ctx, userID)
+	return err
+}
+
+// evaluate builds the checklist and, the first time every item is done, queues the completion event
+func (s *OnboardingService) evaluate(ctx context.Context, userID int64) (*entity.OnboardingEntity, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[OnboardingService-evaluate] Failed to get user")
+		if err.Error() == "record not found" {
+			return nil, errors.New("user not found")
+		}
+		return nil, err
+	}
+
+	state, err := s.onboardingRepo.GetState(ctx, userID)
+	if err != nil {
+		return nil, errors.New("failed to get onboarding")
+	}
+
+	onboarding := entity.BuildOnboarding(user, state)
+	if !onboarding.Completed || state.CompletedAt != nil {
+		return onboarding, nil
+	}
+
+	marked, err := s.onboardingRepo.MarkCompleted(ctx, userID)
+	if err != nil {
+		// The checklist is still correct; completion is retried on the next evaluation
+		log.Error().Err(err).Int64("user_id", userID).Msg("[OnboardingService-evaluate] Failed to mark onboarding completed")
+		return onboarding, nil
+	}
+
+	now := time.Now()
+	onboarding.CompletedAt = &now
+	if marked {
+		s.emitCompleted(ctx, user, now)
+	}
+	return onboarding, nil
+}
+
+func (s *OnboardingService) emitCompleted(ctx context.Context, user *entity.UserEntity, completedAt time.Time) {
+	eventID := fmt.Sprintf("%s:%d", entity.EventUserOnboardingCompleted, user.ID)
+	event := entity.EventEntity{
+		ID:         eventID,
+		Type:       entity.EventUserOnboardingCompleted,
+		OccurredAt: completedAt,
+		Data: map[string]interface{}{
+			"user_id": user.ID,
+			"email":   user.Email,
+			"name":    user.Name,
+		},
+	}
+
+	if _, err := s.jobService.EnqueueUnique(ctx, entity.JobTypePublishEvent, eventID, event, time.Time{}); err != nil && err.Error() != "job already enqueued" {
+		log.Error().Err(err).Int64("user_id", user.ID).Msg("[OnboardingService-emitCompleted] Failed to queue onboarding completed event")
+		return
+	}
+
+	log.Info().Int64("user_id", user.ID).Msg("[OnboardingService-emitCompleted] Onboarding completed")
+}
+
+func NewOnboardingService(onboardingRepo port.OnboardingRepositoryInterface, userRepo port.UserRepositoryInterface, jobService port.JobServiceInterface) port.OnboardingServiceInterface {
+	return &OnboardingService{
+		onboardingRepo: onboardingRepo,
+		userRepo:       userRepo,
+		jobService:     jobService,
+	}
+}
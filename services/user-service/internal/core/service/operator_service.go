@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+type OperatorService struct {
+	userRepo              port.UserRepositoryInterface
+	sessionRepo           port.SessionInterface
+	verificationTokenRepo port.VerificationTokenInterface
+	blacklistTokenRepo    port.BlacklistTokenInterface
+	userService           port.UserServiceInterface
+	auditLogService       port.AuditLogServiceInterface
+	emailPolicy           *utils.EmailPolicy
+	// operator is who runs the command, recorded with every audit entry
+	operator string
+}
+
+func (s *OperatorService) PromoteUser(ctx context.Context, email, roleName string) (*entity.UserEntity, error) {
+	user, err := s.findUser(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := s.userRepo.GetRoleByName(ctx, roleName)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("role not found")
+		}
+		return nil, errors.New("failed to get role")
+	}
+
+	if user.RoleName == role.Name {
+		return nil, errors.New("user already has this role")
+	}
+
+	if err := s.userRepo.UpdateUserRole(ctx, user.ID, role.ID); err != nil {
+		return nil, errors.New("failed to update role")
+	}
+
+	// The role is a claim in the user's tokens, so it only changes once they sign in again
+	if err := s.sessionRepo.DeleteAllUserTokens(ctx, user.ID); err != nil {
+		log.Warn().Err(err).Int64("user_id", user.ID).Msg("[OperatorService-PromoteUser] Failed to sign out sessions after the role change")
+	}
+
+	s.audit(ctx, user.ID, entity.AuditEventRoleChanged, map[string]interface{}{
+		"previous_role": user.RoleName,
+		"role":          role.Name,
+	})
+	log.Info().Int64("user_id", user.ID).Str("previous_role", user.RoleName).Str("role", role.Name).Str("operator", s.operator).Msg("[OperatorService-PromoteUser] User role changed")
+
+	user.RoleName = role.Name
+	user.RoleID = role.ID
+	return user, nil
+}
+
+func (s *OperatorService) VerifyUser(ctx context.Context, email string) (*entity.UserEntity, error) {
+	user, err := s.findUser(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsVerified {
+		return nil, errors.New("user is already verified")
+	}
+
+	if err := s.userRepo.UpdateUserVerificationStatus(ctx, user.ID, true); err != nil {
+		return nil, errors.New("failed to verify user")
+	}
+
+	// The link in the verification email has nothing left to do
+	if _, err := s.verificationTokenRepo.DeleteUserTokens(ctx, user.ID, entity.TokenTypeEmailVerification); err != nil {
+		log.Warn().Err(err).Int64("user_id", user.ID).Msg("[OperatorService-VerifyUser] Failed to delete verification tokens")
+	}
+
+	s.audit(ctx, user.ID, entity.AuditEventVerifiedManually, nil)
+	log.Info().Int64("user_id", user.ID).Str("operator", s.operator).Msg("[OperatorService-VerifyUser] User verified manually")
+
+	user.IsVerified = true
+	return user, nil
+}
+
+func (s *OperatorService) RevokeSessions(ctx context.Context, email string) (int, error) {
+	user, err := s.findUser(ctx, email)
+	if err != nil {
+		return 0, err
+	}
+
+	sessions, err := s.sessionRepo.GetUserSessions(ctx, user.ID)
+	if err != nil {
+		return 0, errors.New("failed to get sessions")
+	}
+
+	if err := s.sessionRepo.DeleteAllUserTokens(ctx, user.ID); err != nil {
+		return 0, errors.New("failed to revoke sessions")
+	}
+
+	s.audit(ctx, user.ID, entity.AuditEventSessionsRevoked, map[string]interface{}{"sessions": len(sessions)})
+	log.Info().Int64("user_id", user.ID).Int("sessions", len(sessions)).Str("operator", s.operator).Msg("[OperatorService-RevokeSessions] Sessions revoked")
+	return len(sessions), nil
+}
+
+// ResendVerification says why nothing was sent, unlike the public endpoint, which answers the
+// same either way so it cannot be used to find out who has an account
+func (s *OperatorService) ResendVerification(ctx context.Context, email string) error {
+	user, err := s.findUser(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if user.IsVerified {
+		return errors.New("user is already verified")
+	}
+
+	return s.userService.ResendVerificationEmail(ctx, user.Email)
+}
+
+// PurgeTokens runs what the verification token and blacklist cleanup jobs do, right away
+func (s *OperatorService) PurgeTokens(ctx context.Context) (*entity.TokenPurgeEntity, error) {
+	verificationTokens, err := s.verificationTokenRepo.DeleteExpiredVerificationTokens(ctx)
+	if err != nil {
+		return nil, errors.New("failed to purge verification tokens")
+	}
+
+	blacklistEntries, err := s.blacklistTokenRepo.DeleteExpired(ctx)
+	if err != nil {
+		return nil, errors.New("failed to purge blacklisted tokens")
+	}
+
+	log.Info().Int64("verification_tokens", verificationTokens).Int64("blacklist_entries", blacklistEntries).Str("operator", s.operator).Msg("[OperatorService-PurgeTokens] Expired tokens purged")
+	return &entity.TokenPurgeEntity{
+		ExpiredVerifications: verificationTokens,
+		BlacklistEntries:     blacklistEntries,
+	}, nil
+}
+
+func (s *OperatorService) findUser(ctx context.Context, email string) (*entity.UserEntity, error) {
+	normalized, err := s.emailPolicy.NormalizeEmail(email)
+	if err != nil {
+		return nil, ErrInvalidEmail
+	}
+
+	user, err := s.userRepo.GetUserByEmailIncludingUnverified(ctx, normalized)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("user not found")
+		}
+		return nil, errors.New("failed to get user")
+	}
+	return user, nil
+}
+
+func (s *OperatorService) audit(ctx context.Context, userID int64, event string, metadata map[string]interface{}) {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadata["operator"] = s.operator
+	metadata["source"] = "adminctl"
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{UserID: userID, Event: event, Metadata: metadata})
+}
+
+func NewOperatorService(userRepo port.UserRepositoryInterface, sessionRepo port.SessionInterface, verificationTokenRepo port.VerificationTokenInterface, blacklistTokenRepo port.BlacklistTokenInterface, userService port.UserServiceInterface, auditLogService port.AuditLogServiceInterface, cfg *config.Config, operator string) port.OperatorServiceInterface {
+	return &OperatorService{
+		userRepo:              userRepo,
+		sessionRepo:           sessionRepo,
+		verificationTokenRepo: verificationTokenRepo,
+		blacklistTokenRepo:    blacklistTokenRepo,
+		userService:           userService,
+		auditLogService:       auditLogService,
+		emailPolicy:           EmailPolicyFromConfig(cfg),
+		operator:              operator,
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+// passwordWarningBatch is how many accounts one query of SendExpiryWarnings reads
+const passwordWarningBatch = 200
+
+type PasswordPolicyService struct {
+	userRepo       port.UserRepositoryInterface
+	roleRepo       port.RoleRepositoryInterface
+	sessionRepo    port.SessionInterface
+	emailPublisher port.EmailInterface
+	roles          port.RoleResolverInterface
+	policy         entity.PasswordPolicy
+}
+
+// effectiveRoles is roleName with the roles it inherits from, or roleName alone without a resolver
+func effectiveRoles(ctx context.Context, roles port.RoleResolverInterface, roleName string) []string {
+	if roles == nil {
+		return []string{roleName}
+	}
+	return roles.EffectiveRoles(ctx, roleName)
+}
+
+// PasswordExpired only reads the session for roles the policy covers, and the account only when
+// the session was not signed in through SSO, so other requests cost nothing extra. It fails open
+// when the account cannot be read, the same as EffectiveRoles does with the hierarchy.
+func (s *PasswordPolicyService) PasswordExpired(ctx context.Context, userID int64, sessionID, roleName string) bool {
+	if s.policy.MaxAge <= 0 || !s.policy.AppliesTo(effectiveRoles(ctx, s.roles, roleName)) {
+		return false
+	}
+
+	// Sessions from before the flag existed count as password sessions
+	if sessionID != "" {
+		if session, err := s.sessionRepo.GetSession(ctx, userID, sessionID); err == nil && session.SSO {
+			return false
+		}
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[PasswordPolicyService-PasswordExpired] Failed to get user")
+		return false
+	}
+	return s.policy.Expired(user.PasswordChangedAt, time.Now())
+}
+
+// policyRoles lists the roles the policy names together with every role inheriting from one of
+// them, since the warning query matches role names
+func (s *PasswordPolicyService) policyRoles(ctx context.Context) ([]string, error) {
+	roles, err := s.roleRepo.GetAllRoles(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	names := append([]string{}, s.policy.Roles...)
+	for _, role := range roles {
+		if !s.policy.AppliesTo([]string{role.Name}) && s.policy.AppliesTo(effectiveRoles(ctx, s.roles, role.Name)) {
+			names = append(names, role.Name)
+		}
+	}
+	return names, nil
+}
+
+func (s *PasswordPolicyService) SendExpiryWarnings(ctx context.Context) (int, error) {
+	if s.policy.MaxAge <= 0 || len(s.policy.Roles) == 0 {
+		return 0, nil
+	}
+
+	// Passwords set in this window expire within the warning period but have not expired yet;
+	// expired ones are told at sign-in instead
+	now := time.Now()
+	changedAfter := now.Add(-s.policy.MaxAge)
+	changedBefore := now.Add(s.policy.WarnBefore - s.policy.MaxAge)
+
+	roles, err := s.policyRoles(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("[PasswordPolicyService-SendExpiryWarnings] Failed to list roles")
+		return 0, err
+	}
+
+	sent := 0
+	var afterID int64
+	for {
+		users, err := s.userRepo.GetPasswordsDueForWarning(ctx, roles, changedAfter, changedBefore, afterID, passwordWarningBatch)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, user := range users {
+			afterID = user.UserID
+			// The email shows the expiry on the user's own clock
+			expiresAt := s.policy.ExpiresAt(user.PasswordChangedAt).In(utils.UserLocation(user.Timezone))
+			if err := s.emailPublisher.SendPasswordExpiryWarningEmail(ctx, user.Email, user.Name, expiresAt); err != nil {
+				// Left unmarked, so the next run tries again
+				log.Error().Err(err).Int64("user_id", user.UserID).Msg("[PasswordPolicyService-SendExpiryWarnings] Failed to send warning")
+				continue
+			}
+			if err := s.userRepo.MarkPasswordExpiryWarned(ctx, user.UserID); err != nil {
+				log.Error().Err(err).Int64("user_id", user.UserID).Msg("[PasswordPolicyService-SendExpiryWarnings] Failed to mark warning sent")
+				continue
+			}
+			sent++
+		}
+
+		if len(users) < passwordWarningBatch {
+			break
+		}
+	}
+
+	log.Info().Int("sent", sent).Msg("[PasswordPolicyService-SendExpiryWarnings] Password expiry warnings sent")
+	return sent, nil
+}
+
+func NewPasswordPolicyService(userRepo port.UserRepositoryInterface, roleRepo port.RoleRepositoryInterface, sessionRepo port.SessionInterface, emailPublisher port.EmailInterface, roles port.RoleResolverInterface, cfg *config.Config) port.PasswordPolicyServiceInterface {
+	return &PasswordPolicyService{
+		userRepo:       userRepo,
+		roleRepo:       roleRepo,
+		sessionRepo:    sessionRepo,
+		emailPublisher: emailPublisher,
+		roles:          roles,
+		policy:         *PasswordPolicyFromConfig(cfg),
+	}
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+type PhotoModerationService struct {
+	moderationRepo  port.PhotoModerationRepositoryInterface
+	jobService      port.JobServiceInterface
+	moderator       port.ImageModeratorInterface
+	userRepo        port.UserRepositoryInterface
+	storage         port.StorageInterface
+	emailPublisher  port.EmailInterface
+	flagThreshold   float64
+	removeThreshold float64
+}
+
+func (s *PhotoModerationService) Submit(ctx context.Context, userID int64, imageURL string) error {
+	moderation, err := s.moderationRepo.Create(ctx, &entity.PhotoModerationEntity{
+		UserID:   userID,
+		ImageURL: imageURL,
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[PhotoModerationService-Submit] Failed to create photo moderation")
+		return errors.New("failed to submit photo for moderation")
+	}
+
+	// Without a job the photo stays pending, where it still shows up in the admin queue
+	if _, err := s.jobService.Enqueue(ctx, entity.JobTypeModeratePhoto, entity.PhotoModerationJobPayload{ModerationID: moderation.ID}, time.Time{}); err != nil {
+		log.Error().Err(err).Int64("moderation_id", moderation.ID).Msg("[PhotoModerationService-Submit] Failed to queue photo moderation")
+		return errors.New("failed to submit photo for moderation")
+	}
+
+	return nil
+}
+
+func (s *PhotoModerationService) Moderate(ctx context.Context, moderationID int64) error {
+	moderation, err := s.moderationRepo.GetByID(ctx, moderationID)
+	if err != nil {
+		if err.Error() == "record not found" {
+			log.Warn().Int64("moderation_id", moderationID).Msg("[PhotoModerationService-Moderate] Photo moderation no longer exists")
+			return nil
+		}
+		return err
+	}
+	if moderation.Status != entity.PhotoModerationPending {
+		return nil
+	}
+	if s.moderator == nil {
+		return errors.New("photo moderation provider is not configured")
+	}
+
+	// A replaced photo has already been deleted from storage, so there is nothing left to check
+	if current, err := s.isCurrentPhoto(ctx, moderation); err != nil {
+		return err
+	} else if !current {
+		return s.decide(ctx, moderation, &entity.PhotoModerationEntity{Status: entity.PhotoModerationSuperseded}, entity.PhotoModerationPending)
+	}
+
+	result, err := s.moderator.Moderate(ctx, moderation.ImageURL)
+	if err != nil {
+		log.Error().Err(err).Int64("moderation_id", moderationID).Msg("[PhotoModerationService-Moderate] Moderation provider failed")
+		return err
+	}
+
+	update := &entity.PhotoModerationEntity{
+		Status: entity.PhotoModerationApproved,
+		Score:  &result.Score,
+		Labels: result.Labels,
+	}
+	switch {
+	case result.Score >= s.removeThreshold:
+		update.Status = entity.PhotoModerationRemoved
+	case result.Score >= s.flagThreshold:
+		update.Status = entity.PhotoModerationFlagged
+	}
+
+	if err := s.decide(ctx, moderation, update, entity.PhotoModerationPending); err != nil {
+		return err
+	}
+	if update.Status == entity.PhotoModerationRemoved {
+		// No reason, so the email falls back to the community guidelines one in the user's language
+		s.removePhoto(ctx, moderation, "")
+	}
+
+	log.Info().Int64("moderation_id", moderationID).Int64("user_id", moderation.UserID).Float64("score", result.Score).Str("status", update.Status).Msg("[PhotoModerationService-Moderate] Photo moderated")
+	return nil
+}
+
+func (s *PhotoModerationService) GetModerations(ctx context.Context, status string, page, limit int) ([]entity.PhotoModerationEntity, *entity.PaginationEntity, error) {
+	page, limit = normalizePage(page, limit)
+
+	moderations, totalCount, err := s.moderationRepo.List(ctx, status, page, limit)
+	if err != nil {
+		return nil, nil, errors.New("failed to retrieve photo moderations")
+	}
+
+	return moderations, newPagination(page, limit, totalCount), nil
+}
+
+func (s *PhotoModerationService) GetModerationByID(ctx context.Context, id int64) (*entity.PhotoModerationEntity, error) {
+	moderation, err := s.moderationRepo.GetByID(ctx, id)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("photo moderation not found")
+		}
+		return nil, errors.New("failed to retrieve photo moderation")
+	}
+	return moderation, nil
+}
+
+// Approve keeps a photo the provider flagged, or one still waiting for the provider
+func (s *PhotoModerationService) Approve(ctx context.Context, id, adminID int64, note string) (*entity.PhotoModerationEntity, error) {
+	moderation, err := s.GetModerationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := &entity.PhotoModerationEntity{
+		Status:     entity.PhotoModerationApproved,
+		ReviewedBy: adminID,
+		ReviewNote: strings.TrimSpace(note),
+	}
+	if err := s.decide(ctx, moderation, update, entity.PhotoModerationPending, entity.PhotoModerationFlagged); err != nil {
+		return nil, err
+	}
+
+	log.Info().Int64("moderation_id", id).Int64("admin_id", adminID).Msg("[PhotoModerationService-Approve] Photo approved")
+	return s.GetModerationByID(ctx, id)
+}
+
+// Remove takes a photo down; approved photos can still be removed after a user report
+func (s *PhotoModerationService) Remove(ctx context.Context, id, adminID int64, reason string) (*entity.PhotoModerationEntity, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("removal reason is required")
+	}
+
+	moderation, err := s.GetModerationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	update := &entity.PhotoModerationEntity{
+		Status:     entity.PhotoModerationRemoved,
+		ReviewedBy: adminID,
+		ReviewNote: reason,
+	}
+	if err := s.decide(ctx, moderation, update, entity.PhotoModerationPending, entity.PhotoModerationFlagged, entity.PhotoModerationApproved); err != nil {
+		return nil, err
+	}
+	s.removePhoto(ctx, moderation, reason)
+
+	log.Info().Int64("moderation_id", id).Int64("admin_id", adminID).Msg("[PhotoModerationService-Remove] Photo removed")
+	return s.GetModerationByID(ctx, id)
+}
+
+func (s *PhotoModerationService) decide(ctx context.Context, moderation *entity.PhotoModerationEntity, update *entity.PhotoModerationEntity, fromStatuses ...string) error {
+	if err := s.moderationRepo.UpdateStatus(ctx, moderation.ID, fromStatuses, update); err != nil {
+		if err.Error() == "photo moderation already decided" {
+			return err
+		}
+		return errors.New("failed to update photo moderation")
+	}
+	return nil
+}
+
+func (s *PhotoModerationService) isCurrentPhoto(ctx context.Context, moderation *entity.PhotoModerationEntity) (bool, error) {
+	user, err := s.userRepo.GetUserByID(ctx, moderation.UserID)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return false, nil
+		}
+		log.Error().Err(err).Int64("user_id", moderation.UserID).Msg("[PhotoModerationService-isCurrentPhoto] Failed to get user")
+		return false, err
+	}
+	return user.Photo == moderation.ImageURL, nil
+}
+
+// removePhoto clears the photo from the profile when it is still in use, deletes it and tells
+// the user why. The decision is already recorded, so failures here are only logged.
+func (s *PhotoModerationService) removePhoto(ctx context.Context, moderation *entity.PhotoModerationEntity, reason string) {
+	user, err := s.userRepo.GetUserByID(ctx, moderation.UserID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", moderation.UserID).Msg("[PhotoModerationService-removePhoto] Failed to get user")
+		return
+	}
+
+	// The original the photo was cropped from goes with it
+	removed := []string{moderation.ImageURL}
+	if user.Photo == moderation.ImageURL {
+		if err := s.userRepo.UpdateUserPhoto(ctx, user.ID, ""); err != nil {
+			log.Error().Err(err).Int64("user_id", user.ID).Msg("[PhotoModerationService-removePhoto] Failed to clear profile photo")
+			return
+		}
+		if user.PhotoOriginal != "" {
+			removed = append(removed, user.PhotoOriginal)
+		}
+	}
+
+	for _, imageURL := range removed {
+		if objectName := objectNameFromURL(imageURL); objectName != "" && s.storage != nil {
+			if err := s.storage.DeleteFile(ctx, "", objectName); err != nil {
+				log.Warn().Err(err).Str("image_url", imageURL).Msg("[PhotoModerationService-removePhoto] Failed to delete photo from storage")
+			}
+		}
+	}
+
+	if s.emailPublisher == nil {
+		return
+	}
+	if err := s.emailPublisher.SendPhotoRemovedEmail(ctx, user.Email, user.Name, reason); err != nil {
+		log.Error().Err(err).Int64("user_id", user.ID).Msg("[PhotoModerationService-removePhoto] Failed to send photo removed email")
+	}
+}
+
+func NewPhotoModerationService(moderationRepo port.PhotoModerationRepositoryInterface, jobService port.JobServiceInterface, moderator port.ImageModeratorInterface, userRepo port.UserRepositoryInterface, storage port.StorageInterface, emailPublisher port.EmailInterface, cfg *config.Config) port.PhotoModerationServiceInterface {
+	flagThreshold, removeThreshold := cfg.Moderation.Thresholds()
+
+	return &PhotoModerationService{
+		moderationRepo:  moderationRepo,
+		jobService:      jobService,
+		moderator:       moderator,
+		userRepo:        userRepo,
+		storage:         storage,
+		emailPublisher:  emailPublisher,
+		flagThreshold:   flagThreshold,
+		removeThreshold: removeThreshold,
+	}
+}
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// Risk weights per signal; the sum is compared against RISK_STEP_UP_THRESHOLD
+const (
+	riskWeightNewDevice        = 30
+	riskWeightFailedAttempts   = 20
+	riskWeightManyFailures     = 40
+	riskWeightImpossibleTravel = 50
+
+	riskFailedAttemptsLow  = 3
+	riskFailedAttemptsHigh = 6
+
+	// IP geolocation is coarse, so short hops are never treated as travel
+	riskMinTravelDistanceKm = 100
+)
+
+const (
+	defaultStepUpThreshold       = 50
+	defaultFailedSignInWindowMin = 15
+	defaultMaxTravelSpeedKmh     = 900
+	defaultOTPTTLMinutes         = 10
+	defaultOTPMaxAttempts        = 5
+)
+
+type RiskService struct {
+	riskRepo        port.RiskRepositoryInterface
+	deviceRepo      port.DeviceRepositoryInterface
+	auditLogService port.AuditLogServiceInterface
+	ipLocator       port.IPLocatorInterface
+	emailPublisher  port.EmailInterface
+	config          config.Risk
+}
+
+func (s *RiskService) Assess(ctx context.Context, user *entity.UserEntity, client entity.ClientEntity) *entity.RiskAssessmentEntity {
+	assessment := &entity.RiskAssessmentEntity{}
+	addSignal := func(signal string, weight int) {
+		assessment.Signals = append(assessment.Signals, signal)
+		assessment.Score += weight
+	}
+
+	if s.isNewDevice(ctx, user.ID, client) {
+		addSignal(entity.RiskSignalNewDevice, riskWeightNewDevice)
+	}
+
+	failures, err := s.riskRepo.GetFailedSignIns(ctx, user.Email)
+	if err != nil {
+		log.Warn().Err(err).Int64("user_id", user.ID).Msg("[RiskService-Assess] Failed to get failed sign-ins")
+	}
+	switch {
+	case failures >= riskFailedAttemptsHigh:
+		addSignal(entity.RiskSignalFailedAttempts, riskWeightManyFailures)
+	case failures >= riskFailedAttemptsLow:
+		addSignal(entity.RiskSignalFailedAttempts, riskWeightFailedAttempts)
+	}
+
+	metadata := map[string]interface{}{}
+	if location := s.locate(ctx, client.IPAddress); location != nil {
+		assessment.Location = location
+
+		last, err := s.riskRepo.GetLastLocation(ctx, user.ID)
+		if err != nil {
+			log.Warn().Err(err).Int64("user_id", user.ID).Msg("[RiskService-Assess] Failed to get last location")
+		}
+		if last != nil {
+			if distance, impossible := s.isImpossibleTravel(*last, *location); impossible {
+				addSignal(entity.RiskSignalImpossibleTravel, riskWeightImpossibleTravel)
+				metadata["distance_km"] = int(distance)
+			}
+		}
+	}
+
+	assessment.RequiresStepUp = s.config.StepUpEnabled && assessment.Score >= s.config.StepUpThreshold
+
+	if assessment.Score > 0 {
+		metadata["score"] = assessment.Score
+		metadata["signals"] = assessment.Signals
+		metadata["failed_attempts"] = failures
+		metadata["requires_step_up"] = assessment.RequiresStepUp
+		s.audit(ctx, user.ID, entity.AuditEventSignInRisk, client, metadata)
+
+		log.Info().Int64("user_id", user.ID).Int("score", assessment.Score).Strs("signals", assessment.Signals).Msg("[RiskService-Assess] Risky sign-in detected")
+	}
+
+	return assessment
+}
+
+func (s *RiskService) RecordFailedSignIn(ctx context.Context, email string, userID int64, client entity.ClientEntity) {
+	window := time.Duration(s.config.FailedSignInWindowMin) * time.Minute
+
+	failures, err := s.riskRepo.IncrementFailedSignIns(ctx, email, window)
+	if err != nil {
+		log.Warn().Err(err).Int64("user_id", userID).Msg("[RiskService-RecordFailedSignIn] Failed to count failed sign-in")
+	}
+
+	s.audit(ctx, userID, entity.AuditEventSignInFailed, client, map[string]interface{}{
+		"failed_attempts": failures,
+	})
+}
+
+func (s *RiskService) RecordSuccessfulSignIn(ctx context.Context, user *entity.UserEntity, location *entity.LoginLocationEntity) {
+	if err := s.riskRepo.ResetFailedSignIns(ctx, user.Email); err != nil {
+		log.Warn().Err(err).Int64("user_id", user.ID).Msg("[RiskService-RecordSuccessfulSignIn] Failed to reset failed sign-ins")
+	}
+
+	if location != nil {
+		if err := s.riskRepo.SetLastLocation(ctx, user.ID, *location); err != nil {
+			log.Warn().Err(err).Int64("user_id", user.ID).Msg("[RiskService-RecordSuccessfulSignIn] Failed to store location")
+		}
+	}
+}
+
+func (s *RiskService) StartStepUp(ctx context.Context, user *entity.UserEntity, client entity.ClientEntity, assessment *entity.RiskAssessmentEntity, rememberMe bool) (*entity.StepUpChallengeEntity, error) {
+	if s.emailPublisher == nil {
+		log.Error().Int64("user_id", user.ID).Msg("[RiskService-StartStepUp] Email publisher not available")
+		return nil, errors.New("failed to send verification code")
+	}
+
+	code, err := generateOTP()
+	if err != nil {
+		log.Error().Err(err).Msg("[RiskService-StartStepUp] Failed to generate code")
+		return nil, errors.New("failed to send verification code")
+	}
+
+	challenge := &entity.StepUpChallengeEntity{
+		ID:         uuid.New().String(),
+		UserID:     user.ID,
+		CodeHash:   hashOTP(code),
+		Score:      assessment.Score,
+		Signals:    assessment.Signals,
+		Location:   assessment.Location,
+		RememberMe: rememberMe,
+		ExpiresAt:  time.Now().Add(time.Duration(s.config.OTPTTLMinutes) * time.Minute),
+	}
+
+	if err := s.riskRepo.SaveChallenge(ctx, challenge); err != nil {
+		log.Error().Err(err).Int64("user_id", user.ID).Msg("[RiskService-StartStepUp] Failed to save challenge")
+		return nil, errors.New("failed to send verification code")
+	}
+
+	if err := s.emailPublisher.SendSignInOTPEmail(ctx, user.Email, code, challenge.ExpiresAt); err != nil {
+		log.Error().Err(err).Int64("user_id", user.ID).Msg("[RiskService-StartStepUp] Failed to send code")
+		s.riskRepo.DeleteChallenge(ctx, challenge.ID)
+		return nil, errors.New("failed to send verification code")
+	}
+
+	s.audit(ctx, user.ID, entity.AuditEventStepUpRequired, client, map[string]interface{}{
+		"score":   assessment.Score,
+		"signals": assessment.Signals,
+	})
+
+	log.Info().Int64("user_id", user.ID).Int("score", assessment.Score).Msg("[RiskService-StartStepUp] Step-up verification required")
+	return challenge, nil
+}
+
+func (s *RiskService) VerifyStepUp(ctx context.Context, challengeID, code string, client entity.ClientEntity) (*entity.StepUpChallengeEntity, error) {
+	challenge, err := s.riskRepo.GetChallenge(ctx, challengeID)
+	if err != nil {
+		if err.Error() == "challenge not found" {
+			return nil, errors.New("verification challenge not found")
+		}
+		log.Error().Err(err).Msg("[RiskService-VerifyStepUp] Failed to get challenge")
+		return nil, errors.New("failed to verify code")
+	}
+
+	if challenge.Attempts >= s.config.OTPMaxAttempts {
+		s.riskRepo.DeleteChallenge(ctx, challengeID)
+		return nil, errors.New("too many verification attempts")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashOTP(code)), []byte(challenge.CodeHash)) != 1 {
+		attempts, err := s.riskRepo.IncrementChallengeAttempts(ctx, challengeID)
+		if err != nil {
+			log.Error().Err(err).Msg("[RiskService-VerifyStepUp] Failed to count attempt")
+			return nil, errors.New("failed to verify code")
+		}
+
+		if attempts >= s.config.OTPMaxAttempts {
+			s.riskRepo.DeleteChallenge(ctx, challengeID)
+			s.audit(ctx, challenge.UserID, entity.AuditEventStepUpLockedOut, client, map[string]interface{}{"attempts": attempts})
+			log.Warn().Int64("user_id", challenge.UserID).Msg("[RiskService-VerifyStepUp] Too many verification attempts")
+			return nil, errors.New("too many verification attempts")
+		}
+
+		s.audit(ctx, challenge.UserID, entity.AuditEventStepUpFailed, client, map[string]interface{}{"attempts": attempts})
+		return nil, errors.New("invalid verification code")
+	}
+
+	// Single use: a leaked code cannot be replayed once the session is issued
+	if err := s.riskRepo.DeleteChallenge(ctx, challengeID); err != nil {
+		log.Error().Err(err).Msg("[RiskService-VerifyStepUp] Failed to delete challenge")
+		return nil, errors.New("failed to verify code")
+	}
+
+	s.audit(ctx, challenge.UserID, entity.AuditEventStepUpVerified, client, map[string]interface{}{
+		"score":   challenge.Score,
+		"signals": challenge.Signals,
+	})
+
+	log.Info().Int64("user_id", challenge.UserID).Msg("[RiskService-VerifyStepUp] Step-up verification passed")
+	return challenge, nil
+}
+
+func (s *RiskService) isNewDevice(ctx context.Context, userID int64, client entity.ClientEntity) bool {
+	if s.deviceRepo == nil {
+		return false
+	}
+
+	_, err := s.deviceRepo.FindDevice(ctx, userID, client.Fingerprint())
+	if err == nil {
+		return false
+	}
+	if err.Error() != "record not found" {
+		log.Warn().Err(err).Int64("user_id", userID).Msg("[RiskService-isNewDevice] Failed to find device")
+		return false
+	}
+
+	// A brand new account has no devices yet, which is not suspicious
+	count, err := s.deviceRepo.CountDevices(ctx, userID)
+	if err != nil {
+		log.Warn().Err(err).Int64("user_id", userID).Msg("[RiskService-isNewDevice] Failed to count devices")
+		return false
+	}
+	return count > 0
+}
+
+func (s *RiskService) locate(ctx context.Context, ip string) *entity.LoginLocationEntity {
+	if s.ipLocator == nil || ip == "" {
+		return nil
+	}
+
+	location, err := s.ipLocator.Locate(ctx, ip)
+	if err != nil {
+		log.Debug().Err(err).Str("ip", ip).Msg("[RiskService-locate] Could not locate IP")
+		return nil
+	}
+
+	return location
+}
+
+func (s *RiskService) isImpossibleTravel(last, current entity.LoginLocationEntity) (float64, bool) {
+	distance := utils.HaversineKm(last.Lat, last.Lng, current.Lat, current.Lng)
+	if distance < riskMinTravelDistanceKm {
+		return distance, false
+	}
+
+	hours := current.At.Sub(last.At).Hours()
+	if hours <= 0 {
+		return distance, true
+	}
+	return distance, distance/hours > float64(s.config.MaxTravelSpeedKmh)
+}
+
+func (s *RiskService) audit(ctx context.Context, userID int64, event string, client entity.ClientEntity, metadata map[string]interface{}) {
+	if s.auditLogService == nil {
+		return
+	}
+
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{
+		UserID:    userID,
+		Event:     event,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Metadata:  metadata,
+	})
+}
+
+// generateOTP returns a uniformly random 6-digit code
+func generateOTP() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+func hashOTP(code string) string {
+	hash := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(hash[:])
+}
+
+func NewRiskService(riskRepo port.RiskRepositoryInterface, deviceRepo port.DeviceRepositoryInterface, auditLogService port.AuditLogServiceInterface, ipLocator port.IPLocatorInterface, emailPublisher port.EmailInterface, cfg *config.Config) port.RiskServiceInterface {
+	riskConfig := cfg.Risk
+	if riskConfig.StepUpThreshold <= 0 {
+		riskConfig.StepUpThreshold = defaultStepUpThreshold
+	}
+	if riskConfig.FailedSignInWindowMin <= 0 {
+		riskConfig.FailedSignInWindowMin = defaultFailedSignInWindowMin
+	}
+	if riskConfig.MaxTravelSpeedKmh <= 0 {
+		riskConfig.MaxTravelSpeedKmh = defaultMaxTravelSpeedKmh
+	}
+	if riskConfig.OTPTTLMinutes <= 0 {
+		riskConfig.OTPTTLMinutes = defaultOTPTTLMinutes
+	}
+	if riskConfig.OTPMaxAttempts <= 0 {
+		riskConfig.OTPMaxAttempts = defaultOTPMaxAttempts
+	}
+
+	return &RiskService{
+		riskRepo:        riskRepo,
+		deviceRepo:      deviceRepo,
+		auditLogService: auditLogService,
+		ipLocator:       ipLocator,
+		emailPublisher:  emailPublisher,
+		config:          riskConfig,
+	}
+}
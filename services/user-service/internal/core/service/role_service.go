@@ -2,27 +2,40 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 	"user-service/internal/core/domain/entity"
 	"user-service/internal/core/port"
 
 	"github.com/rs/zerolog/log"
 )
 
+// roleHierarchyTTL bounds how long a parent change made on another instance takes to apply here
+const roleHierarchyTTL = 30 * time.Second
+
 type RoleService struct {
 	roleRepo port.RoleRepositoryInterface
+
+	// hierarchy maps a role name to its parent's name; it is read on every authenticated request
+	mu                sync.Mutex
+	hierarchy         map[string]string
+	hierarchyLoadedAt time.Time
 }
 
-func (s *RoleService) GetAllRoles(ctx context.Context, search string) ([]entity.RoleEntity, error) {
-	roles, err := s.roleRepo.GetAllRoles(ctx, search)
+func (s *RoleService) GetAllRoles(ctx context.Context, search string, page, limit int) ([]entity.RoleEntity, *entity.PaginationEntity, error) {
+	page, limit = normalizePage(page, limit)
+
+	roles, totalCount, err := s.roleRepo.GetRoles(ctx, search, page, limit)
 	if err != nil {
 		log.Error().Err(err).Str("search", search).Msg("[RoleService-GetAllRoles] Failed to get roles")
-		return nil, err
+		return nil, nil, err
 	}
 
-	log.Info().Int("count", len(roles)).Str("search", search).Msg("[RoleService-GetAllRoles] Roles retrieved successfully")
-	return roles, nil
+	log.Info().Int("count", len(roles)).Int64("total_count", totalCount).Str("search", search).Msg("[RoleService-GetAllRoles] Roles retrieved successfully")
+	return roles, newPagination(page, limit, totalCount), nil
 }
 
 func (s *RoleService) GetRoleByID(ctx context.Context, id int64) (*entity.RoleEntity, error) {
@@ -82,11 +95,13 @@ func (s *RoleService) CreateRole(ctx context.Context, name string) (*entity.Role
 		return nil, err
 	}
 
+	s.invalidateHierarchy()
 	log.Info().Int64("role_id", createdRole.ID).Str("role_name", createdRole.Name).Msg("[RoleService-CreateRole] Role created successfully")
 	return createdRole, nil
 }
 
-func (s *RoleService) UpdateRole(ctx context.Context, id int64, name string) (*entity.RoleEntity, error) {
+// UpdateRole renames the role only if it is still at the given version
+func (s *RoleService) UpdateRole(ctx context.Context, id int64, name string, version int64) (*entity.RoleEntity, error) {
 	// Validate input
 	if name == "" {
 		log.Warn().Int64("role_id", id).Msg("[RoleService-UpdateRole] Role name cannot be empty")
@@ -116,6 +131,11 @@ func (s *RoleService) UpdateRole(ctx context.Context, id int64, name string) (*e
 		return nil, err
 	}
 
+	if version > 0 && existingRole.Version != version {
+		log.Warn().Int64("role_id", id).Int64("version", version).Int64("current_version", existingRole.Version).Msg("[RoleService-UpdateRole] Stale role version")
+		return nil, &entity.VersionConflictError{CurrentVersion: existingRole.Version}
+	}
+
 	// Check if another role with the same name already exists (excluding current role)
 	allRoles, err := s.roleRepo.GetAllRoles(ctx, "")
 	if err != nil {
@@ -132,7 +152,8 @@ func (s *RoleService) UpdateRole(ctx context.Context, id int64, name string) (*e
 
 	// Create updated role entity
 	updatedRoleEntity := &entity.RoleEntity{
-		Name: name,
+		Name:    name,
+		Version: version,
 	}
 
 	// Update role in repository
@@ -142,6 +163,7 @@ func (s *RoleService) UpdateRole(ctx context.Context, id int64, name string) (*e
 		return nil, err
 	}
 
+	s.invalidateHierarchy()
 	log.Info().Int64("role_id", id).Str("old_name", existingRole.Name).Str("new_name", updatedRole.Name).Msg("[RoleService-UpdateRole] Role updated successfully")
 	return updatedRole, nil
 }
@@ -170,10 +192,146 @@ func (s *RoleService) DeleteRole(ctx context.Context, id int64) error {
 		return err
 	}
 
+	s.invalidateHierarchy()
 	log.Info().Int64("role_id", id).Str("role_name", role.Name).Msg("[RoleService-DeleteRole] Role deleted successfully")
 	return nil
 }
 
+// SetRoleParent refuses a parent that already inherits from the role, directly or through its
+// ancestors, since resolving that role would never end
+func (s *RoleService) SetRoleParent(ctx context.Context, id int64, parentID *int64, version int64) (*entity.RoleEntity, error) {
+	existingRole, err := s.roleRepo.GetRoleByID(ctx, id)
+	if err != nil {
+		log.Error().Err(err).Int64("role_id", id).Msg("[RoleService-SetRoleParent] Failed to get existing role")
+		if err.Error() == "record not found" {
+			return nil, fmt.Errorf("role not found")
+		}
+		return nil, err
+	}
+
+	if version > 0 && existingRole.Version != version {
+		log.Warn().Int64("role_id", id).Int64("version", version).Int64("current_version", existingRole.Version).Msg("[RoleService-SetRoleParent] Stale role version")
+		return nil, &entity.VersionConflictError{CurrentVersion: existingRole.Version}
+	}
+
+	if parentID != nil {
+		if *parentID == id {
+			log.Warn().Int64("role_id", id).Msg("[RoleService-SetRoleParent] Role cannot inherit from itself")
+			return nil, errors.New("role cannot inherit from itself")
+		}
+
+		roles, err := s.roleRepo.GetAllRoles(ctx, "")
+		if err != nil {
+			log.Error().Err(err).Int64("role_id", id).Msg("[RoleService-SetRoleParent] Failed to load roles")
+			return nil, err
+		}
+		if err := checkRoleParent(roles, id, *parentID); err != nil {
+			log.Warn().Err(err).Int64("role_id", id).Int64("parent_id", *parentID).Msg("[RoleService-SetRoleParent] Invalid parent role")
+			return nil, err
+		}
+	}
+
+	updatedRole, err := s.roleRepo.UpdateRoleParent(ctx, id, parentID, version)
+	if err != nil {
+		log.Error().Err(err).Int64("role_id", id).Msg("[RoleService-SetRoleParent] Failed to update role parent")
+		if err.Error() == "record not found" {
+			return nil, fmt.Errorf("role not found")
+		}
+		return nil, err
+	}
+
+	s.invalidateHierarchy()
+	log.Info().Int64("role_id", id).Interface("parent_id", parentID).Msg("[RoleService-SetRoleParent] Role parent updated successfully")
+	return updatedRole, nil
+}
+
+// EffectiveRoles falls back to the role alone when the hierarchy cannot be loaded, so a database
+// outage never grants more than the token's own role
+func (s *RoleService) EffectiveRoles(ctx context.Context, roleName string) []string {
+	hierarchy, err := s.loadHierarchy(ctx)
+	if err != nil {
+		log.Error().Err(err).Str("role_name", roleName).Msg("[RoleService-EffectiveRoles] Failed to load role hierarchy")
+		return []string{roleName}
+	}
+
+	roles := []string{roleName}
+	seen := map[string]bool{roleName: true}
+	for parent, ok := hierarchy[roleName]; ok && !seen[parent]; parent, ok = hierarchy[parent] {
+		// seen guards against a cycle written concurrently by two parent changes
+		seen[parent] = true
+		roles = append(roles, parent)
+	}
+	return roles
+}
+
+func (s *RoleService) loadHierarchy(ctx context.Context) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.hierarchy != nil && time.Since(s.hierarchyLoadedAt) < roleHierarchyTTL {
+		return s.hierarchy, nil
+	}
+
+	roles, err := s.roleRepo.GetAllRoles(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[int64]string, len(roles))
+	for _, role := range roles {
+		names[role.ID] = role.Name
+	}
+	hierarchy := make(map[string]string, len(roles))
+	for _, role := range roles {
+		// A deleted parent is not listed, so its children stop inheriting from it
+		if role.ParentID == nil {
+			continue
+		}
+		if parent, ok := names[*role.ParentID]; ok {
+			hierarchy[role.Name] = parent
+		}
+	}
+
+	s.hierarchy, s.hierarchyLoadedAt = hierarchy, time.Now()
+	return hierarchy, nil
+}
+
+func (s *RoleService) invalidateHierarchy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.hierarchy = nil
+}
+
+// checkRoleParent walks up from parentID and fails if it reaches id
+func checkRoleParent(roles []entity.RoleEntity, id, parentID int64) error {
+	byID := make(map[int64]entity.RoleEntity, len(roles))
+	for _, role := range roles {
+		byID[role.ID] = role
+	}
+
+	if _, ok := byID[parentID]; !ok {
+		return errors.New("parent role not found")
+	}
+
+	seen := map[int64]bool{}
+	for current := &parentID; current != nil; {
+		if *current == id {
+			return errors.New("role hierarchy cannot contain a cycle")
+		}
+		if seen[*current] {
+			break
+		}
+		seen[*current] = true
+
+		role, ok := byID[*current]
+		if !ok {
+			break
+		}
+		current = role.ParentID
+	}
+	return nil
+}
+
 func NewRoleService(roleRepo port.RoleRepositoryInterface) port.RoleServiceInterface {
 	return &RoleService{
 		roleRepo: roleRepo,
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// savedViewFilters lists the query parameters each list takes. Shared views are replayed by
+// other admins, so values are checked here rather than trusted to the list endpoint.
+var savedViewFilters = map[string]map[string]func(string) error{
+	entity.SavedViewListCustomers: {
+		"search":  validateViewSearch,
+		"orderBy": validateViewCustomerOrder,
+		"limit":   validateViewLimit,
+	},
+}
+
+var customerOrderPattern = regexp.MustCompile(`(?i)^(users\.)?(name|email|username|created_at|updated_at)( (asc|desc))?$`)
+
+type SavedViewService struct {
+	viewRepo port.SavedViewRepositoryInterface
+}
+
+func (s *SavedViewService) GetViews(ctx context.Context, adminID int64, list string) ([]entity.SavedViewEntity, error) {
+	if list != "" {
+		if _, ok := savedViewFilters[list]; !ok {
+			return nil, unknownViewListError()
+		}
+	}
+
+	views, err := s.viewRepo.GetViews(ctx, adminID, list)
+	if err != nil {
+		log.Error().Err(err).Int64("admin_id", adminID).Str("list", list).Msg("[SavedViewService-GetViews] Failed to get saved views")
+		return nil, err
+	}
+
+	return views, nil
+}
+
+func (s *SavedViewService) CreateView(ctx context.Context, view *entity.SavedViewEntity) (*entity.SavedViewEntity, error) {
+	if err := validateSavedView(view); err != nil {
+		log.Warn().Err(err).Int64("admin_id", view.OwnerID).Msg("[SavedViewService-CreateView] Invalid saved view")
+		return nil, err
+	}
+
+	createdView, err := s.viewRepo.CreateView(ctx, view)
+	if err != nil {
+		log.Error().Err(err).Int64("admin_id", view.OwnerID).Str("name", view.Name).Msg("[SavedViewService-CreateView] Failed to create saved view")
+		return nil, err
+	}
+
+	log.Info().Int64("view_id", createdView.ID).Int64("admin_id", view.OwnerID).Bool("shared", createdView.Shared).Msg("[SavedViewService-CreateView] Saved view created successfully")
+	return createdView, nil
+}
+
+func (s *SavedViewService) UpdateView(ctx context.Context, adminID, id int64, view *entity.SavedViewEntity) (*entity.SavedViewEntity, error) {
+	existing, err := s.ownedView(ctx, adminID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The list is fixed at creation; the filters only make sense for it
+	view.ID, view.OwnerID, view.List = existing.ID, existing.OwnerID, existing.List
+	if err := validateSavedView(view); err != nil {
+		log.Warn().Err(err).Int64("view_id", id).Msg("[SavedViewService-UpdateView] Invalid saved view")
+		return nil, err
+	}
+
+	updatedView, err := s.viewRepo.UpdateView(ctx, view)
+	if err != nil {
+		log.Error().Err(err).Int64("view_id", id).Msg("[SavedViewService-UpdateView] Failed to update saved view")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("saved view not found")
+		}
+		return nil, err
+	}
+
+	log.Info().Int64("view_id", id).Int64("admin_id", adminID).Msg("[SavedViewService-UpdateView] Saved view updated successfully")
+	return updatedView, nil
+}
+
+func (s *SavedViewService) DeleteView(ctx context.Context, adminID, id int64) error {
+	if _, err := s.ownedView(ctx, adminID, id); err != nil {
+		return err
+	}
+
+	if err := s.viewRepo.DeleteView(ctx, id); err != nil {
+		log.Error().Err(err).Int64("view_id", id).Msg("[SavedViewService-DeleteView] Failed to delete saved view")
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("saved view not found")
+		}
+		return err
+	}
+
+	log.Info().Int64("view_id", id).Int64("admin_id", adminID).Msg("[SavedViewService-DeleteView] Saved view deleted successfully")
+	return nil
+}
+
+// ownedView hides other admins' views, shared or not, behind the same error as a missing one
+func (s *SavedViewService) ownedView(ctx context.Context, adminID, id int64) (*entity.SavedViewEntity, error) {
+	view, err := s.viewRepo.GetViewByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("saved view not found")
+		}
+		log.Error().Err(err).Int64("view_id", id).Msg("[SavedViewService-ownedView] Failed to get saved view")
+		return nil, err
+	}
+
+	if view.OwnerID != adminID {
+		log.Warn().Int64("view_id", id).Int64("admin_id", adminID).Msg("[SavedViewService-ownedView] Saved view belongs to another admin")
+		return nil, errors.New("saved view not found")
+	}
+
+	return view, nil
+}
+
+func validateSavedView(view *entity.SavedViewEntity) error {
+	filters, ok := savedViewFilters[view.List]
+	if !ok {
+		return unknownViewListError()
+	}
+
+	view.Name = strings.TrimSpace(view.Name)
+	if view.Name == "" || utf8.RuneCountInString(view.Name) > 100 {
+		return errors.New("saved view name must be between 1 and 100 characters")
+	}
+
+	for key, value := range view.Filters {
+		validate, ok := filters[key]
+		if !ok {
+			return fmt.Errorf("saved view filter '%s' is not supported by the %s list", key, view.List)
+		}
+		if err := validate(strings.TrimSpace(value)); err != nil {
+			return fmt.Errorf("saved view filter '%s' %w", key, err)
+		}
+		view.Filters[key] = strings.TrimSpace(value)
+	}
+
+	return nil
+}
+
+func unknownViewListError() error {
+	lists := make([]string, 0, len(savedViewFilters))
+	for list := range savedViewFilters {
+		lists = append(lists, list)
+	}
+	sort.Strings(lists)
+	return fmt.Errorf("saved view list must be one of: %s", strings.Join(lists, ", "))
+}
+
+func validateViewSearch(value string) error {
+	if utf8.RuneCountInString(value) > 100 {
+		return errors.New("must be at most 100 characters")
+	}
+	return nil
+}
+
+func validateViewCustomerOrder(value string) error {
+	if !customerOrderPattern.MatchString(value) {
+		return errors.New("must be a customer column (name, email, username, created_at, updated_at) optionally followed by ASC or DESC")
+	}
+	return nil
+}
+
+func validateViewLimit(value string) error {
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 1 || limit > 100 {
+		return errors.New("must be a number between 1 and 100")
+	}
+	return nil
+}
+
+func NewSavedViewService(viewRepo port.SavedViewRepositoryInterface) port.SavedViewServiceInterface {
+	return &SavedViewService{
+		viewRepo: viewRepo,
+	}
+}
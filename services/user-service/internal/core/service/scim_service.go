@@ -0,0 +1,713 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// scimFilterPattern matches the only filter form identity providers rely on: attr eq "value"
+var scimFilterPattern = regexp.MustCompile(`(?i)^\s*([a-z.]+)\s+eq\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// scimMemberFilterPattern picks the member out of a remove path like members[value eq "12"]
+var scimMemberFilterPattern = regexp.MustCompile(`(?i)^members\[\s*value\s+eq\s+"([^"]*)"\s*\]$`)
+
+type SCIMService struct {
+	scimRepo        port.SCIMRepositoryInterface
+	userRepo        port.UserRepositoryInterface
+	sessionRepo     port.SessionInterface
+	auditLogService port.AuditLogServiceInterface
+	emailPolicy     *utils.EmailPolicy
+	passwordHasher  port.PasswordInterface
+	groups          []string
+	pageSize        int
+}
+
+func (s *SCIMService) ListUsers(ctx context.Context, filter string, startIndex, count int) ([]entity.SCIMUserEntity, int64, error) {
+	parsed, err := parseSCIMFilter(filter, "username", "externalid")
+	if err != nil {
+		return nil, 0, err
+	}
+	if parsed != nil && parsed.Attribute == "username" {
+		parsed.Value = s.normalizeUserName(parsed.Value)
+	}
+
+	offset, limit := s.page(startIndex, count)
+	users, total, err := s.scimRepo.ListUsers(ctx, parsed, offset, limit)
+	if err != nil {
+		log.Error().Err(err).Str("filter", filter).Msg("[SCIMService-ListUsers] Failed to list users")
+		return nil, 0, err
+	}
+
+	for i := range users {
+		s.hideOtherRoles(&users[i])
+	}
+	return users, total, nil
+}
+
+func (s *SCIMService) GetUser(ctx context.Context, id int64) (*entity.SCIMUserEntity, error) {
+	user, err := s.scimRepo.GetUser(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("scim user not found")
+		}
+		return nil, err
+	}
+
+	s.hideOtherRoles(user)
+	return user, nil
+}
+
+func (s *SCIMService) CreateUser(ctx context.Context, user entity.SCIMUserEntity) (*entity.SCIMUserEntity, error) {
+	if err := s.validateUser(&user); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.userRepo.GetUserByEmailIncludingUnverified(ctx, user.UserName)
+	switch {
+	case err == nil:
+		return s.adoptUser(ctx, existing, user)
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		log.Error().Err(err).Str("email", user.UserName).Msg("[SCIMService-CreateUser] Failed to look up existing account")
+		return nil, err
+	}
+
+	password, err := randomHex(24)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := s.passwordHasher.HashPassword(password)
+	if err != nil {
+		log.Error().Err(err).Str("email", user.UserName).Msg("[SCIMService-CreateUser] Failed to hash password")
+		return nil, err
+	}
+
+	created, err := s.scimRepo.CreateUser(ctx, &user, hashedPassword)
+	if err != nil {
+		if err.Error() != "email already exists" {
+			log.Error().Err(err).Str("email", user.UserName).Msg("[SCIMService-CreateUser] Failed to create user")
+		}
+		return nil, err
+	}
+
+	s.record(ctx, entity.AuditEventSCIMUserProvisioned, created.ID, map[string]interface{}{
+		"email":       created.UserName,
+		"external_id": created.ExternalID,
+		"active":      created.Active,
+	})
+	log.Info().Int64("user_id", created.ID).Str("email", created.UserName).Msg("[SCIMService-CreateUser] User provisioned")
+
+	s.hideOtherRoles(created)
+	return created, nil
+}
+
+// adoptUser brings a staff account that existed before provisioning under SCIM management.
+// Customers' accounts are never handed to the identity provider.
+func (s *SCIMService) adoptUser(ctx context.Context, existing *entity.UserEntity, user entity.SCIMUserEntity) (*entity.SCIMUserEntity, error) {
+	if _, err := s.scimRepo.GetUser(ctx, existing.ID); err == nil {
+		return nil, errors.New("scim user already exists")
+	}
+	if !s.isGroup(existing.RoleName) {
+		log.Warn().Int64("user_id", existing.ID).Str("role", existing.RoleName).Msg("[SCIMService-CreateUser] Email belongs to an account outside the SCIM groups")
+		return nil, errors.New("email already exists")
+	}
+
+	if err := s.scimRepo.AdoptUser(ctx, existing.ID, user.ExternalID); err != nil {
+		return nil, err
+	}
+	user.ID = existing.ID
+	if _, err := s.save(ctx, &user, true); err != nil {
+		return nil, err
+	}
+
+	s.record(ctx, entity.AuditEventSCIMUserProvisioned, existing.ID, map[string]interface{}{
+		"email":       user.UserName,
+		"external_id": user.ExternalID,
+		"active":      user.Active,
+		"adopted":     true,
+	})
+	log.Info().Int64("user_id", existing.ID).Str("email", user.UserName).Msg("[SCIMService-CreateUser] Existing staff account adopted")
+	return s.GetUser(ctx, existing.ID)
+}
+
+func (s *SCIMService) ReplaceUser(ctx context.Context, id int64, user entity.SCIMUserEntity) (*entity.SCIMUserEntity, error) {
+	current, err := s.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateUser(&user); err != nil {
+		return nil, err
+	}
+
+	user.ID = id
+	deactivated, err := s.save(ctx, &user, current.Active)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordUpdate(ctx, &user, deactivated)
+	return s.GetUser(ctx, id)
+}
+
+func (s *SCIMService) PatchUser(ctx context.Context, id int64, ops []entity.SCIMPatchOperation) (*entity.SCIMUserEntity, error) {
+	current, err := s.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	user := *current
+	if err := applySCIMUserPatch(&user, ops); err != nil {
+		return nil, err
+	}
+	if err := s.validateUser(&user); err != nil {
+		return nil, err
+	}
+
+	deactivated, err := s.save(ctx, &user, current.Active)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordUpdate(ctx, &user, deactivated)
+	return s.GetUser(ctx, id)
+}
+
+// save stores user and reports whether an active account was deactivated, which also ends its
+// sessions
+func (s *SCIMService) save(ctx context.Context, user *entity.SCIMUserEntity, wasActive bool) (bool, error) {
+	if err := s.scimRepo.UpdateUser(ctx, user); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, errors.New("scim user not found")
+		}
+		if err.Error() != "email already exists" {
+			log.Error().Err(err).Int64("user_id", user.ID).Msg("[SCIMService-save] Failed to update user")
+		}
+		return false, err
+	}
+
+	if !wasActive || user.Active {
+		return false, nil
+	}
+	s.endSessions(ctx, user.ID)
+	log.Info().Int64("user_id", user.ID).Msg("[SCIMService-save] User deactivated")
+	return true, nil
+}
+
+func (s *SCIMService) recordUpdate(ctx context.Context, user *entity.SCIMUserEntity, deactivated bool) {
+	event := entity.AuditEventSCIMUserUpdated
+	if deactivated {
+		event = entity.AuditEventSCIMUserDeactivated
+	}
+	s.record(ctx, event, user.ID, map[string]interface{}{
+		"email":       user.UserName,
+		"external_id": user.ExternalID,
+		"active":      user.Active,
+	})
+}
+
+func (s *SCIMService) DeleteUser(ctx context.Context, id int64) error {
+	if err := s.scimRepo.DeleteUser(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("scim user not found")
+		}
+		log.Error().Err(err).Int64("user_id", id).Msg("[SCIMService-DeleteUser] Failed to delete user")
+		return err
+	}
+
+	s.endSessions(ctx, id)
+	s.record(ctx, entity.AuditEventSCIMUserDeleted, id, nil)
+	log.Info().Int64("user_id", id).Msg("[SCIMService-DeleteUser] User deprovisioned")
+	return nil
+}
+
+func (s *SCIMService) ListGroups(ctx context.Context, filter string, startIndex, count int, withMembers bool) ([]entity.SCIMGroupEntity, int64, error) {
+	parsed, err := parseSCIMFilter(filter, "displayname")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset, limit := s.page(startIndex, count)
+	groups, total, err := s.scimRepo.ListGroups(ctx, s.groups, parsed, offset, limit, withMembers)
+	if err != nil {
+		log.Error().Err(err).Str("filter", filter).Msg("[SCIMService-ListGroups] Failed to list groups")
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
+
+func (s *SCIMService) GetGroup(ctx context.Context, id int64) (*entity.SCIMGroupEntity, error) {
+	group, err := s.scimRepo.GetGroup(ctx, id, s.groups)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("scim group not found")
+		}
+		return nil, err
+	}
+	return group, nil
+}
+
+func (s *SCIMService) ReplaceGroupMembers(ctx context.Context, id int64, memberIDs []int64) (*entity.SCIMGroupEntity, error) {
+	group, err := s.GetGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	target := make(map[int64]bool, len(memberIDs))
+	for _, memberID := range memberIDs {
+		target[memberID] = true
+	}
+	if err := s.setMembers(ctx, group, target); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, id)
+}
+
+func (s *SCIMService) PatchGroup(ctx context.Context, id int64, ops []entity.SCIMPatchOperation) (*entity.SCIMGroupEntity, error) {
+	group, err := s.GetGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	target := make(map[int64]bool, len(group.Members))
+	for _, member := range group.Members {
+		target[member.UserID] = true
+	}
+	if err := applySCIMGroupPatch(group, target, ops); err != nil {
+		return nil, err
+	}
+
+	if err := s.setMembers(ctx, group, target); err != nil {
+		return nil, err
+	}
+	return s.GetGroup(ctx, id)
+}
+
+// setMembers gives the group's role to the accounts in target that lack it and takes it from
+// the current members that are not in target, who fall back to Customer. A user holds one role,
+// so joining a group leaves any other. Changed accounts sign in again to pick up the new role.
+func (s *SCIMService) setMembers(ctx context.Context, group *entity.SCIMGroupEntity, target map[int64]bool) error {
+	current := make(map[int64]bool, len(group.Members))
+	for _, member := range group.Members {
+		current[member.UserID] = true
+	}
+
+	var added, removed []int64
+	for userID := range target {
+		if current[userID] {
+			continue
+		}
+		if _, err := s.scimRepo.GetUser(ctx, userID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return fmt.Errorf("scim member %d not found", userID)
+			}
+			return err
+		}
+		added = append(added, userID)
+	}
+	for userID := range current {
+		if !target[userID] {
+			removed = append(removed, userID)
+		}
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		return nil
+	}
+
+	var fallbackRoleID int64
+	if len(removed) > 0 {
+		customerRole, err := s.userRepo.GetRoleByName(ctx, "Customer")
+		if err != nil {
+			log.Error().Err(err).Msg("[SCIMService-setMembers] Failed to find Customer role")
+			return err
+		}
+		fallbackRoleID = customerRole.ID
+	}
+
+	for _, userID := range added {
+		if err := s.changeRole(ctx, userID, group.ID, group.Name, "added"); err != nil {
+			return err
+		}
+	}
+	for _, userID := range removed {
+		if err := s.changeRole(ctx, userID, fallbackRoleID, group.Name, "removed"); err != nil {
+			return err
+		}
+	}
+
+	log.Info().Int64("role_id", group.ID).Int("added", len(added)).Int("removed", len(removed)).Msg("[SCIMService-setMembers] Group membership changed")
+	return nil
+}
+
+func (s *SCIMService) changeRole(ctx context.Context, userID, roleID int64, group, change string) error {
+	if err := s.userRepo.UpdateUserRole(ctx, userID, roleID); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Int64("role_id", roleID).Msg("[SCIMService-changeRole] Failed to change role")
+		return err
+	}
+
+	s.endSessions(ctx, userID)
+	s.record(ctx, entity.AuditEventSCIMGroupChanged, userID, map[string]interface{}{
+		"group":  group,
+		"change": change,
+	})
+	return nil
+}
+
+func (s *SCIMService) validateUser(user *entity.SCIMUserEntity) error {
+	email, err := s.emailPolicy.NormalizeEmail(user.UserName)
+	if err != nil {
+		return errors.New("scim userName must be a valid email")
+	}
+	user.UserName = email
+	user.Name = strings.TrimSpace(user.Name)
+	user.Phone = strings.TrimSpace(user.Phone)
+
+	switch {
+	case user.Name == "":
+		return errors.New("scim name is required")
+	case len(user.Name) > 255:
+		return errors.New("scim name must not exceed 255 characters")
+	case len(user.Phone) > 20:
+		return errors.New("scim phone number must not exceed 20 characters")
+	case len(user.ExternalID) > 255:
+		return errors.New("scim externalId must not exceed 255 characters")
+	}
+	return nil
+}
+
+func (s *SCIMService) normalizeUserName(userName string) string {
+	if email, err := s.emailPolicy.NormalizeEmail(userName); err == nil {
+		return email
+	}
+	return strings.ToLower(userName)
+}
+
+// page turns SCIM's 1-based startIndex and count into an offset and limit; a negative count
+// asks for the default page size
+func (s *SCIMService) page(startIndex, count int) (int, int) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 || count > s.pageSize {
+		count = s.pageSize
+	}
+	return startIndex - 1, count
+}
+
+func (s *SCIMService) isGroup(roleName string) bool {
+	for _, group := range s.groups {
+		if group == roleName {
+			return true
+		}
+	}
+	return false
+}
+
+// hideOtherRoles keeps roles outside the SCIM groups, such as Customer, from the IdP
+func (s *SCIMService) hideOtherRoles(user *entity.SCIMUserEntity) {
+	if !s.isGroup(user.RoleName) {
+		user.RoleID = 0
+		user.RoleName = ""
+	}
+}
+
+func (s *SCIMService) endSessions(ctx context.Context, userID int64) {
+	if err := s.sessionRepo.DeleteAllUserTokens(ctx, userID); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[SCIMService-endSessions] Failed to revoke sessions")
+	}
+}
+
+// record audits a change against the affected account, naming the identity provider's key
+func (s *SCIMService) record(ctx context.Context, event string, userID int64, metadata map[string]interface{}) {
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	if principal, ok := entity.PrincipalFromContext(ctx); ok && principal.IsAPIKey() {
+		metadata["api_key_id"] = principal.APIKeyID
+		metadata["partner"] = principal.Partner
+	}
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{UserID: userID, Event: event, Metadata: metadata})
+}
+
+// parseSCIMFilter reads an `attribute eq "value"` filter on one of attributes, which are
+// lowercase. An empty filter matches everything.
+func parseSCIMFilter(filter string, attributes ...string) (*entity.SCIMFilter, error) {
+	if strings.TrimSpace(filter) == "" {
+		return nil, nil
+	}
+
+	match := scimFilterPattern.FindStringSubmatch(filter)
+	if match == nil {
+		return nil, errors.New("scim filter is not supported")
+	}
+	attribute := strings.ToLower(match[1])
+	supported := false
+	for _, a := range attributes {
+		supported = supported || a == attribute
+	}
+	if !supported {
+		return nil, errors.New("scim filter is not supported")
+	}
+
+	value, err := strconv.Unquote(`"` + match[2] + `"`)
+	if err != nil {
+		return nil, errors.New("scim filter is not supported")
+	}
+	return &entity.SCIMFilter{Attribute: attribute, Value: value}, nil
+}
+
+// applySCIMUserPatch applies PatchOp operations to user. Operations without a path carry an
+// object of attributes, as Entra ID sends them.
+func applySCIMUserPatch(user *entity.SCIMUserEntity, ops []entity.SCIMPatchOperation) error {
+	givenName, familyName, _ := strings.Cut(user.Name, " ")
+	nameParts := false
+
+	var apply func(op, path string, value interface{}) error
+	apply = func(op, path string, value interface{}) error {
+		if path == "" {
+			attributes, ok := value.(map[string]interface{})
+			if !ok {
+				return errors.New("scim patch without a path needs an object value")
+			}
+			for key, v := range attributes {
+				if err := apply(op, key, v); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		switch strings.ToLower(path) {
+		case "active":
+			active, err := scimBool(value)
+			if err != nil {
+				return errors.New("scim active must be true or false")
+			}
+			user.Active = active
+		case "username":
+			user.UserName = scimString(value)
+		case "externalid":
+			user.ExternalID = scimString(value)
+		case "displayname", "name.formatted":
+			user.Name = scimString(value)
+			givenName, familyName, _ = strings.Cut(user.Name, " ")
+		case "name.givenname":
+			givenName, nameParts = scimString(value), true
+		case "name.familyname":
+			familyName, nameParts = scimString(value), true
+		case "name":
+			name, ok := value.(map[string]interface{})
+			if !ok {
+				return errors.New("scim name must be an object")
+			}
+			for key, v := range name {
+				if err := apply(op, "name."+key, v); err != nil {
+					return err
+				}
+			}
+		case `phonenumbers[type eq "work"].value`, `phonenumbers[type eq "mobile"].value`:
+			user.Phone = scimString(value)
+		case "phonenumbers":
+			user.Phone = scimPrimaryValue(value)
+		case `emails[type eq "work"].value`, "emails":
+			// userName is the account's email; the emails list only mirrors it
+		default:
+			return fmt.Errorf("scim path %s is not supported", path)
+		}
+		return nil
+	}
+
+	for _, op := range ops {
+		switch op.Op {
+		case "add", "replace":
+		case "remove":
+			switch strings.ToLower(op.Path) {
+			case "externalid":
+				user.ExternalID = ""
+				continue
+			case "phonenumbers":
+				user.Phone = ""
+				continue
+			}
+			return fmt.Errorf("scim path %s cannot be removed", op.Path)
+		default:
+			return fmt.Errorf("scim patch op %s is not supported", op.Op)
+		}
+		if err := apply(op.Op, op.Path, op.Value); err != nil {
+			return err
+		}
+	}
+
+	if nameParts {
+		user.Name = strings.TrimSpace(givenName + " " + familyName)
+	}
+	return nil
+}
+
+// applySCIMGroupPatch turns PatchOp operations on members into the target member set. The
+// group's name is the role's and cannot be changed over SCIM.
+func applySCIMGroupPatch(group *entity.SCIMGroupEntity, target map[int64]bool, ops []entity.SCIMPatchOperation) error {
+	for _, op := range ops {
+		path := strings.ToLower(op.Path)
+		value := op.Value
+
+		// Entra ID may send {"op": "replace", "value": {"members": [...], "displayName": "..."}}
+		if path == "" {
+			attributes, ok := value.(map[string]interface{})
+			if !ok {
+				return errors.New("scim patch without a path needs an object value")
+			}
+			if name, ok := attributes["displayName"]; ok && scimString(name) != group.Name {
+				return errors.New("scim group displayName cannot be changed")
+			}
+			members, ok := attributes["members"]
+			if !ok {
+				continue
+			}
+			path, value = "members", members
+		}
+
+		if match := scimMemberFilterPattern.FindStringSubmatch(op.Path); match != nil && op.Op == "remove" {
+			id, err := strconv.ParseInt(match[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("scim member %s is not a valid id", match[1])
+			}
+			delete(target, id)
+			continue
+		}
+
+		switch {
+		case path == "displayname":
+			if scimString(value) != group.Name {
+				return errors.New("scim group displayName cannot be changed")
+			}
+			continue
+		case path != "members":
+			return fmt.Errorf("scim path %s is not supported", op.Path)
+		}
+
+		ids, err := scimMemberIDs(value)
+		if err != nil {
+			return err
+		}
+		switch op.Op {
+		case "add":
+			for _, id := range ids {
+				target[id] = true
+			}
+		case "replace":
+			for id := range target {
+				delete(target, id)
+			}
+			for _, id := range ids {
+				target[id] = true
+			}
+		case "remove":
+			if value == nil {
+				for id := range target {
+					delete(target, id)
+				}
+			}
+			for _, id := range ids {
+				delete(target, id)
+			}
+		default:
+			return fmt.Errorf("scim patch op %s is not supported", op.Op)
+		}
+	}
+	return nil
+}
+
+// scimMemberIDs reads [{"value": "12"}, ...]
+func scimMemberIDs(value interface{}) ([]int64, error) {
+	if value == nil {
+		return nil, nil
+	}
+	members, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("scim members must be a list")
+	}
+
+	ids := make([]int64, 0, len(members))
+	for _, member := range members {
+		object, ok := member.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("scim members must be a list")
+		}
+		raw := scimString(object["value"])
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("scim member %s is not a valid id", raw)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func scimString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// scimBool also takes "True"/"False", which some identity providers send
+func scimBool(value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		return strconv.ParseBool(strings.ToLower(v))
+	default:
+		return false, errors.New("not a boolean")
+	}
+}
+
+// scimPrimaryValue picks the primary entry's value from a multi-valued attribute, or the first
+func scimPrimaryValue(value interface{}) string {
+	entries, ok := value.([]interface{})
+	if !ok {
+		return ""
+	}
+
+	first := ""
+	for i, entry := range entries {
+		object, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if primary, _ := object["primary"].(bool); primary {
+			return scimString(object["value"])
+		}
+		if i == 0 {
+			first = scimString(object["value"])
+		}
+	}
+	return first
+}
+
+func NewSCIMService(scimRepo port.SCIMRepositoryInterface, userRepo port.UserRepositoryInterface, sessionRepo port.SessionInterface, auditLogService port.AuditLogServiceInterface, cfg *config.Config) port.SCIMServiceInterface {
+	return &SCIMService{
+		scimRepo:        scimRepo,
+		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		auditLogService: auditLogService,
+		emailPolicy:     EmailPolicyFromConfig(cfg),
+		passwordHasher:  PasswordHasherFromConfig(cfg),
+		groups:          cfg.SCIM.ManagedGroups(),
+		pageSize:        cfg.SCIM.PageSize(),
+	}
+}
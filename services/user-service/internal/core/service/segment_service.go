@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+type SegmentService struct {
+	segmentRepo     port.SegmentRepositoryInterface
+	userRepo        port.UserRepositoryInterface
+	jobService      port.JobServiceInterface
+	emailPublisher  port.EmailInterface
+	auditLogService port.AuditLogServiceInterface
+	batchSize       int
+	batchInterval   time.Duration
+}
+
+// CreateSegment stores the filter and materializes the members straight away
+func (s *SegmentService) CreateSegment(ctx context.Context, segment *entity.SegmentEntity) (*entity.SegmentEntity, error) {
+	segment.Name = strings.TrimSpace(segment.Name)
+	segment.Filter.City = strings.TrimSpace(segment.Filter.City)
+	if err := validateSegment(segment); err != nil {
+		return nil, err
+	}
+
+	created, err := s.segmentRepo.CreateSegment(ctx, segment)
+	if err != nil {
+		if err.Error() == "segment already exists" {
+			return nil, err
+		}
+		return nil, errors.New("failed to create segment")
+	}
+
+	log.Info().Int64("segment_id", created.ID).Str("name", created.Name).Int64("created_by", segment.CreatedBy).Msg("[SegmentService-CreateSegment] Segment created")
+	return s.RefreshSegment(ctx, created.ID)
+}
+
+func (s *SegmentService) GetSegments(ctx context.Context) ([]entity.SegmentEntity, error) {
+	segments, err := s.segmentRepo.GetSegments(ctx)
+	if err != nil {
+		return nil, errors.New("failed to retrieve segments")
+	}
+	return segments, nil
+}
+
+func (s *SegmentService) GetSegmentByID(ctx context.Context, id int64) (*entity.SegmentEntity, error) {
+	segment, err := s.segmentRepo.GetSegmentByID(ctx, id)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("segment not found")
+		}
+		return nil, errors.New("failed to retrieve segment")
+	}
+	return segment, nil
+}
+
+// RefreshSegment re-runs the filter, e.g. before a campaign, since members are a snapshot
+func (s *SegmentService) RefreshSegment(ctx context.Context, id int64) (*entity.SegmentEntity, error) {
+	members, err := s.segmentRepo.Materialize(ctx, id)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("segment not found")
+		}
+		return nil, errors.New("failed to materialize segment")
+	}
+
+	log.Info().Int64("segment_id", id).Int64("members", members).Msg("[SegmentService-RefreshSegment] Segment materialized")
+	return s.GetSegmentByID(ctx, id)
+}
+
+func (s *SegmentService) DeleteSegment(ctx context.Context, id int64) error {
+	if err := s.segmentRepo.DeleteSegment(ctx, id); err != nil {
+		if err.Error() == "record not found" {
+			return errors.New("segment not found")
+		}
+		return errors.New("failed to delete segment")
+	}
+	return nil
+}
+
+// LaunchCampaign splits the members into batches of batchSize and queues batch i to run
+// i*batchInterval from now, so a large segment does not flood RabbitMQ or the mail provider
+func (s *SegmentService) LaunchCampaign(ctx context.Context, segmentID int64, campaign *entity.CampaignEntity) (*entity.CampaignEntity, error) {
+	campaign.Name = strings.TrimSpace(campaign.Name)
+	if campaign.Name == "" {
+		return nil, errors.New("campaign name is required")
+	}
+	if strings.TrimSpace(campaign.Subject) == "" || strings.TrimSpace(campaign.Body) == "" {
+		return nil, errors.New("campaign subject and body are required")
+	}
+
+	segment, err := s.GetSegmentByID(ctx, segmentID)
+	if err != nil {
+		return nil, err
+	}
+	if segment.MemberCount == 0 {
+		return nil, errors.New("segment has no members")
+	}
+
+	campaign.SegmentID = segmentID
+	created, err := s.segmentRepo.CreateCampaign(ctx, campaign)
+	if err != nil {
+		return nil, errors.New("failed to launch campaign")
+	}
+
+	startAt := time.Now()
+	var afterUserID int64
+	for {
+		userIDs, err := s.segmentRepo.GetMemberIDs(ctx, segmentID, afterUserID, s.batchSize)
+		if err != nil {
+			return nil, errors.New("failed to launch campaign")
+		}
+		if len(userIDs) == 0 {
+			break
+		}
+
+		payload := entity.CampaignBatchJobPayload{CampaignID: created.ID, UserIDs: userIDs}
+		uniqueKey := fmt.Sprintf("campaign:%d:%d", created.ID, created.Batches)
+		runAt := startAt.Add(time.Duration(created.Batches) * s.batchInterval)
+		if _, err := s.jobService.EnqueueUnique(ctx, entity.JobTypeSendCampaignBatch, uniqueKey, payload, runAt); err != nil {
+			log.Error().Err(err).Int64("campaign_id", created.ID).Int("batch", created.Batches).Msg("[SegmentService-LaunchCampaign] Failed to queue campaign batch")
+			return nil, errors.New("failed to launch campaign")
+		}
+
+		created.Batches++
+		created.Recipients += int64(len(userIDs))
+		afterUserID = userIDs[len(userIDs)-1]
+	}
+
+	// The batches are queued either way; the counts are only for the admin's overview
+	_ = s.segmentRepo.UpdateCampaignFanOut(ctx, created.ID, created.Recipients, created.Batches)
+
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{
+		UserID: campaign.CreatedBy,
+		Event:  entity.AuditEventCampaignLaunched,
+		Metadata: map[string]interface{}{
+			"campaign_id": created.ID,
+			"segment_id":  segmentID,
+			"name":        created.Name,
+			"recipients":  created.Recipients,
+		},
+	})
+
+	log.Info().Int64("campaign_id", created.ID).Int64("segment_id", segmentID).Int64("recipients", created.Recipients).Int("batches", created.Batches).Msg("[SegmentService-LaunchCampaign] Campaign launched")
+	return created, nil
+}
+
+// SendCampaignBatch publishes one email per recipient. A failure retries the whole batch, so
+// notification-service should dedupe on campaign_id and the address.
+func (s *SegmentService) SendCampaignBatch(ctx context.Context, campaignID int64, userIDs []int64) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	campaign, err := s.segmentRepo.GetCampaignByID(ctx, campaignID)
+	if err != nil {
+		if err.Error() == "record not found" {
+			// Deleting the segment deletes its campaigns; the remaining batches have nothing to send
+			log.Warn().Int64("campaign_id", campaignID).Msg("[SegmentService-SendCampaignBatch] Campaign no longer exists, batch skipped")
+			return nil
+		}
+		return err
+	}
+
+	recipients, err := s.segmentRepo.GetRecipients(ctx, userIDs)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, recipient := range recipients {
+		if err := s.emailPublisher.SendCampaignEmail(ctx, recipient.Email, recipient.Name, campaign); err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to send campaign to %d of %d recipients", failed, len(recipients))
+	}
+
+	log.Info().Int64("campaign_id", campaignID).Int("sent", len(recipients)).Int("skipped", len(userIDs)-len(recipients)).Msg("[SegmentService-SendCampaignBatch] Campaign batch sent")
+	return nil
+}
+
+// RecordOrder is called by the order service for every order; repeats of an order count once
+func (s *SegmentService) RecordOrder(ctx context.Context, userID int64, orderID string, orderedAt time.Time) error {
+	if userID <= 0 {
+		return errors.New("invalid user id")
+	}
+	orderID = strings.TrimSpace(orderID)
+	if orderID == "" {
+		return errors.New("order id is required")
+	}
+	if orderedAt.IsZero() {
+		orderedAt = time.Now()
+	}
+
+	if _, err := s.userRepo.GetUserByID(ctx, userID); err != nil {
+		if err.Error() == "record not found" {
+			return errors.New("user not found")
+		}
+		return err
+	}
+
+	recorded, err := s.segmentRepo.RecordOrder(ctx, userID, orderID, orderedAt)
+	if err != nil {
+		return errors.New("failed to record order")
+	}
+	if !recorded {
+		log.Debug().Int64("user_id", userID).Str("order_id", orderID).Msg("[SegmentService-RecordOrder] Order already recorded")
+	}
+	return nil
+}
+
+func validateSegment(segment *entity.SegmentEntity) error {
+	filter := segment.Filter
+	if segment.Name == "" {
+		return errors.New("segment name is required")
+	}
+	if filter.SignedUpFrom != nil && filter.SignedUpTo != nil && !filter.SignedUpFrom.Before(*filter.SignedUpTo) {
+		return errors.New("segment signed_up_from must be before signed_up_to")
+	}
+	if (filter.MinOrders != nil && *filter.MinOrders < 0) || (filter.MaxOrders != nil && *filter.MaxOrders < 0) {
+		return errors.New("segment order counts cannot be negative")
+	}
+	if filter.MinOrders != nil && filter.MaxOrders != nil && *filter.MinOrders > *filter.MaxOrders {
+		return errors.New("segment min_orders cannot exceed max_orders")
+	}
+	return nil
+}
+
+func NewSegmentService(segmentRepo port.SegmentRepositoryInterface, userRepo port.UserRepositoryInterface, jobService port.JobServiceInterface, emailPublisher port.EmailInterface, auditLogService port.AuditLogServiceInterface, cfg *config.Config) port.SegmentServiceInterface {
+	batchSize, batchInterval := cfg.Campaign.Batch()
+	return &SegmentService{
+		segmentRepo:     segmentRepo,
+		userRepo:        userRepo,
+		jobService:      jobService,
+		emailPublisher:  emailPublisher,
+		auditLogService: auditLogService,
+		batchSize:       batchSize,
+		batchInterval:   batchInterval,
+	}
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+	"user-service/utils/i18n"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+type SSOService struct {
+	provider        port.OIDCProviderInterface
+	stateRepo       port.SSOStateRepositoryInterface
+	ssoRepo         port.SSORepositoryInterface
+	userRepo        port.UserRepositoryInterface
+	sessionRepo     port.SessionInterface
+	signIn          port.ExternalSignInInterface
+	auditLogService port.AuditLogServiceInterface
+	emailPolicy     *utils.EmailPolicy
+	passwordHasher  port.PasswordInterface
+	groupRoles      []config.SSOGroupRole
+	jit             bool
+	stateTTL        time.Duration
+}
+
+func (s *SSOService) StartLogin(ctx context.Context, rememberMe bool) (string, *entity.SSOStateEntity, error) {
+	state := &entity.SSOStateEntity{
+		RememberMe: rememberMe,
+		ExpiresAt:  time.Now().Add(s.stateTTL),
+	}
+	var err error
+	if state.ID, err = randomHex(16); err != nil {
+		return "", nil, err
+	}
+	if state.Nonce, err = randomHex(16); err != nil {
+		return "", nil, err
+	}
+	// 64 hex characters, inside the 43-128 PKCE allows
+	if state.CodeVerifier, err = randomHex(32); err != nil {
+		return "", nil, err
+	}
+
+	authURL, err := s.provider.AuthCodeURL(ctx, state.ID, state.Nonce, pkceChallenge(state.CodeVerifier))
+	if err != nil {
+		log.Error().Err(err).Msg("[SSOService-StartLogin] Identity provider unavailable")
+		return "", nil, errors.New("sso provider unavailable")
+	}
+	if err := s.stateRepo.SaveState(ctx, state); err != nil {
+		log.Error().Err(err).Msg("[SSOService-StartLogin] Failed to save state")
+		return "", nil, err
+	}
+	return authURL, state, nil
+}
+
+func (s *SSOService) CompleteLogin(ctx context.Context, stateID, code string, client entity.ClientEntity) (*entity.UserEntity, string, error) {
+	state, err := s.stateRepo.TakeState(ctx, stateID)
+	if err != nil {
+		if err.Error() != "sso state not found" {
+			log.Error().Err(err).Msg("[SSOService-CompleteLogin] Failed to take state")
+		}
+		return nil, "", err
+	}
+
+	identity, err := s.provider.Exchange(ctx, code, state.CodeVerifier)
+	if err != nil {
+		log.Warn().Err(err).Msg("[SSOService-CompleteLogin] Code exchange failed")
+		return nil, "", errors.New("sso code exchange failed")
+	}
+	// The nonce ties the ID token to this sign-in, so a token replayed from another one fails
+	if identity.Nonce != state.Nonce {
+		log.Warn().Str("subject", identity.Subject).Msg("[SSOService-CompleteLogin] Nonce mismatch")
+		return nil, "", errors.New("sso code exchange failed")
+	}
+
+	role := s.roleFor(identity.Groups)
+	if role == "" {
+		log.Warn().Str("subject", identity.Subject).Strs("groups", identity.Groups).Msg("[SSOService-CompleteLogin] No group maps to a staff role")
+		return nil, "", errors.New("sso user has no staff role")
+	}
+
+	userID, err := s.resolveUser(ctx, identity, role)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := s.syncRole(ctx, userID, role, identity.Subject); err != nil {
+		return nil, "", err
+	}
+
+	user, token, err := s.signIn.SignInExternal(ctx, userID, client, state.RememberMe)
+	if err != nil {
+		return nil, "", err
+	}
+
+	s.record(ctx, entity.AuditEventSSOSignIn, user.ID, client, map[string]interface{}{
+		"subject": identity.Subject,
+		"role":    role,
+	})
+	log.Info().Int64("user_id", user.ID).Str("role", role).Msg("[SSOService-CompleteLogin] Staff signed in through SSO")
+	return user, token, nil
+}
+
+// resolveUser finds the account linked to the provider's subject. On a first sign-in the
+// account is matched by verified email and linked, or created when JIT provisioning is on.
+func (s *SSOService) resolveUser(ctx context.Context, identity *entity.OIDCIdentityEntity, role string) (int64, error) {
+	userID, err := s.ssoRepo.GetUserIDBySubject(ctx, identity.Subject)
+	if err == nil {
+		user, err := s.userRepo.GetUserByID(ctx, userID)
+		if err != nil {
+			log.Error().Err(err).Int64("user_id", userID).Msg("[SSOService-resolveUser] Failed to get linked user")
+			return 0, err
+		}
+		if !s.isStaffRole(user.RoleName) {
+			return 0, s.refuse(ctx, user, identity, "sso email belongs to a non-staff account")
+		}
+		return userID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	// Linking by email is only safe when the provider vouches for the address; a token without
+	// email_verified says nothing about who owns the mailbox
+	if identity.Email == "" || identity.EmailVerified == nil || !*identity.EmailVerified {
+		log.Warn().Str("subject", identity.Subject).Msg("[SSOService-resolveUser] Provider did not return a verified email")
+		return 0, errors.New("sso email is not verified")
+	}
+	email, err := s.emailPolicy.NormalizeEmail(identity.Email)
+	if err != nil {
+		return 0, errors.New("sso email is not verified")
+	}
+
+	existing, err := s.userRepo.GetUserByEmailIncludingUnverified(ctx, email)
+	switch {
+	case err == nil:
+		if !s.isStaffRole(existing.RoleName) {
+			return 0, s.refuse(ctx, existing, identity, "sso email belongs to a non-staff account")
+		}
+		if err := s.ssoRepo.LinkSubject(ctx, existing.ID, identity.Subject); err != nil {
+			return 0, err
+		}
+		log.Info().Int64("user_id", existing.ID).Str("subject", identity.Subject).Msg("[SSOService-resolveUser] Staff account linked")
+		return existing.ID, nil
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		log.Error().Err(err).Str("email", email).Msg("[SSOService-resolveUser] Failed to look up account")
+		return 0, err
+	}
+
+	if !s.jit {
+		log.Warn().Str("email", email).Msg("[SSOService-resolveUser] No account and JIT provisioning is off")
+		return 0, errors.New("sso account not provisioned")
+	}
+	return s.provision(ctx, identity, email, role)
+}
+
+func (s *SSOService) provision(ctx context.Context, identity *entity.OIDCIdentityEntity, email, role string) (int64, error) {
+	// Staff provisioned here sign in through the provider; the random password only exists
+	// so the column is never empty, and can be replaced through the reset flow
+	password, err := randomHex(24)
+	if err != nil {
+		return 0, err
+	}
+	hashedPassword, err := s.passwordHasher.HashPassword(password)
+	if err != nil {
+		log.Error().Err(err).Str("email", email).Msg("[SSOService-provision] Failed to hash password")
+		return 0, err
+	}
+
+	name := identity.Name
+	if name == "" {
+		name = email
+	}
+	userID, err := s.ssoRepo.CreateUser(ctx, &entity.UserEntity{
+		Name:     name,
+		Email:    email,
+		Language: i18n.FromContext(ctx),
+	}, hashedPassword, role, identity.Subject)
+	if err != nil {
+		return 0, err
+	}
+
+	s.record(ctx, entity.AuditEventSSOUserProvisioned, userID, entity.ClientEntity{}, map[string]interface{}{
+		"subject": identity.Subject,
+		"email":   email,
+		"role":    role,
+	})
+	log.Info().Int64("user_id", userID).Str("role", role).Msg("[SSOService-provision] Staff account provisioned")
+	return userID, nil
+}
+
+// syncRole gives the account the role its groups map to. The old sessions carry the old role,
+// so they end.
+func (s *SSOService) syncRole(ctx context.Context, userID int64, role, subject string) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[SSOService-syncRole] Failed to get user")
+		return err
+	}
+	if user.RoleName == role {
+		return nil
+	}
+
+	target, err := s.userRepo.GetRoleByName(ctx, role)
+	if err != nil {
+		log.Error().Err(err).Str("role", role).Msg("[SSOService-syncRole] Mapped role not found")
+		return err
+	}
+	if err := s.userRepo.UpdateUserRole(ctx, userID, target.ID); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[SSOService-syncRole] Failed to change role")
+		return err
+	}
+	if err := s.sessionRepo.DeleteAllUserTokens(ctx, userID); err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[SSOService-syncRole] Failed to revoke sessions")
+	}
+
+	s.record(ctx, entity.AuditEventSSORoleChanged, userID, entity.ClientEntity{}, map[string]interface{}{
+		"subject": subject,
+		"from":    user.RoleName,
+		"to":      role,
+	})
+	log.Info().Int64("user_id", userID).Str("from", user.RoleName).Str("to", role).Msg("[SSOService-syncRole] Role changed from provider groups")
+	return nil
+}
+
+// roleFor returns the role of the first mapping whose group the user is in
+func (s *SSOService) roleFor(groups []string) string {
+	for _, mapping := range s.groupRoles {
+		for _, group := range groups {
+			if group == mapping.Group {
+				return mapping.Role
+			}
+		}
+	}
+	return ""
+}
+
+// isStaffRole reports whether SSO manages the role; customers and vendors never sign in through SSO
+func (s *SSOService) isStaffRole(roleName string) bool {
+	for _, mapping := range s.groupRoles {
+		if mapping.Role == roleName {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SSOService) refuse(ctx context.Context, user *entity.UserEntity, identity *entity.OIDCIdentityEntity, reason string) error {
+	log.Warn().Int64("user_id", user.ID).Str("role", user.RoleName).Str("subject", identity.Subject).Str("reason", reason).Msg("[SSOService-refuse] SSO sign-in refused")
+	s.record(ctx, entity.AuditEventSSOSignInRefused, user.ID, entity.ClientEntity{}, map[string]interface{}{
+		"subject": identity.Subject,
+		"reason":  reason,
+	})
+	return errors.New(reason)
+}
+
+func (s *SSOService) record(ctx context.Context, event string, userID int64, client entity.ClientEntity, metadata map[string]interface{}) {
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{
+		UserID:    userID,
+		Event:     event,
+		IPAddress: client.IPAddress,
+		UserAgent: client.UserAgent,
+		Metadata:  metadata,
+	})
+}
+
+// pkceChallenge is the S256 code challenge for verifier (RFC 7636)
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func NewSSOService(provider port.OIDCProviderInterface, stateRepo port.SSOStateRepositoryInterface, ssoRepo port.SSORepositoryInterface, userRepo port.UserRepositoryInterface, sessionRepo port.SessionInterface, signIn port.ExternalSignInInterface, auditLogService port.AuditLogServiceInterface, cfg *config.Config) port.SSOServiceInterface {
+	return &SSOService{
+		provider:        provider,
+		stateRepo:       stateRepo,
+		ssoRepo:         ssoRepo,
+		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		signIn:          signIn,
+		auditLogService: auditLogService,
+		emailPolicy:     EmailPolicyFromConfig(cfg),
+		passwordHasher:  PasswordHasherFromConfig(cfg),
+		groupRoles:      cfg.SSO.GroupRoles,
+		jit:             cfg.SSO.JITProvisioning,
+		stateTTL:        cfg.SSO.StateTTL(),
+	}
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// profilePhotoPrefix is how the storage adapter names profile photos and avatars; KYC
+	// documents and chunked uploads live under their own folders and are never reconciled
+	profilePhotoPrefix = "profile-"
+	// orphanPhotoGrace gives an upload time to be saved on the profile before it counts as orphaned
+	orphanPhotoGrace = 15 * time.Minute
+)
+
+type StorageEventService struct {
+	userRepo   port.UserRepositoryInterface
+	objects    port.StoredObjectRepositoryInterface
+	jobService port.JobServiceInterface
+	storage    port.StorageInterface
+	projectURL string
+	bucket     string
+}
+
+func (s *StorageEventService) HandleEvent(ctx context.Context, event entity.StorageEventEntity) error {
+	// Other buckets in the project are not ours to reconcile
+	if event.Bucket != s.bucket || event.ObjectName == "" {
+		return nil
+	}
+
+	switch event.Type {
+	case entity.StorageEventDeleted:
+		// Otherwise an identical upload would reuse the record and point at the deleted object
+		if err := s.objects.ForgetObject(ctx, event.Bucket, event.ObjectName); err != nil {
+			return errors.New("failed to forget stored object")
+		}
+
+		photoURL := s.publicURL(event.Bucket, event.ObjectName)
+		userIDs, err := s.userRepo.ClearPhotoByURL(ctx, photoURL)
+		if err != nil {
+			return errors.New("failed to clear photo references")
+		}
+		if len(userIDs) > 0 {
+			log.Warn().Str("object", event.ObjectName).Ints64("user_ids", userIDs).Msg("[StorageEventService-HandleEvent] Photo deleted from the bucket, cleared from profiles")
+		}
+		return nil
+	case entity.StorageEventCreated:
+		if !strings.HasPrefix(event.ObjectName, profilePhotoPrefix) {
+			return nil
+		}
+		payload := entity.ReconcileProfilePhotoJobPayload{Bucket: event.Bucket, ObjectName: event.ObjectName}
+		if _, err := s.jobService.Enqueue(ctx, entity.JobTypeReconcileProfilePhoto, payload, time.Now().Add(orphanPhotoGrace)); err != nil {
+			log.Error().Err(err).Str("object", event.ObjectName).Msg("[StorageEventService-HandleEvent] Failed to queue profile photo reconcile")
+			return errors.New("failed to queue profile photo reconcile")
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// ReconcileProfilePhoto removes a profile photo that was uploaded but never saved on a
+// profile, e.g. when the upload request failed after storing the file
+func (s *StorageEventService) ReconcileProfilePhoto(ctx context.Context, bucket, objectName string) error {
+	photoURL := s.publicURL(bucket, objectName)
+	referenced, err := s.userRepo.IsPhotoReferenced(ctx, photoURL)
+	if err != nil {
+		return err
+	}
+	if referenced || s.storage == nil {
+		return nil
+	}
+
+	if err := s.storage.DeleteFile(ctx, bucket, objectName); err != nil {
+		log.Error().Err(err).Str("object", objectName).Msg("[StorageEventService-ReconcileProfilePhoto] Failed to delete orphaned photo")
+		return err
+	}
+
+	log.Info().Str("object", objectName).Msg("[StorageEventService-ReconcileProfilePhoto] Orphaned profile photo deleted")
+	return nil
+}
+
+// publicURL matches what the storage adapter stores in users.photo
+func (s *StorageEventService) publicURL(bucket, objectName string) string {
+	return fmt.Sprintf("%s/storage/v1/object/public/%s/%s", s.projectURL, bucket, objectName)
+}
+
+func NewStorageEventService(userRepo port.UserRepositoryInterface, objects port.StoredObjectRepositoryInterface, jobService port.JobServiceInterface, storage port.StorageInterface, cfg *config.Config) port.StorageEventServiceInterface {
+	return &StorageEventService{
+		userRepo:   userRepo,
+		objects:    objects,
+		jobService: jobService,
+		storage:    storage,
+		projectURL: strings.TrimSuffix(cfg.Supabase.ProjectURL, "/"),
+		bucket:     cfg.Supabase.BucketName,
+	}
+}
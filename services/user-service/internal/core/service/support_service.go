@@ -0,0 +1,361 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// supportAttachmentLinkTTL is how long a link shown in the app or admin panel works
+	supportAttachmentLinkTTL = 10 * time.Minute
+	// supportWebhookLinkTTL outlives the webhook retries, so a late delivery still has working links
+	supportWebhookLinkTTL = 24 * time.Hour
+
+	supportSubjectMaxLength = 150
+	supportMessageMaxLength = 5000
+	supportNoteMaxLength    = 1000
+)
+
+// supportStaffRoles may be assigned tickets; they are the roles the admin routes accept
+var supportStaffRoles = map[string]bool{
+	"Super Admin": true,
+}
+
+// supportContentTypes maps the sniffed content type to the stored extension
+var supportContentTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+type SupportService struct {
+	supportRepo     port.SupportRepositoryInterface
+	userRepo        port.UserRepositoryInterface
+	storage         port.StorageInterface
+	emailPublisher  port.EmailInterface
+	webhooks        port.WebhookDispatcherInterface
+	auditLogService port.AuditLogServiceInterface
+	config          config.Support
+}
+
+func (s *SupportService) CreateTicket(ctx context.Context, userID int64, category, subject, message string, uploads []entity.SupportUploadEntity) (*entity.SupportTicketEntity, error) {
+	category = strings.ToLower(strings.TrimSpace(category))
+	if !entity.IsSupportTicketCategory(category) {
+		return nil, fmt.Errorf("category must be one of %s", strings.Join(entity.SupportTicketCategories, ", "))
+	}
+	subject, message = strings.TrimSpace(subject), strings.TrimSpace(message)
+	if subject == "" || len(subject) > supportSubjectMaxLength {
+		return nil, errors.New("subject is required and must be at most 150 characters")
+	}
+	if message == "" || len(message) > supportMessageMaxLength {
+		return nil, errors.New("message is required and must be at most 5000 characters")
+	}
+	if len(uploads) > s.config.AttachmentLimit() {
+		return nil, errors.New("too many attachments")
+	}
+
+	attachments := make([]entity.SupportAttachmentEntity, 0, len(uploads))
+	for _, upload := range uploads {
+		if len(upload.Data) == 0 {
+			return nil, errors.New("attachment is empty")
+		}
+		if int64(len(upload.Data)) > s.config.MaxSize() {
+			return nil, errors.New("attachment exceeds the size limit")
+		}
+		contentType := http.DetectContentType(upload.Data)
+		ext, ok := supportContentTypes[contentType]
+		if !ok {
+			return nil, errors.New("attachment must be a JPEG, PNG, WebP or PDF file")
+		}
+		attachments = append(attachments, entity.SupportAttachmentEntity{
+			ObjectName:  utils.ContentObjectName(fmt.Sprintf("%d/", userID), upload.Data, ext),
+			FileName:    path.Base(upload.FileName),
+			ContentType: contentType,
+			Size:        int64(len(upload.Data)),
+		})
+	}
+
+	if len(attachments) > 0 && s.storage == nil {
+		log.Error().Int64("user_id", userID).Msg("[SupportService-CreateTicket] Storage is not configured")
+		return nil, errors.New("storage service unavailable")
+	}
+
+	bucket := s.config.BucketName()
+	for i, attachment := range attachments {
+		if _, err := s.storage.UploadFile(ctx, bucket, attachment.ObjectName, bytes.NewReader(uploads[i].Data), attachment.ContentType); err != nil {
+			log.Error().Err(err).Int64("user_id", userID).Msg("[SupportService-CreateTicket] Failed to upload attachment")
+			s.deleteAttachments(ctx, attachments[:i])
+			if errors.Is(err, port.ErrFileInfected) || errors.Is(err, port.ErrScannerUnavailable) {
+				return nil, err
+			}
+			return nil, errors.New("failed to upload attachment")
+		}
+	}
+
+	ticket, err := s.supportRepo.Create(ctx, &entity.SupportTicketEntity{
+		UserID:      userID,
+		Category:    category,
+		Subject:     subject,
+		Message:     message,
+		Attachments: attachments,
+	})
+	if err != nil {
+		s.deleteAttachments(ctx, attachments)
+		return nil, errors.New("failed to create support ticket")
+	}
+
+	log.Info().Int64("user_id", userID).Int64("ticket_id", ticket.ID).Str("category", category).Int("attachments", len(attachments)).Msg("[SupportService-CreateTicket] Support ticket created")
+	s.dispatchWebhook(ctx, entity.WebhookEventSupportTicketCreated, fmt.Sprintf("%s:%d", entity.WebhookEventSupportTicketCreated, ticket.ID), ticket)
+	return ticket, nil
+}
+
+func (s *SupportService) GetMyTickets(ctx context.Context, userID int64, page, limit int) ([]entity.SupportTicketEntity, *entity.PaginationEntity, error) {
+	page, limit = normalizePage(page, limit)
+	tickets, totalCount, err := s.supportRepo.ListByUser(ctx, userID, page, limit)
+	if err != nil {
+		return nil, nil, errors.New("failed to retrieve support tickets")
+	}
+
+	return tickets, newPagination(page, limit, totalCount), nil
+}
+
+func (s *SupportService) GetMyTicket(ctx context.Context, userID, id int64) (*entity.SupportTicketEntity, error) {
+	ticket, err := s.getTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	// Another customer's ticket looks the same as one that does not exist
+	if ticket.UserID != userID {
+		return nil, errors.New("support ticket not found")
+	}
+
+	s.signAttachments(ctx, ticket, supportAttachmentLinkTTL)
+	return ticket, nil
+}
+
+func (s *SupportService) GetTickets(ctx context.Context, filter entity.SupportTicketFilter, page, limit int) ([]entity.SupportTicketEntity, *entity.PaginationEntity, error) {
+	page, limit = normalizePage(page, limit)
+	tickets, totalCount, err := s.supportRepo.List(ctx, filter, page, limit)
+	if err != nil {
+		return nil, nil, errors.New("failed to retrieve support tickets")
+	}
+
+	return tickets, newPagination(page, limit, totalCount), nil
+}
+
+func (s *SupportService) GetTicket(ctx context.Context, id int64) (*entity.SupportTicketEntity, error) {
+	ticket, err := s.getTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.signAttachments(ctx, ticket, supportAttachmentLinkTTL)
+	return ticket, nil
+}
+
+func (s *SupportService) Assign(ctx context.Context, id, assigneeID, adminID int64) (*entity.SupportTicketEntity, error) {
+	if _, err := s.getTicket(ctx, id); err != nil {
+		return nil, err
+	}
+
+	assignee, err := s.userRepo.GetUserByID(ctx, assigneeID)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("assignee not found")
+		}
+		return nil, errors.New("failed to assign support ticket")
+	}
+	if !supportStaffRoles[assignee.RoleName] {
+		return nil, errors.New("assignee must be a staff member")
+	}
+
+	if err := s.supportRepo.Assign(ctx, id, assigneeID); err != nil {
+		return nil, errors.New("failed to assign support ticket")
+	}
+
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{
+		UserID: adminID,
+		Event:  entity.AuditEventSupportTicketAssigned,
+		Metadata: map[string]interface{}{
+			"ticket_id":   id,
+			"assignee_id": assigneeID,
+		},
+	})
+
+	ticket, err := s.getTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Int64("ticket_id", id).Int64("assignee_id", assigneeID).Int64("admin_id", adminID).Msg("[SupportService-Assign] Support ticket assigned")
+	s.dispatchWebhook(ctx, entity.WebhookEventSupportTicketUpdated, supportUpdateEventID(ticket), ticket)
+	return ticket, nil
+}
+
+func (s *SupportService) UpdateStatus(ctx context.Context, id int64, status, note string, adminID int64) (*entity.SupportTicketEntity, error) {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if !entity.IsSupportTicketStatus(status) {
+		return nil, fmt.Errorf("status must be one of %s", strings.Join(entity.SupportTicketStatuses, ", "))
+	}
+	note = strings.TrimSpace(note)
+	if len(note) > supportNoteMaxLength {
+		return nil, errors.New("note must be at most 1000 characters")
+	}
+
+	current, err := s.getTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status == status {
+		return nil, errors.New("support ticket already has this status")
+	}
+
+	if err := s.supportRepo.UpdateStatus(ctx, id, status); err != nil {
+		return nil, errors.New("failed to update support ticket")
+	}
+
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{
+		UserID: adminID,
+		Event:  entity.AuditEventSupportTicketStatus,
+		Metadata: map[string]interface{}{
+			"ticket_id": id,
+			"from":      current.Status,
+			"to":        status,
+		},
+	})
+
+	ticket, err := s.getTicket(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info().Int64("ticket_id", id).Int64("admin_id", adminID).Str("from", current.Status).Str("to", status).Msg("[SupportService-UpdateStatus] Support ticket status updated")
+	s.notifyCustomer(ctx, ticket, note)
+	s.dispatchWebhook(ctx, entity.WebhookEventSupportTicketUpdated, supportUpdateEventID(ticket), ticket)
+	return ticket, nil
+}
+
+// notifyCustomer emails the new status; the update itself already succeeded, so a failure is only logged
+func (s *SupportService) notifyCustomer(ctx context.Context, ticket *entity.SupportTicketEntity, note string) {
+	if s.emailPublisher == nil {
+		return
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, ticket.UserID)
+	if err != nil {
+		log.Warn().Err(err).Int64("ticket_id", ticket.ID).Msg("[SupportService-notifyCustomer] Failed to load ticket owner")
+		return
+	}
+	if err := s.emailPublisher.SendSupportTicketUpdateEmail(ctx, user.Email, user.Name, ticket, note); err != nil {
+		log.Error().Err(err).Int64("ticket_id", ticket.ID).Msg("[SupportService-notifyCustomer] Failed to send support ticket update email")
+	}
+}
+
+// dispatchWebhook forwards the ticket to subscribed integrations, such as an external helpdesk.
+// A failed dispatch never fails the request.
+func (s *SupportService) dispatchWebhook(ctx context.Context, eventType, eventID string, ticket *entity.SupportTicketEntity) {
+	if s.webhooks == nil {
+		return
+	}
+
+	s.signAttachments(ctx, ticket, supportWebhookLinkTTL)
+	attachments := make([]map[string]interface{}, 0, len(ticket.Attachments))
+	for _, attachment := range ticket.Attachments {
+		attachments = append(attachments, map[string]interface{}{
+			"file_name":    attachment.FileName,
+			"content_type": attachment.ContentType,
+			"size":         attachment.Size,
+			"url":          attachment.URL,
+		})
+	}
+
+	data := map[string]interface{}{
+		"ticket_id":   ticket.ID,
+		"user_id":     ticket.UserID,
+		"category":    ticket.Category,
+		"subject":     ticket.Subject,
+		"message":     ticket.Message,
+		"status":      ticket.Status,
+		"assignee_id": ticket.AssigneeID,
+		"attachments": attachments,
+		"created_at":  ticket.CreatedAt,
+		"updated_at":  ticket.UpdatedAt,
+	}
+	if user, err := s.userRepo.GetUserByID(ctx, ticket.UserID); err == nil {
+		data["email"] = user.Email
+		data["name"] = user.Name
+	}
+
+	if err := s.webhooks.Dispatch(ctx, eventType, eventID, data); err != nil {
+		log.Error().Err(err).Int64("ticket_id", ticket.ID).Str("event", eventType).Msg("[SupportService-dispatchWebhook] Failed to dispatch webhook")
+	}
+}
+
+// signAttachments fills the attachment links; without them the ticket is still readable
+func (s *SupportService) signAttachments(ctx context.Context, ticket *entity.SupportTicketEntity, ttl time.Duration) {
+	if len(ticket.Attachments) == 0 {
+		return
+	}
+	signer, ok := s.storage.(port.SignedURLInterface)
+	if !ok {
+		log.Warn().Int64("ticket_id", ticket.ID).Msg("[SupportService-signAttachments] Storage cannot sign attachment links")
+		return
+	}
+	for i := range ticket.Attachments {
+		url, err := signer.SignedURL(ctx, s.config.BucketName(), ticket.Attachments[i].ObjectName, ttl)
+		if err != nil {
+			log.Error().Err(err).Int64("ticket_id", ticket.ID).Msg("[SupportService-signAttachments] Failed to sign attachment link")
+			continue
+		}
+		ticket.Attachments[i].URL = url
+	}
+}
+
+func (s *SupportService) deleteAttachments(ctx context.Context, attachments []entity.SupportAttachmentEntity) {
+	for _, attachment := range attachments {
+		if err := s.storage.DeleteFile(ctx, s.config.BucketName(), attachment.ObjectName); err != nil {
+			log.Error().Err(err).Str("object_name", attachment.ObjectName).Msg("[SupportService-deleteAttachments] Failed to delete attachment")
+		}
+	}
+}
+
+func (s *SupportService) getTicket(ctx context.Context, id int64) (*entity.SupportTicketEntity, error) {
+	ticket, err := s.supportRepo.GetByID(ctx, id)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("support ticket not found")
+		}
+		return nil, errors.New("failed to retrieve support ticket")
+	}
+	return ticket, nil
+}
+
+// supportUpdateEventID is unique per change, since every change moves updated_at
+func supportUpdateEventID(ticket *entity.SupportTicketEntity) string {
+	return fmt.Sprintf("%s:%d:%d", entity.WebhookEventSupportTicketUpdated, ticket.ID, ticket.UpdatedAt.UnixNano())
+}
+
+func NewSupportService(supportRepo port.SupportRepositoryInterface, userRepo port.UserRepositoryInterface, storage port.StorageInterface, emailPublisher port.EmailInterface, webhooks port.WebhookDispatcherInterface, auditLogService port.AuditLogServiceInterface, cfg *config.Config) port.SupportServiceInterface {
+	return &SupportService{
+		supportRepo:     supportRepo,
+		userRepo:        userRepo,
+		storage:         storage,
+		emailPublisher:  emailPublisher,
+		webhooks:        webhooks,
+		auditLogService: auditLogService,
+		config:          cfg.Support,
+	}
+}
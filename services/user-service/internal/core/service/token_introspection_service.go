@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+type TokenIntrospectionService struct {
+	jwtUtil       port.JWTInterface
+	sessionRepo   port.SessionInterface
+	blacklistRepo port.BlacklistTokenInterface
+	passwords     port.PasswordExpiryCheckerInterface
+}
+
+func (s *TokenIntrospectionService) Introspect(ctx context.Context, token string) (*entity.TokenIntrospectionEntity, error) {
+	token = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(token), "Bearer "))
+	if token == "" {
+		return nil, errors.New("token is required")
+	}
+
+	claims, err := s.jwtUtil.ValidateJWT(token)
+	if err != nil {
+		return &entity.TokenIntrospectionEntity{Reason: entity.TokenInactiveInvalid}, nil
+	}
+
+	if s.blacklistRepo != nil {
+		hash := sha256.Sum256([]byte(token))
+		if s.blacklistRepo.IsTokenBlacklisted(ctx, hex.EncodeToString(hash[:])) {
+			return &entity.TokenIntrospectionEntity{Reason: entity.TokenInactiveRevoked, UserID: claims.UserID}, nil
+		}
+	}
+
+	// Tokens issued before sessions existed carry no session_id; JWTMiddleware still accepts them
+	if claims.SessionID != "" && !s.sessionRepo.ValidateToken(ctx, claims.UserID, claims.SessionID, token) {
+		return &entity.TokenIntrospectionEntity{Reason: entity.TokenInactiveNoSession, UserID: claims.UserID}, nil
+	}
+
+	// Other services must stop accepting the session exactly when JWTMiddleware does
+	if s.passwords != nil && s.passwords.PasswordExpired(ctx, claims.UserID, claims.SessionID, claims.RoleName) {
+		return &entity.TokenIntrospectionEntity{Reason: entity.TokenInactivePasswordExpired, UserID: claims.UserID}, nil
+	}
+
+	result := &entity.TokenIntrospectionEntity{
+		Active:    true,
+		UserID:    claims.UserID,
+		Email:     claims.Email,
+		RoleName:  claims.RoleName,
+		SessionID: claims.SessionID,
+		Issuer:    claims.Issuer,
+	}
+	if claims.IssuedAt != nil {
+		result.IssuedAt = claims.IssuedAt.Time
+	}
+	if claims.ExpiresAt != nil {
+		result.ExpiresAt = claims.ExpiresAt.Time
+	}
+
+	log.Debug().Int64("user_id", claims.UserID).Str("session_id", claims.SessionID).Msg("[TokenIntrospectionService-Introspect] Token is active")
+	return result, nil
+}
+
+func NewTokenIntrospectionService(jwtUtil port.JWTInterface, sessionRepo port.SessionInterface, blacklistRepo port.BlacklistTokenInterface, passwords port.PasswordExpiryCheckerInterface) port.TokenIntrospectionServiceInterface {
+	return &TokenIntrospectionService{
+		jwtUtil:       jwtUtil,
+		sessionRepo:   sessionRepo,
+		blacklistRepo: blacklistRepo,
+		passwords:     passwords,
+	}
+}
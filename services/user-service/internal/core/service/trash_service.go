@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+type TrashService struct {
+	trashRepo       port.TrashRepositoryInterface
+	auditLogService port.AuditLogServiceInterface
+}
+
+func (s *TrashService) GetDeletedUsers(ctx context.Context, search string, page, limit int) ([]entity.TrashedUserEntity, *entity.PaginationEntity, error) {
+	page, limit = normalizePage(page, limit)
+
+	users, totalCount, err := s.trashRepo.GetDeletedUsers(ctx, search, page, limit)
+	if err != nil {
+		log.Error().Err(err).Str("search", search).Msg("[TrashService-GetDeletedUsers] Failed to get deleted users")
+		return nil, nil, errors.New("failed to get deleted users")
+	}
+
+	return users, newPagination(page, limit, totalCount), nil
+}
+
+func (s *TrashService) RestoreUser(ctx context.Context, userID, adminID int64) (*entity.TrashedUserEntity, error) {
+	if userID <= 0 {
+		return nil, errors.New("invalid user id")
+	}
+
+	user, err := s.trashRepo.RestoreUser(ctx, userID)
+	if err != nil {
+		switch err.Error() {
+		case "user not found", "email is used by another account":
+			return nil, err
+		}
+		log.Error().Err(err).Int64("user_id", userID).Msg("[TrashService-RestoreUser] Failed to restore user")
+		return nil, errors.New("failed to restore user")
+	}
+
+	s.record(ctx, entity.AuditEventRecordRestored, adminID, entity.TrashRecordUser, userID, map[string]interface{}{
+		"email":          user.Email,
+		"merged_into_id": user.MergedIntoID,
+	})
+
+	log.Info().Int64("user_id", userID).Int64("admin_id", adminID).Msg("[TrashService-RestoreUser] User restored")
+	return user, nil
+}
+
+func (s *TrashService) PurgeUser(ctx context.Context, userID, adminID int64) error {
+	if userID <= 0 {
+		return errors.New("invalid user id")
+	}
+
+	user, err := s.trashRepo.PurgeUser(ctx, userID)
+	if err != nil {
+		switch err.Error() {
+		case "user not found", "user has ledger history":
+			return err
+		default:
+			log.Error().Err(err).Int64("user_id", userID).Msg("[TrashService-PurgeUser] Failed to purge user")
+			return errors.New("failed to purge user")
+		}
+	}
+
+	// The row is gone, so the audit entry keeps the identifying fields in its metadata
+	s.record(ctx, entity.AuditEventRecordPurged, adminID, entity.TrashRecordUser, userID, map[string]interface{}{
+		"email": user.Email,
+		"name":  user.Name,
+	})
+
+	log.Info().Int64("user_id", userID).Int64("admin_id", adminID).Msg("[TrashService-PurgeUser] User purged")
+	return nil
+}
+
+func (s *TrashService) GetDeletedRoles(ctx context.Context, search string, page, limit int) ([]entity.TrashedRoleEntity, *entity.PaginationEntity, error) {
+	page, limit = normalizePage(page, limit)
+
+	roles, totalCount, err := s.trashRepo.GetDeletedRoles(ctx, search, page, limit)
+	if err != nil {
+		log.Error().Err(err).Str("search", search).Msg("[TrashService-GetDeletedRoles] Failed to get deleted roles")
+		return nil, nil, errors.New("failed to get deleted roles")
+	}
+
+	return roles, newPagination(page, limit, totalCount), nil
+}
+
+func (s *TrashService) RestoreRole(ctx context.Context, roleID, adminID int64) (*entity.TrashedRoleEntity, error) {
+	if roleID <= 0 {
+		return nil, errors.New("invalid role id")
+	}
+
+	role, err := s.trashRepo.RestoreRole(ctx, roleID)
+	if err != nil {
+		if err.Error() == "role not found" {
+			return nil, err
+		}
+		log.Error().Err(err).Int64("role_id", roleID).Msg("[TrashService-RestoreRole] Failed to restore role")
+		return nil, errors.New("failed to restore role")
+	}
+
+	s.record(ctx, entity.AuditEventRecordRestored, adminID, entity.TrashRecordRole, roleID, map[string]interface{}{
+		"name": role.Name,
+	})
+
+	log.Info().Int64("role_id", roleID).Int64("admin_id", adminID).Msg("[TrashService-RestoreRole] Role restored")
+	return role, nil
+}
+
+func (s *TrashService) PurgeRole(ctx context.Context, roleID, adminID int64) error {
+	if roleID <= 0 {
+		return errors.New("invalid role id")
+	}
+
+	role, err := s.trashRepo.PurgeRole(ctx, roleID)
+	if err != nil {
+		if err.Error() == "role not found" {
+			return err
+		}
+		log.Error().Err(err).Int64("role_id", roleID).Msg("[TrashService-PurgeRole] Failed to purge role")
+		return errors.New("failed to purge role")
+	}
+
+	s.record(ctx, entity.AuditEventRecordPurged, adminID, entity.TrashRecordRole, roleID, map[string]interface{}{
+		"name": role.Name,
+	})
+
+	log.Info().Int64("role_id", roleID).Int64("admin_id", adminID).Msg("[TrashService-PurgeRole] Role purged")
+	return nil
+}
+
+// record files the entry under the acting admin; a purged user can no longer be referenced by id
+func (s *TrashService) record(ctx context.Context, event string, adminID int64, recordType string, recordID int64, metadata map[string]interface{}) {
+	metadata["record_type"] = recordType
+	metadata["record_id"] = recordID
+
+	s.auditLogService.Record(ctx, entity.AuditLogEntity{
+		UserID:   adminID,
+		Event:    event,
+		Metadata: metadata,
+	})
+}
+
+func NewTrashService(trashRepo port.TrashRepositoryInterface, auditLogService port.AuditLogServiceInterface) port.TrashServiceInterface {
+	return &TrashService{
+		trashRepo:       trashRepo,
+		auditLogService: auditLogService,
+	}
+}
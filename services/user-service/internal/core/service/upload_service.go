@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+
+	"github.com/rs/zerolog/log"
+)
+
+var checksumPattern = regexp.MustCompile(`^[a-f0-9]{64}$`)
+
+// uploadContentTypes are what resumable uploads are for: gallery images and KYC documents
+var uploadContentTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+}
+
+type UploadService struct {
+	chunkStore port.ChunkStoreInterface
+	storage    port.StorageInterface
+	chunkSize  int64
+	maxSize    int64
+	ttl        time.Duration
+}
+
+func (s *UploadService) CreateUpload(ctx context.Context, userID int64, filename, contentType string, size int64, checksum string) (*entity.UploadSessionEntity, error) {
+	checksum = strings.ToLower(strings.TrimSpace(checksum))
+	filename = filepath.Base(strings.TrimSpace(filename))
+
+	switch {
+	case filename == "." || filename == "/":
+		return nil, errors.New("filename is required")
+	case uploadContentTypes[contentType] == "":
+		return nil, errors.New("unsupported content type")
+	case size <= 0:
+		return nil, errors.New("file size must be greater than zero")
+	case size > s.maxSize:
+		return nil, fmt.Errorf("file size must not exceed %d bytes", s.maxSize)
+	case !checksumPattern.MatchString(checksum):
+		return nil, errors.New("checksum must be a hex encoded SHA-256")
+	}
+
+	uploadID, err := randomHex(16)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[UploadService-CreateUpload] Failed to generate upload id")
+		return nil, errors.New("failed to create upload")
+	}
+
+	now := time.Now()
+	session := &entity.UploadSessionEntity{
+		ID:             uploadID,
+		UserID:         userID,
+		Filename:       filename,
+		ContentType:    contentType,
+		Size:           size,
+		Checksum:       checksum,
+		ChunkSize:      s.chunkSize,
+		TotalChunks:    int((size + s.chunkSize - 1) / s.chunkSize),
+		CreatedAt:      now,
+		ExpiresAt:      now.Add(s.ttl),
+		ReceivedChunks: []int{},
+	}
+
+	if err := s.chunkStore.CreateSession(ctx, session); err != nil {
+		return nil, errors.New("failed to create upload")
+	}
+
+	log.Info().Int64("user_id", userID).Str("upload_id", uploadID).Int64("size", size).Int("chunks", session.TotalChunks).Msg("[UploadService-CreateUpload] Upload started")
+	return session, nil
+}
+
+func (s *UploadService) GetUpload(ctx context.Context, userID int64, uploadID string) (*entity.UploadSessionEntity, error) {
+	return s.ownedSession(ctx, userID, uploadID)
+}
+
+func (s *UploadService) UploadChunk(ctx context.Context, userID int64, uploadID string, index int, data io.Reader, checksum string) (*entity.UploadSessionEntity, error) {
+	session, err := s.ownedSession(ctx, userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if index < 0 || index >= session.TotalChunks {
+		return nil, errors.New("chunk index out of range")
+	}
+
+	expected := session.ChunkLength(index)
+	hash := sha256.New()
+	written, err := s.chunkStore.WriteChunk(ctx, uploadID, index, io.TeeReader(data, hash), expected)
+	if err != nil {
+		log.Error().Err(err).Str("upload_id", uploadID).Int("index", index).Msg("[UploadService-UploadChunk] Failed to store chunk")
+		return nil, errors.New("failed to store chunk")
+	}
+
+	reject := func(message string) (*entity.UploadSessionEntity, error) {
+		if err := s.chunkStore.DeleteChunk(ctx, uploadID, index); err != nil {
+			log.Warn().Err(err).Str("upload_id", uploadID).Int("index", index).Msg("[UploadService-UploadChunk] Failed to delete rejected chunk")
+		}
+		return nil, errors.New(message)
+	}
+
+	if written != expected {
+		log.Warn().Str("upload_id", uploadID).Int("index", index).Int64("expected", expected).Int64("received", written).Msg("[UploadService-UploadChunk] Chunk size mismatch")
+		return reject(fmt.Sprintf("chunk %d must be exactly %d bytes", index, expected))
+	}
+
+	if checksum != "" && !strings.EqualFold(checksum, hex.EncodeToString(hash.Sum(nil))) {
+		log.Warn().Str("upload_id", uploadID).Int("index", index).Msg("[UploadService-UploadChunk] Chunk checksum mismatch")
+		return reject("chunk checksum mismatch")
+	}
+
+	return s.chunkStore.GetSession(ctx, uploadID)
+}
+
+// CompleteUpload verifies the whole file before it reaches storage, then drops the chunks.
+// A checksum mismatch discards the upload, since there is no telling which chunk was bad.
+func (s *UploadService) CompleteUpload(ctx context.Context, userID int64, uploadID string) (*entity.UploadResultEntity, error) {
+	session, err := s.ownedSession(ctx, userID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+
+	if missing := session.MissingChunks(); len(missing) > 0 {
+		return nil, fmt.Errorf("upload is incomplete, %d chunks missing", len(missing))
+	}
+
+	if s.storage == nil {
+		return nil, errors.New("storage is unavailable")
+	}
+
+	checksum, err := s.assembledChecksum(ctx, session)
+	if err != nil {
+		log.Error().Err(err).Str("upload_id", uploadID).Msg("[UploadService-CompleteUpload] Failed to read chunks")
+		return nil, errors.New("failed to assemble upload")
+	}
+
+	if checksum != session.Checksum {
+		log.Warn().Str("upload_id", uploadID).Str("expected", session.Checksum).Str("actual", checksum).Msg("[UploadService-CompleteUpload] Checksum mismatch, upload discarded")
+		s.deleteSession(ctx, uploadID)
+		return nil, errors.New("checksum mismatch")
+	}
+
+	assembled, err := s.chunkStore.OpenAssembled(ctx, uploadID, session.TotalChunks)
+	if err != nil {
+		log.Error().Err(err).Str("upload_id", uploadID).Msg("[UploadService-CompleteUpload] Failed to open chunks")
+		return nil, errors.New("failed to assemble upload")
+	}
+	defer assembled.Close()
+
+	// The checksum was verified above, so it names the object by its content
+	objectName := fmt.Sprintf("uploads/%d/%s%s", userID, session.Checksum, uploadContentTypes[session.ContentType])
+	url, err := s.storage.UploadFile(ctx, "", objectName, assembled, session.ContentType)
+	if err != nil {
+		// Chunks are kept, so the client can retry completing without uploading again
+		log.Error().Err(err).Str("upload_id", uploadID).Msg("[UploadService-CompleteUpload] Failed to upload to storage")
+		if errors.Is(err, port.ErrFileInfected) || errors.Is(err, port.ErrFileTooLarge) {
+			// The content will not change, so keeping the chunks for a retry is pointless
+			s.deleteSession(ctx, uploadID)
+			return nil, err
+		}
+		if errors.Is(err, port.ErrScannerUnavailable) {
+			return nil, err
+		}
+		return nil, errors.New("failed to upload file")
+	}
+
+	s.deleteSession(ctx, uploadID)
+
+	log.Info().Int64("user_id", userID).Str("upload_id", uploadID).Str("url", url).Int64("size", session.Size).Msg("[UploadService-CompleteUpload] Upload completed")
+	return &entity.UploadResultEntity{
+		URL:         url,
+		Filename:    session.Filename,
+		ContentType: session.ContentType,
+		Size:        session.Size,
+		Checksum:    session.Checksum,
+	}, nil
+}
+
+func (s *UploadService) AbortUpload(ctx context.Context, userID int64, uploadID string) error {
+	if _, err := s.ownedSession(ctx, userID, uploadID); err != nil {
+		return err
+	}
+
+	if err := s.chunkStore.DeleteSession(ctx, uploadID); err != nil {
+		return errors.New("failed to abort upload")
+	}
+
+	log.Info().Int64("user_id", userID).Str("upload_id", uploadID).Msg("[UploadService-AbortUpload] Upload aborted")
+	return nil
+}
+
+func (s *UploadService) CleanupExpired(ctx context.Context) (int, error) {
+	expired, err := s.chunkStore.ListExpired(ctx, time.Now())
+	if err != nil {
+		log.Error().Err(err).Msg("[UploadService-CleanupExpired] Failed to list expired uploads")
+		return 0, err
+	}
+
+	removed := 0
+	for _, uploadID := range expired {
+		if err := s.chunkStore.DeleteSession(ctx, uploadID); err != nil {
+			continue
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// ownedSession hides other users' and expired uploads behind "upload not found"
+func (s *UploadService) ownedSession(ctx context.Context, userID int64, uploadID string) (*entity.UploadSessionEntity, error) {
+	session, err := s.chunkStore.GetSession(ctx, uploadID)
+	if err != nil {
+		if err.Error() == "upload not found" {
+			return nil, err
+		}
+		return nil, errors.New("failed to get upload")
+	}
+
+	if session.UserID != userID || time.Now().After(session.ExpiresAt) {
+		return nil, errors.New("upload not found")
+	}
+	return session, nil
+}
+
+func (s *UploadService) assembledChecksum(ctx context.Context, session *entity.UploadSessionEntity) (string, error) {
+	assembled, err := s.chunkStore.OpenAssembled(ctx, session.ID, session.TotalChunks)
+	if err != nil {
+		return "", err
+	}
+	defer assembled.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, assembled)
+	if err != nil {
+		return "", err
+	}
+	if size != session.Size {
+		return "", fmt.Errorf("assembled %d bytes, expected %d", size, session.Size)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+func (s *UploadService) deleteSession(ctx context.Context, uploadID string) {
+	if err := s.chunkStore.DeleteSession(ctx, uploadID); err != nil {
+		log.Warn().Err(err).Str("upload_id", uploadID).Msg("[UploadService-deleteSession] Failed to delete chunks, cleanup will retry")
+	}
+}
+
+func NewUploadService(chunkStore port.ChunkStoreInterface, storage port.StorageInterface, cfg *config.Config) port.UploadServiceInterface {
+	return &UploadService{
+		chunkStore: chunkStore,
+		storage:    storage,
+		chunkSize:  cfg.Upload.ChunkSize(),
+		maxSize:    cfg.Upload.MaxSize(),
+		ttl:        cfg.Upload.TTL(),
+	}
+}
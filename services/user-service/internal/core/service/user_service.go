@@ -3,28 +3,177 @@ package service
 import (
 	"context"
 	"errors"
+	"strings"
 	"user-service/config"
 	"user-service/internal/core/domain/entity"
 	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/rs/zerolog/log"
 )
 
 var (
 	ErrInvalidEmail = errors.New("invalid email format")
 	ErrUserNotFound = errors.New("user not found")
+	// ErrEmailUndeliverable means a hard bounce was reported for the address
+	ErrEmailUndeliverable = errors.New("email address is undeliverable")
 )
 
+// MaxBatchUserIDs caps GetUsersByIDs so one internal call stays a single, bounded query
+const MaxBatchUserIDs = 500
+
 type UserService struct {
 	AuthServiceInterface
-	config *config.Config
+	invites port.InviteServiceInterface
+	legal   port.LegalServiceInterface
+	config  *config.Config
 }
 
 func (u *UserService) GetProfile(ctx context.Context, userID int64) (*entity.UserEntity, error) {
 	return u.AuthServiceInterface.GetProfile(ctx, userID)
 }
 
-func NewUserService(userRepo port.UserRepositoryInterface, sessionRepo port.SessionInterface, jwtUtil port.JWTInterface, verificationTokenRepo port.VerificationTokenInterface, emailPublisher port.EmailInterface, blacklistTokenRepo port.BlacklistTokenInterface, storage port.StorageInterface, cfg *config.Config) port.UserServiceInterface {
+// CreateUserAccount needs an invite code when REGISTRATION_MODE is invite; the use is taken
+// before the account is created and given back if creating it fails. In open mode the code is ignored.
+// Once terms are published the user has to accept them, and the accepted versions are recorded.
+func (u *UserService) CreateUserAccount(ctx context.Context, email, name, password, passwordConfirmation, inviteCode string, lat, lng float64, consent *entity.ConsentEntity) error {
+	if u.legal != nil {
+		if err := u.legal.CheckSignupConsent(ctx, consent); err != nil {
+			log.Warn().Err(err).Str("email", email).Msg("[UserService-CreateUserAccount] Terms not accepted")
+			return err
+		}
+	}
+
+	if err := u.createUserAccount(ctx, email, name, password, passwordConfirmation, inviteCode, lat, lng); err != nil {
+		return err
+	}
+
+	if u.legal != nil {
+		// The stored address is the normalized one; it was validated by the call above
+		normalized, _ := EmailPolicyFromConfig(u.config).NormalizeEmail(email)
+		u.legal.RecordSignupConsent(ctx, normalized, consent)
+	}
+	return nil
+}
+
+func (u *UserService) createUserAccount(ctx context.Context, email, name, password, passwordConfirmation, inviteCode string, lat, lng float64) error {
+	if u.config == nil || !u.config.Registration.InviteOnly() {
+		return u.AuthServiceInterface.CreateUserAccount(ctx, email, name, password, passwordConfirmation, lat, lng)
+	}
+	if u.invites == nil {
+		log.Error().Msg("[UserService-CreateUserAccount] Registration is invite only but invites are not available")
+		return errors.New("failed to create account")
+	}
+
+	invite, err := u.invites.Reserve(ctx, inviteCode)
+	if err != nil {
+		log.Warn().Err(err).Str("email", email).Msg("[UserService-CreateUserAccount] Invite code rejected")
+		return err
+	}
+
+	if err := u.AuthServiceInterface.CreateUserAccount(ctx, email, name, password, passwordConfirmation, lat, lng); err != nil {
+		u.invites.Release(ctx, invite)
+		return err
+	}
+
+	u.invites.RecordRedemption(ctx, invite, strings.ToLower(strings.TrimSpace(email)))
+	log.Info().Int64("invite_id", invite.ID).Str("email", email).Msg("[UserService-CreateUserAccount] Invite redeemed")
+	return nil
+}
+
+// UserServiceDeps adds signup gating to AuthServiceDeps. Email, token lifetime and password
+// settings left nil are read from Config.
+type UserServiceDeps struct {
+	AuthServiceDeps
+	Invites port.InviteServiceInterface
+	Legal   port.LegalServiceInterface
+	Config  *config.Config
+}
+
+func NewUserService(deps UserServiceDeps) port.UserServiceInterface {
+	auth := deps.AuthServiceDeps
+	if auth.EmailPolicy == nil {
+		auth.EmailPolicy = EmailPolicyFromConfig(deps.Config)
+	}
+	if auth.TokenLifetimes == nil {
+		auth.TokenLifetimes = TokenLifetimesFromConfig(deps.Config)
+	}
+	if auth.PasswordHasher == nil {
+		auth.PasswordHasher = PasswordHasherFromConfig(deps.Config)
+	}
+	if auth.PasswordPolicy == nil {
+		auth.PasswordPolicy = PasswordPolicyFromConfig(deps.Config)
+	}
+
 	return &UserService{
-		AuthServiceInterface: NewAuthService(userRepo, sessionRepo, jwtUtil, verificationTokenRepo, emailPublisher, blacklistTokenRepo, storage),
-		config:               cfg,
+		AuthServiceInterface: NewAuthService(auth),
+		invites:              deps.Invites,
+		legal:                deps.Legal,
+		config:               deps.Config,
+	}
+}
+
+// TokenLifetimesFromConfig reads the configured token lifetimes; unset or invalid ones keep the
+// defaults, as RunServer has already refused to start with invalid values
+func TokenLifetimesFromConfig(cfg *config.Config) *entity.TokenLifetimes {
+	lifetimes := &entity.TokenLifetimes{}
+	if cfg == nil {
+		return lifetimes
+	}
+	lifetimes.EmailVerification, _ = config.ParseTokenLifetime(cfg.TokenLifetimes.EmailVerification)
+	lifetimes.PasswordReset, _ = config.ParseTokenLifetime(cfg.TokenLifetimes.PasswordReset)
+	lifetimes.EmailChange, _ = config.ParseTokenLifetime(cfg.TokenLifetimes.EmailChange)
+	lifetimes.EmailChangeRevert, _ = config.ParseTokenLifetime(cfg.TokenLifetimes.EmailChangeRevert)
+	return lifetimes
+}
+
+// PasswordHasherFromConfig builds the hasher for new passwords; zero settings keep the defaults
+func PasswordHasherFromConfig(cfg *config.Config) *utils.PasswordHasher {
+	hasher := utils.DefaultPasswordHasher()
+	if cfg == nil {
+		return hasher
+	}
+
+	settings := cfg.PasswordHashing
+	if strings.EqualFold(strings.TrimSpace(settings.Algorithm), utils.PasswordAlgorithmArgon2id) {
+		hasher.Algorithm = utils.PasswordAlgorithmArgon2id
+	}
+	if settings.BcryptCost != 0 {
+		hasher.BcryptCost = settings.BcryptCost
+	}
+	if settings.Argon2Memory != 0 {
+		hasher.Argon2.Memory = settings.Argon2Memory
+	}
+	if settings.Argon2Iterations != 0 {
+		hasher.Argon2.Iterations = settings.Argon2Iterations
+	}
+	if settings.Argon2Parallelism != 0 {
+		hasher.Argon2.Parallelism = settings.Argon2Parallelism
+	}
+	return hasher
+}
+
+// PasswordPolicyFromConfig reads the staff password policy; nil config enforces nothing
+func PasswordPolicyFromConfig(cfg *config.Config) *entity.PasswordPolicy {
+	if cfg == nil {
+		return &entity.PasswordPolicy{}
+	}
+	return &entity.PasswordPolicy{
+		Roles:       cfg.PasswordPolicy.PolicyRoles(),
+		MaxAge:      cfg.PasswordPolicy.MaxAge(),
+		HistorySize: cfg.PasswordPolicy.HistorySize,
+		WarnBefore:  cfg.PasswordPolicy.WarnBefore(),
+	}
+}
+
+// EmailPolicyFromConfig builds the signup/email-change policy; nil config means the defaults
+func EmailPolicyFromConfig(cfg *config.Config) *utils.EmailPolicy {
+	if cfg == nil {
+		return utils.DefaultEmailPolicy()
+	}
+	plusAddressing := utils.PlusAddressingKeep
+	if cfg.Email.PlusAddressing == utils.PlusAddressingStrip {
+		plusAddressing = utils.PlusAddressingStrip
 	}
+	return utils.NewEmailPolicy(plusAddressing, cfg.Email.BlockDisposable, cfg.Email.DisposableDomains)
 }
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+var vendorDocumentTypes = map[string]bool{
+	"ktp":   true,
+	"npwp":  true,
+	"nib":   true,
+	"other": true,
+}
+
+type VendorService struct {
+	vendorRepo port.VendorRepositoryInterface
+	storage    port.StorageInterface
+}
+
+func (s *VendorService) RegisterVendor(ctx context.Context, userID int64, vendor *entity.VendorEntity) (*entity.VendorEntity, error) {
+	vendor.StoreName = strings.TrimSpace(vendor.StoreName)
+	if len(vendor.StoreName) < 3 || len(vendor.StoreName) > 100 {
+		log.Warn().Int64("user_id", userID).Msg("[VendorService-RegisterVendor] Store name length invalid")
+		return nil, errors.New("store name must be between 3 and 100 characters")
+	}
+
+	if vendor.Lat != 0 || vendor.Lng != 0 {
+		if err := utils.ValidateCoordinates(vendor.Lat, vendor.Lng); err != nil {
+			log.Warn().Int64("user_id", userID).Msg("[VendorService-RegisterVendor] Invalid coordinates")
+			return nil, err
+		}
+	}
+
+	existing, err := s.vendorRepo.GetVendorByUserID(ctx, userID)
+	if err != nil && err.Error() != "record not found" {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[VendorService-RegisterVendor] Failed to check existing vendor")
+		return nil, errors.New("failed to register vendor")
+	}
+	if existing != nil {
+		log.Warn().Int64("user_id", userID).Str("status", existing.Status).Msg("[VendorService-RegisterVendor] Vendor application already exists")
+		return nil, errors.New("vendor application already exists")
+	}
+
+	vendor.UserID = userID
+	vendor.Description = strings.TrimSpace(vendor.Description)
+	vendor.Phone = strings.TrimSpace(vendor.Phone)
+	vendor.Address = strings.TrimSpace(vendor.Address)
+
+	createdVendor, err := s.vendorRepo.CreateVendor(ctx, vendor)
+	if err != nil {
+		log.Error().Err(err).Int64("user_id", userID).Msg("[VendorService-RegisterVendor] Failed to create vendor")
+		return nil, errors.New("failed to register vendor")
+	}
+
+	log.Info().Int64("vendor_id", createdVendor.ID).Int64("user_id", userID).Msg("[VendorService-RegisterVendor] Vendor registered, awaiting approval")
+	return createdVendor, nil
+}
+
+func (s *VendorService) GetMyVendor(ctx context.Context, userID int64) (*entity.VendorEntity, error) {
+	vendor, err := s.vendorRepo.GetVendorByUserID(ctx, userID)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("vendor not found")
+		}
+		log.Error().Err(err).Int64("user_id", userID).Msg("[VendorService-GetMyVendor] Failed to get vendor")
+		return nil, err
+	}
+
+	return vendor, nil
+}
+
+func (s *VendorService) GetApprovedVendorByUserID(ctx context.Context, userID int64) (*entity.VendorEntity, error) {
+	vendor, err := s.GetMyVendor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if vendor.Status != entity.VendorStatusApproved {
+		log.Warn().Int64("user_id", userID).Str("status", vendor.Status).Msg("[VendorService-GetApprovedVendorByUserID] Vendor is not approved")
+		return nil, errors.New("vendor is not approved")
+	}
+
+	return vendor, nil
+}
+
+func (s *VendorService) UploadDocument(ctx context.Context, userID int64, documentType string, file io.Reader, contentType, filename string) (*entity.VendorDocumentEntity, error) {
+	documentType = strings.ToLower(strings.TrimSpace(documentType))
+	if !vendorDocumentTypes[documentType] {
+		log.Warn().Int64("user_id", userID).Str("document_type", documentType).Msg("[VendorService-UploadDocument] Invalid document type")
+		return nil, errors.New("document type must be one of ktp, npwp, nib, other")
+	}
+
+	vendor, err := s.GetMyVendor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if vendor.Status != entity.VendorStatusPending {
+		log.Warn().Int64("vendor_id", vendor.ID).Str("status", vendor.Status).Msg("[VendorService-UploadDocument] Vendor is not pending review")
+		return nil, errors.New("vendor is not pending review")
+	}
+
+	if s.storage == nil {
+		log.Error().Int64("vendor_id", vendor.ID).Msg("[VendorService-UploadDocument] Storage is not configured")
+		return nil, errors.New("storage service unavailable")
+	}
+
+	// Documents are capped at 5MB by the handler, so naming them by content can read them whole
+	data, err := io.ReadAll(file)
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendor.ID).Msg("[VendorService-UploadDocument] Failed to read document")
+		return nil, errors.New("failed to upload document")
+	}
+
+	objectName := utils.ContentObjectName(fmt.Sprintf("vendor-kyc/%d/%s-", vendor.ID, documentType), data, strings.ToLower(filepath.Ext(filename)))
+	fileURL, err := s.storage.UploadFile(ctx, "", objectName, bytes.NewReader(data), contentType)
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendor.ID).Msg("[VendorService-UploadDocument] Failed to upload document")
+		if errors.Is(err, port.ErrFileInfected) || errors.Is(err, port.ErrScannerUnavailable) {
+			return nil, err
+		}
+		return nil, errors.New("failed to upload document")
+	}
+
+	document, err := s.vendorRepo.AddVendorDocument(ctx, &entity.VendorDocumentEntity{
+		VendorID:     vendor.ID,
+		DocumentType: documentType,
+		FileURL:      fileURL,
+		ObjectName:   objectName,
+		ContentType:  contentType,
+	})
+	if err != nil {
+		log.Error().Err(err).Int64("vendor_id", vendor.ID).Msg("[VendorService-UploadDocument] Failed to save document")
+		if deleteErr := s.storage.DeleteFile(ctx, "", objectName); deleteErr != nil {
+			log.Error().Err(deleteErr).Str("object_name", objectName).Msg("[VendorService-UploadDocument] Failed to delete uploaded document after database error")
+		}
+		return nil, errors.New("failed to save document")
+	}
+
+	log.Info().Int64("vendor_id", vendor.ID).Str("document_type", documentType).Msg("[VendorService-UploadDocument] Document uploaded successfully")
+	return document, nil
+}
+
+func (s *VendorService) GetVendors(ctx context.Context, status string, page, limit int) ([]entity.VendorEntity, *entity.PaginationEntity, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10 // Default limit
+	}
+
+	vendors, totalCount, err := s.vendorRepo.GetVendors(ctx, status, page, limit)
+	if err != nil {
+		log.Error().Err(err).Str("status", status).Msg("[VendorService-GetVendors] Failed to get vendors")
+		return nil, nil, errors.New("failed to retrieve vendors")
+	}
+
+	pagination := &entity.PaginationEntity{
+		Page:       page,
+		TotalCount: totalCount,
+		PerPage:    limit,
+		TotalPage:  int((totalCount + int64(limit) - 1) / int64(limit)),
+	}
+
+	return vendors, pagination, nil
+}
+
+func (s *VendorService) GetVendorByID(ctx context.Context, id int64) (*entity.VendorEntity, error) {
+	vendor, err := s.vendorRepo.GetVendorByID(ctx, id)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("vendor not found")
+		}
+		log.Error().Err(err).Int64("vendor_id", id).Msg("[VendorService-GetVendorByID] Failed to get vendor")
+		return nil, err
+	}
+
+	return vendor, nil
+}
+
+func (s *VendorService) ApproveVendor(ctx context.Context, id, adminID int64) error {
+	vendor, err := s.GetVendorByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if vendor.Status != entity.VendorStatusPending {
+		log.Warn().Int64("vendor_id", id).Str("status", vendor.Status).Msg("[VendorService-ApproveVendor] Vendor is not pending review")
+		return errors.New("vendor is not pending review")
+	}
+
+	if len(vendor.Documents) == 0 {
+		log.Warn().Int64("vendor_id", id).Msg("[VendorService-ApproveVendor] Vendor has no KYC documents")
+		return errors.New("vendor has no KYC documents")
+	}
+
+	if err := s.vendorRepo.ApproveVendor(ctx, id, adminID); err != nil {
+		log.Error().Err(err).Int64("vendor_id", id).Msg("[VendorService-ApproveVendor] Failed to approve vendor")
+		if err.Error() == "vendor is not pending review" {
+			return err
+		}
+		return errors.New("failed to approve vendor")
+	}
+
+	log.Info().Int64("vendor_id", id).Int64("admin_id", adminID).Msg("[VendorService-ApproveVendor] Vendor approved")
+	return nil
+}
+
+func (s *VendorService) RejectVendor(ctx context.Context, id, adminID int64, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("rejection reason is required")
+	}
+
+	vendor, err := s.GetVendorByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if vendor.Status != entity.VendorStatusPending {
+		log.Warn().Int64("vendor_id", id).Str("status", vendor.Status).Msg("[VendorService-RejectVendor] Vendor is not pending review")
+		return errors.New("vendor is not pending review")
+	}
+
+	if err := s.vendorRepo.RejectVendor(ctx, id, adminID, reason); err != nil {
+		log.Error().Err(err).Int64("vendor_id", id).Msg("[VendorService-RejectVendor] Failed to reject vendor")
+		if err.Error() == "vendor is not pending review" {
+			return err
+		}
+		return errors.New("failed to reject vendor")
+	}
+
+	log.Info().Int64("vendor_id", id).Int64("admin_id", adminID).Msg("[VendorService-RejectVendor] Vendor rejected")
+	return nil
+}
+
+func NewVendorService(vendorRepo port.VendorRepositoryInterface, storage port.StorageInterface) port.VendorServiceInterface {
+	return &VendorService{
+		vendorRepo: vendorRepo,
+		storage:    storage,
+	}
+}
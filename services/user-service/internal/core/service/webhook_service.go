@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/utils"
+
+	"github.com/rs/zerolog/log"
+)
+
+const minWebhookSecretLength = 16
+
+type WebhookService struct {
+	webhookRepo port.WebhookRepositoryInterface
+	jobService  port.JobServiceInterface
+	sender      port.WebhookSenderInterface
+}
+
+func (s *WebhookService) Dispatch(ctx context.Context, eventType, eventID string, data interface{}) error {
+	if !entity.IsWebhookEventType(eventType) {
+		return errors.New("unknown webhook event type")
+	}
+	if strings.TrimSpace(eventID) == "" {
+		return errors.New("event id is required")
+	}
+
+	endpoints, err := s.webhookRepo.GetActiveEndpointsForEvent(ctx, eventType)
+	if err != nil {
+		log.Error().Err(err).Str("event", eventType).Msg("[WebhookService-Dispatch] Failed to get webhook endpoints")
+		return errors.New("failed to dispatch webhook")
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(entity.EventEntity{
+		ID:         eventID,
+		Type:       eventType,
+		OccurredAt: time.Now(),
+		Data:       data,
+	})
+	if err != nil {
+		log.Error().Err(err).Str("event", eventType).Msg("[WebhookService-Dispatch] Failed to encode payload")
+		return errors.New("invalid webhook payload")
+	}
+
+	failed := 0
+	for _, endpoint := range endpoints {
+		delivery, err := s.webhookRepo.CreateDelivery(ctx, &entity.WebhookDeliveryEntity{
+			EndpointID: endpoint.ID,
+			EventID:    eventID,
+			EventType:  eventType,
+			Payload:    payload,
+		})
+		if err != nil {
+			if err.Error() == "webhook delivery already exists" {
+				continue
+			}
+			failed++
+			continue
+		}
+
+		// A delivery without a job stays pending in the log, where an admin can redeliver it
+		if _, err := s.jobService.Enqueue(ctx, entity.JobTypeDeliverWebhook, entity.WebhookDeliveryJobPayload{DeliveryID: delivery.ID}, time.Time{}); err != nil {
+			log.Error().Err(err).Int64("delivery_id", delivery.ID).Msg("[WebhookService-Dispatch] Failed to queue webhook delivery")
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return errors.New("failed to dispatch webhook")
+	}
+
+	log.Info().Str("event", eventType).Str("event_id", eventID).Int("endpoints", len(endpoints)).Msg("[WebhookService-Dispatch] Webhook deliveries queued")
+	return nil
+}
+
+func (s *WebhookService) CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpointEntity) (*entity.WebhookEndpointEntity, error) {
+	if endpoint.Secret == "" {
+		secret, err := randomHex(24)
+		if err != nil {
+			log.Error().Err(err).Msg("[WebhookService-CreateEndpoint] Failed to generate secret")
+			return nil, errors.New("failed to create webhook endpoint")
+		}
+		endpoint.Secret = "whsec_" + secret
+	}
+
+	if err := s.validate(endpoint); err != nil {
+		return nil, err
+	}
+
+	created, err := s.webhookRepo.CreateEndpoint(ctx, endpoint)
+	if err != nil {
+		log.Error().Err(err).Str("url", endpoint.URL).Msg("[WebhookService-CreateEndpoint] Failed to create webhook endpoint")
+		return nil, errors.New("failed to create webhook endpoint")
+	}
+
+	log.Info().Int64("endpoint_id", created.ID).Strs("events", created.EventTypes).Int64("created_by", endpoint.CreatedBy).Msg("[WebhookService-CreateEndpoint] Webhook endpoint created")
+	return created, nil
+}
+
+func (s *WebhookService) GetEndpoints(ctx context.Context) ([]entity.WebhookEndpointEntity, error) {
+	endpoints, err := s.webhookRepo.GetEndpoints(ctx)
+	if err != nil {
+		return nil, errors.New("failed to retrieve webhook endpoints")
+	}
+	return endpoints, nil
+}
+
+func (s *WebhookService) GetEndpointByID(ctx context.Context, id int64) (*entity.WebhookEndpointEntity, error) {
+	endpoint, err := s.webhookRepo.GetEndpointByID(ctx, id)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("webhook endpoint not found")
+		}
+		return nil, errors.New("failed to retrieve webhook endpoint")
+	}
+	return endpoint, nil
+}
+
+// UpdateEndpoint replaces the endpoint settings; an empty secret keeps the current one
+func (s *WebhookService) UpdateEndpoint(ctx context.Context, id int64, endpoint *entity.WebhookEndpointEntity) (*entity.WebhookEndpointEntity, error) {
+	if err := s.validate(endpoint); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.webhookRepo.UpdateEndpoint(ctx, id, endpoint)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil, errors.New("webhook endpoint not found")
+		}
+		return nil, errors.New("failed to update webhook endpoint")
+	}
+
+	log.Info().Int64("endpoint_id", id).Bool("is_active", updated.IsActive).Strs("events", updated.EventTypes).Msg("[WebhookService-UpdateEndpoint] Webhook endpoint updated")
+	return updated, nil
+}
+
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, id int64) error {
+	if err := s.webhookRepo.DeleteEndpoint(ctx, id); err != nil {
+		if err.Error() == "record not found" {
+			return errors.New("webhook endpoint not found")
+		}
+		return errors.New("failed to delete webhook endpoint")
+	}
+
+	log.Info().Int64("endpoint_id", id).Msg("[WebhookService-DeleteEndpoint] Webhook endpoint deleted")
+	return nil
+}
+
+func (s *WebhookService) GetDeliveries(ctx context.Context, endpointID int64, status string, page, limit int) ([]entity.WebhookDeliveryEntity, *entity.PaginationEntity, error) {
+	if _, err := s.GetEndpointByID(ctx, endpointID); err != nil {
+		return nil, nil, err
+	}
+
+	page, limit = normalizePage(page, limit)
+	deliveries, totalCount, err := s.webhookRepo.GetDeliveries(ctx, endpointID, status, page, limit)
+	if err != nil {
+		return nil, nil, errors.New("failed to retrieve webhook deliveries")
+	}
+
+	return deliveries, newPagination(page, limit, totalCount), nil
+}
+
+// Redeliver queues a finished delivery again with the same event id and payload
+func (s *WebhookService) Redeliver(ctx context.Context, deliveryID int64) error {
+	delivery, err := s.webhookRepo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return errors.New("webhook delivery not found")
+		}
+		return errors.New("failed to redeliver webhook")
+	}
+	if delivery.Status == entity.WebhookDeliveryPending {
+		return errors.New("webhook delivery is still pending")
+	}
+
+	if err := s.webhookRepo.ResetDelivery(ctx, deliveryID); err != nil {
+		return errors.New("failed to redeliver webhook")
+	}
+	if _, err := s.jobService.Enqueue(ctx, entity.JobTypeDeliverWebhook, entity.WebhookDeliveryJobPayload{DeliveryID: deliveryID}, time.Time{}); err != nil {
+		log.Error().Err(err).Int64("delivery_id", deliveryID).Msg("[WebhookService-Redeliver] Failed to queue webhook delivery")
+		return errors.New("failed to redeliver webhook")
+	}
+
+	log.Info().Int64("delivery_id", deliveryID).Int64("endpoint_id", delivery.EndpointID).Msg("[WebhookService-Redeliver] Webhook delivery queued again")
+	return nil
+}
+
+func (s *WebhookService) Deliver(ctx context.Context, deliveryID int64, lastAttempt bool) error {
+	delivery, err := s.webhookRepo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		// The endpoint (and its deliveries) was deleted after the job was queued
+		if err.Error() == "record not found" {
+			return nil
+		}
+		return err
+	}
+	if delivery.Status == entity.WebhookDeliverySucceeded {
+		return nil
+	}
+
+	endpoint, err := s.webhookRepo.GetEndpointByID(ctx, delivery.EndpointID)
+	if err != nil {
+		if err.Error() == "record not found" {
+			return nil
+		}
+		return err
+	}
+
+	attempt := &entity.WebhookAttemptEntity{}
+	if !endpoint.IsActive {
+		attempt.Status = entity.WebhookDeliveryFailed
+		attempt.Error = "webhook endpoint is disabled"
+		return s.webhookRepo.RecordAttempt(ctx, deliveryID, attempt)
+	}
+
+	headers := map[string]string{
+		utils.WebhookSignatureHeader: utils.SignWebhookPayload(endpoint.Secret, time.Now().Unix(), delivery.Payload),
+		"X-Webhook-Event":            delivery.EventType,
+		"X-Webhook-ID":               delivery.EventID,
+		"X-Webhook-Delivery":         strconv.FormatInt(delivery.ID, 10),
+	}
+
+	startedAt := time.Now()
+	resp, err := s.sender.Send(ctx, endpoint.URL, headers, delivery.Payload)
+	attempt.DurationMs = time.Since(startedAt).Milliseconds()
+
+	switch {
+	case err != nil:
+		attempt.Error = err.Error()
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		attempt.ResponseStatus = resp.StatusCode
+		attempt.ResponseBody = resp.Body
+		attempt.Error = fmt.Sprintf("endpoint responded with status %d", resp.StatusCode)
+	default:
+		attempt.ResponseStatus = resp.StatusCode
+		attempt.ResponseBody = resp.Body
+	}
+
+	switch {
+	case attempt.Error == "":
+		attempt.Status = entity.WebhookDeliverySucceeded
+	case lastAttempt:
+		attempt.Status = entity.WebhookDeliveryFailed
+	default:
+		attempt.Status = entity.WebhookDeliveryPending
+	}
+
+	if recordErr := s.webhookRepo.RecordAttempt(ctx, deliveryID, attempt); recordErr != nil {
+		log.Error().Err(recordErr).Int64("delivery_id", deliveryID).Msg("[WebhookService-Deliver] Failed to record attempt")
+	}
+
+	if attempt.Error != "" {
+		log.Warn().Int64("delivery_id", deliveryID).Int64("endpoint_id", endpoint.ID).Str("error", attempt.Error).Bool("last_attempt", lastAttempt).Msg("[WebhookService-Deliver] Webhook delivery failed")
+		return errors.New(attempt.Error)
+	}
+
+	log.Info().Int64("delivery_id", deliveryID).Int64("endpoint_id", endpoint.ID).Int("status", attempt.ResponseStatus).Int64("duration_ms", attempt.DurationMs).Msg("[WebhookService-Deliver] Webhook delivered")
+	return nil
+}
+
+func (s *WebhookService) validate(endpoint *entity.WebhookEndpointEntity) error {
+	endpoint.URL = strings.TrimSpace(endpoint.URL)
+	parsed, err := url.Parse(endpoint.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.New("webhook url must be an absolute http or https url")
+	}
+
+	if endpoint.Secret != "" && len(endpoint.Secret) < minWebhookSecretLength {
+		return errors.New("webhook secret must be at least 16 characters")
+	}
+
+	eventTypes := make([]string, 0, len(endpoint.EventTypes))
+	seen := make(map[string]bool, len(endpoint.EventTypes))
+	for _, eventType := range endpoint.EventTypes {
+		eventType = strings.TrimSpace(eventType)
+		if !entity.IsWebhookEventType(eventType) {
+			return errors.New("unknown webhook event type")
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	if len(eventTypes) == 0 {
+		return errors.New("at least one event type is required")
+	}
+	endpoint.EventTypes = eventTypes
+
+	return nil
+}
+
+func NewWebhookService(webhookRepo port.WebhookRepositoryInterface, jobService port.JobServiceInterface, sender port.WebhookSenderInterface) port.WebhookServiceInterface {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		jobService:  jobService,
+		sender:      sender,
+	}
+}
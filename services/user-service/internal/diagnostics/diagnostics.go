@@ -0,0 +1,138 @@
+// Package diagnostics serves pprof, expvar and on-demand goroutine/heap dumps on a listener
+// of its own, so none of it is reachable through APP_PORT or the public gateway.
+package diagnostics
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"path/filepath"
+	"runtime"
+	runtimepprof "runtime/pprof"
+	"time"
+	"user-service/config"
+
+	"github.com/rs/zerolog/log"
+)
+
+func init() {
+	// memstats and cmdline come with expvar itself
+	expvar.Publish("goroutines", expvar.Func(func() any { return runtime.NumGoroutine() }))
+}
+
+// Handler routes /debug/pprof/*, /debug/vars and POST /debug/dump, behind the token if one is set
+func Handler(cfg config.Diagnostics) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+	mux.HandleFunc("/debug/dump", dumpHandler(cfg.DumpDir))
+
+	if cfg.Token == "" {
+		return mux
+	}
+	expected := []byte("Bearer " + cfg.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// Start listens in the background when diagnostics are enabled and returns the matching stop
+func Start(cfg config.Diagnostics) func(ctx context.Context) {
+	if !cfg.Enabled {
+		return func(ctx context.Context) {}
+	}
+
+	server := &http.Server{
+		Addr:              cfg.ListenAddr(),
+		Handler:           Handler(cfg),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		log.Info().Str("addr", server.Addr).Msg("[Diagnostics-Start] Serving pprof and expvar")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Str("addr", server.Addr).Msg("[Diagnostics-Start] Diagnostics listener stopped")
+		}
+	}()
+
+	return func(ctx context.Context) {
+		if err := server.Shutdown(ctx); err != nil {
+			log.Error().Err(err).Msg("[Diagnostics-Stop] Failed to stop diagnostics listener")
+		}
+	}
+}
+
+type dumpResponse struct {
+	Goroutine string `json:"goroutine"`
+	Heap      string `json:"heap"`
+}
+
+// dumpHandler writes a full goroutine dump and a heap profile to dir (the temp dir by default),
+// for comparing snapshots taken before and after load without keeping a profile request open
+func dumpHandler(dir string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		dir := dir
+		if dir == "" {
+			dir = os.TempDir()
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			log.Error().Err(err).Str("dir", dir).Msg("[Diagnostics-dump] Failed to create dump directory")
+			http.Error(w, "Failed to create dump directory", http.StatusInternalServerError)
+			return
+		}
+
+		stamp := time.Now().UTC().Format("20060102T150405.000Z")
+		resp := dumpResponse{
+			Goroutine: filepath.Join(dir, fmt.Sprintf("goroutine-%s.txt", stamp)),
+			Heap:      filepath.Join(dir, fmt.Sprintf("heap-%s.pprof", stamp)),
+		}
+
+		if err := writeProfile(resp.Goroutine, "goroutine", 2); err != nil {
+			log.Error().Err(err).Msg("[Diagnostics-dump] Failed to write goroutine dump")
+			http.Error(w, "Failed to write goroutine dump", http.StatusInternalServerError)
+			return
+		}
+		// The heap profile reflects the last GC, so collect first to include recent growth
+		runtime.GC()
+		if err := writeProfile(resp.Heap, "heap", 0); err != nil {
+			log.Error().Err(err).Msg("[Diagnostics-dump] Failed to write heap profile")
+			http.Error(w, "Failed to write heap profile", http.StatusInternalServerError)
+			return
+		}
+
+		log.Info().Str("goroutine", resp.Goroutine).Str("heap", resp.Heap).Msg("[Diagnostics-dump] Wrote goroutine and heap dumps")
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}
+}
+
+func writeProfile(path, name string, debug int) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := runtimepprof.Lookup(name).WriteTo(file, debug); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
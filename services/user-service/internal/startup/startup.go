@@ -0,0 +1,93 @@
+// Package startup waits for the service's backing dependencies (Postgres, Redis,
+// RabbitMQ) before the app is wired, so container start order does not matter.
+package startup
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Dependency is one backing service to connect to; Connect is retried until it returns nil
+type Dependency struct {
+	Name    string
+	Connect func(ctx context.Context) error
+}
+
+// Policy controls the retry schedule. Backoff doubles from InitialBackoff up to
+// MaxBackoff, and every dependency gives up once Timeout has passed since WaitFor started.
+type Policy struct {
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Timeout        time.Duration
+}
+
+// Report holds the last error per dependency; a dependency missing from Failed is ready
+type Report struct {
+	Failed map[string]error
+}
+
+// Ready reports whether the named dependency connected before the timeout
+func (r Report) Ready(name string) bool {
+	_, failed := r.Failed[name]
+	return !failed
+}
+
+// WaitFor connects to all dependencies concurrently, retrying each with backoff
+// until it succeeds or the policy timeout (or ctx) expires.
+func WaitFor(ctx context.Context, policy Policy, deps ...Dependency) Report {
+	ctx, cancel := context.WithTimeout(ctx, policy.Timeout)
+	defer cancel()
+
+	report := Report{Failed: make(map[string]error)}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, dep := range deps {
+		wg.Add(1)
+		go func(dep Dependency) {
+			defer wg.Done()
+			if err := connectWithRetry(ctx, policy, dep); err != nil {
+				mu.Lock()
+				report.Failed[dep.Name] = err
+				mu.Unlock()
+			}
+		}(dep)
+	}
+
+	wg.Wait()
+	return report
+}
+
+func connectWithRetry(ctx context.Context, policy Policy, dep Dependency) error {
+	started := time.Now()
+	backoff := policy.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := dep.Connect(ctx)
+		if err == nil {
+			log.Info().Str("dependency", dep.Name).Int("attempt", attempt).Dur("elapsed", time.Since(started)).Msg("[Startup-WaitFor] Dependency ready")
+			return nil
+		}
+
+		deadline, _ := ctx.Deadline()
+		if ctx.Err() != nil || time.Now().Add(backoff).After(deadline) {
+			log.Error().Err(err).Str("dependency", dep.Name).Int("attempt", attempt).Dur("elapsed", time.Since(started)).Msg("[Startup-WaitFor] Giving up on dependency")
+			return err
+		}
+		log.Warn().Err(err).Str("dependency", dep.Name).Int("attempt", attempt).Dur("retry_in", backoff).Msg("[Startup-WaitFor] Dependency not ready")
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
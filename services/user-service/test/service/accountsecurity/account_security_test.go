@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type securityFixture struct {
+	userRepo    *mocks.MockUserRepository
+	tokenRepo   *mocks.MockVerificationTokenRepository
+	sessionRepo *mocks.MockSessionRepository
+	email       *mocks.MockEmailPublisher
+	auditRepo   *mocks.MockAuditLogRepository
+	service     port.AccountSecurityServiceInterface
+}
+
+func newSecurityFixture() *securityFixture {
+	f := &securityFixture{
+		userRepo:    new(mocks.MockUserRepository),
+		tokenRepo:   new(mocks.MockVerificationTokenRepository),
+		sessionRepo: new(mocks.MockSessionRepository),
+		email:       new(mocks.MockEmailPublisher),
+		auditRepo:   new(mocks.MockAuditLogRepository),
+	}
+	f.service = service.NewAccountSecurityService(f.userRepo, f.tokenRepo, f.sessionRepo, f.email, service.NewAuditLogService(f.auditRepo))
+	return f
+}
+
+func revertToken(token string) *entity.VerificationTokenEntity {
+	return &entity.VerificationTokenEntity{
+		UserID:    7,
+		Token:     token,
+		TokenType: entity.TokenTypeEmailChangeRevert,
+		NewEmail:  "owner@example.com",
+		ExpiresAt: time.Now().Add(entity.DefaultTokenLifetimes().EmailChangeRevert),
+	}
+}
+
+func TestRevertEmailChange_RestoresLocksAndAlertsAdmins(t *testing.T) {
+	ctx := context.Background()
+	f := newSecurityFixture()
+
+	f.tokenRepo.On("GetVerificationToken", ctx, "revert-token").Return(revertToken("revert-token"), nil)
+	f.userRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7, Email: "attacker@example.com"}, nil)
+	f.userRepo.On("RestoreEmailAndLock", ctx, int64(7), "owner@example.com", mock.AnythingOfType("string")).Return(nil)
+	f.sessionRepo.On("DeleteAllUserTokens", ctx, int64(7)).Return(nil)
+	f.tokenRepo.On("ClaimVerificationToken", ctx, "revert-token").Return(nil)
+	f.auditRepo.On("Create", ctx, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == entity.AuditEventEmailChangeReverted && auditLog.UserID == 7 && auditLog.Metadata["replaced_email"] == "attacker@example.com"
+	})).Return(nil)
+	f.userRepo.On("GetEmailsByRole", ctx, "Super Admin").Return([]string{"admin1@example.com", "admin2@example.com"}, nil)
+	f.email.On("SendAccountLockedAlertEmail", ctx, "admin1@example.com", int64(7), "owner@example.com", "attacker@example.com").Return(nil)
+	f.email.On("SendAccountLockedAlertEmail", ctx, "admin2@example.com", int64(7), "owner@example.com", "attacker@example.com").Return(errors.New("broker down"))
+
+	err := f.service.RevertEmailChange(ctx, "revert-token")
+
+	// A failed alert does not undo the revert
+	assert.NoError(t, err)
+	f.userRepo.AssertExpectations(t)
+	f.sessionRepo.AssertExpectations(t)
+	f.tokenRepo.AssertExpectations(t)
+	f.auditRepo.AssertExpectations(t)
+	f.email.AssertExpectations(t)
+}
+
+func TestRevertEmailChange_RejectsOtherTokenTypes(t *testing.T) {
+	ctx := context.Background()
+	f := newSecurityFixture()
+
+	token := revertToken("change-token")
+	token.TokenType = "email_change"
+	f.tokenRepo.On("GetVerificationToken", ctx, "change-token").Return(token, nil)
+
+	err := f.service.RevertEmailChange(ctx, "change-token")
+
+	assert.EqualError(t, err, "invalid token type")
+	f.userRepo.AssertNotCalled(t, "RestoreEmailAndLock", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRevertEmailChange_ExpiredToken(t *testing.T) {
+	ctx := context.Background()
+	f := newSecurityFixture()
+
+	f.tokenRepo.On("GetVerificationToken", ctx, "old-token").Return(nil, gorm.ErrRecordNotFound)
+
+	err := f.service.RevertEmailChange(ctx, "old-token")
+
+	assert.EqualError(t, err, "invalid or expired revert token")
+}
+
+func TestRevertEmailChange_PreviousEmailTaken(t *testing.T) {
+	ctx := context.Background()
+	f := newSecurityFixture()
+
+	f.tokenRepo.On("GetVerificationToken", ctx, "revert-token").Return(revertToken("revert-token"), nil)
+	f.userRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7, Email: "attacker@example.com"}, nil)
+	f.tokenRepo.On("ClaimVerificationToken", ctx, "revert-token").Return(nil)
+	f.userRepo.On("RestoreEmailAndLock", ctx, int64(7), "owner@example.com", mock.AnythingOfType("string")).Return(errors.New("email already taken"))
+	f.tokenRepo.On("ReleaseVerificationToken", ctx, "revert-token").Return(nil)
+
+	err := f.service.RevertEmailChange(ctx, "revert-token")
+
+	assert.EqualError(t, err, "previous email is already in use")
+	f.sessionRepo.AssertNotCalled(t, "DeleteAllUserTokens", mock.Anything, mock.Anything)
+	// The link keeps working once support has freed the address
+	f.tokenRepo.AssertExpectations(t)
+}
+
+func TestUnlockAccount(t *testing.T) {
+	ctx := context.Background()
+	f := newSecurityFixture()
+
+	f.userRepo.On("UnlockUser", ctx, int64(7)).Return(nil)
+	f.userRepo.On("UnlockUser", ctx, int64(8)).Return(gorm.ErrRecordNotFound)
+	f.auditRepo.On("Create", ctx, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == entity.AuditEventAccountUnlocked && auditLog.UserID == 7 && auditLog.Metadata["admin_id"] == int64(1)
+	})).Return(nil)
+
+	assert.NoError(t, f.service.UnlockAccount(ctx, 7, 1))
+	assert.EqualError(t, f.service.UnlockAccount(ctx, 8, 1), "account is not locked")
+	f.auditRepo.AssertExpectations(t)
+}
+
+func TestSignIn_LockedAccountIsRejected(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(mocks.MockUserRepository)
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockRepo},
+		Config:          &config.Config{},
+	})
+
+	hashedPassword, _ := utils.HashPassword("password123")
+	lockedAt := time.Now()
+	mockRepo.On("GetUserByEmail", ctx, "owner@example.com").Return(&entity.UserEntity{
+		ID:         7,
+		Email:      "owner@example.com",
+		Password:   hashedPassword,
+		IsVerified: true,
+		LockedAt:   &lockedAt,
+	}, nil)
+
+	user, token, err := userService.SignIn(ctx, entity.UserEntity{Email: "owner@example.com", Password: "password123"}, entity.ClientEntity{}, false)
+
+	assert.EqualError(t, err, "account is locked")
+	assert.Nil(t, user)
+	assert.Empty(t, token)
+}
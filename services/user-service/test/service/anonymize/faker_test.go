@@ -0,0 +1,71 @@
+package main
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"user-service/database/anonymize"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaker_SameKeyGivesSameValues(t *testing.T) {
+	a := anonymize.NewFaker([]byte("staging-2026-10"))
+	b := anonymize.NewFaker([]byte("staging-2026-10"))
+
+	assert.Equal(t, a.Name("user:42"), b.Name("user:42"))
+	assert.Equal(t, a.Email("user:42"), b.Email("user:42"))
+	assert.Equal(t, a.Phone("user:42"), b.Phone("user:42"))
+}
+
+func TestFaker_SeedChangesValues(t *testing.T) {
+	a := anonymize.NewFaker([]byte("one"))
+	b := anonymize.NewFaker([]byte("two"))
+
+	assert.NotEqual(t, a.Phone("user:42"), b.Phone("user:42"))
+}
+
+func TestFaker_EmailsAndUsernamesAreUnique(t *testing.T) {
+	faker := anonymize.NewFaker([]byte("seed"))
+	emailPattern := regexp.MustCompile(`^[a-z]+\.[a-z]+\.u\d+@example\.com$`)
+
+	emails := make(map[string]bool)
+	usernames := make(map[string]bool)
+	for id := 1; id <= 5000; id++ {
+		key := "user:" + strconv.Itoa(id)
+		email := faker.Email(key)
+		username := faker.Username(key)
+
+		assert.Regexp(t, emailPattern, email)
+		assert.LessOrEqual(t, len(username), 30)
+		assert.False(t, emails[email], email)
+		assert.False(t, usernames[username], username)
+		emails[email] = true
+		usernames[username] = true
+	}
+}
+
+func TestFaker_EmailDoesNotLeakTheKey(t *testing.T) {
+	faker := anonymize.NewFaker([]byte("seed"))
+
+	assert.True(t, strings.HasSuffix(faker.Email("redemption:7"), ".r7@example.com"))
+}
+
+func TestFaker_PhoneAndAddressFormats(t *testing.T) {
+	faker := anonymize.NewFaker([]byte("seed"))
+
+	assert.Regexp(t, `^\+628\d{10}$`, faker.Phone("vendor:3"))
+	assert.Regexp(t, `^Jl\. [A-Za-z ]+ No\. \d+$`, faker.Address("user:3"))
+}
+
+func TestFaker_JitterStaysClose(t *testing.T) {
+	faker := anonymize.NewFaker([]byte("seed"))
+
+	for id := 1; id <= 1000; id++ {
+		lat, lng := faker.Jitter("user:"+strconv.Itoa(id), -6.2088, 106.8456)
+		assert.LessOrEqual(t, math.Abs(lat+6.2088), 0.0045)
+		assert.LessOrEqual(t, math.Abs(lng-106.8456), 0.0045)
+	}
+}
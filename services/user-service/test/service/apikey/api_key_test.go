@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/adapter/handler"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type apiKeyFixture struct {
+	apiKeyRepo *mocks.MockAPIKeyRepository
+	auditRepo  *mocks.MockAuditLogRepository
+	service    port.APIKeyServiceInterface
+}
+
+func newAPIKeyFixture() *apiKeyFixture {
+	f := &apiKeyFixture{
+		apiKeyRepo: new(mocks.MockAPIKeyRepository),
+		auditRepo:  new(mocks.MockAuditLogRepository),
+	}
+	f.service = service.NewAPIKeyService(f.apiKeyRepo, service.NewAuditLogService(f.auditRepo))
+	return f
+}
+
+func sha256Hex(value string) string {
+	hash := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestCreateAPIKey_ReturnsPlainKeyOnceAndStoresOnlyItsHash(t *testing.T) {
+	ctx := context.Background()
+	f := newAPIKeyFixture()
+
+	var stored *entity.APIKeyEntity
+	f.apiKeyRepo.On("CreateAPIKey", ctx, mock.AnythingOfType("*entity.APIKeyEntity")).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*entity.APIKeyEntity)
+	}).Return(&entity.APIKeyEntity{ID: 4, Name: "Acme", Scopes: []string{entity.APIKeyScopeCustomersRead}}, nil)
+	f.auditRepo.On("Create", ctx, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == entity.AuditEventAPIKeyCreated && auditLog.UserID == 1
+	})).Return(nil)
+
+	created, plainKey, err := f.service.CreateAPIKey(ctx, &entity.APIKeyEntity{
+		Name:      "  Acme ",
+		Scopes:    []string{entity.APIKeyScopeCustomersRead},
+		CreatedBy: 1,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), created.ID)
+	assert.True(t, strings.HasPrefix(plainKey, "jsk_"))
+	assert.Len(t, plainKey, 52)
+	assert.Equal(t, "Acme", stored.Name)
+	assert.Equal(t, plainKey[:12], stored.Prefix)
+	assert.Equal(t, sha256Hex(plainKey), stored.KeyHash)
+	f.auditRepo.AssertExpectations(t)
+}
+
+func TestCreateAPIKey_Validation(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	cases := map[string]struct {
+		apiKey entity.APIKeyEntity
+		err    string
+	}{
+		"name":    {entity.APIKeyEntity{Name: " ", Scopes: []string{entity.APIKeyScopeCustomersRead}}, "api key name is required"},
+		"scopes":  {entity.APIKeyEntity{Name: "Acme"}, "at least one scope is required"},
+		"unknown": {entity.APIKeyEntity{Name: "Acme", Scopes: []string{"users:write"}}, "unknown api key scope"},
+		"quota":   {entity.APIKeyEntity{Name: "Acme", Scopes: []string{entity.APIKeyScopeCustomersRead}, Quota: -1}, "api key quota must not be negative"},
+		"expiry":  {entity.APIKeyEntity{Name: "Acme", Scopes: []string{entity.APIKeyScopeCustomersRead}, ExpiresAt: &past}, "api key expiry must be in the future"},
+	}
+
+	for name, tc := range cases {
+		f := newAPIKeyFixture()
+		apiKey := tc.apiKey
+		_, plainKey, err := f.service.CreateAPIKey(context.Background(), &apiKey)
+		assert.EqualError(t, err, tc.err, name)
+		assert.Empty(t, plainKey, name)
+		f.apiKeyRepo.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
+	}
+}
+
+func TestAuthenticate_RejectsUnknownRevokedAndExpiredKeys(t *testing.T) {
+	ctx := context.Background()
+	revokedAt := time.Now().Add(-time.Hour)
+	expiresAt := time.Now().Add(-time.Minute)
+
+	f := newAPIKeyFixture()
+	f.apiKeyRepo.On("GetAPIKeyByHash", ctx, sha256Hex("jsk_unknown")).Return(nil, gorm.ErrRecordNotFound)
+	f.apiKeyRepo.On("GetAPIKeyByHash", ctx, sha256Hex("jsk_revoked")).Return(&entity.APIKeyEntity{ID: 1, RevokedAt: &revokedAt}, nil)
+	f.apiKeyRepo.On("GetAPIKeyByHash", ctx, sha256Hex("jsk_expired")).Return(&entity.APIKeyEntity{ID: 2, ExpiresAt: &expiresAt}, nil)
+	f.apiKeyRepo.On("GetAPIKeyByHash", ctx, sha256Hex("jsk_down")).Return(nil, errors.New("connection refused"))
+
+	_, err := f.service.Authenticate(ctx, "not-a-partner-key")
+	assert.EqualError(t, err, "invalid api key")
+	_, err = f.service.Authenticate(ctx, "jsk_unknown")
+	assert.EqualError(t, err, "invalid api key")
+	_, err = f.service.Authenticate(ctx, "jsk_revoked")
+	assert.EqualError(t, err, "api key revoked")
+	_, err = f.service.Authenticate(ctx, "jsk_expired")
+	assert.EqualError(t, err, "api key expired")
+	_, err = f.service.Authenticate(ctx, "jsk_down")
+	assert.EqualError(t, err, "failed to authenticate api key")
+
+	f.apiKeyRepo.AssertNotCalled(t, "TouchLastUsed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthenticate_TouchesLastUsedAtMostOncePerMinute(t *testing.T) {
+	ctx := context.Background()
+	recent := time.Now().Add(-10 * time.Second)
+	stale := time.Now().Add(-2 * time.Minute)
+
+	f := newAPIKeyFixture()
+	f.apiKeyRepo.On("GetAPIKeyByHash", ctx, sha256Hex("jsk_recent")).Return(&entity.APIKeyEntity{ID: 1, LastUsedAt: &recent}, nil)
+	f.apiKeyRepo.On("GetAPIKeyByHash", ctx, sha256Hex("jsk_stale")).Return(&entity.APIKeyEntity{ID: 2, LastUsedAt: &stale}, nil)
+	f.apiKeyRepo.On("TouchLastUsed", ctx, int64(2), mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	apiKey, err := f.service.Authenticate(ctx, "jsk_recent")
+	assert.NoError(t, err)
+	assert.Equal(t, recent, *apiKey.LastUsedAt)
+
+	apiKey, err = f.service.Authenticate(ctx, "jsk_stale")
+	assert.NoError(t, err)
+	assert.True(t, apiKey.LastUsedAt.After(stale))
+
+	f.apiKeyRepo.AssertExpectations(t)
+}
+
+func TestRevokeAPIKey_NotFound(t *testing.T) {
+	ctx := context.Background()
+	f := newAPIKeyFixture()
+	f.apiKeyRepo.On("RevokeAPIKey", ctx, int64(9), int64(1), mock.AnythingOfType("time.Time")).Return(gorm.ErrRecordNotFound)
+
+	assert.EqualError(t, f.service.RevokeAPIKey(ctx, 9, 1), "api key not found")
+	f.auditRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func servePartner(apiKeys *mocks.MockAPIKeyService, headers map[string]string, scope string) (*httptest.ResponseRecorder, entity.PrincipalEntity) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/partner/customers", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+
+	var principal entity.PrincipalEntity
+	h := middleware.APIKeyMiddleware(apiKeys)(middleware.RequireAPIKeyScope(scope)(func(c echo.Context) error {
+		principal, _ = middleware.GetPrincipal(c)
+		return c.NoContent(http.StatusOK)
+	}))
+	_ = h(e.NewContext(req, rec))
+	return rec, principal
+}
+
+func TestAPIKeyMiddleware_SetsPrincipalAndEnforcesScope(t *testing.T) {
+	apiKeys := new(mocks.MockAPIKeyService)
+	apiKeys.On("Authenticate", mock.Anything, "jsk_acme").Return(&entity.APIKeyEntity{ID: 4, Name: "Acme", Scopes: []string{entity.APIKeyScopeCustomersRead}}, nil)
+	apiKeys.On("Authenticate", mock.Anything, "jsk_revoked").Return(nil, errors.New("api key revoked"))
+
+	rec, principal := servePartner(apiKeys, map[string]string{middleware.APIKeyHeader: "jsk_acme"}, entity.APIKeyScopeCustomersRead)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, principal.IsAPIKey())
+	assert.Equal(t, int64(4), principal.APIKeyID)
+	assert.Equal(t, "Acme", principal.Partner)
+
+	rec, _ = servePartner(apiKeys, map[string]string{middleware.APIKeyHeader: "jsk_acme"}, entity.APIKeyScopeWebhooksManage)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec, _ = servePartner(apiKeys, map[string]string{middleware.APIKeyHeader: "jsk_revoked"}, entity.APIKeyScopeCustomersRead)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec, _ = servePartner(apiKeys, nil, entity.APIKeyScopeCustomersRead)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestRateLimit_DatabaseKeyIsAuthenticatedOnceAndUsesItsQuota(t *testing.T) {
+	apiKeys := new(mocks.MockAPIKeyService)
+	apiKeys.On("Authenticate", mock.Anything, "jsk_acme").Return(&entity.APIKeyEntity{ID: 4, Name: "Acme", Quota: 50, Scopes: []string{entity.APIKeyScopeCustomersRead}}, nil).Once()
+	limiter := new(mocks.MockRateLimitRepository)
+	limiter.On("Hit", mock.Anything, "api_key:4", 50, 24*time.Hour).Return(entity.RateLimitEntity{Limit: 50, Count: 1, ResetAt: time.Now().Add(time.Hour)}, nil)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/partner/customers", nil)
+	req.Header.Set(middleware.APIKeyHeader, "jsk_acme")
+	rec := httptest.NewRecorder()
+
+	called := false
+	h := middleware.RateLimitMiddleware(limiter, apiKeys, config.RateLimit{})(middleware.APIKeyMiddleware(apiKeys)(func(c echo.Context) error {
+		called = true
+		return c.NoContent(http.StatusOK)
+	}))
+	_ = h(e.NewContext(req, rec))
+
+	assert.True(t, called)
+	assert.Equal(t, "49", rec.Header().Get(middleware.RateLimitRemainingHeader))
+	apiKeys.AssertExpectations(t)
+}
+
+func TestWebhookHandler_HidesOtherPartnersEndpoints(t *testing.T) {
+	ctx := context.Background()
+	webhookRepo := new(mocks.MockWebhookRepository)
+	webhookRepo.On("GetEndpointByID", mock.Anything, int64(3)).Return(&entity.WebhookEndpointEntity{ID: 3, APIKeyID: 7, URL: "https://other.example.com/hook"}, nil)
+	webhookHandler := handler.NewWebhookHandler(service.NewWebhookService(webhookRepo, service.NewJobService(new(mocks.MockJobRepository)), new(mocks.MockWebhookSender)))
+
+	serve := func(principal entity.PrincipalEntity) int {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/partner/webhooks/3", nil).WithContext(ctx)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("3")
+		c.Set(middleware.PrincipalContextKey, principal)
+		_ = webhookHandler.GetEndpoint(c)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusNotFound, serve(entity.PrincipalEntity{Type: entity.PrincipalTypeAPIKey, APIKeyID: 4}))
+	assert.Equal(t, http.StatusOK, serve(entity.PrincipalEntity{Type: entity.PrincipalTypeAPIKey, APIKeyID: 7}))
+	assert.Equal(t, http.StatusOK, serve(entity.PrincipalEntity{Type: entity.PrincipalTypeUser, UserID: 1}))
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-service/config"
+	"user-service/internal/adapter/handler"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// pngImage starts with the PNG signature, which is all content sniffing looks at
+var pngImage = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...)
+
+type assetFixture struct {
+	assetRepo *mocks.MockAssetRepository
+	storage   *mocks.MockStorage
+	auditRepo *mocks.MockAuditLogRepository
+	service   port.AssetServiceInterface
+}
+
+func newAssetFixture(assets config.Assets) *assetFixture {
+	f := &assetFixture{
+		assetRepo: new(mocks.MockAssetRepository),
+		storage:   new(mocks.MockStorage),
+		auditRepo: new(mocks.MockAuditLogRepository),
+	}
+	f.service = service.NewAssetService(f.assetRepo, f.storage, service.NewAuditLogService(f.auditRepo), &config.Config{Assets: assets})
+	return f
+}
+
+func TestUploadAsset_StoresUnderHashedName(t *testing.T) {
+	ctx := context.Background()
+	f := newAssetFixture(config.Assets{PublicBaseURL: "https://cdn.example.com/assets"})
+
+	var objectName string
+	f.assetRepo.On("GetAsset", ctx, "products/bayam.png").Return(nil, gorm.ErrRecordNotFound)
+	f.storage.On("UploadFile", ctx, "assets", mock.MatchedBy(func(name string) bool {
+		objectName = name
+		return strings.HasPrefix(name, "products/bayam.") && strings.HasSuffix(name, ".png") && len(name) == len("products/bayam..png")+12
+	}), mock.Anything, "image/png").Return("https://storage.example.com/object/public/assets/x", nil)
+	f.assetRepo.On("SaveAsset", ctx, mock.MatchedBy(func(asset *entity.AssetEntity) bool {
+		return asset.Name == "products/bayam.png" && asset.ObjectName == objectName && asset.UploadedBy == 1
+	})).Return(nil)
+	f.auditRepo.On("Create", ctx, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == entity.AuditEventAssetUploaded
+	})).Return(nil)
+
+	asset, err := f.service.UploadAsset(ctx, " Products/Bayam.png ", bytes.NewReader(pngImage), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/assets/"+objectName, asset.URL)
+	assert.Equal(t, int64(len(pngImage)), asset.Size)
+	assert.Len(t, asset.Hash, 64)
+	f.storage.AssertExpectations(t)
+	f.assetRepo.AssertExpectations(t)
+}
+
+func TestUploadAsset_UnchangedContentSkipsUpload(t *testing.T) {
+	ctx := context.Background()
+	f := newAssetFixture(config.Assets{})
+
+	f.assetRepo.On("GetAsset", ctx, "logo.png").Return(nil, gorm.ErrRecordNotFound).Once()
+	f.storage.On("UploadFile", ctx, "assets", mock.Anything, mock.Anything, "image/png").Return("https://storage.example.com/logo.png", nil).Once()
+	f.assetRepo.On("SaveAsset", ctx, mock.Anything).Return(nil).Once()
+	f.auditRepo.On("Create", ctx, mock.Anything).Return(nil)
+
+	existing, err := f.service.UploadAsset(ctx, "logo.png", bytes.NewReader(pngImage), 1)
+	assert.NoError(t, err)
+
+	f.assetRepo.On("GetAsset", ctx, "logo.png").Return(existing, nil).Once()
+	again, err := f.service.UploadAsset(ctx, "logo.png", bytes.NewReader(pngImage), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, existing.URL, again.URL)
+	f.storage.AssertNumberOfCalls(t, "UploadFile", 1)
+}
+
+func TestUploadAsset_RejectsInvalidInput(t *testing.T) {
+	ctx := context.Background()
+	f := newAssetFixture(config.Assets{MaxSizeKB: 1})
+	f.assetRepo.On("GetAsset", ctx, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+	cases := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{"../logo.png", pngImage, "asset name is invalid"},
+		{"logo", pngImage, "asset name is invalid"},
+		{"logo.jpg", pngImage, "asset content does not match its extension"},
+		{"logo.png", []byte("<svg xmlns='http://www.w3.org/2000/svg'></svg>"), "asset must be a PNG, JPEG, GIF or WebP image"},
+		{"logo.png", append(pngImage, bytes.Repeat([]byte{0}, 1024)...), "asset exceeds the size limit"},
+		{"logo.png", nil, "asset is empty"},
+	}
+	for _, tc := range cases {
+		_, err := f.service.UploadAsset(ctx, tc.name, bytes.NewReader(tc.data), 1)
+		assert.EqualError(t, err, tc.wantErr, tc.name)
+	}
+	f.storage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUploadAsset_WithoutStorage(t *testing.T) {
+	ctx := context.Background()
+	assetRepo := new(mocks.MockAssetRepository)
+	assetService := service.NewAssetService(assetRepo, nil, service.NewAuditLogService(new(mocks.MockAuditLogRepository)), &config.Config{})
+
+	assetRepo.On("GetAsset", ctx, "logo.png").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := assetService.UploadAsset(ctx, "logo.png", bytes.NewReader(pngImage), 1)
+	assert.EqualError(t, err, "storage not available")
+}
+
+func TestGetManifestHandler_MapsNamesToURLs(t *testing.T) {
+	f := newAssetFixture(config.Assets{})
+	h := handler.NewAssetHandler(f.service)
+
+	f.assetRepo.On("GetAssets", mock.Anything).Return([]entity.AssetEntity{
+		{Name: "logo.png", URL: "https://cdn.example.com/logo.0123456789ab.png", Hash: "0123456789ab"},
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/assets/manifest", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	assert.NoError(t, h.GetManifest(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "public, max-age=60", rec.Header().Get("Cache-Control"))
+
+	var body struct {
+		Data map[string]struct {
+			URL string `json:"url"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "https://cdn.example.com/logo.0123456789ab.png", body.Data["logo.png"].URL)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// racingUserRepository never sees the other signups when checking, like two requests that read
+// before either writes; CreateUser then behaves like the unique index on users.email
+type racingUserRepository struct {
+	mocks.MockUserRepository
+	mu     sync.Mutex
+	emails map[string]int64
+}
+
+func (r *racingUserRepository) GetUserByEmailIncludingUnverified(ctx context.Context, email string) (*entity.UserEntity, error) {
+	return nil, errors.New("record not found")
+}
+
+func (r *racingUserRepository) CreateUser(ctx context.Context, user *entity.UserEntity) (*entity.UserEntity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.emails[user.Email]; ok {
+		return nil, errors.New("email already exists")
+	}
+	id := int64(len(r.emails) + 1)
+	r.emails[user.Email] = id
+	return &entity.UserEntity{ID: id, Email: user.Email, Name: user.Name}, nil
+}
+
+func TestUserService_CreateUserAccount_ConcurrentSignupsOneWins(t *testing.T) {
+	userRepo := &racingUserRepository{emails: make(map[string]int64)}
+	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
+	mockEmailPublisher := new(mocks.MockEmailPublisher)
+	mockStorage := new(mocks.MockStorage)
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              userRepo,
+			VerificationTokenRepo: mockVerificationTokenRepo,
+			EmailPublisher:        mockEmailPublisher,
+			Storage:               mockStorage,
+		},
+		Config: &config.Config{},
+	})
+
+	ctx := context.Background()
+	email := "race@example.com"
+
+	mockStorage.On("UploadFile", ctx, "", "", mock.Anything, "image/png").Return("https://project.supabase.co/storage/v1/object/public/avatars/profile-race.png", nil)
+	mockStorage.On("DeleteFile", ctx, "", "profile-race.png").Return(nil)
+	mockVerificationTokenRepo.On("CreateVerificationToken", ctx, mock.AnythingOfType("*entity.VerificationTokenEntity")).Return(nil)
+	mockEmailPublisher.On("SendVerificationEmail", ctx, email, mock.AnythingOfType("string")).Return(nil)
+
+	const signups = 8
+	errs := make(chan error, signups)
+	var wg sync.WaitGroup
+	for i := 0; i < signups; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- service.CreateUserAccount(ctx, email, "Race User", "password123", "password123", "", 0, 0, nil)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded, rejected := 0, 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case err.Error() == "email already exists":
+			rejected++
+		default:
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	assert.Equal(t, 1, succeeded)
+	assert.Equal(t, signups-1, rejected)
+	// Every loser cleans up the avatar it uploaded, and only the winner is emailed
+	mockStorage.AssertNumberOfCalls(t, "DeleteFile", signups-1)
+	mockEmailPublisher.AssertNumberOfCalls(t, "SendVerificationEmail", 1)
+}
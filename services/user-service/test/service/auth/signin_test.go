@@ -18,7 +18,10 @@ func TestUserService_SignIn_UserNotFound(t *testing.T) {
 	// Setup
 	mockRepo := new(mocks.MockUserRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(mockRepo, nil, nil, nil, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockRepo, Storage: mockStorage},
+		Config:          &config.Config{},
+	})
 
 	ctx := context.Background()
 	email := "notfound@example.com"
@@ -29,7 +32,7 @@ func TestUserService_SignIn_UserNotFound(t *testing.T) {
 	user, token, err := service.SignIn(ctx, entity.UserEntity{
 		Email:    email,
 		Password: "password123",
-	})
+	}, entity.ClientEntity{}, false)
 
 	// Assert
 	assert.Error(t, err)
@@ -44,7 +47,10 @@ func TestUserService_SignIn_InvalidEmail(t *testing.T) {
 	// Setup
 	mockRepo := new(mocks.MockUserRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(mockRepo, nil, nil, nil, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockRepo, Storage: mockStorage},
+		Config:          &config.Config{},
+	})
 
 	ctx := context.Background()
 
@@ -52,7 +58,7 @@ func TestUserService_SignIn_InvalidEmail(t *testing.T) {
 	user, token, err := service.SignIn(ctx, entity.UserEntity{
 		Email:    "",
 		Password: "password123",
-	})
+	}, entity.ClientEntity{}, false)
 
 	// Assert
 	assert.Error(t, err)
@@ -76,7 +82,15 @@ func TestUserService_AdminCheck_Success(t *testing.T) {
 			JwtIssuer:    "test-issuer",
 		},
 	}
-	service := service.NewUserService(mockUserRepo, mockSessionRepo, mockJWTUtil, nil, nil, nil, mockStorage, mockConfig)
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:    mockUserRepo,
+			SessionRepo: mockSessionRepo,
+			JWTUtil:     mockJWTUtil,
+			Storage:     mockStorage,
+		},
+		Config: mockConfig,
+	})
 
 	ctx := context.Background()
 	email := "admin@example.com"
@@ -93,14 +107,15 @@ func TestUserService_AdminCheck_Success(t *testing.T) {
 
 	// Mock expectations
 	mockUserRepo.On("GetUserByEmail", ctx, email).Return(adminUser, nil)
-	mockJWTUtil.On("GenerateJWTWithSession", int64(1), email, "admin", mock.AnythingOfType("string")).Return("admin-jwt-token", nil)
-	mockSessionRepo.On("StoreToken", ctx, int64(1), mock.AnythingOfType("string"), "admin-jwt-token").Return(nil)
+	mockJWTUtil.On("GenerateJWTWithSession", int64(1), email, "admin", mock.AnythingOfType("string"), false).Return("admin-jwt-token", nil)
+	mockSessionRepo.On("StoreToken", ctx, int64(1), mock.AnythingOfType("string"), "admin-jwt-token", false).Return(nil)
+	mockSessionRepo.On("SetSessionClient", ctx, int64(1), mock.AnythingOfType("string"), entity.ClientEntity{}, (*entity.LoginLocationEntity)(nil)).Return(nil)
 
 	// Execute
 	user, token, err := service.SignIn(ctx, entity.UserEntity{
 		Email:    email,
 		Password: password,
-	})
+	}, entity.ClientEntity{}, false)
 
 	// Assert
 	assert.NoError(t, err)
@@ -112,3 +127,31 @@ func TestUserService_AdminCheck_Success(t *testing.T) {
 	mockSessionRepo.AssertExpectations(t)
 	mockJWTUtil.AssertExpectations(t)
 }
+
+func TestUserService_SignIn_RememberMe(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockJWTUtil := new(mocks.MockJWTUtil)
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:    mockUserRepo,
+			SessionRepo: mockSessionRepo,
+			JWTUtil:     mockJWTUtil,
+		},
+		Config: &config.Config{},
+	})
+
+	ctx := context.Background()
+	hashedPassword, _ := utils.HashPassword("password123")
+	mockUserRepo.On("GetUserByEmail", ctx, "john@example.com").Return(&entity.UserEntity{ID: 1, Email: "john@example.com", Password: hashedPassword, RoleName: "Customer"}, nil)
+	mockJWTUtil.On("GenerateJWTWithSession", int64(1), "john@example.com", "Customer", mock.AnythingOfType("string"), true).Return("long-jwt-token", nil)
+	mockSessionRepo.On("StoreToken", ctx, int64(1), mock.AnythingOfType("string"), "long-jwt-token", true).Return(nil)
+	mockSessionRepo.On("SetSessionClient", ctx, int64(1), mock.AnythingOfType("string"), entity.ClientEntity{}, (*entity.LoginLocationEntity)(nil)).Return(nil)
+
+	_, token, err := service.SignIn(ctx, entity.UserEntity{Email: "john@example.com", Password: "password123"}, entity.ClientEntity{}, true)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "long-jwt-token", token)
+	mockJWTUtil.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+}
@@ -19,7 +19,15 @@ func TestUserService_CreateUserAccount_Success(t *testing.T) {
 	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
 	mockEmailPublisher := new(mocks.MockEmailPublisher)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(mockUserRepo, nil, nil, mockVerificationTokenRepo, mockEmailPublisher, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              mockUserRepo,
+			VerificationTokenRepo: mockVerificationTokenRepo,
+			EmailPublisher:        mockEmailPublisher,
+			Storage:               mockStorage,
+		},
+		Config: &config.Config{},
+	})
 
 	ctx := context.Background()
 	email := "test@example.com"
@@ -29,15 +37,19 @@ func TestUserService_CreateUserAccount_Success(t *testing.T) {
 
 	// Mock expectations
 	mockUserRepo.On("GetUserByEmailIncludingUnverified", ctx, email).Return(nil, errors.New("record not found"))
-	mockUserRepo.On("CreateUser", ctx, mock.AnythingOfType("*entity.UserEntity")).Return(&entity.UserEntity{ID: 1, Email: email, Name: name}, nil)
+	mockStorage.On("UploadFile", ctx, "", "", mock.Anything, "image/png").Return("https://cdn.example.com/profile-1.png", nil)
+	mockUserRepo.On("CreateUser", ctx, mock.MatchedBy(func(user *entity.UserEntity) bool {
+		return user.Photo == "https://cdn.example.com/profile-1.png"
+	})).Return(&entity.UserEntity{ID: 1, Email: email, Name: name}, nil)
 	mockVerificationTokenRepo.On("CreateVerificationToken", ctx, mock.AnythingOfType("*entity.VerificationTokenEntity")).Return(nil)
 	mockEmailPublisher.On("SendVerificationEmail", ctx, email, mock.AnythingOfType("string")).Return(nil)
 
 	// Execute
-	err := service.CreateUserAccount(ctx, email, name, password, passwordConfirmation)
+	err := service.CreateUserAccount(ctx, email, name, password, passwordConfirmation, "", 0, 0, nil)
 
 	// Assert
 	assert.NoError(t, err)
+	mockStorage.AssertExpectations(t)
 	mockUserRepo.AssertExpectations(t)
 	mockVerificationTokenRepo.AssertExpectations(t)
 	mockEmailPublisher.AssertExpectations(t)
@@ -47,7 +59,10 @@ func TestUserService_CreateUserAccount_EmailAlreadyExists(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(mockUserRepo, nil, nil, nil, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage},
+		Config:          &config.Config{},
+	})
 
 	ctx := context.Background()
 	email := "existing@example.com"
@@ -56,7 +71,7 @@ func TestUserService_CreateUserAccount_EmailAlreadyExists(t *testing.T) {
 	mockUserRepo.On("GetUserByEmailIncludingUnverified", ctx, email).Return(existingUser, nil)
 
 	// Execute
-	err := service.CreateUserAccount(ctx, email, "Test User", "password123", "password123")
+	err := service.CreateUserAccount(ctx, email, "Test User", "password123", "password123", "", 0, 0, nil)
 
 	// Assert
 	assert.Error(t, err)
@@ -69,7 +84,14 @@ func TestUserService_VerifyUserAccount_Success(t *testing.T) {
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(mockUserRepo, nil, nil, mockVerificationTokenRepo, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              mockUserRepo,
+			VerificationTokenRepo: mockVerificationTokenRepo,
+			Storage:               mockStorage,
+		},
+		Config: &config.Config{},
+	})
 
 	ctx := context.Background()
 	token := "valid-token"
@@ -78,7 +100,7 @@ func TestUserService_VerifyUserAccount_Success(t *testing.T) {
 	verificationToken := &entity.VerificationTokenEntity{UserID: 1, Token: token}
 	mockVerificationTokenRepo.On("GetVerificationToken", ctx, token).Return(verificationToken, nil)
 	mockUserRepo.On("UpdateUserVerificationStatus", ctx, int64(1), true).Return(nil)
-	mockVerificationTokenRepo.On("DeleteVerificationToken", ctx, token).Return(nil)
+	mockVerificationTokenRepo.On("ClaimVerificationToken", ctx, token).Return(nil)
 
 	// Execute
 	err := service.VerifyUserAccount(ctx, token)
@@ -88,3 +110,43 @@ func TestUserService_VerifyUserAccount_Success(t *testing.T) {
 	mockUserRepo.AssertExpectations(t)
 	mockVerificationTokenRepo.AssertExpectations(t)
 }
+
+func TestUserService_VerifyUserAccount_QueuesUserVerifiedEvent(t *testing.T) {
+	// Setup
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
+	mockJobRepo := new(mocks.MockJobRepository)
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              mockUserRepo,
+			VerificationTokenRepo: mockVerificationTokenRepo,
+			Jobs:                  service.NewJobService(mockJobRepo),
+		},
+		Config: &config.Config{},
+	})
+
+	ctx := context.Background()
+	token := "valid-token"
+
+	// Mock expectations
+	mockVerificationTokenRepo.On("GetVerificationToken", ctx, token).Return(&entity.VerificationTokenEntity{UserID: 1, Token: token}, nil)
+	mockVerificationTokenRepo.On("ClaimVerificationToken", ctx, token).Return(nil)
+	mockUserRepo.On("UpdateUserVerificationStatus", ctx, int64(1), true).Return(nil)
+	mockUserRepo.On("GetUserByID", ctx, int64(1)).Return(&entity.UserEntity{ID: 1, Email: "new@example.com", Name: "New User", Language: "id"}, nil)
+	mockJobRepo.On("Enqueue", ctx, mock.MatchedBy(func(job *entity.JobEntity) bool {
+		var event entity.EventEntity
+		if job.Type != entity.JobTypePublishEvent || job.UniqueKey != "user.verified:1" || job.Decode(&event) != nil {
+			return false
+		}
+		data, ok := event.Data.(map[string]interface{})
+		return ok && event.Type == entity.EventUserVerified && data["email"] == "new@example.com" && data["language"] == "id"
+	})).Return(&entity.JobEntity{ID: 1}, nil)
+
+	// Execute
+	err := service.VerifyUserAccount(ctx, token)
+
+	// Assert
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+	mockJobRepo.AssertExpectations(t)
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNormalizeUsername(t *testing.T) {
+	valid := map[string]string{
+		"Budi_Santoso": "budi_santoso",
+		" siti.r ":     "siti.r",
+		"abc":          "abc",
+	}
+	for input, expected := range valid {
+		username, err := utils.NormalizeUsername(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, username)
+	}
+
+	for _, input := range []string{"ab", "1budi", "budi..santoso", "budi.", "budi santoso", "budi-santoso", "abcdefghijklmnopqrstuvwxyz12345"} {
+		_, err := utils.NormalizeUsername(input)
+		assert.ErrorIs(t, err, utils.ErrInvalidUsername, input)
+	}
+}
+
+func TestSignIn_ByUsername(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockJWTUtil := new(mocks.MockJWTUtil)
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:    mockUserRepo,
+			SessionRepo: mockSessionRepo,
+			JWTUtil:     mockJWTUtil,
+			Storage:     new(mocks.MockStorage),
+		},
+		Config: &config.Config{},
+	})
+
+	ctx := context.Background()
+	hashedPassword, _ := utils.HashPassword("password123")
+	mockUserRepo.On("GetUserByUsername", ctx, "budi_santoso").Return(&entity.UserEntity{
+		ID:       7,
+		Email:    "budi@example.com",
+		Username: "budi_santoso",
+		Password: hashedPassword,
+		RoleName: "Customer",
+	}, nil)
+	mockJWTUtil.On("GenerateJWTWithSession", int64(7), "budi@example.com", "Customer", mock.AnythingOfType("string"), false).Return("jwt-token", nil)
+	mockSessionRepo.On("StoreToken", ctx, int64(7), mock.AnythingOfType("string"), "jwt-token", false).Return(nil)
+	mockSessionRepo.On("SetSessionClient", ctx, int64(7), mock.AnythingOfType("string"), entity.ClientEntity{}, (*entity.LoginLocationEntity)(nil)).Return(nil)
+
+	user, token, err := service.SignIn(ctx, entity.UserEntity{Username: "Budi_Santoso", Password: "password123"}, entity.ClientEntity{}, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "jwt-token", token)
+	assert.Equal(t, "budi_santoso", user.Username)
+	mockUserRepo.AssertNotCalled(t, "GetUserByEmail", mock.Anything, mock.Anything)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestSignIn_MalformedUsernameIsNotFound(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+		Config:          &config.Config{},
+	})
+
+	_, _, err := service.SignIn(context.Background(), entity.UserEntity{Username: "no spaces", Password: "password123"}, entity.ClientEntity{}, false)
+
+	assert.EqualError(t, err, "user not found")
+	mockUserRepo.AssertNotCalled(t, "GetUserByUsername", mock.Anything, mock.Anything)
+}
+
+func TestCheckUsernameAvailability(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := new(mocks.MockUserRepository)
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+		Config:          &config.Config{},
+	})
+
+	mockUserRepo.On("IsUsernameTaken", ctx, "siti").Return(true, nil)
+	mockUserRepo.On("IsUsernameTaken", ctx, "budi").Return(false, nil)
+
+	availability, err := service.CheckUsernameAvailability(ctx, "Budi")
+	assert.NoError(t, err)
+	assert.True(t, availability.Available)
+	assert.Equal(t, "budi", availability.Username)
+
+	availability, err = service.CheckUsernameAvailability(ctx, "siti")
+	assert.NoError(t, err)
+	assert.False(t, availability.Available)
+	assert.Equal(t, "username already taken", availability.Reason)
+
+	availability, err = service.CheckUsernameAvailability(ctx, "Admin")
+	assert.NoError(t, err)
+	assert.False(t, availability.Available)
+	assert.Equal(t, utils.ErrReservedUsername.Error(), availability.Reason)
+
+	availability, err = service.CheckUsernameAvailability(ctx, "a!")
+	assert.NoError(t, err)
+	assert.False(t, availability.Available)
+	assert.Equal(t, utils.ErrInvalidUsername.Error(), availability.Reason)
+
+	mockUserRepo.AssertNumberOfCalls(t, "IsUsernameTaken", 2)
+}
+
+func TestChangeUsername(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("claims a free username", func(t *testing.T) {
+		mockUserRepo := new(mocks.MockUserRepository)
+		service := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+
+		mockUserRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7, Username: "budi"}, nil)
+		mockUserRepo.On("IsUsernameTaken", ctx, "budi.s").Return(false, nil)
+		mockUserRepo.On("UpdateUsername", ctx, int64(7), "budi.s").Return(nil)
+
+		username, err := service.ChangeUsername(ctx, 7, "Budi.S")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "budi.s", username)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects reserved username", func(t *testing.T) {
+		mockUserRepo := new(mocks.MockUserRepository)
+		service := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+
+		_, err := service.ChangeUsername(ctx, 7, "support")
+
+		assert.ErrorIs(t, err, utils.ErrReservedUsername)
+		mockUserRepo.AssertNotCalled(t, "UpdateUsername", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("loses a concurrent claim", func(t *testing.T) {
+		mockUserRepo := new(mocks.MockUserRepository)
+		service := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+
+		mockUserRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7}, nil)
+		mockUserRepo.On("IsUsernameTaken", ctx, "budi").Return(false, nil)
+		mockUserRepo.On("UpdateUsername", ctx, int64(7), "budi").Return(errors.New("username already taken"))
+
+		_, err := service.ChangeUsername(ctx, 7, "budi")
+
+		assert.EqualError(t, err, "username already taken")
+	})
+}
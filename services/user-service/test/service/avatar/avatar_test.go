@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/png"
+	"testing"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGenerateAvatar_IsStableAndMirrored(t *testing.T) {
+	first, err := utils.GenerateAvatar("budi@example.com", 100)
+	assert.NoError(t, err)
+
+	second, _ := utils.GenerateAvatar("budi@example.com", 100)
+	assert.Equal(t, first, second)
+
+	other, _ := utils.GenerateAvatar("siti@example.com", 100)
+	assert.NotEqual(t, first, other)
+
+	img, err := png.Decode(bytes.NewReader(first))
+	assert.NoError(t, err)
+	assert.Equal(t, 100, img.Bounds().Dx())
+	assert.Equal(t, 100, img.Bounds().Dy())
+
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 50; x++ {
+			assert.Equal(t, img.At(x, y), img.At(99-x, y))
+		}
+	}
+}
+
+func TestCreateUserAccount_SignsUpWithoutAvatarWhenStorageFails(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockTokenRepo := new(mocks.MockVerificationTokenRepository)
+	mockEmail := new(mocks.MockEmailPublisher)
+	mockStorage := new(mocks.MockStorage)
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              mockUserRepo,
+			VerificationTokenRepo: mockTokenRepo,
+			EmailPublisher:        mockEmail,
+			Storage:               mockStorage,
+		},
+		Config: &config.Config{},
+	})
+
+	mockUserRepo.On("GetUserByEmailIncludingUnverified", ctx, "budi@example.com").Return(nil, errors.New("record not found"))
+	mockStorage.On("UploadFile", ctx, "", "", mock.Anything, "image/png").Return("", errors.New("storage is unavailable"))
+	mockUserRepo.On("CreateUser", ctx, mock.MatchedBy(func(user *entity.UserEntity) bool {
+		return user.Photo == ""
+	})).Return(&entity.UserEntity{ID: 7, Email: "budi@example.com"}, nil)
+	mockTokenRepo.On("CreateVerificationToken", ctx, mock.Anything).Return(nil)
+	mockEmail.On("SendVerificationEmail", ctx, "budi@example.com", mock.AnythingOfType("string")).Return(nil)
+
+	err := userService.CreateUserAccount(ctx, "budi@example.com", "Budi", "password123", "password123", "", 0, 0, nil)
+
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestRegenerateAvatar_ReplacesPhoto(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockStorage := new(mocks.MockStorage)
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage},
+		Config:          &config.Config{},
+	})
+
+	oldPhoto := "https://project.supabase.co/storage/v1/object/public/avatars/profile-old.png"
+	newPhoto := "https://project.supabase.co/storage/v1/object/public/avatars/profile-new.png"
+
+	mockUserRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7, Photo: oldPhoto}, nil)
+	mockStorage.On("UploadFile", ctx, "", "", mock.Anything, "image/png").Return(newPhoto, nil)
+	mockUserRepo.On("UpdateUserPhoto", ctx, int64(7), newPhoto).Return(nil)
+	mockStorage.On("DeleteFile", ctx, "", "profile-old.png").Return(nil)
+
+	imageURL, err := userService.RegenerateAvatar(ctx, 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, newPhoto, imageURL)
+	mockUserRepo.AssertExpectations(t)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestRegenerateAvatar_WithoutStorage(t *testing.T) {
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: new(mocks.MockUserRepository)},
+		Config:          &config.Config{},
+	})
+
+	_, err := userService.RegenerateAvatar(context.Background(), 7)
+
+	assert.EqualError(t, err, "failed to upload image")
+}
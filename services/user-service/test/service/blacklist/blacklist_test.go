@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/adapter/repository"
+	"user-service/internal/adapter/worker"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestNewBlacklistTokenBackend(t *testing.T) {
+	for backend, want := range map[string]interface{}{
+		"":         &repository.BlacklistTokenRepository{},
+		"postgres": &repository.BlacklistTokenRepository{},
+		"Redis":    &repository.RedisBlacklistTokenRepository{},
+	} {
+		repo, err := repository.NewBlacklistTokenBackend(&config.Config{Blacklist: config.Blacklist{Backend: backend}}, nil, nil)
+		assert.NoError(t, err, backend)
+		assert.IsType(t, want, repo, backend)
+	}
+
+	_, err := repository.NewBlacklistTokenBackend(&config.Config{Blacklist: config.Blacklist{Backend: "memcached"}}, nil, nil)
+	assert.EqualError(t, err, "unsupported blacklist backend: memcached")
+}
+
+func TestWorker_BlacklistCleanupRemovesExpiredAndReportsSize(t *testing.T) {
+	mockJobRepo := new(mocks.MockJobRepository)
+	mockBlacklist := new(mocks.MockBlacklistTokenRepository)
+	jobWorker := worker.NewWorker(mockJobRepo, service.NewJobService(mockJobRepo), 1, 10*time.Millisecond)
+
+	job := &entity.JobEntity{ID: 5, Type: worker.JobTypeCleanupBlacklist, Attempts: 1, MaxAttempts: 3}
+	done := make(chan struct{}, 1)
+
+	mockJobRepo.On("Enqueue", mock.Anything, mock.Anything).Return(job, nil)
+	mockJobRepo.On("RequeueStale", mock.Anything, mock.Anything).Return(int64(0), nil)
+	mockJobRepo.On("ClaimNext", mock.Anything, []string{worker.JobTypeCleanupBlacklist}, mock.Anything).Return(job, nil).Once()
+	mockJobRepo.On("ClaimNext", mock.Anything, []string{worker.JobTypeCleanupBlacklist}, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	mockJobRepo.On("MarkCompleted", mock.Anything, int64(5)).Run(func(args mock.Arguments) { done <- struct{}{} }).Return(nil)
+	mockBlacklist.On("DeleteExpired", mock.Anything).Return(int64(12), nil)
+	mockBlacklist.On("Count", mock.Anything).Return(int64(4), nil)
+
+	jobWorker.RegisterBlacklistCleanup(mockBlacklist)
+	jobWorker.Start(context.Background())
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("cleanup job was not processed")
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	jobWorker.Stop(stopCtx)
+
+	mockBlacklist.AssertExpectations(t)
+	assert.Equal(t, float64(4), gaugeValue(t, "token_blacklist_size"))
+}
+
+func gaugeValue(t *testing.T, name string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	assert.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == name {
+			return family.GetMetric()[0].GetGauge().GetValue()
+		}
+	}
+	t.Fatalf("metric %s not registered", name)
+	return 0
+}
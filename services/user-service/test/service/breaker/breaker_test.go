@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"user-service/internal/adapter/breaker"
+	"user-service/internal/adapter/storage"
+	"user-service/internal/core/port"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var testSettings = breaker.Settings{
+	FailureThreshold: 3,
+	OpenTimeout:      50 * time.Millisecond,
+	HalfOpenRequests: 1,
+}
+
+func TestBreaker_OpensAfterConsecutiveFailuresAndRecovers(t *testing.T) {
+	b := breaker.New("test-recovery", testSettings)
+	calls := 0
+	failing := func() error {
+		calls++
+		return errors.New("connection reset")
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.EqualError(t, b.Execute(failing), "connection reset")
+	}
+	assert.Equal(t, "open", b.State())
+
+	// While open, calls are rejected without reaching the dependency
+	err := b.Execute(failing)
+	assert.ErrorIs(t, err, breaker.ErrOpen)
+	assert.EqualError(t, err, "test-recovery is unavailable, circuit breaker is open")
+	assert.Equal(t, 3, calls)
+
+	time.Sleep(60 * time.Millisecond)
+	assert.NoError(t, b.Execute(func() error { return nil }))
+	assert.Equal(t, "closed", b.State())
+}
+
+func TestBreaker_CanceledCallsDoNotTrip(t *testing.T) {
+	b := breaker.New("test-canceled", testSettings)
+
+	for i := 0; i < 5; i++ {
+		_ = b.Execute(func() error { return context.Canceled })
+	}
+
+	assert.Equal(t, "closed", b.State())
+}
+
+func TestBreakerStorage_ShortCircuitsUploads(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mocks.MockStorage)
+	inner.On("UploadFile", ctx, "", "avatar.png", mock.Anything, "image/png").Return("", errors.New("upload failed with status 502"))
+	s := storage.NewBreakerStorage(inner, breaker.New("test-storage", testSettings))
+
+	for i := 0; i < 3; i++ {
+		_, err := s.UploadFile(ctx, "", "avatar.png", strings.NewReader("png"), "image/png")
+		assert.EqualError(t, err, "upload failed with status 502")
+	}
+	_, err := s.UploadFile(ctx, "", "avatar.png", strings.NewReader("png"), "image/png")
+
+	assert.ErrorIs(t, err, breaker.ErrOpen)
+	inner.AssertNumberOfCalls(t, "UploadFile", 3)
+}
+
+func TestBreakerStorage_OversizedUploadsDoNotTrip(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mocks.MockStorage)
+	inner.On("UploadFile", ctx, "", "video.mp4", mock.Anything, "video/mp4").Return("", port.ErrFileTooLarge)
+	s := storage.NewBreakerStorage(inner, breaker.New("test-storage-size", testSettings))
+
+	for i := 0; i < 5; i++ {
+		_, err := s.UploadFile(ctx, "", "video.mp4", strings.NewReader("mp4"), "video/mp4")
+		assert.ErrorIs(t, err, port.ErrFileTooLarge)
+	}
+
+	inner.AssertNumberOfCalls(t, "UploadFile", 5)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"runtime"
+	"testing"
+	"user-service/internal/buildinfo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet_UsesLinkerValues(t *testing.T) {
+	defer func(version, commit, builtAt string) {
+		buildinfo.Version, buildinfo.GitCommit, buildinfo.BuildTime = version, commit, builtAt
+	}(buildinfo.Version, buildinfo.GitCommit, buildinfo.BuildTime)
+
+	buildinfo.Version = "1.4.0"
+	buildinfo.GitCommit = "abc1234"
+	buildinfo.BuildTime = "2026-01-02T03:04:05Z"
+
+	info := buildinfo.Get()
+
+	assert.Equal(t, "1.4.0", info.Version)
+	assert.Equal(t, "abc1234", info.GitCommit)
+	assert.Equal(t, "2026-01-02T03:04:05Z", info.BuildTime)
+	assert.Equal(t, runtime.Version(), info.GoVersion)
+	assert.Equal(t, runtime.GOOS+"/"+runtime.GOARCH, info.Platform)
+}
+
+func TestGet_NeverReturnsEmptyFields(t *testing.T) {
+	defer func(commit, builtAt string) {
+		buildinfo.GitCommit, buildinfo.BuildTime = commit, builtAt
+	}(buildinfo.GitCommit, buildinfo.BuildTime)
+
+	buildinfo.GitCommit = ""
+	buildinfo.BuildTime = ""
+
+	info := buildinfo.Get()
+
+	assert.NotEmpty(t, info.GitCommit)
+	assert.NotEmpty(t, info.BuildTime)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-service/internal/adapter/cdn"
+	"user-service/internal/adapter/httpclient"
+	"user-service/internal/adapter/storage"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	projectURL = "https://test.supabase.co"
+	storageURL = "https://test.supabase.co/storage/v1/object/public/profile-images/profile-abc.jpg"
+	cdnURL     = "https://img.jualansayur.id/profile-images/profile-abc.jpg"
+)
+
+func TestRewriter_MapsStorageURLsToTheCDNAndBack(t *testing.T) {
+	r := cdn.NewRewriter(projectURL+"/", "https://img.jualansayur.id/")
+
+	assert.True(t, r.Enabled())
+	assert.Equal(t, cdnURL, r.PublicURL(storageURL))
+	assert.Equal(t, storageURL, r.StorageURL(cdnURL))
+	assert.Equal(t, cdnURL, r.ObjectURL("profile-images", "profile-abc.jpg"))
+
+	// URLs from elsewhere are not touched
+	assert.Equal(t, "https://example.com/me.jpg", r.PublicURL("https://example.com/me.jpg"))
+	assert.Equal(t, "https://example.com/me.jpg", r.StorageURL("https://example.com/me.jpg"))
+	assert.Equal(t, "", r.PublicURL(""))
+}
+
+func TestRewriter_WithoutBaseURLLeavesURLsAlone(t *testing.T) {
+	r := cdn.NewRewriter(projectURL, "")
+
+	assert.False(t, r.Enabled())
+	assert.Equal(t, storageURL, r.PublicURL(storageURL))
+	assert.Equal(t, "", r.ObjectURL("profile-images", "profile-abc.jpg"))
+}
+
+func TestHTTPPurger_SendsPurgeRequest(t *testing.T) {
+	var (
+		files []string
+		auth  string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Files []string `json:"files"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		files, auth = body.Files, r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	purger := cdn.NewHTTPPurger(server.URL, "purge-token", httpclient.New(httpclient.Options{Name: "test"}))
+
+	require.NoError(t, purger.Purge(context.Background(), []string{cdnURL}))
+	assert.Equal(t, []string{cdnURL}, files)
+	assert.Equal(t, "Bearer purge-token", auth)
+}
+
+func TestHTTPPurger_ReportsRejectedPurge(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte("invalid token"))
+	}))
+	t.Cleanup(server.Close)
+
+	purger := cdn.NewHTTPPurger(server.URL, "wrong", httpclient.New(httpclient.Options{Name: "test"}))
+
+	assert.EqualError(t, purger.Purge(context.Background(), []string{cdnURL}), "CDN purge failed with status 403: invalid token")
+}
+
+func TestCDNPurgingStorage_PurgesDeletedObjects(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mocks.MockStorage)
+	purger := new(mocks.MockCDNPurger)
+	s := storage.NewCDNPurgingStorage(inner, cdn.NewRewriter(projectURL, "https://img.jualansayur.id"), purger, "profile-images")
+
+	inner.On("DeleteFile", ctx, "", "profile-abc.jpg").Return(nil)
+	purger.On("Purge", ctx, []string{cdnURL}).Return(errors.New("CDN purge failed with status 500"))
+
+	assert.NoError(t, s.DeleteFile(ctx, "", "profile-abc.jpg"), "a failed purge does not fail the delete")
+	purger.AssertExpectations(t)
+}
+
+func TestCDNPurgingStorage_KeepsCacheWhenDeleteFails(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mocks.MockStorage)
+	purger := new(mocks.MockCDNPurger)
+	s := storage.NewCDNPurgingStorage(inner, cdn.NewRewriter(projectURL, "https://img.jualansayur.id"), purger, "profile-images")
+
+	inner.On("DeleteFile", ctx, "", "profile-abc.jpg").Return(errors.New("delete failed with status 502"))
+
+	assert.EqualError(t, s.DeleteFile(ctx, "", "profile-abc.jpg"), "delete failed with status 502")
+	purger.AssertNotCalled(t, "Purge", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type customerImportFixture struct {
+	userRepo  *mocks.MockUserRepository
+	tokenRepo *mocks.MockVerificationTokenRepository
+	email     *mocks.MockEmailPublisher
+	reports   *mocks.MockCustomerImportReportRepository
+	service   port.CustomerImportServiceInterface
+}
+
+func newCustomerImportFixture() *customerImportFixture {
+	f := &customerImportFixture{
+		userRepo:  new(mocks.MockUserRepository),
+		tokenRepo: new(mocks.MockVerificationTokenRepository),
+		email:     new(mocks.MockEmailPublisher),
+		reports:   new(mocks.MockCustomerImportReportRepository),
+	}
+	f.service = service.NewCustomerImportService(f.userRepo, f.tokenRepo, f.email, f.reports, nil, nil, nil)
+	return f
+}
+
+func TestImportCustomers_CreatesVerifiedAccountsAndInvites(t *testing.T) {
+	ctx := context.Background()
+	f := newCustomerImportFixture()
+
+	csv := "Name,Email,Phone\nBudi Santoso,Budi@Example.com,0812\n"
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "budi@example.com").Return(nil, errors.New("record not found"))
+	f.userRepo.On("CreateUser", ctx, mock.MatchedBy(func(user *entity.UserEntity) bool {
+		return user.Email == "budi@example.com" && user.IsVerified && user.Password != ""
+	})).Return(&entity.UserEntity{ID: 7, Email: "budi@example.com"}, nil)
+	f.tokenRepo.On("CreateVerificationToken", ctx, mock.MatchedBy(func(token *entity.VerificationTokenEntity) bool {
+		return token.UserID == 7 && token.TokenType == "password_reset"
+	})).Return(nil)
+	f.email.On("SendCustomerInviteEmail", ctx, "budi@example.com", "Budi Santoso", mock.AnythingOfType("string")).Return(nil)
+
+	result, err := f.service.ImportCustomers(ctx, strings.NewReader(csv), 1, false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.TotalRows)
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 1, result.InvitesSent)
+	assert.Empty(t, result.ReportID)
+	f.userRepo.AssertExpectations(t)
+	f.email.AssertExpectations(t)
+}
+
+func TestImportCustomers_DryRunReportsRowErrors(t *testing.T) {
+	ctx := context.Background()
+	f := newCustomerImportFixture()
+
+	csv := "email,name\nsiti@example.com,Siti\nsiti@example.com,Siti Dua\nnot-an-email,Joko\n"
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "siti@example.com").Return(nil, errors.New("record not found"))
+	f.reports.On("SaveReport", ctx, mock.AnythingOfType("string"), mock.MatchedBy(func(report []byte) bool {
+		return strings.Contains(string(report), "duplicate email, already on row 2")
+	}), mock.Anything).Return(nil)
+
+	result, err := f.service.ImportCustomers(ctx, strings.NewReader(csv), 1, true)
+
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 3, result.TotalRows)
+	assert.Equal(t, 1, result.Valid)
+	assert.Equal(t, 0, result.Imported)
+	assert.Equal(t, 2, result.Failed)
+	assert.Equal(t, 3, result.Errors[0].Row)
+	assert.NotEmpty(t, result.ReportID)
+	f.userRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	f.reports.AssertExpectations(t)
+}
+
+func TestImportCustomers_RejectsMissingColumns(t *testing.T) {
+	f := newCustomerImportFixture()
+
+	_, err := f.service.ImportCustomers(context.Background(), strings.NewReader("name,phone\nBudi,0812\n"), 1, false)
+
+	assert.EqualError(t, err, "csv header must contain name and email columns")
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuthService_GetCustomersNearby_Success(t *testing.T) {
+	// Setup
+	mockUserRepo := &mocks.MockUserRepository{}
+	authService := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
+
+	ctx := context.Background()
+	nearby := []entity.UserEntity{
+		{ID: 1, Name: "John Customer", Lat: -6.2088, Lng: 106.8456, DistanceKm: 0.4},
+		{ID: 3, Name: "Bob Customer", Lat: -6.2146, Lng: 106.8451, DistanceKm: 1.1},
+	}
+	mockUserRepo.On("GetCustomersNearby", ctx, -6.2100, 106.8450, 5.0, 20).Return(nearby, nil)
+
+	// Execute
+	customers, err := authService.GetCustomersNearby(ctx, -6.2100, 106.8450, 5, 20)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, customers, 2)
+	assert.Equal(t, 0.4, customers[0].DistanceKm)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestAuthService_GetCustomersNearby_DefaultLimit(t *testing.T) {
+	mockUserRepo := &mocks.MockUserRepository{}
+	authService := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
+
+	ctx := context.Background()
+	mockUserRepo.On("GetCustomersNearby", ctx, -6.2100, 106.8450, 5.0, 20).Return([]entity.UserEntity{}, nil)
+
+	_, err := authService.GetCustomersNearby(ctx, -6.2100, 106.8450, 5, 0)
+
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestAuthService_GetCustomersNearby_InvalidInput(t *testing.T) {
+	mockUserRepo := &mocks.MockUserRepository{}
+	authService := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
+
+	_, err := authService.GetCustomersNearby(context.Background(), 0, 0, 5, 20)
+	assert.Error(t, err)
+	assert.Equal(t, "invalid coordinates", err.Error())
+
+	_, err = authService.GetCustomersNearby(context.Background(), -6.21, 106.84, 0, 20)
+	assert.Error(t, err)
+	assert.Equal(t, "radius must be between 0 and 100 km", err.Error())
+
+	_, err = authService.GetCustomersNearby(context.Background(), -6.21, 106.84, 500, 20)
+	assert.Error(t, err)
+
+	mockUserRepo.AssertNotCalled(t, "GetCustomersNearby", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -45,7 +45,7 @@ func TestAuthService_GetCustomers_Success(t *testing.T) {
 	mockUserRepo.On("GetCustomers", mock.Anything, "", 1, 10, "").Return(expectedCustomers, expectedTotalCount, nil)
 
 	// Test service
-	authService := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, nil)
+	authService := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
 	customers, pagination, err := authService.GetCustomers(context.Background(), "", 1, 10, "")
 
 	// Assert
@@ -80,7 +80,7 @@ func TestAuthService_GetCustomers_WithSearch(t *testing.T) {
 	mockUserRepo.On("GetCustomers", mock.Anything, searchTerm, 1, 10, "").Return(expectedCustomers, expectedTotalCount, nil)
 
 	// Test service
-	authService := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, nil)
+	authService := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
 	customers, pagination, err := authService.GetCustomers(context.Background(), searchTerm, 1, 10, "")
 
 	// Assert
@@ -116,7 +116,7 @@ func TestAuthService_GetCustomers_WithPagination(t *testing.T) {
 	mockUserRepo.On("GetCustomers", mock.Anything, "", page, limit, "").Return(expectedCustomers, expectedTotalCount, nil)
 
 	// Test service
-	authService := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, nil)
+	authService := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
 	customers, pagination, err := authService.GetCustomers(context.Background(), "", page, limit, "")
 
 	// Assert
@@ -136,7 +136,7 @@ func TestAuthService_GetCustomers_RepositoryError(t *testing.T) {
 	mockUserRepo.On("GetCustomers", mock.Anything, "", 1, 10, "").Return(nil, int64(0), expectedError)
 
 	// Test service
-	authService := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, nil)
+	authService := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
 	customers, pagination, err := authService.GetCustomers(context.Background(), "", 1, 10, "")
 
 	// Assert
@@ -156,7 +156,7 @@ func TestAuthService_GetCustomers_EmptyResult(t *testing.T) {
 	mockUserRepo.On("GetCustomers", mock.Anything, "nonexistent", 1, 10, "").Return(expectedCustomers, expectedTotalCount, nil)
 
 	// Test service
-	authService := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, nil)
+	authService := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
 	customers, pagination, err := authService.GetCustomers(context.Background(), "nonexistent", 1, 10, "")
 
 	// Assert
@@ -190,7 +190,7 @@ func TestAuthService_GetCustomerByID_Success(t *testing.T) {
 	mockUserRepo.On("GetCustomerByID", mock.Anything, customerID).Return(expectedCustomer, nil)
 
 	// Test service
-	authService := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, nil)
+	authService := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
 	customer, err := authService.GetCustomerByID(context.Background(), customerID)
 
 	// Assert
@@ -208,7 +208,7 @@ func TestAuthService_GetCustomerByID_NotFound(t *testing.T) {
 	mockUserRepo.On("GetCustomerByID", mock.Anything, customerID).Return(nil, gorm.ErrRecordNotFound)
 
 	// Test service
-	authService := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, nil)
+	authService := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
 	customer, err := authService.GetCustomerByID(context.Background(), customerID)
 
 	// Assert
@@ -227,7 +227,7 @@ func TestAuthService_GetCustomerByID_RepositoryError(t *testing.T) {
 	mockUserRepo.On("GetCustomerByID", mock.Anything, customerID).Return(nil, expectedError)
 
 	// Test service
-	authService := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, nil)
+	authService := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
 	customer, err := authService.GetCustomerByID(context.Background(), customerID)
 
 	// Assert
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomerSuggestService_Suggest_CachesNormalizedQuery(t *testing.T) {
+	mockUserRepo := &mocks.MockUserRepository{}
+	suggestService := service.NewCustomerSuggestService(mockUserRepo, &config.Config{})
+
+	matches := []entity.UserEntity{{ID: 1, Name: "Budi Santoso", Email: "budi@example.com"}}
+	mockUserRepo.On("SuggestCustomers", mock.Anything, "bud", 10).Return(matches, nil).Once()
+
+	first, err := suggestService.Suggest(context.Background(), "  Bud ")
+	require.NoError(t, err)
+	second, err := suggestService.Suggest(context.Background(), "bud")
+	require.NoError(t, err)
+
+	assert.Equal(t, matches, first)
+	assert.Equal(t, matches, second)
+	mockUserRepo.AssertNumberOfCalls(t, "SuggestCustomers", 1)
+}
+
+func TestCustomerSuggestService_Suggest_IgnoresShortQuery(t *testing.T) {
+	mockUserRepo := &mocks.MockUserRepository{}
+	suggestService := service.NewCustomerSuggestService(mockUserRepo, &config.Config{})
+
+	customers, err := suggestService.Suggest(context.Background(), "bu")
+
+	require.NoError(t, err)
+	assert.Empty(t, customers)
+	mockUserRepo.AssertNotCalled(t, "SuggestCustomers", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCustomerSuggestService_Suggest_EnforcesLatencyBudget(t *testing.T) {
+	mockUserRepo := &mocks.MockUserRepository{}
+	suggestService := service.NewCustomerSuggestService(mockUserRepo, &config.Config{CustomerSuggest: config.CustomerSuggest{TimeoutMs: 10}})
+
+	mockUserRepo.On("SuggestCustomers", mock.Anything, "budi", 10).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return(nil, context.DeadlineExceeded)
+
+	_, err := suggestService.Suggest(context.Background(), "budi")
+
+	assert.EqualError(t, err, "customer suggestions timed out")
+}
+
+func TestCustomerSuggestService_Suggest_DoesNotCacheErrors(t *testing.T) {
+	mockUserRepo := &mocks.MockUserRepository{}
+	suggestService := service.NewCustomerSuggestService(mockUserRepo, &config.Config{})
+
+	mockUserRepo.On("SuggestCustomers", mock.Anything, "budi", 10).Return(nil, errors.New("connection refused")).Once()
+	mockUserRepo.On("SuggestCustomers", mock.Anything, "budi", 10).Return([]entity.UserEntity{{ID: 1}}, nil).Once()
+
+	_, err := suggestService.Suggest(context.Background(), "budi")
+	assert.EqualError(t, err, "connection refused")
+
+	customers, err := suggestService.Suggest(context.Background(), "budi")
+	require.NoError(t, err)
+	assert.Len(t, customers, 1)
+}
+
+func TestLRUCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := utils.NewLRUCache[int](2, time.Minute)
+	cache.Add("a", 1)
+	cache.Add("b", 2)
+	cache.Get("a")
+	cache.Add("c", 3)
+
+	_, ok := cache.Get("b")
+	assert.False(t, ok, "b was used least recently")
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestLRUCache_ExpiresEntries(t *testing.T) {
+	cache := utils.NewLRUCache[int](2, time.Millisecond)
+	cache.Add("a", 1)
+	time.Sleep(5 * time.Millisecond)
+
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestLRUCache_DisabledWithoutSize(t *testing.T) {
+	cache := utils.NewLRUCache[int](-1, time.Minute)
+	cache.Add("a", 1)
+
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+}
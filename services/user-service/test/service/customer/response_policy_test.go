@@ -0,0 +1,98 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-service/config"
+	"user-service/internal/adapter/handler"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponsePolicy_NearestRoleWithEntriesDecides(t *testing.T) {
+	policy := entity.ResponsePolicyEntity{
+		"Courier":    {"customer.email", "customer.username", "order.customer_id"},
+		"Dispatcher": {"customer.email"},
+	}
+
+	assert.Equal(t, []string{"email", "username"}, policy.HiddenFields([]string{"Courier"}, entity.ResponsePayloadCustomer))
+	assert.Equal(t, []string{"customer_id"}, policy.HiddenFields([]string{"Courier"}, "order"), "other payloads are kept apart")
+	assert.Equal(t, []string{"email", "username"}, policy.HiddenFields([]string{"Senior Courier", "Courier"}, entity.ResponsePayloadCustomer), "inherited from Courier")
+	assert.Equal(t, []string{"email"}, policy.HiddenFields([]string{"Dispatcher", "Courier"}, entity.ResponsePayloadCustomer), "own policy wins")
+	assert.Empty(t, policy.HiddenFields([]string{"Dispatcher", "Courier"}, "order"))
+	assert.Empty(t, policy.HiddenFields([]string{"Super Admin"}, entity.ResponsePayloadCustomer))
+}
+
+func TestResponsePolicy_DefaultsToHidingCustomerEmailFromCouriers(t *testing.T) {
+	assert.Contains(t, config.ResponsePolicy{}.Hidden()["Courier"], "customer.email")
+
+	configured := config.ResponsePolicy{HiddenFields: map[string][]string{"Partner": {"customer.email"}}}
+	assert.Equal(t, configured.HiddenFields, configured.Hidden())
+}
+
+func TestUserEntity_WithoutFields(t *testing.T) {
+	customer := entity.UserEntity{ID: 7, Email: "sari@example.com", Username: "sari", Address: "Jl. Kemang 1", PostalCode: "12730", Lat: -6.26, Lng: 106.81}
+
+	shaped, hidden := customer.WithoutFields([]string{"email", "address", "unknown"})
+
+	assert.Equal(t, []string{"email", "address"}, hidden)
+	assert.Empty(t, shaped.Email)
+	assert.Empty(t, shaped.Address)
+	assert.Empty(t, shaped.PostalCode)
+	assert.Zero(t, shaped.Lat)
+	assert.Equal(t, "sari", shaped.Username)
+	assert.Equal(t, "sari@example.com", customer.Email, "the original is not changed")
+}
+
+func TestGetCustomerByID_ShapesResponseForRole(t *testing.T) {
+	cases := []struct {
+		roles     []string
+		wantEmail string
+		wantHide  []interface{}
+	}{
+		{[]string{"Super Admin"}, "sari@example.com", nil},
+		{[]string{"Courier"}, "", []interface{}{"email", "username"}},
+		{[]string{"Night Courier", "Courier"}, "", []interface{}{"email", "username"}},
+	}
+	for _, tc := range cases {
+		mockUserRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+		h := handler.NewCustomerHandler(userService, nil, &config.Config{})
+
+		customer := entity.UserEntity{ID: 7, Name: "Sari", Email: "sari@example.com", Username: "sari", Phone: "08123"}
+		mockUserRepo.On("GetCustomerByID", mock.Anything, int64(7)).Return(&customer, nil)
+
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/admin/customers/7", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues("7")
+		c.Set(middleware.PrincipalContextKey, entity.PrincipalEntity{Type: entity.PrincipalTypeUser, Role: tc.roles[0]})
+		c.Set(middleware.UserRolesContextKey, tc.roles)
+
+		require.NoError(t, h.GetCustomerByID(c))
+
+		var body struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, tc.wantEmail, body.Data["email"], tc.roles)
+		assert.Equal(t, "08123", body.Data["phone"], tc.roles)
+		if tc.wantHide == nil {
+			assert.NotContains(t, body.Data, "hidden_fields", tc.roles)
+		} else {
+			assert.Equal(t, tc.wantHide, body.Data["hidden_fields"], tc.roles)
+		}
+	}
+}
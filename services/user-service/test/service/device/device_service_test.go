@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type signInFixture struct {
+	user        *entity.UserEntity
+	userRepo    *mocks.MockUserRepository
+	sessionRepo *mocks.MockSessionRepository
+	jwtUtil     *mocks.MockJWTUtil
+	email       *mocks.MockEmailPublisher
+	deviceRepo  *mocks.MockDeviceRepository
+	service     service.AuthServiceInterface
+}
+
+func newSignInFixture(ctx context.Context, client entity.ClientEntity) *signInFixture {
+	f := &signInFixture{
+		userRepo:    new(mocks.MockUserRepository),
+		sessionRepo: new(mocks.MockSessionRepository),
+		jwtUtil:     new(mocks.MockJWTUtil),
+		email:       new(mocks.MockEmailPublisher),
+		deviceRepo:  new(mocks.MockDeviceRepository),
+	}
+	f.service = service.NewAuthService(service.AuthServiceDeps{
+		UserRepo:       f.userRepo,
+		SessionRepo:    f.sessionRepo,
+		JWTUtil:        f.jwtUtil,
+		EmailPublisher: f.email,
+		DeviceRepo:     f.deviceRepo,
+	})
+
+	hashedPassword, _ := utils.HashPassword("password123")
+	f.user = &entity.UserEntity{
+		ID:       7,
+		Email:    "buyer@example.com",
+		Password: hashedPassword,
+		RoleName: "Customer",
+	}
+	f.userRepo.On("GetUserByEmail", ctx, "buyer@example.com").Return(f.user, nil)
+	f.jwtUtil.On("GenerateJWTWithSession", int64(7), "buyer@example.com", "Customer", mock.AnythingOfType("string"), false).Return("jwt-token", nil)
+	f.sessionRepo.On("StoreToken", ctx, int64(7), mock.AnythingOfType("string"), "jwt-token", false).Return(nil)
+	f.sessionRepo.On("SetSessionClient", ctx, int64(7), mock.AnythingOfType("string"), client, (*entity.LoginLocationEntity)(nil)).Return(nil)
+	return f
+}
+
+func (f *signInFixture) signIn(ctx context.Context, client entity.ClientEntity) error {
+	_, _, err := f.service.SignIn(ctx, entity.UserEntity{Email: "buyer@example.com", Password: "password123"}, client, false)
+	return err
+}
+
+func TestClientEntity_Fingerprint(t *testing.T) {
+	chrome := entity.ClientEntity{UserAgent: "Mozilla/5.0 Chrome", IPAddress: "10.0.0.1"}
+	sameBrowserOtherIP := entity.ClientEntity{UserAgent: "mozilla/5.0 chrome ", IPAddress: "10.0.0.2"}
+	app := entity.ClientEntity{UserAgent: "Mozilla/5.0 Chrome", DeviceID: "device-123"}
+
+	assert.Len(t, chrome.Fingerprint(), 64)
+	assert.Equal(t, chrome.Fingerprint(), sameBrowserOtherIP.Fingerprint())
+	assert.NotEqual(t, chrome.Fingerprint(), app.Fingerprint())
+}
+
+func TestAuthService_SignIn_NewDeviceSendsAlert(t *testing.T) {
+	ctx := context.Background()
+	client := entity.ClientEntity{UserAgent: "Mozilla/5.0 Firefox", IPAddress: "203.0.113.9"}
+	f := newSignInFixture(ctx, client)
+
+	firstSeen := time.Now().UTC()
+	f.deviceRepo.On("TouchDevice", ctx, int64(7), client).Return(&entity.DeviceEntity{ID: 3, UserID: 7, FirstSeenAt: firstSeen}, true, nil)
+	f.deviceRepo.On("CountDevices", ctx, int64(7)).Return(int64(2), nil)
+	f.email.On("SendNewDeviceSignInEmail", ctx, "buyer@example.com", "Mozilla/5.0 Firefox", "203.0.113.9", "", firstSeen).Return(nil)
+
+	assert.NoError(t, f.signIn(ctx, client))
+	f.sessionRepo.AssertExpectations(t)
+	f.deviceRepo.AssertExpectations(t)
+	f.email.AssertExpectations(t)
+}
+
+func TestAuthService_SignIn_NewDeviceAlertUsesUserTimezone(t *testing.T) {
+	ctx := context.Background()
+	client := entity.ClientEntity{UserAgent: "Mozilla/5.0 Firefox", IPAddress: "203.0.113.9"}
+	f := newSignInFixture(ctx, client)
+	f.user.Timezone = "Asia/Jakarta"
+
+	firstSeen := time.Date(2026, 3, 1, 17, 30, 0, 0, time.UTC)
+	f.deviceRepo.On("TouchDevice", ctx, int64(7), client).Return(&entity.DeviceEntity{ID: 3, UserID: 7, FirstSeenAt: firstSeen}, true, nil)
+	f.deviceRepo.On("CountDevices", ctx, int64(7)).Return(int64(2), nil)
+	f.email.On("SendNewDeviceSignInEmail", ctx, "buyer@example.com", "Mozilla/5.0 Firefox", "203.0.113.9", "", mock.MatchedBy(func(signedInAt time.Time) bool {
+		// Same instant, shown as 00:30 the next day in Jakarta
+		return signedInAt.Equal(firstSeen) && signedInAt.Location().String() == "Asia/Jakarta" && signedInAt.Hour() == 0
+	})).Return(nil)
+
+	assert.NoError(t, f.signIn(ctx, client))
+	f.email.AssertExpectations(t)
+}
+
+func TestAuthService_SignIn_NewDeviceAlertNamesTheBrowser(t *testing.T) {
+	ctx := context.Background()
+	client := entity.ClientEntity{
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36",
+		IPAddress: "203.0.113.9",
+	}
+	f := newSignInFixture(ctx, client)
+
+	firstSeen := time.Now().UTC()
+	f.deviceRepo.On("TouchDevice", ctx, int64(7), client).Return(&entity.DeviceEntity{ID: 3, UserID: 7, FirstSeenAt: firstSeen}, true, nil)
+	f.deviceRepo.On("CountDevices", ctx, int64(7)).Return(int64(2), nil)
+	f.email.On("SendNewDeviceSignInEmail", ctx, "buyer@example.com", "Chrome on Windows", "203.0.113.9", "", firstSeen).Return(nil)
+
+	assert.NoError(t, f.signIn(ctx, client))
+	f.email.AssertExpectations(t)
+}
+
+func TestDeviceDisplayName(t *testing.T) {
+	cases := map[string]string{
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Safari/537.36 Edg/126.0.0.0":           "Edge on Windows",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_5 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.5 Mobile/15E148 Safari/604.1": "Safari on iPhone",
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 14.5; rv:127.0) Gecko/20100101 Firefox/127.0":                                                     "Firefox on macOS",
+		"Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/126.0.0.0 Mobile Safari/537.36":                   "Chrome on Android",
+		"okhttp/4.12.0": "Android app",
+		"curl/8.0":      "",
+		"":              "",
+	}
+	for userAgent, want := range cases {
+		assert.Equal(t, want, utils.DeviceDisplayName(userAgent), userAgent)
+	}
+}
+
+func TestLoginLocationEntity_Place(t *testing.T) {
+	assert.Equal(t, "Jakarta, Indonesia", (&entity.LoginLocationEntity{City: "Jakarta", Country: "Indonesia"}).Place())
+	assert.Equal(t, "Indonesia", (&entity.LoginLocationEntity{Country: "Indonesia"}).Place())
+	assert.Equal(t, "", (*entity.LoginLocationEntity)(nil).Place())
+}
+
+func TestAuthService_SignIn_FirstDeviceNoAlert(t *testing.T) {
+	ctx := context.Background()
+	client := entity.ClientEntity{UserAgent: "Mozilla/5.0 Firefox", IPAddress: "203.0.113.9"}
+	f := newSignInFixture(ctx, client)
+
+	f.deviceRepo.On("TouchDevice", ctx, int64(7), client).Return(&entity.DeviceEntity{ID: 1, UserID: 7}, true, nil)
+	f.deviceRepo.On("CountDevices", ctx, int64(7)).Return(int64(1), nil)
+
+	assert.NoError(t, f.signIn(ctx, client))
+	f.email.AssertNotCalled(t, "SendNewDeviceSignInEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_SignIn_KnownDeviceNoAlert(t *testing.T) {
+	ctx := context.Background()
+	client := entity.ClientEntity{UserAgent: "Mozilla/5.0 Firefox", IPAddress: "203.0.113.9"}
+	f := newSignInFixture(ctx, client)
+
+	f.deviceRepo.On("TouchDevice", ctx, int64(7), client).Return(&entity.DeviceEntity{ID: 1, UserID: 7}, false, nil)
+
+	assert.NoError(t, f.signIn(ctx, client))
+	f.deviceRepo.AssertNotCalled(t, "CountDevices", mock.Anything, mock.Anything)
+	f.email.AssertNotCalled(t, "SendNewDeviceSignInEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_SignIn_DeviceErrorDoesNotBlockSignIn(t *testing.T) {
+	ctx := context.Background()
+	client := entity.ClientEntity{UserAgent: "curl/8.0"}
+	f := newSignInFixture(ctx, client)
+
+	f.deviceRepo.On("TouchDevice", ctx, int64(7), client).Return(nil, false, errors.New("connection refused"))
+
+	assert.NoError(t, f.signIn(ctx, client))
+	f.email.AssertNotCalled(t, "SendNewDeviceSignInEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestDeviceService_SetTrusted(t *testing.T) {
+	ctx := context.Background()
+	mockRepo := new(mocks.MockDeviceRepository)
+	deviceService := service.NewDeviceService(mockRepo)
+
+	mockRepo.On("SetTrusted", ctx, int64(7), int64(3), true).Return(nil)
+	mockRepo.On("SetTrusted", ctx, int64(7), int64(99), true).Return(gorm.ErrRecordNotFound)
+
+	assert.NoError(t, deviceService.SetTrusted(ctx, 7, 3, true))
+
+	err := deviceService.SetTrusted(ctx, 7, 99, true)
+	assert.Error(t, err)
+	assert.Equal(t, "device not found", err.Error())
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"user-service/config"
+	"user-service/internal/diagnostics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnosticsValidate_RequiresTokenBeyondLoopback(t *testing.T) {
+	assert.NoError(t, config.Diagnostics{}.Validate())
+	assert.NoError(t, config.Diagnostics{Enabled: true}.Validate())
+	assert.NoError(t, config.Diagnostics{Enabled: true, Addr: "localhost:6060"}.Validate())
+	assert.NoError(t, config.Diagnostics{Enabled: true, Addr: "[::1]:6060"}.Validate())
+	assert.NoError(t, config.Diagnostics{Enabled: true, Addr: ":6060", Token: "s3cret"}.Validate())
+
+	assert.Error(t, config.Diagnostics{Enabled: true, Addr: ":6060"}.Validate())
+	assert.Error(t, config.Diagnostics{Enabled: true, Addr: "10.0.0.5:6060"}.Validate())
+	assert.Error(t, config.Diagnostics{Enabled: true, Addr: "6060"}.Validate())
+}
+
+func TestDiagnosticsHandler_ChecksToken(t *testing.T) {
+	h := diagnostics.Handler(config.Diagnostics{Token: "s3cret"})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/vars", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"goroutines"`)
+	assert.Contains(t, rec.Body.String(), `"memstats"`)
+}
+
+func TestDiagnosticsDump_WritesGoroutineAndHeapFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "dumps")
+	h := diagnostics.Handler(config.Diagnostics{DumpDir: dir})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/dump", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/dump", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp struct {
+		Goroutine string `json:"goroutine"`
+		Heap      string `json:"heap"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, dir, filepath.Dir(resp.Goroutine))
+
+	goroutines, err := os.ReadFile(resp.Goroutine)
+	require.NoError(t, err)
+	assert.Contains(t, string(goroutines), "goroutine ")
+	heap, err := os.Stat(resp.Heap)
+	require.NoError(t, err)
+	assert.NotZero(t, heap.Size())
+}
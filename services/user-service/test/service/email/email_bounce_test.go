@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/adapter/handler"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type bounceFixture struct {
+	userRepo  *mocks.MockUserRepository
+	tokenRepo *mocks.MockVerificationTokenRepository
+	publisher *mocks.MockEmailPublisher
+	service   interface {
+		ResendVerificationEmail(ctx context.Context, email string) error
+		RecordEmailBounce(ctx context.Context, email, bounceType, reason string) error
+	}
+}
+
+func newBounceFixture() *bounceFixture {
+	f := &bounceFixture{
+		userRepo:  new(mocks.MockUserRepository),
+		tokenRepo: new(mocks.MockVerificationTokenRepository),
+		publisher: new(mocks.MockEmailPublisher),
+	}
+	f.service = service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              f.userRepo,
+			VerificationTokenRepo: f.tokenRepo,
+			EmailPublisher:        f.publisher,
+		},
+		Config: &config.Config{},
+	})
+	return f
+}
+
+func TestRecordEmailBounce_HardBounceInvalidatesVerification(t *testing.T) {
+	ctx := context.Background()
+	f := newBounceFixture()
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "budi@example.com").Return(&entity.UserEntity{ID: 7, Email: "budi@example.com"}, nil)
+	f.userRepo.On("MarkEmailBounced", ctx, int64(7), "budi@example.com", "550 mailbox does not exist").Return(nil)
+	f.tokenRepo.On("DeleteUserTokens", ctx, int64(7), entity.TokenTypeEmailVerification).Return(int64(1), nil)
+
+	err := f.service.RecordEmailBounce(ctx, " Budi@Example.com ", entity.EmailBounceHard, "550 mailbox does not exist")
+
+	assert.NoError(t, err)
+	f.userRepo.AssertExpectations(t)
+	f.tokenRepo.AssertExpectations(t)
+}
+
+func TestRecordEmailBounce_VerifiedAccountKeepsTokens(t *testing.T) {
+	ctx := context.Background()
+	f := newBounceFixture()
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "budi@example.com").Return(&entity.UserEntity{ID: 7, Email: "budi@example.com", IsVerified: true}, nil)
+	f.userRepo.On("MarkEmailBounced", ctx, int64(7), "budi@example.com", "").Return(nil)
+
+	assert.NoError(t, f.service.RecordEmailBounce(ctx, "budi@example.com", entity.EmailBounceHard, ""))
+	f.tokenRepo.AssertNotCalled(t, "DeleteUserTokens", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecordEmailBounce_SoftBounceIsIgnored(t *testing.T) {
+	f := newBounceFixture()
+
+	assert.NoError(t, f.service.RecordEmailBounce(context.Background(), "budi@example.com", entity.EmailBounceSoft, "mailbox full"))
+	f.userRepo.AssertNotCalled(t, "MarkEmailBounced", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecordEmailBounce_UnknownAddress(t *testing.T) {
+	ctx := context.Background()
+	f := newBounceFixture()
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "ghost@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	assert.EqualError(t, f.service.RecordEmailBounce(ctx, "ghost@example.com", entity.EmailBounceHard, ""), "user not found")
+}
+
+func TestResendVerificationEmail_BouncedAddressIsRejected(t *testing.T) {
+	ctx := context.Background()
+	f := newBounceFixture()
+	bouncedAt := time.Now().Add(-time.Hour)
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "budi@example.com").Return(&entity.UserEntity{ID: 7, Email: "budi@example.com", BouncedAt: &bouncedAt}, nil)
+
+	err := f.service.ResendVerificationEmail(ctx, "budi@example.com")
+
+	assert.ErrorIs(t, err, service.ErrEmailUndeliverable)
+	f.publisher.AssertNotCalled(t, "SendVerificationEmail", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResendVerificationEmail_SendsNewLink(t *testing.T) {
+	ctx := context.Background()
+	f := newBounceFixture()
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "budi@example.com").Return(&entity.UserEntity{ID: 7, Email: "budi@example.com"}, nil)
+	f.tokenRepo.On("DeleteUserTokens", ctx, int64(7), entity.TokenTypeEmailVerification).Return(int64(1), nil)
+	f.tokenRepo.On("CreateVerificationToken", ctx, mock.MatchedBy(func(token *entity.VerificationTokenEntity) bool {
+		return token.UserID == 7 && token.TokenType == entity.TokenTypeEmailVerification
+	})).Return(nil)
+	f.publisher.On("SendVerificationEmail", ctx, "budi@example.com", mock.AnythingOfType("string")).Return(nil)
+
+	assert.NoError(t, f.service.ResendVerificationEmail(ctx, "budi@example.com"))
+	f.tokenRepo.AssertExpectations(t)
+	f.publisher.AssertExpectations(t)
+}
+
+func TestResendVerificationEmail_UnknownAndVerifiedSucceedSilently(t *testing.T) {
+	ctx := context.Background()
+	f := newBounceFixture()
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "ghost@example.com").Return(nil, gorm.ErrRecordNotFound)
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "done@example.com").Return(&entity.UserEntity{ID: 8, IsVerified: true}, nil)
+
+	assert.NoError(t, f.service.ResendVerificationEmail(ctx, "ghost@example.com"))
+	assert.NoError(t, f.service.ResendVerificationEmail(ctx, "done@example.com"))
+	f.publisher.AssertNotCalled(t, "SendVerificationEmail", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecordEmailBounceHandler_ValidatesType(t *testing.T) {
+	h := handler.NewInternalHandler(nil, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/internal/emails/bounces", strings.NewReader(`{"email":"budi@example.com","type":"complaint"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	assert.NoError(t, h.RecordEmailBounce(echo.New().NewContext(req, rec)))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestRecordEmailBounceHandler_UnknownAddress(t *testing.T) {
+	f := newBounceFixture()
+	h := handler.NewInternalHandler(service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              f.userRepo,
+			VerificationTokenRepo: f.tokenRepo,
+			EmailPublisher:        f.publisher,
+		},
+		Config: &config.Config{},
+	}), nil)
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", mock.Anything, "ghost@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	req := httptest.NewRequest(http.MethodPost, "/internal/emails/bounces", strings.NewReader(`{"email":"ghost@example.com","type":"hard"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	assert.NoError(t, h.RecordEmailBounce(echo.New().NewContext(req, rec)))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -18,7 +18,10 @@ func TestAuthService_VerifyEmailChange_Success(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
-	service := service.NewAuthService(mockUserRepo, nil, nil, mockVerificationTokenRepo, nil, nil, nil)
+	service := service.NewAuthService(service.AuthServiceDeps{
+		UserRepo:              mockUserRepo,
+		VerificationTokenRepo: mockVerificationTokenRepo,
+	})
 
 	ctx := context.Background()
 	token := "valid-email-change-token"
@@ -36,9 +39,10 @@ func TestAuthService_VerifyEmailChange_Success(t *testing.T) {
 
 	// Mock expectations
 	mockVerificationTokenRepo.On("GetVerificationToken", ctx, token).Return(verificationToken, nil)
+	mockUserRepo.On("GetUserByID", ctx, userID).Return(&entity.UserEntity{ID: userID, Email: "oldemail@example.com"}, nil)
 	mockUserRepo.On("UpdateUserEmail", ctx, userID, newEmail).Return(nil)
 	mockUserRepo.On("UpdateUserVerificationStatus", ctx, userID, true).Return(nil)
-	mockVerificationTokenRepo.On("DeleteVerificationToken", ctx, token).Return(nil)
+	mockVerificationTokenRepo.On("ClaimVerificationToken", ctx, token).Return(nil)
 
 	// Execute
 	err := service.VerifyEmailChange(ctx, token)
@@ -52,7 +56,7 @@ func TestAuthService_VerifyEmailChange_Success(t *testing.T) {
 func TestAuthService_VerifyEmailChange_InvalidToken(t *testing.T) {
 	// Setup
 	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
-	service := service.NewAuthService(nil, nil, nil, mockVerificationTokenRepo, nil, nil, nil)
+	service := service.NewAuthService(service.AuthServiceDeps{VerificationTokenRepo: mockVerificationTokenRepo})
 
 	ctx := context.Background()
 	token := "invalid-token"
@@ -72,7 +76,7 @@ func TestAuthService_VerifyEmailChange_InvalidToken(t *testing.T) {
 func TestAuthService_VerifyEmailChange_WrongTokenType(t *testing.T) {
 	// Setup
 	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
-	service := service.NewAuthService(nil, nil, nil, mockVerificationTokenRepo, nil, nil, nil)
+	service := service.NewAuthService(service.AuthServiceDeps{VerificationTokenRepo: mockVerificationTokenRepo})
 
 	ctx := context.Background()
 	token := "wrong-type-token"
@@ -101,7 +105,7 @@ func TestAuthService_VerifyEmailChange_WrongTokenType(t *testing.T) {
 func TestAuthService_VerifyEmailChange_MissingNewEmail(t *testing.T) {
 	// Setup
 	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
-	service := service.NewAuthService(nil, nil, nil, mockVerificationTokenRepo, nil, nil, nil)
+	service := service.NewAuthService(service.AuthServiceDeps{VerificationTokenRepo: mockVerificationTokenRepo})
 
 	ctx := context.Background()
 	token := "missing-email-token"
@@ -131,7 +135,10 @@ func TestAuthService_VerifyEmailChange_UpdateEmailFailure(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
-	service := service.NewAuthService(mockUserRepo, nil, nil, mockVerificationTokenRepo, nil, nil, nil)
+	service := service.NewAuthService(service.AuthServiceDeps{
+		UserRepo:              mockUserRepo,
+		VerificationTokenRepo: mockVerificationTokenRepo,
+	})
 
 	ctx := context.Background()
 	token := "update-failure-token"
@@ -149,7 +156,10 @@ func TestAuthService_VerifyEmailChange_UpdateEmailFailure(t *testing.T) {
 
 	// Mock expectations
 	mockVerificationTokenRepo.On("GetVerificationToken", ctx, token).Return(verificationToken, nil)
+	mockUserRepo.On("GetUserByID", ctx, userID).Return(&entity.UserEntity{ID: userID, Email: "oldemail@example.com"}, nil)
+	mockVerificationTokenRepo.On("ClaimVerificationToken", ctx, token).Return(nil)
 	mockUserRepo.On("UpdateUserEmail", ctx, userID, newEmail).Return(errors.New("database error"))
+	mockVerificationTokenRepo.On("ReleaseVerificationToken", ctx, token).Return(nil)
 
 	// Execute
 	err := service.VerifyEmailChange(ctx, token)
@@ -160,7 +170,6 @@ func TestAuthService_VerifyEmailChange_UpdateEmailFailure(t *testing.T) {
 	mockVerificationTokenRepo.AssertExpectations(t)
 	mockUserRepo.AssertExpectations(t)
 	mockUserRepo.AssertNotCalled(t, "UpdateUserVerificationStatus", mock.Anything, mock.Anything, mock.Anything)
-	mockVerificationTokenRepo.AssertNotCalled(t, "DeleteVerificationToken", mock.Anything, mock.Anything)
 }
 
 func TestAuthService_CompleteEmailChangeFlow(t *testing.T) {
@@ -170,7 +179,13 @@ func TestAuthService_CompleteEmailChangeFlow(t *testing.T) {
 	mockJWTUtil := new(mocks.MockJWTUtil)
 	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
 	mockEmailPublisher := new(mocks.MockEmailPublisher)
-	service := service.NewAuthService(mockUserRepo, mockSessionRepo, mockJWTUtil, mockVerificationTokenRepo, mockEmailPublisher, nil, nil)
+	service := service.NewAuthService(service.AuthServiceDeps{
+		UserRepo:              mockUserRepo,
+		SessionRepo:           mockSessionRepo,
+		JWTUtil:               mockJWTUtil,
+		VerificationTokenRepo: mockVerificationTokenRepo,
+		EmailPublisher:        mockEmailPublisher,
+	})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -198,13 +213,13 @@ func TestAuthService_CompleteEmailChangeFlow(t *testing.T) {
 		assert.Equal(t, userID, token.UserID)
 		assert.Equal(t, "email_change", token.TokenType)
 		assert.Equal(t, newEmail, token.NewEmail)
-	})
+	}).Once()
 	mockEmailPublisher.On("SendEmailChangeVerificationEmail", ctx, newEmail, mock.AnythingOfType("string")).Return(nil)
 	mockUserRepo.On("UpdateUserVerificationStatus", ctx, userID, false).Return(nil)
-	mockUserRepo.On("UpdateUserProfile", ctx, userID, name, oldEmail, phone, address, lat, lng, photo).Return(nil)
+	mockUserRepo.On("UpdateUserProfile", ctx, userID, name, oldEmail, phone, address, lat, lng, photo, int64(0)).Return(nil)
 
 	// Execute UpdateProfile
-	err := service.UpdateProfile(ctx, userID, name, newEmail, phone, address, lat, lng, photo)
+	err := service.UpdateProfile(ctx, userID, name, newEmail, phone, address, lat, lng, photo, 0)
 	assert.NoError(t, err)
 
 	// Step 2: Verify Email Change
@@ -221,7 +236,14 @@ func TestAuthService_CompleteEmailChangeFlow(t *testing.T) {
 	mockVerificationTokenRepo.On("GetVerificationToken", ctx, token).Return(verificationToken, nil)
 	mockUserRepo.On("UpdateUserEmail", ctx, userID, newEmail).Return(nil)
 	mockUserRepo.On("UpdateUserVerificationStatus", ctx, userID, true).Return(nil)
-	mockVerificationTokenRepo.On("DeleteVerificationToken", ctx, token).Return(nil)
+	mockVerificationTokenRepo.On("ClaimVerificationToken", ctx, token).Return(nil)
+	// The old address gets a link to undo the change
+	mockVerificationTokenRepo.On("CreateVerificationToken", ctx, mock.AnythingOfType("*entity.VerificationTokenEntity")).Return(nil).Run(func(args mock.Arguments) {
+		token := args.Get(1).(*entity.VerificationTokenEntity)
+		assert.Equal(t, entity.TokenTypeEmailChangeRevert, token.TokenType)
+		assert.Equal(t, oldEmail, token.NewEmail)
+	}).Once()
+	mockEmailPublisher.On("SendEmailChangeRevertEmail", ctx, oldEmail, newEmail, mock.AnythingOfType("string")).Return(nil)
 
 	// Execute VerifyEmailChange
 	err = service.VerifyEmailChange(ctx, token)
@@ -239,14 +261,15 @@ func TestAuthService_CompleteEmailChangeFlow(t *testing.T) {
 	}
 
 	mockUserRepo.On("GetUserByEmail", ctx, newEmail).Return(updatedUser, nil)
-	mockJWTUtil.On("GenerateJWTWithSession", userID, newEmail, "Customer", mock.AnythingOfType("string")).Return("jwt-token", nil)
-	mockSessionRepo.On("StoreToken", ctx, userID, mock.AnythingOfType("string"), "jwt-token").Return(nil)
+	mockJWTUtil.On("GenerateJWTWithSession", userID, newEmail, "Customer", mock.AnythingOfType("string"), false).Return("jwt-token", nil)
+	mockSessionRepo.On("StoreToken", ctx, userID, mock.AnythingOfType("string"), "jwt-token", false).Return(nil)
+	mockSessionRepo.On("SetSessionClient", ctx, userID, mock.AnythingOfType("string"), entity.ClientEntity{}, (*entity.LoginLocationEntity)(nil)).Return(nil)
 
 	// Execute SignIn with new email
 	user, jwtToken, err := service.SignIn(ctx, entity.UserEntity{
 		Email:    newEmail,
 		Password: "password123",
-	})
+	}, entity.ClientEntity{}, false)
 
 	assert.NoError(t, err)
 	assert.NotNil(t, user)
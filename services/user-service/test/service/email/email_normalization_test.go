@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	keep := utils.NewEmailPolicy(utils.PlusAddressingKeep, true, nil)
+	strip := utils.NewEmailPolicy(utils.PlusAddressingStrip, true, nil)
+
+	cases := []struct {
+		name   string
+		policy *utils.EmailPolicy
+		input  string
+		want   string
+	}{
+		{"trims and lowercases", keep, "  Budi@Example.COM ", "budi@example.com"},
+		{"gmail ignores dots", keep, "Budi.Santoso@gmail.com", "budisantoso@gmail.com"},
+		{"googlemail is gmail", keep, "budi.s@googlemail.com", "budis@gmail.com"},
+		{"plus tag kept", keep, "budi+promo@example.com", "budi+promo@example.com"},
+		{"plus tag stripped", strip, "budi+promo@example.com", "budi@example.com"},
+		{"unicode domain to punycode", keep, "siti@bücher.de", "siti@xn--bcher-kva.de"},
+		{"decomposed unicode is composed", keep, "jose\u0301@example.com", "jos\u00e9@example.com"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.policy.NormalizeEmail(tc.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	for _, invalid := range []string{"", "budi", "budi@", "@example.com", "budi@localhost", "Budi <budi@example.com>", "budi @example.com"} {
+		_, err := keep.NormalizeEmail(invalid)
+		assert.ErrorIs(t, err, utils.ErrInvalidEmail, invalid)
+	}
+}
+
+func TestCheckDomain_BlocksDisposableDomainsAndSubdomains(t *testing.T) {
+	policy := utils.NewEmailPolicy(utils.PlusAddressingKeep, true, []string{"Throwaway.Example"})
+
+	assert.ErrorIs(t, policy.CheckDomain("budi@mailinator.com"), utils.ErrDisposableEmail)
+	assert.ErrorIs(t, policy.CheckDomain("budi@eu.mailinator.com"), utils.ErrDisposableEmail)
+	assert.ErrorIs(t, policy.CheckDomain("budi@throwaway.example"), utils.ErrDisposableEmail)
+	assert.NoError(t, policy.CheckDomain("budi@gmail.com"))
+	assert.NoError(t, utils.NewEmailPolicy(utils.PlusAddressingKeep, false, nil).CheckDomain("budi@mailinator.com"))
+}
+
+func TestCreateUserAccount_RejectsDisposableEmail(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	cfg := &config.Config{Email: config.Email{BlockDisposable: true}}
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo},
+		Config:          cfg,
+	})
+
+	err := userService.CreateUserAccount(context.Background(), "budi@yopmail.com", "Budi", "password123", "password123", "", 0, 0, nil)
+
+	assert.EqualError(t, err, "disposable email addresses are not allowed")
+	mockUserRepo.AssertNotCalled(t, "GetUserByEmailIncludingUnverified", mock.Anything, mock.Anything)
+}
+
+func TestCreateUserAccount_StoresNormalizedGmailAddress(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockTokenRepo := new(mocks.MockVerificationTokenRepository)
+	mockEmail := new(mocks.MockEmailPublisher)
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              mockUserRepo,
+			VerificationTokenRepo: mockTokenRepo,
+			EmailPublisher:        mockEmail,
+		},
+		Config: &config.Config{},
+	})
+
+	mockUserRepo.On("GetUserByEmailIncludingUnverified", ctx, "budisantoso@gmail.com").Return(nil, errors.New("record not found"))
+	mockUserRepo.On("CreateUser", ctx, mock.MatchedBy(func(user *entity.UserEntity) bool {
+		return user.Email == "budisantoso@gmail.com"
+	})).Return(&entity.UserEntity{ID: 1, Email: "budisantoso@gmail.com"}, nil)
+	mockTokenRepo.On("CreateVerificationToken", ctx, mock.Anything).Return(nil)
+	mockEmail.On("SendVerificationEmail", ctx, "budisantoso@gmail.com", mock.AnythingOfType("string")).Return(nil)
+
+	err := userService.CreateUserAccount(ctx, "Budi.Santoso@GoogleMail.com", "Budi", "password123", "password123", "", 0, 0, nil)
+
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestForgotPassword_FallsBackToAddressAsTyped(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := new(mocks.MockUserRepository)
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo},
+		Config:          &config.Config{},
+	})
+
+	// An account stored before normalization keeps its dotted spelling
+	mockUserRepo.On("GetUserByEmail", ctx, "budisantoso@gmail.com").Return(nil, errors.New("record not found"))
+	mockUserRepo.On("GetUserByEmail", ctx, "budi.santoso@gmail.com").Return(&entity.UserEntity{ID: 3, Email: "budi.santoso@gmail.com"}, nil)
+
+	err := userService.ForgotPassword(ctx, "Budi.Santoso@gmail.com")
+
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+}
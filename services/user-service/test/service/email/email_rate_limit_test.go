@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newEmailRateLimitService() (port.EmailRateLimitServiceInterface, *mocks.MockEmailRateLimitRepository, *mocks.MockAuditLogRepository) {
+	limitRepo := new(mocks.MockEmailRateLimitRepository)
+	auditRepo := new(mocks.MockAuditLogRepository)
+	auditRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+	return service.NewEmailRateLimitService(limitRepo, service.NewAuditLogService(auditRepo)), limitRepo, auditRepo
+}
+
+func TestEmailRateLimitService_SetLimitPublishesSnapshot(t *testing.T) {
+	svc, limitRepo, auditRepo := newEmailRateLimitService()
+	ctx := context.Background()
+
+	stored := []entity.EmailRateLimitEntity{
+		{EmailType: entity.EmailRateLimitAllEmails, MaxPerHour: 20},
+		{EmailType: "back_in_stock", MaxPerHour: 3, UpdatedBy: 1},
+	}
+	limitRepo.On("UpsertLimit", ctx, mock.MatchedBy(func(l *entity.EmailRateLimitEntity) bool {
+		return l.EmailType == "back_in_stock" && l.MaxPerHour == 3 && l.UpdatedBy == 1
+	})).Return(&stored[1], nil)
+	limitRepo.On("GetAllLimits", ctx).Return(stored, nil)
+	limitRepo.On("PublishLimits", ctx, stored).Return(nil)
+
+	limit, err := svc.SetLimit(ctx, 1, " Back_In_Stock ", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "back_in_stock", limit.EmailType)
+
+	limitRepo.AssertExpectations(t)
+	auditRepo.AssertCalled(t, "Create", mock.Anything, mock.MatchedBy(func(a *entity.AuditLogEntity) bool {
+		return a.Event == entity.AuditEventEmailRateLimitChanged && a.UserID == 1
+	}))
+}
+
+func TestEmailRateLimitService_SetLimitValidates(t *testing.T) {
+	svc, limitRepo, _ := newEmailRateLimitService()
+	ctx := context.Background()
+
+	_, err := svc.SetLimit(ctx, 1, "back-in-stock", 3)
+	assert.EqualError(t, err, "email type must be * or a template name such as back_in_stock")
+
+	_, err = svc.SetLimit(ctx, 1, "*", 1001)
+	assert.EqualError(t, err, "max per hour must be between 0 and 1000")
+
+	_, err = svc.SetLimit(ctx, 1, "*", -1)
+	assert.EqualError(t, err, "max per hour must be between 0 and 1000")
+
+	limitRepo.AssertNotCalled(t, "UpsertLimit", mock.Anything, mock.Anything)
+}
+
+func TestEmailRateLimitService_SetLimitReportsUnpublishedChange(t *testing.T) {
+	svc, limitRepo, _ := newEmailRateLimitService()
+	ctx := context.Background()
+
+	limit := entity.EmailRateLimitEntity{EmailType: entity.EmailRateLimitAllEmails, MaxPerHour: 0}
+	limitRepo.On("UpsertLimit", ctx, mock.Anything).Return(&limit, nil)
+	limitRepo.On("GetAllLimits", ctx).Return([]entity.EmailRateLimitEntity{limit}, nil)
+	limitRepo.On("PublishLimits", ctx, mock.Anything).Return(errors.New("redis: connection refused"))
+
+	_, err := svc.SetLimit(ctx, 1, "*", 0)
+	assert.EqualError(t, err, "failed to apply email rate limits")
+}
+
+func TestEmailRateLimitService_DeleteLimit(t *testing.T) {
+	svc, limitRepo, _ := newEmailRateLimitService()
+	ctx := context.Background()
+
+	limitRepo.On("DeleteLimit", ctx, "unknown_type").Return(gorm.ErrRecordNotFound)
+	err := svc.DeleteLimit(ctx, 1, "unknown_type")
+	assert.EqualError(t, err, "email rate limit not found")
+
+	limitRepo.On("DeleteLimit", ctx, "back_in_stock").Return(nil)
+	limitRepo.On("GetAllLimits", ctx).Return([]entity.EmailRateLimitEntity{}, nil)
+	limitRepo.On("PublishLimits", ctx, []entity.EmailRateLimitEntity{}).Return(nil)
+
+	assert.NoError(t, svc.DeleteLimit(ctx, 1, "back_in_stock"))
+	limitRepo.AssertCalled(t, "PublishLimits", ctx, []entity.EmailRateLimitEntity{})
+}
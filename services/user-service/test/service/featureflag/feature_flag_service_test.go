@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestFeatureFlagEntity_IsEnabledFor(t *testing.T) {
+	flag := entity.FeatureFlagEntity{
+		Key:               "two_factor_auth",
+		Enabled:           false,
+		RolloutPercentage: 100,
+		Overrides:         map[int64]bool{7: true},
+	}
+
+	assert.True(t, flag.IsEnabledFor(7), "override wins over disabled flag")
+	assert.False(t, flag.IsEnabledFor(8))
+
+	flag.Enabled = true
+	flag.Overrides = map[int64]bool{7: false}
+	assert.False(t, flag.IsEnabledFor(7), "override can opt a user out")
+	assert.True(t, flag.IsEnabledFor(8))
+	assert.True(t, flag.IsEnabledFor(0), "full rollout includes anonymous users")
+
+	flag.RolloutPercentage = 50
+	assert.False(t, flag.IsEnabledFor(0), "partial rollout excludes anonymous users")
+}
+
+func TestFeatureFlagEntity_RolloutIsStableAndProportional(t *testing.T) {
+	flag := entity.FeatureFlagEntity{Key: "new_storage_backend", Enabled: true, RolloutPercentage: 25}
+
+	enabled := 0
+	for userID := int64(1); userID <= 10000; userID++ {
+		if flag.IsEnabledFor(userID) {
+			enabled++
+		}
+		assert.Equal(t, flag.IsEnabledFor(userID), flag.IsEnabledFor(userID))
+	}
+	assert.InDelta(t, 2500, enabled, 300)
+
+	// Growing the rollout never drops users who already had the feature
+	wider := flag
+	wider.RolloutPercentage = 60
+	for userID := int64(1); userID <= 1000; userID++ {
+		if flag.IsEnabledFor(userID) {
+			assert.True(t, wider.IsEnabledFor(userID))
+		}
+	}
+}
+
+func TestFeatureFlagService_IsEnabled_FailsClosed(t *testing.T) {
+	mockFlagRepo := new(mocks.MockFeatureFlagRepository)
+	flagService := service.NewFeatureFlagService(mockFlagRepo)
+	ctx := context.Background()
+
+	mockFlagRepo.On("GetFlagByKey", ctx, "missing").Return(nil, gorm.ErrRecordNotFound)
+	mockFlagRepo.On("GetFlagByKey", ctx, "broken").Return(nil, errors.New("connection refused"))
+
+	assert.False(t, flagService.IsEnabled(ctx, "missing", 1))
+	assert.False(t, flagService.IsEnabled(ctx, "broken", 1))
+}
+
+func TestFeatureFlagService_CreateFlag_AlreadyExists(t *testing.T) {
+	mockFlagRepo := new(mocks.MockFeatureFlagRepository)
+	flagService := service.NewFeatureFlagService(mockFlagRepo)
+	ctx := context.Background()
+
+	mockFlagRepo.On("GetFlagByKey", ctx, "two_factor_auth").Return(&entity.FeatureFlagEntity{Key: "two_factor_auth"}, nil)
+
+	_, err := flagService.CreateFlag(ctx, &entity.FeatureFlagEntity{Key: " Two_Factor_Auth "})
+
+	assert.EqualError(t, err, "feature flag already exists")
+	mockFlagRepo.AssertNotCalled(t, "CreateFlag", mock.Anything, mock.Anything)
+}
+
+func TestFeatureFlagService_CreateFlag_InvalidInput(t *testing.T) {
+	flagService := service.NewFeatureFlagService(new(mocks.MockFeatureFlagRepository))
+
+	_, err := flagService.CreateFlag(context.Background(), &entity.FeatureFlagEntity{Key: "has spaces"})
+	assert.EqualError(t, err, "flag key must be 2-100 lowercase letters, digits, dots, dashes or underscores")
+
+	_, err = flagService.CreateFlag(context.Background(), &entity.FeatureFlagEntity{Key: "valid_key", RolloutPercentage: 101})
+	assert.EqualError(t, err, "rollout percentage must be between 0 and 100")
+}
+
+func TestFeatureFlagMiddleware(t *testing.T) {
+	mockFlagRepo := new(mocks.MockFeatureFlagRepository)
+	flagService := service.NewFeatureFlagService(mockFlagRepo)
+
+	mockFlagRepo.On("GetFlagByKey", mock.Anything, "beta_checkout").Return(&entity.FeatureFlagEntity{
+		Key:       "beta_checkout",
+		Overrides: map[int64]bool{42: true},
+	}, nil)
+
+	handler := middleware.FeatureFlagMiddleware(flagService, "beta_checkout")(func(c echo.Context) error {
+		return c.String(http.StatusOK, "success")
+	})
+
+	e := echo.New()
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	c.Set("user_id", int64(42))
+	assert.NoError(t, handler(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+	c.Set("user_id", int64(43))
+	assert.NoError(t, handler(c))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
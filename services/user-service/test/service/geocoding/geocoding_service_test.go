@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGeocodingService_ReverseGeocode_Success(t *testing.T) {
+	// Setup
+	mockGeocoder := new(mocks.MockGeocoder)
+	geocodingService := service.NewGeocodingService(mockGeocoder)
+
+	ctx := context.Background()
+	resolved := &entity.AddressEntity{
+		Address:    "Jl. Braga, Bandung",
+		Province:   "Jawa Barat",
+		City:       "Kota Bandung",
+		District:   "Sumur Bandung",
+		PostalCode: "40111",
+		Lat:        -6.91749,
+		Lng:        107.60912,
+	}
+
+	mockGeocoder.On("ReverseGeocode", ctx, -6.9175, 107.6091).Return(resolved, nil)
+
+	// Execute
+	address, err := geocodingService.ReverseGeocode(ctx, -6.9175, 107.6091)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Jawa Barat", address.Province)
+	assert.Equal(t, "40111", address.PostalCode)
+	assert.Equal(t, -6.9175, address.Lat, "caller coordinates are kept")
+	mockGeocoder.AssertExpectations(t)
+}
+
+func TestGeocodingService_ReverseGeocode_InvalidCoordinates(t *testing.T) {
+	mockGeocoder := new(mocks.MockGeocoder)
+	geocodingService := service.NewGeocodingService(mockGeocoder)
+
+	cases := []struct {
+		name     string
+		lat, lng float64
+	}{
+		{"latitude too high", 91, 106.8},
+		{"longitude too low", -6.2, -181},
+		{"null island", 0, 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := geocodingService.ReverseGeocode(context.Background(), tc.lat, tc.lng)
+			assert.Error(t, err)
+			assert.Equal(t, "invalid coordinates", err.Error())
+		})
+	}
+
+	mockGeocoder.AssertNotCalled(t, "ReverseGeocode", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGeocodingService_Geocode_NotFound(t *testing.T) {
+	mockGeocoder := new(mocks.MockGeocoder)
+	geocodingService := service.NewGeocodingService(mockGeocoder)
+
+	ctx := context.Background()
+	mockGeocoder.On("Geocode", ctx, "Nowhere street").Return(nil, errors.New("location not found"))
+
+	_, err := geocodingService.Geocode(ctx, "  Nowhere street ")
+
+	assert.Error(t, err)
+	assert.Equal(t, "location not found", err.Error())
+	mockGeocoder.AssertExpectations(t)
+}
+
+func TestGeocodingService_Geocode_ProviderDown(t *testing.T) {
+	geocodingService := service.NewGeocodingService(nil)
+
+	_, err := geocodingService.Geocode(context.Background(), "Jl. Braga")
+
+	assert.Error(t, err)
+	assert.Equal(t, "geocoding service unavailable", err.Error())
+}
+
+func TestAuthService_UpdateProfile_StoresAddressComponents(t *testing.T) {
+	// Setup
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockGeocoder := new(mocks.MockGeocoder)
+	authService := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo, Geocoder: mockGeocoder})
+
+	ctx := context.Background()
+	userID := int64(1)
+	currentUser := &entity.UserEntity{ID: userID, Email: "john@example.com", Photo: "photo.jpg"}
+	resolved := &entity.AddressEntity{Province: "DKI Jakarta", City: "Jakarta Pusat", District: "Menteng", PostalCode: "10310"}
+
+	mockUserRepo.On("GetUserByID", ctx, userID).Return(currentUser, nil)
+	mockUserRepo.On("UpdateUserProfile", ctx, userID, "John", "john@example.com", "0812", "Jl. Sudirman", -6.2088, 106.8456, "photo.jpg", int64(0)).Return(nil)
+	mockGeocoder.On("ReverseGeocode", ctx, -6.2088, 106.8456).Return(resolved, nil)
+	mockUserRepo.On("UpdateUserAddressComponents", ctx, userID, *resolved).Return(nil)
+
+	// Execute
+	err := authService.UpdateProfile(ctx, userID, "John", "john@example.com", "0812", "Jl. Sudirman", -6.2088, 106.8456, "photo.jpg", 0)
+
+	// Assert
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+	mockGeocoder.AssertExpectations(t)
+}
+
+func TestAuthService_UpdateProfile_GeocoderFailureIgnored(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockGeocoder := new(mocks.MockGeocoder)
+	authService := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo, Geocoder: mockGeocoder})
+
+	ctx := context.Background()
+	userID := int64(1)
+	currentUser := &entity.UserEntity{ID: userID, Email: "john@example.com"}
+
+	mockUserRepo.On("GetUserByID", ctx, userID).Return(currentUser, nil)
+	mockUserRepo.On("UpdateUserProfile", ctx, userID, "John", "john@example.com", "0812", "Jl. Sudirman", -6.2088, 106.8456, "", int64(0)).Return(nil)
+	mockGeocoder.On("ReverseGeocode", ctx, -6.2088, 106.8456).Return(nil, errors.New("timeout"))
+
+	err := authService.UpdateProfile(ctx, userID, "John", "john@example.com", "0812", "Jl. Sudirman", -6.2088, 106.8456, "", 0)
+
+	assert.NoError(t, err)
+	mockUserRepo.AssertNotCalled(t, "UpdateUserAddressComponents", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_UpdateProfile_InvalidCoordinates(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	authService := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
+
+	err := authService.UpdateProfile(context.Background(), 1, "John", "john@example.com", "0812", "Jl. Sudirman", -95, 106.8456, "", 0)
+
+	assert.Error(t, err)
+	assert.Equal(t, "invalid coordinates", err.Error())
+	mockUserRepo.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+}
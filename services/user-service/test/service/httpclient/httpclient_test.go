@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"user-service/internal/adapter/httpclient"
+	"user-service/utils/querytrace"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fastRetries = httpclient.Options{Name: "test", BaseBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+// flakyServer answers 503 to the first failures requests, then 200 with the request body
+func flakyServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Seen-Request-Id", r.Header.Get("X-Request-Id"))
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &attempts
+}
+
+func TestHTTPClient_RetriesIdempotentRequestsOn5xx(t *testing.T) {
+	server, attempts := flakyServer(t, 2)
+	client := httpclient.New(fastRetries)
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, bytes.NewReader([]byte("photo")))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "photo", string(body), "the body is sent again in full")
+	assert.EqualValues(t, 3, attempts.Load())
+}
+
+func TestHTTPClient_GivesUpAfterMaxRetries(t *testing.T) {
+	server, attempts := flakyServer(t, 10)
+	client := httpclient.New(fastRetries)
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.EqualValues(t, 3, attempts.Load())
+}
+
+func TestHTTPClient_PostIsRetriedOnlyWhenIdempotent(t *testing.T) {
+	server, attempts := flakyServer(t, 1)
+	client := httpclient.New(fastRetries)
+
+	resp, err := client.Post(server.URL, "application/json", bytes.NewReader([]byte(`{}`)))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.EqualValues(t, 1, attempts.Load())
+
+	attempts.Store(0)
+	req, err := http.NewRequestWithContext(httpclient.Idempotent(context.Background()), http.MethodPost, server.URL, bytes.NewReader([]byte(`{}`)))
+	require.NoError(t, err)
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.EqualValues(t, 2, attempts.Load())
+}
+
+// dialFailures fails the first failures round trips as if the server refused the connection
+type dialFailures struct {
+	failures int32
+	attempts atomic.Int32
+}
+
+func (d *dialFailures) RoundTrip(req *http.Request) (*http.Response, error) {
+	if d.attempts.Add(1) <= d.failures {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	}
+	return &http.Response{StatusCode: http.StatusCreated, Body: http.NoBody, Request: req}, nil
+}
+
+func TestHTTPClient_RetriesFailedDialsForAnyMethod(t *testing.T) {
+	base := &dialFailures{failures: 1}
+	client := &http.Client{Transport: httpclient.NewTransport(fastRetries, base)}
+
+	req, err := http.NewRequest(http.MethodPost, "http://storage.example.com/upload", bytes.NewReader([]byte("photo")))
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.EqualValues(t, 2, base.attempts.Load())
+}
+
+func TestHTTPClient_StreamedBodyIsNotRetried(t *testing.T) {
+	server, attempts := flakyServer(t, 1)
+	client := httpclient.New(fastRetries)
+
+	// An io.Pipe cannot be rewound, so the request goes out once
+	reader, writer := io.Pipe()
+	go func() {
+		_, _ = writer.Write([]byte("photo"))
+		writer.Close()
+	}()
+	req, err := http.NewRequest(http.MethodPut, server.URL, reader)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.EqualValues(t, 1, attempts.Load())
+}
+
+func TestHTTPClient_ForwardsRequestID(t *testing.T) {
+	server, _ := flakyServer(t, 0)
+	client := httpclient.New(fastRetries)
+
+	ctx := querytrace.WithTrace(context.Background(), querytrace.New(http.MethodGet, "/api/v1/addresses", "req-7"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "req-7", resp.Header.Get("X-Seen-Request-Id"))
+	assert.Empty(t, req.Header.Get("X-Request-Id"), "the caller's request is not modified")
+}
+
+func TestHTTPClient_StopsRetryingWhenContextEnds(t *testing.T) {
+	server, attempts := flakyServer(t, 10)
+	client := httpclient.New(httpclient.Options{Name: "test", BaseBackoff: time.Minute, MaxBackoff: time.Minute})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+
+	_, err = client.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.LessOrEqual(t, attempts.Load(), int32(2))
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"user-service/utils/i18n"
+	"user-service/utils/validator"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	cases := map[string]string{
+		"":                         "en",
+		"id":                       "id",
+		"id-ID":                    "id",
+		"in":                       "id",
+		"en-US,en;q=0.9":           "en",
+		"en;q=0.5, id;q=0.8":       "id",
+		"fr, id;q=0.2":             "id",
+		"fr, de":                   "en",
+		"id;q=0, en;q=0.1":         "en",
+		"id;q=abc, en":             "en",
+		"en-GB;q=0.8, id-ID;q=0.8": "en",
+	}
+
+	for header, want := range cases {
+		assert.Equal(t, want, i18n.Negotiate(header), header)
+	}
+}
+
+func TestT_FillsPlaceholdersAndFallsBack(t *testing.T) {
+	body := i18n.T(i18n.Indonesian, "email.signin_otp.body", i18n.Params{"name": "Budi", "code": "123456", "minutes": 10, "signature": "-"})
+	assert.Contains(t, body, "Halo Budi,")
+	assert.Contains(t, body, "123456")
+	assert.Contains(t, body, "10 menit")
+
+	assert.Equal(t, "Pengguna tidak ditemukan", i18n.T(i18n.Indonesian, "User not found"))
+	// Unknown languages use English, and unknown keys are returned as they are
+	assert.Equal(t, "Verify Your Account", i18n.T("fr", "email.verification.subject"))
+	assert.Equal(t, "Something new happened", i18n.T(i18n.Indonesian, "Something new happened"))
+}
+
+func TestCatalogs_HaveTheSameTemplates(t *testing.T) {
+	// Every email and validation template must exist in both languages with the same placeholders
+	for _, key := range []string{
+		"email.verification.body", "email.email_change.body", "email.password_reset.body",
+		"email.new_device.body", "email.signin_otp.body", "email.account_merged.body",
+		"email.customer_invite.body", "email.photo_removed.body", "email.email_change_revert.body",
+		"email.support_ticket_update.body",
+		"validation.required", "validation.email", "validation.min", "validation.username",
+	} {
+		en, id := i18n.T(i18n.English, key), i18n.T(i18n.Indonesian, key)
+		assert.NotEqual(t, key, en, key)
+		assert.NotEqual(t, en, id, key)
+		for _, placeholder := range []string{"{name}", "{link}", "{device}", "{ip}", "{time}", "{code}", "{minutes}", "{merged_email}", "{surviving_email}", "{reason}", "{new_email}", "{expires}", "{ticket_id}", "{status_label}", "{note_block}", "{signature}", "{0}", "{1}"} {
+			assert.Equal(t, strings.Contains(en, placeholder), strings.Contains(id, placeholder), key+" "+placeholder)
+		}
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, "en", i18n.FromContext(context.Background()))
+	assert.Equal(t, "id", i18n.FromContext(i18n.WithLanguage(context.Background(), "id")))
+	assert.Equal(t, "en", i18n.FromContext(i18n.WithLanguage(context.Background(), "fr")))
+}
+
+type signUpRequest struct {
+	Email    string `validate:"required,email"`
+	Password string `validate:"required,min=8"`
+}
+
+func TestValidateContext_UsesRequestLanguage(t *testing.T) {
+	v := validator.NewValidator()
+	req := &signUpRequest{Email: "budi@example.com", Password: "short"}
+
+	assert.EqualError(t, v.Validate(req), "Password must be at least 8 characters")
+	assert.EqualError(t, v.ValidateContext(context.Background(), req), "Password must be at least 8 characters")
+	assert.EqualError(t, v.ValidateContext(i18n.WithLanguage(context.Background(), "id"), req), "Password minimal 8 karakter")
+
+	// Tags without a custom message use the library's Indonesian translation
+	type optionalRequest struct {
+		Count int `validate:"gte=1"`
+	}
+	err := v.ValidateContext(i18n.WithLanguage(context.Background(), "id"), &optionalRequest{})
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "must be")
+}
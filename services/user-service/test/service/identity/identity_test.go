@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var (
+	jpegDocument = append([]byte{0xFF, 0xD8, 0xFF, 0xE0}, bytes.Repeat([]byte{0}, 64)...)
+	adultBirth   = time.Date(1990, time.May, 17, 0, 0, 0, 0, time.UTC)
+)
+
+// signingStorage adds signed links to the storage mock, like Supabase
+type signingStorage struct {
+	*mocks.MockStorage
+}
+
+func (s signingStorage) SignedURL(ctx context.Context, bucketName, objectName string, expiresIn time.Duration) (string, error) {
+	return "https://storage.example.com/sign/" + bucketName + "/" + objectName + "?token=abc", nil
+}
+
+type identityFixture struct {
+	identityRepo *mocks.MockIdentityRepository
+	storage      *mocks.MockStorage
+	auditRepo    *mocks.MockAuditLogRepository
+	service      port.IdentityServiceInterface
+}
+
+func newIdentityFixture(identity config.Identity) *identityFixture {
+	f := &identityFixture{
+		identityRepo: new(mocks.MockIdentityRepository),
+		storage:      new(mocks.MockStorage),
+		auditRepo:    new(mocks.MockAuditLogRepository),
+	}
+	f.service = service.NewIdentityService(f.identityRepo, signingStorage{f.storage}, service.NewAuditLogService(f.auditRepo), &config.Config{Identity: identity})
+	return f
+}
+
+func TestSubmit_StoresDocumentInPrivateBucket(t *testing.T) {
+	ctx := context.Background()
+	f := newIdentityFixture(config.Identity{})
+
+	f.identityRepo.On("GetLatest", ctx, int64(5)).Return(nil, gorm.ErrRecordNotFound)
+	f.storage.On("UploadFile", ctx, "identity-documents", mock.MatchedBy(func(name string) bool {
+		return strings.HasPrefix(name, "5/ktp-") && strings.HasSuffix(name, ".jpg")
+	}), mock.Anything, "image/jpeg").Return("https://storage.example.com/object/public/identity-documents/x", nil)
+	f.identityRepo.On("Create", ctx, mock.MatchedBy(func(v *entity.IdentityVerificationEntity) bool {
+		return v.UserID == 5 && v.DocumentType == "ktp" && v.DateOfBirth.Equal(adultBirth)
+	})).Return(&entity.IdentityVerificationEntity{ID: 1, UserID: 5, Status: entity.IdentityVerificationPending}, nil)
+
+	verification, err := f.service.Submit(ctx, 5, " KTP ", adultBirth, bytes.NewReader(jpegDocument))
+
+	assert.NoError(t, err)
+	assert.Equal(t, entity.IdentityVerificationPending, verification.Status)
+	f.storage.AssertExpectations(t)
+	f.identityRepo.AssertExpectations(t)
+}
+
+func TestSubmit_RejectsInvalidInput(t *testing.T) {
+	ctx := context.Background()
+	f := newIdentityFixture(config.Identity{MaxSizeKB: 1})
+
+	cases := []struct {
+		documentType string
+		dateOfBirth  time.Time
+		data         []byte
+		wantErr      string
+	}{
+		{"npwp", adultBirth, jpegDocument, "document type must be one of ktp, passport, sim"},
+		{"ktp", time.Now().AddDate(1, 0, 0), jpegDocument, "date of birth is invalid"},
+		{"ktp", time.Time{}, jpegDocument, "date of birth is invalid"},
+		{"ktp", adultBirth, nil, "document is empty"},
+		{"ktp", adultBirth, []byte("GIF89a........"), "document must be a JPEG, PNG or PDF file"},
+		{"ktp", adultBirth, append(jpegDocument, bytes.Repeat([]byte{0}, 1024)...), "document exceeds the size limit"},
+	}
+	for _, tc := range cases {
+		_, err := f.service.Submit(ctx, 5, tc.documentType, tc.dateOfBirth, bytes.NewReader(tc.data))
+		assert.EqualError(t, err, tc.wantErr)
+	}
+	f.storage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSubmit_OnePendingAtATime(t *testing.T) {
+	ctx := context.Background()
+	f := newIdentityFixture(config.Identity{})
+
+	f.identityRepo.On("GetLatest", ctx, int64(5)).Return(&entity.IdentityVerificationEntity{ID: 1, Status: entity.IdentityVerificationPending}, nil)
+
+	_, err := f.service.Submit(ctx, 5, "ktp", adultBirth, bytes.NewReader(jpegDocument))
+
+	assert.EqualError(t, err, "identity verification already pending")
+	f.storage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStatus(t *testing.T) {
+	ctx := context.Background()
+	future := time.Now().Add(24 * time.Hour)
+	past := time.Now().Add(-time.Hour)
+	minor := time.Now().AddDate(-18, 0, 0)
+
+	approved := &entity.IdentityVerificationEntity{ID: 1, Status: entity.IdentityVerificationApproved, DateOfBirth: adultBirth, ExpiresAt: &future}
+
+	t.Run("never submitted", func(t *testing.T) {
+		f := newIdentityFixture(config.Identity{})
+		f.identityRepo.On("GetLatest", ctx, int64(5)).Return(nil, gorm.ErrRecordNotFound)
+
+		status, err := f.service.Status(ctx, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.IdentityVerificationNone, status.Status)
+		assert.False(t, status.Verified)
+	})
+
+	t.Run("approved adult", func(t *testing.T) {
+		f := newIdentityFixture(config.Identity{})
+		f.identityRepo.On("GetLatest", ctx, int64(5)).Return(approved, nil)
+
+		status, err := f.service.Status(ctx, 5)
+		assert.NoError(t, err)
+		assert.True(t, status.Verified)
+		assert.True(t, status.AgeVerified)
+	})
+
+	t.Run("approved but under the minimum age", func(t *testing.T) {
+		f := newIdentityFixture(config.Identity{MinimumAge: 21})
+		f.identityRepo.On("GetLatest", ctx, int64(5)).Return(&entity.IdentityVerificationEntity{Status: entity.IdentityVerificationApproved, DateOfBirth: minor, ExpiresAt: &future}, nil)
+
+		status, err := f.service.Status(ctx, 5)
+		assert.NoError(t, err)
+		assert.True(t, status.Verified)
+		assert.False(t, status.AgeVerified)
+	})
+
+	t.Run("approval expired", func(t *testing.T) {
+		f := newIdentityFixture(config.Identity{})
+		f.identityRepo.On("GetLatest", ctx, int64(5)).Return(&entity.IdentityVerificationEntity{Status: entity.IdentityVerificationApproved, DateOfBirth: adultBirth, ExpiresAt: &past}, nil)
+
+		status, err := f.service.Status(ctx, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.IdentityVerificationExpired, status.Status)
+		assert.False(t, status.Verified)
+	})
+
+	t.Run("resubmission keeps the earlier approval", func(t *testing.T) {
+		f := newIdentityFixture(config.Identity{})
+		f.identityRepo.On("GetLatest", ctx, int64(5)).Return(&entity.IdentityVerificationEntity{ID: 2, Status: entity.IdentityVerificationPending}, nil)
+		f.identityRepo.On("GetLatestApproved", ctx, int64(5)).Return(approved, nil)
+
+		status, err := f.service.Status(ctx, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, entity.IdentityVerificationPending, status.Status)
+		assert.True(t, status.Verified)
+	})
+}
+
+func TestApprove_SetsExpiryAndAudits(t *testing.T) {
+	ctx := context.Background()
+	f := newIdentityFixture(config.Identity{ValidityDays: 30})
+
+	f.identityRepo.On("GetByID", ctx, int64(1)).Return(&entity.IdentityVerificationEntity{ID: 1, UserID: 5, Status: entity.IdentityVerificationPending}, nil)
+	f.identityRepo.On("Review", ctx, int64(1), mock.MatchedBy(func(update *entity.IdentityVerificationEntity) bool {
+		return update.Status == entity.IdentityVerificationApproved && update.ReviewedBy == 9 &&
+			update.ExpiresAt != nil && update.ExpiresAt.Sub(time.Now()) > 29*24*time.Hour
+	})).Return(nil).Once()
+	f.identityRepo.On("Review", ctx, int64(1), mock.Anything).Return(errors.New("identity verification already reviewed"))
+	f.auditRepo.On("Create", ctx, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == entity.AuditEventIdentityApproved && auditLog.UserID == 9
+	})).Return(nil)
+
+	_, err := f.service.Approve(ctx, 1, 9)
+	assert.NoError(t, err)
+
+	_, err = f.service.Reject(ctx, 1, 9, " ")
+	assert.EqualError(t, err, "rejection reason is required")
+
+	_, err = f.service.Reject(ctx, 1, 9, "blurry photo")
+	assert.EqualError(t, err, "identity verification already reviewed")
+}
+
+func TestGetVerification_SignsDocumentLink(t *testing.T) {
+	ctx := context.Background()
+	f := newIdentityFixture(config.Identity{Bucket: "kyc"})
+
+	f.identityRepo.On("GetByID", ctx, int64(1)).Return(&entity.IdentityVerificationEntity{ID: 1, ObjectName: "5/ktp-abc.jpg"}, nil)
+
+	verification, err := f.service.GetVerification(ctx, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://storage.example.com/sign/kyc/5/ktp-abc.jpg?token=abc", verification.DocumentURL)
+}
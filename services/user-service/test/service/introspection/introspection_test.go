@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-service/config"
+	"user-service/internal/adapter/handler"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var cfg = &config.Config{App: config.App{JwtSecretKey: "test-secret", JwtIssuer: "user-service"}}
+
+func tokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestIntrospect(t *testing.T) {
+	ctx := context.Background()
+	token, err := utils.GenerateJWTWithSession(cfg, 7, "john@example.com", "Customer", "sess_7", false)
+	assert.NoError(t, err)
+
+	t.Run("active token returns its claims", func(t *testing.T) {
+		sessionRepo := new(mocks.MockSessionRepository)
+		blacklistRepo := new(mocks.MockBlacklistTokenRepository)
+		blacklistRepo.On("IsTokenBlacklisted", ctx, tokenHash(token)).Return(false)
+		sessionRepo.On("ValidateToken", ctx, int64(7), "sess_7", token).Return(true)
+
+		result, err := service.NewTokenIntrospectionService(utils.NewJWTUtil(cfg), sessionRepo, blacklistRepo, nil).Introspect(ctx, "Bearer "+token)
+
+		assert.NoError(t, err)
+		assert.True(t, result.Active)
+		assert.Equal(t, int64(7), result.UserID)
+		assert.Equal(t, "Customer", result.RoleName)
+		assert.Equal(t, "sess_7", result.SessionID)
+		assert.Equal(t, "user-service", result.Issuer)
+		assert.False(t, result.ExpiresAt.IsZero())
+	})
+
+	t.Run("blacklisted token is revoked", func(t *testing.T) {
+		sessionRepo := new(mocks.MockSessionRepository)
+		blacklistRepo := new(mocks.MockBlacklistTokenRepository)
+		blacklistRepo.On("IsTokenBlacklisted", ctx, tokenHash(token)).Return(true)
+
+		result, err := service.NewTokenIntrospectionService(utils.NewJWTUtil(cfg), sessionRepo, blacklistRepo, nil).Introspect(ctx, token)
+
+		assert.NoError(t, err)
+		assert.False(t, result.Active)
+		assert.Equal(t, entity.TokenInactiveRevoked, result.Reason)
+		sessionRepo.AssertNotCalled(t, "ValidateToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("token without a session is inactive", func(t *testing.T) {
+		sessionRepo := new(mocks.MockSessionRepository)
+		blacklistRepo := new(mocks.MockBlacklistTokenRepository)
+		blacklistRepo.On("IsTokenBlacklisted", ctx, tokenHash(token)).Return(false)
+		sessionRepo.On("ValidateToken", ctx, int64(7), "sess_7", token).Return(false)
+
+		result, err := service.NewTokenIntrospectionService(utils.NewJWTUtil(cfg), sessionRepo, blacklistRepo, nil).Introspect(ctx, token)
+
+		assert.NoError(t, err)
+		assert.False(t, result.Active)
+		assert.Equal(t, entity.TokenInactiveNoSession, result.Reason)
+	})
+
+	t.Run("token signed with another key is invalid", func(t *testing.T) {
+		other := &config.Config{App: config.App{JwtSecretKey: "other-secret", JwtIssuer: "user-service"}}
+		forged, err := utils.GenerateJWTWithSession(other, 7, "john@example.com", "Super Admin", "sess_7", false)
+		assert.NoError(t, err)
+
+		result, err := service.NewTokenIntrospectionService(utils.NewJWTUtil(cfg), new(mocks.MockSessionRepository), new(mocks.MockBlacklistTokenRepository), nil).Introspect(ctx, forged)
+
+		assert.NoError(t, err)
+		assert.False(t, result.Active)
+		assert.Equal(t, entity.TokenInactiveInvalid, result.Reason)
+	})
+}
+
+func introspect(t *testing.T, h handler.InternalHandlerInterface, body string) (int, map[string]interface{}) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/internal/auth/introspect", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	assert.NoError(t, h.IntrospectToken(e.NewContext(req, rec)))
+
+	var payload struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &payload))
+	return rec.Code, payload.Data
+}
+
+func TestIntrospectTokenHandler(t *testing.T) {
+	token, err := utils.GenerateJWTWithSession(cfg, 7, "john@example.com", "Customer", "sess_7", false)
+	assert.NoError(t, err)
+
+	sessionRepo := new(mocks.MockSessionRepository)
+	blacklistRepo := new(mocks.MockBlacklistTokenRepository)
+	blacklistRepo.On("IsTokenBlacklisted", mock.Anything, mock.Anything).Return(false)
+	sessionRepo.On("ValidateToken", mock.Anything, int64(7), "sess_7", token).Return(true)
+	h := handler.NewInternalHandler(nil, service.NewTokenIntrospectionService(utils.NewJWTUtil(cfg), sessionRepo, blacklistRepo, nil))
+
+	code, data := introspect(t, h, `{"token":"`+token+`"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, data["active"])
+	assert.Equal(t, "7", data["sub"])
+	assert.Equal(t, "Customer", data["role"])
+
+	// Inactive tokens only say so, without claims
+	code, data = introspect(t, h, `{"token":"not-a-jwt"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, map[string]interface{}{"active": false}, data)
+
+	code, _ = introspect(t, h, `{}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/adapter/handler"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var inviteOnly = &config.Config{Registration: config.Registration{Mode: "invite"}}
+
+type signupFixture struct {
+	userRepo   *mocks.MockUserRepository
+	inviteRepo *mocks.MockInviteRepository
+	service    port.UserServiceInterface
+}
+
+func newSignupFixture(cfg *config.Config) *signupFixture {
+	f := &signupFixture{
+		userRepo:   new(mocks.MockUserRepository),
+		inviteRepo: new(mocks.MockInviteRepository),
+	}
+	f.service = service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: f.userRepo},
+		Invites:         service.NewInviteService(f.inviteRepo),
+		Config:          cfg,
+	})
+	return f
+}
+
+func TestCreateUserAccount_OpenModeIgnoresInviteCode(t *testing.T) {
+	ctx := context.Background()
+	f := newSignupFixture(&config.Config{})
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "budi@example.com").Return(&entity.UserEntity{ID: 1}, nil)
+
+	err := f.service.CreateUserAccount(ctx, "budi@example.com", "Budi", "password123", "password123", "", 0, 0, nil)
+
+	assert.EqualError(t, err, "email already exists")
+	f.inviteRepo.AssertNotCalled(t, "ReserveUse", mock.Anything, mock.Anything)
+}
+
+func TestCreateUserAccount_InviteModeRequiresCode(t *testing.T) {
+	ctx := context.Background()
+	f := newSignupFixture(inviteOnly)
+
+	err := f.service.CreateUserAccount(ctx, "budi@example.com", "Budi", "password123", "password123", "  ", 0, 0, nil)
+
+	assert.EqualError(t, err, "invite code is required")
+	f.userRepo.AssertNotCalled(t, "GetUserByEmailIncludingUnverified", mock.Anything, mock.Anything)
+}
+
+func TestCreateUserAccount_ReleasesInviteWhenSignupFails(t *testing.T) {
+	ctx := context.Background()
+	f := newSignupFixture(inviteOnly)
+
+	f.inviteRepo.On("ReserveUse", ctx, "BETA-BANDUNG").Return(int64(7), true, nil)
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "budi@example.com").Return(&entity.UserEntity{ID: 1}, nil)
+	f.inviteRepo.On("ReleaseUse", ctx, int64(7)).Return(nil)
+
+	err := f.service.CreateUserAccount(ctx, "budi@example.com", "Budi", "password123", "password123", " beta-bandung ", 0, 0, nil)
+
+	assert.EqualError(t, err, "email already exists")
+	f.inviteRepo.AssertExpectations(t)
+	f.inviteRepo.AssertNotCalled(t, "CreateRedemption", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReserveInvite_ExplainsRejection(t *testing.T) {
+	ctx := context.Background()
+	past := time.Now().Add(-time.Hour)
+
+	cases := []struct {
+		invite  *entity.InviteEntity
+		lookup  error
+		wantErr string
+	}{
+		{nil, gorm.ErrRecordNotFound, "invalid invite code"},
+		{&entity.InviteEntity{MaxUses: 5, Uses: 1, RevokedAt: &past}, nil, "invalid invite code"},
+		{&entity.InviteEntity{MaxUses: 5, Uses: 1, ExpiresAt: &past}, nil, "invite code has expired"},
+		{&entity.InviteEntity{MaxUses: 5, Uses: 5}, nil, "invite code has been used up"},
+	}
+	for _, tc := range cases {
+		inviteRepo := new(mocks.MockInviteRepository)
+		inviteRepo.On("ReserveUse", ctx, "BETA").Return(int64(0), false, nil)
+		if tc.invite != nil {
+			inviteRepo.On("GetInviteByCode", ctx, "BETA").Return(tc.invite, nil)
+		} else {
+			inviteRepo.On("GetInviteByCode", ctx, "BETA").Return(nil, tc.lookup)
+		}
+
+		_, err := service.NewInviteService(inviteRepo).Reserve(ctx, "beta")
+		assert.EqualError(t, err, tc.wantErr)
+	}
+}
+
+func TestCreateInvite_GeneratesReadableCode(t *testing.T) {
+	ctx := context.Background()
+	inviteRepo := new(mocks.MockInviteRepository)
+	invite := &entity.InviteEntity{MaxUses: 10}
+	inviteRepo.On("CreateInvite", ctx, invite).Return(invite, nil)
+
+	_, err := service.NewInviteService(inviteRepo).CreateInvite(ctx, invite)
+
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^[A-HJ-NP-Z2-9]{5}-[A-HJ-NP-Z2-9]{5}$`), invite.Code)
+}
+
+func TestCreateInvite_RejectsInvalidInput(t *testing.T) {
+	ctx := context.Background()
+	inviteService := service.NewInviteService(new(mocks.MockInviteRepository))
+	past := time.Now().Add(-time.Minute)
+
+	_, err := inviteService.CreateInvite(ctx, &entity.InviteEntity{Code: "AB", MaxUses: 1})
+	assert.EqualError(t, err, "invite code must be 4-32 letters, digits or dashes")
+
+	_, err = inviteService.CreateInvite(ctx, &entity.InviteEntity{Code: "BETA", MaxUses: 0})
+	assert.EqualError(t, err, "invite max_uses must be at least 1")
+
+	_, err = inviteService.CreateInvite(ctx, &entity.InviteEntity{Code: "BETA", MaxUses: 1, ExpiresAt: &past})
+	assert.EqualError(t, err, "invite expiry must be in the future")
+}
+
+func TestCreateUserAccountHandler_InviteRejectedIsForbidden(t *testing.T) {
+	f := newSignupFixture(inviteOnly)
+	f.inviteRepo.On("ReserveUse", mock.Anything, "BETA").Return(int64(0), false, nil)
+	f.inviteRepo.On("GetInviteByCode", mock.Anything, "BETA").Return(&entity.InviteEntity{MaxUses: 1, Uses: 1}, nil)
+
+	h := handler.NewAuthHandler(f.service, inviteOnly)
+	body := `{"email":"budi@example.com","name":"Budi","password":"password123","password_confirmation":"password123","invite_code":"beta"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/signup", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	assert.NoError(t, h.CreateUserAccount(c))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invite code has been used up")
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-service/config"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newConfig(staticAllow ...string) *config.Config {
+	return &config.Config{AdminAccess: config.AdminAccess{IPAllowlist: staticAllow}}
+}
+
+func mockRules(repo *mocks.MockIPAccessRepository, allow, deny []entity.IPRuleEntity) {
+	repo.On("GetRules", mock.Anything, entity.IPRuleListAllow).Return(allow, nil)
+	repo.On("GetRules", mock.Anything, entity.IPRuleListDeny).Return(deny, nil)
+}
+
+func TestNormalizeCIDR(t *testing.T) {
+	cidr, err := utils.NormalizeCIDR("10.1.2.3/8")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", cidr)
+
+	cidr, err = utils.NormalizeCIDR(" 203.0.113.9 ")
+	assert.NoError(t, err)
+	assert.Equal(t, "203.0.113.9/32", cidr)
+
+	cidr, err = utils.NormalizeCIDR("2001:db8::1")
+	assert.NoError(t, err)
+	assert.Equal(t, "2001:db8::1/128", cidr)
+
+	_, err = utils.NormalizeCIDR("10.0.0.0/33")
+	assert.Equal(t, utils.ErrInvalidCIDR, err)
+}
+
+func TestIsAllowed_NoRulesAllowsEveryone(t *testing.T) {
+	repo := new(mocks.MockIPAccessRepository)
+	mockRules(repo, []entity.IPRuleEntity{}, []entity.IPRuleEntity{})
+	svc := service.NewIPAccessService(repo, newConfig())
+
+	allowed, _ := svc.IsAllowed(context.Background(), "198.51.100.7")
+	assert.True(t, allowed)
+}
+
+func TestIsAllowed_AllowlistAndDenylist(t *testing.T) {
+	repo := new(mocks.MockIPAccessRepository)
+	mockRules(repo,
+		[]entity.IPRuleEntity{{CIDR: "10.0.0.0/8", ListType: entity.IPRuleListAllow}},
+		[]entity.IPRuleEntity{{CIDR: "10.6.0.0/16", ListType: entity.IPRuleListDeny}},
+	)
+	svc := service.NewIPAccessService(repo, newConfig())
+
+	allowed, _ := svc.IsAllowed(context.Background(), "10.1.2.3")
+	assert.True(t, allowed)
+
+	allowed, reason := svc.IsAllowed(context.Background(), "10.6.1.1")
+	assert.False(t, allowed, "denylist wins over a wider allow range")
+	assert.Equal(t, "ip denied", reason)
+
+	allowed, reason = svc.IsAllowed(context.Background(), "198.51.100.7")
+	assert.False(t, allowed)
+	assert.Equal(t, "ip not in allowlist", reason)
+}
+
+func TestIsAllowed_StaticAllowlistBypassesRedis(t *testing.T) {
+	repo := new(mocks.MockIPAccessRepository)
+	repo.On("GetRules", mock.Anything, mock.Anything).Return(nil, errors.New("redis down"))
+	svc := service.NewIPAccessService(repo, newConfig("192.0.2.0/24"))
+
+	allowed, _ := svc.IsAllowed(context.Background(), "192.0.2.10")
+	assert.True(t, allowed)
+	repo.AssertNotCalled(t, "GetRules", mock.Anything, mock.Anything)
+
+	allowed, reason := svc.IsAllowed(context.Background(), "198.51.100.7")
+	assert.False(t, allowed, "fails closed when dynamic rules cannot be loaded")
+	assert.Equal(t, "ip rules unavailable", reason)
+}
+
+func TestAddRule_Success(t *testing.T) {
+	repo := new(mocks.MockIPAccessRepository)
+	mockRules(repo, []entity.IPRuleEntity{}, []entity.IPRuleEntity{})
+	repo.On("AddRule", mock.Anything, mock.MatchedBy(func(rule *entity.IPRuleEntity) bool {
+		return rule.CIDR == "10.0.0.0/8" && rule.ListType == entity.IPRuleListAllow && rule.CreatedBy == 1
+	})).Return(nil)
+	svc := service.NewIPAccessService(repo, newConfig())
+
+	rule, err := svc.AddRule(context.Background(), entity.IPRuleListAllow, "10.9.9.9/8", " office ", 1, "10.2.3.4")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.0/8", rule.CIDR)
+	assert.Equal(t, "office", rule.Note)
+	repo.AssertExpectations(t)
+}
+
+func TestAddRule_RejectsLockout(t *testing.T) {
+	repo := new(mocks.MockIPAccessRepository)
+	mockRules(repo, []entity.IPRuleEntity{}, []entity.IPRuleEntity{})
+	svc := service.NewIPAccessService(repo, newConfig())
+
+	_, err := svc.AddRule(context.Background(), entity.IPRuleListAllow, "10.0.0.0/8", "", 1, "198.51.100.7")
+	assert.EqualError(t, err, "this change would block your current IP")
+
+	_, err = svc.AddRule(context.Background(), entity.IPRuleListDeny, "198.51.100.0/24", "", 1, "198.51.100.7")
+	assert.EqualError(t, err, "this change would block your current IP")
+	repo.AssertNotCalled(t, "AddRule", mock.Anything, mock.Anything)
+}
+
+func TestAddRule_StaticCallerCannotBeLockedOut(t *testing.T) {
+	repo := new(mocks.MockIPAccessRepository)
+	mockRules(repo, []entity.IPRuleEntity{}, []entity.IPRuleEntity{})
+	repo.On("AddRule", mock.Anything, mock.Anything).Return(nil)
+	svc := service.NewIPAccessService(repo, newConfig("192.0.2.10"))
+
+	_, err := svc.AddRule(context.Background(), entity.IPRuleListDeny, "192.0.2.0/24", "", 1, "192.0.2.10")
+	assert.NoError(t, err)
+}
+
+func TestAddRule_Validation(t *testing.T) {
+	repo := new(mocks.MockIPAccessRepository)
+	svc := service.NewIPAccessService(repo, newConfig())
+
+	_, err := svc.AddRule(context.Background(), "block", "10.0.0.0/8", "", 1, "10.0.0.1")
+	assert.EqualError(t, err, "list type must be allow or deny")
+
+	_, err = svc.AddRule(context.Background(), entity.IPRuleListAllow, "not-an-ip", "", 1, "10.0.0.1")
+	assert.EqualError(t, err, "invalid IP address or CIDR range")
+}
+
+func TestRemoveRule_RejectsLockout(t *testing.T) {
+	repo := new(mocks.MockIPAccessRepository)
+	mockRules(repo, []entity.IPRuleEntity{
+		{CIDR: "10.0.0.0/8", ListType: entity.IPRuleListAllow},
+		{CIDR: "198.51.100.0/24", ListType: entity.IPRuleListAllow},
+	}, []entity.IPRuleEntity{})
+	repo.On("RemoveRule", mock.Anything, entity.IPRuleListAllow, "10.0.0.0/8").Return(nil)
+	svc := service.NewIPAccessService(repo, newConfig())
+
+	err := svc.RemoveRule(context.Background(), entity.IPRuleListAllow, "198.51.100.0/24", "198.51.100.7")
+	assert.EqualError(t, err, "this change would block your current IP")
+
+	err = svc.RemoveRule(context.Background(), entity.IPRuleListAllow, "10.0.0.0/8", "198.51.100.7")
+	assert.NoError(t, err)
+}
+
+func TestRemoveRule_NotFound(t *testing.T) {
+	repo := new(mocks.MockIPAccessRepository)
+	mockRules(repo, []entity.IPRuleEntity{}, []entity.IPRuleEntity{})
+	repo.On("RemoveRule", mock.Anything, entity.IPRuleListDeny, "203.0.113.0/24").Return(errors.New("rule not found"))
+	svc := service.NewIPAccessService(repo, newConfig())
+
+	err := svc.RemoveRule(context.Background(), entity.IPRuleListDeny, "203.0.113.0/24", "10.0.0.1")
+	assert.EqualError(t, err, "ip rule not found")
+}
+
+func TestAdminIPMiddleware_BlocksBeforeHandler(t *testing.T) {
+	repo := new(mocks.MockIPAccessRepository)
+	mockRules(repo, []entity.IPRuleEntity{}, []entity.IPRuleEntity{{CIDR: "203.0.113.0/24", ListType: entity.IPRuleListDeny}})
+	svc := service.NewIPAccessService(repo, newConfig())
+
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	e.GET("/api/v1/admin/check", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}, middleware.AdminIPMiddleware(svc))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/check", nil)
+	req.RemoteAddr = "203.0.113.5:41000"
+	req.Header.Set("X-Forwarded-For", "10.0.0.1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code, "spoofed X-Forwarded-For is ignored")
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/admin/check", nil)
+	req.RemoteAddr = "198.51.100.7:41000"
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
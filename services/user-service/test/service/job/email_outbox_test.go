@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+	"user-service/internal/adapter/message"
+	"user-service/internal/adapter/worker"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestEmailOutbox_BuffersWhileBrokerIsDown(t *testing.T) {
+	mockJobRepo := new(mocks.MockJobRepository)
+	outbox := message.NewEmailOutbox(message.NewBroker(nil, nil, 0), nil, service.NewJobService(mockJobRepo))
+
+	var buffered entity.BufferedEmailEntity
+	mockJobRepo.On("Enqueue", mock.Anything, mock.MatchedBy(func(job *entity.JobEntity) bool {
+		return job.Type == entity.JobTypePublishEmail && json.Unmarshal(job.Payload, &buffered) == nil
+	})).Return(&entity.JobEntity{ID: 1}, nil)
+
+	err := outbox.Publish(context.Background(), &entity.BufferedEmailEntity{RoutingKey: "email_queue", ContentType: "application/json", Body: json.RawMessage(`{"type":"email_verification"}`)})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "email_queue", buffered.RoutingKey)
+	assert.JSONEq(t, `{"type":"email_verification"}`, string(buffered.Body))
+	mockJobRepo.AssertExpectations(t)
+}
+
+func TestEmailOutbox_BufferFailureReturnsPublishError(t *testing.T) {
+	mockJobRepo := new(mocks.MockJobRepository)
+	outbox := message.NewEmailOutbox(message.NewBroker(nil, nil, 0), nil, service.NewJobService(mockJobRepo))
+
+	mockJobRepo.On("Enqueue", mock.Anything, mock.Anything).Return(nil, errors.New("db down"))
+
+	err := outbox.Publish(context.Background(), &entity.BufferedEmailEntity{RoutingKey: "email_queue"})
+
+	assert.EqualError(t, err, "rabbitmq not available")
+}
+
+func TestEmailOutbox_WithoutJobQueueReturnsError(t *testing.T) {
+	outbox := message.NewEmailOutbox(message.NewBroker(nil, nil, 0), nil, nil)
+
+	err := outbox.Publish(context.Background(), &entity.BufferedEmailEntity{RoutingKey: "email_queue"})
+
+	assert.EqualError(t, err, "rabbitmq not available")
+}
+
+func TestEmailOutbox_FlushDoesNotBufferAgain(t *testing.T) {
+	mockJobRepo := new(mocks.MockJobRepository)
+	outbox := message.NewEmailOutbox(message.NewBroker(nil, nil, 0), nil, service.NewJobService(mockJobRepo))
+
+	err := outbox.Flush(context.Background(), &entity.BufferedEmailEntity{RoutingKey: "email_queue"})
+
+	assert.EqualError(t, err, "rabbitmq not available")
+	mockJobRepo.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+}
+
+func TestEmailPublisher_BuffersVerificationEmail(t *testing.T) {
+	mockJobRepo := new(mocks.MockJobRepository)
+	outbox := message.NewEmailOutbox(message.NewBroker(nil, nil, 0), nil, service.NewJobService(mockJobRepo))
+	publisher := message.NewEmailPublisher(outbox, nil, nil, "https://api.jualan-sayur.test")
+
+	var buffered entity.BufferedEmailEntity
+	mockJobRepo.On("Enqueue", mock.Anything, mock.MatchedBy(func(job *entity.JobEntity) bool {
+		return job.Type == entity.JobTypePublishEmail && json.Unmarshal(job.Payload, &buffered) == nil
+	})).Return(&entity.JobEntity{ID: 1}, nil)
+
+	assert.NoError(t, publisher.SendVerificationEmail(context.Background(), "budi@example.com", "token-1"))
+
+	var email map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buffered.Body, &email))
+	assert.Equal(t, "email_queue", buffered.RoutingKey)
+	assert.Equal(t, "budi@example.com", email["email"])
+	assert.Equal(t, "email_verification", email["type"])
+}
+
+func TestEmailPublisher_RevertLinkUsesPublicBaseURL(t *testing.T) {
+	mockJobRepo := new(mocks.MockJobRepository)
+	outbox := message.NewEmailOutbox(message.NewBroker(nil, nil, 0), nil, service.NewJobService(mockJobRepo))
+	publisher := message.NewEmailPublisher(outbox, nil, nil, "https://api.jualan-sayur.test/")
+
+	var buffered entity.BufferedEmailEntity
+	mockJobRepo.On("Enqueue", mock.Anything, mock.MatchedBy(func(job *entity.JobEntity) bool {
+		return job.Type == entity.JobTypePublishEmail && json.Unmarshal(job.Payload, &buffered) == nil
+	})).Return(&entity.JobEntity{ID: 1}, nil)
+
+	assert.NoError(t, publisher.SendEmailChangeRevertEmail(context.Background(), "budi@example.com", "budi.baru@example.com", "token-1"))
+
+	var email message.EmailVerificationMessage
+	assert.NoError(t, json.Unmarshal(buffered.Body, &email))
+	assert.Equal(t, "https://api.jualan-sayur.test/api/v1/auth/revert-email-change?token=token-1", email.Data["link"])
+	assert.Contains(t, email.Body, "https://api.jualan-sayur.test/api/v1/auth/revert-email-change?token=token-1")
+}
+
+func TestEmailPublisher_InviteLinkUsesPublicBaseURL(t *testing.T) {
+	mockJobRepo := new(mocks.MockJobRepository)
+	outbox := message.NewEmailOutbox(message.NewBroker(nil, nil, 0), nil, service.NewJobService(mockJobRepo))
+	publisher := message.NewEmailPublisher(outbox, nil, nil, "https://api.jualan-sayur.test")
+
+	var buffered entity.BufferedEmailEntity
+	mockJobRepo.On("Enqueue", mock.Anything, mock.MatchedBy(func(job *entity.JobEntity) bool {
+		return job.Type == entity.JobTypePublishEmail && json.Unmarshal(job.Payload, &buffered) == nil
+	})).Return(&entity.JobEntity{ID: 1}, nil)
+
+	assert.NoError(t, publisher.SendCustomerInviteEmail(context.Background(), "budi@example.com", "Budi Santoso", "token-1"))
+
+	var email message.EmailVerificationMessage
+	assert.NoError(t, json.Unmarshal(buffered.Body, &email))
+	assert.Equal(t, "customer_invite", email.Type)
+	assert.Equal(t, "https://api.jualan-sayur.test/api/v1/auth/reset-password?token=token-1", email.Data["link"])
+}
+
+func TestWorker_BufferedEmailIsRetriedWhileBrokerIsDown(t *testing.T) {
+	mockJobRepo := new(mocks.MockJobRepository)
+	jobWorker := worker.NewWorker(mockJobRepo, service.NewJobService(mockJobRepo), 1, 10*time.Millisecond)
+	jobWorker.RegisterEmailPublishing(message.NewEmailOutbox(message.NewBroker(nil, nil, 0), nil, nil))
+
+	job := &entity.JobEntity{ID: 9, Type: entity.JobTypePublishEmail, Payload: json.RawMessage(`{"routing_key":"email_queue"}`), Attempts: 1, MaxAttempts: 5}
+	done := make(chan struct{}, 1)
+
+	mockJobRepo.On("RequeueStale", mock.Anything, mock.Anything).Return(int64(0), nil)
+	mockJobRepo.On("ClaimNext", mock.Anything, []string{entity.JobTypePublishEmail}, mock.Anything).Return(job, nil).Once()
+	mockJobRepo.On("ClaimNext", mock.Anything, []string{entity.JobTypePublishEmail}, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	mockJobRepo.On("MarkFailed", mock.Anything, int64(9), "rabbitmq not available", mock.Anything).
+		Run(func(args mock.Arguments) { done <- struct{}{} }).
+		Return(nil)
+
+	jobWorker.Start(context.Background())
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("buffered email was not processed")
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	jobWorker.Stop(stopCtx)
+}
+
+func TestWorker_ReleaseFlushesEachType(t *testing.T) {
+	mockJobRepo := new(mocks.MockJobRepository)
+	jobWorker := worker.NewWorker(mockJobRepo, service.NewJobService(mockJobRepo), 1, time.Second)
+	ctx := context.Background()
+
+	mockJobRepo.On("Release", ctx, entity.JobTypePublishEmail).Return(int64(3), nil)
+	mockJobRepo.On("Release", ctx, entity.JobTypePublishEvent).Return(int64(0), errors.New("db down"))
+
+	jobWorker.Release(ctx, entity.JobTypePublishEmail, entity.JobTypePublishEvent)
+
+	mockJobRepo.AssertExpectations(t)
+}
+
+func TestBroker_HealthFollowsChannel(t *testing.T) {
+	broker := message.NewBroker(nil, nil, 0)
+
+	assert.False(t, broker.Healthy())
+	assert.Nil(t, broker.Channel())
+}
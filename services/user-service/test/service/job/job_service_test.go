@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-service/internal/adapter/worker"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestJobService_Enqueue_EncodesPayload(t *testing.T) {
+	mockJobRepo := new(mocks.MockJobRepository)
+	jobService := service.NewJobService(mockJobRepo)
+	ctx := context.Background()
+
+	mockJobRepo.On("Enqueue", ctx, mock.MatchedBy(func(job *entity.JobEntity) bool {
+		return job.Type == "export.customers" && string(job.Payload) == `{"requested_by":7}` &&
+			job.MaxAttempts == entity.DefaultJobMaxAttempts && !job.RunAt.IsZero()
+	})).Return(&entity.JobEntity{ID: 1, Type: "export.customers"}, nil)
+
+	job, err := jobService.Enqueue(ctx, "export.customers", map[string]int{"requested_by": 7}, time.Time{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), job.ID)
+	mockJobRepo.AssertExpectations(t)
+}
+
+func TestJobService_Enqueue_TypeRequired(t *testing.T) {
+	mockJobRepo := new(mocks.MockJobRepository)
+	jobService := service.NewJobService(mockJobRepo)
+
+	_, err := jobService.Enqueue(context.Background(), " ", nil, time.Now())
+
+	assert.EqualError(t, err, "job type is required")
+	mockJobRepo.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+}
+
+func TestJobService_EnqueueUnique_AlreadyEnqueued(t *testing.T) {
+	mockJobRepo := new(mocks.MockJobRepository)
+	jobService := service.NewJobService(mockJobRepo)
+	ctx := context.Background()
+
+	mockJobRepo.On("Enqueue", ctx, mock.MatchedBy(func(job *entity.JobEntity) bool {
+		return job.UniqueKey == "jobs.prune:1700000000"
+	})).Return(nil, errors.New("job already enqueued"))
+
+	_, err := jobService.EnqueueUnique(ctx, "jobs.prune", "jobs.prune:1700000000", nil, time.Now())
+
+	assert.EqualError(t, err, "job already enqueued")
+}
+
+func TestJobService_RetryJob_NotFound(t *testing.T) {
+	mockJobRepo := new(mocks.MockJobRepository)
+	jobService := service.NewJobService(mockJobRepo)
+	ctx := context.Background()
+
+	mockJobRepo.On("RetryJob", ctx, int64(9)).Return(gorm.ErrRecordNotFound)
+
+	err := jobService.RetryJob(ctx, 9)
+
+	assert.EqualError(t, err, "job not found")
+}
+
+func TestWorker_FailedJobIsRescheduled(t *testing.T) {
+	mockJobRepo := new(mocks.MockJobRepository)
+	jobWorker := worker.NewWorker(mockJobRepo, service.NewJobService(mockJobRepo), 1, 10*time.Millisecond)
+
+	job := &entity.JobEntity{ID: 3, Type: "test.fail", Attempts: 1, MaxAttempts: 3}
+	done := make(chan *time.Time, 1)
+
+	mockJobRepo.On("RequeueStale", mock.Anything, mock.Anything).Return(int64(0), nil)
+	mockJobRepo.On("ClaimNext", mock.Anything, []string{"test.fail"}, mock.Anything).Return(job, nil).Once()
+	mockJobRepo.On("ClaimNext", mock.Anything, []string{"test.fail"}, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	mockJobRepo.On("MarkFailed", mock.Anything, int64(3), "boom", mock.Anything).
+		Run(func(args mock.Arguments) { done <- args.Get(3).(*time.Time) }).
+		Return(nil)
+
+	jobWorker.Register("test.fail", func(ctx context.Context, job *entity.JobEntity) error {
+		return errors.New("boom")
+	})
+	jobWorker.Start(context.Background())
+
+	select {
+	case retryAt := <-done:
+		assert.NotNil(t, retryAt)
+		assert.WithinDuration(t, time.Now().Add(worker.Backoff(1)), *retryAt, 5*time.Second)
+	case <-time.After(2 * time.Second):
+		t.Fatal("job was not processed")
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	jobWorker.Stop(stopCtx)
+}
+
+func TestWorker_LastAttemptFailsPermanently(t *testing.T) {
+	mockJobRepo := new(mocks.MockJobRepository)
+	jobWorker := worker.NewWorker(mockJobRepo, service.NewJobService(mockJobRepo), 1, 10*time.Millisecond)
+
+	job := &entity.JobEntity{ID: 4, Type: "test.panic", Attempts: 3, MaxAttempts: 3}
+	done := make(chan *time.Time, 1)
+
+	mockJobRepo.On("RequeueStale", mock.Anything, mock.Anything).Return(int64(0), nil)
+	mockJobRepo.On("ClaimNext", mock.Anything, []string{"test.panic"}, mock.Anything).Return(job, nil).Once()
+	mockJobRepo.On("ClaimNext", mock.Anything, []string{"test.panic"}, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	mockJobRepo.On("MarkFailed", mock.Anything, int64(4), "panic: unexpected", mock.Anything).
+		Run(func(args mock.Arguments) { done <- args.Get(3).(*time.Time) }).
+		Return(nil)
+
+	jobWorker.Register("test.panic", func(ctx context.Context, job *entity.JobEntity) error {
+		panic("unexpected")
+	})
+	jobWorker.Start(context.Background())
+
+	select {
+	case retryAt := <-done:
+		assert.Nil(t, retryAt)
+	case <-time.After(2 * time.Second):
+		t.Fatal("job was not processed")
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	jobWorker.Stop(stopCtx)
+}
+
+func TestWorker_Backoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, worker.Backoff(1))
+	assert.Equal(t, 2*time.Minute, worker.Backoff(2))
+	assert.Equal(t, time.Hour, worker.Backoff(20))
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newLedgerConfig() *config.Config {
+	return &config.Config{Ledger: config.Ledger{CommissionRateBps: 1000, MinWithdrawalAmount: 50000}}
+}
+
+func TestLedgerService_RecordOrderEarning_DeductsCommission(t *testing.T) {
+	mockLedgerRepo := new(mocks.MockLedgerRepository)
+	mockVendorRepo := new(mocks.MockVendorRepository)
+	ledgerService := service.NewLedgerService(mockLedgerRepo, mockVendorRepo, newLedgerConfig())
+	ctx := context.Background()
+
+	mockVendorRepo.On("GetVendorByID", ctx, int64(10)).Return(&entity.VendorEntity{ID: 10, Status: entity.VendorStatusApproved}, nil)
+
+	var recorded *entity.LedgerTransactionEntity
+	mockLedgerRepo.On("RecordTransaction", ctx, mock.AnythingOfType("*entity.LedgerTransactionEntity")).
+		Run(func(args mock.Arguments) { recorded = args.Get(1).(*entity.LedgerTransactionEntity) }).
+		Return(&entity.LedgerTransactionEntity{ID: 1}, nil)
+
+	_, err := ledgerService.RecordOrderEarning(ctx, 10, "ORD-1", 125005, 99)
+
+	assert.NoError(t, err)
+	assert.True(t, recorded.IsBalanced())
+	assert.Equal(t, entity.LedgerTransactionOrderEarning, recorded.Type)
+	assert.Equal(t, "ORD-1", recorded.ReferenceID)
+
+	amounts := map[string]int64{}
+	for _, entry := range recorded.Entries {
+		amounts[entry.Account] = entry.Amount
+	}
+	assert.Equal(t, int64(-125005), amounts[entity.LedgerAccountPlatformCash])
+	assert.Equal(t, int64(12501), amounts[entity.LedgerAccountPlatformCommission])
+	assert.Equal(t, int64(112504), amounts[entity.LedgerAccountVendorPayable])
+}
+
+func TestLedgerService_RecordOrderEarning_Duplicate(t *testing.T) {
+	mockLedgerRepo := new(mocks.MockLedgerRepository)
+	mockVendorRepo := new(mocks.MockVendorRepository)
+	ledgerService := service.NewLedgerService(mockLedgerRepo, mockVendorRepo, newLedgerConfig())
+	ctx := context.Background()
+
+	mockVendorRepo.On("GetVendorByID", ctx, int64(10)).Return(&entity.VendorEntity{ID: 10, Status: entity.VendorStatusApproved}, nil)
+	mockLedgerRepo.On("RecordTransaction", ctx, mock.Anything).Return(nil, errors.New("ledger transaction already recorded"))
+
+	transaction, err := ledgerService.RecordOrderEarning(ctx, 10, "ORD-1", 100000, 99)
+
+	assert.Nil(t, transaction)
+	assert.EqualError(t, err, "order earning already recorded")
+}
+
+func TestLedgerService_RecordOrderEarning_VendorNotApproved(t *testing.T) {
+	mockLedgerRepo := new(mocks.MockLedgerRepository)
+	mockVendorRepo := new(mocks.MockVendorRepository)
+	ledgerService := service.NewLedgerService(mockLedgerRepo, mockVendorRepo, newLedgerConfig())
+	ctx := context.Background()
+
+	mockVendorRepo.On("GetVendorByID", ctx, int64(10)).Return(&entity.VendorEntity{ID: 10, Status: entity.VendorStatusPending}, nil)
+
+	_, err := ledgerService.RecordOrderEarning(ctx, 10, "ORD-1", 100000, 99)
+
+	assert.EqualError(t, err, "vendor is not approved")
+	mockLedgerRepo.AssertNotCalled(t, "RecordTransaction", mock.Anything, mock.Anything)
+}
+
+func TestLedgerService_RequestWithdrawal_BelowMinimum(t *testing.T) {
+	mockLedgerRepo := new(mocks.MockLedgerRepository)
+	ledgerService := service.NewLedgerService(mockLedgerRepo, nil, newLedgerConfig())
+
+	_, err := ledgerService.RequestWithdrawal(context.Background(), 10, &entity.WithdrawalEntity{Amount: 10000})
+
+	assert.EqualError(t, err, "withdrawal amount is below the minimum")
+	mockLedgerRepo.AssertNotCalled(t, "CreateWithdrawal", mock.Anything, mock.Anything)
+}
+
+func TestLedgerService_RequestWithdrawal_InsufficientBalance(t *testing.T) {
+	mockLedgerRepo := new(mocks.MockLedgerRepository)
+	ledgerService := service.NewLedgerService(mockLedgerRepo, nil, newLedgerConfig())
+	ctx := context.Background()
+
+	mockLedgerRepo.On("CreateWithdrawal", ctx, mock.MatchedBy(func(w *entity.WithdrawalEntity) bool {
+		return w.VendorID == 10 && w.Amount == 75000
+	})).Return(nil, errors.New("insufficient balance"))
+
+	_, err := ledgerService.RequestWithdrawal(ctx, 10, &entity.WithdrawalEntity{Amount: 75000, BankName: "BCA"})
+
+	assert.EqualError(t, err, "insufficient balance")
+	mockLedgerRepo.AssertExpectations(t)
+}
+
+func TestLedgerService_ApproveWithdrawal_NotPending(t *testing.T) {
+	mockLedgerRepo := new(mocks.MockLedgerRepository)
+	ledgerService := service.NewLedgerService(mockLedgerRepo, nil, newLedgerConfig())
+	ctx := context.Background()
+
+	mockLedgerRepo.On("ApproveWithdrawal", ctx, int64(5), int64(99)).Return(errors.New("withdrawal is not pending"))
+
+	err := ledgerService.ApproveWithdrawal(ctx, 5, 99)
+
+	assert.EqualError(t, err, "withdrawal is not pending")
+}
+
+func TestLedgerService_RejectWithdrawal_NotFound(t *testing.T) {
+	mockLedgerRepo := new(mocks.MockLedgerRepository)
+	ledgerService := service.NewLedgerService(mockLedgerRepo, nil, newLedgerConfig())
+	ctx := context.Background()
+
+	mockLedgerRepo.On("RejectWithdrawal", ctx, int64(5), int64(99), "Rekening tidak valid").Return(errors.New("record not found"))
+
+	err := ledgerService.RejectWithdrawal(ctx, 5, 99, " Rekening tidak valid ")
+
+	assert.EqualError(t, err, "withdrawal not found")
+}
+
+func TestLedgerTransactionEntity_IsBalanced(t *testing.T) {
+	balanced := entity.LedgerTransactionEntity{Entries: []entity.LedgerEntryEntity{{Amount: -100}, {Amount: 90}, {Amount: 10}}}
+	unbalanced := entity.LedgerTransactionEntity{Entries: []entity.LedgerEntryEntity{{Amount: -100}, {Amount: 90}}}
+	single := entity.LedgerTransactionEntity{Entries: []entity.LedgerEntryEntity{{Amount: 0}}}
+
+	assert.True(t, balanced.IsBalanced())
+	assert.False(t, unbalanced.IsBalanced())
+	assert.False(t, single.IsBalanced())
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-service/config"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var currentDocuments = []entity.LegalDocumentEntity{
+	{ID: 1, Kind: entity.LegalDocumentTerms, Version: "2026-10", URL: "https://example.com/terms"},
+	{ID: 2, Kind: entity.LegalDocumentPrivacy, Version: "v3", URL: "https://example.com/privacy"},
+}
+
+func TestConsentStatus_RequiredWhenVersionsBump(t *testing.T) {
+	ctx := context.Background()
+	legalRepo := new(mocks.MockLegalRepository)
+	legalService := service.NewLegalService(legalRepo, service.NewAuditLogService(new(mocks.MockAuditLogRepository)))
+
+	legalRepo.On("GetCurrentDocuments", ctx).Return(currentDocuments, nil).Once()
+	legalRepo.On("GetUserConsent", ctx, int64(1)).Return(&entity.ConsentStatusEntity{AcceptedTerms: "2026-10", AcceptedPrivacy: "v3"}, nil)
+	legalRepo.On("GetUserConsent", ctx, int64(2)).Return(&entity.ConsentStatusEntity{AcceptedTerms: "2026-10", AcceptedPrivacy: "v2"}, nil)
+
+	upToDate, err := legalService.ConsentStatus(ctx, 1)
+	assert.NoError(t, err)
+	assert.False(t, upToDate.Required())
+
+	outdated, err := legalService.ConsentStatus(ctx, 2)
+	assert.NoError(t, err)
+	assert.True(t, outdated.Required())
+
+	// The current documents were cached after the first lookup
+	legalRepo.AssertNumberOfCalls(t, "GetCurrentDocuments", 1)
+}
+
+func TestAccept_RejectsOutdatedVersionAndStoresCurrent(t *testing.T) {
+	ctx := context.Background()
+	legalRepo := new(mocks.MockLegalRepository)
+	legalService := service.NewLegalService(legalRepo, service.NewAuditLogService(new(mocks.MockAuditLogRepository)))
+
+	legalRepo.On("GetCurrentDocuments", ctx).Return(currentDocuments, nil)
+	legalRepo.On("SaveConsent", ctx, mock.MatchedBy(func(consent *entity.ConsentEntity) bool {
+		return consent.UserID == 7 && consent.TermsVersion == "2026-10" && consent.PrivacyVersion == "v3" && consent.IPAddress == "10.0.0.1"
+	})).Return(nil).Once()
+
+	err := legalService.Accept(ctx, &entity.ConsentEntity{UserID: 7, TermsVersion: "2026-09", PrivacyVersion: "v3"})
+	assert.EqualError(t, err, "consent version is outdated")
+
+	err = legalService.Accept(ctx, &entity.ConsentEntity{UserID: 7, TermsVersion: "2026-10", PrivacyVersion: "v3", IPAddress: "10.0.0.1"})
+	assert.NoError(t, err)
+	legalRepo.AssertExpectations(t)
+}
+
+func TestPublishDocument_ValidatesAndRefreshesCurrent(t *testing.T) {
+	ctx := context.Background()
+	legalRepo := new(mocks.MockLegalRepository)
+	auditRepo := new(mocks.MockAuditLogRepository)
+	legalService := service.NewLegalService(legalRepo, service.NewAuditLogService(auditRepo))
+
+	_, err := legalService.PublishDocument(ctx, &entity.LegalDocumentEntity{Kind: "cookies", Version: "v1", URL: "https://example.com/cookies"})
+	assert.EqualError(t, err, "legal document kind must be terms or privacy")
+	_, err = legalService.PublishDocument(ctx, &entity.LegalDocumentEntity{Kind: "terms", Version: "v 1", URL: "https://example.com/terms"})
+	assert.EqualError(t, err, "legal document version must be 1-32 letters, digits, dots, dashes or underscores")
+	_, err = legalService.PublishDocument(ctx, &entity.LegalDocumentEntity{Kind: "terms", Version: "v1", URL: "javascript:alert(1)"})
+	assert.EqualError(t, err, "legal document url must be an http(s) URL")
+
+	legalRepo.On("GetCurrentDocuments", ctx).Return([]entity.LegalDocumentEntity{}, nil)
+	_, err = legalService.GetCurrentDocuments(ctx)
+	assert.NoError(t, err)
+
+	doc := &entity.LegalDocumentEntity{Kind: " Terms ", Version: "2026-10", URL: "https://example.com/terms", PublishedBy: 1}
+	legalRepo.On("CreateDocument", ctx, doc).Return(&entity.LegalDocumentEntity{ID: 3, Kind: "terms", Version: "2026-10"}, nil)
+	auditRepo.On("Create", ctx, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == entity.AuditEventLegalPublished
+	})).Return(nil)
+
+	_, err = legalService.PublishDocument(ctx, doc)
+	assert.NoError(t, err)
+	assert.Equal(t, "terms", doc.Kind)
+
+	_, err = legalService.GetCurrentDocuments(ctx)
+	assert.NoError(t, err)
+	legalRepo.AssertNumberOfCalls(t, "GetCurrentDocuments", 2)
+}
+
+func TestCreateUserAccount_RequiresAcceptedTerms(t *testing.T) {
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	legalRepo := new(mocks.MockLegalRepository)
+	legalService := service.NewLegalService(legalRepo, service.NewAuditLogService(new(mocks.MockAuditLogRepository)))
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: userRepo},
+		Legal:           legalService,
+		Config:          &config.Config{},
+	})
+
+	legalRepo.On("GetCurrentDocuments", ctx).Return(currentDocuments, nil)
+
+	err := userService.CreateUserAccount(ctx, "budi@example.com", "Budi", "password123", "password123", "", 0, 0, nil)
+	assert.EqualError(t, err, "terms must be accepted")
+	userRepo.AssertNotCalled(t, "GetUserByEmailIncludingUnverified", mock.Anything, mock.Anything)
+
+	userRepo.On("GetUserByEmailIncludingUnverified", ctx, "budi@example.com").Return(&entity.UserEntity{ID: 1}, nil)
+	err = userService.CreateUserAccount(ctx, "budi@example.com", "Budi", "password123", "password123", "", 0, 0, &entity.ConsentEntity{})
+	assert.EqualError(t, err, "email already exists")
+	legalRepo.AssertNotCalled(t, "SaveConsent", mock.Anything, mock.Anything)
+}
+
+func TestConsentMiddleware_BlocksUntilAccepted(t *testing.T) {
+	legalRepo := new(mocks.MockLegalRepository)
+	legalService := service.NewLegalService(legalRepo, service.NewAuditLogService(new(mocks.MockAuditLogRepository)))
+
+	legalRepo.On("GetCurrentDocuments", mock.Anything).Return(currentDocuments, nil)
+	legalRepo.On("GetUserConsent", mock.Anything, int64(1)).Return(&entity.ConsentStatusEntity{AcceptedTerms: "2026-09", AcceptedPrivacy: "v3"}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/profile", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set("user_id", int64(1))
+
+	called := false
+	h := middleware.ConsentMiddleware(legalService)(func(c echo.Context) error {
+		called = true
+		return c.NoContent(http.StatusOK)
+	})
+
+	assert.NoError(t, h(c))
+	assert.False(t, called)
+	assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"terms_version":"2026-10"`)
+}
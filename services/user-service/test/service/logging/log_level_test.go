@@ -0,0 +1,77 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-service/internal/adapter/handler"
+
+	"github.com/hilmirazib/jualan-sayur/pkg/logging"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// restoreLogging puts back the global logger and level that the other tests rely on
+func restoreLogging(t *testing.T) {
+	logger, level := log.Logger, zerolog.GlobalLevel()
+	t.Cleanup(func() {
+		log.Logger = logger
+		zerolog.SetGlobalLevel(level)
+	})
+}
+
+func TestSetup_RejectsUnknownLevelAndFormat(t *testing.T) {
+	restoreLogging(t)
+
+	assert.EqualError(t, logging.Setup(logging.Config{Level: "verbose"}, "user-service", "test"), `unknown log level "verbose"`)
+	assert.EqualError(t, logging.Setup(logging.Config{Format: "xml"}, "user-service", "test"), `unknown log format "xml", use json or console`)
+
+	assert.NoError(t, logging.Setup(logging.Config{Level: "WARN", Format: "console"}, "user-service", "test"))
+	assert.Equal(t, "warn", logging.Level())
+	assert.NoError(t, logging.Setup(logging.Config{}, "user-service", "test"))
+	assert.Equal(t, "info", logging.Level())
+}
+
+func TestToggleDebug_SwitchesBetweenDebugAndTheConfiguredLevel(t *testing.T) {
+	restoreLogging(t)
+	assert.NoError(t, logging.Setup(logging.Config{Level: "warn"}, "user-service", "test"))
+
+	logging.ToggleDebug()
+	assert.Equal(t, "debug", logging.Level())
+	logging.ToggleDebug()
+	assert.Equal(t, "warn", logging.Level())
+
+	// A level set through the admin endpoint toggles to debug too
+	assert.NoError(t, logging.SetLevel("error"))
+	logging.ToggleDebug()
+	assert.Equal(t, "debug", logging.Level())
+}
+
+func TestLogLevelHandler_SetLevel(t *testing.T) {
+	restoreLogging(t)
+	assert.NoError(t, logging.Setup(logging.Config{Level: "info"}, "user-service", "test"))
+	logLevelHandler := handler.NewLogLevelHandler()
+
+	serve := func(body string) *httptest.ResponseRecorder {
+		e := echo.New()
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/log-level", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.Set("user_id", int64(1))
+		_ = logLevelHandler.SetLevel(c)
+		return rec
+	}
+
+	rec := serve(`{"level":"verbose"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "info", logging.Level())
+
+	rec = serve(`{"level":"debug"}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"level":"debug"`)
+	assert.Equal(t, "debug", logging.Level())
+}
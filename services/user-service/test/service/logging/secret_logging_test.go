@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"user-service/config"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// moduleRoot is relative to this package, which is where go test runs it from
+const moduleRoot = "../../.."
+
+// fieldMethods are the zerolog event methods whose first argument is the field key
+var fieldMethods = map[string]bool{"Str": true, "Strs": true, "Interface": true, "Any": true, "Bytes": true, "RawJSON": true, "Stringer": true}
+
+// formatMethods take values straight into the message
+var formatMethods = map[string]bool{"Msgf": true, "Printf": true, "Println": true, "Print": true, "Fatalf": true, "Panicf": true}
+
+// nonSecretKeys look sensitive by name but only describe a secret
+var nonSecretKeys = map[string]bool{"token_type": true}
+
+// logSafe reports a value that is already masked: a fingerprint, a redaction or any other call
+func logSafe(expr ast.Expr) bool {
+	switch v := expr.(type) {
+	case *ast.CallExpr:
+		return true
+	case *ast.SelectorExpr:
+		return v.Sel.Name == "RedactedValue"
+	}
+	return false
+}
+
+// sensitiveName is the variable or field name of a value such as token, req.Token or authHeader
+func sensitiveName(expr ast.Expr) (string, bool) {
+	var name string
+	switch v := expr.(type) {
+	case *ast.Ident:
+		name = v.Name
+	case *ast.SelectorExpr:
+		name = v.Sel.Name
+	default:
+		return "", false
+	}
+	return name, utils.IsSensitiveKey(name) || strings.EqualFold(name, "authHeader")
+}
+
+func scanLogCalls(t *testing.T, root string) (violations []string, calls int) {
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == "vendor" || strings.HasPrefix(d.Name(), ".") && path != root {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			pos := fset.Position(call.Pos())
+
+			switch {
+			case fieldMethods[sel.Sel.Name] && len(call.Args) >= 2:
+				lit, ok := call.Args[0].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+				calls++
+				key, _ := strconv.Unquote(lit.Value)
+				if nonSecretKeys[key] || logSafe(call.Args[1]) {
+					return true
+				}
+				if utils.IsSensitiveKey(key) {
+					violations = append(violations, pos.String()+": field "+strconv.Quote(key)+" logs a raw secret")
+				} else if name, ok := sensitiveName(call.Args[1]); ok {
+					violations = append(violations, pos.String()+": field "+strconv.Quote(key)+" logs "+name)
+				}
+			case formatMethods[sel.Sel.Name]:
+				calls++
+				for _, arg := range call.Args {
+					if name, ok := sensitiveName(arg); ok {
+						violations = append(violations, pos.String()+": "+sel.Sel.Name+" formats "+name)
+					}
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	require.NoError(t, err)
+	return violations, calls
+}
+
+func TestLogCallSites_NeverLogRawSecrets(t *testing.T) {
+	violations, calls := scanLogCalls(t, moduleRoot)
+
+	// Guards against the scan silently finding nothing, e.g. after the package moves
+	assert.Greater(t, calls, 500)
+	assert.Empty(t, violations, "wrap secrets in utils.SecretFingerprint before logging them")
+}
+
+func TestSecretFingerprint(t *testing.T) {
+	fingerprint := utils.SecretFingerprint("reset-token-123")
+
+	hash := sha256.Sum256([]byte("reset-token-123"))
+	stored := hex.EncodeToString(hash[:])
+
+	assert.Len(t, fingerprint, 12)
+	assert.True(t, strings.HasPrefix(stored, fingerprint), "matches the stored token_hash prefix")
+	assert.Equal(t, fingerprint, utils.SecretFingerprint("reset-token-123"))
+	assert.NotEqual(t, fingerprint, utils.SecretFingerprint("reset-token-124"))
+	assert.Empty(t, utils.SecretFingerprint(""))
+}
+
+func TestResetPassword_LogsFingerprintInsteadOfToken(t *testing.T) {
+	var buf bytes.Buffer
+	original := log.Logger
+	log.Logger = zerolog.New(&buf)
+	t.Cleanup(func() { log.Logger = original })
+
+	ctx := context.Background()
+	tokenRepo := new(mocks.MockVerificationTokenRepository)
+	tokenRepo.On("GetVerificationToken", ctx, "secret-reset-token").Return(nil, gorm.ErrRecordNotFound)
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{VerificationTokenRepo: tokenRepo},
+		Config:          &config.Config{},
+	})
+
+	err := userService.ResetPassword(ctx, "secret-reset-token", "new-password-123", "new-password-123")
+
+	assert.Error(t, err)
+	assert.NotContains(t, buf.String(), "secret-reset-token")
+	assert.Contains(t, buf.String(), `"token_fingerprint":"`+utils.SecretFingerprint("secret-reset-token")+`"`)
+}
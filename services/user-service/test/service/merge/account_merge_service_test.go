@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mergeFixture struct {
+	mergeRepo   *mocks.MockAccountMergeRepository
+	sessionRepo *mocks.MockSessionRepository
+	email       *mocks.MockEmailPublisher
+	jobRepo     *mocks.MockJobRepository
+	auditRepo   *mocks.MockAuditLogRepository
+	service     port.AccountMergeServiceInterface
+}
+
+func newMergeFixture() *mergeFixture {
+	f := &mergeFixture{
+		mergeRepo:   new(mocks.MockAccountMergeRepository),
+		sessionRepo: new(mocks.MockSessionRepository),
+		email:       new(mocks.MockEmailPublisher),
+		jobRepo:     new(mocks.MockJobRepository),
+		auditRepo:   new(mocks.MockAuditLogRepository),
+	}
+	f.service = service.NewAccountMergeService(f.mergeRepo, f.sessionRepo, f.email, service.NewJobService(f.jobRepo), service.NewAuditLogService(f.auditRepo))
+	return f
+}
+
+func mergeResult() *entity.AccountMergeEntity {
+	return &entity.AccountMergeEntity{
+		SourceUserID:        3,
+		TargetUserID:        9,
+		SourceEmail:         "old@example.com",
+		TargetEmail:         "new@example.com",
+		ProfileFieldsCopied: []string{"address"},
+		DevicesMoved:        2,
+		MergedAt:            time.Now(),
+	}
+}
+
+func TestMergeAccounts_Success(t *testing.T) {
+	ctx := context.Background()
+	f := newMergeFixture()
+
+	f.mergeRepo.On("MergeAccounts", ctx, int64(3), int64(9), false).Return(mergeResult(), nil)
+	f.sessionRepo.On("DeleteAllUserTokens", ctx, int64(3)).Return(nil)
+	f.auditRepo.On("Create", ctx, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == entity.AuditEventAccountMerged && auditLog.UserID == 9 && auditLog.Metadata["admin_id"] == int64(1)
+	})).Return(nil)
+	f.jobRepo.On("Enqueue", ctx, mock.MatchedBy(func(job *entity.JobEntity) bool {
+		return job.Type == entity.JobTypePublishEvent && job.UniqueKey == "user.merged:3"
+	})).Return(&entity.JobEntity{ID: 1}, nil)
+	f.email.On("SendAccountMergedEmail", ctx, "new@example.com", "old@example.com", "new@example.com").Return(nil)
+	f.email.On("SendAccountMergedEmail", ctx, "old@example.com", "old@example.com", "new@example.com").Return(nil)
+
+	result, err := f.service.MergeAccounts(ctx, 3, 9, 1, "customer changed email", false)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), result.DevicesMoved)
+	f.sessionRepo.AssertExpectations(t)
+	f.auditRepo.AssertExpectations(t)
+	f.jobRepo.AssertExpectations(t)
+	f.email.AssertExpectations(t)
+}
+
+func TestMergeAccounts_DryRunHasNoSideEffects(t *testing.T) {
+	ctx := context.Background()
+	f := newMergeFixture()
+
+	preview := mergeResult()
+	preview.DryRun = true
+	f.mergeRepo.On("MergeAccounts", ctx, int64(3), int64(9), true).Return(preview, nil)
+
+	result, err := f.service.MergeAccounts(ctx, 3, 9, 1, "", true)
+
+	assert.NoError(t, err)
+	assert.True(t, result.DryRun)
+	f.sessionRepo.AssertNotCalled(t, "DeleteAllUserTokens", mock.Anything, mock.Anything)
+	f.jobRepo.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+	f.email.AssertNotCalled(t, "SendAccountMergedEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMergeAccounts_Validation(t *testing.T) {
+	ctx := context.Background()
+	f := newMergeFixture()
+
+	_, err := f.service.MergeAccounts(ctx, 3, 3, 1, "duplicate", false)
+	assert.EqualError(t, err, "cannot merge an account into itself")
+
+	_, err = f.service.MergeAccounts(ctx, 0, 3, 1, "duplicate", false)
+	assert.EqualError(t, err, "invalid user id")
+
+	_, err = f.service.MergeAccounts(ctx, 3, 9, 1, "  ", false)
+	assert.EqualError(t, err, "merge reason is required")
+
+	f.mergeRepo.AssertNotCalled(t, "MergeAccounts", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestMergeAccounts_RepositoryErrors(t *testing.T) {
+	ctx := context.Background()
+	f := newMergeFixture()
+
+	f.mergeRepo.On("MergeAccounts", ctx, int64(3), int64(9), false).Return(nil, errors.New("both accounts have vendor profiles")).Once()
+	_, err := f.service.MergeAccounts(ctx, 3, 9, 1, "duplicate", false)
+	assert.EqualError(t, err, "both accounts have vendor profiles")
+
+	f.mergeRepo.On("MergeAccounts", ctx, int64(3), int64(9), false).Return(nil, errors.New("deadlock detected")).Once()
+	_, err = f.service.MergeAccounts(ctx, 3, 9, 1, "duplicate", false)
+	assert.EqualError(t, err, "failed to merge accounts")
+
+	f.sessionRepo.AssertNotCalled(t, "DeleteAllUserTokens", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-service/internal/adapter/middleware"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBodyLimitServer() *echo.Echo {
+	e := echo.New()
+	e.Use(middleware.BodyLimitMiddleware(10, 100, "/api/v1/uploads/:id/chunks/:index"))
+
+	readAll := func(c echo.Context) error {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return c.NoContent(http.StatusBadRequest)
+		}
+		return c.String(http.StatusOK, string(body))
+	}
+	e.POST("/api/v1/auth/signin", readAll)
+	e.PUT("/api/v1/uploads/:id/chunks/:index", readAll)
+	return e
+}
+
+func TestBodyLimitMiddleware_RejectsDeclaredOversizedBody(t *testing.T) {
+	e := newBodyLimitServer()
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/auth/signin", strings.NewReader(strings.Repeat("a", 11))))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/auth/signin", strings.NewReader("small")))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestBodyLimitMiddleware_GivesUploadRoutesTheLargerLimit(t *testing.T) {
+	e := newBodyLimitServer()
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/uploads/abc/chunks/0", strings.NewReader(strings.Repeat("a", 50))))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/uploads/abc/chunks/0", strings.NewReader(strings.Repeat("a", 101))))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestBodyLimitMiddleware_CapsBodiesWithoutLength(t *testing.T) {
+	e := newBodyLimitServer()
+
+	// A chunked body declares no length, so the limit is enforced while reading
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/signin", io.NopCloser(strings.NewReader(strings.Repeat("a", 11))))
+	req.ContentLength = -1
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
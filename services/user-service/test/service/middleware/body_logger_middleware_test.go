@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-service/internal/adapter/middleware"
+	"user-service/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	original := log.Logger
+	log.Logger = zerolog.New(&buf)
+	t.Cleanup(func() { log.Logger = original })
+	return &buf
+}
+
+func TestBodyLoggerMiddleware_RedactsSecrets(t *testing.T) {
+	logs := captureLogs(t)
+
+	e := echo.New()
+	body := `{"email":"john@example.com","password":"hunter2","profile":{"new_password":"hunter3"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/signin?token=abc123", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer secret-jwt")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := middleware.BodyLoggerMiddleware(middleware.BodyLoggerConfig{SampleRate: 1})(func(c echo.Context) error {
+		// The handler must still see the original body
+		received, _ := io.ReadAll(c.Request().Body)
+		assert.Equal(t, body, string(received))
+		return c.JSON(http.StatusOK, map[string]string{"access_token": "jwt-value", "message": "ok"})
+	})
+
+	assert.NoError(t, handler(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "jwt-value")
+
+	output := logs.String()
+	assert.Contains(t, output, "john@example.com")
+	assert.Contains(t, output, utils.RedactedValue)
+	for _, secret := range []string{"hunter2", "hunter3", "abc123", "secret-jwt", "jwt-value"} {
+		assert.NotContains(t, output, secret)
+	}
+}
+
+func TestBodyLoggerMiddleware_TruncatesAndSkipsBinary(t *testing.T) {
+	logs := captureLogs(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("binary-data"))
+	req.Header.Set(echo.HeaderContentType, "image/png")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := middleware.BodyLoggerMiddleware(middleware.BodyLoggerConfig{SampleRate: 1, MaxBodyBytes: 20})(func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"message": strings.Repeat("x", 100)})
+	})
+
+	assert.NoError(t, handler(c))
+
+	output := logs.String()
+	assert.NotContains(t, output, "binary-data")
+	assert.Contains(t, output, "[body omitted: image/png]")
+	assert.Contains(t, output, "...(truncated)")
+}
+
+func TestBodyLoggerMiddleware_DisabledWhenSampleRateZero(t *testing.T) {
+	logs := captureLogs(t)
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"a":1}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	handler := middleware.BodyLoggerMiddleware(middleware.BodyLoggerConfig{})(func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	assert.NoError(t, handler(c))
+	assert.Empty(t, logs.String())
+}
+
+func TestRedactURI(t *testing.T) {
+	assert.Equal(t, "/auth/verify?token=%5BREDACTED%5D", utils.RedactURI("/auth/verify?token=abc"))
+	assert.Equal(t, "/customers?page=2", utils.RedactURI("/customers?page=2"))
+	assert.Equal(t, "/health", utils.RedactURI("/health"))
+}
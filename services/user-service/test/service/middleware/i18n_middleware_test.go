@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/adapter/middleware"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newLocalizedEcho() *echo.Echo {
+	e := echo.New()
+	e.JSONSerializer = middleware.LocalizedJSONSerializer{}
+	e.Use(middleware.LanguageMiddleware())
+	e.GET("/default", func(c echo.Context) error {
+		return c.JSON(http.StatusNotFound, response.DefaultResponse{Message: "User not found"})
+	})
+	e.GET("/map", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"message": "Users retrieved successfully",
+			"data":    []string{"User not found"},
+		})
+	})
+	return e
+}
+
+func TestLanguageMiddleware_TranslatesMessageForIndonesianClient(t *testing.T) {
+	e := newLocalizedEcho()
+
+	for _, path := range []string{"/default", "/map"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Language", "id-ID,id;q=0.9,en;q=0.8")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, "id", rec.Header().Get("Content-Language"))
+		assert.Contains(t, rec.Header().Values(echo.HeaderVary), "Accept-Language")
+		assert.NotContains(t, rec.Body.String(), `"message":"User`)
+	}
+}
+
+func TestLanguageMiddleware_OnlyTranslatesTopLevelMessage(t *testing.T) {
+	e := newLocalizedEcho()
+	req := httptest.NewRequest(http.MethodGet, "/map", nil)
+	req.Header.Set("Accept-Language", "id")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Contains(t, rec.Body.String(), `"message":"Daftar pengguna berhasil diambil"`)
+	// Data is returned untouched
+	assert.Contains(t, rec.Body.String(), `"data":["User not found"]`)
+}
+
+func TestLanguageMiddleware_FallsBackToEnglish(t *testing.T) {
+	e := newLocalizedEcho()
+
+	for _, header := range []string{"", "fr-FR,de;q=0.5", "id;q=0"} {
+		req := httptest.NewRequest(http.MethodGet, "/default", nil)
+		req.Header.Set("Accept-Language", header)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, "en", rec.Header().Get("Content-Language"), header)
+		assert.Contains(t, rec.Body.String(), `"message":"User not found"`, header)
+	}
+}
+
+func TestLanguageMiddleware_TranslatesEchoErrors(t *testing.T) {
+	e := newLocalizedEcho()
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	req.Header.Set("Accept-Language", "id")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Tidak ditemukan")
+}
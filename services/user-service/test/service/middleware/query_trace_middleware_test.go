@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"user-service/internal/adapter/middleware"
+	"user-service/utils/querytrace"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryTraceMiddleware_TracesRouteAndRequestID(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.RequestIDMiddleware())
+	e.Use(middleware.QueryTraceMiddleware(30))
+
+	var trace *querytrace.Trace
+	e.GET("/api/v1/users/:id", func(c echo.Context) error {
+		trace = querytrace.FromContext(c.Request().Context())
+		trace.Record(time.Millisecond)
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/42", nil)
+	req.Header.Set(echo.HeaderXRequestID, "client-req-1")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.NotNil(t, trace)
+	assert.Equal(t, "/api/v1/users/:id", trace.Route)
+	assert.Equal(t, http.MethodGet, trace.Method)
+	assert.Equal(t, "client-req-1", trace.RequestID)
+	assert.Equal(t, "client-req-1", rec.Header().Get(echo.HeaderXRequestID))
+	assert.EqualValues(t, 1, trace.Queries())
+}
+
+func TestQueryTraceMiddleware_GeneratesRequestID(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.RequestIDMiddleware())
+	e.Use(middleware.QueryTraceMiddleware(30))
+
+	var requestID string
+	e.GET("/health", func(c echo.Context) error {
+		requestID = querytrace.FromContext(c.Request().Context()).RequestID
+		return c.NoContent(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	assert.NotEmpty(t, requestID)
+	assert.Equal(t, requestID, rec.Header().Get(echo.HeaderXRequestID))
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-service/config"
+	"user-service/internal/adapter/middleware"
+
+	"github.com/hilmirazib/jualan-sayur/pkg/security"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSecureServer(cfg config.Security) *echo.Echo {
+	e := echo.New()
+	securityConfig := middleware.SecurityConfig(cfg)
+	e.Use(security.HeadersMiddleware(securityConfig))
+	e.Use(security.CSRFMiddleware(securityConfig))
+	e.GET(middleware.CSRFTokenPath, security.CSRFTokenHandler)
+	ok := func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}
+	e.GET("/api/v1/profile", ok)
+	e.POST("/api/v1/profile", ok)
+	e.POST("/api/v1/auth/signin", ok)
+	e.POST("/internal/users/batch", ok)
+	return e
+}
+
+func TestSecurityHeadersMiddleware_Defaults(t *testing.T) {
+	e := newSecureServer(config.Security{HSTSMaxAge: 31536000})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/profile", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "DENY", rec.Header().Get("X-Frame-Options"))
+	assert.Equal(t, "strict-origin-when-cross-origin", rec.Header().Get("Referrer-Policy"))
+	assert.Empty(t, rec.Header().Get("Strict-Transport-Security"), "HSTS is never sent over plain HTTP")
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/profile", nil)
+	req.Header.Set(echo.HeaderXForwardedProto, "https")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "max-age=31536000; includeSubdomains", rec.Header().Get("Strict-Transport-Security"))
+}
+
+func TestCSRFMiddleware_DisabledByDefault(t *testing.T) {
+	e := newSecureServer(config.Security{SessionCookieName: "sayur_session"})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/profile", nil)
+	req.AddCookie(&http.Cookie{Name: "sayur_session", Value: "session"})
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestCSRFMiddleware_DoubleSubmit(t *testing.T) {
+	e := newSecureServer(config.Security{CSRFEnabled: true, SessionCookieName: "sayur_session"})
+
+	// Bearer clients never carry the session cookie and are not affected
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/profile", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer jwt")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Cookie sessions without a token are rejected
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/profile", nil)
+	req.AddCookie(&http.Cookie{Name: "sayur_session", Value: "session"})
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Fetch a token, then submit it in both the cookie and the header
+	req = httptest.NewRequest(http.MethodGet, middleware.CSRFTokenPath, nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data map[string]string `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	token := body.Data["csrf_token"]
+	assert.NotEmpty(t, token)
+
+	var csrfCookie *http.Cookie
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == security.CSRFCookieName {
+			csrfCookie = cookie
+		}
+	}
+	if assert.NotNil(t, csrfCookie) {
+		assert.Equal(t, token, csrfCookie.Value)
+		assert.Equal(t, http.SameSiteStrictMode, csrfCookie.SameSite)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/profile", nil)
+	req.AddCookie(&http.Cookie{Name: "sayur_session", Value: "session"})
+	req.AddCookie(&http.Cookie{Name: security.CSRFCookieName, Value: token})
+	req.Header.Set(security.CSRFHeaderName, token)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/profile", nil)
+	req.AddCookie(&http.Cookie{Name: "sayur_session", Value: "session"})
+	req.AddCookie(&http.Cookie{Name: security.CSRFCookieName, Value: token})
+	req.Header.Set(security.CSRFHeaderName, "forged")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+// csrfToken fetches a token the way a browser does before it has a session
+func csrfToken(t *testing.T, e *echo.Echo) string {
+	req := httptest.NewRequest(http.MethodGet, middleware.CSRFTokenPath, nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data map[string]string `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return body.Data["csrf_token"]
+}
+
+func TestCSRFMiddleware_SignInWithoutSessionNeedsToken(t *testing.T) {
+	e := newSecureServer(config.Security{CSRFEnabled: true, SessionCookieName: "sayur_session"})
+
+	// A cross-site form carries neither a session nor a token; it must not sign the browser in
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/signin", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	token := csrfToken(t, e)
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/auth/signin", nil)
+	req.AddCookie(&http.Cookie{Name: security.CSRFCookieName, Value: token})
+	req.Header.Set(security.CSRFHeaderName, token)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestCSRFMiddleware_ExemptsOnlyListedRoutes(t *testing.T) {
+	e := newSecureServer(config.Security{CSRFEnabled: true, SessionCookieName: "sayur_session"})
+
+	// Service-to-service calls authenticate with a key header, never the session cookie
+	req := httptest.NewRequest(http.MethodPost, "/internal/users/batch", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// Safe requests without a session get no CSRF cookie they did not ask for
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/profile", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Result().Cookies())
+
+	// A session cookie is checked even on an exempt-looking bearer request
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/profile", nil)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer jwt")
+	req.AddCookie(&http.Cookie{Name: "sayur_session", Value: "session"})
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-service/config"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func serveInternal(e *echo.Echo, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/internal/users/batch", nil)
+	if token != "" {
+		req.Header.Set(middleware.ServiceTokenHeader, token)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServiceAuthMiddleware_DisabledWithoutKeys(t *testing.T) {
+	e := echo.New()
+	e.POST("/internal/users/batch", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}, middleware.ServiceAuthMiddleware(&config.Config{}))
+
+	assert.Equal(t, http.StatusServiceUnavailable, serveInternal(e, "anything").Code)
+}
+
+func TestServiceAuthMiddleware_SetsServicePrincipal(t *testing.T) {
+	cfg := &config.Config{InternalAuth: config.InternalAuth{ServiceKeys: map[string][]string{
+		// Two keys for order-service while it is being rotated
+		"order-service":    {"order-old-key", "order-new-key"},
+		"delivery-service": {"delivery-key"},
+	}}}
+
+	var principal entity.PrincipalEntity
+	e := echo.New()
+	e.POST("/internal/users/batch", func(c echo.Context) error {
+		principal, _ = middleware.GetPrincipal(c)
+		return c.String(http.StatusOK, "ok")
+	}, middleware.ServiceAuthMiddleware(cfg))
+
+	assert.Equal(t, http.StatusUnauthorized, serveInternal(e, "").Code)
+	assert.Equal(t, http.StatusUnauthorized, serveInternal(e, "wrong").Code)
+
+	for _, key := range []string{"order-old-key", "order-new-key"} {
+		assert.Equal(t, http.StatusOK, serveInternal(e, key).Code)
+		assert.True(t, principal.IsService())
+		assert.Equal(t, "order-service", principal.ServiceName)
+	}
+
+	assert.Equal(t, http.StatusOK, serveInternal(e, "delivery-key").Code)
+	assert.Equal(t, "delivery-service", principal.ServiceName)
+}
+
+func TestServiceAuthMiddleware_PutsPrincipalOnRequestContext(t *testing.T) {
+	cfg := &config.Config{InternalAuth: config.InternalAuth{ServiceKeys: map[string][]string{
+		"order-service": {"order-key"},
+	}}}
+
+	var principal entity.PrincipalEntity
+	var found bool
+	e := echo.New()
+	e.POST("/internal/users/batch", func(c echo.Context) error {
+		// Services and repositories only see the request context, not the echo.Context
+		principal, found = entity.PrincipalFromContext(c.Request().Context())
+		return c.String(http.StatusOK, "ok")
+	}, middleware.ServiceAuthMiddleware(cfg))
+
+	assert.Equal(t, http.StatusOK, serveInternal(e, "order-key").Code)
+	assert.True(t, found)
+	assert.True(t, principal.IsService())
+	assert.Equal(t, "order-service", principal.ServiceName)
+}
+
+func TestServiceAuthMiddleware_AllowedServices(t *testing.T) {
+	cfg := &config.Config{InternalAuth: config.InternalAuth{ServiceKeys: map[string][]string{
+		"order-service":        {"order-key"},
+		"notification-service": {"notification-key"},
+	}}}
+
+	e := echo.New()
+	e.POST("/internal/users/batch", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}, middleware.ServiceAuthMiddleware(cfg, "order-service"))
+
+	assert.Equal(t, http.StatusOK, serveInternal(e, "order-key").Code)
+	assert.Equal(t, http.StatusForbidden, serveInternal(e, "notification-key").Code)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/adapter/middleware"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestJWTMiddleware_AcceptsSessionCookie(t *testing.T) {
+	cfg := &config.Config{
+		App:      config.App{JwtSecretKey: "test-secret", JwtIssuer: "test"},
+		Security: config.Security{SessionCookieEnabled: true, SessionCookieName: "sayur_session"},
+	}
+	token, err := utils.GenerateJWTWithSession(cfg, 1, "john@example.com", "Customer", "sess_1", false)
+	assert.NoError(t, err)
+
+	sessionRepo := new(mocks.MockSessionRepository)
+	sessionRepo.On("ValidateToken", mock.Anything, int64(1), "sess_1", token).Return(true)
+
+	e := echo.New()
+	e.GET("/api/v1/auth/profile", func(c echo.Context) error {
+		assert.Equal(t, token, c.Get("access_token"))
+		principal, ok := middleware.GetPrincipal(c)
+		assert.True(t, ok)
+		assert.True(t, principal.IsUser())
+		assert.Equal(t, int64(1), principal.UserID)
+		return c.String(http.StatusOK, "ok")
+	}, middleware.JWTMiddleware(cfg, sessionRepo, nil, nil, nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/profile", nil)
+	req.AddCookie(&http.Cookie{Name: "sayur_session", Value: token})
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	// The cookie is ignored while cookie sessions are disabled
+	cfg.Security.SessionCookieEnabled = false
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestSessionCookie_Attributes(t *testing.T) {
+	cfg := config.Security{SessionCookieEnabled: true, SessionCookieName: "sayur_session", SessionCookieSameSite: "strict", CookieSecure: true}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/signin", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	assert.False(t, middleware.UsesCookieSession(c, cfg), "bearer mode stays the default")
+
+	req.Header.Set(middleware.ClientTypeHeader, "web")
+	assert.True(t, middleware.UsesCookieSession(c, cfg))
+
+	middleware.SetSessionCookie(c, cfg, "jwt", 12*time.Hour)
+	cookies := rec.Result().Cookies()
+	if assert.Len(t, cookies, 1) {
+		assert.Equal(t, 12*60*60, cookies[0].MaxAge)
+		assert.Equal(t, "sayur_session", cookies[0].Name)
+		assert.True(t, cookies[0].HttpOnly)
+		assert.True(t, cookies[0].Secure)
+		assert.Equal(t, http.SameSiteStrictMode, cookies[0].SameSite)
+		assert.Equal(t, "/api/v1", cookies[0].Path)
+	}
+
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	middleware.ClearSessionCookies(c, cfg)
+	for _, cookie := range rec.Result().Cookies() {
+		assert.Equal(t, -1, cookie.MaxAge)
+	}
+}
+
+func TestSecurity_ValidateRequiresCSRFForCookieSessions(t *testing.T) {
+	assert.NoError(t, config.Security{}.Validate())
+	assert.NoError(t, config.Security{CSRFEnabled: true}.Validate())
+	assert.NoError(t, config.Security{SessionCookieEnabled: true, CSRFEnabled: true}.Validate())
+
+	assert.ErrorContains(t, config.Security{SessionCookieEnabled: true}.Validate(), "CSRF_ENABLED")
+}
@@ -117,3 +117,38 @@ func TestSuperAdminMiddleware_Forbidden_InvalidRole(t *testing.T) {
 	expectedResponse := `{"message":"Access denied","data":null}`
 	assert.JSONEq(t, expectedResponse, rec.Body.String())
 }
+
+func TestSuperAdminMiddleware_AllowsInheritingRole(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/roles", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	// Owner inherits from Super Admin
+	c.Set("user_role", "Owner")
+	c.Set(middleware.UserRolesContextKey, []string{"Owner", "Super Admin"})
+
+	handler := middleware.SuperAdminMiddleware()(func(c echo.Context) error {
+		return c.String(http.StatusOK, "success")
+	})
+
+	assert.NoError(t, handler(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRoleMiddleware_UsesResolvedRolesOnly(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/courier/deliveries", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	c.Set("user_role", "Courier Lead")
+	c.Set(middleware.UserRolesContextKey, []string{"Courier Lead"})
+
+	handler := middleware.RoleMiddleware("Courier")(func(c echo.Context) error {
+		return c.String(http.StatusOK, "success")
+	})
+
+	assert.NoError(t, handler(c))
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"user-service/internal/adapter/middleware"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutMiddleware_AnswersServiceUnavailableOnDeadline(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/profile", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := middleware.TimeoutMiddleware(10*time.Millisecond, time.Second)(func(c echo.Context) error {
+		// Stands in for a repository call that honours the request context
+		<-c.Request().Context().Done()
+		return c.Request().Context().Err()
+	})
+
+	err := handler(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), "Request timed out")
+}
+
+func TestTimeoutMiddleware_GivesUploadsTheLongerDeadline(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/profile/image-upload", nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEMultipartForm+"; boundary=x")
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	var remaining time.Duration
+	handler := middleware.TimeoutMiddleware(10*time.Millisecond, time.Minute)(func(c echo.Context) error {
+		deadline, _ := c.Request().Context().Deadline()
+		remaining = time.Until(deadline)
+		return c.NoContent(http.StatusOK)
+	})
+
+	assert.NoError(t, handler(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Greater(t, remaining, 30*time.Second)
+}
@@ -3,6 +3,7 @@ package mocks
 import (
 	"context"
 	"io"
+	"time"
 	"user-service/internal/core/domain/entity"
 	"user-service/utils"
 
@@ -56,6 +57,32 @@ func (m *MockUserRepository) UpdateUserPassword(ctx context.Context, userID int6
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) ChangeUserPassword(ctx context.Context, userID int64, hashedPassword string, keepHistory int) error {
+	args := m.Called(ctx, userID, hashedPassword, keepHistory)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetPasswordHistory(ctx context.Context, userID int64, limit int) ([]string, error) {
+	args := m.Called(ctx, userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockUserRepository) GetPasswordsDueForWarning(ctx context.Context, roles []string, changedAfter, changedBefore time.Time, afterID int64, limit int) ([]entity.PasswordExpiryEntity, error) {
+	args := m.Called(ctx, roles, changedAfter, changedBefore, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.PasswordExpiryEntity), args.Error(1)
+}
+
+func (m *MockUserRepository) MarkPasswordExpiryWarned(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) GetUserByID(ctx context.Context, userID int64) (*entity.UserEntity, error) {
 	args := m.Called(ctx, userID)
 	if args.Get(0) == nil {
@@ -69,13 +96,23 @@ func (m *MockUserRepository) UpdateUserPhoto(ctx context.Context, userID int64,
 	return args.Error(0)
 }
 
+func (m *MockUserRepository) UpdateUserPhotos(ctx context.Context, userID int64, photoURL, originalURL string) error {
+	args := m.Called(ctx, userID, photoURL, originalURL)
+	return args.Error(0)
+}
+
 func (m *MockUserRepository) UpdateUserEmail(ctx context.Context, userID int64, email string) error {
 	args := m.Called(ctx, userID, email)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) UpdateUserProfile(ctx context.Context, userID int64, name, email, phone, address string, lat, lng float64, photo string) error {
-	args := m.Called(ctx, userID, name, email, phone, address, lat, lng, photo)
+func (m *MockUserRepository) UpdateUserProfile(ctx context.Context, userID int64, name, email, phone, address string, lat, lng float64, photo string, version int64) error {
+	args := m.Called(ctx, userID, name, email, phone, address, lat, lng, photo, version)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateUserAddressComponents(ctx context.Context, userID int64, address entity.AddressEntity) error {
+	args := m.Called(ctx, userID, address)
 	return args.Error(0)
 }
 
@@ -87,6 +124,14 @@ func (m *MockUserRepository) GetCustomers(ctx context.Context, search string, pa
 	return args.Get(0).([]entity.UserEntity), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockUserRepository) SuggestCustomers(ctx context.Context, query string, limit int) ([]entity.UserEntity, error) {
+	args := m.Called(ctx, query, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UserEntity), args.Error(1)
+}
+
 func (m *MockUserRepository) GetCustomerByID(ctx context.Context, customerID int64) (*entity.UserEntity, error) {
 	args := m.Called(ctx, customerID)
 	if args.Get(0) == nil {
@@ -95,6 +140,109 @@ func (m *MockUserRepository) GetCustomerByID(ctx context.Context, customerID int
 	return args.Get(0).(*entity.UserEntity), args.Error(1)
 }
 
+func (m *MockUserRepository) GetCustomersNearby(ctx context.Context, lat, lng, radiusKm float64, limit int) ([]entity.UserEntity, error) {
+	args := m.Called(ctx, lat, lng, radiusKm, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UserEntity), args.Error(1)
+}
+
+func (m *MockUserRepository) GetUsersByIDs(ctx context.Context, userIDs []int64) ([]entity.UserEntity, error) {
+	args := m.Called(ctx, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UserEntity), args.Error(1)
+}
+
+func (m *MockUserRepository) GetUserByUsername(ctx context.Context, username string) (*entity.UserEntity, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.UserEntity), args.Error(1)
+}
+
+func (m *MockUserRepository) IsUsernameTaken(ctx context.Context, username string) (bool, error) {
+	args := m.Called(ctx, username)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateUsername(ctx context.Context, userID int64, username string) error {
+	args := m.Called(ctx, userID, username)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateTimezone(ctx context.Context, userID int64, timezone string) error {
+	args := m.Called(ctx, userID, timezone)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateLanguage(ctx context.Context, userID int64, language string) error {
+	args := m.Called(ctx, userID, language)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateUserRole(ctx context.Context, userID, roleID int64) error {
+	args := m.Called(ctx, userID, roleID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdatePrivacySettings(ctx context.Context, userID int64, settings entity.PrivacySettingsEntity) error {
+	args := m.Called(ctx, userID, settings)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetLanguageByEmail(ctx context.Context, email string) (string, error) {
+	args := m.Called(ctx, email)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserRepository) ClearPhotoByURL(ctx context.Context, photoURL string) ([]int64, error) {
+	args := m.Called(ctx, photoURL)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *MockUserRepository) IsPhotoReferenced(ctx context.Context, photoURL string) (bool, error) {
+	args := m.Called(ctx, photoURL)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) RestoreEmailAndLock(ctx context.Context, userID int64, email, reason string) error {
+	args := m.Called(ctx, userID, email, reason)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UnlockUser(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) MarkEmailBounced(ctx context.Context, userID int64, email, reason string) error {
+	args := m.Called(ctx, userID, email, reason)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetLockedUsers(ctx context.Context) ([]entity.UserEntity, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UserEntity), args.Error(1)
+}
+
+func (m *MockUserRepository) GetEmailsByRole(ctx context.Context, roleName string) ([]string, error) {
+	args := m.Called(ctx, roleName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
 func (m *MockUserRepository) CreateCustomer(ctx context.Context, customer *entity.UserEntity) (*entity.UserEntity, error) {
 	args := m.Called(ctx, customer)
 	if args.Get(0) == nil {
@@ -118,11 +266,19 @@ type MockSessionRepository struct {
 	mock.Mock
 }
 
-func (m *MockSessionRepository) StoreToken(ctx context.Context, userID int64, sessionID, token string) error {
-	args := m.Called(ctx, userID, sessionID, token)
+func (m *MockSessionRepository) StoreToken(ctx context.Context, userID int64, sessionID, token string, rememberMe bool) error {
+	args := m.Called(ctx, userID, sessionID, token, rememberMe)
 	return args.Error(0)
 }
 
+func (m *MockSessionRepository) GetSession(ctx context.Context, userID int64, sessionID string) (*entity.SessionInfo, error) {
+	args := m.Called(ctx, userID, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SessionInfo), args.Error(1)
+}
+
 func (m *MockSessionRepository) GetToken(ctx context.Context, userID int64, sessionID string) (string, error) {
 	args := m.Called(ctx, userID, sessionID)
 	return args.String(0), args.Error(1)
@@ -158,8 +314,8 @@ func (m *MockJWTUtil) GenerateJWT(userID int64, email, roleName string) (string,
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockJWTUtil) GenerateJWTWithSession(userID int64, email, role, sessionID string) (string, error) {
-	args := m.Called(userID, email, role, sessionID)
+func (m *MockJWTUtil) GenerateJWTWithSession(userID int64, email, role, sessionID string, rememberMe bool) (string, error) {
+	args := m.Called(userID, email, role, sessionID, rememberMe)
 	return args.String(0), args.Error(1)
 }
 
@@ -189,11 +345,26 @@ func (m *MockVerificationTokenRepository) GetVerificationToken(ctx context.Conte
 	return args.Get(0).(*entity.VerificationTokenEntity), args.Error(1)
 }
 
-func (m *MockVerificationTokenRepository) DeleteVerificationToken(ctx context.Context, token string) error {
+func (m *MockVerificationTokenRepository) ClaimVerificationToken(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func (m *MockVerificationTokenRepository) ReleaseVerificationToken(ctx context.Context, token string) error {
 	args := m.Called(ctx, token)
 	return args.Error(0)
 }
 
+func (m *MockVerificationTokenRepository) DeleteExpiredVerificationTokens(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockVerificationTokenRepository) DeleteUserTokens(ctx context.Context, userID int64, tokenType string) (int64, error) {
+	args := m.Called(ctx, userID, tokenType)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // MockEmailPublisher mocks the email publisher
 type MockEmailPublisher struct {
 	mock.Mock
@@ -229,6 +400,16 @@ func (m *MockBlacklistTokenRepository) IsTokenBlacklisted(ctx context.Context, t
 	return args.Bool(0)
 }
 
+func (m *MockBlacklistTokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockBlacklistTokenRepository) Count(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // MockStorage mocks the storage interface
 type MockStorage struct {
 	mock.Mock
@@ -257,6 +438,14 @@ func (m *MockRoleRepository) GetAllRoles(ctx context.Context, search string) ([]
 	return args.Get(0).([]entity.RoleEntity), args.Error(1)
 }
 
+func (m *MockRoleRepository) GetRoles(ctx context.Context, search string, page, limit int) ([]entity.RoleEntity, int64, error) {
+	args := m.Called(ctx, search, page, limit)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entity.RoleEntity), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockRoleRepository) GetRoleByID(ctx context.Context, id int64) (*entity.RoleEntity, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -281,6 +470,14 @@ func (m *MockRoleRepository) UpdateRole(ctx context.Context, id int64, role *ent
 	return args.Get(0).(*entity.RoleEntity), args.Error(1)
 }
 
+func (m *MockRoleRepository) UpdateRoleParent(ctx context.Context, id int64, parentID *int64, version int64) (*entity.RoleEntity, error) {
+	args := m.Called(ctx, id, parentID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RoleEntity), args.Error(1)
+}
+
 func (m *MockRoleRepository) DeleteRole(ctx context.Context, id int64) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -291,12 +488,12 @@ type MockRoleService struct {
 	mock.Mock
 }
 
-func (m *MockRoleService) GetAllRoles(ctx context.Context, search string) ([]entity.RoleEntity, error) {
-	args := m.Called(ctx, search)
+func (m *MockRoleService) GetAllRoles(ctx context.Context, search string, page, limit int) ([]entity.RoleEntity, *entity.PaginationEntity, error) {
+	args := m.Called(ctx, search, page, limit)
 	if args.Get(0) == nil {
-		return nil, args.Error(1)
+		return nil, nil, args.Error(2)
 	}
-	return args.Get(0).([]entity.RoleEntity), args.Error(1)
+	return args.Get(0).([]entity.RoleEntity), args.Get(1).(*entity.PaginationEntity), args.Error(2)
 }
 
 func (m *MockRoleService) GetRoleByID(ctx context.Context, id int64) (*entity.RoleEntity, error) {
@@ -315,8 +512,16 @@ func (m *MockRoleService) CreateRole(ctx context.Context, name string) (*entity.
 	return args.Get(0).(*entity.RoleEntity), args.Error(1)
 }
 
-func (m *MockRoleService) UpdateRole(ctx context.Context, id int64, name string) (*entity.RoleEntity, error) {
-	args := m.Called(ctx, id, name)
+func (m *MockRoleService) UpdateRole(ctx context.Context, id int64, name string, version int64) (*entity.RoleEntity, error) {
+	args := m.Called(ctx, id, name, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.RoleEntity), args.Error(1)
+}
+
+func (m *MockRoleService) SetRoleParent(ctx context.Context, id int64, parentID *int64, version int64) (*entity.RoleEntity, error) {
+	args := m.Called(ctx, id, parentID, version)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -328,6 +533,11 @@ func (m *MockRoleService) DeleteRole(ctx context.Context, id int64) error {
 	return args.Error(0)
 }
 
+func (m *MockRoleService) EffectiveRoles(ctx context.Context, roleName string) []string {
+	args := m.Called(ctx, roleName)
+	return args.Get(0).([]string)
+}
+
 // MockUserService mocks the user service
 type MockUserService struct {
 	mock.Mock
@@ -375,6 +585,14 @@ func (m *MockUserService) GetUserByID(ctx context.Context, userID int64) (*entit
 	return args.Get(0).(*entity.UserEntity), args.Error(1)
 }
 
+func (m *MockUserService) SignInExternal(ctx context.Context, userID int64, client entity.ClientEntity, rememberMe bool) (*entity.UserEntity, string, error) {
+	args := m.Called(ctx, userID, client, rememberMe)
+	if args.Get(0) == nil {
+		return nil, "", args.Error(2)
+	}
+	return args.Get(0).(*entity.UserEntity), args.String(1), args.Error(2)
+}
+
 func (m *MockUserService) UpdateUserPhoto(ctx context.Context, userID int64, photoURL string) error {
 	args := m.Called(ctx, userID, photoURL)
 	return args.Error(0)
@@ -415,3 +633,1453 @@ func (m *MockUserService) DeleteCustomer(ctx context.Context, customerID int64)
 	args := m.Called(ctx, customerID)
 	return args.Error(0)
 }
+
+// MockGeocoder mocks the geocoder adapter
+type MockGeocoder struct {
+	mock.Mock
+}
+
+func (m *MockGeocoder) ReverseGeocode(ctx context.Context, lat, lng float64) (*entity.AddressEntity, error) {
+	args := m.Called(ctx, lat, lng)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AddressEntity), args.Error(1)
+}
+
+func (m *MockGeocoder) Geocode(ctx context.Context, address string) (*entity.AddressEntity, error) {
+	args := m.Called(ctx, address)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AddressEntity), args.Error(1)
+}
+
+// MockDeliveryZoneRepository mocks the delivery zone repository
+type MockDeliveryZoneRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeliveryZoneRepository) GetAllZones(ctx context.Context, activeOnly bool) ([]entity.DeliveryZoneEntity, error) {
+	args := m.Called(ctx, activeOnly)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.DeliveryZoneEntity), args.Error(1)
+}
+
+func (m *MockDeliveryZoneRepository) GetZoneByID(ctx context.Context, id int64) (*entity.DeliveryZoneEntity, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.DeliveryZoneEntity), args.Error(1)
+}
+
+func (m *MockDeliveryZoneRepository) CreateZone(ctx context.Context, zone *entity.DeliveryZoneEntity) (*entity.DeliveryZoneEntity, error) {
+	args := m.Called(ctx, zone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.DeliveryZoneEntity), args.Error(1)
+}
+
+func (m *MockDeliveryZoneRepository) UpdateZone(ctx context.Context, id int64, zone *entity.DeliveryZoneEntity) (*entity.DeliveryZoneEntity, error) {
+	args := m.Called(ctx, id, zone)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.DeliveryZoneEntity), args.Error(1)
+}
+
+func (m *MockDeliveryZoneRepository) DeleteZone(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockEmailRateLimitRepository mocks the email rate limit repository and its Redis snapshot
+type MockEmailRateLimitRepository struct {
+	mock.Mock
+}
+
+func (m *MockEmailRateLimitRepository) GetAllLimits(ctx context.Context) ([]entity.EmailRateLimitEntity, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.EmailRateLimitEntity), args.Error(1)
+}
+
+func (m *MockEmailRateLimitRepository) UpsertLimit(ctx context.Context, limit *entity.EmailRateLimitEntity) (*entity.EmailRateLimitEntity, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.EmailRateLimitEntity), args.Error(1)
+}
+
+func (m *MockEmailRateLimitRepository) DeleteLimit(ctx context.Context, emailType string) error {
+	args := m.Called(ctx, emailType)
+	return args.Error(0)
+}
+
+func (m *MockEmailRateLimitRepository) PublishLimits(ctx context.Context, limits []entity.EmailRateLimitEntity) error {
+	args := m.Called(ctx, limits)
+	return args.Error(0)
+}
+
+// MockCDNPurger mocks the CDN cache purge
+type MockCDNPurger struct {
+	mock.Mock
+}
+
+func (m *MockCDNPurger) Purge(ctx context.Context, urls []string) error {
+	args := m.Called(ctx, urls)
+	return args.Error(0)
+}
+
+// MockStoredObjectRepository mocks the stored object repository
+type MockStoredObjectRepository struct {
+	mock.Mock
+}
+
+func (m *MockStoredObjectRepository) GetObject(ctx context.Context, bucket, objectName string) (*entity.StoredObjectEntity, error) {
+	args := m.Called(ctx, bucket, objectName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.StoredObjectEntity), args.Error(1)
+}
+
+func (m *MockStoredObjectRepository) AddReference(ctx context.Context, object *entity.StoredObjectEntity) (*entity.StoredObjectEntity, error) {
+	args := m.Called(ctx, object)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.StoredObjectEntity), args.Error(1)
+}
+
+func (m *MockStoredObjectRepository) RemoveReference(ctx context.Context, bucket, objectName string) (int, error) {
+	args := m.Called(ctx, bucket, objectName)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockStoredObjectRepository) DeleteUnreferenced(ctx context.Context, bucket, objectName string) (bool, error) {
+	args := m.Called(ctx, bucket, objectName)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStoredObjectRepository) ForgetObject(ctx context.Context, bucket, objectName string) error {
+	args := m.Called(ctx, bucket, objectName)
+	return args.Error(0)
+}
+
+// MockSavedViewRepository mocks the saved view repository
+type MockSavedViewRepository struct {
+	mock.Mock
+}
+
+func (m *MockSavedViewRepository) GetViews(ctx context.Context, ownerID int64, list string) ([]entity.SavedViewEntity, error) {
+	args := m.Called(ctx, ownerID, list)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.SavedViewEntity), args.Error(1)
+}
+
+func (m *MockSavedViewRepository) GetViewByID(ctx context.Context, id int64) (*entity.SavedViewEntity, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SavedViewEntity), args.Error(1)
+}
+
+func (m *MockSavedViewRepository) CreateView(ctx context.Context, view *entity.SavedViewEntity) (*entity.SavedViewEntity, error) {
+	args := m.Called(ctx, view)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SavedViewEntity), args.Error(1)
+}
+
+func (m *MockSavedViewRepository) UpdateView(ctx context.Context, view *entity.SavedViewEntity) (*entity.SavedViewEntity, error) {
+	args := m.Called(ctx, view)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SavedViewEntity), args.Error(1)
+}
+
+func (m *MockSavedViewRepository) DeleteView(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockVendorRepository mocks the vendor repository
+type MockVendorRepository struct {
+	mock.Mock
+}
+
+func (m *MockVendorRepository) CreateVendor(ctx context.Context, vendor *entity.VendorEntity) (*entity.VendorEntity, error) {
+	args := m.Called(ctx, vendor)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.VendorEntity), args.Error(1)
+}
+
+func (m *MockVendorRepository) GetVendorByID(ctx context.Context, id int64) (*entity.VendorEntity, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.VendorEntity), args.Error(1)
+}
+
+func (m *MockVendorRepository) GetVendorByUserID(ctx context.Context, userID int64) (*entity.VendorEntity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.VendorEntity), args.Error(1)
+}
+
+func (m *MockVendorRepository) GetVendors(ctx context.Context, status string, page, limit int) ([]entity.VendorEntity, int64, error) {
+	args := m.Called(ctx, status, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entity.VendorEntity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockVendorRepository) AddVendorDocument(ctx context.Context, document *entity.VendorDocumentEntity) (*entity.VendorDocumentEntity, error) {
+	args := m.Called(ctx, document)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.VendorDocumentEntity), args.Error(1)
+}
+
+func (m *MockVendorRepository) ApproveVendor(ctx context.Context, id, reviewerID int64) error {
+	args := m.Called(ctx, id, reviewerID)
+	return args.Error(0)
+}
+
+func (m *MockVendorRepository) RejectVendor(ctx context.Context, id, reviewerID int64, reason string) error {
+	args := m.Called(ctx, id, reviewerID, reason)
+	return args.Error(0)
+}
+
+// MockLedgerRepository mocks the ledger repository
+type MockLedgerRepository struct {
+	mock.Mock
+}
+
+func (m *MockLedgerRepository) RecordTransaction(ctx context.Context, transaction *entity.LedgerTransactionEntity) (*entity.LedgerTransactionEntity, error) {
+	args := m.Called(ctx, transaction)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.LedgerTransactionEntity), args.Error(1)
+}
+
+func (m *MockLedgerRepository) GetVendorBalance(ctx context.Context, vendorID int64) (*entity.VendorBalanceEntity, error) {
+	args := m.Called(ctx, vendorID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.VendorBalanceEntity), args.Error(1)
+}
+
+func (m *MockLedgerRepository) GetVendorEntries(ctx context.Context, vendorID int64, page, limit int) ([]entity.LedgerEntryEntity, int64, error) {
+	args := m.Called(ctx, vendorID, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entity.LedgerEntryEntity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockLedgerRepository) CreateWithdrawal(ctx context.Context, withdrawal *entity.WithdrawalEntity) (*entity.WithdrawalEntity, error) {
+	args := m.Called(ctx, withdrawal)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WithdrawalEntity), args.Error(1)
+}
+
+func (m *MockLedgerRepository) GetWithdrawalByID(ctx context.Context, id int64) (*entity.WithdrawalEntity, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WithdrawalEntity), args.Error(1)
+}
+
+func (m *MockLedgerRepository) GetWithdrawals(ctx context.Context, vendorID int64, status string, page, limit int) ([]entity.WithdrawalEntity, int64, error) {
+	args := m.Called(ctx, vendorID, status, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entity.WithdrawalEntity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockLedgerRepository) ApproveWithdrawal(ctx context.Context, id, reviewerID int64) error {
+	args := m.Called(ctx, id, reviewerID)
+	return args.Error(0)
+}
+
+func (m *MockLedgerRepository) RejectWithdrawal(ctx context.Context, id, reviewerID int64, reason string) error {
+	args := m.Called(ctx, id, reviewerID, reason)
+	return args.Error(0)
+}
+
+func (m *MockLedgerRepository) GetLedgerAudit(ctx context.Context) (*entity.LedgerAuditEntity, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.LedgerAuditEntity), args.Error(1)
+}
+
+// MockJobRepository mocks the job repository
+type MockJobRepository struct {
+	mock.Mock
+}
+
+func (m *MockJobRepository) Enqueue(ctx context.Context, job *entity.JobEntity) (*entity.JobEntity, error) {
+	args := m.Called(ctx, job)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.JobEntity), args.Error(1)
+}
+
+func (m *MockJobRepository) ClaimNext(ctx context.Context, types []string, workerID string) (*entity.JobEntity, error) {
+	args := m.Called(ctx, types, workerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.JobEntity), args.Error(1)
+}
+
+func (m *MockJobRepository) MarkCompleted(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) MarkFailed(ctx context.Context, id int64, lastError string, retryAt *time.Time) error {
+	args := m.Called(ctx, id, lastError, retryAt)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) RequeueStale(ctx context.Context, lockedBefore time.Time) (int64, error) {
+	args := m.Called(ctx, lockedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockJobRepository) DeleteCompletedBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockJobRepository) GetJobs(ctx context.Context, status, jobType string, page, limit int) ([]entity.JobEntity, int64, error) {
+	args := m.Called(ctx, status, jobType, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entity.JobEntity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockJobRepository) Release(ctx context.Context, jobType string) (int64, error) {
+	args := m.Called(ctx, jobType)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockJobRepository) RetryJob(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// MockFeatureFlagRepository mocks the feature flag repository
+type MockFeatureFlagRepository struct {
+	mock.Mock
+}
+
+func (m *MockFeatureFlagRepository) GetAllFlags(ctx context.Context) ([]entity.FeatureFlagEntity, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.FeatureFlagEntity), args.Error(1)
+}
+
+func (m *MockFeatureFlagRepository) GetFlagByKey(ctx context.Context, key string) (*entity.FeatureFlagEntity, error) {
+	args := m.Called(ctx, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.FeatureFlagEntity), args.Error(1)
+}
+
+func (m *MockFeatureFlagRepository) CreateFlag(ctx context.Context, flag *entity.FeatureFlagEntity) (*entity.FeatureFlagEntity, error) {
+	args := m.Called(ctx, flag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.FeatureFlagEntity), args.Error(1)
+}
+
+func (m *MockFeatureFlagRepository) UpdateFlag(ctx context.Context, key string, flag *entity.FeatureFlagEntity) (*entity.FeatureFlagEntity, error) {
+	args := m.Called(ctx, key, flag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.FeatureFlagEntity), args.Error(1)
+}
+
+func (m *MockFeatureFlagRepository) DeleteFlag(ctx context.Context, key string) error {
+	args := m.Called(ctx, key)
+	return args.Error(0)
+}
+
+func (m *MockFeatureFlagRepository) SetOverride(ctx context.Context, key string, userID int64, enabled bool) error {
+	args := m.Called(ctx, key, userID, enabled)
+	return args.Error(0)
+}
+
+func (m *MockFeatureFlagRepository) DeleteOverride(ctx context.Context, key string, userID int64) error {
+	args := m.Called(ctx, key, userID)
+	return args.Error(0)
+}
+
+// MockIPAccessRepository mocks the admin IP rule repository
+type MockIPAccessRepository struct {
+	mock.Mock
+}
+
+func (m *MockIPAccessRepository) GetRules(ctx context.Context, listType string) ([]entity.IPRuleEntity, error) {
+	args := m.Called(ctx, listType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.IPRuleEntity), args.Error(1)
+}
+
+func (m *MockIPAccessRepository) AddRule(ctx context.Context, rule *entity.IPRuleEntity) error {
+	args := m.Called(ctx, rule)
+	return args.Error(0)
+}
+
+func (m *MockIPAccessRepository) RemoveRule(ctx context.Context, listType, cidr string) error {
+	args := m.Called(ctx, listType, cidr)
+	return args.Error(0)
+}
+
+// MockOnboardingRepository mocks the onboarding repository
+type MockOnboardingRepository struct {
+	mock.Mock
+}
+
+func (m *MockOnboardingRepository) GetState(ctx context.Context, userID int64) (*entity.OnboardingStateEntity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.OnboardingStateEntity), args.Error(1)
+}
+
+func (m *MockOnboardingRepository) Dismiss(ctx context.Context, userID int64) error {
+	args := m.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (m *MockOnboardingRepository) MarkFirstOrder(ctx context.Context, userID int64, orderedAt time.Time) error {
+	args := m.Called(ctx, userID, orderedAt)
+	return args.Error(0)
+}
+
+func (m *MockOnboardingRepository) MarkCompleted(ctx context.Context, userID int64) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionRepository) SetSessionClient(ctx context.Context, userID int64, sessionID string, client entity.ClientEntity, location *entity.LoginLocationEntity) error {
+	args := m.Called(ctx, userID, sessionID, client, location)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) MarkSSOSession(ctx context.Context, userID int64, sessionID string) error {
+	args := m.Called(ctx, userID, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockEmailPublisher) SendNewDeviceSignInEmail(ctx context.Context, email, device, ipAddress, location string, signedInAt time.Time) error {
+	args := m.Called(ctx, email, device, ipAddress, location, signedInAt)
+	return args.Error(0)
+}
+
+// MockDeviceRepository mocks the device repository
+type MockDeviceRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeviceRepository) TouchDevice(ctx context.Context, userID int64, client entity.ClientEntity) (*entity.DeviceEntity, bool, error) {
+	args := m.Called(ctx, userID, client)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*entity.DeviceEntity), args.Bool(1), args.Error(2)
+}
+
+func (m *MockDeviceRepository) CountDevices(ctx context.Context, userID int64) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDeviceRepository) GetDevices(ctx context.Context, userID int64) ([]entity.DeviceEntity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.DeviceEntity), args.Error(1)
+}
+
+func (m *MockDeviceRepository) SetTrusted(ctx context.Context, userID, deviceID int64, trusted bool) error {
+	args := m.Called(ctx, userID, deviceID, trusted)
+	return args.Error(0)
+}
+
+func (m *MockEmailPublisher) SendSignInOTPEmail(ctx context.Context, email, code string, expiresAt time.Time) error {
+	args := m.Called(ctx, email, code, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockDeviceRepository) FindDevice(ctx context.Context, userID int64, fingerprint string) (*entity.DeviceEntity, error) {
+	args := m.Called(ctx, userID, fingerprint)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.DeviceEntity), args.Error(1)
+}
+
+// MockAuditLogRepository mocks the audit log repository
+type MockAuditLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditLogRepository) Create(ctx context.Context, auditLog *entity.AuditLogEntity) error {
+	args := m.Called(ctx, auditLog)
+	return args.Error(0)
+}
+
+func (m *MockAuditLogRepository) List(ctx context.Context, filter entity.AuditLogFilter, page, limit int) ([]entity.AuditLogEntity, int64, error) {
+	args := m.Called(ctx, filter, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entity.AuditLogEntity), args.Get(1).(int64), args.Error(2)
+}
+
+// MockRiskRepository mocks the sign-in risk repository
+type MockRiskRepository struct {
+	mock.Mock
+}
+
+func (m *MockRiskRepository) IncrementFailedSignIns(ctx context.Context, email string, window time.Duration) (int64, error) {
+	args := m.Called(ctx, email, window)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRiskRepository) GetFailedSignIns(ctx context.Context, email string) (int64, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRiskRepository) ResetFailedSignIns(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockRiskRepository) GetLastLocation(ctx context.Context, userID int64) (*entity.LoginLocationEntity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.LoginLocationEntity), args.Error(1)
+}
+
+func (m *MockRiskRepository) SetLastLocation(ctx context.Context, userID int64, location entity.LoginLocationEntity) error {
+	args := m.Called(ctx, userID, location)
+	return args.Error(0)
+}
+
+func (m *MockRiskRepository) SaveChallenge(ctx context.Context, challenge *entity.StepUpChallengeEntity) error {
+	args := m.Called(ctx, challenge)
+	return args.Error(0)
+}
+
+func (m *MockRiskRepository) GetChallenge(ctx context.Context, challengeID string) (*entity.StepUpChallengeEntity, error) {
+	args := m.Called(ctx, challengeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.StepUpChallengeEntity), args.Error(1)
+}
+
+func (m *MockRiskRepository) IncrementChallengeAttempts(ctx context.Context, challengeID string) (int, error) {
+	args := m.Called(ctx, challengeID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRiskRepository) DeleteChallenge(ctx context.Context, challengeID string) error {
+	args := m.Called(ctx, challengeID)
+	return args.Error(0)
+}
+
+// MockIPLocator mocks IP geolocation
+type MockIPLocator struct {
+	mock.Mock
+}
+
+func (m *MockIPLocator) Locate(ctx context.Context, ip string) (*entity.LoginLocationEntity, error) {
+	args := m.Called(ctx, ip)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.LoginLocationEntity), args.Error(1)
+}
+
+func (m *MockEmailPublisher) SendEmailChangeRevertEmail(ctx context.Context, email, newEmail, token string) error {
+	args := m.Called(ctx, email, newEmail, token)
+	return args.Error(0)
+}
+
+func (m *MockEmailPublisher) SendAccountLockedAlertEmail(ctx context.Context, adminEmail string, userID int64, restoredEmail, replacedEmail string) error {
+	args := m.Called(ctx, adminEmail, userID, restoredEmail, replacedEmail)
+	return args.Error(0)
+}
+
+func (m *MockEmailPublisher) SendPasswordChangedEmail(ctx context.Context, email string, changedAt time.Time) error {
+	args := m.Called(ctx, email, changedAt)
+	return args.Error(0)
+}
+
+func (m *MockEmailPublisher) SendPasswordExpiryWarningEmail(ctx context.Context, email, name string, expiresAt time.Time) error {
+	args := m.Called(ctx, email, name, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockEmailPublisher) SendAccountMergedEmail(ctx context.Context, email, mergedEmail, survivingEmail string) error {
+	args := m.Called(ctx, email, mergedEmail, survivingEmail)
+	return args.Error(0)
+}
+
+func (m *MockEmailPublisher) SendCustomerInviteEmail(ctx context.Context, email, name, token string) error {
+	args := m.Called(ctx, email, name, token)
+	return args.Error(0)
+}
+
+func (m *MockEmailPublisher) SendPhotoRemovedEmail(ctx context.Context, email, name, reason string) error {
+	args := m.Called(ctx, email, name, reason)
+	return args.Error(0)
+}
+
+func (m *MockEmailPublisher) SendCampaignEmail(ctx context.Context, email, name string, campaign *entity.CampaignEntity) error {
+	args := m.Called(ctx, email, name, campaign)
+	return args.Error(0)
+}
+
+func (m *MockEmailPublisher) SendSupportTicketUpdateEmail(ctx context.Context, email, name string, ticket *entity.SupportTicketEntity, note string) error {
+	args := m.Called(ctx, email, name, ticket, note)
+	return args.Error(0)
+}
+
+// MockAccountMergeRepository mocks the account merge repository
+type MockAccountMergeRepository struct {
+	mock.Mock
+}
+
+func (m *MockAccountMergeRepository) MergeAccounts(ctx context.Context, sourceUserID, targetUserID int64, dryRun bool) (*entity.AccountMergeEntity, error) {
+	args := m.Called(ctx, sourceUserID, targetUserID, dryRun)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AccountMergeEntity), args.Error(1)
+}
+
+// MockTrashRepository mocks the soft-deleted records repository
+type MockTrashRepository struct {
+	mock.Mock
+}
+
+func (m *MockTrashRepository) GetDeletedUsers(ctx context.Context, search string, page, limit int) ([]entity.TrashedUserEntity, int64, error) {
+	args := m.Called(ctx, search, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entity.TrashedUserEntity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTrashRepository) RestoreUser(ctx context.Context, userID int64) (*entity.TrashedUserEntity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.TrashedUserEntity), args.Error(1)
+}
+
+func (m *MockTrashRepository) PurgeUser(ctx context.Context, userID int64) (*entity.TrashedUserEntity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.TrashedUserEntity), args.Error(1)
+}
+
+func (m *MockTrashRepository) GetDeletedRoles(ctx context.Context, search string, page, limit int) ([]entity.TrashedRoleEntity, int64, error) {
+	args := m.Called(ctx, search, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entity.TrashedRoleEntity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTrashRepository) RestoreRole(ctx context.Context, roleID int64) (*entity.TrashedRoleEntity, error) {
+	args := m.Called(ctx, roleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.TrashedRoleEntity), args.Error(1)
+}
+
+func (m *MockTrashRepository) PurgeRole(ctx context.Context, roleID int64) (*entity.TrashedRoleEntity, error) {
+	args := m.Called(ctx, roleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.TrashedRoleEntity), args.Error(1)
+}
+
+// MockCustomerImportReportRepository mocks the customer import report repository
+type MockCustomerImportReportRepository struct {
+	mock.Mock
+}
+
+func (m *MockCustomerImportReportRepository) SaveReport(ctx context.Context, reportID string, report []byte, ttl time.Duration) error {
+	args := m.Called(ctx, reportID, report, ttl)
+	return args.Error(0)
+}
+
+func (m *MockCustomerImportReportRepository) GetReport(ctx context.Context, reportID string) ([]byte, error) {
+	args := m.Called(ctx, reportID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+// MockWebhookRepository mocks the webhook repository
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) CreateEndpoint(ctx context.Context, endpoint *entity.WebhookEndpointEntity) (*entity.WebhookEndpointEntity, error) {
+	args := m.Called(ctx, endpoint)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WebhookEndpointEntity), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetEndpoints(ctx context.Context) ([]entity.WebhookEndpointEntity, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.WebhookEndpointEntity), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetEndpointByID(ctx context.Context, id int64) (*entity.WebhookEndpointEntity, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WebhookEndpointEntity), args.Error(1)
+}
+
+func (m *MockWebhookRepository) UpdateEndpoint(ctx context.Context, id int64, endpoint *entity.WebhookEndpointEntity) (*entity.WebhookEndpointEntity, error) {
+	args := m.Called(ctx, id, endpoint)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WebhookEndpointEntity), args.Error(1)
+}
+
+func (m *MockWebhookRepository) DeleteEndpoint(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetActiveEndpointsForEvent(ctx context.Context, eventType string) ([]entity.WebhookEndpointEntity, error) {
+	args := m.Called(ctx, eventType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.WebhookEndpointEntity), args.Error(1)
+}
+
+func (m *MockWebhookRepository) CreateDelivery(ctx context.Context, delivery *entity.WebhookDeliveryEntity) (*entity.WebhookDeliveryEntity, error) {
+	args := m.Called(ctx, delivery)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WebhookDeliveryEntity), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetDeliveryByID(ctx context.Context, id int64) (*entity.WebhookDeliveryEntity, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WebhookDeliveryEntity), args.Error(1)
+}
+
+func (m *MockWebhookRepository) RecordAttempt(ctx context.Context, id int64, attempt *entity.WebhookAttemptEntity) error {
+	args := m.Called(ctx, id, attempt)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ResetDelivery(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDeliveries(ctx context.Context, endpointID int64, status string, page, limit int) ([]entity.WebhookDeliveryEntity, int64, error) {
+	args := m.Called(ctx, endpointID, status, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entity.WebhookDeliveryEntity), args.Get(1).(int64), args.Error(2)
+}
+
+// MockWebhookSender mocks the outgoing webhook HTTP client
+type MockWebhookSender struct {
+	mock.Mock
+}
+
+func (m *MockWebhookSender) Send(ctx context.Context, url string, headers map[string]string, body []byte) (*entity.WebhookResponseEntity, error) {
+	args := m.Called(ctx, url, headers, body)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.WebhookResponseEntity), args.Error(1)
+}
+
+// MockFileScanner mocks the antivirus scanner
+type MockFileScanner struct {
+	mock.Mock
+}
+
+func (m *MockFileScanner) Scan(ctx context.Context, file io.Reader) (*entity.ScanResultEntity, error) {
+	args := m.Called(ctx, file)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ScanResultEntity), args.Error(1)
+}
+
+// MockPhotoModerationRepository mocks the photo moderation repository
+type MockPhotoModerationRepository struct {
+	mock.Mock
+}
+
+func (m *MockPhotoModerationRepository) Create(ctx context.Context, moderation *entity.PhotoModerationEntity) (*entity.PhotoModerationEntity, error) {
+	args := m.Called(ctx, moderation)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PhotoModerationEntity), args.Error(1)
+}
+
+func (m *MockPhotoModerationRepository) GetByID(ctx context.Context, id int64) (*entity.PhotoModerationEntity, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PhotoModerationEntity), args.Error(1)
+}
+
+func (m *MockPhotoModerationRepository) List(ctx context.Context, status string, page, limit int) ([]entity.PhotoModerationEntity, int64, error) {
+	args := m.Called(ctx, status, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entity.PhotoModerationEntity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPhotoModerationRepository) UpdateStatus(ctx context.Context, id int64, fromStatuses []string, update *entity.PhotoModerationEntity) error {
+	args := m.Called(ctx, id, fromStatuses, update)
+	return args.Error(0)
+}
+
+// MockImageModerator mocks the image moderation provider
+type MockImageModerator struct {
+	mock.Mock
+}
+
+func (m *MockImageModerator) Moderate(ctx context.Context, imageURL string) (*entity.ModerationResultEntity, error) {
+	args := m.Called(ctx, imageURL)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ModerationResultEntity), args.Error(1)
+}
+
+// MockPhotoModerationSubmitter mocks the hook the profile photo upload calls
+type MockPhotoModerationSubmitter struct {
+	mock.Mock
+}
+
+func (m *MockPhotoModerationSubmitter) Submit(ctx context.Context, userID int64, imageURL string) error {
+	args := m.Called(ctx, userID, imageURL)
+	return args.Error(0)
+}
+
+type MockRateLimitRepository struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitRepository) Hit(ctx context.Context, key string, limit int, window time.Duration) (entity.RateLimitEntity, error) {
+	args := m.Called(ctx, key, limit, window)
+	return args.Get(0).(entity.RateLimitEntity), args.Error(1)
+}
+
+// MockAPIKeyRepository mocks the partner API key store
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) CreateAPIKey(ctx context.Context, apiKey *entity.APIKeyEntity) (*entity.APIKeyEntity, error) {
+	args := m.Called(ctx, apiKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.APIKeyEntity), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeys(ctx context.Context, page, limit int) ([]entity.APIKeyEntity, int64, error) {
+	args := m.Called(ctx, page, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entity.APIKeyEntity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeyByID(ctx context.Context, id int64) (*entity.APIKeyEntity, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.APIKeyEntity), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, keyHash string) (*entity.APIKeyEntity, error) {
+	args := m.Called(ctx, keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.APIKeyEntity), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) RevokeAPIKey(ctx context.Context, id, revokedBy int64, at time.Time) error {
+	args := m.Called(ctx, id, revokedBy, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) TouchLastUsed(ctx context.Context, id int64, at time.Time) error {
+	args := m.Called(ctx, id, at)
+	return args.Error(0)
+}
+
+// MockAPIKeyService mocks API key authentication for middleware tests
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyService) CreateAPIKey(ctx context.Context, apiKey *entity.APIKeyEntity) (*entity.APIKeyEntity, string, error) {
+	args := m.Called(ctx, apiKey)
+	if args.Get(0) == nil {
+		return nil, args.String(1), args.Error(2)
+	}
+	return args.Get(0).(*entity.APIKeyEntity), args.String(1), args.Error(2)
+}
+
+func (m *MockAPIKeyService) GetAPIKeys(ctx context.Context, page, limit int) ([]entity.APIKeyEntity, *entity.PaginationEntity, error) {
+	args := m.Called(ctx, page, limit)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]entity.APIKeyEntity), args.Get(1).(*entity.PaginationEntity), args.Error(2)
+}
+
+func (m *MockAPIKeyService) GetAPIKeyByID(ctx context.Context, id int64) (*entity.APIKeyEntity, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.APIKeyEntity), args.Error(1)
+}
+
+func (m *MockAPIKeyService) RevokeAPIKey(ctx context.Context, id, revokedBy int64) error {
+	args := m.Called(ctx, id, revokedBy)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyService) Authenticate(ctx context.Context, plainKey string) (*entity.APIKeyEntity, error) {
+	args := m.Called(ctx, plainKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.APIKeyEntity), args.Error(1)
+}
+
+// MockSegmentRepository mocks the customer segment repository
+type MockSegmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockSegmentRepository) CreateSegment(ctx context.Context, segment *entity.SegmentEntity) (*entity.SegmentEntity, error) {
+	args := m.Called(ctx, segment)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SegmentEntity), args.Error(1)
+}
+
+func (m *MockSegmentRepository) GetSegments(ctx context.Context) ([]entity.SegmentEntity, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.SegmentEntity), args.Error(1)
+}
+
+func (m *MockSegmentRepository) GetSegmentByID(ctx context.Context, id int64) (*entity.SegmentEntity, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SegmentEntity), args.Error(1)
+}
+
+func (m *MockSegmentRepository) DeleteSegment(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSegmentRepository) Materialize(ctx context.Context, id int64) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSegmentRepository) GetMemberIDs(ctx context.Context, segmentID, afterUserID int64, limit int) ([]int64, error) {
+	args := m.Called(ctx, segmentID, afterUserID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]int64), args.Error(1)
+}
+
+func (m *MockSegmentRepository) CreateCampaign(ctx context.Context, campaign *entity.CampaignEntity) (*entity.CampaignEntity, error) {
+	args := m.Called(ctx, campaign)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.CampaignEntity), args.Error(1)
+}
+
+func (m *MockSegmentRepository) UpdateCampaignFanOut(ctx context.Context, id, recipients int64, batches int) error {
+	args := m.Called(ctx, id, recipients, batches)
+	return args.Error(0)
+}
+
+func (m *MockSegmentRepository) GetCampaignByID(ctx context.Context, id int64) (*entity.CampaignEntity, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.CampaignEntity), args.Error(1)
+}
+
+func (m *MockSegmentRepository) GetRecipients(ctx context.Context, userIDs []int64) ([]entity.UserEntity, error) {
+	args := m.Called(ctx, userIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UserEntity), args.Error(1)
+}
+
+func (m *MockSegmentRepository) RecordOrder(ctx context.Context, userID int64, orderID string, orderedAt time.Time) (bool, error) {
+	args := m.Called(ctx, userID, orderID, orderedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockAssetRepository mocks the email asset repository
+type MockAssetRepository struct {
+	mock.Mock
+}
+
+func (m *MockAssetRepository) GetAssets(ctx context.Context) ([]entity.AssetEntity, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.AssetEntity), args.Error(1)
+}
+
+func (m *MockAssetRepository) GetAsset(ctx context.Context, name string) (*entity.AssetEntity, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.AssetEntity), args.Error(1)
+}
+
+func (m *MockAssetRepository) SaveAsset(ctx context.Context, asset *entity.AssetEntity) error {
+	args := m.Called(ctx, asset)
+	return args.Error(0)
+}
+
+// MockInviteRepository mocks the invite code repository
+type MockInviteRepository struct {
+	mock.Mock
+}
+
+func (m *MockInviteRepository) CreateInvite(ctx context.Context, invite *entity.InviteEntity) (*entity.InviteEntity, error) {
+	args := m.Called(ctx, invite)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.InviteEntity), args.Error(1)
+}
+
+func (m *MockInviteRepository) GetInvites(ctx context.Context, page, limit int) ([]entity.InviteEntity, int64, error) {
+	args := m.Called(ctx, page, limit)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entity.InviteEntity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockInviteRepository) GetInviteByCode(ctx context.Context, code string) (*entity.InviteEntity, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.InviteEntity), args.Error(1)
+}
+
+func (m *MockInviteRepository) RevokeInvite(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockInviteRepository) ReserveUse(ctx context.Context, code string) (int64, bool, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(int64), args.Bool(1), args.Error(2)
+}
+
+func (m *MockInviteRepository) ReleaseUse(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockInviteRepository) CreateRedemption(ctx context.Context, id int64, email string) error {
+	args := m.Called(ctx, id, email)
+	return args.Error(0)
+}
+
+// MockLegalRepository mocks the legal document and consent repository
+type MockLegalRepository struct {
+	mock.Mock
+}
+
+func (m *MockLegalRepository) CreateDocument(ctx context.Context, doc *entity.LegalDocumentEntity) (*entity.LegalDocumentEntity, error) {
+	args := m.Called(ctx, doc)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.LegalDocumentEntity), args.Error(1)
+}
+
+func (m *MockLegalRepository) GetDocuments(ctx context.Context, kind string) ([]entity.LegalDocumentEntity, error) {
+	args := m.Called(ctx, kind)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.LegalDocumentEntity), args.Error(1)
+}
+
+func (m *MockLegalRepository) GetCurrentDocuments(ctx context.Context) ([]entity.LegalDocumentEntity, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.LegalDocumentEntity), args.Error(1)
+}
+
+func (m *MockLegalRepository) GetUserConsent(ctx context.Context, userID int64) (*entity.ConsentStatusEntity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.ConsentStatusEntity), args.Error(1)
+}
+
+func (m *MockLegalRepository) GetUserIDByEmail(ctx context.Context, email string) (int64, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockLegalRepository) SaveConsent(ctx context.Context, consent *entity.ConsentEntity) error {
+	args := m.Called(ctx, consent)
+	return args.Error(0)
+}
+
+func (m *MockLegalRepository) GetConsentHistory(ctx context.Context, userID int64) ([]entity.UserConsentEntity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.UserConsentEntity), args.Error(1)
+}
+
+// MockIdentityRepository mocks the identity verification repository
+type MockIdentityRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdentityRepository) Create(ctx context.Context, verification *entity.IdentityVerificationEntity) (*entity.IdentityVerificationEntity, error) {
+	args := m.Called(ctx, verification)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.IdentityVerificationEntity), args.Error(1)
+}
+
+func (m *MockIdentityRepository) GetByID(ctx context.Context, id int64) (*entity.IdentityVerificationEntity, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.IdentityVerificationEntity), args.Error(1)
+}
+
+func (m *MockIdentityRepository) GetLatest(ctx context.Context, userID int64) (*entity.IdentityVerificationEntity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.IdentityVerificationEntity), args.Error(1)
+}
+
+func (m *MockIdentityRepository) GetLatestApproved(ctx context.Context, userID int64) (*entity.IdentityVerificationEntity, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.IdentityVerificationEntity), args.Error(1)
+}
+
+func (m *MockIdentityRepository) List(ctx context.Context, status string, page, limit int) ([]entity.IdentityVerificationEntity, int64, error) {
+	args := m.Called(ctx, status, page, limit)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entity.IdentityVerificationEntity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockIdentityRepository) Review(ctx context.Context, id int64, update *entity.IdentityVerificationEntity) error {
+	args := m.Called(ctx, id, update)
+	return args.Error(0)
+}
+
+// MockSupportRepository mocks the support ticket repository
+type MockSupportRepository struct {
+	mock.Mock
+}
+
+func (m *MockSupportRepository) Create(ctx context.Context, ticket *entity.SupportTicketEntity) (*entity.SupportTicketEntity, error) {
+	args := m.Called(ctx, ticket)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SupportTicketEntity), args.Error(1)
+}
+
+func (m *MockSupportRepository) GetByID(ctx context.Context, id int64) (*entity.SupportTicketEntity, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SupportTicketEntity), args.Error(1)
+}
+
+func (m *MockSupportRepository) ListByUser(ctx context.Context, userID int64, page, limit int) ([]entity.SupportTicketEntity, int64, error) {
+	args := m.Called(ctx, userID, page, limit)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entity.SupportTicketEntity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockSupportRepository) List(ctx context.Context, filter entity.SupportTicketFilter, page, limit int) ([]entity.SupportTicketEntity, int64, error) {
+	args := m.Called(ctx, filter, page, limit)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entity.SupportTicketEntity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockSupportRepository) Assign(ctx context.Context, id, assigneeID int64) error {
+	args := m.Called(ctx, id, assigneeID)
+	return args.Error(0)
+}
+
+func (m *MockSupportRepository) UpdateStatus(ctx context.Context, id int64, status string) error {
+	args := m.Called(ctx, id, status)
+	return args.Error(0)
+}
+
+type MockSCIMRepository struct {
+	mock.Mock
+}
+
+func (m *MockSCIMRepository) ListUsers(ctx context.Context, filter *entity.SCIMFilter, offset, limit int) ([]entity.SCIMUserEntity, int64, error) {
+	args := m.Called(ctx, filter, offset, limit)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entity.SCIMUserEntity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockSCIMRepository) GetUser(ctx context.Context, id int64) (*entity.SCIMUserEntity, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SCIMUserEntity), args.Error(1)
+}
+
+func (m *MockSCIMRepository) CreateUser(ctx context.Context, user *entity.SCIMUserEntity, hashedPassword string) (*entity.SCIMUserEntity, error) {
+	args := m.Called(ctx, user, hashedPassword)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SCIMUserEntity), args.Error(1)
+}
+
+func (m *MockSCIMRepository) AdoptUser(ctx context.Context, id int64, externalID string) error {
+	args := m.Called(ctx, id, externalID)
+	return args.Error(0)
+}
+
+func (m *MockSCIMRepository) UpdateUser(ctx context.Context, user *entity.SCIMUserEntity) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockSCIMRepository) DeleteUser(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSCIMRepository) ListGroups(ctx context.Context, names []string, filter *entity.SCIMFilter, offset, limit int, withMembers bool) ([]entity.SCIMGroupEntity, int64, error) {
+	args := m.Called(ctx, names, filter, offset, limit, withMembers)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entity.SCIMGroupEntity), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockSCIMRepository) GetGroup(ctx context.Context, id int64, names []string) (*entity.SCIMGroupEntity, error) {
+	args := m.Called(ctx, id, names)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SCIMGroupEntity), args.Error(1)
+}
+
+type MockOIDCProvider struct {
+	mock.Mock
+}
+
+func (m *MockOIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	args := m.Called(ctx, state, nonce, codeChallenge)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockOIDCProvider) Exchange(ctx context.Context, code, codeVerifier string) (*entity.OIDCIdentityEntity, error) {
+	args := m.Called(ctx, code, codeVerifier)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.OIDCIdentityEntity), args.Error(1)
+}
+
+type MockSSOStateRepository struct {
+	mock.Mock
+}
+
+func (m *MockSSOStateRepository) SaveState(ctx context.Context, state *entity.SSOStateEntity) error {
+	args := m.Called(ctx, state)
+	return args.Error(0)
+}
+
+func (m *MockSSOStateRepository) TakeState(ctx context.Context, id string) (*entity.SSOStateEntity, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.SSOStateEntity), args.Error(1)
+}
+
+type MockSSORepository struct {
+	mock.Mock
+}
+
+func (m *MockSSORepository) GetUserIDBySubject(ctx context.Context, subject string) (int64, error) {
+	args := m.Called(ctx, subject)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSSORepository) LinkSubject(ctx context.Context, userID int64, subject string) error {
+	args := m.Called(ctx, userID, subject)
+	return args.Error(0)
+}
+
+func (m *MockSSORepository) CreateUser(ctx context.Context, user *entity.UserEntity, hashedPassword, roleName, subject string) (int64, error) {
+	args := m.Called(ctx, user, hashedPassword, roleName, subject)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockSSOService struct {
+	mock.Mock
+}
+
+func (m *MockSSOService) StartLogin(ctx context.Context, rememberMe bool) (string, *entity.SSOStateEntity, error) {
+	args := m.Called(ctx, rememberMe)
+	if args.Get(1) == nil {
+		return args.String(0), nil, args.Error(2)
+	}
+	return args.String(0), args.Get(1).(*entity.SSOStateEntity), args.Error(2)
+}
+
+func (m *MockSSOService) CompleteLogin(ctx context.Context, stateID, code string, client entity.ClientEntity) (*entity.UserEntity, string, error) {
+	args := m.Called(ctx, stateID, code, client)
+	if args.Get(0) == nil {
+		return nil, args.String(1), args.Error(2)
+	}
+	return args.Get(0).(*entity.UserEntity), args.String(1), args.Error(2)
+}
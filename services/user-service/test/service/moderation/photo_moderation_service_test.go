@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const photoURL = "https://test.supabase.co/storage/v1/object/public/profile-images/photo-uuid.jpg"
+
+type photoModerationFixture struct {
+	moderationRepo *mocks.MockPhotoModerationRepository
+	jobRepo        *mocks.MockJobRepository
+	moderator      *mocks.MockImageModerator
+	userRepo       *mocks.MockUserRepository
+	storage        *mocks.MockStorage
+	email          *mocks.MockEmailPublisher
+	service        port.PhotoModerationServiceInterface
+}
+
+func newPhotoModerationFixture() *photoModerationFixture {
+	f := &photoModerationFixture{
+		moderationRepo: new(mocks.MockPhotoModerationRepository),
+		jobRepo:        new(mocks.MockJobRepository),
+		moderator:      new(mocks.MockImageModerator),
+		userRepo:       new(mocks.MockUserRepository),
+		storage:        new(mocks.MockStorage),
+		email:          new(mocks.MockEmailPublisher),
+	}
+	f.service = service.NewPhotoModerationService(f.moderationRepo, service.NewJobService(f.jobRepo), f.moderator, f.userRepo, f.storage, f.email, &config.Config{})
+	return f
+}
+
+func pendingModeration() *entity.PhotoModerationEntity {
+	return &entity.PhotoModerationEntity{ID: 3, UserID: 7, ImageURL: photoURL, Status: entity.PhotoModerationPending}
+}
+
+func withStatus(status string) interface{} {
+	return mock.MatchedBy(func(update *entity.PhotoModerationEntity) bool { return update.Status == status })
+}
+
+func TestUploadProfileImage_SubmitsPhotoForModeration(t *testing.T) {
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	storage := new(mocks.MockStorage)
+	submitter := new(mocks.MockPhotoModerationSubmitter)
+	authService := service.NewAuthService(service.AuthServiceDeps{
+		UserRepo:        userRepo,
+		Storage:         storage,
+		PhotoModeration: submitter,
+	})
+
+	userRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7}, nil)
+	storage.On("UploadFile", ctx, "", "", mock.Anything, "image/png").Return(photoURL, nil)
+	userRepo.On("UpdateUserPhoto", ctx, int64(7), photoURL).Return(nil)
+	submitter.On("Submit", ctx, int64(7), photoURL).Return(errors.New("failed to submit photo for moderation"))
+
+	url, err := authService.UploadProfileImage(ctx, 7, strings.NewReader("png"), "image/png", "me.png")
+
+	// A failed submission never fails the upload itself
+	assert.NoError(t, err)
+	assert.Equal(t, photoURL, url)
+	submitter.AssertExpectations(t)
+}
+
+func TestSubmit_QueuesModerationJob(t *testing.T) {
+	ctx := context.Background()
+	f := newPhotoModerationFixture()
+
+	f.moderationRepo.On("Create", ctx, mock.Anything).Return(pendingModeration(), nil)
+	f.jobRepo.On("Enqueue", ctx, mock.MatchedBy(func(job *entity.JobEntity) bool {
+		return job.Type == entity.JobTypeModeratePhoto && strings.Contains(string(job.Payload), `"moderation_id":3`)
+	})).Return(&entity.JobEntity{ID: 1}, nil)
+
+	assert.NoError(t, f.service.Submit(ctx, 7, photoURL))
+	f.jobRepo.AssertExpectations(t)
+}
+
+func TestModerate_RemovesViolatingPhotoAndNotifiesUser(t *testing.T) {
+	ctx := context.Background()
+	f := newPhotoModerationFixture()
+
+	f.moderationRepo.On("GetByID", ctx, int64(3)).Return(pendingModeration(), nil)
+	f.userRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7, Email: "budi@example.com", Name: "Budi", Photo: photoURL}, nil)
+	f.moderator.On("Moderate", ctx, photoURL).Return(&entity.ModerationResultEntity{Score: 0.97, Labels: []string{"nudity"}}, nil)
+	f.moderationRepo.On("UpdateStatus", ctx, int64(3), []string{entity.PhotoModerationPending}, mock.MatchedBy(func(update *entity.PhotoModerationEntity) bool {
+		return update.Status == entity.PhotoModerationRemoved && *update.Score == 0.97 && update.Labels[0] == "nudity"
+	})).Return(nil)
+	f.userRepo.On("UpdateUserPhoto", ctx, int64(7), "").Return(nil)
+	f.storage.On("DeleteFile", ctx, "", "photo-uuid.jpg").Return(nil)
+	f.email.On("SendPhotoRemovedEmail", ctx, "budi@example.com", "Budi", mock.AnythingOfType("string")).Return(nil)
+
+	assert.NoError(t, f.service.Moderate(ctx, 3))
+	f.moderationRepo.AssertExpectations(t)
+	f.userRepo.AssertExpectations(t)
+	f.storage.AssertExpectations(t)
+	f.email.AssertExpectations(t)
+}
+
+func TestModerate_FlagsBorderlinePhotoForReview(t *testing.T) {
+	ctx := context.Background()
+	f := newPhotoModerationFixture()
+
+	f.moderationRepo.On("GetByID", ctx, int64(3)).Return(pendingModeration(), nil)
+	f.userRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7, Photo: photoURL}, nil)
+	f.moderator.On("Moderate", ctx, photoURL).Return(&entity.ModerationResultEntity{Score: 0.7}, nil)
+	f.moderationRepo.On("UpdateStatus", ctx, int64(3), mock.Anything, withStatus(entity.PhotoModerationFlagged)).Return(nil)
+
+	assert.NoError(t, f.service.Moderate(ctx, 3))
+	f.userRepo.AssertNotCalled(t, "UpdateUserPhoto", mock.Anything, mock.Anything, mock.Anything)
+	f.email.AssertNotCalled(t, "SendPhotoRemovedEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestModerate_SkipsReplacedPhoto(t *testing.T) {
+	ctx := context.Background()
+	f := newPhotoModerationFixture()
+
+	f.moderationRepo.On("GetByID", ctx, int64(3)).Return(pendingModeration(), nil)
+	f.userRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7, Photo: "https://cdn/newer.jpg"}, nil)
+	f.moderationRepo.On("UpdateStatus", ctx, int64(3), mock.Anything, withStatus(entity.PhotoModerationSuperseded)).Return(nil)
+
+	assert.NoError(t, f.service.Moderate(ctx, 3))
+	f.moderator.AssertNotCalled(t, "Moderate", mock.Anything, mock.Anything)
+}
+
+func TestModerate_ProviderFailureIsRetried(t *testing.T) {
+	ctx := context.Background()
+	f := newPhotoModerationFixture()
+
+	f.moderationRepo.On("GetByID", ctx, int64(3)).Return(pendingModeration(), nil)
+	f.userRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7, Photo: photoURL}, nil)
+	f.moderator.On("Moderate", ctx, photoURL).Return(nil, errors.New("moderation service returned status 503"))
+
+	assert.Error(t, f.service.Moderate(ctx, 3))
+	f.moderationRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestReview_ApproveAndRemove(t *testing.T) {
+	ctx := context.Background()
+	f := newPhotoModerationFixture()
+
+	_, err := f.service.Remove(ctx, 3, 1, "  ")
+	assert.EqualError(t, err, "removal reason is required")
+
+	flagged := pendingModeration()
+	flagged.Status = entity.PhotoModerationFlagged
+	f.moderationRepo.On("GetByID", ctx, int64(3)).Return(flagged, nil)
+	f.moderationRepo.On("UpdateStatus", ctx, int64(3), mock.Anything, withStatus(entity.PhotoModerationApproved)).Return(errors.New("photo moderation already decided"))
+
+	_, err = f.service.Approve(ctx, 3, 1, "")
+	assert.EqualError(t, err, "photo moderation already decided")
+
+	f.moderationRepo.On("UpdateStatus", ctx, int64(3), mock.Anything, mock.MatchedBy(func(update *entity.PhotoModerationEntity) bool {
+		return update.Status == entity.PhotoModerationRemoved && update.ReviewedBy == 1 && update.ReviewNote == "offensive text"
+	})).Return(nil)
+	f.userRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7, Email: "budi@example.com", Photo: "https://cdn/newer.jpg"}, nil)
+	f.storage.On("DeleteFile", ctx, "", "photo-uuid.jpg").Return(nil)
+	f.email.On("SendPhotoRemovedEmail", ctx, "budi@example.com", "", "offensive text").Return(nil)
+
+	_, err = f.service.Remove(ctx, 3, 1, "offensive text")
+
+	assert.NoError(t, err)
+	// The user already uses a different photo, which must be left alone
+	f.userRepo.AssertNotCalled(t, "UpdateUserPhoto", mock.Anything, mock.Anything, mock.Anything)
+	f.email.AssertExpectations(t)
+}
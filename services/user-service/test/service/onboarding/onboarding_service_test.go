@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func completeProfile() *entity.UserEntity {
+	return &entity.UserEntity{ID: 7, Name: "Sari", Email: "sari@example.com", Photo: "https://cdn/p.jpg", Phone: "0812", Address: "Jl. Melati 1", Lat: -6.2, Lng: 106.8}
+}
+
+func TestBuildOnboarding_Percentage(t *testing.T) {
+	onboarding := entity.BuildOnboarding(&entity.UserEntity{Photo: "p.jpg", Phone: "0812"}, &entity.OnboardingStateEntity{})
+
+	assert.Equal(t, 50, onboarding.Percentage)
+	assert.False(t, onboarding.Completed)
+	assert.Len(t, onboarding.Items, 4)
+
+	// An address without coordinates cannot be delivered to yet
+	onboarding = entity.BuildOnboarding(&entity.UserEntity{Address: "Jl. Melati 1"}, &entity.OnboardingStateEntity{})
+	assert.Equal(t, 0, onboarding.Percentage)
+}
+
+func TestGetOnboarding_Incomplete(t *testing.T) {
+	onboardingRepo := new(mocks.MockOnboardingRepository)
+	userRepo := new(mocks.MockUserRepository)
+	jobRepo := new(mocks.MockJobRepository)
+	svc := service.NewOnboardingService(onboardingRepo, userRepo, service.NewJobService(jobRepo))
+
+	ctx := context.Background()
+	dismissedAt := time.Now()
+	userRepo.On("GetUserByID", ctx, int64(7)).Return(completeProfile(), nil)
+	onboardingRepo.On("GetState", ctx, int64(7)).Return(&entity.OnboardingStateEntity{UserID: 7, DismissedAt: &dismissedAt}, nil)
+
+	onboarding, err := svc.GetOnboarding(ctx, 7)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 75, onboarding.Percentage)
+	assert.True(t, onboarding.Dismissed)
+	assert.False(t, onboarding.Completed)
+	onboardingRepo.AssertNotCalled(t, "MarkCompleted", mock.Anything, mock.Anything)
+	jobRepo.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+}
+
+func TestRecordFirstOrder_CompletesOnboardingAndQueuesEvent(t *testing.T) {
+	onboardingRepo := new(mocks.MockOnboardingRepository)
+	userRepo := new(mocks.MockUserRepository)
+	jobRepo := new(mocks.MockJobRepository)
+	svc := service.NewOnboardingService(onboardingRepo, userRepo, service.NewJobService(jobRepo))
+
+	ctx := context.Background()
+	orderedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	userRepo.On("GetUserByID", ctx, int64(7)).Return(completeProfile(), nil)
+	onboardingRepo.On("MarkFirstOrder", ctx, int64(7), orderedAt).Return(nil)
+	onboardingRepo.On("GetState", ctx, int64(7)).Return(&entity.OnboardingStateEntity{UserID: 7, FirstOrderAt: &orderedAt}, nil)
+	onboardingRepo.On("MarkCompleted", ctx, int64(7)).Return(true, nil)
+	jobRepo.On("Enqueue", ctx, mock.MatchedBy(func(job *entity.JobEntity) bool {
+		var event entity.EventEntity
+		return job.Type == entity.JobTypePublishEvent &&
+			job.UniqueKey == "user.onboarding_completed:7" &&
+			job.Decode(&event) == nil && event.Type == entity.EventUserOnboardingCompleted
+	})).Return(&entity.JobEntity{ID: 1}, nil)
+
+	err := svc.RecordFirstOrder(ctx, 7, orderedAt)
+
+	assert.NoError(t, err)
+	onboardingRepo.AssertExpectations(t)
+	jobRepo.AssertExpectations(t)
+}
+
+func TestGetOnboarding_CompletedOnlyEmitsOnce(t *testing.T) {
+	onboardingRepo := new(mocks.MockOnboardingRepository)
+	userRepo := new(mocks.MockUserRepository)
+	jobRepo := new(mocks.MockJobRepository)
+	svc := service.NewOnboardingService(onboardingRepo, userRepo, service.NewJobService(jobRepo))
+
+	ctx := context.Background()
+	orderedAt := time.Now()
+	userRepo.On("GetUserByID", ctx, int64(7)).Return(completeProfile(), nil)
+	onboardingRepo.On("GetState", ctx, int64(7)).Return(&entity.OnboardingStateEntity{UserID: 7, FirstOrderAt: &orderedAt}, nil)
+	// Another request already marked it complete
+	onboardingRepo.On("MarkCompleted", ctx, int64(7)).Return(false, nil)
+
+	onboarding, err := svc.GetOnboarding(ctx, 7)
+
+	assert.NoError(t, err)
+	assert.True(t, onboarding.Completed)
+	assert.NotNil(t, onboarding.CompletedAt)
+	jobRepo.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+}
+
+func TestRecordFirstOrder_UserNotFound(t *testing.T) {
+	onboardingRepo := new(mocks.MockOnboardingRepository)
+	userRepo := new(mocks.MockUserRepository)
+	svc := service.NewOnboardingService(onboardingRepo, userRepo, nil)
+
+	ctx := context.Background()
+	userRepo.On("GetUserByID", ctx, int64(99)).Return(nil, gorm.ErrRecordNotFound)
+
+	err := svc.RecordFirstOrder(ctx, 99, time.Now())
+
+	assert.EqualError(t, err, "user not found")
+	onboardingRepo.AssertNotCalled(t, "MarkFirstOrder", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type operatorFixture struct {
+	userRepo              *mocks.MockUserRepository
+	sessionRepo           *mocks.MockSessionRepository
+	verificationTokenRepo *mocks.MockVerificationTokenRepository
+	blacklistRepo         *mocks.MockBlacklistTokenRepository
+	emailPublisher        *mocks.MockEmailPublisher
+	auditRepo             *mocks.MockAuditLogRepository
+	service               port.OperatorServiceInterface
+}
+
+func newOperatorFixture() *operatorFixture {
+	f := &operatorFixture{
+		userRepo:              new(mocks.MockUserRepository),
+		sessionRepo:           new(mocks.MockSessionRepository),
+		verificationTokenRepo: new(mocks.MockVerificationTokenRepository),
+		blacklistRepo:         new(mocks.MockBlacklistTokenRepository),
+		emailPublisher:        new(mocks.MockEmailPublisher),
+		auditRepo:             new(mocks.MockAuditLogRepository),
+	}
+	cfg := &config.Config{}
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              f.userRepo,
+			SessionRepo:           f.sessionRepo,
+			VerificationTokenRepo: f.verificationTokenRepo,
+			EmailPublisher:        f.emailPublisher,
+			BlacklistTokenRepo:    f.blacklistRepo,
+		},
+		Config: cfg,
+	})
+	f.service = service.NewOperatorService(f.userRepo, f.sessionRepo, f.verificationTokenRepo, f.blacklistRepo, userService, service.NewAuditLogService(f.auditRepo), cfg, "budi")
+	return f
+}
+
+func auditedBy(event, operator string) interface{} {
+	return mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == event && auditLog.Metadata["operator"] == operator
+	})
+}
+
+func TestPromoteUser_ChangesRoleAndSignsOut(t *testing.T) {
+	ctx := context.Background()
+	f := newOperatorFixture()
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "siti@example.com").Return(&entity.UserEntity{ID: 7, Email: "siti@example.com", RoleName: "Customer", RoleID: 2}, nil)
+	f.userRepo.On("GetRoleByName", ctx, "Super Admin").Return(&entity.RoleEntity{ID: 1, Name: "Super Admin"}, nil)
+	f.userRepo.On("UpdateUserRole", ctx, int64(7), int64(1)).Return(nil)
+	f.sessionRepo.On("DeleteAllUserTokens", ctx, int64(7)).Return(nil)
+	f.auditRepo.On("Create", ctx, auditedBy(entity.AuditEventRoleChanged, "budi")).Return(nil)
+
+	user, err := f.service.PromoteUser(ctx, " Siti@Example.com ", "Super Admin")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Super Admin", user.RoleName)
+	f.sessionRepo.AssertExpectations(t)
+	f.auditRepo.AssertExpectations(t)
+}
+
+func TestPromoteUser_RejectsUnknownUserAndRole(t *testing.T) {
+	ctx := context.Background()
+	f := newOperatorFixture()
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "nobody@example.com").Return(nil, gorm.ErrRecordNotFound)
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "siti@example.com").Return(&entity.UserEntity{ID: 7, RoleName: "Customer"}, nil)
+	f.userRepo.On("GetRoleByName", ctx, "Owner").Return(nil, gorm.ErrRecordNotFound)
+	f.userRepo.On("GetRoleByName", ctx, "Customer").Return(&entity.RoleEntity{ID: 2, Name: "Customer"}, nil)
+
+	_, err := f.service.PromoteUser(ctx, "nobody@example.com", "Super Admin")
+	assert.EqualError(t, err, "user not found")
+
+	_, err = f.service.PromoteUser(ctx, "siti@example.com", "Owner")
+	assert.EqualError(t, err, "role not found")
+
+	_, err = f.service.PromoteUser(ctx, "siti@example.com", "Customer")
+	assert.EqualError(t, err, "user already has this role")
+
+	f.userRepo.AssertNotCalled(t, "UpdateUserRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVerifyUser_VoidsVerificationLinks(t *testing.T) {
+	ctx := context.Background()
+	f := newOperatorFixture()
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "siti@example.com").Return(&entity.UserEntity{ID: 7, Email: "siti@example.com"}, nil)
+	f.userRepo.On("UpdateUserVerificationStatus", ctx, int64(7), true).Return(nil)
+	f.verificationTokenRepo.On("DeleteUserTokens", ctx, int64(7), entity.TokenTypeEmailVerification).Return(int64(1), nil)
+	f.auditRepo.On("Create", ctx, auditedBy(entity.AuditEventVerifiedManually, "budi")).Return(nil)
+
+	user, err := f.service.VerifyUser(ctx, "siti@example.com")
+
+	assert.NoError(t, err)
+	assert.True(t, user.IsVerified)
+	f.verificationTokenRepo.AssertExpectations(t)
+	f.auditRepo.AssertExpectations(t)
+}
+
+func TestVerifyUser_AlreadyVerified(t *testing.T) {
+	ctx := context.Background()
+	f := newOperatorFixture()
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "siti@example.com").Return(&entity.UserEntity{ID: 7, IsVerified: true}, nil)
+
+	_, err := f.service.VerifyUser(ctx, "siti@example.com")
+
+	assert.EqualError(t, err, "user is already verified")
+	f.userRepo.AssertNotCalled(t, "UpdateUserVerificationStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRevokeSessions_ReportsCount(t *testing.T) {
+	ctx := context.Background()
+	f := newOperatorFixture()
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "siti@example.com").Return(&entity.UserEntity{ID: 7}, nil)
+	f.sessionRepo.On("GetUserSessions", ctx, int64(7)).Return([]entity.SessionInfo{{}, {}}, nil)
+	f.sessionRepo.On("DeleteAllUserTokens", ctx, int64(7)).Return(nil)
+	f.auditRepo.On("Create", ctx, auditedBy(entity.AuditEventSessionsRevoked, "budi")).Return(nil)
+
+	revoked, err := f.service.RevokeSessions(ctx, "siti@example.com")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, revoked)
+	f.sessionRepo.AssertExpectations(t)
+}
+
+func TestResendVerification_ExplainsWhyNothingWasSent(t *testing.T) {
+	ctx := context.Background()
+	f := newOperatorFixture()
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "nobody@example.com").Return(nil, gorm.ErrRecordNotFound)
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "siti@example.com").Return(&entity.UserEntity{ID: 7, IsVerified: true}, nil)
+
+	assert.EqualError(t, f.service.ResendVerification(ctx, "nobody@example.com"), "user not found")
+	assert.EqualError(t, f.service.ResendVerification(ctx, "siti@example.com"), "user is already verified")
+	f.emailPublisher.AssertNotCalled(t, "SendVerificationEmail", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResendVerification_SendsNewLink(t *testing.T) {
+	ctx := context.Background()
+	f := newOperatorFixture()
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "siti@example.com").Return(&entity.UserEntity{ID: 7, Email: "siti@example.com"}, nil)
+	f.verificationTokenRepo.On("DeleteUserTokens", ctx, int64(7), entity.TokenTypeEmailVerification).Return(int64(1), nil)
+	f.verificationTokenRepo.On("CreateVerificationToken", ctx, mock.Anything).Return(nil)
+	f.emailPublisher.On("SendVerificationEmail", ctx, "siti@example.com", mock.Anything).Return(nil)
+
+	assert.NoError(t, f.service.ResendVerification(ctx, "siti@example.com"))
+	f.emailPublisher.AssertExpectations(t)
+}
+
+func TestPurgeTokens_CountsBothStores(t *testing.T) {
+	ctx := context.Background()
+	f := newOperatorFixture()
+
+	f.verificationTokenRepo.On("DeleteExpiredVerificationTokens", ctx).Return(int64(12), nil)
+	f.blacklistRepo.On("DeleteExpired", ctx).Return(int64(3), nil)
+
+	purged, err := f.service.PurgeTokens(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &entity.TokenPurgeEntity{ExpiredVerifications: 12, BlacklistEntries: 3}, purged)
+}
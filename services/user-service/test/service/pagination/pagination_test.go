@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/adapter/handler"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func getRoles(t *testing.T, target string, mockRoleService *mocks.MockRoleService) (*httptest.ResponseRecorder, map[string]interface{}) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+
+	err := handler.NewRoleHandler(mockRoleService).GetAllRoles(e.NewContext(req, rec))
+	assert.NoError(t, err)
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return rec, body
+}
+
+func TestListResponse_MetaAndLinkHeader(t *testing.T) {
+	mockRoleService := &mocks.MockRoleService{}
+	mockRoleService.On("GetAllRoles", mock.Anything, "a", 2, 1).Return(
+		[]entity.RoleEntity{{ID: 2, Name: "Customer"}},
+		&entity.PaginationEntity{Page: 2, TotalCount: 3, PerPage: 1, TotalPage: 3},
+		nil,
+	)
+
+	rec, body := getRoles(t, "/api/v1/admin/roles?search=a&page=2&limit=1", mockRoleService)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	meta := body["meta"].(map[string]interface{})
+	assert.Equal(t, float64(2), meta["page"])
+	assert.Equal(t, float64(1), meta["per_page"])
+	assert.Equal(t, float64(3), meta["total"])
+	assert.Equal(t, float64(3), meta["total_pages"])
+	assert.NotEmpty(t, meta["next_cursor"])
+	assert.NotEmpty(t, meta["prev_cursor"])
+
+	assert.Equal(t,
+		`</api/v1/admin/roles?limit=1&page=1&search=a>; rel="first", `+
+			`</api/v1/admin/roles?limit=1&page=1&search=a>; rel="prev", `+
+			`</api/v1/admin/roles?limit=1&page=3&search=a>; rel="next", `+
+			`</api/v1/admin/roles?limit=1&page=3&search=a>; rel="last"`,
+		rec.Header().Get("Link"))
+}
+
+func TestListResponse_CursorSelectsPage(t *testing.T) {
+	first := &mocks.MockRoleService{}
+	first.On("GetAllRoles", mock.Anything, "", 0, 0).Return(
+		[]entity.RoleEntity{{ID: 1, Name: "Super Admin"}},
+		&entity.PaginationEntity{Page: 1, TotalCount: 12, PerPage: 10, TotalPage: 2},
+		nil,
+	)
+	_, body := getRoles(t, "/api/v1/admin/roles", first)
+	meta := body["meta"].(map[string]interface{})
+	assert.Nil(t, meta["prev_cursor"])
+
+	second := &mocks.MockRoleService{}
+	second.On("GetAllRoles", mock.Anything, "", 2, 10).Return(
+		[]entity.RoleEntity{{ID: 11, Name: "Courier"}},
+		&entity.PaginationEntity{Page: 2, TotalCount: 12, PerPage: 10, TotalPage: 2},
+		nil,
+	)
+	rec, body := getRoles(t, "/api/v1/admin/roles?page=5&cursor="+meta["next_cursor"].(string), second)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Nil(t, body["meta"].(map[string]interface{})["next_cursor"])
+	assert.NotContains(t, rec.Header().Get("Link"), `rel="next"`)
+	second.AssertExpectations(t)
+}
+
+func TestListResponse_InvalidCursorFallsBackToPage(t *testing.T) {
+	mockRoleService := &mocks.MockRoleService{}
+	mockRoleService.On("GetAllRoles", mock.Anything, "", 3, 0).Return(
+		[]entity.RoleEntity{},
+		&entity.PaginationEntity{Page: 3, TotalCount: 0, PerPage: 10, TotalPage: 0},
+		nil,
+	)
+
+	rec, _ := getRoles(t, "/api/v1/admin/roles?page=3&cursor=not-a-cursor", mockRoleService)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	// Past the end the prev and last links still point somewhere that exists
+	assert.Contains(t, rec.Header().Get("Link"), `</api/v1/admin/roles?limit=10&page=1>; rel="prev"`)
+	mockRoleService.AssertExpectations(t)
+}
+
+func TestGetSessions_NewestFirstWithoutExpired(t *testing.T) {
+	ctx := context.Background()
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{SessionRepo: mockSessionRepo},
+		Config:          &config.Config{},
+	})
+
+	now := time.Now()
+	mockSessionRepo.On("GetUserSessions", ctx, int64(1)).Return([]entity.SessionInfo{
+		{SessionID: "old", CreatedAt: now.Add(-48 * time.Hour), ExpiresAt: now.Add(time.Hour)},
+		{SessionID: "expired", CreatedAt: now.Add(-72 * time.Hour), ExpiresAt: now.Add(-time.Hour)},
+		{SessionID: "new", CreatedAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)},
+		{SessionID: "middle", CreatedAt: now.Add(-24 * time.Hour), ExpiresAt: now.Add(time.Hour)},
+	}, nil)
+
+	sessions, pagination, err := userService.GetSessions(ctx, 1, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"new", "middle"}, []string{sessions[0].SessionID, sessions[1].SessionID})
+	assert.Equal(t, &entity.PaginationEntity{Page: 1, TotalCount: 3, PerPage: 2, TotalPage: 2}, pagination)
+
+	sessions, _, err = userService.GetSessions(ctx, 1, 2, 2)
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, "old", sessions[0].SessionID)
+
+	sessions, _, err = userService.GetSessions(ctx, 1, 9, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, sessions)
+}
+
+func TestGetSessions_RepositoryError(t *testing.T) {
+	ctx := context.Background()
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{SessionRepo: mockSessionRepo},
+		Config:          &config.Config{},
+	})
+
+	mockSessionRepo.On("GetUserSessions", ctx, int64(1)).Return([]entity.SessionInfo(nil), errors.New("redis down"))
+
+	_, _, err := userService.GetSessions(ctx, 1, 1, 10)
+	assert.EqualError(t, err, "failed to retrieve sessions")
+}
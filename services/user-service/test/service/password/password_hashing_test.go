@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Small argon2 settings keep the tests fast; production defaults are 64 MiB and 3 passes
+var fastArgon2 = config.PasswordHashing{Algorithm: "argon2id", Argon2Memory: 19 * 1024, Argon2Iterations: 1, Argon2Parallelism: 1}
+
+func TestPasswordHasher_Argon2idRoundTrip(t *testing.T) {
+	hasher := service.PasswordHasherFromConfig(&config.Config{PasswordHashing: fastArgon2})
+
+	hash, err := hasher.HashPassword("correct horse")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=19456,t=1,p=1$"))
+	assert.True(t, hasher.CheckPasswordHash("correct horse", hash))
+	assert.False(t, hasher.CheckPasswordHash("wrong horse", hash))
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	other, err := hasher.HashPassword("correct horse")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other, "each hash gets its own salt")
+}
+
+func TestPasswordHasher_VerifiesLegacyHashesAfterSwitching(t *testing.T) {
+	bcryptHash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+
+	argon := service.PasswordHasherFromConfig(&config.Config{PasswordHashing: fastArgon2})
+	assert.True(t, argon.CheckPasswordHash("password123", bcryptHash))
+	assert.True(t, argon.NeedsRehash(bcryptHash))
+
+	argonHash, err := argon.HashPassword("password123")
+	require.NoError(t, err)
+
+	// Switching back verifies the argon2id hashes too
+	bcrypt := service.PasswordHasherFromConfig(&config.Config{})
+	assert.True(t, bcrypt.CheckPasswordHash("password123", argonHash))
+	assert.True(t, bcrypt.NeedsRehash(argonHash))
+	assert.False(t, bcrypt.NeedsRehash(bcryptHash))
+}
+
+func TestPasswordHasher_NeedsRehashWhenCostsChange(t *testing.T) {
+	argon := service.PasswordHasherFromConfig(&config.Config{PasswordHashing: fastArgon2})
+	hash, err := argon.HashPassword("password123")
+	require.NoError(t, err)
+
+	stronger := fastArgon2
+	stronger.Argon2Iterations = 2
+	assert.True(t, service.PasswordHasherFromConfig(&config.Config{PasswordHashing: stronger}).NeedsRehash(hash))
+
+	bcryptHash, err := utils.HashPassword("password123")
+	require.NoError(t, err)
+	costlier := service.PasswordHasherFromConfig(&config.Config{PasswordHashing: config.PasswordHashing{BcryptCost: 12}})
+	assert.True(t, costlier.NeedsRehash(bcryptHash))
+}
+
+func TestPasswordHasher_RejectsMalformedArgon2Hash(t *testing.T) {
+	hasher := service.PasswordHasherFromConfig(&config.Config{PasswordHashing: fastArgon2})
+
+	assert.False(t, hasher.CheckPasswordHash("password123", "$argon2id$v=19$m=abc$salt$key"))
+	assert.False(t, hasher.CheckPasswordHash("password123", "$argon2id$v=18$m=19456,t=1,p=1$c2FsdA$a2V5"))
+	assert.True(t, hasher.NeedsRehash("$argon2id$broken"))
+}
+
+func TestPasswordHashing_Validate(t *testing.T) {
+	assert.NoError(t, config.PasswordHashing{}.Validate())
+	assert.NoError(t, fastArgon2.Validate())
+	assert.NoError(t, config.PasswordHashing{Algorithm: "Argon2id", BcryptCost: 12}.Validate())
+
+	assert.EqualError(t, config.PasswordHashing{Algorithm: "scrypt"}.Validate(), `PASSWORD_HASH_ALGORITHM: unknown algorithm "scrypt"`)
+	assert.ErrorContains(t, config.PasswordHashing{BcryptCost: 4}.Validate(), "PASSWORD_BCRYPT_COST")
+	assert.ErrorContains(t, config.PasswordHashing{Argon2Memory: 1024}.Validate(), "PASSWORD_ARGON2_MEMORY")
+	assert.ErrorContains(t, config.PasswordHashing{Argon2Iterations: 50}.Validate(), "PASSWORD_ARGON2_ITERATIONS")
+}
+
+type signInFixture struct {
+	userRepo    *mocks.MockUserRepository
+	sessionRepo *mocks.MockSessionRepository
+	jwtUtil     *mocks.MockJWTUtil
+	service     interface {
+		SignIn(ctx context.Context, req entity.UserEntity, client entity.ClientEntity, rememberMe bool) (*entity.UserEntity, string, error)
+	}
+}
+
+func newSignInFixture(hashing config.PasswordHashing, storedHash string) *signInFixture {
+	f := &signInFixture{
+		userRepo:    new(mocks.MockUserRepository),
+		sessionRepo: new(mocks.MockSessionRepository),
+		jwtUtil:     new(mocks.MockJWTUtil),
+	}
+	f.service = service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: f.userRepo, SessionRepo: f.sessionRepo, JWTUtil: f.jwtUtil},
+		Config:          &config.Config{PasswordHashing: hashing},
+	})
+
+	f.userRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(&entity.UserEntity{ID: 1, Email: "user@example.com", Password: storedHash, RoleName: "Customer"}, nil)
+	f.jwtUtil.On("GenerateJWTWithSession", int64(1), "user@example.com", "Customer", mock.AnythingOfType("string"), false).Return("jwt-token", nil)
+	f.sessionRepo.On("StoreToken", mock.Anything, int64(1), mock.AnythingOfType("string"), "jwt-token", false).Return(nil)
+	f.sessionRepo.On("SetSessionClient", mock.Anything, int64(1), mock.AnythingOfType("string"), entity.ClientEntity{}, (*entity.LoginLocationEntity)(nil)).Return(nil)
+	return f
+}
+
+func (f *signInFixture) signIn(password string) error {
+	_, _, err := f.service.SignIn(context.Background(), entity.UserEntity{Email: "user@example.com", Password: password}, entity.ClientEntity{}, false)
+	return err
+}
+
+func TestSignIn_UpgradesLegacyBcryptHash(t *testing.T) {
+	legacyHash, _ := utils.HashPassword("password123")
+	f := newSignInFixture(fastArgon2, legacyHash)
+
+	var upgraded string
+	f.userRepo.On("UpdateUserPassword", mock.Anything, int64(1), mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { upgraded = args.String(2) }).Return(nil)
+
+	require.NoError(t, f.signIn("password123"))
+
+	assert.True(t, strings.HasPrefix(upgraded, "$argon2id$"))
+	assert.True(t, utils.CheckPasswordHash("password123", upgraded))
+}
+
+func TestSignIn_CurrentHashIsLeftAlone(t *testing.T) {
+	currentHash, _ := service.PasswordHasherFromConfig(&config.Config{PasswordHashing: fastArgon2}).HashPassword("password123")
+	f := newSignInFixture(fastArgon2, currentHash)
+
+	require.NoError(t, f.signIn("password123"))
+
+	f.userRepo.AssertNotCalled(t, "UpdateUserPassword", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSignIn_FailedUpgradeStillSignsIn(t *testing.T) {
+	legacyHash, _ := utils.HashPassword("password123")
+	f := newSignInFixture(fastArgon2, legacyHash)
+	f.userRepo.On("UpdateUserPassword", mock.Anything, int64(1), mock.AnythingOfType("string")).Return(errors.New("connection reset"))
+
+	assert.NoError(t, f.signIn("password123"))
+}
+
+func TestSignIn_WrongPasswordIsNotRehashed(t *testing.T) {
+	legacyHash, _ := utils.HashPassword("password123")
+	f := newSignInFixture(fastArgon2, legacyHash)
+
+	assert.EqualError(t, f.signIn("not-the-password"), "incorrect password")
+	f.userRepo.AssertNotCalled(t, "UpdateUserPassword", mock.Anything, mock.Anything, mock.Anything)
+}
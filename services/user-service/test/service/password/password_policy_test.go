@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// staffPolicy expires Super Admin passwords after 90 days and blocks the last 5
+var staffPolicy = config.PasswordPolicy{Roles: []string{"Super Admin"}, MaxAgeDays: 90, HistorySize: 5, WarnDays: 7}
+
+func policyConfig() *config.Config {
+	return &config.Config{
+		PasswordHashing: config.PasswordHashing{BcryptCost: 4},
+		PasswordPolicy:  staffPolicy,
+	}
+}
+
+// staffRoles has Owner inherit from Super Admin, which the policy names
+func staffRoles() *mocks.MockRoleRepository {
+	superAdmin := int64(2)
+	roleRepo := new(mocks.MockRoleRepository)
+	roleRepo.On("GetAllRoles", mock.Anything, "").Return([]entity.RoleEntity{
+		{ID: 2, Name: "Super Admin"},
+		{ID: 3, Name: "Owner", ParentID: &superAdmin},
+		{ID: 4, Name: "Customer"},
+	}, nil)
+	return roleRepo
+}
+
+func hashFor(t *testing.T, password string) string {
+	hash, err := service.PasswordHasherFromConfig(policyConfig()).HashPassword(password)
+	require.NoError(t, err)
+	return hash
+}
+
+type policySignInFixture struct {
+	userRepo    *mocks.MockUserRepository
+	sessionRepo *mocks.MockSessionRepository
+	jwtUtil     *mocks.MockJWTUtil
+	service     port.UserServiceInterface
+}
+
+func newPolicySignInFixture(t *testing.T, role string, changedAt time.Time) *policySignInFixture {
+	f := &policySignInFixture{
+		userRepo:    new(mocks.MockUserRepository),
+		sessionRepo: new(mocks.MockSessionRepository),
+		jwtUtil:     new(mocks.MockJWTUtil),
+	}
+	f.service = service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: f.userRepo, SessionRepo: f.sessionRepo, JWTUtil: f.jwtUtil, Roles: service.NewRoleService(staffRoles())},
+		Config:          policyConfig(),
+	})
+
+	f.userRepo.On("GetUserByEmail", mock.Anything, "staff@example.com").Return(&entity.UserEntity{
+		ID: 1, Email: "staff@example.com", Password: hashFor(t, "password123"), RoleName: role, PasswordChangedAt: changedAt,
+	}, nil)
+	f.jwtUtil.On("GenerateJWTWithSession", int64(1), "staff@example.com", role, mock.AnythingOfType("string"), false).Return("jwt-token", nil)
+	f.sessionRepo.On("StoreToken", mock.Anything, int64(1), mock.AnythingOfType("string"), "jwt-token", false).Return(nil)
+	f.sessionRepo.On("SetSessionClient", mock.Anything, int64(1), mock.AnythingOfType("string"), entity.ClientEntity{}, (*entity.LoginLocationEntity)(nil)).Return(nil)
+	return f
+}
+
+func (f *policySignInFixture) signIn() error {
+	_, _, err := f.service.SignIn(context.Background(), entity.UserEntity{Email: "staff@example.com", Password: "password123"}, entity.ClientEntity{}, false)
+	return err
+}
+
+func TestSignIn_RefusesExpiredStaffPassword(t *testing.T) {
+	f := newPolicySignInFixture(t, "Super Admin", time.Now().Add(-91*24*time.Hour))
+
+	err := f.signIn()
+
+	assert.EqualError(t, err, "password expired")
+	f.sessionRepo.AssertNotCalled(t, "StoreToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSignIn_RefusesExpiredPasswordOfInheritingRole(t *testing.T) {
+	f := newPolicySignInFixture(t, "Owner", time.Now().Add(-91*24*time.Hour))
+
+	assert.EqualError(t, f.signIn(), "password expired")
+}
+
+func TestSignIn_AllowsStaffPasswordWithinMaxAge(t *testing.T) {
+	f := newPolicySignInFixture(t, "Super Admin", time.Now().Add(-89*24*time.Hour))
+
+	assert.NoError(t, f.signIn())
+}
+
+func TestSignIn_PolicySkipsCustomers(t *testing.T) {
+	f := newPolicySignInFixture(t, "Customer", time.Now().Add(-400*24*time.Hour))
+
+	assert.NoError(t, f.signIn())
+}
+
+type policyResetFixture struct {
+	userRepo    *mocks.MockUserRepository
+	tokenRepo   *mocks.MockVerificationTokenRepository
+	sessionRepo *mocks.MockSessionRepository
+	service     port.UserServiceInterface
+}
+
+// newPolicyResetFixture has a valid reset token for user 1, whose current password is "current-pass1"
+func newPolicyResetFixture(t *testing.T, role string) *policyResetFixture {
+	f := &policyResetFixture{
+		userRepo:    new(mocks.MockUserRepository),
+		tokenRepo:   new(mocks.MockVerificationTokenRepository),
+		sessionRepo: new(mocks.MockSessionRepository),
+	}
+	f.service = service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              f.userRepo,
+			SessionRepo:           f.sessionRepo,
+			VerificationTokenRepo: f.tokenRepo,
+			Storage:               new(mocks.MockStorage),
+		},
+		Config: policyConfig(),
+	})
+
+	f.tokenRepo.On("GetVerificationToken", mock.Anything, resetToken).Return(&entity.VerificationTokenEntity{UserID: 1, Token: resetToken, TokenType: entity.TokenTypePasswordReset}, nil)
+	f.tokenRepo.On("ClaimVerificationToken", mock.Anything, resetToken).Return(nil)
+	f.userRepo.On("GetUserByID", mock.Anything, int64(1)).Return(&entity.UserEntity{ID: 1, Password: hashFor(t, "current-pass1"), RoleName: role}, nil)
+	f.sessionRepo.On("GetUserSessions", mock.Anything, int64(1)).Return([]entity.SessionInfo{}, nil)
+	f.sessionRepo.On("DeleteAllUserTokens", mock.Anything, int64(1)).Return(nil)
+	return f
+}
+
+func (f *policyResetFixture) reset(password string) error {
+	return f.service.ResetPassword(context.Background(), resetToken, password, password)
+}
+
+func TestResetPassword_RefusesRecentPasswordForStaff(t *testing.T) {
+	f := newPolicyResetFixture(t, "Super Admin")
+	f.userRepo.On("GetPasswordHistory", mock.Anything, int64(1), 4).Return([]string{hashFor(t, "older-pass1"), hashFor(t, "oldest-pass1")}, nil)
+
+	assert.EqualError(t, f.reset("oldest-pass1"), "password was used recently")
+	assert.EqualError(t, f.reset("current-pass1"), "password was used recently")
+
+	f.userRepo.AssertNotCalled(t, "ChangeUserPassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	f.tokenRepo.AssertNotCalled(t, "ClaimVerificationToken", mock.Anything, mock.Anything)
+}
+
+func TestResetPassword_KeepsHistoryForStaff(t *testing.T) {
+	f := newPolicyResetFixture(t, "Super Admin")
+	f.userRepo.On("GetPasswordHistory", mock.Anything, int64(1), 4).Return([]string{hashFor(t, "older-pass1")}, nil)
+	f.userRepo.On("ChangeUserPassword", mock.Anything, int64(1), mock.AnythingOfType("string"), 4).Return(nil)
+
+	require.NoError(t, f.reset("brand-new-pass1"))
+
+	f.userRepo.AssertExpectations(t)
+}
+
+func TestResetPassword_NoHistoryForCustomers(t *testing.T) {
+	f := newPolicyResetFixture(t, "Customer")
+	f.userRepo.On("ChangeUserPassword", mock.Anything, int64(1), mock.AnythingOfType("string"), 0).Return(nil)
+
+	// A customer may pick the current password again
+	require.NoError(t, f.reset("current-pass1"))
+
+	f.userRepo.AssertNotCalled(t, "GetPasswordHistory", mock.Anything, mock.Anything, mock.Anything)
+	f.userRepo.AssertExpectations(t)
+}
+
+func TestSendExpiryWarnings_WarnsOncePerPassword(t *testing.T) {
+	ctx := context.Background()
+	userRepo := new(mocks.MockUserRepository)
+	email := new(mocks.MockEmailPublisher)
+	roleRepo := staffRoles()
+	policyService := service.NewPasswordPolicyService(userRepo, roleRepo, new(mocks.MockSessionRepository), email, service.NewRoleService(roleRepo), policyConfig())
+
+	changedAt := time.Now().Add(-85 * 24 * time.Hour)
+	var changedAfter, changedBefore time.Time
+	// Owner inherits from Super Admin, so its staff are warned as well
+	userRepo.On("GetPasswordsDueForWarning", ctx, []string{"Super Admin", "Owner"}, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"), int64(0), mock.AnythingOfType("int")).
+		Run(func(args mock.Arguments) {
+			changedAfter = args.Get(2).(time.Time)
+			changedBefore = args.Get(3).(time.Time)
+		}).
+		Return([]entity.PasswordExpiryEntity{
+			{UserID: 3, Email: "andi@toko.id", Name: "Andi", Timezone: "Asia/Jakarta", PasswordChangedAt: changedAt},
+			{UserID: 5, Email: "sari@toko.id", Name: "Sari", PasswordChangedAt: changedAt},
+		}, nil)
+	email.On("SendPasswordExpiryWarningEmail", ctx, "andi@toko.id", "Andi", mock.MatchedBy(func(expiresAt time.Time) bool {
+		return expiresAt.Equal(changedAt.Add(90*24*time.Hour)) && expiresAt.Location().String() == "Asia/Jakarta"
+	})).Return(nil)
+	email.On("SendPasswordExpiryWarningEmail", ctx, "sari@toko.id", "Sari", mock.AnythingOfType("time.Time")).Return(errors.New("broker down"))
+	userRepo.On("MarkPasswordExpiryWarned", ctx, int64(3)).Return(nil)
+
+	sent, err := policyService.SendExpiryWarnings(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	// Expired passwords are left to sign-in; only those expiring within 7 days are warned
+	assert.WithinDuration(t, time.Now().Add(-90*24*time.Hour), changedAfter, time.Minute)
+	assert.WithinDuration(t, time.Now().Add(-83*24*time.Hour), changedBefore, time.Minute)
+	// The failed warning stays unmarked and is retried on the next run
+	userRepo.AssertNotCalled(t, "MarkPasswordExpiryWarned", ctx, int64(5))
+}
+
+func TestSendExpiryWarnings_NothingWhileExpiryIsOff(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	policyService := service.NewPasswordPolicyService(userRepo, new(mocks.MockRoleRepository), new(mocks.MockSessionRepository), new(mocks.MockEmailPublisher), nil, &config.Config{PasswordPolicy: config.PasswordPolicy{HistorySize: 5}})
+
+	sent, err := policyService.SendExpiryWarnings(context.Background())
+
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+	userRepo.AssertNotCalled(t, "GetPasswordsDueForWarning", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+type expiryCheckFixture struct {
+	userRepo    *mocks.MockUserRepository
+	sessionRepo *mocks.MockSessionRepository
+	checker     port.PasswordPolicyServiceInterface
+}
+
+// newExpiryCheckFixture has user 1 on password session sess_1, with a password set at changedAt
+func newExpiryCheckFixture(changedAt time.Time, sso bool) *expiryCheckFixture {
+	roleRepo := staffRoles()
+	f := &expiryCheckFixture{
+		userRepo:    new(mocks.MockUserRepository),
+		sessionRepo: new(mocks.MockSessionRepository),
+	}
+	f.checker = service.NewPasswordPolicyService(f.userRepo, roleRepo, f.sessionRepo, nil, service.NewRoleService(roleRepo), policyConfig())
+
+	f.sessionRepo.On("GetSession", mock.Anything, int64(1), "sess_1").Return(&entity.SessionInfo{SessionID: "sess_1", UserID: 1, SSO: sso}, nil)
+	f.userRepo.On("GetUserByID", mock.Anything, int64(1)).Return(&entity.UserEntity{ID: 1, PasswordChangedAt: changedAt}, nil)
+	return f
+}
+
+func TestPasswordExpired_EndsStaffPasswordSessions(t *testing.T) {
+	ctx := context.Background()
+
+	expired := newExpiryCheckFixture(time.Now().Add(-91*24*time.Hour), false)
+	assert.True(t, expired.checker.PasswordExpired(ctx, 1, "sess_1", "Super Admin"))
+	assert.True(t, expired.checker.PasswordExpired(ctx, 1, "sess_1", "Owner"), "inherited roles follow the policy too")
+
+	current := newExpiryCheckFixture(time.Now().Add(-89*24*time.Hour), false)
+	assert.False(t, current.checker.PasswordExpired(ctx, 1, "sess_1", "Super Admin"))
+}
+
+func TestPasswordExpired_SkipsSSOSessionsAndCustomers(t *testing.T) {
+	ctx := context.Background()
+
+	sso := newExpiryCheckFixture(time.Now().Add(-91*24*time.Hour), true)
+	assert.False(t, sso.checker.PasswordExpired(ctx, 1, "sess_1", "Super Admin"))
+	sso.userRepo.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+
+	customer := newExpiryCheckFixture(time.Now().Add(-400*24*time.Hour), false)
+	assert.False(t, customer.checker.PasswordExpired(ctx, 1, "sess_1", "Customer"))
+	customer.sessionRepo.AssertNotCalled(t, "GetSession", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPasswordExpired_EndsSessionInMiddlewareAndIntrospection(t *testing.T) {
+	cfg := &config.Config{App: config.App{JwtSecretKey: "test-secret", JwtIssuer: "user-service"}}
+	token, err := utils.GenerateJWTWithSession(cfg, 1, "staff@example.com", "Super Admin", "sess_1", true)
+	require.NoError(t, err)
+
+	f := newExpiryCheckFixture(time.Now().Add(-91*24*time.Hour), false)
+	f.sessionRepo.On("ValidateToken", mock.Anything, int64(1), "sess_1", token).Return(true)
+
+	e := echo.New()
+	e.GET("/api/v1/auth/profile", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	}, middleware.JWTMiddleware(cfg, f.sessionRepo, nil, nil, f.checker))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/profile", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	result, err := service.NewTokenIntrospectionService(utils.NewJWTUtil(cfg), f.sessionRepo, nil, f.checker).Introspect(context.Background(), token)
+	require.NoError(t, err)
+	assert.False(t, result.Active)
+	assert.Equal(t, entity.TokenInactivePasswordExpired, result.Reason)
+}
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	assert.NoError(t, config.PasswordPolicy{}.Validate())
+	assert.NoError(t, staffPolicy.Validate())
+	assert.Equal(t, []string{"Super Admin"}, config.PasswordPolicy{}.PolicyRoles())
+	assert.Zero(t, config.PasswordPolicy{}.MaxAge())
+
+	assert.ErrorContains(t, config.PasswordPolicy{MaxAgeDays: -1}.Validate(), "PASSWORD_MAX_AGE_DAYS")
+	assert.ErrorContains(t, config.PasswordPolicy{HistorySize: 30}.Validate(), "PASSWORD_HISTORY_SIZE")
+	assert.ErrorContains(t, config.PasswordPolicy{MaxAgeDays: 5}.Validate(), "PASSWORD_EXPIRY_WARN_DAYS")
+}
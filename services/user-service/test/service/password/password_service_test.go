@@ -19,7 +19,15 @@ func TestUserService_ForgotPassword_Success(t *testing.T) {
 	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
 	mockEmailPublisher := new(mocks.MockEmailPublisher)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(mockUserRepo, nil, nil, mockVerificationTokenRepo, mockEmailPublisher, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              mockUserRepo,
+			VerificationTokenRepo: mockVerificationTokenRepo,
+			EmailPublisher:        mockEmailPublisher,
+			Storage:               mockStorage,
+		},
+		Config: &config.Config{},
+	})
 
 	ctx := context.Background()
 	email := "user@example.com"
@@ -49,7 +57,10 @@ func TestUserService_ForgotPassword_InvalidEmail(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(mockUserRepo, nil, nil, nil, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage},
+		Config:          &config.Config{},
+	})
 
 	ctx := context.Background()
 
@@ -68,7 +79,10 @@ func TestUserService_ForgotPassword_UserNotFound(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(mockUserRepo, nil, nil, nil, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage},
+		Config:          &config.Config{},
+	})
 
 	ctx := context.Background()
 	email := "notfound@example.com"
@@ -88,7 +102,10 @@ func TestUserService_ForgotPassword_UserNotVerified(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(mockUserRepo, nil, nil, nil, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage},
+		Config:          &config.Config{},
+	})
 
 	ctx := context.Background()
 	email := "unverified@example.com"
@@ -114,8 +131,17 @@ func TestUserService_ResetPassword_Success(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
+	mockSessionRepo := new(mocks.MockSessionRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(mockUserRepo, nil, nil, mockVerificationTokenRepo, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              mockUserRepo,
+			SessionRepo:           mockSessionRepo,
+			VerificationTokenRepo: mockVerificationTokenRepo,
+			Storage:               mockStorage,
+		},
+		Config: &config.Config{},
+	})
 
 	ctx := context.Background()
 	token := "valid-reset-token"
@@ -130,8 +156,10 @@ func TestUserService_ResetPassword_Success(t *testing.T) {
 
 	// Mock expectations
 	mockVerificationTokenRepo.On("GetVerificationToken", ctx, token).Return(resetToken, nil)
-	mockUserRepo.On("UpdateUserPassword", ctx, int64(1), mock.AnythingOfType("string")).Return(nil)
-	mockVerificationTokenRepo.On("DeleteVerificationToken", ctx, token).Return(nil)
+	mockUserRepo.On("ChangeUserPassword", ctx, int64(1), mock.AnythingOfType("string"), 0).Return(nil)
+	mockSessionRepo.On("GetUserSessions", ctx, int64(1)).Return([]entity.SessionInfo{}, nil)
+	mockSessionRepo.On("DeleteAllUserTokens", ctx, int64(1)).Return(nil)
+	mockVerificationTokenRepo.On("ClaimVerificationToken", ctx, token).Return(nil)
 
 	// Execute
 	err := service.ResetPassword(ctx, token, newPassword, passwordConfirmation)
@@ -139,6 +167,7 @@ func TestUserService_ResetPassword_Success(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	mockUserRepo.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
 	mockVerificationTokenRepo.AssertExpectations(t)
 }
 
@@ -146,7 +175,13 @@ func TestUserService_ResetPassword_InvalidToken(t *testing.T) {
 	// Setup
 	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(nil, nil, nil, mockVerificationTokenRepo, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			VerificationTokenRepo: mockVerificationTokenRepo,
+			Storage:               mockStorage,
+		},
+		Config: &config.Config{},
+	})
 
 	ctx := context.Background()
 	token := "invalid-token"
@@ -167,7 +202,13 @@ func TestUserService_ResetPassword_PasswordMismatch(t *testing.T) {
 	// Setup
 	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(nil, nil, nil, mockVerificationTokenRepo, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			VerificationTokenRepo: mockVerificationTokenRepo,
+			Storage:               mockStorage,
+		},
+		Config: &config.Config{},
+	})
 
 	ctx := context.Background()
 	token := "valid-token"
@@ -189,7 +230,13 @@ func TestUserService_ResetPassword_PasswordTooShort(t *testing.T) {
 	// Setup
 	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(nil, nil, nil, mockVerificationTokenRepo, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			VerificationTokenRepo: mockVerificationTokenRepo,
+			Storage:               mockStorage,
+		},
+		Config: &config.Config{},
+	})
 
 	ctx := context.Background()
 	token := "valid-token"
@@ -211,7 +258,13 @@ func TestUserService_ResetPassword_WrongTokenType(t *testing.T) {
 	// Setup
 	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(nil, nil, nil, mockVerificationTokenRepo, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			VerificationTokenRepo: mockVerificationTokenRepo,
+			Storage:               mockStorage,
+		},
+		Config: &config.Config{},
+	})
 
 	ctx := context.Background()
 	token := "email-verification-token"
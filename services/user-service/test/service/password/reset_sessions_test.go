@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const resetToken = "valid-reset-token"
+
+type resetFixture struct {
+	userRepo      *mocks.MockUserRepository
+	sessionRepo   *mocks.MockSessionRepository
+	tokenRepo     *mocks.MockVerificationTokenRepository
+	blacklistRepo *mocks.MockBlacklistTokenRepository
+	email         *mocks.MockEmailPublisher
+	service       port.UserServiceInterface
+	tokens        map[string]string
+}
+
+// newResetFixture has a valid reset token for user 1, who is signed in on two devices
+func newResetFixture(t *testing.T) *resetFixture {
+	cfg := &config.Config{App: config.App{JwtSecretKey: "test-secret", JwtIssuer: "test"}}
+	f := &resetFixture{
+		userRepo:      new(mocks.MockUserRepository),
+		sessionRepo:   new(mocks.MockSessionRepository),
+		tokenRepo:     new(mocks.MockVerificationTokenRepository),
+		blacklistRepo: new(mocks.MockBlacklistTokenRepository),
+		email:         new(mocks.MockEmailPublisher),
+		tokens:        map[string]string{},
+	}
+	f.service = service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              f.userRepo,
+			SessionRepo:           f.sessionRepo,
+			JWTUtil:               utils.NewJWTUtil(cfg),
+			VerificationTokenRepo: f.tokenRepo,
+			EmailPublisher:        f.email,
+			BlacklistTokenRepo:    f.blacklistRepo,
+			Storage:               new(mocks.MockStorage),
+		},
+		Config: cfg,
+	})
+
+	var sessions []entity.SessionInfo
+	for _, sessionID := range []string{"sess_phone", "sess_laptop"} {
+		token, err := utils.GenerateJWTWithSession(cfg, 1, "john@example.com", "Customer", sessionID, false)
+		assert.NoError(t, err)
+		f.tokens[sessionID] = token
+		sessions = append(sessions, entity.SessionInfo{SessionID: sessionID, UserID: 1})
+		f.sessionRepo.On("GetToken", mock.Anything, int64(1), sessionID).Return(token, nil)
+	}
+
+	f.tokenRepo.On("GetVerificationToken", mock.Anything, resetToken).Return(&entity.VerificationTokenEntity{UserID: 1, Token: resetToken, TokenType: "password_reset"}, nil)
+	f.userRepo.On("ChangeUserPassword", mock.Anything, int64(1), mock.AnythingOfType("string"), 0).Return(nil)
+	f.userRepo.On("GetUserByID", mock.Anything, int64(1)).Return(&entity.UserEntity{ID: 1, Email: "john@example.com", Timezone: "Asia/Jakarta"}, nil)
+	f.sessionRepo.On("GetUserSessions", mock.Anything, int64(1)).Return(sessions, nil)
+	return f
+}
+
+func hashOf(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestResetPassword_SignsOutEverywhereAndNotifies(t *testing.T) {
+	f := newResetFixture(t)
+	f.blacklistRepo.On("AddToBlacklist", mock.Anything, hashOf(f.tokens["sess_phone"]), mock.AnythingOfType("int64")).Return(nil)
+	f.blacklistRepo.On("AddToBlacklist", mock.Anything, hashOf(f.tokens["sess_laptop"]), mock.AnythingOfType("int64")).Return(nil)
+	f.sessionRepo.On("DeleteAllUserTokens", mock.Anything, int64(1)).Return(nil)
+	f.email.On("SendPasswordChangedEmail", mock.Anything, "john@example.com", mock.MatchedBy(func(changedAt time.Time) bool {
+		return changedAt.Location().String() == "Asia/Jakarta"
+	})).Return(nil)
+	f.tokenRepo.On("ClaimVerificationToken", mock.Anything, resetToken).Return(nil)
+
+	err := f.service.ResetPassword(context.Background(), resetToken, "newpassword123", "newpassword123")
+
+	assert.NoError(t, err)
+	f.blacklistRepo.AssertExpectations(t)
+	f.sessionRepo.AssertExpectations(t)
+	f.email.AssertExpectations(t)
+	f.tokenRepo.AssertExpectations(t)
+}
+
+func TestResetPassword_SessionsDeletedWhenBlacklistFails(t *testing.T) {
+	f := newResetFixture(t)
+	f.blacklistRepo.On("AddToBlacklist", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("connection reset"))
+	f.sessionRepo.On("DeleteAllUserTokens", mock.Anything, int64(1)).Return(nil)
+	f.email.On("SendPasswordChangedEmail", mock.Anything, "john@example.com", mock.Anything).Return(nil)
+	f.tokenRepo.On("ClaimVerificationToken", mock.Anything, resetToken).Return(nil)
+
+	err := f.service.ResetPassword(context.Background(), resetToken, "newpassword123", "newpassword123")
+
+	assert.NoError(t, err, "deleted sessions are enough to reject the old tokens")
+	f.tokenRepo.AssertExpectations(t)
+}
+
+func TestResetPassword_TokensBlacklistedWhenSessionDeleteFails(t *testing.T) {
+	f := newResetFixture(t)
+	f.blacklistRepo.On("AddToBlacklist", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	f.sessionRepo.On("DeleteAllUserTokens", mock.Anything, int64(1)).Return(errors.New("redis timeout"))
+	f.email.On("SendPasswordChangedEmail", mock.Anything, "john@example.com", mock.Anything).Return(nil)
+	f.tokenRepo.On("ClaimVerificationToken", mock.Anything, resetToken).Return(nil)
+
+	err := f.service.ResetPassword(context.Background(), resetToken, "newpassword123", "newpassword123")
+
+	assert.NoError(t, err, "blacklisted tokens are rejected even while their sessions remain")
+	f.blacklistRepo.AssertNumberOfCalls(t, "AddToBlacklist", 2)
+}
+
+func TestResetPassword_FailsWhenTokensMayStillWork(t *testing.T) {
+	f := newResetFixture(t)
+	f.blacklistRepo.On("AddToBlacklist", mock.Anything, hashOf(f.tokens["sess_phone"]), mock.Anything).Return(nil)
+	f.blacklistRepo.On("AddToBlacklist", mock.Anything, hashOf(f.tokens["sess_laptop"]), mock.Anything).Return(errors.New("connection reset"))
+	f.sessionRepo.On("DeleteAllUserTokens", mock.Anything, int64(1)).Return(errors.New("redis timeout"))
+	f.email.On("SendPasswordChangedEmail", mock.Anything, "john@example.com", mock.Anything).Return(nil)
+	f.tokenRepo.On("ClaimVerificationToken", mock.Anything, resetToken).Return(nil)
+	f.tokenRepo.On("ReleaseVerificationToken", mock.Anything, resetToken).Return(nil)
+
+	err := f.service.ResetPassword(context.Background(), resetToken, "newpassword123", "newpassword123")
+
+	assert.EqualError(t, err, "failed to revoke sessions")
+	// The owner is still warned, and the link stays usable to retry the sign-out
+	f.email.AssertExpectations(t)
+	f.tokenRepo.AssertExpectations(t)
+}
+
+func TestResetPassword_EmailFailureDoesNotFailReset(t *testing.T) {
+	f := newResetFixture(t)
+	f.blacklistRepo.On("AddToBlacklist", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	f.sessionRepo.On("DeleteAllUserTokens", mock.Anything, int64(1)).Return(nil)
+	f.email.On("SendPasswordChangedEmail", mock.Anything, "john@example.com", mock.Anything).Return(errors.New("rabbitmq unavailable"))
+	f.tokenRepo.On("ClaimVerificationToken", mock.Anything, resetToken).Return(nil)
+
+	err := f.service.ResetPassword(context.Background(), resetToken, "newpassword123", "newpassword123")
+
+	assert.NoError(t, err)
+	f.tokenRepo.AssertExpectations(t)
+}
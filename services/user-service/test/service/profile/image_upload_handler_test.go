@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"user-service/config"
+	"user-service/internal/adapter/handler"
+	"user-service/internal/adapter/handler/response"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// pngHeader is enough for http.DetectContentType to recognise a PNG
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func uploadPhoto(t *testing.T, h handler.AuthHandlerInterface, filename, declaredType string, content []byte) (*httptest.ResponseRecorder, response.DefaultResponse) {
+	return uploadPhotoWithFields(t, h, filename, declaredType, content, nil)
+}
+
+// uploadPhotoWithFields sends extra form fields, such as the rotation and crop, with the photo
+func uploadPhotoWithFields(t *testing.T, h handler.AuthHandlerInterface, filename, declaredType string, content []byte, fields map[string]string) (*httptest.ResponseRecorder, response.DefaultResponse) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	header := make(map[string][]string)
+	header["Content-Disposition"] = []string{`form-data; name="photo"; filename="` + filename + `"`}
+	header["Content-Type"] = []string{declaredType}
+	part, err := writer.CreatePart(header)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/profile/image-upload", &body)
+	req.Header.Set(echo.HeaderContentType, writer.FormDataContentType())
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.Set("user_id", int64(1))
+
+	require.NoError(t, h.ImageUploadProfile(c))
+
+	var resp response.DefaultResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return rec, resp
+}
+
+func TestImageUploadProfile_Validation(t *testing.T) {
+	t.Run("stores a PNG with the sniffed content type", func(t *testing.T) {
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockStorage := new(mocks.MockStorage)
+		userService := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage},
+			Config:          &config.Config{},
+		})
+		h := handler.NewAuthHandler(userService, &config.Config{})
+
+		mockUserRepo.On("GetUserByID", mock.Anything, int64(1)).Return(&entity.UserEntity{ID: 1}, nil)
+		// The client claims JPEG; the bytes say PNG, and the original is stored as such
+		mockStorage.On("UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "image/png").Return("https://cdn.example.com/original.png", nil)
+		mockStorage.On("UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "image/jpeg").Return("https://cdn.example.com/new.jpg", nil)
+		mockUserRepo.On("UpdateUserPhotos", mock.Anything, int64(1), "https://cdn.example.com/new.jpg", "https://cdn.example.com/original.png").Return(nil)
+
+		rec, resp := uploadPhoto(t, h, "avatar.png", "image/jpeg", encodePNG(t, 40, 20))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		data := resp.Data.(map[string]interface{})
+		assert.Equal(t, "https://cdn.example.com/new.jpg", data["image_url"])
+		assert.Equal(t, "https://cdn.example.com/original.png", data["original_url"])
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("rejects text renamed to .png with 415", func(t *testing.T) {
+		mockStorage := new(mocks.MockStorage)
+		h := handler.NewAuthHandler(service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: new(mocks.MockUserRepository), Storage: mockStorage},
+			Config:          &config.Config{},
+		}), &config.Config{})
+
+		rec, resp := uploadPhoto(t, h, "avatar.png", "image/png", []byte("<html><script>alert(1)</script></html>"))
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+		assert.Equal(t, "Invalid file type, only JPEG, PNG, GIF and WebP images are allowed", resp.Message)
+		mockStorage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects an image with an unsupported extension with 415", func(t *testing.T) {
+		mockStorage := new(mocks.MockStorage)
+		h := handler.NewAuthHandler(service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: new(mocks.MockUserRepository), Storage: mockStorage},
+			Config:          &config.Config{},
+		}), &config.Config{})
+
+		rec, _ := uploadPhoto(t, h, "avatar.exe", "image/png", pngHeader)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+		mockStorage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a file over 5MB with 413", func(t *testing.T) {
+		mockStorage := new(mocks.MockStorage)
+		h := handler.NewAuthHandler(service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: new(mocks.MockUserRepository), Storage: mockStorage},
+			Config:          &config.Config{},
+		}), &config.Config{})
+
+		rec, resp := uploadPhoto(t, h, "avatar.png", "image/png", append(pngHeader, make([]byte, 5<<20)...))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Equal(t, "File size too large, maximum 5MB", resp.Message)
+	})
+
+	t.Run("rejects an empty file with 400", func(t *testing.T) {
+		h := handler.NewAuthHandler(service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{
+				UserRepo: new(mocks.MockUserRepository),
+				Storage:  new(mocks.MockStorage),
+			},
+			Config: &config.Config{},
+		}), &config.Config{})
+
+		rec, resp := uploadPhoto(t, h, "avatar.png", "image/png", nil)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "File is empty", resp.Message)
+	})
+}
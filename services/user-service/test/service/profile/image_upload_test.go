@@ -17,7 +17,7 @@ func TestAuthService_UploadProfileImage_Success_WithOldPhotoCleanup(t *testing.T
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, mockStorage)
+	service := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -53,7 +53,7 @@ func TestAuthService_UploadProfileImage_Success_NoExistingPhoto(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, mockStorage)
+	service := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -89,7 +89,7 @@ func TestAuthService_UploadProfileImage_UploadFailure(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, mockStorage)
+	service := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -122,7 +122,7 @@ func TestAuthService_UploadProfileImage_DatabaseUpdateFailure(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, mockStorage)
+	service := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -158,7 +158,7 @@ func TestAuthService_UploadProfileImage_OldPhotoDeletionFailure(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, mockStorage)
+	service := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -194,7 +194,7 @@ func TestAuthService_UploadProfileImage_GetUserFailure(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, mockStorage)
+	service := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage})
 
 	ctx := context.Background()
 	userID := int64(1)
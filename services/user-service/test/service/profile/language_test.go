@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestChangeLanguage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores a supported language", func(t *testing.T) {
+		mockUserRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+
+		mockUserRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7, Language: "en"}, nil)
+		mockUserRepo.On("UpdateLanguage", ctx, int64(7), "id").Return(nil)
+
+		language, err := userService.ChangeLanguage(ctx, 7, " ID ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "id", language)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a language without email templates", func(t *testing.T) {
+		mockUserRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+
+		_, err := userService.ChangeLanguage(ctx, 7, "fr")
+
+		assert.EqualError(t, err, "unsupported language")
+		mockUserRepo.AssertNotCalled(t, "UpdateLanguage", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("skips the write when nothing changes", func(t *testing.T) {
+		mockUserRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+
+		mockUserRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7, Language: "id"}, nil)
+
+		language, err := userService.ChangeLanguage(ctx, 7, "id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "id", language)
+		mockUserRepo.AssertNotCalled(t, "UpdateLanguage", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reports a failed write", func(t *testing.T) {
+		mockUserRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+
+		mockUserRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7}, nil)
+		mockUserRepo.On("UpdateLanguage", ctx, int64(7), "en").Return(errors.New("connection reset"))
+
+		_, err := userService.ChangeLanguage(ctx, 7, "en")
+
+		assert.EqualError(t, err, "failed to update language")
+	})
+}
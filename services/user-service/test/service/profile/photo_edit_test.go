@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"testing"
+	"user-service/config"
+	"user-service/internal/adapter/handler"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	red  = color.RGBA{R: 255, A: 255}
+	blue = color.RGBA{B: 255, A: 255}
+)
+
+// halves draws a w×h image with a red left half and a blue right half
+func halves(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			if x < w/2 {
+				img.Set(x, y, red)
+			} else {
+				img.Set(x, y, blue)
+			}
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, halves(w, h)))
+	return buf.Bytes()
+}
+
+// encodeJPEGWithOrientation writes a JPEG whose APP1 segment carries the EXIF Orientation tag
+func encodeJPEGWithOrientation(t *testing.T, w, h int, orientation uint16) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, halves(w, h), &jpeg.Options{Quality: 95}))
+
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	data := buf.Bytes()
+	return append(append([]byte{0xFF, 0xD8}, app1...), data[2:]...)
+}
+
+func decodeProcessed(t *testing.T, data []byte) image.Image {
+	img, format, err := image.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, image.Rect(0, 0, utils.ProfilePhotoSize, utils.ProfilePhotoSize), img.Bounds())
+	return img
+}
+
+func assertColor(t *testing.T, img image.Image, x, y int, want color.RGBA) {
+	r, g, b, _ := img.At(x, y).RGBA()
+	got := color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: 255}
+	// JPEG is lossy, so only the dominant channel is compared
+	assert.InDelta(t, want.R, got.R, 40, "red at %d,%d", x, y)
+	assert.InDelta(t, want.B, got.B, 40, "blue at %d,%d", x, y)
+}
+
+func TestProcessProfilePhoto(t *testing.T) {
+	t.Run("takes the centred square of a landscape photo", func(t *testing.T) {
+		out, err := utils.ProcessProfilePhoto(encodePNG(t, 40, 20), 0, nil)
+		require.NoError(t, err)
+
+		img := decodeProcessed(t, out)
+		assertColor(t, img, 50, 256, red)
+		assertColor(t, img, 460, 256, blue)
+	})
+
+	t.Run("applies the EXIF orientation", func(t *testing.T) {
+		// Orientation 6 is shown turned 90° clockwise, so the red left half ends up on top
+		out, err := utils.ProcessProfilePhoto(encodeJPEGWithOrientation(t, 40, 20, 6), 0, nil)
+		require.NoError(t, err)
+
+		img := decodeProcessed(t, out)
+		assertColor(t, img, 256, 50, red)
+		assertColor(t, img, 256, 460, blue)
+	})
+
+	t.Run("rotates clockwise", func(t *testing.T) {
+		out, err := utils.ProcessProfilePhoto(encodePNG(t, 40, 20), 270, nil)
+		require.NoError(t, err)
+
+		img := decodeProcessed(t, out)
+		assertColor(t, img, 256, 50, blue)
+		assertColor(t, img, 256, 460, red)
+	})
+
+	t.Run("crops the requested rectangle", func(t *testing.T) {
+		crop := image.Rect(20, 0, 40, 20)
+		out, err := utils.ProcessProfilePhoto(encodePNG(t, 40, 20), 0, &crop)
+		require.NoError(t, err)
+
+		img := decodeProcessed(t, out)
+		assertColor(t, img, 10, 10, blue)
+		assertColor(t, img, 500, 500, blue)
+	})
+
+	t.Run("rejects a crop outside the image", func(t *testing.T) {
+		crop := image.Rect(30, 0, 50, 20)
+		_, err := utils.ProcessProfilePhoto(encodePNG(t, 40, 20), 0, &crop)
+		assert.ErrorIs(t, err, utils.ErrPhotoCrop)
+	})
+
+	t.Run("rejects an unsupported rotation", func(t *testing.T) {
+		_, err := utils.ProcessProfilePhoto(encodePNG(t, 40, 20), 45, nil)
+		assert.ErrorIs(t, err, utils.ErrPhotoRotation)
+	})
+}
+
+func TestImageUploadProfile_PhotoEdit(t *testing.T) {
+	t.Run("rejects a rotation that is not a quarter turn", func(t *testing.T) {
+		mockStorage := new(mocks.MockStorage)
+		h := handler.NewAuthHandler(service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: new(mocks.MockUserRepository), Storage: mockStorage},
+			Config:          &config.Config{},
+		}), &config.Config{})
+
+		rec, _ := uploadPhotoWithFields(t, h, "avatar.png", "image/png", encodePNG(t, 40, 20), map[string]string{"rotation": "45"})
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		mockStorage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a partial crop", func(t *testing.T) {
+		mockStorage := new(mocks.MockStorage)
+		h := handler.NewAuthHandler(service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: new(mocks.MockUserRepository), Storage: mockStorage},
+			Config:          &config.Config{},
+		}), &config.Config{})
+
+		rec, _ := uploadPhotoWithFields(t, h, "avatar.png", "image/png", encodePNG(t, 40, 20), map[string]string{"crop_x": "0", "crop_y": "0"})
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		mockStorage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("rejects a crop outside the rotated image", func(t *testing.T) {
+		mockUserRepo := new(mocks.MockUserRepository)
+		mockStorage := new(mocks.MockStorage)
+		h := handler.NewAuthHandler(service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage},
+			Config:          &config.Config{},
+		}), &config.Config{})
+
+		// After a quarter turn the 40×20 photo is 20 wide
+		rec, resp := uploadPhotoWithFields(t, h, "avatar.png", "image/png", encodePNG(t, 40, 20), map[string]string{
+			"rotation": "90", "crop_x": "10", "crop_y": "0", "crop_width": "20", "crop_height": "20",
+		})
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		assert.Equal(t, "Crop is outside the image", resp.Message)
+		mockStorage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
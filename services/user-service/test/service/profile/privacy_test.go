@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-service/config"
+	"user-service/internal/adapter/handler"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var privateCustomer = entity.UserEntity{
+	ID:         7,
+	Name:       "Siti",
+	Phone:      "081234567890",
+	Address:    "Jl. Melati No. 5",
+	City:       "Bandung",
+	District:   "Coblong",
+	PostalCode: "40132",
+	Lat:        -6.89,
+	Lng:        107.61,
+	Privacy:    entity.PrivacySettingsEntity{HidePhone: true, HideAddress: true},
+}
+
+func TestChangePrivacySettings(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores new settings", func(t *testing.T) {
+		mockUserRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+		settings := entity.PrivacySettingsEntity{HidePhone: true}
+
+		mockUserRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7}, nil)
+		mockUserRepo.On("UpdatePrivacySettings", ctx, int64(7), settings).Return(nil)
+
+		saved, err := userService.ChangePrivacySettings(ctx, 7, settings)
+
+		assert.NoError(t, err)
+		assert.Equal(t, settings, *saved)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("skips the write when nothing changes", func(t *testing.T) {
+		mockUserRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+		settings := entity.PrivacySettingsEntity{HideAddress: true}
+
+		mockUserRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7, Privacy: settings}, nil)
+
+		_, err := userService.ChangePrivacySettings(ctx, 7, settings)
+
+		assert.NoError(t, err)
+		mockUserRepo.AssertNotCalled(t, "UpdatePrivacySettings", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMaskedFor(t *testing.T) {
+	masked, hidden := privateCustomer.MaskedFor(entity.AudienceVendor)
+
+	assert.Equal(t, []string{entity.PrivacyFieldPhone, entity.PrivacyFieldAddress}, hidden)
+	assert.Empty(t, masked.Phone)
+	assert.Empty(t, masked.Address)
+	assert.Empty(t, masked.PostalCode)
+	assert.Zero(t, masked.Lat)
+	assert.Equal(t, "Coblong", masked.District, "the area stays visible")
+
+	for _, audience := range []string{entity.AudienceSelf, entity.AudienceAdmin, entity.AudienceCourier} {
+		unmasked, hidden := privateCustomer.MaskedFor(audience)
+		assert.Equal(t, privateCustomer, unmasked, audience)
+		assert.Empty(t, hidden, audience)
+	}
+}
+
+func TestBatchGetUsers_MasksForVendorsByDefault(t *testing.T) {
+	cases := []struct {
+		body      string
+		wantPhone string
+	}{
+		{`{"ids":[7]}`, ""},
+		{`{"ids":[7],"audience":"vendor"}`, ""},
+		{`{"ids":[7],"audience":"courier"}`, privateCustomer.Phone},
+	}
+	for _, tc := range cases {
+		mockUserRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+		h := handler.NewInternalHandler(userService, nil)
+
+		mockUserRepo.On("GetUsersByIDs", mock.Anything, []int64{7}).Return([]entity.UserEntity{privateCustomer}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/internal/v1/users/batch", strings.NewReader(tc.body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(req, rec)
+		c.Set(middleware.PrincipalContextKey, entity.PrincipalEntity{Type: entity.PrincipalTypeService, ServiceName: "order-service"})
+
+		assert.NoError(t, h.BatchGetUsers(c))
+		assert.Equal(t, http.StatusOK, rec.Code, tc.body)
+
+		var body struct {
+			Data struct {
+				Users []struct {
+					Phone        string   `json:"phone"`
+					HiddenFields []string `json:"hidden_fields"`
+				} `json:"users"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Len(t, body.Data.Users, 1)
+		assert.Equal(t, tc.wantPhone, body.Data.Users[0].Phone, tc.body)
+		assert.Equal(t, tc.wantPhone == "", len(body.Data.Users[0].HiddenFields) > 0, tc.body)
+	}
+}
+
+func TestGetCustomerByID_MasksForPartners(t *testing.T) {
+	principals := []struct {
+		principal entity.PrincipalEntity
+		wantPhone string
+	}{
+		{entity.PrincipalEntity{Type: entity.PrincipalTypeAPIKey, Partner: "warung-app"}, ""},
+		{entity.PrincipalEntity{Type: entity.PrincipalTypeUser, Role: "Super Admin"}, privateCustomer.Phone},
+	}
+	for _, tc := range principals {
+		mockUserRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+		h := handler.NewCustomerHandler(userService, nil, &config.Config{})
+
+		customer := privateCustomer
+		mockUserRepo.On("GetCustomerByID", mock.Anything, int64(7)).Return(&customer, nil)
+
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/partner/customers/7", nil), rec)
+		c.SetParamNames("id")
+		c.SetParamValues("7")
+		c.Set(middleware.PrincipalContextKey, tc.principal)
+
+		assert.NoError(t, h.GetCustomerByID(c))
+
+		var body struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, tc.wantPhone, body.Data["phone"], tc.principal.Type)
+	}
+}
@@ -15,7 +15,7 @@ import (
 func TestAuthService_UpdateProfile_Success(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
-	service := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, nil)
+	service := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -36,10 +36,10 @@ func TestAuthService_UpdateProfile_Success(t *testing.T) {
 
 	// Mock expectations
 	mockUserRepo.On("GetUserByID", ctx, userID).Return(currentUser, nil)
-	mockUserRepo.On("UpdateUserProfile", ctx, userID, name, email, phone, address, lat, lng, photo).Return(nil)
+	mockUserRepo.On("UpdateUserProfile", ctx, userID, name, email, phone, address, lat, lng, photo, int64(0)).Return(nil)
 
 	// Execute
-	err := service.UpdateProfile(ctx, userID, name, email, phone, address, lat, lng, photo)
+	err := service.UpdateProfile(ctx, userID, name, email, phone, address, lat, lng, photo, 0)
 
 	// Assert
 	assert.NoError(t, err)
@@ -49,7 +49,7 @@ func TestAuthService_UpdateProfile_Success(t *testing.T) {
 func TestAuthService_UpdateProfile_EmailAlreadyExists(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
-	service := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, nil)
+	service := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -74,19 +74,19 @@ func TestAuthService_UpdateProfile_EmailAlreadyExists(t *testing.T) {
 	mockUserRepo.On("GetUserByEmailIncludingUnverified", ctx, email).Return(existingUser, nil)
 
 	// Execute
-	err := service.UpdateProfile(ctx, userID, name, email, phone, address, lat, lng, photo)
+	err := service.UpdateProfile(ctx, userID, name, email, phone, address, lat, lng, photo, 0)
 
 	// Assert
 	assert.Error(t, err)
 	assert.Equal(t, "email already exists", err.Error())
 	mockUserRepo.AssertExpectations(t)
-	mockUserRepo.AssertNotCalled(t, "UpdateUserProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "UpdateUserProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestAuthService_UpdateProfile_SameUserEmail(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
-	service := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, nil)
+	service := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -107,10 +107,10 @@ func TestAuthService_UpdateProfile_SameUserEmail(t *testing.T) {
 
 	// Mock expectations
 	mockUserRepo.On("GetUserByID", ctx, userID).Return(currentUser, nil)
-	mockUserRepo.On("UpdateUserProfile", ctx, userID, name, email, phone, address, lat, lng, photo).Return(nil)
+	mockUserRepo.On("UpdateUserProfile", ctx, userID, name, email, phone, address, lat, lng, photo, int64(0)).Return(nil)
 
 	// Execute
-	err := service.UpdateProfile(ctx, userID, name, email, phone, address, lat, lng, photo)
+	err := service.UpdateProfile(ctx, userID, name, email, phone, address, lat, lng, photo, 0)
 
 	// Assert
 	assert.NoError(t, err)
@@ -120,7 +120,7 @@ func TestAuthService_UpdateProfile_SameUserEmail(t *testing.T) {
 func TestAuthService_UpdateProfile_InvalidEmail(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
-	service := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, nil)
+	service := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -133,19 +133,19 @@ func TestAuthService_UpdateProfile_InvalidEmail(t *testing.T) {
 	photo := "https://example.com/photo.jpg"
 
 	// Execute - should fail email validation before calling repository
-	err := service.UpdateProfile(ctx, userID, name, email, phone, address, lat, lng, photo)
+	err := service.UpdateProfile(ctx, userID, name, email, phone, address, lat, lng, photo, 0)
 
 	// Assert
 	assert.Error(t, err)
 	// Email validation error should be returned
 	mockUserRepo.AssertNotCalled(t, "GetUserByEmailIncludingUnverified", mock.Anything, mock.Anything)
-	mockUserRepo.AssertNotCalled(t, "UpdateUserProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "UpdateUserProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestAuthService_UpdateProfile_EmptyEmail(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
-	service := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, nil)
+	service := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -158,12 +158,12 @@ func TestAuthService_UpdateProfile_EmptyEmail(t *testing.T) {
 	photo := "https://example.com/photo.jpg"
 
 	// Execute - should fail email validation
-	err := service.UpdateProfile(ctx, userID, name, email, phone, address, lat, lng, photo)
+	err := service.UpdateProfile(ctx, userID, name, email, phone, address, lat, lng, photo, 0)
 
 	// Assert
 	assert.Error(t, err)
 	mockUserRepo.AssertNotCalled(t, "GetUserByEmailIncludingUnverified", mock.Anything, mock.Anything)
-	mockUserRepo.AssertNotCalled(t, "UpdateUserProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "UpdateUserProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestAuthService_UpdateProfile_DatabaseError(t *testing.T) {
@@ -172,7 +172,12 @@ func TestAuthService_UpdateProfile_DatabaseError(t *testing.T) {
 	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
 	mockEmailPublisher := new(mocks.MockEmailPublisher)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewAuthService(mockUserRepo, nil, nil, mockVerificationTokenRepo, mockEmailPublisher, nil, mockStorage)
+	service := service.NewAuthService(service.AuthServiceDeps{
+		UserRepo:              mockUserRepo,
+		VerificationTokenRepo: mockVerificationTokenRepo,
+		EmailPublisher:        mockEmailPublisher,
+		Storage:               mockStorage,
+	})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -200,10 +205,10 @@ func TestAuthService_UpdateProfile_DatabaseError(t *testing.T) {
 	mockEmailPublisher.On("SendEmailChangeVerificationEmail", ctx, email, mock.AnythingOfType("string")).Return(nil)
 	mockUserRepo.On("UpdateUserVerificationStatus", ctx, userID, false).Return(nil)
 	// Profile update fails
-	mockUserRepo.On("UpdateUserProfile", ctx, userID, name, "old@example.com", phone, address, lat, lng, photo).Return(errors.New("database connection failed"))
+	mockUserRepo.On("UpdateUserProfile", ctx, userID, name, "old@example.com", phone, address, lat, lng, photo, int64(0)).Return(errors.New("database connection failed"))
 
 	// Execute
-	err := service.UpdateProfile(ctx, userID, name, email, phone, address, lat, lng, photo)
+	err := service.UpdateProfile(ctx, userID, name, email, phone, address, lat, lng, photo, 0)
 
 	// Assert
 	assert.Error(t, err)
@@ -217,7 +222,7 @@ func TestAuthService_UpdateProfile_DatabaseError(t *testing.T) {
 func TestAuthService_UpdateProfile_EmailCheckError(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
-	service := service.NewAuthService(mockUserRepo, nil, nil, nil, nil, nil, nil)
+	service := service.NewAuthService(service.AuthServiceDeps{UserRepo: mockUserRepo})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -241,11 +246,11 @@ func TestAuthService_UpdateProfile_EmailCheckError(t *testing.T) {
 	mockUserRepo.On("GetUserByEmailIncludingUnverified", ctx, email).Return(nil, errors.New("database connection error"))
 
 	// Execute
-	err := service.UpdateProfile(ctx, userID, name, email, phone, address, lat, lng, photo)
+	err := service.UpdateProfile(ctx, userID, name, email, phone, address, lat, lng, photo, 0)
 
 	// Assert
 	assert.Error(t, err)
 	assert.Equal(t, "unable to verify email availability", err.Error())
 	mockUserRepo.AssertExpectations(t)
-	mockUserRepo.AssertNotCalled(t, "UpdateUserProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "UpdateUserProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
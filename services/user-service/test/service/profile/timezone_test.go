@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNormalizeTimezone(t *testing.T) {
+	valid := map[string]string{
+		"Asia/Jakarta":    "Asia/Jakarta",
+		" Asia/Makassar ": "Asia/Makassar",
+		"UTC":             "UTC",
+	}
+	for input, want := range valid {
+		got, err := utils.NormalizeTimezone(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	for _, input := range []string{"", "Local", "local", "Asia/Bandung", "+07:00", "../etc/passwd"} {
+		_, err := utils.NormalizeTimezone(input)
+		assert.ErrorIs(t, err, utils.ErrInvalidTimezone, input)
+	}
+}
+
+func TestUserLocation_FallsBackToUTC(t *testing.T) {
+	assert.Equal(t, time.UTC, utils.UserLocation(""))
+	assert.Equal(t, time.UTC, utils.UserLocation("Mars/Olympus"))
+	assert.Equal(t, "Asia/Jayapura", utils.UserLocation("Asia/Jayapura").String())
+}
+
+func TestChangeTimezone(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores a valid timezone", func(t *testing.T) {
+		mockUserRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+
+		mockUserRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7, Timezone: "UTC"}, nil)
+		mockUserRepo.On("UpdateTimezone", ctx, int64(7), "Asia/Jakarta").Return(nil)
+
+		timezone, err := userService.ChangeTimezone(ctx, 7, " Asia/Jakarta ")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Asia/Jakarta", timezone)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects an unknown timezone", func(t *testing.T) {
+		mockUserRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+
+		_, err := userService.ChangeTimezone(ctx, 7, "Asia/Bandung")
+
+		assert.ErrorIs(t, err, utils.ErrInvalidTimezone)
+		mockUserRepo.AssertNotCalled(t, "UpdateTimezone", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("skips the write when nothing changes", func(t *testing.T) {
+		mockUserRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+
+		mockUserRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7, Timezone: "Asia/Jakarta"}, nil)
+
+		timezone, err := userService.ChangeTimezone(ctx, 7, "Asia/Jakarta")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Asia/Jakarta", timezone)
+		mockUserRepo.AssertNotCalled(t, "UpdateTimezone", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("reports a failed write", func(t *testing.T) {
+		mockUserRepo := new(mocks.MockUserRepository)
+		userService := service.NewUserService(service.UserServiceDeps{
+			AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: new(mocks.MockStorage)},
+			Config:          &config.Config{},
+		})
+
+		mockUserRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7}, nil)
+		mockUserRepo.On("UpdateTimezone", ctx, int64(7), "Asia/Makassar").Return(errors.New("connection reset"))
+
+		_, err := userService.ChangeTimezone(ctx, 7, "Asia/Makassar")
+
+		assert.EqualError(t, err, "failed to update timezone")
+	})
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+	"user-service/test/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func serve(limiter *mocks.MockRateLimitRepository, cfg config.RateLimit, path string, headers map[string]string) (*httptest.ResponseRecorder, bool) {
+	e := echo.New()
+	e.IPExtractor = echo.ExtractIPDirect()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = "203.0.113.7:4321"
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+
+	called := false
+	handler := middleware.RateLimitMiddleware(limiter, nil, cfg)(func(c echo.Context) error {
+		called = true
+		return c.NoContent(http.StatusOK)
+	})
+	_ = handler(e.NewContext(req, rec))
+	return rec, called
+}
+
+func TestRateLimit_QuotaHeadersOnEveryResponse(t *testing.T) {
+	limiter := new(mocks.MockRateLimitRepository)
+	resetAt := time.Now().Add(42 * time.Second)
+	limiter.On("Hit", mock.Anything, "ip:203.0.113.7", 300, time.Minute).Return(entity.RateLimitEntity{Limit: 300, Count: 12, ResetAt: resetAt}, nil)
+
+	rec, called := serve(limiter, config.RateLimit{}, "/api/v1/auth/profile", nil)
+
+	assert.True(t, called)
+	assert.Equal(t, "300", rec.Header().Get(middleware.RateLimitLimitHeader))
+	assert.Equal(t, "288", rec.Header().Get(middleware.RateLimitRemainingHeader))
+	assert.Equal(t, strconv.FormatInt(resetAt.Unix(), 10), rec.Header().Get(middleware.RateLimitResetHeader))
+}
+
+func TestRateLimit_SoftModeLetsExceededRequestsThrough(t *testing.T) {
+	limiter := new(mocks.MockRateLimitRepository)
+	limiter.On("Hit", mock.Anything, "ip:203.0.113.7", 10, 30*time.Second).Return(entity.RateLimitEntity{Limit: 10, Count: 11, ResetAt: time.Now().Add(time.Second)}, nil)
+
+	rec, called := serve(limiter, config.RateLimit{Mode: config.RateLimitModeSoft, Requests: 10, WindowSeconds: 30}, "/api/v1/auth/profile", nil)
+
+	assert.True(t, called)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get(middleware.RateLimitRemainingHeader))
+}
+
+func TestRateLimit_EnforceModeRejectsWithRetryAfter(t *testing.T) {
+	limiter := new(mocks.MockRateLimitRepository)
+	limiter.On("Hit", mock.Anything, "ip:203.0.113.7", 10, time.Minute).Return(entity.RateLimitEntity{Limit: 10, Count: 11, ResetAt: time.Now().Add(20 * time.Second)}, nil)
+
+	rec, called := serve(limiter, config.RateLimit{Mode: config.RateLimitModeEnforce, Requests: 10}, "/api/v1/auth/profile", nil)
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "20", rec.Header().Get(echo.HeaderRetryAfter))
+	assert.Equal(t, "0", rec.Header().Get(middleware.RateLimitRemainingHeader))
+}
+
+func TestRateLimit_PartnerKeyUsesItsOwnEnforcedQuota(t *testing.T) {
+	cfg := config.RateLimit{
+		Mode:          config.RateLimitModeSoft,
+		PartnerQuotas: map[string]config.PartnerQuota{"acme-key-1": {Partner: "acme", Requests: 1000}},
+	}
+
+	limiter := new(mocks.MockRateLimitRepository)
+	limiter.On("Hit", mock.Anything, "partner:acme", 1000, 24*time.Hour).Return(entity.RateLimitEntity{Limit: 1000, Count: 1, ResetAt: time.Now().Add(time.Hour)}, nil).Once()
+	rec, called := serve(limiter, cfg, "/api/v1/auth/profile", map[string]string{middleware.APIKeyHeader: "acme-key-1"})
+	assert.True(t, called)
+	assert.Equal(t, "999", rec.Header().Get(middleware.RateLimitRemainingHeader))
+
+	// Soft mode does not apply to partners
+	limiter = new(mocks.MockRateLimitRepository)
+	limiter.On("Hit", mock.Anything, "partner:acme", 1000, 24*time.Hour).Return(entity.RateLimitEntity{Limit: 1000, Count: 1001, ResetAt: time.Now().Add(time.Hour)}, nil)
+	rec, called = serve(limiter, cfg, "/api/v1/auth/profile", map[string]string{middleware.APIKeyHeader: "acme-key-1"})
+	assert.False(t, called)
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+}
+
+func TestRateLimit_UnknownPartnerKeyIsRejected(t *testing.T) {
+	limiter := new(mocks.MockRateLimitRepository)
+	cfg := config.RateLimit{PartnerQuotas: map[string]config.PartnerQuota{"acme-key-1": {Partner: "acme", Requests: 1000}}}
+
+	rec, called := serve(limiter, cfg, "/api/v1/auth/profile", map[string]string{middleware.APIKeyHeader: "guessed-key"})
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	limiter.AssertNotCalled(t, "Hit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRateLimit_RedisErrorFailsOpen(t *testing.T) {
+	limiter := new(mocks.MockRateLimitRepository)
+	limiter.On("Hit", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(entity.RateLimitEntity{}, errors.New("redis: connection refused"))
+
+	rec, called := serve(limiter, config.RateLimit{Mode: config.RateLimitModeEnforce}, "/api/v1/auth/profile", nil)
+
+	assert.True(t, called)
+	assert.Empty(t, rec.Header().Get(middleware.RateLimitLimitHeader))
+}
+
+func TestRateLimit_SkipsProbesAndInternalCalls(t *testing.T) {
+	limiter := new(mocks.MockRateLimitRepository)
+
+	for _, path := range []string{"/health", "/metrics", "/version", "/internal/users/batch"} {
+		_, called := serve(limiter, config.RateLimit{Mode: config.RateLimitModeEnforce}, path, nil)
+		assert.True(t, called, path)
+	}
+	_, called := serve(limiter, config.RateLimit{Mode: config.RateLimitModeOff}, "/api/v1/auth/profile", nil)
+	assert.True(t, called)
+
+	limiter.AssertNotCalled(t, "Hit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRateLimitConfig_Validate(t *testing.T) {
+	assert.NoError(t, config.RateLimit{}.Validate())
+	assert.NoError(t, config.RateLimit{Mode: config.RateLimitModeEnforce}.Validate())
+	assert.EqualError(t, config.RateLimit{Mode: "strict"}.Validate(), `RATE_LIMIT_MODE: unknown mode "strict"`)
+}
+
+func TestRateLimitEntity_Remaining(t *testing.T) {
+	assert.Equal(t, 3, entity.RateLimitEntity{Limit: 5, Count: 2}.Remaining())
+	assert.Equal(t, 0, entity.RateLimitEntity{Limit: 5, Count: 9}.Remaining())
+	assert.False(t, entity.RateLimitEntity{Limit: 5, Count: 5}.Exceeded())
+	assert.True(t, entity.RateLimitEntity{Limit: 5, Count: 6}.Exceeded())
+}
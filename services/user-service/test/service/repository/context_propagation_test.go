@@ -0,0 +1,203 @@
+package main
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"user-service/internal/adapter/repository"
+	"user-service/internal/core/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type ctxMarker struct{}
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// contextRecorder flags every statement whose context is not the one the caller passed in
+type contextRecorder struct {
+	current  string
+	executed int
+	dropped  []string
+}
+
+func (r *contextRecorder) check(tx *gorm.DB) {
+	r.executed++
+	if tx.Statement.Context.Value(ctxMarker{}) == nil {
+		r.dropped = append(r.dropped, r.current+": "+tx.Statement.SQL.String())
+	}
+}
+
+func recordingDB(t *testing.T) (*gorm.DB, *contextRecorder) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost user=test dbname=test sslmode=disable"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	recorder := &contextRecorder{}
+	callbacks := db.Callback()
+	require.NoError(t, callbacks.Create().After("gorm:create").Register("test:context", recorder.check))
+	require.NoError(t, callbacks.Query().After("gorm:query").Register("test:context", recorder.check))
+	require.NoError(t, callbacks.Update().After("gorm:update").Register("test:context", recorder.check))
+	require.NoError(t, callbacks.Delete().After("gorm:delete").Register("test:context", recorder.check))
+	require.NoError(t, callbacks.Row().After("gorm:row").Register("test:context", recorder.check))
+	require.NoError(t, callbacks.Raw().After("gorm:raw").Register("test:context", recorder.check))
+	return db, recorder
+}
+
+// callAll invokes every method of repo with the marked context and zero values for the other
+// arguments. Methods that panic on those values still report the statements they ran first.
+func callAll(recorder *contextRecorder, name string, repo interface{}) {
+	ctx := context.WithValue(context.Background(), ctxMarker{}, true)
+	value := reflect.ValueOf(repo)
+
+	for i := 0; i < value.NumMethod(); i++ {
+		method := value.Method(i)
+		methodType := method.Type()
+		recorder.current = name + "." + value.Type().Method(i).Name
+
+		args := make([]reflect.Value, methodType.NumIn())
+		for j := range args {
+			paramType := methodType.In(j)
+			switch {
+			case paramType == contextType:
+				args[j] = reflect.ValueOf(ctx)
+			case paramType.Kind() == reflect.Pointer && paramType.Elem().Kind() == reflect.Struct:
+				args[j] = reflect.New(paramType.Elem())
+			default:
+				args[j] = reflect.Zero(paramType)
+			}
+		}
+
+		func() {
+			defer func() { _ = recover() }()
+			if methodType.IsVariadic() {
+				method.CallSlice(args)
+			} else {
+				method.Call(args)
+			}
+		}()
+	}
+}
+
+// contextRepositories builds every database-backed repository; the sweep below checks that none
+// is left out
+func contextRepositories(db *gorm.DB) map[string]interface{} {
+	return map[string]interface{}{
+		"APIKeyRepository":               repository.NewAPIKeyRepository(db),
+		"AccountMergeRepository":         repository.NewAccountMergeRepository(db),
+		"AssetRepository":                repository.NewAssetRepository(db),
+		"AuditLogRepository":             repository.NewAuditLogRepository(db),
+		"BlacklistTokenRepository":       repository.NewBlacklistTokenRepository(db),
+		"CustomerImportReportRepository": repository.NewCustomerImportReportRepository(nil),
+		"DeliveryZoneRepository":         repository.NewDeliveryZoneRepository(db),
+		"DeviceRepository":               repository.NewDeviceRepository(db),
+		"EmailRateLimitRepository":       repository.NewEmailRateLimitRepository(db, nil),
+		"FeatureFlagRepository":          repository.NewFeatureFlagRepository(db, nil),
+		"IdentityRepository":             repository.NewIdentityRepository(db),
+		"InviteRepository":               repository.NewInviteRepository(db),
+		"JobRepository":                  repository.NewJobRepository(db),
+		"LedgerRepository":               repository.NewLedgerRepository(db),
+		"LegalRepository":                repository.NewLegalRepository(db),
+		"OnboardingRepository":           repository.NewOnboardingRepository(db),
+		"PhotoModerationRepository":      repository.NewPhotoModerationRepository(db),
+		"RoleRepository":                 repository.NewRoleRepository(db),
+		"SCIMRepository":                 repository.NewSCIMRepository(db),
+		"SSORepository":                  repository.NewSSORepository(db),
+		"SavedViewRepository":            repository.NewSavedViewRepository(db),
+		"SegmentRepository":              repository.NewSegmentRepository(db),
+		"StoredObjectRepository":         repository.NewStoredObjectRepository(db),
+		"SupportRepository":              repository.NewSupportRepository(db),
+		"TrashRepository":                repository.NewTrashRepository(db),
+		"UserRepository":                 repository.NewUserRepository(db),
+		"VendorRepository":               repository.NewVendorRepository(db),
+		"VerificationTokenRepository":    repository.NewVerificationTokenRepository(db),
+		"WebhookRepository":              repository.NewWebhookRepository(db),
+	}
+}
+
+func TestRepositories_PassCallerContextToEveryQuery(t *testing.T) {
+	db, recorder := recordingDB(t)
+
+	repositories := contextRepositories(db)
+
+	names := make([]string, 0, len(repositories))
+	for name := range repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		callAll(recorder, name, repositories[name])
+	}
+
+	// Guard against the sweep silently running nothing
+	assert.Greater(t, recorder.executed, 50)
+	assert.Empty(t, recorder.dropped, "queries built without WithContext(ctx)")
+}
+
+func TestRepositories_RecorderCatchesDroppedContext(t *testing.T) {
+	db, recorder := recordingDB(t)
+	recorder.current = "example"
+
+	db.Where("email = ?", "user@example.com").First(&model.User{})
+
+	require.Len(t, recorder.dropped, 1)
+	assert.Contains(t, recorder.dropped[0], `FROM "users"`)
+}
+
+// Every constructor that takes a *gorm.DB must be in the sweep, so a new repository cannot skip it
+func TestRepositories_SweepCoversEveryConstructor(t *testing.T) {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), "../../../internal/adapter/repository", func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	require.NoError(t, err)
+
+	covered := contextRepositories(nil)
+	var missing []string
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "New") || !strings.HasSuffix(fn.Name.Name, "Repository") {
+					continue
+				}
+				if !takesGormDB(fn) {
+					continue
+				}
+				if _, ok := covered[strings.TrimPrefix(fn.Name.Name, "New")]; !ok {
+					missing = append(missing, fn.Name.Name)
+				}
+			}
+		}
+	}
+	sort.Strings(missing)
+	assert.Empty(t, missing, "add these constructors to contextRepositories")
+}
+
+func takesGormDB(fn *ast.FuncDecl) bool {
+	for _, param := range fn.Type.Params.List {
+		star, ok := param.Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		if sel, ok := star.X.(*ast.SelectorExpr); ok && sel.Sel.Name == "DB" {
+			if pkg, ok := sel.X.(*ast.Ident); ok && pkg.Name == "gorm" {
+				return true
+			}
+		}
+	}
+	return false
+}
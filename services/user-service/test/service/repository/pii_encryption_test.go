@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql/driver"
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"user-service/config"
+	"user-service/internal/adapter/repository"
+	"user-service/internal/core/domain/model"
+	"user-service/utils/pii"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+func newPIIKey(t *testing.T) []byte {
+	key := make([]byte, pii.KeySize)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+// usePIIKeys registers keys by id with keyID as the current one until the test ends
+func usePIIKeys(t *testing.T, keyID string, keys map[string][]byte) {
+	settings := config.PIIEncryption{KeyID: keyID, Keys: map[string]string{}}
+	for id, key := range keys {
+		settings.Keys[id] = base64.StdEncoding.EncodeToString(key)
+	}
+	require.NoError(t, repository.RegisterPIIEncryption(settings))
+	t.Cleanup(func() { require.NoError(t, repository.RegisterPIIEncryption(config.PIIEncryption{})) })
+}
+
+func TestPIICipher_RoundTrip(t *testing.T) {
+	cipher, err := pii.NewCipher("k1", map[string][]byte{"k1": newPIIKey(t)})
+	require.NoError(t, err)
+
+	first, err := cipher.Encrypt("+628123456789")
+	require.NoError(t, err)
+	second, err := cipher.Encrypt("+628123456789")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(first, "enc:v1:k1:"))
+	assert.NotContains(t, first, "628123456789")
+	// A fresh nonce per value, so equal phone numbers cannot be matched in a dump
+	assert.NotEqual(t, first, second)
+
+	plaintext, err := cipher.Decrypt(first)
+	require.NoError(t, err)
+	assert.Equal(t, "+628123456789", plaintext)
+
+	empty, err := cipher.Encrypt("")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestPIICipher_ReadsPlaintextFromBeforeEncryption(t *testing.T) {
+	cipher, err := pii.NewCipher("k1", map[string][]byte{"k1": newPIIKey(t)})
+	require.NoError(t, err)
+
+	plaintext, err := cipher.Decrypt("Jl. Merdeka No. 1")
+	require.NoError(t, err)
+	assert.Equal(t, "Jl. Merdeka No. 1", plaintext)
+	assert.False(t, cipher.Current("Jl. Merdeka No. 1"))
+}
+
+func TestPIICipher_KeyRotation(t *testing.T) {
+	oldKey, newKey := newPIIKey(t), newPIIKey(t)
+	before, err := pii.NewCipher("2025-06", map[string][]byte{"2025-06": oldKey})
+	require.NoError(t, err)
+	sealed, err := before.Encrypt("Jl. Merdeka No. 1")
+	require.NoError(t, err)
+
+	after, err := pii.NewCipher("2026-01", map[string][]byte{"2025-06": oldKey, "2026-01": newKey})
+	require.NoError(t, err)
+	plaintext, err := after.Decrypt(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "Jl. Merdeka No. 1", plaintext)
+	assert.False(t, after.Current(sealed), "values under the retired key are left for the backfill")
+
+	// Once the retired key is dropped its values can no longer be read
+	dropped, err := pii.NewCipher("2026-01", map[string][]byte{"2026-01": newKey})
+	require.NoError(t, err)
+	_, err = dropped.Decrypt(sealed)
+	assert.ErrorIs(t, err, pii.ErrUnknownKey)
+
+	// Without a current key, values still decrypt but new ones are written in plaintext
+	decryptOnly, err := pii.NewCipher("", map[string][]byte{"2025-06": oldKey})
+	require.NoError(t, err)
+	written, err := decryptOnly.Encrypt("+628123456789")
+	require.NoError(t, err)
+	assert.Equal(t, "+628123456789", written)
+	assert.False(t, decryptOnly.Current(sealed))
+}
+
+func TestPIICipher_RejectsTamperedValue(t *testing.T) {
+	cipher, err := pii.NewCipher("k1", map[string][]byte{"k1": newPIIKey(t)})
+	require.NoError(t, err)
+	sealed, err := cipher.Encrypt("+628123456789")
+	require.NoError(t, err)
+
+	tampered := sealed[:len(sealed)-2] + "AA"
+	if tampered == sealed {
+		tampered = sealed[:len(sealed)-2] + "BB"
+	}
+	_, err = cipher.Decrypt(tampered)
+	assert.ErrorContains(t, err, "failed authentication")
+}
+
+func TestNewPIICipher_RejectsBadKeys(t *testing.T) {
+	_, err := pii.NewCipher("k1", map[string][]byte{"k1": make([]byte, 16)})
+	assert.ErrorContains(t, err, "want 32")
+
+	_, err = pii.NewCipher("k2", map[string][]byte{"k1": newPIIKey(t)})
+	assert.ErrorContains(t, err, "not among the configured keys")
+
+	_, err = pii.NewCipher("k:1", map[string][]byte{"k:1": newPIIKey(t)})
+	assert.ErrorContains(t, err, "may only contain")
+}
+
+func TestPIIEncryption_Validate(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(newPIIKey(t))
+
+	assert.NoError(t, config.PIIEncryption{}.Validate())
+	assert.NoError(t, config.PIIEncryption{KeyID: "k1", Keys: map[string]string{"k1": key}}.Validate())
+	assert.NoError(t, config.PIIEncryption{Keys: map[string]string{"k1": key}}.Validate())
+
+	assert.ErrorContains(t, config.PIIEncryption{KeyID: "k2", Keys: map[string]string{"k1": key}}.Validate(), "PII_ENCRYPTION_KEY_ID")
+	assert.ErrorContains(t, config.PIIEncryption{Keys: map[string]string{"k1": "not base64!"}}.Validate(), "PII_ENCRYPTION_KEYS")
+	assert.ErrorContains(t, config.PIIEncryption{Keys: map[string]string{"k1": base64.StdEncoding.EncodeToString([]byte("short"))}}.Validate(), "want 32")
+}
+
+func TestPIITables_CoversTaggedColumns(t *testing.T) {
+	tables, err := repository.PIITables(model.All())
+	require.NoError(t, err)
+
+	byTable := map[string][]string{}
+	for _, table := range tables {
+		byTable[table.Table] = table.Columns
+	}
+	assert.Equal(t, map[string][]string{
+		"users":   {"address", "phone"},
+		"vendors": {"phone", "address"},
+	}, byTable)
+}
+
+// piiDryRunDB records the bound values of every create and update, resolved as the driver sees them
+func piiDryRunDB(t *testing.T) (*gorm.DB, *[]interface{}) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost user=test dbname=test sslmode=disable"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+
+	var values []interface{}
+	record := func(tx *gorm.DB) {
+		for _, v := range tx.Statement.Vars {
+			if valuer, ok := v.(driver.Valuer); ok {
+				resolved, err := valuer.Value()
+				require.NoError(t, err)
+				v = resolved
+			}
+			values = append(values, v)
+		}
+	}
+	require.NoError(t, db.Callback().Create().After("gorm:create").Register("test:values", record))
+	require.NoError(t, db.Callback().Update().After("gorm:update").Register("test:values", record))
+	return db, &values
+}
+
+// sealedValues decrypts the recorded strings that were written encrypted
+func sealedValues(t *testing.T, values []interface{}, key []byte) []string {
+	cipher, err := pii.NewCipher("", map[string][]byte{"k1": key})
+	require.NoError(t, err)
+
+	var plaintexts []string
+	for _, v := range values {
+		s, ok := v.(string)
+		if !ok || !pii.IsEncrypted(s) {
+			continue
+		}
+		plaintext, err := cipher.Decrypt(s)
+		require.NoError(t, err)
+		plaintexts = append(plaintexts, plaintext)
+	}
+	return plaintexts
+}
+
+func TestPIISerializer_EncryptsStructWrites(t *testing.T) {
+	key := newPIIKey(t)
+	usePIIKeys(t, "k1", map[string][]byte{"k1": key})
+	db, values := piiDryRunDB(t)
+
+	require.NoError(t, db.Create(&model.User{Name: "Budi", Email: "budi@example.com", Phone: "+628123456789", Address: "Jl. Merdeka No. 1"}).Error)
+
+	assert.ElementsMatch(t, []string{"+628123456789", "Jl. Merdeka No. 1"}, sealedValues(t, *values, key))
+	assert.NotContains(t, *values, "+628123456789")
+	assert.Contains(t, *values, "Budi", "other columns are untouched")
+}
+
+func TestPIISerializer_EncryptsMapUpdates(t *testing.T) {
+	key := newPIIKey(t)
+	usePIIKeys(t, "k1", map[string][]byte{"k1": key})
+	db, values := piiDryRunDB(t)
+
+	err := repository.NewUserRepository(db).UpdateUserProfile(context.Background(), 1, "Budi", "budi@example.com", "+628123456789", "Jl. Merdeka No. 1", 0, 0, "", 0)
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{"+628123456789", "Jl. Merdeka No. 1"}, sealedValues(t, *values, key))
+	assert.NotContains(t, *values, "Jl. Merdeka No. 1")
+}
+
+func TestPIISerializer_WritesPlaintextWithoutKeys(t *testing.T) {
+	db, values := piiDryRunDB(t)
+
+	require.NoError(t, db.Create(&model.User{Name: "Budi", Phone: "+628123456789"}).Error)
+
+	assert.Contains(t, *values, "+628123456789")
+}
+
+func TestPIISerializer_DecryptsOnScan(t *testing.T) {
+	key := newPIIKey(t)
+	usePIIKeys(t, "k1", map[string][]byte{"k1": key})
+	cipher, err := pii.NewCipher("k1", map[string][]byte{"k1": key})
+	require.NoError(t, err)
+	sealed, err := cipher.Encrypt("+628123456789")
+	require.NoError(t, err)
+
+	s, err := schema.Parse(&model.User{}, &sync.Map{}, schema.NamingStrategy{})
+	require.NoError(t, err)
+	field := s.LookUpField("Phone")
+	require.NotNil(t, field.Serializer)
+
+	var user model.User
+	dst := reflect.ValueOf(&user).Elem()
+	require.NoError(t, field.Serializer.Scan(context.Background(), field, dst, []byte(sealed)))
+	assert.Equal(t, "+628123456789", user.Phone)
+
+	// Rows not yet backfilled and NULL columns read as they are
+	require.NoError(t, field.Serializer.Scan(context.Background(), field, dst, "+628000000000"))
+	assert.Equal(t, "+628000000000", user.Phone)
+	require.NoError(t, field.Serializer.Scan(context.Background(), field, dst, nil))
+	assert.Empty(t, user.Phone)
+
+	// A value under a key that is not configured fails loudly instead of showing ciphertext
+	usePIIKeys(t, "k2", map[string][]byte{"k2": newPIIKey(t)})
+	assert.ErrorIs(t, field.Serializer.Scan(context.Background(), field, dst, sealed), pii.ErrUnknownKey)
+}
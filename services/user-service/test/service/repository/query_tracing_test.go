@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"user-service/internal/adapter/repository"
+	"user-service/utils/querytrace"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeConnPool records the statements it is given; it only supports Exec
+type fakeConnPool struct {
+	statements []string
+	committed  bool
+}
+
+func (p *fakeConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+func (p *fakeConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	p.statements = append(p.statements, query)
+	return driverResult(1), nil
+}
+
+func (p *fakeConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	p.statements = append(p.statements, query)
+	return nil, errors.New("not supported")
+}
+
+func (p *fakeConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	p.statements = append(p.statements, query)
+	return nil
+}
+
+func (p *fakeConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return &fakeTx{fakeConnPool: p}, nil
+}
+
+type fakeTx struct {
+	*fakeConnPool
+}
+
+func (t *fakeTx) Commit() error {
+	t.committed = true
+	return nil
+}
+
+func (t *fakeTx) Rollback() error {
+	return nil
+}
+
+type driverResult int64
+
+func (r driverResult) LastInsertId() (int64, error) { return 0, nil }
+func (r driverResult) RowsAffected() (int64, error) { return int64(r), nil }
+
+func tracedDB(t *testing.T, comments, withRequestID bool) (*gorm.DB, *fakeConnPool) {
+	pool := &fakeConnPool{}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: pool}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	require.NoError(t, repository.RegisterQueryTracing(db, comments, withRequestID))
+	return db, pool
+}
+
+func TestQueryTracing_TagsAndCountsStatements(t *testing.T) {
+	db, pool := tracedDB(t, true, true)
+	trace := querytrace.New("PUT", "/api/v1/users/:id", "req-1")
+	ctx := querytrace.WithTrace(context.Background(), trace)
+
+	require.NoError(t, db.WithContext(ctx).Exec("UPDATE users SET name = ?", "Budi").Error)
+	require.NoError(t, db.WithContext(ctx).Exec("UPDATE users SET phone = ?", "0812").Error)
+
+	require.Len(t, pool.statements, 2)
+	assert.Equal(t, "UPDATE users SET name = $1 /*method='PUT',request_id='req-1',route='%2Fapi%2Fv1%2Fusers%2F%3Aid'*/", pool.statements[0])
+	assert.EqualValues(t, 2, trace.Queries())
+}
+
+func TestQueryTracing_InsideTransactions(t *testing.T) {
+	db, pool := tracedDB(t, true, false)
+	trace := querytrace.New("POST", "/api/v1/orders", "req-2")
+
+	err := db.WithContext(querytrace.WithTrace(context.Background(), trace)).Transaction(func(tx *gorm.DB) error {
+		return tx.Exec("DELETE FROM carts WHERE user_id = ?", 7).Error
+	})
+
+	require.NoError(t, err)
+	assert.True(t, pool.committed)
+	require.Len(t, pool.statements, 1)
+	assert.True(t, strings.HasSuffix(pool.statements[0], "/*method='POST',route='%2Fapi%2Fv1%2Forders'*/"), "route only: %s", pool.statements[0])
+	assert.EqualValues(t, 1, trace.Queries())
+}
+
+func TestQueryTracing_ClientRequestIDCannotCloseComment(t *testing.T) {
+	db, pool := tracedDB(t, true, true)
+	ctx := querytrace.WithTrace(context.Background(), querytrace.New("GET", "/api/v1/users", "x*/; DROP TABLE users; --"))
+
+	require.NoError(t, db.WithContext(ctx).Exec("SELECT 1").Error)
+
+	require.Len(t, pool.statements, 1)
+	assert.Equal(t, 1, strings.Count(pool.statements[0], "*/"))
+	assert.True(t, strings.HasSuffix(pool.statements[0], "*/"))
+}
+
+func TestQueryTracing_UntracedAndDisabled(t *testing.T) {
+	db, pool := tracedDB(t, true, true)
+	require.NoError(t, db.Exec("DELETE FROM jobs").Error)
+	assert.Equal(t, []string{"DELETE FROM jobs"}, pool.statements, "background work has no trace")
+
+	db, pool = tracedDB(t, false, false)
+	trace := querytrace.New("GET", "/api/v1/users", "req-3")
+	require.NoError(t, db.WithContext(querytrace.WithTrace(context.Background(), trace)).Exec("DELETE FROM jobs").Error)
+	assert.Equal(t, []string{"DELETE FROM jobs"}, pool.statements)
+	assert.EqualValues(t, 1, trace.Queries(), "statements are counted with comments off")
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"testing"
+	"time"
+	"user-service/internal/adapter/repository"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// shop is shaped like the old users model, whose lat/lng were strings on numeric columns
+type shop struct {
+	ID        int64 `gorm:"PrimaryKey"`
+	Name      string
+	Email     string `gorm:"unique"`
+	Lat       string
+	Lng       *float64
+	OwnerID   int64  `gorm:"index"`
+	Settings  string `gorm:"type:jsonb"`
+	Note      *string
+	Code      string `gorm:"not null"`
+	CreatedAt time.Time
+}
+
+func liveShops() entity.TableSchemaEntity {
+	return entity.TableSchemaEntity{
+		Name:   "shops",
+		Exists: true,
+		Columns: map[string]entity.ColumnSchemaEntity{
+			"id":         {Type: "int4"},
+			"name":       {Type: "varchar"},
+			"email":      {Type: "varchar", Unique: true},
+			"lat":        {Type: "numeric", Nullable: true},
+			"lng":        {Type: "numeric", Nullable: true},
+			"owner_id":   {Type: "int8"},
+			"settings":   {Type: "jsonb"},
+			"note":       {Type: "text", Nullable: true},
+			"code":       {Type: "varchar"},
+			"created_at": {Type: "timestamp", Nullable: true},
+		},
+		Indexes: []entity.IndexSchemaEntity{
+			{Name: "idx_shops_owner_id", Columns: []string{"owner_id"}},
+		},
+	}
+}
+
+func kinds(drifts []entity.SchemaDriftEntity) map[string]string {
+	found := make(map[string]string)
+	for _, drift := range drifts {
+		found[drift.Column] = drift.Kind
+	}
+	return found
+}
+
+func TestCompareSchema_MatchingTable(t *testing.T) {
+	drifts, err := repository.CompareSchema(&shop{}, liveShops())
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"lat": entity.SchemaDriftTypeMismatch}, kinds(drifts))
+}
+
+func TestCompareSchema_ReportsEachKind(t *testing.T) {
+	live := liveShops()
+	delete(live.Columns, "name")
+	live.Columns["note"] = entity.ColumnSchemaEntity{Type: "text"}
+	live.Columns["code"] = entity.ColumnSchemaEntity{Type: "varchar", Nullable: true}
+	live.Columns["email"] = entity.ColumnSchemaEntity{Type: "varchar"}
+	live.Columns["location"] = entity.ColumnSchemaEntity{Type: "geography", Nullable: true}
+	live.Indexes = nil
+
+	drifts, err := repository.CompareSchema(&shop{}, live)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"name":     entity.SchemaDriftMissingColumn,
+		"lat":      entity.SchemaDriftTypeMismatch,
+		"note":     entity.SchemaDriftNullability,
+		"code":     entity.SchemaDriftNullability,
+		"email":    entity.SchemaDriftMissingIndex,
+		"owner_id": entity.SchemaDriftMissingIndex,
+		"location": entity.SchemaDriftUnmappedColumn,
+	}, kinds(drifts))
+
+	for _, drift := range drifts {
+		assert.Equal(t, drift.Kind == entity.SchemaDriftUnmappedColumn, drift.Harmless(), drift.Column)
+	}
+}
+
+func TestCompareSchema_MissingTable(t *testing.T) {
+	drifts, err := repository.CompareSchema(&shop{}, entity.TableSchemaEntity{})
+
+	require.NoError(t, err)
+	require.Len(t, drifts, 1)
+	assert.Equal(t, entity.SchemaDriftMissingTable, drifts[0].Kind)
+	assert.Equal(t, "shops", drifts[0].Table)
+}
+
+func TestCompareSchema_EveryRegisteredModelParses(t *testing.T) {
+	tables := make(map[string]bool)
+	for _, m := range model.All() {
+		drifts, err := repository.CompareSchema(m, entity.TableSchemaEntity{})
+		require.NoError(t, err)
+		require.Len(t, drifts, 1)
+		assert.False(t, tables[drifts[0].Table], "%s is registered twice", drifts[0].Table)
+		tables[drifts[0].Table] = true
+	}
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"user-service/internal/adapter/repository"
+	"user-service/internal/core/domain/entity"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// violatingConnPool fails every statement with a unique_violation on one constraint
+type violatingConnPool struct {
+	constraint string
+}
+
+func (p *violatingConnPool) violation() error {
+	return &pgconn.PgError{Severity: "ERROR", Code: "23505", ConstraintName: p.constraint, Message: "duplicate key value violates unique constraint"}
+}
+
+func (p *violatingConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, p.violation()
+}
+
+func (p *violatingConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return nil, p.violation()
+}
+
+func (p *violatingConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return nil, p.violation()
+}
+
+func (p *violatingConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return nil
+}
+
+func (p *violatingConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	return &violatingTx{violatingConnPool: p}, nil
+}
+
+type violatingTx struct {
+	*violatingConnPool
+}
+
+func (t *violatingTx) Commit() error   { return nil }
+func (t *violatingTx) Rollback() error { return nil }
+
+func violatingUserRepository(t *testing.T, constraint string) *repository.UserRepository {
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: &violatingConnPool{constraint: constraint}}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	require.NoError(t, err)
+	return repository.NewUserRepository(db).(*repository.UserRepository)
+}
+
+func TestUserRepository_EmailUniqueViolation(t *testing.T) {
+	repo := violatingUserRepository(t, "idx_users_email_active")
+	ctx := context.Background()
+
+	_, err := repo.CreateUser(ctx, &entity.UserEntity{Name: "Budi", Email: "budi@example.com"})
+	assert.EqualError(t, err, "email already exists")
+
+	err = repo.UpdateUserEmail(ctx, 1, "budi@example.com")
+	assert.EqualError(t, err, "email already exists")
+
+	err = repo.UpdateUserProfile(ctx, 1, "Budi", "budi@example.com", "", "", 0, 0, "", 0)
+	assert.EqualError(t, err, "email already exists")
+}
+
+func TestUserRepository_OtherUniqueViolationIsNotEmail(t *testing.T) {
+	repo := violatingUserRepository(t, "idx_users_username")
+	ctx := context.Background()
+
+	_, err := repo.CreateUser(ctx, &entity.UserEntity{Name: "Budi", Email: "budi@example.com"})
+	require.Error(t, err)
+	assert.NotEqual(t, "email already exists", err.Error())
+
+	err = repo.UpdateUserEmail(ctx, 1, "budi@example.com")
+	require.Error(t, err)
+	assert.NotEqual(t, "email already exists", err.Error())
+
+	err = repo.UpdateUserProfile(ctx, 1, "Budi", "budi@example.com", "", "", 0, 0, "", 0)
+	require.Error(t, err)
+	assert.NotEqual(t, "email already exists", err.Error())
+
+	var pgErr *pgconn.PgError
+	assert.ErrorAs(t, err, &pgErr, "the driver error is passed through for the caller to log")
+
+	err = repo.UpdateUsername(ctx, 1, "budi")
+	assert.EqualError(t, err, "username already taken")
+}
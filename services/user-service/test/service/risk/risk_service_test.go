@@ -0,0 +1,241 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type riskFixture struct {
+	riskRepo   *mocks.MockRiskRepository
+	deviceRepo *mocks.MockDeviceRepository
+	auditRepo  *mocks.MockAuditLogRepository
+	ipLocator  *mocks.MockIPLocator
+	email      *mocks.MockEmailPublisher
+	service    port.RiskServiceInterface
+}
+
+func newRiskFixture() *riskFixture {
+	f := &riskFixture{
+		riskRepo:   new(mocks.MockRiskRepository),
+		deviceRepo: new(mocks.MockDeviceRepository),
+		auditRepo:  new(mocks.MockAuditLogRepository),
+		ipLocator:  new(mocks.MockIPLocator),
+		email:      new(mocks.MockEmailPublisher),
+	}
+	cfg := &config.Config{Risk: config.Risk{StepUpEnabled: true}}
+	f.service = service.NewRiskService(f.riskRepo, f.deviceRepo, service.NewAuditLogService(f.auditRepo), f.ipLocator, f.email, cfg)
+	f.auditRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	return f
+}
+
+var (
+	riskUser   = &entity.UserEntity{ID: 7, Email: "buyer@example.com", RoleName: "Customer"}
+	riskClient = entity.ClientEntity{UserAgent: "Mozilla/5.0 Firefox", IPAddress: "203.0.113.9"}
+)
+
+func TestRiskService_Assess_KnownDeviceIsLowRisk(t *testing.T) {
+	ctx := context.Background()
+	f := newRiskFixture()
+
+	f.deviceRepo.On("FindDevice", ctx, int64(7), riskClient.Fingerprint()).Return(&entity.DeviceEntity{ID: 1}, nil)
+	f.riskRepo.On("GetFailedSignIns", ctx, "buyer@example.com").Return(int64(0), nil)
+	f.ipLocator.On("Locate", ctx, "203.0.113.9").Return(&entity.LoginLocationEntity{Lat: -6.2, Lng: 106.8, At: time.Now()}, nil)
+	f.riskRepo.On("GetLastLocation", ctx, int64(7)).Return(nil, nil)
+
+	assessment := f.service.Assess(ctx, riskUser, riskClient)
+
+	assert.Equal(t, 0, assessment.Score)
+	assert.False(t, assessment.RequiresStepUp)
+	assert.NotNil(t, assessment.Location)
+	f.auditRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestRiskService_Assess_NewDeviceAfterFailuresRequiresStepUp(t *testing.T) {
+	ctx := context.Background()
+	f := newRiskFixture()
+
+	f.deviceRepo.On("FindDevice", ctx, int64(7), riskClient.Fingerprint()).Return(nil, gorm.ErrRecordNotFound)
+	f.deviceRepo.On("CountDevices", ctx, int64(7)).Return(int64(2), nil)
+	f.riskRepo.On("GetFailedSignIns", ctx, "buyer@example.com").Return(int64(3), nil)
+	f.ipLocator.On("Locate", ctx, "203.0.113.9").Return(nil, errors.New("location not found"))
+
+	assessment := f.service.Assess(ctx, riskUser, riskClient)
+
+	assert.Equal(t, 50, assessment.Score)
+	assert.ElementsMatch(t, []string{entity.RiskSignalNewDevice, entity.RiskSignalFailedAttempts}, assessment.Signals)
+	assert.True(t, assessment.RequiresStepUp)
+	f.auditRepo.AssertCalled(t, "Create", ctx, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == entity.AuditEventSignInRisk && auditLog.UserID == 7 && auditLog.IPAddress == "203.0.113.9"
+	}))
+}
+
+func TestRiskService_Assess_FirstDeviceIsNotNew(t *testing.T) {
+	ctx := context.Background()
+	f := newRiskFixture()
+
+	f.deviceRepo.On("FindDevice", ctx, int64(7), riskClient.Fingerprint()).Return(nil, gorm.ErrRecordNotFound)
+	f.deviceRepo.On("CountDevices", ctx, int64(7)).Return(int64(0), nil)
+	f.riskRepo.On("GetFailedSignIns", ctx, "buyer@example.com").Return(int64(0), nil)
+	f.ipLocator.On("Locate", ctx, "203.0.113.9").Return(nil, errors.New("location not found"))
+
+	assessment := f.service.Assess(ctx, riskUser, riskClient)
+
+	assert.Equal(t, 0, assessment.Score)
+}
+
+func TestRiskService_Assess_ImpossibleTravel(t *testing.T) {
+	ctx := context.Background()
+	f := newRiskFixture()
+
+	f.deviceRepo.On("FindDevice", ctx, int64(7), riskClient.Fingerprint()).Return(&entity.DeviceEntity{ID: 1}, nil)
+	f.riskRepo.On("GetFailedSignIns", ctx, "buyer@example.com").Return(int64(0), nil)
+	// Jakarta now, London an hour ago: ~11,700 km in one hour
+	f.ipLocator.On("Locate", ctx, "203.0.113.9").Return(&entity.LoginLocationEntity{Lat: -6.2, Lng: 106.8, At: time.Now()}, nil)
+	f.riskRepo.On("GetLastLocation", ctx, int64(7)).Return(&entity.LoginLocationEntity{Lat: 51.5, Lng: -0.12, At: time.Now().Add(-time.Hour)}, nil)
+
+	assessment := f.service.Assess(ctx, riskUser, riskClient)
+
+	assert.Equal(t, []string{entity.RiskSignalImpossibleTravel}, assessment.Signals)
+	assert.True(t, assessment.RequiresStepUp)
+}
+
+func TestRiskService_Assess_StepUpDisabled(t *testing.T) {
+	ctx := context.Background()
+	f := newRiskFixture()
+	f.service = service.NewRiskService(f.riskRepo, f.deviceRepo, service.NewAuditLogService(f.auditRepo), nil, f.email, &config.Config{})
+
+	f.deviceRepo.On("FindDevice", ctx, int64(7), riskClient.Fingerprint()).Return(nil, gorm.ErrRecordNotFound)
+	f.deviceRepo.On("CountDevices", ctx, int64(7)).Return(int64(1), nil)
+	f.riskRepo.On("GetFailedSignIns", ctx, "buyer@example.com").Return(int64(6), nil)
+
+	assessment := f.service.Assess(ctx, riskUser, riskClient)
+
+	assert.Equal(t, 70, assessment.Score)
+	assert.False(t, assessment.RequiresStepUp)
+}
+
+func TestRiskService_StepUp_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	f := newRiskFixture()
+
+	var saved *entity.StepUpChallengeEntity
+	var code string
+	f.riskRepo.On("SaveChallenge", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*entity.StepUpChallengeEntity)
+	}).Return(nil)
+	f.email.On("SendSignInOTPEmail", ctx, "buyer@example.com", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Run(func(args mock.Arguments) {
+		code = args.String(2)
+	}).Return(nil)
+
+	challenge, err := f.service.StartStepUp(ctx, riskUser, riskClient, &entity.RiskAssessmentEntity{Score: 50, Signals: []string{entity.RiskSignalNewDevice}}, true)
+	assert.NoError(t, err)
+	assert.Len(t, code, 6)
+	assert.NotEqual(t, code, saved.CodeHash)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), challenge.ExpiresAt, time.Minute)
+
+	f.riskRepo.On("GetChallenge", ctx, challenge.ID).Return(saved, nil)
+	f.riskRepo.On("DeleteChallenge", ctx, challenge.ID).Return(nil)
+
+	verified, err := f.service.VerifyStepUp(ctx, challenge.ID, code, riskClient)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), verified.UserID)
+	assert.True(t, verified.RememberMe)
+	f.riskRepo.AssertCalled(t, "DeleteChallenge", ctx, challenge.ID)
+}
+
+func TestRiskService_VerifyStepUp_WrongCodeLocksOut(t *testing.T) {
+	ctx := context.Background()
+	f := newRiskFixture()
+
+	challenge := &entity.StepUpChallengeEntity{ID: "challenge-1", UserID: 7, CodeHash: "not-a-real-hash", Attempts: 3}
+	f.riskRepo.On("GetChallenge", ctx, "challenge-1").Return(challenge, nil)
+	f.riskRepo.On("IncrementChallengeAttempts", ctx, "challenge-1").Return(4, nil).Once()
+
+	_, err := f.service.VerifyStepUp(ctx, "challenge-1", "123456", riskClient)
+	assert.EqualError(t, err, "invalid verification code")
+
+	f.riskRepo.On("IncrementChallengeAttempts", ctx, "challenge-1").Return(5, nil).Once()
+	f.riskRepo.On("DeleteChallenge", ctx, "challenge-1").Return(nil)
+
+	_, err = f.service.VerifyStepUp(ctx, "challenge-1", "123456", riskClient)
+	assert.EqualError(t, err, "too many verification attempts")
+	f.riskRepo.AssertCalled(t, "DeleteChallenge", ctx, "challenge-1")
+}
+
+func TestRiskService_VerifyStepUp_ExpiredChallenge(t *testing.T) {
+	ctx := context.Background()
+	f := newRiskFixture()
+
+	f.riskRepo.On("GetChallenge", ctx, "gone").Return(nil, errors.New("challenge not found"))
+
+	_, err := f.service.VerifyStepUp(ctx, "gone", "123456", riskClient)
+	assert.EqualError(t, err, "verification challenge not found")
+}
+
+func TestAuthService_SignIn_RiskyLoginHoldsSession(t *testing.T) {
+	ctx := context.Background()
+	f := newRiskFixture()
+	userRepo := new(mocks.MockUserRepository)
+	sessionRepo := new(mocks.MockSessionRepository)
+	authService := service.NewAuthService(service.AuthServiceDeps{
+		UserRepo:       userRepo,
+		SessionRepo:    sessionRepo,
+		EmailPublisher: f.email,
+		DeviceRepo:     f.deviceRepo,
+		RiskService:    f.service,
+	})
+
+	hashedPassword, _ := utils.HashPassword("password123")
+	userRepo.On("GetUserByEmail", ctx, "buyer@example.com").Return(&entity.UserEntity{ID: 7, Email: "buyer@example.com", Password: hashedPassword}, nil)
+	f.deviceRepo.On("FindDevice", ctx, int64(7), riskClient.Fingerprint()).Return(&entity.DeviceEntity{ID: 1}, nil)
+	f.riskRepo.On("GetFailedSignIns", ctx, "buyer@example.com").Return(int64(0), nil)
+	f.ipLocator.On("Locate", ctx, "203.0.113.9").Return(&entity.LoginLocationEntity{Lat: -6.2, Lng: 106.8, At: time.Now()}, nil)
+	f.riskRepo.On("GetLastLocation", ctx, int64(7)).Return(&entity.LoginLocationEntity{Lat: 51.5, Lng: -0.12, At: time.Now().Add(-time.Hour)}, nil)
+	f.riskRepo.On("SaveChallenge", ctx, mock.Anything).Return(nil)
+	f.email.On("SendSignInOTPEmail", ctx, "buyer@example.com", mock.Anything, mock.Anything).Return(nil)
+
+	user, token, err := authService.SignIn(ctx, entity.UserEntity{Email: "buyer@example.com", Password: "password123"}, riskClient, false)
+
+	var stepUp *entity.StepUpRequiredError
+	assert.ErrorAs(t, err, &stepUp)
+	assert.NotEmpty(t, stepUp.ChallengeID)
+	assert.Nil(t, user)
+	assert.Empty(t, token)
+	sessionRepo.AssertNotCalled(t, "StoreToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthService_SignIn_WrongPasswordRecordsFailure(t *testing.T) {
+	ctx := context.Background()
+	f := newRiskFixture()
+	userRepo := new(mocks.MockUserRepository)
+	authService := service.NewAuthService(service.AuthServiceDeps{
+		UserRepo:       userRepo,
+		EmailPublisher: f.email,
+		DeviceRepo:     f.deviceRepo,
+		RiskService:    f.service,
+	})
+
+	hashedPassword, _ := utils.HashPassword("password123")
+	userRepo.On("GetUserByEmail", ctx, "buyer@example.com").Return(&entity.UserEntity{ID: 7, Email: "buyer@example.com", Password: hashedPassword}, nil)
+	f.riskRepo.On("IncrementFailedSignIns", ctx, "buyer@example.com", 15*time.Minute).Return(int64(1), nil)
+
+	_, _, err := authService.SignIn(ctx, entity.UserEntity{Email: "buyer@example.com", Password: "wrong-password"}, riskClient, false)
+
+	assert.EqualError(t, err, "incorrect password")
+	f.riskRepo.AssertExpectations(t)
+	f.auditRepo.AssertCalled(t, "Create", ctx, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == entity.AuditEventSignInFailed
+	}))
+}
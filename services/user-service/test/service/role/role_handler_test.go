@@ -30,7 +30,7 @@ func TestRoleHandler_GetAllRoles_Success(t *testing.T) {
 		{ID: 1, Name: "Super Admin"},
 		{ID: 2, Name: "Customer"},
 	}
-	mockRoleService.On("GetAllRoles", mock.Anything, "").Return(expectedRoles, nil)
+	mockRoleService.On("GetAllRoles", mock.Anything, "", 0, 0).Return(expectedRoles, &entity.PaginationEntity{Page: 1, TotalCount: 2, PerPage: 10, TotalPage: 1}, nil)
 
 	// Test handler
 	roleHandler := handler.NewRoleHandler(mockRoleService)
@@ -74,7 +74,7 @@ func TestRoleHandler_GetAllRoles_WithSearch(t *testing.T) {
 	expectedRoles := []entity.RoleEntity{
 		{ID: 1, Name: "Super Admin"},
 	}
-	mockRoleService.On("GetAllRoles", mock.Anything, "admin").Return(expectedRoles, nil)
+	mockRoleService.On("GetAllRoles", mock.Anything, "admin", 0, 0).Return(expectedRoles, &entity.PaginationEntity{Page: 1, TotalCount: 1, PerPage: 10, TotalPage: 1}, nil)
 
 	// Test handler
 	roleHandler := handler.NewRoleHandler(mockRoleService)
@@ -110,7 +110,7 @@ func TestRoleHandler_GetAllRoles_EmptyResult(t *testing.T) {
 	// Setup mocks
 	mockRoleService := &mocks.MockRoleService{}
 	expectedRoles := []entity.RoleEntity{}
-	mockRoleService.On("GetAllRoles", mock.Anything, "nonexistent").Return(expectedRoles, nil)
+	mockRoleService.On("GetAllRoles", mock.Anything, "nonexistent", 0, 0).Return(expectedRoles, &entity.PaginationEntity{Page: 1, TotalCount: 0, PerPage: 10, TotalPage: 0}, nil)
 
 	// Test handler
 	roleHandler := handler.NewRoleHandler(mockRoleService)
@@ -370,7 +370,7 @@ func TestRoleHandler_CreateRole_ValidationFailed(t *testing.T) {
 func TestRoleHandler_UpdateRole_Success(t *testing.T) {
 	// Setup Echo
 	e := echo.New()
-	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/roles/1", strings.NewReader(`{"name":"Updated Admin"}`))
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/roles/1", strings.NewReader(`{"name":"Updated Admin","version":1}`))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
@@ -384,7 +384,7 @@ func TestRoleHandler_UpdateRole_Success(t *testing.T) {
 		ID:   1,
 		Name: "Updated Admin",
 	}
-	mockRoleService.On("UpdateRole", mock.Anything, int64(1), "Updated Admin").Return(updatedRole, nil)
+	mockRoleService.On("UpdateRole", mock.Anything, int64(1), "Updated Admin", int64(1)).Return(updatedRole, nil)
 
 	// Test handler
 	roleHandler := handler.NewRoleHandler(mockRoleService)
@@ -406,7 +406,7 @@ func TestRoleHandler_UpdateRole_Success(t *testing.T) {
 func TestRoleHandler_UpdateRole_InvalidID(t *testing.T) {
 	// Setup Echo
 	e := echo.New()
-	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/roles/abc", strings.NewReader(`{"name":"Updated Admin"}`))
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/roles/abc", strings.NewReader(`{"name":"Updated Admin","version":1}`))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
@@ -431,7 +431,7 @@ func TestRoleHandler_UpdateRole_InvalidID(t *testing.T) {
 	assert.Equal(t, "Invalid role ID format", response["message"])
 	assert.Nil(t, response["data"])
 
-	mockRoleService.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything)
+	mockRoleService.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestRoleHandler_UpdateRole_InvalidJSON(t *testing.T) {
@@ -462,13 +462,13 @@ func TestRoleHandler_UpdateRole_InvalidJSON(t *testing.T) {
 	assert.Equal(t, "Invalid request format", response["message"])
 	assert.Nil(t, response["data"])
 
-	mockRoleService.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything)
+	mockRoleService.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestRoleHandler_UpdateRole_NotFound(t *testing.T) {
 	// Setup Echo
 	e := echo.New()
-	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/roles/999", strings.NewReader(`{"name":"Updated Admin"}`))
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/roles/999", strings.NewReader(`{"name":"Updated Admin","version":1}`))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
@@ -478,7 +478,7 @@ func TestRoleHandler_UpdateRole_NotFound(t *testing.T) {
 
 	// Setup mocks
 	mockRoleService := &mocks.MockRoleService{}
-	mockRoleService.On("UpdateRole", mock.Anything, int64(999), "Updated Admin").Return(nil, errors.New("role not found"))
+	mockRoleService.On("UpdateRole", mock.Anything, int64(999), "Updated Admin", int64(1)).Return(nil, errors.New("role not found"))
 
 	// Test handler
 	roleHandler := handler.NewRoleHandler(mockRoleService)
@@ -525,13 +525,13 @@ func TestRoleHandler_UpdateRole_ValidationFailed(t *testing.T) {
 	assert.Equal(t, "Name is required", response["message"])
 	assert.Nil(t, response["data"])
 
-	mockRoleService.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything)
+	mockRoleService.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 }
 
 func TestRoleHandler_UpdateRole_DuplicateName(t *testing.T) {
 	// Setup Echo
 	e := echo.New()
-	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/roles/1", strings.NewReader(`{"name":"Customer"}`))
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/roles/1", strings.NewReader(`{"name":"Customer","version":1}`))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
@@ -541,7 +541,7 @@ func TestRoleHandler_UpdateRole_DuplicateName(t *testing.T) {
 
 	// Setup mocks
 	mockRoleService := &mocks.MockRoleService{}
-	mockRoleService.On("UpdateRole", mock.Anything, int64(1), "Customer").Return(nil, errors.New("role with name 'Customer' already exists"))
+	mockRoleService.On("UpdateRole", mock.Anything, int64(1), "Customer", int64(1)).Return(nil, errors.New("role with name 'Customer' already exists"))
 
 	// Test handler
 	roleHandler := handler.NewRoleHandler(mockRoleService)
@@ -563,7 +563,7 @@ func TestRoleHandler_UpdateRole_DuplicateName(t *testing.T) {
 func TestRoleHandler_UpdateRole_ServiceError(t *testing.T) {
 	// Setup Echo
 	e := echo.New()
-	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/roles/1", strings.NewReader(`{"name":"Updated Admin"}`))
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/roles/1", strings.NewReader(`{"name":"Updated Admin","version":1}`))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
 	rec := httptest.NewRecorder()
 	c := e.NewContext(req, rec)
@@ -573,7 +573,7 @@ func TestRoleHandler_UpdateRole_ServiceError(t *testing.T) {
 
 	// Setup mocks
 	mockRoleService := &mocks.MockRoleService{}
-	mockRoleService.On("UpdateRole", mock.Anything, int64(1), "Updated Admin").Return(nil, assert.AnError)
+	mockRoleService.On("UpdateRole", mock.Anything, int64(1), "Updated Admin", int64(1)).Return(nil, assert.AnError)
 
 	// Test handler
 	roleHandler := handler.NewRoleHandler(mockRoleService)
@@ -755,7 +755,7 @@ func TestRoleHandler_GetAllRoles_ServiceError(t *testing.T) {
 
 	// Setup mocks
 	mockRoleService := &mocks.MockRoleService{}
-	mockRoleService.On("GetAllRoles", mock.Anything, "").Return(nil, assert.AnError)
+	mockRoleService.On("GetAllRoles", mock.Anything, "", 0, 0).Return(nil, nil, assert.AnError)
 
 	// Test handler
 	roleHandler := handler.NewRoleHandler(mockRoleService)
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func roleID(id int64) *int64 {
+	return &id
+}
+
+// Admin <- Super Admin <- Owner; Customer stands alone
+func hierarchyRoles() []entity.RoleEntity {
+	return []entity.RoleEntity{
+		{ID: 1, Name: "Admin"},
+		{ID: 2, Name: "Super Admin", ParentID: roleID(1)},
+		{ID: 3, Name: "Owner", ParentID: roleID(2)},
+		{ID: 4, Name: "Customer"},
+	}
+}
+
+func TestRoleService_SetRoleParent_Success(t *testing.T) {
+	ctx := context.Background()
+	mockRoleRepo := &mocks.MockRoleRepository{}
+	roleService := service.NewRoleService(mockRoleRepo)
+
+	mockRoleRepo.On("GetRoleByID", ctx, int64(4)).Return(&entity.RoleEntity{ID: 4, Name: "Customer", Version: 2}, nil)
+	mockRoleRepo.On("GetAllRoles", ctx, "").Return(hierarchyRoles(), nil)
+	mockRoleRepo.On("UpdateRoleParent", ctx, int64(4), roleID(1), int64(2)).Return(&entity.RoleEntity{ID: 4, Name: "Customer", ParentID: roleID(1), Version: 3}, nil)
+
+	role, err := roleService.SetRoleParent(ctx, 4, roleID(1), 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), role.Version)
+	mockRoleRepo.AssertExpectations(t)
+}
+
+func TestRoleService_SetRoleParent_RejectsCycle(t *testing.T) {
+	ctx := context.Background()
+	mockRoleRepo := &mocks.MockRoleRepository{}
+	roleService := service.NewRoleService(mockRoleRepo)
+
+	// Admin under Owner would make Admin its own ancestor
+	mockRoleRepo.On("GetRoleByID", ctx, int64(1)).Return(&entity.RoleEntity{ID: 1, Name: "Admin", Version: 1}, nil)
+	mockRoleRepo.On("GetAllRoles", ctx, "").Return(hierarchyRoles(), nil)
+
+	_, err := roleService.SetRoleParent(ctx, 1, roleID(3), 1)
+
+	assert.EqualError(t, err, "role hierarchy cannot contain a cycle")
+	mockRoleRepo.AssertNotCalled(t, "UpdateRoleParent", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRoleService_SetRoleParent_RejectsSelfAndMissingParent(t *testing.T) {
+	ctx := context.Background()
+	mockRoleRepo := &mocks.MockRoleRepository{}
+	roleService := service.NewRoleService(mockRoleRepo)
+
+	mockRoleRepo.On("GetRoleByID", ctx, int64(4)).Return(&entity.RoleEntity{ID: 4, Name: "Customer", Version: 1}, nil)
+	mockRoleRepo.On("GetAllRoles", ctx, "").Return(hierarchyRoles(), nil)
+
+	_, err := roleService.SetRoleParent(ctx, 4, roleID(4), 1)
+	assert.EqualError(t, err, "role cannot inherit from itself")
+
+	_, err = roleService.SetRoleParent(ctx, 4, roleID(99), 1)
+	assert.EqualError(t, err, "parent role not found")
+}
+
+func TestRoleService_SetRoleParent_ClearsParentWithoutLoadingRoles(t *testing.T) {
+	ctx := context.Background()
+	mockRoleRepo := &mocks.MockRoleRepository{}
+	roleService := service.NewRoleService(mockRoleRepo)
+
+	mockRoleRepo.On("GetRoleByID", ctx, int64(2)).Return(&entity.RoleEntity{ID: 2, Name: "Super Admin", ParentID: roleID(1), Version: 1}, nil)
+	mockRoleRepo.On("UpdateRoleParent", ctx, int64(2), (*int64)(nil), int64(1)).Return(&entity.RoleEntity{ID: 2, Name: "Super Admin", Version: 2}, nil)
+
+	_, err := roleService.SetRoleParent(ctx, 2, nil, 1)
+
+	require.NoError(t, err)
+	mockRoleRepo.AssertNotCalled(t, "GetAllRoles", mock.Anything, mock.Anything)
+}
+
+func TestRoleService_SetRoleParent_StaleVersion(t *testing.T) {
+	ctx := context.Background()
+	mockRoleRepo := &mocks.MockRoleRepository{}
+	roleService := service.NewRoleService(mockRoleRepo)
+
+	mockRoleRepo.On("GetRoleByID", ctx, int64(4)).Return(&entity.RoleEntity{ID: 4, Name: "Customer", Version: 5}, nil)
+
+	_, err := roleService.SetRoleParent(ctx, 4, roleID(1), 3)
+
+	var conflict *entity.VersionConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.Equal(t, int64(5), conflict.CurrentVersion)
+}
+
+func TestRoleService_EffectiveRoles_WalksAncestorsOnce(t *testing.T) {
+	ctx := context.Background()
+	mockRoleRepo := &mocks.MockRoleRepository{}
+	roleService := service.NewRoleService(mockRoleRepo)
+
+	mockRoleRepo.On("GetAllRoles", ctx, "").Return(hierarchyRoles(), nil).Once()
+
+	assert.Equal(t, []string{"Owner", "Super Admin", "Admin"}, roleService.EffectiveRoles(ctx, "Owner"))
+	assert.Equal(t, []string{"Customer"}, roleService.EffectiveRoles(ctx, "Customer"))
+	mockRoleRepo.AssertNumberOfCalls(t, "GetAllRoles", 1)
+}
+
+func TestRoleService_EffectiveRoles_StopsAtCycleInStoredData(t *testing.T) {
+	ctx := context.Background()
+	mockRoleRepo := &mocks.MockRoleRepository{}
+	roleService := service.NewRoleService(mockRoleRepo)
+
+	mockRoleRepo.On("GetAllRoles", ctx, "").Return([]entity.RoleEntity{
+		{ID: 1, Name: "Admin", ParentID: roleID(2)},
+		{ID: 2, Name: "Super Admin", ParentID: roleID(1)},
+	}, nil)
+
+	assert.Equal(t, []string{"Admin", "Super Admin"}, roleService.EffectiveRoles(ctx, "Admin"))
+}
+
+func TestRoleService_EffectiveRoles_FallsBackToOwnRole(t *testing.T) {
+	ctx := context.Background()
+	mockRoleRepo := &mocks.MockRoleRepository{}
+	roleService := service.NewRoleService(mockRoleRepo)
+
+	mockRoleRepo.On("GetAllRoles", ctx, "").Return(nil, errors.New("connection refused"))
+
+	assert.Equal(t, []string{"Owner"}, roleService.EffectiveRoles(ctx, "Owner"))
+}
@@ -20,16 +20,17 @@ func TestRoleService_GetAllRoles_Success(t *testing.T) {
 		{ID: 1, Name: "Super Admin"},
 		{ID: 2, Name: "Customer"},
 	}
-	mockRoleRepo.On("GetAllRoles", mock.Anything, "").Return(expectedRoles, nil)
+	mockRoleRepo.On("GetRoles", mock.Anything, "", 1, 10).Return(expectedRoles, int64(2), nil)
 
 	// Test service
 	roleService := service.NewRoleService(mockRoleRepo)
-	roles, err := roleService.GetAllRoles(context.Background(), "")
+	roles, pagination, err := roleService.GetAllRoles(context.Background(), "", 0, 0)
 
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, expectedRoles, roles)
 	assert.Len(t, roles, 2)
+	assert.Equal(t, &entity.PaginationEntity{Page: 1, TotalCount: 2, PerPage: 10, TotalPage: 1}, pagination)
 	mockRoleRepo.AssertExpectations(t)
 }
 
@@ -40,17 +41,18 @@ func TestRoleService_GetAllRoles_WithSearch(t *testing.T) {
 	expectedRoles := []entity.RoleEntity{
 		{ID: 1, Name: "Super Admin"},
 	}
-	mockRoleRepo.On("GetAllRoles", mock.Anything, searchTerm).Return(expectedRoles, nil)
+	mockRoleRepo.On("GetRoles", mock.Anything, searchTerm, 1, 10).Return(expectedRoles, int64(1), nil)
 
 	// Test service
 	roleService := service.NewRoleService(mockRoleRepo)
-	roles, err := roleService.GetAllRoles(context.Background(), searchTerm)
+	roles, pagination, err := roleService.GetAllRoles(context.Background(), searchTerm, 0, 0)
 
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, expectedRoles, roles)
 	assert.Len(t, roles, 1)
 	assert.Equal(t, "Super Admin", roles[0].Name)
+	assert.Equal(t, int64(1), pagination.TotalCount)
 	mockRoleRepo.AssertExpectations(t)
 }
 
@@ -58,11 +60,11 @@ func TestRoleService_GetAllRoles_RepositoryError(t *testing.T) {
 	// Setup
 	mockRoleRepo := &mocks.MockRoleRepository{}
 	expectedError := errors.New("database connection failed")
-	mockRoleRepo.On("GetAllRoles", mock.Anything, "").Return(nil, expectedError)
+	mockRoleRepo.On("GetRoles", mock.Anything, "", 1, 10).Return(nil, int64(0), expectedError)
 
 	// Test service
 	roleService := service.NewRoleService(mockRoleRepo)
-	roles, err := roleService.GetAllRoles(context.Background(), "")
+	roles, _, err := roleService.GetAllRoles(context.Background(), "", 0, 0)
 
 	// Assert
 	assert.Error(t, err)
@@ -216,7 +218,7 @@ func TestRoleService_UpdateRole_Success(t *testing.T) {
 
 	// Test service
 	roleService := service.NewRoleService(mockRoleRepo)
-	role, err := roleService.UpdateRole(context.Background(), roleID, newName)
+	role, err := roleService.UpdateRole(context.Background(), roleID, newName, 0)
 
 	// Assert
 	assert.NoError(t, err)
@@ -236,7 +238,7 @@ func TestRoleService_UpdateRole_NotFound(t *testing.T) {
 
 	// Test service
 	roleService := service.NewRoleService(mockRoleRepo)
-	role, err := roleService.UpdateRole(context.Background(), roleID, newName)
+	role, err := roleService.UpdateRole(context.Background(), roleID, newName, 0)
 
 	// Assert
 	assert.Error(t, err)
@@ -253,7 +255,7 @@ func TestRoleService_UpdateRole_EmptyName(t *testing.T) {
 
 	// Test service
 	roleService := service.NewRoleService(mockRoleRepo)
-	role, err := roleService.UpdateRole(context.Background(), 1, "")
+	role, err := roleService.UpdateRole(context.Background(), 1, "", 0)
 
 	// Assert
 	assert.Error(t, err)
@@ -270,7 +272,7 @@ func TestRoleService_UpdateRole_WhitespaceName(t *testing.T) {
 
 	// Test service
 	roleService := service.NewRoleService(mockRoleRepo)
-	role, err := roleService.UpdateRole(context.Background(), 1, "   ")
+	role, err := roleService.UpdateRole(context.Background(), 1, "   ", 0)
 
 	// Assert
 	assert.Error(t, err)
@@ -287,7 +289,7 @@ func TestRoleService_UpdateRole_NameTooShort(t *testing.T) {
 
 	// Test service
 	roleService := service.NewRoleService(mockRoleRepo)
-	role, err := roleService.UpdateRole(context.Background(), 1, "A")
+	role, err := roleService.UpdateRole(context.Background(), 1, "A", 0)
 
 	// Assert
 	assert.Error(t, err)
@@ -305,7 +307,7 @@ func TestRoleService_UpdateRole_NameTooLong(t *testing.T) {
 
 	// Test service
 	roleService := service.NewRoleService(mockRoleRepo)
-	role, err := roleService.UpdateRole(context.Background(), 1, longName)
+	role, err := roleService.UpdateRole(context.Background(), 1, longName, 0)
 
 	// Assert
 	assert.Error(t, err)
@@ -337,7 +339,7 @@ func TestRoleService_UpdateRole_DuplicateName(t *testing.T) {
 
 	// Test service
 	roleService := service.NewRoleService(mockRoleRepo)
-	role, err := roleService.UpdateRole(context.Background(), roleID, newName)
+	role, err := roleService.UpdateRole(context.Background(), roleID, newName, 0)
 
 	// Assert
 	assert.Error(t, err)
@@ -359,7 +361,7 @@ func TestRoleService_UpdateRole_GetRoleByIDError(t *testing.T) {
 
 	// Test service
 	roleService := service.NewRoleService(mockRoleRepo)
-	role, err := roleService.UpdateRole(context.Background(), roleID, newName)
+	role, err := roleService.UpdateRole(context.Background(), roleID, newName, 0)
 
 	// Assert
 	assert.Error(t, err)
@@ -389,7 +391,7 @@ func TestRoleService_UpdateRole_CheckExistingRolesError(t *testing.T) {
 
 	// Test service
 	roleService := service.NewRoleService(mockRoleRepo)
-	role, err := roleService.UpdateRole(context.Background(), roleID, newName)
+	role, err := roleService.UpdateRole(context.Background(), roleID, newName, 0)
 
 	// Assert
 	assert.Error(t, err)
@@ -424,7 +426,7 @@ func TestRoleService_UpdateRole_RepositoryError(t *testing.T) {
 
 	// Test service
 	roleService := service.NewRoleService(mockRoleRepo)
-	role, err := roleService.UpdateRole(context.Background(), roleID, newName)
+	role, err := roleService.UpdateRole(context.Background(), roleID, newName, 0)
 
 	// Assert
 	assert.Error(t, err)
@@ -437,16 +439,17 @@ func TestRoleService_GetAllRoles_EmptyResult(t *testing.T) {
 	// Setup
 	mockRoleRepo := &mocks.MockRoleRepository{}
 	expectedRoles := []entity.RoleEntity{}
-	mockRoleRepo.On("GetAllRoles", mock.Anything, "nonexistent").Return(expectedRoles, nil)
+	mockRoleRepo.On("GetRoles", mock.Anything, "nonexistent", 1, 10).Return(expectedRoles, int64(0), nil)
 
 	// Test service
 	roleService := service.NewRoleService(mockRoleRepo)
-	roles, err := roleService.GetAllRoles(context.Background(), "nonexistent")
+	roles, pagination, err := roleService.GetAllRoles(context.Background(), "nonexistent", 0, 0)
 
 	// Assert
 	assert.NoError(t, err)
 	assert.Equal(t, expectedRoles, roles)
 	assert.Len(t, roles, 0)
+	assert.Equal(t, 0, pagination.TotalPage)
 	mockRoleRepo.AssertExpectations(t)
 }
 
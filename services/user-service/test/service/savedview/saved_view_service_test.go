@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestSavedViewService_CreateView_Success(t *testing.T) {
+	ctx := context.Background()
+	viewRepo := new(mocks.MockSavedViewRepository)
+	viewService := service.NewSavedViewService(viewRepo)
+
+	view := &entity.SavedViewEntity{
+		OwnerID: 1,
+		List:    entity.SavedViewListCustomers,
+		Name:    "  Jakarta terbaru ",
+		Filters: map[string]string{"search": " jakarta ", "orderBy": "users.created_at DESC", "limit": "50"},
+		Shared:  true,
+	}
+	viewRepo.On("CreateView", ctx, mock.MatchedBy(func(v *entity.SavedViewEntity) bool {
+		return v.Name == "Jakarta terbaru" && v.Filters["search"] == "jakarta"
+	})).Return(&entity.SavedViewEntity{ID: 7, OwnerID: 1, Name: "Jakarta terbaru", Shared: true}, nil)
+
+	created, err := viewService.CreateView(ctx, view)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), created.ID)
+	viewRepo.AssertExpectations(t)
+}
+
+func TestSavedViewService_CreateView_RejectsInvalidViews(t *testing.T) {
+	cases := map[string]struct {
+		view    entity.SavedViewEntity
+		message string
+	}{
+		"unknown list": {
+			view:    entity.SavedViewEntity{List: "orders", Name: "Orders"},
+			message: "saved view list must be one of: customers",
+		},
+		"blank name": {
+			view:    entity.SavedViewEntity{List: entity.SavedViewListCustomers, Name: "  "},
+			message: "saved view name must be between 1 and 100 characters",
+		},
+		"unknown filter": {
+			view:    entity.SavedViewEntity{List: entity.SavedViewListCustomers, Name: "City", Filters: map[string]string{"city": "Bandung"}},
+			message: "saved view filter 'city' is not supported by the customers list",
+		},
+		"raw SQL order": {
+			view:    entity.SavedViewEntity{List: entity.SavedViewListCustomers, Name: "Order", Filters: map[string]string{"orderBy": "(SELECT password FROM users LIMIT 1)"}},
+			message: "saved view filter 'orderBy' must be a customer column (name, email, username, created_at, updated_at) optionally followed by ASC or DESC",
+		},
+		"limit out of range": {
+			view:    entity.SavedViewEntity{List: entity.SavedViewListCustomers, Name: "Big", Filters: map[string]string{"limit": "500"}},
+			message: "saved view filter 'limit' must be a number between 1 and 100",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			viewRepo := new(mocks.MockSavedViewRepository)
+			viewService := service.NewSavedViewService(viewRepo)
+
+			_, err := viewService.CreateView(context.Background(), &tc.view)
+
+			assert.EqualError(t, err, tc.message)
+			viewRepo.AssertNotCalled(t, "CreateView", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSavedViewService_GetViews_RejectsUnknownList(t *testing.T) {
+	viewRepo := new(mocks.MockSavedViewRepository)
+	viewService := service.NewSavedViewService(viewRepo)
+
+	_, err := viewService.GetViews(context.Background(), 1, "orders")
+
+	assert.EqualError(t, err, "saved view list must be one of: customers")
+}
+
+func TestSavedViewService_UpdateView_KeepsOwnerAndList(t *testing.T) {
+	ctx := context.Background()
+	viewRepo := new(mocks.MockSavedViewRepository)
+	viewService := service.NewSavedViewService(viewRepo)
+
+	viewRepo.On("GetViewByID", ctx, int64(7)).Return(&entity.SavedViewEntity{ID: 7, OwnerID: 1, List: entity.SavedViewListCustomers}, nil)
+	viewRepo.On("UpdateView", ctx, mock.MatchedBy(func(v *entity.SavedViewEntity) bool {
+		return v.ID == 7 && v.OwnerID == 1 && v.List == entity.SavedViewListCustomers && v.Name == "Renamed"
+	})).Return(&entity.SavedViewEntity{ID: 7, OwnerID: 1, Name: "Renamed"}, nil)
+
+	_, err := viewService.UpdateView(ctx, 1, 7, &entity.SavedViewEntity{OwnerID: 2, List: "orders", Name: "Renamed"})
+
+	require.NoError(t, err)
+	viewRepo.AssertExpectations(t)
+}
+
+func TestSavedViewService_UpdateView_HidesOtherAdminsViews(t *testing.T) {
+	ctx := context.Background()
+	viewRepo := new(mocks.MockSavedViewRepository)
+	viewService := service.NewSavedViewService(viewRepo)
+
+	viewRepo.On("GetViewByID", ctx, int64(7)).Return(&entity.SavedViewEntity{ID: 7, OwnerID: 2, List: entity.SavedViewListCustomers, Shared: true}, nil)
+
+	_, err := viewService.UpdateView(ctx, 1, 7, &entity.SavedViewEntity{Name: "Mine now"})
+
+	assert.EqualError(t, err, "saved view not found")
+	viewRepo.AssertNotCalled(t, "UpdateView", mock.Anything, mock.Anything)
+}
+
+func TestSavedViewService_DeleteView(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("owner deletes", func(t *testing.T) {
+		viewRepo := new(mocks.MockSavedViewRepository)
+		viewService := service.NewSavedViewService(viewRepo)
+		viewRepo.On("GetViewByID", ctx, int64(7)).Return(&entity.SavedViewEntity{ID: 7, OwnerID: 1}, nil)
+		viewRepo.On("DeleteView", ctx, int64(7)).Return(nil)
+
+		assert.NoError(t, viewService.DeleteView(ctx, 1, 7))
+		viewRepo.AssertExpectations(t)
+	})
+
+	t.Run("missing view", func(t *testing.T) {
+		viewRepo := new(mocks.MockSavedViewRepository)
+		viewService := service.NewSavedViewService(viewRepo)
+		viewRepo.On("GetViewByID", ctx, int64(7)).Return(nil, gorm.ErrRecordNotFound)
+
+		assert.EqualError(t, viewService.DeleteView(ctx, 1, 7), "saved view not found")
+	})
+
+	t.Run("repository failure", func(t *testing.T) {
+		viewRepo := new(mocks.MockSavedViewRepository)
+		viewService := service.NewSavedViewService(viewRepo)
+		viewRepo.On("GetViewByID", ctx, int64(7)).Return(nil, errors.New("connection refused"))
+
+		assert.EqualError(t, viewService.DeleteView(ctx, 1, 7), "connection refused")
+	})
+}
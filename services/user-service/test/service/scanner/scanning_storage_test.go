@@ -0,0 +1,170 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+	"user-service/internal/adapter/scanner"
+	"user-service/internal/adapter/storage"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type scanningStorageFixture struct {
+	storage   *mocks.MockStorage
+	scanner   *mocks.MockFileScanner
+	auditRepo *mocks.MockAuditLogRepository
+}
+
+func newScanningStorageFixture(logOnly bool) (*scanningStorageFixture, port.StorageInterface) {
+	f := &scanningStorageFixture{
+		storage:   new(mocks.MockStorage),
+		scanner:   new(mocks.MockFileScanner),
+		auditRepo: new(mocks.MockAuditLogRepository),
+	}
+	return f, storage.NewScanningStorage(f.storage, f.scanner, service.NewAuditLogService(f.auditRepo), logOnly)
+}
+
+func TestScanningStorage_CleanFileIsStored(t *testing.T) {
+	f, scanningStorage := newScanningStorageFixture(false)
+	ctx := context.Background()
+
+	f.scanner.On("Scan", ctx, mock.Anything).Return(&entity.ScanResultEntity{}, nil)
+	f.storage.On("UploadFile", ctx, "", "photo.png", mock.MatchedBy(func(file io.Reader) bool {
+		content, _ := io.ReadAll(file)
+		return string(content) == "clean image"
+	}), "image/png").Return("https://cdn/photo.png", nil)
+
+	url, err := scanningStorage.UploadFile(ctx, "", "photo.png", strings.NewReader("clean image"), "image/png")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn/photo.png", url)
+	f.auditRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestScanningStorage_InfectedFileIsRejectedAndAudited(t *testing.T) {
+	f, scanningStorage := newScanningStorageFixture(false)
+	ctx := entity.ContextWithPrincipal(context.Background(), entity.PrincipalEntity{UserID: 42})
+
+	f.scanner.On("Scan", ctx, mock.Anything).Return(&entity.ScanResultEntity{Infected: true, Signature: "Eicar-Test-Signature"}, nil)
+	f.auditRepo.On("Create", ctx, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.UserID == 42 &&
+			auditLog.Event == entity.AuditEventUploadInfected &&
+			auditLog.Metadata["signature"] == "Eicar-Test-Signature" &&
+			auditLog.Metadata["action"] == "rejected"
+	})).Return(nil)
+
+	_, err := scanningStorage.UploadFile(ctx, "", "doc.pdf", strings.NewReader("X5O!P%@AP"), "application/pdf")
+
+	assert.ErrorIs(t, err, port.ErrFileInfected)
+	f.storage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	f.auditRepo.AssertExpectations(t)
+}
+
+func TestScanningStorage_LogOnlyStoresInfectedFile(t *testing.T) {
+	f, scanningStorage := newScanningStorageFixture(true)
+	ctx := context.Background()
+
+	f.scanner.On("Scan", ctx, mock.Anything).Return(&entity.ScanResultEntity{Infected: true, Signature: "Eicar-Test-Signature"}, nil)
+	f.auditRepo.On("Create", ctx, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Metadata["action"] == "stored"
+	})).Return(nil)
+	f.storage.On("UploadFile", ctx, "", "doc.pdf", mock.Anything, "application/pdf").Return("https://cdn/doc.pdf", nil)
+
+	url, err := scanningStorage.UploadFile(ctx, "", "doc.pdf", strings.NewReader("X5O!P%@AP"), "application/pdf")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "https://cdn/doc.pdf", url)
+	f.auditRepo.AssertExpectations(t)
+}
+
+func TestScanningStorage_ScannerFailure(t *testing.T) {
+	ctx := context.Background()
+
+	f, scanningStorage := newScanningStorageFixture(false)
+	f.scanner.On("Scan", ctx, mock.Anything).Return(nil, errors.New("connect to clamd: connection refused"))
+
+	_, err := scanningStorage.UploadFile(ctx, "", "photo.png", strings.NewReader("image"), "image/png")
+
+	assert.ErrorIs(t, err, port.ErrScannerUnavailable)
+	f.storage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	// In log-only mode an unreachable scanner must not block uploads
+	f, scanningStorage = newScanningStorageFixture(true)
+	f.scanner.On("Scan", ctx, mock.Anything).Return(nil, errors.New("connect to clamd: connection refused"))
+	f.storage.On("UploadFile", ctx, "", "photo.png", mock.Anything, "image/png").Return("https://cdn/photo.png", nil)
+
+	_, err = scanningStorage.UploadFile(ctx, "", "photo.png", strings.NewReader("image"), "image/png")
+
+	assert.NoError(t, err)
+}
+
+// fakeClamd answers one INSTREAM session, replying FOUND when the stream contains "EICAR"
+func fakeClamd(t *testing.T) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		if command, err := reader.ReadString(0); err != nil || command != "zINSTREAM\x00" {
+			return
+		}
+
+		var stream strings.Builder
+		for {
+			var size uint32
+			if err := binary.Read(reader, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			chunk := make([]byte, size)
+			if _, err := io.ReadFull(reader, chunk); err != nil {
+				return
+			}
+			stream.Write(chunk)
+		}
+
+		reply := "stream: OK\x00"
+		if strings.Contains(stream.String(), "EICAR") {
+			reply = "stream: Eicar-Test-Signature FOUND\x00"
+		}
+		conn.Write([]byte(reply))
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestClamAVScanner_Scan(t *testing.T) {
+	ctx := context.Background()
+
+	result, err := scanner.NewClamAVScanner(fakeClamd(t), time.Second).Scan(ctx, strings.NewReader("hello"))
+	assert.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	result, err = scanner.NewClamAVScanner("tcp://"+fakeClamd(t), time.Second).Scan(ctx, strings.NewReader("EICAR-STANDARD-ANTIVIRUS-TEST-FILE"))
+	assert.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, "Eicar-Test-Signature", result.Signature)
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-service/config"
+	"user-service/internal/adapter/handler"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type scimFixture struct {
+	scimRepo    *mocks.MockSCIMRepository
+	userRepo    *mocks.MockUserRepository
+	sessionRepo *mocks.MockSessionRepository
+	auditRepo   *mocks.MockAuditLogRepository
+	cfg         *config.Config
+	service     port.SCIMServiceInterface
+}
+
+func newSCIMFixture() *scimFixture {
+	f := &scimFixture{
+		scimRepo:    new(mocks.MockSCIMRepository),
+		userRepo:    new(mocks.MockUserRepository),
+		sessionRepo: new(mocks.MockSessionRepository),
+		auditRepo:   new(mocks.MockAuditLogRepository),
+		cfg: &config.Config{
+			PasswordHashing: config.PasswordHashing{BcryptCost: 4},
+			SCIM:            config.SCIM{MaxResults: 50},
+		},
+	}
+	f.service = service.NewSCIMService(f.scimRepo, f.userRepo, f.sessionRepo, service.NewAuditLogService(f.auditRepo), f.cfg)
+	return f
+}
+
+func (f *scimFixture) expectAudit(event string, userID int64) {
+	f.auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == event && auditLog.UserID == userID
+	})).Return(nil).Once()
+}
+
+func TestSCIMCreateUser_ProvisionsNewAccount(t *testing.T) {
+	ctx := context.Background()
+	f := newSCIMFixture()
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "budi@toko.id").Return(nil, gorm.ErrRecordNotFound)
+	var hashed string
+	f.scimRepo.On("CreateUser", ctx, mock.MatchedBy(func(user *entity.SCIMUserEntity) bool {
+		return user.UserName == "budi@toko.id" && user.Name == "Budi" && user.ExternalID == "okta-1" && user.Active
+	}), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		hashed = args.String(2)
+	}).Return(&entity.SCIMUserEntity{ID: 7, UserName: "budi@toko.id", Name: "Budi", ExternalID: "okta-1", Active: true, RoleID: 2, RoleName: "Customer"}, nil)
+	f.expectAudit(entity.AuditEventSCIMUserProvisioned, 7)
+
+	created, err := f.service.CreateUser(ctx, entity.SCIMUserEntity{UserName: " Budi@Toko.ID ", Name: " Budi ", ExternalID: "okta-1", Active: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), created.ID)
+	assert.NotEmpty(t, hashed)
+	// Customer is not a SCIM group, so the IdP sees no group
+	assert.Zero(t, created.RoleID)
+	assert.Empty(t, created.RoleName)
+	f.auditRepo.AssertExpectations(t)
+}
+
+func TestSCIMCreateUser_AdoptsStaffButNotCustomers(t *testing.T) {
+	ctx := context.Background()
+	f := newSCIMFixture()
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "kurir@toko.id").Return(&entity.UserEntity{ID: 3, Email: "kurir@toko.id", RoleName: "Courier"}, nil)
+	f.scimRepo.On("GetUser", ctx, int64(3)).Return(nil, gorm.ErrRecordNotFound).Once()
+	f.scimRepo.On("AdoptUser", ctx, int64(3), "okta-3").Return(nil)
+	f.scimRepo.On("UpdateUser", ctx, mock.MatchedBy(func(user *entity.SCIMUserEntity) bool { return user.ID == 3 })).Return(nil)
+	f.scimRepo.On("GetUser", ctx, int64(3)).Return(&entity.SCIMUserEntity{ID: 3, UserName: "kurir@toko.id", Name: "Kurir", Active: true, RoleID: 4, RoleName: "Courier"}, nil)
+	f.expectAudit(entity.AuditEventSCIMUserProvisioned, 3)
+
+	adopted, err := f.service.CreateUser(ctx, entity.SCIMUserEntity{UserName: "kurir@toko.id", Name: "Kurir", ExternalID: "okta-3", Active: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Courier", adopted.RoleName)
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "pembeli@toko.id").Return(&entity.UserEntity{ID: 9, Email: "pembeli@toko.id", RoleName: "Customer"}, nil)
+	f.scimRepo.On("GetUser", ctx, int64(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err = f.service.CreateUser(ctx, entity.SCIMUserEntity{UserName: "pembeli@toko.id", Name: "Pembeli", Active: true})
+	assert.EqualError(t, err, "email already exists")
+	f.scimRepo.AssertNotCalled(t, "AdoptUser", ctx, int64(9), mock.Anything)
+}
+
+func TestSCIMPatchUser_DeactivationEndsSessions(t *testing.T) {
+	ctx := context.Background()
+	f := newSCIMFixture()
+
+	f.scimRepo.On("GetUser", ctx, int64(5)).Return(&entity.SCIMUserEntity{ID: 5, UserName: "admin@toko.id", Name: "Admin", Active: true}, nil).Once()
+	f.scimRepo.On("UpdateUser", ctx, mock.MatchedBy(func(user *entity.SCIMUserEntity) bool { return user.ID == 5 && !user.Active })).Return(nil)
+	f.sessionRepo.On("DeleteAllUserTokens", ctx, int64(5)).Return(nil)
+	f.expectAudit(entity.AuditEventSCIMUserDeactivated, 5)
+	f.scimRepo.On("GetUser", ctx, int64(5)).Return(&entity.SCIMUserEntity{ID: 5, UserName: "admin@toko.id", Name: "Admin", Active: false}, nil)
+
+	user, err := f.service.PatchUser(ctx, 5, []entity.SCIMPatchOperation{{Op: "replace", Path: "active", Value: false}})
+
+	require.NoError(t, err)
+	assert.False(t, user.Active)
+	f.sessionRepo.AssertExpectations(t)
+	f.auditRepo.AssertExpectations(t)
+}
+
+func TestSCIMPatchUser_RejectsUnknownPath(t *testing.T) {
+	ctx := context.Background()
+	f := newSCIMFixture()
+	f.scimRepo.On("GetUser", ctx, int64(5)).Return(&entity.SCIMUserEntity{ID: 5, UserName: "admin@toko.id", Name: "Admin", Active: true}, nil)
+
+	_, err := f.service.PatchUser(ctx, 5, []entity.SCIMPatchOperation{{Op: "replace", Path: "password", Value: "secret"}})
+
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "scim path "))
+	f.scimRepo.AssertNotCalled(t, "UpdateUser", mock.Anything, mock.Anything)
+}
+
+func TestSCIMPatchGroup_MovesMembersInAndOut(t *testing.T) {
+	ctx := context.Background()
+	f := newSCIMFixture()
+
+	f.scimRepo.On("GetGroup", ctx, int64(4), []string{"Super Admin", "Courier"}).Return(&entity.SCIMGroupEntity{
+		ID: 4, Name: "Courier", Members: []entity.SCIMMemberEntity{{UserID: 10}, {UserID: 11}},
+	}, nil)
+	f.scimRepo.On("GetUser", ctx, int64(12)).Return(&entity.SCIMUserEntity{ID: 12}, nil)
+	f.userRepo.On("GetRoleByName", ctx, "Customer").Return(&entity.RoleEntity{ID: 2, Name: "Customer"}, nil)
+	f.userRepo.On("UpdateUserRole", ctx, int64(12), int64(4)).Return(nil)
+	f.userRepo.On("UpdateUserRole", ctx, int64(10), int64(2)).Return(nil)
+	f.sessionRepo.On("DeleteAllUserTokens", ctx, int64(12)).Return(nil)
+	f.sessionRepo.On("DeleteAllUserTokens", ctx, int64(10)).Return(nil)
+	f.expectAudit(entity.AuditEventSCIMGroupChanged, 12)
+	f.expectAudit(entity.AuditEventSCIMGroupChanged, 10)
+
+	_, err := f.service.PatchGroup(ctx, 4, []entity.SCIMPatchOperation{
+		{Op: "add", Path: "members", Value: []interface{}{map[string]interface{}{"value": "12"}}},
+		{Op: "remove", Path: `members[value eq "10"]`},
+	})
+
+	require.NoError(t, err)
+	f.userRepo.AssertExpectations(t)
+	f.sessionRepo.AssertExpectations(t)
+	f.userRepo.AssertNotCalled(t, "UpdateUserRole", ctx, int64(11), mock.Anything)
+}
+
+func TestSCIMReplaceGroupMembers_UnknownMember(t *testing.T) {
+	ctx := context.Background()
+	f := newSCIMFixture()
+
+	f.scimRepo.On("GetGroup", ctx, int64(4), mock.Anything).Return(&entity.SCIMGroupEntity{ID: 4, Name: "Courier"}, nil)
+	f.scimRepo.On("GetUser", ctx, int64(99)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := f.service.ReplaceGroupMembers(ctx, 4, []int64{99})
+
+	assert.EqualError(t, err, "scim member 99 not found")
+	f.userRepo.AssertNotCalled(t, "UpdateUserRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSCIMListUsers_Filters(t *testing.T) {
+	ctx := context.Background()
+	f := newSCIMFixture()
+
+	f.scimRepo.On("ListUsers", ctx, &entity.SCIMFilter{Attribute: "username", Value: "budi@toko.id"}, 0, 50).Return([]entity.SCIMUserEntity{{ID: 7}}, int64(1), nil)
+
+	users, total, err := f.service.ListUsers(ctx, `userName eq "Budi@Toko.id"`, 1, 500)
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, int64(1), total)
+
+	_, _, err = f.service.ListUsers(ctx, `name.familyName co "Bu"`, 1, 10)
+	assert.EqualError(t, err, "scim filter is not supported")
+}
+
+func serveSCIM(f *scimFixture, apiKeys *mocks.MockAPIKeyService, method, path, body string) *httptest.ResponseRecorder {
+	e := echo.New()
+	h := handler.NewSCIMHandler(f.service, f.cfg)
+	scim := e.Group("/scim/v2", middleware.SCIMAuthMiddleware(apiKeys))
+	scim.POST("/Users", h.CreateUser)
+	scim.GET("/Users/:id", h.GetUser)
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, middleware.SCIMContentType)
+	req.Header.Set(echo.HeaderAuthorization, "Bearer jsk_idp")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSCIMHandler_CreateUserAndErrors(t *testing.T) {
+	f := newSCIMFixture()
+	apiKeys := new(mocks.MockAPIKeyService)
+	apiKeys.On("Authenticate", mock.Anything, "jsk_idp").Return(&entity.APIKeyEntity{ID: 8, Name: "Okta", Scopes: []string{entity.APIKeyScopeSCIMProvision}}, nil)
+
+	f.userRepo.On("GetUserByEmailIncludingUnverified", mock.Anything, "siti@toko.id").Return(nil, gorm.ErrRecordNotFound)
+	f.scimRepo.On("CreateUser", mock.Anything, mock.Anything, mock.Anything).Return(&entity.SCIMUserEntity{ID: 21, UserName: "siti@toko.id", Name: "Siti Aminah", Active: true, Version: 1}, nil)
+	f.auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == entity.AuditEventSCIMUserProvisioned && auditLog.Metadata["api_key_id"] == int64(8)
+	})).Return(nil)
+
+	rec := serveSCIM(f, apiKeys, http.MethodPost, "/scim/v2/Users", `{"schemas":["urn:ietf:params:scim:schemas:core:2.0:User"],"userName":"siti@toko.id","name":{"givenName":"Siti","familyName":"Aminah"}}`)
+
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, middleware.SCIMContentType, rec.Header().Get(echo.HeaderContentType))
+	var created map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, "21", created["id"])
+	assert.Equal(t, "http://example.com/scim/v2/Users/21", created["meta"].(map[string]interface{})["location"])
+	f.auditRepo.AssertExpectations(t)
+
+	f.scimRepo.On("GetUser", mock.Anything, int64(404)).Return(nil, gorm.ErrRecordNotFound)
+	rec = serveSCIM(f, apiKeys, http.MethodGet, "/scim/v2/Users/404", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Contains(t, rec.Body.String(), "urn:ietf:params:scim:api:messages:2.0:Error")
+}
+
+func TestSCIMAuthMiddleware_RequiresProvisionScope(t *testing.T) {
+	f := newSCIMFixture()
+	apiKeys := new(mocks.MockAPIKeyService)
+	apiKeys.On("Authenticate", mock.Anything, "jsk_idp").Return(&entity.APIKeyEntity{ID: 8, Name: "Acme", Scopes: []string{entity.APIKeyScopeCustomersRead}}, nil).Once()
+
+	rec := serveSCIM(f, apiKeys, http.MethodGet, "/scim/v2/Users/1", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	apiKeys.On("Authenticate", mock.Anything, "jsk_idp").Return(nil, errors.New("api key revoked"))
+	rec = serveSCIM(f, apiKeys, http.MethodGet, "/scim/v2/Users/1", "")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	f.scimRepo.AssertNotCalled(t, "GetUser", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/adapter/handler"
+	"user-service/internal/adapter/message"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type segmentFixture struct {
+	segmentRepo *mocks.MockSegmentRepository
+	userRepo    *mocks.MockUserRepository
+	jobRepo     *mocks.MockJobRepository
+	auditRepo   *mocks.MockAuditLogRepository
+	publisher   *mocks.MockEmailPublisher
+	service     port.SegmentServiceInterface
+}
+
+func newSegmentFixture(campaign config.Campaign) *segmentFixture {
+	f := &segmentFixture{
+		segmentRepo: new(mocks.MockSegmentRepository),
+		userRepo:    new(mocks.MockUserRepository),
+		jobRepo:     new(mocks.MockJobRepository),
+		auditRepo:   new(mocks.MockAuditLogRepository),
+		publisher:   new(mocks.MockEmailPublisher),
+	}
+	f.service = service.NewSegmentService(f.segmentRepo, f.userRepo, service.NewJobService(f.jobRepo), f.publisher, service.NewAuditLogService(f.auditRepo), &config.Config{Campaign: campaign})
+	return f
+}
+
+func intPtr(v int) *int { return &v }
+
+func TestCreateSegment_MaterializesMembers(t *testing.T) {
+	ctx := context.Background()
+	f := newSegmentFixture(config.Campaign{})
+	materializedAt := time.Now()
+
+	f.segmentRepo.On("CreateSegment", ctx, mock.MatchedBy(func(segment *entity.SegmentEntity) bool {
+		return segment.Name == "Bandung regulars" && segment.Filter.City == "Bandung" && *segment.Filter.MinOrders == 3
+	})).Return(&entity.SegmentEntity{ID: 4, Name: "Bandung regulars"}, nil)
+	f.segmentRepo.On("Materialize", ctx, int64(4)).Return(int64(120), nil)
+	f.segmentRepo.On("GetSegmentByID", ctx, int64(4)).Return(&entity.SegmentEntity{ID: 4, Name: "Bandung regulars", MemberCount: 120, MaterializedAt: &materializedAt}, nil)
+
+	segment, err := f.service.CreateSegment(ctx, &entity.SegmentEntity{
+		Name:   " Bandung regulars ",
+		Filter: entity.SegmentFilterEntity{City: " Bandung ", MinOrders: intPtr(3)},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(120), segment.MemberCount)
+	f.segmentRepo.AssertExpectations(t)
+}
+
+func TestCreateSegment_RejectsInvalidFilters(t *testing.T) {
+	f := newSegmentFixture(config.Campaign{})
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, -1, 0)
+
+	_, err := f.service.CreateSegment(context.Background(), &entity.SegmentEntity{Name: "Spring", Filter: entity.SegmentFilterEntity{SignedUpFrom: &from, SignedUpTo: &to}})
+	assert.EqualError(t, err, "segment signed_up_from must be before signed_up_to")
+
+	_, err = f.service.CreateSegment(context.Background(), &entity.SegmentEntity{Name: "Odd", Filter: entity.SegmentFilterEntity{MinOrders: intPtr(5), MaxOrders: intPtr(2)}})
+	assert.EqualError(t, err, "segment min_orders cannot exceed max_orders")
+
+	f.segmentRepo.AssertNotCalled(t, "CreateSegment", mock.Anything, mock.Anything)
+}
+
+func TestLaunchCampaign_StaggersBatches(t *testing.T) {
+	ctx := context.Background()
+	f := newSegmentFixture(config.Campaign{BatchSize: 2, BatchIntervalSeconds: 30})
+
+	f.segmentRepo.On("GetSegmentByID", ctx, int64(4)).Return(&entity.SegmentEntity{ID: 4, MemberCount: 3}, nil)
+	f.segmentRepo.On("CreateCampaign", ctx, mock.Anything).Return(&entity.CampaignEntity{ID: 9, SegmentID: 4, Name: "Spinach discount"}, nil)
+	f.segmentRepo.On("GetMemberIDs", ctx, int64(4), int64(0), 2).Return([]int64{11, 12}, nil)
+	f.segmentRepo.On("GetMemberIDs", ctx, int64(4), int64(12), 2).Return([]int64{15}, nil)
+	f.segmentRepo.On("GetMemberIDs", ctx, int64(4), int64(15), 2).Return([]int64{}, nil)
+	f.segmentRepo.On("UpdateCampaignFanOut", ctx, int64(9), int64(3), 2).Return(nil)
+	f.auditRepo.On("Create", ctx, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == entity.AuditEventCampaignLaunched && auditLog.UserID == 1
+	})).Return(nil)
+
+	var jobs []*entity.JobEntity
+	f.jobRepo.On("Enqueue", ctx, mock.MatchedBy(func(job *entity.JobEntity) bool {
+		return job.Type == entity.JobTypeSendCampaignBatch
+	})).Run(func(args mock.Arguments) {
+		jobs = append(jobs, args.Get(1).(*entity.JobEntity))
+	}).Return(&entity.JobEntity{ID: 1}, nil)
+
+	campaign, err := f.service.LaunchCampaign(ctx, 4, &entity.CampaignEntity{Name: "Spinach discount", Subject: "Hi {name}", Body: "Fresh spinach, 20% off", CreatedBy: 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), campaign.Recipients)
+	assert.Equal(t, 2, campaign.Batches)
+	assert.Len(t, jobs, 2)
+	assert.Equal(t, "campaign:9:0", jobs[0].UniqueKey)
+	assert.Equal(t, "campaign:9:1", jobs[1].UniqueKey)
+	assert.Equal(t, 30*time.Second, jobs[1].RunAt.Sub(jobs[0].RunAt))
+
+	var payload entity.CampaignBatchJobPayload
+	assert.NoError(t, json.Unmarshal(jobs[1].Payload, &payload))
+	assert.Equal(t, []int64{15}, payload.UserIDs)
+	f.segmentRepo.AssertExpectations(t)
+	f.auditRepo.AssertExpectations(t)
+}
+
+func TestLaunchCampaign_EmptySegment(t *testing.T) {
+	ctx := context.Background()
+	f := newSegmentFixture(config.Campaign{})
+
+	f.segmentRepo.On("GetSegmentByID", ctx, int64(4)).Return(&entity.SegmentEntity{ID: 4}, nil)
+
+	_, err := f.service.LaunchCampaign(ctx, 4, &entity.CampaignEntity{Name: "Spinach", Subject: "Hi", Body: "Body"})
+
+	assert.EqualError(t, err, "segment has no members")
+	f.segmentRepo.AssertNotCalled(t, "CreateCampaign", mock.Anything, mock.Anything)
+}
+
+func TestSendCampaignBatch_PublishesPerRecipient(t *testing.T) {
+	ctx := context.Background()
+	f := newSegmentFixture(config.Campaign{})
+	campaign := &entity.CampaignEntity{ID: 9, Name: "Spinach discount", Subject: "Hi {name}", Body: "Fresh spinach"}
+
+	f.segmentRepo.On("GetCampaignByID", ctx, int64(9)).Return(campaign, nil)
+	// User 12 bounced after launch and is no longer a recipient
+	f.segmentRepo.On("GetRecipients", ctx, []int64{11, 12}).Return([]entity.UserEntity{{ID: 11, Name: "Budi", Email: "budi@example.com"}}, nil)
+	f.publisher.On("SendCampaignEmail", ctx, "budi@example.com", "Budi", campaign).Return(nil)
+
+	assert.NoError(t, f.service.SendCampaignBatch(ctx, 9, []int64{11, 12}))
+	f.publisher.AssertExpectations(t)
+}
+
+func TestSendCampaignBatch_FailureRetriesBatch(t *testing.T) {
+	ctx := context.Background()
+	f := newSegmentFixture(config.Campaign{})
+	campaign := &entity.CampaignEntity{ID: 9}
+
+	f.segmentRepo.On("GetCampaignByID", ctx, int64(9)).Return(campaign, nil)
+	f.segmentRepo.On("GetRecipients", ctx, []int64{11}).Return([]entity.UserEntity{{ID: 11, Email: "budi@example.com"}}, nil)
+	f.publisher.On("SendCampaignEmail", ctx, "budi@example.com", "", campaign).Return(errors.New("rabbitmq not available"))
+
+	assert.EqualError(t, f.service.SendCampaignBatch(ctx, 9, []int64{11}), "failed to send campaign to 1 of 1 recipients")
+}
+
+func TestSendCampaignBatch_DeletedCampaignIsSkipped(t *testing.T) {
+	ctx := context.Background()
+	f := newSegmentFixture(config.Campaign{})
+
+	f.segmentRepo.On("GetCampaignByID", ctx, int64(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	assert.NoError(t, f.service.SendCampaignBatch(ctx, 9, []int64{11}))
+	f.segmentRepo.AssertNotCalled(t, "GetRecipients", mock.Anything, mock.Anything)
+}
+
+func TestRecordOrder_UnknownUser(t *testing.T) {
+	ctx := context.Background()
+	f := newSegmentFixture(config.Campaign{})
+
+	f.userRepo.On("GetUserByID", ctx, int64(7)).Return(nil, gorm.ErrRecordNotFound)
+
+	assert.EqualError(t, f.service.RecordOrder(ctx, 7, "ord-1", time.Now()), "user not found")
+	f.segmentRepo.AssertNotCalled(t, "RecordOrder", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRecordOrder_RepeatedOrderIsAccepted(t *testing.T) {
+	ctx := context.Background()
+	f := newSegmentFixture(config.Campaign{})
+	orderedAt := time.Now().Add(-time.Hour)
+
+	f.userRepo.On("GetUserByID", ctx, int64(7)).Return(&entity.UserEntity{ID: 7}, nil)
+	f.segmentRepo.On("RecordOrder", ctx, int64(7), "ord-1", orderedAt).Return(false, nil)
+
+	assert.NoError(t, f.service.RecordOrder(ctx, 7, " ord-1 ", orderedAt))
+}
+
+func TestLaunchCampaignHandler_RequiresSubjectAndBody(t *testing.T) {
+	h := handler.NewSegmentHandler(nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/segments/4/campaigns", strings.NewReader(`{"name":"Spinach discount"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("4")
+
+	assert.NoError(t, h.LaunchCampaign(c))
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+}
+
+func TestLaunchCampaignHandler_EmptySegmentConflicts(t *testing.T) {
+	f := newSegmentFixture(config.Campaign{})
+	h := handler.NewSegmentHandler(f.service)
+
+	f.segmentRepo.On("GetSegmentByID", mock.Anything, int64(4)).Return(&entity.SegmentEntity{ID: 4}, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/segments/4/campaigns", strings.NewReader(`{"name":"Spinach discount","subject":"Hi","body":"Fresh spinach"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("4")
+
+	assert.NoError(t, h.LaunchCampaign(c))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
+
+func TestSendCampaignEmail_PersonalizesMessage(t *testing.T) {
+	jobRepo := new(mocks.MockJobRepository)
+	outbox := message.NewEmailOutbox(message.NewBroker(nil, nil, 0), nil, service.NewJobService(jobRepo))
+	publisher := message.NewEmailPublisher(outbox, nil, nil, "https://api.jualan-sayur.test")
+
+	var buffered entity.BufferedEmailEntity
+	jobRepo.On("Enqueue", mock.Anything, mock.MatchedBy(func(job *entity.JobEntity) bool {
+		return job.Type == entity.JobTypePublishEmail && json.Unmarshal(job.Payload, &buffered) == nil
+	})).Return(&entity.JobEntity{ID: 1}, nil)
+
+	campaign := &entity.CampaignEntity{ID: 9, Name: "Spinach discount", Subject: "{name}, fresh spinach in Bandung", Body: "Hi {name}, use {code}", Data: map[string]string{"code": "BAYAM20"}}
+	assert.NoError(t, publisher.SendCampaignEmail(context.Background(), "budi@example.com", "Budi", campaign))
+
+	var email struct {
+		Type    string            `json:"type"`
+		Subject string            `json:"subject"`
+		Body    string            `json:"body"`
+		Data    map[string]string `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(buffered.Body, &email))
+	assert.Equal(t, "email_queue", buffered.RoutingKey)
+	assert.Equal(t, "campaign", email.Type)
+	assert.Equal(t, "Budi, fresh spinach in Bandung", email.Subject)
+	// Only {name} is filled in here; other placeholders are left for notification-service templates
+	assert.Equal(t, "Hi Budi, use {code}", email.Body)
+	assert.Equal(t, "9", email.Data["campaign_id"])
+	assert.Equal(t, "BAYAM20", email.Data["code"])
+}
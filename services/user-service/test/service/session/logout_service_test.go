@@ -17,7 +17,14 @@ func TestUserService_Logout_Success(t *testing.T) {
 	mockSessionRepo := new(mocks.MockSessionRepository)
 	mockBlacklistRepo := new(mocks.MockBlacklistTokenRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(nil, mockSessionRepo, nil, nil, nil, mockBlacklistRepo, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			SessionRepo:        mockSessionRepo,
+			BlacklistTokenRepo: mockBlacklistRepo,
+			Storage:            mockStorage,
+		},
+		Config: &config.Config{},
+	})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -42,7 +49,10 @@ func TestUserService_Logout_SessionDeletionFails(t *testing.T) {
 	// Setup
 	mockSessionRepo := new(mocks.MockSessionRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(nil, mockSessionRepo, nil, nil, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{SessionRepo: mockSessionRepo, Storage: mockStorage},
+		Config:          &config.Config{},
+	})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -65,7 +75,14 @@ func TestUserService_Logout_BlacklistFailureIgnored(t *testing.T) {
 	mockSessionRepo := new(mocks.MockSessionRepository)
 	mockBlacklistRepo := new(mocks.MockBlacklistTokenRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(nil, mockSessionRepo, nil, nil, nil, mockBlacklistRepo, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			SessionRepo:        mockSessionRepo,
+			BlacklistTokenRepo: mockBlacklistRepo,
+			Storage:            mockStorage,
+		},
+		Config: &config.Config{},
+	})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -90,7 +107,10 @@ func TestUserService_Logout_WithoutToken(t *testing.T) {
 	// Setup
 	mockSessionRepo := new(mocks.MockSessionRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(nil, mockSessionRepo, nil, nil, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{SessionRepo: mockSessionRepo, Storage: mockStorage},
+		Config:          &config.Config{},
+	})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -113,7 +133,15 @@ func TestUserService_Logout_WithoutExpiration(t *testing.T) {
 	mockSessionRepo := new(mocks.MockSessionRepository)
 	mockBlacklistRepo := new(mocks.MockBlacklistTokenRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(mockUserRepo, mockSessionRepo, nil, nil, nil, mockBlacklistRepo, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:           mockUserRepo,
+			SessionRepo:        mockSessionRepo,
+			BlacklistTokenRepo: mockBlacklistRepo,
+			Storage:            mockStorage,
+		},
+		Config: &config.Config{},
+	})
 
 	ctx := context.Background()
 	userID := int64(1)
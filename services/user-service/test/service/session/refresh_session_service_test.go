@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestUserService_RefreshSession_Success(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockJWT := new(mocks.MockJWTUtil)
+	mockBlacklistRepo := new(mocks.MockBlacklistTokenRepository)
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:           mockUserRepo,
+			SessionRepo:        mockSessionRepo,
+			JWTUtil:            mockJWT,
+			BlacklistTokenRepo: mockBlacklistRepo,
+		},
+		Config: &config.Config{},
+	})
+
+	ctx := context.Background()
+	user := &entity.UserEntity{ID: 1, Email: "john@example.com", RoleName: "Customer"}
+
+	mockUserRepo.On("GetUserByID", ctx, int64(1)).Return(user, nil)
+	mockSessionRepo.On("GetSession", ctx, int64(1), "sess_1").Return(&entity.SessionInfo{SessionID: "sess_1"}, nil)
+	mockJWT.On("GenerateJWTWithSession", int64(1), "john@example.com", "Customer", "sess_1", false).Return("new-token", nil)
+	mockSessionRepo.On("StoreToken", ctx, int64(1), "sess_1", "new-token", false).Return(nil)
+	mockBlacklistRepo.On("AddToBlacklist", ctx, mock.AnythingOfType("string"), int64(1640995200)).Return(nil)
+
+	refreshedUser, token, err := service.RefreshSession(ctx, 1, "sess_1", "old-token", 1640995200)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new-token", token)
+	assert.Equal(t, user, refreshedUser)
+	mockSessionRepo.AssertExpectations(t)
+	mockBlacklistRepo.AssertExpectations(t)
+}
+
+func TestUserService_RefreshSession_RequiresSession(t *testing.T) {
+	service := service.NewUserService(service.UserServiceDeps{
+		Config: &config.Config{},
+	})
+
+	_, _, err := service.RefreshSession(context.Background(), 1, "", "old-token", 1640995200)
+
+	assert.EqualError(t, err, "session required")
+}
+
+func TestUserService_RefreshSession_UserNotFound(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo},
+		Config:          &config.Config{},
+	})
+
+	ctx := context.Background()
+	mockUserRepo.On("GetUserByID", ctx, int64(1)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, _, err := service.RefreshSession(ctx, 1, "sess_1", "old-token", 1640995200)
+
+	assert.EqualError(t, err, "user not found")
+}
+
+func TestUserService_RefreshSession_StoreFails(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockJWT := new(mocks.MockJWTUtil)
+	mockBlacklistRepo := new(mocks.MockBlacklistTokenRepository)
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:           mockUserRepo,
+			SessionRepo:        mockSessionRepo,
+			JWTUtil:            mockJWT,
+			BlacklistTokenRepo: mockBlacklistRepo,
+		},
+		Config: &config.Config{},
+	})
+
+	ctx := context.Background()
+	mockUserRepo.On("GetUserByID", ctx, int64(1)).Return(&entity.UserEntity{ID: 1, Email: "john@example.com", RoleName: "Customer"}, nil)
+	mockSessionRepo.On("GetSession", ctx, int64(1), "sess_1").Return(&entity.SessionInfo{SessionID: "sess_1"}, nil)
+	mockJWT.On("GenerateJWTWithSession", int64(1), "john@example.com", "Customer", "sess_1", false).Return("new-token", nil)
+	mockSessionRepo.On("StoreToken", ctx, int64(1), "sess_1", "new-token", false).Return(errors.New("redis down"))
+
+	_, _, err := service.RefreshSession(ctx, 1, "sess_1", "old-token", 1640995200)
+
+	assert.EqualError(t, err, "failed to create session")
+	mockBlacklistRepo.AssertNotCalled(t, "AddToBlacklist", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUserService_RefreshSession_KeepsRememberMe(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockJWT := new(mocks.MockJWTUtil)
+	mockBlacklistRepo := new(mocks.MockBlacklistTokenRepository)
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:           mockUserRepo,
+			SessionRepo:        mockSessionRepo,
+			JWTUtil:            mockJWT,
+			BlacklistTokenRepo: mockBlacklistRepo,
+		},
+		Config: &config.Config{},
+	})
+
+	ctx := context.Background()
+	mockUserRepo.On("GetUserByID", ctx, int64(1)).Return(&entity.UserEntity{ID: 1, Email: "john@example.com", RoleName: "Customer"}, nil)
+	mockSessionRepo.On("GetSession", ctx, int64(1), "sess_1").Return(&entity.SessionInfo{SessionID: "sess_1", RememberMe: true}, nil)
+	mockJWT.On("GenerateJWTWithSession", int64(1), "john@example.com", "Customer", "sess_1", true).Return("new-token", nil)
+	mockSessionRepo.On("StoreToken", ctx, int64(1), "sess_1", "new-token", true).Return(nil)
+	mockBlacklistRepo.On("AddToBlacklist", ctx, mock.AnythingOfType("string"), int64(1640995200)).Return(nil)
+
+	_, token, err := service.RefreshSession(ctx, 1, "sess_1", "old-token", 1640995200)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new-token", token)
+	mockJWT.AssertExpectations(t)
+	mockSessionRepo.AssertExpectations(t)
+}
+
+func TestUserService_RefreshSession_UnreadableSessionUsesShortLifetime(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockSessionRepo := new(mocks.MockSessionRepository)
+	mockJWT := new(mocks.MockJWTUtil)
+	mockBlacklistRepo := new(mocks.MockBlacklistTokenRepository)
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:           mockUserRepo,
+			SessionRepo:        mockSessionRepo,
+			JWTUtil:            mockJWT,
+			BlacklistTokenRepo: mockBlacklistRepo,
+		},
+		Config: &config.Config{},
+	})
+
+	ctx := context.Background()
+	mockUserRepo.On("GetUserByID", ctx, int64(1)).Return(&entity.UserEntity{ID: 1, Email: "john@example.com", RoleName: "Customer"}, nil)
+	mockSessionRepo.On("GetSession", ctx, int64(1), "sess_1").Return(nil, errors.New("redis down"))
+	mockJWT.On("GenerateJWTWithSession", int64(1), "john@example.com", "Customer", "sess_1", false).Return("new-token", nil)
+	mockSessionRepo.On("StoreToken", ctx, int64(1), "sess_1", "new-token", false).Return(nil)
+	mockBlacklistRepo.On("AddToBlacklist", ctx, mock.AnythingOfType("string"), int64(1640995200)).Return(nil)
+
+	_, _, err := service.RefreshSession(ctx, 1, "sess_1", "old-token", 1640995200)
+
+	assert.NoError(t, err)
+	mockJWT.AssertExpectations(t)
+}
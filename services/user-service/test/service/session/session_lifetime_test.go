@@ -0,0 +1,49 @@
+package main
+
+import (
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessions_Validate(t *testing.T) {
+	assert.NoError(t, config.Sessions{}.Validate())
+	assert.NoError(t, config.Sessions{TTL: "8h", RememberMeTTL: "1440h"}.Validate())
+
+	assert.EqualError(t, config.Sessions{TTL: "half a day"}.Validate(), `SESSION_TTL: invalid duration "half a day"`)
+	assert.ErrorContains(t, config.Sessions{TTL: "720h"}.Validate(), "SESSION_TTL: 720h0m0s is outside")
+	assert.ErrorContains(t, config.Sessions{RememberMeTTL: "4320h"}.Validate(), "SESSION_REMEMBER_ME_TTL: 4320h0m0s is outside")
+	assert.ErrorContains(t, config.Sessions{TTL: "24h", RememberMeTTL: "6h"}.Validate(), "shorter than SESSION_TTL")
+}
+
+func TestSessions_Lifetime(t *testing.T) {
+	assert.Equal(t, 12*time.Hour, config.Sessions{}.Lifetime(false))
+	assert.Equal(t, 30*24*time.Hour, config.Sessions{}.Lifetime(true))
+
+	sessions := config.Sessions{TTL: "2h", RememberMeTTL: "168h"}
+	assert.Equal(t, 2*time.Hour, sessions.Lifetime(false))
+	assert.Equal(t, 7*24*time.Hour, sessions.Lifetime(true))
+
+	// Values past the ceilings never reach a token
+	assert.Equal(t, config.DefaultSessionTTL, config.Sessions{TTL: "720h"}.Lifetime(false))
+	assert.Equal(t, config.DefaultRememberMeSessionTTL, config.Sessions{RememberMeTTL: "4320h"}.Lifetime(true))
+}
+
+func TestGenerateJWTWithSession_ExpiryFollowsRememberMe(t *testing.T) {
+	cfg := &config.Config{App: config.App{JwtSecretKey: "test-secret", JwtIssuer: "user-service"}}
+
+	short, err := utils.GenerateJWTWithSession(cfg, 1, "john@example.com", "Customer", "sess_1", false)
+	assert.NoError(t, err)
+	claims, err := utils.ValidateJWT(cfg, short)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(12*time.Hour), claims.ExpiresAt.Time, time.Minute)
+
+	long, err := utils.GenerateJWTWithSession(cfg, 1, "john@example.com", "Customer", "sess_2", true)
+	assert.NoError(t, err)
+	claims, err = utils.ValidateJWT(cfg, long)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(30*24*time.Hour), claims.ExpiresAt.Time, time.Minute)
+}
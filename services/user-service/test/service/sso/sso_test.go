@@ -0,0 +1,467 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/adapter/handler"
+	"user-service/internal/adapter/middleware"
+	"user-service/internal/adapter/oidc"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type ssoFixture struct {
+	provider    *mocks.MockOIDCProvider
+	stateRepo   *mocks.MockSSOStateRepository
+	ssoRepo     *mocks.MockSSORepository
+	userRepo    *mocks.MockUserRepository
+	sessionRepo *mocks.MockSessionRepository
+	signIn      *mocks.MockUserService
+	auditRepo   *mocks.MockAuditLogRepository
+	cfg         *config.Config
+	service     port.SSOServiceInterface
+}
+
+func newSSOFixture(jit bool) *ssoFixture {
+	f := &ssoFixture{
+		provider:    new(mocks.MockOIDCProvider),
+		stateRepo:   new(mocks.MockSSOStateRepository),
+		ssoRepo:     new(mocks.MockSSORepository),
+		userRepo:    new(mocks.MockUserRepository),
+		sessionRepo: new(mocks.MockSessionRepository),
+		signIn:      new(mocks.MockUserService),
+		auditRepo:   new(mocks.MockAuditLogRepository),
+		cfg: &config.Config{
+			PasswordHashing: config.PasswordHashing{BcryptCost: 4},
+			SSO: config.SSO{
+				Enabled:  true,
+				Issuer:   "https://idp.toko.id",
+				ClientID: "admin-panel",
+				GroupRoles: []config.SSOGroupRole{
+					{Group: "admins", Role: "Super Admin"},
+					{Group: "ops", Role: "Operator"},
+				},
+				JITProvisioning: jit,
+			},
+		},
+	}
+	f.service = service.NewSSOService(f.provider, f.stateRepo, f.ssoRepo, f.userRepo, f.sessionRepo, f.signIn, service.NewAuditLogService(f.auditRepo), f.cfg)
+	return f
+}
+
+// expectCallback sets up the state and the identity the provider returns for code "code-1"
+func (f *ssoFixture) expectCallback(ctx context.Context, identity *entity.OIDCIdentityEntity) {
+	f.stateRepo.On("TakeState", ctx, "state-1").Return(&entity.SSOStateEntity{
+		ID:           "state-1",
+		Nonce:        "nonce-1",
+		CodeVerifier: "verifier-1",
+		RememberMe:   true,
+	}, nil)
+	f.provider.On("Exchange", ctx, "code-1", "verifier-1").Return(identity, nil)
+}
+
+func (f *ssoFixture) expectAudit(event string, userID int64) {
+	f.auditRepo.On("Create", mock.Anything, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == event && auditLog.UserID == userID
+	})).Return(nil).Once()
+}
+
+func verified(value bool) *bool {
+	return &value
+}
+
+func TestSSOStartLogin_SavesStateAndSendsPKCEChallenge(t *testing.T) {
+	ctx := context.Background()
+	f := newSSOFixture(false)
+
+	var challenge string
+	f.provider.On("AuthCodeURL", ctx, mock.AnythingOfType("string"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		challenge = args.String(3)
+	}).Return("https://idp.toko.id/authorize?x=1", nil)
+	f.stateRepo.On("SaveState", ctx, mock.AnythingOfType("*entity.SSOStateEntity")).Return(nil)
+
+	authURL, state, err := f.service.StartLogin(ctx, true)
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://idp.toko.id/authorize?x=1", authURL)
+	assert.Len(t, state.ID, 32)
+	assert.Len(t, state.CodeVerifier, 64)
+	assert.NotEqual(t, state.ID, state.Nonce)
+	assert.True(t, state.RememberMe)
+	assert.WithinDuration(t, time.Now().Add(10*time.Minute), state.ExpiresAt, time.Minute)
+	// The challenge is derived from the verifier, never the verifier itself
+	assert.NotEmpty(t, challenge)
+	assert.NotEqual(t, state.CodeVerifier, challenge)
+}
+
+func TestSSOStartLogin_ProviderDown(t *testing.T) {
+	ctx := context.Background()
+	f := newSSOFixture(false)
+
+	f.provider.On("AuthCodeURL", ctx, mock.Anything, mock.Anything, mock.Anything).Return("", errors.New("connection refused"))
+
+	_, _, err := f.service.StartLogin(ctx, false)
+
+	assert.EqualError(t, err, "sso provider unavailable")
+	f.stateRepo.AssertNotCalled(t, "SaveState", mock.Anything, mock.Anything)
+}
+
+func TestSSOCompleteLogin_ProvisionsStaffWhenJITIsOn(t *testing.T) {
+	ctx := context.Background()
+	f := newSSOFixture(true)
+
+	f.expectCallback(ctx, &entity.OIDCIdentityEntity{
+		Subject: "sub-1", Email: "Sari@Toko.ID", EmailVerified: verified(true), Name: "Sari", Groups: []string{"staff", "ops"}, Nonce: "nonce-1",
+	})
+	f.ssoRepo.On("GetUserIDBySubject", ctx, "sub-1").Return(int64(0), gorm.ErrRecordNotFound)
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "sari@toko.id").Return(nil, gorm.ErrRecordNotFound)
+	f.ssoRepo.On("CreateUser", ctx, mock.MatchedBy(func(user *entity.UserEntity) bool {
+		return user.Email == "sari@toko.id" && user.Name == "Sari"
+	}), mock.AnythingOfType("string"), "Operator", "sub-1").Return(int64(9), nil)
+	f.userRepo.On("GetUserByID", ctx, int64(9)).Return(&entity.UserEntity{ID: 9, RoleName: "Operator"}, nil)
+	f.signIn.On("SignInExternal", ctx, int64(9), entity.ClientEntity{IPAddress: "10.0.0.1"}, true).Return(&entity.UserEntity{ID: 9, RoleName: "Operator"}, "jwt-9", nil)
+	f.expectAudit(entity.AuditEventSSOUserProvisioned, 9)
+	f.expectAudit(entity.AuditEventSSOSignIn, 9)
+
+	user, token, err := f.service.CompleteLogin(ctx, "state-1", "code-1", entity.ClientEntity{IPAddress: "10.0.0.1"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), user.ID)
+	assert.Equal(t, "jwt-9", token)
+	f.ssoRepo.AssertExpectations(t)
+	f.auditRepo.AssertExpectations(t)
+	f.userRepo.AssertNotCalled(t, "UpdateUserRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSSOCompleteLogin_NotProvisionedWhenJITIsOff(t *testing.T) {
+	ctx := context.Background()
+	f := newSSOFixture(false)
+
+	f.expectCallback(ctx, &entity.OIDCIdentityEntity{
+		Subject: "sub-1", Email: "sari@toko.id", EmailVerified: verified(true), Groups: []string{"ops"}, Nonce: "nonce-1",
+	})
+	f.ssoRepo.On("GetUserIDBySubject", ctx, "sub-1").Return(int64(0), gorm.ErrRecordNotFound)
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "sari@toko.id").Return(nil, gorm.ErrRecordNotFound)
+
+	_, _, err := f.service.CompleteLogin(ctx, "state-1", "code-1", entity.ClientEntity{})
+
+	assert.EqualError(t, err, "sso account not provisioned")
+	f.ssoRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSSOCompleteLogin_LinksExistingStaffByEmail(t *testing.T) {
+	ctx := context.Background()
+	f := newSSOFixture(false)
+
+	f.expectCallback(ctx, &entity.OIDCIdentityEntity{
+		Subject: "sub-2", Email: "andi@toko.id", EmailVerified: verified(true), Groups: []string{"admins"}, Nonce: "nonce-1",
+	})
+	f.ssoRepo.On("GetUserIDBySubject", ctx, "sub-2").Return(int64(0), gorm.ErrRecordNotFound)
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "andi@toko.id").Return(&entity.UserEntity{ID: 4, RoleName: "Super Admin"}, nil)
+	f.ssoRepo.On("LinkSubject", ctx, int64(4), "sub-2").Return(nil)
+	f.userRepo.On("GetUserByID", ctx, int64(4)).Return(&entity.UserEntity{ID: 4, RoleName: "Super Admin"}, nil)
+	f.signIn.On("SignInExternal", ctx, int64(4), entity.ClientEntity{}, true).Return(&entity.UserEntity{ID: 4}, "jwt-4", nil)
+	f.expectAudit(entity.AuditEventSSOSignIn, 4)
+
+	_, token, err := f.service.CompleteLogin(ctx, "state-1", "code-1", entity.ClientEntity{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "jwt-4", token)
+	f.ssoRepo.AssertExpectations(t)
+}
+
+func TestSSOCompleteLogin_RefusesCustomerAccount(t *testing.T) {
+	ctx := context.Background()
+	f := newSSOFixture(true)
+
+	f.expectCallback(ctx, &entity.OIDCIdentityEntity{
+		Subject: "sub-3", Email: "budi@toko.id", EmailVerified: verified(true), Groups: []string{"ops"}, Nonce: "nonce-1",
+	})
+	f.ssoRepo.On("GetUserIDBySubject", ctx, "sub-3").Return(int64(0), gorm.ErrRecordNotFound)
+	f.userRepo.On("GetUserByEmailIncludingUnverified", ctx, "budi@toko.id").Return(&entity.UserEntity{ID: 12, RoleName: "Customer"}, nil)
+	f.expectAudit(entity.AuditEventSSOSignInRefused, 12)
+
+	_, _, err := f.service.CompleteLogin(ctx, "state-1", "code-1", entity.ClientEntity{})
+
+	assert.EqualError(t, err, "sso email belongs to a non-staff account")
+	f.ssoRepo.AssertNotCalled(t, "LinkSubject", mock.Anything, mock.Anything, mock.Anything)
+	f.signIn.AssertNotCalled(t, "SignInExternal", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	f.auditRepo.AssertExpectations(t)
+}
+
+func TestSSOCompleteLogin_RequiresVerifiedEmailToLink(t *testing.T) {
+	ctx := context.Background()
+	f := newSSOFixture(true)
+
+	f.expectCallback(ctx, &entity.OIDCIdentityEntity{
+		Subject: "sub-4", Email: "andi@toko.id", EmailVerified: verified(false), Groups: []string{"admins"}, Nonce: "nonce-1",
+	})
+	f.ssoRepo.On("GetUserIDBySubject", ctx, "sub-4").Return(int64(0), gorm.ErrRecordNotFound)
+
+	_, _, err := f.service.CompleteLogin(ctx, "state-1", "code-1", entity.ClientEntity{})
+
+	assert.EqualError(t, err, "sso email is not verified")
+	f.userRepo.AssertNotCalled(t, "GetUserByEmailIncludingUnverified", mock.Anything, mock.Anything)
+}
+
+func TestSSOCompleteLogin_RequiresEmailVerifiedClaimToLink(t *testing.T) {
+	ctx := context.Background()
+	f := newSSOFixture(true)
+
+	f.expectCallback(ctx, &entity.OIDCIdentityEntity{
+		Subject: "sub-5", Email: "andi@toko.id", Groups: []string{"admins"}, Nonce: "nonce-1",
+	})
+	f.ssoRepo.On("GetUserIDBySubject", ctx, "sub-5").Return(int64(0), gorm.ErrRecordNotFound)
+
+	_, _, err := f.service.CompleteLogin(ctx, "state-1", "code-1", entity.ClientEntity{})
+
+	assert.EqualError(t, err, "sso email is not verified")
+	f.userRepo.AssertNotCalled(t, "GetUserByEmailIncludingUnverified", mock.Anything, mock.Anything)
+	f.ssoRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSSOCompleteLogin_NoMappedGroup(t *testing.T) {
+	ctx := context.Background()
+	f := newSSOFixture(true)
+
+	f.expectCallback(ctx, &entity.OIDCIdentityEntity{Subject: "sub-5", Groups: []string{"finance"}, Nonce: "nonce-1"})
+
+	_, _, err := f.service.CompleteLogin(ctx, "state-1", "code-1", entity.ClientEntity{})
+
+	assert.EqualError(t, err, "sso user has no staff role")
+	f.ssoRepo.AssertNotCalled(t, "GetUserIDBySubject", mock.Anything, mock.Anything)
+}
+
+func TestSSOCompleteLogin_NonceMismatch(t *testing.T) {
+	ctx := context.Background()
+	f := newSSOFixture(true)
+
+	f.expectCallback(ctx, &entity.OIDCIdentityEntity{Subject: "sub-1", Groups: []string{"admins"}, Nonce: "nonce-other"})
+
+	_, _, err := f.service.CompleteLogin(ctx, "state-1", "code-1", entity.ClientEntity{})
+
+	assert.EqualError(t, err, "sso code exchange failed")
+	f.ssoRepo.AssertNotCalled(t, "GetUserIDBySubject", mock.Anything, mock.Anything)
+}
+
+func TestSSOCompleteLogin_StateUsedTwice(t *testing.T) {
+	ctx := context.Background()
+	f := newSSOFixture(true)
+
+	f.stateRepo.On("TakeState", ctx, "state-1").Return(nil, errors.New("sso state not found"))
+
+	_, _, err := f.service.CompleteLogin(ctx, "state-1", "code-1", entity.ClientEntity{})
+
+	assert.EqualError(t, err, "sso state not found")
+	f.provider.AssertNotCalled(t, "Exchange", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSSOCompleteLogin_SyncsRoleAndRevokesSessions(t *testing.T) {
+	ctx := context.Background()
+	f := newSSOFixture(false)
+
+	// The user moved from ops to admins at the provider
+	f.expectCallback(ctx, &entity.OIDCIdentityEntity{Subject: "sub-6", Groups: []string{"admins"}, Nonce: "nonce-1"})
+	f.ssoRepo.On("GetUserIDBySubject", ctx, "sub-6").Return(int64(6), nil)
+	f.userRepo.On("GetUserByID", ctx, int64(6)).Return(&entity.UserEntity{ID: 6, RoleName: "Operator"}, nil)
+	f.userRepo.On("GetRoleByName", ctx, "Super Admin").Return(&entity.RoleEntity{ID: 1, Name: "Super Admin"}, nil)
+	f.userRepo.On("UpdateUserRole", ctx, int64(6), int64(1)).Return(nil)
+	f.sessionRepo.On("DeleteAllUserTokens", ctx, int64(6)).Return(nil)
+	f.signIn.On("SignInExternal", ctx, int64(6), entity.ClientEntity{}, true).Return(&entity.UserEntity{ID: 6, RoleName: "Super Admin"}, "jwt-6", nil)
+	f.expectAudit(entity.AuditEventSSORoleChanged, 6)
+	f.expectAudit(entity.AuditEventSSOSignIn, 6)
+
+	_, _, err := f.service.CompleteLogin(ctx, "state-1", "code-1", entity.ClientEntity{})
+
+	require.NoError(t, err)
+	f.userRepo.AssertExpectations(t)
+	f.sessionRepo.AssertExpectations(t)
+	f.auditRepo.AssertExpectations(t)
+}
+
+func TestSSOCallback_RejectsStateFromAnotherBrowser(t *testing.T) {
+	ssoService := new(mocks.MockSSOService)
+	h := handler.NewSSOHandler(ssoService, &config.Config{})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/sso/callback", strings.NewReader(`{"code":"code-1","state":"state-attacker"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.AddCookie(&http.Cookie{Name: middleware.SSOStateCookieName, Value: "state-1"})
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, h.Callback(e.NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	ssoService.AssertNotCalled(t, "CompleteLogin", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSSOCallback_MapsNonStaffAccountToConflict(t *testing.T) {
+	ssoService := new(mocks.MockSSOService)
+	ssoService.On("CompleteLogin", mock.Anything, "state-1", "code-1", mock.Anything).Return(nil, "", errors.New("sso email belongs to a non-staff account"))
+	h := handler.NewSSOHandler(ssoService, &config.Config{})
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/sso/callback", strings.NewReader(`{"code":"code-1","state":"state-1"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.AddCookie(&http.Cookie{Name: middleware.SSOStateCookieName, Value: "state-1"})
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, h.Callback(e.NewContext(req, rec)))
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	// The state cookie is single use whatever the outcome
+	assert.Contains(t, rec.Header().Get("Set-Cookie"), middleware.SSOStateCookieName+"=;")
+}
+
+// fakeIdP is a minimal OpenID provider: discovery, one RSA key and a token endpoint that
+// returns whatever ID token the test signs
+type fakeIdP struct {
+	server  *httptest.Server
+	key     *rsa.PrivateKey
+	idToken func(issuer string) jwt.MapClaims
+	form    url.Values
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	idp := &fakeIdP{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		idp.form = r.PostForm
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.idToken(idp.server.URL))
+		token.Header["kid"] = "key-1"
+		signed, _ := token.SignedString(key)
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "at", "token_type": "Bearer", "id_token": signed})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+	return idp
+}
+
+func (idp *fakeIdP) provider() port.OIDCProviderInterface {
+	return oidc.NewProvider(config.SSO{
+		Issuer:      idp.server.URL,
+		ClientID:    "admin-panel",
+		RedirectURL: "https://admin.toko.id/sso/callback",
+	}, idp.server.Client())
+}
+
+func validClaims(issuer string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":            issuer,
+		"aud":            "admin-panel",
+		"sub":            "sub-1",
+		"email":          "sari@toko.id",
+		"email_verified": true,
+		"name":           "Sari",
+		"nonce":          "nonce-1",
+		"groups":         []string{"ops"},
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(5 * time.Minute).Unix(),
+	}
+}
+
+func TestOIDCProvider_AuthCodeURL(t *testing.T) {
+	idp := newFakeIdP(t)
+
+	authURL, err := idp.provider().AuthCodeURL(context.Background(), "state-1", "nonce-1", "challenge-1")
+
+	require.NoError(t, err)
+	parsed, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "/authorize", parsed.Path)
+	query := parsed.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "admin-panel", query.Get("client_id"))
+	assert.Equal(t, "openid email profile", query.Get("scope"))
+	assert.Equal(t, "state-1", query.Get("state"))
+	assert.Equal(t, "nonce-1", query.Get("nonce"))
+	assert.Equal(t, "challenge-1", query.Get("code_challenge"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+}
+
+func TestOIDCProvider_ExchangeVerifiesIDToken(t *testing.T) {
+	idp := newFakeIdP(t)
+	idp.idToken = validClaims
+
+	identity, err := idp.provider().Exchange(context.Background(), "code-1", "verifier-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "code-1", idp.form.Get("code"))
+	assert.Equal(t, "verifier-1", idp.form.Get("code_verifier"))
+	assert.Equal(t, "sub-1", identity.Subject)
+	assert.Equal(t, "sari@toko.id", identity.Email)
+	require.NotNil(t, identity.EmailVerified)
+	assert.True(t, *identity.EmailVerified)
+	assert.Equal(t, "nonce-1", identity.Nonce)
+	assert.Equal(t, []string{"ops"}, identity.Groups)
+}
+
+func TestOIDCProvider_RejectsTokenForAnotherClient(t *testing.T) {
+	idp := newFakeIdP(t)
+	idp.idToken = func(issuer string) jwt.MapClaims {
+		claims := validClaims(issuer)
+		claims["aud"] = "storefront"
+		return claims
+	}
+
+	_, err := idp.provider().Exchange(context.Background(), "code-1", "verifier-1")
+
+	assert.Error(t, err)
+}
+
+func TestOIDCProvider_RejectsExpiredToken(t *testing.T) {
+	idp := newFakeIdP(t)
+	idp.idToken = func(issuer string) jwt.MapClaims {
+		claims := validClaims(issuer)
+		claims["exp"] = time.Now().Add(-time.Hour).Unix()
+		return claims
+	}
+
+	_, err := idp.provider().Exchange(context.Background(), "code-1", "verifier-1")
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+	"user-service/internal/startup"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testPolicy = startup.Policy{
+	InitialBackoff: 5 * time.Millisecond,
+	MaxBackoff:     20 * time.Millisecond,
+	Timeout:        200 * time.Millisecond,
+}
+
+func TestWaitFor_RetriesUntilDependencyIsUp(t *testing.T) {
+	var attempts int32
+
+	report := startup.WaitFor(context.Background(), testPolicy, startup.Dependency{
+		Name: "postgres",
+		Connect: func(ctx context.Context) error {
+			if atomic.AddInt32(&attempts, 1) < 3 {
+				return errors.New("connection refused")
+			}
+			return nil
+		},
+	})
+
+	assert.True(t, report.Ready("postgres"))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func TestWaitFor_GivesUpAfterTimeout(t *testing.T) {
+	started := time.Now()
+
+	report := startup.WaitFor(context.Background(), testPolicy,
+		startup.Dependency{Name: "redis", Connect: func(ctx context.Context) error { return nil }},
+		startup.Dependency{Name: "rabbitmq", Connect: func(ctx context.Context) error {
+			return errors.New("connection refused")
+		}},
+	)
+
+	assert.True(t, report.Ready("redis"))
+	assert.False(t, report.Ready("rabbitmq"))
+	assert.EqualError(t, report.Failed["rabbitmq"], "connection refused")
+	assert.Less(t, time.Since(started), time.Second)
+}
@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"user-service/internal/adapter/storage"
+	"user-service/internal/core/domain/entity"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const objectURL = "https://cdn.example.com/storage/v1/object/public/photos/"
+
+func sha256Hex(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// readsUpload makes the storage mock consume the upload like a real backend would
+func readsUpload(received *string) func(mock.Arguments) {
+	return func(args mock.Arguments) {
+		data, _ := io.ReadAll(args.Get(3).(io.Reader))
+		*received = string(data)
+	}
+}
+
+func TestContentAddressedStorage_UploadFile_RecordsNewObject(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mocks.MockStorage)
+	objects := new(mocks.MockStoredObjectRepository)
+	s := storage.NewContentAddressedStorage(inner, objects, "photos")
+
+	name := utils.ContentObjectName("7/", []byte("photo"), ".jpg")
+	var received string
+	objects.On("GetObject", ctx, "support", name).Return(nil, gorm.ErrRecordNotFound)
+	inner.On("UploadFile", ctx, "support", name, mock.Anything, "image/jpeg").Run(readsUpload(&received)).Return(objectURL+name, nil)
+	objects.On("AddReference", ctx, mock.MatchedBy(func(o *entity.StoredObjectEntity) bool {
+		return o.Bucket == "support" && o.ObjectName == name && o.SHA256 == sha256Hex("photo") && o.Size == 5 && o.URL == objectURL+name
+	})).Return(&entity.StoredObjectEntity{RefCount: 1}, nil)
+
+	url, err := s.UploadFile(ctx, "support", name, strings.NewReader("photo"), "image/jpeg")
+
+	require.NoError(t, err)
+	assert.Equal(t, objectURL+name, url)
+	assert.Equal(t, "photo", received)
+	objects.AssertExpectations(t)
+}
+
+func TestContentAddressedStorage_UploadFile_ReusesStoredObject(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mocks.MockStorage)
+	objects := new(mocks.MockStoredObjectRepository)
+	s := storage.NewContentAddressedStorage(inner, objects, "photos")
+
+	existing := &entity.StoredObjectEntity{ObjectName: "7/abc.jpg", URL: objectURL + "7/abc.jpg", RefCount: 1}
+	objects.On("GetObject", ctx, "photos", "7/abc.jpg").Return(existing, nil)
+	objects.On("AddReference", ctx, existing).Return(&entity.StoredObjectEntity{RefCount: 2}, nil)
+
+	url, err := s.UploadFile(ctx, "", "7/abc.jpg", strings.NewReader("photo"), "image/jpeg")
+
+	require.NoError(t, err)
+	assert.Equal(t, existing.URL, url)
+	inner.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestContentAddressedStorage_UploadFile_NamesUnnamedUploadsByContent(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mocks.MockStorage)
+	objects := new(mocks.MockStoredObjectRepository)
+	s := storage.NewContentAddressedStorage(inner, objects, "photos")
+
+	name := "profile-" + sha256Hex("png bytes") + ".png"
+	var received string
+	objects.On("GetObject", ctx, "photos", name).Return(nil, gorm.ErrRecordNotFound)
+	inner.On("UploadFile", ctx, "", name, mock.Anything, "image/png").Run(readsUpload(&received)).Return(objectURL+name, nil)
+	objects.On("AddReference", ctx, mock.Anything).Return(&entity.StoredObjectEntity{RefCount: 1}, nil)
+
+	url, err := s.UploadFile(ctx, "", "", strings.NewReader("png bytes"), "image/png")
+
+	require.NoError(t, err)
+	assert.Equal(t, objectURL+name, url)
+	assert.Equal(t, "png bytes", received, "the spooled copy is uploaded in full")
+}
+
+func TestContentAddressedStorage_UploadFile_ReusesObjectStoredConcurrently(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mocks.MockStorage)
+	objects := new(mocks.MockStoredObjectRepository)
+	s := storage.NewContentAddressedStorage(inner, objects, "photos")
+
+	existing := &entity.StoredObjectEntity{ObjectName: "7/abc.jpg", URL: objectURL + "7/abc.jpg", RefCount: 1}
+	objects.On("GetObject", ctx, "photos", "7/abc.jpg").Return(nil, gorm.ErrRecordNotFound).Once()
+	inner.On("UploadFile", ctx, "", "7/abc.jpg", mock.Anything, "image/jpeg").Return("", errors.New("upload failed with status 400: The resource already exists"))
+	objects.On("GetObject", ctx, "photos", "7/abc.jpg").Return(existing, nil).Once()
+	objects.On("AddReference", ctx, existing).Return(&entity.StoredObjectEntity{RefCount: 2}, nil)
+
+	url, err := s.UploadFile(ctx, "", "7/abc.jpg", strings.NewReader("photo"), "image/jpeg")
+
+	require.NoError(t, err)
+	assert.Equal(t, existing.URL, url)
+}
+
+func TestContentAddressedStorage_UploadFile_ReturnsUploadError(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mocks.MockStorage)
+	objects := new(mocks.MockStoredObjectRepository)
+	s := storage.NewContentAddressedStorage(inner, objects, "photos")
+
+	objects.On("GetObject", ctx, "photos", "7/abc.jpg").Return(nil, gorm.ErrRecordNotFound)
+	inner.On("UploadFile", ctx, "", "7/abc.jpg", mock.Anything, "image/jpeg").Return("", errors.New("upload failed with status 502"))
+
+	_, err := s.UploadFile(ctx, "", "7/abc.jpg", strings.NewReader("photo"), "image/jpeg")
+
+	assert.EqualError(t, err, "upload failed with status 502")
+	objects.AssertNotCalled(t, "AddReference", mock.Anything, mock.Anything)
+}
+
+func TestContentAddressedStorage_DeleteFile_KeepsReferencedObject(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mocks.MockStorage)
+	objects := new(mocks.MockStoredObjectRepository)
+	s := storage.NewContentAddressedStorage(inner, objects, "photos")
+
+	objects.On("RemoveReference", ctx, "photos", "7/abc.jpg").Return(1, nil)
+
+	require.NoError(t, s.DeleteFile(ctx, "", "7/abc.jpg"))
+	inner.AssertNotCalled(t, "DeleteFile", mock.Anything, mock.Anything, mock.Anything)
+	objects.AssertNotCalled(t, "DeleteUnreferenced", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestContentAddressedStorage_DeleteFile_DeletesLastReference(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mocks.MockStorage)
+	objects := new(mocks.MockStoredObjectRepository)
+	s := storage.NewContentAddressedStorage(inner, objects, "photos")
+
+	objects.On("RemoveReference", ctx, "photos", "7/abc.jpg").Return(0, nil)
+	objects.On("DeleteUnreferenced", ctx, "photos", "7/abc.jpg").Return(true, nil)
+	inner.On("DeleteFile", ctx, "", "7/abc.jpg").Return(nil)
+
+	require.NoError(t, s.DeleteFile(ctx, "", "7/abc.jpg"))
+	inner.AssertExpectations(t)
+}
+
+func TestContentAddressedStorage_DeleteFile_KeepsObjectReusedMeanwhile(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mocks.MockStorage)
+	objects := new(mocks.MockStoredObjectRepository)
+	s := storage.NewContentAddressedStorage(inner, objects, "photos")
+
+	objects.On("RemoveReference", ctx, "photos", "7/abc.jpg").Return(0, nil)
+	objects.On("DeleteUnreferenced", ctx, "photos", "7/abc.jpg").Return(false, nil)
+
+	require.NoError(t, s.DeleteFile(ctx, "", "7/abc.jpg"))
+	inner.AssertNotCalled(t, "DeleteFile", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestContentAddressedStorage_DeleteFile_DeletesUnrecordedObject(t *testing.T) {
+	ctx := context.Background()
+	inner := new(mocks.MockStorage)
+	objects := new(mocks.MockStoredObjectRepository)
+	s := storage.NewContentAddressedStorage(inner, objects, "photos")
+
+	objects.On("RemoveReference", ctx, "photos", "profile-legacy.jpg").Return(0, gorm.ErrRecordNotFound)
+	inner.On("DeleteFile", ctx, "", "profile-legacy.jpg").Return(nil)
+
+	require.NoError(t, s.DeleteFile(ctx, "", "profile-legacy.jpg"))
+	inner.AssertExpectations(t)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"user-service/internal/adapter/httpclient"
+	"user-service/internal/adapter/storage"
+	"user-service/internal/core/port"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uploadServer stores the "file" form field of each upload in received
+func uploadServer(t *testing.T, received *[]byte) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		*received, _ = io.ReadAll(file)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newSupabaseStorage(t *testing.T, url string, maxSize int64) port.StorageInterface {
+	s, err := storage.NewSupabaseStorage(url, "key", "photos", 5*time.Second, maxSize, httpclient.New(httpclient.Options{Name: "test"}))
+	require.NoError(t, err)
+	return s
+}
+
+func TestSupabaseStorage_UploadFile_StreamsContent(t *testing.T) {
+	var received []byte
+	server := uploadServer(t, &received)
+	s := newSupabaseStorage(t, server.URL, 1<<20)
+
+	content := bytes.Repeat([]byte("sayur"), 100000)
+	url, err := s.UploadFile(context.Background(), "", "photo.jpg", bytes.NewReader(content), "image/jpeg")
+
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/storage/v1/object/public/photos/photo.jpg", url)
+	assert.Equal(t, content, received)
+}
+
+func TestSupabaseStorage_UploadFile_AcceptsFileAtLimit(t *testing.T) {
+	var received []byte
+	server := uploadServer(t, &received)
+	s := newSupabaseStorage(t, server.URL, 10)
+
+	_, err := s.UploadFile(context.Background(), "", "photo.jpg", strings.NewReader("0123456789"), "image/jpeg")
+
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(received))
+}
+
+func TestSupabaseStorage_UploadFile_RejectsFileOverLimit(t *testing.T) {
+	var received []byte
+	server := uploadServer(t, &received)
+	s := newSupabaseStorage(t, server.URL, 10)
+
+	_, err := s.UploadFile(context.Background(), "", "photo.jpg", strings.NewReader("0123456789a"), "image/jpeg")
+
+	assert.ErrorIs(t, err, port.ErrFileTooLarge)
+	assert.Nil(t, received, "an oversized file is never stored")
+}
+
+func TestSupabaseStorage_UploadFile_RejectsEmptyFile(t *testing.T) {
+	var received []byte
+	server := uploadServer(t, &received)
+	s := newSupabaseStorage(t, server.URL, 10)
+
+	_, err := s.UploadFile(context.Background(), "", "photo.jpg", strings.NewReader(""), "image/jpeg")
+
+	require.Error(t, err)
+	assert.Equal(t, "file content is empty", err.Error())
+}
+
+func TestSupabaseStorage_UploadFile_ReportsServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("bucket not found"))
+	}))
+	t.Cleanup(server.Close)
+	s := newSupabaseStorage(t, server.URL, 1<<20)
+
+	_, err := s.UploadFile(context.Background(), "", "photo.jpg", strings.NewReader("photo"), "image/jpeg")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "upload failed with status 400")
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/adapter/handler"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	webhookSecret = "storage-webhook-secret"
+	photoURL      = "https://test.supabase.co/storage/v1/object/public/profile-images/profile-uuid.jpg"
+)
+
+type storageEventFixture struct {
+	userRepo *mocks.MockUserRepository
+	objects  *mocks.MockStoredObjectRepository
+	jobRepo  *mocks.MockJobRepository
+	storage  *mocks.MockStorage
+	service  port.StorageEventServiceInterface
+}
+
+func newStorageEventFixture() *storageEventFixture {
+	f := &storageEventFixture{
+		userRepo: new(mocks.MockUserRepository),
+		objects:  new(mocks.MockStoredObjectRepository),
+		jobRepo:  new(mocks.MockJobRepository),
+		storage:  new(mocks.MockStorage),
+	}
+	cfg := &config.Config{Supabase: config.Supabase{ProjectURL: "https://test.supabase.co/", BucketName: "profile-images"}}
+	f.service = service.NewStorageEventService(f.userRepo, f.objects, service.NewJobService(f.jobRepo), f.storage, cfg)
+	return f
+}
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	body := []byte(`{"type":"DELETE"}`)
+	now := time.Now()
+	header := utils.SignWebhookPayload(webhookSecret, now.Unix(), body)
+
+	assert.NoError(t, utils.VerifyWebhookSignature(webhookSecret, header, body, now))
+	assert.NoError(t, utils.VerifyWebhookSignature(webhookSecret, header, body, now.Add(4*time.Minute)))
+
+	for name, err := range map[string]error{
+		"wrong secret":  utils.VerifyWebhookSignature("other-secret", header, body, now),
+		"tampered body": utils.VerifyWebhookSignature(webhookSecret, header, []byte(`{"type":"INSERT"}`), now),
+		"replayed":      utils.VerifyWebhookSignature(webhookSecret, header, body, now.Add(10*time.Minute)),
+		"missing":       utils.VerifyWebhookSignature(webhookSecret, "", body, now),
+		"malformed":     utils.VerifyWebhookSignature(webhookSecret, "t=abc,v1=00", body, now),
+	} {
+		assert.ErrorIs(t, err, utils.ErrInvalidWebhookSignature, name)
+	}
+}
+
+func TestHandleEvent_DeletedObjectClearsProfilePhoto(t *testing.T) {
+	ctx := context.Background()
+	f := newStorageEventFixture()
+
+	f.objects.On("ForgetObject", ctx, "profile-images", "profile-uuid.jpg").Return(nil)
+	f.userRepo.On("ClearPhotoByURL", ctx, photoURL).Return([]int64{7}, nil)
+
+	err := f.service.HandleEvent(ctx, entity.StorageEventEntity{Type: entity.StorageEventDeleted, Bucket: "profile-images", ObjectName: "profile-uuid.jpg"})
+
+	assert.NoError(t, err)
+	f.userRepo.AssertExpectations(t)
+	f.objects.AssertExpectations(t)
+}
+
+func TestHandleEvent_CreatedProfilePhotoQueuesDelayedReconcile(t *testing.T) {
+	ctx := context.Background()
+	f := newStorageEventFixture()
+
+	f.jobRepo.On("Enqueue", ctx, mock.MatchedBy(func(job *entity.JobEntity) bool {
+		return job.Type == entity.JobTypeReconcileProfilePhoto && job.RunAt.After(time.Now().Add(10*time.Minute)) &&
+			strings.Contains(string(job.Payload), `"object_name":"profile-uuid.jpg"`)
+	})).Return(&entity.JobEntity{ID: 1}, nil)
+
+	err := f.service.HandleEvent(ctx, entity.StorageEventEntity{Type: entity.StorageEventCreated, Bucket: "profile-images", ObjectName: "profile-uuid.jpg"})
+
+	assert.NoError(t, err)
+	f.jobRepo.AssertExpectations(t)
+}
+
+func TestHandleEvent_IgnoresOtherBucketsAndFiles(t *testing.T) {
+	ctx := context.Background()
+	f := newStorageEventFixture()
+
+	for _, event := range []entity.StorageEventEntity{
+		{Type: entity.StorageEventDeleted, Bucket: "marketing", ObjectName: "profile-uuid.jpg"},
+		{Type: entity.StorageEventCreated, Bucket: "profile-images", ObjectName: "vendor-kyc/3/ktp-uuid.pdf"},
+		{Type: entity.StorageEventCreated, Bucket: "profile-images", ObjectName: "uploads/7/uuid.pdf"},
+	} {
+		assert.NoError(t, f.service.HandleEvent(ctx, event))
+	}
+
+	f.userRepo.AssertNotCalled(t, "ClearPhotoByURL", mock.Anything, mock.Anything)
+	f.jobRepo.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+}
+
+func TestReconcileProfilePhoto(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deletes a photo no profile uses", func(t *testing.T) {
+		f := newStorageEventFixture()
+		f.userRepo.On("IsPhotoReferenced", ctx, photoURL).Return(false, nil)
+		f.storage.On("DeleteFile", ctx, "profile-images", "profile-uuid.jpg").Return(nil)
+
+		assert.NoError(t, f.service.ReconcileProfilePhoto(ctx, "profile-images", "profile-uuid.jpg"))
+		f.storage.AssertExpectations(t)
+	})
+
+	t.Run("keeps a photo in use", func(t *testing.T) {
+		f := newStorageEventFixture()
+		f.userRepo.On("IsPhotoReferenced", ctx, photoURL).Return(true, nil)
+
+		assert.NoError(t, f.service.ReconcileProfilePhoto(ctx, "profile-images", "profile-uuid.jpg"))
+		f.storage.AssertNotCalled(t, "DeleteFile", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("retries when the check fails", func(t *testing.T) {
+		f := newStorageEventFixture()
+		f.userRepo.On("IsPhotoReferenced", ctx, photoURL).Return(false, errors.New("connection reset"))
+
+		assert.Error(t, f.service.ReconcileProfilePhoto(ctx, "profile-images", "profile-uuid.jpg"))
+		f.storage.AssertNotCalled(t, "DeleteFile", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func postStorageWebhook(h handler.StorageWebhookHandlerInterface, body, signature string) *httptest.ResponseRecorder {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/supabase/storage", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if signature != "" {
+		req.Header.Set(utils.WebhookSignatureHeader, signature)
+	}
+	rec := httptest.NewRecorder()
+	_ = h.ReceiveStorageEvent(e.NewContext(req, rec))
+	return rec
+}
+
+func TestReceiveStorageEvent(t *testing.T) {
+	deleted := `{"type":"DELETE","table":"objects","schema":"storage","record":null,"old_record":{"id":"a1","bucket_id":"profile-images","name":"profile-uuid.jpg"}}`
+
+	t.Run("rejects an unsigned request", func(t *testing.T) {
+		f := newStorageEventFixture()
+		rec := postStorageWebhook(handler.NewStorageWebhookHandler(f.service, webhookSecret), deleted, "")
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		f.userRepo.AssertNotCalled(t, "ClearPhotoByURL", mock.Anything, mock.Anything)
+	})
+
+	t.Run("is unavailable without a secret", func(t *testing.T) {
+		f := newStorageEventFixture()
+		rec := postStorageWebhook(handler.NewStorageWebhookHandler(f.service, ""), deleted, utils.SignWebhookPayload("", time.Now().Unix(), []byte(deleted)))
+
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("reconciles a signed delete", func(t *testing.T) {
+		f := newStorageEventFixture()
+		f.objects.On("ForgetObject", mock.Anything, "profile-images", "profile-uuid.jpg").Return(nil)
+		f.userRepo.On("ClearPhotoByURL", mock.Anything, photoURL).Return([]int64{7}, nil)
+
+		rec := postStorageWebhook(handler.NewStorageWebhookHandler(f.service, webhookSecret), deleted, utils.SignWebhookPayload(webhookSecret, time.Now().Unix(), []byte(deleted)))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		f.userRepo.AssertExpectations(t)
+	})
+
+	t.Run("treats a move as delete plus create", func(t *testing.T) {
+		f := newStorageEventFixture()
+		moved := `{"type":"UPDATE","table":"objects","schema":"storage","record":{"bucket_id":"profile-images","name":"archive/profile-uuid.jpg"},"old_record":{"bucket_id":"profile-images","name":"profile-uuid.jpg"}}`
+		f.objects.On("ForgetObject", mock.Anything, "profile-images", "profile-uuid.jpg").Return(nil)
+		f.userRepo.On("ClearPhotoByURL", mock.Anything, photoURL).Return([]int64{}, nil)
+
+		rec := postStorageWebhook(handler.NewStorageWebhookHandler(f.service, webhookSecret), moved, utils.SignWebhookPayload(webhookSecret, time.Now().Unix(), []byte(moved)))
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		f.userRepo.AssertExpectations(t)
+		// The new name is outside the profile photo naming, so nothing is queued
+		f.jobRepo.AssertNotCalled(t, "Enqueue", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var pngScreenshot = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...)
+
+// signingStorage adds signed links to the storage mock, like Supabase
+type signingStorage struct {
+	*mocks.MockStorage
+}
+
+func (s signingStorage) SignedURL(ctx context.Context, bucketName, objectName string, expiresIn time.Duration) (string, error) {
+	return "https://storage.example.com/sign/" + bucketName + "/" + objectName + "?ttl=" + expiresIn.String(), nil
+}
+
+type dispatched struct {
+	eventType string
+	eventID   string
+	data      map[string]interface{}
+}
+
+// recordingDispatcher stands in for the webhook service and remembers what was raised
+type recordingDispatcher struct {
+	events []dispatched
+}
+
+func (d *recordingDispatcher) Dispatch(ctx context.Context, eventType, eventID string, data interface{}) error {
+	d.events = append(d.events, dispatched{eventType: eventType, eventID: eventID, data: data.(map[string]interface{})})
+	return nil
+}
+
+type supportFixture struct {
+	supportRepo *mocks.MockSupportRepository
+	userRepo    *mocks.MockUserRepository
+	storage     *mocks.MockStorage
+	email       *mocks.MockEmailPublisher
+	webhooks    *recordingDispatcher
+	auditRepo   *mocks.MockAuditLogRepository
+	service     port.SupportServiceInterface
+}
+
+func newSupportFixture(support config.Support) *supportFixture {
+	f := &supportFixture{
+		supportRepo: new(mocks.MockSupportRepository),
+		userRepo:    new(mocks.MockUserRepository),
+		storage:     new(mocks.MockStorage),
+		email:       new(mocks.MockEmailPublisher),
+		webhooks:    &recordingDispatcher{},
+		auditRepo:   new(mocks.MockAuditLogRepository),
+	}
+	f.service = service.NewSupportService(f.supportRepo, f.userRepo, signingStorage{f.storage}, f.email, f.webhooks, service.NewAuditLogService(f.auditRepo), &config.Config{Support: support})
+	return f
+}
+
+var customer = &entity.UserEntity{ID: 5, Email: "sari@example.com", Name: "Sari", RoleName: "Customer"}
+
+func TestCreateTicket_UploadsAttachmentsAndForwardsToHelpdesk(t *testing.T) {
+	ctx := context.Background()
+	f := newSupportFixture(config.Support{})
+
+	f.storage.On("UploadFile", ctx, "support-attachments", mock.MatchedBy(func(name string) bool {
+		return strings.HasPrefix(name, "5/") && strings.HasSuffix(name, ".png")
+	}), mock.Anything, "image/png").Return("", nil)
+	f.supportRepo.On("Create", ctx, mock.MatchedBy(func(ticket *entity.SupportTicketEntity) bool {
+		return ticket.UserID == 5 && ticket.Category == "delivery" && ticket.Subject == "Sayur layu" &&
+			len(ticket.Attachments) == 1 && ticket.Attachments[0].FileName == "foto.png"
+	})).Return(&entity.SupportTicketEntity{
+		ID: 11, UserID: 5, Category: "delivery", Subject: "Sayur layu", Message: "Bayam datang layu",
+		Status:      entity.SupportTicketOpen,
+		Attachments: []entity.SupportAttachmentEntity{{ObjectName: "5/a.png", FileName: "foto.png", ContentType: "image/png", Size: 72}},
+	}, nil)
+	f.userRepo.On("GetUserByID", ctx, int64(5)).Return(customer, nil)
+
+	ticket, err := f.service.CreateTicket(ctx, 5, " Delivery ", "Sayur layu", "Bayam datang layu",
+		[]entity.SupportUploadEntity{{FileName: "../../foto.png", Data: pngScreenshot}})
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.SupportTicketOpen, ticket.Status)
+	require.Len(t, f.webhooks.events, 1)
+	event := f.webhooks.events[0]
+	assert.Equal(t, entity.WebhookEventSupportTicketCreated, event.eventType)
+	assert.Equal(t, "support.ticket_created:11", event.eventID)
+	assert.Equal(t, "sari@example.com", event.data["email"])
+	// The helpdesk gets links that outlive the webhook retries
+	attachments := event.data["attachments"].([]map[string]interface{})
+	assert.Contains(t, attachments[0]["url"], "ttl=24h0m0s")
+	f.storage.AssertExpectations(t)
+}
+
+func TestCreateTicket_RejectsInvalidInput(t *testing.T) {
+	ctx := context.Background()
+	f := newSupportFixture(config.Support{MaxSizeKB: 1, MaxAttachments: 1})
+
+	cases := []struct {
+		category string
+		subject  string
+		uploads  []entity.SupportUploadEntity
+		wantErr  string
+	}{
+		{"refund", "Subject", nil, "category must be one of order, payment, delivery, account, other"},
+		{"order", " ", nil, "subject is required and must be at most 150 characters"},
+		{"order", "Subject", []entity.SupportUploadEntity{{Data: pngScreenshot}, {Data: pngScreenshot}}, "too many attachments"},
+		{"order", "Subject", []entity.SupportUploadEntity{{Data: []byte("plain text")}}, "attachment must be a JPEG, PNG, WebP or PDF file"},
+		{"order", "Subject", []entity.SupportUploadEntity{{Data: bytes.Repeat([]byte{0}, 2048)}}, "attachment exceeds the size limit"},
+	}
+	for _, tc := range cases {
+		_, err := f.service.CreateTicket(ctx, 5, tc.category, tc.subject, "Message", tc.uploads)
+		assert.EqualError(t, err, tc.wantErr)
+	}
+	f.storage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	f.supportRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateTicket_DeletesAttachmentsWhenSaveFails(t *testing.T) {
+	ctx := context.Background()
+	f := newSupportFixture(config.Support{})
+
+	f.storage.On("UploadFile", ctx, "support-attachments", mock.Anything, mock.Anything, "image/png").Return("", nil)
+	f.storage.On("DeleteFile", ctx, "support-attachments", mock.Anything).Return(nil)
+	f.supportRepo.On("Create", ctx, mock.Anything).Return(nil, errors.New("connection reset"))
+
+	_, err := f.service.CreateTicket(ctx, 5, "order", "Subject", "Message",
+		[]entity.SupportUploadEntity{{FileName: "a.png", Data: pngScreenshot}, {FileName: "b.png", Data: pngScreenshot}})
+
+	assert.EqualError(t, err, "failed to create support ticket")
+	f.storage.AssertNumberOfCalls(t, "DeleteFile", 2)
+	assert.Empty(t, f.webhooks.events)
+}
+
+func TestGetMyTicket_HidesOtherCustomersTickets(t *testing.T) {
+	ctx := context.Background()
+	f := newSupportFixture(config.Support{})
+
+	f.supportRepo.On("GetByID", ctx, int64(11)).Return(&entity.SupportTicketEntity{ID: 11, UserID: 6}, nil)
+	f.supportRepo.On("GetByID", ctx, int64(12)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := f.service.GetMyTicket(ctx, 5, 11)
+	assert.EqualError(t, err, "support ticket not found")
+
+	_, err = f.service.GetMyTicket(ctx, 5, 12)
+	assert.EqualError(t, err, "support ticket not found")
+}
+
+func TestAssign_OnlyStaff(t *testing.T) {
+	ctx := context.Background()
+	f := newSupportFixture(config.Support{})
+
+	f.supportRepo.On("GetByID", ctx, int64(11)).Return(&entity.SupportTicketEntity{ID: 11, UserID: 5, Status: entity.SupportTicketOpen}, nil)
+	f.userRepo.On("GetUserByID", ctx, int64(5)).Return(customer, nil)
+	f.userRepo.On("GetUserByID", ctx, int64(2)).Return(&entity.UserEntity{ID: 2, RoleName: "Super Admin"}, nil)
+	f.userRepo.On("GetUserByID", ctx, int64(9)).Return(nil, gorm.ErrRecordNotFound)
+	f.supportRepo.On("Assign", ctx, int64(11), int64(2)).Return(nil)
+	f.auditRepo.On("Create", ctx, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == entity.AuditEventSupportTicketAssigned && auditLog.UserID == 1
+	})).Return(nil)
+
+	_, err := f.service.Assign(ctx, 11, 5, 1)
+	assert.EqualError(t, err, "assignee must be a staff member")
+
+	_, err = f.service.Assign(ctx, 11, 9, 1)
+	assert.EqualError(t, err, "assignee not found")
+
+	_, err = f.service.Assign(ctx, 11, 2, 1)
+	assert.NoError(t, err)
+	f.supportRepo.AssertNumberOfCalls(t, "Assign", 1)
+	require.Len(t, f.webhooks.events, 1)
+	assert.Equal(t, entity.WebhookEventSupportTicketUpdated, f.webhooks.events[0].eventType)
+	f.email.AssertNotCalled(t, "SendSupportTicketUpdateEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestUpdateStatus_EmailsCustomer(t *testing.T) {
+	ctx := context.Background()
+	f := newSupportFixture(config.Support{})
+
+	resolved := &entity.SupportTicketEntity{ID: 11, UserID: 5, Status: entity.SupportTicketResolved, UpdatedAt: time.Unix(1700000000, 0)}
+	f.supportRepo.On("GetByID", ctx, int64(11)).Return(&entity.SupportTicketEntity{ID: 11, UserID: 5, Status: entity.SupportTicketInProgress}, nil).Once()
+	f.supportRepo.On("GetByID", ctx, int64(11)).Return(resolved, nil)
+	f.supportRepo.On("UpdateStatus", ctx, int64(11), entity.SupportTicketResolved).Return(nil)
+	f.auditRepo.On("Create", ctx, mock.Anything).Return(nil)
+	f.userRepo.On("GetUserByID", ctx, int64(5)).Return(customer, nil)
+	f.email.On("SendSupportTicketUpdateEmail", ctx, "sari@example.com", "Sari", resolved, "Dana sudah dikembalikan").Return(nil)
+
+	ticket, err := f.service.UpdateStatus(ctx, 11, "Resolved", " Dana sudah dikembalikan ", 1)
+
+	require.NoError(t, err)
+	assert.Equal(t, entity.SupportTicketResolved, ticket.Status)
+	f.email.AssertExpectations(t)
+	require.Len(t, f.webhooks.events, 1)
+	assert.Equal(t, "support.ticket_updated:11:1700000000000000000", f.webhooks.events[0].eventID)
+}
+
+func TestUpdateStatus_RejectsUnknownAndUnchangedStatus(t *testing.T) {
+	ctx := context.Background()
+	f := newSupportFixture(config.Support{})
+
+	f.supportRepo.On("GetByID", ctx, int64(11)).Return(&entity.SupportTicketEntity{ID: 11, UserID: 5, Status: entity.SupportTicketOpen}, nil)
+
+	_, err := f.service.UpdateStatus(ctx, 11, "archived", "", 1)
+	assert.EqualError(t, err, "status must be one of open, in_progress, waiting_on_customer, resolved, closed")
+
+	_, err = f.service.UpdateStatus(ctx, 11, entity.SupportTicketOpen, "", 1)
+	assert.EqualError(t, err, "support ticket already has this status")
+
+	f.supportRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything)
+	f.email.AssertNotCalled(t, "SendSupportTicketUpdateEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils/i18n"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTokenLifetimes_Validate(t *testing.T) {
+	assert.NoError(t, config.TokenLifetimes{}.Validate())
+	assert.NoError(t, config.TokenLifetimes{EmailVerification: "48h", PasswordReset: "15m", EmailChangeRevert: "720h"}.Validate())
+
+	assert.EqualError(t, config.TokenLifetimes{PasswordReset: "1 hour"}.Validate(), `TOKEN_TTL_PASSWORD_RESET: invalid duration "1 hour"`)
+	assert.ErrorContains(t, config.TokenLifetimes{EmailChange: "1m"}.Validate(), "TOKEN_TTL_EMAIL_CHANGE: 1m0s is outside")
+	assert.ErrorContains(t, config.TokenLifetimes{EmailChangeRevert: "8760h"}.Validate(), "TOKEN_TTL_EMAIL_CHANGE_REVERT")
+}
+
+func TestTokenLifetimes_ForFallsBackToDefaults(t *testing.T) {
+	lifetimes := service.TokenLifetimesFromConfig(&config.Config{TokenLifetimes: config.TokenLifetimes{PasswordReset: "30m"}})
+
+	assert.Equal(t, 30*time.Minute, lifetimes.For(entity.TokenTypePasswordReset))
+	assert.Equal(t, 24*time.Hour, lifetimes.For(entity.TokenTypeEmailVerification))
+	assert.Equal(t, 7*24*time.Hour, lifetimes.For(entity.TokenTypeEmailChangeRevert))
+	assert.Zero(t, lifetimes.For("unknown"))
+}
+
+func TestForgotPassword_UsesConfiguredLifetime(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
+	mockEmailPublisher := new(mocks.MockEmailPublisher)
+	cfg := &config.Config{TokenLifetimes: config.TokenLifetimes{PasswordReset: "15m"}}
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              mockUserRepo,
+			VerificationTokenRepo: mockVerificationTokenRepo,
+			EmailPublisher:        mockEmailPublisher,
+		},
+		Config: cfg,
+	})
+
+	mockUserRepo.On("GetUserByEmail", ctx, "user@example.com").Return(&entity.UserEntity{ID: 1, Email: "user@example.com", IsVerified: true}, nil)
+	mockVerificationTokenRepo.On("CreateVerificationToken", ctx, mock.MatchedBy(func(token *entity.VerificationTokenEntity) bool {
+		return token.TokenType == entity.TokenTypePasswordReset && time.Until(token.ExpiresAt) > 14*time.Minute && time.Until(token.ExpiresAt) <= 15*time.Minute
+	})).Return(nil)
+	mockEmailPublisher.On("SendPasswordResetEmail", ctx, "user@example.com", mock.AnythingOfType("string")).Return(nil)
+
+	assert.NoError(t, userService.ForgotPassword(ctx, "user@example.com"))
+	mockVerificationTokenRepo.AssertExpectations(t)
+}
+
+func TestDuration_UsesLargestExactUnit(t *testing.T) {
+	assert.Equal(t, "1 hour", i18n.Duration(i18n.English, time.Hour))
+	assert.Equal(t, "24 hours", i18n.Duration(i18n.English, 24*time.Hour))
+	assert.Equal(t, "7 days", i18n.Duration(i18n.English, 7*24*time.Hour))
+	assert.Equal(t, "90 minutes", i18n.Duration(i18n.English, 90*time.Minute))
+	assert.Equal(t, "2 hari", i18n.Duration(i18n.Indonesian, 48*time.Hour))
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type trashFixture struct {
+	trashRepo *mocks.MockTrashRepository
+	auditRepo *mocks.MockAuditLogRepository
+	service   port.TrashServiceInterface
+}
+
+func newTrashFixture() *trashFixture {
+	f := &trashFixture{
+		trashRepo: new(mocks.MockTrashRepository),
+		auditRepo: new(mocks.MockAuditLogRepository),
+	}
+	f.service = service.NewTrashService(f.trashRepo, service.NewAuditLogService(f.auditRepo))
+	return f
+}
+
+func auditFor(event, recordType string, recordID int64) interface{} {
+	return mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == event &&
+			auditLog.UserID == 1 &&
+			auditLog.Metadata["record_type"] == recordType &&
+			auditLog.Metadata["record_id"] == recordID
+	})
+}
+
+func TestGetDeletedUsers_NormalizesPagination(t *testing.T) {
+	ctx := context.Background()
+	f := newTrashFixture()
+
+	users := []entity.TrashedUserEntity{{ID: 4, Email: "gone@example.com", DeletedAt: time.Now()}}
+	f.trashRepo.On("GetDeletedUsers", ctx, "gone", 1, 10).Return(users, int64(11), nil)
+
+	result, pagination, err := f.service.GetDeletedUsers(ctx, "gone", 0, 500)
+
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, 2, pagination.TotalPage)
+	f.trashRepo.AssertExpectations(t)
+}
+
+func TestRestoreUser_RecordsAudit(t *testing.T) {
+	ctx := context.Background()
+	f := newTrashFixture()
+
+	f.trashRepo.On("RestoreUser", ctx, int64(4)).Return(&entity.TrashedUserEntity{ID: 4, Email: "back@example.com"}, nil)
+	f.auditRepo.On("Create", ctx, auditFor(entity.AuditEventRecordRestored, entity.TrashRecordUser, 4)).Return(nil)
+
+	user, err := f.service.RestoreUser(ctx, 4, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "back@example.com", user.Email)
+	f.auditRepo.AssertExpectations(t)
+}
+
+func TestRestoreUser_NotInTrash(t *testing.T) {
+	ctx := context.Background()
+	f := newTrashFixture()
+
+	f.trashRepo.On("RestoreUser", ctx, int64(4)).Return(nil, errors.New("user not found"))
+
+	_, err := f.service.RestoreUser(ctx, 4, 1)
+
+	assert.EqualError(t, err, "user not found")
+	f.auditRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestPurgeUser_KeepsIdentityInAudit(t *testing.T) {
+	ctx := context.Background()
+	f := newTrashFixture()
+
+	f.trashRepo.On("PurgeUser", ctx, int64(4)).Return(&entity.TrashedUserEntity{ID: 4, Name: "Gone", Email: "gone@example.com"}, nil)
+	f.auditRepo.On("Create", ctx, mock.MatchedBy(func(auditLog *entity.AuditLogEntity) bool {
+		return auditLog.Event == entity.AuditEventRecordPurged && auditLog.Metadata["email"] == "gone@example.com"
+	})).Return(nil)
+
+	err := f.service.PurgeUser(ctx, 4, 1)
+
+	assert.NoError(t, err)
+	f.auditRepo.AssertExpectations(t)
+}
+
+func TestPurgeUser_LedgerHistoryBlocksPurge(t *testing.T) {
+	ctx := context.Background()
+	f := newTrashFixture()
+
+	f.trashRepo.On("PurgeUser", ctx, int64(4)).Return(nil, errors.New("user has ledger history"))
+
+	err := f.service.PurgeUser(ctx, 4, 1)
+
+	assert.EqualError(t, err, "user has ledger history")
+	f.auditRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestPurgeUser_HidesRepositoryErrors(t *testing.T) {
+	ctx := context.Background()
+	f := newTrashFixture()
+
+	f.trashRepo.On("PurgeUser", ctx, int64(4)).Return(nil, errors.New("connection reset"))
+
+	err := f.service.PurgeUser(ctx, 4, 1)
+
+	assert.EqualError(t, err, "failed to purge user")
+}
+
+func TestRestoreRole_RecordsAudit(t *testing.T) {
+	ctx := context.Background()
+	f := newTrashFixture()
+
+	f.trashRepo.On("RestoreRole", ctx, int64(7)).Return(&entity.TrashedRoleEntity{ID: 7, Name: "Courier"}, nil)
+	f.auditRepo.On("Create", ctx, auditFor(entity.AuditEventRecordRestored, entity.TrashRecordRole, 7)).Return(nil)
+
+	role, err := f.service.RestoreRole(ctx, 7, 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Courier", role.Name)
+	f.auditRepo.AssertExpectations(t)
+}
+
+func TestPurgeRole_RecordsAudit(t *testing.T) {
+	ctx := context.Background()
+	f := newTrashFixture()
+
+	f.trashRepo.On("PurgeRole", ctx, int64(7)).Return(&entity.TrashedRoleEntity{ID: 7, Name: "Courier"}, nil)
+	f.auditRepo.On("Create", ctx, auditFor(entity.AuditEventRecordPurged, entity.TrashRecordRole, 7)).Return(nil)
+
+	err := f.service.PurgeRole(ctx, 7, 1)
+
+	assert.NoError(t, err)
+	f.auditRepo.AssertExpectations(t)
+}
+
+func TestPurgeRole_InvalidID(t *testing.T) {
+	f := newTrashFixture()
+
+	err := f.service.PurgeRole(context.Background(), 0, 1)
+
+	assert.EqualError(t, err, "invalid role id")
+	f.trashRepo.AssertNotCalled(t, "PurgeRole", mock.Anything, mock.Anything)
+}
+
+func TestRestoreUser_EmailTakenByNewAccount(t *testing.T) {
+	ctx := context.Background()
+	f := newTrashFixture()
+
+	f.trashRepo.On("RestoreUser", ctx, int64(4)).Return(nil, errors.New("email is used by another account"))
+
+	_, err := f.service.RestoreUser(ctx, 4, 1)
+
+	assert.EqualError(t, err, "email is used by another account")
+	f.auditRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
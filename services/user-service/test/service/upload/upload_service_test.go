@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/adapter/storage"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type uploadFixture struct {
+	dir     string
+	storage *mocks.MockStorage
+	service port.UploadServiceInterface
+}
+
+// newUploadFixture uses 1 KB chunks on a temp dir, so files of a few KB span several chunks
+func newUploadFixture(t *testing.T) *uploadFixture {
+	f := &uploadFixture{dir: t.TempDir(), storage: new(mocks.MockStorage)}
+	cfg := &config.Config{Upload: config.Upload{ChunkSizeKB: 1, MaxSizeMB: 1}}
+	f.service = service.NewUploadService(storage.NewLocalChunkStore(f.dir), f.storage, cfg)
+	return f
+}
+
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestUpload_ChunksOutOfOrderAreAssembled(t *testing.T) {
+	ctx := context.Background()
+	f := newUploadFixture(t)
+	file := bytes.Repeat([]byte("sayur-segar-"), 200) // 2400 bytes, 3 chunks
+
+	session, err := f.service.CreateUpload(ctx, 7, "../ktp.pdf", "application/pdf", int64(len(file)), checksum(file))
+	require.NoError(t, err)
+	assert.Equal(t, "ktp.pdf", session.Filename)
+	assert.Equal(t, 3, session.TotalChunks)
+
+	for _, index := range []int{2, 0} {
+		end := (index + 1) * 1024
+		if end > len(file) {
+			end = len(file)
+		}
+		session, err = f.service.UploadChunk(ctx, 7, session.ID, index, bytes.NewReader(file[index*1024:end]), "")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []int{0, 2}, session.ReceivedChunks)
+	assert.Equal(t, []int{1}, session.MissingChunks())
+
+	_, err = f.service.CompleteUpload(ctx, 7, session.ID)
+	assert.EqualError(t, err, "upload is incomplete, 1 chunks missing")
+
+	_, err = f.service.UploadChunk(ctx, 7, session.ID, 1, bytes.NewReader(file[1024:2048]), checksum(file[1024:2048]))
+	require.NoError(t, err)
+
+	var stored []byte
+	f.storage.On("UploadFile", ctx, "", mock.MatchedBy(func(objectName string) bool {
+		return strings.HasPrefix(objectName, "uploads/7/") && strings.HasSuffix(objectName, ".pdf")
+	}), mock.Anything, "application/pdf").Run(func(args mock.Arguments) {
+		stored, _ = io.ReadAll(args.Get(3).(io.Reader))
+	}).Return("https://cdn.example.com/uploads/7/ktp.pdf", nil)
+
+	result, err := f.service.CompleteUpload(ctx, 7, session.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "https://cdn.example.com/uploads/7/ktp.pdf", result.URL)
+	assert.Equal(t, file, stored)
+	_, err = os.Stat(filepath.Join(f.dir, session.ID))
+	assert.True(t, os.IsNotExist(err), "chunks are removed after completion")
+}
+
+func TestUpload_RejectsBadChunks(t *testing.T) {
+	ctx := context.Background()
+	f := newUploadFixture(t)
+	file := bytes.Repeat([]byte{1}, 1500)
+
+	session, err := f.service.CreateUpload(ctx, 7, "foto.png", "image/png", int64(len(file)), checksum(file))
+	require.NoError(t, err)
+
+	_, err = f.service.UploadChunk(ctx, 7, session.ID, 0, bytes.NewReader(file[:1000]), "")
+	assert.EqualError(t, err, "chunk 0 must be exactly 1024 bytes")
+
+	_, err = f.service.UploadChunk(ctx, 7, session.ID, 1, bytes.NewReader(file), "")
+	assert.EqualError(t, err, "chunk 1 must be exactly 476 bytes")
+
+	_, err = f.service.UploadChunk(ctx, 7, session.ID, 0, bytes.NewReader(file[:1024]), checksum([]byte("other")))
+	assert.EqualError(t, err, "chunk checksum mismatch")
+
+	_, err = f.service.UploadChunk(ctx, 7, session.ID, 2, bytes.NewReader(file[:10]), "")
+	assert.EqualError(t, err, "chunk index out of range")
+
+	session, err = f.service.GetUpload(ctx, 7, session.ID)
+	require.NoError(t, err)
+	assert.Empty(t, session.ReceivedChunks)
+}
+
+func TestUpload_ChecksumMismatchDiscardsUpload(t *testing.T) {
+	ctx := context.Background()
+	f := newUploadFixture(t)
+	file := bytes.Repeat([]byte{2}, 100)
+
+	session, err := f.service.CreateUpload(ctx, 7, "foto.png", "image/png", 100, checksum([]byte("something else")))
+	require.NoError(t, err)
+	_, err = f.service.UploadChunk(ctx, 7, session.ID, 0, bytes.NewReader(file), "")
+	require.NoError(t, err)
+
+	_, err = f.service.CompleteUpload(ctx, 7, session.ID)
+
+	assert.EqualError(t, err, "checksum mismatch")
+	f.storage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	_, err = f.service.GetUpload(ctx, 7, session.ID)
+	assert.EqualError(t, err, "upload not found")
+}
+
+func TestUpload_ValidatesAndScopesToOwner(t *testing.T) {
+	ctx := context.Background()
+	f := newUploadFixture(t)
+	sum := checksum([]byte("x"))
+
+	_, err := f.service.CreateUpload(ctx, 7, "run.exe", "application/x-msdownload", 10, sum)
+	assert.EqualError(t, err, "unsupported content type")
+
+	_, err = f.service.CreateUpload(ctx, 7, "big.pdf", "application/pdf", 2<<20, sum)
+	assert.EqualError(t, err, "file size must not exceed 1048576 bytes")
+
+	session, err := f.service.CreateUpload(ctx, 7, "foto.jpg", "image/jpeg", 10, sum)
+	require.NoError(t, err)
+
+	_, err = f.service.GetUpload(ctx, 8, session.ID)
+	assert.EqualError(t, err, "upload not found")
+
+	_, err = f.service.GetUpload(ctx, 7, "../../etc")
+	assert.EqualError(t, err, "upload not found")
+
+	assert.NoError(t, f.service.AbortUpload(ctx, 7, session.ID))
+	_, err = f.service.GetUpload(ctx, 7, session.ID)
+	assert.EqualError(t, err, "upload not found")
+}
+
+func TestUpload_CleanupRemovesExpired(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	chunkStore := storage.NewLocalChunkStore(dir)
+	uploadService := service.NewUploadService(chunkStore, nil, &config.Config{})
+
+	active, err := uploadService.CreateUpload(ctx, 7, "foto.jpg", "image/jpeg", 10, checksum([]byte("x")))
+	require.NoError(t, err)
+
+	abandonedID := strings.Repeat("a", 32)
+	require.NoError(t, chunkStore.CreateSession(ctx, &entity.UploadSessionEntity{
+		ID:        abandonedID,
+		UserID:    7,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}))
+
+	removed, err := uploadService.CleanupExpired(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	_, err = os.Stat(filepath.Join(dir, abandonedID))
+	assert.True(t, os.IsNotExist(err))
+	_, err = uploadService.GetUpload(ctx, 7, active.ID)
+	assert.NoError(t, err)
+}
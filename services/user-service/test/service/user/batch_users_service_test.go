@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserService_GetUsersByIDs_DeduplicatesIDs(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo},
+		Config:          &config.Config{},
+	})
+
+	ctx := context.Background()
+	users := []entity.UserEntity{{ID: 1, Name: "Budi", Lat: -6.2, Lng: 106.8}, {ID: 3, Name: "Sari"}}
+	mockUserRepo.On("GetUsersByIDs", ctx, []int64{3, 1, 2}).Return(users, nil)
+
+	result, err := userService.GetUsersByIDs(ctx, []int64{3, 1, 3, 2, 1})
+
+	assert.NoError(t, err)
+	assert.Equal(t, users, result)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestUserService_GetUsersByIDs_Validation(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo},
+		Config:          &config.Config{},
+	})
+
+	_, err := userService.GetUsersByIDs(context.Background(), nil)
+	assert.EqualError(t, err, "user ids are required")
+
+	_, err = userService.GetUsersByIDs(context.Background(), []int64{1, 0})
+	assert.EqualError(t, err, "invalid user id")
+
+	tooMany := make([]int64, service.MaxBatchUserIDs+1)
+	for i := range tooMany {
+		tooMany[i] = int64(i + 1)
+	}
+	_, err = userService.GetUsersByIDs(context.Background(), tooMany)
+	assert.EqualError(t, err, "too many user ids")
+
+	// Duplicates do not count against the limit
+	atLimit := append(tooMany[:service.MaxBatchUserIDs:service.MaxBatchUserIDs], 1, 2, 3)
+	mockUserRepo.On("GetUsersByIDs", context.Background(), tooMany[:service.MaxBatchUserIDs]).Return([]entity.UserEntity{}, nil)
+	_, err = userService.GetUsersByIDs(context.Background(), atLimit)
+	assert.NoError(t, err)
+}
+
+func TestUserService_GetUsersByIDs_RepositoryError(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo},
+		Config:          &config.Config{},
+	})
+
+	ctx := context.Background()
+	mockUserRepo.On("GetUsersByIDs", ctx, []int64{1}).Return(nil, errors.New("connection refused"))
+
+	_, err := userService.GetUsersByIDs(ctx, []int64{1})
+	assert.Error(t, err)
+}
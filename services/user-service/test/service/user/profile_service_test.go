@@ -16,7 +16,10 @@ func TestUserService_GetProfile_Success(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(mockUserRepo, nil, nil, nil, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage},
+		Config:          &config.Config{},
+	})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -50,7 +53,10 @@ func TestUserService_GetProfile_UserNotFound(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(mockUserRepo, nil, nil, nil, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage},
+		Config:          &config.Config{},
+	})
 
 	ctx := context.Background()
 	userID := int64(999)
@@ -72,7 +78,10 @@ func TestUserService_GetProfile_DatabaseError(t *testing.T) {
 	// Setup
 	mockUserRepo := new(mocks.MockUserRepository)
 	mockStorage := new(mocks.MockStorage)
-	service := service.NewUserService(mockUserRepo, nil, nil, nil, nil, nil, mockStorage, &config.Config{})
+	service := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, Storage: mockStorage},
+		Config:          &config.Config{},
+	})
 
 	ctx := context.Background()
 	userID := int64(1)
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newUserService(userRepo *mocks.MockUserRepository, tokenRepo *mocks.MockVerificationTokenRepository) port.UserServiceInterface {
+	return service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: userRepo, VerificationTokenRepo: tokenRepo},
+		Config:          &config.Config{},
+	})
+}
+
+func TestVerifyUserAccount_RejectsUsedToken(t *testing.T) {
+	ctx := context.Background()
+	userRepo, tokenRepo := new(mocks.MockUserRepository), new(mocks.MockVerificationTokenRepository)
+
+	tokenRepo.On("GetVerificationToken", ctx, "used-token").Return(nil, port.ErrTokenAlreadyUsed)
+
+	err := newUserService(userRepo, tokenRepo).VerifyUserAccount(ctx, "used-token")
+
+	assert.ErrorIs(t, err, port.ErrTokenAlreadyUsed)
+	userRepo.AssertNotCalled(t, "UpdateUserVerificationStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVerifyUserAccount_LosingTheClaimChangesNothing(t *testing.T) {
+	ctx := context.Background()
+	userRepo, tokenRepo := new(mocks.MockUserRepository), new(mocks.MockVerificationTokenRepository)
+
+	// A concurrent request with the same link claimed it between the lookup and the claim
+	tokenRepo.On("GetVerificationToken", ctx, "raced-token").Return(&entity.VerificationTokenEntity{UserID: 1, Token: "raced-token"}, nil)
+	tokenRepo.On("ClaimVerificationToken", ctx, "raced-token").Return(port.ErrTokenAlreadyUsed)
+
+	err := newUserService(userRepo, tokenRepo).VerifyUserAccount(ctx, "raced-token")
+
+	assert.ErrorIs(t, err, port.ErrTokenAlreadyUsed)
+	userRepo.AssertNotCalled(t, "UpdateUserVerificationStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestVerifyUserAccount_ExpiredBeforeClaim(t *testing.T) {
+	ctx := context.Background()
+	userRepo, tokenRepo := new(mocks.MockUserRepository), new(mocks.MockVerificationTokenRepository)
+
+	tokenRepo.On("GetVerificationToken", ctx, "late-token").Return(&entity.VerificationTokenEntity{UserID: 1, Token: "late-token"}, nil)
+	tokenRepo.On("ClaimVerificationToken", ctx, "late-token").Return(gorm.ErrRecordNotFound)
+
+	err := newUserService(userRepo, tokenRepo).VerifyUserAccount(ctx, "late-token")
+
+	assert.EqualError(t, err, "invalid or expired verification token")
+}
+
+func TestVerifyUserAccount_ReleasesTokenWhenUpdateFails(t *testing.T) {
+	ctx := context.Background()
+	userRepo, tokenRepo := new(mocks.MockUserRepository), new(mocks.MockVerificationTokenRepository)
+
+	tokenRepo.On("GetVerificationToken", ctx, "valid-token").Return(&entity.VerificationTokenEntity{UserID: 1, Token: "valid-token"}, nil)
+	tokenRepo.On("ClaimVerificationToken", ctx, "valid-token").Return(nil)
+	userRepo.On("UpdateUserVerificationStatus", ctx, int64(1), true).Return(errors.New("connection reset"))
+	tokenRepo.On("ReleaseVerificationToken", ctx, "valid-token").Return(nil)
+
+	err := newUserService(userRepo, tokenRepo).VerifyUserAccount(ctx, "valid-token")
+
+	assert.EqualError(t, err, "failed to verify account")
+	tokenRepo.AssertExpectations(t)
+}
+
+func TestResetPassword_RejectsUsedToken(t *testing.T) {
+	ctx := context.Background()
+	userRepo, tokenRepo := new(mocks.MockUserRepository), new(mocks.MockVerificationTokenRepository)
+
+	tokenRepo.On("GetVerificationToken", ctx, "used-reset").Return(&entity.VerificationTokenEntity{
+		UserID:    1,
+		Token:     "used-reset",
+		TokenType: entity.TokenTypePasswordReset,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	tokenRepo.On("ClaimVerificationToken", ctx, "used-reset").Return(port.ErrTokenAlreadyUsed)
+
+	err := newUserService(userRepo, tokenRepo).ResetPassword(ctx, "used-reset", "newpassword123", "newpassword123")
+
+	assert.ErrorIs(t, err, port.ErrTokenAlreadyUsed)
+	userRepo.AssertNotCalled(t, "ChangeUserPassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
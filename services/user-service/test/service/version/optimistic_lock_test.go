@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"user-service/internal/adapter/handler"
+	"user-service/internal/adapter/repository"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/domain/model"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func dryRunDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost user=test dbname=test sslmode=disable"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, repository.RegisterOptimisticLocking(db))
+	return db
+}
+
+func TestOptimisticLock_VersionedUpdateMatchesAndBumpsVersion(t *testing.T) {
+	db := dryRunDB(t)
+
+	stmt := db.Model(&model.Role{ID: 7, Version: 3}).Updates(map[string]interface{}{"name": "Courier"}).Statement
+
+	assert.Contains(t, stmt.SQL.String(), `"version"=$`)
+	assert.Contains(t, stmt.SQL.String(), `"roles"."version" = $`)
+	assert.Contains(t, stmt.Vars, int64(3))
+	assert.Contains(t, stmt.Vars, int64(4))
+}
+
+func TestOptimisticLock_UnversionedUpdateIsUntouched(t *testing.T) {
+	db := dryRunDB(t)
+
+	stmt := db.Model(&model.User{}).Where("id = ?", 5).Updates(map[string]interface{}{"is_verified": true}).Statement
+
+	assert.NotContains(t, stmt.SQL.String(), "version")
+}
+
+func TestRoleService_UpdateRole_StaleVersion(t *testing.T) {
+	ctx := context.Background()
+	mockRoleRepo := new(mocks.MockRoleRepository)
+	roleService := service.NewRoleService(mockRoleRepo)
+
+	mockRoleRepo.On("GetRoleByID", ctx, int64(7)).Return(&entity.RoleEntity{ID: 7, Name: "Courier", Version: 4}, nil)
+
+	_, err := roleService.UpdateRole(ctx, 7, "Kurir", 3)
+
+	var conflict *entity.VersionConflictError
+	require.True(t, errors.As(err, &conflict))
+	assert.Equal(t, int64(4), conflict.CurrentVersion)
+	mockRoleRepo.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRoleService_UpdateRole_PassesVersionToRepository(t *testing.T) {
+	ctx := context.Background()
+	mockRoleRepo := new(mocks.MockRoleRepository)
+	roleService := service.NewRoleService(mockRoleRepo)
+
+	mockRoleRepo.On("GetRoleByID", ctx, int64(7)).Return(&entity.RoleEntity{ID: 7, Name: "Courier", Version: 3}, nil)
+	mockRoleRepo.On("GetAllRoles", ctx, "").Return([]entity.RoleEntity{{ID: 7, Name: "Courier"}}, nil)
+	// Another admin saved in between: the repository reports the conflict
+	mockRoleRepo.On("UpdateRole", ctx, int64(7), mock.MatchedBy(func(role *entity.RoleEntity) bool {
+		return role.Version == 3 && role.Name == "Kurir"
+	})).Return(nil, &entity.VersionConflictError{CurrentVersion: 4})
+
+	_, err := roleService.UpdateRole(ctx, 7, "Kurir", 3)
+
+	var conflict *entity.VersionConflictError
+	require.True(t, errors.As(err, &conflict))
+	assert.Equal(t, int64(4), conflict.CurrentVersion)
+}
+
+func TestAuthService_UpdateProfile_StaleVersionHasNoSideEffects(t *testing.T) {
+	ctx := context.Background()
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockEmailPublisher := new(mocks.MockEmailPublisher)
+	authService := service.NewAuthService(service.AuthServiceDeps{
+		UserRepo:       mockUserRepo,
+		EmailPublisher: mockEmailPublisher,
+	})
+
+	mockUserRepo.On("GetUserByID", ctx, int64(1)).Return(&entity.UserEntity{ID: 1, Email: "old@example.com", Version: 6}, nil)
+
+	err := authService.UpdateProfile(ctx, 1, "John", "new@example.com", "0812", "Jl. Sudirman", -6.2088, 106.8456, "", 5)
+
+	var conflict *entity.VersionConflictError
+	require.True(t, errors.As(err, &conflict))
+	assert.Equal(t, int64(6), conflict.CurrentVersion)
+	mockEmailPublisher.AssertNotCalled(t, "SendEmailChangeVerificationEmail", mock.Anything, mock.Anything, mock.Anything)
+	mockUserRepo.AssertNotCalled(t, "UpdateUserProfile", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func newRoleUpdateContext(body, ifMatch string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/roles/7", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/api/v1/admin/roles/:id")
+	c.SetParamNames("id")
+	c.SetParamValues("7")
+	return c, rec
+}
+
+func TestRoleHandler_UpdateRole_VersionRequired(t *testing.T) {
+	mockRoleService := &mocks.MockRoleService{}
+	c, rec := newRoleUpdateContext(`{"name":"Kurir"}`, "")
+
+	err := handler.NewRoleHandler(mockRoleService).UpdateRole(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusPreconditionRequired, rec.Code)
+	mockRoleService.AssertNotCalled(t, "UpdateRole", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRoleHandler_UpdateRole_IfMatchHeader(t *testing.T) {
+	mockRoleService := &mocks.MockRoleService{}
+	mockRoleService.On("UpdateRole", mock.Anything, int64(7), "Kurir", int64(3)).Return(&entity.RoleEntity{ID: 7, Name: "Kurir", Version: 4}, nil)
+	c, rec := newRoleUpdateContext(`{"name":"Kurir","version":1}`, `W/"3"`)
+
+	err := handler.NewRoleHandler(mockRoleService).UpdateRole(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"4"`, rec.Header().Get("ETag"))
+	mockRoleService.AssertExpectations(t)
+}
+
+func TestRoleHandler_UpdateRole_InvalidIfMatch(t *testing.T) {
+	mockRoleService := &mocks.MockRoleService{}
+	c, rec := newRoleUpdateContext(`{"name":"Kurir"}`, `"abc"`)
+
+	err := handler.NewRoleHandler(mockRoleService).UpdateRole(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRoleHandler_UpdateRole_Conflict(t *testing.T) {
+	mockRoleService := &mocks.MockRoleService{}
+	mockRoleService.On("UpdateRole", mock.Anything, int64(7), "Kurir", int64(3)).Return(nil, &entity.VersionConflictError{CurrentVersion: 5})
+	c, rec := newRoleUpdateContext(`{"name":"Kurir","version":3}`, "")
+
+	err := handler.NewRoleHandler(mockRoleService).UpdateRole(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, `"5"`, rec.Header().Get("ETag"))
+
+	var response map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, float64(5), response["data"].(map[string]interface{})["current_version"])
+}
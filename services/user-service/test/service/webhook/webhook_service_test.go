@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/port"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+	"user-service/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type webhookFixture struct {
+	webhookRepo *mocks.MockWebhookRepository
+	jobRepo     *mocks.MockJobRepository
+	sender      *mocks.MockWebhookSender
+	service     port.WebhookServiceInterface
+}
+
+func newWebhookFixture() *webhookFixture {
+	f := &webhookFixture{
+		webhookRepo: new(mocks.MockWebhookRepository),
+		jobRepo:     new(mocks.MockJobRepository),
+		sender:      new(mocks.MockWebhookSender),
+	}
+	f.service = service.NewWebhookService(f.webhookRepo, service.NewJobService(f.jobRepo), f.sender)
+	return f
+}
+
+func pendingDelivery() *entity.WebhookDeliveryEntity {
+	return &entity.WebhookDeliveryEntity{
+		ID:         9,
+		EndpointID: 3,
+		EventID:    "user.created:7",
+		EventType:  entity.WebhookEventUserCreated,
+		Payload:    json.RawMessage(`{"event":"user.created"}`),
+		Status:     entity.WebhookDeliveryPending,
+	}
+}
+
+func activeEndpoint() *entity.WebhookEndpointEntity {
+	return &entity.WebhookEndpointEntity{
+		ID:         3,
+		URL:        "https://crm.example.com/hooks",
+		Secret:     "whsec_0123456789abcdef",
+		EventTypes: []string{entity.WebhookEventUserCreated},
+		IsActive:   true,
+	}
+}
+
+func TestDispatch_QueuesDeliveryPerSubscribedEndpoint(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture()
+
+	endpoints := []entity.WebhookEndpointEntity{{ID: 3}, {ID: 4}}
+	f.webhookRepo.On("GetActiveEndpointsForEvent", ctx, entity.WebhookEventUserCreated).Return(endpoints, nil)
+	f.webhookRepo.On("CreateDelivery", ctx, mock.MatchedBy(func(delivery *entity.WebhookDeliveryEntity) bool {
+		return delivery.EndpointID == 3 && delivery.EventID == "user.created:7"
+	})).Return(&entity.WebhookDeliveryEntity{ID: 9}, nil)
+	// The second endpoint already has this event, so it is not queued twice
+	f.webhookRepo.On("CreateDelivery", ctx, mock.MatchedBy(func(delivery *entity.WebhookDeliveryEntity) bool {
+		return delivery.EndpointID == 4
+	})).Return(nil, errors.New("webhook delivery already exists"))
+	f.jobRepo.On("Enqueue", ctx, mock.MatchedBy(func(job *entity.JobEntity) bool {
+		return job.Type == entity.JobTypeDeliverWebhook && string(job.Payload) == `{"delivery_id":9}`
+	})).Return(&entity.JobEntity{ID: 1}, nil).Once()
+
+	err := f.service.Dispatch(ctx, entity.WebhookEventUserCreated, "user.created:7", map[string]interface{}{"user_id": 7})
+
+	assert.NoError(t, err)
+	f.webhookRepo.AssertExpectations(t)
+	f.jobRepo.AssertExpectations(t)
+}
+
+func TestDispatch_RejectsUnknownEvent(t *testing.T) {
+	f := newWebhookFixture()
+
+	err := f.service.Dispatch(context.Background(), "order.shipped", "order.shipped:1", nil)
+
+	assert.EqualError(t, err, "unknown webhook event type")
+	f.webhookRepo.AssertNotCalled(t, "GetActiveEndpointsForEvent", mock.Anything, mock.Anything)
+}
+
+func TestDeliver_SignsPayloadAndRecordsSuccess(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture()
+	delivery := pendingDelivery()
+	endpoint := activeEndpoint()
+
+	f.webhookRepo.On("GetDeliveryByID", ctx, int64(9)).Return(delivery, nil)
+	f.webhookRepo.On("GetEndpointByID", ctx, int64(3)).Return(endpoint, nil)
+	f.sender.On("Send", ctx, endpoint.URL, mock.MatchedBy(func(headers map[string]string) bool {
+		signature := headers[utils.WebhookSignatureHeader]
+		timestamp, err := strconv.ParseInt(strings.TrimPrefix(strings.Split(signature, ",")[0], "t="), 10, 64)
+		return err == nil &&
+			signature == utils.SignWebhookPayload(endpoint.Secret, timestamp, delivery.Payload) &&
+			headers["X-Webhook-ID"] == "user.created:7" &&
+			headers["X-Webhook-Delivery"] == "9"
+	}), []byte(delivery.Payload)).Return(&entity.WebhookResponseEntity{StatusCode: 204}, nil)
+	f.webhookRepo.On("RecordAttempt", ctx, int64(9), mock.MatchedBy(func(attempt *entity.WebhookAttemptEntity) bool {
+		return attempt.Status == entity.WebhookDeliverySucceeded && attempt.ResponseStatus == 204 && attempt.Error == ""
+	})).Return(nil)
+
+	err := f.service.Deliver(ctx, 9, false)
+
+	assert.NoError(t, err)
+	f.sender.AssertExpectations(t)
+	f.webhookRepo.AssertExpectations(t)
+}
+
+func TestDeliver_ErrorStatusIsRetriedUntilLastAttempt(t *testing.T) {
+	ctx := context.Background()
+
+	for _, lastAttempt := range []bool{false, true} {
+		f := newWebhookFixture()
+		expectedStatus := entity.WebhookDeliveryPending
+		if lastAttempt {
+			expectedStatus = entity.WebhookDeliveryFailed
+		}
+
+		f.webhookRepo.On("GetDeliveryByID", ctx, int64(9)).Return(pendingDelivery(), nil)
+		f.webhookRepo.On("GetEndpointByID", ctx, int64(3)).Return(activeEndpoint(), nil)
+		f.sender.On("Send", ctx, mock.Anything, mock.Anything, mock.Anything).Return(&entity.WebhookResponseEntity{StatusCode: 503, Body: "maintenance"}, nil)
+		f.webhookRepo.On("RecordAttempt", ctx, int64(9), mock.MatchedBy(func(attempt *entity.WebhookAttemptEntity) bool {
+			return attempt.Status == expectedStatus && attempt.ResponseStatus == 503 && attempt.ResponseBody == "maintenance"
+		})).Return(nil)
+
+		err := f.service.Deliver(ctx, 9, lastAttempt)
+
+		assert.EqualError(t, err, "endpoint responded with status 503")
+		f.webhookRepo.AssertExpectations(t)
+	}
+}
+
+func TestDeliver_SkipsDisabledEndpoint(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture()
+	endpoint := activeEndpoint()
+	endpoint.IsActive = false
+
+	f.webhookRepo.On("GetDeliveryByID", ctx, int64(9)).Return(pendingDelivery(), nil)
+	f.webhookRepo.On("GetEndpointByID", ctx, int64(3)).Return(endpoint, nil)
+	f.webhookRepo.On("RecordAttempt", ctx, int64(9), mock.MatchedBy(func(attempt *entity.WebhookAttemptEntity) bool {
+		return attempt.Status == entity.WebhookDeliveryFailed
+	})).Return(nil)
+
+	err := f.service.Deliver(ctx, 9, false)
+
+	assert.NoError(t, err)
+	f.sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCreateEndpoint_GeneratesSecretAndValidates(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture()
+
+	f.webhookRepo.On("CreateEndpoint", ctx, mock.MatchedBy(func(endpoint *entity.WebhookEndpointEntity) bool {
+		return strings.HasPrefix(endpoint.Secret, "whsec_") && len(endpoint.EventTypes) == 1
+	})).Return(&entity.WebhookEndpointEntity{ID: 3}, nil)
+
+	_, err := f.service.CreateEndpoint(ctx, &entity.WebhookEndpointEntity{
+		URL:        "https://crm.example.com/hooks",
+		EventTypes: []string{entity.WebhookEventOrderPaid, entity.WebhookEventOrderPaid},
+	})
+	assert.NoError(t, err)
+
+	_, err = f.service.CreateEndpoint(ctx, &entity.WebhookEndpointEntity{
+		URL:        "ftp://crm.example.com/hooks",
+		EventTypes: []string{entity.WebhookEventOrderPaid},
+	})
+	assert.EqualError(t, err, "webhook url must be an absolute http or https url")
+
+	_, err = f.service.CreateEndpoint(ctx, &entity.WebhookEndpointEntity{
+		URL:        "https://crm.example.com/hooks",
+		Secret:     "short",
+		EventTypes: []string{entity.WebhookEventOrderPaid},
+	})
+	assert.EqualError(t, err, "webhook secret must be at least 16 characters")
+	f.webhookRepo.AssertNumberOfCalls(t, "CreateEndpoint", 1)
+}
+
+func TestRedeliver_RejectsPendingDelivery(t *testing.T) {
+	ctx := context.Background()
+	f := newWebhookFixture()
+
+	f.webhookRepo.On("GetDeliveryByID", ctx, int64(9)).Return(pendingDelivery(), nil)
+
+	err := f.service.Redeliver(ctx, 9)
+
+	assert.EqualError(t, err, "webhook delivery is still pending")
+	f.webhookRepo.AssertNotCalled(t, "ResetDelivery", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"user-service/config"
+	"user-service/internal/core/domain/entity"
+	"user-service/internal/core/service"
+	"user-service/test/service/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// Monas, central Jakarta
+const (
+	jakartaLat = -6.1754
+	jakartaLng = 106.8272
+)
+
+func jakartaRadiusZone() entity.DeliveryZoneEntity {
+	return entity.DeliveryZoneEntity{
+		ID:        1,
+		Name:      "Jakarta Pusat",
+		ZoneType:  entity.ZoneTypeRadius,
+		CenterLat: jakartaLat,
+		CenterLng: jakartaLng,
+		RadiusKm:  5,
+		IsActive:  true,
+	}
+}
+
+func bandungPolygonZone() entity.DeliveryZoneEntity {
+	return entity.DeliveryZoneEntity{
+		ID:       2,
+		Name:     "Bandung Kota",
+		ZoneType: entity.ZoneTypePolygon,
+		Polygon: []entity.GeoPointEntity{
+			{Lat: -6.85, Lng: 107.55},
+			{Lat: -6.85, Lng: 107.70},
+			{Lat: -6.98, Lng: 107.70},
+			{Lat: -6.98, Lng: 107.55},
+		},
+		IsActive: true,
+	}
+}
+
+func TestDeliveryZoneService_CheckPoint_InsideRadius(t *testing.T) {
+	// Setup
+	mockZoneRepo := new(mocks.MockDeliveryZoneRepository)
+	zoneService := service.NewDeliveryZoneService(mockZoneRepo)
+
+	ctx := context.Background()
+	mockZoneRepo.On("GetAllZones", ctx, true).Return([]entity.DeliveryZoneEntity{bandungPolygonZone(), jakartaRadiusZone()}, nil)
+
+	// Execute - Gambir station is ~1km from Monas
+	zone, covered, err := zoneService.CheckPoint(ctx, -6.1767, 106.8306)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.True(t, covered)
+	assert.Equal(t, int64(1), zone.ID)
+	mockZoneRepo.AssertExpectations(t)
+}
+
+func TestDeliveryZoneService_CheckPoint_InsidePolygon(t *testing.T) {
+	mockZoneRepo := new(mocks.MockDeliveryZoneRepository)
+	zoneService := service.NewDeliveryZoneService(mockZoneRepo)
+
+	ctx := context.Background()
+	mockZoneRepo.On("GetAllZones", ctx, true).Return([]entity.DeliveryZoneEntity{jakartaRadiusZone(), bandungPolygonZone()}, nil)
+
+	zone, covered, err := zoneService.CheckPoint(ctx, -6.9175, 107.6191)
+
+	assert.NoError(t, err)
+	assert.True(t, covered)
+	assert.Equal(t, "Bandung Kota", zone.Name)
+}
+
+func TestDeliveryZoneService_CheckPoint_Outside(t *testing.T) {
+	mockZoneRepo := new(mocks.MockDeliveryZoneRepository)
+	zoneService := service.NewDeliveryZoneService(mockZoneRepo)
+
+	ctx := context.Background()
+	mockZoneRepo.On("GetAllZones", ctx, true).Return([]entity.DeliveryZoneEntity{jakartaRadiusZone(), bandungPolygonZone()}, nil)
+
+	// Surabaya
+	zone, covered, err := zoneService.CheckPoint(ctx, -7.2575, 112.7521)
+
+	assert.NoError(t, err)
+	assert.False(t, covered)
+	assert.Nil(t, zone)
+}
+
+func TestDeliveryZoneService_CheckPoint_NoZonesConfigured(t *testing.T) {
+	mockZoneRepo := new(mocks.MockDeliveryZoneRepository)
+	zoneService := service.NewDeliveryZoneService(mockZoneRepo)
+
+	ctx := context.Background()
+	mockZoneRepo.On("GetAllZones", ctx, true).Return([]entity.DeliveryZoneEntity{}, nil)
+
+	zone, covered, err := zoneService.CheckPoint(ctx, -7.2575, 112.7521)
+
+	assert.NoError(t, err)
+	assert.True(t, covered, "coverage is unrestricted until a zone is defined")
+	assert.Nil(t, zone)
+}
+
+func TestDeliveryZoneService_CheckPoint_InvalidCoordinates(t *testing.T) {
+	mockZoneRepo := new(mocks.MockDeliveryZoneRepository)
+	zoneService := service.NewDeliveryZoneService(mockZoneRepo)
+
+	_, _, err := zoneService.CheckPoint(context.Background(), 120, 106.8)
+
+	assert.Error(t, err)
+	assert.Equal(t, "invalid coordinates", err.Error())
+	mockZoneRepo.AssertNotCalled(t, "GetAllZones", mock.Anything, mock.Anything)
+}
+
+func TestDeliveryZoneService_CreateZone_Validation(t *testing.T) {
+	mockZoneRepo := new(mocks.MockDeliveryZoneRepository)
+	zoneService := service.NewDeliveryZoneService(mockZoneRepo)
+
+	cases := []struct {
+		name    string
+		zone    entity.DeliveryZoneEntity
+		message string
+	}{
+		{"unknown type", entity.DeliveryZoneEntity{Name: "Zone", ZoneType: "circle"}, "zone type must be radius or polygon"},
+		{"radius without radius", entity.DeliveryZoneEntity{Name: "Zone", ZoneType: entity.ZoneTypeRadius, CenterLat: jakartaLat, CenterLng: jakartaLng}, "radius zone requires a valid center and a positive radius"},
+		{"polygon with two points", entity.DeliveryZoneEntity{Name: "Zone", ZoneType: entity.ZoneTypePolygon, Polygon: []entity.GeoPointEntity{{Lat: -6.1, Lng: 106.1}, {Lat: -6.2, Lng: 106.2}}}, "polygon zone requires at least 3 points"},
+		{"short name", entity.DeliveryZoneEntity{Name: " A ", ZoneType: entity.ZoneTypeRadius}, "zone name must be between 2 and 100 characters"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			zone := tc.zone
+			_, err := zoneService.CreateZone(context.Background(), &zone)
+			assert.Error(t, err)
+			assert.Equal(t, tc.message, err.Error())
+		})
+	}
+
+	mockZoneRepo.AssertNotCalled(t, "CreateZone", mock.Anything, mock.Anything)
+}
+
+func TestDeliveryZoneService_CreateZone_DuplicateName(t *testing.T) {
+	mockZoneRepo := new(mocks.MockDeliveryZoneRepository)
+	zoneService := service.NewDeliveryZoneService(mockZoneRepo)
+
+	ctx := context.Background()
+	mockZoneRepo.On("GetAllZones", ctx, false).Return([]entity.DeliveryZoneEntity{jakartaRadiusZone()}, nil)
+
+	zone := jakartaRadiusZone()
+	zone.ID = 0
+	zone.Name = "jakarta pusat"
+	_, err := zoneService.CreateZone(ctx, &zone)
+
+	assert.Error(t, err)
+	assert.Equal(t, "zone with name 'jakarta pusat' already exists", err.Error())
+}
+
+func TestDeliveryZoneService_DeleteZone_NotFound(t *testing.T) {
+	mockZoneRepo := new(mocks.MockDeliveryZoneRepository)
+	zoneService := service.NewDeliveryZoneService(mockZoneRepo)
+
+	ctx := context.Background()
+	mockZoneRepo.On("DeleteZone", ctx, int64(99)).Return(errors.New("record not found"))
+
+	err := zoneService.DeleteZone(ctx, 99)
+
+	assert.Error(t, err)
+	assert.Equal(t, "zone not found", err.Error())
+}
+
+func TestUserService_UpdateProfile_OutsideDeliveryArea(t *testing.T) {
+	// Setup
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockZoneRepo := new(mocks.MockDeliveryZoneRepository)
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, ZoneRepo: mockZoneRepo},
+		Config:          &config.Config{},
+	})
+
+	ctx := context.Background()
+	mockZoneRepo.On("GetAllZones", ctx, true).Return([]entity.DeliveryZoneEntity{jakartaRadiusZone()}, nil)
+
+	// Execute - Surabaya is outside the Jakarta zone
+	err := userService.UpdateProfile(ctx, 1, "John", "john@example.com", "0812", "Jl. Tunjungan", -7.2575, 112.7521, "", 0)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Equal(t, "location is outside our delivery area", err.Error())
+	mockUserRepo.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+}
+
+func TestUserService_CreateUserAccount_OutsideDeliveryArea(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockZoneRepo := new(mocks.MockDeliveryZoneRepository)
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{UserRepo: mockUserRepo, ZoneRepo: mockZoneRepo},
+		Config:          &config.Config{},
+	})
+
+	ctx := context.Background()
+	mockZoneRepo.On("GetAllZones", ctx, true).Return([]entity.DeliveryZoneEntity{jakartaRadiusZone()}, nil)
+
+	err := userService.CreateUserAccount(ctx, "john@example.com", "John", "password123", "password123", "", -7.2575, 112.7521, nil)
+
+	assert.Error(t, err)
+	assert.Equal(t, "location is outside our delivery area", err.Error())
+	mockUserRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
+func TestUserService_CreateUserAccount_WithoutLocationSkipsZoneCheck(t *testing.T) {
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockZoneRepo := new(mocks.MockDeliveryZoneRepository)
+	mockVerificationTokenRepo := new(mocks.MockVerificationTokenRepository)
+	mockEmailPublisher := new(mocks.MockEmailPublisher)
+	userService := service.NewUserService(service.UserServiceDeps{
+		AuthServiceDeps: service.AuthServiceDeps{
+			UserRepo:              mockUserRepo,
+			VerificationTokenRepo: mockVerificationTokenRepo,
+			EmailPublisher:        mockEmailPublisher,
+			ZoneRepo:              mockZoneRepo,
+		},
+		Config: &config.Config{},
+	})
+
+	ctx := context.Background()
+	email := "john@example.com"
+	mockUserRepo.On("GetUserByEmailIncludingUnverified", ctx, email).Return(nil, errors.New("record not found"))
+	mockUserRepo.On("CreateUser", ctx, mock.AnythingOfType("*entity.UserEntity")).Return(&entity.UserEntity{ID: 1, Email: email}, nil)
+	mockVerificationTokenRepo.On("CreateVerificationToken", ctx, mock.AnythingOfType("*entity.VerificationTokenEntity")).Return(nil)
+	mockEmailPublisher.On("SendVerificationEmail", ctx, email, mock.AnythingOfType("string")).Return(nil)
+
+	err := userService.CreateUserAccount(ctx, email, "John", "password123", "password123", "", 0, 0, nil)
+
+	assert.NoError(t, err)
+	mockZoneRepo.AssertNotCalled(t, "GetAllZones", mock.Anything, mock.Anything)
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 
 	"user-service/config"
+	"user-service/utils"
 )
 
 func main() {
@@ -13,7 +14,7 @@ func main() {
 	log.Println("=== CONFIG TEST ===")
 	log.Printf("App Port: %s", cfg.App.AppPort)
 	log.Printf("App Env: %s", cfg.App.AppEnv)
-	log.Printf("JWT Secret: %s", cfg.App.JwtSecretKey)
+	log.Printf("JWT Secret: %s (fingerprint)", utils.SecretFingerprint(cfg.App.JwtSecretKey))
 	log.Printf("JWT Issuer: %s", cfg.App.JwtIssuer)
 
 	log.Println("\n=== DATABASE CONFIG ===")
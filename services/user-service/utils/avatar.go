@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+)
+
+// AvatarSize is the edge length in pixels of generated avatars
+const AvatarSize = 256
+
+const avatarGrid = 5
+
+var avatarBackground = color.RGBA{R: 0xf0, G: 0xf0, B: 0xf0, A: 0xff}
+
+// GenerateAvatar renders a mirrored 5x5 identicon for seed as a PNG. The same seed always
+// gives the same image, so an account keeps its avatar until it is regenerated with a new seed.
+func GenerateAvatar(seed string, size int) ([]byte, error) {
+	if size < avatarGrid {
+		size = AvatarSize
+	}
+	hash := sha256.Sum256([]byte(seed))
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: avatarBackground}, image.Point{}, draw.Src)
+
+	cell := size * 8 / 10 / avatarGrid
+	offset := (size - cell*avatarGrid) / 2
+	fill := &image.Uniform{C: avatarColor(hash[0], hash[1])}
+
+	// Only the left three columns come from the hash; the right two mirror them
+	for row := 0; row < avatarGrid; row++ {
+		for col := 0; col < (avatarGrid+1)/2; col++ {
+			if hash[2+row*3+col]%2 == 0 {
+				continue
+			}
+			for _, c := range []int{col, avatarGrid - 1 - col} {
+				rect := image.Rect(offset+c*cell, offset+row*cell, offset+(c+1)*cell, offset+(row+1)*cell)
+				draw.Draw(img, rect, fill, image.Point{}, draw.Src)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// avatarColor picks a hue from the hash at fixed saturation and lightness, so every
+// avatar is readable on the light background
+func avatarColor(hueByte, shadeByte byte) color.RGBA {
+	hue := float64(hueByte) / 256 * 360
+	lightness := 0.45 + float64(shadeByte%16)/100
+	return hslToRGB(hue, 0.55, lightness)
+}
+
+func hslToRGB(hue, saturation, lightness float64) color.RGBA {
+	chroma := (1 - math.Abs(2*lightness-1)) * saturation
+	segment := hue / 60
+	x := chroma * (1 - math.Abs(math.Mod(segment, 2)-1))
+
+	var r, g, b float64
+	switch {
+	case segment < 1:
+		r, g, b = chroma, x, 0
+	case segment < 2:
+		r, g, b = x, chroma, 0
+	case segment < 3:
+		r, g, b = 0, chroma, x
+	case segment < 4:
+		r, g, b = 0, x, chroma
+	case segment < 5:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+
+	m := lightness - chroma/2
+	return color.RGBA{R: uint8((r + m) * 255), G: uint8((g + m) * 255), B: uint8((b + m) * 255), A: 0xff}
+}
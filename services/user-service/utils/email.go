@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrInvalidEmail    = errors.New("invalid email format")
+	ErrDisposableEmail = errors.New("disposable email addresses are not allowed")
+)
+
+// Plus-addressing policies: keep "budi+promo@x.com" as is, or store it as "budi@x.com"
+const (
+	PlusAddressingKeep  = "keep"
+	PlusAddressingStrip = "strip"
+)
+
+// DisposableEmailDomains is the built-in blocklist; EMAIL_DISPOSABLE_DOMAINS adds to it
+var DisposableEmailDomains = []string{
+	"10minutemail.com", "10minutemail.net", "burnermail.io", "discard.email", "dispostable.com",
+	"emailfake.com", "emailondeck.com", "fakeinbox.com", "getnada.com", "grr.la",
+	"guerrillamail.com", "guerrillamail.net", "guerrillamail.org", "guerrillamailblock.com", "mailcatch.com",
+	"maildrop.cc", "mailinator.com", "mailnesia.com", "minuteinbox.com", "mintemail.com",
+	"moakt.com", "mohmal.com", "mytemp.email", "sharklasers.com", "spam4.me",
+	"spamgourmet.com", "temp-mail.io", "temp-mail.org", "tempail.com", "tempmail.com",
+	"tempr.email", "throwawaymail.com", "trashmail.com", "trashmail.de", "yopmail.com",
+	"yopmail.fr",
+}
+
+// EmailPolicy decides how addresses are canonicalized and which domains are refused
+type EmailPolicy struct {
+	PlusAddressing  string
+	BlockDisposable bool
+	blocked         map[string]struct{}
+}
+
+// NewEmailPolicy builds a policy blocking the built-in disposable domains plus extraDomains
+func NewEmailPolicy(plusAddressing string, blockDisposable bool, extraDomains []string) *EmailPolicy {
+	policy := &EmailPolicy{
+		PlusAddressing:  plusAddressing,
+		BlockDisposable: blockDisposable,
+		blocked:         make(map[string]struct{}),
+	}
+	for _, domain := range append(append([]string{}, DisposableEmailDomains...), extraDomains...) {
+		if domain = strings.ToLower(strings.TrimSpace(domain)); domain != "" {
+			policy.blocked[domain] = struct{}{}
+		}
+	}
+	return policy
+}
+
+// DefaultEmailPolicy keeps plus tags and blocks the built-in disposable domains
+func DefaultEmailPolicy() *EmailPolicy {
+	return NewEmailPolicy(PlusAddressingKeep, true, nil)
+}
+
+// NormalizeEmail returns the canonical form of an address: NFC-normalized, lowercased,
+// with an ASCII (punycode) domain. Gmail ignores dots in the local part and treats
+// googlemail.com as gmail.com, so those are folded; plus tags follow the policy.
+func (p *EmailPolicy) NormalizeEmail(email string) (string, error) {
+	email = norm.NFC.String(strings.TrimSpace(email))
+	if email == "" || strings.ContainsAny(email, " \t\r\n<>") {
+		return "", ErrInvalidEmail
+	}
+
+	// Rejects display names, comments and anything else that is not a bare address
+	parsed, err := mail.ParseAddress(email)
+	if err != nil || parsed.Address != email {
+		return "", ErrInvalidEmail
+	}
+
+	at := strings.LastIndex(email, "@")
+	local, domain := strings.ToLower(email[:at]), strings.TrimSuffix(strings.ToLower(email[at+1:]), ".")
+
+	domain, err = idna.Lookup.ToASCII(domain)
+	if err != nil || !strings.Contains(domain, ".") {
+		return "", ErrInvalidEmail
+	}
+
+	if p.PlusAddressing == PlusAddressingStrip {
+		if plus := strings.Index(local, "+"); plus > 0 {
+			local = local[:plus]
+		}
+	}
+
+	if domain == "gmail.com" || domain == "googlemail.com" {
+		domain = "gmail.com"
+		local = strings.ReplaceAll(local, ".", "")
+	}
+
+	if local == "" {
+		return "", ErrInvalidEmail
+	}
+	return local + "@" + domain, nil
+}
+
+// CheckDomain refuses disposable domains and their subdomains; email must already be normalized
+func (p *EmailPolicy) CheckDomain(email string) error {
+	if !p.BlockDisposable {
+		return nil
+	}
+
+	domain := email[strings.LastIndex(email, "@")+1:]
+	for {
+		if _, blocked := p.blocked[domain]; blocked {
+			return ErrDisposableEmail
+		}
+		dot := strings.Index(domain, ".")
+		if dot < 0 {
+			return nil
+		}
+		domain = domain[dot+1:]
+	}
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"errors"
+	"math"
+)
+
+var ErrInvalidCoordinates = errors.New("invalid coordinates")
+
+// ValidateCoordinates checks that lat/lng are finite and inside the WGS84 range
+func ValidateCoordinates(lat, lng float64) error {
+	if math.IsNaN(lat) || math.IsNaN(lng) || math.IsInf(lat, 0) || math.IsInf(lng, 0) {
+		return ErrInvalidCoordinates
+	}
+
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return ErrInvalidCoordinates
+	}
+
+	// 0,0 is what a failed parse produces, never a real delivery address
+	if lat == 0 && lng == 0 {
+		return ErrInvalidCoordinates
+	}
+
+	return nil
+}
+
+const earthRadiusKm = 6371.0
+
+// HaversineKm returns the great-circle distance between two points in kilometers
+func HaversineKm(lat1, lng1, lat2, lng2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLng := toRad(lng2 - lng1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// PointInPolygon uses ray casting; vertices are [lat, lng] pairs and the ring may be open or closed.
+// Delivery zones are city-sized, so treating lat/lng as planar is accurate enough.
+func PointInPolygon(lat, lng float64, vertices [][2]float64) bool {
+	if len(vertices) < 3 {
+		return false
+	}
+
+	inside := false
+	for i, j := 0, len(vertices)-1; i < len(vertices); j, i = i, i+1 {
+		latI, lngI := vertices[i][0], vertices[i][1]
+		latJ, lngJ := vertices[j][0], vertices[j][1]
+
+		if (latI > lat) != (latJ > lat) &&
+			lng < (lngJ-lngI)*(lat-latI)/(latJ-latI)+lngI {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
@@ -0,0 +1,180 @@
+package i18n
+
+// catalogEN only holds templates; plain English messages are their own key
+var catalogEN = map[string]string{
+	"email.default_name": "User",
+	"email.signature":    "Best regards,\nYour App Team",
+
+	"duration.minute":  "{n} minute",
+	"duration.minutes": "{n} minutes",
+	"duration.hour":    "{n} hour",
+	"duration.hours":   "{n} hours",
+	"duration.day":     "{n} day",
+	"duration.days":    "{n} days",
+
+	"email.verification.subject": "Verify Your Account",
+	"email.verification.body": `Hi {name},
+
+Please click this link to verify your account:
+{link}
+
+Link expires in {expires}.
+
+If you didn't create an account, please ignore this email.
+
+{signature}`,
+
+	"email.email_change.subject": "Verify Your Email Change",
+	"email.email_change.body": `Hi {name},
+
+You requested to change your email address. Please click this link to verify your new email:
+{link}
+
+Link expires in {expires}.
+
+If you didn't request this change, please ignore this email.
+
+{signature}`,
+
+	"email.email_change_revert.subject": "Your Email Address Was Changed",
+	"email.email_change_revert.body": `Hi {name},
+
+The email address on your account was changed to {new_email}. This address will no longer be used to sign in.
+
+If you didn't make this change, click this link to restore this address and lock the account until our team has reviewed it:
+{link}
+
+Link expires in {expires}.
+
+If you made this change, you can ignore this email.
+
+{signature}`,
+
+	"email.password_reset.subject": "Reset Your Password",
+	"email.password_reset.body": `Hi {name},
+
+You requested to reset your password. Please click this link to reset your password:
+{link}
+
+Link expires in {expires}.
+
+If you didn't request this, please ignore this email.
+
+{signature}`,
+
+	"email.new_device.unknown_device":   "Unknown device",
+	"email.new_device.unknown_ip":       "Unknown",
+	"email.new_device.unknown_location": "Unknown",
+	"email.new_device.subject":          "New Sign-In to Your Account",
+	"email.new_device.body": `Hi {name},
+
+We noticed a sign-in to your account from a device we haven't seen before:
+
+Device: {device}
+IP address: {ip}
+Location: {location}
+Time: {time}
+
+If this was you, you can mark the device as trusted from your account settings.
+
+If this wasn't you, please reset your password immediately.
+
+{signature}`,
+
+	"email.password_changed.subject": "Your Password Was Changed",
+	"email.password_changed.body": `Hi {name},
+
+The password for your account was changed on {time}. For your security, you have been signed out on all devices.
+
+If you didn't change it, please reset your password again right away and contact our support team.
+
+{signature}`,
+
+	"email.password_expiry_warning.subject": "Your Password Expires Soon",
+	"email.password_expiry_warning.body": `Hi {name},
+
+The password for your staff account expires on {time}. After that you cannot sign in until you choose a new one.
+
+Change it now with "Forgot password" on the sign-in page. Recently used passwords cannot be picked again.
+
+{signature}`,
+
+	"email.signin_otp.subject": "Your Sign-In Verification Code",
+	"email.signin_otp.body": `Hi {name},
+
+We noticed unusual activity on a sign-in to your account. Enter this code to continue:
+
+{code}
+
+The code expires in {minutes} minutes.
+
+If you didn't try to sign in, please reset your password immediately.
+
+{signature}`,
+
+	"email.account_merged.subject": "Your Accounts Have Been Merged",
+	"email.account_merged.body": `Hi {name},
+
+At your request, our support team merged your account {merged_email} into {surviving_email}.
+
+From now on, please sign in with {surviving_email}. Your profile details, devices and order history are available there, and you have been signed out of the old account.
+
+If you didn't ask for this, please contact our support team right away.
+
+{signature}`,
+
+	"email.customer_invite.subject": "Your Account Is Ready",
+	"email.customer_invite.body": `Hi {name},
+
+An account has been created for you with this email address. Please click this link to set your password and start shopping:
+{link}
+
+Link expires in 7 days. After that you can still use "Forgot password" on the sign in page.
+
+If you don't recognize this, please ignore this email.
+
+{signature}`,
+
+	"email.account_locked_alert.subject": "Account #{user_id} locked for review",
+	"email.account_locked_alert.body": `Hi,
+
+The previous owner of account #{user_id} reverted an email change. The address {replaced_email} was removed, {restored_email} was restored, all sessions were signed out and the account is locked.
+
+Please review the account and unlock it from the admin panel once it is safe.
+
+{signature}`,
+
+	"email.photo_removed.default_reason": "it does not meet our community guidelines",
+	"email.photo_removed.subject":        "Your Profile Photo Was Removed",
+	"email.photo_removed.body": `Hi {name},
+
+We removed your profile photo because {reason}.
+
+You can upload a new photo from your profile at any time. Photos are reviewed after upload.
+
+If you think this was a mistake, please contact our support team.
+
+{signature}`,
+
+	"email.support_ticket_update.subject": "Ticket #{ticket_id}: {status_label}",
+	"email.support_ticket_update.note":    "\n\nMessage from our support team:\n{note}",
+	"email.support_ticket_update.body": `Hi {name},
+
+Your support ticket #{ticket_id} "{subject}" is now {status_label}.{note_block}
+
+You can follow the ticket from the Help section of the app.
+
+{signature}`,
+
+	"support.status.open":                "open",
+	"support.status.in_progress":         "in progress",
+	"support.status.waiting_on_customer": "waiting for your reply",
+	"support.status.resolved":            "resolved",
+	"support.status.closed":              "closed",
+
+	// Validation messages use the validator's "{0}" (field) and "{1}" (parameter) placeholders
+	"validation.required": "{0} is required",
+	"validation.email":    "{0} must be a valid email address",
+	"validation.min":      "{0} must be at least {1} characters",
+	"validation.username": "{0} must be 3-30 characters, start with a letter and contain only letters, numbers, '.' or '_'",
+}
@@ -0,0 +1,784 @@
+package i18n
+
+var catalogID = map[string]string{
+	// General
+	"Internal server error":               "Terjadi kesalahan pada server",
+	"Invalid request format":              "Format request tidak valid",
+	"Validation failed":                   "Validasi gagal",
+	"Not found":                           "Tidak ditemukan",
+	"Access denied":                       "Akses ditolak",
+	"Unauthorized":                        "Tidak terautentikasi",
+	"Rate limit exceeded":                 "Terlalu banyak request, coba lagi nanti",
+	"Request timed out, please try again": "Request melebihi batas waktu, silakan coba lagi",
+	"Failed to process request":           "Gagal memproses request",
+	"Invalid status filter":               "Filter status tidak valid",
+	"Invalid version, send the version from the latest read":           "Versi tidak valid, kirim versi dari data terakhir yang dibaca",
+	"The record was changed by someone else, reload and try again":     "Data telah diubah oleh pengguna lain, muat ulang dan coba lagi",
+	"Version is required, send it as If-Match header or version field": "Versi wajib diisi, kirim melalui header If-Match atau field version",
+	"Invalid or missing CSRF token":                                    "Token CSRF tidak valid atau tidak ada",
+	"CSRF token issued":                                                "Token CSRF diterbitkan",
+	"Not Found":                                                        "Tidak ditemukan",
+	"Method Not Allowed":                                               "Metode tidak diizinkan",
+	"Request Entity Too Large":                                         "Ukuran request terlalu besar",
+	"Service Unavailable":                                              "Layanan tidak tersedia",
+
+	// Authentication and sessions
+	"Authorization header required":                            "Header Authorization wajib diisi",
+	"Invalid authorization header format. Use: Bearer <token>": "Format header Authorization tidak valid. Gunakan: Bearer <token>",
+	"Authentication failed":                                    "Autentikasi gagal",
+	"Authentication successful":                                "Autentikasi berhasil",
+	"Sign in successful":                                       "Berhasil masuk",
+	"Logout successful":                                        "Berhasil keluar",
+	"Failed to logout":                                         "Gagal keluar",
+	"Session expired or invalid":                               "Sesi kedaluwarsa atau tidak valid",
+	"Session refreshed successfully":                           "Sesi berhasil diperbarui",
+	"Failed to refresh session":                                "Gagal memperbarui sesi",
+	"Token has been revoked":                                   "Token telah dicabut",
+	"Token is not bound to a session, please sign in again":    "Token tidak terikat pada sesi, silakan masuk kembali",
+	"Invalid or expired token":                                 "Token tidak valid atau kedaluwarsa",
+	"Invalid token type":                                       "Jenis token tidak valid",
+	"Incorrect password":                                       "Password salah",
+	"Invalid service token":                                    "Token layanan tidak valid",
+	"Internal API is not configured":                           "API internal belum dikonfigurasi",
+	"Additional verification required, a code has been sent to your email": "Verifikasi tambahan diperlukan, kode telah dikirim ke email Anda",
+	"Invalid verification code":                                "Kode verifikasi tidak valid",
+	"Verification code has expired, please sign in again":      "Kode verifikasi telah kedaluwarsa, silakan masuk kembali",
+	"Too many attempts, please sign in again":                  "Terlalu banyak percobaan, silakan masuk kembali",
+	"Unable to send verification code, please try again later": "Tidak dapat mengirim kode verifikasi, silakan coba lagi nanti",
+
+	// Account, verification and password
+	"Account created successfully. Please check your email for verification.":       "Akun berhasil dibuat. Silakan cek email Anda untuk verifikasi.",
+	"Account verified successfully. You can now sign in.":                           "Akun berhasil diverifikasi. Sekarang Anda dapat masuk.",
+	"Failed to create account":                                                      "Gagal membuat akun",
+	"Failed to verify account":                                                      "Gagal memverifikasi akun",
+	"Failed to create verification token":                                           "Gagal membuat token verifikasi",
+	"Failed to generate verification token":                                         "Gagal membuat token verifikasi",
+	"Failed to update verification status":                                          "Gagal memperbarui status verifikasi",
+	"Invalid or expired verification token":                                         "Token verifikasi tidak valid atau kedaluwarsa",
+	"Verification token is required":                                                "Token verifikasi wajib diisi",
+	"Email change verified successfully. You can now sign in with your new email.":  "Perubahan email berhasil diverifikasi. Sekarang Anda dapat masuk dengan email baru.",
+	"Failed to verify email change":                                                 "Gagal memverifikasi perubahan email",
+	"If an account with this email exists, you will receive a password reset link.": "Jika akun dengan email ini terdaftar, Anda akan menerima tautan reset password.",
+	"Password reset successfully. You can now sign in with your new password.":      "Password berhasil direset. Sekarang Anda dapat masuk dengan password baru.",
+	"Failed to reset password":                                                      "Gagal mereset password",
+	"Invalid or expired reset token":                                                "Token reset tidak valid atau kedaluwarsa",
+	"This link has already been used":                                               "Tautan ini sudah pernah digunakan",
+	"Reset token is required":                                                       "Token reset wajib diisi",
+	"Email already exists":                                                          "Email sudah terdaftar",
+	"Email is required":                                                             "Email wajib diisi",
+	"Invalid email format":                                                          "Format email tidak valid",
+	"Disposable email addresses are not allowed":                                    "Alamat email sementara tidak diizinkan",
+	"Unable to verify email availability":                                           "Tidak dapat memeriksa ketersediaan email",
+	"Password is required":                                                          "Password wajib diisi",
+	"Password must be at least 8 characters long":                                   "Password minimal 8 karakter",
+	"Password confirmation is required":                                             "Konfirmasi password wajib diisi",
+	"Password confirmation does not match":                                          "Konfirmasi password tidak cocok",
+
+	// Profile
+	"Profile retrieved successfully":               "Profil berhasil diambil",
+	"Profile updated successfully":                 "Profil berhasil diperbarui",
+	"Failed to update profile":                     "Gagal memperbarui profil",
+	"Profile image uploaded successfully":          "Foto profil berhasil diunggah",
+	"Failed to upload image to storage":            "Gagal mengunggah gambar ke storage",
+	"Avatar regenerated successfully":              "Avatar berhasil dibuat ulang",
+	"Name is required":                             "Nama wajib diisi",
+	"Name must be at least 2 characters long":      "Nama minimal 2 karakter",
+	"Name must not exceed 100 characters":          "Nama maksimal 100 karakter",
+	"Phone is required":                            "Nomor telepon wajib diisi",
+	"Address is required":                          "Alamat wajib diisi",
+	"Photo is required":                            "Foto wajib diisi",
+	"Latitude is required":                         "Latitude wajib diisi",
+	"Longitude is required":                        "Longitude wajib diisi",
+	"Latitude must be between -90 and 90":          "Latitude harus di antara -90 dan 90",
+	"Longitude must be between -180 and 180":       "Longitude harus di antara -180 dan 180",
+	"Invalid coordinates":                          "Koordinat tidak valid",
+	"Username is required":                         "Username wajib diisi",
+	"Username already taken":                       "Username sudah dipakai",
+	"Username is reserved":                         "Username tidak dapat digunakan",
+	"Username updated successfully":                "Username berhasil diperbarui",
+	"Username availability retrieved successfully": "Ketersediaan username berhasil diperiksa",
+	"Username must be 3-30 characters, start with a letter and contain only letters, numbers, '.' or '_'": "Username harus 3-30 karakter, diawali huruf dan hanya berisi huruf, angka, '.' atau '_'",
+
+	"Timezone must be an IANA name such as Asia/Jakarta": "Zona waktu harus berupa nama IANA seperti Asia/Jakarta",
+	"Timezone updated successfully":                      "Zona waktu berhasil diperbarui",
+	"Language must be en or id":                          "Bahasa harus en atau id",
+	"Language updated successfully":                      "Bahasa berhasil diperbarui",
+	"Privacy settings updated successfully":              "Pengaturan privasi berhasil diperbarui",
+
+	// Files and uploads
+	"File is required":                                                      "File wajib diisi",
+	"File is empty":                                                         "File kosong",
+	"File must be between 1 byte and 5MB":                                   "Ukuran file harus antara 1 byte dan 5MB",
+	"File size too large, maximum 5MB":                                      "Ukuran file terlalu besar, maksimal 5MB",
+	"Failed to process file":                                                "Gagal memproses file",
+	"Failed to process uploaded file":                                       "Gagal memproses file yang diunggah",
+	"Invalid file type, only JPEG, PNG and PDF are allowed":                 "Jenis file tidak valid, hanya JPEG, PNG dan PDF yang diizinkan",
+	"Invalid file type, only JPEG, PNG, GIF and WebP images are allowed":    "Jenis file tidak valid, hanya gambar JPEG, PNG, GIF dan WebP yang diizinkan",
+	"Image cannot be read":                                                  "Gambar tidak bisa dibaca",
+	"Image dimensions too large":                                            "Dimensi gambar terlalu besar",
+	"Rotation must be 0, 90, 180 or 270":                                    "Rotasi harus 0, 90, 180 atau 270",
+	"Crop is outside the image":                                             "Area crop berada di luar gambar",
+	"Request must be multipart/form-data with a file field":                 "Request harus berupa multipart/form-data dengan field file",
+	"Invalid multipart body":                                                "Body multipart tidak valid",
+	"Storage service unavailable":                                           "Layanan storage tidak tersedia",
+	"File storage is unavailable, please try again later":                   "Storage file tidak tersedia, silakan coba lagi nanti",
+	"File was rejected by the virus scanner":                                "File ditolak oleh pemindai virus",
+	"File was rejected by the virus scanner, the upload was discarded":      "File ditolak oleh pemindai virus, upload dibuang",
+	"File is larger than the storage allows, the upload was discarded":      "Ukuran file melebihi batas storage, upload dibuang",
+	"File scanning is temporarily unavailable, please try again later":      "Pemindaian file sedang tidak tersedia, silakan coba lagi nanti",
+	"Upload created successfully":                                           "Upload berhasil dibuat",
+	"Upload retrieved successfully":                                         "Upload berhasil diambil",
+	"Upload completed successfully":                                         "Upload berhasil diselesaikan",
+	"Upload aborted successfully":                                           "Upload berhasil dibatalkan",
+	"Upload not found":                                                      "Upload tidak ditemukan",
+	"Chunk uploaded successfully":                                           "Chunk berhasil diunggah",
+	"Invalid chunk index":                                                   "Indeks chunk tidak valid",
+	"Checksum mismatch, the upload was discarded and must be started again": "Checksum tidak cocok, upload dibuang dan harus dimulai ulang",
+
+	// Photo moderation
+	"Invalid photo moderation ID format":                      "Format ID moderasi foto tidak valid",
+	"Photo moderations retrieved successfully":                "Daftar moderasi foto berhasil diambil",
+	"Photo moderation retrieved successfully":                 "Moderasi foto berhasil diambil",
+	"Photo approved successfully":                             "Foto berhasil disetujui",
+	"Photo removed successfully. The user has been notified.": "Foto berhasil dihapus. Pengguna telah diberi tahu.",
+
+	// Users and customers
+	"User not found":               "Pengguna tidak ditemukan",
+	"Users retrieved successfully": "Daftar pengguna berhasil diambil",
+	"Password was changed, but signing out other devices failed. Please open the reset link again.": "Password sudah diubah, tetapi gagal mengeluarkan perangkat lain. Silakan buka kembali tautan reset.",
+	"Account is locked pending review. Please contact support.":                                     "Akun dikunci sambil menunggu peninjauan. Silakan hubungi dukungan.",
+	"Revert token is required":        "Token pembatalan wajib diisi",
+	"Invalid or expired revert token": "Token pembatalan tidak valid atau sudah kedaluwarsa",
+	"Previous email is already used by another account. Please contact support.":        "Email sebelumnya sudah dipakai akun lain. Silakan hubungi dukungan.",
+	"Failed to revert email change":                                                     "Gagal membatalkan perubahan email",
+	"Your email has been restored and the account is locked until our team reviews it.": "Email Anda telah dipulihkan dan akun dikunci sampai tim kami selesai meninjaunya.",
+	"Token introspected successfully":                                                   "Token berhasil diperiksa",
+	"token is required":                                                                 "token wajib diisi",
+	"Failed to retrieve users":                                                          "Gagal mengambil daftar pengguna",
+	"Invalid user ID format":                                                            "Format ID pengguna tidak valid",
+	"Too many user ids, maximum is 500":                                                 "Terlalu banyak ID pengguna, maksimal 500",
+	"Customer not found":                                                                "Pelanggan tidak ditemukan",
+	"Customer retrieved successfully":                                                   "Pelanggan berhasil diambil",
+	"Customers retrieved successfully":                                                  "Daftar pelanggan berhasil diambil",
+	"Failed to retrieve customer":                                                       "Gagal mengambil data pelanggan",
+	"Failed to retrieve customers":                                                      "Gagal mengambil daftar pelanggan",
+	"Invalid customer ID format":                                                        "Format ID pelanggan tidak valid",
+	"Nearby customers retrieved successfully":                                           "Pelanggan terdekat berhasil diambil",
+	"Failed to retrieve nearby customers":                                               "Gagal mengambil pelanggan terdekat",
+	"Customer suggestions retrieved successfully":                                       "Saran pelanggan berhasil diambil",
+	"Customer suggestions timed out":                                                    "Pencarian saran pelanggan melebihi batas waktu",
+	"Failed to suggest customers":                                                       "Gagal mengambil saran pelanggan",
+	"lat and lng query parameters must be valid numbers":                                "Parameter query lat dan lng harus berupa angka yang valid",
+	"radius_km must be a valid number":                                                  "radius_km harus berupa angka yang valid",
+	"Customers imported successfully":                                                   "Pelanggan berhasil diimpor",
+	"Import preview generated, no accounts were created":                                "Pratinjau impor dibuat, belum ada akun yang dibuat",
+	"Failed to import customers":                                                        "Gagal mengimpor pelanggan",
+	"Import report not found or expired":                                                "Laporan impor tidak ditemukan atau sudah kedaluwarsa",
+	"Failed to retrieve import report":                                                  "Gagal mengambil laporan impor",
+	"Invalid dry_run value":                                                             "Nilai dry_run tidak valid",
+	"Accounts merged successfully":                                                      "Akun berhasil digabungkan",
+	"Merge preview generated, no changes were made":                                     "Pratinjau penggabungan dibuat, belum ada perubahan",
+	"Failed to merge accounts":                                                          "Gagal menggabungkan akun",
+	"Locked accounts retrieved successfully":                                            "Daftar akun terkunci berhasil diambil",
+	"Failed to get locked accounts":                                                     "Gagal mengambil daftar akun terkunci",
+	"Account is not locked":                                                             "Akun tidak dalam keadaan terkunci",
+	"Account unlocked successfully":                                                     "Akun berhasil dibuka kembali",
+	"Failed to unlock account":                                                          "Gagal membuka kunci akun",
+
+	// Trash
+	"Deleted users retrieved successfully":         "Daftar pengguna terhapus berhasil diambil",
+	"Failed to retrieve deleted users":             "Gagal mengambil daftar pengguna terhapus",
+	"User not found in trash":                      "Pengguna tidak ditemukan di tempat sampah",
+	"User restored successfully":                   "Pengguna berhasil dipulihkan",
+	"User permanently deleted":                     "Pengguna dihapus permanen",
+	"User has ledger history and cannot be purged": "Pengguna memiliki riwayat ledger dan tidak dapat dihapus permanen",
+	"Email is already used by an active account":   "Email sudah dipakai akun aktif lain",
+	"Deleted roles retrieved successfully":         "Daftar role terhapus berhasil diambil",
+	"Failed to retrieve deleted roles":             "Gagal mengambil daftar role terhapus",
+	"Role not found in trash":                      "Role tidak ditemukan di tempat sampah",
+	"Role restored successfully":                   "Role berhasil dipulihkan",
+	"Role permanently deleted":                     "Role dihapus permanen",
+
+	// Roles
+	"Role not found":                        "Role tidak ditemukan",
+	"Role retrieved successfully":           "Role berhasil diambil",
+	"Roles retrieved successfully":          "Daftar role berhasil diambil",
+	"Role created successfully":             "Role berhasil dibuat",
+	"Role updated successfully":             "Role berhasil diperbarui",
+	"Role deleted successfully":             "Role berhasil dihapus",
+	"Failed to retrieve role":               "Gagal mengambil role",
+	"Failed to retrieve roles":              "Gagal mengambil daftar role",
+	"Failed to create role":                 "Gagal membuat role",
+	"Failed to update role":                 "Gagal memperbarui role",
+	"Failed to delete role":                 "Gagal menghapus role",
+	"Invalid role ID format":                "Format ID role tidak valid",
+	"Role parent updated successfully":      "Induk role berhasil diperbarui",
+	"Failed to update role parent":          "Gagal memperbarui induk role",
+	"parent role not found":                 "Role induk tidak ditemukan",
+	"role cannot inherit from itself":       "Role tidak bisa mewarisi dirinya sendiri",
+	"role hierarchy cannot contain a cycle": "Hierarki role tidak boleh membentuk siklus",
+
+	// Devices
+	"Devices retrieved successfully": "Daftar perangkat berhasil diambil",
+	"Device updated successfully":    "Perangkat berhasil diperbarui",
+	"Device not found":               "Perangkat tidak ditemukan",
+	"Invalid device ID format":       "Format ID perangkat tidak valid",
+	"trusted is required":            "trusted wajib diisi",
+
+	// Email verification and bounces
+	"If an unverified account with this email exists, a new verification link has been sent.": "Jika akun yang belum terverifikasi dengan email ini terdaftar, tautan verifikasi baru telah dikirim.",
+	"Emails to this address bounced. Please sign up again with a different email address.":    "Email ke alamat ini tidak terkirim (bounce). Silakan daftar ulang dengan alamat email lain.",
+	"Bounce recorded successfully": "Bounce berhasil dicatat",
+	"Failed to record bounce":      "Gagal mencatat bounce",
+
+	// Customer segments and campaigns
+	"Segments retrieved successfully":                         "Daftar segmen berhasil diambil",
+	"Segment retrieved successfully":                          "Segmen berhasil diambil",
+	"Segment created successfully":                            "Segmen berhasil dibuat",
+	"Segment refreshed successfully":                          "Segmen berhasil diperbarui",
+	"Segment deleted successfully":                            "Segmen berhasil dihapus",
+	"Segment not found":                                       "Segmen tidak ditemukan",
+	"Segment with this name already exists":                   "Segmen dengan nama ini sudah ada",
+	"Segment has no members, refresh it or change the filter": "Segmen tidak memiliki anggota, perbarui atau ubah filternya",
+	"Invalid segment ID format":                               "Format ID segmen tidak valid",
+	"Failed to retrieve segments":                             "Gagal mengambil daftar segmen",
+	"Failed to retrieve segment":                              "Gagal mengambil segmen",
+	"Failed to create segment":                                "Gagal membuat segmen",
+	"Failed to refresh segment":                               "Gagal memperbarui segmen",
+	"Failed to delete segment":                                "Gagal menghapus segmen",
+	"Campaign launched successfully":                          "Kampanye berhasil diluncurkan",
+	"Failed to launch campaign":                               "Gagal meluncurkan kampanye",
+	"Order recorded successfully":                             "Pesanan berhasil dicatat",
+	"Failed to record order":                                  "Gagal mencatat pesanan",
+
+	// Email assets
+	"Asset uploaded successfully":           "Asset berhasil diunggah",
+	"Assets retrieved successfully":         "Daftar asset berhasil diambil",
+	"Asset manifest retrieved successfully": "Manifest asset berhasil diambil",
+	"Asset name is required":                "Nama asset wajib diisi",
+	"Asset exceeds the size limit":          "Ukuran asset melebihi batas",
+	"Failed to upload asset":                "Gagal mengunggah asset",
+	"Failed to retrieve assets":             "Gagal mengambil daftar asset",
+	"Failed to retrieve asset manifest":     "Gagal mengambil manifest asset",
+
+	// Invite-only registration
+	"An invite code is required to sign up":              "Kode undangan diperlukan untuk mendaftar",
+	"invalid invite code":                                "Kode undangan tidak valid",
+	"invite code has expired":                            "Kode undangan sudah kedaluwarsa",
+	"invite code has been used up":                       "Kode undangan sudah habis dipakai",
+	"invite code must be 4-32 letters, digits or dashes": "Kode undangan harus 4-32 huruf, angka atau tanda hubung",
+	"invite max_uses must be at least 1":                 "max_uses undangan minimal 1",
+	"invite expiry must be in the future":                "Masa berlaku undangan harus di masa depan",
+	"Invites retrieved successfully":                     "Daftar undangan berhasil diambil",
+	"Invite created successfully":                        "Undangan berhasil dibuat",
+	"Invite revoked successfully":                        "Undangan berhasil dicabut",
+	"Invite not found":                                   "Undangan tidak ditemukan",
+	"Invite code already exists":                         "Kode undangan sudah ada",
+	"Invalid invite ID format":                           "Format ID undangan tidak valid",
+	"Failed to retrieve invites":                         "Gagal mengambil daftar undangan",
+	"Failed to create invite":                            "Gagal membuat undangan",
+	"Failed to revoke invite":                            "Gagal mencabut undangan",
+
+	// Terms of service and privacy consent
+	"You must accept the terms of service and privacy policy":                          "Anda harus menyetujui syarat layanan dan kebijakan privasi",
+	"Please accept the updated terms of service and privacy policy to continue":        "Silakan setujui syarat layanan dan kebijakan privasi terbaru untuk melanjutkan",
+	"consent version is outdated":                                                      "Versi dokumen yang disetujui sudah tidak berlaku",
+	"legal document version already exists":                                            "Versi dokumen legal sudah ada",
+	"legal document kind must be terms or privacy":                                     "Jenis dokumen legal harus terms atau privacy",
+	"legal document version must be 1-32 letters, digits, dots, dashes or underscores": "Versi dokumen legal harus 1-32 huruf, angka, titik, tanda hubung atau garis bawah",
+	"legal document url must be an http(s) URL":                                        "URL dokumen legal harus berupa URL http(s)",
+	"Legal documents retrieved successfully":                                           "Dokumen legal berhasil diambil",
+	"Legal document published successfully":                                            "Dokumen legal berhasil diterbitkan",
+	"Consent retrieved successfully":                                                   "Persetujuan berhasil diambil",
+	"Consent recorded successfully":                                                    "Persetujuan berhasil dicatat",
+	"Failed to retrieve legal documents":                                               "Gagal mengambil dokumen legal",
+	"Failed to publish legal document":                                                 "Gagal menerbitkan dokumen legal",
+	"Failed to retrieve consent":                                                       "Gagal mengambil persetujuan",
+	"Failed to record consent":                                                         "Gagal mencatat persetujuan",
+
+	// Identity verification
+	"Date of birth must be in YYYY-MM-DD format":      "Tanggal lahir harus berformat YYYY-MM-DD",
+	"Identity document submitted for review":          "Dokumen identitas berhasil dikirim untuk ditinjau",
+	"Identity verification retrieved successfully":    "Verifikasi identitas berhasil diambil",
+	"Identity verifications retrieved successfully":   "Daftar verifikasi identitas berhasil diambil",
+	"Identity verification approved successfully":     "Verifikasi identitas berhasil disetujui",
+	"Identity verification rejected successfully":     "Verifikasi identitas berhasil ditolak",
+	"Identity verification not found":                 "Verifikasi identitas tidak ditemukan",
+	"Invalid identity verification ID format":         "Format ID verifikasi identitas tidak valid",
+	"identity verification already pending":           "Masih ada verifikasi identitas yang menunggu ditinjau",
+	"identity verification already reviewed":          "Verifikasi identitas sudah ditinjau",
+	"document type must be one of ktp, passport, sim": "Jenis dokumen harus salah satu dari ktp, passport, sim",
+	"date of birth is invalid":                        "Tanggal lahir tidak valid",
+	"document is empty":                               "Dokumen kosong",
+	"document must be a JPEG, PNG or PDF file":        "Dokumen harus berupa file JPEG, PNG atau PDF",
+	"Document exceeds the size limit":                 "Ukuran dokumen melebihi batas",
+	"Failed to retrieve identity verification":        "Gagal mengambil verifikasi identitas",
+	"Failed to retrieve identity verifications":       "Gagal mengambil daftar verifikasi identitas",
+	"Failed to approve identity verification":         "Gagal menyetujui verifikasi identitas",
+	"Failed to reject identity verification":          "Gagal menolak verifikasi identitas",
+
+	// Support tickets
+	"Support ticket created successfully":                              "Tiket support berhasil dibuat",
+	"Support tickets retrieved successfully":                           "Daftar tiket support berhasil diambil",
+	"Support ticket retrieved successfully":                            "Tiket support berhasil diambil",
+	"Support ticket assigned successfully":                             "Tiket support berhasil ditugaskan",
+	"Support ticket updated successfully":                              "Tiket support berhasil diperbarui",
+	"Support ticket not found":                                         "Tiket support tidak ditemukan",
+	"Support ticket already has this status":                           "Tiket support sudah berstatus ini",
+	"Assignee not found":                                               "Staf yang ditugaskan tidak ditemukan",
+	"Invalid support ticket ID format":                                 "Format ID tiket support tidak valid",
+	"Invalid category filter":                                          "Filter kategori tidak valid",
+	"Invalid assignee filter":                                          "Filter staf tidak valid",
+	"category must be one of order, payment, delivery, account, other": "Kategori harus salah satu dari order, payment, delivery, account, other",
+	"subject is required and must be at most 150 characters":           "Subjek wajib diisi dan maksimal 150 karakter",
+	"message is required and must be at most 5000 characters":          "Pesan wajib diisi dan maksimal 5000 karakter",
+	"note must be at most 1000 characters":                             "Catatan maksimal 1000 karakter",
+	"too many attachments":                                             "Jumlah lampiran terlalu banyak",
+	"attachment is empty":                                              "Lampiran kosong",
+	"attachment must be a JPEG, PNG, WebP or PDF file":                 "Lampiran harus berupa file JPEG, PNG, WebP atau PDF",
+	"Attachment exceeds the size limit":                                "Ukuran lampiran melebihi batas",
+	"assignee must be a staff member":                                  "Tiket hanya bisa ditugaskan ke staf",
+	"Failed to create support ticket":                                  "Gagal membuat tiket support",
+	"Failed to retrieve support tickets":                               "Gagal mengambil daftar tiket support",
+	"Failed to retrieve support ticket":                                "Gagal mengambil tiket support",
+	"Failed to assign support ticket":                                  "Gagal menugaskan tiket support",
+	"Failed to update support ticket":                                  "Gagal memperbarui tiket support",
+
+	// Onboarding and features
+	"Onboarding retrieved successfully":    "Onboarding berhasil diambil",
+	"Onboarding dismissed successfully":    "Onboarding berhasil ditutup",
+	"First order recorded successfully":    "Pesanan pertama berhasil dicatat",
+	"Features retrieved successfully":      "Daftar fitur berhasil diambil",
+	"Failed to retrieve features":          "Gagal mengambil daftar fitur",
+	"Feature flags retrieved successfully": "Daftar feature flag berhasil diambil",
+	"Feature flag retrieved successfully":  "Feature flag berhasil diambil",
+	"Feature flag created successfully":    "Feature flag berhasil dibuat",
+	"Feature flag updated successfully":    "Feature flag berhasil diperbarui",
+	"Feature flag deleted successfully":    "Feature flag berhasil dihapus",
+	"Failed to retrieve feature flags":     "Gagal mengambil daftar feature flag",
+	"Override set successfully":            "Override berhasil disimpan",
+	"Override deleted successfully":        "Override berhasil dihapus",
+
+	// Geocoding and delivery zones
+	"Address resolved successfully":                 "Alamat berhasil ditemukan",
+	"Location resolved successfully":                "Lokasi berhasil ditemukan",
+	"Location not found":                            "Lokasi tidak ditemukan",
+	"Geocoding service unavailable":                 "Layanan geocoding tidak tersedia",
+	"Delivery zones retrieved successfully":         "Daftar zona pengiriman berhasil diambil",
+	"Delivery zone retrieved successfully":          "Zona pengiriman berhasil diambil",
+	"Delivery zone created successfully":            "Zona pengiriman berhasil dibuat",
+	"Delivery zone updated successfully":            "Zona pengiriman berhasil diperbarui",
+	"Delivery zone deleted successfully":            "Zona pengiriman berhasil dihapus",
+	"Delivery zone not found":                       "Zona pengiriman tidak ditemukan",
+	"Failed to retrieve delivery zones":             "Gagal mengambil daftar zona pengiriman",
+	"Invalid zone ID format":                        "Format ID zona tidak valid",
+	"Delivery coverage checked successfully":        "Jangkauan pengiriman berhasil diperiksa",
+	"Sorry, we do not deliver to your location yet": "Maaf, kami belum melayani pengiriman ke lokasi Anda",
+	"Unable to verify delivery coverage":            "Tidak dapat memeriksa jangkauan pengiriman",
+
+	// Saved views
+	"Saved views retrieved successfully":                         "Daftar tampilan tersimpan berhasil diambil",
+	"Saved view created successfully":                            "Tampilan tersimpan berhasil dibuat",
+	"Saved view updated successfully":                            "Tampilan tersimpan berhasil diperbarui",
+	"Saved view deleted successfully":                            "Tampilan tersimpan berhasil dihapus",
+	"Saved view not found":                                       "Tampilan tersimpan tidak ditemukan",
+	"Invalid saved view ID format":                               "Format ID tampilan tersimpan tidak valid",
+	"You already have a saved view with this name for this list": "Anda sudah memiliki tampilan tersimpan dengan nama ini untuk daftar ini",
+	"Failed to retrieve saved views":                             "Gagal mengambil daftar tampilan tersimpan",
+	"Failed to create saved view":                                "Gagal membuat tampilan tersimpan",
+	"Failed to update saved view":                                "Gagal memperbarui tampilan tersimpan",
+	"Failed to delete saved view":                                "Gagal menghapus tampilan tersimpan",
+	"saved view name must be between 1 and 100 characters":       "Nama tampilan tersimpan harus 1 sampai 100 karakter",
+
+	// Email rate limits
+	"Email rate limits retrieved successfully":                                "Batas pengiriman email berhasil diambil",
+	"Failed to retrieve email rate limits":                                    "Gagal mengambil batas pengiriman email",
+	"Email rate limit saved successfully":                                     "Batas pengiriman email berhasil disimpan",
+	"Failed to save email rate limit":                                         "Gagal menyimpan batas pengiriman email",
+	"Email rate limit deleted successfully":                                   "Batas pengiriman email berhasil dihapus",
+	"Failed to delete email rate limit":                                       "Gagal menghapus batas pengiriman email",
+	"Email rate limit not found":                                              "Batas pengiriman email tidak ditemukan",
+	"Email rate limits were saved but could not be applied, please try again": "Batas pengiriman email tersimpan tetapi belum dapat diterapkan, silakan coba lagi",
+	"email type must be * or a template name such as back_in_stock":           "jenis email harus * atau nama template seperti back_in_stock",
+	"max per hour must be between 0 and 1000":                                 "batas per jam harus antara 0 dan 1000",
+
+	// Vendors and ledger
+	"Vendor not found":               "Vendor tidak ditemukan",
+	"Vendor retrieved successfully":  "Vendor berhasil diambil",
+	"Vendors retrieved successfully": "Daftar vendor berhasil diambil",
+	"Failed to retrieve vendors":     "Gagal mengambil daftar vendor",
+	"Invalid vendor ID format":       "Format ID vendor tidak valid",
+	"Vendor account is not approved": "Akun vendor belum disetujui",
+	"Vendor application submitted. Please upload your KYC documents for review.":          "Pendaftaran vendor terkirim. Silakan unggah dokumen KYC Anda untuk ditinjau.",
+	"Vendor approved successfully. The vendor must sign in again to use vendor features.": "Vendor berhasil disetujui. Vendor harus masuk kembali untuk menggunakan fitur vendor.",
+	"Vendor rejected successfully":          "Vendor berhasil ditolak",
+	"Document uploaded successfully":        "Dokumen berhasil diunggah",
+	"Balance retrieved successfully":        "Saldo berhasil diambil",
+	"Failed to retrieve balance":            "Gagal mengambil saldo",
+	"Ledger entries retrieved successfully": "Daftar entri ledger berhasil diambil",
+	"Failed to retrieve ledger entries":     "Gagal mengambil daftar entri ledger",
+	"Ledger audited successfully":           "Audit ledger berhasil",
+	"Failed to audit ledger":                "Gagal mengaudit ledger",
+	"Order earning recorded successfully":   "Pendapatan pesanan berhasil dicatat",
+	"Failed to record earning":              "Gagal mencatat pendapatan",
+	"Withdrawal requested successfully":     "Penarikan dana berhasil diajukan",
+	"Withdrawal approved successfully":      "Penarikan dana berhasil disetujui",
+	"Withdrawal rejected successfully":      "Penarikan dana berhasil ditolak",
+	"Withdrawal not found":                  "Penarikan dana tidak ditemukan",
+	"Withdrawals retrieved successfully":    "Daftar penarikan dana berhasil diambil",
+	"Failed to request withdrawal":          "Gagal mengajukan penarikan dana",
+	"Failed to retrieve withdrawals":        "Gagal mengambil daftar penarikan dana",
+	"Invalid withdrawal ID format":          "Format ID penarikan dana tidak valid",
+
+	// Admin tools
+	"Audit logs retrieved successfully":            "Audit log berhasil diambil",
+	"Failed to retrieve audit logs":                "Gagal mengambil audit log",
+	"IP rules retrieved successfully":              "Daftar aturan IP berhasil diambil",
+	"IP rule added successfully":                   "Aturan IP berhasil ditambahkan",
+	"IP rule removed successfully":                 "Aturan IP berhasil dihapus",
+	"Failed to retrieve IP rules":                  "Gagal mengambil daftar aturan IP",
+	"Log level retrieved successfully":             "Level log berhasil diambil",
+	"Log level updated successfully":               "Level log berhasil diperbarui",
+	"Jobs retrieved successfully":                  "Daftar job berhasil diambil",
+	"Failed to retrieve jobs":                      "Gagal mengambil daftar job",
+	"Job not found":                                "Job tidak ditemukan",
+	"Job queued for retry":                         "Job dijadwalkan ulang",
+	"Failed to retry job":                          "Gagal menjadwalkan ulang job",
+	"Only failed jobs can be retried":              "Hanya job yang gagal yang dapat dijadwalkan ulang",
+	"Invalid job ID format":                        "Format ID job tidak valid",
+	"Webhook endpoints retrieved successfully":     "Daftar endpoint webhook berhasil diambil",
+	"Webhook endpoint retrieved successfully":      "Endpoint webhook berhasil diambil",
+	"Webhook endpoint created successfully":        "Endpoint webhook berhasil dibuat",
+	"Webhook endpoint updated successfully":        "Endpoint webhook berhasil diperbarui",
+	"Webhook endpoint deleted successfully":        "Endpoint webhook berhasil dihapus",
+	"Webhook deliveries retrieved successfully":    "Log pengiriman webhook berhasil diambil",
+	"Webhook delivery queued":                      "Pengiriman webhook dijadwalkan",
+	"Invalid webhook ID format":                    "Format ID webhook tidak valid",
+	"Invalid delivery ID format":                   "Format ID pengiriman tidak valid",
+	"Event ignored":                                "Event diabaikan",
+	"Invalid webhook signature":                    "Signature webhook tidak valid",
+	"Storage webhook is not configured":            "Webhook storage belum dikonfigurasi",
+	"Failed to process storage event":              "Gagal memproses event storage",
+	"Event accepted":                               "Event diterima",
+	"Event cannot be dispatched by other services": "Event tidak dapat dikirim oleh layanan lain",
+
+	// Fallback messages when an operation fails
+	"Failed to abort upload":                            "Gagal membatalkan upload",
+	"Failed to add IP rule":                             "Gagal menambahkan aturan IP",
+	"Failed to approve photo":                           "Gagal menyetujui foto",
+	"Failed to approve vendor":                          "Gagal menyetujui vendor",
+	"Failed to check delivery coverage":                 "Gagal memeriksa jangkauan pengiriman",
+	"Failed to complete upload":                         "Gagal menyelesaikan upload",
+	"Failed to create delivery zone":                    "Gagal membuat zona pengiriman",
+	"Failed to create feature flag":                     "Gagal membuat feature flag",
+	"Failed to create upload":                           "Gagal membuat upload",
+	"Failed to create webhook endpoint":                 "Gagal membuat endpoint webhook",
+	"Failed to delete delivery zone":                    "Gagal menghapus zona pengiriman",
+	"Failed to delete feature flag":                     "Gagal menghapus feature flag",
+	"Failed to delete override":                         "Gagal menghapus override",
+	"Failed to delete webhook endpoint":                 "Gagal menghapus endpoint webhook",
+	"Failed to dismiss onboarding":                      "Gagal menutup onboarding",
+	"Failed to dispatch event":                          "Gagal mengirim event",
+	"Failed to get upload":                              "Gagal mengambil upload",
+	"Failed to purge role":                              "Gagal menghapus permanen role",
+	"Failed to purge user":                              "Gagal menghapus permanen pengguna",
+	"Failed to record first order":                      "Gagal mencatat pesanan pertama",
+	"Failed to redeliver webhook":                       "Gagal mengirim ulang webhook",
+	"Failed to register vendor":                         "Gagal mendaftarkan vendor",
+	"Failed to reject vendor":                           "Gagal menolak vendor",
+	"Failed to remove IP rule":                          "Gagal menghapus aturan IP",
+	"Failed to remove photo":                            "Gagal menghapus foto",
+	"Failed to restore role":                            "Gagal memulihkan role",
+	"Failed to restore user":                            "Gagal memulihkan pengguna",
+	"Failed to retrieve delivery zone":                  "Gagal mengambil zona pengiriman",
+	"Failed to retrieve devices":                        "Gagal mengambil daftar perangkat",
+	"Failed to retrieve feature flag":                   "Gagal mengambil feature flag",
+	"Failed to retrieve onboarding":                     "Gagal mengambil onboarding",
+	"Failed to retrieve photo moderation":               "Gagal mengambil moderasi foto",
+	"Failed to retrieve photo moderations":              "Gagal mengambil daftar moderasi foto",
+	"Failed to retrieve vendor":                         "Gagal mengambil vendor",
+	"Failed to retrieve webhook deliveries":             "Gagal mengambil log pengiriman webhook",
+	"Failed to retrieve webhook endpoint":               "Gagal mengambil endpoint webhook",
+	"Failed to retrieve webhook endpoints":              "Gagal mengambil daftar endpoint webhook",
+	"Failed to set override":                            "Gagal menyimpan override",
+	"Failed to store chunk":                             "Gagal menyimpan chunk",
+	"Failed to update delivery zone":                    "Gagal memperbarui zona pengiriman",
+	"Failed to update device":                           "Gagal memperbarui perangkat",
+	"Failed to update feature flag":                     "Gagal memperbarui feature flag",
+	"Failed to update webhook endpoint":                 "Gagal memperbarui endpoint webhook",
+	"Failed to upload document":                         "Gagal mengunggah dokumen",
+	"Service unavailable, please try again later":       "Layanan tidak tersedia, silakan coba lagi nanti",
+	"Sorry, this location is outside our delivery area": "Maaf, lokasi ini berada di luar area pengiriman kami",
+	"We deliver to this location":                       "Kami melayani pengiriman ke lokasi ini",
+
+	// Service errors that handlers pass through
+	"vendor not found":                                   "vendor tidak ditemukan",
+	"vendor application already exists":                  "pendaftaran vendor sudah ada",
+	"vendor is not pending review":                       "vendor tidak sedang menunggu peninjauan",
+	"vendor has no KYC documents":                        "vendor belum memiliki dokumen KYC",
+	"rejection reason is required":                       "alasan penolakan wajib diisi",
+	"store name must be between 3 and 100 characters":    "nama toko harus 3 sampai 100 karakter",
+	"invalid coordinates":                                "koordinat tidak valid",
+	"document type must be one of ktp, npwp, nib, other": "jenis dokumen harus salah satu dari ktp, npwp, nib, other",
+	"amount must be greater than 0":                      "jumlah harus lebih dari 0",
+	"insufficient balance":                               "saldo tidak mencukupi",
+	"withdrawal amount is below the minimum":             "jumlah penarikan di bawah batas minimum",
+	"withdrawal is not pending":                          "penarikan dana tidak sedang menunggu",
+	"order earning already recorded":                     "pendapatan pesanan sudah dicatat",
+	"order id is required":                               "order id wajib diisi",
+	"user not found":                                     "pengguna tidak ditemukan",
+	"source user not found":                              "pengguna sumber tidak ditemukan",
+	"target user not found":                              "pengguna tujuan tidak ditemukan",
+	"cannot merge an account into itself":                "akun tidak dapat digabungkan ke dirinya sendiri",
+	"both accounts have vendor profiles":                 "kedua akun memiliki profil vendor",
+	"merge reason is required":                           "alasan penggabungan wajib diisi",
+	"csv file is empty":                                  "file csv kosong",
+	"invalid csv file":                                   "file csv tidak valid",
+	"failed to read csv file":                            "gagal membaca file csv",
+	"csv header must contain name and email columns":     "header csv harus berisi kolom name dan email",
+	"feature flag not found":                             "feature flag tidak ditemukan",
+	"feature flag already exists":                        "feature flag sudah ada",
+	"override not found":                                 "override tidak ditemukan",
+	"flag key must be 2-100 lowercase letters, digits, dots, dashes or underscores": "key flag harus 2-100 karakter berupa huruf kecil, angka, titik, tanda hubung atau garis bawah",
+	"invalid IP address or CIDR range":                                              "alamat IP atau rentang CIDR tidak valid",
+	"ip rule not found":                                                             "aturan IP tidak ditemukan",
+	"list type must be allow or deny":                                               "jenis daftar harus allow atau deny",
+	"this change would block your current IP":                                       "perubahan ini akan memblokir IP Anda saat ini",
+	"webhook endpoint not found":                                                    "endpoint webhook tidak ditemukan",
+	"webhook delivery not found":                                                    "pengiriman webhook tidak ditemukan",
+	"webhook delivery is still pending":                                             "pengiriman webhook masih menunggu",
+	"webhook url must be an absolute http or https url":                             "url webhook harus berupa url http atau https absolut",
+	"webhook secret must be at least 16 characters":                                 "secret webhook minimal 16 karakter",
+	"unknown webhook event type":                                                    "jenis event webhook tidak dikenal",
+	"at least one event type is required":                                           "minimal satu jenis event wajib diisi",
+	"event id is required":                                                          "event id wajib diisi",
+	"photo moderation not found":                                                    "moderasi foto tidak ditemukan",
+	"photo moderation already decided":                                              "moderasi foto sudah diputuskan",
+	"removal reason is required":                                                    "alasan penghapusan wajib diisi",
+	"filename is required":                                                          "nama file wajib diisi",
+	"unsupported content type":                                                      "jenis konten tidak didukung",
+	"location is outside our delivery area":                                         "lokasi berada di luar area pengiriman kami",
+	"zone not found":                                                                "zona tidak ditemukan",
+	"device not found":                                                              "perangkat tidak ditemukan",
+	"job not found":                                                                 "job tidak ditemukan",
+	"username already taken":                                                        "username sudah dipakai",
+	"disposable email addresses are not allowed":                                    "alamat email sementara tidak diizinkan",
+	"email already exists":                                                          "email sudah terdaftar",
+	"invalid email format":                                                          "format email tidak valid",
+
+	"address is required":                         "alamat wajib diisi",
+	"failed to create account":                    "gagal membuat akun",
+	"failed to create reset token":                "gagal membuat token reset",
+	"failed to create verification token":         "gagal membuat token verifikasi",
+	"failed to generate reset token":              "gagal membuat token reset",
+	"failed to generate token":                    "gagal membuat token",
+	"failed to generate verification token":       "gagal membuat token verifikasi",
+	"failed to logout":                            "gagal keluar",
+	"failed to process password":                  "gagal memproses password",
+	"failed to process request":                   "gagal memproses request",
+	"failed to send verification code":            "gagal mengirim kode verifikasi",
+	"failed to update password":                   "gagal memperbarui password",
+	"failed to update profile":                    "gagal memperbarui profil",
+	"failed to update verification status":        "gagal memperbarui status verifikasi",
+	"failed to upload image":                      "gagal mengunggah gambar",
+	"failed to validate token":                    "gagal memvalidasi token",
+	"failed to verify account":                    "gagal memverifikasi akun",
+	"failed to verify email change":               "gagal memverifikasi perubahan email",
+	"failed to verify token":                      "gagal memverifikasi token",
+	"file is infected":                            "file terinfeksi",
+	"file exceeds the storage size limit":         "ukuran file melebihi batas storage",
+	"file scanner is unavailable":                 "pemindai file tidak tersedia",
+	"geocoding service unavailable":               "layanan geocoding tidak tersedia",
+	"incorrect password":                          "password salah",
+	"invalid or expired reset token":              "token reset tidak valid atau kedaluwarsa",
+	"invalid or expired verification token":       "token verifikasi tidak valid atau kedaluwarsa",
+	"invalid role id":                             "id role tidak valid",
+	"invalid token type":                          "jenis token tidak valid",
+	"invalid user id":                             "id pengguna tidak valid",
+	"invalid verification code":                   "kode verifikasi tidak valid",
+	"job is not failed":                           "job tidak dalam status gagal",
+	"location not found":                          "lokasi tidak ditemukan",
+	"password confirmation does not match":        "konfirmasi password tidak cocok",
+	"password is required":                        "password wajib diisi",
+	"password must be at least 8 characters long": "password minimal 8 karakter",
+	"role not found":                              "role tidak ditemukan",
+	"session required":                            "sesi diperlukan",
+	"storage service unavailable":                 "layanan storage tidak tersedia",
+	"too many user ids":                           "terlalu banyak id pengguna",
+	"too many verification attempts":              "terlalu banyak percobaan verifikasi",
+	"unable to verify delivery coverage":          "tidak dapat memeriksa jangkauan pengiriman",
+	"unable to verify email availability":         "tidak dapat memeriksa ketersediaan email",
+	"user has ledger history":                     "pengguna memiliki riwayat ledger",
+	"user ids are required":                       "id pengguna wajib diisi",
+	"vendor is not approved":                      "vendor belum disetujui",
+	"verification challenge not found":            "tantangan verifikasi tidak ditemukan",
+	"withdrawal not found":                        "penarikan dana tidak ditemukan",
+
+	// Emails
+	"email.default_name": "Pengguna",
+	"email.signature":    "Salam hangat,\nTim Jualan Sayur",
+
+	"duration.minute":  "{n} menit",
+	"duration.minutes": "{n} menit",
+	"duration.hour":    "{n} jam",
+	"duration.hours":   "{n} jam",
+	"duration.day":     "{n} hari",
+	"duration.days":    "{n} hari",
+
+	"email.verification.subject": "Verifikasi Akun Anda",
+	"email.verification.body": `Halo {name},
+
+Silakan klik tautan berikut untuk memverifikasi akun Anda:
+{link}
+
+Tautan berlaku selama {expires}.
+
+Jika Anda tidak membuat akun, abaikan email ini.
+
+{signature}`,
+
+	"email.email_change.subject": "Verifikasi Perubahan Email Anda",
+	"email.email_change.body": `Halo {name},
+
+Anda meminta perubahan alamat email. Silakan klik tautan berikut untuk memverifikasi email baru Anda:
+{link}
+
+Tautan berlaku selama {expires}.
+
+Jika Anda tidak meminta perubahan ini, abaikan email ini.
+
+{signature}`,
+
+	"email.email_change_revert.subject": "Alamat Email Anda Telah Diubah",
+	"email.email_change_revert.body": `Halo {name},
+
+Alamat email akun Anda telah diubah menjadi {new_email}. Alamat ini tidak akan dipakai lagi untuk login.
+
+Jika bukan Anda yang melakukan perubahan ini, klik tautan berikut untuk mengembalikan alamat ini dan mengunci akun sampai tim kami selesai meninjaunya:
+{link}
+
+Tautan berlaku selama {expires}.
+
+Jika Anda yang melakukan perubahan ini, abaikan email ini.
+
+{signature}`,
+
+	"email.password_reset.subject": "Reset Password Anda",
+	"email.password_reset.body": `Halo {name},
+
+Anda meminta reset password. Silakan klik tautan berikut untuk mereset password Anda:
+{link}
+
+Tautan berlaku selama {expires}.
+
+Jika Anda tidak memintanya, abaikan email ini.
+
+{signature}`,
+
+	"email.new_device.unknown_device":   "Perangkat tidak dikenal",
+	"email.new_device.unknown_ip":       "Tidak diketahui",
+	"email.new_device.unknown_location": "Tidak diketahui",
+	"email.new_device.subject":          "Login Baru ke Akun Anda",
+	"email.new_device.body": `Halo {name},
+
+Kami mendeteksi login ke akun Anda dari perangkat yang belum pernah digunakan sebelumnya:
+
+Perangkat: {device}
+Alamat IP: {ip}
+Lokasi: {location}
+Waktu: {time}
+
+Jika itu Anda, Anda dapat menandai perangkat ini sebagai tepercaya dari pengaturan akun.
+
+Jika bukan Anda, segera reset password Anda.
+
+{signature}`,
+
+	"email.password_changed.subject": "Password Anda Telah Diubah",
+	"email.password_changed.body": `Halo {name},
+
+Password akun Anda diubah pada {time}. Demi keamanan, Anda telah dikeluarkan dari semua perangkat.
+
+Jika bukan Anda yang mengubahnya, segera reset password Anda lagi dan hubungi tim support kami.
+
+{signature}`,
+
+	"email.password_expiry_warning.subject": "Password Anda Akan Segera Kedaluwarsa",
+	"email.password_expiry_warning.body": `Halo {name},
+
+Password akun staf Anda akan kedaluwarsa pada {time}. Setelah itu Anda tidak dapat login sampai memilih password baru.
+
+Ganti sekarang melalui "Lupa password" di halaman login. Password yang baru saja dipakai tidak dapat dipilih lagi.
+
+{signature}`,
+
+	"email.signin_otp.subject": "Kode Verifikasi Login Anda",
+	"email.signin_otp.body": `Halo {name},
+
+Kami mendeteksi aktivitas tidak biasa saat login ke akun Anda. Masukkan kode berikut untuk melanjutkan:
+
+{code}
+
+Kode berlaku selama {minutes} menit.
+
+Jika Anda tidak mencoba login, segera reset password Anda.
+
+{signature}`,
+
+	"email.account_merged.subject": "Akun Anda Telah Digabungkan",
+	"email.account_merged.body": `Halo {name},
+
+Atas permintaan Anda, tim support kami telah menggabungkan akun {merged_email} ke {surviving_email}.
+
+Mulai sekarang, silakan masuk dengan {surviving_email}. Detail profil, perangkat, dan riwayat pesanan Anda tersedia di sana, dan Anda telah dikeluarkan dari akun lama.
+
+Jika Anda tidak memintanya, segera hubungi tim support kami.
+
+{signature}`,
+
+	"email.customer_invite.subject": "Akun Anda Sudah Siap",
+	"email.customer_invite.body": `Halo {name},
+
+Sebuah akun telah dibuat untuk Anda dengan alamat email ini. Silakan klik tautan berikut untuk membuat password dan mulai berbelanja:
+{link}
+
+Tautan berlaku selama 7 hari. Setelah itu Anda tetap dapat menggunakan "Lupa password" di halaman login.
+
+Jika Anda tidak mengenali ini, abaikan email ini.
+
+{signature}`,
+
+	"email.photo_removed.default_reason": "foto tersebut tidak sesuai dengan pedoman komunitas kami",
+	"email.photo_removed.subject":        "Foto Profil Anda Telah Dihapus",
+	"email.photo_removed.body": `Halo {name},
+
+Kami menghapus foto profil Anda karena {reason}.
+
+Anda dapat mengunggah foto baru dari profil kapan saja. Foto akan ditinjau setelah diunggah.
+
+Jika menurut Anda ini keliru, silakan hubungi tim support kami.
+
+{signature}`,
+
+	"email.support_ticket_update.subject": "Tiket #{ticket_id}: {status_label}",
+	"email.support_ticket_update.note":    "\n\nPesan dari tim support kami:\n{note}",
+	"email.support_ticket_update.body": `Halo {name},
+
+Tiket support Anda #{ticket_id} "{subject}" sekarang berstatus {status_label}.{note_block}
+
+Anda dapat memantau tiket ini dari menu Bantuan di aplikasi.
+
+{signature}`,
+
+	"support.status.open":                "dibuka",
+	"support.status.in_progress":         "sedang diproses",
+	"support.status.waiting_on_customer": "menunggu balasan Anda",
+	"support.status.resolved":            "selesai",
+	"support.status.closed":              "ditutup",
+
+	// Validation
+	"validation.required": "{0} wajib diisi",
+	"validation.email":    "{0} harus berupa alamat email yang valid",
+	"validation.min":      "{0} minimal {1} karakter",
+	"validation.username": "{0} harus 3-30 karakter, diawali huruf dan hanya berisi huruf, angka, '.' atau '_'",
+}
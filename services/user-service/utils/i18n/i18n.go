@@ -0,0 +1,139 @@
+// Package i18n translates API messages, validation errors and emails.
+//
+// Catalogs are keyed by the English message itself, so a message without a translation
+// is still readable, and English needs catalog entries only for templates (emails).
+// Templates use named placeholders such as "{name}", filled from Params.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	English    = "en"
+	Indonesian = "id"
+	// Default is used when the client accepts none of the supported languages
+	Default = English
+)
+
+// Params fill the "{placeholder}" parts of a message
+type Params map[string]interface{}
+
+var catalogs = map[string]map[string]string{
+	English:    catalogEN,
+	Indonesian: catalogID,
+}
+
+// Supported reports whether lang has a catalog
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// T translates key into lang, falling back to English and then to the key itself
+func T(lang, key string, params ...Params) string {
+	message, ok := catalogs[lang][key]
+	if !ok {
+		if message, ok = catalogs[Default][key]; !ok {
+			message = key
+		}
+	}
+
+	for _, p := range params {
+		for name, value := range p {
+			message = strings.ReplaceAll(message, "{"+name+"}", fmt.Sprint(value))
+		}
+	}
+	return message
+}
+
+// Duration spells out d in days, hours or minutes, using the largest unit that divides it exactly.
+// Days start at two, so a day reads as "24 hours".
+func Duration(lang string, d time.Duration) string {
+	unit, n := "minute", int64(d/time.Minute)
+	switch {
+	case d >= 48*time.Hour && d%(24*time.Hour) == 0:
+		unit, n = "day", int64(d/(24*time.Hour))
+	case d >= time.Hour && d%time.Hour == 0:
+		unit, n = "hour", int64(d/time.Hour)
+	}
+
+	key := "duration." + unit
+	if n != 1 {
+		key += "s"
+	}
+	return T(lang, key, Params{"n": n})
+}
+
+// Negotiate picks the best supported language from an Accept-Language header
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		lang    string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, quality := strings.TrimSpace(part), 1.0
+		if semicolon := strings.Index(tag, ";"); semicolon >= 0 {
+			param := strings.TrimSpace(tag[semicolon+1:])
+			tag = strings.TrimSpace(tag[:semicolon])
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				if err != nil {
+					continue
+				}
+				quality = q
+			}
+		}
+		if tag == "" || quality <= 0 {
+			continue
+		}
+
+		if lang := baseLanguage(tag); Supported(lang) {
+			candidates = append(candidates, candidate{lang: lang, quality: quality})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return Default
+	}
+
+	// Stable, so equal qualities keep the client's order
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+	return candidates[0].lang
+}
+
+// baseLanguage maps "id-ID" to "id"; "in" is the legacy code for Indonesian
+func baseLanguage(tag string) string {
+	lang := strings.ToLower(tag)
+	if dash := strings.IndexAny(lang, "-_"); dash >= 0 {
+		lang = lang[:dash]
+	}
+	if lang == "in" {
+		return Indonesian
+	}
+	return lang
+}
+
+type languageContextKey struct{}
+
+// WithLanguage stores the negotiated language so services and publishers can localize too
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, languageContextKey{}, lang)
+}
+
+// FromContext returns the request language, or Default outside a request
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(languageContextKey{}).(string); ok && Supported(lang) {
+		return lang
+	}
+	return Default
+}
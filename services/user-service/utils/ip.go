@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+var ErrInvalidCIDR = errors.New("invalid IP address or CIDR range")
+
+// NormalizeCIDR accepts an IP or CIDR and returns its canonical network form,
+// so 10.0.0.7/8 and 10.0.0.0/8 are stored as the same rule
+func NormalizeCIDR(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if !strings.Contains(value, "/") {
+		ip := net.ParseIP(value)
+		if ip == nil {
+			return "", ErrInvalidCIDR
+		}
+		if ip.To4() != nil {
+			return ip.String() + "/32", nil
+		}
+		return ip.String() + "/128", nil
+	}
+
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return "", ErrInvalidCIDR
+	}
+	return network.String(), nil
+}
+
+// IPInCIDRs reports whether ip falls in any of the ranges; invalid ranges never match
+func IPInCIDRs(ip string, cidrs []string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+
+	for _, cidr := range cidrs {
+		normalized, err := NormalizeCIDR(cidr)
+		if err != nil {
+			continue
+		}
+		_, network, _ := net.ParseCIDR(normalized)
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -16,11 +16,12 @@ type JWTClaims struct {
 }
 
 func GenerateJWT(cfg *config.Config, userID int64, email, roleName string) (string, error) {
-	return GenerateJWTWithSession(cfg, userID, email, roleName, "")
+	return GenerateJWTWithSession(cfg, userID, email, roleName, "", false)
 }
 
-func GenerateJWTWithSession(cfg *config.Config, userID int64, email, roleName, sessionID string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+// GenerateJWTWithSession signs a token that expires with its session, see config.Sessions.Lifetime
+func GenerateJWTWithSession(cfg *config.Config, userID int64, email, roleName, sessionID string, rememberMe bool) (string, error) {
+	expirationTime := time.Now().Add(cfg.Sessions.Lifetime(rememberMe))
 
 	claims := &JWTClaims{
 		UserID:    userID,
@@ -79,8 +80,8 @@ func (j *JWTUtil) GenerateJWT(userID int64, email, roleName string) (string, err
 	return GenerateJWT(j.config, userID, email, roleName)
 }
 
-func (j *JWTUtil) GenerateJWTWithSession(userID int64, email, roleName, sessionID string) (string, error) {
-	return GenerateJWTWithSession(j.config, userID, email, roleName, sessionID)
+func (j *JWTUtil) GenerateJWTWithSession(userID int64, email, roleName, sessionID string, rememberMe bool) (string, error) {
+	return GenerateJWTWithSession(j.config, userID, email, roleName, sessionID, rememberMe)
 }
 
 func (j *JWTUtil) ValidateJWT(tokenString string) (*JWTClaims, error) {
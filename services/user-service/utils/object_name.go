@@ -0,0 +1,13 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// ContentObjectName names a storage object prefix+<sha256 of data>+ext. Identical files get the
+// same name, so storage can keep one copy and a retried upload lands on the same object.
+func ContentObjectName(prefix string, data []byte, ext string) string {
+	sum := sha256.Sum256(data)
+	return prefix + hex.EncodeToString(sum[:]) + ext
+}
@@ -0,0 +1,213 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// ProfilePhotoSize is the edge length in pixels of processed profile photos
+const ProfilePhotoSize = 512
+
+// maxPhotoPixels stops a small file that decodes into a huge bitmap from exhausting memory
+const maxPhotoPixels = 40_000_000
+
+var (
+	ErrPhotoUnreadable = errors.New("image cannot be read")
+	ErrPhotoTooLarge   = errors.New("image dimensions too large")
+	ErrPhotoRotation   = errors.New("rotation must be 0, 90, 180 or 270")
+	ErrPhotoCrop       = errors.New("crop is outside the image")
+)
+
+// EXIF orientations, see the TIFF 6.0 Orientation tag
+const (
+	orientationNormal     = 1
+	orientationFlipH      = 2
+	orientation180        = 3
+	orientationFlipV      = 4
+	orientationTranspose  = 5
+	orientation90         = 6
+	orientationTransverse = 7
+	orientation270        = 8
+)
+
+// ProcessProfilePhoto turns an uploaded photo into a square JPEG of ProfilePhotoSize pixels.
+// The EXIF orientation is applied first, then rotation (clockwise degrees), then crop, so the
+// crop is in the coordinates of the upright image the user saw in the editor. A nil crop takes
+// the centred square; a crop that is not square uses its centred square. Transparency becomes
+// white and the output carries no metadata.
+func ProcessProfilePhoto(data []byte, rotation int, crop *image.Rectangle) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrPhotoUnreadable
+	}
+	if config.Width*config.Height > maxPhotoPixels {
+		return nil, ErrPhotoTooLarge
+	}
+
+	var turn int
+	switch rotation {
+	case 0:
+	case 90:
+		turn = orientation90
+	case 180:
+		turn = orientation180
+	case 270:
+		turn = orientation270
+	default:
+		return nil, ErrPhotoRotation
+	}
+
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrPhotoUnreadable
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, decoded.Bounds().Dx(), decoded.Bounds().Dy()))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(img, img.Bounds(), decoded, decoded.Bounds().Min, draw.Over)
+
+	img = orient(img, exifOrientation(data))
+	if turn != 0 {
+		img = orient(img, turn)
+	}
+
+	area := img.Bounds()
+	if crop != nil {
+		if crop.Empty() || !crop.In(area) {
+			return nil, ErrPhotoCrop
+		}
+		area = *crop
+	}
+	area = centredSquare(area)
+
+	out := image.NewRGBA(image.Rect(0, 0, ProfilePhotoSize, ProfilePhotoSize))
+	draw.CatmullRom.Scale(out, out.Bounds(), img, area, draw.Src, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, out, &jpeg.Options{Quality: 90}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func centredSquare(r image.Rectangle) image.Rectangle {
+	side := r.Dx()
+	if r.Dy() < side {
+		side = r.Dy()
+	}
+	origin := image.Pt(r.Min.X+(r.Dx()-side)/2, r.Min.Y+(r.Dy()-side)/2)
+	return image.Rectangle{Min: origin, Max: origin.Add(image.Pt(side, side))}
+}
+
+// orient returns src transformed so that an image stored with the given EXIF orientation
+// displays upright. src must start at the origin.
+func orient(src *image.RGBA, orientation int) *image.RGBA {
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+
+	// Each case maps a destination pixel to the source pixel it shows
+	var (
+		dw, dh int
+		from   func(x, y int) (int, int)
+	)
+	switch orientation {
+	case orientationFlipH:
+		dw, dh, from = w, h, func(x, y int) (int, int) { return w - 1 - x, y }
+	case orientation180:
+		dw, dh, from = w, h, func(x, y int) (int, int) { return w - 1 - x, h - 1 - y }
+	case orientationFlipV:
+		dw, dh, from = w, h, func(x, y int) (int, int) { return x, h - 1 - y }
+	case orientationTranspose:
+		dw, dh, from = h, w, func(x, y int) (int, int) { return y, x }
+	case orientation90:
+		dw, dh, from = h, w, func(x, y int) (int, int) { return y, h - 1 - x }
+	case orientationTransverse:
+		dw, dh, from = h, w, func(x, y int) (int, int) { return w - 1 - y, h - 1 - x }
+	case orientation270:
+		dw, dh, from = h, w, func(x, y int) (int, int) { return w - 1 - y, x }
+	default:
+		return src
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			sx, sy := from(x, y)
+			copy(dst.Pix[dst.PixOffset(x, y):dst.PixOffset(x, y)+4], src.Pix[src.PixOffset(sx, sy):src.PixOffset(sx, sy)+4])
+		}
+	}
+	return dst
+}
+
+// exifOrientation reads the Orientation tag from a JPEG's APP1 segment. Anything that is not
+// a JPEG, or has no readable tag, is treated as already upright.
+func exifOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return orientationNormal
+	}
+
+	for pos := 2; pos+4 <= len(data); {
+		if data[pos] != 0xFF {
+			return orientationNormal
+		}
+		marker := data[pos+1]
+		// Start of scan: the metadata segments are all before it
+		if marker == 0xDA {
+			return orientationNormal
+		}
+		length := int(binary.BigEndian.Uint16(data[pos+2:]))
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return orientationNormal
+		}
+		segment := data[pos+4 : end]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		pos = end
+	}
+	return orientationNormal
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return orientationNormal
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return orientationNormal
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return orientationNormal
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if value := int(order.Uint16(tiff[entry+8:])); value >= orientationNormal && value <= orientation270 {
+				return value
+			}
+			break
+		}
+	}
+	return orientationNormal
+}
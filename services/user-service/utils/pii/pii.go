@@ -0,0 +1,143 @@
+// Package pii encrypts personal data columns. It holds the AES-GCM cipher and the GORM "pii"
+// serializer, which the model package imports so the serializer is registered wherever models
+// are parsed, including binaries that never touch the repositories.
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// prefix marks a value written by Cipher; anything without it is plaintext from before
+// encryption was turned on
+const prefix = "enc:v1:"
+
+// KeySize is the AES-256 key length
+const KeySize = 32
+
+var ErrUnknownKey = errors.New("pii value encrypted with an unknown key")
+
+// Cipher encrypts personal data columns with AES-256-GCM. A stored value reads
+// "enc:v1:<key id>:<base64 nonce+ciphertext>", so rows written before a key rotation still name
+// the key that opens them. A nil *Cipher, or one without a current key, writes plaintext.
+type Cipher struct {
+	keyID string
+	aeads map[string]cipher.AEAD
+}
+
+// NewCipher takes every key that may still be in the database; keyID picks the one new values
+// are written with and may be empty to stop encrypting while old values still decrypt
+func NewCipher(keyID string, keys map[string][]byte) (*Cipher, error) {
+	c := &Cipher{keyID: keyID, aeads: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if !ValidKeyID(id) {
+			return nil, fmt.Errorf("pii key id %q may only contain letters, digits, '-', '_' and '.'", id)
+		}
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("pii key %q is %d bytes, want %d", id, len(key), KeySize)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		c.aeads[id] = aead
+	}
+	if keyID != "" && c.aeads[keyID] == nil {
+		return nil, fmt.Errorf("pii key %q is not among the configured keys", keyID)
+	}
+	return c, nil
+}
+
+// ValidKeyID keeps ids to characters that cannot be confused with the value's separators
+func ValidKeyID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Encrypt seals plaintext with the current key. Empty values stay empty so "no phone" remains
+// distinguishable in queries.
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	if c == nil || c.keyID == "" || plaintext == "" {
+		return plaintext, nil
+	}
+
+	aead := c.aeads[c.keyID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return prefix + c.keyID + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value written by Encrypt and returns plaintext values unchanged
+func (c *Cipher) Decrypt(stored string) (string, error) {
+	keyID, payload, ok := split(stored)
+	if !ok {
+		return stored, nil
+	}
+
+	var aead cipher.AEAD
+	if c != nil {
+		aead = c.aeads[keyID]
+	}
+	if aead == nil {
+		return "", fmt.Errorf("%w %q", ErrUnknownKey, keyID)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("malformed pii value for key %q", keyID)
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("pii value for key %q failed authentication", keyID)
+	}
+	return string(plaintext), nil
+}
+
+// Current reports whether stored is already in the form Encrypt would write: sealed with the
+// current key, or plaintext while there is none. Empty values are always current.
+func (c *Cipher) Current(stored string) bool {
+	if stored == "" {
+		return true
+	}
+	keyID, _, ok := split(stored)
+	if c == nil || c.keyID == "" {
+		return !ok
+	}
+	return ok && keyID == c.keyID
+}
+
+// IsEncrypted reports whether stored was written by a Cipher
+func IsEncrypted(stored string) bool {
+	_, _, ok := split(stored)
+	return ok
+}
+
+func split(stored string) (keyID, payload string, ok bool) {
+	rest, found := strings.CutPrefix(stored, prefix)
+	if !found {
+		return "", "", false
+	}
+	return strings.Cut(rest, ":")
+}
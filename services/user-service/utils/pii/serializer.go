@@ -0,0 +1,69 @@
+package pii
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"gorm.io/gorm/schema"
+)
+
+// current seals fields tagged `gorm:"serializer:pii"`. While it is unset values are written
+// as plaintext and ciphertext cannot be read.
+var current atomic.Pointer[Cipher]
+
+func init() {
+	schema.RegisterSerializer("pii", Serializer{})
+}
+
+// Use makes c the cipher of the "pii" serializer; nil turns encryption off. GORM keeps
+// serializers in a process-wide registry, so the cipher is process-wide too.
+func Use(c *Cipher) {
+	current.Store(c)
+}
+
+// Current is the cipher set by Use, or nil
+func Current() *Cipher {
+	return current.Load()
+}
+
+// Serializer encrypts a string field on every struct write and decrypts it on every scan,
+// including scans into row structs that carry the tag
+type Serializer struct{}
+
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var stored string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		stored = v
+	case []byte:
+		stored = string(v)
+	default:
+		return fmt.Errorf("pii column %s: unexpected %T", field.DBName, dbValue)
+	}
+
+	plaintext, err := Current().Decrypt(stored)
+	if err != nil {
+		return fmt.Errorf("pii column %s: %w", field.DBName, err)
+	}
+	return field.Set(ctx, dst, plaintext)
+}
+
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("pii column %s: unexpected %T", field.DBName, fieldValue)
+	}
+	return Current().Encrypt(plaintext)
+}
+
+// Value seals a string written through an Updates map. GORM passes map values to the driver
+// as they are, without the field's serializer, so plain strings would be stored in the clear.
+type Value string
+
+func (v Value) Value() (driver.Value, error) {
+	return Current().Encrypt(string(v))
+}
@@ -0,0 +1,65 @@
+// Package querytrace carries the route and request ID of an HTTP request down to the GORM
+// plugin, which tags each SQL statement with them and counts the statements per request.
+package querytrace
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Trace is shared by every query of one request; queries may run concurrently
+type Trace struct {
+	Method    string
+	Route     string
+	RequestID string
+
+	queries  atomic.Int64
+	duration atomic.Int64
+}
+
+func New(method, route, requestID string) *Trace {
+	return &Trace{Method: method, Route: route, RequestID: requestID}
+}
+
+// Record counts one statement that took d
+func (t *Trace) Record(d time.Duration) {
+	t.queries.Add(1)
+	t.duration.Add(int64(d))
+}
+
+func (t *Trace) Queries() int64 {
+	return t.queries.Load()
+}
+
+// Duration is the time spent in the database, summed over the request's statements
+func (t *Trace) Duration() time.Duration {
+	return time.Duration(t.duration.Load())
+}
+
+// Comment is the trace as a SQL comment in the sqlcommenter format, e.g.
+// /*method='GET',request_id='4f1c',route='%2Fapi%2Fv1%2Fusers%2F%3Aid'*/. Values are URL-encoded,
+// so a request ID sent by the client cannot close the comment. Without withRequestID the
+// comment is the same for every request to the route.
+func (t *Trace) Comment(withRequestID bool) string {
+	pairs := []string{"method='" + url.QueryEscape(t.Method) + "'"}
+	if withRequestID && t.RequestID != "" {
+		pairs = append(pairs, "request_id='"+url.QueryEscape(t.RequestID)+"'")
+	}
+	pairs = append(pairs, "route='"+url.QueryEscape(t.Route)+"'")
+	return "/*" + strings.Join(pairs, ",") + "*/"
+}
+
+type traceContextKey struct{}
+
+func WithTrace(ctx context.Context, trace *Trace) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, trace)
+}
+
+// FromContext returns nil outside a request, e.g. for background jobs
+func FromContext(ctx context.Context) *Trace {
+	trace, _ := ctx.Value(traceContextKey{}).(*Trace)
+	return trace
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+const RedactedValue = "[REDACTED]"
+
+// sensitiveKeyParts marks any field whose lowercased name contains one of these as secret,
+// so password_confirmation, new_password, refresh_token and api_key are all caught
+var sensitiveKeyParts = []string{"password", "token", "secret", "authorization", "api_key", "apikey", "cookie", "otp"}
+
+// IsSensitiveKey reports whether a field, header or query parameter name holds a secret
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactJSON replaces the values of sensitive keys at any depth. ok is false when body is
+// not valid JSON, in which case callers should not log it at all.
+func RedactJSON(body []byte) (redacted []byte, ok bool) {
+	var data interface{}
+	if err := json.Unmarshal(body, &data); err != nil {
+		return nil, false
+	}
+
+	redacted, err := json.Marshal(redactValue(data))
+	if err != nil {
+		return nil, false
+	}
+	return redacted, true
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, inner := range v {
+			if IsSensitiveKey(key) {
+				v[key] = RedactedValue
+				continue
+			}
+			v[key] = redactValue(inner)
+		}
+		return v
+	case []interface{}:
+		for i, inner := range v {
+			v[i] = redactValue(inner)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// RedactValues redacts sensitive keys in form bodies and query strings
+func RedactValues(values url.Values) url.Values {
+	for key := range values {
+		if IsSensitiveKey(key) {
+			values[key] = []string{RedactedValue}
+		}
+	}
+	return values
+}
+
+// RedactURI redacts sensitive query parameters, e.g. /auth/verify?token=...
+func RedactURI(uri string) string {
+	path, rawQuery, found := strings.Cut(uri, "?")
+	if !found {
+		return uri
+	}
+
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return path + "?" + RedactedValue
+	}
+
+	return path + "?" + RedactValues(values).Encode()
+}
+
+// SecretFingerprint stands in for a token or other secret in logs. It is the first 12 hex
+// characters of the secret's SHA-256, enough to correlate log lines for the same token (and
+// to find a verification token by its stored token_hash prefix) but useless for replaying it.
+func SecretFingerprint(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:6])
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	// The runtime image has no zoneinfo, so the IANA database is compiled in
+	_ "time/tzdata"
+)
+
+// DefaultTimezone is used for users who never picked one
+const DefaultTimezone = "UTC"
+
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+// NormalizeTimezone checks an IANA zone name such as "Asia/Jakarta". "Local" is rejected
+// because it would mean the server's zone, not the user's.
+func NormalizeTimezone(timezone string) (string, error) {
+	timezone = strings.TrimSpace(timezone)
+	if timezone == "" || strings.EqualFold(timezone, "Local") {
+		return "", ErrInvalidTimezone
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return "", ErrInvalidTimezone
+	}
+	return location.String(), nil
+}
+
+// UserLocation loads a stored timezone, falling back to UTC for empty or unknown names
+func UserLocation(timezone string) *time.Location {
+	if timezone == "" {
+		return time.UTC
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
@@ -0,0 +1,68 @@
+package utils
+
+import "strings"
+
+// userAgentBrowsers is checked in order: Edge and Opera also claim to be Chrome, and Chrome
+// claims to be Safari, so the more specific tokens come first
+var userAgentBrowsers = []struct {
+	token string
+	name  string
+}{
+	{"edg/", "Edge"},
+	{"opr/", "Opera"},
+	{"samsungbrowser/", "Samsung Internet"},
+	{"firefox/", "Firefox"},
+	{"fxios/", "Firefox"},
+	{"crios/", "Chrome"},
+	{"chrome/", "Chrome"},
+	{"safari/", "Safari"},
+	{"okhttp/", "Android app"},
+	{"cfnetwork/", "iOS app"},
+}
+
+var userAgentSystems = []struct {
+	token string
+	name  string
+}{
+	{"iphone", "iPhone"},
+	{"ipad", "iPad"},
+	{"android", "Android"},
+	{"windows", "Windows"},
+	{"mac os x", "macOS"},
+	{"macintosh", "macOS"},
+	{"cros", "ChromeOS"},
+	{"linux", "Linux"},
+	{"darwin", "iOS"},
+}
+
+// DeviceDisplayName turns a User-Agent into a short label such as "Chrome on Windows" for
+// session lists and sign-in alerts. It returns "" when neither part is recognised, so callers
+// can fall back to the raw header.
+func DeviceDisplayName(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+
+	browser := ""
+	for _, b := range userAgentBrowsers {
+		if strings.Contains(ua, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	system := ""
+	for _, s := range userAgentSystems {
+		if strings.Contains(ua, s.token) {
+			system = s.name
+			break
+		}
+	}
+
+	switch {
+	case browser != "" && system != "":
+		return browser + " on " + system
+	case browser != "":
+		return browser
+	default:
+		return system
+	}
+}
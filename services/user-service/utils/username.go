@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrInvalidUsername  = errors.New("username must be 3-30 characters, start with a letter and contain only letters, numbers, '.' or '_'")
+	ErrReservedUsername = errors.New("username is reserved")
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-z][a-z0-9._]{2,29}$`)
+
+// ReservedUsernames cannot be claimed: they collide with routes, roles or could impersonate staff
+var ReservedUsernames = map[string]struct{}{
+	"admin": {}, "administrator": {}, "api": {}, "billing": {}, "customer": {},
+	"help": {}, "info": {}, "jualansayur": {}, "login": {}, "logout": {},
+	"me": {}, "moderator": {}, "null": {}, "official": {}, "profile": {},
+	"root": {}, "sayur": {}, "security": {}, "settings": {}, "signin": {},
+	"signup": {}, "staff": {}, "superadmin": {}, "support": {}, "system": {},
+	"undefined": {}, "user": {}, "vendor": {}, "www": {},
+}
+
+// NormalizeUsername lowercases and validates a username. Usernames are stored lowercased,
+// so "Budi_Santoso" and "budi_santoso" are the same handle.
+func NormalizeUsername(username string) (string, error) {
+	username = strings.ToLower(strings.TrimSpace(username))
+	if !usernamePattern.MatchString(username) || strings.Contains(username, "..") || strings.HasSuffix(username, ".") {
+		return "", ErrInvalidUsername
+	}
+	return username, nil
+}
+
+// IsReservedUsername expects a normalized username
+func IsReservedUsername(username string) bool {
+	_, reserved := ReservedUsernames[username]
+	return reserved
+}
@@ -1,62 +1,96 @@
 package validator
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"user-service/utils"
+	"user-service/utils/i18n"
 
 	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/id"
 	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	idTranslations "github.com/go-playground/validator/v10/translations/id"
 	"github.com/rs/zerolog/log"
 )
 
 type Validator struct {
 	Validator  *validator.Validate
 	Translator ut.Translator
+	// translators holds one translator per i18n language; Translator is the English one
+	translators map[string]ut.Translator
 }
 
+// customTranslations override the library defaults with the messages in the i18n catalogs
+var customTranslations = []string{"required", "email", "min", "username"}
+
 func NewValidator() *Validator {
-	en := en.New()
-	uni := ut.New(en, en)
-	trans, found := uni.GetTranslator("en")
-	if !found {
-		log.Fatal().Msg("[NewValidator] Translator not found")
-	}
+	enLocale := en.New()
+	uni := ut.New(enLocale, enLocale, id.New())
 
 	validate := validator.New()
 
-	if err := enTranslations.RegisterDefaultTranslations(validate, trans); err != nil {
-		log.Fatal().Err(err).Msg("[NewValidator] Failed to register translations")
+	// username follows utils.NormalizeUsername; reserved names are checked by the service
+	validate.RegisterValidation("username", func(fl validator.FieldLevel) bool {
+		_, err := utils.NormalizeUsername(fl.Field().String())
+		return err == nil
+	})
+
+	translators := make(map[string]ut.Translator)
+	for lang, registerDefaults := range map[string]func(*validator.Validate, ut.Translator) error{
+		i18n.English:    enTranslations.RegisterDefaultTranslations,
+		i18n.Indonesian: idTranslations.RegisterDefaultTranslations,
+	} {
+		trans, found := uni.GetTranslator(lang)
+		if !found {
+			log.Fatal().Str("lang", lang).Msg("[NewValidator] Translator not found")
+		}
+
+		if err := registerDefaults(validate, trans); err != nil {
+			log.Fatal().Err(err).Str("lang", lang).Msg("[NewValidator] Failed to register translations")
+		}
+
+		for _, tag := range customTranslations {
+			registerTranslation(validate, trans, lang, tag)
+		}
+		translators[lang] = trans
 	}
 
-	validate.RegisterTranslation("required", trans, func(ut ut.Translator) error {
-		return ut.Add("required", "{0} is required", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("required", fe.Field())
-		return t
-	})
+	return &Validator{
+		Validator:   validate,
+		Translator:  translators[i18n.English],
+		translators: translators,
+	}
+}
 
-	validate.RegisterTranslation("email", trans, func(ut ut.Translator) error {
-		return ut.Add("email", "{0} must be a valid email address", true)
+func registerTranslation(validate *validator.Validate, trans ut.Translator, lang, tag string) {
+	validate.RegisterTranslation(tag, trans, func(ut ut.Translator) error {
+		return ut.Add(tag, i18n.T(lang, "validation."+tag), true)
 	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("email", fe.Field())
+		t, _ := ut.T(tag, fe.Field(), fe.Param())
 		return t
 	})
+}
 
-	validate.RegisterTranslation("min", trans, func(ut ut.Translator) error {
-		return ut.Add("min", "{0} must be at least {1} characters", true)
-	}, func(ut ut.Translator, fe validator.FieldError) string {
-		t, _ := ut.T("min", fe.Field(), fe.Param())
-		return t
-	})
+func (v *Validator) Validate(i interface{}) error {
+	return v.validate(v.Translator, i)
+}
 
-	return &Validator{
-		Validator:  validate,
-		Translator: trans,
+// ValidateContext is Validate with messages in the request language
+func (v *Validator) ValidateContext(ctx context.Context, i interface{}) error {
+	return v.validate(v.translator(ctx), i)
+}
+
+func (v *Validator) translator(ctx context.Context) ut.Translator {
+	if trans, ok := v.translators[i18n.FromContext(ctx)]; ok {
+		return trans
 	}
+	return v.Translator
 }
 
-func (v *Validator) Validate(i interface{}) error {
+func (v *Validator) validate(trans ut.Translator, i interface{}) error {
 	err := v.Validator.Struct(i)
 
 	if err != nil {
@@ -64,11 +98,11 @@ func (v *Validator) Validate(i interface{}) error {
 		if errors.As(err, &validationErrors) {
 			var errorMessages []string
 			for _, e := range validationErrors {
-				translatedMsg := e.Translate(v.Translator)
+				translatedMsg := e.Translate(trans)
 				log.Info().
 					Str("field", e.Field()).
 					Str("tag", e.Tag()).
-					Str("value", e.Value().(string)).
+					Str("value", fmt.Sprintf("%v", e.Value())).
 					Str("message", translatedMsg).
 					Msg("[Validate] Validation error")
 
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WebhookSignatureHeader carries "t=<unix seconds>,v1=<hex HMAC-SHA256>"
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+// SignWebhookPayload signs "<timestamp>.<body>" with the endpoint secret. Including the
+// timestamp lets receivers reject replayed requests.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// WebhookSignatureTolerance is how old a signed timestamp may be before it counts as a replay
+const WebhookSignatureTolerance = 5 * time.Minute
+
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// VerifyWebhookSignature checks a header made by SignWebhookPayload: the HMAC must match and
+// the timestamp must be within WebhookSignatureTolerance of now, in either direction
+func VerifyWebhookSignature(secret, header string, body []byte, now time.Time) error {
+	var (
+		timestamp int64
+		signature string
+	)
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return ErrInvalidWebhookSignature
+			}
+			timestamp = parsed
+		case "v1":
+			signature = value
+		}
+	}
+	if timestamp == 0 || signature == "" {
+		return ErrInvalidWebhookSignature
+	}
+
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > WebhookSignatureTolerance || age < -WebhookSignatureTolerance {
+		return ErrInvalidWebhookSignature
+	}
+
+	expected := SignWebhookPayload(secret, timestamp, body)
+	if !hmac.Equal([]byte(expected), []byte(fmt.Sprintf("t=%d,v1=%s", timestamp, signature))) {
+		return ErrInvalidWebhookSignature
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+)
+
+// Status is one service's line in the version report
+type Status struct {
+	Service  string
+	Database string
+	// Before and Version are 0 when no migration has been applied
+	Before  uint
+	Version uint
+	Latest  uint
+	Pending int
+	Dirty   bool
+	// Connected is false when the database could not be reached, so Version means nothing
+	Connected bool
+	Err       error
+}
+
+// Runner applies each service's migrations to that service's own database
+type Runner struct {
+	Services []Service
+}
+
+// Up migrates every service in order and stops at the first failure, since later services may
+// depend on tables the failed one was about to create
+func (r *Runner) Up() []Status {
+	return r.each(func(m *migrate.Migrate) error {
+		if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			return err
+		}
+		return nil
+	}, true)
+}
+
+// Down rolls back steps migrations of a single service
+func (r *Runner) Down(steps int) []Status {
+	return r.each(func(m *migrate.Migrate) error {
+		if err := m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			return err
+		}
+		return nil
+	}, true)
+}
+
+// Force sets the recorded version without running anything, to recover from a dirty state
+func (r *Runner) Force(version int) []Status {
+	return r.each(func(m *migrate.Migrate) error {
+		return m.Force(version)
+	}, true)
+}
+
+// Status reports every service, carrying on past services it cannot reach
+func (r *Runner) Status() []Status {
+	return r.each(nil, false)
+}
+
+func (r *Runner) each(apply func(m *migrate.Migrate) error, stopOnError bool) []Status {
+	statuses := make([]Status, 0, len(r.Services))
+	for _, service := range r.Services {
+		status := run(service, apply)
+		statuses = append(statuses, status)
+		if status.Err != nil && stopOnError {
+			break
+		}
+	}
+	return statuses
+}
+
+func run(service Service, apply func(m *migrate.Migrate) error) Status {
+	status := Status{Service: service.Name}
+
+	latest, err := service.LatestVersion()
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	status.Latest = latest
+
+	databaseURL, err := service.DatabaseURL()
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	status.Database = redact(databaseURL)
+
+	m, err := migrate.New("file://"+service.MigrationsDir, databaseURL)
+	if err != nil {
+		status.Err = fmt.Errorf("failed to initialize migrate: %w", err)
+		return status
+	}
+	defer m.Close()
+
+	status.Before, status.Dirty, err = version(m)
+	if err != nil {
+		status.Err = err
+		return status
+	}
+	status.Connected = true
+
+	if apply != nil {
+		if err := apply(m); err != nil {
+			status.Err = err
+		}
+	}
+
+	status.Version, status.Dirty, err = version(m)
+	if err != nil && status.Err == nil {
+		status.Err = err
+	}
+	status.Pending, _ = service.Pending(status.Version)
+	return status
+}
+
+func version(m *migrate.Migrate) (uint, bool, error) {
+	v, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+	return v, dirty, err
+}
+
+// redact hides the password, since the report ends up in CI logs
+func redact(databaseURL string) string {
+	u, err := url.Parse(databaseURL)
+	if err != nil {
+		return "(invalid database URL)"
+	}
+	return u.Redacted()
+}
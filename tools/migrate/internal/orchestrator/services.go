@@ -0,0 +1,154 @@
+package orchestrator
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+)
+
+// serviceOrder is the order migrations run in. A service comes after the ones whose data it
+// refers to: orders point at users and products, payments at orders. Services not listed
+// run last, alphabetically.
+var serviceOrder = []string{
+	"user-service",
+	"product-service",
+	"order-service",
+	"payment-service",
+	"notification-service",
+}
+
+// migrationsPath is where every service keeps its golang-migrate files
+const migrationsPath = "database/migrations"
+
+var ErrNoDatabase = errors.New("no database configured")
+
+type Service struct {
+	Name string
+	Dir  string
+	// MigrationsDir holds NNNNNN_name.up.sql / .down.sql files
+	MigrationsDir string
+}
+
+// Discover finds the services under servicesDir that have migrations, in the order they run
+func Discover(servicesDir string) ([]Service, error) {
+	entries, err := os.ReadDir(servicesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var services []Service
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(servicesDir, entry.Name())
+		migrationsDir := filepath.Join(dir, migrationsPath)
+		if info, err := os.Stat(migrationsDir); err != nil || !info.IsDir() {
+			continue
+		}
+		services = append(services, Service{Name: entry.Name(), Dir: dir, MigrationsDir: migrationsDir})
+	}
+
+	sort.SliceStable(services, func(i, j int) bool {
+		ri, rj := rank(services[i].Name), rank(services[j].Name)
+		if ri != rj {
+			return ri < rj
+		}
+		return services[i].Name < services[j].Name
+	})
+	return services, nil
+}
+
+func rank(name string) int {
+	for i, service := range serviceOrder {
+		if service == name {
+			return i
+		}
+	}
+	return len(serviceOrder)
+}
+
+// EnvPrefix is the environment prefix for a service's database URL, e.g. USER_SERVICE
+func EnvPrefix(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// DatabaseURL is <SERVICE>_DATABASE_URL when set, e.g. USER_SERVICE_DATABASE_URL, so CI and
+// deployments can point each service at its own database. Otherwise it is built from the
+// DATABASE_* settings in the service's own .env, the same ones the service connects with.
+func (s Service) DatabaseURL() (string, error) {
+	if databaseURL := os.Getenv(EnvPrefix(s.Name) + "_DATABASE_URL"); databaseURL != "" {
+		return databaseURL, nil
+	}
+
+	env, err := godotenv.Read(filepath.Join(s.Dir, ".env"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNoDatabase
+		}
+		return "", err
+	}
+	if env["DATABASE_HOST"] == "" || env["DATABASE_NAME"] == "" {
+		return "", ErrNoDatabase
+	}
+
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(env["DATABASE_USER"], env["DATABASE_PASSWORD"]),
+		Host:     env["DATABASE_HOST"] + ":" + env["DATABASE_PORT"],
+		Path:     "/" + env["DATABASE_NAME"],
+		RawQuery: "sslmode=disable",
+	}
+	return u.String(), nil
+}
+
+// LatestVersion is the highest migration version in the service's directory, 0 when empty
+func (s Service) LatestVersion() (uint, error) {
+	versions, err := s.versions()
+	if err != nil || len(versions) == 0 {
+		return 0, err
+	}
+	return versions[len(versions)-1], nil
+}
+
+// Pending counts the migrations above current
+func (s Service) Pending(current uint) (int, error) {
+	versions, err := s.versions()
+	if err != nil {
+		return 0, err
+	}
+
+	pending := 0
+	for _, version := range versions {
+		if version > current {
+			pending++
+		}
+	}
+	return pending, nil
+}
+
+func (s Service) versions() ([]uint, error) {
+	files, err := filepath.Glob(filepath.Join(s.MigrationsDir, "*.up.sql"))
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]uint, 0, len(files))
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(filepath.Base(file), "_")
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: migration file name must start with its version", filepath.Base(file))
+		}
+		versions = append(versions, uint(version))
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"migrate-tool/internal/orchestrator"
+)
+
+// migrate runs the database migrations of every service, each against its own database:
+//
+//	go run . -cmd status
+//	go run . -cmd up
+//	go run . -cmd down -service user-service -steps 1
+func main() {
+	servicesDir := flag.String("services", "../../services", "directory holding the services")
+	cmd := flag.String("cmd", "status", "migration command: up/down/force/status")
+	only := flag.String("service", "", "limit to one service; required for down and force")
+	steps := flag.Int("steps", 1, "migrations to roll back for down")
+	version := flag.Int("version", -1, "migration version for force")
+	flag.Parse()
+
+	services, err := orchestrator.Discover(*servicesDir)
+	if err != nil {
+		log.Fatalf("failed to find services: %v", err)
+	}
+	if *only != "" {
+		services = pick(services, *only)
+		if len(services) == 0 {
+			log.Fatalf("service %s has no %s directory", *only, "database/migrations")
+		}
+	}
+	if len(services) == 0 {
+		log.Fatalf("no service under %s has migrations", *servicesDir)
+	}
+
+	runner := &orchestrator.Runner{Services: services}
+
+	var statuses []orchestrator.Status
+	switch *cmd {
+	case "status":
+		statuses = runner.Status()
+	case "up":
+		statuses = runner.Up()
+	case "down":
+		if *only == "" {
+			log.Fatalf("down needs -service; rolling back every service at once is never what you want")
+		}
+		statuses = runner.Down(*steps)
+	case "force":
+		if *only == "" || *version < 0 {
+			log.Fatalf("force needs -service and -version")
+		}
+		statuses = runner.Force(*version)
+	default:
+		log.Fatalf("unknown cmd: %s", *cmd)
+	}
+
+	if !report(os.Stdout, services, statuses) {
+		os.Exit(1)
+	}
+}
+
+func pick(services []orchestrator.Service, name string) []orchestrator.Service {
+	for _, service := range services {
+		if service.Name == name {
+			return []orchestrator.Service{service}
+		}
+	}
+	return nil
+}
+
+// report prints one line per service and whether every service succeeded. Services after a
+// failed one were not touched and are shown as skipped.
+func report(out *os.File, services []orchestrator.Service, statuses []orchestrator.Status) bool {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tDATABASE\tVERSION\tLATEST\tPENDING\tSTATE")
+
+	ok := true
+	for i, service := range services {
+		if i >= len(statuses) {
+			fmt.Fprintf(w, "%s\t-\t-\t-\t-\tskipped\n", service.Name)
+			continue
+		}
+
+		status := statuses[i]
+		state := "ok"
+		switch {
+		case status.Err != nil:
+			state = "error: " + status.Err.Error()
+			ok = false
+		case status.Dirty:
+			state = "dirty, fix the failed migration then force the version"
+			ok = false
+		case status.Before != status.Version:
+			state = fmt.Sprintf("migrated from %d", status.Before)
+		}
+
+		// Without a database there is no version to show, only the latest one available
+		database, version, pending := "-", "-", "-"
+		if status.Database != "" {
+			database = status.Database
+		}
+		if status.Connected {
+			version = fmt.Sprint(status.Version)
+			pending = fmt.Sprint(status.Pending)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", status.Service, database, version, status.Latest, pending, state)
+	}
+	w.Flush()
+	return ok
+}